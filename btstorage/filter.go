@@ -0,0 +1,267 @@
+package btstorage
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+
+	btpb "google.golang.org/genproto/googleapis/bigtable/v2"
+)
+
+// cellFilter returns the cells of the row that pass the filter, the cells must not be modified in place
+type cellFilter func(key []byte, cells []*Cell) []*Cell
+
+// newCellFilter compiles the row filter of a read request, a nil filter passes all cells
+func newCellFilter(filter *btpb.RowFilter) (cellFilter, error) {
+	if filter == nil {
+		return passAll, nil
+	}
+
+	switch f := filter.Filter.(type) {
+	case *btpb.RowFilter_PassAllFilter:
+		return passAll, nil
+	case *btpb.RowFilter_BlockAllFilter:
+		return blockAll, nil
+	case *btpb.RowFilter_Chain_:
+		filters, err := newCellFilters(f.Chain.Filters)
+		if err != nil {
+			return nil, err
+		}
+		return func(key []byte, cells []*Cell) []*Cell {
+			for _, filter := range filters {
+				if len(cells) == 0 {
+					break
+				}
+				cells = filter(key, cells)
+			}
+			return cells
+		}, nil
+	case *btpb.RowFilter_Interleave_:
+		filters, err := newCellFilters(f.Interleave.Filters)
+		if err != nil {
+			return nil, err
+		}
+		return func(key []byte, cells []*Cell) []*Cell {
+			res := []*Cell{}
+			for _, filter := range filters {
+				res = append(res, filter(key, cells)...)
+			}
+			sortCells(res)
+			return res
+		}, nil
+	case *btpb.RowFilter_Condition_:
+		predicate, err := newCellFilter(f.Condition.PredicateFilter)
+		if err != nil {
+			return nil, err
+		}
+		trueFilter, falseFilter := blockAll, blockAll
+		if f.Condition.TrueFilter != nil {
+			trueFilter, err = newCellFilter(f.Condition.TrueFilter)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if f.Condition.FalseFilter != nil {
+			falseFilter, err = newCellFilter(f.Condition.FalseFilter)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return func(key []byte, cells []*Cell) []*Cell {
+			if len(predicate(key, cells)) > 0 {
+				return trueFilter(key, cells)
+			}
+			return falseFilter(key, cells)
+		}, nil
+	case *btpb.RowFilter_RowKeyRegexFilter:
+		re, err := compileRegex(f.RowKeyRegexFilter)
+		if err != nil {
+			return nil, err
+		}
+		return func(key []byte, cells []*Cell) []*Cell {
+			if re.Match(key) {
+				return cells
+			}
+			return nil
+		}, nil
+	case *btpb.RowFilter_FamilyNameRegexFilter:
+		re, err := compileRegex([]byte(f.FamilyNameRegexFilter))
+		if err != nil {
+			return nil, err
+		}
+		return cellMatcher(func(cell *Cell) bool {
+			return re.MatchString(cell.Family)
+		}), nil
+	case *btpb.RowFilter_ColumnQualifierRegexFilter:
+		re, err := compileRegex(f.ColumnQualifierRegexFilter)
+		if err != nil {
+			return nil, err
+		}
+		return cellMatcher(func(cell *Cell) bool {
+			return re.Match(cell.Qualifier)
+		}), nil
+	case *btpb.RowFilter_ValueRegexFilter:
+		re, err := compileRegex(f.ValueRegexFilter)
+		if err != nil {
+			return nil, err
+		}
+		return cellMatcher(func(cell *Cell) bool {
+			return re.Match(cell.Value)
+		}), nil
+	case *btpb.RowFilter_ColumnRangeFilter:
+		r := f.ColumnRangeFilter
+		return cellMatcher(func(cell *Cell) bool {
+			if cell.Family != r.FamilyName {
+				return false
+			}
+			switch start := r.StartQualifier.(type) {
+			case *btpb.ColumnRange_StartQualifierClosed:
+				if bytes.Compare(cell.Qualifier, start.StartQualifierClosed) < 0 {
+					return false
+				}
+			case *btpb.ColumnRange_StartQualifierOpen:
+				if bytes.Compare(cell.Qualifier, start.StartQualifierOpen) <= 0 {
+					return false
+				}
+			}
+			switch end := r.EndQualifier.(type) {
+			case *btpb.ColumnRange_EndQualifierClosed:
+				if bytes.Compare(cell.Qualifier, end.EndQualifierClosed) > 0 {
+					return false
+				}
+			case *btpb.ColumnRange_EndQualifierOpen:
+				if bytes.Compare(cell.Qualifier, end.EndQualifierOpen) >= 0 {
+					return false
+				}
+			}
+			return true
+		}), nil
+	case *btpb.RowFilter_ValueRangeFilter:
+		r := f.ValueRangeFilter
+		return cellMatcher(func(cell *Cell) bool {
+			switch start := r.StartValue.(type) {
+			case *btpb.ValueRange_StartValueClosed:
+				if bytes.Compare(cell.Value, start.StartValueClosed) < 0 {
+					return false
+				}
+			case *btpb.ValueRange_StartValueOpen:
+				if bytes.Compare(cell.Value, start.StartValueOpen) <= 0 {
+					return false
+				}
+			}
+			switch end := r.EndValue.(type) {
+			case *btpb.ValueRange_EndValueClosed:
+				if bytes.Compare(cell.Value, end.EndValueClosed) > 0 {
+					return false
+				}
+			case *btpb.ValueRange_EndValueOpen:
+				if bytes.Compare(cell.Value, end.EndValueOpen) >= 0 {
+					return false
+				}
+			}
+			return true
+		}), nil
+	case *btpb.RowFilter_TimestampRangeFilter:
+		r := f.TimestampRangeFilter
+		return cellMatcher(func(cell *Cell) bool {
+			return cell.Timestamp >= r.StartTimestampMicros && (r.EndTimestampMicros == 0 || cell.Timestamp < r.EndTimestampMicros)
+		}), nil
+	case *btpb.RowFilter_CellsPerColumnLimitFilter:
+		limit := int(f.CellsPerColumnLimitFilter)
+		return func(key []byte, cells []*Cell) []*Cell {
+			res := make([]*Cell, 0, len(cells))
+			count := 0
+			for i, cell := range cells {
+				if i == 0 || cell.Family != cells[i-1].Family || !bytes.Equal(cell.Qualifier, cells[i-1].Qualifier) {
+					count = 0
+				}
+				count++
+				if count <= limit {
+					res = append(res, cell)
+				}
+			}
+			return res
+		}, nil
+	case *btpb.RowFilter_CellsPerRowOffsetFilter:
+		offset := int(f.CellsPerRowOffsetFilter)
+		return func(key []byte, cells []*Cell) []*Cell {
+			if offset >= len(cells) {
+				return nil
+			}
+			return cells[offset:]
+		}, nil
+	case *btpb.RowFilter_CellsPerRowLimitFilter:
+		limit := int(f.CellsPerRowLimitFilter)
+		return func(key []byte, cells []*Cell) []*Cell {
+			if limit >= len(cells) {
+				return cells
+			}
+			return cells[:limit]
+		}, nil
+	case *btpb.RowFilter_StripValueTransformer:
+		return func(key []byte, cells []*Cell) []*Cell {
+			res := make([]*Cell, len(cells))
+			for i, cell := range cells {
+				res[i] = &Cell{Family: cell.Family, Qualifier: cell.Qualifier, Timestamp: cell.Timestamp}
+			}
+			return res
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported row filter %T", filter.Filter)
+}
+
+func newCellFilters(filters []*btpb.RowFilter) ([]cellFilter, error) {
+	res := make([]cellFilter, len(filters))
+	for i, filter := range filters {
+		f, err := newCellFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = f
+	}
+	return res, nil
+}
+
+func passAll(key []byte, cells []*Cell) []*Cell {
+	return cells
+}
+
+func blockAll(key []byte, cells []*Cell) []*Cell {
+	return nil
+}
+
+// cellMatcher returns a filter passing the cells matching fn
+func cellMatcher(fn func(cell *Cell) bool) cellFilter {
+	return func(key []byte, cells []*Cell) []*Cell {
+		res := make([]*Cell, 0, len(cells))
+		for _, cell := range cells {
+			if fn(cell) {
+				res = append(res, cell)
+			}
+		}
+		return res
+	}
+}
+
+// compileRegex compiles a RE2 expression of a filter which has to match the whole input
+func compileRegex(expr []byte) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", expr))
+	if err != nil {
+		return nil, fmt.Errorf("error compiling filter regex %q: %w", expr, err)
+	}
+	return re, nil
+}
+
+// sortCells sorts the cells by family, qualifier and descending timestamp
+func sortCells(cells []*Cell) {
+	sort.SliceStable(cells, func(i, j int) bool {
+		if cells[i].Family != cells[j].Family {
+			return cells[i].Family < cells[j].Family
+		}
+		if c := bytes.Compare(cells[i].Qualifier, cells[j].Qualifier); c != 0 {
+			return c < 0
+		}
+		return cells[i].Timestamp > cells[j].Timestamp
+	})
+}
@@ -0,0 +1,173 @@
+package btstorage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// PostgresStorage stores the cells of all tables in the bigtable_cells table. The column families of a table are not
+// declared, so schema setup is a no-op and garbage collection policies (max versions, max age) are not enforced.
+type PostgresStorage struct {
+	db *sqlx.DB
+}
+
+// NewPostgresStorage returns a storage using the bigtable_cells table of the database
+func NewPostgresStorage(db *sqlx.DB) *PostgresStorage {
+	return &PostgresStorage{db: db}
+}
+
+func (s *PostgresStorage) ReadRows(ctx context.Context, table string, ranges []RowRange, fn func(row *Row) bool) error {
+	args := []interface{}{table}
+	conditions := []string{}
+	keys := pq.ByteaArray{}
+	for _, r := range ranges {
+		// single row lookups are collected in one condition to keep the query small for multi key reads
+		if r.Start != nil && r.End != nil && len(r.End) == len(r.Start)+1 && r.End[len(r.Start)] == 0 && bytes.HasPrefix(r.End, r.Start) {
+			keys = append(keys, r.Start)
+			continue
+		}
+		bounds := []string{}
+		if r.Start != nil {
+			args = append(args, r.Start)
+			bounds = append(bounds, fmt.Sprintf("row_key >= $%d", len(args)))
+		}
+		if r.End != nil {
+			args = append(args, r.End)
+			bounds = append(bounds, fmt.Sprintf("row_key < $%d", len(args)))
+		}
+		if len(bounds) == 0 {
+			bounds = append(bounds, "TRUE")
+		}
+		conditions = append(conditions, "("+strings.Join(bounds, " AND ")+")")
+	}
+	if len(keys) > 0 {
+		args = append(args, keys)
+		conditions = append(conditions, fmt.Sprintf("row_key = ANY($%d)", len(args)))
+	}
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	rows, err := s.db.QueryxContext(ctx, fmt.Sprintf(`
+		SELECT row_key, family, qualifier, ts, value
+		FROM bigtable_cells
+		WHERE table_name = $1 AND (%s)
+		ORDER BY row_key, family, qualifier, ts DESC`, strings.Join(conditions, " OR ")), args...)
+	if err != nil {
+		return fmt.Errorf("error reading rows of table %v: %w", table, err)
+	}
+	defer rows.Close()
+
+	var row *Row
+	for rows.Next() {
+		var key []byte
+		cell := &Cell{}
+		err := rows.Scan(&key, &cell.Family, &cell.Qualifier, &cell.Timestamp, &cell.Value)
+		if err != nil {
+			return fmt.Errorf("error scanning cell of table %v: %w", table, err)
+		}
+		if row != nil && string(row.Key) != string(key) {
+			if !fn(row) {
+				return nil
+			}
+			row = nil
+		}
+		if row == nil {
+			row = &Row{Key: key}
+		}
+		row.Cells = append(row.Cells, cell)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error reading rows of table %v: %w", table, err)
+	}
+	if row != nil {
+		fn(row)
+	}
+	return nil
+}
+
+func (s *PostgresStorage) MutateRow(ctx context.Context, table string, key []byte, mutations []*Mutation) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i := 0; i < len(mutations); i++ {
+		m := mutations[i]
+		switch m.Type {
+		case MutationSetCell:
+			// consecutive writes are batched, later writes of the same cell overwrite earlier ones
+			j := i
+			for j < len(mutations) && mutations[j].Type == MutationSetCell {
+				j++
+			}
+			err = setCells(ctx, tx, table, key, mutations[i:j])
+			i = j - 1
+		case MutationDeleteCells:
+			if m.EndTimestamp == 0 {
+				_, err = tx.ExecContext(ctx, `DELETE FROM bigtable_cells WHERE table_name = $1 AND row_key = $2 AND family = $3 AND qualifier = $4 AND ts >= $5`,
+					table, key, m.Family, notNull(m.Qualifier), m.StartTimestamp)
+			} else {
+				_, err = tx.ExecContext(ctx, `DELETE FROM bigtable_cells WHERE table_name = $1 AND row_key = $2 AND family = $3 AND qualifier = $4 AND ts >= $5 AND ts < $6`,
+					table, key, m.Family, notNull(m.Qualifier), m.StartTimestamp, m.EndTimestamp)
+			}
+		case MutationDeleteFamily:
+			_, err = tx.ExecContext(ctx, `DELETE FROM bigtable_cells WHERE table_name = $1 AND row_key = $2 AND family = $3`, table, key, m.Family)
+		case MutationDeleteRow:
+			_, err = tx.ExecContext(ctx, `DELETE FROM bigtable_cells WHERE table_name = $1 AND row_key = $2`, table, key)
+		default:
+			err = fmt.Errorf("unknown mutation type %v", m.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("error mutating row %x of table %v: %w", key, table, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing db transaction: %w", err)
+	}
+	return nil
+}
+
+func setCells(ctx context.Context, tx *sqlx.Tx, table string, key []byte, mutations []*Mutation) error {
+	// a single insert must not update the same cell twice
+	index := make(map[string]int, len(mutations))
+	families := []string{}
+	qualifiers := pq.ByteaArray{}
+	timestamps := []int64{}
+	values := pq.ByteaArray{}
+	for _, m := range mutations {
+		cellKey := fmt.Sprintf("%s:%x:%d", m.Family, m.Qualifier, m.Timestamp)
+		if i, ok := index[cellKey]; ok {
+			values[i] = notNull(m.Value)
+			continue
+		}
+		index[cellKey] = len(families)
+		families = append(families, m.Family)
+		qualifiers = append(qualifiers, notNull(m.Qualifier))
+		timestamps = append(timestamps, m.Timestamp)
+		values = append(values, notNull(m.Value))
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO bigtable_cells (table_name, row_key, family, qualifier, ts, value)
+		SELECT $1, $2, family, qualifier, ts, value FROM UNNEST($3::VARCHAR[], $4::BYTEA[], $5::BIGINT[], $6::BYTEA[]) AS c(family, qualifier, ts, value)
+		ON CONFLICT (table_name, row_key, family, qualifier, ts) DO UPDATE SET value = EXCLUDED.value`,
+		table, key, pq.Array(families), qualifiers, pq.Array(timestamps), values)
+	return err
+}
+
+// notNull returns an empty slice for nil, protobuf decodes empty bytes as nil which would be stored as NULL
+func notNull(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	return b
+}
@@ -0,0 +1,203 @@
+package btstorage
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	btpb "google.golang.org/genproto/googleapis/bigtable/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// maxMessageSize matches the message size limit of the bigtable client
+const maxMessageSize = 1 << 28
+
+// Server implements the parts of the Bigtable data api used by the explorer: reading rows with filters and applying
+// unconditional mutations. Garbage collection policies of the column families are not applied.
+type Server struct {
+	btpb.UnimplementedBigtableServer
+	storage Storage
+}
+
+// NewClientConn starts an in-process server on top of the storage and returns a connection to it which can be passed
+// to the bigtable client via option.WithGRPCConn
+func NewClientConn(storage Storage) (*grpc.ClientConn, error) {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.MaxRecvMsgSize(maxMessageSize), grpc.MaxSendMsgSize(maxMessageSize))
+	btpb.RegisterBigtableServer(server, &Server{storage: storage})
+	go func() {
+		err := server.Serve(listener)
+		if err != nil {
+			logger.WithError(err).Error("error serving bigtable api")
+		}
+	}()
+
+	return grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMessageSize), grpc.MaxCallSendMsgSize(maxMessageSize)),
+	)
+}
+
+// tableId returns the id of the table of a fully qualified table name, projects/{project}/instances/{instance}/tables/{table}
+func tableId(tableName string) string {
+	return tableName[strings.LastIndex(tableName, "/")+1:]
+}
+
+// ReadRows streams the rows of the requested row set, every row is sent as a single response with one chunk per cell
+func (s *Server) ReadRows(req *btpb.ReadRowsRequest, stream btpb.Bigtable_ReadRowsServer) error {
+	filter, err := newCellFilter(req.Filter)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var sendErr error
+	count := int64(0)
+	err = s.storage.ReadRows(stream.Context(), tableId(req.TableName), rowRanges(req.Rows), func(row *Row) bool {
+		cells := filter(row.Key, row.Cells)
+		if len(cells) == 0 {
+			return true
+		}
+		sendErr = stream.Send(&btpb.ReadRowsResponse{Chunks: rowChunks(row.Key, cells)})
+		if sendErr != nil {
+			return false
+		}
+		count++
+		return req.RowsLimit == 0 || count < req.RowsLimit
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// rowRanges converts the requested row set to half-open ranges, an empty row set selects the whole table
+func rowRanges(rows *btpb.RowSet) []RowRange {
+	if rows == nil || (len(rows.RowKeys) == 0 && len(rows.RowRanges) == 0) {
+		return []RowRange{{}}
+	}
+
+	ranges := make([]RowRange, 0, len(rows.RowKeys)+len(rows.RowRanges))
+	for _, key := range rows.RowKeys {
+		ranges = append(ranges, RowRange{Start: key, End: keySuccessor(key)})
+	}
+	for _, r := range rows.RowRanges {
+		rr := RowRange{}
+		switch start := r.StartKey.(type) {
+		case *btpb.RowRange_StartKeyClosed:
+			rr.Start = start.StartKeyClosed
+		case *btpb.RowRange_StartKeyOpen:
+			rr.Start = keySuccessor(start.StartKeyOpen)
+		}
+		switch end := r.EndKey.(type) {
+		case *btpb.RowRange_EndKeyClosed:
+			rr.End = keySuccessor(end.EndKeyClosed)
+		case *btpb.RowRange_EndKeyOpen:
+			// an empty end key is unbounded
+			if len(end.EndKeyOpen) > 0 {
+				rr.End = end.EndKeyOpen
+			}
+		}
+		ranges = append(ranges, rr)
+	}
+	return ranges
+}
+
+// keySuccessor returns the smallest key that is greater than the key
+func keySuccessor(key []byte) []byte {
+	return append(append(make([]byte, 0, len(key)+1), key...), 0)
+}
+
+func rowChunks(key []byte, cells []*Cell) []*btpb.ReadRowsResponse_CellChunk {
+	chunks := make([]*btpb.ReadRowsResponse_CellChunk, len(cells))
+	for i, cell := range cells {
+		chunks[i] = &btpb.ReadRowsResponse_CellChunk{
+			RowKey:          key,
+			FamilyName:      wrapperspb.String(cell.Family),
+			Qualifier:       wrapperspb.Bytes(cell.Qualifier),
+			TimestampMicros: cell.Timestamp,
+			Value:           cell.Value,
+		}
+	}
+	chunks[len(chunks)-1].RowStatus = &btpb.ReadRowsResponse_CellChunk_CommitRow{CommitRow: true}
+	return chunks
+}
+
+// MutateRow applies the mutations of a single row
+func (s *Server) MutateRow(ctx context.Context, req *btpb.MutateRowRequest) (*btpb.MutateRowResponse, error) {
+	err := s.mutateRow(ctx, req.TableName, req.RowKey, req.Mutations)
+	if err != nil {
+		return nil, err
+	}
+	return &btpb.MutateRowResponse{}, nil
+}
+
+// MutateRows applies the mutations of every entry atomically per row and reports the status of every entry
+func (s *Server) MutateRows(req *btpb.MutateRowsRequest, stream btpb.Bigtable_MutateRowsServer) error {
+	res := &btpb.MutateRowsResponse{Entries: make([]*btpb.MutateRowsResponse_Entry, len(req.Entries))}
+	for i, entry := range req.Entries {
+		err := s.mutateRow(stream.Context(), req.TableName, entry.RowKey, entry.Mutations)
+		res.Entries[i] = &btpb.MutateRowsResponse_Entry{Index: int64(i), Status: status.Convert(err).Proto()}
+	}
+	return stream.Send(res)
+}
+
+func (s *Server) mutateRow(ctx context.Context, tableName string, key []byte, mutations []*btpb.Mutation) error {
+	if len(key) == 0 {
+		return status.Error(codes.InvalidArgument, "row key required")
+	}
+
+	// server side timestamps are in milliseconds like the ones of gcp
+	now := time.Now().UnixMilli() * 1000
+	converted := make([]*Mutation, 0, len(mutations))
+	for _, m := range mutations {
+		switch mut := m.Mutation.(type) {
+		case *btpb.Mutation_SetCell_:
+			ts := mut.SetCell.TimestampMicros
+			if ts == -1 {
+				ts = now
+			}
+			converted = append(converted, &Mutation{
+				Type:      MutationSetCell,
+				Family:    mut.SetCell.FamilyName,
+				Qualifier: mut.SetCell.ColumnQualifier,
+				Timestamp: ts,
+				Value:     mut.SetCell.Value,
+			})
+		case *btpb.Mutation_DeleteFromColumn_:
+			mutation := &Mutation{
+				Type:      MutationDeleteCells,
+				Family:    mut.DeleteFromColumn.FamilyName,
+				Qualifier: mut.DeleteFromColumn.ColumnQualifier,
+			}
+			if r := mut.DeleteFromColumn.TimeRange; r != nil {
+				mutation.StartTimestamp = r.StartTimestampMicros
+				mutation.EndTimestamp = r.EndTimestampMicros
+			}
+			converted = append(converted, mutation)
+		case *btpb.Mutation_DeleteFromFamily_:
+			converted = append(converted, &Mutation{Type: MutationDeleteFamily, Family: mut.DeleteFromFamily.FamilyName})
+		case *btpb.Mutation_DeleteFromRow_:
+			converted = append(converted, &Mutation{Type: MutationDeleteRow})
+		default:
+			return status.Errorf(codes.Unimplemented, "unsupported mutation %T", m.Mutation)
+		}
+	}
+
+	err := s.storage.MutateRow(ctx, tableId(tableName), key, converted)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
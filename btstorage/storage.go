@@ -0,0 +1,67 @@
+// Package btstorage serves the Bigtable data api on top of a pluggable storage so the explorer can run without a GCP
+// Bigtable instance, e.g. for private devnets. The bigtable client of the db package connects to the in-process server
+// and is not aware of the storage backend.
+package btstorage
+
+import (
+	"context"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+var logger = utils.NewLogger("btstorage")
+
+// Cell is a single version of a column of a row, the timestamp is in microseconds
+type Cell struct {
+	Family    string
+	Qualifier []byte
+	Timestamp int64
+	Value     []byte
+}
+
+// Row is a row of a table with its cells ordered by family, qualifier and descending timestamp
+type Row struct {
+	Key   []byte
+	Cells []*Cell
+}
+
+// RowRange is the range of row keys from Start (inclusive) to End (exclusive), nil keys are unbounded
+type RowRange struct {
+	Start []byte
+	End   []byte
+}
+
+// MutationType is the kind of change of a Mutation
+type MutationType int
+
+const (
+	// MutationSetCell writes the value of the cell with the family, qualifier and timestamp of the mutation
+	MutationSetCell MutationType = iota
+	// MutationDeleteCells deletes the cells of the column with a timestamp in [StartTimestamp, EndTimestamp), an
+	// EndTimestamp of 0 is unbounded
+	MutationDeleteCells
+	// MutationDeleteFamily deletes all cells of the family of the mutation
+	MutationDeleteFamily
+	// MutationDeleteRow deletes all cells of the row
+	MutationDeleteRow
+)
+
+// Mutation is a change of a row
+type Mutation struct {
+	Type           MutationType
+	Family         string
+	Qualifier      []byte
+	Timestamp      int64
+	Value          []byte
+	StartTimestamp int64
+	EndTimestamp   int64
+}
+
+// Storage persists the cells of the tables served by the Server
+type Storage interface {
+	// ReadRows calls fn for every row of the table with a key in one of the ranges in ascending row key order until fn
+	// returns false. Every row is only returned once even if the ranges overlap.
+	ReadRows(ctx context.Context, table string, ranges []RowRange, fn func(row *Row) bool) error
+	// MutateRow applies the mutations to the row of the table atomically and in order
+	MutateRow(ctx context.Context, table string, key []byte, mutations []*Mutation) error
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// hotCacheSize is the number of keys kept in the in-process tier
+	hotCacheSize = 1024
+	// hotCacheTTL bounds the staleness of a hot key if its invalidation message is lost
+	hotCacheTTL = time.Second * 10
+)
+
+type hotCacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+func newHotCache() *lru.ARCCache {
+	hotCache, err := lru.NewARC(hotCacheSize)
+	if err != nil {
+		panic(fmt.Sprintf("error creating hot cache: %v", err))
+	}
+	return hotCache
+}
+
+// invalidationChannel is the redis channel the keys written to the tiered cache are published on
+func invalidationChannel() string {
+	return fmt.Sprintf("%d:cache:invalidate", utils.Config.Chain.ClConfig.DepositChainID)
+}
+
+// publishInvalidation notifies all processes that the key changed so they drop it from their in-process tiers
+func (cache *tieredCache) publishInvalidation(ctx context.Context, key string) {
+	err := cache.remoteCache.Publish(ctx, invalidationChannel(), key)
+	if err != nil {
+		utils.LogError(err, "error publishing cache invalidation", 0, map[string]interface{}{"key": key})
+	}
+}
+
+// invalidate removes the key from the in-process tiers
+func (cache *tieredCache) invalidate(key string) {
+	cache.hotCache.Remove(key)
+	cache.localGoCache.Del([]byte(key))
+}
+
+// GetUint64Hot returns the value of a key that is read on every page view. The value is kept in an in-process lru
+// tier until it is invalidated by a write or expires, on a miss it is read from the tiered cache.
+func (cache *tieredCache) GetUint64Hot(key string) (uint64, error) {
+	if entry, ok := cache.hotCache.Get(key); ok && time.Now().Before(entry.(*hotCacheEntry).expires) {
+		metrics.CacheHits.WithLabelValues("hot", "lru").Inc()
+		return entry.(*hotCacheEntry).value.(uint64), nil
+	}
+
+	value, err := cache.getUint64(key, hotCacheTTL, "hot")
+	if err != nil {
+		return 0, err
+	}
+	cache.hotCache.Add(key, &hotCacheEntry{value: value, expires: time.Now().Add(hotCacheTTL)})
+	return value, nil
+}
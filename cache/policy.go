@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/sync/singleflight"
+)
+
+// KeyType selects the ttl policy of a cached value
+type KeyType string
+
+const (
+	// KeyTypeIndexPage is the index page data, written by the index page data updater
+	KeyTypeIndexPage KeyType = "indexPage"
+	// KeyTypeEpoch is the data of an epoch that is not finalized yet and may still change
+	KeyTypeEpoch KeyType = "epoch"
+	// KeyTypeFinalizedEpoch is the data of a finalized epoch
+	KeyTypeFinalizedEpoch KeyType = "finalizedEpoch"
+	// KeyTypeValidatorOverview is the statistics based data of the validator page, keys contain the last exported
+	// statistics day so they change when new statistics are available
+	KeyTypeValidatorOverview KeyType = "validatorOverview"
+	// KeyTypeFeatureFlags is the list of feature flags, written by the feature flags package whenever a flag changes
+	KeyTypeFeatureFlags KeyType = "featureFlags"
+	// KeyTypeEmbed is the data of an embeddable widget, it is refreshed about once per slot
+	KeyTypeEmbed KeyType = "embed"
+	// KeyTypeOgImage is a rendered preview image, keys contain the epoch it was rendered in so a new epoch renders a
+	// fresh image
+	KeyTypeOgImage KeyType = "ogImage"
+)
+
+// ttlPolicy is the expiration of a key type in the local and the remote tier. The local tier is per process, writes
+// invalidate it via pub/sub but it is kept short for data that changes in case an invalidation is lost.
+type ttlPolicy struct {
+	Local  time.Duration
+	Remote time.Duration
+}
+
+var ttlPolicies = map[KeyType]ttlPolicy{
+	KeyTypeIndexPage:         {Local: time.Second * 5, Remote: utils.Day},
+	KeyTypeEpoch:             {Local: time.Second * 2, Remote: time.Second * 12},
+	KeyTypeFinalizedEpoch:    {Local: time.Minute, Remote: time.Hour},
+	KeyTypeValidatorOverview: {Local: time.Minute, Remote: time.Minute * 10},
+	KeyTypeFeatureFlags:      {Local: time.Second * 10, Remote: time.Hour},
+	KeyTypeEmbed:             {Local: time.Second * 5, Remote: time.Second * 12},
+	KeyTypeOgImage:           {Local: time.Minute, Remote: time.Minute * 10},
+}
+
+// values larger than compressionThreshold bytes are stored zstd compressed
+const compressionThreshold = 4096
+
+var (
+	// loads deduplicates concurrent remote reads and loads of the same key
+	loads         singleflight.Group
+	zstdEncoder   *zstd.Encoder
+	zstdDecoder   *zstd.Decoder
+	zstdFrameHead = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+func init() {
+	var err error
+	zstdEncoder, err = zstd.NewWriter(nil)
+	if err != nil {
+		panic(fmt.Sprintf("error creating zstd encoder: %v", err))
+	}
+	zstdDecoder, err = zstd.NewReader(nil)
+	if err != nil {
+		panic(fmt.Sprintf("error creating zstd decoder: %v", err))
+	}
+}
+
+// Set stores the value in both tiers with the expiration of the key type
+func Set(keyType KeyType, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("error marshalling value of key %v: %w", key, err)
+	}
+	if len(data) > compressionThreshold {
+		data = zstdEncoder.EncodeAll(data, make([]byte, 0, len(data)/4))
+	}
+
+	policy := ttlPolicies[keyType]
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	TieredCache.localGoCache.Set([]byte(key), data, int(policy.Local.Seconds()))
+	err = TieredCache.remoteCache.SetBytes(ctx, key, data, policy.Remote)
+	if err != nil {
+		return err
+	}
+	TieredCache.publishInvalidation(ctx, key)
+	return nil
+}
+
+// Get returns the value of the key from the local tier or the remote tier. Concurrent remote reads of the same key
+// are deduplicated.
+func Get[T any](keyType KeyType, key string) (T, error) {
+	var value T
+
+	data, err := TieredCache.localGoCache.Get([]byte(key))
+	if err == nil {
+		metrics.CacheHits.WithLabelValues(string(keyType), "local").Inc()
+		return value, decode(data, &value)
+	}
+
+	res, err, _ := loads.Do("remote:"+key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+		defer cancel()
+
+		data, err := TieredCache.remoteCache.GetBytes(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		TieredCache.localGoCache.Set([]byte(key), data, int(ttlPolicies[keyType].Local.Seconds()))
+		return data, nil
+	})
+	if err != nil {
+		if err != redis.Nil {
+			return value, fmt.Errorf("error retrieving key %v from remote cache: %w", key, err)
+		}
+		metrics.CacheMisses.WithLabelValues(string(keyType)).Inc()
+		return value, err
+	}
+
+	metrics.CacheHits.WithLabelValues(string(keyType), "remote").Inc()
+	return value, decode(res.([]byte), &value)
+}
+
+// GetOrLoad returns the cached value of the key, on a miss the value is loaded and cached. Concurrent misses of the
+// same key share a single load so an expiring hot key does not stampede the database.
+func GetOrLoad[T any](keyType KeyType, key string, load func() (T, error)) (T, error) {
+	value, err := Get[T](keyType, key)
+	if err == nil {
+		return value, nil
+	}
+	if err != redis.Nil {
+		utils.LogError(err, "error retrieving cached value, loading it", 0, map[string]interface{}{"key": key})
+	}
+
+	res, err, _ := loads.Do("load:"+key, func() (interface{}, error) {
+		value, err := load()
+		if err != nil {
+			return nil, err
+		}
+		err = Set(keyType, key, value)
+		if err != nil {
+			utils.LogError(err, "error caching loaded value", 0, map[string]interface{}{"key": key})
+		}
+		return value, nil
+	})
+	if err != nil {
+		return value, err
+	}
+	return res.(T), nil
+}
+
+func decode(data []byte, value interface{}) error {
+	if bytes.HasPrefix(data, zstdFrameHead) {
+		var err error
+		data, err = zstdDecoder.DecodeAll(data, nil)
+		if err != nil {
+			return fmt.Errorf("error decompressing cached value: %w", err)
+		}
+	}
+	// numbers decoded into interfaces are kept as json numbers so large integers do not lose precision
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	return d.Decode(value)
+}
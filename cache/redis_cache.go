@@ -82,6 +82,42 @@ func (cache *RedisCache) GetBool(ctx context.Context, key string) (bool, error)
 	return returnValue, nil
 }
 
+func (cache *RedisCache) SetBytes(ctx context.Context, key string, value []byte, expiration time.Duration) error {
+	return cache.redisRemoteCache.Set(ctx, key, value, expiration).Err()
+}
+
+func (cache *RedisCache) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	return cache.redisRemoteCache.Get(ctx, key).Bytes()
+}
+
+func (cache *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	return cache.redisRemoteCache.Del(ctx, keys...).Err()
+}
+
+func (cache *RedisCache) Publish(ctx context.Context, channel, message string) error {
+	return cache.redisRemoteCache.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe calls fn for every message published on the channel until the context is done, the subscription is
+// re-established after connection losses
+func (cache *RedisCache) Subscribe(ctx context.Context, channel string, fn func(message string)) {
+	sub := cache.redisRemoteCache.Subscribe(ctx, channel)
+	defer sub.Close()
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			fn(msg.Payload)
+		}
+	}
+}
+
 func (cache *RedisCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	valueMarshal, err := json.Marshal(value)
 	if err != nil {
@@ -7,14 +7,17 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 
 	"github.com/coocood/freecache"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/sirupsen/logrus"
 )
 
 // Tiered cache is a cache implementation combining a
 type tieredCache struct {
+	hotCache     *lru.ARCCache
 	localGoCache *freecache.Cache
 	remoteCache  RemoteCache
 }
@@ -24,11 +27,18 @@ type RemoteCache interface {
 	SetString(ctx context.Context, key, value string, expiration time.Duration) error
 	SetUint64(ctx context.Context, key string, value uint64, expiration time.Duration) error
 	SetBool(ctx context.Context, key string, value bool, expiration time.Duration) error
+	SetBytes(ctx context.Context, key string, value []byte, expiration time.Duration) error
 
 	Get(ctx context.Context, key string, returnValue any) (any, error)
 	GetString(ctx context.Context, key string) (string, error)
 	GetUint64(ctx context.Context, key string) (uint64, error)
 	GetBool(ctx context.Context, key string) (bool, error)
+	GetBytes(ctx context.Context, key string) ([]byte, error)
+
+	Delete(ctx context.Context, keys ...string) error
+
+	Publish(ctx context.Context, channel, message string) error
+	Subscribe(ctx context.Context, channel string, fn func(message string))
 }
 
 var TieredCache *tieredCache
@@ -43,9 +53,11 @@ func MustInitTieredCache(redisAddress string) {
 	}
 
 	TieredCache = &tieredCache{
+		hotCache:     newHotCache(),
 		remoteCache:  remoteCache,
 		localGoCache: freecache.NewCache(100 * 1024 * 1024), // 100 MB
 	}
+	go remoteCache.Subscribe(context.Background(), invalidationChannel(), TieredCache.invalidate)
 }
 
 func (cache *tieredCache) SetString(key, value string, expiration time.Duration) error {
@@ -53,13 +65,19 @@ func (cache *tieredCache) SetString(key, value string, expiration time.Duration)
 	defer cancel()
 
 	cache.localGoCache.Set([]byte(key), []byte(value), int(expiration.Seconds()))
-	return cache.remoteCache.SetString(ctx, key, value, expiration)
+	err := cache.remoteCache.SetString(ctx, key, value, expiration)
+	if err != nil {
+		return err
+	}
+	cache.publishInvalidation(ctx, key)
+	return nil
 }
 
 func (cache *tieredCache) GetStringWithLocalTimeout(key string, localExpiration time.Duration) (string, error) {
 	// try to retrieve the key from the local cache
 	wanted, err := cache.localGoCache.Get([]byte(key))
 	if err == nil {
+		metrics.CacheHits.WithLabelValues("tiered", "local").Inc()
 		return string(wanted), nil
 	}
 
@@ -69,8 +87,10 @@ func (cache *tieredCache) GetStringWithLocalTimeout(key string, localExpiration
 
 	value, err := cache.remoteCache.GetString(ctx, key)
 	if err != nil {
+		metrics.CacheMisses.WithLabelValues("tiered").Inc()
 		return "", err
 	}
+	metrics.CacheHits.WithLabelValues("tiered", "remote").Inc()
 
 	cache.localGoCache.Set([]byte(key), []byte(value), int(localExpiration.Seconds()))
 	return value, nil
@@ -81,10 +101,19 @@ func (cache *tieredCache) SetUint64(key string, value uint64, expiration time.Du
 	defer cancel()
 
 	cache.localGoCache.Set([]byte(key), []byte(fmt.Sprintf("%d", value)), int(expiration.Seconds()))
-	return cache.remoteCache.SetUint64(ctx, key, value, expiration)
+	err := cache.remoteCache.SetUint64(ctx, key, value, expiration)
+	if err != nil {
+		return err
+	}
+	cache.publishInvalidation(ctx, key)
+	return nil
 }
 
 func (cache *tieredCache) GetUint64WithLocalTimeout(key string, localExpiration time.Duration) (uint64, error) {
+	return cache.getUint64(key, localExpiration, "tiered")
+}
+
+func (cache *tieredCache) getUint64(key string, localExpiration time.Duration, keyType string) (uint64, error) {
 
 	// try to retrieve the key from the local cache
 	wanted, err := cache.localGoCache.Get([]byte(key))
@@ -93,6 +122,7 @@ func (cache *tieredCache) GetUint64WithLocalTimeout(key string, localExpiration
 		if err != nil {
 			return 0, err
 		}
+		metrics.CacheHits.WithLabelValues(keyType, "local").Inc()
 		return returnValue, nil
 	}
 
@@ -102,8 +132,10 @@ func (cache *tieredCache) GetUint64WithLocalTimeout(key string, localExpiration
 
 	value, err := cache.remoteCache.GetUint64(ctx, key)
 	if err != nil {
+		metrics.CacheMisses.WithLabelValues(keyType).Inc()
 		return 0, err
 	}
+	metrics.CacheHits.WithLabelValues(keyType, "remote").Inc()
 
 	cache.localGoCache.Set([]byte(key), []byte(fmt.Sprintf("%d", value)), int(localExpiration.Seconds()))
 	return value, nil
@@ -114,7 +146,12 @@ func (cache *tieredCache) SetBool(key string, value bool, expiration time.Durati
 	defer cancel()
 
 	cache.localGoCache.Set([]byte(key), []byte(fmt.Sprintf("%t", value)), int(expiration.Seconds()))
-	return cache.remoteCache.SetBool(ctx, key, value, expiration)
+	err := cache.remoteCache.SetBool(ctx, key, value, expiration)
+	if err != nil {
+		return err
+	}
+	cache.publishInvalidation(ctx, key)
+	return nil
 }
 
 func (cache *tieredCache) GetBoolWithLocalTimeout(key string, localExpiration time.Duration) (bool, error) {
@@ -126,6 +163,7 @@ func (cache *tieredCache) GetBoolWithLocalTimeout(key string, localExpiration ti
 		if err != nil {
 			return false, err
 		}
+		metrics.CacheHits.WithLabelValues("tiered", "local").Inc()
 		return returnValue, nil
 	}
 
@@ -135,8 +173,10 @@ func (cache *tieredCache) GetBoolWithLocalTimeout(key string, localExpiration ti
 
 	value, err := cache.remoteCache.GetBool(ctx, key)
 	if err != nil {
+		metrics.CacheMisses.WithLabelValues("tiered").Inc()
 		return false, err
 	}
+	metrics.CacheHits.WithLabelValues("tiered", "remote").Inc()
 
 	cache.localGoCache.Set([]byte(key), []byte(fmt.Sprintf("%t", value)), int(localExpiration.Seconds()))
 	return value, nil
@@ -151,7 +191,12 @@ func (cache *tieredCache) Set(key string, value interface{}, expiration time.Dur
 		return err
 	}
 	cache.localGoCache.Set([]byte(key), valueMarshal, int(expiration.Seconds()))
-	return cache.remoteCache.Set(ctx, key, value, expiration)
+	err = cache.remoteCache.Set(ctx, key, value, expiration)
+	if err != nil {
+		return err
+	}
+	cache.publishInvalidation(ctx, key)
+	return nil
 }
 
 func (cache *tieredCache) GetWithLocalTimeout(key string, localExpiration time.Duration, returnValue interface{}) (interface{}, error) {
@@ -163,6 +208,7 @@ func (cache *tieredCache) GetWithLocalTimeout(key string, localExpiration time.D
 			utils.LogError(err, "error unmarshalling data for key", 0, map[string]interface{}{"key": key})
 			return nil, err
 		}
+		metrics.CacheHits.WithLabelValues("tiered", "local").Inc()
 		return returnValue, nil
 	}
 
@@ -172,8 +218,10 @@ func (cache *tieredCache) GetWithLocalTimeout(key string, localExpiration time.D
 
 	value, err := cache.remoteCache.Get(ctx, key, returnValue)
 	if err != nil {
+		metrics.CacheMisses.WithLabelValues("tiered").Inc()
 		return nil, err
 	}
+	metrics.CacheHits.WithLabelValues("tiered", "remote").Inc()
 
 	valueMarshal, err := json.Marshal(value)
 	if err != nil {
@@ -183,3 +231,19 @@ func (cache *tieredCache) GetWithLocalTimeout(key string, localExpiration time.D
 	cache.localGoCache.Set([]byte(key), valueMarshal, int(localExpiration.Seconds()))
 	return value, nil
 }
+
+// Delete removes the keys from the remote tier and from the in-process tiers of all processes
+func (cache *tieredCache) Delete(keys ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	err := cache.remoteCache.Delete(ctx, keys...)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		cache.invalidate(key)
+		cache.publishInvalidation(ctx, key)
+	}
+	return nil
+}
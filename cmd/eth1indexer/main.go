@@ -21,6 +21,7 @@ import (
 	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
 	"github.com/gobitfly/eth2-beaconchain-explorer/rpc"
 	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/tracing"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 	"github.com/gobitfly/eth2-beaconchain-explorer/version"
@@ -73,6 +74,8 @@ func main() {
 	enableEnsUpdater := flag.Bool("ens.enabled", false, "Enable ens update process")
 	ensBatchSize := flag.Int64("ens.batch", 200, "Batch size for ens updates")
 
+	enableAddressEvents := flag.Bool("address.events.enabled", false, "Enable recording of events of addresses watched for notifications")
+
 	flag.Parse()
 
 	if *versionFlag {
@@ -87,6 +90,12 @@ func main() {
 		logrus.Fatalf("error reading config file: %v", err)
 	}
 	utils.Config = cfg
+
+	shutdownTracing, err := tracing.Init()
+	if err != nil {
+		utils.LogFatal(err, "error initializing tracing", 0)
+	}
+	defer shutdownTracing(context.Background())
 	logrus.WithField("config", *configPath).WithField("version", version.Version).WithField("chainName", utils.Config.Chain.ClConfig.ConfigName).Printf("starting")
 
 	if utils.Config.Metrics.Enabled {
@@ -165,6 +174,8 @@ func main() {
 	}
 	defer bt.Close()
 
+	go bt.ReplayMutationQueue()
+
 	if *tokenPriceExport {
 		go func() {
 			for {
@@ -199,7 +210,26 @@ func main() {
 		bt.TransformUncle,
 		bt.TransformWithdrawals,
 		bt.TransformEnsNameRegistered,
-		bt.TransformContract)
+		bt.TransformContract,
+		bt.TransformUserOperations,
+		bt.TransformL2BridgeTx)
+
+	if *enableAddressEvents {
+		err = db.UpdateEth1AddressWatchlistCache()
+		if err != nil {
+			utils.LogFatal(err, "error loading eth1 address watchlist", 0)
+		}
+		go func() {
+			for {
+				time.Sleep(time.Minute)
+				err := db.UpdateEth1AddressWatchlistCache()
+				if err != nil {
+					utils.LogError(err, "error updating eth1 address watchlist", 0)
+				}
+			}
+		}()
+		transforms = append(transforms, bt.TransformEth1AddressEvents)
+	}
 
 	cache := freecache.NewCache(100 * 1024 * 1024) // 100 MB limit
 
@@ -370,6 +400,7 @@ func main() {
 					}
 					cache.Clear()
 
+					services.ReportExporterProgress("eth1indexer", "Running", services.ExporterUnitBlock, uint64(endBlock), lastBlockFromNode, uint64(endBlock-startBlock+1))
 					startBlock = endBlock + 1
 				}
 				if continueAfterError {
@@ -378,17 +409,57 @@ func main() {
 			}
 		}
 
+		err = reexportGaps(bt, client, transforms, *concurrencyData, cache, *traceMode)
+		if err != nil {
+			utils.LogError(err, "error re-exporting gaps", 0)
+		}
+
 		if *enableBalanceUpdater {
 			ProcessMetadataUpdates(bt, client, balanceUpdaterPrefix, *balanceUpdaterBatchSize, 10)
 		}
 
 		logrus.Infof("index run completed")
-		services.ReportStatus("eth1indexer", "Running", nil)
+		services.ReportExporterProgress("eth1indexer", "Running", services.ExporterUnitBlock, lastBlockFromNode, lastBlockFromNode, 0)
 	}
 
 	// utils.WaitForCtrlC()
 }
 
+// reexportGaps indexes the blocks the gap exporter found missing in the blocks or data table
+func reexportGaps(bt *db.Bigtable, client *rpc.ErigonClient, transforms []func(blk *types.Eth1Block, cache *freecache.Cache) (*types.BulkMutations, *types.BulkMutations, error), concurrency int64, cache *freecache.Cache, traceMode string) error {
+	gaps, err := db.GetExportGaps(db.GapKindEth1Block, db.GapKindEth1Data)
+	if err != nil {
+		return err
+	}
+
+	for _, gap := range gaps {
+		logrus.Infof("re-exporting %v %v", gap.Kind, gap.Number)
+
+		block := int64(gap.Number)
+		if gap.Kind == db.GapKindEth1Block {
+			err = IndexFromNode(bt, client, block, block, 1, traceMode)
+		}
+		if err == nil {
+			err = bt.IndexEventsWithTransformers(block, block, transforms, concurrency, cache)
+			cache.Clear()
+		}
+		if err != nil {
+			utils.LogError(err, "error re-exporting gap", 0, map[string]interface{}{"kind": gap.Kind, "number": gap.Number})
+			err = db.FailExportGap(gap.Kind, gap.Number, err)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = db.ResolveExportGap(gap.Kind, gap.Number)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func ImportEnsUpdatesLoop(bt *db.Bigtable, client *rpc.ErigonClient, batchSize int64) {
 	for {
 		time.Sleep(time.Second * 5)
@@ -625,6 +696,8 @@ func IndexFromNode(bt *db.Bigtable, client *rpc.ErigonClient, start, end, concur
 
 	processedBlocks := int64(0)
 
+	batcher := bt.NewBlocksBatcher()
+
 	for i := start; i <= end; i++ {
 
 		i := i
@@ -651,7 +724,7 @@ func IndexFromNode(bt *db.Bigtable, client *rpc.ErigonClient, start, end, concur
 			metrics.TaskDuration.WithLabelValues("rpc_el_get_block_traces").Observe(timings.Traces.Seconds())
 
 			dbStart := time.Now()
-			err = bt.SaveBlock(bc)
+			err = bt.SaveBlockBatched(batcher, bc)
 			if err != nil {
 				return fmt.Errorf("error saving block: %v to bigtable: %w", i, err)
 
@@ -677,6 +750,12 @@ func IndexFromNode(bt *db.Bigtable, client *rpc.ErigonClient, start, end, concur
 
 	err := g.Wait()
 
+	// the buffered blocks are written even if indexing failed so the successfully retrieved blocks are not lost
+	flushErr := batcher.Close()
+	if err == nil {
+		err = flushErr
+	}
+
 	if err != nil {
 		return err
 	}
@@ -14,9 +14,11 @@ import (
 	"time"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
+	"github.com/gobitfly/eth2-beaconchain-explorer/dataexport"
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
 	ethclients "github.com/gobitfly/eth2-beaconchain-explorer/ethClients"
 	"github.com/gobitfly/eth2-beaconchain-explorer/exporter"
+	"github.com/gobitfly/eth2-beaconchain-explorer/faucet"
 	"github.com/gobitfly/eth2-beaconchain-explorer/handlers"
 	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
 	"github.com/gobitfly/eth2-beaconchain-explorer/price"
@@ -24,6 +26,7 @@ import (
 	"github.com/gobitfly/eth2-beaconchain-explorer/rpc"
 	"github.com/gobitfly/eth2-beaconchain-explorer/services"
 	"github.com/gobitfly/eth2-beaconchain-explorer/static"
+	"github.com/gobitfly/eth2-beaconchain-explorer/tracing"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 	"github.com/gobitfly/eth2-beaconchain-explorer/version"
@@ -39,7 +42,6 @@ import (
 	"github.com/gorilla/csrf"
 	"github.com/gorilla/mux"
 	_ "github.com/jackc/pgx/v5/stdlib"
-	"github.com/phyber/negroni-gzip/gzip"
 	"github.com/stripe/stripe-go/v72"
 	"github.com/urfave/negroni"
 	"github.com/zesik/proxyaddr"
@@ -64,6 +66,7 @@ var frontendHttpServer *http.Server
 func main() {
 	configPath := flag.String("config", "", "Path to the config file, if empty string defaults will be used")
 	versionFlag := flag.Bool("version", false, "Show version and exit")
+	devnetFlag := flag.Bool("devnet", false, "Run exporter and frontend against a local devnet, loading the chain config from the node and storing all data in postgres")
 	flag.Parse()
 
 	if *versionFlag {
@@ -72,12 +75,18 @@ func main() {
 		return
 	}
 
-	cfg := &types.Config{}
+	cfg := &types.Config{Devnet: *devnetFlag}
 	err := utils.ReadConfig(cfg, *configPath)
 	if err != nil {
 		logrus.Fatalf("error reading config file: %v", err)
 	}
 	utils.Config = cfg
+
+	shutdownTracing, err := tracing.Init()
+	if err != nil {
+		utils.LogFatal(err, "error initializing tracing", 0)
+	}
+	defer shutdownTracing(context.Background())
 	logrus.WithFields(logrus.Fields{
 		"config":    *configPath,
 		"version":   version.Version,
@@ -109,14 +118,16 @@ func main() {
 			MaxIdleConns: cfg.WriterDatabase.MaxIdleConns,
 			SSL:          cfg.WriterDatabase.SSL,
 		}, &types.DatabaseConfig{
-			Username:     cfg.ReaderDatabase.Username,
-			Password:     cfg.ReaderDatabase.Password,
-			Name:         cfg.ReaderDatabase.Name,
-			Host:         cfg.ReaderDatabase.Host,
-			Port:         cfg.ReaderDatabase.Port,
-			MaxOpenConns: cfg.ReaderDatabase.MaxOpenConns,
-			MaxIdleConns: cfg.ReaderDatabase.MaxIdleConns,
-			SSL:          cfg.ReaderDatabase.SSL,
+			Username:          cfg.ReaderDatabase.Username,
+			Password:          cfg.ReaderDatabase.Password,
+			Name:              cfg.ReaderDatabase.Name,
+			Host:              cfg.ReaderDatabase.Host,
+			Port:              cfg.ReaderDatabase.Port,
+			MaxOpenConns:      cfg.ReaderDatabase.MaxOpenConns,
+			MaxIdleConns:      cfg.ReaderDatabase.MaxIdleConns,
+			SSL:               cfg.ReaderDatabase.SSL,
+			Replicas:          cfg.ReaderDatabase.Replicas,
+			MaxReplicationLag: cfg.ReaderDatabase.MaxReplicationLag,
 		}, "pgx", "postgres")
 	}()
 
@@ -214,6 +225,14 @@ func main() {
 
 	wg.Wait()
 
+	if utils.Config.DbAutoMigrate {
+		logrus.Infof("applying db schema migrations")
+		err := db.ApplyEmbeddedDbSchema(-2)
+		if err != nil {
+			logrus.Fatalf("error applying db schema migrations: %v", err)
+		}
+	}
+
 	if utils.Config.TieredCacheProvider != "redis" {
 		logrus.Fatalf("no cache provider set, please set TierdCacheProvider (example redis)")
 	}
@@ -261,7 +280,12 @@ func main() {
 		}
 
 		go services.StartHistoricPriceService()
+		go db.BigtableClient.ReplayMutationQueue()
 		go exporter.Start(rpcClient)
+
+		if utils.Config.ConsistencyAudit.Enabled {
+			go services.StartConsistencyAuditService(rpcClient)
+		}
 	}
 
 	if cfg.Frontend.Enabled {
@@ -277,6 +301,7 @@ func main() {
 		apiV1Router := router.PathPrefix("/api/v1").Subrouter()
 		router.PathPrefix("/api/v1/docs/").Handler(httpSwagger.WrapHandler)
 		apiV1Router.HandleFunc("/latestState", handlers.ApiLatestState).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/monitoring/consistency", handlers.ApiConsistencyReport).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/epoch/{epoch}", handlers.ApiEpoch).Methods("GET", "OPTIONS")
 
 		apiV1Router.HandleFunc("/epoch/{epoch}/blocks", handlers.ApiEpochSlots).Methods("GET", "OPTIONS")
@@ -288,6 +313,7 @@ func main() {
 		apiV1Router.HandleFunc("/slot/{slot}/proposerslashings", handlers.ApiSlotProposerSlashings).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/slot/{slot}/voluntaryexits", handlers.ApiSlotVoluntaryExits).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/slot/{slot}/withdrawals", handlers.ApiSlotWithdrawals).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/slotviz", handlers.ApiSlotViz).Methods("GET", "OPTIONS")
 
 		// deprecated, use slot equivalents
 		apiV1Router.HandleFunc("/block/{slotOrHash}", handlers.ApiSlots).Methods("GET", "OPTIONS")
@@ -296,6 +322,7 @@ func main() {
 		apiV1Router.HandleFunc("/block/{slot}/attesterslashings", handlers.ApiSlotAttesterSlashings).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/block/{slot}/proposerslashings", handlers.ApiSlotProposerSlashings).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/block/{slot}/voluntaryexits", handlers.ApiSlotVoluntaryExits).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/block/{slot}/production", handlers.ApiBlockProduction).Methods("GET", "OPTIONS")
 
 		apiV1Router.HandleFunc("/sync_committee/{period}", handlers.ApiSyncCommittee).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/eth1deposit/{txhash}", handlers.ApiEth1Deposit).Methods("GET", "OPTIONS")
@@ -303,51 +330,108 @@ func main() {
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}", handlers.ApiValidatorGet).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator", handlers.ApiValidatorPost).Methods("POST", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/withdrawals", handlers.ApiValidatorWithdrawals).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/withdrawal-estimate", handlers.ApiValidatorWithdrawalEstimate).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/exit-readiness", handlers.ApiValidatorExitReadiness).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/fee-recipients", handlers.ApiValidatorFeeRecipients).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/relay-registrations", handlers.ApiValidatorRelayRegistrations).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/sync_committees", handlers.ApiValidatorSyncCommittees).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/blsChange", handlers.ApiValidatorBlsChange).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/credential-changes", handlers.ApiValidatorCredentialChanges).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/balancehistory", handlers.ApiValidatorBalanceHistory).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/incomedetailhistory", handlers.ApiValidatorIncomeDetailsHistory).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/performance", handlers.ApiValidatorPerformance).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/execution/performance", handlers.ApiValidatorExecutionPerformance).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/mev", handlers.ApiValidatorMevIncome).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/luck", handlers.ApiValidatorLuck).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/dutycalendar", handlers.ApiValidatorDutyCalendar).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/calculator/staking", handlers.ApiStakingCalculator).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/attestations", handlers.ApiValidatorAttestations).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/proposals", handlers.ApiValidatorProposals).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/deposits", handlers.ApiValidatorDeposits).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/deposit-origin", handlers.ApiValidatorDepositOrigin).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/attestationefficiency", handlers.ApiValidatorAttestationEfficiency).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/attestationeffectiveness", handlers.ApiValidatorAttestationEffectiveness).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/effectiveness-score", handlers.ApiValidatorEffectivenessScore).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/effectiveness/formula", handlers.ApiEffectivenessScoreFormula).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/stats/{index}", handlers.ApiValidatorDailyStats).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/eth1/{address}", handlers.ApiValidatorByEth1Address).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validator/withdrawalCredentials/{withdrawalCredentialsOrEth1address}", handlers.ApiWithdrawalCredentialsValidators).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validators", handlers.ApiValidatorSetSnapshot).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validators/queue", handlers.ApiValidatorQueue).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/validators/proposalLuck", handlers.ApiProposalLuck).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validators/dvt", handlers.ApiValidatorsDvt).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validators/{index}/entity", handlers.ApiValidatorEntity).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/graffitiwall", handlers.ApiGraffitiwall).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/chart/{chart}", handlers.ApiChart).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/charts/{series}", handlers.ApiChartSeries).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/network/incidents", handlers.ApiNetworkIncidents).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/chain/forks", handlers.ApiChainForks).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/user/token", handlers.APIGetToken).Methods("POST", "OPTIONS")
+		apiV1Router.HandleFunc("/user/token/revoke", handlers.APIRevokeToken).Methods("POST", "OPTIONS")
 		apiV1Router.HandleFunc("/dashboard/data/allbalances", handlers.DashboardDataBalanceCombined).Methods("GET", "OPTIONS") // consensus & execution
 		apiV1Router.HandleFunc("/dashboard/data/balances", handlers.DashboardDataBalance).Methods("GET", "OPTIONS")            // new app versions
 		apiV1Router.HandleFunc("/dashboard/data/balance", handlers.APIDashboardDataBalance).Methods("GET", "OPTIONS")          // old app versions
 		apiV1Router.HandleFunc("/dashboard/data/proposals", handlers.DashboardDataProposals).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/dashboard/tax", handlers.DashboardTaxReport).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/dashboard/shared/{publicId}", handlers.ApiSharedValidatorDashboard).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/dashboard/shared/{publicId}/groups", handlers.ValidatorDashboardGroupsData).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/dashboard/shared/{publicId}/summary", handlers.ValidatorDashboardSummaryData).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/dashboard/shared/{publicId}/dutycalendar", handlers.ValidatorDashboardDutyCalendarData).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/dashboard/shared/{publicId}/charts", handlers.ValidatorDashboardCharts).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/dashboard/shared/{publicId}/charts/{chartId}/series", handlers.ValidatorDashboardChartSeries).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/dashboard/address/{address}", handlers.WithdrawalAddressDashboardData).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/stripe/webhook", handlers.StripeWebhook).Methods("POST")
 		apiV1Router.HandleFunc("/stats/{apiKey}/{machine}", handlers.ClientStatsPostOld).Methods("POST", "OPTIONS")
 		apiV1Router.HandleFunc("/stats/{apiKey}", handlers.ClientStatsPostOld).Methods("POST", "OPTIONS")
 		apiV1Router.HandleFunc("/client/metrics", handlers.ClientStatsPostNew).Methods("POST", "OPTIONS")
 		apiV1Router.HandleFunc("/app/dashboard", handlers.ApiDashboard).Methods("POST", "OPTIONS")
+		apiV1Router.Handle("/app/widget", utils.AuthorizedAPIMiddleware(handlers.OAuthScopeMiddleware(http.HandlerFunc(handlers.ApiAppWidget)))).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/relays/marketshare", handlers.ApiRelaysMarketShare).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/staking-flows", handlers.ApiStakingFlows).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/l2-flows", handlers.ApiL2Flows).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/validators/projection", handlers.ApiValidatorSetProjection).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/blocks/quality/leaderboard", handlers.ApiBlockQualityLeaderboard).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/rocketpool/stats", handlers.ApiRocketpoolStats).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/rocketpool/validator/{indexOrPubkey}", handlers.ApiRocketpoolValidators).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/rocketpool/node/{address}", handlers.ApiRocketpoolNode).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/lido/operators", handlers.ApiLidoOperators).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/pools/apr", handlers.ApiPoolsApr).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/pools/risk", handlers.ApiPoolsRisk).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/compare", handlers.ApiCompare).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/ethstore/{day}", handlers.ApiEthStoreDay).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/price/{currency}", handlers.ApiPrice).Methods("GET", "OPTIONS")
 
 		apiV1Router.HandleFunc("/execution/gasnow", handlers.ApiEth1GasNowData).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/execution/block/{blockNumber}", handlers.ApiETH1ExecBlocks).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/tx/{hash}/internal", handlers.ApiEth1TxInternal).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/tx/{hash}/decoded", handlers.ApiEth1TxDecoded).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/tx/{hash}/userops", handlers.ApiEth1TxUserOps).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/userops/{hash}", handlers.ApiEth1UserOp).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/mempool/stats", handlers.ApiMempoolStats).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/execution/{addressIndexOrPubkey}/produced", handlers.ApiETH1AccountProducedBlocks).Methods("GET", "OPTIONS")
 
 		apiV1Router.HandleFunc("/execution/address/{address}", handlers.ApiEth1Address).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/execution/address/{address}/erc20tokens", handlers.ApiEth1AddressERC20Tokens).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/address/{address}/erc20", handlers.ApiEth1AddressERC20Transfers).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/address/{address}/nfts", handlers.ApiEth1AddressNFTs).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/address/{address}/userops", handlers.ApiEth1AddressUserOps).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/execution/address/{address}/labels", handlers.ApiEth1AddressLabels).Methods("GET", "OPTIONS")
 
 		apiV1Router.HandleFunc("/validator/{indexOrPubkey}/widget", handlers.GetMobileWidgetStatsGet).Methods("GET")
+		apiV1Router.HandleFunc("/validator/{index}/name", handlers.ApiValidatorName).Methods("GET", "OPTIONS")
 		apiV1Router.HandleFunc("/dashboard/widget", handlers.GetMobileWidgetStatsPost).Methods("POST")
 		apiV1Router.HandleFunc("/ens/lookup/{domain}", handlers.ResolveEnsDomain).Methods("GET", "OPTIONS")
+		apiV1Router.HandleFunc("/search", handlers.ApiSearch).Methods("GET", "OPTIONS")
 		apiV1Router.Use(utils.CORSMiddleware)
 
+		err = handlers.RegisterNetworkApiRoutes(router, apiV1Router)
+		if err != nil {
+			logrus.Fatalf("error registering network api routes: %v", err)
+		}
+
 		apiV1AuthRouter := apiV1Router.PathPrefix("/user").Subrouter()
 		apiV1AuthRouter.HandleFunc("/mobile/notify/register", handlers.MobileNotificationUpdatePOST).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/notifications/mute", handlers.MobileNotificationMutePOST).Methods("POST", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/mobile/settings", handlers.MobileDeviceSettings).Methods("GET", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/mobile/settings", handlers.MobileDeviceSettingsPOST).Methods("POST", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/validator/saved", handlers.MobileTagedValidators).Methods("GET", "OPTIONS")
@@ -355,23 +439,59 @@ func main() {
 
 		apiV1AuthRouter.HandleFunc("/validator/{pubkey}/add", handlers.UserValidatorWatchlistAdd).Methods("POST", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/validator/{pubkey}/remove", handlers.UserValidatorWatchlistRemove).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/validator/fee-recipients", handlers.UserExpectedFeeRecipients).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/validator/{pubkey}/fee-recipient", handlers.UserExpectedFeeRecipientSet).Methods("PUT", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/validator/{pubkey}/fee-recipient", handlers.UserExpectedFeeRecipientDelete).Methods("DELETE", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/dashboard/save", handlers.UserDashboardWatchlistAdd).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/validators/import", handlers.ValidatorKeysImport).Methods("POST", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/dashboard/remove", handlers.UserDashboardWatchlistRemove).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/embed/sign", handlers.ApiEmbedSign).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/embed/keys", handlers.ApiEmbedKeys).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/embed/keys/{key}", handlers.ApiEmbedKeyRevoke).Methods("DELETE", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards", handlers.UserValidatorDashboards).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards", handlers.UserValidatorDashboardCreate).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}", handlers.UserValidatorDashboard).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}", handlers.UserValidatorDashboardUpdate).Methods("PUT", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}", handlers.UserValidatorDashboardDelete).Methods("DELETE", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/groups", handlers.ValidatorDashboardGroupsData).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/summary", handlers.ValidatorDashboardSummaryData).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/dutycalendar", handlers.ValidatorDashboardDutyCalendarData).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/charts", handlers.ValidatorDashboardCharts).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/charts", handlers.UserValidatorDashboardChartCreate).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/charts/{chartId}", handlers.UserValidatorDashboardChartUpdate).Methods("PUT", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/charts/{chartId}", handlers.UserValidatorDashboardChartDelete).Methods("DELETE", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/charts/{chartId}/series", handlers.ValidatorDashboardChartSeries).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/share", handlers.UserValidatorDashboardShare).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/share/{publicId}", handlers.UserValidatorDashboardUnshare).Methods("DELETE", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/organization", handlers.UserValidatorDashboardOrganizationShare).Methods("PUT", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/dashboards/{dashboardId}/organization", handlers.UserValidatorDashboardOrganizationUnshare).Methods("DELETE", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/notifications/bundled/subscribe", handlers.MultipleUsersNotificationsSubscribe).Methods("POST", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/notifications/bundled/unsubscribe", handlers.MultipleUsersNotificationsUnsubscribe).Methods("POST", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/notifications/subscribe", handlers.UserNotificationsSubscribe).Methods("POST", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/notifications/unsubscribe", handlers.UserNotificationsUnsubscribe).Methods("POST", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/notifications", handlers.UserNotificationsSubscribed).Methods("POST", "GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/notifications/subscriptions", handlers.UserNotificationSubscriptions).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/notifications/subscriptions", handlers.UserNotificationSubscriptionsCreate).Methods("POST", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/notifications/subscriptions/{subscriptionId}", handlers.UserNotificationSubscription).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/notifications/subscriptions/{subscriptionId}", handlers.UserNotificationSubscriptionUpdate).Methods("PUT", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/notifications/subscriptions/{subscriptionId}", handlers.UserNotificationSubscriptionDelete).Methods("DELETE", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/stats", handlers.ClientStats).Methods("GET", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/stats/{offset}/{limit}", handlers.ClientStats).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/machines", handlers.ClientStatsMachines).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/machine/{name}/history", handlers.ClientStatsHistory).Methods("GET", "OPTIONS")
+		apiV1AuthRouter.HandleFunc("/audit", handlers.ApiUserAuditLog).Methods("GET", "OPTIONS")
 		apiV1AuthRouter.HandleFunc("/ethpool", handlers.RegisterEthpoolSubscription).Methods("POST", "OPTIONS")
 
 		apiV1AuthRouter.Use(utils.CORSMiddleware)
 		apiV1AuthRouter.Use(utils.AuthorizedAPIMiddleware)
+		apiV1AuthRouter.Use(handlers.OAuthScopeMiddleware)
 
 		router.HandleFunc("/api/healthz", handlers.ApiHealthz).Methods("GET", "HEAD")
 		router.HandleFunc("/api/healthz-loadbalancer", handlers.ApiHealthzLoadbalancer).Methods("GET", "HEAD")
 
+		if cache.TieredCache != nil {
+			price.SetCache(cache.TieredCache)
+		}
 		logrus.Infof("initializing prices")
 		price.Init(utils.Config.Chain.ClConfig.DepositChainID, utils.Config.Eth1ErigonEndpoint, utils.Config.Frontend.ClCurrency, utils.Config.Frontend.ElCurrency)
 
@@ -411,6 +531,12 @@ func main() {
 			router.HandleFunc("/", handlers.Index).Methods("GET")
 			router.HandleFunc("/latestState", handlers.LatestState).Methods("GET")
 			router.HandleFunc("/launchMetrics", handlers.SlotVizMetrics).Methods("GET")
+			router.HandleFunc("/embed/validator/{index}", handlers.EmbedValidator).Methods("GET")
+			router.HandleFunc("/embed/network/participation", handlers.EmbedNetworkParticipation).Methods("GET")
+			router.HandleFunc("/embed/queue", handlers.EmbedQueue).Methods("GET")
+			router.HandleFunc("/img/og/validator/{index:[0-9]+}.png", handlers.OgImageValidator).Methods("GET")
+			router.HandleFunc("/img/og/slot/{slot:[0-9]+}.png", handlers.OgImageSlot).Methods("GET")
+			router.HandleFunc("/img/og/validator/{index:[0-9]+}/milestone/{milestone}.png", handlers.OgImageValidatorMilestone).Methods("GET")
 			router.HandleFunc("/index/data", handlers.IndexPageData).Methods("GET")
 			router.HandleFunc("/slot/{slotOrHash}", handlers.Slot).Methods("GET")
 			router.HandleFunc("/slot/{slotOrHash}/deposits", handlers.SlotDepositData).Methods("GET")
@@ -431,9 +557,12 @@ func main() {
 			router.HandleFunc("/address/{address}/transactions", handlers.Eth1AddressTransactions).Methods("GET")
 			router.HandleFunc("/address/{address}/internalTxns", handlers.Eth1AddressInternalTransactions).Methods("GET")
 			router.HandleFunc("/address/{address}/blobTxns", handlers.Eth1AddressBlobTransactions).Methods("GET")
+			router.HandleFunc("/address/{address}/readContract", handlers.Eth1AddressReadContract).Methods("GET")
+			router.HandleFunc("/address/{address}/userOps", handlers.Eth1AddressUserOperations).Methods("GET")
 			router.HandleFunc("/address/{address}/erc20", handlers.Eth1AddressErc20Transactions).Methods("GET")
 			router.HandleFunc("/address/{address}/erc721", handlers.Eth1AddressErc721Transactions).Methods("GET")
 			router.HandleFunc("/address/{address}/erc1155", handlers.Eth1AddressErc1155Transactions).Methods("GET")
+			router.HandleFunc("/address/{address}/verify", handlers.Eth1AddressVerifyContract).Methods("POST")
 			router.HandleFunc("/token/{token}", handlers.Eth1Token).Methods("GET")
 			router.HandleFunc("/token/{token}/transfers", handlers.Eth1TokenTransfers).Methods("GET")
 			router.HandleFunc("/transactions", handlers.Eth1Transactions).Methods("GET")
@@ -443,6 +572,7 @@ func main() {
 			router.HandleFunc("/tx/{hash}", handlers.Eth1TransactionTx).Methods("GET")
 			router.HandleFunc("/tx/{hash}/data", handlers.Eth1TransactionTxData).Methods("GET")
 			router.HandleFunc("/mempool", handlers.MempoolView).Methods("GET")
+			router.HandleFunc("/mempool/data", handlers.MempoolViewData).Methods("GET")
 			router.HandleFunc("/burn", handlers.Burn).Methods("GET")
 			router.HandleFunc("/burn/data", handlers.BurnPageData).Methods("GET")
 			router.HandleFunc("/gasnow", handlers.GasNow).Methods("GET")
@@ -459,6 +589,8 @@ func main() {
 			router.HandleFunc("/epoch/{epoch}", handlers.Epoch).Methods("GET")
 			router.HandleFunc("/epochs", handlers.Epochs).Methods("GET")
 			router.HandleFunc("/epochs/data", handlers.EpochsData).Methods("GET")
+			router.HandleFunc("/sync_committees", handlers.SyncCommittees).Methods("GET")
+			router.HandleFunc("/sync_committee/{period}", handlers.SyncCommittee).Methods("GET")
 
 			router.HandleFunc("/validator/{index}", handlers.Validator).Methods("GET")
 			router.HandleFunc("/validator/{index}/proposedblocks", handlers.ValidatorProposedBlocks).Methods("GET")
@@ -469,6 +601,7 @@ func main() {
 			router.HandleFunc("/validator/{pubkey}/deposits", handlers.ValidatorDeposits).Methods("GET")
 			router.HandleFunc("/validator/{index}/slashings", handlers.ValidatorSlashings).Methods("GET")
 			router.HandleFunc("/validator/{index}/effectiveness", handlers.ValidatorAttestationInclusionEffectiveness).Methods("GET")
+			router.HandleFunc("/validator/{index}/milestone/{milestone}", handlers.ValidatorMilestone).Methods("GET")
 			router.HandleFunc("/validator/{pubkey}/name", handlers.SaveValidatorName).Methods("POST")
 			router.HandleFunc("/watchlist/add", handlers.UsersModalAddValidator).Methods("POST")
 			router.HandleFunc("/validator/{pubkey}/remove", handlers.UserValidatorWatchlistRemove).Methods("POST")
@@ -477,8 +610,11 @@ func main() {
 			router.HandleFunc("/validators/data", handlers.ValidatorsData).Methods("GET")
 			router.HandleFunc("/validators/slashings", handlers.ValidatorsSlashings).Methods("GET")
 			router.HandleFunc("/validators/slashings/data", handlers.ValidatorsSlashingsData).Methods("GET")
+			router.HandleFunc("/exits", handlers.Exits).Methods("GET")
+			router.HandleFunc("/exits/data", handlers.ExitsData).Methods("GET")
 			router.HandleFunc("/validators/leaderboard", handlers.ValidatorsLeaderboard).Methods("GET")
 			router.HandleFunc("/validators/leaderboard/data", handlers.ValidatorsLeaderboardData).Methods("GET")
+			router.HandleFunc("/validators/block-quality", handlers.BlockQuality).Methods("GET")
 			router.HandleFunc("/validators/withdrawals", handlers.Withdrawals).Methods("GET")
 			router.HandleFunc("/validators/withdrawals/data", handlers.WithdrawalsData).Methods("GET")
 			router.HandleFunc("/validators/withdrawals/bls", handlers.BLSChangeData).Methods("GET")
@@ -501,17 +637,57 @@ func main() {
 			router.HandleFunc("/dashboard/data/validators", handlers.DashboardDataValidators).Methods("GET")
 			router.HandleFunc("/dashboard/data/withdrawal", handlers.DashboardDataWithdrawals).Methods("GET")
 			router.HandleFunc("/dashboard/data/effectiveness", handlers.DashboardDataEffectiveness).Methods("GET")
+			router.HandleFunc("/dashboard/data/percentiles", handlers.DashboardDataPercentiles).Methods("GET")
+			router.HandleFunc("/dashboard/data/dutycalendar", handlers.DashboardDataDutyCalendar).Methods("GET")
+			router.HandleFunc("/dashboard/tax", handlers.DashboardTaxReport).Methods("GET")
+			router.HandleFunc("/dashboard/shared/{publicId}", handlers.SharedValidatorDashboard).Methods("GET")
+			router.HandleFunc("/dashboard/shared/{publicId}/groups", handlers.ValidatorDashboardGroupsData).Methods("GET")
+			router.HandleFunc("/dashboard/shared/{publicId}/summary", handlers.ValidatorDashboardSummaryData).Methods("GET")
+			router.HandleFunc("/dashboard/shared/{publicId}/dutycalendar", handlers.ValidatorDashboardDutyCalendarData).Methods("GET")
+			router.HandleFunc("/dashboard/shared/{publicId}/charts", handlers.ValidatorDashboardCharts).Methods("GET")
+			router.HandleFunc("/dashboard/shared/{publicId}/charts/{chartId}/series", handlers.ValidatorDashboardChartSeries).Methods("GET")
+			router.HandleFunc("/dashboard/address/{address}", handlers.WithdrawalAddressDashboard).Methods("GET")
+			router.HandleFunc("/dashboard/address/{address}/summary", handlers.WithdrawalAddressDashboardData).Methods("GET")
+			router.HandleFunc("/dashboard/saved", handlers.UserValidatorDashboards).Methods("GET")
+			router.HandleFunc("/dashboard/saved", handlers.UserValidatorDashboardCreate).Methods("POST")
+			router.HandleFunc("/dashboard/saved/{dashboardId}/groups", handlers.ValidatorDashboardGroupsData).Methods("GET")
+			router.HandleFunc("/dashboard/saved/{dashboardId}/summary", handlers.ValidatorDashboardSummaryData).Methods("GET")
+			router.HandleFunc("/dashboard/saved/{dashboardId}/dutycalendar", handlers.ValidatorDashboardDutyCalendarData).Methods("GET")
+			router.HandleFunc("/dashboard/saved/{dashboardId}/charts", handlers.ValidatorDashboardCharts).Methods("GET")
+			router.HandleFunc("/dashboard/saved/{dashboardId}/charts", handlers.UserValidatorDashboardChartCreate).Methods("POST")
+			router.HandleFunc("/dashboard/saved/{dashboardId}/charts/{chartId}", handlers.UserValidatorDashboardChartUpdate).Methods("PUT")
+			router.HandleFunc("/dashboard/saved/{dashboardId}/charts/{chartId}", handlers.UserValidatorDashboardChartDelete).Methods("DELETE")
+			router.HandleFunc("/dashboard/saved/{dashboardId}/charts/{chartId}/series", handlers.ValidatorDashboardChartSeries).Methods("GET")
+			router.HandleFunc("/dashboard/saved/{dashboardId}", handlers.UserValidatorDashboardUpdate).Methods("PUT")
+			router.HandleFunc("/dashboard/saved/{dashboardId}", handlers.UserValidatorDashboardDelete).Methods("DELETE")
+			router.HandleFunc("/dashboard/saved/{dashboardId}/share", handlers.UserValidatorDashboardShare).Methods("POST")
+			router.HandleFunc("/dashboard/saved/{dashboardId}/share/{publicId}", handlers.UserValidatorDashboardUnshare).Methods("DELETE")
+			router.HandleFunc("/dashboard/saved/{dashboardId}/organization", handlers.UserValidatorDashboardOrganizationShare).Methods("PUT")
+			router.HandleFunc("/dashboard/saved/{dashboardId}/organization", handlers.UserValidatorDashboardOrganizationUnshare).Methods("DELETE")
 			router.HandleFunc("/dashboard/data/earnings", handlers.DashboardDataEarnings).Methods("GET")
 			router.HandleFunc("/graffitiwall", handlers.Graffitiwall).Methods("GET")
 			router.HandleFunc("/calculator", handlers.StakingCalculator).Methods("GET")
 			router.HandleFunc("/search", handlers.Search).Methods("POST")
 			router.HandleFunc("/search/{type}/{search}", handlers.SearchAhead).Methods("GET")
 			router.HandleFunc("/imprint", handlers.Imprint).Methods("GET")
+			router.HandleFunc("/language/{lang}", handlers.SetLanguage).Methods("GET")
 			router.HandleFunc("/mobile", handlers.MobilePage).Methods("GET")
 			router.HandleFunc("/tools/unitConverter", handlers.UnitConverter).Methods("GET")
 			router.HandleFunc("/tools/broadcast", handlers.Broadcast).Methods("GET")
 			router.HandleFunc("/tools/broadcast", handlers.BroadcastPost).Methods("POST")
 			router.HandleFunc("/tools/broadcast/status/{jobID}", handlers.BroadcastStatus).Methods("GET")
+			if utils.Config.Frontend.Faucet.Enabled {
+				if err := faucet.Init(); err != nil {
+					logrus.Fatalf("error initializing faucet: %v", err)
+				}
+				router.HandleFunc("/faucet", handlers.Faucet).Methods("GET")
+				router.HandleFunc("/faucet", handlers.FaucetPost).Methods("POST")
+			}
+			if utils.Config.DataExport.Enabled {
+				dataexport.Init()
+				router.HandleFunc("/data", handlers.Data).Methods("GET")
+				router.HandleFunc("/data/{dataset}/{day:[0-9]+}", handlers.DataDownload).Methods("GET")
+			}
 
 			router.HandleFunc("/tables/{tableId}/state", handlers.GetDataTableStateChanges).Methods("GET")
 			router.HandleFunc("/tables/{tableId}/state", handlers.SetDataTableStateChanges).Methods("PUT")
@@ -522,13 +698,22 @@ func main() {
 			router.HandleFunc("/stakingServices", handlers.StakingServices).Methods("GET")
 
 			router.HandleFunc("/ethClients", handlers.EthClientsServices).Methods("GET")
+			router.HandleFunc("/forks", handlers.ChainForks).Methods("GET")
 			router.HandleFunc("/pools", handlers.Pools).Methods("GET")
+			router.HandleFunc("/pools/apr", handlers.PoolsApr).Methods("GET")
+			router.HandleFunc("/pools/risk", handlers.PoolsRisk).Methods("GET")
+			router.HandleFunc("/compare", handlers.Compare).Methods("GET")
 			router.HandleFunc("/relays", handlers.Relays).Methods("GET")
+			router.HandleFunc("/l2-flows", handlers.L2Flows).Methods("GET")
+			router.HandleFunc("/relays/anomalies", handlers.RelaysAnomalies).Methods("GET")
+			router.HandleFunc("/censorship", handlers.Censorship).Methods("GET")
 			router.HandleFunc("/pools/rocketpool", handlers.PoolsRocketpool).Methods("GET")
 			router.HandleFunc("/pools/rocketpool/data/minipools", handlers.PoolsRocketpoolDataMinipools).Methods("GET")
 			router.HandleFunc("/pools/rocketpool/data/nodes", handlers.PoolsRocketpoolDataNodes).Methods("GET")
 			router.HandleFunc("/pools/rocketpool/data/dao_proposals", handlers.PoolsRocketpoolDataDAOProposals).Methods("GET")
 			router.HandleFunc("/pools/rocketpool/data/dao_members", handlers.PoolsRocketpoolDataDAOMembers).Methods("GET")
+			router.HandleFunc("/pools/rocketpool/node/{address}", handlers.PoolsRocketpoolNode).Methods("GET")
+			router.HandleFunc("/pools/lido", handlers.PoolsLido).Methods("GET")
 
 			router.HandleFunc("/advertisewithus", handlers.AdvertiseWithUs).Methods("GET")
 			router.HandleFunc("/advertisewithus", handlers.AdvertiseWithUsPost).Methods("POST")
@@ -547,6 +732,10 @@ func main() {
 			signUpRouter := router.PathPrefix("/").Subrouter()
 			signUpRouter.HandleFunc("/login", handlers.Login).Methods("GET")
 			signUpRouter.HandleFunc("/login", handlers.LoginPost).Methods("POST")
+			signUpRouter.HandleFunc("/login/2fa", handlers.Login2FA).Methods("GET")
+			signUpRouter.HandleFunc("/login/2fa", handlers.Login2FAPost).Methods("POST")
+			signUpRouter.HandleFunc("/login/webauthn", handlers.LoginWebAuthnOptions).Methods("GET")
+			signUpRouter.HandleFunc("/login/passkey", handlers.LoginPasskeyPost).Methods("POST")
 			signUpRouter.HandleFunc("/logout", handlers.Logout).Methods("GET")
 			signUpRouter.HandleFunc("/register", handlers.Register).Methods("GET")
 			signUpRouter.HandleFunc("/register", handlers.RegisterPost).Methods("POST")
@@ -576,12 +765,32 @@ func main() {
 			authRouter.HandleFunc("/settings/flags", handlers.UserUpdateFlagsPost).Methods("POST")
 			authRouter.HandleFunc("/settings/delete", handlers.UserDeletePost).Methods("POST")
 			authRouter.HandleFunc("/settings/email", handlers.UserUpdateEmailPost).Methods("POST")
+			authRouter.HandleFunc("/settings/locale", handlers.UserUpdateLocalePost).Methods("POST")
+			authRouter.HandleFunc("/security", handlers.UserSecurity).Methods("GET")
+			authRouter.HandleFunc("/sessions/{sessionId}/revoke", handlers.UserSessionRevokePost).Methods("POST")
+			authRouter.HandleFunc("/organization", handlers.UserOrganization).Methods("GET")
+			authRouter.HandleFunc("/organization", handlers.UserOrganizationCreatePost).Methods("POST")
+			authRouter.HandleFunc("/organization/delete", handlers.UserOrganizationDeletePost).Methods("POST")
+			authRouter.HandleFunc("/organization/leave", handlers.UserOrganizationLeavePost).Methods("POST")
+			authRouter.HandleFunc("/organization/invites", handlers.UserOrganizationInvitePost).Methods("POST")
+			authRouter.HandleFunc("/organization/invites/{inviteId}/revoke", handlers.UserOrganizationInviteRevokePost).Methods("POST")
+			authRouter.HandleFunc("/organization/invites/{inviteId}/accept", handlers.UserOrganizationInviteAcceptPost).Methods("POST")
+			authRouter.HandleFunc("/organization/invites/{inviteId}/decline", handlers.UserOrganizationInviteDeclinePost).Methods("POST")
+			authRouter.HandleFunc("/organization/members/{memberId}/role", handlers.UserOrganizationMemberRolePost).Methods("POST")
+			authRouter.HandleFunc("/organization/members/{memberId}/remove", handlers.UserOrganizationMemberRemovePost).Methods("POST")
+			authRouter.HandleFunc("/organization/webhooks/{webhookId}/share", handlers.UserOrganizationWebhookSharePost).Methods("POST")
+			authRouter.HandleFunc("/apps/{deviceId}/revoke", handlers.UserOAuthAppRevokePost).Methods("POST")
+			authRouter.HandleFunc("/webauthn/register", handlers.UserWebAuthnRegisterOptions).Methods("GET")
+			authRouter.HandleFunc("/webauthn/register", handlers.UserWebAuthnRegisterPost).Methods("POST")
+			authRouter.HandleFunc("/webauthn/recovery", handlers.UserRecoveryCodesPost).Methods("POST")
+			authRouter.HandleFunc("/webauthn/{credentialId}/delete", handlers.UserWebAuthnCredentialDeletePost).Methods("POST")
 			authRouter.HandleFunc("/notifications", handlers.UserNotificationsCenter).Methods("GET")
 			authRouter.HandleFunc("/notifications/channels", handlers.UsersNotificationChannels).Methods("POST")
 			authRouter.HandleFunc("/notifications/data", handlers.UserNotificationsData).Methods("GET")
 			authRouter.HandleFunc("/notifications/subscribe", handlers.UserNotificationsSubscribe).Methods("POST")
 			authRouter.HandleFunc("/notifications/network/update", handlers.UserModalAddNetworkEvent).Methods("POST")
 			authRouter.HandleFunc("/watchlist/add", handlers.UsersModalAddValidator).Methods("POST")
+			authRouter.HandleFunc("/validators/import", handlers.ValidatorKeysImport).Methods("POST")
 			authRouter.HandleFunc("/watchlist/remove", handlers.UserModalRemoveSelectedValidator).Methods("POST")
 			authRouter.HandleFunc("/watchlist/update", handlers.UserModalManageNotificationModal).Methods("POST")
 			authRouter.HandleFunc("/notifications/unsubscribe", handlers.UserNotificationsUnsubscribe).Methods("POST")
@@ -593,6 +802,16 @@ func main() {
 			authRouter.HandleFunc("/ad_configuration/delete", handlers.AdConfigurationDeletePost).Methods("POST")
 			authRouter.HandleFunc("/explorer_configuration", handlers.ExplorerConfiguration).Methods("GET")
 			authRouter.HandleFunc("/explorer_configuration", handlers.ExplorerConfigurationPost).Methods("POST")
+			authRouter.HandleFunc("/validator_name_claims", handlers.ValidatorNameClaims).Methods("GET")
+			authRouter.HandleFunc("/validator_name_claims", handlers.ValidatorNameClaimsPost).Methods("POST")
+			authRouter.HandleFunc("/address_labels", handlers.AddressLabels).Methods("GET")
+			authRouter.HandleFunc("/address_labels", handlers.AddressLabelsPost).Methods("POST")
+			authRouter.HandleFunc("/address_labels/submit", handlers.AddressLabelSubmit).Methods("POST")
+			authRouter.HandleFunc("/export_gaps", handlers.ExportGaps).Methods("GET")
+			authRouter.HandleFunc("/feature_flags", handlers.FeatureFlags).Methods("GET")
+			authRouter.HandleFunc("/feature_flags", handlers.FeatureFlagsPost).Methods("POST")
+			authRouter.HandleFunc("/log_levels", handlers.LogLevels).Methods("GET")
+			authRouter.HandleFunc("/log_levels", handlers.LogLevelsPost).Methods("POST")
 
 			authRouter.HandleFunc("/notifications-center", handlers.UserNotificationsCenter).Methods("GET")
 			authRouter.HandleFunc("/notifications-center/removeall", handlers.RemoveAllValidatorsAndUnsubscribe).Methods("POST")
@@ -634,21 +853,23 @@ func main() {
 
 		}
 
+		router.Use(tracing.HttpMiddleware)
+		router.Use(utils.RequestIDMiddleware)
 		if utils.Config.Metrics.Enabled {
 			router.Use(metrics.HttpMiddleware)
 		}
+		router.Use(db.QueryBudgetMiddleware)
 
 		ratelimit.Init()
 		router.Use(ratelimit.HttpMiddleware)
 
 		n := negroni.New(negroni.NewRecovery())
-		n.Use(gzip.Gzip(gzip.DefaultCompression))
 
 		pa := &proxyaddr.ProxyAddr{}
 		pa.Init(proxyaddr.CIDRLoopback)
 		n.Use(pa)
 
-		n.UseHandler(utils.SessionStore.SCS.LoadAndSave(router))
+		n.UseHandler(utils.CompressionMiddleware(utils.SessionStore.SCS.LoadAndSave(router)))
 
 		if utils.Config.Frontend.HttpWriteTimeout == 0 {
 			utils.Config.Frontend.HttpWriteTimeout = time.Second * 15
@@ -684,6 +905,24 @@ func main() {
 		}(utils.Config.Metrics.Address)
 	}
 
+	if utils.Config.AdminApi.Enabled {
+		if utils.Config.AdminApi.ApiKey == "" {
+			logrus.Fatal("admin api is enabled but no api key is configured")
+		}
+		go func(addr string) {
+			logrus.Infof("serving admin api on %v", addr)
+			srv := &http.Server{
+				Addr:         addr,
+				Handler:      handlers.AdminApiRouter(),
+				ReadTimeout:  time.Second * 10,
+				WriteTimeout: time.Minute,
+			}
+			if err := srv.ListenAndServe(); err != nil {
+				logrus.WithError(err).Fatal("Error serving admin api")
+			}
+		}(utils.Config.AdminApi.Address)
+	}
+
 	if utils.Config.Frontend.ShowDonors.Enabled {
 		services.InitGitCoinFeed()
 	}
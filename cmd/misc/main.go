@@ -77,14 +77,14 @@ func main() {
 	statsPartitionCommand := commands.StatsMigratorCommand{}
 
 	configPath := flag.String("config", "config/default.config.yml", "Path to the config file")
-	flag.StringVar(&opts.Command, "command", "", "command to run, available: updateAPIKey, applyDbSchema, initBigtableSchema, epoch-export, debug-rewards, debug-blocks, clear-bigtable, index-old-eth1-blocks, update-aggregation-bits, historic-prices-export, index-missing-blocks, export-epoch-missed-slots, migrate-last-attestation-slot-bigtable, export-genesis-validators, update-block-finalization-sequentially, nameValidatorsByRanges, export-stats-totals, export-sync-committee-periods, export-sync-committee-validator-stats, partition-validator-stats, migrate-app-purchases, disable-user-per-email, validate-firebase-tokens")
+	flag.StringVar(&opts.Command, "command", "", "command to run, available: updateAPIKey, migrate (up|down|status), applyDbSchema, initBigtableSchema, epoch-export, debug-rewards, debug-blocks, clear-bigtable, index-old-eth1-blocks, update-aggregation-bits, historic-prices-export, index-missing-blocks, export-epoch-missed-slots, migrate-last-attestation-slot-bigtable, export-genesis-validators, update-block-finalization-sequentially, nameValidatorsByRanges, export-stats-totals, export-sync-committee-periods, export-sync-committee-validator-stats, partition-validator-stats, migrate-app-purchases, disable-user-per-email, validate-firebase-tokens, backfill-validator-milestones")
 	flag.Uint64Var(&opts.StartEpoch, "start-epoch", 0, "start epoch")
 	flag.Uint64Var(&opts.EndEpoch, "end-epoch", 0, "end epoch")
 	flag.Uint64Var(&opts.User, "user", 0, "user id")
 	flag.Uint64Var(&opts.StartDay, "day-start", 0, "start day to debug")
 	flag.Uint64Var(&opts.EndDay, "day-end", 0, "end day to debug")
 	flag.Uint64Var(&opts.Validator, "validator", 0, "validator to check for")
-	flag.Int64Var(&opts.TargetVersion, "target-version", -2, "Db migration target version, use -2 to apply up to the latest version, -1 to apply only the next version or the specific versions")
+	flag.Int64Var(&opts.TargetVersion, "target-version", -2, "Db migration target version, use -2 to apply up to the latest version, -1 to apply only the next version or the specific versions. For migrate down a negative version rolls back only the latest migration")
 	flag.StringVar(&opts.Table, "table", "", "big table table")
 	flag.StringVar(&opts.Family, "family", "", "big table family")
 	flag.StringVar(&opts.Key, "key", "", "big table key")
@@ -220,6 +220,31 @@ func main() {
 		if err != nil {
 			logrus.WithError(err).Fatal("error updating API key")
 		}
+	case "migrate":
+		// the action follows the flags, e.g. -command migrate down
+		switch flag.Arg(0) {
+		case "up", "":
+			logrus.Infof("applying db schema migrations")
+			err := db.ApplyEmbeddedDbSchema(opts.TargetVersion)
+			if err != nil {
+				logrus.WithError(err).Fatal("error applying db schema migrations")
+			}
+			logrus.Infof("db schema migrations applied successfully")
+		case "down":
+			logrus.Infof("rolling back db schema migrations")
+			err := db.RollbackEmbeddedDbSchema(opts.TargetVersion)
+			if err != nil {
+				logrus.WithError(err).Fatal("error rolling back db schema migrations")
+			}
+			logrus.Infof("db schema migrations rolled back successfully")
+		case "status":
+			err := db.PrintEmbeddedDbSchemaStatus()
+			if err != nil {
+				logrus.WithError(err).Fatal("error retrieving db schema status")
+			}
+		default:
+			logrus.Fatalf("unknown migrate action %v, available: up, down, status", flag.Arg(0))
+		}
 	case "applyDbSchema":
 		logrus.Infof("applying db schema")
 		err := db.ApplyEmbeddedDbSchema(opts.TargetVersion)
@@ -442,6 +467,8 @@ func main() {
 		err = fixEpochs()
 	case "validate-firebase-tokens":
 		err = validateFirebaseTokens()
+	case "backfill-validator-milestones":
+		err = db.BackfillValidatorFirstEthMilestones(opts.StartDay, opts.EndDay)
 	default:
 		utils.LogFatal(nil, fmt.Sprintf("unknown command %s", opts.Command), 2)
 	}
@@ -1587,7 +1614,7 @@ func indexOldEth1Blocks(startBlock uint64, endBlock uint64, batchSize uint64, co
 	logrus.Infof("transformerFlag: %v", transformerFlag)
 	transformerList := strings.Split(transformerFlag, ",")
 	if transformerFlag == "all" {
-		transformerList = []string{"TransformBlock", "TransformTx", "TransformBlobTx", "TransformItx", "TransformERC20", "TransformERC721", "TransformERC1155", "TransformWithdrawals", "TransformUncle", "TransformEnsNameRegistered", "TransformContract"}
+		transformerList = []string{"TransformBlock", "TransformTx", "TransformBlobTx", "TransformItx", "TransformERC20", "TransformERC721", "TransformERC1155", "TransformWithdrawals", "TransformUncle", "TransformEnsNameRegistered", "TransformContract", "TransformUserOperations", "TransformL2BridgeTx"}
 	} else if len(transformerList) == 0 {
 		utils.LogError(nil, "no transformer functions provided", 0)
 		return
@@ -1622,6 +1649,10 @@ func indexOldEth1Blocks(startBlock uint64, endBlock uint64, batchSize uint64, co
 			importENSChanges = true
 		case "TransformContract":
 			transforms = append(transforms, bt.TransformContract)
+		case "TransformUserOperations":
+			transforms = append(transforms, bt.TransformUserOperations)
+		case "TransformL2BridgeTx":
+			transforms = append(transforms, bt.TransformL2BridgeTx)
 		default:
 			utils.LogError(nil, "Invalid transformer flag %v", 0)
 			return
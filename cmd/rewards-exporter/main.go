@@ -202,14 +202,13 @@ func main() {
 				if err != nil {
 					logrus.Errorf("error marking rewards_exported as true for epoch %v: %v", e, err)
 				}
-				services.ReportStatus("rewardsExporter", "Running", nil)
-
 				if e > lastExportedEpoch {
 					lastExportedEpoch = e
 				}
+				services.ReportExporterProgress("rewardsExporter", "Running", services.ExporterUnitEpoch, lastExportedEpoch, latestFinalizedEpoch, 1)
 			}
 
-			services.ReportStatus("rewardsExporter", "Running", nil)
+			services.ReportExporterProgress("rewardsExporter", "Running", services.ExporterUnitEpoch, lastExportedEpoch, latestFinalizedEpoch, 0)
 			time.Sleep(*sleepDuration)
 
 		}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"math/big"
@@ -9,11 +10,13 @@ import (
 	"time"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
+	"github.com/gobitfly/eth2-beaconchain-explorer/dataexport"
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
 	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
 	"github.com/gobitfly/eth2-beaconchain-explorer/price"
 	"github.com/gobitfly/eth2-beaconchain-explorer/rpc"
 	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/tracing"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 	"github.com/gobitfly/eth2-beaconchain-explorer/version"
@@ -23,13 +26,23 @@ import (
 )
 
 type options struct {
-	configPath                string
-	statisticsDayToExport     int64
-	statisticsDaysToExport    string
-	statisticsValidatorToggle bool
-	statisticsChartToggle     bool
-	statisticsGraffitiToggle  bool
-	resetStatus               bool
+	configPath                    string
+	statisticsDayToExport         int64
+	statisticsDaysToExport        string
+	statisticsValidatorToggle     bool
+	statisticsChartToggle         bool
+	statisticsGraffitiToggle      bool
+	statisticsMevIncomeToggle     bool
+	statisticsRelaysToggle        bool
+	statisticsCensorshipToggle    bool
+	statisticsStakingFlowsToggle  bool
+	statisticsL2FlowsToggle       bool
+	statisticsAttPackingToggle    bool
+	statisticsEffectivenessToggle bool
+	statisticsLeaderboardToggle   bool
+	dataExportToggle              bool
+	clickhouseBackfillToggle      bool
+	resetStatus                   bool
 }
 
 var opt = &options{}
@@ -41,6 +54,16 @@ func main() {
 	flag.BoolVar(&opt.statisticsValidatorToggle, "validators.enabled", false, "Toggle exporting validator statistics")
 	flag.BoolVar(&opt.statisticsChartToggle, "charts.enabled", false, "Toggle exporting chart series")
 	flag.BoolVar(&opt.statisticsGraffitiToggle, "graffiti.enabled", false, "Toggle exporting graffiti statistics")
+	flag.BoolVar(&opt.statisticsMevIncomeToggle, "mevIncome.enabled", false, "Toggle exporting mev income statistics")
+	flag.BoolVar(&opt.statisticsRelaysToggle, "relays.enabled", false, "Toggle exporting relay and builder market share statistics")
+	flag.BoolVar(&opt.statisticsCensorshipToggle, "censorship.enabled", false, "Toggle exporting censorship statistics")
+	flag.BoolVar(&opt.statisticsStakingFlowsToggle, "stakingFlows.enabled", false, "Toggle exporting daily deposit and withdrawal flow statistics")
+	flag.BoolVar(&opt.statisticsL2FlowsToggle, "l2Flows.enabled", false, "Toggle exporting daily layer 2 bridge flow statistics")
+	flag.BoolVar(&opt.statisticsAttPackingToggle, "attestationPacking.enabled", false, "Toggle exporting attestation packing statistics of blocks")
+	flag.BoolVar(&opt.statisticsEffectivenessToggle, "effectiveness.enabled", false, "Toggle exporting daily validator effectiveness scores")
+	flag.BoolVar(&opt.statisticsLeaderboardToggle, "leaderboard.enabled", false, "Toggle refreshing the validator leaderboard whenever new validator statistics have been exported")
+	flag.BoolVar(&opt.dataExportToggle, "dataExport.enabled", false, "Toggle exporting daily dumps of attestations, blocks and validator statistics to the object store configured in dataExport")
+	flag.BoolVar(&opt.clickhouseBackfillToggle, "clickhouse.backfill", false, "Copy the already exported validator statistics of the days from postgres to clickhouse")
 	flag.BoolVar(&opt.resetStatus, "validators.reset", false, "Export stats independet if they have already been exported previously")
 
 	versionFlag := flag.Bool("version", false, "Show version and exit")
@@ -61,6 +84,12 @@ func main() {
 	}
 	utils.Config = cfg
 
+	shutdownTracing, err := tracing.Init()
+	if err != nil {
+		utils.LogFatal(err, "error initializing tracing", 0)
+	}
+	defer shutdownTracing(context.Background())
+
 	if utils.Config.Metrics.Enabled {
 		go func(addr string) {
 			logrus.Infof("serving metrics on %v", addr)
@@ -121,6 +150,25 @@ func main() {
 	defer db.FrontendReaderDB.Close()
 	defer db.FrontendWriterDB.Close()
 
+	if cfg.ClickHouse.StatisticsSinkEnabled {
+		db.MustInitClickhouseDB(&types.DatabaseConfig{
+			Username:     cfg.ClickHouse.WriterDatabase.Username,
+			Password:     cfg.ClickHouse.WriterDatabase.Password,
+			Name:         cfg.ClickHouse.WriterDatabase.Name,
+			Host:         cfg.ClickHouse.WriterDatabase.Host,
+			Port:         cfg.ClickHouse.WriterDatabase.Port,
+			MaxOpenConns: cfg.ClickHouse.WriterDatabase.MaxOpenConns,
+			MaxIdleConns: cfg.ClickHouse.WriterDatabase.MaxIdleConns,
+			SSL:          true,
+		}, nil, "clickhouse", "clickhouse")
+		defer db.ClickhouseWriterDb.Close()
+
+		err = db.InitClickhouseStatisticsSchema()
+		if err != nil {
+			logrus.Fatalf("error initializing clickhouse statistics schema: %v", err)
+		}
+	}
+
 	_, err = db.InitBigtable(cfg.Bigtable.Project, cfg.Bigtable.Instance, fmt.Sprintf("%d", utils.Config.Chain.ClConfig.DepositChainID), utils.Config.RedisCacheEndpoint)
 	if err != nil {
 		logrus.Fatalf("error connecting to bigtable: %v", err)
@@ -128,6 +176,13 @@ func main() {
 
 	price.Init(utils.Config.Chain.ClConfig.DepositChainID, utils.Config.Eth1ErigonEndpoint, utils.Config.Frontend.ClCurrency, utils.Config.Frontend.ElCurrency)
 
+	if opt.dataExportToggle {
+		if !utils.Config.DataExport.Enabled {
+			logrus.Fatalf("exporting data dumps requires dataExport.enabled")
+		}
+		dataexport.Init()
+	}
+
 	if utils.Config.TieredCacheProvider != "redis" {
 		logrus.Fatalf("No cache provider set. Please set TierdCacheProvider (example redis)")
 	}
@@ -178,6 +233,25 @@ func main() {
 			}
 		}
 
+		if opt.clickhouseBackfillToggle {
+			if !cfg.ClickHouse.StatisticsSinkEnabled {
+				logrus.Fatalf("clickhouse backfill requires clickhouse.statisticsSinkEnabled")
+			}
+			logrus.Infof("copying validator statistics for days %v-%v to clickhouse", firstDay, lastDay)
+			for d := firstDay; d <= lastDay; d++ {
+				data, err := db.GatherStatisticsForDay(int64(d))
+				if err != nil {
+					utils.LogError(err, fmt.Errorf("error gathering stats for day %v", d), 0)
+					break
+				}
+				err = db.WriteValidatorStatisticsToClickhouse(d, data)
+				if err != nil {
+					utils.LogError(err, fmt.Errorf("error copying stats for day %v to clickhouse", d), 0)
+					break
+				}
+			}
+		}
+
 		if opt.statisticsChartToggle {
 			logrus.Infof("exporting chart series for days %v-%v", firstDay, lastDay)
 			for d := firstDay; d <= lastDay; d++ {
@@ -204,6 +278,76 @@ func main() {
 			}
 		}
 
+		if opt.statisticsMevIncomeToggle {
+			for d := firstDay; d <= lastDay; d++ {
+				err = db.WriteMevIncomeStatisticsForDay(int64(d))
+				if err != nil {
+					logrus.Errorf("error exporting mev-income-stats from day %v: %v", d, err)
+					break
+				}
+			}
+		}
+
+		if opt.statisticsRelaysToggle {
+			for d := firstDay; d <= lastDay; d++ {
+				err = db.WriteRelayStatisticsForDay(int64(d))
+				if err != nil {
+					logrus.Errorf("error exporting relay-stats from day %v: %v", d, err)
+					break
+				}
+			}
+		}
+
+		if opt.statisticsCensorshipToggle {
+			for d := firstDay; d <= lastDay; d++ {
+				err = db.WriteCensorshipStatisticsForDay(int64(d))
+				if err != nil {
+					logrus.Errorf("error exporting censorship-stats from day %v: %v", d, err)
+					break
+				}
+			}
+		}
+
+		if opt.statisticsStakingFlowsToggle {
+			for d := firstDay; d <= lastDay; d++ {
+				err = db.WriteStakingFlowStatisticsForDay(int64(d))
+				if err != nil {
+					logrus.Errorf("error exporting staking-flow-stats from day %v: %v", d, err)
+					break
+				}
+			}
+		}
+
+		if opt.statisticsL2FlowsToggle {
+			for d := firstDay; d <= lastDay; d++ {
+				err = db.WriteL2BridgeFlowStatisticsForDay(int64(d))
+				if err != nil {
+					logrus.Errorf("error exporting l2-bridge-flow-stats from day %v: %v", d, err)
+					break
+				}
+			}
+		}
+
+		if opt.statisticsAttPackingToggle {
+			for d := firstDay; d <= lastDay; d++ {
+				err = db.WriteAttestationPackingStatisticsForDay(int64(d))
+				if err != nil {
+					logrus.Errorf("error exporting attestation-packing-stats from day %v: %v", d, err)
+					break
+				}
+			}
+		}
+
+		if opt.statisticsEffectivenessToggle {
+			for d := firstDay; d <= lastDay; d++ {
+				err = db.WriteValidatorEffectivenessScoresForDay(int64(d))
+				if err != nil {
+					logrus.Errorf("error exporting effectiveness-scores from day %v: %v", d, err)
+					break
+				}
+			}
+		}
+
 		return
 	} else if opt.statisticsDayToExport >= 0 {
 
@@ -236,9 +380,62 @@ func main() {
 				logrus.Errorf("error exporting chart series from day %v: %v", opt.statisticsDayToExport, err)
 			}
 		}
+
+		if opt.statisticsMevIncomeToggle {
+			err = db.WriteMevIncomeStatisticsForDay(opt.statisticsDayToExport)
+			if err != nil {
+				logrus.Errorf("error exporting mev-income-stats from day %v: %v", opt.statisticsDayToExport, err)
+			}
+		}
+
+		if opt.statisticsRelaysToggle {
+			err = db.WriteRelayStatisticsForDay(opt.statisticsDayToExport)
+			if err != nil {
+				logrus.Errorf("error exporting relay-stats from day %v: %v", opt.statisticsDayToExport, err)
+			}
+		}
+
+		if opt.statisticsCensorshipToggle {
+			err = db.WriteCensorshipStatisticsForDay(opt.statisticsDayToExport)
+			if err != nil {
+				logrus.Errorf("error exporting censorship-stats from day %v: %v", opt.statisticsDayToExport, err)
+			}
+		}
+
+		if opt.statisticsStakingFlowsToggle {
+			err = db.WriteStakingFlowStatisticsForDay(opt.statisticsDayToExport)
+			if err != nil {
+				logrus.Errorf("error exporting staking-flow-stats from day %v: %v", opt.statisticsDayToExport, err)
+			}
+		}
+
+		if opt.statisticsL2FlowsToggle {
+			err = db.WriteL2BridgeFlowStatisticsForDay(opt.statisticsDayToExport)
+			if err != nil {
+				logrus.Errorf("error exporting l2-bridge-flow-stats from day %v: %v", opt.statisticsDayToExport, err)
+			}
+		}
+
+		if opt.statisticsAttPackingToggle {
+			err = db.WriteAttestationPackingStatisticsForDay(opt.statisticsDayToExport)
+			if err != nil {
+				logrus.Errorf("error exporting attestation-packing-stats from day %v: %v", opt.statisticsDayToExport, err)
+			}
+		}
+
+		if opt.statisticsEffectivenessToggle {
+			err = db.WriteValidatorEffectivenessScoresForDay(opt.statisticsDayToExport)
+			if err != nil {
+				logrus.Errorf("error exporting effectiveness-scores from day %v: %v", opt.statisticsDayToExport, err)
+			}
+		}
 		return
 	}
 
+	if utils.Config.Retention.Enabled {
+		go services.StartRetentionService()
+	}
+
 	go statisticsLoop(rpcClient)
 
 	utils.WaitForCtrlC()
@@ -247,6 +444,10 @@ func main() {
 }
 
 func statisticsLoop(client rpc.Client) {
+	// the days of the validator statistics and effectiveness scores the validator leaderboard has last been refreshed with
+	leaderboardStatsDay, leaderboardEffectivenessDay := int64(-1), int64(-1)
+	lastReportedDay := uint64(0)
+
 	for {
 
 		var loopError error
@@ -315,6 +516,14 @@ func statisticsLoop(client rpc.Client) {
 					}
 				}
 			}
+
+			hours, err := db.UpdateChartSeriesHourly(latestEpoch)
+			if err != nil {
+				logrus.Errorf("error exporting hourly chart series: %v", err)
+				loopError = err
+			} else {
+				logrus.Infof("exported %v hours of hourly chart series", hours)
+			}
 		}
 
 		if opt.statisticsGraffitiToggle {
@@ -344,15 +553,288 @@ func statisticsLoop(client rpc.Client) {
 			}
 		}
 
-		if loopError == nil {
-			services.ReportStatus("statistics", "Running", nil)
+		if opt.statisticsMevIncomeToggle {
+			mevIncomeStatsStatus := []struct {
+				Day    uint64
+				Status bool
+			}{}
+			err := db.WriterDb.Select(&mevIncomeStatsStatus, "select day, status from mev_income_stats_status")
+			if err != nil {
+				logrus.Errorf("error retrieving mevIncomeStatsStatus: %v", err)
+			} else {
+				mevIncomeStatsStatusMap := map[uint64]bool{}
+				for _, s := range mevIncomeStatsStatus {
+					mevIncomeStatsStatusMap[s.Day] = s.Status
+				}
+				for day := uint64(0); day <= currentDay; day++ {
+					if !mevIncomeStatsStatusMap[day] {
+						logrus.Infof("exporting mev-income-stats for day %v", day)
+						err = db.WriteMevIncomeStatisticsForDay(int64(day))
+						if err != nil {
+							logrus.Errorf("error exporting mev-income-stats for day %v: %v", day, err)
+							loopError = err
+							break
+						}
+					}
+				}
+			}
+		}
+
+		if opt.statisticsRelaysToggle {
+			relayStatsStatus := []struct {
+				Day    uint64
+				Status bool
+			}{}
+			err := db.WriterDb.Select(&relayStatsStatus, "select day, status from relays_daily_stats_status")
+			if err != nil {
+				logrus.Errorf("error retrieving relayStatsStatus: %v", err)
+			} else {
+				relayStatsStatusMap := map[uint64]bool{}
+				for _, s := range relayStatsStatus {
+					relayStatsStatusMap[s.Day] = s.Status
+				}
+				for day := uint64(0); day <= currentDay; day++ {
+					if !relayStatsStatusMap[day] {
+						logrus.Infof("exporting relay-stats for day %v", day)
+						err = db.WriteRelayStatisticsForDay(int64(day))
+						if err != nil {
+							logrus.Errorf("error exporting relay-stats for day %v: %v", day, err)
+							loopError = err
+							break
+						}
+					}
+				}
+			}
+		}
+
+		if opt.statisticsCensorshipToggle {
+			censorshipStatsStatus := []struct {
+				Day    uint64
+				Status bool
+			}{}
+			err := db.WriterDb.Select(&censorshipStatsStatus, "select day, status from censorship_daily_stats_status")
+			if err != nil {
+				logrus.Errorf("error retrieving censorshipStatsStatus: %v", err)
+			} else {
+				censorshipStatsStatusMap := map[uint64]bool{}
+				for _, s := range censorshipStatsStatus {
+					censorshipStatsStatusMap[s.Day] = s.Status
+				}
+				for day := uint64(0); day <= currentDay; day++ {
+					if !censorshipStatsStatusMap[day] {
+						logrus.Infof("exporting censorship-stats for day %v", day)
+						err = db.WriteCensorshipStatisticsForDay(int64(day))
+						if err != nil {
+							logrus.Errorf("error exporting censorship-stats for day %v: %v", day, err)
+							loopError = err
+							break
+						}
+					}
+				}
+			}
+		}
+
+		if opt.statisticsStakingFlowsToggle {
+			stakingFlowsStatus := []struct {
+				Day    uint64
+				Status bool
+			}{}
+			err := db.WriterDb.Select(&stakingFlowsStatus, "select day, status from staking_flows_daily_status")
+			if err != nil {
+				logrus.Errorf("error retrieving stakingFlowsStatus: %v", err)
+			} else {
+				stakingFlowsStatusMap := map[uint64]bool{}
+				for _, s := range stakingFlowsStatus {
+					stakingFlowsStatusMap[s.Day] = s.Status
+				}
+				for day := uint64(0); day <= currentDay; day++ {
+					if !stakingFlowsStatusMap[day] {
+						logrus.Infof("exporting staking-flow-stats for day %v", day)
+						err = db.WriteStakingFlowStatisticsForDay(int64(day))
+						if err != nil {
+							logrus.Errorf("error exporting staking-flow-stats for day %v: %v", day, err)
+							loopError = err
+							break
+						}
+					}
+				}
+			}
+		}
+
+		if opt.statisticsL2FlowsToggle {
+			l2FlowsStatus := []struct {
+				Day    uint64
+				Status bool
+			}{}
+			// the day the exporter first runs is only partially indexed, start with the day after it
+			l2FlowsFirstDay, err := db.GetL2BridgeFlowsFirstDay(currentDay + 1)
+			if err != nil {
+				logrus.Errorf("error retrieving l2FlowsFirstDay: %v", err)
+			} else if err = db.WriterDb.Select(&l2FlowsStatus, "select day, status from l2_bridge_flows_daily_status"); err != nil {
+				logrus.Errorf("error retrieving l2FlowsStatus: %v", err)
+			} else {
+				l2FlowsStatusMap := map[uint64]bool{}
+				for _, s := range l2FlowsStatus {
+					l2FlowsStatusMap[s.Day] = s.Status
+				}
+				for day := l2FlowsFirstDay; day <= currentDay; day++ {
+					if !l2FlowsStatusMap[day] {
+						logrus.Infof("exporting l2-bridge-flow-stats for day %v", day)
+						err = db.WriteL2BridgeFlowStatisticsForDay(int64(day))
+						if err != nil {
+							logrus.Errorf("error exporting l2-bridge-flow-stats for day %v: %v", day, err)
+							loopError = err
+							break
+						}
+					}
+				}
+			}
+		}
+
+		if opt.statisticsAttPackingToggle {
+			attPackingStatus := []struct {
+				Day    uint64
+				Status bool
+			}{}
+			err := db.WriterDb.Select(&attPackingStatus, "select day, status from attestation_packing_status")
+			if err != nil {
+				logrus.Errorf("error retrieving attPackingStatus: %v", err)
+			} else {
+				attPackingStatusMap := map[uint64]bool{}
+				for _, s := range attPackingStatus {
+					attPackingStatusMap[s.Day] = s.Status
+				}
+				for day := uint64(0); day <= currentDay; day++ {
+					if !attPackingStatusMap[day] {
+						logrus.Infof("exporting attestation-packing-stats for day %v", day)
+						err = db.WriteAttestationPackingStatisticsForDay(int64(day))
+						if err != nil {
+							logrus.Errorf("error exporting attestation-packing-stats for day %v: %v", day, err)
+							loopError = err
+							break
+						}
+					}
+				}
+			}
+		}
+
+		if opt.statisticsEffectivenessToggle {
+			effectivenessStatus := []struct {
+				Day    uint64
+				Status bool
+			}{}
+			err := db.WriterDb.Select(&effectivenessStatus, "select day, status from validator_effectiveness_status where version = $1", utils.EffectivenessScoreVersion)
+			if err != nil {
+				logrus.Errorf("error retrieving effectivenessStatus: %v", err)
+			} else {
+				effectivenessStatusMap := map[uint64]bool{}
+				for _, s := range effectivenessStatus {
+					effectivenessStatusMap[s.Day] = s.Status
+				}
+				for day := uint64(0); day <= currentDay; day++ {
+					if !effectivenessStatusMap[day] {
+						logrus.Infof("exporting effectiveness-scores for day %v", day)
+						err = db.WriteValidatorEffectivenessScoresForDay(int64(day))
+						if err != nil {
+							logrus.Errorf("error exporting effectiveness-scores for day %v: %v", day, err)
+							loopError = err
+							break
+						}
+					}
+				}
+			}
+		}
+
+		if opt.statisticsLeaderboardToggle {
+			statsDay, err := db.GetLastExportedStatisticDay()
+			if err != nil && err != db.ErrNoStats {
+				logrus.Errorf("error retreiving latest exported day from the db: %v", err)
+			} else if err == nil {
+				effectivenessDay, ok, err := db.GetLatestEffectivenessScoreDay()
+				if err != nil {
+					logrus.Errorf("error retreiving latest effectiveness score day from the db: %v", err)
+				} else {
+					if !ok {
+						effectivenessDay = 0
+					}
+					if int64(statsDay) != leaderboardStatsDay || int64(effectivenessDay) != leaderboardEffectivenessDay {
+						err = db.RefreshValidatorLeaderboard()
+						if err != nil {
+							logrus.Errorf("error refreshing validator leaderboard: %v", err)
+							loopError = err
+						} else {
+							leaderboardStatsDay, leaderboardEffectivenessDay = int64(statsDay), int64(effectivenessDay)
+						}
+					}
+				}
+			}
+		}
+
+		if opt.dataExportToggle {
+			err := exportDataDumps(previousDay)
+			if err != nil {
+				logrus.Errorf("error exporting data dumps: %v", err)
+				loopError = err
+			}
+		}
+
+		status := "Running"
+		if loopError != nil {
+			status = loopError.Error()
+		}
+		exportedDay, err := db.GetLastExportedStatisticDay()
+		if err != nil && err != db.ErrNoStats {
+			logrus.Errorf("error retreiving latest exported day from the db: %v", err)
+			services.ReportStatus("statistics", status, nil)
 		} else {
-			services.ReportStatus("statistics", loopError.Error(), nil)
+			exportedDays := uint64(0)
+			if exportedDay > lastReportedDay && lastReportedDay > 0 {
+				exportedDays = exportedDay - lastReportedDay
+			}
+			lastReportedDay = exportedDay
+			services.ReportExporterProgress("statistics", status, services.ExporterUnitDay, exportedDay, previousDay, exportedDays)
 		}
 		time.Sleep(time.Minute)
 	}
 }
 
+// exportDataDumps dumps every dataset for the days after its last export up to the previous day, the datasets that
+// require the validator statistics only up to the last day the statistics have been exported for. Datasets that have
+// never been exported start with the last available day.
+func exportDataDumps(previousDay uint64) error {
+	lastExportDays, err := db.GetLastDataExportDays()
+	if err != nil {
+		return err
+	}
+	statsDay, err := db.GetLastExportedStatisticDay()
+	if err != nil && err != db.ErrNoStats {
+		return err
+	}
+	statsExported := err == nil
+
+	for _, dataset := range dataexport.Datasets {
+		lastDay := previousDay
+		if dataset.RequiresStats {
+			if !statsExported {
+				continue
+			}
+			lastDay = statsDay
+		}
+		firstDay := lastDay
+		if day, ok := lastExportDays[dataset.Name]; ok {
+			firstDay = day + 1
+		}
+		for day := firstDay; day <= lastDay; day++ {
+			logrus.Infof("exporting data dump of dataset %v for day %v", dataset.Name, day)
+			err = dataexport.ExportDay(context.Background(), dataset, day)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func clearStatsStatusTable(day uint64) {
 	logrus.Infof("deleting validator_stats_status for day %v", day)
 	_, err := db.WriterDb.Exec("DELETE FROM validator_stats_status WHERE day = $1", day)
@@ -0,0 +1,318 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package lido
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// CSModuleMetaData contains all meta data concerning the CSModule contract.
+var CSModuleMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[],\"name\":\"getNodeOperatorsCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"nodeOperatorId\",\"type\":\"uint256\"}],\"name\":\"getNodeOperatorSummary\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"targetLimitMode\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"targetValidatorsCount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"stuckValidatorsCount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"refundedValidatorsCount\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"stuckPenaltyEndTimestamp\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"totalExitedValidators\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"totalDepositedValidators\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"depositableValidatorsCount\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"nodeOperatorId\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"startIndex\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"keysCount\",\"type\":\"uint256\"}],\"name\":\"getSigningKeys\",\"outputs\":[{\"internalType\":\"bytes\",\"name\":\"\",\"type\":\"bytes\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// CSModuleABI is the input ABI used to generate the binding from.
+// Deprecated: Use CSModuleMetaData.ABI instead.
+var CSModuleABI = CSModuleMetaData.ABI
+
+// CSModule is an auto generated Go binding around an Ethereum contract.
+type CSModule struct {
+	CSModuleCaller     // Read-only binding to the contract
+	CSModuleTransactor // Write-only binding to the contract
+	CSModuleFilterer   // Log filterer for contract events
+}
+
+// CSModuleCaller is an auto generated read-only Go binding around an Ethereum contract.
+type CSModuleCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// CSModuleTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type CSModuleTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// CSModuleFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type CSModuleFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// CSModuleSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type CSModuleSession struct {
+	Contract     *CSModule         // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// CSModuleCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type CSModuleCallerSession struct {
+	Contract *CSModuleCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts   // Call options to use throughout this session
+}
+
+// CSModuleTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type CSModuleTransactorSession struct {
+	Contract     *CSModuleTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts   // Transaction auth options to use throughout this session
+}
+
+// CSModuleRaw is an auto generated low-level Go binding around an Ethereum contract.
+type CSModuleRaw struct {
+	Contract *CSModule // Generic contract binding to access the raw methods on
+}
+
+// CSModuleCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type CSModuleCallerRaw struct {
+	Contract *CSModuleCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// CSModuleTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type CSModuleTransactorRaw struct {
+	Contract *CSModuleTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewCSModule creates a new instance of CSModule, bound to a specific deployed contract.
+func NewCSModule(address common.Address, backend bind.ContractBackend) (*CSModule, error) {
+	contract, err := bindCSModule(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &CSModule{CSModuleCaller: CSModuleCaller{contract: contract}, CSModuleTransactor: CSModuleTransactor{contract: contract}, CSModuleFilterer: CSModuleFilterer{contract: contract}}, nil
+}
+
+// NewCSModuleCaller creates a new read-only instance of CSModule, bound to a specific deployed contract.
+func NewCSModuleCaller(address common.Address, caller bind.ContractCaller) (*CSModuleCaller, error) {
+	contract, err := bindCSModule(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &CSModuleCaller{contract: contract}, nil
+}
+
+// NewCSModuleTransactor creates a new write-only instance of CSModule, bound to a specific deployed contract.
+func NewCSModuleTransactor(address common.Address, transactor bind.ContractTransactor) (*CSModuleTransactor, error) {
+	contract, err := bindCSModule(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &CSModuleTransactor{contract: contract}, nil
+}
+
+// NewCSModuleFilterer creates a new log filterer instance of CSModule, bound to a specific deployed contract.
+func NewCSModuleFilterer(address common.Address, filterer bind.ContractFilterer) (*CSModuleFilterer, error) {
+	contract, err := bindCSModule(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &CSModuleFilterer{contract: contract}, nil
+}
+
+// bindCSModule binds a generic wrapper to an already deployed contract.
+func bindCSModule(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := CSModuleMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_CSModule *CSModuleRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _CSModule.Contract.CSModuleCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_CSModule *CSModuleRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _CSModule.Contract.CSModuleTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_CSModule *CSModuleRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _CSModule.Contract.CSModuleTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_CSModule *CSModuleCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _CSModule.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_CSModule *CSModuleTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _CSModule.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_CSModule *CSModuleTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _CSModule.Contract.contract.Transact(opts, method, params...)
+}
+
+// GetNodeOperatorSummary is a free data retrieval call binding the contract method 0xb3076c3c.
+//
+// Solidity: function getNodeOperatorSummary(uint256 nodeOperatorId) view returns(uint256 targetLimitMode, uint256 targetValidatorsCount, uint256 stuckValidatorsCount, uint256 refundedValidatorsCount, uint256 stuckPenaltyEndTimestamp, uint256 totalExitedValidators, uint256 totalDepositedValidators, uint256 depositableValidatorsCount)
+func (_CSModule *CSModuleCaller) GetNodeOperatorSummary(opts *bind.CallOpts, nodeOperatorId *big.Int) (struct {
+	TargetLimitMode            *big.Int
+	TargetValidatorsCount      *big.Int
+	StuckValidatorsCount       *big.Int
+	RefundedValidatorsCount    *big.Int
+	StuckPenaltyEndTimestamp   *big.Int
+	TotalExitedValidators      *big.Int
+	TotalDepositedValidators   *big.Int
+	DepositableValidatorsCount *big.Int
+}, error) {
+	var out []interface{}
+	err := _CSModule.contract.Call(opts, &out, "getNodeOperatorSummary", nodeOperatorId)
+
+	outstruct := new(struct {
+		TargetLimitMode            *big.Int
+		TargetValidatorsCount      *big.Int
+		StuckValidatorsCount       *big.Int
+		RefundedValidatorsCount    *big.Int
+		StuckPenaltyEndTimestamp   *big.Int
+		TotalExitedValidators      *big.Int
+		TotalDepositedValidators   *big.Int
+		DepositableValidatorsCount *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.TargetLimitMode = *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	outstruct.TargetValidatorsCount = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	outstruct.StuckValidatorsCount = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.RefundedValidatorsCount = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.StuckPenaltyEndTimestamp = *abi.ConvertType(out[4], new(*big.Int)).(**big.Int)
+	outstruct.TotalExitedValidators = *abi.ConvertType(out[5], new(*big.Int)).(**big.Int)
+	outstruct.TotalDepositedValidators = *abi.ConvertType(out[6], new(*big.Int)).(**big.Int)
+	outstruct.DepositableValidatorsCount = *abi.ConvertType(out[7], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetNodeOperatorSummary is a free data retrieval call binding the contract method 0xb3076c3c.
+//
+// Solidity: function getNodeOperatorSummary(uint256 nodeOperatorId) view returns(uint256 targetLimitMode, uint256 targetValidatorsCount, uint256 stuckValidatorsCount, uint256 refundedValidatorsCount, uint256 stuckPenaltyEndTimestamp, uint256 totalExitedValidators, uint256 totalDepositedValidators, uint256 depositableValidatorsCount)
+func (_CSModule *CSModuleSession) GetNodeOperatorSummary(nodeOperatorId *big.Int) (struct {
+	TargetLimitMode            *big.Int
+	TargetValidatorsCount      *big.Int
+	StuckValidatorsCount       *big.Int
+	RefundedValidatorsCount    *big.Int
+	StuckPenaltyEndTimestamp   *big.Int
+	TotalExitedValidators      *big.Int
+	TotalDepositedValidators   *big.Int
+	DepositableValidatorsCount *big.Int
+}, error) {
+	return _CSModule.Contract.GetNodeOperatorSummary(&_CSModule.CallOpts, nodeOperatorId)
+}
+
+// GetNodeOperatorSummary is a free data retrieval call binding the contract method 0xb3076c3c.
+//
+// Solidity: function getNodeOperatorSummary(uint256 nodeOperatorId) view returns(uint256 targetLimitMode, uint256 targetValidatorsCount, uint256 stuckValidatorsCount, uint256 refundedValidatorsCount, uint256 stuckPenaltyEndTimestamp, uint256 totalExitedValidators, uint256 totalDepositedValidators, uint256 depositableValidatorsCount)
+func (_CSModule *CSModuleCallerSession) GetNodeOperatorSummary(nodeOperatorId *big.Int) (struct {
+	TargetLimitMode            *big.Int
+	TargetValidatorsCount      *big.Int
+	StuckValidatorsCount       *big.Int
+	RefundedValidatorsCount    *big.Int
+	StuckPenaltyEndTimestamp   *big.Int
+	TotalExitedValidators      *big.Int
+	TotalDepositedValidators   *big.Int
+	DepositableValidatorsCount *big.Int
+}, error) {
+	return _CSModule.Contract.GetNodeOperatorSummary(&_CSModule.CallOpts, nodeOperatorId)
+}
+
+// GetNodeOperatorsCount is a free data retrieval call binding the contract method 0xa70c70e4.
+//
+// Solidity: function getNodeOperatorsCount() view returns(uint256)
+func (_CSModule *CSModuleCaller) GetNodeOperatorsCount(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _CSModule.contract.Call(opts, &out, "getNodeOperatorsCount")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetNodeOperatorsCount is a free data retrieval call binding the contract method 0xa70c70e4.
+//
+// Solidity: function getNodeOperatorsCount() view returns(uint256)
+func (_CSModule *CSModuleSession) GetNodeOperatorsCount() (*big.Int, error) {
+	return _CSModule.Contract.GetNodeOperatorsCount(&_CSModule.CallOpts)
+}
+
+// GetNodeOperatorsCount is a free data retrieval call binding the contract method 0xa70c70e4.
+//
+// Solidity: function getNodeOperatorsCount() view returns(uint256)
+func (_CSModule *CSModuleCallerSession) GetNodeOperatorsCount() (*big.Int, error) {
+	return _CSModule.Contract.GetNodeOperatorsCount(&_CSModule.CallOpts)
+}
+
+// GetSigningKeys is a free data retrieval call binding the contract method 0x59e25c12.
+//
+// Solidity: function getSigningKeys(uint256 nodeOperatorId, uint256 startIndex, uint256 keysCount) view returns(bytes)
+func (_CSModule *CSModuleCaller) GetSigningKeys(opts *bind.CallOpts, nodeOperatorId *big.Int, startIndex *big.Int, keysCount *big.Int) ([]byte, error) {
+	var out []interface{}
+	err := _CSModule.contract.Call(opts, &out, "getSigningKeys", nodeOperatorId, startIndex, keysCount)
+
+	if err != nil {
+		return *new([]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]byte)).(*[]byte)
+
+	return out0, err
+
+}
+
+// GetSigningKeys is a free data retrieval call binding the contract method 0x59e25c12.
+//
+// Solidity: function getSigningKeys(uint256 nodeOperatorId, uint256 startIndex, uint256 keysCount) view returns(bytes)
+func (_CSModule *CSModuleSession) GetSigningKeys(nodeOperatorId *big.Int, startIndex *big.Int, keysCount *big.Int) ([]byte, error) {
+	return _CSModule.Contract.GetSigningKeys(&_CSModule.CallOpts, nodeOperatorId, startIndex, keysCount)
+}
+
+// GetSigningKeys is a free data retrieval call binding the contract method 0x59e25c12.
+//
+// Solidity: function getSigningKeys(uint256 nodeOperatorId, uint256 startIndex, uint256 keysCount) view returns(bytes)
+func (_CSModule *CSModuleCallerSession) GetSigningKeys(nodeOperatorId *big.Int, startIndex *big.Int, keysCount *big.Int) ([]byte, error) {
+	return _CSModule.Contract.GetSigningKeys(&_CSModule.CallOpts, nodeOperatorId, startIndex, keysCount)
+}
@@ -0,0 +1,27 @@
+package lido
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+//go:generate abigen -abi node_operators_registry.json -out node_operators_registry.go -pkg lido -type NodeOperatorsRegistry
+//go:generate abigen -abi cs_module.json -out cs_module.go -pkg lido -type CSModule
+
+// see: https://docs.lido.fi/deployed-contracts/
+
+const (
+	ModuleCurated = "curated"
+	ModuleCSM     = "csm"
+)
+
+// NodeOperatorsRegistryAddressesByChainID are the addresses of the curated staking module
+var NodeOperatorsRegistryAddressesByChainID = map[uint64]common.Address{
+	1:     common.HexToAddress("0x55032650b14df07b85bF18A3a3eC8E0Af2e028d5"),
+	17000: common.HexToAddress("0x595F64Ddc3856a3b5Ff4f4CC1d1fb4B46cFd2bAC"),
+}
+
+// CSModuleAddressesByChainID are the addresses of the community staking module
+var CSModuleAddressesByChainID = map[uint64]common.Address{
+	1:     common.HexToAddress("0xdA7dE2ECdDfccC6c3AF10108Db212ACBBf9EA83F"),
+	17000: common.HexToAddress("0x4562c3e63c2e586cD1651B958C22F88135aCAd4f"),
+}
@@ -0,0 +1,394 @@
+// Package dataexport writes daily dumps of attestations, blocks and validator statistics as gzipped csv or parquet
+// files to an s3 compatible object store and signs download links for them.
+package dataexport
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/parquet-go/parquet-go"
+)
+
+var logger = utils.NewLogger("dataexport")
+
+// defaultLinkExpiry is the validity of signed download links if none is configured
+const defaultLinkExpiry = time.Hour
+
+// Formats of the dumps, datasets are dumped as csv unless configured otherwise
+const (
+	FormatCsv     = "csv"
+	FormatParquet = "parquet"
+)
+
+// Dataset is a table that is dumped once per day. The query selects the rows of a day with all columns cast to text,
+// $1 and $2 are the first and last slot of the day, or $1 is the day for datasets that require the statistics.
+type Dataset struct {
+	Name        string
+	Description string
+	Columns     []string
+	// RequiresStats is set for datasets that can only be exported once the validator statistics of the day are exported
+	RequiresStats bool
+	query         string
+}
+
+// Datasets are the datasets that are exported, in the order they are listed
+var Datasets = []*Dataset{
+	{
+		Name:        "attestations",
+		Description: "Attestations included in the blocks of the day, one row per aggregate with the indices of the attesting validators",
+		Columns:     []string{"block_slot", "block_index", "block_status", "slot", "committee_index", "beacon_block_root", "source_epoch", "target_epoch", "aggregation_bits", "validators"},
+		query: `
+			SELECT
+				a.block_slot::TEXT,
+				a.block_index::TEXT,
+				COALESCE(b.status, ''),
+				a.slot::TEXT,
+				a.committeeindex::TEXT,
+				ENCODE(a.beaconblockroot, 'hex'),
+				a.source_epoch::TEXT,
+				a.target_epoch::TEXT,
+				ENCODE(a.aggregationbits, 'hex'),
+				ARRAY_TO_STRING(a.validators, ' ')
+			FROM blocks_attestations a
+			LEFT JOIN blocks b ON b.blockroot = a.block_root AND b.slot = a.block_slot
+			WHERE a.block_slot >= $1 AND a.block_slot <= $2
+			ORDER BY a.block_slot, a.block_index`,
+	},
+	{
+		Name:        "blocks",
+		Description: "Blocks and missed slots of the day, status 1 is proposed, 2 missed and 3 orphaned",
+		Columns:     []string{"slot", "epoch", "block_root", "parent_root", "proposer", "status", "graffiti", "attestations", "deposits", "voluntary_exits", "proposer_slashings", "attester_slashings", "withdrawals", "sync_participation", "exec_block_number", "exec_block_hash", "exec_fee_recipient", "exec_gas_used", "exec_gas_limit", "exec_base_fee_per_gas", "exec_transactions"},
+		query: `
+			SELECT
+				slot::TEXT,
+				epoch::TEXT,
+				ENCODE(blockroot, 'hex'),
+				ENCODE(parentroot, 'hex'),
+				proposer::TEXT,
+				status,
+				COALESCE(graffiti_text, ''),
+				attestationscount::TEXT,
+				depositscount::TEXT,
+				voluntaryexitscount::TEXT,
+				proposerslashingscount::TEXT,
+				attesterslashingscount::TEXT,
+				withdrawalcount::TEXT,
+				syncaggregate_participation::TEXT,
+				COALESCE(exec_block_number::TEXT, ''),
+				COALESCE(ENCODE(exec_block_hash, 'hex'), ''),
+				COALESCE(ENCODE(exec_fee_recipient, 'hex'), ''),
+				COALESCE(exec_gas_used::TEXT, ''),
+				COALESCE(exec_gas_limit::TEXT, ''),
+				COALESCE(exec_base_fee_per_gas::TEXT, ''),
+				exec_transactions_count::TEXT
+			FROM blocks
+			WHERE slot >= $1 AND slot <= $2
+			ORDER BY slot, status`,
+	},
+	{
+		Name:          "validator_stats",
+		Description:   "Daily statistics of every validator, balances in gwei and execution layer rewards in wei",
+		Columns:       []string{"validator_index", "day", "start_balance", "end_balance", "end_effective_balance", "missed_attestations", "orphaned_attestations", "proposed_blocks", "missed_blocks", "orphaned_blocks", "participated_sync", "missed_sync", "orphaned_sync", "deposits_amount", "withdrawals_amount", "cl_rewards_gwei", "el_rewards_wei", "mev_rewards_wei"},
+		RequiresStats: true,
+		query: `
+			SELECT
+				validatorindex::TEXT,
+				day::TEXT,
+				COALESCE(start_balance::TEXT, ''),
+				COALESCE(end_balance::TEXT, ''),
+				COALESCE(end_effective_balance::TEXT, ''),
+				COALESCE(missed_attestations::TEXT, ''),
+				COALESCE(orphaned_attestations::TEXT, ''),
+				COALESCE(proposed_blocks::TEXT, ''),
+				COALESCE(missed_blocks::TEXT, ''),
+				COALESCE(orphaned_blocks::TEXT, ''),
+				COALESCE(participated_sync::TEXT, ''),
+				COALESCE(missed_sync::TEXT, ''),
+				COALESCE(orphaned_sync::TEXT, ''),
+				COALESCE(deposits_amount::TEXT, ''),
+				COALESCE(withdrawals_amount::TEXT, ''),
+				COALESCE(cl_rewards_gwei::TEXT, ''),
+				COALESCE(el_rewards_wei::TEXT, ''),
+				COALESCE(mev_rewards_wei::TEXT, '')
+			FROM validator_stats
+			WHERE day = $1
+			ORDER BY validatorindex`,
+	},
+}
+
+// Format returns the configured format of the dumps of the dataset
+func (d *Dataset) Format() string {
+	if f := utils.Config.DataExport.Formats[d.Name]; f != "" {
+		return f
+	}
+	return FormatCsv
+}
+
+// GetDataset returns the dataset with the name or nil if there is none
+func GetDataset(name string) *Dataset {
+	for _, d := range Datasets {
+		if d.Name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+var client *s3.Client
+
+// Init creates the client of the object store the dumps are written to
+func Init() {
+	for name, format := range utils.Config.DataExport.Formats {
+		if GetDataset(name) == nil {
+			logger.Fatalf("unknown dataset %v in data export formats", name)
+		}
+		if format != FormatCsv && format != FormatParquet {
+			logger.Fatalf("unknown data export format %v of dataset %v", format, name)
+		}
+	}
+
+	cfg := utils.Config.DataExport.S3
+	region := cfg.Region
+	if region == "" {
+		region = "auto"
+	}
+	resolver := aws.EndpointResolverWithOptionsFunc(func(service, r string, options ...interface{}) (aws.Endpoint, error) {
+		return aws.Endpoint{
+			PartitionID:       "aws",
+			URL:               cfg.Endpoint,
+			SigningRegion:     region,
+			HostnameImmutable: true,
+		}, nil
+	})
+	client = s3.NewFromConfig(aws.Config{
+		Region:                      region,
+		Credentials:                 credentials.NewStaticCredentialsProvider(cfg.AccessKeyId, cfg.AccessKeySecret, ""),
+		EndpointResolverWithOptions: resolver,
+	}, func(o *s3.Options) {
+		o.UsePathStyle = true
+	})
+}
+
+// fileExtension returns the extension of dump files of the format
+func fileExtension(format string) string {
+	if format == FormatParquet {
+		return "parquet"
+	}
+	return "csv.gz"
+}
+
+// objectKey returns the key of the dump of the dataset of the day, e.g. mainnet/blocks/blocks_00001234.csv.gz
+func objectKey(dataset *Dataset, day uint64) string {
+	prefix := utils.Config.DataExport.Prefix
+	if prefix == "" {
+		prefix = utils.Config.Chain.Name
+	}
+	return fmt.Sprintf("%s/%s/%s_%08d.%s", prefix, dataset.Name, dataset.Name, day, fileExtension(dataset.Format()))
+}
+
+// ExportDay dumps the rows of the dataset of the day to the object store and records the export. The dump is written
+// to a temporary file first as uploads require the size of the object.
+func ExportDay(ctx context.Context, dataset *Dataset, day uint64) error {
+	start := time.Now()
+	slotsPerDay := utils.EpochsPerDay() * utils.Config.Chain.ClConfig.SlotsPerEpoch
+	firstSlot := day * slotsPerDay
+	lastSlot := firstSlot + slotsPerDay - 1
+
+	format := dataset.Format()
+	file, err := os.CreateTemp("", fmt.Sprintf("dataexport-%s-*.%s", dataset.Name, fileExtension(format)))
+	if err != nil {
+		return fmt.Errorf("error creating temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	var rowCount uint64
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(utils.Config.DataExport.S3.Bucket),
+		Body:   file,
+	}
+	if format == FormatParquet {
+		rowCount, err = writeParquet(ctx, file, dataset, firstSlot, lastSlot, day)
+		input.ContentType = aws.String("application/vnd.apache.parquet")
+	} else {
+		rowCount, err = writeCsv(ctx, file, dataset, firstSlot, lastSlot, day)
+		input.ContentType = aws.String("text/csv")
+		input.ContentEncoding = aws.String("gzip")
+	}
+	if err != nil {
+		return err
+	}
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("error retrieving size of dump: %w", err)
+	}
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("error rewinding dump: %w", err)
+	}
+
+	key := objectKey(dataset, day)
+	input.Key = aws.String(key)
+	input.ContentLength = size
+	_, err = client.PutObject(ctx, input)
+	if err != nil {
+		return fmt.Errorf("error uploading dump of dataset %v of day %v: %w", dataset.Name, day, err)
+	}
+
+	err = db.SaveDataExport(&types.DataExport{
+		Dataset:   dataset.Name,
+		Day:       day,
+		ObjectKey: key,
+		Rows:      rowCount,
+		SizeBytes: uint64(size),
+	})
+	if err != nil {
+		return err
+	}
+	logger.Infof("exported %v rows of dataset %v of day %v (%v bytes) in %v", rowCount, dataset.Name, day, size, time.Since(start))
+	return nil
+}
+
+// writeCsv writes the gzipped rows of the dataset with a header line to w and returns the amount of rows
+func writeCsv(ctx context.Context, w io.Writer, dataset *Dataset, firstSlot, lastSlot, day uint64) (uint64, error) {
+	gz := gzip.NewWriter(w)
+	cw := csv.NewWriter(gz)
+
+	err := cw.Write(dataset.Columns)
+	if err != nil {
+		return 0, fmt.Errorf("error writing csv header: %w", err)
+	}
+
+	record := make([]string, len(dataset.Columns))
+	rowCount, err := queryDataset(ctx, dataset, firstSlot, lastSlot, day, func(values []sql.NullString) error {
+		for i, v := range values {
+			record[i] = v.String
+		}
+		err := cw.Write(record)
+		if err != nil {
+			return fmt.Errorf("error writing csv row: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	cw.Flush()
+	if err = cw.Error(); err != nil {
+		return 0, fmt.Errorf("error flushing csv: %w", err)
+	}
+	if err = gz.Close(); err != nil {
+		return 0, fmt.Errorf("error closing gzip writer: %w", err)
+	}
+	return rowCount, nil
+}
+
+// writeParquet writes the rows of the dataset as zstd compressed parquet to w and returns the amount of rows. All
+// columns are nullable strings, parquet orders the columns of a schema by name.
+func writeParquet(ctx context.Context, w io.Writer, dataset *Dataset, firstSlot, lastSlot, day uint64) (uint64, error) {
+	group := parquet.Group{}
+	for _, c := range dataset.Columns {
+		group[c] = parquet.Optional(parquet.String())
+	}
+	schema := parquet.NewSchema(dataset.Name, group)
+
+	// leafs maps the columns of the dataset to the columns of the schema
+	leafs := make([]int, len(dataset.Columns))
+	for i, c := range dataset.Columns {
+		leaf, ok := schema.Lookup(c)
+		if !ok {
+			return 0, fmt.Errorf("error looking up parquet column %v of dataset %v", c, dataset.Name)
+		}
+		leafs[i] = leaf.ColumnIndex
+	}
+
+	pw := parquet.NewWriter(w, schema, parquet.Compression(&parquet.Zstd))
+	row := make(parquet.Row, len(dataset.Columns))
+	rowCount, err := queryDataset(ctx, dataset, firstSlot, lastSlot, day, func(values []sql.NullString) error {
+		for i, v := range values {
+			if v.Valid {
+				row[leafs[i]] = parquet.ByteArrayValue([]byte(v.String)).Level(0, 1, leafs[i])
+			} else {
+				row[leafs[i]] = parquet.NullValue().Level(0, 0, leafs[i])
+			}
+		}
+		_, err := pw.WriteRows([]parquet.Row{row})
+		if err != nil {
+			return fmt.Errorf("error writing parquet row: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if err = pw.Close(); err != nil {
+		return 0, fmt.Errorf("error closing parquet writer: %w", err)
+	}
+	return rowCount, nil
+}
+
+// queryDataset calls fn with the values of every row of the dataset of the day and returns the amount of rows
+func queryDataset(ctx context.Context, dataset *Dataset, firstSlot, lastSlot, day uint64, fn func(values []sql.NullString) error) (uint64, error) {
+	args := []interface{}{firstSlot, lastSlot}
+	if dataset.RequiresStats {
+		args = []interface{}{day}
+	}
+	rows, err := db.ReaderDb.QueryContext(ctx, dataset.query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("error querying dataset %v of day %v: %w", dataset.Name, day, err)
+	}
+	defer rows.Close()
+
+	values := make([]sql.NullString, len(dataset.Columns))
+	dest := make([]interface{}, len(values))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	rowCount := uint64(0)
+	for rows.Next() {
+		err = rows.Scan(dest...)
+		if err != nil {
+			return 0, fmt.Errorf("error scanning row of dataset %v of day %v: %w", dataset.Name, day, err)
+		}
+		err = fn(values)
+		if err != nil {
+			return 0, err
+		}
+		rowCount++
+	}
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating rows of dataset %v of day %v: %w", dataset.Name, day, err)
+	}
+	return rowCount, nil
+}
+
+// SignedUrl returns a presigned download link of the object
+func SignedUrl(ctx context.Context, key string) (string, error) {
+	expiry := utils.Config.DataExport.LinkExpiry
+	if expiry <= 0 {
+		expiry = defaultLinkExpiry
+	}
+	req, err := s3.NewPresignClient(client).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(utils.Config.DataExport.S3.Bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expiry))
+	if err != nil {
+		return "", fmt.Errorf("error signing download link of %v: %w", key, err)
+	}
+	return req.URL, nil
+}
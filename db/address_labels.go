@@ -0,0 +1,114 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/lib/pq"
+)
+
+// GetAddressLabels returns the approved labels of an address
+func GetAddressLabels(address []byte) ([]*types.AddressLabel, error) {
+	labels := []*types.AddressLabel{}
+	err := ReaderDb.Select(&labels, `
+		SELECT id, address, label, category, source, note, status, submitted_by, created_ts, reviewed_ts, reviewed_by
+		FROM address_labels
+		WHERE address = $1 AND status = $2
+		ORDER BY category, label`, address, types.AddressLabelApproved)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving labels of address %#x: %w", address, err)
+	}
+	return labels, nil
+}
+
+// GetAddressLabelsForAddresses returns the approved labels of the addresses, keyed by the string of the address bytes
+func GetAddressLabelsForAddresses(addresses [][]byte) (map[string][]*types.AddressLabel, error) {
+	res := make(map[string][]*types.AddressLabel)
+	if len(addresses) == 0 {
+		return res, nil
+	}
+
+	labels := []*types.AddressLabel{}
+	err := ReaderDb.Select(&labels, `
+		SELECT id, address, label, category, source, note, status, submitted_by, created_ts, reviewed_ts, reviewed_by
+		FROM address_labels
+		WHERE address = ANY($1) AND status = $2
+		ORDER BY category, label`, pq.ByteaArray(addresses), types.AddressLabelApproved)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving labels of %v addresses: %w", len(addresses), err)
+	}
+	for _, l := range labels {
+		res[string(l.Address)] = append(res[string(l.Address)], l)
+	}
+	return res, nil
+}
+
+// SaveAddressLabel stores a label, labels of admins are approved right away while community labels are queued for moderation
+func SaveAddressLabel(label *types.AddressLabel) error {
+	label.Status = types.AddressLabelPending
+	if label.Source == types.AddressLabelSourceAdmin {
+		now := time.Now().UTC()
+		label.Status = types.AddressLabelApproved
+		label.ReviewedTs = &now
+		label.ReviewedBy = label.SubmittedBy
+	}
+
+	err := WriterDb.Get(&label.Id, `
+		INSERT INTO address_labels (address, label, category, source, note, status, submitted_by, reviewed_ts, reviewed_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`,
+		label.Address, label.Label, label.Category, label.Source, label.Note, label.Status, label.SubmittedBy, label.ReviewedTs, label.ReviewedBy)
+	if err != nil {
+		return fmt.Errorf("error inserting label of address %#x: %w", label.Address, err)
+	}
+	return nil
+}
+
+// GetPendingAddressLabelCountByUser returns the number of labels submitted by the user that have not been reviewed yet
+func GetPendingAddressLabelCountByUser(userId uint64) (uint64, error) {
+	var count uint64
+	err := ReaderDb.Get(&count, `SELECT COUNT(*) FROM address_labels WHERE submitted_by = $1 AND status = $2`, userId, types.AddressLabelPending)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving pending address label count of user %v: %w", userId, err)
+	}
+	return count, nil
+}
+
+// GetAddressLabelsByStatus returns the latest labels with the given status, oldest labels first
+func GetAddressLabelsByStatus(status string, limit uint64) ([]*types.AddressLabel, error) {
+	labels := []*types.AddressLabel{}
+	err := ReaderDb.Select(&labels, `
+		SELECT id, address, label, category, source, note, status, submitted_by, created_ts, reviewed_ts, reviewed_by
+		FROM address_labels
+		WHERE status = $1
+		ORDER BY created_ts, id
+		LIMIT $2`, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving %v address labels: %w", status, err)
+	}
+	return labels, nil
+}
+
+// ReviewAddressLabel approves or rejects a label, approved labels can be rejected afterwards to hide them again
+func ReviewAddressLabel(id uint64, approve bool, reviewer uint64) error {
+	status := types.AddressLabelApproved
+	if !approve {
+		status = types.AddressLabelRejected
+	}
+
+	var updated uint64
+	err := WriterDb.Get(&updated, `
+		UPDATE address_labels SET status = $2, reviewed_ts = NOW() AT TIME ZONE 'utc', reviewed_by = $3
+		WHERE id = $1 AND status != $2
+		RETURNING id`, id, status, reviewer)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("no address label with id %v that is not %v", id, status)
+	}
+	if err != nil {
+		return fmt.Errorf("error updating address label %v: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// AddUserAuditLogEntry records a security relevant change of the account of a user
+func AddUserAuditLogEntry(userId uint64, action types.UserAuditAction, ip, userAgent string, details types.UserAuditDetails) error {
+	_, err := FrontendWriterDB.Exec(`
+		INSERT INTO users_audit_log (user_id, action, ip, user_agent, details)
+		VALUES ($1, $2, $3, $4, $5)`, userId, action, ip, truncateString(userAgent, 500), details)
+	if err != nil {
+		return fmt.Errorf("error inserting audit log entry %v of user %v: %w", action, userId, err)
+	}
+	return nil
+}
+
+// GetUserAuditLog returns the audit log entries of a user, newest first
+func GetUserAuditLog(userId uint64, limit, offset uint64) ([]*types.UserAuditLogEntry, error) {
+	entries := []*types.UserAuditLogEntry{}
+	err := FrontendReaderDB.Select(&entries, `
+		SELECT id, action, ip, user_agent, details, created_at
+		FROM users_audit_log
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, userId, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving audit log of user %v: %w", userId, err)
+	}
+	return entries, nil
+}
+
+// AddUserSession records a new web session of a user, only the sha256 hash of the session token is stored
+func AddUserSession(userId uint64, tokenHash []byte, ip, userAgent string) error {
+	_, err := FrontendWriterDB.Exec(`
+		INSERT INTO users_sessions (user_id, token_hash, ip, user_agent)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (token_hash) DO NOTHING`, userId, tokenHash, ip, truncateString(userAgent, 500))
+	if err != nil {
+		return fmt.Errorf("error inserting session of user %v: %w", userId, err)
+	}
+	return nil
+}
+
+// GetUserSessions returns the web sessions of a user that have not ended or expired yet, newest first
+func GetUserSessions(userId uint64) ([]*types.UserSession, error) {
+	sessions := []*types.UserSession{}
+	err := FrontendReaderDB.Select(&sessions, `
+		SELECT id, token_hash, ip, user_agent, created_at
+		FROM users_sessions
+		WHERE user_id = $1 AND ended_at IS NULL AND created_at > $2
+		ORDER BY created_at DESC`, userId, time.Now().Add(-utils.Week))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving sessions of user %v: %w", userId, err)
+	}
+	return sessions, nil
+}
+
+// EndUserSession marks a web session of a user as ended and returns the hash of its token, returns nil if the session
+// does not exist, has already ended or belongs to another user
+func EndUserSession(userId, sessionId uint64) ([]byte, error) {
+	tokenHashes := [][]byte{}
+	err := FrontendWriterDB.Select(&tokenHashes, `
+		UPDATE users_sessions SET ended_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND ended_at IS NULL
+		RETURNING token_hash`, sessionId, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error ending session %v of user %v: %w", sessionId, userId, err)
+	}
+	if len(tokenHashes) == 0 {
+		return nil, nil
+	}
+	return tokenHashes[0], nil
+}
+
+// EndUserSessionByTokenHash marks the web session with the given token hash as ended, e.g. on logout
+func EndUserSessionByTokenHash(tokenHash []byte) error {
+	_, err := FrontendWriterDB.Exec(`UPDATE users_sessions SET ended_at = NOW() WHERE token_hash = $1 AND ended_at IS NULL`, tokenHash)
+	if err != nil {
+		return fmt.Errorf("error ending session: %w", err)
+	}
+	return nil
+}
+
+// EndUserSessions marks all web sessions of a user as ended, e.g. after a password change
+func EndUserSessions(userId uint64) error {
+	_, err := FrontendWriterDB.Exec(`UPDATE users_sessions SET ended_at = NOW() WHERE user_id = $1 AND ended_at IS NULL`, userId)
+	if err != nil {
+		return fmt.Errorf("error ending sessions of user %v: %w", userId, err)
+	}
+	return nil
+}
+
+func truncateString(s string, length int) string {
+	if len(s) > length {
+		return strings.ToValidUTF8(s[:length], "")
+	}
+	return s
+}
@@ -13,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gobitfly/eth2-beaconchain-explorer/btstorage"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 	"github.com/lib/pq"
@@ -21,14 +22,34 @@ import (
 	"github.com/go-redis/redis/v8"
 	itypes "github.com/gobitfly/eth-rewards/types"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"golang.org/x/exp/maps"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/api/option"
+	"google.golang.org/grpc"
 	"google.golang.org/protobuf/proto"
 )
 
 var BigtableClient *Bigtable
 
+// BigtableBackendPostgres is the bigtable.backend config value storing the bigtable data in postgres instead of a gcp
+// bigtable instance, used for self-hosted deployments
+const BigtableBackendPostgres = "postgres"
+
+// MachineMetricsResolutions are the resolutions machine metrics are stored in, the raw metrics (one per minute) are
+// downsampled into the coarser resolutions on insert
+var MachineMetricsResolutions = map[string]time.Duration{
+	"1m": time.Minute,
+	"5m": time.Minute * 5,
+	"1h": time.Hour,
+}
+
+var machineMetricsResolutionFamilies = map[string]string{
+	"1m": MACHINE_METRICS_COLUMN_FAMILY,
+	"5m": MACHINE_METRICS_5M_COLUMN_FAMILY,
+	"1h": MACHINE_METRICS_1H_COLUMN_FAMILY,
+}
+
 const (
 	DEFAULT_FAMILY                        = "f"
 	VALIDATOR_BALANCES_FAMILY             = "vb"
@@ -38,6 +59,8 @@ const (
 	INCOME_DETAILS_COLUMN_FAMILY          = "id"
 	STATS_COLUMN_FAMILY                   = "stats"
 	MACHINE_METRICS_COLUMN_FAMILY         = "mm"
+	MACHINE_METRICS_5M_COLUMN_FAMILY      = "mm5m"
+	MACHINE_METRICS_1H_COLUMN_FAMILY      = "mm1h"
 	SERIES_FAMILY                         = "series"
 
 	SUM_COLUMN = "sum"
@@ -99,7 +122,30 @@ func InitBigtable(project, instance, chainId, redisAddress string) (*Bigtable, e
 	defer cancel()
 
 	poolSize := 50
-	btClient, err := gcp_bigtable.NewClient(ctx, project, instance, option.WithGRPCConnectionPool(poolSize))
+	clientOption := option.WithGRPCConnectionPool(poolSize)
+	if utils.Config.Bigtable.Backend == BigtableBackendPostgres {
+		logger.Infof("using postgres bigtable backend, storing bigtable data in the bigtable_cells table of the writer database")
+		writer, _ := mustInitDB(&types.DatabaseConfig{
+			Username:     utils.Config.WriterDatabase.Username,
+			Password:     utils.Config.WriterDatabase.Password,
+			Name:         utils.Config.WriterDatabase.Name,
+			Host:         utils.Config.WriterDatabase.Host,
+			Port:         utils.Config.WriterDatabase.Port,
+			MaxOpenConns: utils.Config.WriterDatabase.MaxOpenConns,
+			MaxIdleConns: utils.Config.WriterDatabase.MaxIdleConns,
+			SSL:          utils.Config.WriterDatabase.SSL,
+		}, nil, "pgx", "postgres")
+		conn, err := btstorage.NewClientConn(btstorage.NewPostgresStorage(writer))
+		if err != nil {
+			return nil, err
+		}
+		clientOption = option.WithGRPCConn(conn)
+	}
+	clientOptions := []option.ClientOption{clientOption}
+	if utils.Config.Tracing.Enabled {
+		clientOptions = append(clientOptions, option.WithGRPCDialOption(grpc.WithStatsHandler(otelgrpc.NewClientHandler())))
+	}
+	btClient, err := gcp_bigtable.NewClient(ctx, project, instance, clientOptions...)
 	// btClient, err := gcp_bigtable.NewClient(context.Background(), project, instance)
 	if err != nil {
 		return nil, err
@@ -227,8 +273,16 @@ func (bigtable *Bigtable) SaveMachineMetric(process string, userID uint64, machi
 		return err
 	}
 
-	dataMut := gcp_bigtable.NewMutation()
+	// the downsampled cells are written with the timestamp of the start of their bucket, every insert of the bucket
+	// overwrites the previous one so the last sample of the bucket is kept (counters stay exact, gauges are sampled)
+	dataMut := types.NewMutation()
 	dataMut.Set(MACHINE_METRICS_COLUMN_FAMILY, "v1", ts, data)
+	for resolution, family := range machineMetricsResolutionFamilies {
+		if family == MACHINE_METRICS_COLUMN_FAMILY {
+			continue
+		}
+		dataMut.Set(family, "v1", gcp_bigtable.Time(ts.Time().Truncate(MachineMetricsResolutions[resolution])), data)
+	}
 
 	bulkMut := types.BulkMutation{ // schedule the mutation for writing
 		Key: rowKeyData,
@@ -430,6 +484,108 @@ func getMachineMetrics[T types.MachineMetricSystem | types.MachineMetricNode | t
 	return res, nil
 }
 
+// GetMachineMetricsHistory returns the system, validator and beaconnode metrics of a machine of the user within the time
+// range in the given resolution (see MachineMetricsResolutions), ordered by time ascending
+func (bigtable Bigtable) GetMachineMetricsHistory(userID uint64, machine, resolution string, from, to time.Time) (*types.MachineMetricsHistory, error) {
+
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"userId":     userID,
+			"machine":    machine,
+			"resolution": resolution,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	family, ok := machineMetricsResolutionFamilies[resolution]
+	if !ok {
+		return nil, fmt.Errorf("unsupported machine metrics resolution %v", resolution)
+	}
+
+	res := &types.MachineMetricsHistory{
+		Machine:    machine,
+		Resolution: resolution,
+		From:       from.Unix(),
+		To:         to.Unix(),
+	}
+
+	g := errgroup.Group{}
+	g.Go(func() error {
+		var err error
+		res.System, err = getMachineMetricsHistory(bigtable, "system", userID, machine, family, from, to,
+			func(data []byte) *types.MachineMetricSystem {
+				obj := &types.MachineMetricSystem{}
+				if proto.Unmarshal(data, obj) != nil {
+					return nil
+				}
+				obj.Machine = &machine
+				return obj
+			},
+		)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		res.Validator, err = getMachineMetricsHistory(bigtable, "validator", userID, machine, family, from, to,
+			func(data []byte) *types.MachineMetricValidator {
+				obj := &types.MachineMetricValidator{}
+				if proto.Unmarshal(data, obj) != nil {
+					return nil
+				}
+				obj.Machine = &machine
+				return obj
+			},
+		)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		res.Node, err = getMachineMetricsHistory(bigtable, "beaconnode", userID, machine, family, from, to,
+			func(data []byte) *types.MachineMetricNode {
+				obj := &types.MachineMetricNode{}
+				if proto.Unmarshal(data, obj) != nil {
+					return nil
+				}
+				obj.Machine = &machine
+				return obj
+			},
+		)
+		return err
+	})
+	err := g.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func getMachineMetricsHistory[T types.MachineMetricSystem | types.MachineMetricNode | types.MachineMetricValidator](bigtable Bigtable, process string, userID uint64, machine, family string, from, to time.Time, marshler func(data []byte) *T) ([]*T, error) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
+	defer cancel()
+
+	filter := gcp_bigtable.ChainFilters(
+		gcp_bigtable.FamilyFilter(family),
+		gcp_bigtable.TimestampRangeFilter(from, to),
+	)
+
+	row, err := bigtable.tableMachineMetrics.ReadRow(ctx, bigtable.GetMachineRowKey(userID, process, machine), gcp_bigtable.RowFilter(filter))
+	if err != nil {
+		return nil, fmt.Errorf("error reading %v machine metrics history: %w", process, err)
+	}
+
+	// cells are returned newest first
+	cells := row[family]
+	res := make([]*T, 0, len(cells))
+	for i := len(cells) - 1; i >= 0; i-- {
+		obj := marshler(cells[i].Value)
+		if obj == nil {
+			return nil, fmt.Errorf("error unmarshalling %v machine metric of user %v", process, userID)
+		}
+		res = append(res, obj)
+	}
+	return res, nil
+}
+
 func (bigtable Bigtable) GetMachineRowKey(userID uint64, process string, machine string) string {
 	return fmt.Sprintf("u:%s:p:%s:m:%s", bigtable.reversePaddedUserID(userID), process, machine)
 }
@@ -555,7 +711,7 @@ func (bigtable *Bigtable) SaveValidatorBalances(epoch uint64, validators []*type
 		effectiveBalanceEncoded := uint8(validator.EffectiveBalance / 1e9) // we can encode the effective balance in 1 byte as it is capped at 32ETH and only decrements in 1 ETH steps
 
 		combined := append(balanceEncoded, effectiveBalanceEncoded)
-		mut := &gcp_bigtable.Mutation{}
+		mut := types.NewMutation()
 		mut.Set(VALIDATOR_BALANCES_FAMILY, "b", ts, combined)
 		key := fmt.Sprintf("%s:%s:%s:%s", bigtable.chainId, bigtable.validatorIndexToKey(validator.Index), VALIDATOR_BALANCES_FAMILY, epochKey)
 
@@ -571,10 +727,10 @@ func (bigtable *Bigtable) SaveValidatorBalances(epoch uint64, validators []*type
 	highestActiveIndexEncoded := make([]byte, 8)
 	binary.LittleEndian.PutUint64(highestActiveIndexEncoded, highestActiveIndex)
 
-	mut := &gcp_bigtable.Mutation{}
+	mut := types.NewMutation()
 	mut.Set(VALIDATOR_HIGHEST_ACTIVE_INDEX_FAMILY, VALIDATOR_HIGHEST_ACTIVE_INDEX_FAMILY, ts, highestActiveIndexEncoded)
 	key := fmt.Sprintf("%s:%s:%s", bigtable.chainId, VALIDATOR_HIGHEST_ACTIVE_INDEX_FAMILY, epochKey)
-	err = bigtable.tableValidatorsHistory.Apply(ctx, key, mut)
+	err = bigtable.tableValidatorsHistory.Apply(ctx, key, mut.Mutation)
 	if err != nil {
 		return err
 	}
@@ -606,7 +762,7 @@ func (bigtable *Bigtable) SaveAttestationDuties(duties map[types.Slot]map[types.
 
 	mutsInclusionSlot := types.NewBulkMutations(MAX_BATCH_MUTATIONS)
 
-	mutLastAttestationSlot := gcp_bigtable.NewMutation()
+	mutLastAttestationSlot := types.NewMutation()
 	mutLastAttestationSlotCount := 0
 
 	for attestedSlot, validators := range duties {
@@ -619,7 +775,7 @@ func (bigtable *Bigtable) SaveAttestationDuties(duties map[types.Slot]map[types.
 			for _, inclusionSlot := range inclusions {
 				key := fmt.Sprintf("%s:%s:%s:%s", bigtable.chainId, bigtable.validatorIndexToKey(uint64(validator)), ATTESTATIONS_FAMILY, bigtable.reversedPaddedEpoch(epoch))
 
-				mutInclusionSlot := gcp_bigtable.NewMutation()
+				mutInclusionSlot := types.NewMutation()
 				ts := gcp_bigtable.Time(utils.SlotToTime(uint64(inclusionSlot)))
 				mutInclusionSlot.Set(ATTESTATIONS_FAMILY, fmt.Sprintf("%d", attestedSlot), ts, []byte{})
 
@@ -632,12 +788,12 @@ func (bigtable *Bigtable) SaveAttestationDuties(duties map[types.Slot]map[types.
 
 					if mutLastAttestationSlotCount == MAX_BATCH_MUTATIONS {
 						mutStart := time.Now()
-						err := bigtable.tableValidators.Apply(ctx, fmt.Sprintf("%s:lastAttestationSlot", bigtable.chainId), mutLastAttestationSlot)
+						err := bigtable.tableValidators.Apply(ctx, fmt.Sprintf("%s:lastAttestationSlot", bigtable.chainId), mutLastAttestationSlot.Mutation)
 						if err != nil {
 							bigtable.LastAttestationCacheMux.Unlock()
 							return fmt.Errorf("error applying last attestation slot mutations: %v", err)
 						}
-						mutLastAttestationSlot = gcp_bigtable.NewMutation()
+						mutLastAttestationSlot = types.NewMutation()
 						mutLastAttestationSlotCount = 0
 						logger.Infof("applied last attestation slot mutations in %v", time.Since(mutStart))
 					}
@@ -654,7 +810,7 @@ func (bigtable *Bigtable) SaveAttestationDuties(duties map[types.Slot]map[types.
 	}
 
 	if mutLastAttestationSlotCount > 0 {
-		err := bigtable.tableValidators.Apply(ctx, fmt.Sprintf("%s:lastAttestationSlot", bigtable.chainId), mutLastAttestationSlot)
+		err := bigtable.tableValidators.Apply(ctx, fmt.Sprintf("%s:lastAttestationSlot", bigtable.chainId), mutLastAttestationSlot.Mutation)
 		if err != nil {
 			return fmt.Errorf("error applying last attestation slot mutations: %v", err)
 		}
@@ -669,9 +825,9 @@ func (bigtable *Bigtable) SetLastAttestationSlot(validator uint64, lastAttestati
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
 
-	mutLastAttestationSlot := gcp_bigtable.NewMutation()
+	mutLastAttestationSlot := types.NewMutation()
 	mutLastAttestationSlot.Set(ATTESTATIONS_FAMILY, fmt.Sprintf("%d", validator), gcp_bigtable.Timestamp(lastAttestationSlot*1000), []byte{})
-	err := bigtable.tableValidators.Apply(ctx, fmt.Sprintf("%s:lastAttestationSlot", bigtable.chainId), mutLastAttestationSlot)
+	err := bigtable.tableValidators.Apply(ctx, fmt.Sprintf("%s:lastAttestationSlot", bigtable.chainId), mutLastAttestationSlot.Mutation)
 	if err != nil {
 		return err
 	}
@@ -691,7 +847,7 @@ func (bigtable *Bigtable) SaveSyncComitteeDuties(duties map[types.Slot]map[types
 
 	for slot, validators := range duties {
 		for validator, participated := range validators {
-			mut := gcp_bigtable.NewMutation()
+			mut := types.NewMutation()
 			if participated {
 				ts := gcp_bigtable.Time(utils.SlotToTime(uint64(slot)).Add(time.Second)) // add 1 second to avoid collisions with duties
 				mut.Set(SYNC_COMMITTEES_FAMILY, "s", ts, []byte{})
@@ -761,6 +917,44 @@ func (bigtable *Bigtable) GetValidatorBalanceHistory(validators []uint64, startE
 	}
 }
 
+// StreamValidatorBalanceHistory calls fn with the balances of the validators between startEpoch and endEpoch in chunks
+// of at most chunkSize epochs, oldest chunk first and sorted by epoch ascending within a chunk. Chunks never span the
+// v2 schema cut off epoch as the schema is selected by the end epoch of a query.
+func (bigtable *Bigtable) StreamValidatorBalanceHistory(validators []uint64, startEpoch, endEpoch, chunkSize uint64, fn func(map[uint64][]*types.ValidatorBalance) error) error {
+	if chunkSize == 0 {
+		return fmt.Errorf("invalid chunk size %v", chunkSize)
+	}
+	for start := startEpoch; start <= endEpoch; {
+		end := endEpoch
+		if end-start >= chunkSize {
+			end = start + chunkSize - 1
+		}
+		if start < bigtable.v2SchemaCutOffEpoch && end >= bigtable.v2SchemaCutOffEpoch {
+			end = bigtable.v2SchemaCutOffEpoch - 1
+		}
+
+		history, err := bigtable.GetValidatorBalanceHistory(validators, start, end)
+		if err != nil {
+			return err
+		}
+		for _, balances := range history {
+			sort.Slice(balances, func(i, j int) bool {
+				return balances[i].Epoch < balances[j].Epoch
+			})
+		}
+		err = fn(history)
+		if err != nil {
+			return err
+		}
+
+		if end == math.MaxUint64 {
+			break
+		}
+		start = end + 1
+	}
+	return nil
+}
+
 //lint:ignore U1000 will be used later on
 func (bigtable *Bigtable) getValidatorBalanceHistoryClickhouse(validators []uint64, startEpoch uint64, endEpoch uint64) (map[uint64][]*types.ValidatorBalance, error) {
 	startEpochTs := utils.EpochToTime(startEpoch)
@@ -2289,7 +2483,7 @@ func (bigtable *Bigtable) SaveValidatorIncomeDetails(epoch uint64, rewards map[u
 			return err
 		}
 
-		mut := &gcp_bigtable.Mutation{}
+		mut := types.NewMutation()
 		mut.Set(INCOME_DETAILS_COLUMN_FAMILY, "i", ts, data)
 		key := fmt.Sprintf("%s:%s:%s:%s", bigtable.chainId, bigtable.validatorIndexToKey(i), INCOME_DETAILS_COLUMN_FAMILY, bigtable.reversedPaddedEpoch(epoch))
 
@@ -2316,7 +2510,7 @@ func (bigtable *Bigtable) SaveValidatorIncomeDetails(epoch uint64, rewards map[u
 		return err
 	}
 
-	mut := &gcp_bigtable.Mutation{}
+	mut := types.NewMutation()
 	mut.Set(STATS_COLUMN_FAMILY, SUM_COLUMN, ts, sum)
 
 	muts.Add(fmt.Sprintf("%s:%s:%s", bigtable.chainId, SUM_COLUMN, bigtable.reversedPaddedEpoch(epoch)), mut)
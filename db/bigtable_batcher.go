@@ -0,0 +1,390 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	gcp_bigtable "cloud.google.com/go/bigtable"
+	"github.com/lib/pq"
+	btpb "google.golang.org/genproto/googleapis/bigtable/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// bigtableApplyRetries is the number of times failed entries of a bulk write are retried before they are queued
+	bigtableApplyRetries = 3
+
+	mutationQueueReplayInterval = time.Minute
+	mutationQueueReplayBatch    = 1000
+	mutationQueueMaxBackoff     = time.Hour * 6
+	// mutationQueueReplayLease is the time claimed mutations are hidden from other replays while they are applied
+	mutationQueueReplayLease = time.Minute * 15
+)
+
+// MutationBatcher buffers the mutations of a table and applies them in bulk once batchSize mutations are buffered or
+// the flush interval elapsed. Mutations that can not be applied are stored in the mutation queue and replayed later.
+type MutationBatcher struct {
+	bigtable  *Bigtable
+	table     *gcp_bigtable.Table
+	batchSize int
+
+	mu   sync.Mutex
+	muts *types.BulkMutations
+	done chan struct{}
+}
+
+// NewMutationBatcher returns a batcher for the table, Close has to be called to flush the remaining mutations
+func (bigtable *Bigtable) NewMutationBatcher(table *gcp_bigtable.Table, batchSize int, flushInterval time.Duration) *MutationBatcher {
+	if batchSize > MAX_BATCH_MUTATIONS {
+		batchSize = MAX_BATCH_MUTATIONS
+	}
+	b := &MutationBatcher{
+		bigtable:  bigtable,
+		table:     table,
+		batchSize: batchSize,
+		muts:      types.NewBulkMutations(batchSize),
+		done:      make(chan struct{}),
+	}
+	go b.flushPeriodically(flushInterval)
+	return b
+}
+
+// Add buffers the mutation of the row, the buffer is applied if it is full
+func (b *MutationBatcher) Add(key string, mut *types.Mutation) error {
+	b.mu.Lock()
+	b.muts.Add(key, mut)
+	if b.muts.Len() < b.batchSize {
+		b.mu.Unlock()
+		return nil
+	}
+	muts := b.muts
+	b.muts = types.NewBulkMutations(b.batchSize)
+	b.mu.Unlock()
+
+	return b.bigtable.applyBulk(b.table, muts)
+}
+
+// Flush applies the buffered mutations
+func (b *MutationBatcher) Flush() error {
+	b.mu.Lock()
+	muts := b.muts
+	b.muts = types.NewBulkMutations(b.batchSize)
+	b.mu.Unlock()
+
+	if muts.Len() == 0 {
+		return nil
+	}
+	return b.bigtable.applyBulk(b.table, muts)
+}
+
+// Close stops the periodic flushes and applies the buffered mutations
+func (b *MutationBatcher) Close() error {
+	close(b.done)
+	return b.Flush()
+}
+
+func (b *MutationBatcher) flushPeriodically(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-ticker.C:
+			err := b.Flush()
+			if err != nil {
+				utils.LogError(err, "error flushing bigtable mutations", 0)
+			}
+		}
+	}
+}
+
+// applyBulk applies the mutations, entries failing are retried with backoff. Entries that still fail are stored in
+// the mutation queue, an error is only returned if they could not be stored.
+func (bigtable *Bigtable) applyBulk(table *gcp_bigtable.Table, mutations *types.BulkMutations) error {
+	keys, muts := mutations.Keys, mutations.Muts
+
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+		errs, err := table.ApplyBulk(ctx, keys, gcpMutations(muts))
+		cancel()
+		if err == nil && errs == nil {
+			return nil
+		}
+
+		if err != nil {
+			lastErr = err
+		} else {
+			failedKeys := make([]string, 0, len(errs))
+			failedMuts := make([]*types.Mutation, 0, len(errs))
+			for i, e := range errs {
+				if e != nil {
+					failedKeys = append(failedKeys, keys[i])
+					failedMuts = append(failedMuts, muts[i])
+					lastErr = e
+				}
+			}
+			keys, muts = failedKeys, failedMuts
+		}
+
+		if attempt == bigtableApplyRetries {
+			break
+		}
+		logger.Warnf("error applying %v of %v bigtable mutations (attempt %v), retrying: %v", len(keys), mutations.Len(), attempt+1, lastErr)
+		time.Sleep(time.Second * time.Duration(1<<attempt))
+	}
+
+	err := bigtable.enqueueMutations(table, keys, muts, lastErr)
+	if err != nil {
+		return fmt.Errorf("error applying %v bigtable mutations: %v, queueing them failed: %w", len(keys), lastErr, err)
+	}
+	logger.Warnf("queued %v bigtable mutations that could not be applied: %v", len(keys), lastErr)
+	return nil
+}
+
+// enqueueMutations stores the mutations in the mutation queue. A mutation is queued only once, queueing it again does
+// not reset its attempts.
+func (bigtable *Bigtable) enqueueMutations(table *gcp_bigtable.Table, keys []string, muts []*types.Mutation, cause error) error {
+	if WriterDb == nil {
+		return fmt.Errorf("mutation queue requires a writer database")
+	}
+	tableName, err := bigtable.tableName(table)
+	if err != nil {
+		return err
+	}
+
+	mutationKeys := make(pq.ByteaArray, len(keys))
+	mutations := make(pq.ByteaArray, len(keys))
+	for i, key := range keys {
+		data, err := proto.Marshal(&btpb.MutateRowsRequest_Entry{RowKey: []byte(key), Mutations: muts[i].Ops})
+		if err != nil {
+			return fmt.Errorf("error marshalling mutation of row %v: %w", key, err)
+		}
+		mutationKey := sha256.Sum256(append([]byte(tableName+":"), data...))
+		mutationKeys[i] = mutationKey[:]
+		mutations[i] = data
+	}
+
+	_, err = WriterDb.Exec(`
+		INSERT INTO bigtable_mutation_queue (mutation_key, table_name, row_key, mutation, last_error)
+		SELECT mutation_key, $1, row_key, mutation, $5 FROM UNNEST($2::BYTEA[], $3::TEXT[], $4::BYTEA[]) AS q(mutation_key, row_key, mutation)
+		ON CONFLICT (mutation_key) DO NOTHING`,
+		tableName, mutationKeys, pq.Array(keys), mutations, fmt.Sprint(cause))
+	if err != nil {
+		return fmt.Errorf("error inserting into bigtable_mutation_queue: %w", err)
+	}
+	return nil
+}
+
+// ReplayMutationQueue periodically applies the queued mutations, it can run in several processes at once
+func (bigtable *Bigtable) ReplayMutationQueue() {
+	for {
+		err := bigtable.replayMutationQueue()
+		if err != nil {
+			utils.LogError(err, "error replaying bigtable mutation queue", 0)
+		}
+		time.Sleep(mutationQueueReplayInterval)
+	}
+}
+
+func (bigtable *Bigtable) replayMutationQueue() error {
+	var queued int64
+	err := WriterDb.Get(&queued, `SELECT COUNT(*) FROM bigtable_mutation_queue`)
+	if err != nil {
+		return fmt.Errorf("error counting queued mutations: %w", err)
+	}
+	metrics.BigtableQueuedMutations.Set(float64(queued))
+	if queued == 0 {
+		return nil
+	}
+
+	rows, err := claimQueuedMutations()
+	if err != nil {
+		return err
+	}
+
+	type tableBatch struct {
+		mutationKeys [][]byte
+		attempts     []int
+		muts         *types.BulkMutations
+	}
+	batches := map[string]*tableBatch{}
+	for _, row := range rows {
+		entry := &btpb.MutateRowsRequest_Entry{}
+		err := proto.Unmarshal(row.Mutation, entry)
+		if err == nil {
+			var mut *types.Mutation
+			mut, err = types.NewMutationFromOps(entry.Mutations)
+			if err == nil {
+				batch := batches[row.TableName]
+				if batch == nil {
+					batch = &tableBatch{muts: types.NewBulkMutations(len(rows))}
+					batches[row.TableName] = batch
+				}
+				batch.mutationKeys = append(batch.mutationKeys, row.MutationKey)
+				batch.attempts = append(batch.attempts, row.Attempts)
+				batch.muts.Add(string(entry.RowKey), mut)
+				continue
+			}
+		}
+		// a mutation that can not be decoded will never succeed, it is kept with the maximum backoff for inspection
+		utils.LogError(err, "error decoding queued bigtable mutation", 0, map[string]interface{}{"mutationKey": fmt.Sprintf("%x", row.MutationKey)})
+		err = updateQueuedMutationAttempt(row.MutationKey, err, mutationQueueMaxBackoff)
+		if err != nil {
+			return err
+		}
+	}
+
+	applied := 0
+	for tableName, batch := range batches {
+		table, err := bigtable.tableByName(tableName)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+		errs, err := table.ApplyBulk(ctx, batch.muts.Keys, gcpMutations(batch.muts.Muts))
+		cancel()
+
+		done := pq.ByteaArray{}
+		for i, mutationKey := range batch.mutationKeys {
+			applyErr := err
+			if applyErr == nil && errs != nil {
+				applyErr = errs[i]
+			}
+			if applyErr == nil {
+				done = append(done, mutationKey)
+				continue
+			}
+
+			backoff := mutationQueueMaxBackoff
+			if batch.attempts[i] < 10 {
+				backoff = time.Minute * time.Duration(1<<batch.attempts[i])
+			}
+			if backoff > mutationQueueMaxBackoff {
+				backoff = mutationQueueMaxBackoff
+			}
+			err := updateQueuedMutationAttempt(mutationKey, applyErr, backoff)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = WriterDb.Exec(`DELETE FROM bigtable_mutation_queue WHERE mutation_key = ANY($1)`, done)
+		if err != nil {
+			return fmt.Errorf("error deleting applied mutations: %w", err)
+		}
+		applied += len(done)
+	}
+
+	logger.Infof("replayed %v of %v queued bigtable mutations", applied, len(rows))
+	return nil
+}
+
+type queuedMutation struct {
+	MutationKey []byte `db:"mutation_key"`
+	TableName   string `db:"table_name"`
+	Mutation    []byte `db:"mutation"`
+	Attempts    int    `db:"attempts"`
+}
+
+// claimQueuedMutations retrieves the due mutations and postpones them by the replay lease so other replays skip them.
+// The claim is committed before the mutations are applied, mutations of a replay that dies are retried after the lease.
+func claimQueuedMutations() ([]queuedMutation, error) {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows := []queuedMutation{}
+	err = tx.Select(&rows, `
+		SELECT mutation_key, table_name, mutation, attempts
+		FROM bigtable_mutation_queue
+		WHERE next_attempt_ts <= NOW()
+		ORDER BY next_attempt_ts
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`, mutationQueueReplayBatch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving queued mutations: %w", err)
+	}
+	if len(rows) == 0 {
+		return rows, nil
+	}
+
+	mutationKeys := make(pq.ByteaArray, len(rows))
+	for i, row := range rows {
+		mutationKeys[i] = row.MutationKey
+	}
+	_, err = tx.Exec(`
+		UPDATE bigtable_mutation_queue
+		SET next_attempt_ts = NOW() + $2 * INTERVAL '1 second'
+		WHERE mutation_key = ANY($1)`, mutationKeys, mutationQueueReplayLease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("error claiming queued mutations: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("error committing db transaction: %w", err)
+	}
+	return rows, nil
+}
+
+func updateQueuedMutationAttempt(mutationKey []byte, cause error, backoff time.Duration) error {
+	_, err := WriterDb.Exec(`
+		UPDATE bigtable_mutation_queue
+		SET attempts = attempts + 1, last_error = $2, next_attempt_ts = NOW() + $3 * INTERVAL '1 second'
+		WHERE mutation_key = $1`, mutationKey, cause.Error(), backoff.Seconds())
+	if err != nil {
+		return fmt.Errorf("error updating queued mutation %x: %w", mutationKey, err)
+	}
+	return nil
+}
+
+func (bigtable *Bigtable) tables() map[string]*gcp_bigtable.Table {
+	return map[string]*gcp_bigtable.Table{
+		"data":                           bigtable.tableData,
+		"blocks":                         bigtable.tableBlocks,
+		"metadata_updates":               bigtable.tableMetadataUpdates,
+		"metadata":                       bigtable.tableMetadata,
+		"beaconchain":                    bigtable.tableBeaconchain,
+		"machine_metrics":                bigtable.tableMachineMetrics,
+		"beaconchain_validators":         bigtable.tableValidators,
+		"beaconchain_validators_history": bigtable.tableValidatorsHistory,
+	}
+}
+
+func (bigtable *Bigtable) tableByName(name string) (*gcp_bigtable.Table, error) {
+	table, ok := bigtable.tables()[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown table %v", name)
+	}
+	return table, nil
+}
+
+func (bigtable *Bigtable) tableName(table *gcp_bigtable.Table) (string, error) {
+	for name, t := range bigtable.tables() {
+		if t == table {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("unknown bigtable table")
+}
+
+// gcpMutations returns the bigtable client mutations of the mutations
+func gcpMutations(muts []*types.Mutation) []*gcp_bigtable.Mutation {
+	res := make([]*gcp_bigtable.Mutation, len(muts))
+	for i, mut := range muts {
+		res[i] = mut.Mutation
+	}
+	return res
+}
@@ -18,9 +18,6 @@ func (bigtable *Bigtable) WriteBulk(mutations *types.BulkMutations, table *gcp_b
 
 	callingFunctionName := utils.GetParentFuncName()
 
-	ctx, done := context.WithTimeout(context.Background(), time.Minute*5)
-	defer done()
-
 	numMutations := len(mutations.Muts)
 	numKeys := len(mutations.Keys)
 	if numKeys != numMutations {
@@ -36,41 +33,19 @@ func (bigtable *Bigtable) WriteBulk(mutations *types.BulkMutations, table *gcp_b
 		length = MAX_BATCH_MUTATIONS
 	}
 
-	iterations := numKeys / length
-
-	for offset := 0; offset < iterations; offset++ {
-		start := offset * length
-		end := offset*length + length
-
-		startTime := time.Now()
-		errs, err := table.ApplyBulk(ctx, mutations.Keys[start:end], mutations.Muts[start:end])
-		for _, e := range errs {
-			if e != nil {
-				return e
-			}
-		}
-		if err != nil {
-			return err
+	for start := 0; start < numKeys; start += length {
+		end := start + length
+		if end > numKeys {
+			end = numKeys
 		}
-		logger.Infof("%s: wrote from %v to %v rows to bigtable in %.1f s", callingFunctionName, start, end, time.Since(startTime).Seconds())
-
-	}
 
-	if (iterations * length) < numKeys {
-		start := iterations * length
 		startTime := time.Now()
-		errs, err := table.ApplyBulk(ctx, mutations.Keys[start:], mutations.Muts[start:])
+		// failed mutations are retried and queued for replay if they still fail
+		err := bigtable.applyBulk(table, &types.BulkMutations{Keys: mutations.Keys[start:end], Muts: mutations.Muts[start:end]})
 		if err != nil {
 			return err
 		}
-		for _, e := range errs {
-			if e != nil {
-				return e
-			}
-		}
-		logger.Infof("%s: wrote from %v to %v rows to bigtable in %.1fs", callingFunctionName, start, numKeys, time.Since(startTime).Seconds())
-
-		return nil
+		logger.Infof("%s: wrote from %v to %v rows to bigtable in %.1f s", callingFunctionName, start, end, time.Since(startTime).Seconds())
 	}
 
 	return nil
@@ -133,7 +108,7 @@ func (bigtable *Bigtable) ClearByPrefix(table string, family, columns, prefix st
 			logger.Infof("would delete key %v", row_)
 		}
 
-		mutDelete := gcp_bigtable.NewMutation()
+		mutDelete := types.NewMutation()
 		if columns == "*" {
 			mutDelete.DeleteRow()
 		} else {
@@ -241,7 +216,7 @@ func (bigtable *Bigtable) ClearByRowRange(table string, family, columns string,
 			logger.Infof("would delete key %v", row_)
 		}
 
-		mutDelete := gcp_bigtable.NewMutation()
+		mutDelete := types.NewMutation()
 		if columns == "*" {
 			mutDelete.DeleteRow()
 		} else {
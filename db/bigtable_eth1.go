@@ -4,15 +4,21 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"math/big"
+	"net"
+	"net/http"
+	"net/url"
 	"sort"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
@@ -43,6 +49,7 @@ import (
 
 const (
 	ECR20TokensPerAddressLimit    = uint64(200) // when changing this, you will have to update the swagger docu for func ApiEth1Address too
+	NFTsPerAddressLimit           = int64(100)  // when changing this, you will have to update the swagger docu for func ApiEth1AddressNFTs too
 	digitLimitInAddressPagesTable = 17
 	nameLimitInAddressPagesTable  = 0
 )
@@ -65,6 +72,7 @@ const (
 const (
 	DATA_COLUMN                    = "d"
 	INDEX_COLUMN                   = "i"
+	NFT_OWNERSHIP_COLUMN           = "o"
 	DEFAULT_FAMILY_BLOCKS          = "default"
 	METADATA_UPDATES_FAMILY_BLOCKS = "blocks"
 	ACCOUNT_METADATA_FAMILY        = "a"
@@ -129,17 +137,12 @@ func (bigtable *Bigtable) SaveBlock(block *types.Eth1Block) error {
 		metrics.TaskDuration.WithLabelValues("bt_save_block").Observe(time.Since(startTime).Seconds())
 	}()
 
-	encodedBc, err := proto.Marshal(block)
-
+	key, mut, err := bigtable.blockMutation(block)
 	if err != nil {
 		return err
 	}
-	ts := gcp_bigtable.Timestamp(0)
-
-	mut := gcp_bigtable.NewMutation()
-	mut.Set(DEFAULT_FAMILY_BLOCKS, "data", ts, encodedBc)
 
-	err = bigtable.tableBlocks.Apply(ctx, fmt.Sprintf("%s:%s", bigtable.chainId, reversedPaddedBlockNumber(block.Number)), mut)
+	err = bigtable.tableBlocks.Apply(ctx, key, mut.Mutation)
 
 	if err != nil {
 		return err
@@ -147,6 +150,35 @@ func (bigtable *Bigtable) SaveBlock(block *types.Eth1Block) error {
 	return nil
 }
 
+// NewBlocksBatcher returns a batcher writing blocks to the blocks table in bulk
+func (bigtable *Bigtable) NewBlocksBatcher() *MutationBatcher {
+	// blocks including their traces can be several MB large, so batches are kept small
+	return bigtable.NewMutationBatcher(bigtable.tableBlocks, 100, time.Second*5)
+}
+
+// SaveBlockBatched adds the block to a batcher returned by NewBlocksBatcher
+func (bigtable *Bigtable) SaveBlockBatched(batcher *MutationBatcher, block *types.Eth1Block) error {
+	key, mut, err := bigtable.blockMutation(block)
+	if err != nil {
+		return err
+	}
+	return batcher.Add(key, mut)
+}
+
+func (bigtable *Bigtable) blockMutation(block *types.Eth1Block) (string, *types.Mutation, error) {
+	encodedBc, err := proto.Marshal(block)
+
+	if err != nil {
+		return "", nil, err
+	}
+	ts := gcp_bigtable.Timestamp(0)
+
+	mut := types.NewMutation()
+	mut.Set(DEFAULT_FAMILY_BLOCKS, "data", ts, encodedBc)
+
+	return fmt.Sprintf("%s:%s", bigtable.chainId, reversedPaddedBlockNumber(block.Number)), mut, nil
+}
+
 func (bigtable *Bigtable) GetBlockFromBlocksTable(number uint64) (*types.Eth1Block, error) {
 	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
 		logger.WithFields(logrus.Fields{
@@ -309,6 +341,46 @@ func (bigtable *Bigtable) CheckForGapsInDataTable(lookback int) error {
 	return nil
 }
 
+// GetMissingBlocks returns the numbers of the blocks missing between the last lookback blocks of the blocks table, or
+// of the data table if dataTable is set
+func (bigtable *Bigtable) GetMissingBlocks(lookback int, dataTable bool) ([]uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	table := bigtable.tableBlocks
+	prefix := bigtable.chainId + ":"
+	if dataTable {
+		table = bigtable.tableData
+		prefix = bigtable.chainId + ":B:"
+	}
+
+	missing := []uint64{}
+	previous := 0
+	i := 0
+	var parseErr error
+	err := table.ReadRows(ctx, gcp_bigtable.PrefixRange(prefix), func(r gcp_bigtable.Row) bool {
+		c, err := strconv.Atoi(strings.Replace(r.Key(), prefix, "", 1))
+		if err != nil {
+			parseErr = fmt.Errorf("error parsing block number from key %v: %w", r.Key(), err)
+			return false
+		}
+		c = MAX_EL_BLOCK_NUMBER - c
+
+		// rows are sorted by descending block number
+		for block := previous - 1; previous != 0 && block > c; block-- {
+			missing = append(missing, uint64(block))
+		}
+		previous = c
+
+		i++
+		return i < lookback
+	}, gcp_bigtable.RowFilter(gcp_bigtable.StripValueFilter()))
+	if err != nil {
+		return nil, err
+	}
+	return missing, parseErr
+}
+
 func (bigtable *Bigtable) GetLastBlockInDataTable() (int, error) {
 	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
 		logger.Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
@@ -901,7 +973,7 @@ func (bigtable *Bigtable) TransformBlock(block *types.Eth1Block, cache *freecach
 
 	// <chainID>:b:<reverse number>
 	key := fmt.Sprintf("%s:B:%s", bigtable.chainId, reversedPaddedBlockNumber(block.GetNumber()))
-	mut := gcp_bigtable.NewMutation()
+	mut := types.NewMutation()
 
 	b, err := proto.Marshal(&idx)
 	if err != nil {
@@ -919,7 +991,7 @@ func (bigtable *Bigtable) TransformBlock(block *types.Eth1Block, cache *freecach
 	}
 
 	for _, idx := range indexes {
-		mut := gcp_bigtable.NewMutation()
+		mut := types.NewMutation()
 		mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
 
 		bulkData.Keys = append(bulkData.Keys, idx)
@@ -1029,7 +1101,7 @@ func (bigtable *Bigtable) TransformTx(blk *types.Eth1Block, cache *freecache.Cac
 			return nil, nil, err
 		}
 
-		mut := gcp_bigtable.NewMutation()
+		mut := types.NewMutation()
 		mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), b)
 
 		bulkData.Keys = append(bulkData.Keys, key)
@@ -1057,7 +1129,7 @@ func (bigtable *Bigtable) TransformTx(blk *types.Eth1Block, cache *freecache.Cac
 		}
 
 		for _, idx := range indexes {
-			mut := gcp_bigtable.NewMutation()
+			mut := types.NewMutation()
 			mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
 
 			bulkData.Keys = append(bulkData.Keys, idx)
@@ -1123,7 +1195,7 @@ func (bigtable *Bigtable) TransformBlobTx(blk *types.Eth1Block, cache *freecache
 			return nil, nil, err
 		}
 
-		mut := gcp_bigtable.NewMutation()
+		mut := types.NewMutation()
 		mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), b)
 
 		bulkData.Keys = append(bulkData.Keys, key)
@@ -1144,7 +1216,7 @@ func (bigtable *Bigtable) TransformBlobTx(blk *types.Eth1Block, cache *freecache
 		}
 
 		for _, idx := range indexes {
-			mut := gcp_bigtable.NewMutation()
+			mut := types.NewMutation()
 			mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
 
 			bulkData.Keys = append(bulkData.Keys, idx)
@@ -1229,7 +1301,7 @@ func (bigtable *Bigtable) TransformContract(blk *types.Eth1Block, cache *freecac
 					address = itx.GetFrom()
 				}
 
-				mutWrite := gcp_bigtable.NewMutation()
+				mutWrite := types.NewMutation()
 				ts, err := encodeIsContractUpdateTs(blk.GetNumber(), uint64(i), uint64(j))
 				if err != nil {
 					utils.LogError(err, "error generating bigtable isContract timestamp", 0)
@@ -1320,14 +1392,14 @@ func (bigtable *Bigtable) TransformItx(blk *types.Eth1Block, cache *freecache.Ca
 
 			// Delete existing delegatecall data or add/update other data
 			if itx.GetType() == "delegatecall" {
-				mut := gcp_bigtable.NewMutation()
+				mut := types.NewMutation()
 				mut.DeleteCellsInColumn(DEFAULT_FAMILY, DATA_COLUMN)
 
 				bulkData.Keys = append(bulkData.Keys, key)
 				bulkData.Muts = append(bulkData.Muts, mut)
 
 				for _, idx := range indexes {
-					mut := gcp_bigtable.NewMutation()
+					mut := types.NewMutation()
 					mut.DeleteCellsInColumn(DEFAULT_FAMILY, key)
 
 					bulkData.Keys = append(bulkData.Keys, idx)
@@ -1339,14 +1411,14 @@ func (bigtable *Bigtable) TransformItx(blk *types.Eth1Block, cache *freecache.Ca
 					return nil, nil, err
 				}
 
-				mut := gcp_bigtable.NewMutation()
+				mut := types.NewMutation()
 				mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), b)
 
 				bulkData.Keys = append(bulkData.Keys, key)
 				bulkData.Muts = append(bulkData.Muts, mut)
 
 				for _, idx := range indexes {
-					mut := gcp_bigtable.NewMutation()
+					mut := types.NewMutation()
 					mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
 
 					bulkData.Keys = append(bulkData.Keys, idx)
@@ -1478,7 +1550,7 @@ func (bigtable *Bigtable) TransformERC20(blk *types.Eth1Block, cache *freecache.
 				return nil, nil, err
 			}
 
-			mut := gcp_bigtable.NewMutation()
+			mut := types.NewMutation()
 			mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), b)
 
 			bulkData.Keys = append(bulkData.Keys, key)
@@ -1499,7 +1571,7 @@ func (bigtable *Bigtable) TransformERC20(blk *types.Eth1Block, cache *freecache.
 			}
 
 			for _, idx := range indexes {
-				mut := gcp_bigtable.NewMutation()
+				mut := types.NewMutation()
 				mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
 
 				// if i == 3 || i == 4 {
@@ -1560,6 +1632,12 @@ func (bigtable *Bigtable) TransformERC20(blk *types.Eth1Block, cache *freecache.
 // Family: f
 // Column: <chainID>:ERC721:<txHash>:<paddedLogIndex>
 // Cell:   nil
+//
+// It tracks the ownership of every token by:
+// Row:    <chainID>:NFT:O:<OWNER_ADDRESS>:ERC721:<TOKEN_ADDRESS>:<TOKEN_ID>
+// Family: f
+// Column: o
+// Cell:   0x01 if the token was received, 0x00 if it was sent (the cell timestamp encodes block, tx and log index so the latest transfer wins)
 func (bigtable *Bigtable) TransformERC721(blk *types.Eth1Block, cache *freecache.Cache) (bulkData *types.BulkMutations, bulkMetadataUpdates *types.BulkMutations, err error) {
 	startTime := time.Now()
 	defer func() {
@@ -1632,7 +1710,7 @@ func (bigtable *Bigtable) TransformERC721(blk *types.Eth1Block, cache *freecache
 				return nil, nil, err
 			}
 
-			mut := gcp_bigtable.NewMutation()
+			mut := types.NewMutation()
 			mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), b)
 
 			bulkData.Keys = append(bulkData.Keys, key)
@@ -1654,7 +1732,7 @@ func (bigtable *Bigtable) TransformERC721(blk *types.Eth1Block, cache *freecache
 			}
 
 			for _, idx := range indexes {
-				mut := gcp_bigtable.NewMutation()
+				mut := types.NewMutation()
 				mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
 
 				// if i == 3 || i == 4 {
@@ -1664,6 +1742,17 @@ func (bigtable *Bigtable) TransformERC721(blk *types.Eth1Block, cache *freecache
 				bulkData.Keys = append(bulkData.Keys, idx)
 				bulkData.Muts = append(bulkData.Muts, mut)
 			}
+
+			ts, err := encodeIsContractUpdateTs(blk.GetNumber(), uint64(i), uint64(j))
+			if err != nil {
+				utils.LogError(err, "error generating bigtable nft ownership timestamp", 0)
+				continue
+			}
+			// both marks share the timestamp, a self transfer must not mark the token as unowned
+			if !bytes.Equal(indexedLog.From, indexedLog.To) {
+				bigtable.markNFTOwnership(bulkData, indexedLog.From, types.NFTStandardERC721, indexedLog.TokenAddress, tokenId, ts, false)
+			}
+			bigtable.markNFTOwnership(bulkData, indexedLog.To, types.NFTStandardERC721, indexedLog.TokenAddress, tokenId, ts, true)
 		}
 	}
 
@@ -1715,6 +1804,13 @@ func (bigtable *Bigtable) TransformERC721(blk *types.Eth1Block, cache *freecache
 // Family: f
 // Column: <chainID>:ERC1155:<txHash>:<paddedLogIndex>
 // Cell:   nil
+//
+// It tracks every token ever received by an address by:
+// Row:    <chainID>:NFT:O:<TO_ADDRESS>:ERC1155:<TOKEN_ADDRESS>:<TOKEN_ID>
+// Family: f
+// Column: o
+// Cell:   0x01
+// As erc1155 tokens can be partially transferred the remaining balance has to be checked via the node when reading
 func (bigtable *Bigtable) TransformERC1155(blk *types.Eth1Block, cache *freecache.Cache) (bulkData *types.BulkMutations, bulkMetadataUpdates *types.BulkMutations, err error) {
 	startTime := time.Now()
 	defer func() {
@@ -1766,6 +1862,7 @@ func (bigtable *Bigtable) TransformERC1155(blk *types.Eth1Block, cache *freecach
 			}
 
 			indexedLog := &types.ETh1ERC1155Indexed{}
+			receivedIds := []*big.Int{}
 			transferBatch, _ := filterer.ParseTransferBatch(ethLog)
 			transferSingle, _ := filterer.ParseTransferSingle(ethLog)
 			if transferBatch == nil && transferSingle == nil {
@@ -1799,6 +1896,7 @@ func (bigtable *Bigtable) TransformERC1155(blk *types.Eth1Block, cache *freecach
 					indexedLog.Value = values[ti]
 					indexedLog.TokenAddress = log.GetAddress()
 				}
+				receivedIds = transferBatch.Ids
 			} else if transferSingle != nil {
 				indexedLog.BlockNumber = blk.GetNumber()
 				indexedLog.Time = blk.GetTime()
@@ -1809,6 +1907,7 @@ func (bigtable *Bigtable) TransformERC1155(blk *types.Eth1Block, cache *freecach
 				indexedLog.TokenId = transferSingle.Id.Bytes()
 				indexedLog.Value = transferSingle.Value.Bytes()
 				indexedLog.TokenAddress = log.GetAddress()
+				receivedIds = append(receivedIds, transferSingle.Id)
 			}
 
 			b, err := proto.Marshal(indexedLog)
@@ -1816,7 +1915,7 @@ func (bigtable *Bigtable) TransformERC1155(blk *types.Eth1Block, cache *freecach
 				return nil, nil, err
 			}
 
-			mut := gcp_bigtable.NewMutation()
+			mut := types.NewMutation()
 			mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), b)
 
 			bulkData.Keys = append(bulkData.Keys, key)
@@ -1838,7 +1937,7 @@ func (bigtable *Bigtable) TransformERC1155(blk *types.Eth1Block, cache *freecach
 			}
 
 			for _, idx := range indexes {
-				mut := gcp_bigtable.NewMutation()
+				mut := types.NewMutation()
 				mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
 
 				// if i == 3 || i == 4 {
@@ -1848,6 +1947,15 @@ func (bigtable *Bigtable) TransformERC1155(blk *types.Eth1Block, cache *freecach
 				bulkData.Keys = append(bulkData.Keys, idx)
 				bulkData.Muts = append(bulkData.Muts, mut)
 			}
+
+			ts, err := encodeIsContractUpdateTs(blk.GetNumber(), uint64(i), uint64(j))
+			if err != nil {
+				utils.LogError(err, "error generating bigtable nft ownership timestamp", 0)
+				continue
+			}
+			for _, id := range receivedIds {
+				bigtable.markNFTOwnership(bulkData, indexedLog.To, types.NFTStandardERC1155, indexedLog.TokenAddress, id, ts, true)
+			}
 		}
 	}
 
@@ -1910,7 +2018,7 @@ func (bigtable *Bigtable) TransformUncle(block *types.Eth1Block, cache *freecach
 
 		// store uncles in with the key <chainid>:U:<reversePaddedBlockNumber>:<reversePaddedUncleIndex>
 		key := fmt.Sprintf("%s:U:%s:%s", bigtable.chainId, reversedPaddedBlockNumber(block.GetNumber()), iReversed)
-		mut := gcp_bigtable.NewMutation()
+		mut := types.NewMutation()
 
 		b, err := proto.Marshal(&uncleIndexed)
 		if err != nil {
@@ -1928,7 +2036,7 @@ func (bigtable *Bigtable) TransformUncle(block *types.Eth1Block, cache *freecach
 		}
 
 		for _, idx := range indexes {
-			mut := gcp_bigtable.NewMutation()
+			mut := types.NewMutation()
 			mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
 
 			bulkData.Keys = append(bulkData.Keys, idx)
@@ -1984,7 +2092,7 @@ func (bigtable *Bigtable) TransformWithdrawals(block *types.Eth1Block, cache *fr
 
 		// store withdrawals with the key <chainid>:W:<reversePaddedBlockNumber>:<reversePaddedWithdrawalIndex>
 		key := fmt.Sprintf("%s:W:%s:%s", bigtable.chainId, reversedPaddedBlockNumber(block.GetNumber()), iReversed)
-		mut := gcp_bigtable.NewMutation()
+		mut := types.NewMutation()
 
 		b, err := proto.Marshal(&withdrawalIndexed)
 		if err != nil {
@@ -2002,7 +2110,7 @@ func (bigtable *Bigtable) TransformWithdrawals(block *types.Eth1Block, cache *fr
 		}
 
 		for _, idx := range indexes {
-			mut := gcp_bigtable.NewMutation()
+			mut := types.NewMutation()
 			mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
 
 			bulkData.Keys = append(bulkData.Keys, idx)
@@ -2849,6 +2957,44 @@ func (bigtable *Bigtable) GetAddressInternalTableData(address []byte, pageToken
 	return data, nil
 }
 
+// GetInternalTransactionsForTx returns the indexed internal transactions (value transfers) of a transaction in trace order
+func (bigtable *Bigtable) GetInternalTransactionsForTx(txHash []byte) ([]*types.Eth1InternalTransactionIndexed, error) {
+
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"txHash": txHash,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
+	defer cancel()
+
+	data := make([]*types.Eth1InternalTransactionIndexed, 0)
+	var parseErr error
+	err := bigtable.tableData.ReadRows(ctx, gcp_bigtable.PrefixRange(fmt.Sprintf("%s:ITX:%x:", bigtable.chainId, txHash)), func(row gcp_bigtable.Row) bool {
+		b := &types.Eth1InternalTransactionIndexed{}
+		parseErr = proto.Unmarshal(row[DEFAULT_FAMILY][0].Value, b)
+		if parseErr != nil {
+			return false
+		}
+		data = append(data, b)
+		return true
+	}, gcp_bigtable.RowFilter(gcp_bigtable.ColumnFilter(DATA_COLUMN)))
+	if err != nil {
+		return nil, err
+	}
+	if parseErr != nil {
+		return nil, fmt.Errorf("error parsing Eth1InternalTransactionIndexed data: %w", parseErr)
+	}
+
+	// rows are keyed by the reverse padded trace index
+	for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+		data[i], data[j] = data[j], data[i]
+	}
+	return data, nil
+}
+
 func (bigtable *Bigtable) GetInternalTransfersForTransaction(transaction []byte, from []byte, parityTrace []*rpc.ParityTraceResult, currency string) ([]types.ITransaction, error) {
 
 	names := make(map[string]string)
@@ -3159,6 +3305,44 @@ func (bigtable *Bigtable) GetAddressErc20TableData(address []byte, pageToken str
 	return data, nil
 }
 
+// GetAddressErc20Transfers returns a page of erc20 transfers of an address together with the metadata of the transferred tokens,
+// if a token is given only transfers of that token are returned
+func (bigtable *Bigtable) GetAddressErc20Transfers(address []byte, token []byte, pageToken string, limit int64) ([]*types.Eth1ERC20Indexed, map[string]*types.ERC20Metadata, string, error) {
+	defaultPageToken := fmt.Sprintf("%s:I:ERC20:%x:%s:", bigtable.chainId, address, FILTER_TIME)
+	if len(token) > 0 {
+		defaultPageToken = fmt.Sprintf("%s:I:ERC20:%x:%x:%s", bigtable.chainId, token, address, FILTER_TIME)
+	}
+	if pageToken == "" {
+		pageToken = defaultPageToken
+	} else if !strings.HasPrefix(pageToken, defaultPageToken) {
+		return nil, nil, "", fmt.Errorf("invalid pageToken for function GetAddressErc20Transfers: %s", pageToken)
+	}
+
+	var transfers []*types.Eth1ERC20Indexed
+	var lastKey string
+	var err error
+	if len(token) > 0 {
+		transfers, lastKey, err = bigtable.GetEth1TxForToken(pageToken, limit)
+	} else {
+		transfers, lastKey, err = bigtable.GetEth1ERC20ForAddress(pageToken, limit)
+	}
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	names := make(map[string]string)
+	tokens := make(map[string]*types.ERC20Metadata)
+	for _, t := range transfers {
+		tokens[string(t.TokenAddress)] = nil
+	}
+	_, tokens, err = bigtable.GetAddressesNamesArMetadata(&names, &tokens)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return transfers, tokens, lastKey, nil
+}
+
 func (bigtable *Bigtable) GetEth1ERC721ForAddress(prefix string, limit int64) ([]*types.Eth1ERC721Indexed, string, error) {
 
 	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
@@ -3732,13 +3916,270 @@ func (bigtable *Bigtable) GetERC20MetadataForAddress(address []byte) (*types.ERC
 	return ret, nil
 }
 
+// GetNFTsForAddress returns the erc721 and erc1155 tokens currently owned by an address
+func (bigtable *Bigtable) GetNFTsForAddress(address []byte, limit int64) ([]*types.Eth1AddressNFT, error) {
+
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"address": address,
+			"limit":   limit,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
+	defer cancel()
+
+	prefix := fmt.Sprintf("%s:NFT:O:%x:", bigtable.chainId, address)
+	// only return rows where the latest transfer was a receipt
+	filter := gcp_bigtable.ChainFilters(
+		gcp_bigtable.ColumnFilter(NFT_OWNERSHIP_COLUMN),
+		gcp_bigtable.LatestNFilter(1),
+		gcp_bigtable.ValueFilter("\x01"),
+	)
+
+	nfts := make([]*types.Eth1AddressNFT, 0, limit)
+	var parseErr error
+	err := bigtable.tableData.ReadRows(ctx, gcp_bigtable.PrefixRange(prefix), func(row gcp_bigtable.Row) bool {
+		// <standard>:<token>:<tokenId>
+		parts := strings.Split(strings.TrimPrefix(row.Key(), prefix), ":")
+		if len(parts) != 3 {
+			parseErr = fmt.Errorf("unexpected nft ownership row key %v", row.Key())
+			return false
+		}
+		tokenId, ok := new(big.Int).SetString(parts[2], 16)
+		if !ok {
+			parseErr = fmt.Errorf("invalid token id in nft ownership row key %v", row.Key())
+			return false
+		}
+		nfts = append(nfts, &types.Eth1AddressNFT{
+			Standard:     parts[0],
+			TokenAddress: common.FromHex(parts[1]),
+			TokenId:      tokenId,
+			Balance:      big.NewInt(1),
+		})
+		return true
+	}, gcp_bigtable.RowFilter(filter), gcp_bigtable.LimitRows(limit))
+	if err != nil {
+		return nil, err
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	// erc1155 tokens may have been sent partially so the ownership index only tells us which tokens have been received
+	g := new(errgroup.Group)
+	g.SetLimit(10)
+	for _, nft := range nfts {
+		nft := nft
+		g.Go(func() error {
+			if nft.Standard == types.NFTStandardERC1155 {
+				balance, err := rpc.CurrentGethClient.GetERC1155TokenBalance(address, nft.TokenAddress, nft.TokenId)
+				if err != nil {
+					logger.Warnf("error retrieving erc1155 balance of token %x id %v for address %x: %v", nft.TokenAddress, nft.TokenId, address, err)
+				} else {
+					nft.Balance = balance
+				}
+			}
+			metadata, err := bigtable.GetNFTMetadata(nft.Standard, nft.TokenAddress, nft.TokenId)
+			if err != nil {
+				return err
+			}
+			nft.Metadata = metadata
+			return nil
+		})
+	}
+	err = g.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make([]*types.Eth1AddressNFT, 0, len(nfts))
+	for _, nft := range nfts {
+		if nft.Balance.Sign() > 0 {
+			owned = append(owned, nft)
+		}
+	}
+	return owned, nil
+}
+
+// GetNFTMetadata returns the cached metadata of an nft. Token uris mostly point to external servers, so the metadata
+// is resolved in the background by nftMetadataWorker and nil is returned until it is available.
+func (bigtable *Bigtable) GetNFTMetadata(standard string, token []byte, tokenId *big.Int) (*types.NFTMetadata, error) {
+	cacheKey := nftMetadataCacheKey(bigtable.chainId, token, tokenId)
+	if cached, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, time.Hour*1, new(types.NFTMetadata)); err == nil {
+		return cached.(*types.NFTMetadata), nil
+	}
+
+	nftMetadataWorkerOnce.Do(func() {
+		for i := 0; i < nftMetadataWorkers; i++ {
+			go bigtable.nftMetadataWorker()
+		}
+	})
+	if _, pending := nftMetadataPending.LoadOrStore(cacheKey, true); pending {
+		return nil, nil
+	}
+	select {
+	case nftMetadataQueue <- &nftMetadataRequest{Standard: standard, Token: token, TokenId: tokenId}:
+	default:
+		// the queue is full, the metadata is requested again by the next page view
+		nftMetadataPending.Delete(cacheKey)
+	}
+	return nil, nil
+}
+
+func nftMetadataCacheKey(chainId string, token []byte, tokenId *big.Int) string {
+	return fmt.Sprintf("%s:NFT:%#x:%s", chainId, token, tokenId.Text(16))
+}
+
+type nftMetadataRequest struct {
+	Standard string
+	Token    []byte
+	TokenId  *big.Int
+}
+
+const nftMetadataWorkers = 4
+
+var nftMetadataQueue = make(chan *nftMetadataRequest, 1000)
+var nftMetadataPending sync.Map
+var nftMetadataWorkerOnce sync.Once
+
+// nftMetadataWorker resolves the token uris of queued nfts and caches their metadata
+func (bigtable *Bigtable) nftMetadataWorker() {
+	for req := range nftMetadataQueue {
+		cacheKey := nftMetadataCacheKey(bigtable.chainId, req.Token, req.TokenId)
+		err := bigtable.resolveNFTMetadata(cacheKey, req)
+		if err != nil {
+			logger.WithError(err).Warnf("error caching metadata of nft %x id %v", req.Token, req.TokenId)
+		}
+		nftMetadataPending.Delete(cacheKey)
+	}
+}
+
+func (bigtable *Bigtable) resolveNFTMetadata(cacheKey string, req *nftMetadataRequest) error {
+	metadata := &types.NFTMetadata{}
+	uri, err := rpc.CurrentGethClient.GetNFTTokenURI(req.Standard, req.Token, req.TokenId)
+	if err != nil {
+		logger.Warnf("error retrieving token uri of nft %x id %v: %v", req.Token, req.TokenId, err)
+	} else {
+		metadata, err = fetchNFTMetadata(uri)
+		if err != nil {
+			logger.Warnf("error retrieving metadata of nft %x id %v from %v: %v", req.Token, req.TokenId, uri, err)
+			metadata = &types.NFTMetadata{}
+		}
+		metadata.TokenURI = uri
+	}
+
+	// failed lookups are cached for a shorter time so temporarily unavailable metadata servers get retried
+	expiration := utils.Day
+	if metadata.TokenURI == "" || metadata.Name == "" && metadata.Image == "" {
+		expiration = time.Minute * 10
+	}
+	return cache.TieredCache.Set(cacheKey, metadata, expiration)
+}
+
+// nftHttpClient is used to retrieve nft metadata from external servers, it only connects to public addresses so token
+// uris can not be used to reach internal services
+var nftHttpClient = &http.Client{
+	Timeout: time.Second * 5,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: time.Second * 5,
+			Control: dialPublicAddressOnly,
+		}).DialContext,
+		TLSHandshakeTimeout:   time.Second * 5,
+		ResponseHeaderTimeout: time.Second * 5,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       time.Minute,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 3 {
+			return fmt.Errorf("stopped after %v redirects", len(via))
+		}
+		return nil
+	},
+}
+
+// carrierGradeNat is the shared address space of RFC 6598, it is not covered by net.IP.IsPrivate
+var carrierGradeNat = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// dialPublicAddressOnly is the control function of the nft metadata dialer, it is called with the resolved address of
+// every connection (including redirects) and rejects all addresses that are not publicly routable
+func dialPublicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || carrierGradeNat.Contains(ip) ||
+		ip.To4() != nil && ip.To4()[0] == 0 {
+		return fmt.Errorf("connecting to non public address %v is not allowed", host)
+	}
+	return nil
+}
+
+// maxNFTMetadataSize limits the size of nft metadata documents we are willing to read
+const maxNFTMetadataSize = 256 << 10
+
+func fetchNFTMetadata(uri string) (*types.NFTMetadata, error) {
+	metadata := &types.NFTMetadata{}
+
+	var body []byte
+	switch {
+	case strings.HasPrefix(uri, "data:application/json;base64,"):
+		b, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(uri, "data:application/json;base64,"))
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	case strings.HasPrefix(uri, "data:application/json,"):
+		b, err := url.PathUnescape(strings.TrimPrefix(uri, "data:application/json,"))
+		if err != nil {
+			return nil, err
+		}
+		body = []byte(b)
+	default:
+		// ipfs uris are fetched through the public gateway
+		resolved := utils.ResolveIpfsURI(uri)
+		if !strings.HasPrefix(resolved, "https://") && !strings.HasPrefix(resolved, "http://") {
+			return nil, fmt.Errorf("unsupported token uri scheme")
+		}
+		resp, err := nftHttpClient.Get(resolved)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code %v", resp.StatusCode)
+		}
+		if resp.ContentLength > maxNFTMetadataSize {
+			return nil, fmt.Errorf("metadata of %v bytes exceeds the limit of %v bytes", resp.ContentLength, maxNFTMetadataSize)
+		}
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxNFTMetadataSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(body) > maxNFTMetadataSize {
+			return nil, fmt.Errorf("metadata exceeds the limit of %v bytes", maxNFTMetadataSize)
+		}
+	}
+
+	err := json.Unmarshal(body, metadata)
+	if err != nil {
+		return nil, err
+	}
+	metadata.Image = utils.ResolveIpfsURI(metadata.Image)
+	return metadata, nil
+}
+
 func (bigtable *Bigtable) SaveERC20Metadata(address []byte, metadata *types.ERC20Metadata) error {
 	rowKey := fmt.Sprintf("%s:%x", bigtable.chainId, address)
 
 	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
 	defer cancel()
 
-	mut := gcp_bigtable.NewMutation()
+	mut := types.NewMutation()
 	if len(metadata.Decimals) > 0 {
 		mut.Set(ERC20_METADATA_FAMILY, ERC20_COLUMN_DECIMALS, gcp_bigtable.Timestamp(0), metadata.Decimals)
 	}
@@ -3768,7 +4209,7 @@ func (bigtable *Bigtable) SaveERC20Metadata(address []byte, metadata *types.ERC2
 		mut.Set(ERC20_METADATA_FAMILY, ERC20_COLUMN_LOGO_FORMAT, gcp_bigtable.Timestamp(0), []byte(metadata.LogoFormat))
 	}
 
-	return bigtable.tableMetadata.Apply(ctx, rowKey, mut)
+	return bigtable.tableMetadata.Apply(ctx, rowKey, mut.Mutation)
 }
 
 func (bigtable *Bigtable) GetAddressName(address []byte) (string, error) {
@@ -4081,10 +4522,10 @@ func (bigtable *Bigtable) SaveAddressName(address []byte, name string) error {
 	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
 	defer cancel()
 
-	mut := gcp_bigtable.NewMutation()
+	mut := types.NewMutation()
 	mut.Set(ACCOUNT_METADATA_FAMILY, ACCOUNT_COLUMN_NAME, gcp_bigtable.Timestamp(0), []byte(name))
 
-	return bigtable.tableMetadata.Apply(ctx, fmt.Sprintf("%s:%x", bigtable.chainId, address), mut)
+	return bigtable.tableMetadata.Apply(ctx, fmt.Sprintf("%s:%x", bigtable.chainId, address), mut.Mutation)
 }
 
 func (bigtable *Bigtable) GetContractMetadata(address []byte) (*types.ContractMetadata, error) {
@@ -4179,11 +4620,55 @@ func (bigtable *Bigtable) SaveContractMetadata(address []byte, metadata *types.C
 	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
 	defer cancel()
 
-	mut := gcp_bigtable.NewMutation()
+	mut := types.NewMutation()
 	mut.Set(CONTRACT_METADATA_FAMILY, CONTRACT_NAME, gcp_bigtable.Timestamp(0), []byte(metadata.Name))
 	mut.Set(CONTRACT_METADATA_FAMILY, CONTRACT_ABI, gcp_bigtable.Timestamp(0), metadata.ABIJson)
 
-	return bigtable.tableMetadata.Apply(ctx, fmt.Sprintf("%s:%x", bigtable.chainId, address), mut)
+	err := bigtable.tableMetadata.Apply(ctx, fmt.Sprintf("%s:%x", bigtable.chainId, address), mut.Mutation)
+	if err != nil {
+		return err
+	}
+
+	if metadata.ABI != nil {
+		return bigtable.SaveAbiSignatures(metadata.ABI)
+	}
+	return nil
+}
+
+// SaveAbiSignatures adds the method and event signatures of a contract abi to the signature database
+func (bigtable *Bigtable) SaveAbiSignatures(contractAbi *abi.ABI) error {
+	methods := make([]types.Signature, 0, len(contractAbi.Methods))
+	for _, method := range contractAbi.Methods {
+		methods = append(methods, types.Signature{Text: method.Sig, Hex: fmt.Sprintf("0x%x", method.ID)})
+	}
+	err := bigtable.SaveSignatures(methods, types.MethodSignature)
+	if err != nil {
+		return fmt.Errorf("error saving method signatures: %w", err)
+	}
+
+	events := make([]types.Signature, 0, len(contractAbi.Events))
+	for _, event := range contractAbi.Events {
+		if event.Anonymous {
+			continue
+		}
+		events = append(events, types.Signature{Text: event.Sig, Hex: event.ID.Hex()})
+	}
+	err = bigtable.SaveSignatures(events, types.EventSignature)
+	if err != nil {
+		return fmt.Errorf("error saving event signatures: %w", err)
+	}
+	return nil
+}
+
+// UpdateContractMetadata overwrites the stored and cached metadata of a contract, e.g. after its source has been verified
+func (bigtable *Bigtable) UpdateContractMetadata(address []byte, metadata *types.ContractMetadata) error {
+	err := bigtable.SaveContractMetadata(address, metadata)
+	if err != nil {
+		return err
+	}
+
+	rowKey := fmt.Sprintf("%s:%x", bigtable.chainId, address)
+	return cache.TieredCache.Set(bigtable.chainId+":CONTRACT:"+rowKey, metadata, utils.Day)
 }
 
 func (bigtable *Bigtable) SaveBalances(balances []*types.Eth1AddressBalance, deleteKeys []string) error {
@@ -4198,11 +4683,11 @@ func (bigtable *Bigtable) SaveBalances(balances []*types.Eth1AddressBalance, del
 
 	mutsWrite := &types.BulkMutations{
 		Keys: make([]string, 0, len(balances)),
-		Muts: make([]*gcp_bigtable.Mutation, 0, len(balances)),
+		Muts: make([]*types.Mutation, 0, len(balances)),
 	}
 
 	for _, balance := range balances {
-		mutWrite := gcp_bigtable.NewMutation()
+		mutWrite := types.NewMutation()
 
 		mutWrite.Set(ACCOUNT_METADATA_FAMILY, fmt.Sprintf("B:%x", balance.Token), gcp_bigtable.Timestamp(0), balance.Balance)
 		mutsWrite.Keys = append(mutsWrite.Keys, fmt.Sprintf("%s:%x", bigtable.chainId, balance.Address))
@@ -4220,10 +4705,10 @@ func (bigtable *Bigtable) SaveBalances(balances []*types.Eth1AddressBalance, del
 	}
 	mutsDelete := &types.BulkMutations{
 		Keys: make([]string, 0, len(balances)),
-		Muts: make([]*gcp_bigtable.Mutation, 0, len(balances)),
+		Muts: make([]*types.Mutation, 0, len(balances)),
 	}
 	for _, key := range deleteKeys {
-		mutDelete := gcp_bigtable.NewMutation()
+		mutDelete := types.NewMutation()
 		mutDelete.DeleteRow()
 		mutsDelete.Keys = append(mutsDelete.Keys, key)
 		mutsDelete.Muts = append(mutsDelete.Muts, mutDelete)
@@ -4245,12 +4730,12 @@ func (bigtable *Bigtable) SaveERC20TokenPrices(prices []*types.ERC20TokenPrice)
 
 	mutsWrite := &types.BulkMutations{
 		Keys: make([]string, 0, len(prices)),
-		Muts: make([]*gcp_bigtable.Mutation, 0, len(prices)),
+		Muts: make([]*types.Mutation, 0, len(prices)),
 	}
 
 	for _, price := range prices {
 		rowKey := fmt.Sprintf("%s:%x", bigtable.chainId, price.Token)
-		mut := gcp_bigtable.NewMutation()
+		mut := types.NewMutation()
 		mut.Set(ERC20_METADATA_FAMILY, ERC20_COLUMN_PRICE, gcp_bigtable.Timestamp(0), price.Price)
 		mut.Set(ERC20_METADATA_FAMILY, ERC20_COLUMN_TOTALSUPPLY, gcp_bigtable.Timestamp(0), price.TotalSupply)
 		mutsWrite.Keys = append(mutsWrite.Keys, rowKey)
@@ -4270,11 +4755,11 @@ func (bigtable *Bigtable) SaveBlockKeys(blockNumber uint64, blockHash []byte, ke
 	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
 	defer cancel()
 
-	mut := gcp_bigtable.NewMutation()
+	mut := types.NewMutation()
 	mut.Set(METADATA_UPDATES_FAMILY_BLOCKS, "keys", gcp_bigtable.Now(), []byte(keys))
 
 	key := fmt.Sprintf("%s:BLOCK:%s:%x", bigtable.chainId, reversedPaddedBlockNumber(blockNumber), blockHash)
-	err := bigtable.tableMetadataUpdates.Apply(ctx, key, mut)
+	err := bigtable.tableMetadataUpdates.Apply(ctx, key, mut.Mutation)
 
 	return err
 }
@@ -4328,7 +4813,7 @@ func (bigtable *Bigtable) DeleteBlock(blockNumber uint64, blockHash []byte) erro
 
 	mutsDelete := &types.BulkMutations{
 		Keys: make([]string, 0),
-		Muts: make([]*gcp_bigtable.Mutation, 0),
+		Muts: make([]*types.Mutation, 0),
 	}
 
 	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
@@ -4342,7 +4827,7 @@ func (bigtable *Bigtable) DeleteBlock(blockNumber uint64, blockHash []byte) erro
 	defer cancel()
 
 	err = bigtable.tableMetadata.ReadRows(ctx, gcp_bigtable.PrefixRange(fmt.Sprintf("%s:S:", bigtable.chainId)), func(row gcp_bigtable.Row) bool {
-		mutDelete := gcp_bigtable.NewMutation()
+		mutDelete := types.NewMutation()
 		mutDelete.DeleteTimestampRange(ACCOUNT_METADATA_FAMILY, ACCOUNT_IS_CONTRACT, starttime, endtime)
 
 		mutsDelete.Keys = append(mutsDelete.Keys, row.Key())
@@ -4369,11 +4854,17 @@ func (bigtable *Bigtable) DeleteBlock(blockNumber uint64, blockHash []byte) erro
 	// Delete all of those keys
 	mutsDelete = &types.BulkMutations{
 		Keys: make([]string, 0, len(keys)),
-		Muts: make([]*gcp_bigtable.Mutation, 0, len(keys)),
+		Muts: make([]*types.Mutation, 0, len(keys)),
 	}
+	nftOwnershipPrefix := fmt.Sprintf("%s:NFT:O:", bigtable.chainId)
 	for _, key := range keys {
-		mutDelete := gcp_bigtable.NewMutation()
-		mutDelete.DeleteRow()
+		mutDelete := types.NewMutation()
+		if strings.HasPrefix(key, nftOwnershipPrefix) {
+			// nft ownership rows are shared between blocks, only remove the transfers of this block
+			mutDelete.DeleteTimestampRange(DEFAULT_FAMILY, NFT_OWNERSHIP_COLUMN, starttime, endtime)
+		} else {
+			mutDelete.DeleteRow()
+		}
 		mutsDelete.Keys = append(mutsDelete.Keys, key)
 		mutsDelete.Muts = append(mutsDelete.Muts, mutDelete)
 	}
@@ -4385,9 +4876,9 @@ func (bigtable *Bigtable) DeleteBlock(blockNumber uint64, blockHash []byte) erro
 
 	mutsDelete = &types.BulkMutations{
 		Keys: make([]string, 0, len(keys)),
-		Muts: make([]*gcp_bigtable.Mutation, 0, len(keys)),
+		Muts: make([]*types.Mutation, 0, len(keys)),
 	}
-	mutDelete := gcp_bigtable.NewMutation()
+	mutDelete := types.NewMutation()
 	mutDelete.DeleteRow()
 	mutsDelete.Keys = append(mutsDelete.Keys, fmt.Sprintf("%s:%s", bigtable.chainId, reversedPaddedBlockNumber(blockNumber)))
 	mutsDelete.Muts = append(mutsDelete.Muts, mutDelete)
@@ -4615,7 +5106,7 @@ func (bigtable *Bigtable) SaveSignatureImportStatus(status types.SignatureImport
 
 	mutsWrite := &types.BulkMutations{
 		Keys: make([]string, 0, 1),
-		Muts: make([]*gcp_bigtable.Mutation, 0, 1),
+		Muts: make([]*types.Mutation, 0, 1),
 	}
 
 	s, err := json.Marshal(status)
@@ -4623,7 +5114,7 @@ func (bigtable *Bigtable) SaveSignatureImportStatus(status types.SignatureImport
 		return err
 	}
 
-	mut := gcp_bigtable.NewMutation()
+	mut := types.NewMutation()
 	mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), s)
 
 	key := fmt.Sprintf("1:%v_SIGNATURE_IMPORT_STATUS", getSignaturePrefix(st))
@@ -4645,11 +5136,11 @@ func (bigtable *Bigtable) SaveSignatures(signatures []types.Signature, st types.
 
 	mutsWrite := &types.BulkMutations{
 		Keys: make([]string, 0, 1),
-		Muts: make([]*gcp_bigtable.Mutation, 0, 1),
+		Muts: make([]*types.Mutation, 0, 1),
 	}
 
 	for _, sig := range signatures {
-		mut := gcp_bigtable.NewMutation()
+		mut := types.NewMutation()
 		mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), []byte(sig.Text))
 
 		key := fmt.Sprintf("1:%v_SIGNATURE:%v", getSignaturePrefix(st), sig.Hex)
@@ -4777,11 +5268,31 @@ func prefixSuccessor(prefix string, pos int) string {
 	return string(ans)
 }
 
+func nftOwnershipKey(chainId string, owner []byte, standard string, token []byte, tokenId *big.Int) string {
+	return fmt.Sprintf("%s:NFT:O:%x:%s:%x:%s", chainId, owner, standard, token, tokenId.Text(16))
+}
+
+func (bigtable *Bigtable) markNFTOwnership(mutations *types.BulkMutations, owner []byte, standard string, token []byte, tokenId *big.Int, ts gcp_bigtable.Timestamp, owned bool) {
+	// mints and burns do not change the ownership of the zero address
+	if bytes.Equal(owner, ZERO_ADDRESS) {
+		return
+	}
+	value := []byte{0x0}
+	if owned {
+		value = []byte{0x1}
+	}
+	mut := types.NewMutation()
+	mut.Set(DEFAULT_FAMILY, NFT_OWNERSHIP_COLUMN, ts, value)
+
+	mutations.Keys = append(mutations.Keys, nftOwnershipKey(bigtable.chainId, owner, standard, token, tokenId))
+	mutations.Muts = append(mutations.Muts, mut)
+}
+
 func (bigtable *Bigtable) markBalanceUpdate(address []byte, token []byte, mutations *types.BulkMutations, cache *freecache.Cache) {
 	balanceUpdateKey := fmt.Sprintf("%s:B:%x", bigtable.chainId, address)                        // format is B: for balance update as chainid:prefix:address (token id will be encoded as column name)
 	balanceUpdateCacheKey := []byte(fmt.Sprintf("%s:B:%x:%x", bigtable.chainId, address, token)) // format is B: for balance update as chainid:prefix:address (token id will be encoded as column name)
 	if _, err := cache.Get(balanceUpdateCacheKey); err != nil {
-		mut := gcp_bigtable.NewMutation()
+		mut := types.NewMutation()
 		mut.Set(DEFAULT_FAMILY, fmt.Sprintf("%x", token), gcp_bigtable.Timestamp(0), []byte{})
 
 		mutations.Keys = append(mutations.Keys, balanceUpdateKey)
@@ -4807,13 +5318,13 @@ func (bigtable *Bigtable) SaveGasNowHistory(slow, standard, rapid, fast *big.Int
 
 	gcpTs := gcp_bigtable.Time(ts)
 
-	mut := gcp_bigtable.NewMutation()
+	mut := types.NewMutation()
 	mut.Set(SERIES_FAMILY, GASNOW_SLOW_COLUMN, gcpTs, slow.Bytes())
 	mut.Set(SERIES_FAMILY, GASNOW_STANDARD_COLUMN, gcpTs, standard.Bytes())
 	mut.Set(SERIES_FAMILY, GASNOW_FAST_COLUMN, gcpTs, fast.Bytes())
 	mut.Set(SERIES_FAMILY, GASNOW_RAPID_COLUMN, gcpTs, rapid.Bytes())
 
-	err := bigtable.tableMetadata.Apply(ctx, row, mut)
+	err := bigtable.tableMetadata.Apply(ctx, row, mut.Mutation)
 	if err != nil {
 		return fmt.Errorf("error saving gas now history to bigtable. err: %w", err)
 	}
@@ -13,6 +13,10 @@ import (
 )
 
 func InitBigtableSchema() error {
+	if utils.Config.Bigtable.Backend == BigtableBackendPostgres {
+		logrus.Infof("using postgres bigtable backend, the bigtable_cells table is created by the db migrations")
+		return nil
+	}
 
 	tables := make(map[string]map[string]gcp_bigtable.GCPolicy)
 
@@ -35,7 +39,9 @@ func InitBigtableSchema() error {
 		DEFAULT_FAMILY:           nil,
 	}
 	tables["machine_metrics"] = map[string]gcp_bigtable.GCPolicy{
-		MACHINE_METRICS_COLUMN_FAMILY: gcp_bigtable.MaxAgeGCPolicy(utils.Day * 31),
+		MACHINE_METRICS_COLUMN_FAMILY:    gcp_bigtable.MaxAgeGCPolicy(utils.Day * 31),
+		MACHINE_METRICS_5M_COLUMN_FAMILY: gcp_bigtable.MaxAgeGCPolicy(utils.Day * 180),
+		MACHINE_METRICS_1H_COLUMN_FAMILY: gcp_bigtable.MaxAgeGCPolicy(utils.Day * 730),
 	}
 	tables["metadata"] = map[string]gcp_bigtable.GCPolicy{
 		ACCOUNT_METADATA_FAMILY:  nil,
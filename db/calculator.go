@@ -0,0 +1,39 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// GetStakingCalculatorNetworkStats returns the average consensus and execution apr of the network (as fractions) and the
+// mev statistics of the proposed blocks over the last exported days
+func GetStakingCalculatorNetworkStats(days uint64) (*types.StakingCalculatorNetworkStats, error) {
+	stats := &types.StakingCalculatorNetworkStats{}
+	err := ReaderDb.Get(stats, `
+		SELECT
+			COALESCE(AVG(consensus_rewards_sum_wei / NULLIF(effective_balances_sum_wei, 0) * 365), 0) AS consensus_apr,
+			COALESCE(AVG(tx_fees_sum_wei / NULLIF(effective_balances_sum_wei, 0) * 365), 0) AS execution_apr
+		FROM eth_store_stats
+		WHERE validator = -1 AND day > (SELECT COALESCE(MAX(day), 0) FROM eth_store_stats WHERE validator = -1) - $1`, days)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving eth.store apr for staking calculator: %w", err)
+	}
+
+	err = ReaderDb.Get(stats, `
+		SELECT
+			COALESCE(SUM(builder_blocks)::FLOAT / NULLIF(SUM(builder_blocks + local_blocks), 0), 0) AS mev_block_share,
+			COALESCE(SUM(mev_value) / NULLIF(SUM(builder_blocks), 0) / 1e18, 0) AS avg_mev_per_block
+		FROM mev_income
+		WHERE day > (SELECT COALESCE(MAX(day), 0) FROM mev_income_stats_status WHERE status) - $1`, days)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving mev statistics for staking calculator: %w", err)
+	}
+
+	stats.TotalStaked, err = GetTotalEligibleEther()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving total eligible ether for staking calculator: %w", err)
+	}
+
+	return stats, nil
+}
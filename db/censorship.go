@@ -0,0 +1,163 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// SaveCensorshipWatchedTxs stores transactions of watchlisted addresses seen in the mempool, the first seen timestamp of already known transactions is kept
+func SaveCensorshipWatchedTxs(txs []*types.CensorshipWatchedTx) error {
+	if len(txs) == 0 {
+		return nil
+	}
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in SaveCensorshipWatchedTxs: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, wtx := range txs {
+		_, err = tx.Exec(`
+			insert into censorship_watched_txs (tx_hash, from_address, to_address, first_seen_ts)
+			values ($1, $2, $3, to_timestamp($4) at time zone 'utc')
+			on conflict (tx_hash) do nothing`, wtx.TxHash, wtx.FromAddress, wtx.ToAddress, wtx.FirstSeen.Unix())
+		if err != nil {
+			return fmt.Errorf("error inserting watched tx %#x in SaveCensorshipWatchedTxs: %w", wtx.TxHash, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetPendingCensorshipWatchedTxHashes returns the hashes of all watched transactions that have not been included yet and were first seen after the given time
+func GetPendingCensorshipWatchedTxHashes(since time.Time) ([][]byte, error) {
+	hashes := [][]byte{}
+	err := ReaderDb.Select(&hashes, `
+		select tx_hash
+		from censorship_watched_txs
+		where included_slot is null and first_seen_ts >= to_timestamp($1) at time zone 'utc'`, since.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("error getting pending watched txs: %w", err)
+	}
+	return hashes, nil
+}
+
+// UpdateCensorshipWatchedTxInclusion marks a watched transaction as included in the given execution block.
+// The inclusion delay is the time between the transaction being first seen and the timestamp of the including block,
+// skipped blocks are all canonical blocks proposed after the transaction was first seen that did not include it.
+// It returns false if the including block has not been exported yet.
+func UpdateCensorshipWatchedTxInclusion(txHash []byte, blockNumber uint64) (bool, error) {
+	var firstSeen uint64
+	err := WriterDb.Get(&firstSeen, `select extract(epoch from first_seen_ts)::bigint from censorship_watched_txs where tx_hash = $1`, txHash)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error getting first seen time of watched tx %#x: %w", txHash, err)
+	}
+	// bound the scan of skipped blocks to the slots between the tx being first seen and its inclusion
+	firstSlot := utils.TimeToSlot(firstSeen)
+
+	res, err := WriterDb.Exec(`
+		update censorship_watched_txs wt set
+			included_block_number = b.exec_block_number,
+			included_slot         = b.slot,
+			inclusion_delay       = greatest(b.exec_timestamp - extract(epoch from wt.first_seen_ts)::int, 0),
+			skipped_blocks        = (
+				select count(*)
+				from blocks sb
+				where sb.slot >= $3 and sb.slot < b.slot
+					and sb.status = '1' and sb.exec_block_number is not null
+					and sb.exec_timestamp > extract(epoch from wt.first_seen_ts)::int
+					and sb.exec_block_number < b.exec_block_number
+			)
+		from blocks b
+		where wt.tx_hash = $1 and b.exec_block_number = $2 and b.status = '1'`, txHash, blockNumber, firstSlot)
+	if err != nil {
+		return false, fmt.Errorf("error updating inclusion of watched tx %#x: %w", txHash, err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// GetCensorshipDailyStats returns the censorship metrics of all builders, relays and the whole network for the given day
+func GetCensorshipDailyStats(day uint64) ([]*types.CensorshipDailyStats, error) {
+	stats := []*types.CensorshipDailyStats{}
+	err := ReaderDb.Select(&stats, `
+		select
+			day, entity_type, entity, block_count, watched_tx_block_count, watched_tx_count, avg_inclusion_delay, avg_skipped_blocks,
+			coalesce((select '0x' || encode(builders.extra_data, 'hex') from builders where '0x' || encode(builders.builder_pubkey, 'hex') = censorship_daily_stats.entity), '') as builder_extra_data
+		from censorship_daily_stats
+		where day = $1
+		order by entity_type, block_count desc`, day)
+	if err != nil {
+		return nil, fmt.Errorf("error getting censorship stats for day %v: %w", day, err)
+	}
+	return stats, nil
+}
+
+// GetCensorshipNetworkHistory returns the daily network wide censorship metrics starting at the given day
+func GetCensorshipNetworkHistory(fromDay uint64) ([]*types.CensorshipDailyStats, error) {
+	stats := []*types.CensorshipDailyStats{}
+	err := ReaderDb.Select(&stats, `
+		select day, entity_type, entity, block_count, watched_tx_block_count, watched_tx_count, avg_inclusion_delay, avg_skipped_blocks, '' as builder_extra_data
+		from censorship_daily_stats
+		where day >= $1 and entity_type = $2
+		order by day`, fromDay, types.CensorshipEntityNetwork)
+	if err != nil {
+		return nil, fmt.Errorf("error getting censorship network history: %w", err)
+	}
+	return stats, nil
+}
+
+// GetLatestCensorshipStatsDay returns the latest day that has been completely exported
+func GetLatestCensorshipStatsDay() (uint64, error) {
+	var day uint64
+	err := ReaderDb.Get(&day, `select coalesce(max(day), 0) from censorship_daily_stats_status where status`)
+	if err != nil {
+		return 0, fmt.Errorf("error getting latest censorship stats day: %w", err)
+	}
+	return day, nil
+}
+
+// GetRecentCensorshipWatchedTxs returns the most recently included watched transactions
+func GetRecentCensorshipWatchedTxs(limit uint64) ([]*types.CensorshipWatchedTx, error) {
+	txs := []*types.CensorshipWatchedTx{}
+	err := ReaderDb.Select(&txs, `
+		select
+			wt.tx_hash,
+			wt.from_address,
+			wt.to_address,
+			wt.first_seen_ts,
+			wt.included_block_number,
+			wt.included_slot,
+			wt.inclusion_delay,
+			wt.skipped_blocks,
+			blocks.proposer,
+			coalesce(rb.tag_id, '') as relay_tag_id,
+			coalesce(rb.builder_pubkey, ''::bytea) as builder_pubkey
+		from censorship_watched_txs wt
+		inner join blocks on blocks.slot = wt.included_slot and blocks.status = '1'
+		left join lateral (
+			select tag_id, builder_pubkey
+			from relays_blocks
+			where relays_blocks.block_slot = blocks.slot and relays_blocks.block_root = blocks.blockroot
+			order by tag_id
+			limit 1
+		) rb on true
+		where wt.included_slot is not null
+		order by wt.included_slot desc, wt.tx_hash
+		limit $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error getting recent watched txs: %w", err)
+	}
+	return txs, nil
+}
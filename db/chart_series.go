@@ -0,0 +1,122 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// ChartSeriesHourlyRetention is the time range the hourly chart series are kept for, older ranges are served daily
+const ChartSeriesHourlyRetention = 90 * utils.Day
+
+// WriteChartSeriesForHour aggregates the consensus indicators of the epochs (and their slots) of an hour into
+// chart_series_hourly, an epoch belongs to the hour it started in. The indicators match the daily indicators of chart_series.
+func WriteChartSeriesForHour(hour time.Time) error {
+	hour = hour.UTC().Truncate(time.Hour)
+	firstEpoch := utils.TimeToEpoch(hour)
+	lastEpoch := utils.TimeToEpoch(hour.Add(time.Hour))
+	if lastEpoch <= firstEpoch {
+		return nil
+	}
+	slotsPerEpoch := int64(utils.Config.Chain.ClConfig.SlotsPerEpoch)
+
+	_, err := WriterDb.Exec(`
+		WITH hour_epochs AS (
+			SELECT epoch, validatorscount, eligibleether, averagevalidatorbalance, globalparticipationrate
+			FROM epochs
+			WHERE epoch >= $2 AND epoch < $3
+		),
+		hour_blocks AS (
+			SELECT status, COUNT(*) AS blocks
+			FROM blocks
+			WHERE slot >= $4 AND slot < $5
+			GROUP BY status
+		)
+		INSERT INTO chart_series_hourly (time, indicator, value)
+		SELECT $1, indicator, value FROM (
+			SELECT 'ACTIVE_VALIDATORS' AS indicator, AVG(validatorscount) AS value FROM hour_epochs
+			UNION ALL
+			SELECT 'STAKED_ETH', (ARRAY_AGG(eligibleether ORDER BY epoch DESC))[1] / 1e9 FROM hour_epochs
+			UNION ALL
+			SELECT 'AVG_VALIDATOR_BALANCE_ETH', AVG(averagevalidatorbalance) / 1e9 FROM hour_epochs
+			UNION ALL
+			SELECT 'AVG_PARTICIPATION_RATE', AVG(globalparticipationrate) FROM hour_epochs
+			UNION ALL
+			SELECT 'PROPOSED_BLOCKS', COALESCE(SUM(blocks) FILTER (WHERE status = '1'), 0) FROM hour_blocks
+			UNION ALL
+			SELECT 'MISSED_BLOCKS', COALESCE(SUM(blocks) FILTER (WHERE status = '2'), 0) FROM hour_blocks
+			UNION ALL
+			SELECT 'ORPHANED_BLOCKS', COALESCE(SUM(blocks) FILTER (WHERE status = '3'), 0) FROM hour_blocks
+		) indicators
+		WHERE value IS NOT NULL
+		ON CONFLICT (indicator, time) DO UPDATE SET value = excluded.value`,
+		hour, firstEpoch, lastEpoch, firstEpoch*slotsPerEpoch, lastEpoch*slotsPerEpoch)
+	if err != nil {
+		return fmt.Errorf("error writing hourly chart series of %v: %w", hour, err)
+	}
+	return nil
+}
+
+// UpdateChartSeriesHourly writes the hourly chart series incrementally: the latest written hour is rewritten (it might
+// have been incomplete) and all following hours up to the hour of the epoch are added. Hours older than the retention are
+// neither backfilled nor kept. The amount of written hours is returned.
+func UpdateChartSeriesHourly(epoch uint64) (int, error) {
+	until := utils.EpochToTime(epoch).UTC().Truncate(time.Hour)
+	from := until.Add(-ChartSeriesHourlyRetention)
+
+	var latest sql.NullTime
+	err := WriterDb.Get(&latest, `SELECT MAX(time) FROM chart_series_hourly`)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving latest hourly chart series: %w", err)
+	}
+	if latest.Valid && latest.Time.After(from) {
+		from = latest.Time
+	}
+
+	written := 0
+	for hour := from; !hour.After(until); hour = hour.Add(time.Hour) {
+		err = WriteChartSeriesForHour(hour)
+		if err != nil {
+			return written, err
+		}
+		written++
+	}
+
+	_, err = WriterDb.Exec(`DELETE FROM chart_series_hourly WHERE time < $1`, until.Add(-ChartSeriesHourlyRetention))
+	if err != nil {
+		return written, fmt.Errorf("error pruning hourly chart series: %w", err)
+	}
+	return written, nil
+}
+
+// GetChartSeriesIndicators returns the indicators of the daily chart series
+func GetChartSeriesIndicators() ([]string, error) {
+	indicators := []string{}
+	err := ReaderDb.Select(&indicators, `SELECT DISTINCT indicator FROM chart_series WHERE time > NOW() - INTERVAL '1 week' ORDER BY indicator`)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving chart series indicators: %w", err)
+	}
+	return indicators, nil
+}
+
+// GetChartSeriesPoints returns the points of a chart series within [from, to), resolution is either "day" or "hour"
+func GetChartSeriesPoints(indicator, resolution string, from, to time.Time) ([]*types.ChartSeriesPoint, error) {
+	table := "chart_series"
+	if resolution == "hour" {
+		table = "chart_series_hourly"
+	}
+
+	points := []*types.ChartSeriesPoint{}
+	err := ReaderDb.Select(&points, fmt.Sprintf(`
+		SELECT time, value
+		FROM %s
+		WHERE indicator = $1 AND time >= $2 AND time < $3
+		ORDER BY time`, table), indicator, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving %v points of chart series %v: %w", resolution, indicator, err)
+	}
+	return points, nil
+}
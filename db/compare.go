@@ -0,0 +1,146 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/lib/pq"
+)
+
+// GetComparisonEntities returns the entities which can be compared, largest first
+func GetComparisonEntities() ([]string, error) {
+	entities := []string{}
+	err := ReaderDb.Select(&entities, `
+		SELECT pool FROM pool_luck_history
+		WHERE day = (SELECT MAX(day) FROM pool_luck_history) AND pool != 'Unknown'
+		ORDER BY validators DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving comparison entities: %w", err)
+	}
+	return entities, nil
+}
+
+// GetPerformanceComparison returns the daily income, effectiveness, proposal luck and uptime of the given validators
+// and entities between fromDay and toDay (both inclusive). Income is per validator so that entities of different
+// sizes can be compared with each other and with single validators.
+func GetPerformanceComparison(validatorIndices []uint64, entities []string, fromDay, toDay uint64) ([]*types.ApiComparisonResponse, error) {
+	rows := []struct {
+		Subject            string          `db:"subject"`
+		Day                uint64          `db:"day"`
+		Validators         uint64          `db:"validators"`
+		Income             float64         `db:"income"`
+		Effectiveness      sql.NullFloat64 `db:"effectiveness"`
+		Proposals          uint64          `db:"proposals"`
+		ExpectedProposals  float64         `db:"expected_proposals"`
+		MissedAttestations uint64          `db:"missed_attestations"`
+	}{}
+	err := ReaderDb.Select(&rows, `
+		WITH subjects AS (
+			SELECT 'validator:' || validatorindex AS subject, validatorindex
+			FROM validators
+			WHERE validatorindex = ANY($1)
+			UNION ALL
+			SELECT 'entity:' || p.pool AS subject, v.validatorindex
+			FROM validator_pool p
+			INNER JOIN validators v ON v.pubkey = p.publickey
+			WHERE p.pool = ANY($2)
+		)
+		SELECT
+			s.subject,
+			vs.day,
+			COUNT(*) AS validators,
+			COALESCE(SUM((COALESCE(vs.cl_rewards_gwei, 0)::NUMERIC * 1e9 + COALESCE(vs.el_rewards_wei, 0)) / 1e18), 0)::FLOAT AS income,
+			AVG(ve.score)::FLOAT AS effectiveness,
+			SUM(COALESCE(vs.proposed_blocks, 0) + COALESCE(vs.missed_blocks, 0) + COALESCE(vs.orphaned_blocks, 0)) AS proposals,
+			COALESCE(SUM(vs.expected_proposals), 0) AS expected_proposals,
+			COALESCE(SUM(vs.missed_attestations), 0) AS missed_attestations
+		FROM subjects s
+		INNER JOIN validator_stats vs ON vs.validatorindex = s.validatorindex AND vs.day >= $3 AND vs.day <= $4
+		LEFT JOIN validator_effectiveness ve ON ve.validatorindex = vs.validatorindex AND ve.day = vs.day AND ve.version = $5
+		GROUP BY s.subject, vs.day
+		ORDER BY s.subject, vs.day`, pq.Array(validatorIndices), pq.Array(entities), fromDay, toDay, utils.EffectivenessScoreVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving performance comparison: %w", err)
+	}
+
+	comparison := make(map[string]*types.ApiComparisonResponse, len(validatorIndices)+len(entities))
+	result := make([]*types.ApiComparisonResponse, 0, len(validatorIndices)+len(entities))
+	for _, index := range validatorIndices {
+		c := &types.ApiComparisonResponse{
+			Subject: fmt.Sprintf("validator:%d", index),
+			Type:    "validator",
+			Name:    strconv.FormatUint(index, 10),
+			History: []*types.ApiComparisonHistory{},
+		}
+		comparison[c.Subject] = c
+		result = append(result, c)
+	}
+	for _, entity := range entities {
+		c := &types.ApiComparisonResponse{
+			Subject: "entity:" + entity,
+			Type:    "entity",
+			Name:    entity,
+			History: []*types.ApiComparisonHistory{},
+		}
+		comparison[c.Subject] = c
+		result = append(result, c)
+	}
+
+	epochsPerDay := utils.EpochsPerDay()
+	effectivenessDays := make(map[string]uint64, len(result))
+	validatorDays := make(map[string]uint64, len(result))
+	missedAttestations := make(map[string]uint64, len(result))
+	for _, row := range rows {
+		c, ok := comparison[row.Subject]
+		if !ok || row.Validators == 0 {
+			continue
+		}
+		h := &types.ApiComparisonHistory{
+			Day:        row.Day,
+			DayStart:   utils.DayToTime(int64(row.Day)),
+			Validators: row.Validators,
+			Income:     row.Income / float64(row.Validators),
+			Uptime:     1 - float64(row.MissedAttestations)/float64(row.Validators*epochsPerDay),
+		}
+		if h.Uptime < 0 {
+			h.Uptime = 0
+		}
+		if row.Effectiveness.Valid {
+			effectiveness := row.Effectiveness.Float64
+			h.Effectiveness = &effectiveness
+			c.Effectiveness += row.Effectiveness.Float64
+			effectivenessDays[row.Subject]++
+		}
+
+		c.Validators = row.Validators
+		c.Income += h.Income
+		c.Proposals += row.Proposals
+		c.ExpectedProposals += row.ExpectedProposals
+		if c.ExpectedProposals > 0 {
+			h.ProposalLuck = float64(c.Proposals) / c.ExpectedProposals
+		}
+		validatorDays[row.Subject] += row.Validators
+		missedAttestations[row.Subject] += row.MissedAttestations
+		c.History = append(c.History, h)
+	}
+	for _, c := range result {
+		if days := effectivenessDays[c.Subject]; days > 0 {
+			c.Effectiveness /= float64(days)
+		}
+		if c.ExpectedProposals > 0 {
+			c.ProposalLuck = float64(c.Proposals) / c.ExpectedProposals
+		}
+		if validatorDays[c.Subject] > 0 {
+			c.Uptime = 1 - float64(missedAttestations[c.Subject])/float64(validatorDays[c.Subject]*epochsPerDay)
+			if c.Uptime < 0 {
+				c.Uptime = 0
+			}
+		}
+	}
+
+	return result, nil
+}
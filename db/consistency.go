@@ -0,0 +1,36 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// SaveConsistencyReport stores the report of a consistency audit run
+func SaveConsistencyReport(report *types.ConsistencyReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("error marshalling consistency report: %w", err)
+	}
+	_, err = WriterDb.Exec(`INSERT INTO consistency_audit_reports (ts, mismatches, report) VALUES ($1, $2, $3)`, report.Ts, len(report.Mismatches), data)
+	if err != nil {
+		return fmt.Errorf("error saving consistency report: %w", err)
+	}
+	return nil
+}
+
+// GetLatestConsistencyReport returns the report of the last consistency audit run
+func GetLatestConsistencyReport() (*types.ConsistencyReport, error) {
+	var data []byte
+	err := WriterDb.Get(&data, `SELECT report FROM consistency_audit_reports ORDER BY ts DESC LIMIT 1`)
+	if err != nil {
+		return nil, err
+	}
+	report := &types.ConsistencyReport{}
+	err = json.Unmarshal(data, report)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling consistency report: %w", err)
+	}
+	return report, nil
+}
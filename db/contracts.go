@@ -0,0 +1,73 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// SaveVerifiedContract stores the abi and source files of a verified contract, previously stored files of the contract are replaced
+func SaveVerifiedContract(contract *types.VerifiedContract) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in SaveVerifiedContract: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		insert into verified_contracts (address, name, compiler_version, match_type, abi, source, verified_ts)
+		values ($1, $2, $3, $4, $5, $6, to_timestamp($7) at time zone 'utc')
+		on conflict (address) do update set
+			name             = excluded.name,
+			compiler_version = excluded.compiler_version,
+			match_type       = excluded.match_type,
+			abi              = excluded.abi,
+			source           = excluded.source,
+			verified_ts      = excluded.verified_ts`,
+		contract.Address, contract.Name, contract.CompilerVersion, contract.MatchType, string(contract.ABIJson), contract.Source, contract.VerifiedTs.Unix())
+	if err != nil {
+		return fmt.Errorf("error inserting verified contract %#x: %w", contract.Address, err)
+	}
+
+	_, err = tx.Exec(`delete from verified_contracts_files where address = $1`, contract.Address)
+	if err != nil {
+		return fmt.Errorf("error deleting files of verified contract %#x: %w", contract.Address, err)
+	}
+
+	for _, file := range contract.Files {
+		_, err = tx.Exec(`insert into verified_contracts_files (address, path, content) values ($1, $2, $3)`, contract.Address, file.Path, file.Content)
+		if err != nil {
+			return fmt.Errorf("error inserting file %v of verified contract %#x: %w", file.Path, contract.Address, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetVerifiedContract returns the verified contract including its source files, nil is returned if the contract has not been verified
+func GetVerifiedContract(address []byte) (*types.VerifiedContract, error) {
+	contract := &types.VerifiedContract{}
+	err := ReaderDb.Get(contract, `
+		select address, name, compiler_version, match_type, abi, source, verified_ts
+		from verified_contracts
+		where address = $1`, address)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting verified contract %#x: %w", address, err)
+	}
+
+	err = ReaderDb.Select(&contract.Files, `
+		select path, content
+		from verified_contracts_files
+		where address = $1
+		order by path`, address)
+	if err != nil {
+		return nil, fmt.Errorf("error getting files of verified contract %#x: %w", address, err)
+	}
+
+	return contract, nil
+}
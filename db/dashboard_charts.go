@@ -0,0 +1,183 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/lib/pq"
+)
+
+// validatorDashboardChartMetrics maps the metrics of custom dashboard charts to their value per validator and day,
+// all amounts are in ETH. The expressions refer to validator_stats (vs) and validator_effectiveness (ve).
+var validatorDashboardChartMetrics = map[string]string{
+	"income":              "(vs.cl_rewards_gwei::NUMERIC * 1e9 + COALESCE(vs.el_rewards_wei, 0)) / 1e18",
+	"cl_income":           "vs.cl_rewards_gwei::NUMERIC / 1e9",
+	"el_income":           "COALESCE(vs.el_rewards_wei, 0) / 1e18",
+	"balance":             "vs.end_balance::NUMERIC / 1e9",
+	"effective_balance":   "vs.end_effective_balance::NUMERIC / 1e9",
+	"missed_attestations": "vs.missed_attestations",
+	"proposed_blocks":     "vs.proposed_blocks",
+	"effectiveness":       "ve.score",
+}
+
+// validatorDashboardChartAggregations maps the aggregations of custom dashboard charts to the sql aggregate functions
+var validatorDashboardChartAggregations = map[string]string{
+	"sum": "SUM",
+	"avg": "AVG",
+	"min": "MIN",
+	"max": "MAX",
+}
+
+// IsValidatorDashboardChartMetric returns whether custom dashboard charts support the metric
+func IsValidatorDashboardChartMetric(metric string) bool {
+	_, ok := validatorDashboardChartMetrics[metric]
+	return ok
+}
+
+// IsValidatorDashboardChartAggregation returns whether custom dashboard charts support the aggregation
+func IsValidatorDashboardChartAggregation(aggregation string) bool {
+	_, ok := validatorDashboardChartAggregations[aggregation]
+	return ok
+}
+
+// GetValidatorDashboardCharts returns the custom charts of a validator dashboard, oldest first
+func GetValidatorDashboardCharts(dashboardId uint64) ([]*types.UserValidatorDashboardChart, error) {
+	rows := []struct {
+		types.UserValidatorDashboardChart
+		GroupIds pq.Int64Array `db:"group_ids"`
+	}{}
+	err := ReaderDb.Select(&rows, `
+		SELECT id, name, metric, aggregation, group_ids, per_group, days, created_at
+		FROM users_val_dashboards_charts
+		WHERE dashboard_id = $1
+		ORDER BY id`, dashboardId)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving charts of validator dashboard %v: %w", dashboardId, err)
+	}
+
+	charts := make([]*types.UserValidatorDashboardChart, 0, len(rows))
+	for _, row := range rows {
+		chart := row.UserValidatorDashboardChart
+		chart.GroupIds = make([]uint64, len(row.GroupIds))
+		for i, id := range row.GroupIds {
+			chart.GroupIds[i] = uint64(id)
+		}
+		charts = append(charts, &chart)
+	}
+	return charts, nil
+}
+
+// GetValidatorDashboardChart returns a custom chart of a validator dashboard, nil if the chart does not exist
+func GetValidatorDashboardChart(dashboardId, chartId uint64) (*types.UserValidatorDashboardChart, error) {
+	charts, err := GetValidatorDashboardCharts(dashboardId)
+	if err != nil {
+		return nil, err
+	}
+	for _, chart := range charts {
+		if chart.Id == chartId {
+			return chart, nil
+		}
+	}
+	return nil, nil
+}
+
+// CountValidatorDashboardCharts returns the number of custom charts of a validator dashboard
+func CountValidatorDashboardCharts(dashboardId uint64) (uint64, error) {
+	var count uint64
+	err := ReaderDb.Get(&count, `SELECT COUNT(*) FROM users_val_dashboards_charts WHERE dashboard_id = $1`, dashboardId)
+	if err != nil {
+		return 0, fmt.Errorf("error counting charts of validator dashboard %v: %w", dashboardId, err)
+	}
+	return count, nil
+}
+
+// CreateValidatorDashboardChart saves a custom chart of a validator dashboard and returns its id
+func CreateValidatorDashboardChart(dashboardId uint64, chart *types.UserValidatorDashboardChart) (uint64, error) {
+	var chartId uint64
+	err := WriterDb.Get(&chartId, `
+		INSERT INTO users_val_dashboards_charts (dashboard_id, name, metric, aggregation, group_ids, per_group, days)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`, dashboardId, chart.Name, chart.Metric, chart.Aggregation, pq.Array(chart.GroupIds), chart.PerGroup, chart.Days)
+	if err != nil {
+		return 0, fmt.Errorf("error inserting chart of validator dashboard %v: %w", dashboardId, err)
+	}
+	return chartId, nil
+}
+
+// UpdateValidatorDashboardChart replaces the definition of a custom chart of a validator dashboard, returns false if the
+// chart does not exist
+func UpdateValidatorDashboardChart(dashboardId, chartId uint64, chart *types.UserValidatorDashboardChart) (bool, error) {
+	res, err := WriterDb.Exec(`
+		UPDATE users_val_dashboards_charts SET name = $3, metric = $4, aggregation = $5, group_ids = $6, per_group = $7, days = $8
+		WHERE id = $1 AND dashboard_id = $2`, chartId, dashboardId, chart.Name, chart.Metric, chart.Aggregation, pq.Array(chart.GroupIds), chart.PerGroup, chart.Days)
+	if err != nil {
+		return false, fmt.Errorf("error updating chart %v of validator dashboard %v: %w", chartId, dashboardId, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// DeleteValidatorDashboardChart deletes a custom chart of a validator dashboard, returns false if the chart does not exist
+func DeleteValidatorDashboardChart(dashboardId, chartId uint64) (bool, error) {
+	res, err := WriterDb.Exec(`DELETE FROM users_val_dashboards_charts WHERE id = $1 AND dashboard_id = $2`, chartId, dashboardId)
+	if err != nil {
+		return false, fmt.Errorf("error deleting chart %v of validator dashboard %v: %w", chartId, dashboardId, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetValidatorDashboardChartPoints aggregates the metric of a custom chart over the validators of the selected groups
+// (all groups if none are selected) per day within [fromDay, toDay]. The points are returned per group id if the chart
+// shows one series per group, otherwise all points are returned under the group id -1.
+func GetValidatorDashboardChartPoints(dashboardId uint64, chart *types.UserValidatorDashboardChart, fromDay, toDay uint64) (map[int64][]*types.ChartSeriesPoint, error) {
+	metric, ok := validatorDashboardChartMetrics[chart.Metric]
+	if !ok {
+		return nil, fmt.Errorf("unsupported chart metric %v", chart.Metric)
+	}
+	aggregation, ok := validatorDashboardChartAggregations[chart.Aggregation]
+	if !ok {
+		return nil, fmt.Errorf("unsupported chart aggregation %v", chart.Aggregation)
+	}
+
+	rows := []struct {
+		GroupId int64           `db:"group_id"`
+		Day     uint64          `db:"day"`
+		Value   sql.NullFloat64 `db:"value"`
+	}{}
+	err := ReaderDb.Select(&rows, fmt.Sprintf(`
+		SELECT
+			CASE WHEN $5 THEN dv.group_id ELSE -1 END AS group_id,
+			vs.day,
+			%s(%s)::FLOAT AS value
+		FROM users_val_dashboards_validators dv
+		INNER JOIN validator_stats vs ON vs.validatorindex = dv.validator_index AND vs.day >= $2 AND vs.day <= $3
+		LEFT JOIN validator_effectiveness ve ON ve.validatorindex = vs.validatorindex AND ve.day = vs.day AND ve.version = $6
+		WHERE dv.dashboard_id = $1 AND (CARDINALITY($4::SMALLINT[]) = 0 OR dv.group_id = ANY($4))
+		GROUP BY 1, vs.day
+		ORDER BY 1, vs.day`, aggregation, metric), dashboardId, fromDay, toDay, pq.Array(chart.GroupIds), chart.PerGroup, utils.EffectivenessScoreVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving points of chart %v of validator dashboard %v: %w", chart.Id, dashboardId, err)
+	}
+
+	points := map[int64][]*types.ChartSeriesPoint{}
+	for _, row := range rows {
+		if !row.Value.Valid {
+			continue
+		}
+		points[row.GroupId] = append(points[row.GroupId], &types.ChartSeriesPoint{
+			Time:  utils.DayToTime(int64(row.Day)),
+			Value: row.Value.Float64,
+		})
+	}
+	return points, nil
+}
@@ -0,0 +1,657 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// GetUserValidatorDashboards returns the validator dashboards of a user on the current network, oldest first
+func GetUserValidatorDashboards(userId uint64) ([]*types.UserValidatorDashboard, error) {
+	dashboards := []*types.UserValidatorDashboard{}
+	err := ReaderDb.Select(&dashboards, `
+		SELECT id, name, created_at, organization_id
+		FROM users_val_dashboards
+		WHERE user_id = $1 AND network = $2
+		ORDER BY id`, userId, utils.Config.Chain.ClConfig.DepositChainID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validator dashboards of user %v: %w", userId, err)
+	}
+
+	err = loadValidatorDashboardsDetails(dashboards, true)
+	if err != nil {
+		return nil, err
+	}
+	return dashboards, nil
+}
+
+// GetUserValidatorDashboard returns a validator dashboard of a user, nil if the dashboard does not exist or belongs to another user
+func GetUserValidatorDashboard(userId, dashboardId uint64) (*types.UserValidatorDashboard, error) {
+	dashboard := &types.UserValidatorDashboard{}
+	err := ReaderDb.Get(dashboard, `
+		SELECT id, name, created_at, organization_id
+		FROM users_val_dashboards
+		WHERE id = $1 AND user_id = $2 AND network = $3`, dashboardId, userId, utils.Config.Chain.ClConfig.DepositChainID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validator dashboard %v: %w", dashboardId, err)
+	}
+
+	err = loadValidatorDashboardsDetails([]*types.UserValidatorDashboard{dashboard}, true)
+	if err != nil {
+		return nil, err
+	}
+	return dashboard, nil
+}
+
+// GetOrganizationValidatorDashboards returns the validator dashboards shared with an organization on the current
+// network except the ones of the given user, oldest first
+func GetOrganizationValidatorDashboards(organizationId, exceptUserId uint64) ([]*types.UserValidatorDashboard, error) {
+	dashboards := []*types.UserValidatorDashboard{}
+	err := ReaderDb.Select(&dashboards, `
+		SELECT id, name, created_at, organization_id
+		FROM users_val_dashboards
+		WHERE organization_id = $1 AND user_id != $2 AND network = $3
+		ORDER BY id`, organizationId, exceptUserId, utils.Config.Chain.ClConfig.DepositChainID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validator dashboards of organization %v: %w", organizationId, err)
+	}
+
+	err = loadValidatorDashboardsDetails(dashboards, true)
+	if err != nil {
+		return nil, err
+	}
+	return dashboards, nil
+}
+
+// GetValidatorDashboardOwner returns the user owning a validator dashboard on the current network and the organization
+// it is shared with, both are 0 if the dashboard does not exist
+func GetValidatorDashboardOwner(dashboardId uint64) (uint64, uint64, error) {
+	owner := struct {
+		UserId         uint64        `db:"user_id"`
+		OrganizationId sql.NullInt64 `db:"organization_id"`
+	}{}
+	err := ReaderDb.Get(&owner, `
+		SELECT user_id, organization_id
+		FROM users_val_dashboards
+		WHERE id = $1 AND network = $2`, dashboardId, utils.Config.Chain.ClConfig.DepositChainID)
+	if err == sql.ErrNoRows {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("error retrieving owner of validator dashboard %v: %w", dashboardId, err)
+	}
+	return owner.UserId, uint64(owner.OrganizationId.Int64), nil
+}
+
+// SetUserValidatorDashboardOrganization shares a validator dashboard of a user with an organization, a nil organization
+// unshares it. Returns false if the dashboard does not exist or belongs to another user.
+func SetUserValidatorDashboardOrganization(userId, dashboardId uint64, organizationId *uint64) (bool, error) {
+	res, err := WriterDb.Exec(`
+		UPDATE users_val_dashboards SET organization_id = $4
+		WHERE id = $1 AND user_id = $2 AND network = $3`, dashboardId, userId, utils.Config.Chain.ClConfig.DepositChainID, organizationId)
+	if err != nil {
+		return false, fmt.Errorf("error updating organization of validator dashboard %v: %w", dashboardId, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetSharedValidatorDashboard returns the validator dashboard shared under the public id, nil if there is no such share.
+// The shares of the dashboard are not returned.
+func GetSharedValidatorDashboard(publicId string) (*types.UserValidatorDashboard, error) {
+	dashboard := &types.UserValidatorDashboard{}
+	err := ReaderDb.Get(dashboard, `
+		SELECT d.id, s.name, d.created_at
+		FROM users_val_dashboards_sharing s
+		INNER JOIN users_val_dashboards d ON d.id = s.dashboard_id
+		WHERE s.public_id = $1 AND d.network = $2`, publicId, utils.Config.Chain.ClConfig.DepositChainID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving shared validator dashboard %v: %w", publicId, err)
+	}
+
+	err = loadValidatorDashboardsDetails([]*types.UserValidatorDashboard{dashboard}, false)
+	if err != nil {
+		return nil, err
+	}
+	return dashboard, nil
+}
+
+func loadValidatorDashboardsDetails(dashboards []*types.UserValidatorDashboard, withShares bool) error {
+	if len(dashboards) == 0 {
+		return nil
+	}
+	dashboardsById := make(map[uint64]*types.UserValidatorDashboard, len(dashboards))
+	ids := make([]uint64, 0, len(dashboards))
+	for _, d := range dashboards {
+		d.Groups = []*types.UserValidatorDashboardGroup{}
+		dashboardsById[d.Id] = d
+		ids = append(ids, d.Id)
+	}
+
+	groups := []struct {
+		DashboardId uint64 `db:"dashboard_id"`
+		types.UserValidatorDashboardGroup
+		Validators pq.Int64Array `db:"validators"`
+	}{}
+	err := ReaderDb.Select(&groups, `
+		SELECT g.dashboard_id, g.id, g.name, COALESCE(ARRAY_AGG(v.validator_index ORDER BY v.validator_index) FILTER (WHERE v.validator_index IS NOT NULL), '{}') AS validators
+		FROM users_val_dashboards_groups g
+		LEFT JOIN users_val_dashboards_validators v ON v.dashboard_id = g.dashboard_id AND v.group_id = g.id
+		WHERE g.dashboard_id = ANY($1)
+		GROUP BY g.dashboard_id, g.id, g.name
+		ORDER BY g.dashboard_id, g.id`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("error retrieving validator dashboard groups: %w", err)
+	}
+	for _, g := range groups {
+		group := g.UserValidatorDashboardGroup
+		group.Validators = make([]uint64, len(g.Validators))
+		for i, v := range g.Validators {
+			group.Validators[i] = uint64(v)
+		}
+		dashboardsById[g.DashboardId].Groups = append(dashboardsById[g.DashboardId].Groups, &group)
+	}
+
+	if !withShares {
+		return nil
+	}
+	shares := []struct {
+		DashboardId uint64 `db:"dashboard_id"`
+		types.UserValidatorDashboardShare
+	}{}
+	err = ReaderDb.Select(&shares, `
+		SELECT dashboard_id, public_id, name
+		FROM users_val_dashboards_sharing
+		WHERE dashboard_id = ANY($1)
+		ORDER BY public_id`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("error retrieving validator dashboard shares: %w", err)
+	}
+	for _, s := range shares {
+		share := s.UserValidatorDashboardShare
+		dashboardsById[s.DashboardId].Shares = append(dashboardsById[s.DashboardId].Shares, &share)
+	}
+	return nil
+}
+
+// CountUserValidatorDashboards returns the number of validator dashboards of a user on the current network
+func CountUserValidatorDashboards(userId uint64) (uint64, error) {
+	var count uint64
+	err := ReaderDb.Get(&count, `SELECT COUNT(*) FROM users_val_dashboards WHERE user_id = $1 AND network = $2`, userId, utils.Config.Chain.ClConfig.DepositChainID)
+	if err != nil {
+		return 0, fmt.Errorf("error counting validator dashboards of user %v: %w", userId, err)
+	}
+	return count, nil
+}
+
+// CreateUserValidatorDashboard creates a validator dashboard for a user and returns its id, the ids of the groups are
+// their position in the given groups
+func CreateUserValidatorDashboard(userId uint64, name string, groups []*types.UserValidatorDashboardGroup) (uint64, error) {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("error starting db tx in CreateUserValidatorDashboard: %w", err)
+	}
+	defer tx.Rollback()
+
+	var dashboardId uint64
+	err = tx.Get(&dashboardId, `
+		INSERT INTO users_val_dashboards (user_id, network, name)
+		VALUES ($1, $2, $3)
+		RETURNING id`, userId, utils.Config.Chain.ClConfig.DepositChainID, name)
+	if err != nil {
+		return 0, fmt.Errorf("error inserting validator dashboard: %w", err)
+	}
+
+	err = saveValidatorDashboardGroups(tx, dashboardId, groups)
+	if err != nil {
+		return 0, err
+	}
+	return dashboardId, tx.Commit()
+}
+
+// UpdateUserValidatorDashboard renames a validator dashboard of a user and replaces its groups, returns false if the
+// dashboard does not exist or belongs to another user
+func UpdateUserValidatorDashboard(userId, dashboardId uint64, name string, groups []*types.UserValidatorDashboardGroup) (bool, error) {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return false, fmt.Errorf("error starting db tx in UpdateUserValidatorDashboard: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		UPDATE users_val_dashboards SET name = $4
+		WHERE id = $1 AND user_id = $2 AND network = $3`, dashboardId, userId, utils.Config.Chain.ClConfig.DepositChainID, name)
+	if err != nil {
+		return false, fmt.Errorf("error updating validator dashboard %v: %w", dashboardId, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	_, err = tx.Exec(`DELETE FROM users_val_dashboards_groups WHERE dashboard_id = $1`, dashboardId)
+	if err != nil {
+		return false, fmt.Errorf("error deleting groups of validator dashboard %v: %w", dashboardId, err)
+	}
+	err = saveValidatorDashboardGroups(tx, dashboardId, groups)
+	if err != nil {
+		return false, err
+	}
+	// the validators might have changed, the rollup is rebuilt on the next request
+	_, err = tx.Exec(`DELETE FROM users_val_dashboards_stats WHERE dashboard_id = $1`, dashboardId)
+	if err != nil {
+		return false, fmt.Errorf("error deleting stats of validator dashboard %v: %w", dashboardId, err)
+	}
+	return true, tx.Commit()
+}
+
+// AddValidatorsToUserValidatorDashboard adds validators to a group of a validator dashboard of a user, validators that
+// are already part of the dashboard keep their group. Returns false if the dashboard or group does not exist or the
+// dashboard belongs to another user.
+func AddValidatorsToUserValidatorDashboard(userId, dashboardId, groupId uint64, validators []uint64) (bool, error) {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return false, fmt.Errorf("error starting db tx in AddValidatorsToUserValidatorDashboard: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.Get(&exists, `
+		SELECT EXISTS (
+			SELECT 1 FROM users_val_dashboards d
+			INNER JOIN users_val_dashboards_groups g ON g.dashboard_id = d.id
+			WHERE d.id = $1 AND d.user_id = $2 AND d.network = $3 AND g.id = $4
+		)`, dashboardId, userId, utils.Config.Chain.ClConfig.DepositChainID, groupId)
+	if err != nil {
+		return false, fmt.Errorf("error checking group %v of validator dashboard %v: %w", groupId, dashboardId, err)
+	}
+	if !exists {
+		return false, nil
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO users_val_dashboards_validators (dashboard_id, group_id, validator_index)
+		SELECT $1, $2, UNNEST($3::BIGINT[])
+		ON CONFLICT (dashboard_id, validator_index) DO NOTHING`, dashboardId, groupId, pq.Array(validators))
+	if err != nil {
+		return false, fmt.Errorf("error adding validators to group %v of validator dashboard %v: %w", groupId, dashboardId, err)
+	}
+	_, err = tx.Exec(`DELETE FROM users_val_dashboards_stats WHERE dashboard_id = $1`, dashboardId)
+	if err != nil {
+		return false, fmt.Errorf("error deleting stats of validator dashboard %v: %w", dashboardId, err)
+	}
+	return true, tx.Commit()
+}
+
+func saveValidatorDashboardGroups(tx *sqlx.Tx, dashboardId uint64, groups []*types.UserValidatorDashboardGroup) error {
+	for i, group := range groups {
+		_, err := tx.Exec(`INSERT INTO users_val_dashboards_groups (id, dashboard_id, name) VALUES ($1, $2, $3)`, i, dashboardId, group.Name)
+		if err != nil {
+			return fmt.Errorf("error inserting group %v of validator dashboard %v: %w", i, dashboardId, err)
+		}
+		if len(group.Validators) == 0 {
+			continue
+		}
+		// a validator can only be part of one group of a dashboard, the first group it is listed in wins
+		_, err = tx.Exec(`
+			INSERT INTO users_val_dashboards_validators (dashboard_id, group_id, validator_index)
+			SELECT $1, $2, UNNEST($3::BIGINT[])
+			ON CONFLICT (dashboard_id, validator_index) DO NOTHING`, dashboardId, i, pq.Array(group.Validators))
+		if err != nil {
+			return fmt.Errorf("error inserting validators of group %v of validator dashboard %v: %w", i, dashboardId, err)
+		}
+	}
+	return nil
+}
+
+// DeleteUserValidatorDashboard deletes a validator dashboard of a user together with its groups and shares, returns false
+// if the dashboard does not exist or belongs to another user
+func DeleteUserValidatorDashboard(userId, dashboardId uint64) (bool, error) {
+	res, err := WriterDb.Exec(`
+		DELETE FROM users_val_dashboards
+		WHERE id = $1 AND user_id = $2 AND network = $3`, dashboardId, userId, utils.Config.Chain.ClConfig.DepositChainID)
+	if err != nil {
+		return false, fmt.Errorf("error deleting validator dashboard %v: %w", dashboardId, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// ShareUserValidatorDashboard creates a read-only public link to a validator dashboard of a user and returns its public
+// id, returns an empty string if the dashboard does not exist or belongs to another user
+func ShareUserValidatorDashboard(userId, dashboardId uint64, name string) (string, error) {
+	var publicId string
+	err := WriterDb.Get(&publicId, `
+		INSERT INTO users_val_dashboards_sharing (dashboard_id, name, shared_groups)
+		SELECT id, $4, true
+		FROM users_val_dashboards
+		WHERE id = $1 AND user_id = $2 AND network = $3
+		RETURNING public_id`, dashboardId, userId, utils.Config.Chain.ClConfig.DepositChainID, name)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error sharing validator dashboard %v: %w", dashboardId, err)
+	}
+	return publicId, nil
+}
+
+// DeleteUserValidatorDashboardShare revokes a public link to a validator dashboard of a user, returns false if the share
+// does not exist or the dashboard belongs to another user
+func DeleteUserValidatorDashboardShare(userId, dashboardId uint64, publicId string) (bool, error) {
+	res, err := WriterDb.Exec(`
+		DELETE FROM users_val_dashboards_sharing s
+		USING users_val_dashboards d
+		WHERE s.public_id = $1 AND s.dashboard_id = $2 AND d.id = s.dashboard_id AND d.user_id = $3`, publicId, dashboardId, userId)
+	if err != nil {
+		return false, fmt.Errorf("error deleting share %v of validator dashboard %v: %w", publicId, dashboardId, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetValidatorDashboardGroupStats returns the summed duties and income of the validators of every group of a dashboard
+// over the last exported days together with the current state of the validators, ordered by group id
+func GetValidatorDashboardGroupStats(dashboardId uint64, days uint64, epoch uint64) ([]*types.UserValidatorDashboardGroupStats, error) {
+	lastDay, err := GetLastExportedStatisticDay()
+	if err != nil && err != ErrNoStats {
+		return nil, err
+	}
+
+	groups := []*types.UserValidatorDashboardGroupStats{}
+	err = ReaderDb.Select(&groups, `
+		WITH stats AS (
+			SELECT
+				dv.group_id,
+				COUNT(vs.day) FILTER (WHERE vs.end_effective_balance > 0) AS active_days,
+				COALESCE(SUM(vs.missed_attestations), 0) AS missed_attestations,
+				COALESCE(SUM(vs.proposed_blocks), 0) AS proposed_blocks,
+				COALESCE(SUM(vs.missed_blocks), 0) + COALESCE(SUM(vs.orphaned_blocks), 0) AS missed_blocks,
+				COALESCE(SUM(vs.participated_sync), 0) AS participated_sync,
+				COALESCE(SUM(vs.missed_sync), 0) + COALESCE(SUM(vs.orphaned_sync), 0) AS missed_sync,
+				COALESCE(SUM(vs.cl_rewards_gwei), 0) AS cl_rewards_gwei,
+				COALESCE(SUM(vs.el_rewards_wei), 0)::FLOAT AS el_rewards_wei
+			FROM users_val_dashboards_validators dv
+			INNER JOIN validator_stats vs ON vs.validatorindex = dv.validator_index AND vs.day > $2 AND vs.day <= $3
+			WHERE dv.dashboard_id = $1
+			GROUP BY dv.group_id
+		), state AS (
+			SELECT
+				dv.group_id,
+				COUNT(*) AS validators,
+				COUNT(*) FILTER (WHERE v.activationepoch <= $4 AND v.exitepoch > $4) AS active_validators,
+				COUNT(*) FILTER (WHERE v.slashed) AS slashed_validators
+			FROM users_val_dashboards_validators dv
+			LEFT JOIN validators v ON v.validatorindex = dv.validator_index
+			WHERE dv.dashboard_id = $1
+			GROUP BY dv.group_id
+		)
+		SELECT
+			g.id,
+			g.name,
+			COALESCE(state.validators, 0) AS validators,
+			COALESCE(state.active_validators, 0) AS active_validators,
+			COALESCE(state.slashed_validators, 0) AS slashed_validators,
+			COALESCE(stats.active_days, 0) AS active_days,
+			COALESCE(stats.missed_attestations, 0) AS missed_attestations,
+			COALESCE(stats.proposed_blocks, 0) AS proposed_blocks,
+			COALESCE(stats.missed_blocks, 0) AS missed_blocks,
+			COALESCE(stats.participated_sync, 0) AS participated_sync,
+			COALESCE(stats.missed_sync, 0) AS missed_sync,
+			COALESCE(stats.cl_rewards_gwei, 0) AS cl_rewards_gwei,
+			COALESCE(stats.el_rewards_wei, 0) AS el_rewards_wei
+		FROM users_val_dashboards_groups g
+		LEFT JOIN stats ON stats.group_id = g.id
+		LEFT JOIN state ON state.group_id = g.id
+		WHERE g.dashboard_id = $1
+		ORDER BY g.id`, dashboardId, int64(lastDay)-int64(days), lastDay, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving stats of the groups of validator dashboard %v: %w", dashboardId, err)
+	}
+	return groups, nil
+}
+
+// validatorDashboardDayStatsColumns aggregates the validator_stats (vs) of a set of validators per day, the columns match
+// types.UserValidatorDashboardDayStats
+const validatorDashboardDayStatsColumns = `
+			vs.day,
+			COUNT(*) AS validators,
+			COALESCE(SUM(vs.start_balance), 0) AS start_balance,
+			COALESCE(SUM(vs.end_balance), 0) AS end_balance,
+			COALESCE(SUM(vs.end_effective_balance), 0) AS end_effective_balance,
+			COALESCE(SUM(vs.deposits_amount), 0) AS deposits_amount,
+			COALESCE(SUM(vs.withdrawals_amount), 0) AS withdrawals_amount,
+			COALESCE(SUM(vs.cl_rewards_gwei), 0) AS cl_rewards_gwei,
+			COALESCE(SUM(vs.el_rewards_wei), 0)::FLOAT AS el_rewards_wei,
+			COUNT(*) FILTER (WHERE vs.end_effective_balance > 0) AS active_days,
+			COALESCE(SUM(vs.missed_attestations), 0) AS missed_attestations,
+			COALESCE(SUM(vs.proposed_blocks), 0) AS proposed_blocks,
+			COALESCE(SUM(vs.missed_blocks), 0) + COALESCE(SUM(vs.orphaned_blocks), 0) AS missed_blocks,
+			COALESCE(SUM(vs.participated_sync), 0) AS participated_sync,
+			COALESCE(SUM(vs.missed_sync), 0) + COALESCE(SUM(vs.orphaned_sync), 0) AS missed_sync`
+
+// UpdateValidatorDashboardStats rolls up the validator stats of the days which have been exported since the last update
+// of the dashboard into one row per day, so large dashboards do not have to be aggregated over all validators on every request
+func UpdateValidatorDashboardStats(dashboardId uint64) error {
+	lastDay, err := GetLastExportedStatisticDay()
+	if err == ErrNoStats {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = WriterDb.Exec(`
+		INSERT INTO users_val_dashboards_stats (
+			dashboard_id,
+			day,
+			validators,
+			start_balance,
+			end_balance,
+			end_effective_balance,
+			deposits_amount,
+			withdrawals_amount,
+			cl_rewards_gwei,
+			el_rewards_wei,
+			active_days,
+			missed_attestations,
+			proposed_blocks,
+			missed_blocks,
+			participated_sync,
+			missed_sync
+		)
+		SELECT
+			dv.dashboard_id,
+			`+validatorDashboardDayStatsColumns+`
+		FROM users_val_dashboards_validators dv
+		INNER JOIN validator_stats vs ON vs.validatorindex = dv.validator_index
+		WHERE dv.dashboard_id = $1
+			AND vs.day > COALESCE((SELECT MAX(day) FROM users_val_dashboards_stats WHERE dashboard_id = $1), -1)
+			AND vs.day <= $2
+		GROUP BY dv.dashboard_id, vs.day
+		ON CONFLICT (dashboard_id, day) DO NOTHING`, dashboardId, lastDay)
+	if err != nil {
+		return fmt.Errorf("error updating stats of validator dashboard %v: %w", dashboardId, err)
+	}
+	return nil
+}
+
+// GetValidatorDashboardStats returns the daily rollup of the validator stats of a dashboard, see UpdateValidatorDashboardStats
+func GetValidatorDashboardStats(dashboardId uint64) ([]*types.UserValidatorDashboardDayStats, error) {
+	stats := []*types.UserValidatorDashboardDayStats{}
+	err := ReaderDb.Select(&stats, `
+		SELECT
+			day,
+			validators,
+			start_balance,
+			end_balance,
+			end_effective_balance,
+			deposits_amount,
+			withdrawals_amount,
+			cl_rewards_gwei,
+			el_rewards_wei::FLOAT AS el_rewards_wei,
+			active_days,
+			missed_attestations,
+			proposed_blocks,
+			missed_blocks,
+			participated_sync,
+			missed_sync
+		FROM users_val_dashboards_stats
+		WHERE dashboard_id = $1
+		ORDER BY day`, dashboardId)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving stats of validator dashboard %v: %w", dashboardId, err)
+	}
+	return stats, nil
+}
+
+// GetValidatorDashboardSummary returns the number of validators of a dashboard by their state at the given epoch
+func GetValidatorDashboardSummary(dashboardId uint64, epoch uint64) (*types.UserValidatorDashboardSummary, error) {
+	summary := &types.UserValidatorDashboardSummary{}
+	err := ReaderDb.Get(summary, `
+		SELECT
+			COUNT(*) AS validators,
+			COUNT(*) FILTER (WHERE v.activationepoch <= $2 AND v.exitepoch > $2) AS active_validators,
+			COUNT(*) FILTER (WHERE v.validatorindex IS NULL OR v.activationepoch > $2) AS pending_validators,
+			COUNT(*) FILTER (WHERE v.exitepoch <= $2) AS exited_validators,
+			COUNT(*) FILTER (WHERE v.slashed) AS slashed_validators
+		FROM users_val_dashboards_validators dv
+		LEFT JOIN validators v ON v.validatorindex = dv.validator_index
+		WHERE dv.dashboard_id = $1`, dashboardId, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving summary of validator dashboard %v: %w", dashboardId, err)
+	}
+	return summary, nil
+}
+
+// GetValidatorsDayStats aggregates the validator stats of the given validators per day, starting at the given day
+func GetValidatorsDayStats(validatorIndices []uint64, lowerBoundDay uint64) ([]*types.UserValidatorDashboardDayStats, error) {
+	stats := []*types.UserValidatorDashboardDayStats{}
+	if len(validatorIndices) == 0 {
+		return stats, nil
+	}
+	err := ReaderDb.Select(&stats, `
+		SELECT `+validatorDashboardDayStatsColumns+`
+		FROM validator_stats vs
+		WHERE vs.validatorindex = ANY($1) AND vs.day >= $2
+		GROUP BY vs.day
+		ORDER BY vs.day`, pq.Array(validatorIndices), lowerBoundDay)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving daily stats of validators: %w", err)
+	}
+	return stats, nil
+}
+
+// withdrawalAddressCredentials returns the execution (0x01) and compounding (0x02) withdrawal credentials of an address
+func withdrawalAddressCredentials(address []byte) ([][]byte, error) {
+	credentials, err := utils.AddressToWithdrawalCredentials(address)
+	if err != nil {
+		return nil, err
+	}
+	compounding := make([]byte, len(credentials))
+	copy(compounding, credentials)
+	compounding[0] = 0x02
+	return [][]byte{credentials, compounding}, nil
+}
+
+// GetValidatorIndicesByWithdrawalAddress returns the indices of all validators withdrawing to the given address
+func GetValidatorIndicesByWithdrawalAddress(address []byte) ([]uint64, error) {
+	credentials, err := withdrawalAddressCredentials(address)
+	if err != nil {
+		return nil, err
+	}
+	indices := []uint64{}
+	err = ReaderDb.Select(&indices, `
+		SELECT validatorindex FROM validators WHERE withdrawalcredentials = ANY($1) ORDER BY validatorindex`, pq.ByteaArray(credentials))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validators of withdrawal address %x: %w", address, err)
+	}
+	return indices, nil
+}
+
+// GetWithdrawalAddressDashboardSummary returns the number of validators withdrawing to the given address by their state at
+// the given epoch
+func GetWithdrawalAddressDashboardSummary(address []byte, epoch uint64) (*types.UserValidatorDashboardSummary, error) {
+	credentials, err := withdrawalAddressCredentials(address)
+	if err != nil {
+		return nil, err
+	}
+	summary := &types.UserValidatorDashboardSummary{}
+	err = ReaderDb.Get(summary, `
+		SELECT
+			COUNT(*) AS validators,
+			COUNT(*) FILTER (WHERE activationepoch <= $2 AND exitepoch > $2) AS active_validators,
+			COUNT(*) FILTER (WHERE activationepoch > $2) AS pending_validators,
+			COUNT(*) FILTER (WHERE exitepoch <= $2) AS exited_validators,
+			COUNT(*) FILTER (WHERE slashed) AS slashed_validators
+		FROM validators
+		WHERE withdrawalcredentials = ANY($1)`, pq.ByteaArray(credentials), epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving summary of withdrawal address %x: %w", address, err)
+	}
+	return summary, nil
+}
+
+// GetWithdrawalAddressDashboardStats aggregates the validator stats of all validators withdrawing to the given address per
+// day. The result only changes once a day, it is cached until the next day has been exported.
+func GetWithdrawalAddressDashboardStats(address []byte) ([]*types.UserValidatorDashboardDayStats, error) {
+	stats := []*types.UserValidatorDashboardDayStats{}
+	lastDay, err := GetLastExportedStatisticDay()
+	if err == ErrNoStats {
+		return stats, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := withdrawalAddressCredentials(address)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := fmt.Sprintf("%d:withdrawalAddressDashboardStats:%x:%d", utils.Config.Chain.ClConfig.DepositChainID, address, lastDay)
+	if _, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, time.Hour, &stats); err == nil {
+		return stats, nil
+	}
+
+	err = ReaderDb.Select(&stats, `
+		SELECT `+validatorDashboardDayStatsColumns+`
+		FROM validators v
+		INNER JOIN validator_stats vs ON vs.validatorindex = v.validatorindex AND vs.day <= $2
+		WHERE v.withdrawalcredentials = ANY($1)
+		GROUP BY vs.day
+		ORDER BY vs.day`, pq.ByteaArray(credentials), lastDay)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving stats of withdrawal address %x: %w", address, err)
+	}
+
+	err = cache.TieredCache.Set(cacheKey, &stats, time.Hour*24)
+	if err != nil {
+		utils.LogError(err, fmt.Errorf("error setting tieredCache for GetWithdrawalAddressDashboardStats with key %v", cacheKey), 0)
+	}
+	return stats, nil
+}
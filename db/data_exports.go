@@ -0,0 +1,72 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// GetDataExports returns the exported dumps of the datasets of the last days, newest first
+func GetDataExports(days uint64) ([]*types.DataExport, error) {
+	exports := []*types.DataExport{}
+	err := ReaderDb.Select(&exports, `
+		SELECT dataset, day, object_key, rows, size_bytes, created_ts
+		FROM data_exports
+		WHERE day > (SELECT COALESCE(MAX(day), 0) FROM data_exports) - $1
+		ORDER BY day DESC, dataset`, days)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving data exports: %w", err)
+	}
+	return exports, nil
+}
+
+// GetDataExport returns the dump of the dataset of the day or nil if it has not been exported
+func GetDataExport(dataset string, day uint64) (*types.DataExport, error) {
+	export := &types.DataExport{}
+	err := ReaderDb.Get(export, `
+		SELECT dataset, day, object_key, rows, size_bytes, created_ts
+		FROM data_exports
+		WHERE dataset = $1 AND day = $2`, dataset, day)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving data export of dataset %v of day %v: %w", dataset, day, err)
+	}
+	return export, nil
+}
+
+// GetLastDataExportDays returns the last exported day of every dataset that has been exported
+func GetLastDataExportDays() (map[string]uint64, error) {
+	rows := []struct {
+		Dataset string `db:"dataset"`
+		Day     uint64 `db:"day"`
+	}{}
+	err := WriterDb.Select(&rows, `SELECT dataset, MAX(day) AS day FROM data_exports GROUP BY dataset`)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving last data export days: %w", err)
+	}
+	days := make(map[string]uint64, len(rows))
+	for _, r := range rows {
+		days[r.Dataset] = r.Day
+	}
+	return days, nil
+}
+
+// SaveDataExport records the dump of a dataset, a repeated export of the day replaces the previous one
+func SaveDataExport(export *types.DataExport) error {
+	_, err := WriterDb.Exec(`
+		INSERT INTO data_exports (dataset, day, object_key, rows, size_bytes, created_ts)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (dataset, day) DO UPDATE SET
+			object_key = excluded.object_key,
+			rows = excluded.rows,
+			size_bytes = excluded.size_bytes,
+			created_ts = excluded.created_ts`,
+		export.Dataset, export.Day, export.ObjectKey, export.Rows, export.SizeBytes)
+	if err != nil {
+		return fmt.Errorf("error saving data export of dataset %v of day %v: %w", export.Dataset, export.Day, err)
+	}
+	return nil
+}
@@ -23,7 +23,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
-	"github.com/pressly/goose/v3"
 	prysm_deposit "github.com/prysmaticlabs/prysm/v3/contracts/deposit"
 	ethpb "github.com/prysmaticlabs/prysm/v3/proto/prysm/v1alpha1"
 	"github.com/sirupsen/logrus"
@@ -43,8 +42,9 @@ var WriterDb *sqlx.DB
 var ReaderDb *sqlx.DB
 
 var ClickhouseReaderDb *sqlx.DB
+var ClickhouseWriterDb *sqlx.DB
 
-var logger = logrus.StandardLogger().WithField("module", "db")
+var logger = utils.NewLogger("db")
 
 var farFutureEpoch = uint64(18446744073709551615)
 var maxSqlNumber = uint64(9223372036854775807)
@@ -108,7 +108,12 @@ func mustInitDB(writer *types.DatabaseConfig, reader *types.DatabaseConfig, driv
 		}
 
 		logger.Infof("connecting to %s database %s:%s/%s as writer with %d/%d max open/idle connections", databaseBrand, writer.Host, writer.Port, writer.Name, writer.MaxOpenConns, writer.MaxIdleConns)
-		dbConnWriter, err = sqlx.Open(driverName, fmt.Sprintf("%s://%s:%s@%s/%s?%s", databaseBrand, writer.Username, writer.Password, net.JoinHostPort(writer.Host, writer.Port), writer.Name, sslParam))
+		dsn := fmt.Sprintf("%s://%s:%s@%s/%s?%s", databaseBrand, writer.Username, writer.Password, net.JoinHostPort(writer.Host, writer.Port), writer.Name, sslParam)
+		if driverName == "pgx" {
+			dbConnWriter, err = openPostgres(dsn)
+		} else {
+			dbConnWriter, err = sqlx.Open(driverName, dsn)
+		}
 		if err != nil {
 			logger.Fatal(err, "error getting Connection Writer database", 0)
 		}
@@ -150,7 +155,13 @@ func mustInitDB(writer *types.DatabaseConfig, reader *types.DatabaseConfig, driv
 		}
 
 		logger.Infof("connecting to %s database %s:%s/%s as reader with %d/%d max open/idle connections", databaseBrand, reader.Host, reader.Port, reader.Name, reader.MaxOpenConns, reader.MaxIdleConns)
-		dbConnReader, err = sqlx.Open(driverName, fmt.Sprintf("%s://%s:%s@%s/%s?%s", databaseBrand, reader.Username, reader.Password, net.JoinHostPort(reader.Host, reader.Port), reader.Name, sslParam))
+		if len(reader.Replicas) > 0 && driverName == "pgx" {
+			dbConnReader, err = openReplicaRouter(reader, writer)
+		} else if driverName == "pgx" {
+			dbConnReader, err = openPostgres(fmt.Sprintf("%s://%s:%s@%s/%s?%s", databaseBrand, reader.Username, reader.Password, net.JoinHostPort(reader.Host, reader.Port), reader.Name, sslParam))
+		} else {
+			dbConnReader, err = sqlx.Open(driverName, fmt.Sprintf("%s://%s:%s@%s/%s?%s", databaseBrand, reader.Username, reader.Password, net.JoinHostPort(reader.Host, reader.Port), reader.Name, sslParam))
+		}
 		if err != nil {
 			logger.Fatal(err, "error getting Connection Reader database", 0)
 		}
@@ -165,37 +176,13 @@ func mustInitDB(writer *types.DatabaseConfig, reader *types.DatabaseConfig, driv
 }
 
 func MustInitClickhouseDB(writer *types.DatabaseConfig, reader *types.DatabaseConfig, driverName string, databaseBrand string) {
-	_, ClickhouseReaderDb = mustInitDB(writer, reader, driverName, databaseBrand)
+	ClickhouseWriterDb, ClickhouseReaderDb = mustInitDB(writer, reader, driverName, databaseBrand)
 }
 
 func MustInitDB(writer *types.DatabaseConfig, reader *types.DatabaseConfig, driverName string, databaseBrand string) {
 	WriterDb, ReaderDb = mustInitDB(writer, reader, driverName, databaseBrand)
 }
 
-func ApplyEmbeddedDbSchema(version int64) error {
-	goose.SetBaseFS(EmbedMigrations)
-
-	if err := goose.SetDialect("postgres"); err != nil {
-		return err
-	}
-
-	if version == -2 {
-		if err := goose.Up(WriterDb.DB, "migrations"); err != nil {
-			return err
-		}
-	} else if version == -1 {
-		if err := goose.UpByOne(WriterDb.DB, "migrations"); err != nil {
-			return err
-		}
-	} else {
-		if err := goose.UpTo(WriterDb.DB, "migrations", version); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func GetEth1DepositsJoinEth2Deposits(query string, length, start uint64, orderBy, orderDir string, latestEpoch, validatorOnlineThresholdSlot uint64) ([]*types.EthOneDepositsData, uint64, error) {
 	// Initialize the return values
 	deposits := []*types.EthOneDepositsData{}
@@ -239,7 +226,8 @@ func GetEth1DepositsJoinEth2Deposits(query string, length, start uint64, orderBy
 			eth1.signature as signature,
 			eth1.merkletree_index as merkletree_index,
 			eth1.valid_signature as valid_signature,
-			COALESCE(v.state, 'deposited') as state
+			COALESCE(v.state, 'deposited') as state,
+			COALESCE((SELECT w.warning FROM eth1_deposit_warnings w WHERE w.tx_hash = eth1.tx_hash AND w.merkletree_index = eth1.merkletree_index), '') as warning
 		FROM
 			eth1_deposits as eth1
 		LEFT JOIN
@@ -558,6 +546,23 @@ func GetAllNonFinalizedSlots() ([]*GetAllNonFinalizedSlotsRow, error) {
 	return slots, nil
 }
 
+// GetExporterHeads returns the latest epoch, slot and statistics day exported to the db and the head of the node
+func GetExporterHeads() (*types.ExporterHeads, error) {
+	heads := &types.ExporterHeads{}
+	err := WriterDb.Get(heads, `
+		SELECT
+			COALESCE((SELECT headepoch FROM network_liveness ORDER BY headepoch DESC LIMIT 1), 0) AS node_head_epoch,
+			COALESCE((SELECT finalizedepoch FROM network_liveness ORDER BY headepoch DESC LIMIT 1), 0) AS node_finalized_epoch,
+			COALESCE((SELECT MAX(epoch) FROM epochs), 0) AS latest_epoch,
+			COALESCE((SELECT MAX(epoch) FROM epochs WHERE finalized), 0) AS latest_finalized_epoch,
+			COALESCE((SELECT MAX(slot) FROM blocks), 0) AS latest_slot,
+			(SELECT MAX(day) FROM validator_stats_status WHERE status) AS latest_statistics_day`)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving exporter heads: %w", err)
+	}
+	return heads, nil
+}
+
 // Get latest finalized epoch
 func GetLatestFinalizedEpoch() (uint64, error) {
 	var latestFinalized uint64
@@ -1266,6 +1271,159 @@ func GetRelayDataForIndexedBlocks(blocks []*types.Eth1BlockIndexed) (map[common.
 	return relaysDataMap, nil
 }
 
+// GetRelayAnomalies returns the most recent blocks where the proposer received less than the winning relay bid
+func GetRelayAnomalies(limit uint64) ([]*types.RelayAnomaly, error) {
+	anomalies := []*types.RelayAnomaly{}
+	err := ReaderDb.Select(&anomalies, `
+		SELECT
+			relays_anomalies.block_slot,
+			relays_anomalies.block_root,
+			relays_anomalies.exec_block_hash,
+			relays_anomalies.anomaly_type,
+			relays_anomalies.tag_id,
+			relays_anomalies.delivered_value,
+			relays_anomalies.winning_bid_tag_id,
+			relays_anomalies.winning_bid_block_hash,
+			relays_anomalies.winning_bid_value,
+			blocks.proposer
+		FROM relays_anomalies
+		INNER JOIN blocks ON blocks.slot = relays_anomalies.block_slot AND blocks.blockroot = relays_anomalies.block_root
+		ORDER BY relays_anomalies.block_slot DESC
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	return anomalies, nil
+}
+
+// GetRelayAnomaliesForBlock returns the relay anomalies recorded for the execution block with the given hash
+func GetRelayAnomaliesForBlock(execBlockHash []byte) ([]*types.RelayAnomaly, error) {
+	anomalies := []*types.RelayAnomaly{}
+	err := ReaderDb.Select(&anomalies, `
+		SELECT
+			relays_anomalies.block_slot,
+			relays_anomalies.block_root,
+			relays_anomalies.exec_block_hash,
+			relays_anomalies.anomaly_type,
+			relays_anomalies.tag_id,
+			relays_anomalies.delivered_value,
+			relays_anomalies.winning_bid_tag_id,
+			relays_anomalies.winning_bid_block_hash,
+			relays_anomalies.winning_bid_value,
+			blocks.proposer
+		FROM relays_anomalies
+		INNER JOIN blocks ON blocks.slot = relays_anomalies.block_slot AND blocks.blockroot = relays_anomalies.block_root
+		WHERE relays_anomalies.exec_block_hash = $1`, execBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	return anomalies, nil
+}
+
+const blockProductionQuery = `
+	SELECT
+		block_slot,
+		block_root,
+		exec_block_hash,
+		proposer,
+		locally_built,
+		relay_tag_id,
+		builder_pubkey,
+		COALESCE(delivered_value, 0) AS delivered_value,
+		COALESCE(fee_recipient_reward, 0) AS fee_recipient_reward,
+		winning_bid_tag_id,
+		winning_bid_builder_pubkey,
+		winning_bid_block_hash,
+		COALESCE(winning_bid_value, 0) AS winning_bid_value,
+		updated_ts
+	FROM blocks_production`
+
+// SaveBlocksProduction stores the production path of the blocks proposed after the slot, joining the delivered relay
+// payloads, the highest relay bids and the execution rewards. Rows are only updated if the relay data changed.
+func SaveBlocksProduction(fromSlot uint64) error {
+	_, err := WriterDb.Exec(`
+		WITH winning_bids AS (
+			SELECT DISTINCT ON (block_slot) block_slot, tag_id, block_hash, builder_pubkey, value
+			FROM relays_bids
+			WHERE block_slot > $1
+			ORDER BY block_slot, value DESC
+		), delivered AS (
+			SELECT DISTINCT ON (block_slot, block_root) block_slot, block_root, tag_id, builder_pubkey, value
+			FROM relays_blocks
+			WHERE block_slot > $1
+			ORDER BY block_slot, block_root, value DESC, tag_id
+		)
+		INSERT INTO blocks_production (block_slot, block_root, exec_block_hash, proposer, locally_built, relay_tag_id, builder_pubkey, delivered_value, fee_recipient_reward, winning_bid_tag_id, winning_bid_builder_pubkey, winning_bid_block_hash, winning_bid_value, updated_ts)
+		SELECT
+			blocks.slot,
+			blocks.blockroot,
+			blocks.exec_block_hash,
+			blocks.proposer,
+			delivered.block_root IS NULL,
+			delivered.tag_id,
+			delivered.builder_pubkey,
+			delivered.value,
+			(execution_payloads.fee_recipient_reward * 1e18)::NUMERIC(78, 0),
+			winning_bids.tag_id,
+			winning_bids.builder_pubkey,
+			winning_bids.block_hash,
+			winning_bids.value,
+			NOW() AT TIME ZONE 'utc'
+		FROM blocks
+		LEFT JOIN delivered ON delivered.block_slot = blocks.slot AND delivered.block_root = blocks.blockroot
+		LEFT JOIN winning_bids ON winning_bids.block_slot = blocks.slot
+		LEFT JOIN execution_payloads ON execution_payloads.block_hash = blocks.exec_block_hash
+		WHERE blocks.slot > $1 AND blocks.status = '1' AND blocks.exec_block_hash IS NOT NULL
+		ON CONFLICT (block_slot, block_root) DO UPDATE SET
+			locally_built = excluded.locally_built,
+			relay_tag_id = excluded.relay_tag_id,
+			builder_pubkey = excluded.builder_pubkey,
+			delivered_value = excluded.delivered_value,
+			fee_recipient_reward = excluded.fee_recipient_reward,
+			winning_bid_tag_id = excluded.winning_bid_tag_id,
+			winning_bid_builder_pubkey = excluded.winning_bid_builder_pubkey,
+			winning_bid_block_hash = excluded.winning_bid_block_hash,
+			winning_bid_value = excluded.winning_bid_value,
+			updated_ts = excluded.updated_ts
+		WHERE
+			(blocks_production.relay_tag_id, blocks_production.delivered_value, blocks_production.fee_recipient_reward, blocks_production.winning_bid_tag_id, blocks_production.winning_bid_value) IS DISTINCT FROM
+			(excluded.relay_tag_id, excluded.delivered_value, excluded.fee_recipient_reward, excluded.winning_bid_tag_id, excluded.winning_bid_value)`, fromSlot)
+	if err != nil {
+		return fmt.Errorf("error saving block production after slot %v: %w", fromSlot, err)
+	}
+	return nil
+}
+
+// GetBlockProduction returns the production path of the canonical block of the slot, nil if it has not been exported
+func GetBlockProduction(slot uint64) (*types.BlockProduction, error) {
+	production := &types.BlockProduction{}
+	err := ReaderDb.Get(production, blockProductionQuery+`
+		WHERE block_slot = $1 AND block_root = (SELECT blockroot FROM blocks WHERE slot = $1 AND status = '1' LIMIT 1)`, slot)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving block production of slot %v: %w", slot, err)
+	}
+	return production, nil
+}
+
+// GetBlockProductionForBlock returns the production path of the execution block with the given hash, nil if it has not
+// been exported
+func GetBlockProductionForBlock(execBlockHash []byte) (*types.BlockProduction, error) {
+	production := &types.BlockProduction{}
+	err := ReaderDb.Get(production, blockProductionQuery+`
+		WHERE exec_block_hash = $1
+		LIMIT 1`, execBlockHash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving block production of block %#x: %w", execBlockHash, err)
+	}
+	return production, nil
+}
+
 func saveBlocks(blocks map[uint64]map[string]*types.Block, tx *sqlx.Tx, forceSlotUpdate bool) error {
 	start := time.Now()
 	defer func() {
@@ -1723,7 +1881,7 @@ func UpdateQueueDeposits(tx *sqlx.Tx) error {
 		return err
 	}
 
-	// efficiently collect the tnx that pushed each validator over 32 ETH.
+	// efficiently collect the tnx that pushed each validator over the activation balance (32 ETH on mainnet).
 	_, err = tx.Exec(`
 		UPDATE validator_queue_deposits 
 		SET 
@@ -1750,11 +1908,11 @@ func UpdateQueueDeposits(tx *sqlx.Tx) error {
 			FROM CumSum
 			/* join so we can retrieve the validator index again */
 			left join validators on validators.pubkey = CumSum.publickey
-			/* we want the deposit that pushed the cum sum over 32 ETH */
-			WHERE cumTotal>=32000000000
+			/* we want the deposit that pushed the cum sum over the activation balance */
+			WHERE cumTotal>=$1
 			ORDER BY publickey, cumTotal asc 
 		) AS data
-		WHERE validator_queue_deposits.validatorindex=data.validatorindex`)
+		WHERE validator_queue_deposits.validatorindex=data.validatorindex`, utils.Config.Chain.ClConfig.MaxEffectiveBalance)
 	if err != nil {
 		logger.Errorf("error updating validator_queue_deposits: %v", err)
 		return err
@@ -2058,6 +2216,58 @@ func GetSlotVizData(latestEpoch uint64) ([]*types.SlotVizEpochs, error) {
 	return res, nil
 }
 
+// GetSlotVizDuties returns the block proposal duties (slot => validators) and the sync committee members (epoch =>
+// validators) of the given validators between startEpoch and endEpoch
+func GetSlotVizDuties(validators []uint64, startEpoch, endEpoch uint64) (map[uint64][]uint64, map[uint64][]uint64, error) {
+	proposals := map[uint64][]uint64{}
+	syncMembers := map[uint64][]uint64{}
+	if len(validators) == 0 || startEpoch > endEpoch {
+		return proposals, syncMembers, nil
+	}
+
+	proposalRows := []struct {
+		Slot     uint64
+		Proposer uint64
+	}{}
+	err := ReaderDb.Select(&proposalRows, `
+		SELECT DISTINCT slot, proposer
+		FROM blocks
+		WHERE epoch >= $1 AND epoch <= $2 AND proposer = ANY($3)
+		ORDER BY slot`, startEpoch, endEpoch, pq.Array(validators))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting proposal duties for epochs %v - %v: %w", startEpoch, endEpoch, err)
+	}
+	for _, row := range proposalRows {
+		proposals[row.Slot] = append(proposals[row.Slot], row.Proposer)
+	}
+
+	epochsPerPeriod := utils.Config.Chain.ClConfig.EpochsPerSyncCommitteePeriod
+	syncRows := []struct {
+		Period         uint64
+		ValidatorIndex uint64
+	}{}
+	err = ReaderDb.Select(&syncRows, `
+		SELECT DISTINCT period, validatorindex
+		FROM sync_committees
+		WHERE period >= $1 AND period <= $2 AND validatorindex = ANY($3)
+		ORDER BY validatorindex`, startEpoch/epochsPerPeriod, endEpoch/epochsPerPeriod, pq.Array(validators))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting sync committee duties for epochs %v - %v: %w", startEpoch, endEpoch, err)
+	}
+	for epoch := startEpoch; epoch <= endEpoch; epoch++ {
+		if epoch < utils.Config.Chain.ClConfig.AltairForkEpoch {
+			continue
+		}
+		for _, row := range syncRows {
+			if row.Period == epoch/epochsPerPeriod {
+				syncMembers[epoch] = append(syncMembers[epoch], row.ValidatorIndex)
+			}
+		}
+	}
+
+	return proposals, syncMembers, nil
+}
+
 func GetBlockNumber(slot uint64) (block uint64, err error) {
 	err = ReaderDb.Get(&block, `SELECT exec_block_number FROM blocks where slot >= $1 AND exec_block_number > 0 ORDER BY slot LIMIT 1`, slot)
 	return
@@ -3019,6 +3229,176 @@ func GetValidatorsBLSChange(validators []uint64) ([]*types.ValidatorsBLSChange,
 	return change, nil
 }
 
+// GetEpochBLSChanges returns the BLS to execution changes included in the canonical blocks of an epoch
+func GetEpochBLSChanges(epoch uint64) ([]*types.BLSChangeNotification, error) {
+	var changes []*types.BLSChangeNotification
+
+	err := ReaderDb.Select(&changes, `
+	SELECT
+		bls.block_slot AS slot,
+		bls.validatorindex,
+		bls.address,
+		v.pubkey
+	FROM blocks_bls_change bls
+	INNER JOIN blocks b ON b.blockroot = bls.block_root AND b.status = '1'
+	INNER JOIN validators v ON v.validatorindex = bls.validatorindex
+	WHERE bls.block_slot >= $1 AND bls.block_slot < $2
+	ORDER BY bls.block_slot, bls.validatorindex`, epoch*utils.Config.Chain.ClConfig.SlotsPerEpoch, (epoch+1)*utils.Config.Chain.ClConfig.SlotsPerEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting blocks_bls_change for epoch: %d: %w", epoch, err)
+	}
+
+	return changes, nil
+}
+
+// GetEpochVoluntaryExits returns the voluntary exits included in the canonical blocks of an epoch
+func GetEpochVoluntaryExits(epoch uint64) ([]*types.ValidatorExitNotification, error) {
+	var exits []*types.ValidatorExitNotification
+
+	err := ReaderDb.Select(&exits, `
+	SELECT
+		ve.block_slot AS slot,
+		ve.validatorindex,
+		v.exitepoch,
+		v.withdrawableepoch,
+		v.pubkey
+	FROM blocks_voluntaryexits ve
+	INNER JOIN blocks b ON b.slot = ve.block_slot AND b.status = '1'
+	INNER JOIN validators v ON v.validatorindex = ve.validatorindex
+	WHERE ve.block_slot >= $1 AND ve.block_slot < $2
+	ORDER BY ve.block_slot, ve.validatorindex`, epoch*utils.Config.Chain.ClConfig.SlotsPerEpoch, (epoch+1)*utils.Config.Chain.ClConfig.SlotsPerEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting blocks_voluntaryexits for epoch: %d: %w", epoch, err)
+	}
+
+	return exits, nil
+}
+
+// GetValidatorsWithdrawableAtEpoch returns the exited validators whose withdrawable epoch is the given epoch
+func GetValidatorsWithdrawableAtEpoch(epoch uint64) ([]*types.ValidatorExitNotification, error) {
+	var validators []*types.ValidatorExitNotification
+
+	err := ReaderDb.Select(&validators, `
+	SELECT
+		validatorindex,
+		exitepoch,
+		withdrawableepoch,
+		pubkey
+	FROM validators
+	WHERE withdrawableepoch = $1 AND exitepoch <= $1
+	ORDER BY validatorindex`, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting validators withdrawable at epoch: %d: %w", epoch, err)
+	}
+
+	return validators, nil
+}
+
+// GetVoluntaryExits returns the voluntary exits of canonical blocks ordered by slot, newest first
+func GetVoluntaryExits(limit, offset uint64) ([]*types.ExitsPageExit, error) {
+	var exits []*types.ExitsPageExit
+
+	err := ReaderDb.Select(&exits, `
+	SELECT
+		ve.block_slot AS slot,
+		ve.validatorindex,
+		v.exitepoch,
+		v.withdrawableepoch,
+		v.balance
+	FROM blocks_voluntaryexits ve
+	INNER JOIN blocks b ON b.slot = ve.block_slot AND b.status = '1'
+	INNER JOIN validators v ON v.validatorindex = ve.validatorindex
+	ORDER BY ve.block_slot DESC, ve.block_index DESC
+	LIMIT $1
+	OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error getting voluntary exits: %w", err)
+	}
+
+	return exits, nil
+}
+
+func GetVoluntaryExitCount() (uint64, error) {
+	var count uint64
+	err := ReaderDb.Get(&count, `
+	SELECT COUNT(*)
+	FROM blocks_voluntaryexits ve
+	INNER JOIN blocks b ON b.slot = ve.block_slot AND b.status = '1'`)
+	if err != nil {
+		return 0, fmt.Errorf("error getting voluntary exit count: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetExitQueue returns the number of validators that have initiated an exit but not yet reached their exit epoch
+// and the exit epoch of the last validator in the queue
+func GetExitQueue(epoch uint64) (uint64, uint64, error) {
+	queue := struct {
+		Length        uint64 `db:"length"`
+		LastExitEpoch uint64 `db:"last_exit_epoch"`
+	}{}
+	err := ReaderDb.Get(&queue, `
+	SELECT
+		COUNT(*) AS length,
+		COALESCE(MAX(exitepoch), 0) AS last_exit_epoch
+	FROM validators
+	WHERE exitepoch > $1 AND exitepoch < $2`, epoch, maxSqlNumber)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error getting exit queue for epoch %d: %w", epoch, err)
+	}
+
+	return queue.Length, queue.LastExitEpoch, nil
+}
+
+// GetExitEpochCount returns the number of validators exiting in the epoch
+func GetExitEpochCount(exitEpoch uint64) (uint64, error) {
+	var count uint64
+	err := ReaderDb.Get(&count, `SELECT COUNT(*) FROM validators WHERE exitepoch = $1`, exitEpoch)
+	if err != nil {
+		return 0, fmt.Errorf("error getting number of validators exiting in epoch %d: %w", exitEpoch, err)
+	}
+	return count, nil
+}
+
+// GetValidatorScheduledProposals returns the slots of the scheduled proposals of the validator after the slot
+func GetValidatorScheduledProposals(validatorIndex, slot uint64) ([]uint64, error) {
+	slots := []uint64{}
+	err := ReaderDb.Select(&slots, `
+		SELECT slot
+		FROM blocks
+		WHERE proposer = $1 AND slot > $2 AND status = '0'
+		ORDER BY slot`, validatorIndex, slot)
+	if err != nil {
+		return nil, fmt.Errorf("error getting scheduled proposals of validator %d: %w", validatorIndex, err)
+	}
+	return slots, nil
+}
+
+// GetValidatorSyncCommitteePeriodsFrom returns the sync committee periods of the validator starting with the period
+func GetValidatorSyncCommitteePeriodsFrom(validatorIndex, period uint64) ([]uint64, error) {
+	periods := []uint64{}
+	err := ReaderDb.Select(&periods, `
+		SELECT DISTINCT period
+		FROM sync_committees
+		WHERE validatorindex = $1 AND period >= $2
+		ORDER BY period`, validatorIndex, period)
+	if err != nil {
+		return nil, fmt.Errorf("error getting sync committee periods of validator %d: %w", validatorIndex, err)
+	}
+	return periods, nil
+}
+
+// GetValidatorClPerformance7d returns the consensus rewards of the validator in the last 7 days in gwei
+func GetValidatorClPerformance7d(validatorIndex uint64) (int64, error) {
+	var performance int64
+	err := ReaderDb.Get(&performance, `SELECT COALESCE(MAX(cl_performance_7d), 0) FROM validator_performance WHERE validatorindex = $1`, validatorIndex)
+	if err != nil {
+		return 0, fmt.Errorf("error getting 7 day performance of validator %d: %w", validatorIndex, err)
+	}
+	return performance, nil
+}
+
 func GetWithdrawableValidatorCount(epoch uint64) (uint64, error) {
 	var count uint64
 	err := ReaderDb.Get(&count, `
@@ -3347,3 +3727,26 @@ func GetValidatorAttestationHistoryForNotifications(startEpoch uint64, endEpoch
 
 	return epochParticipation, nil
 }
+
+// GetValidatorIndicesForEth1Address returns the indices of validators that were deposited from or withdraw to the given execution layer address
+func GetValidatorIndicesForEth1Address(address []byte, limit uint64) ([]uint64, error) {
+	credentials, err := utils.AddressToWithdrawalCredentials(address)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := []uint64{}
+	err = ReaderDb.Select(&indices, `
+		SELECT validatorindex FROM validators WHERE withdrawalcredentials = $1
+		UNION
+		SELECT validators.validatorindex
+		FROM eth1_deposits
+		INNER JOIN validators ON validators.pubkey = eth1_deposits.publickey
+		WHERE eth1_deposits.from_address = $2
+		ORDER BY validatorindex
+		LIMIT $3`, credentials, address, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validators of address %x: %w", address, err)
+	}
+	return indices, nil
+}
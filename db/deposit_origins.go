@@ -0,0 +1,228 @@
+package db
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/lib/pq"
+)
+
+// depositOriginScanRows is the amount of index rows read per address and hop when looking for the funding transfer
+const depositOriginScanRows = 100
+
+// depositOriginKinds maps the categories of address labels to the kind of deposit origin they describe
+var depositOriginKinds = map[string]string{
+	types.AddressLabelCategoryExchange: types.DepositOriginExchange,
+	types.AddressLabelCategoryEntity:   types.DepositOriginCustodian,
+	types.AddressLabelCategoryContract: types.DepositOriginContract,
+	types.AddressLabelCategoryBridge:   types.DepositOriginBridge,
+	types.AddressLabelCategoryMixer:    types.DepositOriginMixer,
+}
+
+// DepositAddressToTrace is a deposit address together with the time of its first valid deposit
+type DepositAddressToTrace struct {
+	Address      []byte    `db:"from_address"`
+	FirstDeposit time.Time `db:"first_deposit"`
+}
+
+// GetDepositAddressesToTrace returns deposit addresses that have not been traced yet or whose origin was unknown
+// when they were traced before retraceBefore
+func GetDepositAddressesToTrace(retraceBefore time.Time, limit uint64) ([]*DepositAddressToTrace, error) {
+	addresses := []*DepositAddressToTrace{}
+	err := ReaderDb.Select(&addresses, `
+		SELECT d.from_address, MIN(d.block_ts) AS first_deposit
+		FROM eth1_deposits d
+		LEFT JOIN deposit_origins o ON o.from_address = d.from_address
+		WHERE d.valid_signature AND (o.from_address IS NULL OR (o.origin_kind = $1 AND o.traced_ts < $2))
+		GROUP BY d.from_address
+		ORDER BY MIN(d.block_ts)
+		LIMIT $3`, types.DepositOriginUnknown, retraceBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving deposit addresses to trace: %w", err)
+	}
+	return addresses, nil
+}
+
+// depositFundingTransfer is the largest transfer an address received before a given time
+type depositFundingTransfer struct {
+	From        []byte
+	Value       *big.Int
+	Time        time.Time
+	ViaContract bool
+}
+
+// TraceDepositOrigin follows the largest incoming transfer of the deposit address received before its deposit back
+// up to maxHops addresses. The trace stops at the first address with a label or at the first smart contract, which is
+// detected by the funding being an internal transfer. Only eth transfers indexed by the execution layer indexer are
+// followed.
+func (bigtable *Bigtable) TraceDepositOrigin(address []byte, before time.Time, maxHops int) (*types.DepositOrigin, error) {
+	origin := &types.DepositOrigin{
+		DepositAddress: address,
+		Kind:           types.DepositOriginUnknown,
+		Path:           pq.ByteaArray{},
+		TracedTs:       time.Now().UTC(),
+	}
+
+	visited := map[string]bool{string(address): true}
+	current := address
+	viaContract := false
+	for hops := 0; ; hops++ {
+		labels, err := GetAddressLabels(current)
+		if err != nil {
+			return nil, err
+		}
+		if len(labels) > 0 {
+			origin.Kind = depositOriginKinds[labels[0].Category]
+			origin.OriginAddress = current
+			origin.OriginLabel = labels[0].Label
+			break
+		}
+		if viaContract {
+			origin.Kind = types.DepositOriginContract
+			origin.OriginAddress = current
+			break
+		}
+		if hops >= maxHops {
+			break
+		}
+
+		transfer, err := bigtable.getDepositFundingTransfer(current, before)
+		if err != nil {
+			return nil, err
+		}
+		if transfer == nil || visited[string(transfer.From)] {
+			break
+		}
+		visited[string(transfer.From)] = true
+		origin.Path = append(origin.Path, transfer.From)
+
+		current = transfer.From
+		before = transfer.Time
+		viaContract = transfer.ViaContract
+	}
+	origin.Hops = uint64(len(origin.Path))
+
+	return origin, nil
+}
+
+// getDepositFundingTransfer returns the largest successful eth transfer or internal transfer address received up to
+// the given time, nil if none of the scanned transfers funded the address
+func (bigtable *Bigtable) getDepositFundingTransfer(address []byte, before time.Time) (*depositFundingTransfer, error) {
+	revTs := fmt.Sprintf("%019d", MAX_INT-before.Unix())
+
+	txs, _, err := bigtable.GetEth1TxsForAddress(fmt.Sprintf("%s:I:TX:%x:%s:%s", bigtable.chainId, address, FILTER_TIME, revTs), depositOriginScanRows)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving txs of %#x: %w", address, err)
+	}
+	itxs, _, err := bigtable.GetEth1ItxsForAddress(fmt.Sprintf("%s:I:ITX:%x:%s:%s", bigtable.chainId, address, FILTER_TIME, revTs), depositOriginScanRows)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving internal txs of %#x: %w", address, err)
+	}
+
+	var funding *depositFundingTransfer
+	consider := func(from, to, value []byte, ts time.Time, viaContract bool) {
+		if !bytes.Equal(to, address) || bytes.Equal(from, address) {
+			return
+		}
+		v := new(big.Int).SetBytes(value)
+		if v.Sign() == 0 || (funding != nil && funding.Value.Cmp(v) >= 0) {
+			return
+		}
+		funding = &depositFundingTransfer{From: from, Value: v, Time: ts, ViaContract: viaContract}
+	}
+	for _, tx := range txs {
+		if tx.GetErrorMsg() != "" {
+			continue
+		}
+		consider(tx.GetFrom(), tx.GetTo(), tx.GetValue(), tx.GetTime().AsTime(), false)
+	}
+	// internal transfers are always sent by a smart contract
+	for _, itx := range itxs {
+		consider(itx.GetFrom(), itx.GetTo(), itx.GetValue(), itx.GetTime().AsTime(), true)
+	}
+
+	return funding, nil
+}
+
+// SaveDepositOrigins stores the traced origins of deposit addresses, replacing previous traces
+func SaveDepositOrigins(origins []*types.DepositOrigin) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in SaveDepositOrigins: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, o := range origins {
+		_, err = tx.Exec(`
+			INSERT INTO deposit_origins (from_address, origin_kind, origin_address, origin_label, hops, path, traced_ts)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (from_address) DO UPDATE SET
+				origin_kind    = excluded.origin_kind,
+				origin_address = excluded.origin_address,
+				origin_label   = excluded.origin_label,
+				hops           = excluded.hops,
+				path           = excluded.path,
+				traced_ts      = excluded.traced_ts`,
+			o.DepositAddress, o.Kind, o.OriginAddress, o.OriginLabel, o.Hops, o.Path, o.TracedTs)
+		if err != nil {
+			return fmt.Errorf("error saving deposit origin of %#x: %w", o.DepositAddress, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetValidatorDepositOrigins returns the traced origin of the address of the first valid deposit of each validator,
+// validators without a valid deposit are omitted and the origin is nil if the address has not been traced yet
+func GetValidatorDepositOrigins(publicKeys [][]byte) ([]*types.ValidatorDepositOrigin, error) {
+	rows := []struct {
+		PublicKey      []byte         `db:"publickey"`
+		ValidatorIndex *uint64        `db:"validatorindex"`
+		DepositAddress []byte         `db:"from_address"`
+		Kind           sql.NullString `db:"origin_kind"`
+		OriginAddress  []byte         `db:"origin_address"`
+		OriginLabel    sql.NullString `db:"origin_label"`
+		Hops           sql.NullInt64  `db:"hops"`
+		Path           pq.ByteaArray  `db:"path"`
+		TracedTs       sql.NullTime   `db:"traced_ts"`
+	}{}
+	err := ReaderDb.Select(&rows, `
+		SELECT DISTINCT ON (d.publickey)
+			d.publickey, v.validatorindex, d.from_address,
+			o.origin_kind, o.origin_address, o.origin_label, o.hops, o.path, o.traced_ts
+		FROM eth1_deposits d
+		LEFT JOIN validators v ON v.pubkey = d.publickey
+		LEFT JOIN deposit_origins o ON o.from_address = d.from_address
+		WHERE d.publickey = ANY($1) AND d.valid_signature
+		ORDER BY d.publickey, d.block_number, d.tx_index`, pq.ByteaArray(publicKeys))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving deposit origins of %v validators: %w", len(publicKeys), err)
+	}
+
+	origins := make([]*types.ValidatorDepositOrigin, 0, len(rows))
+	for _, r := range rows {
+		o := &types.ValidatorDepositOrigin{
+			PublicKey:      r.PublicKey,
+			ValidatorIndex: r.ValidatorIndex,
+			DepositAddress: r.DepositAddress,
+		}
+		if r.Kind.Valid {
+			o.Origin = &types.DepositOrigin{
+				DepositAddress: r.DepositAddress,
+				Kind:           r.Kind.String,
+				OriginAddress:  r.OriginAddress,
+				OriginLabel:    r.OriginLabel.String,
+				Hops:           uint64(r.Hops.Int64),
+				Path:           r.Path,
+				TracedTs:       r.TracedTs.Time,
+			}
+		}
+		origins = append(origins, o)
+	}
+	return origins, nil
+}
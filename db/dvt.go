@@ -0,0 +1,146 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/lib/pq"
+)
+
+// SaveDvtValidators replaces the validators of a dvt protocol, tags them with the protocol and updates the adoption snapshot
+// of the current day. Validators that are not known to the beacon chain are skipped.
+func SaveDvtValidators(protocol string, validators []*types.DvtValidator) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in SaveDvtValidators: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM validator_dvt WHERE protocol = $1`, protocol)
+	if err != nil {
+		return fmt.Errorf("error deleting %v validators: %w", protocol, err)
+	}
+
+	batchSize := 5000
+	for b := 0; b < len(validators); b += batchSize {
+		end := b + batchSize
+		if len(validators) < end {
+			end = len(validators)
+		}
+
+		pubkeys := make([][]byte, 0, end-b)
+		clusterIds := make([]string, 0, end-b)
+		clusterNames := make([]string, 0, end-b)
+		operators := make([]string, 0, end-b)
+		for _, v := range validators[b:end] {
+			pubkeys = append(pubkeys, v.PublicKey)
+			clusterIds = append(clusterIds, v.ClusterId)
+			clusterNames = append(clusterNames, v.ClusterName)
+			operators = append(operators, strings.Join(v.Operators, ","))
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO validator_dvt (publickey, protocol, cluster_id, cluster_name, operators, updated_ts)
+			SELECT d.publickey, $1, d.cluster_id, d.cluster_name, STRING_TO_ARRAY(d.operators, ','), NOW() AT TIME ZONE 'utc'
+			FROM UNNEST($2::bytea[], $3::text[], $4::text[], $5::text[]) AS d(publickey, cluster_id, cluster_name, operators)
+			WHERE EXISTS (SELECT 1 FROM validators WHERE pubkey = d.publickey)
+			ON CONFLICT (publickey) DO UPDATE SET
+				protocol = excluded.protocol,
+				cluster_id = excluded.cluster_id,
+				cluster_name = excluded.cluster_name,
+				operators = excluded.operators,
+				updated_ts = excluded.updated_ts`,
+			protocol, pq.ByteaArray(pubkeys), pq.StringArray(clusterIds), pq.StringArray(clusterNames), pq.StringArray(operators))
+		if err != nil {
+			return fmt.Errorf("error saving %v validators: %w", protocol, err)
+		}
+	}
+
+	// the protocol is also kept as validator tag to label the validators
+	_, err = tx.Exec(`DELETE FROM validator_tags t WHERE t.tag = $1 AND NOT EXISTS (SELECT 1 FROM validator_dvt d WHERE d.publickey = t.publickey AND d.protocol = $1)`, protocol)
+	if err != nil {
+		return fmt.Errorf("error deleting stale %v validator tags: %w", protocol, err)
+	}
+	_, err = tx.Exec(`INSERT INTO validator_tags (publickey, tag) SELECT publickey, protocol FROM validator_dvt WHERE protocol = $1 ON CONFLICT (publickey, tag) DO NOTHING`, protocol)
+	if err != nil {
+		return fmt.Errorf("error saving %v validator tags: %w", protocol, err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO dvt_adoption_history (day, protocol, validators, active_validators, clusters)
+		SELECT
+			(NOW() AT TIME ZONE 'utc')::date,
+			$1,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE v.status LIKE 'active%'),
+			COUNT(DISTINCT d.cluster_id)
+		FROM validator_dvt d
+		INNER JOIN validators v ON v.pubkey = d.publickey
+		WHERE d.protocol = $1
+		ON CONFLICT (day, protocol) DO UPDATE SET
+			validators = excluded.validators,
+			active_validators = excluded.active_validators,
+			clusters = excluded.clusters`, protocol)
+	if err != nil {
+		return fmt.Errorf("error saving %v adoption: %w", protocol, err)
+	}
+
+	return tx.Commit()
+}
+
+// GetValidatorDvtCluster returns the distributed validator cluster running a validator, nil if the validator is not a distributed validator
+func GetValidatorDvtCluster(pubkey []byte) (*types.ValidatorDvtCluster, error) {
+	cluster := &types.ValidatorDvtCluster{}
+	err := ReaderDb.Get(cluster, `SELECT protocol, cluster_id, cluster_name, operators, updated_ts FROM validator_dvt WHERE publickey = $1`, pubkey)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving dvt cluster of validator %#x: %w", pubkey, err)
+	}
+	return cluster, nil
+}
+
+// GetDvtAdoption returns the current adoption of each dvt protocol together with its daily history
+func GetDvtAdoption() ([]*types.ApiDvtAdoptionResponse, error) {
+	adoption := []*types.ApiDvtAdoptionResponse{}
+	err := ReaderDb.Select(&adoption, `
+		WITH active AS (
+			SELECT COUNT(*) AS validators FROM validators WHERE status LIKE 'active%'
+		)
+		SELECT
+			d.protocol,
+			COUNT(*) AS validators,
+			COUNT(*) FILTER (WHERE v.status LIKE 'active%') AS active_validators,
+			COUNT(DISTINCT d.cluster_id) AS clusters,
+			COALESCE(COUNT(*) FILTER (WHERE v.status LIKE 'active%') * 100.0 / NULLIF((SELECT validators FROM active), 0), 0) AS share
+		FROM validator_dvt d
+		INNER JOIN validators v ON v.pubkey = d.publickey
+		GROUP BY d.protocol
+		ORDER BY d.protocol`)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving dvt adoption: %w", err)
+	}
+
+	history := []struct {
+		Protocol string `db:"protocol"`
+		types.ApiDvtAdoptionHistory
+	}{}
+	err = ReaderDb.Select(&history, `SELECT protocol, day, validators, active_validators, clusters FROM dvt_adoption_history ORDER BY day`)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving dvt adoption history: %w", err)
+	}
+	for _, a := range adoption {
+		a.History = []*types.ApiDvtAdoptionHistory{}
+		for i := range history {
+			if history[i].Protocol == a.Protocol {
+				a.History = append(a.History, &history[i].ApiDvtAdoptionHistory)
+			}
+		}
+	}
+
+	return adoption, nil
+}
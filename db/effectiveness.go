@@ -0,0 +1,281 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/lib/pq"
+)
+
+// WriteValidatorEffectivenessScoresForDay calculates the effectiveness scores of the current formula version of all
+// validators that were active during the day. The validator statistics of the day have to be exported already.
+func WriteValidatorEffectivenessScoresForDay(day int64) error {
+	if day < 0 {
+		logger.Warnf("no effectiveness scores for days before beaconchain")
+		return nil
+	}
+
+	exportStart := time.Now()
+	defer func() {
+		metrics.TaskDuration.WithLabelValues("db_update_validator_effectiveness").Observe(time.Since(exportStart).Seconds())
+	}()
+
+	if err := CheckIfDayIsFinalized(uint64(day)); err != nil {
+		return err
+	}
+
+	var statsExported bool
+	err := WriterDb.Get(&statsExported, `select coalesce(bool_or(status), false) from validator_stats_status where day = $1`, day)
+	if err != nil {
+		return fmt.Errorf("error getting validator_stats_status in WriteValidatorEffectivenessScoresForDay: %w", err)
+	}
+	if !statsExported {
+		return fmt.Errorf("cannot export effectiveness scores of day %v as the validator statistics of the day have not been exported yet", day)
+	}
+
+	firstEpoch, lastEpoch := utils.GetFirstAndLastEpochForDay(uint64(day))
+	slotsPerEpoch := utils.Config.Chain.ClConfig.SlotsPerEpoch
+	firstSlot := firstEpoch * slotsPerEpoch
+	firstSlotOfNextDay := (lastEpoch + 1) * slotsPerEpoch
+	// votes can be included up to two epochs after their slot
+	upperSlot := firstSlotOfNextDay + 2*slotsPerEpoch
+
+	// head_roots holds the root of the canonical head and the first canonical block after every slot of the day, a vote
+	// is correct if it matches the head of its slot (head) or the head of the first slot of its target epoch (target)
+	votes := []struct {
+		ValidatorIndex uint64  `db:"validatorindex"`
+		Included       uint64  `db:"included"`
+		CorrectHead    uint64  `db:"correct_head"`
+		CorrectTarget  uint64  `db:"correct_target"`
+		InclusionSum   float64 `db:"inclusion_sum"`
+	}{}
+	err = ReaderDb.Select(&votes, `
+		with head_roots as (
+			select
+				s.slot,
+				(select b.blockroot from blocks b where b.slot <= s.slot and b.status = '1' order by b.slot desc limit 1) as root,
+				(select b.slot from blocks b where b.slot > s.slot and b.status = '1' order by b.slot limit 1) as next_block_slot
+			from generate_series($1::int, $2::int - 1) as s(slot)
+		), votes as (
+			select
+				a.slot as att_slot,
+				v.validatorindex,
+				min(a.block_slot) as first_inclusion,
+				bool_or(a.beaconblockroot = h.root) as correct_head,
+				bool_or(a.target_root = t.root) as correct_target
+			from blocks_attestations a
+			inner join blocks b on b.slot = a.block_slot and b.blockroot = a.block_root and b.status = '1'
+			inner join head_roots h on h.slot = a.slot
+			inner join head_roots t on t.slot = a.target_epoch * $3
+			cross join unnest(a.validators) as v(validatorindex)
+			where a.block_slot >= $1 and a.block_slot < $4 and a.slot >= $1 and a.slot < $2
+			group by a.slot, v.validatorindex
+		)
+		select
+			votes.validatorindex,
+			count(*) as included,
+			count(*) filter (where votes.correct_head) as correct_head,
+			count(*) filter (where votes.correct_target) as correct_target,
+			sum(coalesce(h.next_block_slot - votes.att_slot, 1)::float / (votes.first_inclusion - votes.att_slot)) as inclusion_sum
+		from votes
+		inner join head_roots h on h.slot = votes.att_slot
+		group by votes.validatorindex`, firstSlot, firstSlotOfNextDay, slotsPerEpoch, upperSlot)
+	if err != nil {
+		return fmt.Errorf("error getting attestation votes in WriteValidatorEffectivenessScoresForDay: %w", err)
+	}
+
+	duties := []struct {
+		ValidatorIndex       uint64 `db:"validatorindex"`
+		ExpectedAttestations uint64 `db:"expected_attestations"`
+		ProposedBlocks       uint64 `db:"proposed_blocks"`
+		MissedBlocks         uint64 `db:"missed_blocks"`
+		OrphanedBlocks       uint64 `db:"orphaned_blocks"`
+		ParticipatedSync     uint64 `db:"participated_sync"`
+		MissedSync           uint64 `db:"missed_sync"`
+		OrphanedSync         uint64 `db:"orphaned_sync"`
+	}{}
+	err = ReaderDb.Select(&duties, `
+		select
+			v.validatorindex,
+			least(v.exitepoch, $2 + 1) - greatest(v.activationepoch, $1) as expected_attestations,
+			coalesce(vs.proposed_blocks, 0) as proposed_blocks,
+			coalesce(vs.missed_blocks, 0) as missed_blocks,
+			coalesce(vs.orphaned_blocks, 0) as orphaned_blocks,
+			coalesce(vs.participated_sync, 0) as participated_sync,
+			coalesce(vs.missed_sync, 0) as missed_sync,
+			coalesce(vs.orphaned_sync, 0) as orphaned_sync
+		from validators v
+		left join validator_stats vs on vs.validatorindex = v.validatorindex and vs.day = $3
+		where v.activationepoch <= $2 and v.exitepoch > $1
+		order by v.validatorindex`, firstEpoch, lastEpoch, day)
+	if err != nil {
+		return fmt.Errorf("error getting validator duties in WriteValidatorEffectivenessScoresForDay: %w", err)
+	}
+
+	votesByValidator := make(map[uint64]int, len(votes))
+	for i, v := range votes {
+		votesByValidator[v.ValidatorIndex] = i
+	}
+
+	scores := make([]*types.ValidatorEffectivenessScore, 0, len(duties))
+	for _, d := range duties {
+		if d.ExpectedAttestations == 0 {
+			continue
+		}
+		score := &types.ValidatorEffectivenessScore{
+			ValidatorIndex: d.ValidatorIndex,
+			Day:            uint64(day),
+			Version:        utils.EffectivenessScoreVersion,
+		}
+
+		if i, ok := votesByValidator[d.ValidatorIndex]; ok && votes[i].Included > 0 {
+			v := votes[i]
+			score.AttestationCorrectness = float64(v.CorrectHead+v.CorrectTarget) / float64(2*d.ExpectedAttestations)
+			if score.AttestationCorrectness > 1 {
+				score.AttestationCorrectness = 1
+			}
+			inclusionDelay := v.InclusionSum / float64(v.Included)
+			score.InclusionDelay = &inclusionDelay
+		}
+		if proposals := d.ProposedBlocks + d.MissedBlocks + d.OrphanedBlocks; proposals > 0 {
+			proposal := float64(d.ProposedBlocks) / float64(proposals)
+			score.Proposal = &proposal
+		}
+		if syncDuties := d.ParticipatedSync + d.MissedSync + d.OrphanedSync; syncDuties > 0 {
+			sync := float64(d.ParticipatedSync) / float64(syncDuties)
+			score.Sync = &sync
+		}
+		score.Score = utils.EffectivenessScore(score)
+
+		scores = append(scores, score)
+	}
+
+	conn, err := WriterDb.Conn(context.Background())
+	if err != nil {
+		return fmt.Errorf("error retrieving raw sql connection: %w", err)
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		conn := driverConn.(*stdlib.Conn).Conn()
+
+		tx, err := conn.Begin(context.Background())
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(context.Background())
+
+		_, err = tx.Exec(context.Background(), "delete from validator_effectiveness where day = $1 and version = $2", day, utils.EffectivenessScoreVersion)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CopyFrom(context.Background(), pgx.Identifier{"validator_effectiveness"}, []string{
+			"validatorindex",
+			"day",
+			"version",
+			"score",
+			"attestation_correctness",
+			"inclusion_delay",
+			"proposal",
+			"sync",
+		}, pgx.CopyFromSlice(len(scores), func(i int) ([]interface{}, error) {
+			return []interface{}{
+				scores[i].ValidatorIndex,
+				scores[i].Day,
+				scores[i].Version,
+				scores[i].Score,
+				scores[i].AttestationCorrectness,
+				scores[i].InclusionDelay,
+				scores[i].Proposal,
+				scores[i].Sync,
+			}, nil
+		}))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(context.Background(), `
+			insert into validator_effectiveness_status (day, version, status)
+			values ($1, $2, true)
+			on conflict (day, version) do update set status = excluded.status`, day, utils.EffectivenessScoreVersion)
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit(context.Background())
+	})
+	if err != nil {
+		return fmt.Errorf("error writing effectiveness scores of day %v: %w", day, err)
+	}
+
+	logger.Infof("exported effectiveness scores of %v validators for day %v, took %v", len(scores), day, time.Since(exportStart))
+	return nil
+}
+
+// GetLatestEffectivenessScoreDay returns the latest day the effectiveness scores of the current formula version have
+// been exported for, ok is false if no day has been exported yet
+func GetLatestEffectivenessScoreDay() (day uint64, ok bool, err error) {
+	var latestDay sql.NullInt64
+	err = ReaderDb.Get(&latestDay, `select max(day) from validator_effectiveness_status where version = $1 and status`, utils.EffectivenessScoreVersion)
+	if err != nil {
+		return 0, false, fmt.Errorf("error getting latest effectiveness score day: %w", err)
+	}
+	if !latestDay.Valid {
+		return 0, false, nil
+	}
+	return uint64(latestDay.Int64), true, nil
+}
+
+// GetLatestValidatorEffectivenessScores returns the effectiveness scores of the given validators of the latest exported day
+func GetLatestValidatorEffectivenessScores(validators []uint64) (map[uint64]*types.ValidatorEffectivenessScore, error) {
+	res := make(map[uint64]*types.ValidatorEffectivenessScore, len(validators))
+
+	day, ok, err := GetLatestEffectivenessScoreDay()
+	if err != nil || !ok {
+		return res, err
+	}
+
+	scores := []*types.ValidatorEffectivenessScore{}
+	err = ReaderDb.Select(&scores, `
+		select validatorindex, day, version, score, attestation_correctness, inclusion_delay, proposal, sync
+		from validator_effectiveness
+		where validatorindex = any($1) and day = $2 and version = $3`, pq.Array(validators), day, utils.EffectivenessScoreVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error getting effectiveness scores of day %v: %w", day, err)
+	}
+	for _, score := range scores {
+		res[score.ValidatorIndex] = score
+	}
+	return res, nil
+}
+
+// GetValidatorEffectivenessScoreHistory returns the effectiveness scores of the given validators for the given number of
+// most recently exported days, ordered by validator and most recent day first
+func GetValidatorEffectivenessScoreHistory(validators []uint64, days uint64) ([]*types.ValidatorEffectivenessScore, error) {
+	scores := []*types.ValidatorEffectivenessScore{}
+
+	day, ok, err := GetLatestEffectivenessScoreDay()
+	if err != nil || !ok {
+		return scores, err
+	}
+	fromDay := int64(day) - int64(days) + 1
+
+	err = ReaderDb.Select(&scores, `
+		select validatorindex, day, version, score, attestation_correctness, inclusion_delay, proposal, sync
+		from validator_effectiveness
+		where validatorindex = any($1) and day >= $2 and day <= $3 and version = $4
+		order by validatorindex, day desc`, pq.Array(validators), fromDay, day, utils.EffectivenessScoreVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error getting effectiveness score history: %w", err)
+	}
+	return scores, nil
+}
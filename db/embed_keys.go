@@ -0,0 +1,70 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// AddUserEmbedKey stores the key of a signed embed url of a user
+func AddUserEmbedKey(userId uint64, key, path string, expiresAt time.Time) error {
+	_, err := FrontendWriterDB.Exec(`
+		INSERT INTO users_embed_keys (user_id, key, path, expires_at)
+		VALUES ($1, $2, $3, $4)`, userId, key, truncateString(path, 256), expiresAt.UTC())
+	if err != nil {
+		return fmt.Errorf("error inserting embed key of user %v: %w", userId, err)
+	}
+	return nil
+}
+
+// CountActiveUserEmbedKeys returns the number of embed keys of a user that are neither expired nor revoked
+func CountActiveUserEmbedKeys(userId uint64) (uint64, error) {
+	var count uint64
+	err := FrontendWriterDB.Get(&count, `
+		SELECT COUNT(*) FROM users_embed_keys
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()`, userId)
+	if err != nil {
+		return 0, fmt.Errorf("error counting embed keys of user %v: %w", userId, err)
+	}
+	return count, nil
+}
+
+// GetUserEmbedKeys returns the embed keys of a user that have not expired yet, newest first
+func GetUserEmbedKeys(userId uint64) ([]*types.UserEmbedKey, error) {
+	keys := []*types.UserEmbedKey{}
+	err := FrontendReaderDB.Select(&keys, `
+		SELECT key, path, created_at, expires_at, revoked_at
+		FROM users_embed_keys
+		WHERE user_id = $1 AND expires_at > NOW()
+		ORDER BY created_at DESC, id DESC`, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving embed keys of user %v: %w", userId, err)
+	}
+	return keys, nil
+}
+
+// RevokeUserEmbedKey revokes an embed key of a user, returns false if the user has no such key that is not yet revoked
+func RevokeUserEmbedKey(userId uint64, key string) (bool, error) {
+	res, err := FrontendWriterDB.Exec(`
+		UPDATE users_embed_keys SET revoked_at = NOW()
+		WHERE user_id = $1 AND key = $2 AND revoked_at IS NULL`, userId, key)
+	if err != nil {
+		return false, fmt.Errorf("error revoking embed key of user %v: %w", userId, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// IsEmbedKeyActive returns true if the embed key exists and has not been revoked
+func IsEmbedKeyActive(key string) (bool, error) {
+	var active bool
+	err := FrontendReaderDB.Get(&active, `SELECT EXISTS (SELECT 1 FROM users_embed_keys WHERE key = $1 AND revoked_at IS NULL)`, key)
+	if err != nil {
+		return false, fmt.Errorf("error checking embed key: %w", err)
+	}
+	return active, nil
+}
@@ -248,7 +248,7 @@ func (bigtable *Bigtable) TransformEnsNameRegistered(blk *types.Eth1Block, cache
 		}
 	}
 	for key := range keys {
-		mut := gcp_bigtable.NewMutation()
+		mut := types.NewMutation()
 		mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
 
 		bulkData.Keys = append(bulkData.Keys, key)
@@ -325,7 +325,7 @@ func (bigtable *Bigtable) ImportEnsUpdates(client *ethclient.Client, readBatchSi
 		name:    make(map[string]bool),
 	}
 
-	mutDelete := gcp_bigtable.NewMutation()
+	mutDelete := types.NewMutation()
 	mutDelete.DeleteRow()
 
 	batchSize := 100
@@ -342,7 +342,7 @@ func (bigtable *Bigtable) ImportEnsUpdates(client *ethclient.Client, readBatchSi
 		g.SetLimit(10) // limit load on the node
 		mutsDelete := &types.BulkMutations{
 			Keys: make([]string, 0, 1),
-			Muts: make([]*gcp_bigtable.Mutation, 0, 1),
+			Muts: make([]*types.Mutation, 0, 1),
 		}
 
 		for _, k := range batch {
@@ -632,7 +632,77 @@ func GetAddressForEnsName(name string) (address *common.Address, err error) {
 	return address, err
 }
 
+// GetEnsProfile returns the valid registration of an ens name together with the other valid names of its address, nil if the name is not registered
+func GetEnsProfile(name string) (*types.EnsProfile, error) {
+	profile := &types.EnsProfile{}
+	err := ReaderDb.Get(profile, `
+	SELECT ens_name, address, is_primary_name, valid_to
+	FROM ens
+	WHERE
+		ens_name = $1 AND
+		valid_to >= now()
+	;`, name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving ens registration of %v: %w", name, err)
+	}
+
+	if len(profile.Address) == 0 {
+		return profile, nil
+	}
+
+	err = ReaderDb.Select(&profile.OtherNames, `
+	SELECT ens_name
+	FROM ens
+	WHERE
+		address = $1 AND
+		ens_name != $2 AND
+		valid_to >= now()
+	ORDER BY is_primary_name DESC, ens_name
+	LIMIT 50
+	;`, profile.Address, name)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving other ens names of %v: %w", name, err)
+	}
+	return profile, nil
+}
+
+// GetEnsNamesByPrefix returns valid ens names starting with prefix, shorter names first
+func GetEnsNamesByPrefix(prefix string, limit uint64) ([]*types.EnsDomainResponse, error) {
+	names := []*types.EnsDomainResponse{}
+	// the wildcards of LIKE must match literally within the prefix
+	escapedPrefix := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix)
+	err := ReaderDb.Select(&names, `
+	SELECT ens_name AS domain, '0x' || ENCODE(address, 'hex') AS address
+	FROM ens
+	WHERE
+		ens_name LIKE ($1 || '%') ESCAPE '\' AND
+		address IS NOT NULL AND
+		valid_to >= now()
+	ORDER BY LENGTH(ens_name), ens_name
+	LIMIT $2
+	;`, escapedPrefix, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving ens names with prefix %v: %w", prefix, err)
+	}
+	return names, nil
+}
+
+// ensNameCache caches the primary ens name of addresses, addresses without a primary name are cached with an empty name
+var ensNameCache = freecache.NewCache(20 * 1024 * 1024) // 20 MB
+
+const ensNameCacheTTL = time.Minute * 10
+
 func GetEnsNameForAddress(address common.Address) (name string, err error) {
+	if cached, err := ensNameCache.Get(address.Bytes()); err == nil {
+		if len(cached) == 0 {
+			return "", sql.ErrNoRows
+		}
+		return string(cached), nil
+	}
+
 	err = ReaderDb.Get(&name, `
 	SELECT ens_name 
 	FROM ens
@@ -641,9 +711,13 @@ func GetEnsNameForAddress(address common.Address) (name string, err error) {
 		is_primary_name AND
 		valid_to >= now()
 	;`, address.Bytes())
+	if err == nil || err == sql.ErrNoRows {
+		ensNameCache.Set(address.Bytes(), []byte(name), int(ensNameCacheTTL.Seconds()))
+	}
 	return name, err
 }
 
+// GetEnsNamesForAddress resolves the primary ens names of the addresses in the map keys, names are served from ensNameCache where possible
 func GetEnsNamesForAddress(addressMap map[string]string) error {
 	if len(addressMap) == 0 {
 		return nil
@@ -655,8 +729,17 @@ func GetEnsNamesForAddress(addressMap map[string]string) error {
 	dbAddresses := []pair{}
 	addresses := make([][]byte, 0, len(addressMap))
 	for add := range addressMap {
+		if cached, err := ensNameCache.Get([]byte(add)); err == nil {
+			if len(cached) > 0 {
+				addressMap[add] = string(cached)
+			}
+			continue
+		}
 		addresses = append(addresses, []byte(add))
 	}
+	if len(addresses) == 0 {
+		return nil
+	}
 
 	err := ReaderDb.Select(&dbAddresses, `
 	SELECT address, ens_name 
@@ -669,8 +752,13 @@ func GetEnsNamesForAddress(addressMap map[string]string) error {
 	if err != nil {
 		return err
 	}
+	found := make(map[string]string, len(dbAddresses))
 	for _, foundling := range dbAddresses {
 		addressMap[string(foundling.Address)] = foundling.EnsName
+		found[string(foundling.Address)] = foundling.EnsName
+	}
+	for _, add := range addresses {
+		ensNameCache.Set(add, []byte(found[string(add)]), int(ensNameCacheTTL.Seconds()))
 	}
 	return nil
 }
@@ -0,0 +1,238 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/erc20"
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/coocood/freecache"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+var eth1AddressWatchlist = make(map[string]bool)
+var eth1AddressWatchlistMux = &sync.RWMutex{}
+
+// SetEth1AddressWatchlist replaces the set of execution layer addresses the eth1 indexer records events for
+func SetEth1AddressWatchlist(addresses [][]byte) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in SetEth1AddressWatchlist: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`delete from eth1_address_watchlist where not address = any($1)`, pq.ByteaArray(addresses))
+	if err != nil {
+		return fmt.Errorf("error deleting unwatched addresses in SetEth1AddressWatchlist: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		insert into eth1_address_watchlist (address)
+		select unnest($1::bytea[])
+		on conflict (address) do nothing`, pq.ByteaArray(addresses))
+	if err != nil {
+		return fmt.Errorf("error inserting watched addresses in SetEth1AddressWatchlist: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// UpdateEth1AddressWatchlistCache reloads the watched addresses used by TransformEth1AddressEvents from the db
+func UpdateEth1AddressWatchlistCache() error {
+	var addresses [][]byte
+	err := ReaderDb.Select(&addresses, `select address from eth1_address_watchlist`)
+	if err != nil {
+		return fmt.Errorf("error retrieving eth1 address watchlist: %w", err)
+	}
+
+	watchlist := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		watchlist[string(address)] = true
+	}
+
+	eth1AddressWatchlistMux.Lock()
+	eth1AddressWatchlist = watchlist
+	eth1AddressWatchlistMux.Unlock()
+	return nil
+}
+
+func isEth1AddressWatched(address []byte) bool {
+	if len(address) == 0 {
+		return false
+	}
+	eth1AddressWatchlistMux.RLock()
+	defer eth1AddressWatchlistMux.RUnlock()
+	return eth1AddressWatchlist[string(address)]
+}
+
+// TransformEth1AddressEvents accepts an eth1 block and records the transactions and erc20 transfers of watched addresses
+// in the eth1_address_events table, from where they are picked up by the notification collector.
+// It does not create any bigtable mutations.
+func (bigtable *Bigtable) TransformEth1AddressEvents(blk *types.Eth1Block, cache *freecache.Cache) (bulkData *types.BulkMutations, bulkMetadataUpdates *types.BulkMutations, err error) {
+	startTime := time.Now()
+	defer func() {
+		metrics.TaskDuration.WithLabelValues("bt_transform_eth1_address_events").Observe(time.Since(startTime).Seconds())
+	}()
+
+	bulkData = &types.BulkMutations{}
+	bulkMetadataUpdates = &types.BulkMutations{}
+
+	eth1AddressWatchlistMux.RLock()
+	watchlistEmpty := len(eth1AddressWatchlist) == 0
+	eth1AddressWatchlistMux.RUnlock()
+	if watchlistEmpty {
+		return bulkData, bulkMetadataUpdates, nil
+	}
+
+	ts := blk.GetTime().AsTime()
+	events := make([]*types.Eth1AddressEvent, 0)
+	addEvent := func(tx *types.Eth1Transaction, logIndex int64, address, counterparty, token []byte, value []byte, eventName types.EventName) {
+		events = append(events, &types.Eth1AddressEvent{
+			BlockNumber:  blk.GetNumber(),
+			Ts:           ts,
+			TxHash:       tx.GetHash(),
+			LogIndex:     logIndex,
+			Address:      address,
+			EventName:    eventName,
+			Counterparty: counterparty,
+			Token:        token,
+			Value:        decimal.NewFromBigInt(new(big.Int).SetBytes(value), 0),
+		})
+	}
+
+	for _, tx := range blk.GetTransactions() {
+		to := tx.GetTo()
+		if len(to) == 0 {
+			to = tx.GetContractAddress()
+		}
+
+		if isEth1AddressWatched(tx.GetFrom()) {
+			addEvent(tx, -1, tx.GetFrom(), to, nil, tx.GetValue(), types.EthAddressOutgoingTransactionEventName)
+			if len(tx.GetData()) > 0 {
+				addEvent(tx, -1, tx.GetFrom(), to, nil, tx.GetValue(), types.EthAddressContractInteractionEventName)
+			}
+		}
+
+		if isEth1AddressWatched(to) {
+			addEvent(tx, -1, to, tx.GetFrom(), nil, tx.GetValue(), types.EthAddressIncomingTransactionEventName)
+			if len(tx.GetData()) > 0 {
+				addEvent(tx, -1, to, tx.GetFrom(), nil, tx.GetValue(), types.EthAddressContractInteractionEventName)
+			}
+		}
+
+		if tx.GetStatus() != 1 {
+			continue
+		}
+
+		for j, log := range tx.GetLogs() {
+			if len(log.GetTopics()) != 3 || !bytes.Equal(log.GetTopics()[0], erc20.TransferTopic) {
+				continue
+			}
+
+			// topics 1 and 2 hold the left padded from and to addresses of the transfer
+			from := log.GetTopics()[1][12:]
+			to := log.GetTopics()[2][12:]
+
+			if isEth1AddressWatched(from) {
+				addEvent(tx, int64(j), from, to, log.GetAddress(), log.GetData(), types.EthAddressTokenTransferEventName)
+			}
+			if isEth1AddressWatched(to) {
+				addEvent(tx, int64(j), to, from, log.GetAddress(), log.GetData(), types.EthAddressTokenTransferEventName)
+			}
+		}
+	}
+
+	if len(events) == 0 {
+		return bulkData, bulkMetadataUpdates, nil
+	}
+
+	err = SaveEth1AddressEvents(events)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bulkData, bulkMetadataUpdates, nil
+}
+
+// SaveEth1AddressEvents stores the events of watched addresses, already stored events are ignored so blocks can be reindexed
+func SaveEth1AddressEvents(events []*types.Eth1AddressEvent) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in SaveEth1AddressEvents: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, event := range events {
+		_, err = tx.Exec(`
+			insert into eth1_address_events (block_number, ts, tx_hash, log_index, address, event_name, counterparty, token, value)
+			values ($1, to_timestamp($2) at time zone 'utc', $3, $4, $5, $6, $7, $8, $9)
+			on conflict (tx_hash, log_index, address, event_name) do nothing`,
+			event.BlockNumber, event.Ts.Unix(), event.TxHash, event.LogIndex, event.Address, event.EventName, event.Counterparty, event.Token, event.Value)
+		if err != nil {
+			return fmt.Errorf("error inserting eth1 address event of tx %x: %w", event.TxHash, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetEth1AddressEvents returns the events of watched addresses in blocks after afterBlock with a timestamp in [from, to)
+func GetEth1AddressEvents(afterBlock uint64, from, to time.Time) ([]*types.Eth1AddressEvent, error) {
+	events := []*types.Eth1AddressEvent{}
+	err := ReaderDb.Select(&events, `
+		select block_number, ts, tx_hash, log_index, address, event_name, counterparty, token, value
+		from eth1_address_events
+		where block_number > $1 and ts >= to_timestamp($2) at time zone 'utc' and ts < to_timestamp($3) at time zone 'utc'
+		order by block_number, tx_hash, log_index`, afterBlock, from.Unix(), to.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving eth1 address events: %w", err)
+	}
+	return events, nil
+}
+
+// GetLastNotifiedEth1AddressEventsBlock returns the last block whose events of watched addresses have been notified, 0 if
+// no events have been notified yet
+func GetLastNotifiedEth1AddressEventsBlock() (uint64, error) {
+	var blockNumber uint64
+	err := WriterDb.Get(&blockNumber, `select coalesce(max(block_number), 0) from eth1_address_events_notified`)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving last notified eth1 address events block: %w", err)
+	}
+	return blockNumber, nil
+}
+
+// SetLastNotifiedEth1AddressEventsBlock stores the last block whose events of watched addresses have been notified
+func SetLastNotifiedEth1AddressEventsBlock(blockNumber uint64) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in SetLastNotifiedEth1AddressEventsBlock: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`insert into eth1_address_events_notified (block_number, ts) values ($1, now()) on conflict (block_number) do nothing`, blockNumber)
+	if err != nil {
+		return fmt.Errorf("error inserting last notified eth1 address events block: %w", err)
+	}
+
+	_, err = tx.Exec(`delete from eth1_address_events_notified where block_number < $1`, blockNumber)
+	if err != nil {
+		return fmt.Errorf("error deleting previous notified eth1 address events blocks: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// DeleteEth1AddressEventsBefore removes all events of watched addresses older than ts
+func DeleteEth1AddressEventsBefore(ts time.Time) error {
+	_, err := WriterDb.Exec(`delete from eth1_address_events where ts < to_timestamp($1) at time zone 'utc'`, ts.Unix())
+	if err != nil {
+		return fmt.Errorf("error deleting eth1 address events: %w", err)
+	}
+	return nil
+}
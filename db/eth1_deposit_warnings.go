@@ -0,0 +1,82 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/lib/pq"
+)
+
+// SaveEth1DepositWarnings flags the deposits made from fromBlock on that most likely were a mistake: deposits with an
+// invalid signature that are not preceded by a valid deposit of the same public key (the beacon chain ignores them)
+// and deposits made after the validator of the public key was activated. Already flagged deposits are left untouched.
+func SaveEth1DepositWarnings(fromBlock uint64) (int64, error) {
+	secondsPerEpoch := utils.Config.Chain.ClConfig.SecondsPerSlot * utils.Config.Chain.ClConfig.SlotsPerEpoch
+	res, err := WriterDb.Exec(`
+		INSERT INTO eth1_deposit_warnings (tx_hash, merkletree_index, publickey, warning)
+		SELECT
+			d.tx_hash,
+			d.merkletree_index,
+			d.publickey,
+			CASE WHEN d.valid_signature THEN $2 ELSE $3 END
+		FROM eth1_deposits d
+		LEFT JOIN validators v ON v.pubkey = d.publickey
+		WHERE d.block_number >= $1 AND NOT d.removed AND (
+			(d.valid_signature AND v.activationepoch <= (EXTRACT(epoch FROM d.block_ts) - $4) / $5) OR
+			(NOT d.valid_signature AND NOT EXISTS (
+				SELECT 1 FROM eth1_deposits p
+				WHERE p.publickey = d.publickey AND p.valid_signature AND NOT p.removed AND (p.block_number, p.tx_index) < (d.block_number, d.tx_index)
+			))
+		)
+		ON CONFLICT (tx_hash, merkletree_index) DO NOTHING`,
+		fromBlock, types.Eth1DepositWarningActivePubkey, types.Eth1DepositWarningInvalidSignature,
+		utils.Config.Chain.GenesisTimestamp, secondsPerEpoch)
+	if err != nil {
+		return 0, fmt.Errorf("error saving eth1 deposit warnings from block %v: %w", fromBlock, err)
+	}
+	return res.RowsAffected()
+}
+
+const eth1DepositWarningsSelect = `
+	SELECT w.tx_hash, w.merkletree_index, w.publickey, v.validatorindex, d.amount, d.block_ts, w.warning, w.detected_ts
+	FROM eth1_deposit_warnings w
+	INNER JOIN eth1_deposits d ON d.tx_hash = w.tx_hash AND d.merkletree_index = w.merkletree_index AND NOT d.removed
+	LEFT JOIN validators v ON v.pubkey = w.publickey`
+
+// GetEth1DepositWarningsForTx returns the warnings of the deposits made in a transaction
+func GetEth1DepositWarningsForTx(txHash []byte) ([]*types.Eth1DepositWarning, error) {
+	warnings := []*types.Eth1DepositWarning{}
+	err := ReaderDb.Select(&warnings, eth1DepositWarningsSelect+`
+		WHERE w.tx_hash = $1
+		ORDER BY w.merkletree_index`, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving eth1 deposit warnings of tx %#x: %w", txHash, err)
+	}
+	return warnings, nil
+}
+
+// GetEth1DepositWarningsForPublicKeys returns the warnings of the deposits made to the given public keys
+func GetEth1DepositWarningsForPublicKeys(publicKeys [][]byte) ([]*types.Eth1DepositWarning, error) {
+	warnings := []*types.Eth1DepositWarning{}
+	err := ReaderDb.Select(&warnings, eth1DepositWarningsSelect+`
+		WHERE w.publickey = ANY($1)
+		ORDER BY d.block_ts DESC`, pq.ByteaArray(publicKeys))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving eth1 deposit warnings of %v public keys: %w", len(publicKeys), err)
+	}
+	return warnings, nil
+}
+
+// GetEth1DepositWarningsDetectedBetween returns the deposit warnings that were flagged by the exporter in the given time range
+func GetEth1DepositWarningsDetectedBetween(from, to time.Time) ([]*types.Eth1DepositWarning, error) {
+	warnings := []*types.Eth1DepositWarning{}
+	err := ReaderDb.Select(&warnings, eth1DepositWarningsSelect+`
+		WHERE w.detected_ts >= $1 AND w.detected_ts < $2`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving eth1 deposit warnings detected between %v and %v: %w", from, to, err)
+	}
+	return warnings, nil
+}
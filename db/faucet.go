@@ -0,0 +1,66 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// LockFaucet serializes the faucet requests of all frontend instances until the transaction ends, so that the funding
+// transactions use consecutive nonces and the cooldowns can not be bypassed by concurrent requests
+func LockFaucet(tx *sqlx.Tx) error {
+	_, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('faucet_requests'))`)
+	if err != nil {
+		return fmt.Errorf("error locking faucet: %w", err)
+	}
+	return nil
+}
+
+// HasRecentFaucetRequest returns whether the address or the ip received funds from the faucet after since
+func HasRecentFaucetRequest(tx *sqlx.Tx, address []byte, ip string, since time.Time) (bool, error) {
+	var exists bool
+	err := tx.Get(&exists, `
+		SELECT EXISTS (
+			SELECT 1 FROM faucet_requests WHERE (address = $1 OR ip = $2) AND created_ts > $3
+		)`, address, ip, since)
+	if err != nil {
+		return false, fmt.Errorf("error checking recent faucet requests: %w", err)
+	}
+	return exists, nil
+}
+
+// SaveFaucetRequest stores a funding transaction of the faucet, it is committed right away so the cooldown of the
+// address and the ip applies even if the transaction holding the faucet lock fails
+func SaveFaucetRequest(address []byte, ip string, amountWei string, txHash []byte) error {
+	_, err := WriterDb.Exec(`INSERT INTO faucet_requests (address, ip, amount, tx_hash) VALUES ($1, $2, $3, $4)`, address, ip, amountWei, txHash)
+	if err != nil {
+		return fmt.Errorf("error saving faucet request: %w", err)
+	}
+	return nil
+}
+
+// DeleteFaucetRequest removes the request of a funding transaction that could not be sent
+func DeleteFaucetRequest(txHash []byte) error {
+	_, err := WriterDb.Exec(`DELETE FROM faucet_requests WHERE tx_hash = $1`, txHash)
+	if err != nil {
+		return fmt.Errorf("error deleting faucet request: %w", err)
+	}
+	return nil
+}
+
+// GetRecentFaucetRequests returns the latest funding transactions of the faucet
+func GetRecentFaucetRequests(limit uint64) ([]*types.FaucetRequest, error) {
+	requests := []*types.FaucetRequest{}
+	err := ReaderDb.Select(&requests, `
+		SELECT address, (amount / 1e18)::FLOAT AS amount, tx_hash, created_ts
+		FROM faucet_requests
+		ORDER BY id DESC
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving recent faucet requests: %w", err)
+	}
+	return requests, nil
+}
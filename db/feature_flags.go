@@ -0,0 +1,46 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// GetFeatureFlags returns all feature flags ordered by name
+func GetFeatureFlags() ([]*types.FeatureFlag, error) {
+	flags := []*types.FeatureFlag{}
+	err := WriterDb.Select(&flags, `
+		SELECT name, enabled, rollout_percentage, description, updated_ts
+		FROM feature_flags
+		ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving feature flags: %w", err)
+	}
+	return flags, nil
+}
+
+// SaveFeatureFlag creates or updates the feature flag
+func SaveFeatureFlag(flag *types.FeatureFlag) error {
+	_, err := WriterDb.Exec(`
+		INSERT INTO feature_flags (name, enabled, rollout_percentage, description, updated_ts)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (name) DO UPDATE SET
+			enabled = excluded.enabled,
+			rollout_percentage = excluded.rollout_percentage,
+			description = excluded.description,
+			updated_ts = excluded.updated_ts`,
+		flag.Name, flag.Enabled, flag.RolloutPercentage, flag.Description)
+	if err != nil {
+		return fmt.Errorf("error saving feature flag %v: %w", flag.Name, err)
+	}
+	return nil
+}
+
+// DeleteFeatureFlag removes the feature flag
+func DeleteFeatureFlag(name string) error {
+	_, err := WriterDb.Exec(`DELETE FROM feature_flags WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("error deleting feature flag %v: %w", name, err)
+	}
+	return nil
+}
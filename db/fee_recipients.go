@@ -0,0 +1,118 @@
+package db
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/lib/pq"
+)
+
+// blockFeeRecipientQuery selects the fee recipient that was paid by the proposed blocks. For blocks built by a relay the
+// execution payload pays the builder, so the fee recipient the proposer registered with the relay is used instead.
+const blockFeeRecipientQuery = `
+	SELECT
+		b.proposer,
+		b.slot,
+		COALESCE(b.exec_block_number, 0) AS exec_block_number,
+		COALESCE(rb.proposer_fee_recipient, b.exec_fee_recipient) AS fee_recipient,
+		rb.proposer_fee_recipient IS NOT NULL AS relayed
+	FROM blocks b
+	LEFT JOIN LATERAL (
+		SELECT proposer_fee_recipient FROM relays_blocks WHERE exec_block_hash = b.exec_block_hash LIMIT 1
+	) rb ON true`
+
+// GetBlockFeeRecipients returns the fee recipients of the blocks proposed in the epoch
+func GetBlockFeeRecipients(epoch uint64) ([]*types.BlockFeeRecipient, error) {
+	feeRecipients := []*types.BlockFeeRecipient{}
+	err := WriterDb.Select(&feeRecipients, blockFeeRecipientQuery+`
+		WHERE b.epoch = $1 AND b.status = '1' AND b.exec_fee_recipient IS NOT NULL
+		ORDER BY b.slot`, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving block fee recipients of epoch %d: %w", epoch, err)
+	}
+	return feeRecipients, nil
+}
+
+// GetValidatorsBlockFeeRecipients returns the fee recipients of the latest blocks proposed by the validators, newest first
+func GetValidatorsBlockFeeRecipients(validators []uint64, limit uint64) ([]*types.BlockFeeRecipient, error) {
+	feeRecipients := []*types.BlockFeeRecipient{}
+	err := ReaderDb.Select(&feeRecipients, blockFeeRecipientQuery+`
+		WHERE b.proposer = ANY($1) AND b.status = '1' AND b.exec_fee_recipient IS NOT NULL
+		ORDER BY b.slot DESC
+		LIMIT $2`, pq.Array(validators), limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving block fee recipients of validators: %w", err)
+	}
+	return feeRecipients, nil
+}
+
+// GetUserExpectedFeeRecipients returns the fee recipients the user expects for its validators on the current network
+func GetUserExpectedFeeRecipients(userID uint64) ([]*types.ExpectedFeeRecipient, error) {
+	feeRecipients := []*types.ExpectedFeeRecipient{}
+	err := FrontendWriterDB.Select(&feeRecipients, `
+		SELECT validator_publickey, fee_recipient, created_ts
+		FROM users_validators_fee_recipients
+		WHERE user_id = $1 AND network = $2
+		ORDER BY created_ts`, userID, utils.GetNetwork())
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving expected fee recipients of user %d: %w", userID, err)
+	}
+	return feeRecipients, nil
+}
+
+// SetUserExpectedFeeRecipient sets the fee recipient the user expects for the validators, replacing a previously set one
+func SetUserExpectedFeeRecipient(userID uint64, pubkeys [][]byte, feeRecipient []byte) error {
+	_, err := FrontendWriterDB.Exec(`
+		INSERT INTO users_validators_fee_recipients (user_id, network, validator_publickey, fee_recipient)
+		SELECT $1, $2, UNNEST($3::bytea[]), $4
+		ON CONFLICT (user_id, network, validator_publickey) DO UPDATE SET
+			fee_recipient = excluded.fee_recipient,
+			created_ts = NOW() AT TIME ZONE 'utc'`,
+		userID, utils.GetNetwork(), pq.ByteaArray(pubkeys), feeRecipient)
+	if err != nil {
+		return fmt.Errorf("error setting expected fee recipient of user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// DeleteUserExpectedFeeRecipient removes the expected fee recipient of the validators of the user
+func DeleteUserExpectedFeeRecipient(userID uint64, pubkeys [][]byte) error {
+	_, err := FrontendWriterDB.Exec(`
+		DELETE FROM users_validators_fee_recipients
+		WHERE user_id = $1 AND network = $2 AND validator_publickey = ANY($3)`,
+		userID, utils.GetNetwork(), pq.ByteaArray(pubkeys))
+	if err != nil {
+		return fmt.Errorf("error deleting expected fee recipient of user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// GetExpectedFeeRecipientsForValidators returns the expected fee recipients of the validators on the current network by
+// user id and hex encoded pubkey
+func GetExpectedFeeRecipientsForValidators(pubkeys [][]byte) (map[uint64]map[string][]byte, error) {
+	rows := []struct {
+		UserID             uint64 `db:"user_id"`
+		ValidatorPublickey []byte `db:"validator_publickey"`
+		FeeRecipient       []byte `db:"fee_recipient"`
+	}{}
+	err := FrontendWriterDB.Select(&rows, `
+		SELECT user_id, validator_publickey, fee_recipient
+		FROM users_validators_fee_recipients
+		WHERE network = $1 AND validator_publickey = ANY($2)`,
+		utils.GetNetwork(), pq.ByteaArray(pubkeys))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving expected fee recipients of validators: %w", err)
+	}
+
+	feeRecipients := make(map[uint64]map[string][]byte, len(rows))
+	for _, row := range rows {
+		if feeRecipients[row.UserID] == nil {
+			feeRecipients[row.UserID] = make(map[string][]byte)
+		}
+		feeRecipients[row.UserID][hex.EncodeToString(row.ValidatorPublickey)] = row.FeeRecipient
+	}
+	return feeRecipients, nil
+}
@@ -0,0 +1,27 @@
+package db
+
+import (
+	"fmt"
+)
+
+// GetBlockGraffitiCounts returns the number of proposed blocks since fromSlot per graffiti matching the regular expression
+func GetBlockGraffitiCounts(fromSlot uint64, pattern string) (map[string]uint64, error) {
+	rows := []struct {
+		Graffiti string `db:"graffiti_text"`
+		Count    uint64 `db:"count"`
+	}{}
+	err := ReaderDb.Select(&rows, `
+		SELECT graffiti_text, COUNT(*) AS count
+		FROM blocks
+		WHERE slot >= $1 AND status = '1' AND graffiti_text ~* $2
+		GROUP BY graffiti_text`, fromSlot, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving graffiti counts of blocks since slot %v: %w", fromSlot, err)
+	}
+
+	counts := make(map[string]uint64, len(rows))
+	for _, row := range rows {
+		counts[row.Graffiti] = row.Count
+	}
+	return counts, nil
+}
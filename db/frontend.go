@@ -55,6 +55,39 @@ func GetUserEmailsByIds(ids []uint64) (map[uint64]string, error) {
 	return mailsByID, nil
 }
 
+// GetUserLocalesByIds returns the locales the emails of the users are sent in.
+func GetUserLocalesByIds(ids []uint64) (map[uint64]string, error) {
+	localesByID := map[uint64]string{}
+	if len(ids) == 0 {
+		return localesByID, nil
+	}
+	var rows []struct {
+		ID     uint64 `db:"id"`
+		Locale string `db:"locale"`
+	}
+	err := FrontendWriterDB.Select(&rows, "SELECT id, locale FROM users WHERE id = ANY($1)", pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		localesByID[r.ID] = r.Locale
+	}
+	return localesByID, nil
+}
+
+// GetUserLocale returns the locale the emails of the user are sent in.
+func GetUserLocale(userID uint64) (string, error) {
+	var locale string
+	err := FrontendWriterDB.Get(&locale, "SELECT locale FROM users WHERE id = $1", userID)
+	return locale, err
+}
+
+// SetUserLocale sets the locale the emails of the user are sent in.
+func SetUserLocale(userID uint64, locale string) error {
+	_, err := FrontendWriterDB.Exec("UPDATE users SET locale = $1 WHERE id = $2", locale, userID)
+	return err
+}
+
 // DeleteUserByEmail deletes a user.
 func DeleteUserByEmail(email string) error {
 	_, err := FrontendWriterDB.Exec("DELETE FROM users WHERE email = $1", email)
@@ -122,22 +155,23 @@ func UpdatePassword(userId uint64, cleartextPassword string) error {
 	return err
 }
 
-// AddAuthorizeCode registers a code that can be used in exchange for an access token
-func AddAuthorizeCode(userId uint64, code, clientId string, appId uint64) error {
+// AddAuthorizeCode registers a code that can be used in exchange for an access token, scope and codeChallenge are
+// optional and stored as NULL if empty
+func AddAuthorizeCode(userId uint64, code, clientId string, appId uint64, scope, codeChallenge string) error {
 	var dbClientID = clientId
 	if len(dbClientID) <= 5 { // remain backwards compatible
 		dbClientID = code
 	}
 	now := time.Now()
 	nowTs := now.Unix()
-	_, err := FrontendWriterDB.Exec("INSERT INTO oauth_codes (user_id, code, app_id, created_ts, client_id) VALUES($1, $2, $3, TO_TIMESTAMP($4), $5) ON CONFLICT (user_id, app_id, client_id) DO UPDATE SET code = $2, created_ts = TO_TIMESTAMP($4), consumed = false", userId, code, appId, nowTs, dbClientID)
+	_, err := FrontendWriterDB.Exec("INSERT INTO oauth_codes (user_id, code, app_id, created_ts, client_id, scope, code_challenge) VALUES($1, $2, $3, TO_TIMESTAMP($4), $5, NULLIF($6, ''), NULLIF($7, '')) ON CONFLICT (user_id, app_id, client_id) DO UPDATE SET code = $2, created_ts = TO_TIMESTAMP($4), consumed = false, scope = NULLIF($6, ''), code_challenge = NULLIF($7, '')", userId, code, appId, nowTs, dbClientID, scope, codeChallenge)
 	return err
 }
 
 // GetAppNameFromRedirectUri receives an oauth redirect_url and returns the registered app name, if exists
 func GetAppDataFromRedirectUri(callback string) (*types.OAuthAppData, error) {
 	data := []*types.OAuthAppData{}
-	err := FrontendWriterDB.Select(&data, "SELECT id, app_name, redirect_uri, active, owner_id FROM oauth_apps WHERE active = true AND redirect_uri = $1", callback)
+	err := FrontendWriterDB.Select(&data, "SELECT id, app_name, redirect_uri, active, owner_id, first_party FROM oauth_apps WHERE active = true AND redirect_uri = $1", callback)
 	if err != nil {
 		return nil, err
 	}
@@ -191,7 +225,7 @@ func GetUserAuthDataByAuthorizationCode(code string) (*types.OAuthCodeData, erro
 	var rows []*types.OAuthCodeData
 	err := FrontendWriterDB.Select(&rows, "UPDATE oauth_codes SET consumed = true WHERE code = $1 AND "+
 		"consumed = false AND created_ts + INTERVAL '35 minutes' > NOW() "+
-		"RETURNING user_id, app_id;", code)
+		"RETURNING user_id, app_id, COALESCE(scope, '') AS scope, COALESCE(code_challenge, '') AS code_challenge;", code)
 
 	if err != nil {
 		return nil, err
@@ -206,18 +240,46 @@ func GetUserAuthDataByAuthorizationCode(code string) (*types.OAuthCodeData, erro
 	return nil, errors.New("no rows found")
 }
 
-// GetByRefreshToken basically used to confirm the claimed user id with the refresh token. Returns the userId if successful
-func GetByRefreshToken(claimUserID, claimAppID, claimDeviceID uint64, hashedRefreshToken string) (uint64, error) {
-	var userID uint64
-	err := FrontendWriterDB.Get(&userID,
-		"SELECT user_id FROM users_devices WHERE user_id = $1 AND "+
+// GetByRefreshToken basically used to confirm the claimed user id with the refresh token. Returns the userId and the
+// granted scope (empty for full access) if successful
+func GetByRefreshToken(claimUserID, claimAppID, claimDeviceID uint64, hashedRefreshToken string) (uint64, string, error) {
+	device := struct {
+		UserID uint64 `db:"user_id"`
+		Scope  string `db:"scope"`
+	}{}
+	err := FrontendWriterDB.Get(&device,
+		"SELECT user_id, COALESCE(scope, '') AS scope FROM users_devices WHERE user_id = $1 AND "+
 			"refresh_token = $2 AND app_id = $3 AND id = $4 AND active = true", claimUserID, hashedRefreshToken, claimAppID, claimDeviceID)
 
 	if err != nil {
-		return 0, err
+		return 0, "", err
+	}
+
+	return device.UserID, device.Scope, nil
+}
+
+// IsUserDeviceActive returns true if the device (the grant of an oauth app) of the user has not been removed or deactivated
+func IsUserDeviceActive(userID, deviceID uint64) (bool, error) {
+	var active bool
+	err := FrontendReaderDB.Get(&active, "SELECT EXISTS (SELECT 1 FROM users_devices WHERE user_id = $1 AND id = $2 AND active = true)", userID, deviceID)
+	if err != nil {
+		return false, fmt.Errorf("error checking device %v of user %v: %w", deviceID, userID, err)
 	}
+	return active, nil
+}
 
-	return userID, nil
+// DeleteUserDeviceByRefreshToken removes the device with the given refresh token and returns its user, returns 0 if
+// no device has the refresh token
+func DeleteUserDeviceByRefreshToken(hashedRefreshToken string) (uint64, error) {
+	userIDs := []uint64{}
+	err := FrontendWriterDB.Select(&userIDs, "DELETE FROM users_devices WHERE refresh_token = $1 AND id != 2 RETURNING user_id", hashedRefreshToken)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting device by refresh token: %w", err)
+	}
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+	return userIDs[0], nil
 }
 
 func GetUserMonitorSharingSetting(userID uint64) (bool, error) {
@@ -248,7 +310,7 @@ func GetUserDevicesByUserID(userID uint64) ([]types.PairedDevice, error) {
 
 	rows, err := FrontendWriterDB.Query(
 		"SELECT users_devices.id, oauth_apps.app_name, users_devices.device_name, users_devices.active, "+
-			"users_devices.notify_enabled, users_devices.created_ts FROM users_devices "+
+			"users_devices.notify_enabled, COALESCE(users_devices.scope, ''), users_devices.created_ts FROM users_devices "+
 			"left join oauth_apps on users_devices.app_id = oauth_apps.id WHERE users_devices.user_id = $1 order by created_ts desc", userID)
 
 	if err != nil {
@@ -259,7 +321,7 @@ func GetUserDevicesByUserID(userID uint64) ([]types.PairedDevice, error) {
 
 	for rows.Next() {
 		pairedDevice := types.PairedDevice{}
-		if err := rows.Scan(&pairedDevice.ID, &pairedDevice.AppName, &pairedDevice.DeviceName, &pairedDevice.Active, &pairedDevice.NotifyEnabled, &pairedDevice.CreatedAt); err != nil {
+		if err := rows.Scan(&pairedDevice.ID, &pairedDevice.AppName, &pairedDevice.DeviceName, &pairedDevice.Active, &pairedDevice.NotifyEnabled, &pairedDevice.Scope, &pairedDevice.CreatedAt); err != nil {
 			return nil, err
 		}
 
@@ -272,11 +334,11 @@ func GetUserDevicesByUserID(userID uint64) ([]types.PairedDevice, error) {
 	return nil, nil
 }
 
-// InsertUserDevice Insert user device and return device id
-func InsertUserDevice(userID uint64, hashedRefreshToken string, name string, appID uint64) (uint64, error) {
+// InsertUserDevice Insert user device and return device id, an empty scope grants full access
+func InsertUserDevice(userID uint64, hashedRefreshToken string, name string, appID uint64, scope string) (uint64, error) {
 	var deviceID uint64
-	err := FrontendWriterDB.Get(&deviceID, "INSERT INTO users_devices (user_id, refresh_token, device_name, app_id, created_ts) VALUES($1, $2, $3, $4, 'NOW()') RETURNING id",
-		userID, hashedRefreshToken, name, appID,
+	err := FrontendWriterDB.Get(&deviceID, "INSERT INTO users_devices (user_id, refresh_token, device_name, app_id, created_ts, scope) VALUES($1, $2, $3, $4, 'NOW()', NULLIF($5, '')) RETURNING id",
+		userID, hashedRefreshToken, name, appID, scope,
 	)
 
 	if err != nil {
@@ -286,13 +348,62 @@ func InsertUserDevice(userID uint64, hashedRefreshToken string, name string, app
 	return deviceID, nil
 }
 
-func MobileNotificatonTokenUpdate(userID, deviceID uint64, notifyToken string) error {
-	_, err := FrontendWriterDB.Exec("UPDATE users_devices SET notification_token = $1 WHERE user_id = $2 AND id = $3;",
-		notifyToken, userID, deviceID,
+func MobileNotificatonTokenUpdate(userID, deviceID uint64, notifyToken string, capabilities []string) error {
+	_, err := FrontendWriterDB.Exec("UPDATE users_devices SET notification_token = $1, capabilities = $4 WHERE user_id = $2 AND id = $3;",
+		notifyToken, userID, deviceID, pq.StringArray(capabilities),
 	)
 	return err
 }
 
+// MuteUserSubscription mutes the push notifications of a subscription of the user until the given time, returns false if
+// the subscription does not exist or belongs to another user
+func MuteUserSubscription(userID, subscriptionID uint64, until time.Time) (bool, error) {
+	res, err := FrontendWriterDB.Exec("UPDATE users_subscriptions SET muted_until = $3 WHERE id = $1 AND user_id = $2", subscriptionID, userID, until)
+	if err != nil {
+		return false, fmt.Errorf("error muting subscription %v: %w", subscriptionID, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetMutedSubscriptionIDs returns the ids of the currently muted subscriptions of the given users
+func GetMutedSubscriptionIDs(userIDs []uint64) (map[uint64]bool, error) {
+	ids := []uint64{}
+	err := FrontendWriterDB.Select(&ids, "SELECT id FROM users_subscriptions WHERE user_id = ANY($1) AND muted_until > NOW()", pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving muted subscriptions: %w", err)
+	}
+	muted := make(map[uint64]bool, len(ids))
+	for _, id := range ids {
+		muted[id] = true
+	}
+	return muted, nil
+}
+
+// GetSubscriptionChannels returns the channels of the given subscriptions, subscriptions sent on all channels of the
+// user are omitted
+func GetSubscriptionChannels(subscriptionIDs []uint64) (map[uint64][]types.NotificationChannel, error) {
+	rows := []struct {
+		ID       uint64         `db:"id"`
+		Channels pq.StringArray `db:"channels"`
+	}{}
+	err := FrontendWriterDB.Select(&rows, "SELECT id, channels::TEXT[] AS channels FROM users_subscriptions WHERE id = ANY($1) AND channels IS NOT NULL", pq.Array(subscriptionIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving subscription channels: %w", err)
+	}
+	channels := make(map[uint64][]types.NotificationChannel, len(rows))
+	for _, row := range rows {
+		channels[row.ID] = make([]types.NotificationChannel, 0, len(row.Channels))
+		for _, ch := range row.Channels {
+			channels[row.ID] = append(channels[row.ID], types.NotificationChannel(ch))
+		}
+	}
+	return channels, nil
+}
+
 // AddSubscription adds a new subscription to the database.
 func AddSubscription(userID uint64, network string, eventName types.EventName, eventFilter string, eventThreshold float64) error {
 	now := time.Now()
@@ -581,27 +692,22 @@ func SetSubscriptionToExpired(tx *sql.Tx, id uint64) error {
 	return err
 }
 
-func GetUserPushTokenByIds(ids []uint64) (map[uint64][]string, error) {
-	pushByID := map[uint64][]string{}
+func GetUserPushTokenByIds(ids []uint64) (map[uint64][]*types.PushDevice, error) {
+	pushByID := map[uint64][]*types.PushDevice{}
 	if len(ids) == 0 {
 		return pushByID, nil
 	}
 	var rows []struct {
-		ID    uint64 `db:"user_id"`
-		Token string `db:"notification_token"`
+		ID uint64 `db:"user_id"`
+		types.PushDevice
 	}
 
-	err := FrontendWriterDB.Select(&rows, "SELECT DISTINCT ON (user_id, notification_token) user_id, notification_token FROM users_devices WHERE (user_id = ANY($1) AND user_id NOT IN (SELECT user_id from users_notification_channels WHERE active = false and channel = $2)) AND notify_enabled = true AND active = true AND notification_token IS NOT NULL AND LENGTH(notification_token) > 20 ORDER BY user_id, notification_token, id DESC", pq.Array(ids), types.PushNotificationChannel)
+	err := FrontendWriterDB.Select(&rows, "SELECT DISTINCT ON (user_id, notification_token) user_id, notification_token, capabilities FROM users_devices WHERE (user_id = ANY($1) AND user_id NOT IN (SELECT user_id from users_notification_channels WHERE active = false and channel = $2)) AND notify_enabled = true AND active = true AND notification_token IS NOT NULL AND LENGTH(notification_token) > 20 ORDER BY user_id, notification_token, id DESC", pq.Array(ids), types.PushNotificationChannel)
 	if err != nil {
 		return nil, err
 	}
-	for _, r := range rows {
-		val, ok := pushByID[r.ID]
-		if ok {
-			pushByID[r.ID] = append(val, r.Token)
-		} else {
-			pushByID[r.ID] = []string{r.Token}
-		}
+	for i := range rows {
+		pushByID[rows[i].ID] = append(pushByID[rows[i].ID], &rows[i].PushDevice)
 	}
 
 	return pushByID, nil
@@ -678,6 +784,9 @@ func getMachineStatsGap(resultCount uint64) int {
 	return 1
 }
 
+// HistoricalPriceCurrencies are the currencies stored in the price table
+var HistoricalPriceCurrencies = []string{"eur", "usd", "rub", "cny", "cad", "jpy", "gbp", "aud", "chf", "inr", "brl", "krw", "hkd", "sgd", "btc"}
+
 func GetHistoricalPrice(chainId uint64, currency string, day uint64) (float64, error) {
 	if chainId != 1 && chainId != 100 {
 		// Don't show a historical price for testnets
@@ -688,20 +797,33 @@ func GetHistoricalPrice(chainId uint64, currency string, day uint64) (float64, e
 	}
 	currency = strings.ToLower(currency)
 
-	if currency != "eur" && currency != "usd" && currency != "rub" && currency != "cny" && currency != "cad" && currency != "jpy" && currency != "gbp" && currency != "aud" {
+	if !utils.SliceContains(HistoricalPriceCurrencies, currency) {
 		return 0.0, fmt.Errorf("currency %v not supported", currency)
 	}
 
+	cacheKey := fmt.Sprintf("%d:historicalPrice:%s:%d", chainId, currency, day)
+	var value float64
+	if _, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, time.Hour, &value); err == nil {
+		return value, nil
+	}
+
 	// Convert day to ts
 	genesisTime := time.Unix(int64(utils.Config.Chain.GenesisTimestamp), 0)
 	dayStartGenesisTime := time.Date(genesisTime.Year(), genesisTime.Month(), genesisTime.Day(), 0, 0, 0, 0, time.UTC)
 	ts := dayStartGenesisTime.Add(utils.Day * time.Duration(day))
 
-	var value float64
-	err := ReaderDb.Get(&value, fmt.Sprintf("SELECT %s FROM price WHERE ts = $1", currency), ts)
+	err := ReaderDb.Get(&value, fmt.Sprintf("SELECT COALESCE(%s, 0) FROM price WHERE ts = $1", currency), ts)
 	if err != nil {
 		return 0.0, err
 	}
+
+	// prices of the current day are still updated
+	if ts.Add(utils.Day).Before(time.Now()) && value > 0 {
+		err = cache.TieredCache.Set(cacheKey, value, time.Hour*24*7)
+		if err != nil {
+			logger.Errorf("error caching historical price: %v", err)
+		}
+	}
 	return value, nil
 }
 
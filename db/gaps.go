@@ -0,0 +1,95 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// GapKindSlot is a slot missing in the blocks table
+	GapKindSlot = "slot"
+	// GapKindEpoch is an epoch missing in the epochs table
+	GapKindEpoch = "epoch"
+	// GapKindEth1Block is an execution block missing in the bigtable blocks table
+	GapKindEth1Block = "eth1_block"
+	// GapKindEth1Data is an execution block missing in the bigtable data table
+	GapKindEth1Data = "eth1_data"
+)
+
+// GetMissingSlots returns the slots missing in the blocks table within the last lookback slots of the table
+func GetMissingSlots(lookback uint64) ([]uint64, error) {
+	missing := []uint64{}
+	err := WriterDb.Select(&missing, `
+		WITH last AS (SELECT MAX(slot) AS slot FROM blocks)
+		SELECT s FROM last, generate_series(GREATEST(last.slot - $1, 0), last.slot) s
+		WHERE NOT EXISTS (SELECT 1 FROM blocks WHERE blocks.slot = s)
+		ORDER BY s`, lookback)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving missing slots: %w", err)
+	}
+	return missing, nil
+}
+
+// GetMissingEpochs returns the epochs missing in the epochs table within the last lookback epochs of the table
+func GetMissingEpochs(lookback uint64) ([]uint64, error) {
+	missing := []uint64{}
+	err := WriterDb.Select(&missing, `
+		WITH last AS (SELECT MAX(epoch) AS epoch FROM epochs)
+		SELECT e FROM last, generate_series(GREATEST(last.epoch - $1, 0), last.epoch) e
+		WHERE NOT EXISTS (SELECT 1 FROM epochs WHERE epochs.epoch = e)
+		ORDER BY e`, lookback)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving missing epochs: %w", err)
+	}
+	return missing, nil
+}
+
+// QueueExportGaps queues the numbers of the kind for re-export, gaps that are already queued are kept as they are
+func QueueExportGaps(kind string, numbers []uint64) error {
+	if len(numbers) == 0 {
+		return nil
+	}
+	_, err := WriterDb.Exec(`
+		INSERT INTO export_gaps (kind, number)
+		SELECT $1, UNNEST($2::bigint[])
+		ON CONFLICT (kind, number) DO NOTHING`, kind, pq.Array(numbers))
+	if err != nil {
+		return fmt.Errorf("error queueing %v gaps: %w", kind, err)
+	}
+	return nil
+}
+
+// GetExportGaps returns the queued gaps of the kinds ordered by number, all kinds if none are given
+func GetExportGaps(kinds ...string) ([]*types.ExportGap, error) {
+	gaps := []*types.ExportGap{}
+	err := WriterDb.Select(&gaps, `
+		SELECT kind, number, detected_ts, attempts, last_error
+		FROM export_gaps
+		WHERE CARDINALITY($1::text[]) = 0 OR kind = ANY($1)
+		ORDER BY kind, number`, pq.Array(kinds))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving export gaps: %w", err)
+	}
+	return gaps, nil
+}
+
+// ResolveExportGap removes a gap that has been re-exported from the queue
+func ResolveExportGap(kind string, number uint64) error {
+	_, err := WriterDb.Exec(`DELETE FROM export_gaps WHERE kind = $1 AND number = $2`, kind, number)
+	if err != nil {
+		return fmt.Errorf("error resolving %v gap %v: %w", kind, number, err)
+	}
+	return nil
+}
+
+// FailExportGap records a failed re-export attempt of a gap
+func FailExportGap(kind string, number uint64, exportErr error) error {
+	_, err := WriterDb.Exec(`UPDATE export_gaps SET attempts = attempts + 1, last_error = $3 WHERE kind = $1 AND number = $2`, kind, number, exportErr.Error())
+	if err != nil {
+		return fmt.Errorf("error updating %v gap %v: %w", kind, number, err)
+	}
+	return nil
+}
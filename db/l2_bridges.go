@@ -0,0 +1,410 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	gcp_bigtable "cloud.google.com/go/bigtable"
+	"github.com/coocood/freecache"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	L2BridgeDeposit    = "deposit"
+	L2BridgeWithdrawal = "withdrawal"
+)
+
+// L2BridgeNetworks are the layer 2 networks whose bridge activity is tracked, in the order they are shown
+var L2BridgeNetworks = []string{"Arbitrum", "Optimism", "Base", "zkSync"}
+
+// L2BridgeContractsEthereum are the mainnet bridge, portal, inbox and messenger contracts of the layer 2 networks
+var L2BridgeContractsEthereum = map[common.Address]string{
+	// Arbitrum One
+	common.HexToAddress("0x8315177aB297bA92A06054cE80a67Ed4DBd7ed3a"): "Arbitrum", // Bridge
+	common.HexToAddress("0x4Dbd4fc535Ac27206064B68FfCf827b0A60BAB3f"): "Arbitrum", // Delayed Inbox
+	common.HexToAddress("0x0B9857ae2D4A3DBe74ffE1d7DF045bb7F96E4840"): "Arbitrum", // Outbox
+	common.HexToAddress("0x72Ce9c846789fdB6fC1f34aC4AD25Dd9ef7031ef"): "Arbitrum", // L1 Gateway Router
+	common.HexToAddress("0xa3A7B6F88361F48403514059F1F16C8E78d60EeC"): "Arbitrum", // L1 ERC20 Gateway
+	// OP Mainnet
+	common.HexToAddress("0x99C9fc46f92E8a1c0deC1b1747d010903E884bE1"): "Optimism", // L1 Standard Bridge
+	common.HexToAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed"): "Optimism", // Optimism Portal
+	common.HexToAddress("0x25ace71c97B33Cc4729CF772ae268934F7ab5fA1"): "Optimism", // L1 Cross Domain Messenger
+	// Base
+	common.HexToAddress("0x3154Cf16ccdb4C6d922629664174b904d80F2C35"): "Base", // L1 Standard Bridge
+	common.HexToAddress("0x49048044D57e1C92A77f79988d21Fa8fAF74E97e"): "Base", // Optimism Portal
+	common.HexToAddress("0x866E82a600A1414e583f7F13623F1aC5d58b0Afa"): "Base", // L1 Cross Domain Messenger
+	// zkSync Era
+	common.HexToAddress("0x32400084C286CF3E17e7B677ea9583e60a000324"): "zkSync", // Diamond Proxy
+	common.HexToAddress("0x57891966931Eb4Bb6FB81430E6cE0A03AAbDe063"): "zkSync", // L1 ERC20 Bridge
+	common.HexToAddress("0xD7f9f54194C633F36CCD5F3da84ad4a1c38cB2cB"): "zkSync", // L1 Shared Bridge
+}
+
+// l2BridgeWithdrawalSelectors are the methods that prove or finalize withdrawals from a layer 2 network, they identify
+// withdrawals that do not move ether (e.g. token withdrawals or withdrawal proofs)
+var l2BridgeWithdrawalSelectors = func() [][]byte {
+	signatures := []string{
+		"executeTransaction(bytes32[],uint256,address,address,uint256,uint256,uint256,uint256,bytes)",
+		"proveWithdrawalTransaction((uint256,address,address,uint256,uint256,bytes),uint256,(bytes32,bytes32,bytes32,bytes32),bytes[])",
+		"finalizeWithdrawalTransaction((uint256,address,address,uint256,uint256,bytes))",
+		"relayMessage(uint256,address,address,uint256,uint256,bytes)",
+		"finalizeEthWithdrawal(uint256,uint256,uint16,bytes,bytes32[])",
+		"finalizeWithdrawal(uint256,uint256,uint16,bytes,bytes32[])",
+		"finalizeWithdrawal(uint256,uint256,uint256,uint16,bytes,bytes32[])",
+	}
+	selectors := make([][]byte, 0, len(signatures))
+	for _, s := range signatures {
+		selectors = append(selectors, crypto.Keccak256([]byte(s))[:4])
+	}
+	return selectors
+}()
+
+func l2BridgeContracts(chainId string) map[common.Address]string {
+	switch chainId {
+	case "1":
+		return L2BridgeContractsEthereum
+	default:
+		return nil
+	}
+}
+
+// TransformL2BridgeTx accepts an eth1 block and creates bigtable mutations for the transactions that interacted with
+// the bridge contracts of a layer 2 network, either by calling them directly or by moving ether into or out of them
+// within the transaction. The direction is derived from the ether flow, transactions without ether flow (e.g. token
+// bridging) are withdrawals if they prove or finalize a withdrawal and deposits otherwise.
+// ==================================================
+//
+// It writes bridge transactions to the table data:
+// Row:    <chainID>:L2B:<txHash>
+// Family: f
+// Column: data
+// Cell:   Json<Eth1L2BridgeTxIndexed>
+//
+// It indexes bridge transactions by:
+// Row:    <chainID>:I:L2B:TIME:<reversePaddedBigtableTimestamp>:<reversePaddedTxIndex>
+// Family: f
+// Column: <chainID>:L2B:<txHash>
+// Cell:   nil
+func (bigtable *Bigtable) TransformL2BridgeTx(blk *types.Eth1Block, cache *freecache.Cache) (bulkData *types.BulkMutations, bulkMetadataUpdates *types.BulkMutations, err error) {
+	startTime := time.Now()
+	defer func() {
+		metrics.TaskDuration.WithLabelValues("bt_transform_l2_bridge_tx").Observe(time.Since(startTime).Seconds())
+	}()
+
+	bulkData = &types.BulkMutations{}
+	bulkMetadataUpdates = &types.BulkMutations{}
+
+	contracts := l2BridgeContracts(bigtable.chainId)
+	if contracts == nil {
+		return bulkData, bulkMetadataUpdates, nil
+	}
+
+	for i, tx := range blk.GetTransactions() {
+		if i >= TX_PER_BLOCK_LIMIT {
+			return nil, nil, fmt.Errorf("unexpected number of transactions in block expected at most %d but got: %v, tx: %x", TX_PER_BLOCK_LIMIT-1, i, tx.GetHash())
+		}
+		if tx.GetErrorMsg() != "" {
+			continue
+		}
+
+		contract := tx.GetTo()
+		network := contracts[common.BytesToAddress(contract)]
+
+		deposited := new(big.Int)
+		withdrawn := new(big.Int)
+		itxs := tx.GetItx()
+		if len(itxs) == 0 && network != "" {
+			deposited.SetBytes(tx.GetValue())
+		}
+		for _, itx := range itxs {
+			if itx.GetType() == "delegatecall" || itx.GetErrorMsg() != "" {
+				continue
+			}
+			value := new(big.Int).SetBytes(itx.GetValue())
+			if value.Sign() == 0 {
+				continue
+			}
+			fromNetwork := contracts[common.BytesToAddress(itx.GetFrom())]
+			toNetwork := contracts[common.BytesToAddress(itx.GetTo())]
+			if fromNetwork == toNetwork {
+				continue
+			}
+			if toNetwork != "" && (network == "" || network == toNetwork) {
+				if network == "" {
+					network, contract = toNetwork, itx.GetTo()
+				}
+				deposited.Add(deposited, value)
+			}
+			if fromNetwork != "" && (network == "" || network == fromNetwork) {
+				if network == "" {
+					network, contract = fromNetwork, itx.GetFrom()
+				}
+				withdrawn.Add(withdrawn, value)
+			}
+		}
+		if network == "" {
+			continue
+		}
+
+		indexedTx := &types.Eth1L2BridgeTxIndexed{
+			TxHash:      tx.GetHash(),
+			BlockNumber: blk.GetNumber(),
+			Time:        blk.GetTime().AsTime(),
+			Network:     network,
+			Direction:   L2BridgeDeposit,
+			From:        tx.GetFrom(),
+			Contract:    contract,
+			Value:       deposited.Bytes(),
+		}
+		if withdrawn.Cmp(deposited) > 0 || (withdrawn.Sign() == 0 && deposited.Sign() == 0 && isL2BridgeWithdrawalCall(tx.GetData())) {
+			indexedTx.Direction = L2BridgeWithdrawal
+			indexedTx.Value = withdrawn.Bytes()
+		}
+
+		b, err := json.Marshal(indexedTx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key := fmt.Sprintf("%s:L2B:%x", bigtable.chainId, tx.GetHash())
+		mut := types.NewMutation()
+		mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), b)
+
+		bulkData.Keys = append(bulkData.Keys, key)
+		bulkData.Muts = append(bulkData.Muts, mut)
+
+		idx := fmt.Sprintf("%s:I:L2B:%s:%s:%s", bigtable.chainId, FILTER_TIME, reversePaddedBigtableTimestamp(blk.GetTime()), reversePaddedIndex(i, TX_PER_BLOCK_LIMIT))
+		mut = types.NewMutation()
+		mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
+
+		bulkData.Keys = append(bulkData.Keys, idx)
+		bulkData.Muts = append(bulkData.Muts, mut)
+	}
+
+	return bulkData, bulkMetadataUpdates, nil
+}
+
+func isL2BridgeWithdrawalCall(input []byte) bool {
+	if len(input) < 4 {
+		return false
+	}
+	for _, selector := range l2BridgeWithdrawalSelectors {
+		if bytes.Equal(input[:4], selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetL2BridgeTx returns the layer 2 bridge interaction of a transaction or nil if the transaction did not interact with a bridge
+func (bigtable *Bigtable) GetL2BridgeTx(txHash []byte) (*types.Eth1L2BridgeTxIndexed, error) {
+
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"txHash": txHash,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
+	defer cancel()
+
+	row, err := bigtable.tableData.ReadRow(ctx, fmt.Sprintf("%s:L2B:%x", bigtable.chainId, txHash), gcp_bigtable.RowFilter(gcp_bigtable.ColumnFilter(DATA_COLUMN)))
+	if err != nil {
+		return nil, err
+	}
+	if len(row[DEFAULT_FAMILY]) == 0 {
+		return nil, nil
+	}
+
+	bridgeTx := &types.Eth1L2BridgeTxIndexed{}
+	err = json.Unmarshal(row[DEFAULT_FAMILY][0].Value, bridgeTx)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Eth1L2BridgeTxIndexed data: %w", err)
+	}
+	return bridgeTx, nil
+}
+
+// GetL2BridgeTxsBetween returns the layer 2 bridge transactions of the blocks with a timestamp in [from, to)
+func (bigtable *Bigtable) GetL2BridgeTxsBetween(from, to time.Time) ([]*types.Eth1L2BridgeTxIndexed, error) {
+
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"from": from,
+			"to":   to,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Minute*5))
+	defer cancel()
+
+	// timestamps are reverse padded, so the last second of the range comes first
+	prefix := fmt.Sprintf("%s:I:L2B:%s:", bigtable.chainId, FILTER_TIME)
+	rowRange := gcp_bigtable.NewRange(
+		fmt.Sprintf("%s%019d:", prefix, MAX_INT-(to.Unix()-1)),
+		fmt.Sprintf("%s%019d;", prefix, MAX_INT-from.Unix()),
+	)
+
+	keys := []string{}
+	err := bigtable.tableData.ReadRows(ctx, rowRange, func(row gcp_bigtable.Row) bool {
+		keys = append(keys, strings.TrimPrefix(row[DEFAULT_FAMILY][0].Column, "f:"))
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]*types.Eth1L2BridgeTxIndexed, 0, len(keys))
+	if len(keys) == 0 {
+		return data, nil
+	}
+
+	var parseErr error
+	err = bigtable.tableData.ReadRows(ctx, gcp_bigtable.RowList(keys), func(row gcp_bigtable.Row) bool {
+		b := &types.Eth1L2BridgeTxIndexed{}
+		parseErr = json.Unmarshal(row[DEFAULT_FAMILY][0].Value, b)
+		if parseErr != nil {
+			return false
+		}
+		data = append(data, b)
+		return true
+	}, gcp_bigtable.RowFilter(gcp_bigtable.ColumnFilter(DATA_COLUMN)))
+	if err != nil {
+		return nil, err
+	}
+	if parseErr != nil {
+		return nil, fmt.Errorf("error parsing Eth1L2BridgeTxIndexed data: %w", parseErr)
+	}
+	return data, nil
+}
+
+// WriteL2BridgeFlowStatisticsForDay aggregates the layer 2 bridge transactions of the execution blocks of the day. The
+// day is skipped if the execution layer indexer has not indexed all of its blocks yet.
+func WriteL2BridgeFlowStatisticsForDay(day int64) error {
+	if day < 0 {
+		logger.Warnf("no l2-bridge-flow-stats for days before beaconchain")
+		return nil
+	}
+
+	from := utils.DayToTime(day)
+	to := utils.DayToTime(day + 1)
+
+	lastBlock, err := BigtableClient.GetMostRecentBlockFromDataTable()
+	if err != nil {
+		return fmt.Errorf("error retrieving most recent indexed block in WriteL2BridgeFlowStatisticsForDay: %w", err)
+	}
+	if lastBlock.GetTime().AsTime().Before(to) {
+		logger.Infof("skipping l2-bridge-flow-stats for day %v as its blocks have not been indexed yet", day)
+		return nil
+	}
+
+	bridgeTxs, err := BigtableClient.GetL2BridgeTxsBetween(from, to)
+	if err != nil {
+		return fmt.Errorf("error retrieving l2 bridge txs in WriteL2BridgeFlowStatisticsForDay: %w", err)
+	}
+
+	flows := make(map[string]*types.L2BridgeFlowsDailyStats, len(L2BridgeNetworks))
+	for _, network := range L2BridgeNetworks {
+		flows[network] = &types.L2BridgeFlowsDailyStats{Day: uint64(day), Network: network}
+	}
+	for _, t := range bridgeTxs {
+		f := flows[t.Network]
+		if f == nil {
+			continue
+		}
+		value := decimal.NewFromBigInt(new(big.Int).SetBytes(t.Value), 0)
+		if t.Direction == L2BridgeWithdrawal {
+			f.WithdrawalCount++
+			f.WithdrawalAmount = f.WithdrawalAmount.Add(value)
+		} else {
+			f.DepositCount++
+			f.DepositAmount = f.DepositAmount.Add(value)
+		}
+	}
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in WriteL2BridgeFlowStatisticsForDay: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, network := range L2BridgeNetworks {
+		f := flows[network]
+		_, err = tx.Exec(`
+			insert into l2_bridge_flows_daily (day, network, deposit_count, deposit_amount, withdrawal_count, withdrawal_amount)
+			values ($1, $2, $3, $4, $5, $6)
+			on conflict (day, network) do update set
+				deposit_count     = excluded.deposit_count,
+				deposit_amount    = excluded.deposit_amount,
+				withdrawal_count  = excluded.withdrawal_count,
+				withdrawal_amount = excluded.withdrawal_amount`,
+			day, network, f.DepositCount, f.DepositAmount, f.WithdrawalCount, f.WithdrawalAmount)
+		if err != nil {
+			return fmt.Errorf("error inserting l2_bridge_flows_daily in WriteL2BridgeFlowStatisticsForDay: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		insert into l2_bridge_flows_daily_status (day, status)
+		values ($1, true)
+		on conflict (day) do update set status = excluded.status`, day)
+	if err != nil {
+		return fmt.Errorf("error inserting l2_bridge_flows_daily_status in WriteL2BridgeFlowStatisticsForDay: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing db tx in WriteL2BridgeFlowStatisticsForDay: %w", err)
+	}
+	logger.Infof("exported l2-bridge-flow-stats for day %v (%v bridge txs)", day, len(bridgeTxs))
+	return nil
+}
+
+// GetL2BridgeFlowsFirstDay returns the first day the bridge transactions are indexed for. The bridge index only covers
+// blocks indexed after its deployment, so on the first call the given day is recorded as the first day; days before it
+// have to be exported explicitly once the blocks have been reindexed.
+func GetL2BridgeFlowsFirstDay(defaultDay uint64) (uint64, error) {
+	_, err := WriterDb.Exec(`
+		insert into l2_bridge_flows_daily_status (day, status)
+		select $1, false
+		where not exists (select 1 from l2_bridge_flows_daily_status)`, defaultDay)
+	if err != nil {
+		return 0, fmt.Errorf("error recording first day of l2_bridge_flows_daily_status: %w", err)
+	}
+
+	var firstDay uint64
+	err = WriterDb.Get(&firstDay, `select min(day) from l2_bridge_flows_daily_status`)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving first day of l2_bridge_flows_daily_status: %w", err)
+	}
+	return firstDay, nil
+}
+
+// GetL2BridgeFlowsHistory returns the daily layer 2 bridge flows per network for all days starting with fromDay
+func GetL2BridgeFlowsHistory(fromDay uint64) ([]*types.L2BridgeFlowsDailyStats, error) {
+	flows := []*types.L2BridgeFlowsDailyStats{}
+	err := ReaderDb.Select(&flows, `
+		select day, network, deposit_count, deposit_amount, withdrawal_count, withdrawal_amount
+		from l2_bridge_flows_daily
+		where day >= $1
+		order by day, network`, fromDay)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving l2 bridge flows history: %w", err)
+	}
+	for _, f := range flows {
+		f.NetFlow = f.DepositAmount.Sub(f.WithdrawalAmount)
+	}
+	return flows, nil
+}
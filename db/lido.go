@@ -0,0 +1,152 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/lib/pq"
+)
+
+// GetLidoOperatorKeysCount returns the amount of exported validator keys per operator of a lido staking module
+func GetLidoOperatorKeysCount(module string) (map[uint64]uint64, error) {
+	rows := []struct {
+		OperatorId uint64 `db:"operator_id"`
+		Count      uint64 `db:"count"`
+	}{}
+	err := ReaderDb.Select(&rows, `SELECT operator_id, COUNT(*) AS count FROM lido_operator_validators WHERE module = $1 GROUP BY operator_id`, module)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving key count of lido %v operators: %w", module, err)
+	}
+	res := make(map[uint64]uint64, len(rows))
+	for _, row := range rows {
+		res[row.OperatorId] = row.Count
+	}
+	return res, nil
+}
+
+// SaveLidoOperatorValidators attributes the deposited keys of a lido operator, starting at key index startIndex, to the operator
+func SaveLidoOperatorValidators(module string, operatorId uint64, startIndex uint64, pubkeys [][]byte) error {
+	if len(pubkeys) == 0 {
+		return nil
+	}
+	_, err := WriterDb.Exec(`
+		INSERT INTO lido_operator_validators (publickey, module, operator_id, key_index)
+		SELECT k.publickey, $2, $3, $4 + k.ordinality - 1
+		FROM UNNEST($1::bytea[]) WITH ORDINALITY AS k(publickey, ordinality)
+		ON CONFLICT (publickey) DO UPDATE SET
+			module = excluded.module,
+			operator_id = excluded.operator_id,
+			key_index = excluded.key_index`, pq.ByteaArray(pubkeys), module, operatorId, startIndex)
+	if err != nil {
+		return fmt.Errorf("error saving validators of lido %v operator %v: %w", module, operatorId, err)
+	}
+	return nil
+}
+
+// SaveLidoOperators stores the current state of lido operators and keeps a history of their penalty state
+func SaveLidoOperators(operators []*types.LidoOperator) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in SaveLidoOperators: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, o := range operators {
+		_, err = tx.NamedExec(`
+			INSERT INTO lido_operators (
+				module, operator_id, name, reward_address, active, total_added_validators, total_deposited_validators, total_exited_validators,
+				depositable_validators, stuck_validators, refunded_validators, target_limit_mode, target_validators, stuck_penalty_end_ts, penalized, updated_ts
+			) VALUES (
+				:module, :operator_id, :name, :reward_address, :active, :total_added_validators, :total_deposited_validators, :total_exited_validators,
+				:depositable_validators, :stuck_validators, :refunded_validators, :target_limit_mode, :target_validators, :stuck_penalty_end_ts, :penalized, :updated_ts
+			)
+			ON CONFLICT (module, operator_id) DO UPDATE SET
+				name = excluded.name,
+				reward_address = excluded.reward_address,
+				active = excluded.active,
+				total_added_validators = excluded.total_added_validators,
+				total_deposited_validators = excluded.total_deposited_validators,
+				total_exited_validators = excluded.total_exited_validators,
+				depositable_validators = excluded.depositable_validators,
+				stuck_validators = excluded.stuck_validators,
+				refunded_validators = excluded.refunded_validators,
+				target_limit_mode = excluded.target_limit_mode,
+				target_validators = excluded.target_validators,
+				stuck_penalty_end_ts = excluded.stuck_penalty_end_ts,
+				penalized = excluded.penalized,
+				updated_ts = excluded.updated_ts`, o)
+		if err != nil {
+			return fmt.Errorf("error saving lido %v operator %v: %w", o.Module, o.OperatorId, err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO lido_operators_penalty_history (module, operator_id, ts, stuck_validators, refunded_validators, stuck_penalty_end_ts, penalized)
+		SELECT o.module, o.operator_id, o.updated_ts, o.stuck_validators, o.refunded_validators, o.stuck_penalty_end_ts, o.penalized
+		FROM lido_operators o
+		LEFT JOIN LATERAL (
+			SELECT stuck_validators, refunded_validators, stuck_penalty_end_ts, penalized
+			FROM lido_operators_penalty_history h
+			WHERE h.module = o.module AND h.operator_id = o.operator_id
+			ORDER BY ts DESC
+			LIMIT 1
+		) h ON true
+		WHERE (h.penalized IS NULL AND (o.penalized OR o.stuck_validators > 0)) OR
+			(h.penalized IS NOT NULL AND (h.stuck_validators, h.refunded_validators, h.stuck_penalty_end_ts, h.penalized) IS DISTINCT FROM (o.stuck_validators, o.refunded_validators, o.stuck_penalty_end_ts, o.penalized))
+		ON CONFLICT DO NOTHING`)
+	if err != nil {
+		return fmt.Errorf("error inserting lido operators penalty history: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetLidoOperators returns the lido operators of a module (all modules if module is empty) together with the performance
+// of their validators in the last 31 days, best performing operators first
+func GetLidoOperators(module string) ([]*types.ApiLidoOperatorResponse, error) {
+	operators := []*types.ApiLidoOperatorResponse{}
+	err := ReaderDb.Select(&operators, `
+		WITH performance AS (
+			SELECT
+				lv.module,
+				lv.operator_id,
+				COUNT(v.validatorindex) AS validators,
+				COUNT(v.validatorindex) FILTER (WHERE v.status LIKE 'active%') AS active_validators,
+				COALESCE(SUM(vp.cl_performance_31d), 0) / 1e9 AS cl_rewards_31d,
+				COALESCE(SUM(vp.el_performance_31d), 0) / 1e18 AS el_rewards_31d
+			FROM lido_operator_validators lv
+			INNER JOIN validators v ON v.pubkey = lv.publickey
+			LEFT JOIN validator_performance vp ON vp.validatorindex = v.validatorindex
+			WHERE $1 = '' OR lv.module = $1
+			GROUP BY lv.module, lv.operator_id
+		)
+		SELECT
+			o.module,
+			o.operator_id,
+			o.name,
+			COALESCE('0x' || ENCODE(o.reward_address, 'hex'), '') AS reward_address,
+			o.active,
+			o.total_deposited_validators,
+			o.total_exited_validators,
+			o.stuck_validators,
+			o.refunded_validators,
+			o.stuck_penalty_end_ts,
+			o.penalized,
+			COALESCE(p.validators, 0) AS validators,
+			COALESCE(p.active_validators, 0) AS active_validators,
+			COALESCE(p.cl_rewards_31d, 0) AS cl_rewards_31d,
+			COALESCE(p.el_rewards_31d, 0) AS el_rewards_31d,
+			COALESCE((p.cl_rewards_31d + p.el_rewards_31d) / NULLIF(p.active_validators, 0), 0) AS rewards_per_validator_31d,
+			COALESCE((p.cl_rewards_31d + p.el_rewards_31d) / NULLIF(p.active_validators * $2::NUMERIC / 1e9, 0) * 365 / 31 * 100, 0) AS apr_31d,
+			o.updated_ts
+		FROM lido_operators o
+		LEFT JOIN performance p ON p.module = o.module AND p.operator_id = o.operator_id
+		WHERE $1 = '' OR o.module = $1
+		ORDER BY apr_31d DESC, o.module, o.operator_id`, module, utils.Config.Chain.ClConfig.MaxEffectiveBalance)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving lido operators: %w", err)
+	}
+	return operators, nil
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/tracing"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// queryLogger logs failed queries, and all queries at trace level
+var queryLogger = utils.NewLogger("dbQueries")
+
+type queryTraceKey struct{}
+
+type queryTrace struct {
+	sql   string
+	start time.Time
+	span  oteltrace.Span
+}
+
+// queryTracer logs the queries of the postgres connections with the request id of the query context and records a span
+// for every query, so queries run with the context of a request (e.g. ReaderDb.SelectContext(r.Context(), ...)) can be
+// traced back to the request
+type queryTracer struct{}
+
+func (queryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx, span := tracing.StartSpan(ctx, "db.query", attribute.String("db.system", "postgresql"), attribute.String("db.statement", data.SQL))
+	return context.WithValue(ctx, queryTraceKey{}, &queryTrace{sql: data.SQL, start: time.Now(), span: span})
+}
+
+func (queryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	trace, ok := ctx.Value(queryTraceKey{}).(*queryTrace)
+	if !ok {
+		return
+	}
+
+	tracing.EndSpan(trace.span, data.Err)
+
+	duration := time.Since(trace.start)
+	if budget := queryBudgetFromContext(ctx); budget != nil {
+		budget.add(trace.sql, duration)
+	}
+
+	entry := utils.LoggerWithContext(ctx, queryLogger).WithFields(logrus.Fields{"query": trace.sql, "duration": duration})
+	if data.Err != nil && !errors.Is(data.Err, context.Canceled) {
+		entry.WithError(data.Err).Error("error executing query")
+		return
+	}
+	if slowQuery := utils.Config.Frontend.QueryBudget.SlowQuery; slowQuery > 0 && duration > slowQuery {
+		entry.Warn("slow query")
+		return
+	}
+	entry.Trace("executed query")
+}
+
+// openPostgres opens a pgx connection pool whose queries are logged by the query tracer
+func openPostgres(dsn string) (*sqlx.DB, error) {
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	connConfig.Tracer = queryTracer{}
+	return sqlx.NewDb(stdlib.OpenDB(*connConfig), "pgx"), nil
+}
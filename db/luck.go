@@ -0,0 +1,94 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/lib/pq"
+)
+
+// GetValidatorsLuck returns the actual and expected proposals and sync committee slots of the given validators over the
+// last exported days, together with the daily history of these days
+func GetValidatorsLuck(validatorIndices []uint64, days uint64) (map[uint64]*types.ApiValidatorLuckResponse, error) {
+	lastDay, err := GetLastExportedStatisticDay()
+	if err != nil && err != ErrNoStats {
+		return nil, err
+	}
+
+	history := []struct {
+		ValidatorIndex uint64 `db:"validatorindex"`
+		types.ApiValidatorLuckHistory
+	}{}
+	err = ReaderDb.Select(&history, `
+		SELECT
+			validatorindex,
+			day,
+			COALESCE(proposed_blocks, 0) + COALESCE(missed_blocks, 0) + COALESCE(orphaned_blocks, 0) AS proposals,
+			expected_proposals,
+			COALESCE(participated_sync, 0) + COALESCE(missed_sync, 0) + COALESCE(orphaned_sync, 0) AS sync_slots,
+			expected_sync_slots
+		FROM validator_stats
+		WHERE validatorindex = ANY($1) AND day > $2 AND day <= $3 AND expected_proposals IS NOT NULL
+		ORDER BY day`, pq.Array(validatorIndices), int64(lastDay)-int64(days), lastDay)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validator luck: %w", err)
+	}
+
+	luck := make(map[uint64]*types.ApiValidatorLuckResponse, len(validatorIndices))
+	for _, index := range validatorIndices {
+		luck[index] = &types.ApiValidatorLuckResponse{
+			ValidatorIndex: index,
+			History:        []*types.ApiValidatorLuckHistory{},
+		}
+	}
+	for i := range history {
+		l, ok := luck[history[i].ValidatorIndex]
+		if !ok {
+			continue
+		}
+		l.Proposals += history[i].Proposals
+		l.ExpectedProposals += history[i].ExpectedProposals
+		l.SyncSlots += history[i].SyncSlots
+		l.ExpectedSyncSlots += history[i].ExpectedSyncSlots
+		history[i].DayStart = utils.DayToTime(int64(history[i].Day))
+		l.History = append(l.History, &history[i].ApiValidatorLuckHistory)
+	}
+	for _, l := range luck {
+		if l.ExpectedProposals > 0 {
+			l.ProposalLuck = float64(l.Proposals) / l.ExpectedProposals
+		}
+		if l.ExpectedSyncSlots > 0 {
+			l.SyncLuck = float64(l.SyncSlots) / l.ExpectedSyncSlots
+		}
+	}
+
+	return luck, nil
+}
+
+// GetPoolLuckHistory returns the daily proposal luck of the pools with the most validators, calculated over a window of
+// the given amount of days as a single day rarely contains a proposal
+func GetPoolLuckHistory(pools int, windowDays int) ([]*types.PoolLuckHistory, error) {
+	history := []*types.PoolLuckHistory{}
+	err := ReaderDb.Select(&history, fmt.Sprintf(`
+		WITH top_pools AS (
+			SELECT pool FROM pool_luck_history
+			WHERE day = (SELECT MAX(day) FROM pool_luck_history) AND pool != 'Unknown'
+			ORDER BY validators DESC
+			LIMIT $1
+		)
+		SELECT
+			day,
+			pool,
+			COALESCE(SUM(proposals) OVER w / NULLIF(SUM(expected_proposals) OVER w, 0), 0) AS proposal_luck,
+			COALESCE(SUM(sync_slots) OVER w / NULLIF(SUM(expected_sync_slots) OVER w, 0), 0) AS sync_luck
+		FROM pool_luck_history
+		WHERE pool IN (SELECT pool FROM top_pools)
+		WINDOW w AS (PARTITION BY pool ORDER BY day ROWS BETWEEN %d PRECEDING AND CURRENT ROW)
+		ORDER BY pool, day`, windowDays-1), pools)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving pool luck history: %w", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// SaveMempoolSnapshot stores the stats and top spenders of a mempool snapshot
+func SaveMempoolSnapshot(stats *types.MempoolStats) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in SaveMempoolSnapshot: %w", err)
+	}
+	defer tx.Rollback()
+
+	ts := stats.Ts.Unix()
+	_, err = tx.Exec(`
+		insert into mempool_snapshots (ts, pending_count, queued_count, basefee_count, pending_gas, gas_price_p10, gas_price_p25, gas_price_p50, gas_price_p75, gas_price_p90)
+		values (to_timestamp($1) at time zone 'utc', $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		on conflict (ts) do nothing`,
+		ts, stats.PendingCount, stats.QueuedCount, stats.BaseFeeCount, stats.PendingGas, stats.GasPriceP10, stats.GasPriceP25, stats.GasPriceP50, stats.GasPriceP75, stats.GasPriceP90)
+	if err != nil {
+		return fmt.Errorf("error inserting mempool snapshot: %w", err)
+	}
+
+	for _, spender := range stats.TopSpenders {
+		_, err = tx.Exec(`
+			insert into mempool_snapshots_top_spenders (ts, address, tx_count, total_fees, total_value)
+			values (to_timestamp($1) at time zone 'utc', $2, $3, $4, $5)
+			on conflict (ts, address) do nothing`,
+			ts, spender.Address, spender.TxCount, spender.TotalFees, spender.TotalValue)
+		if err != nil {
+			return fmt.Errorf("error inserting mempool snapshot top spender %#x: %w", spender.Address, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DeleteMempoolSnapshotsBefore deletes all mempool snapshots taken before the given time
+func DeleteMempoolSnapshotsBefore(before time.Time) error {
+	_, err := WriterDb.Exec(`delete from mempool_snapshots_top_spenders where ts < to_timestamp($1) at time zone 'utc'`, before.Unix())
+	if err != nil {
+		return fmt.Errorf("error deleting mempool snapshot top spenders: %w", err)
+	}
+	_, err = WriterDb.Exec(`delete from mempool_snapshots where ts < to_timestamp($1) at time zone 'utc'`, before.Unix())
+	if err != nil {
+		return fmt.Errorf("error deleting mempool snapshots: %w", err)
+	}
+	return nil
+}
+
+// GetMempoolSnapshotHistory returns the mempool snapshots taken since the given time averaged over buckets of the given size
+func GetMempoolSnapshotHistory(since time.Time, bucket time.Duration) ([]*types.MempoolStats, error) {
+	history := []*types.MempoolStats{}
+	err := ReaderDb.Select(&history, `
+		select
+			to_timestamp(floor(extract(epoch from ts) / $2) * $2) at time zone 'utc' as ts,
+			avg(pending_count)::int as pending_count,
+			avg(queued_count)::int as queued_count,
+			avg(basefee_count)::int as basefee_count,
+			avg(pending_gas)::bigint as pending_gas,
+			avg(gas_price_p10) as gas_price_p10,
+			avg(gas_price_p25) as gas_price_p25,
+			avg(gas_price_p50) as gas_price_p50,
+			avg(gas_price_p75) as gas_price_p75,
+			avg(gas_price_p90) as gas_price_p90
+		from mempool_snapshots
+		where ts >= to_timestamp($1) at time zone 'utc'
+		group by 1
+		order by 1`, since.Unix(), int64(bucket.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("error getting mempool snapshot history: %w", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+// dbSchemaLockId identifies the postgres advisory lock held while the db schema is migrated, so instances starting
+// concurrently with auto migration enabled do not apply the same migration twice
+const dbSchemaLockId = 4293118
+
+// ApplyEmbeddedDbSchema applies the embedded migrations, use version -2 to apply all pending migrations, -1 to apply
+// only the next one or a specific version to migrate up to it
+func ApplyEmbeddedDbSchema(version int64) error {
+	return withDbSchemaLock(func() error {
+		if version == -2 {
+			return goose.Up(WriterDb.DB, "migrations")
+		} else if version == -1 {
+			return goose.UpByOne(WriterDb.DB, "migrations")
+		}
+		return goose.UpTo(WriterDb.DB, "migrations", version)
+	})
+}
+
+// RollbackEmbeddedDbSchema rolls back the latest applied migration if version is negative, otherwise all migrations
+// newer than the version
+func RollbackEmbeddedDbSchema(version int64) error {
+	return withDbSchemaLock(func() error {
+		if version < 0 {
+			return goose.Down(WriterDb.DB, "migrations")
+		}
+		return goose.DownTo(WriterDb.DB, "migrations", version)
+	})
+}
+
+// PrintEmbeddedDbSchemaStatus logs whether and when every embedded migration has been applied
+func PrintEmbeddedDbSchemaStatus() error {
+	if err := initGoose(); err != nil {
+		return err
+	}
+	return goose.Status(WriterDb.DB, "migrations")
+}
+
+func initGoose() error {
+	goose.SetBaseFS(EmbedMigrations)
+	return goose.SetDialect("postgres")
+}
+
+func withDbSchemaLock(fn func() error) error {
+	if err := initGoose(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	conn, err := WriterDb.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting db connection for the schema lock: %w", err)
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", dbSchemaLockId)
+	if err != nil {
+		return fmt.Errorf("error acquiring db schema lock: %w", err)
+	}
+	defer func() {
+		_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", dbSchemaLockId)
+		if err != nil {
+			logger.WithError(err).Error("error releasing db schema lock")
+		}
+	}()
+
+	return fn()
+}
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add last_bids_export_slot column to relays table');
+ALTER TABLE relays ADD COLUMN IF NOT EXISTS last_bids_export_slot INT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create relays_bids table');
+CREATE TABLE IF NOT EXISTS relays_bids (
+    tag_id VARCHAR NOT NULL,
+    block_slot INT NOT NULL,
+    block_hash bytea NOT NULL,
+    builder_pubkey bytea NOT NULL,
+    proposer_pubkey bytea NOT NULL,
+    value NUMERIC NOT NULL,
+    PRIMARY KEY (block_slot, tag_id)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create relays_anomalies table');
+CREATE TABLE IF NOT EXISTS relays_anomalies (
+    block_slot INT NOT NULL,
+    block_root bytea NOT NULL,
+    exec_block_hash bytea NOT NULL,
+    anomaly_type VARCHAR NOT NULL,
+    tag_id VARCHAR NULL,
+    delivered_value NUMERIC NOT NULL,
+    winning_bid_tag_id VARCHAR NOT NULL,
+    winning_bid_block_hash bytea NOT NULL,
+    winning_bid_value NUMERIC NOT NULL,
+    PRIMARY KEY (block_slot, block_root, anomaly_type)
+);
+CREATE INDEX IF NOT EXISTS idx_relays_anomalies_exec_block_hash ON relays_anomalies (exec_block_hash);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop relays_anomalies table');
+DROP TABLE IF EXISTS relays_anomalies;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop relays_bids table');
+DROP TABLE IF EXISTS relays_bids;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop last_bids_export_slot column from relays table');
+ALTER TABLE relays DROP COLUMN IF EXISTS last_bids_export_slot;
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create mev_income table');
+CREATE TABLE IF NOT EXISTS mev_income (
+    validatorindex INT NOT NULL,
+    day INT NOT NULL,
+    builder_blocks INT NOT NULL DEFAULT 0,
+    local_blocks INT NOT NULL DEFAULT 0,
+    mev_value NUMERIC NOT NULL DEFAULT 0,
+    PRIMARY KEY (validatorindex, day)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create mev_income_relays table');
+CREATE TABLE IF NOT EXISTS mev_income_relays (
+    validatorindex INT NOT NULL,
+    day INT NOT NULL,
+    tag_id VARCHAR NOT NULL,
+    block_count INT NOT NULL DEFAULT 0,
+    value NUMERIC NOT NULL DEFAULT 0,
+    PRIMARY KEY (validatorindex, day, tag_id)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create mev_income_stats_status table');
+CREATE TABLE IF NOT EXISTS mev_income_stats_status (
+    day INT NOT NULL,
+    status BOOLEAN NOT NULL,
+    PRIMARY KEY (day)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop mev_income_stats_status table');
+DROP TABLE IF EXISTS mev_income_stats_status;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop mev_income_relays table');
+DROP TABLE IF EXISTS mev_income_relays;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop mev_income table');
+DROP TABLE IF EXISTS mev_income;
+-- +goose StatementEnd
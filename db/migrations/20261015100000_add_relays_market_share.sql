@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create builders table');
+CREATE TABLE IF NOT EXISTS builders (
+    builder_pubkey bytea NOT NULL,
+    extra_data bytea NULL,
+    first_seen_slot INT NOT NULL,
+    last_seen_slot INT NOT NULL,
+    PRIMARY KEY (builder_pubkey)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create relays_daily_stats table');
+CREATE TABLE IF NOT EXISTS relays_daily_stats (
+    day INT NOT NULL,
+    tag_id VARCHAR NOT NULL,
+    block_count INT NOT NULL,
+    total_value NUMERIC NOT NULL,
+    market_share FLOAT NOT NULL,
+    PRIMARY KEY (day, tag_id)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create builders_daily_stats table');
+CREATE TABLE IF NOT EXISTS builders_daily_stats (
+    day INT NOT NULL,
+    builder_pubkey bytea NOT NULL,
+    block_count INT NOT NULL,
+    total_value NUMERIC NOT NULL,
+    avg_bid_value NUMERIC NULL,
+    market_share FLOAT NOT NULL,
+    PRIMARY KEY (day, builder_pubkey)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create relays_daily_stats_status table');
+CREATE TABLE IF NOT EXISTS relays_daily_stats_status (
+    day INT NOT NULL,
+    block_count INT NOT NULL,
+    censoring_block_count INT NOT NULL,
+    status BOOLEAN NOT NULL,
+    PRIMARY KEY (day)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop relays_daily_stats_status table');
+DROP TABLE IF EXISTS relays_daily_stats_status;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop builders_daily_stats table');
+DROP TABLE IF EXISTS builders_daily_stats;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop relays_daily_stats table');
+DROP TABLE IF EXISTS relays_daily_stats;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop builders table');
+DROP TABLE IF EXISTS builders;
+-- +goose StatementEnd
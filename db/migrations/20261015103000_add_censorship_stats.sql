@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create censorship_watched_txs table');
+CREATE TABLE IF NOT EXISTS censorship_watched_txs (
+    tx_hash bytea NOT NULL,
+    from_address bytea NOT NULL,
+    to_address bytea NULL,
+    first_seen_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    included_block_number INT NULL,
+    included_slot INT NULL,
+    inclusion_delay INT NULL,
+    skipped_blocks INT NULL,
+    PRIMARY KEY (tx_hash)
+);
+CREATE INDEX IF NOT EXISTS idx_censorship_watched_txs_included_slot ON censorship_watched_txs (included_slot);
+CREATE INDEX IF NOT EXISTS idx_censorship_watched_txs_first_seen_ts ON censorship_watched_txs (first_seen_ts) WHERE included_slot IS NULL;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create censorship_daily_stats table');
+CREATE TABLE IF NOT EXISTS censorship_daily_stats (
+    day INT NOT NULL,
+    entity_type VARCHAR NOT NULL,
+    entity VARCHAR NOT NULL,
+    block_count INT NOT NULL,
+    watched_tx_block_count INT NOT NULL,
+    watched_tx_count INT NOT NULL,
+    avg_inclusion_delay FLOAT NULL,
+    avg_skipped_blocks FLOAT NULL,
+    PRIMARY KEY (day, entity_type, entity)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create censorship_daily_stats_status table');
+CREATE TABLE IF NOT EXISTS censorship_daily_stats_status (
+    day INT NOT NULL,
+    status BOOLEAN NOT NULL,
+    PRIMARY KEY (day)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop censorship_daily_stats_status table');
+DROP TABLE IF EXISTS censorship_daily_stats_status;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop censorship_daily_stats table');
+DROP TABLE IF EXISTS censorship_daily_stats;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop censorship_watched_txs table');
+DROP TABLE IF EXISTS censorship_watched_txs;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create verified_contracts table');
+CREATE TABLE IF NOT EXISTS verified_contracts (
+    address bytea NOT NULL,
+    name VARCHAR NOT NULL DEFAULT '',
+    compiler_version VARCHAR NOT NULL DEFAULT '',
+    match_type VARCHAR NOT NULL,
+    abi TEXT NOT NULL,
+    source VARCHAR NOT NULL,
+    verified_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    PRIMARY KEY (address)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create verified_contracts_files table');
+CREATE TABLE IF NOT EXISTS verified_contracts_files (
+    address bytea NOT NULL,
+    path VARCHAR NOT NULL,
+    content TEXT NOT NULL,
+    PRIMARY KEY (address, path)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop verified_contracts_files table');
+DROP TABLE IF EXISTS verified_contracts_files;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop verified_contracts table');
+DROP TABLE IF EXISTS verified_contracts;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create mempool_snapshots table');
+CREATE TABLE IF NOT EXISTS mempool_snapshots (
+    ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    pending_count INT NOT NULL,
+    queued_count INT NOT NULL,
+    basefee_count INT NOT NULL,
+    pending_gas BIGINT NOT NULL,
+    gas_price_p10 DOUBLE PRECISION NOT NULL,
+    gas_price_p25 DOUBLE PRECISION NOT NULL,
+    gas_price_p50 DOUBLE PRECISION NOT NULL,
+    gas_price_p75 DOUBLE PRECISION NOT NULL,
+    gas_price_p90 DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (ts)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create mempool_snapshots_top_spenders table');
+CREATE TABLE IF NOT EXISTS mempool_snapshots_top_spenders (
+    ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    address bytea NOT NULL,
+    tx_count INT NOT NULL,
+    total_fees NUMERIC NOT NULL,
+    total_value NUMERIC NOT NULL,
+    PRIMARY KEY (ts, address)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop mempool_snapshots_top_spenders table');
+DROP TABLE IF EXISTS mempool_snapshots_top_spenders;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop mempool_snapshots table');
+DROP TABLE IF EXISTS mempool_snapshots;
+-- +goose StatementEnd
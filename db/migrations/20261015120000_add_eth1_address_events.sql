@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create eth1_address_watchlist table');
+CREATE TABLE IF NOT EXISTS eth1_address_watchlist (
+    address bytea NOT NULL,
+    PRIMARY KEY (address)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create eth1_address_events table');
+CREATE TABLE IF NOT EXISTS eth1_address_events (
+    block_number INT NOT NULL,
+    ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    tx_hash bytea NOT NULL,
+    log_index INT NOT NULL,
+    address bytea NOT NULL,
+    event_name VARCHAR NOT NULL,
+    counterparty bytea NOT NULL,
+    token bytea NULL,
+    value NUMERIC NOT NULL,
+    PRIMARY KEY (tx_hash, log_index, address, event_name)
+);
+CREATE INDEX IF NOT EXISTS idx_eth1_address_events_ts ON eth1_address_events (ts);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop eth1_address_events table');
+DROP TABLE IF EXISTS eth1_address_events;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop eth1_address_watchlist table');
+DROP TABLE IF EXISTS eth1_address_watchlist;
+-- +goose StatementEnd
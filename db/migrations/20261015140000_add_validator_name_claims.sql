@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create validator_name_claims table');
+CREATE TABLE IF NOT EXISTS validator_name_claims (
+    id SERIAL NOT NULL,
+    publickey bytea NOT NULL,
+    name VARCHAR(40) NOT NULL,
+    signer_address bytea NOT NULL,
+    proof_type VARCHAR(20) NOT NULL,
+    message TEXT NOT NULL,
+    signature bytea NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+    reviewed_ts TIMESTAMP WITHOUT TIME ZONE NULL,
+    reviewed_by INT NULL,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_validator_name_claims_status ON validator_name_claims (status, created_ts);
+CREATE INDEX IF NOT EXISTS idx_validator_name_claims_publickey ON validator_name_claims (publickey);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop validator_name_claims table');
+DROP TABLE IF EXISTS validator_name_claims;
+-- +goose StatementEnd
@@ -0,0 +1,63 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create validator_entity_rules table');
+CREATE TABLE IF NOT EXISTS validator_entity_rules (
+    id SERIAL NOT NULL,
+    entity VARCHAR(40) NOT NULL,
+    category VARCHAR(40) NULL,
+    rule_type VARCHAR(30) NOT NULL,
+    value TEXT NOT NULL,
+    priority INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_validator_entity_rules_type_value ON validator_entity_rules (rule_type, value);
+-- minipools are attributed by the rocketpool exporter, the tag rule takes over minipools that got attributed before the exporter picked them up
+INSERT INTO validator_entity_rules (entity, category, rule_type, value, priority) VALUES ('rocketpool', 'Liquid Staking', 'tag', 'rocketpool', 100) ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create validator_entities_versions table');
+CREATE TABLE IF NOT EXISTS validator_entities_versions (
+    version SERIAL NOT NULL,
+    ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    validators INT NOT NULL,
+    changes INT NOT NULL,
+    PRIMARY KEY (version)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create validator_entities_changes table');
+CREATE TABLE IF NOT EXISTS validator_entities_changes (
+    version INT NOT NULL,
+    publickey bytea NOT NULL,
+    old_entity VARCHAR(40) NULL,
+    new_entity VARCHAR(40) NULL,
+    PRIMARY KEY (version, publickey)
+);
+CREATE INDEX IF NOT EXISTS idx_validator_entities_changes_publickey ON validator_entities_changes (publickey);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - add attribution columns to validator_pool');
+ALTER TABLE validator_pool ADD COLUMN IF NOT EXISTS rule_id INT NULL;
+ALTER TABLE validator_pool ADD COLUMN IF NOT EXISTS source VARCHAR(30) NULL;
+ALTER TABLE validator_pool ADD COLUMN IF NOT EXISTS version INT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - remove attribution columns from validator_pool');
+ALTER TABLE validator_pool DROP COLUMN IF EXISTS rule_id;
+ALTER TABLE validator_pool DROP COLUMN IF EXISTS source;
+ALTER TABLE validator_pool DROP COLUMN IF EXISTS version;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop validator_entities_changes table');
+DROP TABLE IF EXISTS validator_entities_changes;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop validator_entities_versions table');
+DROP TABLE IF EXISTS validator_entities_versions;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop validator_entity_rules table');
+DROP TABLE IF EXISTS validator_entity_rules;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create rocketpool_minipools_status_history table');
+CREATE TABLE IF NOT EXISTS rocketpool_minipools_status_history (
+    rocketpool_storage_address bytea NOT NULL,
+    address bytea NOT NULL,
+    status TEXT NOT NULL,
+    status_time TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    PRIMARY KEY (rocketpool_storage_address, address, status, status_time)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create rocketpool_nodes_smoothing_pool_history table');
+CREATE TABLE IF NOT EXISTS rocketpool_nodes_smoothing_pool_history (
+    rocketpool_storage_address bytea NOT NULL,
+    address bytea NOT NULL,
+    opted_in BOOLEAN NOT NULL,
+    ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    PRIMARY KEY (rocketpool_storage_address, address, ts)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - add node address index to rocketpool_minipools');
+CREATE INDEX IF NOT EXISTS idx_rocketpool_minipools_node_address ON rocketpool_minipools (node_address);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop node address index of rocketpool_minipools');
+DROP INDEX IF EXISTS idx_rocketpool_minipools_node_address;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop rocketpool_nodes_smoothing_pool_history table');
+DROP TABLE IF EXISTS rocketpool_nodes_smoothing_pool_history;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop rocketpool_minipools_status_history table');
+DROP TABLE IF EXISTS rocketpool_minipools_status_history;
+-- +goose StatementEnd
@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create lido_operators table');
+CREATE TABLE IF NOT EXISTS lido_operators (
+    module VARCHAR(20) NOT NULL,
+    operator_id INT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    reward_address bytea,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    total_added_validators INT NOT NULL DEFAULT 0,
+    total_deposited_validators INT NOT NULL DEFAULT 0,
+    total_exited_validators INT NOT NULL DEFAULT 0,
+    depositable_validators INT NOT NULL DEFAULT 0,
+    stuck_validators INT NOT NULL DEFAULT 0,
+    refunded_validators INT NOT NULL DEFAULT 0,
+    target_limit_mode INT NOT NULL DEFAULT 0,
+    target_validators INT NOT NULL DEFAULT 0,
+    stuck_penalty_end_ts TIMESTAMP WITHOUT TIME ZONE,
+    penalized BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    PRIMARY KEY (module, operator_id)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create lido_operator_validators table');
+CREATE TABLE IF NOT EXISTS lido_operator_validators (
+    publickey bytea NOT NULL,
+    module VARCHAR(20) NOT NULL,
+    operator_id INT NOT NULL,
+    key_index INT NOT NULL,
+    PRIMARY KEY (publickey)
+);
+CREATE INDEX IF NOT EXISTS idx_lido_operator_validators_operator ON lido_operator_validators (module, operator_id, key_index);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create lido_operators_penalty_history table');
+CREATE TABLE IF NOT EXISTS lido_operators_penalty_history (
+    module VARCHAR(20) NOT NULL,
+    operator_id INT NOT NULL,
+    ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    stuck_validators INT NOT NULL,
+    refunded_validators INT NOT NULL,
+    stuck_penalty_end_ts TIMESTAMP WITHOUT TIME ZONE,
+    penalized BOOLEAN NOT NULL,
+    PRIMARY KEY (module, operator_id, ts)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop lido_operators_penalty_history table');
+DROP TABLE IF EXISTS lido_operators_penalty_history;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop lido_operator_validators table');
+DROP TABLE IF EXISTS lido_operator_validators;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop lido_operators table');
+DROP TABLE IF EXISTS lido_operators;
+-- +goose StatementEnd
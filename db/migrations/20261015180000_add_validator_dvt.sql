@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create validator_dvt table');
+CREATE TABLE IF NOT EXISTS validator_dvt (
+    publickey bytea NOT NULL,
+    protocol VARCHAR(10) NOT NULL,
+    cluster_id TEXT NOT NULL,
+    cluster_name TEXT NOT NULL DEFAULT '',
+    operators TEXT[] NOT NULL DEFAULT '{}',
+    updated_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    PRIMARY KEY (publickey)
+);
+CREATE INDEX IF NOT EXISTS idx_validator_dvt_protocol ON validator_dvt (protocol, cluster_id);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create dvt_adoption_history table');
+CREATE TABLE IF NOT EXISTS dvt_adoption_history (
+    day DATE NOT NULL,
+    protocol VARCHAR(10) NOT NULL,
+    validators INT NOT NULL,
+    active_validators INT NOT NULL,
+    clusters INT NOT NULL,
+    PRIMARY KEY (day, protocol)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop dvt_adoption_history table');
+DROP TABLE IF EXISTS dvt_adoption_history;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop validator_dvt table');
+DROP TABLE IF EXISTS validator_dvt;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create staking_pool_fees table');
+CREATE TABLE IF NOT EXISTS staking_pool_fees (
+    pool VARCHAR(40) NOT NULL,
+    fee FLOAT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (pool)
+);
+INSERT INTO staking_pool_fees (pool, fee, description) VALUES ('Lido', 0.1, 'Protocol fee on staking rewards, split between node operators and the DAO') ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create pool_apr_history table');
+CREATE TABLE IF NOT EXISTS pool_apr_history (
+    day INT NOT NULL,
+    pool VARCHAR(40) NOT NULL,
+    validators INT NOT NULL,
+    consensus_apr FLOAT NOT NULL,
+    execution_apr FLOAT NOT NULL,
+    gross_apr FLOAT NOT NULL,
+    fee FLOAT NOT NULL,
+    net_apr FLOAT NOT NULL,
+    PRIMARY KEY (day, pool)
+);
+CREATE INDEX IF NOT EXISTS idx_pool_apr_history_pool ON pool_apr_history (pool, day DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop pool_apr_history table');
+DROP TABLE IF EXISTS pool_apr_history;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop staking_pool_fees table');
+DROP TABLE IF EXISTS staking_pool_fees;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add validators column to eth_store_stats');
+ALTER TABLE eth_store_stats ADD COLUMN IF NOT EXISTS validators INT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop validators column of eth_store_stats');
+ALTER TABLE eth_store_stats DROP COLUMN IF EXISTS validators;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add percentile columns to validator_stats');
+ALTER TABLE validator_stats ADD COLUMN IF NOT EXISTS attestation_percentile FLOAT;
+ALTER TABLE validator_stats ADD COLUMN IF NOT EXISTS proposal_luck_percentile FLOAT;
+ALTER TABLE validator_stats ADD COLUMN IF NOT EXISTS income_percentile FLOAT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop percentile columns of validator_stats');
+ALTER TABLE validator_stats DROP COLUMN IF EXISTS attestation_percentile;
+ALTER TABLE validator_stats DROP COLUMN IF EXISTS proposal_luck_percentile;
+ALTER TABLE validator_stats DROP COLUMN IF EXISTS income_percentile;
+-- +goose StatementEnd
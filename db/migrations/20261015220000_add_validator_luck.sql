@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add expected duty columns to validator_stats');
+ALTER TABLE validator_stats ADD COLUMN IF NOT EXISTS expected_proposals FLOAT;
+ALTER TABLE validator_stats ADD COLUMN IF NOT EXISTS expected_sync_slots FLOAT;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create pool_luck_history table');
+CREATE TABLE IF NOT EXISTS pool_luck_history (
+    day INT NOT NULL,
+    pool VARCHAR(40) NOT NULL,
+    validators INT NOT NULL,
+    proposals INT NOT NULL,
+    expected_proposals FLOAT NOT NULL,
+    sync_slots INT NOT NULL,
+    expected_sync_slots FLOAT NOT NULL,
+    PRIMARY KEY (day, pool)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop pool_luck_history table');
+DROP TABLE IF EXISTS pool_luck_history;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop expected duty columns of validator_stats');
+ALTER TABLE validator_stats DROP COLUMN IF EXISTS expected_proposals;
+ALTER TABLE validator_stats DROP COLUMN IF EXISTS expected_sync_slots;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add additional currencies to price table');
+ALTER TABLE price ADD COLUMN IF NOT EXISTS chf NUMERIC(20, 10);
+ALTER TABLE price ADD COLUMN IF NOT EXISTS inr NUMERIC(20, 10);
+ALTER TABLE price ADD COLUMN IF NOT EXISTS brl NUMERIC(20, 10);
+ALTER TABLE price ADD COLUMN IF NOT EXISTS krw NUMERIC(20, 10);
+ALTER TABLE price ADD COLUMN IF NOT EXISTS hkd NUMERIC(20, 10);
+ALTER TABLE price ADD COLUMN IF NOT EXISTS sgd NUMERIC(20, 10);
+ALTER TABLE price ADD COLUMN IF NOT EXISTS btc NUMERIC(20, 10);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - remove additional currencies from price table');
+ALTER TABLE price DROP COLUMN IF EXISTS chf;
+ALTER TABLE price DROP COLUMN IF EXISTS inr;
+ALTER TABLE price DROP COLUMN IF EXISTS brl;
+ALTER TABLE price DROP COLUMN IF EXISTS krw;
+ALTER TABLE price DROP COLUMN IF EXISTS hkd;
+ALTER TABLE price DROP COLUMN IF EXISTS sgd;
+ALTER TABLE price DROP COLUMN IF EXISTS btc;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - widen network of users_val_dashboards to hold all chain ids');
+ALTER TABLE users_val_dashboards ALTER COLUMN network TYPE BIGINT;
+CREATE INDEX IF NOT EXISTS idx_users_val_dashboards_user_id ON users_val_dashboards (user_id, network);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop index on users_val_dashboards');
+DROP INDEX IF EXISTS idx_users_val_dashboards_user_id;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add daily rollup of the validator stats of validator dashboards');
+CREATE TABLE IF NOT EXISTS users_val_dashboards_stats (
+    dashboard_id          BIGINT  NOT NULL,
+    day                   INT     NOT NULL,
+    validators            INT     NOT NULL,
+    start_balance         BIGINT  NOT NULL,
+    end_balance           BIGINT  NOT NULL,
+    end_effective_balance BIGINT  NOT NULL,
+    deposits_amount       BIGINT  NOT NULL,
+    withdrawals_amount    BIGINT  NOT NULL,
+    cl_rewards_gwei       BIGINT  NOT NULL,
+    el_rewards_wei        DECIMAL NOT NULL,
+    active_days           INT     NOT NULL,
+    missed_attestations   INT     NOT NULL,
+    proposed_blocks       INT     NOT NULL,
+    missed_blocks         INT     NOT NULL,
+    participated_sync     INT     NOT NULL,
+    missed_sync           INT     NOT NULL,
+    foreign key (dashboard_id) references users_val_dashboards(id) ON DELETE CASCADE,
+    primary key (dashboard_id, day)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop daily rollup of the validator stats of validator dashboards');
+DROP TABLE IF EXISTS users_val_dashboards_stats;
+-- +goose StatementEnd
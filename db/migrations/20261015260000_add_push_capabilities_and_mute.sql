@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add push capabilities of devices and muting of subscriptions');
+ALTER TABLE users_devices ADD COLUMN IF NOT EXISTS capabilities TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE users_subscriptions ADD COLUMN IF NOT EXISTS muted_until TIMESTAMP WITHOUT TIME ZONE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - remove push capabilities of devices and muting of subscriptions');
+ALTER TABLE users_devices DROP COLUMN IF EXISTS capabilities;
+ALTER TABLE users_subscriptions DROP COLUMN IF EXISTS muted_until;
+-- +goose StatementEnd
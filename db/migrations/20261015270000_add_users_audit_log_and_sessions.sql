@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add audit log and sessions of users');
+CREATE TABLE IF NOT EXISTS users_audit_log (
+    id         BIGSERIAL                   NOT NULL,
+    user_id    INT                         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action     VARCHAR(50)                 NOT NULL,
+    ip         VARCHAR(45)                 NOT NULL DEFAULT '',
+    user_agent VARCHAR(500)                NOT NULL DEFAULT '',
+    details    JSONB,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_users_audit_log_user_id_created_at ON users_audit_log (user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS users_sessions (
+    id         BIGSERIAL                   NOT NULL,
+    user_id    INT                         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash BYTEA                       NOT NULL UNIQUE,
+    ip         VARCHAR(45)                 NOT NULL DEFAULT '',
+    user_agent VARCHAR(500)                NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    ended_at   TIMESTAMP WITHOUT TIME ZONE,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_users_sessions_user_id ON users_sessions (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - remove audit log and sessions of users');
+DROP TABLE IF EXISTS users_sessions;
+DROP TABLE IF EXISTS users_audit_log;
+-- +goose StatementEnd
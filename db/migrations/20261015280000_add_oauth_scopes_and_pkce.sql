@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add scopes and pkce to the oauth flow');
+ALTER TABLE oauth_apps ADD COLUMN IF NOT EXISTS first_party BOOLEAN NOT NULL DEFAULT false;
+-- all apps registered so far are our own mobile apps, third-party apps are restricted to scoped tokens
+UPDATE oauth_apps SET first_party = true;
+ALTER TABLE oauth_codes ADD COLUMN IF NOT EXISTS scope TEXT;
+ALTER TABLE oauth_codes ADD COLUMN IF NOT EXISTS code_challenge VARCHAR(128);
+ALTER TABLE users_devices ADD COLUMN IF NOT EXISTS scope TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - remove scopes and pkce from the oauth flow');
+ALTER TABLE users_devices DROP COLUMN IF EXISTS scope;
+ALTER TABLE oauth_codes DROP COLUMN IF EXISTS code_challenge;
+ALTER TABLE oauth_codes DROP COLUMN IF EXISTS scope;
+ALTER TABLE oauth_apps DROP COLUMN IF EXISTS first_party;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add webauthn credentials and recovery codes of users');
+CREATE TABLE IF NOT EXISTS users_webauthn_credentials (
+    id            BIGSERIAL                   NOT NULL,
+    user_id       INT                         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    credential_id BYTEA                       NOT NULL UNIQUE,
+    public_key    BYTEA                       NOT NULL,
+    algorithm     INT                         NOT NULL,
+    sign_count    BIGINT                      NOT NULL DEFAULT 0,
+    name          VARCHAR(100)                NOT NULL DEFAULT '',
+    created_at    TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    last_used_at  TIMESTAMP WITHOUT TIME ZONE,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_users_webauthn_credentials_user_id ON users_webauthn_credentials (user_id);
+
+CREATE TABLE IF NOT EXISTS users_recovery_codes (
+    id        BIGSERIAL                   NOT NULL,
+    user_id   INT                         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64)                 NOT NULL,
+    used_at   TIMESTAMP WITHOUT TIME ZONE,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_users_recovery_codes_user_id ON users_recovery_codes (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - remove webauthn credentials and recovery codes of users');
+DROP TABLE IF EXISTS users_recovery_codes;
+DROP TABLE IF EXISTS users_webauthn_credentials;
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add organizations sharing dashboards, webhooks and api quota between users');
+CREATE TABLE IF NOT EXISTS organizations (
+    id         BIGSERIAL                   NOT NULL,
+    name       VARCHAR(100)                NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id)
+);
+
+-- a user can only be member of one organization, the member with the owner role pays for the shared api quota
+CREATE TABLE IF NOT EXISTS organization_members (
+    organization_id BIGINT                      NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id         INT                         NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    role            VARCHAR(10)                 NOT NULL,
+    joined_at       TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+-- invites are accepted by the user logged in with the invited email
+CREATE TABLE IF NOT EXISTS organization_invites (
+    id              BIGSERIAL                   NOT NULL,
+    organization_id BIGINT                      NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    email           VARCHAR(100)                NOT NULL,
+    role            VARCHAR(10)                 NOT NULL,
+    invited_by      INT                         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at      TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id),
+    UNIQUE (organization_id, email)
+);
+
+ALTER TABLE users_val_dashboards ADD COLUMN IF NOT EXISTS organization_id BIGINT;
+CREATE INDEX IF NOT EXISTS idx_users_val_dashboards_organization_id ON users_val_dashboards (organization_id);
+ALTER TABLE users_webhooks ADD COLUMN IF NOT EXISTS organization_id BIGINT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - remove organizations');
+ALTER TABLE users_webhooks DROP COLUMN IF EXISTS organization_id;
+DROP INDEX IF EXISTS idx_users_val_dashboards_organization_id;
+ALTER TABLE users_val_dashboards DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organization_invites;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add bigtable_cells table for the postgres bigtable backend');
+-- every cell of every bigtable table, only used if bigtable.backend is set to postgres
+CREATE TABLE IF NOT EXISTS bigtable_cells (
+    table_name VARCHAR(100) NOT NULL,
+    row_key    BYTEA        NOT NULL,
+    family     VARCHAR(100) NOT NULL,
+    qualifier  BYTEA        NOT NULL,
+    ts         BIGINT       NOT NULL,
+    value      BYTEA        NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bigtable_cells ON bigtable_cells (table_name, row_key, family, qualifier, ts DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - remove bigtable_cells table');
+DROP TABLE IF EXISTS bigtable_cells;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add network_liveness_daily table for network liveness rolled up by the retention service');
+-- day is the unix timestamp of the start of the day, max_finality_delay the max distance between head and finalized epoch
+CREATE TABLE IF NOT EXISTS network_liveness_daily (
+    day                BIGINT NOT NULL,
+    max_finality_delay INT    NOT NULL,
+    PRIMARY KEY (day)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - remove network_liveness_daily table');
+DROP TABLE IF EXISTS network_liveness_daily;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add bigtable_mutation_queue table for bigtable mutations that failed to apply');
+-- mutation_key is the hash of table, row key and mutation so the same mutation is queued only once
+CREATE TABLE IF NOT EXISTS bigtable_mutation_queue (
+    mutation_key    BYTEA     NOT NULL,
+    table_name      TEXT      NOT NULL,
+    row_key         TEXT      NOT NULL,
+    mutation        BYTEA     NOT NULL,
+    attempts        INT       NOT NULL DEFAULT 0,
+    last_error      TEXT      NOT NULL DEFAULT '',
+    created_ts      TIMESTAMP NOT NULL DEFAULT NOW(),
+    next_attempt_ts TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (mutation_key)
+);
+CREATE INDEX IF NOT EXISTS idx_bigtable_mutation_queue_next_attempt_ts ON bigtable_mutation_queue (next_attempt_ts);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - remove bigtable_mutation_queue table');
+DROP TABLE IF EXISTS bigtable_mutation_queue;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add consistency_audit_reports table for the reports of the consistency audit service');
+CREATE TABLE IF NOT EXISTS consistency_audit_reports (
+    id         BIGSERIAL NOT NULL,
+    ts         TIMESTAMP NOT NULL DEFAULT NOW(),
+    mismatches INT       NOT NULL,
+    report     JSONB     NOT NULL,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_consistency_audit_reports_ts ON consistency_audit_reports (ts);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - remove consistency_audit_reports table');
+DROP TABLE IF EXISTS consistency_audit_reports;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add export_gaps table for slots, epochs and blocks missing in the exported data');
+-- kind is slot, epoch, eth1_block (missing in the bigtable blocks table) or eth1_data (missing in the bigtable data table)
+CREATE TABLE IF NOT EXISTS export_gaps (
+    kind        TEXT      NOT NULL,
+    number      BIGINT    NOT NULL,
+    detected_ts TIMESTAMP NOT NULL DEFAULT NOW(),
+    attempts    INT       NOT NULL DEFAULT 0,
+    last_error  TEXT      NOT NULL DEFAULT '',
+    PRIMARY KEY (kind, number)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - remove export_gaps table');
+DROP TABLE IF EXISTS export_gaps;
+-- +goose StatementEnd
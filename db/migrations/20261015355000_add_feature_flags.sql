@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add feature_flags table');
+CREATE TABLE IF NOT EXISTS feature_flags (
+    name        TEXT      NOT NULL PRIMARY KEY,
+    enabled     BOOLEAN   NOT NULL DEFAULT FALSE,
+    description TEXT      NOT NULL DEFAULT '',
+    updated_ts  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - remove feature_flags table');
+DROP TABLE IF EXISTS feature_flags;
+-- +goose StatementEnd
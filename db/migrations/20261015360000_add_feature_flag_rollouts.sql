@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add rollout_percentage to feature_flags');
+-- rollout_percentage is the share of users an enabled flag applies to, users are assigned by a hash of the flag name and the user
+ALTER TABLE feature_flags ADD COLUMN IF NOT EXISTS rollout_percentage INT NOT NULL DEFAULT 100 CHECK (rollout_percentage BETWEEN 0 AND 100);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - remove rollout_percentage from feature_flags');
+ALTER TABLE feature_flags DROP COLUMN IF EXISTS rollout_percentage;
+-- +goose StatementEnd
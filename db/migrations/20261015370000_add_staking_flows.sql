@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create staking_flows_daily table');
+CREATE TABLE IF NOT EXISTS staking_flows_daily (
+    day INT NOT NULL,
+    deposit_count INT NOT NULL,
+    deposit_amount BIGINT NOT NULL,
+    full_withdrawal_count INT NOT NULL,
+    full_withdrawal_amount BIGINT NOT NULL,
+    partial_withdrawal_amount BIGINT NOT NULL,
+    PRIMARY KEY (day)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create staking_flows_entities_daily table');
+CREATE TABLE IF NOT EXISTS staking_flows_entities_daily (
+    day INT NOT NULL,
+    entity VARCHAR(40) NOT NULL,
+    deposit_count INT NOT NULL,
+    deposit_amount BIGINT NOT NULL,
+    full_withdrawal_count INT NOT NULL,
+    full_withdrawal_amount BIGINT NOT NULL,
+    PRIMARY KEY (day, entity)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create staking_flows_daily_status table');
+CREATE TABLE IF NOT EXISTS staking_flows_daily_status (
+    day INT NOT NULL,
+    status BOOLEAN NOT NULL,
+    PRIMARY KEY (day)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop staking_flows_daily_status table');
+DROP TABLE IF EXISTS staking_flows_daily_status;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop staking_flows_entities_daily table');
+DROP TABLE IF EXISTS staking_flows_entities_daily;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop staking_flows_daily table');
+DROP TABLE IF EXISTS staking_flows_daily;
+-- +goose StatementEnd
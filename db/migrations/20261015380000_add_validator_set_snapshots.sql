@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create validator_set_snapshots table');
+CREATE TABLE IF NOT EXISTS validator_set_snapshots (
+    epoch INT NOT NULL,
+    validatorindex INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    balance BIGINT NOT NULL,
+    effectivebalance BIGINT NOT NULL,
+    withdrawalcredentials bytea NOT NULL,
+    slashed BOOLEAN NOT NULL,
+    activationeligibilityepoch BIGINT NOT NULL,
+    activationepoch BIGINT NOT NULL,
+    exitepoch BIGINT NOT NULL,
+    withdrawableepoch BIGINT NOT NULL,
+    PRIMARY KEY (epoch, validatorindex)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop validator_set_snapshots table');
+DROP TABLE IF EXISTS validator_set_snapshots;
+-- +goose StatementEnd
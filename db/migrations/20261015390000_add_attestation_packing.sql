@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create attestation_packing table');
+CREATE TABLE IF NOT EXISTS attestation_packing (
+    slot INT NOT NULL,
+    proposer INT NOT NULL,
+    included_votes INT NOT NULL,
+    missed_votes INT NOT NULL,
+    late_votes INT NOT NULL,
+    score FLOAT NOT NULL,
+    PRIMARY KEY (slot)
+);
+CREATE INDEX IF NOT EXISTS idx_attestation_packing_proposer ON attestation_packing (proposer);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create attestation_packing_status table');
+CREATE TABLE IF NOT EXISTS attestation_packing_status (
+    day INT NOT NULL,
+    status BOOLEAN NOT NULL,
+    PRIMARY KEY (day)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop attestation_packing_status table');
+DROP TABLE IF EXISTS attestation_packing_status;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop attestation_packing table');
+DROP TABLE IF EXISTS attestation_packing;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create validator_effectiveness table');
+CREATE TABLE IF NOT EXISTS validator_effectiveness (
+    validatorindex INT NOT NULL,
+    day INT NOT NULL,
+    version INT NOT NULL,
+    score FLOAT NOT NULL,
+    attestation_correctness FLOAT NOT NULL,
+    inclusion_delay FLOAT,
+    proposal FLOAT,
+    sync FLOAT,
+    PRIMARY KEY (validatorindex, day, version)
+);
+CREATE INDEX IF NOT EXISTS idx_validator_effectiveness_day ON validator_effectiveness (day, version);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create validator_effectiveness_status table');
+CREATE TABLE IF NOT EXISTS validator_effectiveness_status (
+    day INT NOT NULL,
+    version INT NOT NULL,
+    status BOOLEAN NOT NULL,
+    PRIMARY KEY (day, version)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop validator_effectiveness_status table');
+DROP TABLE IF EXISTS validator_effectiveness_status;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop validator_effectiveness table');
+DROP TABLE IF EXISTS validator_effectiveness;
+-- +goose StatementEnd
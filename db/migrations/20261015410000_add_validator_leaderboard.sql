@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create validator_leaderboard materialized view');
+-- the rollup covers windows of 1, 7 and 31 days ending with the latest exported day of the validator statistics.
+-- a day counts as active if the validator was active during the whole day (expected_proposals is set).
+-- the efficiency is the average effectiveness score of the version set in explorer.effectiveness_score_version by the
+-- statistics exporter when refreshing the view, the view is populated by its first refresh.
+CREATE MATERIALIZED VIEW IF NOT EXISTS validator_leaderboard AS
+WITH latest AS (
+    SELECT COALESCE(MAX(day), 0) AS day FROM validator_stats_status WHERE status
+), windows(days) AS (
+    VALUES (1), (7), (31)
+)
+SELECT
+    w.days AS window_days,
+    vs.validatorindex,
+    COALESCE(vp.pool, 'Unknown') AS entity,
+    COUNT(*) FILTER (WHERE vs.expected_proposals IS NOT NULL) AS active_days,
+    SUM(COALESCE(vs.cl_rewards_gwei, 0) + COALESCE(vs.el_rewards_wei, 0) / 1e9)::BIGINT AS income_gwei,
+    AVG(ve.score) AS efficiency
+FROM windows w
+CROSS JOIN latest l
+INNER JOIN validator_stats vs ON vs.day > l.day - w.days AND vs.day <= l.day
+INNER JOIN validators v ON v.validatorindex = vs.validatorindex
+LEFT JOIN validator_pool vp ON vp.publickey = v.pubkey
+LEFT JOIN validator_effectiveness ve ON ve.validatorindex = vs.validatorindex AND ve.day = vs.day AND ve.version = current_setting('explorer.effectiveness_score_version', true)::INT
+GROUP BY w.days, vs.validatorindex, vp.pool
+WITH NO DATA;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_validator_leaderboard_validator ON validator_leaderboard (window_days, validatorindex);
+CREATE INDEX IF NOT EXISTS idx_validator_leaderboard_income ON validator_leaderboard (window_days, income_gwei);
+CREATE INDEX IF NOT EXISTS idx_validator_leaderboard_efficiency ON validator_leaderboard (window_days, efficiency);
+CREATE INDEX IF NOT EXISTS idx_validator_leaderboard_entity ON validator_leaderboard (window_days, entity);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop validator_leaderboard materialized view');
+DROP MATERIALIZED VIEW IF EXISTS validator_leaderboard;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add locale column to users');
+-- the locale selects the language of the emails sent to the user, it has to be one of mail.Locales
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale TEXT NOT NULL DEFAULT 'en-US';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop locale column from users');
+ALTER TABLE users DROP COLUMN IF EXISTS locale;
+-- +goose StatementEnd
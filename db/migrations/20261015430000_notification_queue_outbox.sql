@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add dedup_key and claimed columns to notification_queue');
+-- the dedup key prevents notifications collected twice from being queued twice, claimed records when the sender
+-- handed the entry to the provider so an entry is never sent again after the sender was interrupted
+ALTER TABLE notification_queue ADD COLUMN IF NOT EXISTS dedup_key TEXT;
+ALTER TABLE notification_queue ADD COLUMN IF NOT EXISTS claimed TIMESTAMP WITHOUT TIME ZONE;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_queue_dedup_key ON notification_queue (dedup_key);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop dedup_key and claimed columns from notification_queue');
+DROP INDEX IF EXISTS idx_notification_queue_dedup_key;
+ALTER TABLE notification_queue DROP COLUMN IF EXISTS claimed;
+ALTER TABLE notification_queue DROP COLUMN IF EXISTS dedup_key;
+-- +goose StatementEnd
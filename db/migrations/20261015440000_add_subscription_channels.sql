@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add channels column to users_subscriptions');
+-- the channels the notifications of the subscription are sent on, NULL sends them on all channels of the user
+ALTER TABLE users_subscriptions ADD COLUMN IF NOT EXISTS channels notification_channels[];
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop channels column from users_subscriptions');
+ALTER TABLE users_subscriptions DROP COLUMN IF EXISTS channels;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create users_validators_fee_recipients table');
+-- the fee recipient a user expects the blocks of a validator to pay to, proposals with a different fee recipient trigger
+-- the validator_fee_recipient_mismatch notification
+CREATE TABLE IF NOT EXISTS users_validators_fee_recipients (
+    user_id INT NOT NULL,
+    network VARCHAR(20) NOT NULL,
+    validator_publickey BYTEA NOT NULL,
+    fee_recipient BYTEA NOT NULL,
+    created_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+    PRIMARY KEY (user_id, network, validator_publickey)
+);
+CREATE INDEX IF NOT EXISTS idx_users_validators_fee_recipients_validator_publickey ON users_validators_fee_recipients (network, validator_publickey);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop users_validators_fee_recipients table');
+DROP TABLE IF EXISTS users_validators_fee_recipients;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create relays_validator_registrations table');
+-- the result of the last check of a validator registration at a relay, unregistered_ts is set when a registration disappears
+CREATE TABLE IF NOT EXISTS relays_validator_registrations (
+    tag_id VARCHAR NOT NULL,
+    pubkey BYTEA NOT NULL,
+    registered BOOLEAN NOT NULL,
+    fee_recipient BYTEA NULL,
+    gas_limit BIGINT NULL,
+    registration_ts TIMESTAMP WITHOUT TIME ZONE NULL,
+    checked_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    unregistered_ts TIMESTAMP WITHOUT TIME ZONE NULL,
+    PRIMARY KEY (pubkey, tag_id)
+);
+CREATE INDEX IF NOT EXISTS idx_relays_validator_registrations_unregistered_ts ON relays_validator_registrations (unregistered_ts) WHERE unregistered_ts IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop relays_validator_registrations table');
+DROP TABLE IF EXISTS relays_validator_registrations;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create blocks_production table');
+-- the production path of a proposed block: the relay and builder that delivered it, the value the relay reported, the
+-- reward of the execution fee recipient and the highest bid any relay received for the slot
+CREATE TABLE IF NOT EXISTS blocks_production (
+    block_slot INT NOT NULL,
+    block_root BYTEA NOT NULL,
+    exec_block_hash BYTEA NOT NULL,
+    proposer INT NOT NULL,
+    locally_built BOOLEAN NOT NULL,
+    relay_tag_id VARCHAR NULL,
+    builder_pubkey BYTEA NULL,
+    delivered_value NUMERIC NULL,
+    fee_recipient_reward NUMERIC NULL,
+    winning_bid_tag_id VARCHAR NULL,
+    winning_bid_builder_pubkey BYTEA NULL,
+    winning_bid_block_hash BYTEA NULL,
+    winning_bid_value NUMERIC NULL,
+    updated_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+    PRIMARY KEY (block_slot, block_root)
+);
+CREATE INDEX IF NOT EXISTS idx_blocks_production_exec_block_hash ON blocks_production (exec_block_hash);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop blocks_production table');
+DROP TABLE IF EXISTS blocks_production;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create pool_risk_history table');
+CREATE TABLE IF NOT EXISTS pool_risk_history (
+    day INT NOT NULL,
+    pool VARCHAR(40) NOT NULL,
+    validators INT NOT NULL,
+    validators_total INT NOT NULL,
+    slashed_validators INT NOT NULL,
+    slashed_validators_1y INT NOT NULL,
+    slashing_rate FLOAT NOT NULL,
+    operators INT NOT NULL,
+    max_operator_validators INT NOT NULL,
+    operator_concentration FLOAT NOT NULL,
+    correlation_penalty FLOAT NOT NULL,
+    risk_score FLOAT NOT NULL,
+    PRIMARY KEY (day, pool)
+);
+CREATE INDEX IF NOT EXISTS idx_pool_risk_history_pool ON pool_risk_history (pool, day DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop pool_risk_history table');
+DROP TABLE IF EXISTS pool_risk_history;
+-- +goose StatementEnd
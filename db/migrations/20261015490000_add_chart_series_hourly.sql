@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create chart_series_hourly table');
+CREATE TABLE IF NOT EXISTS chart_series_hourly (
+    "time" TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    indicator CHARACTER VARYING(50) NOT NULL,
+    value NUMERIC NOT NULL,
+    PRIMARY KEY (indicator, "time")
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop chart_series_hourly table');
+DROP TABLE IF EXISTS chart_series_hourly;
+-- +goose StatementEnd
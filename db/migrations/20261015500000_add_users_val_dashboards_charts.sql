@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create users_val_dashboards_charts table');
+CREATE TABLE IF NOT EXISTS users_val_dashboards_charts (
+    id BIGSERIAL NOT NULL,
+    dashboard_id BIGINT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    metric VARCHAR(30) NOT NULL,
+    aggregation VARCHAR(10) NOT NULL,
+    group_ids SMALLINT[] NOT NULL DEFAULT '{}',
+    per_group BOOLEAN NOT NULL DEFAULT FALSE,
+    days INT NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (dashboard_id) REFERENCES users_val_dashboards(id) ON DELETE CASCADE,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_users_val_dashboards_charts_dashboard_id ON users_val_dashboards_charts (dashboard_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop users_val_dashboards_charts table');
+DROP TABLE IF EXISTS users_val_dashboards_charts;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create network_incidents table');
+CREATE TABLE IF NOT EXISTS network_incidents (
+    id BIGSERIAL NOT NULL,
+    metric VARCHAR(30) NOT NULL,
+    start_epoch INT NOT NULL,
+    end_epoch INT NOT NULL,
+    value FLOAT NOT NULL,
+    baseline FLOAT NOT NULL,
+    z_score FLOAT NOT NULL,
+    detected_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_network_incidents_metric_epochs ON network_incidents (metric, start_epoch, end_epoch);
+CREATE INDEX IF NOT EXISTS idx_network_incidents_detected_ts ON network_incidents (detected_ts);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop network_incidents table');
+DROP TABLE IF EXISTS network_incidents;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create faucet_requests table');
+CREATE TABLE IF NOT EXISTS faucet_requests (
+    id BIGSERIAL NOT NULL,
+    address BYTEA NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    amount NUMERIC NOT NULL,
+    tx_hash BYTEA NOT NULL,
+    created_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_faucet_requests_address ON faucet_requests (address, created_ts);
+CREATE INDEX IF NOT EXISTS idx_faucet_requests_ip ON faucet_requests (ip, created_ts);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop faucet_requests table');
+DROP TABLE IF EXISTS faucet_requests;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create validator_milestones table');
+CREATE TABLE IF NOT EXISTS validator_milestones (
+    validatorindex INT NOT NULL,
+    milestone VARCHAR(20) NOT NULL,
+    day INT NOT NULL,
+    detected_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (validatorindex, milestone)
+);
+CREATE INDEX IF NOT EXISTS idx_validator_milestones_detected_ts ON validator_milestones (detected_ts);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop validator_milestones table');
+DROP TABLE IF EXISTS validator_milestones;
+-- +goose StatementEnd
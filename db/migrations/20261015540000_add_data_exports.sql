@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create data_exports table');
+CREATE TABLE IF NOT EXISTS data_exports (
+    dataset VARCHAR(40) NOT NULL,
+    day INT NOT NULL,
+    object_key TEXT NOT NULL,
+    rows BIGINT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    created_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (dataset, day)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop data_exports table');
+DROP TABLE IF EXISTS data_exports;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create l2_bridge_flows_daily table');
+CREATE TABLE IF NOT EXISTS l2_bridge_flows_daily (
+    day INT NOT NULL,
+    network VARCHAR(20) NOT NULL,
+    deposit_count INT NOT NULL,
+    deposit_amount NUMERIC NOT NULL,
+    withdrawal_count INT NOT NULL,
+    withdrawal_amount NUMERIC NOT NULL,
+    PRIMARY KEY (day, network)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('up SQL query - create l2_bridge_flows_daily_status table');
+CREATE TABLE IF NOT EXISTS l2_bridge_flows_daily_status (
+    day INT NOT NULL,
+    status BOOLEAN NOT NULL,
+    PRIMARY KEY (day)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop l2_bridge_flows_daily_status table');
+DROP TABLE IF EXISTS l2_bridge_flows_daily_status;
+-- +goose StatementEnd
+-- +goose StatementBegin
+SELECT('down SQL query - drop l2_bridge_flows_daily table');
+DROP TABLE IF EXISTS l2_bridge_flows_daily;
+-- +goose StatementEnd
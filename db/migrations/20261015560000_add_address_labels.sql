@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create address_labels table');
+CREATE TABLE IF NOT EXISTS address_labels (
+    id SERIAL NOT NULL,
+    address bytea NOT NULL,
+    label VARCHAR(50) NOT NULL,
+    category VARCHAR(20) NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    submitted_by INT NULL,
+    created_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+    reviewed_ts TIMESTAMP WITHOUT TIME ZONE NULL,
+    reviewed_by INT NULL,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_address_labels_address ON address_labels (address, status);
+CREATE INDEX IF NOT EXISTS idx_address_labels_status ON address_labels (status, created_ts);
+CREATE INDEX IF NOT EXISTS idx_address_labels_pending_submitted_by ON address_labels (submitted_by) WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop address_labels table');
+DROP TABLE IF EXISTS address_labels;
+-- +goose StatementEnd
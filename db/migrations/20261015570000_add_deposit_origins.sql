@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create deposit_origins table');
+CREATE TABLE IF NOT EXISTS deposit_origins (
+    from_address bytea NOT NULL,
+    origin_kind VARCHAR(20) NOT NULL,
+    origin_address bytea NULL,
+    origin_label VARCHAR(50) NOT NULL DEFAULT '',
+    hops INT NOT NULL,
+    path bytea[] NOT NULL,
+    traced_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    PRIMARY KEY (from_address)
+);
+CREATE INDEX IF NOT EXISTS idx_deposit_origins_kind ON deposit_origins (origin_kind, traced_ts);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop deposit_origins table');
+DROP TABLE IF EXISTS deposit_origins;
+-- +goose StatementEnd
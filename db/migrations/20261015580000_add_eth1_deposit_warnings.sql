@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create eth1_deposit_warnings table');
+CREATE TABLE IF NOT EXISTS eth1_deposit_warnings (
+    tx_hash bytea NOT NULL,
+    merkletree_index bytea NOT NULL,
+    publickey bytea NOT NULL,
+    warning VARCHAR(20) NOT NULL,
+    detected_ts TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tx_hash, merkletree_index)
+);
+CREATE INDEX IF NOT EXISTS idx_eth1_deposit_warnings_publickey ON eth1_deposit_warnings (publickey);
+CREATE INDEX IF NOT EXISTS idx_eth1_deposit_warnings_detected_ts ON eth1_deposit_warnings (detected_ts);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop eth1_deposit_warnings table');
+DROP TABLE IF EXISTS eth1_deposit_warnings;
+-- +goose StatementEnd
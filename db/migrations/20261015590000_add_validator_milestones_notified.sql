@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add notified_ts column to validator_milestones');
+ALTER TABLE validator_milestones ADD COLUMN IF NOT EXISTS notified_ts TIMESTAMP WITHOUT TIME ZONE NULL;
+UPDATE validator_milestones SET notified_ts = detected_ts WHERE notified_ts IS NULL;
+CREATE INDEX IF NOT EXISTS idx_validator_milestones_not_notified ON validator_milestones (detected_ts) WHERE notified_ts IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop notified_ts column from validator_milestones');
+DROP INDEX IF EXISTS idx_validator_milestones_not_notified;
+ALTER TABLE validator_milestones DROP COLUMN IF EXISTS notified_ts;
+-- +goose StatementEnd
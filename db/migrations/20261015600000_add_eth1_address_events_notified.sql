@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - create eth1_address_events_notified table');
+CREATE TABLE IF NOT EXISTS eth1_address_events_notified (
+    block_number INT NOT NULL,
+    ts TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    PRIMARY KEY (block_number)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - drop eth1_address_events_notified table');
+DROP TABLE IF EXISTS eth1_address_events_notified;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT('up SQL query - add embed keys of users');
+CREATE TABLE IF NOT EXISTS users_embed_keys (
+    id         BIGSERIAL                   NOT NULL,
+    user_id    INT                         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    key        VARCHAR(32)                 NOT NULL UNIQUE,
+    path       VARCHAR(256)                NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITHOUT TIME ZONE,
+    PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_users_embed_keys_user_id ON users_embed_keys (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT('down SQL query - remove embed keys of users');
+DROP TABLE IF EXISTS users_embed_keys;
+-- +goose StatementEnd
@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+)
+
+// writeValidatorMilestonesForDay detects the milestones the validators reached up to the day. Milestones are reached
+// once, validators which already reached a milestone are skipped. The day of a milestone is the day it was actually
+// reached, which is older than the exported day for milestones that were reached before they were detected. first_eth is
+// only detected for validators crossing 1 ETH of rewards on the exported day to keep the statistics transaction short,
+// earlier days are covered by BackfillValidatorFirstEthMilestones.
+func writeValidatorMilestonesForDay(day uint64, tx pgx.Tx) error {
+	epochsPerDay := utils.EpochsPerDay()
+	slotsPerDay := epochsPerDay * utils.Config.Chain.ClConfig.SlotsPerEpoch
+
+	_, err := tx.Exec(context.Background(), `
+		WITH stats AS (
+			SELECT
+				validatorindex,
+				COALESCE(proposed_blocks, 0) AS proposed_blocks,
+				COALESCE(cl_rewards_gwei_total, 0)::NUMERIC * 1e9 + COALESCE(el_rewards_wei_total, 0) AS rewards_total
+			FROM validator_stats
+			WHERE day = $1
+		)
+		INSERT INTO validator_milestones (validatorindex, milestone, day, detected_ts)
+		SELECT s.validatorindex, 'first_proposal', (SELECT MIN(b.slot) FROM blocks b WHERE b.proposer = s.validatorindex AND b.status = '1') / $3, clock_timestamp()
+		FROM stats s
+		WHERE s.proposed_blocks > 0
+			AND NOT EXISTS (SELECT 1 FROM validator_milestones m WHERE m.validatorindex = s.validatorindex AND m.milestone = 'first_proposal')
+		UNION ALL
+		SELECT s.validatorindex, 'first_eth', $1, clock_timestamp()
+		FROM stats s
+		LEFT JOIN validator_stats p ON p.validatorindex = s.validatorindex AND p.day = $1 - 1
+		WHERE s.rewards_total >= 1e18
+			AND COALESCE(p.cl_rewards_gwei_total, 0)::NUMERIC * 1e9 + COALESCE(p.el_rewards_wei_total, 0) < 1e18
+			AND NOT EXISTS (SELECT 1 FROM validator_milestones m WHERE m.validatorindex = s.validatorindex AND m.milestone = 'first_eth')
+		UNION ALL
+		SELECT v.validatorindex, 'one_year', v.activationepoch / $2 + 365, clock_timestamp()
+		FROM validators v
+		WHERE v.activationepoch / $2 + 365 <= $1
+			AND v.exitepoch > (v.activationepoch / $2 + 366) * $2
+			AND NOT EXISTS (SELECT 1 FROM validator_milestones m WHERE m.validatorindex = v.validatorindex AND m.milestone = 'one_year')
+		UNION ALL
+		SELECT vs.validatorindex, 'perfect_month', $1, clock_timestamp()
+		FROM validator_stats vs
+		INNER JOIN validators v ON v.validatorindex = vs.validatorindex
+		WHERE vs.day > $1 - 30 AND vs.day <= $1
+			AND v.activationepoch <= ($1 - 29) * $2 AND v.exitepoch > ($1 + 1) * $2
+			AND NOT EXISTS (SELECT 1 FROM validator_milestones m WHERE m.validatorindex = vs.validatorindex AND m.milestone = 'perfect_month')
+		GROUP BY vs.validatorindex
+		HAVING COUNT(*) = 30 AND SUM(COALESCE(vs.missed_attestations, 0)) = 0
+		ON CONFLICT (validatorindex, milestone) DO NOTHING`, int64(day), int64(epochsPerDay), int64(slotsPerDay))
+	if err != nil {
+		return fmt.Errorf("error writing validator milestones of day %v: %w", day, err)
+	}
+	return nil
+}
+
+// GetValidatorMilestones returns the milestones the validator reached, oldest first
+func GetValidatorMilestones(validatorIndex uint64) ([]*types.ValidatorMilestone, error) {
+	milestones := []*types.ValidatorMilestone{}
+	err := ReaderDb.Select(&milestones, `
+		SELECT validatorindex, milestone, day, detected_ts
+		FROM validator_milestones
+		WHERE validatorindex = $1
+		ORDER BY day, milestone`, validatorIndex)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving milestones of validator %v: %w", validatorIndex, err)
+	}
+	return milestones, nil
+}
+
+// GetValidatorMilestone returns the milestone of the validator or nil if the validator did not reach it
+func GetValidatorMilestone(validatorIndex uint64, milestone string) (*types.ValidatorMilestone, error) {
+	m := &types.ValidatorMilestone{}
+	err := ReaderDb.Get(m, `
+		SELECT validatorindex, milestone, day, detected_ts
+		FROM validator_milestones
+		WHERE validatorindex = $1 AND milestone = $2`, validatorIndex, milestone)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving milestone %v of validator %v: %w", milestone, validatorIndex, err)
+	}
+	return m, nil
+}
+
+// GetUnnotifiedValidatorMilestones returns the milestones that have not been processed by the notifications collector
+// yet. The milestones are written within the validator statistics transaction and only become visible once it commits,
+// so they are selected by the notified flag instead of the time they were detected.
+func GetUnnotifiedValidatorMilestones() ([]*types.ValidatorMilestone, error) {
+	milestones := []*types.ValidatorMilestone{}
+	err := WriterDb.Select(&milestones, `
+		SELECT validatorindex, milestone, day, detected_ts
+		FROM validator_milestones
+		WHERE notified_ts IS NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving unnotified validator milestones: %w", err)
+	}
+	return milestones, nil
+}
+
+// SetValidatorMilestonesNotified marks the milestones as processed by the notifications collector
+func SetValidatorMilestonesNotified(milestones []*types.ValidatorMilestone) error {
+	if len(milestones) == 0 {
+		return nil
+	}
+	validators := make(pq.Int64Array, 0, len(milestones))
+	names := make(pq.StringArray, 0, len(milestones))
+	for _, m := range milestones {
+		validators = append(validators, int64(m.ValidatorIndex))
+		names = append(names, m.Milestone)
+	}
+	_, err := WriterDb.Exec(`
+		UPDATE validator_milestones m SET notified_ts = NOW()
+		FROM UNNEST($1::INT[], $2::TEXT[]) AS n(validatorindex, milestone)
+		WHERE m.validatorindex = n.validatorindex AND m.milestone = n.milestone`, validators, names)
+	if err != nil {
+		return fmt.Errorf("error marking %v validator milestones as notified: %w", len(milestones), err)
+	}
+	return nil
+}
+
+// BackfillValidatorFirstEthMilestones detects the first_eth milestones of the days in [fromDay, toDay]. The daily
+// statistics export only detects validators that cross 1 ETH of rewards on the exported day, the milestones of earlier
+// days are backfilled by this function outside of the statistics transaction. Backfilled milestones are not notified.
+func BackfillValidatorFirstEthMilestones(fromDay, toDay uint64) error {
+	for day := fromDay; day <= toDay; day++ {
+		start := time.Now()
+		res, err := WriterDb.Exec(`
+			INSERT INTO validator_milestones (validatorindex, milestone, day, detected_ts, notified_ts)
+			SELECT s.validatorindex, 'first_eth', $1, NOW(), NOW()
+			FROM validator_stats s
+			LEFT JOIN validator_stats p ON p.validatorindex = s.validatorindex AND p.day = $1 - 1
+			WHERE s.day = $1
+				AND COALESCE(s.cl_rewards_gwei_total, 0)::NUMERIC * 1e9 + COALESCE(s.el_rewards_wei_total, 0) >= 1e18
+				AND COALESCE(p.cl_rewards_gwei_total, 0)::NUMERIC * 1e9 + COALESCE(p.el_rewards_wei_total, 0) < 1e18
+			ON CONFLICT (validatorindex, milestone) DO NOTHING`, int64(day))
+		if err != nil {
+			return fmt.Errorf("error backfilling first_eth milestones of day %v: %w", day, err)
+		}
+		rows, _ := res.RowsAffected()
+		logger.Infof("backfilled %v first_eth milestones of day %v, took %v", rows, day, time.Since(start))
+	}
+	return nil
+}
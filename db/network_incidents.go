@@ -0,0 +1,114 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// NetworkIncidentMergeEpochs is the maximum gap in epochs between two anomalies of a metric which are stored as one incident
+const NetworkIncidentMergeEpochs = 2
+
+// GetNetworkEpochMetrics returns the participation rate, the missed slot rate (both in percent) and the average
+// attestation inclusion delay (in slots) of the canonical blocks of the exported epochs within [fromEpoch, toEpoch]
+func GetNetworkEpochMetrics(fromEpoch, toEpoch uint64) ([]*types.NetworkEpochMetrics, error) {
+	metrics := []*types.NetworkEpochMetrics{}
+	err := ReaderDb.Select(&metrics, `
+		SELECT
+			e.epoch,
+			e.globalparticipationrate * 100 AS participation_rate,
+			COALESCE(b.missed, 0)::FLOAT / $3 * 100 AS missed_slot_rate,
+			COALESCE(a.delay, 0) AS attestation_delay
+		FROM epochs e
+		LEFT JOIN (
+			SELECT epoch, COUNT(*) FILTER (WHERE status = '2') AS missed
+			FROM blocks
+			WHERE epoch >= $1 AND epoch <= $2
+			GROUP BY epoch
+		) b ON b.epoch = e.epoch
+		LEFT JOIN (
+			SELECT ba.block_slot / $3 AS epoch, AVG(ba.block_slot - ba.slot)::FLOAT AS delay
+			FROM blocks_attestations ba
+			INNER JOIN blocks b ON b.slot = ba.block_slot AND b.blockroot = ba.block_root AND b.status = '1'
+			WHERE ba.block_slot >= $1 * $3 AND ba.block_slot < ($2 + 1) * $3
+			GROUP BY 1
+		) a ON a.epoch = e.epoch
+		WHERE e.epoch >= $1 AND e.epoch <= $2 AND e.globalparticipationrate IS NOT NULL
+		ORDER BY e.epoch`, fromEpoch, toEpoch, utils.Config.Chain.ClConfig.SlotsPerEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving network metrics of epochs %v to %v: %w", fromEpoch, toEpoch, err)
+	}
+	return metrics, nil
+}
+
+// SaveNetworkIncidents stores the detected incidents. An incident overlapping or closely following a stored incident of
+// the same metric extends the stored one, so that repeated detection runs over the same epochs do not duplicate incidents.
+func SaveNetworkIncidents(incidents []*types.ApiNetworkIncidentResponse) error {
+	if len(incidents) == 0 {
+		return nil
+	}
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, incident := range incidents {
+		_, err = tx.Exec(`
+			WITH merged AS (
+				UPDATE network_incidents SET
+					start_epoch = LEAST(start_epoch, $2),
+					end_epoch = GREATEST(end_epoch, $3),
+					value = CASE WHEN $6 > z_score THEN $4 ELSE value END,
+					baseline = CASE WHEN $6 > z_score THEN $5 ELSE baseline END,
+					z_score = GREATEST(z_score, $6)
+				WHERE id = (
+					SELECT id FROM network_incidents
+					WHERE metric = $1 AND start_epoch <= $3 + $7 AND end_epoch + $7 >= $2
+					ORDER BY id
+					LIMIT 1
+				)
+				RETURNING id
+			)
+			INSERT INTO network_incidents (metric, start_epoch, end_epoch, value, baseline, z_score)
+			SELECT $1, $2, $3, $4, $5, $6
+			WHERE NOT EXISTS (SELECT 1 FROM merged)`,
+			incident.Metric, incident.StartEpoch, incident.EndEpoch, incident.Value, incident.Baseline, incident.ZScore, NetworkIncidentMergeEpochs)
+		if err != nil {
+			return fmt.Errorf("error saving %v incident starting at epoch %v: %w", incident.Metric, incident.StartEpoch, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetNetworkIncidents returns the incidents overlapping the epochs within [fromEpoch, toEpoch], latest first
+func GetNetworkIncidents(fromEpoch, toEpoch uint64) ([]*types.ApiNetworkIncidentResponse, error) {
+	incidents := []*types.ApiNetworkIncidentResponse{}
+	err := ReaderDb.Select(&incidents, `
+		SELECT id, metric, start_epoch, end_epoch, value, baseline, z_score, detected_ts
+		FROM network_incidents
+		WHERE start_epoch <= $2 AND end_epoch >= $1
+		ORDER BY start_epoch DESC, metric`, fromEpoch, toEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving network incidents of epochs %v to %v: %w", fromEpoch, toEpoch, err)
+	}
+	return incidents, nil
+}
+
+// GetNetworkIncidentsDetectedSince returns the incidents which were first detected after ts
+func GetNetworkIncidentsDetectedSince(ts time.Time) ([]*types.ApiNetworkIncidentResponse, error) {
+	incidents := []*types.ApiNetworkIncidentResponse{}
+	err := ReaderDb.Select(&incidents, `
+		SELECT id, metric, start_epoch, end_epoch, value, baseline, z_score, detected_ts
+		FROM network_incidents
+		WHERE detected_ts > $1
+		ORDER BY start_epoch, metric`, ts)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving network incidents detected since %v: %w", ts, err)
+	}
+	return incidents, nil
+}
@@ -0,0 +1,355 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// OrganizationInviteValidity is how long an invite to an organization can be accepted
+const OrganizationInviteValidity = utils.Week
+
+// GetUserOrganization returns the organization of a user together with the role of the user, nil if the user is not
+// part of an organization
+func GetUserOrganization(userId uint64) (*types.Organization, error) {
+	organization := &types.Organization{}
+	err := FrontendWriterDB.Get(organization, `
+		SELECT o.id, o.name, o.created_at, m.role
+		FROM organization_members m
+		INNER JOIN organizations o ON o.id = m.organization_id
+		WHERE m.user_id = $1`, userId)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving organization of user %v: %w", userId, err)
+	}
+	return organization, nil
+}
+
+// CreateOrganization creates an organization owned by the user and returns its id
+func CreateOrganization(userId uint64, name string) (uint64, error) {
+	tx, err := FrontendWriterDB.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var organizationId uint64
+	err = tx.Get(&organizationId, `INSERT INTO organizations (name) VALUES ($1) RETURNING id`, truncateString(name, 100))
+	if err != nil {
+		return 0, fmt.Errorf("error inserting organization of user %v: %w", userId, err)
+	}
+	_, err = tx.Exec(`
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES ($1, $2, $3)`, organizationId, userId, types.OrganizationRoleOwner)
+	if err != nil {
+		return 0, fmt.Errorf("error inserting owner of organization %v: %w", organizationId, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("error committing db transaction: %w", err)
+	}
+	return organizationId, nil
+}
+
+// DeleteOrganization deletes an organization, its members get back their own api quota and the shared dashboards and
+// webhooks are only accessible by their owners again
+func DeleteOrganization(organizationId uint64) error {
+	tx, err := FrontendWriterDB.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = touchOrganizationApiKeys(tx, organizationId)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE users_webhooks SET organization_id = NULL WHERE organization_id = $1`, organizationId)
+	if err != nil {
+		return fmt.Errorf("error unsharing webhooks of organization %v: %w", organizationId, err)
+	}
+	_, err = tx.Exec(`DELETE FROM organizations WHERE id = $1`, organizationId)
+	if err != nil {
+		return fmt.Errorf("error deleting organization %v: %w", organizationId, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing db transaction: %w", err)
+	}
+
+	_, err = WriterDb.Exec(`UPDATE users_val_dashboards SET organization_id = NULL WHERE organization_id = $1`, organizationId)
+	if err != nil {
+		return fmt.Errorf("error unsharing validator dashboards of organization %v: %w", organizationId, err)
+	}
+	return nil
+}
+
+// touchOrganizationApiKeys marks the api keys of the members of an organization as changed, the rate limiter then
+// reassigns them to the quota of the owner of the organization of their user
+func touchOrganizationApiKeys(tx *sqlx.Tx, organizationId uint64) error {
+	_, err := tx.Exec(`
+		UPDATE api_keys SET changed_at = NOW()
+		WHERE user_id IN (SELECT user_id FROM organization_members WHERE organization_id = $1)`, organizationId)
+	if err != nil {
+		return fmt.Errorf("error updating api keys of organization %v: %w", organizationId, err)
+	}
+	return nil
+}
+
+// GetOrganizationMembers returns the members of an organization, the owner first
+func GetOrganizationMembers(organizationId uint64) ([]*types.OrganizationMember, error) {
+	members := []*types.OrganizationMember{}
+	err := FrontendReaderDB.Select(&members, `
+		SELECT m.user_id, u.email, m.role, m.joined_at
+		FROM organization_members m
+		INNER JOIN users u ON u.id = m.user_id
+		WHERE m.organization_id = $1
+		ORDER BY m.role = $2 DESC, m.joined_at`, organizationId, types.OrganizationRoleOwner)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving members of organization %v: %w", organizationId, err)
+	}
+	return members, nil
+}
+
+// GetOrganizationMember returns a member of an organization, nil if the user is not part of the organization
+func GetOrganizationMember(organizationId, userId uint64) (*types.OrganizationMember, error) {
+	member := &types.OrganizationMember{}
+	err := FrontendWriterDB.Get(member, `
+		SELECT m.user_id, u.email, m.role, m.joined_at
+		FROM organization_members m
+		INNER JOIN users u ON u.id = m.user_id
+		WHERE m.organization_id = $1 AND m.user_id = $2`, organizationId, userId)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving member %v of organization %v: %w", userId, organizationId, err)
+	}
+	return member, nil
+}
+
+// CountOrganizationSeats returns the number of members and pending invites of an organization
+func CountOrganizationSeats(organizationId uint64) (int, error) {
+	var count int
+	err := FrontendWriterDB.Get(&count, `
+		SELECT
+			(SELECT COUNT(*) FROM organization_members WHERE organization_id = $1) +
+			(SELECT COUNT(*) FROM organization_invites WHERE organization_id = $1 AND created_at > $2)`,
+		organizationId, time.Now().Add(-OrganizationInviteValidity))
+	if err != nil {
+		return 0, fmt.Errorf("error counting members of organization %v: %w", organizationId, err)
+	}
+	return count, nil
+}
+
+// UpdateOrganizationMemberRole changes the role of a member of an organization, the owner can not be changed. Returns
+// false if there is no such member.
+func UpdateOrganizationMemberRole(organizationId, userId uint64, role types.OrganizationRole) (bool, error) {
+	res, err := FrontendWriterDB.Exec(`
+		UPDATE organization_members SET role = $3
+		WHERE organization_id = $1 AND user_id = $2 AND role != $4`, organizationId, userId, role, types.OrganizationRoleOwner)
+	if err != nil {
+		return false, fmt.Errorf("error updating role of member %v of organization %v: %w", userId, organizationId, err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// RemoveOrganizationMember removes a member from an organization, the dashboards and webhooks the member shared with
+// the organization are unshared. The owner can not be removed. Returns false if there is no such member.
+func RemoveOrganizationMember(organizationId, userId uint64) (bool, error) {
+	tx, err := FrontendWriterDB.Beginx()
+	if err != nil {
+		return false, fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		DELETE FROM organization_members
+		WHERE organization_id = $1 AND user_id = $2 AND role != $3`, organizationId, userId, types.OrganizationRoleOwner)
+	if err != nil {
+		return false, fmt.Errorf("error removing member %v of organization %v: %w", userId, organizationId, err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rows == 0 {
+		return false, nil
+	}
+	_, err = tx.Exec(`UPDATE api_keys SET changed_at = NOW() WHERE user_id = $1`, userId)
+	if err != nil {
+		return false, fmt.Errorf("error updating api keys of user %v: %w", userId, err)
+	}
+	_, err = tx.Exec(`UPDATE users_webhooks SET organization_id = NULL WHERE user_id = $1 AND organization_id = $2`, userId, organizationId)
+	if err != nil {
+		return false, fmt.Errorf("error unsharing webhooks of user %v: %w", userId, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, fmt.Errorf("error committing db transaction: %w", err)
+	}
+
+	_, err = WriterDb.Exec(`
+		UPDATE users_val_dashboards SET organization_id = NULL
+		WHERE user_id = $1 AND organization_id = $2`, userId, organizationId)
+	if err != nil {
+		return false, fmt.Errorf("error unsharing validator dashboards of user %v: %w", userId, err)
+	}
+	return true, nil
+}
+
+// AddOrganizationInvite invites the email to an organization, an existing invite of the email is renewed with the new role
+func AddOrganizationInvite(organizationId uint64, email string, role types.OrganizationRole, invitedBy uint64) error {
+	_, err := FrontendWriterDB.Exec(`
+		INSERT INTO organization_invites (organization_id, email, role, invited_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (organization_id, email) DO UPDATE SET role = EXCLUDED.role, invited_by = EXCLUDED.invited_by, created_at = NOW()`,
+		organizationId, email, role, invitedBy)
+	if err != nil {
+		return fmt.Errorf("error inserting invite to organization %v: %w", organizationId, err)
+	}
+	return nil
+}
+
+// GetOrganizationInvites returns the pending invites of an organization, newest first
+func GetOrganizationInvites(organizationId uint64) ([]*types.OrganizationInvite, error) {
+	invites := []*types.OrganizationInvite{}
+	err := FrontendReaderDB.Select(&invites, `
+		SELECT i.id, i.organization_id, o.name AS organization_name, i.email, i.role, i.created_at
+		FROM organization_invites i
+		INNER JOIN organizations o ON o.id = i.organization_id
+		WHERE i.organization_id = $1 AND i.created_at > $2
+		ORDER BY i.created_at DESC`, organizationId, time.Now().Add(-OrganizationInviteValidity))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving invites of organization %v: %w", organizationId, err)
+	}
+	return invites, nil
+}
+
+// GetOrganizationInvitesByEmail returns the pending invites of an email, newest first
+func GetOrganizationInvitesByEmail(email string) ([]*types.OrganizationInvite, error) {
+	invites := []*types.OrganizationInvite{}
+	err := FrontendReaderDB.Select(&invites, `
+		SELECT i.id, i.organization_id, o.name AS organization_name, i.email, i.role, i.created_at
+		FROM organization_invites i
+		INNER JOIN organizations o ON o.id = i.organization_id
+		WHERE i.email = $1 AND i.created_at > $2
+		ORDER BY i.created_at DESC`, email, time.Now().Add(-OrganizationInviteValidity))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving organization invites: %w", err)
+	}
+	return invites, nil
+}
+
+// DeleteOrganizationInvite revokes an invite of an organization, returns false if there is no such invite
+func DeleteOrganizationInvite(organizationId, inviteId uint64) (bool, error) {
+	res, err := FrontendWriterDB.Exec(`DELETE FROM organization_invites WHERE organization_id = $1 AND id = $2`, organizationId, inviteId)
+	if err != nil {
+		return false, fmt.Errorf("error deleting invite %v of organization %v: %w", inviteId, organizationId, err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// DeclineOrganizationInvite deletes an invite of an email, returns false if there is no such invite
+func DeclineOrganizationInvite(email string, inviteId uint64) (bool, error) {
+	res, err := FrontendWriterDB.Exec(`DELETE FROM organization_invites WHERE email = $1 AND id = $2`, email, inviteId)
+	if err != nil {
+		return false, fmt.Errorf("error deleting organization invite %v: %w", inviteId, err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// AcceptOrganizationInvite adds the user to the organization of a pending invite of the email of the user, the other
+// invites of the email are removed as a user can only be part of one organization. Returns nil if there is no such
+// invite.
+func AcceptOrganizationInvite(userId uint64, email string, inviteId uint64) (*types.OrganizationInvite, error) {
+	tx, err := FrontendWriterDB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	invite := &types.OrganizationInvite{}
+	err = tx.Get(invite, `
+		SELECT i.id, i.organization_id, o.name AS organization_name, i.email, i.role, i.created_at
+		FROM organization_invites i
+		INNER JOIN organizations o ON o.id = i.organization_id
+		WHERE i.id = $1 AND i.email = $2 AND i.created_at > $3`, inviteId, email, time.Now().Add(-OrganizationInviteValidity))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving organization invite %v: %w", inviteId, err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO organization_members (organization_id, user_id, role)
+		VALUES ($1, $2, $3)`, invite.OrganizationID, userId, invite.Role)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting member %v of organization %v: %w", userId, invite.OrganizationID, err)
+	}
+	_, err = tx.Exec(`DELETE FROM organization_invites WHERE email = $1`, email)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting organization invites: %w", err)
+	}
+	_, err = tx.Exec(`UPDATE api_keys SET changed_at = NOW() WHERE user_id = $1`, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error updating api keys of user %v: %w", userId, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("error committing db transaction: %w", err)
+	}
+	return invite, nil
+}
+
+// GetOrganizationWebhooks returns the webhooks of the user together with the webhooks the other members shared with the organization
+func GetOrganizationWebhooks(organizationId, userId uint64) ([]*types.OrganizationWebhook, error) {
+	webhooks := []*types.OrganizationWebhook{}
+	err := FrontendReaderDB.Select(&webhooks, `
+		SELECT id, user_id, COALESCE(destination, 'webhook') AS destination, event_names, organization_id IS NOT NULL AS shared
+		FROM users_webhooks
+		WHERE user_id = $1 OR organization_id = $2
+		ORDER BY id`, userId, organizationId)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving webhooks of organization %v: %w", organizationId, err)
+	}
+	return webhooks, nil
+}
+
+// SetUserWebhookOrganization shares a webhook of a user with an organization, the notifications of all members are
+// then also sent to it. A nil organization unshares the webhook. Returns false if the webhook does not exist.
+func SetUserWebhookOrganization(userId, webhookId uint64, organizationId *uint64) (bool, error) {
+	res, err := FrontendWriterDB.Exec(`UPDATE users_webhooks SET organization_id = $3 WHERE user_id = $1 AND id = $2`, userId, webhookId, organizationId)
+	if err != nil {
+		return false, fmt.Errorf("error updating organization of webhook %v: %w", webhookId, err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
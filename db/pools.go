@@ -0,0 +1,205 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// SavePoolAprSnapshots derives the fee-adjusted apr of every pool from the historical pool performance of the days not
+// snapshotted yet (the latest snapshotted day is refreshed). The fee of rocketpool is the average commission of its
+// staking minipools weighted by the borrowed eth, the fees of other pools are taken from staking_pool_fees, pools without
+// a known fee (e.g. solo stakers) are assumed to not charge one.
+func SavePoolAprSnapshots() error {
+	_, err := WriterDb.Exec(`
+		WITH rocketpool_fee AS (
+			SELECT SUM(node_fee * user_deposit_balance) / NULLIF(SUM(user_deposit_balance), 0) AS fee
+			FROM rocketpool_minipools
+			WHERE status = 'Staking'
+		)
+		INSERT INTO pool_apr_history (day, pool, validators, consensus_apr, execution_apr, gross_apr, fee, net_apr)
+		SELECT
+			h.day,
+			h.pool,
+			h.validators,
+			COALESCE(h.consensus_rewards_sum_wei / NULLIF(h.effective_balances_sum_wei, 0) * 365, 0),
+			COALESCE(h.tx_fees_sum_wei / NULLIF(h.effective_balances_sum_wei, 0) * 365, 0),
+			h.apr,
+			f.fee,
+			h.apr * (1 - f.fee)
+		FROM historical_pool_performance h
+		LEFT JOIN staking_pool_fees spf ON spf.pool = h.pool
+		LEFT JOIN LATERAL (
+			SELECT COALESCE(CASE WHEN h.pool = 'rocketpool' THEN (SELECT fee FROM rocketpool_fee) END, spf.fee, 0) AS fee
+		) f ON true
+		WHERE h.day >= (SELECT COALESCE(MAX(day), 0) FROM pool_apr_history)
+		ON CONFLICT (day, pool) DO UPDATE SET
+			validators = excluded.validators,
+			consensus_apr = excluded.consensus_apr,
+			execution_apr = excluded.execution_apr,
+			gross_apr = excluded.gross_apr,
+			fee = excluded.fee,
+			net_apr = excluded.net_apr`)
+	if err != nil {
+		return fmt.Errorf("error saving pool apr snapshots: %w", err)
+	}
+	return nil
+}
+
+// GetPoolAprComparison returns the average apr of every pool over the last days (in percent), pools with the most validators first
+func GetPoolAprComparison(days uint64) ([]*types.ApiPoolAprResponse, error) {
+	pools := []*types.ApiPoolAprResponse{}
+	err := ReaderDb.Select(&pools, `
+		WITH latest AS (
+			SELECT COALESCE(MAX(day), 0) AS day FROM pool_apr_history
+		)
+		SELECT
+			h.pool,
+			(ARRAY_AGG(h.validators ORDER BY h.day DESC))[1] AS validators,
+			AVG(h.consensus_apr) * 100 AS consensus_apr,
+			AVG(h.execution_apr) * 100 AS execution_apr,
+			AVG(h.gross_apr) * 100 AS gross_apr,
+			(ARRAY_AGG(h.fee ORDER BY h.day DESC))[1] * 100 AS fee,
+			AVG(h.net_apr) * 100 AS net_apr,
+			COUNT(*) AS days
+		FROM pool_apr_history h
+		WHERE h.day > (SELECT day FROM latest) - $1
+		GROUP BY h.pool
+		ORDER BY validators DESC`, days)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving pool apr comparison: %w", err)
+	}
+	return pools, nil
+}
+
+// SavePoolRiskSnapshot stores the slashing and correlation risk metrics of every entity for a day. Validators are grouped
+// into operators by their lido node operator, rocketpool node, distributed validator cluster or (as fallback) their
+// deposit address. The risk score (0-100) weighs the historical slashing rate (saturating at 0.1% of the validators of the
+// entity ever slashed) with 40%, the operator concentration (square root of the herfindahl index of the operator shares)
+// with 30% and the correlation penalty with 30%. The correlation penalty is the share of the balance a validator loses if
+// the largest operator of the entity gets slashed at once (3 times the share of the network, capped at 1).
+func SavePoolRiskSnapshot(day, epoch, epochsPerYear uint64) error {
+	_, err := WriterDb.Exec(`
+		WITH entity_validators AS (
+			SELECT
+				p.pool,
+				v.slashed,
+				v.exitepoch,
+				v.activationepoch <= $2 AND v.exitepoch > $2 AS active,
+				COALESCE(
+					'lido:' || l.module || ':' || l.operator_id,
+					'rocketpool:' || ENCODE(rp.node_address, 'hex'),
+					dvt.protocol || ':' || dvt.cluster_id,
+					'deposit:' || ENCODE(d.from_address, 'hex'),
+					'validator:' || v.validatorindex
+				) AS operator
+			FROM validator_pool p
+			INNER JOIN validators v ON v.pubkey = p.publickey
+			LEFT JOIN lido_operator_validators l ON l.publickey = v.pubkey
+			LEFT JOIN LATERAL (SELECT node_address FROM rocketpool_minipools WHERE pubkey = v.pubkey LIMIT 1) rp ON true
+			LEFT JOIN validator_dvt dvt ON dvt.publickey = v.pubkey
+			LEFT JOIN LATERAL (SELECT from_address FROM eth1_deposits WHERE publickey = v.pubkey AND valid_signature ORDER BY block_number LIMIT 1) d ON true
+			WHERE p.pool IS NOT NULL
+		),
+		network AS (
+			SELECT COUNT(*) AS validators FROM validators WHERE activationepoch <= $2 AND exitepoch > $2
+		),
+		entities AS (
+			SELECT
+				pool,
+				COUNT(*) FILTER (WHERE active) AS validators,
+				COUNT(*) AS validators_total,
+				COUNT(*) FILTER (WHERE slashed) AS slashed_validators,
+				COUNT(*) FILTER (WHERE slashed AND exitepoch + $3 > $2) AS slashed_validators_1y
+			FROM entity_validators
+			GROUP BY pool
+		),
+		operators AS (
+			SELECT pool, operator, COUNT(*) AS validators
+			FROM entity_validators
+			WHERE active
+			GROUP BY pool, operator
+		),
+		concentration AS (
+			SELECT
+				o.pool,
+				COUNT(*) AS operators,
+				MAX(o.validators) AS max_operator_validators,
+				SQRT(SUM((o.validators::FLOAT / e.validators) ^ 2)) AS operator_concentration
+			FROM operators o
+			INNER JOIN entities e ON e.pool = o.pool
+			GROUP BY o.pool
+		),
+		metrics AS (
+			SELECT
+				e.pool,
+				e.validators,
+				e.validators_total,
+				e.slashed_validators,
+				e.slashed_validators_1y,
+				e.slashed_validators::FLOAT / e.validators_total AS slashing_rate,
+				COALESCE(c.operators, 0) AS operators,
+				COALESCE(c.max_operator_validators, 0) AS max_operator_validators,
+				COALESCE(c.operator_concentration, 0) AS operator_concentration,
+				LEAST(1, 3 * COALESCE(c.max_operator_validators, 0)::FLOAT / NULLIF(n.validators, 0)) AS correlation_penalty
+			FROM entities e
+			CROSS JOIN network n
+			LEFT JOIN concentration c ON c.pool = e.pool
+		)
+		INSERT INTO pool_risk_history (day, pool, validators, validators_total, slashed_validators, slashed_validators_1y, slashing_rate, operators, max_operator_validators, operator_concentration, correlation_penalty, risk_score)
+		SELECT
+			$1,
+			pool,
+			validators,
+			validators_total,
+			slashed_validators,
+			slashed_validators_1y,
+			slashing_rate,
+			operators,
+			max_operator_validators,
+			operator_concentration,
+			COALESCE(correlation_penalty, 0),
+			100 * (0.4 * LEAST(1, slashing_rate / 0.001) + 0.3 * operator_concentration + 0.3 * COALESCE(correlation_penalty, 0))
+		FROM metrics
+		ON CONFLICT (day, pool) DO UPDATE SET
+			validators = excluded.validators,
+			validators_total = excluded.validators_total,
+			slashed_validators = excluded.slashed_validators,
+			slashed_validators_1y = excluded.slashed_validators_1y,
+			slashing_rate = excluded.slashing_rate,
+			operators = excluded.operators,
+			max_operator_validators = excluded.max_operator_validators,
+			operator_concentration = excluded.operator_concentration,
+			correlation_penalty = excluded.correlation_penalty,
+			risk_score = excluded.risk_score`, day, epoch, epochsPerYear)
+	if err != nil {
+		return fmt.Errorf("error saving pool risk snapshot of day %v: %w", day, err)
+	}
+	return nil
+}
+
+// GetPoolRisk returns the latest risk metrics of every entity (rates and shares in percent), pools with the most validators first
+func GetPoolRisk() ([]*types.ApiPoolRiskResponse, error) {
+	pools := []*types.ApiPoolRiskResponse{}
+	err := ReaderDb.Select(&pools, `
+		SELECT
+			day,
+			pool,
+			validators,
+			validators_total,
+			slashed_validators,
+			slashed_validators_1y,
+			slashing_rate * 100 AS slashing_rate,
+			operators,
+			max_operator_validators,
+			operator_concentration * 100 AS operator_concentration,
+			correlation_penalty * 100 AS correlation_penalty,
+			risk_score
+		FROM pool_risk_history
+		WHERE day = (SELECT MAX(day) FROM pool_risk_history)
+		ORDER BY validators DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving pool risk: %w", err)
+	}
+	return pools, nil
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// queryBudgetLoggedStatements is the number of the slowest statements logged for a request over budget
+const queryBudgetLoggedStatements = 10
+
+type queryBudgetKey struct{}
+
+type queryBudgetStatement struct {
+	sql      string
+	duration time.Duration
+}
+
+// queryBudget accounts the queries of a request, the queries of a request may run concurrently
+type queryBudget struct {
+	mu         sync.Mutex
+	count      int
+	duration   time.Duration
+	statements []queryBudgetStatement
+}
+
+func (b *queryBudget) add(sql string, duration time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.count++
+	b.duration += duration
+	b.statements = append(b.statements, queryBudgetStatement{sql: sql, duration: duration})
+}
+
+// slowest returns the n slowest statements, statements run more than once are reported once with their total duration
+func (b *queryBudget) slowest(n int) []logrus.Fields {
+	b.mu.Lock()
+	totals := map[string]*queryBudgetStatement{}
+	counts := map[string]int{}
+	for _, s := range b.statements {
+		if _, ok := totals[s.sql]; !ok {
+			totals[s.sql] = &queryBudgetStatement{sql: s.sql}
+		}
+		totals[s.sql].duration += s.duration
+		counts[s.sql]++
+	}
+	b.mu.Unlock()
+
+	statements := make([]*queryBudgetStatement, 0, len(totals))
+	for _, s := range totals {
+		statements = append(statements, s)
+	}
+	sort.Slice(statements, func(i, j int) bool {
+		return statements[i].duration > statements[j].duration
+	})
+	if len(statements) > n {
+		statements = statements[:n]
+	}
+
+	res := make([]logrus.Fields, 0, len(statements))
+	for _, s := range statements {
+		res = append(res, logrus.Fields{"query": s.sql, "count": counts[s.sql], "duration": s.duration})
+	}
+	return res
+}
+
+func queryBudgetFromContext(ctx context.Context) *queryBudget {
+	budget, _ := ctx.Value(queryBudgetKey{}).(*queryBudget)
+	return budget
+}
+
+// QueryBudgetMiddleware accounts the number and the cumulative duration of the db queries of every request. Requests
+// exceeding the configured budget are logged with their slowest statements and marked on their trace span. Only
+// queries run with the request context (e.g. ReaderDb.SelectContext(r.Context(), ...)) are accounted.
+func QueryBudgetMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		budget := &queryBudget{}
+		start := time.Now()
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), queryBudgetKey{}, budget)))
+
+		path := "UNDEFINED"
+		if route := mux.CurrentRoute(r); route != nil {
+			if tpl, err := route.GetPathTemplate(); err == nil {
+				path = tpl
+			}
+		}
+
+		budget.mu.Lock()
+		count, duration := budget.count, budget.duration
+		budget.mu.Unlock()
+
+		if utils.Config.Metrics.Enabled {
+			metrics.HttpRequestsDBQueries.WithLabelValues(path).Observe(float64(count))
+			metrics.HttpRequestsDBDuration.WithLabelValues(path).Observe(duration.Seconds())
+		}
+
+		cfg := utils.Config.Frontend.QueryBudget
+		if count <= cfg.MaxQueries && duration <= cfg.MaxDuration {
+			return
+		}
+
+		if utils.Config.Metrics.Enabled {
+			metrics.HttpRequestsOverDBBudget.WithLabelValues(path).Inc()
+		}
+		span := oteltrace.SpanFromContext(r.Context())
+		span.SetAttributes(attribute.Int("db.query_count", count), attribute.Int64("db.query_duration_ms", duration.Milliseconds()))
+		span.AddEvent("db query budget exceeded")
+
+		utils.LoggerWithContext(r.Context(), queryLogger).WithFields(logrus.Fields{
+			"route":          path,
+			"url":            r.URL.String(),
+			"query_count":    count,
+			"query_duration": duration,
+			"duration":       time.Since(start),
+			"statements":     budget.slowest(queryBudgetLoggedStatements),
+		}).Warn("request exceeded db query budget")
+	})
+}
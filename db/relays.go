@@ -0,0 +1,97 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/lib/pq"
+)
+
+const relayValidatorRegistrationsQuery = `
+	SELECT
+		r.tag_id,
+		tags.metadata ->> 'name' AS relay_name,
+		r.pubkey,
+		r.registered,
+		r.fee_recipient,
+		r.gas_limit,
+		r.registration_ts,
+		r.checked_ts,
+		r.unregistered_ts
+	FROM relays_validator_registrations r
+	LEFT JOIN tags ON tags.id = r.tag_id`
+
+// SaveRelayValidatorRegistrations stores the result of a registration check. If a validator that was registered at a
+// relay is no longer registered the time of the check is kept as unregistered_ts.
+func SaveRelayValidatorRegistrations(registrations []*types.RelayValidatorRegistration) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in SaveRelayValidatorRegistrations: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, r := range registrations {
+		_, err = tx.Exec(`
+			INSERT INTO relays_validator_registrations (tag_id, pubkey, registered, fee_recipient, gas_limit, registration_ts, checked_ts)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (pubkey, tag_id) DO UPDATE SET
+				registered = excluded.registered,
+				fee_recipient = CASE WHEN excluded.registered THEN excluded.fee_recipient ELSE relays_validator_registrations.fee_recipient END,
+				gas_limit = CASE WHEN excluded.registered THEN excluded.gas_limit ELSE relays_validator_registrations.gas_limit END,
+				registration_ts = CASE WHEN excluded.registered THEN excluded.registration_ts ELSE relays_validator_registrations.registration_ts END,
+				checked_ts = excluded.checked_ts,
+				unregistered_ts = CASE
+					WHEN excluded.registered THEN NULL
+					WHEN relays_validator_registrations.registered THEN excluded.checked_ts
+					ELSE relays_validator_registrations.unregistered_ts
+				END`,
+			r.RelayID, r.Pubkey, r.Registered, r.FeeRecipient, r.GasLimit, r.RegistrationTs, r.CheckedTs)
+		if err != nil {
+			return fmt.Errorf("error saving registration of validator %#x at relay %v: %w", r.Pubkey, r.RelayID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetRelayValidatorRegistrations returns the last known registrations of the validators at all relays
+func GetRelayValidatorRegistrations(pubkeys [][]byte) ([]*types.RelayValidatorRegistration, error) {
+	registrations := []*types.RelayValidatorRegistration{}
+	err := ReaderDb.Select(&registrations, relayValidatorRegistrationsQuery+`
+		WHERE r.pubkey = ANY($1)
+		ORDER BY r.pubkey, r.tag_id`, pq.ByteaArray(pubkeys))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving relay registrations of validators: %w", err)
+	}
+	return registrations, nil
+}
+
+// GetRelayValidatorRegistrationsCheckedSince returns the validators whose registrations have been checked at every
+// relay since the time
+func GetRelayValidatorRegistrationsCheckedSince(pubkeys [][]byte, since time.Time) ([][]byte, error) {
+	checked := [][]byte{}
+	err := ReaderDb.Select(&checked, `
+		SELECT pubkey
+		FROM relays_validator_registrations
+		WHERE pubkey = ANY($1)
+		GROUP BY pubkey
+		HAVING MIN(checked_ts) >= $2`, pq.ByteaArray(pubkeys), since)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving checked relay registrations: %w", err)
+	}
+	return checked, nil
+}
+
+// GetRelayValidatorUnregistrations returns the registrations that disappeared in the time range
+func GetRelayValidatorUnregistrations(from, to time.Time) ([]*types.RelayValidatorRegistration, error) {
+	registrations := []*types.RelayValidatorRegistration{}
+	err := WriterDb.Select(&registrations, relayValidatorRegistrationsQuery+`
+		WHERE r.unregistered_ts >= $1 AND r.unregistered_ts < $2
+		ORDER BY r.pubkey, r.tag_id`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving disappeared relay registrations: %w", err)
+	}
+	return registrations, nil
+}
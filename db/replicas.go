@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+)
+
+const replicaHealthCheckInterval = time.Second * 10
+
+// ReadConsistency selects the database handle of a read
+type ReadConsistency int
+
+const (
+	// ReadReplica reads from ReaderDb, which may be served by a replica lagging behind the primary by up to the
+	// configured max replication lag
+	ReadReplica ReadConsistency = iota
+	// ReadPrimary reads from the primary, for paths that have to observe their own writes or check the freshness of
+	// the exported data
+	ReadPrimary
+)
+
+// Reader returns the database handle for reads with the consistency
+func Reader(consistency ReadConsistency) *sqlx.DB {
+	if consistency == ReadPrimary {
+		return WriterDb
+	}
+	return ReaderDb
+}
+
+type readerReplica struct {
+	addr      string
+	connector driver.Connector
+	// health checks use their own single connection pool so they are not blocked by a saturated reader pool
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// replicaRouter opens the connections of the reader pool on the healthy replicas in round robin order. If no replica
+// is healthy the connections are opened on the primary. Pooled connections are recycled after their max lifetime, so
+// reads fail back to a recovered replica within a minute.
+type replicaRouter struct {
+	replicas []*readerReplica
+	primary  driver.Connector
+	maxLag   time.Duration
+	next     atomic.Uint64
+}
+
+// openReplicaRouter returns a reader pool distributing its connections over the reader and its replicas, the writer is
+// used as fallback if it is set
+func openReplicaRouter(reader *types.DatabaseConfig, writer *types.DatabaseConfig) (*sqlx.DB, error) {
+	router := &replicaRouter{maxLag: reader.MaxReplicationLag}
+
+	addrs := append([]string{net.JoinHostPort(reader.Host, reader.Port)}, reader.Replicas...)
+	for _, addr := range addrs {
+		connector, err := postgresConnector(reader, addr)
+		if err != nil {
+			return nil, err
+		}
+		replica := &readerReplica{addr: addr, connector: connector, db: sql.OpenDB(connector)}
+		replica.db.SetMaxOpenConns(1)
+		replica.db.SetConnMaxLifetime(time.Minute)
+		router.replicas = append(router.replicas, replica)
+	}
+	if writer != nil {
+		connector, err := postgresConnector(writer, net.JoinHostPort(writer.Host, writer.Port))
+		if err != nil {
+			return nil, err
+		}
+		router.primary = connector
+	}
+
+	healthy := 0
+	for _, replica := range router.replicas {
+		router.checkReplica(replica)
+		if replica.healthy.Load() {
+			healthy++
+		}
+	}
+	logger.Infof("routing reads over %v of %v reader replicas", healthy, len(router.replicas))
+	go router.checkReplicas()
+
+	return sqlx.NewDb(sql.OpenDB(router), "pgx"), nil
+}
+
+func postgresConnector(cfg *types.DatabaseConfig, addr string) (driver.Connector, error) {
+	sslParam := "sslmode=disable"
+	if cfg.SSL {
+		sslParam = "sslmode=require"
+	}
+	connConfig, err := pgx.ParseConfig(fmt.Sprintf("postgres://%s:%s@%s/%s?%s", cfg.Username, cfg.Password, addr, cfg.Name, sslParam))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing database config of %v: %w", addr, err)
+	}
+	connConfig.Tracer = queryTracer{}
+	return stdlib.GetConnector(*connConfig), nil
+}
+
+func (r *replicaRouter) Connect(ctx context.Context) (driver.Conn, error) {
+	var lastErr error
+	n := uint64(len(r.replicas))
+	start := r.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		replica := r.replicas[(start+i)%n]
+		if !replica.healthy.Load() {
+			continue
+		}
+		conn, err := replica.connector.Connect(ctx)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+		r.setHealthy(replica, false, fmt.Sprintf("error connecting: %v", err))
+	}
+
+	if r.primary != nil {
+		return r.primary.Connect(ctx)
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	// without a primary the replicas are tried even if they are unhealthy
+	return r.replicas[start%n].connector.Connect(ctx)
+}
+
+func (r *replicaRouter) Driver() driver.Driver {
+	return r.replicas[0].connector.Driver()
+}
+
+func (r *replicaRouter) checkReplicas() {
+	for {
+		time.Sleep(replicaHealthCheckInterval)
+		for _, replica := range r.replicas {
+			r.checkReplica(replica)
+		}
+	}
+}
+
+// checkReplica updates the health of the replica, a replica is unhealthy if it is unreachable or lags behind the
+// primary by more than the max replication lag
+func (r *replicaRouter) checkReplica(replica *readerReplica) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	// the replay timestamp only advances with new transactions, a replica that replayed everything it received has no lag
+	var lag float64
+	err := replica.db.QueryRowContext(ctx, `
+		SELECT CASE
+			WHEN pg_is_in_recovery() AND pg_last_wal_receive_lsn() IS DISTINCT FROM pg_last_wal_replay_lsn()
+			THEN COALESCE(EXTRACT(EPOCH FROM NOW() - pg_last_xact_replay_timestamp()), 0)
+			ELSE 0
+		END`).Scan(&lag)
+	if err != nil {
+		metrics.DBReplicaLag.WithLabelValues(replica.addr).Set(-1)
+		r.setHealthy(replica, false, fmt.Sprintf("health check failed: %v", err))
+		return
+	}
+
+	metrics.DBReplicaLag.WithLabelValues(replica.addr).Set(lag)
+	lagDuration := time.Duration(lag * float64(time.Second))
+	if r.maxLag > 0 && lagDuration > r.maxLag {
+		r.setHealthy(replica, false, fmt.Sprintf("replication lag of %v exceeds %v", lagDuration, r.maxLag))
+		return
+	}
+	r.setHealthy(replica, true, "")
+}
+
+func (r *replicaRouter) setHealthy(replica *readerReplica, healthy bool, reason string) {
+	if replica.healthy.Swap(healthy) == healthy {
+		return
+	}
+	if healthy {
+		logger.Infof("reader replica %v is healthy, routing reads to it", replica.addr)
+	} else {
+		logger.Warnf("reader replica %v is unhealthy (%v), routing reads to the remaining replicas", replica.addr, reason)
+	}
+}
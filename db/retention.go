@@ -0,0 +1,213 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/tracing"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// retentionPolicy describes the rows of a table that are pruned once they are older than the retention period
+type retentionPolicy struct {
+	// name is the label of the retention metrics
+	name  string
+	table string
+	days  uint64
+	// condition selects the rows to prune, $1 is the cutoff returned by cutoff
+	condition string
+	cutoff    func(t time.Time) (interface{}, error)
+	// rollup aggregates the rows selected by condition before they are pruned, it has to be idempotent as rows are
+	// rolled up again if a run fails before they are deleted
+	rollup string
+}
+
+func retentionPolicies() []*retentionPolicy {
+	days := utils.Config.Retention.Days
+	policies := []*retentionPolicy{
+		{
+			name:      "blocks_attestations",
+			table:     "blocks_attestations",
+			days:      days.BlocksAttestations,
+			condition: "block_slot < $1",
+			cutoff: func(t time.Time) (interface{}, error) {
+				epoch, err := retentionCutoffEpoch(t)
+				return epoch * utils.Config.Chain.ClConfig.SlotsPerEpoch, err
+			},
+		},
+		{
+			name:      "proposal_assignments",
+			table:     "proposal_assignments",
+			days:      days.ProposalAssignments,
+			condition: "epoch < $1",
+			cutoff: func(t time.Time) (interface{}, error) {
+				return retentionCutoffEpoch(t)
+			},
+		},
+		{
+			name:      "validator_balances_recent",
+			table:     "validator_balances_recent",
+			days:      days.ValidatorBalancesRecent,
+			condition: "epoch < $1",
+			cutoff: func(t time.Time) (interface{}, error) {
+				return retentionCutoffEpoch(t)
+			},
+		},
+		{
+			name:      "network_liveness",
+			table:     "network_liveness",
+			days:      days.NetworkLiveness,
+			condition: "ts < $1",
+			// only whole days are pruned so the rolled up max of a day is complete
+			cutoff: func(t time.Time) (interface{}, error) {
+				return t.UTC().Truncate(utils.Day), nil
+			},
+			rollup: `
+				INSERT INTO network_liveness_daily (day, max_finality_delay)
+				SELECT EXTRACT(epoch FROM date_trunc('day', ts))::bigint AS day, MAX(headepoch - finalizedepoch)
+				FROM network_liveness
+				WHERE ts < $1
+				GROUP BY day
+				ON CONFLICT (day) DO UPDATE SET max_finality_delay = GREATEST(network_liveness_daily.max_finality_delay, EXCLUDED.max_finality_delay)`,
+		},
+	}
+
+	// machine metrics are stored in bigtable which enforces the gc policies of the column families itself, the postgres
+	// backend does not so the raw metrics are pruned here. The 5m and 1h aggregations are kept.
+	if utils.Config.Bigtable.Backend == BigtableBackendPostgres {
+		policies = append(policies, &retentionPolicy{
+			name:      "machine_metrics",
+			table:     "bigtable_cells",
+			days:      days.MachineMetrics,
+			condition: fmt.Sprintf("table_name = 'machine_metrics' AND family = '%s' AND ts < $1", MACHINE_METRICS_COLUMN_FAMILY),
+			cutoff: func(t time.Time) (interface{}, error) {
+				return t.UnixMicro(), nil
+			},
+		})
+	}
+	return policies
+}
+
+// retentionCutoffEpoch returns the first epoch that is kept for the cutoff time. Epochs of days whose statistics have
+// not been exported yet are never pruned as the statistics export still reads them.
+func retentionCutoffEpoch(t time.Time) (uint64, error) {
+	lastExportedDay, err := GetLastExportedStatisticDay()
+	if err == ErrNoStats {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	epoch := uint64(utils.TimeToEpoch(t))
+	firstEpochOfNextDay, _ := utils.GetFirstAndLastEpochForDay(lastExportedDay + 1)
+	if firstEpochOfNextDay < epoch {
+		return firstEpochOfNextDay, nil
+	}
+	return epoch, nil
+}
+
+// RunRetention prunes the rows older than the configured retention period of every table in batches of batchSize rows.
+// In dry run mode nothing is deleted, only the prunable rows and their estimated size are reported.
+func RunRetention(dryRun bool, batchSize uint64) error {
+	var errs []error
+	for _, p := range retentionPolicies() {
+		if p.days == 0 {
+			continue
+		}
+		_, span := tracing.StartSpan(context.Background(), "retention", attribute.String("policy", p.name), attribute.Bool("dry_run", dryRun))
+		err := runRetentionPolicy(p, dryRun, batchSize)
+		tracing.EndSpan(span, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error pruning %v: %w", p.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func runRetentionPolicy(p *retentionPolicy, dryRun bool, batchSize uint64) error {
+	start := time.Now()
+
+	cutoff, err := p.cutoff(time.Now().Add(-utils.Day * time.Duration(p.days)))
+	if err != nil {
+		return fmt.Errorf("error getting retention cutoff: %w", err)
+	}
+
+	var prunable uint64
+	err = WriterDb.Get(&prunable, fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", p.table, p.condition), cutoff)
+	if err != nil {
+		return fmt.Errorf("error counting prunable rows: %w", err)
+	}
+	rowSize, err := estimatedRowSize(p.table)
+	if err != nil {
+		return err
+	}
+	metrics.RetentionPrunableRows.WithLabelValues(p.name).Set(float64(prunable))
+	metrics.RetentionPrunableBytes.WithLabelValues(p.name).Set(float64(prunable) * rowSize)
+
+	if dryRun || prunable == 0 {
+		logger.Infof("retention of %v: %v rows (~%.0f bytes) older than %v days would be pruned", p.name, prunable, float64(prunable)*rowSize, p.days)
+		return nil
+	}
+
+	if p.rollup != "" {
+		_, err = WriterDb.Exec(p.rollup, cutoff)
+		if err != nil {
+			return fmt.Errorf("error rolling up rows: %w", err)
+		}
+	}
+
+	pruned := uint64(0)
+	for {
+		deleted, err := pruneRetentionBatch(p, cutoff, batchSize)
+		if err != nil {
+			return err
+		}
+		pruned += deleted
+		metrics.RetentionPrunedRows.WithLabelValues(p.name).Add(float64(deleted))
+		metrics.RetentionReclaimedBytes.WithLabelValues(p.name).Add(float64(deleted) * rowSize)
+		if deleted < batchSize {
+			break
+		}
+	}
+	metrics.RetentionPrunableRows.WithLabelValues(p.name).Set(0)
+	metrics.RetentionPrunableBytes.WithLabelValues(p.name).Set(0)
+
+	logger.Infof("retention of %v: pruned %v rows older than %v days, took %v", p.name, pruned, p.days, time.Since(start))
+	return nil
+}
+
+// pruneRetentionBatch deletes up to batchSize rows of the policy and returns the number of deleted rows
+func pruneRetentionBatch(p *retentionPolicy, cutoff interface{}, batchSize uint64) (uint64, error) {
+	res, err := WriterDb.Exec(fmt.Sprintf(`
+		DELETE FROM %[1]s WHERE ctid = ANY(ARRAY(
+			SELECT ctid FROM %[1]s WHERE %[2]s LIMIT $2
+		))`, p.table, p.condition), cutoff, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting rows: %w", err)
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error getting deleted rows: %w", err)
+	}
+	return uint64(deleted), nil
+}
+
+// estimatedRowSize returns the average size of a row of the table including its indexes and toasted data, based on
+// the row estimate of the planner statistics
+func estimatedRowSize(table string) (float64, error) {
+	var size float64
+	err := WriterDb.Get(&size, `
+		SELECT pg_total_relation_size(oid) / GREATEST(reltuples, 1)
+		FROM pg_class
+		WHERE oid = $1::regclass`, table)
+	if err != nil {
+		return 0, fmt.Errorf("error estimating row size of table %v: %w", table, err)
+	}
+	return size, nil
+}
@@ -0,0 +1,204 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+var weiPerEther = decimal.NewFromInt(1e18)
+
+// GetRocketpoolNode returns a rocketpool node operator together with its minipools, their lifecycle and the effective returns
+// of the node, nil if the address is not a rocketpool node.
+// The returns only contain execution layer rewards of nodes outside of the smoothing pool, members receive them via the smoothing pool claims.
+func GetRocketpoolNode(address []byte) (*types.ApiRocketpoolNodeResponse, error) {
+	node := struct {
+		Address                []byte          `db:"address"`
+		TimezoneLocation       string          `db:"timezone_location"`
+		RplStake               decimal.Decimal `db:"rpl_stake"`
+		EffectiveRplStake      decimal.Decimal `db:"effective_rpl_stake"`
+		MinRplStake            decimal.Decimal `db:"min_rpl_stake"`
+		MaxRplStake            decimal.Decimal `db:"max_rpl_stake"`
+		RplCumulativeRewards   decimal.Decimal `db:"rpl_cumulative_rewards"`
+		UnclaimedRplRewards    decimal.Decimal `db:"unclaimed_rpl_rewards"`
+		SmoothingPoolOptedIn   bool            `db:"smoothing_pool_opted_in"`
+		ClaimedSmoothingPool   decimal.Decimal `db:"claimed_smoothing_pool"`
+		UnclaimedSmoothingPool decimal.Decimal `db:"unclaimed_smoothing_pool"`
+		DepositCredit          decimal.Decimal `db:"deposit_credit"`
+		RplPrice               decimal.Decimal `db:"rpl_price"`
+	}{}
+	err := ReaderDb.Get(&node, `
+		SELECT
+			n.address,
+			n.timezone_location,
+			n.rpl_stake,
+			n.effective_rpl_stake,
+			n.min_rpl_stake,
+			n.max_rpl_stake,
+			n.rpl_cumulative_rewards,
+			n.unclaimed_rpl_rewards,
+			n.smoothing_pool_opted_in,
+			n.claimed_smoothing_pool,
+			n.unclaimed_smoothing_pool,
+			COALESCE(n.deposit_credit, 0) AS deposit_credit,
+			COALESCE((SELECT rpl_price FROM rocketpool_network_stats ORDER BY id DESC LIMIT 1), 0) AS rpl_price
+		FROM rocketpool_nodes n
+		WHERE n.address = $1
+		LIMIT 1`, address)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving rocketpool node %#x: %w", address, err)
+	}
+
+	minipools := []struct {
+		Address            []byte          `db:"address"`
+		Pubkey             []byte          `db:"pubkey"`
+		ValidatorIndex     *uint64         `db:"validatorindex"`
+		Status             string          `db:"status"`
+		StatusTime         sql.NullTime    `db:"status_time"`
+		DepositType        string          `db:"deposit_type"`
+		NodeFee            float64         `db:"node_fee"`
+		NodeDepositBalance decimal.Decimal `db:"node_deposit_balance"`
+		UserDepositBalance decimal.Decimal `db:"user_deposit_balance"`
+		PenaltyCount       uint64          `db:"penalty_count"`
+		IsVacant           bool            `db:"is_vacant"`
+		ClRewards31dGwei   int64           `db:"cl_performance_31d"`
+		ElRewards31dWei    int64           `db:"el_performance_31d"`
+	}{}
+	err = ReaderDb.Select(&minipools, `
+		SELECT
+			m.address,
+			m.pubkey,
+			v.validatorindex,
+			m.status,
+			m.status_time,
+			m.deposit_type,
+			m.node_fee,
+			COALESCE(m.node_deposit_balance, 0) AS node_deposit_balance,
+			COALESCE(m.user_deposit_balance, 0) AS user_deposit_balance,
+			m.penalty_count,
+			COALESCE(m.is_vacant, false) AS is_vacant,
+			COALESCE(vp.cl_performance_31d, 0) AS cl_performance_31d,
+			COALESCE(vp.el_performance_31d, 0) AS el_performance_31d
+		FROM rocketpool_minipools m
+		LEFT JOIN validators v ON v.pubkey = m.pubkey
+		LEFT JOIN validator_performance vp ON vp.validatorindex = v.validatorindex
+		WHERE m.node_address = $1
+		ORDER BY v.validatorindex NULLS LAST, m.address`, address)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving minipools of rocketpool node %#x: %w", address, err)
+	}
+
+	minipoolAddresses := make([][]byte, 0, len(minipools))
+	for _, m := range minipools {
+		minipoolAddresses = append(minipoolAddresses, m.Address)
+	}
+	statusHistory := []struct {
+		Address []byte `db:"address"`
+		types.ApiRocketpoolMinipoolStatusChange
+	}{}
+	err = ReaderDb.Select(&statusHistory, `
+		SELECT address, status, status_time
+		FROM rocketpool_minipools_status_history
+		WHERE address = ANY($1)
+		ORDER BY status_time`, pq.ByteaArray(minipoolAddresses))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving minipool status history of rocketpool node %#x: %w", address, err)
+	}
+	statusHistoryByMinipool := make(map[string][]*types.ApiRocketpoolMinipoolStatusChange, len(minipools))
+	for i := range statusHistory {
+		key := string(statusHistory[i].Address)
+		statusHistoryByMinipool[key] = append(statusHistoryByMinipool[key], &statusHistory[i].ApiRocketpoolMinipoolStatusChange)
+	}
+
+	res := &types.ApiRocketpoolNodeResponse{
+		Address:                fmt.Sprintf("%#x", node.Address),
+		TimezoneLocation:       node.TimezoneLocation,
+		RplStake:               node.RplStake.Div(weiPerEther).InexactFloat64(),
+		EffectiveRplStake:      node.EffectiveRplStake.Div(weiPerEther).InexactFloat64(),
+		MinRplStake:            node.MinRplStake.Div(weiPerEther).InexactFloat64(),
+		MaxRplStake:            node.MaxRplStake.Div(weiPerEther).InexactFloat64(),
+		RplPrice:               node.RplPrice.Div(weiPerEther).InexactFloat64(),
+		RplCumulativeRewards:   node.RplCumulativeRewards.Div(weiPerEther).InexactFloat64(),
+		UnclaimedRplRewards:    node.UnclaimedRplRewards.Div(weiPerEther).InexactFloat64(),
+		SmoothingPoolOptedIn:   node.SmoothingPoolOptedIn,
+		ClaimedSmoothingPool:   node.ClaimedSmoothingPool.Div(weiPerEther).InexactFloat64(),
+		UnclaimedSmoothingPool: node.UnclaimedSmoothingPool.Div(weiPerEther).InexactFloat64(),
+		DepositCredit:          node.DepositCredit.Div(weiPerEther).InexactFloat64(),
+		MinipoolsByStatus:      make(map[string]uint64),
+		Minipools:              make([]*types.ApiRocketpoolNodeMinipool, 0, len(minipools)),
+		SmoothingPoolHistory:   []*types.ApiRocketpoolSmoothingPoolChange{},
+	}
+
+	bonded := decimal.Zero
+	borrowed := decimal.Zero
+	nodeRewards := decimal.Zero
+	for _, m := range minipools {
+		res.MinipoolsByStatus[m.Status]++
+
+		// the node operator receives the rewards of its bond and the commission on the rewards of the borrowed eth
+		minipoolRewards := decimal.Zero
+		total := m.NodeDepositBalance.Add(m.UserDepositBalance)
+		if m.Status == "Staking" && total.IsPositive() {
+			bonded = bonded.Add(m.NodeDepositBalance)
+			borrowed = borrowed.Add(m.UserDepositBalance)
+
+			rewards := decimal.NewFromInt(m.ClRewards31dGwei).Mul(decimal.NewFromInt(1e9))
+			if !node.SmoothingPoolOptedIn {
+				rewards = rewards.Add(decimal.NewFromInt(m.ElRewards31dWei))
+			}
+			nodeShare := m.NodeDepositBalance.Add(m.UserDepositBalance.Mul(decimal.NewFromFloat(m.NodeFee))).Div(total)
+			minipoolRewards = rewards.Mul(nodeShare)
+			nodeRewards = nodeRewards.Add(minipoolRewards)
+		}
+
+		minipool := &types.ApiRocketpoolNodeMinipool{
+			Address:            fmt.Sprintf("%#x", m.Address),
+			Pubkey:             fmt.Sprintf("%#x", m.Pubkey),
+			ValidatorIndex:     m.ValidatorIndex,
+			Status:             m.Status,
+			DepositType:        m.DepositType,
+			NodeFee:            m.NodeFee,
+			NodeDepositBalance: m.NodeDepositBalance.Div(weiPerEther).InexactFloat64(),
+			UserDepositBalance: m.UserDepositBalance.Div(weiPerEther).InexactFloat64(),
+			PenaltyCount:       m.PenaltyCount,
+			IsVacant:           m.IsVacant,
+			NodeRewards31d:     minipoolRewards.Div(weiPerEther).InexactFloat64(),
+			StatusHistory:      statusHistoryByMinipool[string(m.Address)],
+		}
+		if m.StatusTime.Valid {
+			minipool.StatusTime = m.StatusTime.Time
+		}
+		if minipool.StatusHistory == nil {
+			minipool.StatusHistory = []*types.ApiRocketpoolMinipoolStatusChange{}
+		}
+		res.Minipools = append(res.Minipools, minipool)
+	}
+
+	res.BondedEth = bonded.Div(weiPerEther).InexactFloat64()
+	res.BorrowedEth = borrowed.Div(weiPerEther).InexactFloat64()
+	res.NodeRewards31d = nodeRewards.Div(weiPerEther).InexactFloat64()
+	if borrowed.IsPositive() {
+		res.CollateralRatio = node.RplStake.Mul(node.RplPrice).Div(weiPerEther).Div(borrowed).InexactFloat64()
+	}
+	if bonded.IsPositive() {
+		res.NodeApr31d = nodeRewards.Div(bonded).Mul(decimal.NewFromInt(365)).Div(decimal.NewFromInt(31)).Mul(decimal.NewFromInt(100)).InexactFloat64()
+	}
+
+	err = ReaderDb.Select(&res.SmoothingPoolHistory, `
+		SELECT opted_in, ts
+		FROM rocketpool_nodes_smoothing_pool_history
+		WHERE address = $1
+		ORDER BY ts DESC`, address)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving smoothing pool history of rocketpool node %#x: %w", address, err)
+	}
+
+	return res, nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"math/big"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -20,6 +21,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/lib/pq"
+	"github.com/prysmaticlabs/go-bitfield"
 	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
@@ -259,6 +261,11 @@ func WriteValidatorStatisticsForDay(day uint64, client rpc.Client) error {
 		}
 	}
 
+	if err := calculateValidatorExpectedDutiesForDay(day, validatorData); err != nil {
+		return fmt.Errorf("error in calculateValidatorExpectedDutiesForDay: %w", err)
+	}
+	calculateValidatorPercentilesForDay(day, validatorData)
+
 	conn, err := WriterDb.Conn(context.Background())
 	if err != nil {
 		return fmt.Errorf("error retrieving raw sql connection: %w", err)
@@ -322,6 +329,11 @@ func WriteValidatorStatisticsForDay(day uint64, client rpc.Client) error {
 			"el_rewards_wei_total",
 			"mev_rewards_wei",
 			"mev_rewards_wei_total",
+			"expected_proposals",
+			"expected_sync_slots",
+			"attestation_percentile",
+			"proposal_luck_percentile",
+			"income_percentile",
 		}, pgx.CopyFromSlice(len(validatorData), func(i int) ([]interface{}, error) {
 			return []interface{}{
 				validatorData[i].ValidatorIndex,
@@ -362,6 +374,11 @@ func WriteValidatorStatisticsForDay(day uint64, client rpc.Client) error {
 				validatorData[i].ElRewardsWeiTotal,
 				validatorData[i].MEVRewardsWei,
 				validatorData[i].MEVRewardsWeiTotal,
+				validatorData[i].ExpectedProposals,
+				validatorData[i].ExpectedSyncSlots,
+				validatorData[i].AttestationPercentile,
+				validatorData[i].ProposalLuckPercentile,
+				validatorData[i].IncomePercentile,
 			}, nil
 		}))
 
@@ -369,6 +386,16 @@ func WriteValidatorStatisticsForDay(day uint64, client rpc.Client) error {
 			return err
 		}
 
+		logger.Infof("updating pool luck of day %v", day)
+		if err := writePoolLuckForDay(day, tx); err != nil {
+			return fmt.Errorf("error in writePoolLuckForDay: %w", err)
+		}
+
+		logger.Infof("detecting validator milestones of day %v", day)
+		if err := writeValidatorMilestonesForDay(day, tx); err != nil {
+			return fmt.Errorf("error in writeValidatorMilestonesForDay: %w", err)
+		}
+
 		lastExportedStatsDay, err := GetLastExportedStatisticDay()
 		if err != nil && err != ErrNoStats {
 			return fmt.Errorf("error retrieving last exported statistics day: %w", err)
@@ -457,6 +484,14 @@ func WriteValidatorStatisticsForDay(day uint64, client rpc.Client) error {
 			logger.Infof("skipping total performance export as last exported day (%v) is greater than the exported day (%v)", lastExportedStatsDay, day)
 		}
 
+		// written before the day is marked as exported so a failed clickhouse write is retried with the next export run
+		if utils.Config.ClickHouse.StatisticsSinkEnabled {
+			logger.Infof("writing statistics data of day %v to clickhouse", day)
+			if err := WriteValidatorStatisticsToClickhouse(day, validatorData); err != nil {
+				return fmt.Errorf("error in WriteValidatorStatisticsToClickhouse: %w", err)
+			}
+		}
+
 		logger.Infof("marking day %v as exported", day)
 		if err := WriteValidatorStatsExported(day, tx); err != nil {
 			return fmt.Errorf("error in WriteValidatorStatsExported: %w", err)
@@ -479,6 +514,132 @@ func WriteValidatorStatisticsForDay(day uint64, client rpc.Client) error {
 	return nil
 }
 
+// calculateValidatorExpectedDutiesForDay calculates the amount of proposals and sync committee slots every validator that was
+// active during the whole day could expect. Both duties are assigned randomly, weighted by the effective balance, so the
+// expectation is the share of the validator of the total effective balance of all active validators.
+func calculateValidatorExpectedDutiesForDay(day uint64, data []*types.ValidatorStatsTableDbRow) error {
+	firstEpoch, lastEpoch := utils.GetFirstAndLastEpochForDay(day)
+
+	activeValidators := []uint64{}
+	err := ReaderDb.Select(&activeValidators, `SELECT validatorindex FROM validators WHERE activationepoch <= $1 AND exitepoch > $2 ORDER BY validatorindex`, firstEpoch, lastEpoch)
+	if err != nil {
+		return fmt.Errorf("error retrieving active validators of day %v: %w", day, err)
+	}
+
+	totalEffectiveBalance := int64(0)
+	for _, index := range activeValidators {
+		if index < uint64(len(data)) {
+			totalEffectiveBalance += data[index].EndEffectiveBalance
+		}
+	}
+	if totalEffectiveBalance == 0 {
+		return nil
+	}
+
+	slots := float64((lastEpoch - firstEpoch + 1) * utils.Config.Chain.ClConfig.SlotsPerEpoch)
+	syncCommitteeSlots := float64(0)
+	if firstEpoch >= utils.Config.Chain.ClConfig.AltairForkEpoch {
+		syncCommitteeSlots = slots * float64(utils.Config.Chain.ClConfig.SyncCommitteeSize)
+	}
+
+	for _, index := range activeValidators {
+		if index >= uint64(len(data)) || data[index].EndEffectiveBalance <= 0 {
+			continue
+		}
+		share := float64(data[index].EndEffectiveBalance) / float64(totalEffectiveBalance)
+		expectedProposals := slots * share
+		expectedSyncSlots := syncCommitteeSlots * share
+		data[index].ExpectedProposals = &expectedProposals
+		data[index].ExpectedSyncSlots = &expectedSyncSlots
+	}
+	return nil
+}
+
+// calculateValidatorPercentilesForDay ranks all validators with expected duties by their attestation effectiveness,
+// proposal luck and income. The percentile of a validator is the share of ranked validators that performed strictly
+// worse, so validators sharing the same value share the same percentile.
+func calculateValidatorPercentilesForDay(day uint64, data []*types.ValidatorStatsTableDbRow) {
+	start := time.Now()
+	firstEpoch, lastEpoch := utils.GetFirstAndLastEpochForDay(day)
+
+	ranked := make([]*types.ValidatorStatsTableDbRow, 0, len(data))
+	for _, d := range data {
+		if d.ExpectedProposals != nil {
+			ranked = append(ranked, d)
+		}
+	}
+	if len(ranked) == 0 {
+		return
+	}
+
+	epochs := float64(lastEpoch - firstEpoch + 1)
+	gweiPerWei := decimal.NewFromInt(1e9)
+
+	attestationEffectiveness := make([]float64, len(ranked))
+	proposalLuck := make([]float64, len(ranked))
+	income := make([]float64, len(ranked))
+	for i, d := range ranked {
+		attestationEffectiveness[i] = 1 - float64(d.MissedAttestations)/epochs
+		proposalLuck[i] = float64(d.ProposedBlocks+d.MissedBlocks+d.OrphanedBlocks) / *d.ExpectedProposals
+
+		// the income is normalized by the effective balance to not favor validators with a higher balance
+		income[i] = (float64(d.ClRewardsGWei) + d.ElRewardsWei.Div(gweiPerWei).InexactFloat64()) / float64(d.EndEffectiveBalance)
+	}
+
+	attestationPercentiles := percentileRanks(attestationEffectiveness)
+	proposalLuckPercentiles := percentileRanks(proposalLuck)
+	incomePercentiles := percentileRanks(income)
+	for i, d := range ranked {
+		d.AttestationPercentile = &attestationPercentiles[i]
+		d.ProposalLuckPercentile = &proposalLuckPercentiles[i]
+		d.IncomePercentile = &incomePercentiles[i]
+	}
+
+	logger.Infof("calculated percentiles of %v validators for day %v, took %v", len(ranked), day, time.Since(start))
+}
+
+// percentileRanks returns the share of values (0-100) that are strictly lower than each value
+func percentileRanks(values []float64) []float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	ranks := make([]float64, len(values))
+	for i, v := range values {
+		ranks[i] = float64(sort.SearchFloat64s(sorted, v)) * 100 / float64(len(values))
+	}
+	return ranks
+}
+
+// writePoolLuckForDay aggregates the actual and expected proposals and sync committee slots of the validators of each pool
+func writePoolLuckForDay(day uint64, tx pgx.Tx) error {
+	_, err := tx.Exec(context.Background(), `
+		INSERT INTO pool_luck_history (day, pool, validators, proposals, expected_proposals, sync_slots, expected_sync_slots)
+		SELECT
+			vs.day,
+			COALESCE(vp.pool, 'Unknown'),
+			COUNT(*),
+			SUM(COALESCE(vs.proposed_blocks, 0) + COALESCE(vs.missed_blocks, 0) + COALESCE(vs.orphaned_blocks, 0)),
+			SUM(vs.expected_proposals),
+			SUM(COALESCE(vs.participated_sync, 0) + COALESCE(vs.missed_sync, 0) + COALESCE(vs.orphaned_sync, 0)),
+			SUM(vs.expected_sync_slots)
+		FROM validator_stats vs
+		INNER JOIN validators v ON v.validatorindex = vs.validatorindex
+		LEFT JOIN validator_pool vp ON vp.publickey = v.pubkey
+		WHERE vs.day = $1 AND vs.expected_proposals IS NOT NULL
+		GROUP BY vs.day, vp.pool
+		ON CONFLICT (day, pool) DO UPDATE SET
+			validators = excluded.validators,
+			proposals = excluded.proposals,
+			expected_proposals = excluded.expected_proposals,
+			sync_slots = excluded.sync_slots,
+			expected_sync_slots = excluded.expected_sync_slots`, day)
+	if err != nil {
+		return fmt.Errorf("error writing pool luck of day %v: %w", day, err)
+	}
+	return nil
+}
+
 func WriteValidatorStatsExported(day uint64, tx pgx.Tx) error {
 
 	start := time.Now()
@@ -1170,7 +1331,12 @@ func GatherStatisticsForDay(day int64) ([]*types.ValidatorStatsTableDbRow, error
 		COALESCE(el_rewards_wei, 0) AS el_rewards_wei,
 		COALESCE(el_rewards_wei_total, 0) AS el_rewards_wei_total,
 		COALESCE(mev_rewards_wei, 0) AS mev_rewards_wei,
-		COALESCE(mev_rewards_wei_total, 0) AS mev_rewards_wei_total
+		COALESCE(mev_rewards_wei_total, 0) AS mev_rewards_wei_total,
+		expected_proposals,
+		expected_sync_slots,
+		attestation_percentile,
+		proposal_luck_percentile,
+		income_percentile
 	 from validator_stats WHERE day = $1 ORDER BY validatorindex
 	`, day)
 
@@ -1227,16 +1393,21 @@ func GetValidatorIncomeHistory(validatorIndices []uint64, lowerBoundDay uint64,
 	}
 
 	var result []types.ValidatorIncomeHistory
-	err := ReaderDb.Select(&result, `
-		SELECT 
-			day, 
-			SUM(COALESCE(cl_rewards_gwei, 0)) AS cl_rewards_gwei,
-			SUM(COALESCE(end_balance, 0)) AS end_balance
-		FROM validator_stats 
-		WHERE validatorindex = ANY($1) AND day BETWEEN $2 AND $3 
-		GROUP BY day 
-		ORDER BY day
-	;`, validatorIndicesPqArr, lowerBoundDay, upperBoundDay)
+	var err error
+	if ClickhouseStatisticsReadsEnabled() {
+		result, err = getValidatorIncomeHistoryClickhouse(validatorIndices, lowerBoundDay, upperBoundDay)
+	} else {
+		err = ReaderDb.Select(&result, `
+			SELECT 
+				day, 
+				SUM(COALESCE(cl_rewards_gwei, 0)) AS cl_rewards_gwei,
+				SUM(COALESCE(end_balance, 0)) AS end_balance
+			FROM validator_stats 
+			WHERE validatorindex = ANY($1) AND day BETWEEN $2 AND $3 
+			GROUP BY day 
+			ORDER BY day
+		;`, validatorIndicesPqArr, lowerBoundDay, upperBoundDay)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1410,6 +1581,11 @@ func WriteConsensusChartSeriesForDay(day int64) error {
 		return fmt.Errorf("error inserting STAKED_ETH into chart_series: %w", err)
 	}
 
+	_, err = WriterDb.Exec(`insert into chart_series select $1 as time, 'ACTIVE_VALIDATORS' as indicator, validatorscount as value from epochs where epoch = $2 limit 1 on conflict (time, indicator) do update set time = excluded.time, indicator = excluded.indicator, value = excluded.value`, dateTrunc, lastEpoch-1)
+	if err != nil {
+		return fmt.Errorf("error inserting ACTIVE_VALIDATORS into chart_series: %w", err)
+	}
+
 	_, err = WriterDb.Exec(`insert into chart_series select $1 as time, 'AVG_VALIDATOR_BALANCE_ETH' as indicator, avg(averagevalidatorbalance)/1e9 as value from epochs where epoch >= $2 and epoch < $3 on conflict (time, indicator) do update set time = excluded.time, indicator = excluded.indicator, value = excluded.value`, dateTrunc, firstEpoch, lastEpoch)
 	if err != nil {
 		return fmt.Errorf("error inserting AVG_VALIDATOR_BALANCE_ETH into chart_series: %w", err)
@@ -1864,6 +2040,902 @@ func WriteGraffitiStatisticsForDay(day int64) error {
 	return nil
 }
 
+func WriteMevIncomeStatisticsForDay(day int64) error {
+	if day < 0 {
+		logger.Warnf("no mev-income-stats for days before beaconchain")
+		return nil
+	}
+
+	epochsPerDay := utils.EpochsPerDay()
+	firstSlot := uint64(day) * epochsPerDay * utils.Config.Chain.ClConfig.SlotsPerEpoch
+	firstSlotOfNextDay := uint64(day+1) * epochsPerDay * utils.Config.Chain.ClConfig.SlotsPerEpoch
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in WriteMevIncomeStatisticsForDay: %w", err)
+	}
+	defer tx.Rollback()
+
+	// a payload can be delivered by multiple relays, so the value of a block is only counted once
+	// rewards of blocks flagged as invalid-relay-reward are not counted as mev income
+	_, err = tx.Exec(`
+		insert into mev_income (validatorindex, day, builder_blocks, local_blocks, mev_value)
+		select
+			blocks.proposer,
+			$1::int as day,
+			count(rb.block_root) as builder_blocks,
+			count(*) - count(rb.block_root) as local_blocks,
+			coalesce(sum(rb.value), 0) as mev_value
+		from blocks
+		left join (
+			select relays_blocks.block_slot, relays_blocks.block_root, max(case when bt.blockroot is null then relays_blocks.value else 0 end) as value
+			from relays_blocks
+			left join blocks_tags bt on bt.blockroot = relays_blocks.block_root and bt.tag_id = 'invalid-relay-reward'
+			where relays_blocks.block_slot >= $2 and relays_blocks.block_slot < $3
+			group by relays_blocks.block_slot, relays_blocks.block_root
+		) rb on rb.block_slot = blocks.slot and rb.block_root = blocks.blockroot
+		where blocks.slot >= $2 and blocks.slot < $3 and blocks.status = '1' and blocks.exec_block_hash is not null
+		group by blocks.proposer
+		on conflict (validatorindex, day) do update set
+			builder_blocks = excluded.builder_blocks,
+			local_blocks   = excluded.local_blocks,
+			mev_value      = excluded.mev_value`, day, firstSlot, firstSlotOfNextDay)
+	if err != nil {
+		return fmt.Errorf("error inserting mev_income in WriteMevIncomeStatisticsForDay: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		insert into mev_income_relays (validatorindex, day, tag_id, block_count, value)
+		select
+			blocks.proposer,
+			$1::int as day,
+			relays_blocks.tag_id,
+			count(*) as block_count,
+			coalesce(sum(case when bt.blockroot is null then relays_blocks.value else 0 end), 0) as value
+		from relays_blocks
+		inner join blocks on blocks.slot = relays_blocks.block_slot and blocks.blockroot = relays_blocks.block_root
+		left join blocks_tags bt on bt.blockroot = relays_blocks.block_root and bt.tag_id = 'invalid-relay-reward'
+		where relays_blocks.block_slot >= $2 and relays_blocks.block_slot < $3 and blocks.status = '1'
+		group by blocks.proposer, relays_blocks.tag_id
+		on conflict (validatorindex, day, tag_id) do update set
+			block_count = excluded.block_count,
+			value       = excluded.value`, day, firstSlot, firstSlotOfNextDay)
+	if err != nil {
+		return fmt.Errorf("error inserting mev_income_relays in WriteMevIncomeStatisticsForDay: %w", err)
+	}
+
+	var lastSlot uint64
+	err = tx.Get(&lastSlot, `select coalesce(max(slot),0) from blocks;`)
+	if err != nil {
+		return fmt.Errorf("error getting lastSlot in WriteMevIncomeStatisticsForDay: %w", err)
+	}
+
+	// if last exported slot is younger than the last slot of the exported day then the day is completely exported
+	if day < int64(utils.DayOfSlot(lastSlot)) {
+		_, err = tx.Exec(`insert into mev_income_stats_status (day, status) values ($1, true) on conflict (day) do update set status = excluded.status`, day)
+		if err != nil {
+			return fmt.Errorf("error updating mev_income_stats_status in WriteMevIncomeStatisticsForDay: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing db tx in WriteMevIncomeStatisticsForDay: %w", err)
+	}
+	return nil
+}
+
+// WriteRelayStatisticsForDay indexes the builders seen in relay payloads and computes the daily relay and builder market share.
+// A payload that has been delivered by multiple relays counts towards the market share of every delivering relay.
+func WriteRelayStatisticsForDay(day int64) error {
+	if day < 0 {
+		logger.Warnf("no relay-stats for days before beaconchain")
+		return nil
+	}
+
+	epochsPerDay := utils.EpochsPerDay()
+	firstSlot := uint64(day) * epochsPerDay * utils.Config.Chain.ClConfig.SlotsPerEpoch
+	firstSlotOfNextDay := uint64(day+1) * epochsPerDay * utils.Config.Chain.ClConfig.SlotsPerEpoch
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in WriteRelayStatisticsForDay: %w", err)
+	}
+	defer tx.Rollback()
+
+	var blockCount uint64
+	err = tx.Get(&blockCount, `select count(*) from blocks where slot >= $1 and slot < $2 and status = '1' and exec_block_hash is not null`, firstSlot, firstSlotOfNextDay)
+	if err != nil {
+		return fmt.Errorf("error getting block count in WriteRelayStatisticsForDay: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		insert into builders (builder_pubkey, extra_data, first_seen_slot, last_seen_slot)
+		select distinct on (relays_blocks.builder_pubkey)
+			relays_blocks.builder_pubkey,
+			blocks.exec_extra_data,
+			min(relays_blocks.block_slot) over (partition by relays_blocks.builder_pubkey),
+			max(relays_blocks.block_slot) over (partition by relays_blocks.builder_pubkey)
+		from relays_blocks
+		inner join blocks on blocks.slot = relays_blocks.block_slot and blocks.blockroot = relays_blocks.block_root
+		where relays_blocks.block_slot >= $1 and relays_blocks.block_slot < $2 and blocks.status = '1'
+		order by relays_blocks.builder_pubkey, relays_blocks.block_slot desc
+		on conflict (builder_pubkey) do update set
+			extra_data      = case when excluded.last_seen_slot >= builders.last_seen_slot then excluded.extra_data else builders.extra_data end,
+			first_seen_slot = least(builders.first_seen_slot, excluded.first_seen_slot),
+			last_seen_slot  = greatest(builders.last_seen_slot, excluded.last_seen_slot)`, firstSlot, firstSlotOfNextDay)
+	if err != nil {
+		return fmt.Errorf("error updating builders in WriteRelayStatisticsForDay: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		insert into relays_daily_stats (day, tag_id, block_count, total_value, market_share)
+		select
+			$1::int as day,
+			relays_blocks.tag_id,
+			count(*) as block_count,
+			sum(relays_blocks.value) as total_value,
+			count(*) / greatest($4::float, 1) as market_share
+		from relays_blocks
+		inner join blocks on blocks.slot = relays_blocks.block_slot and blocks.blockroot = relays_blocks.block_root
+		where relays_blocks.block_slot >= $2 and relays_blocks.block_slot < $3 and blocks.status = '1'
+		group by relays_blocks.tag_id
+		on conflict (day, tag_id) do update set
+			block_count  = excluded.block_count,
+			total_value  = excluded.total_value,
+			market_share = excluded.market_share`, day, firstSlot, firstSlotOfNextDay, blockCount)
+	if err != nil {
+		return fmt.Errorf("error inserting relays_daily_stats in WriteRelayStatisticsForDay: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		insert into builders_daily_stats (day, builder_pubkey, block_count, total_value, avg_bid_value, market_share)
+		select
+			$1::int as day,
+			rb.builder_pubkey,
+			count(*) as block_count,
+			sum(rb.value) as total_value,
+			round(avg(bids.value)) as avg_bid_value,
+			count(*) / greatest($4::float, 1) as market_share
+		from (
+			select distinct on (relays_blocks.block_slot, relays_blocks.block_root)
+				relays_blocks.block_slot, relays_blocks.block_root, relays_blocks.builder_pubkey, relays_blocks.value
+			from relays_blocks
+			inner join blocks on blocks.slot = relays_blocks.block_slot and blocks.blockroot = relays_blocks.block_root
+			where relays_blocks.block_slot >= $2 and relays_blocks.block_slot < $3 and blocks.status = '1'
+		) rb
+		left join (
+			select block_slot, builder_pubkey, max(value) as value
+			from relays_bids
+			where block_slot >= $2 and block_slot < $3
+			group by block_slot, builder_pubkey
+		) bids on bids.block_slot = rb.block_slot and bids.builder_pubkey = rb.builder_pubkey
+		group by rb.builder_pubkey
+		on conflict (day, builder_pubkey) do update set
+			block_count   = excluded.block_count,
+			total_value   = excluded.total_value,
+			avg_bid_value = excluded.avg_bid_value,
+			market_share  = excluded.market_share`, day, firstSlot, firstSlotOfNextDay, blockCount)
+	if err != nil {
+		return fmt.Errorf("error inserting builders_daily_stats in WriteRelayStatisticsForDay: %w", err)
+	}
+
+	var censoringBlockCount uint64
+	err = tx.Get(&censoringBlockCount, `
+		select count(distinct (relays_blocks.block_slot, relays_blocks.block_root))
+		from relays_blocks
+		inner join blocks on blocks.slot = relays_blocks.block_slot and blocks.blockroot = relays_blocks.block_root
+		inner join relays on relays.tag_id = relays_blocks.tag_id
+		where relays_blocks.block_slot >= $1 and relays_blocks.block_slot < $2 and blocks.status = '1' and relays.is_censoring`, firstSlot, firstSlotOfNextDay)
+	if err != nil {
+		return fmt.Errorf("error getting censoring block count in WriteRelayStatisticsForDay: %w", err)
+	}
+
+	var lastSlot uint64
+	err = tx.Get(&lastSlot, `select coalesce(max(slot),0) from blocks;`)
+	if err != nil {
+		return fmt.Errorf("error getting lastSlot in WriteRelayStatisticsForDay: %w", err)
+	}
+
+	// if last exported slot is younger than the last slot of the exported day then the day is completely exported
+	_, err = tx.Exec(`
+		insert into relays_daily_stats_status (day, block_count, censoring_block_count, status)
+		values ($1, $2, $3, $4)
+		on conflict (day) do update set
+			block_count           = excluded.block_count,
+			censoring_block_count = excluded.censoring_block_count,
+			status                = excluded.status`, day, blockCount, censoringBlockCount, day < int64(utils.DayOfSlot(lastSlot)))
+	if err != nil {
+		return fmt.Errorf("error updating relays_daily_stats_status in WriteRelayStatisticsForDay: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing db tx in WriteRelayStatisticsForDay: %w", err)
+	}
+	return nil
+}
+
+// WriteCensorshipStatisticsForDay aggregates the inclusion of watched transactions per relay, builder and for the whole network
+func WriteCensorshipStatisticsForDay(day int64) error {
+	if day < 0 {
+		logger.Warnf("no censorship-stats for days before beaconchain")
+		return nil
+	}
+
+	epochsPerDay := utils.EpochsPerDay()
+	firstSlot := uint64(day) * epochsPerDay * utils.Config.Chain.ClConfig.SlotsPerEpoch
+	firstSlotOfNextDay := uint64(day+1) * epochsPerDay * utils.Config.Chain.ClConfig.SlotsPerEpoch
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in WriteCensorshipStatisticsForDay: %w", err)
+	}
+	defer tx.Rollback()
+
+	// every block is attributed to the network, to each relay that delivered it and to its builder (or the local builder if no relay delivered it)
+	_, err = tx.Exec(`
+		with day_blocks as (
+			select slot, blockroot
+			from blocks
+			where slot >= $2 and slot < $3 and status = '1' and exec_block_hash is not null
+		), block_entities as (
+			select day_blocks.slot, $4::varchar as entity_type, $4::varchar as entity
+			from day_blocks
+			union
+			select day_blocks.slot, $5::varchar, relays_blocks.tag_id
+			from day_blocks
+			inner join relays_blocks on relays_blocks.block_slot = day_blocks.slot and relays_blocks.block_root = day_blocks.blockroot
+			union
+			select day_blocks.slot, $6::varchar, '0x' || encode(relays_blocks.builder_pubkey, 'hex')
+			from day_blocks
+			inner join relays_blocks on relays_blocks.block_slot = day_blocks.slot and relays_blocks.block_root = day_blocks.blockroot
+			union
+			select day_blocks.slot, $6::varchar, $7::varchar
+			from day_blocks
+			where not exists (select 1 from relays_blocks where relays_blocks.block_slot = day_blocks.slot and relays_blocks.block_root = day_blocks.blockroot)
+		)
+		insert into censorship_daily_stats (day, entity_type, entity, block_count, watched_tx_block_count, watched_tx_count, avg_inclusion_delay, avg_skipped_blocks)
+		select
+			$1::int as day,
+			block_entities.entity_type,
+			block_entities.entity,
+			count(distinct block_entities.slot) as block_count,
+			count(distinct censorship_watched_txs.included_slot) as watched_tx_block_count,
+			count(censorship_watched_txs.tx_hash) as watched_tx_count,
+			avg(censorship_watched_txs.inclusion_delay) as avg_inclusion_delay,
+			avg(censorship_watched_txs.skipped_blocks) as avg_skipped_blocks
+		from block_entities
+		left join censorship_watched_txs on censorship_watched_txs.included_slot = block_entities.slot
+		group by block_entities.entity_type, block_entities.entity
+		on conflict (day, entity_type, entity) do update set
+			block_count            = excluded.block_count,
+			watched_tx_block_count = excluded.watched_tx_block_count,
+			watched_tx_count       = excluded.watched_tx_count,
+			avg_inclusion_delay    = excluded.avg_inclusion_delay,
+			avg_skipped_blocks     = excluded.avg_skipped_blocks`,
+		day, firstSlot, firstSlotOfNextDay, types.CensorshipEntityNetwork, types.CensorshipEntityRelay, types.CensorshipEntityBuilder, types.CensorshipEntityLocalBuilder)
+	if err != nil {
+		return fmt.Errorf("error inserting censorship_daily_stats in WriteCensorshipStatisticsForDay: %w", err)
+	}
+
+	var lastSlot uint64
+	err = tx.Get(&lastSlot, `select coalesce(max(slot),0) from blocks;`)
+	if err != nil {
+		return fmt.Errorf("error getting lastSlot in WriteCensorshipStatisticsForDay: %w", err)
+	}
+
+	// if last exported slot is younger than the last slot of the exported day then the day is completely exported
+	_, err = tx.Exec(`
+		insert into censorship_daily_stats_status (day, status)
+		values ($1, $2)
+		on conflict (day) do update set status = excluded.status`, day, day < int64(utils.DayOfSlot(lastSlot)))
+	if err != nil {
+		return fmt.Errorf("error updating censorship_daily_stats_status in WriteCensorshipStatisticsForDay: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing db tx in WriteCensorshipStatisticsForDay: %w", err)
+	}
+	return nil
+}
+
+// WriteStakingFlowStatisticsForDay aggregates the deposits and withdrawals included in the canonical blocks of a day.
+// Withdrawals of validators that reached their withdrawable epoch are full withdrawals and count as outflow of stake,
+// all other withdrawals are partial withdrawals of rewards. Deposits and full withdrawals are additionally attributed
+// to the entities of validator_pool.
+func WriteStakingFlowStatisticsForDay(day int64) error {
+	if day < 0 {
+		logger.Warnf("no staking-flow-stats for days before beaconchain")
+		return nil
+	}
+
+	epochsPerDay := utils.EpochsPerDay()
+	firstSlot := uint64(day) * epochsPerDay * utils.Config.Chain.ClConfig.SlotsPerEpoch
+	firstSlotOfNextDay := uint64(day+1) * epochsPerDay * utils.Config.Chain.ClConfig.SlotsPerEpoch
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in WriteStakingFlowStatisticsForDay: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		create temp table staking_flows_deposits on commit drop as
+		select d.publickey, d.amount
+		from blocks_deposits d
+		inner join blocks b on b.slot = d.block_slot and b.blockroot = d.block_root and b.status = '1'
+		where d.block_slot >= $1 and d.block_slot < $2 and d.valid_signature`, firstSlot, firstSlotOfNextDay)
+	if err != nil {
+		return fmt.Errorf("error gathering deposits in WriteStakingFlowStatisticsForDay: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		create temp table staking_flows_withdrawals on commit drop as
+		select v.pubkey as publickey, w.amount, v.withdrawableepoch <= w.block_slot / $3 as is_full
+		from blocks_withdrawals w
+		inner join blocks b on b.slot = w.block_slot and b.blockroot = w.block_root and b.status = '1'
+		inner join validators v on v.validatorindex = w.validatorindex
+		where w.block_slot >= $1 and w.block_slot < $2`, firstSlot, firstSlotOfNextDay, utils.Config.Chain.ClConfig.SlotsPerEpoch)
+	if err != nil {
+		return fmt.Errorf("error gathering withdrawals in WriteStakingFlowStatisticsForDay: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		insert into staking_flows_daily (day, deposit_count, deposit_amount, full_withdrawal_count, full_withdrawal_amount, partial_withdrawal_amount)
+		select
+			$1::int as day,
+			(select count(*) from staking_flows_deposits),
+			(select coalesce(sum(amount), 0) from staking_flows_deposits),
+			(select count(*) from staking_flows_withdrawals where is_full),
+			(select coalesce(sum(amount), 0) from staking_flows_withdrawals where is_full),
+			(select coalesce(sum(amount), 0) from staking_flows_withdrawals where not is_full)
+		on conflict (day) do update set
+			deposit_count             = excluded.deposit_count,
+			deposit_amount            = excluded.deposit_amount,
+			full_withdrawal_count     = excluded.full_withdrawal_count,
+			full_withdrawal_amount    = excluded.full_withdrawal_amount,
+			partial_withdrawal_amount = excluded.partial_withdrawal_amount`, day)
+	if err != nil {
+		return fmt.Errorf("error inserting staking_flows_daily in WriteStakingFlowStatisticsForDay: %w", err)
+	}
+
+	// entities can change between exports of the same day, so the rows of the day are replaced instead of upserted
+	_, err = tx.Exec(`delete from staking_flows_entities_daily where day = $1`, day)
+	if err != nil {
+		return fmt.Errorf("error deleting staking_flows_entities_daily in WriteStakingFlowStatisticsForDay: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		insert into staking_flows_entities_daily (day, entity, deposit_count, deposit_amount, full_withdrawal_count, full_withdrawal_amount)
+		select
+			$1::int as day,
+			flows.entity,
+			sum(flows.deposit_count),
+			sum(flows.deposit_amount),
+			sum(flows.full_withdrawal_count),
+			sum(flows.full_withdrawal_amount)
+		from (
+			select coalesce(p.pool, $2) as entity, count(*) as deposit_count, sum(d.amount) as deposit_amount, 0 as full_withdrawal_count, 0 as full_withdrawal_amount
+			from staking_flows_deposits d
+			left join validator_pool p on p.publickey = d.publickey
+			group by 1
+			union all
+			select coalesce(p.pool, $2), 0, 0, count(*), sum(w.amount)
+			from staking_flows_withdrawals w
+			left join validator_pool p on p.publickey = w.publickey
+			where w.is_full
+			group by 1
+		) flows
+		group by flows.entity`, day, validatorEntityUnknown)
+	if err != nil {
+		return fmt.Errorf("error inserting staking_flows_entities_daily in WriteStakingFlowStatisticsForDay: %w", err)
+	}
+
+	var lastSlot uint64
+	err = tx.Get(&lastSlot, `select coalesce(max(slot),0) from blocks;`)
+	if err != nil {
+		return fmt.Errorf("error getting lastSlot in WriteStakingFlowStatisticsForDay: %w", err)
+	}
+
+	// if last exported slot is younger than the last slot of the exported day then the day is completely exported
+	_, err = tx.Exec(`
+		insert into staking_flows_daily_status (day, status)
+		values ($1, $2)
+		on conflict (day) do update set status = excluded.status`, day, day < int64(utils.DayOfSlot(lastSlot)))
+	if err != nil {
+		return fmt.Errorf("error updating staking_flows_daily_status in WriteStakingFlowStatisticsForDay: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing db tx in WriteStakingFlowStatisticsForDay: %w", err)
+	}
+	return nil
+}
+
+// GetStakingFlowsHistory returns the daily network wide and per entity staking flows for all days starting with fromDay
+func GetStakingFlowsHistory(fromDay uint64) (*types.StakingFlowsHistory, error) {
+	history := &types.StakingFlowsHistory{
+		Days:     []*types.StakingFlowsDailyStats{},
+		Entities: []*types.StakingFlowsEntityDailyStats{},
+	}
+
+	err := ReaderDb.Select(&history.Days, `
+		select day, deposit_count, deposit_amount, full_withdrawal_count, full_withdrawal_amount, partial_withdrawal_amount
+		from staking_flows_daily
+		where day >= $1
+		order by day`, fromDay)
+	if err != nil {
+		return nil, fmt.Errorf("error getting staking_flows_daily: %w", err)
+	}
+
+	err = ReaderDb.Select(&history.Entities, `
+		select day, entity, deposit_count, deposit_amount, full_withdrawal_count, full_withdrawal_amount
+		from staking_flows_entities_daily
+		where day >= $1
+		order by day, deposit_amount desc`, fromDay)
+	if err != nil {
+		return nil, fmt.Errorf("error getting staking_flows_entities_daily: %w", err)
+	}
+
+	for _, d := range history.Days {
+		d.NetFlow = int64(d.DepositAmount) - int64(d.FullWithdrawalAmount)
+	}
+	for _, e := range history.Entities {
+		e.NetFlow = int64(e.DepositAmount) - int64(e.FullWithdrawalAmount)
+	}
+
+	return history, nil
+}
+
+// GetAverageStakingFlowCounts returns the average count of deposits and full withdrawals per day starting with fromDay
+func GetAverageStakingFlowCounts(fromDay uint64) (deposits float64, fullWithdrawals float64, err error) {
+	row := struct {
+		Deposits        float64 `db:"deposits"`
+		FullWithdrawals float64 `db:"full_withdrawals"`
+	}{}
+	err = ReaderDb.Get(&row, `
+		select coalesce(avg(deposit_count), 0) as deposits, coalesce(avg(full_withdrawal_count), 0) as full_withdrawals
+		from staking_flows_daily
+		where day >= $1`, fromDay)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error getting average staking flow counts: %w", err)
+	}
+	return row.Deposits, row.FullWithdrawals, nil
+}
+
+// attestationIncludable returns whether an attestation of attSlot can be included in a block of blockSlot. Before deneb
+// attestations can be included up to one epoch after their slot, afterwards until the end of the epoch following their epoch.
+func attestationIncludable(attSlot, blockSlot uint64) bool {
+	if attSlot >= blockSlot {
+		return false
+	}
+	if utils.EpochOfSlot(blockSlot) < utils.Config.Chain.ClConfig.DenebForkEpoch {
+		return blockSlot-attSlot <= utils.Config.Chain.ClConfig.SlotsPerEpoch
+	}
+	return utils.EpochOfSlot(attSlot)+1 >= utils.EpochOfSlot(blockSlot)
+}
+
+// WriteAttestationPackingStatisticsForDay computes how well the proposers of the canonical blocks of a day packed attestations.
+// A vote (validator and attestation slot) counts as included in the block that included it first. Votes that were
+// first included in a later block although the block could have included them count as missed, included votes with
+// an inclusion distance of more than one slot count as late. The score of a block is included / (included + missed).
+func WriteAttestationPackingStatisticsForDay(day int64) error {
+	if day < 0 {
+		logger.Warnf("no attestation-packing-stats for days before beaconchain")
+		return nil
+	}
+
+	epochsPerDay := utils.EpochsPerDay()
+	firstSlot := uint64(day) * epochsPerDay * utils.Config.Chain.ClConfig.SlotsPerEpoch
+	firstSlotOfNextDay := uint64(day+1) * epochsPerDay * utils.Config.Chain.ClConfig.SlotsPerEpoch
+
+	// votes can be included up to two epochs after their slot, so the votes of the surrounding epochs are needed as well
+	window := 2 * utils.Config.Chain.ClConfig.SlotsPerEpoch
+	lowerSlot := uint64(0)
+	if firstSlot > window {
+		lowerSlot = firstSlot - window
+	}
+	upperSlot := firstSlotOfNextDay + window
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in WriteAttestationPackingStatisticsForDay: %w", err)
+	}
+	defer tx.Rollback()
+
+	attestations := []struct {
+		AttSlot         uint64 `db:"att_slot"`
+		CommitteeIndex  uint64 `db:"committeeindex"`
+		BlockSlot       uint64 `db:"block_slot"`
+		AggregationBits []byte `db:"aggregationbits"`
+	}{}
+	err = tx.Select(&attestations, `
+		select a.slot as att_slot, a.committeeindex, a.block_slot, a.aggregationbits
+		from blocks_attestations a
+		inner join blocks b on b.slot = a.block_slot and b.blockroot = a.block_root and b.status = '1'
+		where a.block_slot >= $1 and a.block_slot < $2 and a.slot >= $1
+		order by a.block_slot, a.block_index`, lowerSlot, upperSlot)
+	if err != nil {
+		return fmt.Errorf("error getting attestations in WriteAttestationPackingStatisticsForDay: %w", err)
+	}
+
+	// votesByAttSlot maps the attestation slot to the number of votes first included in each slot. The votes an
+	// attestation adds are the bits of its aggregation bits that were not set by an earlier attestation of the committee.
+	votesByAttSlot := map[uint64]map[uint64]uint64{}
+	seenByCommittee := map[[2]uint64]bitfield.Bitlist{}
+	for _, a := range attestations {
+		bits := bitfield.Bitlist(a.AggregationBits)
+		key := [2]uint64{a.AttSlot, a.CommitteeIndex}
+
+		newVotes := bits.Count()
+		if seen, ok := seenByCommittee[key]; ok {
+			merged, err := seen.Or(bits)
+			if err != nil {
+				return fmt.Errorf("error merging aggregation bits of slot %v committee %v in WriteAttestationPackingStatisticsForDay: %w", a.AttSlot, a.CommitteeIndex, err)
+			}
+			newVotes = merged.Count() - seen.Count()
+			bits = merged
+		}
+		seenByCommittee[key] = bits
+
+		if newVotes == 0 {
+			continue
+		}
+		if votesByAttSlot[a.AttSlot] == nil {
+			votesByAttSlot[a.AttSlot] = map[uint64]uint64{}
+		}
+		votesByAttSlot[a.AttSlot][a.BlockSlot] += newVotes
+	}
+
+	blocks := []struct {
+		Slot     uint64 `db:"slot"`
+		Proposer uint64 `db:"proposer"`
+	}{}
+	err = tx.Select(&blocks, `select slot, proposer from blocks where slot >= $1 and slot < $2 and status = '1' order by slot`, firstSlot, firstSlotOfNextDay)
+	if err != nil {
+		return fmt.Errorf("error getting blocks in WriteAttestationPackingStatisticsForDay: %w", err)
+	}
+
+	for _, b := range blocks {
+		var included, missed, late uint64
+		for attSlot, firstInclusions := range votesByAttSlot {
+			if !attestationIncludable(attSlot, b.Slot) {
+				continue
+			}
+			for firstInclusion, count := range firstInclusions {
+				if firstInclusion == b.Slot {
+					included += count
+					if b.Slot-attSlot > 1 {
+						late += count
+					}
+				} else if firstInclusion > b.Slot {
+					missed += count
+				}
+			}
+		}
+
+		score := 1.0
+		if included+missed > 0 {
+			score = float64(included) / float64(included+missed)
+		}
+
+		_, err = tx.Exec(`
+			insert into attestation_packing (slot, proposer, included_votes, missed_votes, late_votes, score)
+			values ($1, $2, $3, $4, $5, $6)
+			on conflict (slot) do update set
+				proposer       = excluded.proposer,
+				included_votes = excluded.included_votes,
+				missed_votes   = excluded.missed_votes,
+				late_votes     = excluded.late_votes,
+				score          = excluded.score`, b.Slot, b.Proposer, included, missed, late, score)
+		if err != nil {
+			return fmt.Errorf("error inserting attestation_packing of slot %v in WriteAttestationPackingStatisticsForDay: %w", b.Slot, err)
+		}
+	}
+
+	var lastSlot uint64
+	err = tx.Get(&lastSlot, `select coalesce(max(slot),0) from blocks;`)
+	if err != nil {
+		return fmt.Errorf("error getting lastSlot in WriteAttestationPackingStatisticsForDay: %w", err)
+	}
+
+	// missed votes of the last blocks of the day are only known once the following blocks have been exported
+	_, err = tx.Exec(`
+		insert into attestation_packing_status (day, status)
+		values ($1, $2)
+		on conflict (day) do update set status = excluded.status`, day, lastSlot >= upperSlot)
+	if err != nil {
+		return fmt.Errorf("error updating attestation_packing_status in WriteAttestationPackingStatisticsForDay: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing db tx in WriteAttestationPackingStatisticsForDay: %w", err)
+	}
+	return nil
+}
+
+// GetBlockAttestationPacking returns the attestation packing of the canonical block of a slot, nil is returned if it has not been computed yet
+func GetBlockAttestationPacking(slot uint64) (*types.BlockAttestationPacking, error) {
+	packing := &types.BlockAttestationPacking{}
+	err := ReaderDb.Get(packing, `select slot, proposer, included_votes, missed_votes, late_votes, score from attestation_packing where slot = $1`, slot)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting attestation packing of slot %v: %w", slot, err)
+	}
+	return packing, nil
+}
+
+// GetAttestationPackingLeaderboard returns the attestation packing of the blocks since fromSlot aggregated per entity,
+// entities with less than minBlocks blocks are omitted. The entities with the lowest score come first.
+func GetAttestationPackingLeaderboard(fromSlot, minBlocks uint64) ([]*types.AttestationPackingEntityStats, error) {
+	stats := []*types.AttestationPackingEntityStats{}
+	err := ReaderDb.Select(&stats, `
+		select
+			coalesce(p.pool, $3) as entity,
+			count(*) as block_count,
+			sum(ap.included_votes) as included_votes,
+			sum(ap.missed_votes) as missed_votes,
+			sum(ap.late_votes) as late_votes,
+			avg(ap.score) as avg_score,
+			min(ap.score) as min_score
+		from attestation_packing ap
+		inner join validators v on v.validatorindex = ap.proposer
+		left join validator_pool p on p.publickey = v.pubkey
+		where ap.slot >= $1
+		group by 1
+		having count(*) >= $2
+		order by avg_score, block_count desc`, fromSlot, minBlocks, validatorEntityUnknown)
+	if err != nil {
+		return nil, fmt.Errorf("error getting attestation packing leaderboard: %w", err)
+	}
+	return stats, nil
+}
+
+// GetWorstPackedBlocks returns the blocks since fromSlot with the lowest attestation packing score
+func GetWorstPackedBlocks(fromSlot, limit uint64) ([]*types.BlockAttestationPacking, error) {
+	blocks := []*types.BlockAttestationPacking{}
+	err := ReaderDb.Select(&blocks, `
+		select slot, proposer, included_votes, missed_votes, late_votes, score
+		from attestation_packing
+		where slot >= $1
+		order by score, missed_votes desc
+		limit $2`, fromSlot, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error getting worst packed blocks: %w", err)
+	}
+	return blocks, nil
+}
+
+// GetRelaysMarketShareHistory returns the daily relay and builder market share for all days starting with fromDay
+func GetRelaysMarketShareHistory(fromDay uint64) (*types.RelaysMarketShareHistory, error) {
+	history := &types.RelaysMarketShareHistory{
+		Days:     []*types.RelaysDailyOverview{},
+		Relays:   []*types.RelayDailyStats{},
+		Builders: []*types.BuilderDailyStats{},
+	}
+
+	err := ReaderDb.Select(&history.Days, `
+		SELECT day, block_count, censoring_block_count
+		FROM relays_daily_stats_status
+		WHERE day >= $1
+		ORDER BY day`, fromDay)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving relays_daily_stats_status: %w", err)
+	}
+
+	err = ReaderDb.Select(&history.Relays, `
+		SELECT day, tag_id, block_count, total_value, market_share
+		FROM relays_daily_stats
+		WHERE day >= $1
+		ORDER BY day, block_count DESC`, fromDay)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving relays_daily_stats: %w", err)
+	}
+
+	err = ReaderDb.Select(&history.Builders, `
+		SELECT bds.day, '0x' || encode(bds.builder_pubkey, 'hex') AS builder_pubkey, COALESCE('0x' || encode(builders.extra_data, 'hex'), '') AS extra_data, bds.block_count, bds.total_value, COALESCE(bds.avg_bid_value, 0) AS avg_bid_value, bds.market_share
+		FROM builders_daily_stats bds
+		LEFT JOIN builders ON builders.builder_pubkey = bds.builder_pubkey
+		WHERE bds.day >= $1
+		ORDER BY bds.day, bds.block_count DESC`, fromDay)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving builders_daily_stats: %w", err)
+	}
+
+	return history, nil
+}
+
+// GetValidatorsMevIncome returns the aggregated mev income and relay usage of the given validators
+func GetValidatorsMevIncome(validatorIndices []uint64) (map[uint64]*types.ValidatorMevIncome, error) {
+	incomes := []*types.ValidatorMevIncome{}
+	err := ReaderDb.Select(&incomes, `
+		SELECT
+			validatorindex,
+			COALESCE(SUM(builder_blocks), 0) AS builder_blocks,
+			COALESCE(SUM(local_blocks), 0) AS local_blocks,
+			COALESCE(SUM(mev_value), 0) AS mev_value
+		FROM mev_income
+		WHERE validatorindex = ANY($1)
+		GROUP BY validatorindex`, pq.Array(validatorIndices))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving mev income: %w", err)
+	}
+
+	relays := []struct {
+		ValidatorIndex uint64 `db:"validatorindex"`
+		types.ValidatorMevIncomeRelay
+	}{}
+	err = ReaderDb.Select(&relays, `
+		SELECT
+			validatorindex,
+			tag_id,
+			SUM(block_count) AS block_count,
+			SUM(value) AS value
+		FROM mev_income_relays
+		WHERE validatorindex = ANY($1)
+		GROUP BY validatorindex, tag_id
+		ORDER BY block_count DESC`, pq.Array(validatorIndices))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving mev income relays: %w", err)
+	}
+
+	incomeMap := make(map[uint64]*types.ValidatorMevIncome, len(incomes))
+	for _, income := range incomes {
+		income.Relays = []*types.ValidatorMevIncomeRelay{}
+		incomeMap[income.ValidatorIndex] = income
+	}
+	for i := range relays {
+		income, ok := incomeMap[relays[i].ValidatorIndex]
+		if !ok {
+			continue
+		}
+		income.Relays = append(income.Relays, &relays[i].ValidatorMevIncomeRelay)
+	}
+
+	return incomeMap, nil
+}
+
+// GetValidatorsPercentiles returns the percentile ranks of the given validators averaged over the last exported days,
+// nil if none of the validators has been ranked during these days
+func GetValidatorsPercentiles(validatorIndices []uint64, days uint64) (*types.ValidatorPercentiles, error) {
+	lastDay, err := GetLastExportedStatisticDay()
+	if err == ErrNoStats {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	percentiles := &types.ValidatorPercentiles{}
+	err = ReaderDb.Get(percentiles, `
+		SELECT
+			COUNT(DISTINCT day) AS days,
+			COALESCE(AVG(attestation_percentile), 0) AS attestation_percentile,
+			COALESCE(AVG(proposal_luck_percentile), 0) AS proposal_luck_percentile,
+			COALESCE(AVG(income_percentile), 0) AS income_percentile
+		FROM validator_stats
+		WHERE validatorindex = ANY($1) AND day > $2 AND day <= $3 AND income_percentile IS NOT NULL`,
+		pq.Array(validatorIndices), int64(lastDay)-int64(days), lastDay)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validator percentiles: %w", err)
+	}
+	if percentiles.Days == 0 {
+		return nil, nil
+	}
+	return percentiles, nil
+}
+
+// RefreshValidatorLeaderboard recomputes the validator_leaderboard rollup from the daily validator statistics and effectiveness scores
+func RefreshValidatorLeaderboard() error {
+	start := time.Now()
+	defer func() {
+		metrics.TaskDuration.WithLabelValues("db_refresh_validator_leaderboard").Observe(time.Since(start).Seconds())
+	}()
+
+	populated, err := isValidatorLeaderboardPopulated()
+	if err != nil {
+		return err
+	}
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// the view reads the version of the effectiveness scores from the setting
+	_, err = tx.Exec(`SELECT set_config('explorer.effectiveness_score_version', $1, true)`, fmt.Sprintf("%d", utils.EffectivenessScoreVersion))
+	if err != nil {
+		return fmt.Errorf("error setting effectiveness score version of validator_leaderboard: %w", err)
+	}
+
+	// a view created without data can only be refreshed concurrently once it has been populated
+	if populated {
+		_, err = tx.Exec(`REFRESH MATERIALIZED VIEW CONCURRENTLY validator_leaderboard`)
+	} else {
+		_, err = tx.Exec(`REFRESH MATERIALIZED VIEW validator_leaderboard`)
+	}
+	if err != nil {
+		return fmt.Errorf("error refreshing validator_leaderboard: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing refresh of validator_leaderboard: %w", err)
+	}
+	logger.Infof("refreshed validator leaderboard, took %v", time.Since(start))
+	return nil
+}
+
+func isValidatorLeaderboardPopulated() (bool, error) {
+	var populated bool
+	err := ReaderDb.Get(&populated, `select coalesce((select ispopulated from pg_matviews where matviewname = 'validator_leaderboard'), false)`)
+	if err != nil {
+		return false, fmt.Errorf("error checking if validator_leaderboard is populated: %w", err)
+	}
+	return populated, nil
+}
+
+// GetValidatorLeaderboard returns the validators of the leaderboard window matching the filter, ranked by income or
+// efficiency. The best validators come first unless the bottom performers are requested.
+func GetValidatorLeaderboard(filter *types.ValidatorLeaderboardFilter, limit, offset uint64) ([]*types.ValidatorLeaderboardEntry, error) {
+	orderBy := "l.income_gwei"
+	if filter.Mode == "efficiency" {
+		orderBy = "l.efficiency"
+	}
+	orderDir := "desc"
+	if filter.Bottom {
+		orderDir = "asc"
+	}
+
+	entries := []*types.ValidatorLeaderboardEntry{}
+	populated, err := isValidatorLeaderboardPopulated()
+	if err != nil || !populated {
+		return entries, err
+	}
+	err = ReaderDb.Select(&entries, `
+		select
+			l.validatorindex,
+			v.pubkey,
+			coalesce(vn.name, '') as name,
+			l.entity,
+			l.active_days,
+			l.income_gwei,
+			l.efficiency,
+			count(*) over () as total_count
+		from validator_leaderboard l
+		inner join validators v on v.validatorindex = l.validatorindex
+		left join validator_names vn on vn.publickey = v.pubkey
+		where l.window_days = $1 and l.active_days >= $2 and ($3 = '' or l.entity = $3)
+		order by `+orderBy+` `+orderDir+` nulls last, l.validatorindex
+		limit $4
+		offset $5`, filter.WindowDays, filter.MinActiveDays, filter.Entity, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error getting validator leaderboard: %w", err)
+	}
+	return entries, nil
+}
+
+// GetValidatorLeaderboardEntities returns the entities of the validators of the leaderboard
+func GetValidatorLeaderboardEntities() ([]string, error) {
+	entities := []string{}
+	populated, err := isValidatorLeaderboardPopulated()
+	if err != nil || !populated {
+		return entities, err
+	}
+	err = ReaderDb.Select(&entities, `select distinct entity from validator_leaderboard order by entity`)
+	if err != nil {
+		return nil, fmt.Errorf("error getting validator leaderboard entities: %w", err)
+	}
+	return entities, nil
+}
+
 func CheckIfDayIsFinalized(day uint64) error {
 	_, lastEpoch := utils.GetFirstAndLastEpochForDay(day)
 
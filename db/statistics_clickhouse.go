@@ -0,0 +1,257 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// validatorStatsClickhouseColumns are the columns of the validator_stats_daily clickhouse table, the table mirrors the
+// validator_stats table of postgres. Days are written again on re-exports, the ReplacingMergeTree keeps the latest
+// version of a (validatorindex, day) row so reads have to use FINAL.
+var validatorStatsClickhouseColumns = []string{
+	"validatorindex",
+	"day",
+	"start_balance",
+	"end_balance",
+	"min_balance",
+	"max_balance",
+	"start_effective_balance",
+	"end_effective_balance",
+	"min_effective_balance",
+	"max_effective_balance",
+	"missed_attestations",
+	"missed_attestations_total",
+	"orphaned_attestations",
+	"participated_sync",
+	"participated_sync_total",
+	"missed_sync",
+	"missed_sync_total",
+	"orphaned_sync",
+	"orphaned_sync_total",
+	"proposed_blocks",
+	"missed_blocks",
+	"orphaned_blocks",
+	"attester_slashings",
+	"proposer_slashings",
+	"deposits",
+	"deposits_total",
+	"deposits_amount",
+	"deposits_amount_total",
+	"withdrawals",
+	"withdrawals_total",
+	"withdrawals_amount",
+	"withdrawals_amount_total",
+	"cl_rewards_gwei",
+	"cl_rewards_gwei_total",
+	"el_rewards_wei",
+	"el_rewards_wei_total",
+	"mev_rewards_wei",
+	"mev_rewards_wei_total",
+	"expected_proposals",
+	"expected_sync_slots",
+	"attestation_percentile",
+	"proposal_luck_percentile",
+	"income_percentile",
+}
+
+// ClickhouseStatisticsReadsEnabled returns true if the validator statistics charts and history should be read from clickhouse
+func ClickhouseStatisticsReadsEnabled() bool {
+	return utils.Config.ClickHouseEnabled && utils.Config.ClickHouse.StatisticsReadsEnabled && ClickhouseReaderDb != nil
+}
+
+// InitClickhouseStatisticsSchema creates the validator_stats_daily table if it does not exist yet
+func InitClickhouseStatisticsSchema() error {
+	_, err := ClickhouseWriterDb.Exec(`
+		CREATE TABLE IF NOT EXISTS validator_stats_daily (
+			validatorindex            UInt64,
+			day                       Int64,
+			start_balance             Int64,
+			end_balance               Int64,
+			min_balance               Int64,
+			max_balance               Int64,
+			start_effective_balance   Int64,
+			end_effective_balance     Int64,
+			min_effective_balance     Int64,
+			max_effective_balance     Int64,
+			missed_attestations       Int64,
+			missed_attestations_total Int64,
+			orphaned_attestations     Int64,
+			participated_sync         Int64,
+			participated_sync_total   Int64,
+			missed_sync               Int64,
+			missed_sync_total         Int64,
+			orphaned_sync             Int64,
+			orphaned_sync_total       Int64,
+			proposed_blocks           Int64,
+			missed_blocks             Int64,
+			orphaned_blocks           Int64,
+			attester_slashings        Int64,
+			proposer_slashings        Int64,
+			deposits                  Int64,
+			deposits_total            Int64,
+			deposits_amount           Int64,
+			deposits_amount_total     Int64,
+			withdrawals               Int64,
+			withdrawals_total         Int64,
+			withdrawals_amount        Int64,
+			withdrawals_amount_total  Int64,
+			cl_rewards_gwei           Int64,
+			cl_rewards_gwei_total     Int64,
+			el_rewards_wei            Decimal(38, 0),
+			el_rewards_wei_total      Decimal(38, 0),
+			mev_rewards_wei           Decimal(38, 0),
+			mev_rewards_wei_total     Decimal(38, 0),
+			expected_proposals        Nullable(Float64),
+			expected_sync_slots       Nullable(Float64),
+			attestation_percentile    Nullable(Float64),
+			proposal_luck_percentile  Nullable(Float64),
+			income_percentile         Nullable(Float64),
+			inserted_at               DateTime DEFAULT now()
+		)
+		ENGINE = ReplacingMergeTree(inserted_at)
+		PARTITION BY intDiv(day, 100)
+		ORDER BY (validatorindex, day)`)
+	if err != nil {
+		return fmt.Errorf("error creating validator_stats_daily table: %w", err)
+	}
+	return nil
+}
+
+// WriteValidatorStatisticsToClickhouse writes the statistics of all validators for the day to clickhouse
+func WriteValidatorStatisticsToClickhouse(day uint64, data []*types.ValidatorStatsTableDbRow) error {
+	start := time.Now()
+
+	tx, err := ClickhouseWriterDb.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting clickhouse batch: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO validator_stats_daily (%s)", strings.Join(validatorStatsClickhouseColumns, ", ")))
+	if err != nil {
+		return fmt.Errorf("error preparing clickhouse batch: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, d := range data {
+		if d == nil {
+			continue
+		}
+		_, err = stmt.Exec(
+			d.ValidatorIndex,
+			d.Day,
+			d.StartBalance,
+			d.EndBalance,
+			d.MinBalance,
+			d.MaxBalance,
+			d.StartEffectiveBalance,
+			d.EndEffectiveBalance,
+			d.MinEffectiveBalance,
+			d.MaxEffectiveBalance,
+			d.MissedAttestations,
+			d.MissedAttestationsTotal,
+			d.OrphanedAttestations,
+			d.ParticipatedSync,
+			d.ParticipatedSyncTotal,
+			d.MissedSync,
+			d.MissedSyncTotal,
+			d.OrphanedSync,
+			d.OrphanedSyncTotal,
+			d.ProposedBlocks,
+			d.MissedBlocks,
+			d.OrphanedBlocks,
+			d.AttesterSlashings,
+			d.ProposerSlashing,
+			d.Deposits,
+			d.DepositsTotal,
+			d.DepositsAmount,
+			d.DepositsAmountTotal,
+			d.Withdrawals,
+			d.WithdrawalsTotal,
+			d.WithdrawalsAmount,
+			d.WithdrawalsAmountTotal,
+			d.ClRewardsGWei,
+			d.ClRewardsGWeiTotal,
+			d.ElRewardsWei,
+			d.ElRewardsWeiTotal,
+			d.MEVRewardsWei,
+			d.MEVRewardsWeiTotal,
+			d.ExpectedProposals,
+			d.ExpectedSyncSlots,
+			d.AttestationPercentile,
+			d.ProposalLuckPercentile,
+			d.IncomePercentile,
+		)
+		if err != nil {
+			return fmt.Errorf("error appending statistics of validator %v to clickhouse batch: %w", d.ValidatorIndex, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error sending clickhouse batch: %w", err)
+	}
+
+	logger.Infof("wrote statistics of %v validators for day %v to clickhouse, took %v", len(data), day, time.Since(start))
+	return nil
+}
+
+// getValidatorIncomeHistoryClickhouse is the clickhouse equivalent of the validator_stats query of GetValidatorIncomeHistory
+func getValidatorIncomeHistoryClickhouse(validatorIndices []uint64, lowerBoundDay uint64, upperBoundDay uint64) ([]types.ValidatorIncomeHistory, error) {
+	result := []types.ValidatorIncomeHistory{}
+	err := ClickhouseReaderDb.Select(&result, `
+		SELECT
+			day,
+			SUM(cl_rewards_gwei) AS cl_rewards_gwei,
+			SUM(end_balance) AS end_balance
+		FROM validator_stats_daily FINAL
+		WHERE validatorindex IN (?) AND day >= ? AND day <= ?
+		GROUP BY day
+		ORDER BY day`, validatorIndices, lowerBoundDay, upperBoundDay)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validator income history from clickhouse: %w", err)
+	}
+	return result, nil
+}
+
+// GetValidatorDailyStatsClickhouse returns the daily statistics rows of the validator between startDay and endDay with
+// the columns of the validator daily stats api
+func GetValidatorDailyStatsClickhouse(index uint64, startDay int64, endDay int64) (*sql.Rows, error) {
+	return ClickhouseReaderDb.Query(`
+		SELECT
+			validatorindex,
+			day,
+			start_balance,
+			end_balance,
+			min_balance,
+			max_balance,
+			start_effective_balance,
+			end_effective_balance,
+			min_effective_balance,
+			max_effective_balance,
+			missed_attestations,
+			toInt64(0) AS orphaned_attestations,
+			proposed_blocks,
+			missed_blocks,
+			orphaned_blocks,
+			attester_slashings,
+			proposer_slashings,
+			deposits,
+			deposits_amount,
+			withdrawals,
+			withdrawals_amount,
+			participated_sync,
+			missed_sync,
+			orphaned_sync,
+			attestation_percentile,
+			proposal_luck_percentile,
+			income_percentile
+		FROM validator_stats_daily FINAL
+		WHERE validatorindex = ? AND day <= ? AND day >= ?
+		ORDER BY day DESC`, index, endDay, startDay)
+}
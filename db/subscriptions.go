@@ -288,3 +288,142 @@ func GetMailsSentCount(email string, t time.Time) (int, error) {
 	}
 	return count, err
 }
+
+const userNotificationSubscriptionColumns = `id, event_name, event_filter, COALESCE(event_threshold, 0) AS event_threshold,
+	COALESCE(channels::TEXT[], '{}') AS channels, created_ts, last_sent_ts, last_sent_epoch`
+
+// subscriptionEventName returns the event name a subscription of the event is stored with, events that are not indexed
+// by user are prefixed with the network
+func subscriptionEventName(eventName types.EventName) string {
+	if types.IsUserIndexed(eventName) {
+		return string(eventName)
+	}
+	return utils.GetNetwork() + ":" + string(eventName)
+}
+
+func trimSubscriptionEventNames(subs []*types.UserNotificationSubscription) {
+	for _, sub := range subs {
+		sub.EventName = types.EventName(strings.TrimPrefix(string(sub.EventName), utils.GetNetwork()+":"))
+	}
+}
+
+// subscriptionChannels returns the channels argument of a subscription, no channels are stored as NULL
+func subscriptionChannels(channels pq.StringArray) interface{} {
+	if len(channels) == 0 {
+		return nil
+	}
+	return channels
+}
+
+// GetUserNotificationSubscriptions returns a page of the notification subscriptions of a user on the current network
+// together with the total number of its subscriptions
+func GetUserNotificationSubscriptions(userID, limit, offset uint64) ([]*types.UserNotificationSubscription, uint64, error) {
+	subs := []*types.UserNotificationSubscription{}
+	var total uint64
+	network := utils.GetNetwork() + ":%"
+	err := FrontendWriterDB.Get(&total, `
+		SELECT COUNT(*) FROM users_subscriptions
+		WHERE user_id = $1 AND (event_name LIKE $2 OR event_name = ANY($3))`, userID, network, pq.Array(types.UserIndexEvents))
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting notification subscriptions of user %v: %w", userID, err)
+	}
+	err = FrontendWriterDB.Select(&subs, `
+		SELECT `+userNotificationSubscriptionColumns+` FROM users_subscriptions
+		WHERE user_id = $1 AND (event_name LIKE $2 OR event_name = ANY($3))
+		ORDER BY id
+		LIMIT $4 OFFSET $5`, userID, network, pq.Array(types.UserIndexEvents), limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error retrieving notification subscriptions of user %v: %w", userID, err)
+	}
+	trimSubscriptionEventNames(subs)
+	return subs, total, nil
+}
+
+// GetUserNotificationSubscription returns a notification subscription of a user, nil if it does not exist or belongs
+// to another user
+func GetUserNotificationSubscription(userID, subscriptionID uint64) (*types.UserNotificationSubscription, error) {
+	subs := []*types.UserNotificationSubscription{}
+	err := FrontendWriterDB.Select(&subs, `
+		SELECT `+userNotificationSubscriptionColumns+` FROM users_subscriptions
+		WHERE user_id = $1 AND id = $2`, userID, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving notification subscription %v of user %v: %w", subscriptionID, userID, err)
+	}
+	if len(subs) == 0 {
+		return nil, nil
+	}
+	trimSubscriptionEventNames(subs)
+	return subs[0], nil
+}
+
+// SaveUserNotificationSubscriptions creates the notification subscriptions of a user, subscriptions to the same event
+// and filter as an existing subscription replace its threshold and channels. Returns the saved subscriptions.
+func SaveUserNotificationSubscriptions(userID uint64, subs []*types.UserNotificationSubscription) ([]*types.UserNotificationSubscription, error) {
+	tx, err := FrontendWriterDB.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	ids := make([]uint64, 0, len(subs))
+	for _, sub := range subs {
+		var id uint64
+		err = tx.Get(&id, `
+			INSERT INTO users_subscriptions (user_id, event_name, event_filter, event_threshold, channels, created_ts, created_epoch)
+			VALUES ($1, $2, $3, $4, $5::notification_channels[], TO_TIMESTAMP($6), $7)
+			ON CONFLICT (user_id, event_name, event_filter) DO UPDATE SET event_threshold = EXCLUDED.event_threshold, channels = EXCLUDED.channels
+			RETURNING id`,
+			userID, subscriptionEventName(sub.EventName), sub.EventFilter, sub.EventThreshold, subscriptionChannels(sub.Channels), now.Unix(), utils.TimeToEpoch(now))
+		if err != nil {
+			return nil, fmt.Errorf("error saving notification subscription of user %v: %w", userID, err)
+		}
+		ids = append(ids, id)
+	}
+
+	saved := []*types.UserNotificationSubscription{}
+	err = tx.Select(&saved, `
+		SELECT `+userNotificationSubscriptionColumns+` FROM users_subscriptions
+		WHERE user_id = $1 AND id = ANY($2)
+		ORDER BY id`, userID, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving saved notification subscriptions of user %v: %w", userID, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("error committing notification subscriptions of user %v: %w", userID, err)
+	}
+	trimSubscriptionEventNames(saved)
+	return saved, nil
+}
+
+// UpdateUserNotificationSubscription replaces the threshold and channels of a notification subscription of a user,
+// returns false if the subscription does not exist or belongs to another user
+func UpdateUserNotificationSubscription(userID, subscriptionID uint64, threshold float64, channels pq.StringArray) (bool, error) {
+	res, err := FrontendWriterDB.Exec(`
+		UPDATE users_subscriptions SET event_threshold = $3, channels = $4::notification_channels[]
+		WHERE user_id = $1 AND id = $2`, userID, subscriptionID, threshold, subscriptionChannels(channels))
+	if err != nil {
+		return false, fmt.Errorf("error updating notification subscription %v of user %v: %w", subscriptionID, userID, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// DeleteUserNotificationSubscription deletes a notification subscription of a user, returns false if the subscription
+// does not exist or belongs to another user
+func DeleteUserNotificationSubscription(userID, subscriptionID uint64) (bool, error) {
+	res, err := FrontendWriterDB.Exec("DELETE FROM users_subscriptions WHERE user_id = $1 AND id = $2", userID, subscriptionID)
+	if err != nil {
+		return false, fmt.Errorf("error deleting notification subscription %v of user %v: %w", subscriptionID, userID, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
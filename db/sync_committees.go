@@ -0,0 +1,124 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// GetSyncCommitteePeriods returns all sync committee periods stored in the db, the most recent period first
+func GetSyncCommitteePeriods() ([]*types.SyncCommitteePeriodInfo, error) {
+	periods := []*types.SyncCommitteePeriodInfo{}
+	err := ReaderDb.Select(&periods, `
+		SELECT
+			period,
+			GREATEST(period*$1, $2) AS start_epoch,
+			((period+1)*$1)-1 AS end_epoch,
+			COUNT(DISTINCT validatorindex) AS member_count
+		FROM sync_committees
+		GROUP BY period
+		ORDER BY period DESC`, utils.Config.Chain.ClConfig.EpochsPerSyncCommitteePeriod, utils.Config.Chain.ClConfig.AltairForkEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting sync committee periods: %w", err)
+	}
+	return periods, nil
+}
+
+// GetSyncCommitteePeriod returns the given sync committee period, nil is returned if the committee of the period is not known
+func GetSyncCommitteePeriod(period uint64) (*types.SyncCommitteePeriodInfo, error) {
+	periods := []*types.SyncCommitteePeriodInfo{}
+	err := ReaderDb.Select(&periods, `
+		SELECT
+			period,
+			GREATEST(period*$2, $3) AS start_epoch,
+			((period+1)*$2)-1 AS end_epoch,
+			COUNT(DISTINCT validatorindex) AS member_count
+		FROM sync_committees
+		WHERE period = $1
+		GROUP BY period`, period, utils.Config.Chain.ClConfig.EpochsPerSyncCommitteePeriod, utils.Config.Chain.ClConfig.AltairForkEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting sync committee period %v: %w", period, err)
+	}
+	if len(periods) == 0 {
+		return nil, nil
+	}
+	return periods[0], nil
+}
+
+// GetSyncCommitteeMembers returns the distinct members of the sync committee of the given period ordered by their
+// first position in the committee. A validator can hold multiple positions in the same committee.
+func GetSyncCommitteeMembers(period uint64) ([]*types.SyncCommitteeMember, error) {
+	members := []*types.SyncCommitteeMember{}
+	err := ReaderDb.Select(&members, `
+		SELECT
+			validatorindex,
+			ARRAY_AGG(committeeindex ORDER BY committeeindex) AS committee_indices
+		FROM sync_committees
+		WHERE period = $1
+		GROUP BY validatorindex
+		ORDER BY MIN(committeeindex)`, period)
+	if err != nil {
+		return nil, fmt.Errorf("error getting members of sync committee period %v: %w", period, err)
+	}
+	return members, nil
+}
+
+// GetValidatorSyncCommitteePeriods returns the sync committee periods the given validator was part of, the most recent period first
+func GetValidatorSyncCommitteePeriods(validatorIndex uint64) ([]*types.SyncCommitteePeriodInfo, error) {
+	periods := []*types.SyncCommitteePeriodInfo{}
+	err := ReaderDb.Select(&periods, `
+		SELECT DISTINCT
+			period,
+			GREATEST(period*$2, $3) AS start_epoch,
+			((period+1)*$2)-1 AS end_epoch
+		FROM sync_committees
+		WHERE validatorindex = $1
+		ORDER BY period DESC`, validatorIndex, utils.Config.Chain.ClConfig.EpochsPerSyncCommitteePeriod, utils.Config.Chain.ClConfig.AltairForkEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting sync committee periods of validator %v: %w", validatorIndex, err)
+	}
+	return periods, nil
+}
+
+// GetSyncCommitteePerformance returns the sync duty participation and the net sync committee rewards (rewards minus
+// penalties, in gwei) of the given validators between startEpoch and endEpoch
+func GetSyncCommitteePerformance(validators []uint64, startEpoch, endEpoch uint64) (map[uint64]*types.SyncCommitteePerformance, error) {
+	performance := make(map[uint64]*types.SyncCommitteePerformance, len(validators))
+	for _, validator := range validators {
+		performance[validator] = &types.SyncCommitteePerformance{}
+	}
+	if len(validators) == 0 || startEpoch > endEpoch {
+		return performance, nil
+	}
+
+	stats, err := BigtableClient.GetValidatorSyncDutiesStatistics(validators, startEpoch, endEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting sync duties statistics of validators for epochs %v - %v: %w", startEpoch, endEpoch, err)
+	}
+	for validator, stat := range stats {
+		p, ok := performance[validator]
+		if !ok {
+			continue
+		}
+		p.ParticipatedSync = stat.ParticipatedSync
+		p.MissedSync = stat.MissedSync
+		p.OrphanedSync = stat.OrphanedSync
+	}
+
+	income, err := BigtableClient.GetValidatorIncomeDetailsHistory(validators, startEpoch, endEpoch)
+	if err != nil {
+		return nil, fmt.Errorf("error getting income details of validators for epochs %v - %v: %w", startEpoch, endEpoch, err)
+	}
+	for validator, epochs := range income {
+		p, ok := performance[validator]
+		if !ok {
+			continue
+		}
+		for _, details := range epochs {
+			p.Rewards += int64(details.SyncCommitteeReward) - int64(details.SyncCommitteePenalty)
+		}
+	}
+
+	return performance, nil
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/lib/pq"
+)
+
+// GetValidatorsTaxIncome returns the summed daily consensus and execution income and withdrawals of the given validators
+// between the two days, joined with the price of the day in the given currency. The currency must be a column of the price
+// table (see isValidCurrency), days without a stored price are returned with a price of 0.
+func GetValidatorsTaxIncome(validatorIndices []uint64, lowerBoundDay, upperBoundDay uint64, currency string) ([]*types.ApiTaxReportDay, error) {
+	days := []*types.ApiTaxReportDay{}
+	if len(validatorIndices) == 0 {
+		return days, nil
+	}
+
+	err := ReaderDb.Select(&days, fmt.Sprintf(`
+		WITH income AS (
+			SELECT
+				day,
+				SUM(COALESCE(cl_rewards_gwei, 0)) AS cl_rewards_gwei,
+				SUM(COALESCE(el_rewards_wei, 0))::FLOAT AS el_rewards_wei,
+				SUM(COALESCE(withdrawals_amount, 0)) AS withdrawals_amount
+			FROM validator_stats
+			WHERE validatorindex = ANY($1) AND day BETWEEN $2 AND $3
+			GROUP BY day
+		)
+		SELECT
+			i.day,
+			i.cl_rewards_gwei,
+			i.el_rewards_wei,
+			i.withdrawals_amount,
+			COALESCE(p.%[1]s, 0) AS price
+		FROM income i
+		LEFT JOIN price p ON p.ts::DATE = (TO_TIMESTAMP($4 + i.day * 86400) AT TIME ZONE 'UTC')::DATE
+		ORDER BY i.day`, pq.QuoteIdentifier(currency)), pq.Array(validatorIndices), lowerBoundDay, upperBoundDay, utils.Config.Chain.GenesisTimestamp)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validator tax income: %w", err)
+	}
+	return days, nil
+}
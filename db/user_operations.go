@@ -0,0 +1,375 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	gcp_bigtable "cloud.google.com/go/bigtable"
+	"github.com/coocood/freecache"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/sirupsen/logrus"
+)
+
+// ERC4337EntryPoints are the canonical ERC-4337 EntryPoint deployments by version, they share the same address on all chains
+var ERC4337EntryPoints = map[common.Address]string{
+	common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"): "v0.6",
+	common.HexToAddress("0x0000000071727De22E5E9d8BAf0edAc6f37da032"): "v0.7",
+	common.HexToAddress("0x4337084D9E255Ff0702461CF8895CE9E3b5Ff108"): "v0.8",
+}
+
+var (
+	// UserOperationEvent(bytes32 indexed userOpHash, address indexed sender, address indexed paymaster, uint256 nonce, bool success, uint256 actualGasCost, uint256 actualGasUsed)
+	userOperationEventTopic = crypto.Keccak256([]byte("UserOperationEvent(bytes32,address,address,uint256,bool,uint256,uint256)"))
+	// handleOps(PackedUserOperation[] ops, address beneficiary) of the v0.6 and v0.7+ EntryPoints
+	handleOpsSelectors = [][]byte{
+		crypto.Keccak256([]byte("handleOps((address,uint256,bytes,bytes,uint256,uint256,uint256,uint256,uint256,bytes,bytes)[],address)"))[:4],
+		crypto.Keccak256([]byte("handleOps((address,uint256,bytes,bytes,bytes32,uint256,bytes32,bytes,bytes)[],address)"))[:4],
+	}
+)
+
+// TransformUserOperations accepts an eth1 block and creates bigtable mutations for the ERC-4337 user operations
+// executed by the EntryPoint contracts within its transactions. User operations are parsed from the UserOperationEvent
+// logs of the EntryPoint, the bundler is the sender of the transaction and the beneficiary of the fees is decoded from
+// the handleOps call if the bundler called the EntryPoint directly.
+// ==================================================
+//
+// It writes user operations to the table data:
+// Row:    <chainID>:UOP:<txHash>:<reversePaddedLogIndex>
+// Family: f
+// Column: data
+// Cell:   Json<Eth1UserOperationIndexed>
+//
+// It indexes user operations by:
+// Row:    <chainID>:I:UOP:<SENDER|PAYMASTER|BUNDLER_ADDRESS>:TIME:<reversePaddedBigtableTimestamp>:<reversePaddedTxIndex>:<reversePaddedLogIndex>
+// Family: f
+// Column: <chainID>:UOP:<txHash>:<reversePaddedLogIndex>
+// Cell:   nil
+//
+// Row:    <chainID>:I:UOP:HASH:<userOpHash>
+// Family: f
+// Column: <chainID>:UOP:<txHash>:<reversePaddedLogIndex>
+// Cell:   nil
+func (bigtable *Bigtable) TransformUserOperations(blk *types.Eth1Block, cache *freecache.Cache) (bulkData *types.BulkMutations, bulkMetadataUpdates *types.BulkMutations, err error) {
+	startTime := time.Now()
+	defer func() {
+		metrics.TaskDuration.WithLabelValues("bt_transform_user_operations").Observe(time.Since(startTime).Seconds())
+	}()
+
+	bulkData = &types.BulkMutations{}
+	bulkMetadataUpdates = &types.BulkMutations{}
+
+	for i, tx := range blk.GetTransactions() {
+		if i >= TX_PER_BLOCK_LIMIT {
+			return nil, nil, fmt.Errorf("unexpected number of transactions in block expected at most %d but got: %v, tx: %x", TX_PER_BLOCK_LIMIT-1, i, tx.GetHash())
+		}
+		iReversed := reversePaddedIndex(i, TX_PER_BLOCK_LIMIT)
+
+		for j, log := range tx.GetLogs() {
+			if j >= ITX_PER_TX_LIMIT {
+				return nil, nil, fmt.Errorf("unexpected number of logs in block expected at most %d but got: %v tx: %x", ITX_PER_TX_LIMIT-1, j, tx.GetHash())
+			}
+			topics := log.GetTopics()
+			if len(topics) != 4 || !bytes.Equal(topics[0], userOperationEventTopic) || len(log.GetData()) < 128 {
+				continue
+			}
+			if _, ok := ERC4337EntryPoints[common.BytesToAddress(log.GetAddress())]; !ok {
+				continue
+			}
+			jReversed := reversePaddedIndex(j, ITX_PER_TX_LIMIT)
+
+			data := log.GetData()
+			userOp := &types.Eth1UserOperationIndexed{
+				UserOpHash:    topics[1],
+				TxHash:        tx.GetHash(),
+				BlockNumber:   blk.GetNumber(),
+				Time:          blk.GetTime().AsTime(),
+				LogIndex:      uint64(j),
+				EntryPoint:    log.GetAddress(),
+				Sender:        common.BytesToAddress(topics[2]).Bytes(),
+				Paymaster:     common.BytesToAddress(topics[3]).Bytes(),
+				Bundler:       tx.GetFrom(),
+				Nonce:         new(big.Int).SetBytes(data[0:32]).Bytes(),
+				Success:       new(big.Int).SetBytes(data[32:64]).Sign() != 0,
+				ActualGasCost: new(big.Int).SetBytes(data[64:96]).Bytes(),
+				ActualGasUsed: new(big.Int).SetBytes(data[96:128]).Bytes(),
+			}
+			if bytes.Equal(tx.GetTo(), log.GetAddress()) {
+				userOp.Beneficiary = decodeHandleOpsBeneficiary(tx.GetData())
+			}
+
+			b, err := json.Marshal(userOp)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			key := fmt.Sprintf("%s:UOP:%x:%s", bigtable.chainId, tx.GetHash(), jReversed)
+			mut := types.NewMutation()
+			mut.Set(DEFAULT_FAMILY, DATA_COLUMN, gcp_bigtable.Timestamp(0), b)
+
+			bulkData.Keys = append(bulkData.Keys, key)
+			bulkData.Muts = append(bulkData.Muts, mut)
+
+			indexes := []string{
+				fmt.Sprintf("%s:I:UOP:HASH:%x", bigtable.chainId, userOp.UserOpHash),
+			}
+			addresses := [][]byte{userOp.Sender, userOp.Bundler}
+			if !bytes.Equal(userOp.Paymaster, ZERO_ADDRESS) {
+				addresses = append(addresses, userOp.Paymaster)
+			}
+			indexed := make(map[string]bool, len(addresses))
+			for _, address := range addresses {
+				if indexed[string(address)] {
+					continue
+				}
+				indexed[string(address)] = true
+				indexes = append(indexes, fmt.Sprintf("%s:I:UOP:%x:%s:%s:%s:%s", bigtable.chainId, address, FILTER_TIME, reversePaddedBigtableTimestamp(blk.GetTime()), iReversed, jReversed))
+			}
+
+			for _, idx := range indexes {
+				mut := types.NewMutation()
+				mut.Set(DEFAULT_FAMILY, key, gcp_bigtable.Timestamp(0), nil)
+
+				bulkData.Keys = append(bulkData.Keys, idx)
+				bulkData.Muts = append(bulkData.Muts, mut)
+			}
+		}
+	}
+
+	return bulkData, bulkMetadataUpdates, nil
+}
+
+// decodeHandleOpsBeneficiary returns the beneficiary of a handleOps call or nil if the input is no handleOps call
+func decodeHandleOpsBeneficiary(input []byte) []byte {
+	if len(input) < 4+64 {
+		return nil
+	}
+	for _, selector := range handleOpsSelectors {
+		if bytes.Equal(input[:4], selector) {
+			return common.BytesToAddress(input[4+32 : 4+64]).Bytes()
+		}
+	}
+	return nil
+}
+
+// GetEth1UserOpsForAddress returns the user operations of the index rows starting after prefix, the keys of the index
+// rows are returned as well
+func (bigtable *Bigtable) GetEth1UserOpsForAddress(prefix string, limit int64) ([]*types.Eth1UserOperationIndexed, []string, error) {
+
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"prefix": prefix,
+			"limit":  limit,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
+	defer cancel()
+
+	// add \x00 to the row range such that we skip the previous value
+	rowRange := gcp_bigtable.NewRange(prefix+"\x00", prefixSuccessor(prefix, 5))
+	data := make([]*types.Eth1UserOperationIndexed, 0, limit)
+	keys := make([]string, 0, limit)
+	indexes := make([]string, 0, limit)
+	keysMap := make(map[string]*types.Eth1UserOperationIndexed, limit)
+
+	err := bigtable.tableData.ReadRows(ctx, rowRange, func(row gcp_bigtable.Row) bool {
+		keys = append(keys, strings.TrimPrefix(row[DEFAULT_FAMILY][0].Column, "f:"))
+		indexes = append(indexes, row.Key())
+		return true
+	}, gcp_bigtable.LimitRows(limit))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(keys) == 0 {
+		return data, indexes, nil
+	}
+
+	indexes, keys = bigtable.rearrangeReversePaddedIndexZero(ctx, indexes, keys)
+
+	var parseErr error
+	err = bigtable.tableData.ReadRows(ctx, gcp_bigtable.RowList(keys), func(row gcp_bigtable.Row) bool {
+		b := &types.Eth1UserOperationIndexed{}
+		parseErr = json.Unmarshal(row[DEFAULT_FAMILY][0].Value, b)
+		if parseErr != nil {
+			return false
+		}
+		keysMap[row.Key()] = b
+		return true
+	})
+	if err != nil {
+		logger.WithError(err).WithField("prefix", prefix).WithField("limit", limit).Errorf("error reading rows in bigtable_eth1 / GetEth1UserOpsForAddress")
+		return nil, nil, err
+	}
+	if parseErr != nil {
+		return nil, nil, fmt.Errorf("error parsing Eth1UserOperationIndexed data: %w", parseErr)
+	}
+
+	for _, key := range keys {
+		if d := keysMap[key]; d != nil {
+			data = append(data, d)
+		}
+	}
+
+	return data, indexes, nil
+}
+
+// GetAddressUserOperations returns the user operations an address took part in as sender, paymaster or bundler, most
+// recent first, and the page token of the next page
+func (bigtable *Bigtable) GetAddressUserOperations(address []byte, pageToken string, limit int64) ([]*types.Eth1UserOperationIndexed, string, error) {
+	defaultPageToken := fmt.Sprintf("%s:I:UOP:%x:%s:", bigtable.chainId, address, FILTER_TIME)
+	if pageToken == "" {
+		pageToken = defaultPageToken
+	} else if !strings.HasPrefix(pageToken, defaultPageToken) {
+		return nil, "", fmt.Errorf("invalid pageToken for function GetAddressUserOperations: %s", pageToken)
+	}
+
+	userOps, indexes, err := bigtable.GetEth1UserOpsForAddress(pageToken, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	lastKey := ""
+	if len(indexes) > 0 {
+		lastKey = skipBlockIfLastTxIndex(indexes[len(indexes)-1])
+	}
+	return userOps, lastKey, nil
+}
+
+func (bigtable *Bigtable) GetAddressUserOpsTableData(address []byte, pageToken string) (*types.DataTableResponse, error) {
+
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"address":   address,
+			"pageToken": pageToken,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	userOps, lastKey, err := bigtable.GetAddressUserOperations(address, pageToken, DefaultInfScrollRows)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string)
+	for _, u := range userOps {
+		names[string(u.Sender)] = ""
+		names[string(u.Paymaster)] = ""
+		names[string(u.Bundler)] = ""
+	}
+	names, _, err = BigtableClient.GetAddressesNamesArMetadata(&names, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tableData := make([][]interface{}, len(userOps))
+	for i, u := range userOps {
+		paymaster := utils.FormatAddressWithLimitsInAddressPageTable(address, u.Paymaster, names[string(u.Paymaster)], true, digitLimitInAddressPagesTable, nameLimitInAddressPagesTable, true)
+		if bytes.Equal(u.Paymaster, ZERO_ADDRESS) {
+			paymaster = "-"
+		}
+
+		tableData[i] = []interface{}{
+			utils.FormatHash(u.UserOpHash),
+			utils.FormatTransactionHash(u.TxHash, u.Success),
+			utils.FormatBlockNumber(u.BlockNumber),
+			utils.FormatTimestamp(u.Time.Unix()),
+			utils.FormatAddressWithLimitsInAddressPageTable(address, u.Sender, names[string(u.Sender)], true, digitLimitInAddressPagesTable, nameLimitInAddressPagesTable, true),
+			paymaster,
+			utils.FormatAddressWithLimitsInAddressPageTable(address, u.Bundler, names[string(u.Bundler)], false, digitLimitInAddressPagesTable, nameLimitInAddressPagesTable, true),
+			utils.FormatAmount(new(big.Int).SetBytes(u.ActualGasCost), utils.Config.Frontend.ElCurrency, 6),
+		}
+	}
+
+	data := &types.DataTableResponse{
+		Data:        tableData,
+		PagingToken: lastKey,
+	}
+
+	return data, nil
+}
+
+// GetUserOperationsForTx returns the user operations executed within a transaction in log order
+func (bigtable *Bigtable) GetUserOperationsForTx(txHash []byte) ([]*types.Eth1UserOperationIndexed, error) {
+
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"txHash": txHash,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
+	defer cancel()
+
+	data := make([]*types.Eth1UserOperationIndexed, 0)
+	var parseErr error
+	err := bigtable.tableData.ReadRows(ctx, gcp_bigtable.PrefixRange(fmt.Sprintf("%s:UOP:%x:", bigtable.chainId, txHash)), func(row gcp_bigtable.Row) bool {
+		b := &types.Eth1UserOperationIndexed{}
+		parseErr = json.Unmarshal(row[DEFAULT_FAMILY][0].Value, b)
+		if parseErr != nil {
+			return false
+		}
+		data = append(data, b)
+		return true
+	}, gcp_bigtable.RowFilter(gcp_bigtable.ColumnFilter(DATA_COLUMN)))
+	if err != nil {
+		return nil, err
+	}
+	if parseErr != nil {
+		return nil, fmt.Errorf("error parsing Eth1UserOperationIndexed data: %w", parseErr)
+	}
+
+	// rows are keyed by the reverse padded log index
+	for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+		data[i], data[j] = data[j], data[i]
+	}
+	return data, nil
+}
+
+// GetUserOperation returns the user operation with the hash or nil if it has not been indexed
+func (bigtable *Bigtable) GetUserOperation(userOpHash []byte) (*types.Eth1UserOperationIndexed, error) {
+
+	tmr := time.AfterFunc(REPORT_TIMEOUT, func() {
+		logger.WithFields(logrus.Fields{
+			"userOpHash": userOpHash,
+		}).Warnf("%s call took longer than %v", utils.GetCurrentFuncName(), REPORT_TIMEOUT)
+	})
+	defer tmr.Stop()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Second*30))
+	defer cancel()
+
+	row, err := bigtable.tableData.ReadRow(ctx, fmt.Sprintf("%s:I:UOP:HASH:%x", bigtable.chainId, userOpHash))
+	if err != nil {
+		return nil, err
+	}
+	if len(row[DEFAULT_FAMILY]) == 0 {
+		return nil, nil
+	}
+
+	row, err = bigtable.tableData.ReadRow(ctx, strings.TrimPrefix(row[DEFAULT_FAMILY][0].Column, "f:"), gcp_bigtable.RowFilter(gcp_bigtable.ColumnFilter(DATA_COLUMN)))
+	if err != nil {
+		return nil, err
+	}
+	if len(row[DEFAULT_FAMILY]) == 0 {
+		return nil, nil
+	}
+
+	userOp := &types.Eth1UserOperationIndexed{}
+	err = json.Unmarshal(row[DEFAULT_FAMILY][0].Value, userOp)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Eth1UserOperationIndexed data: %w", err)
+	}
+	return userOp, nil
+}
@@ -0,0 +1,192 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// validatorEntitySoloStaker is the entity of validators whose deposit address only funded a few validators
+const validatorEntitySoloStaker = "Solo Staker"
+
+// validatorEntityUnknown is used in entity aggregations for validators that are not attributed to an entity
+const validatorEntityUnknown = "Unknown"
+
+// UpdateValidatorEntities attributes validators to entities by applying the validator_entity_rules (deposit addresses,
+// withdrawal credentials, fee recipients and validator tags) and the known deposit addresses of stake_pools_stats.
+// The rule with the highest priority wins; validators of deposit addresses that funded at most soloStakerMaxValidators
+// validators and match no rule are attributed to solo stakers. Entries of validator_pool not written by the attribution
+// (e.g. by the rocketpool exporter) are never touched. Changes are stored as a new version, nil is returned if nothing changed.
+func UpdateValidatorEntities(soloStakerMaxValidators uint64) (*types.ValidatorEntitiesVersion, error) {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("error starting db tx in UpdateValidatorEntities: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		CREATE TEMP TABLE validator_entities_new ON COMMIT DROP AS
+		SELECT DISTINCT ON (publickey) publickey, entity, rule_id, source
+		FROM (
+			SELECT d.publickey, r.entity, r.id AS rule_id, r.rule_type AS source, r.priority
+			FROM validator_entity_rules r
+			INNER JOIN eth1_deposits d ON d.from_address = DECODE(r.value, 'hex') AND d.valid_signature
+			WHERE r.rule_type = 'deposit_address'
+			UNION ALL
+			SELECT v.pubkey, r.entity, r.id, r.rule_type, r.priority
+			FROM validator_entity_rules r
+			INNER JOIN validators v ON v.withdrawalcredentials = DECODE(r.value, 'hex')
+			WHERE r.rule_type = 'withdrawal_credentials'
+			UNION ALL
+			SELECT DISTINCT v.pubkey, r.entity, r.id, r.rule_type, r.priority
+			FROM validator_entity_rules r
+			INNER JOIN blocks b ON b.exec_fee_recipient = DECODE(r.value, 'hex') AND b.status = '1'
+			INNER JOIN validators v ON v.validatorindex = b.proposer
+			WHERE r.rule_type = 'fee_recipient'
+			UNION ALL
+			SELECT t.publickey, r.entity, r.id, r.rule_type, r.priority
+			FROM validator_entity_rules r
+			INNER JOIN validator_tags t ON t.tag = r.value
+			WHERE r.rule_type = 'tag'
+			UNION ALL
+			SELECT d.publickey, s.name, NULL, 'stake_pools_stats', -1
+			FROM stake_pools_stats s
+			INNER JOIN eth1_deposits d ON ENCODE(d.from_address, 'hex') = s.address AND d.valid_signature
+			WHERE s.name NOT LIKE '%Rocketpool -%'
+		) candidates
+		ORDER BY publickey, priority DESC, rule_id NULLS LAST`)
+	if err != nil {
+		return nil, fmt.Errorf("error applying validator entity rules: %w", err)
+	}
+
+	_, err = tx.Exec(`ALTER TABLE validator_entities_new ADD PRIMARY KEY (publickey)`)
+	if err != nil {
+		return nil, fmt.Errorf("error adding primary key to validator_entities_new: %w", err)
+	}
+
+	if soloStakerMaxValidators > 0 {
+		_, err = tx.Exec(`
+			INSERT INTO validator_entities_new (publickey, entity, rule_id, source)
+			SELECT d.publickey, $1, NULL, 'solo'
+			FROM (SELECT DISTINCT publickey, from_address FROM eth1_deposits WHERE valid_signature) d
+			INNER JOIN (
+				SELECT from_address FROM eth1_deposits WHERE valid_signature GROUP BY from_address HAVING COUNT(DISTINCT publickey) <= $2
+			) a ON a.from_address = d.from_address
+			WHERE NOT EXISTS (SELECT 1 FROM validator_entities_new n WHERE n.publickey = d.publickey)
+			ON CONFLICT DO NOTHING`, validatorEntitySoloStaker, soloStakerMaxValidators)
+		if err != nil {
+			return nil, fmt.Errorf("error attributing solo stakers: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM validator_entities_new n
+		USING validator_pool p
+		WHERE p.publickey = n.publickey AND p.version IS NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("error excluding externally attributed validators: %w", err)
+	}
+
+	version := &types.ValidatorEntitiesVersion{Ts: time.Now()}
+	err = tx.Get(&version.Version, `
+		INSERT INTO validator_entities_versions (ts, validators, changes)
+		VALUES ($1, 0, 0)
+		RETURNING version`, version.Ts)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting validator entities version: %w", err)
+	}
+
+	res, err := tx.Exec(`
+		INSERT INTO validator_entities_changes (version, publickey, old_entity, new_entity)
+		SELECT $1, COALESCE(n.publickey, p.publickey), p.pool, n.entity
+		FROM validator_entities_new n
+		FULL OUTER JOIN (SELECT publickey, pool FROM validator_pool WHERE version IS NOT NULL) p ON p.publickey = n.publickey
+		WHERE p.pool IS DISTINCT FROM n.entity`, version.Version)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting validator entities changes: %w", err)
+	}
+	changes, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving count of validator entities changes: %w", err)
+	}
+	if changes == 0 {
+		return nil, nil
+	}
+	version.Changes = uint64(changes)
+
+	_, err = tx.Exec(`
+		DELETE FROM validator_pool p
+		USING validator_entities_changes c
+		WHERE c.version = $1 AND c.new_entity IS NULL AND p.publickey = c.publickey AND p.version IS NOT NULL`, version.Version)
+	if err != nil {
+		return nil, fmt.Errorf("error deleting stale validator entities: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO validator_pool (publickey, pool, rule_id, source, version)
+		SELECT n.publickey, n.entity, n.rule_id, n.source, $1
+		FROM validator_entities_new n
+		INNER JOIN validator_entities_changes c ON c.version = $1 AND c.publickey = n.publickey
+		ON CONFLICT (publickey) DO UPDATE SET
+			pool = excluded.pool,
+			rule_id = excluded.rule_id,
+			source = excluded.source,
+			version = excluded.version
+		WHERE validator_pool.version IS NOT NULL`, version.Version)
+	if err != nil {
+		return nil, fmt.Errorf("error saving validator entities: %w", err)
+	}
+
+	err = tx.Get(&version.Validators, `SELECT COUNT(*) FROM validator_entities_new`)
+	if err != nil {
+		return nil, fmt.Errorf("error counting attributed validators: %w", err)
+	}
+
+	_, err = tx.Exec(`UPDATE validator_entities_versions SET validators = $2, changes = $3 WHERE version = $1`, version.Version, version.Validators, version.Changes)
+	if err != nil {
+		return nil, fmt.Errorf("error updating validator entities version: %w", err)
+	}
+
+	return version, tx.Commit()
+}
+
+// GetLatestValidatorEntitiesVersion returns the latest version of the validator attribution, nil if there is none yet
+func GetLatestValidatorEntitiesVersion() (*types.ValidatorEntitiesVersion, error) {
+	version := &types.ValidatorEntitiesVersion{}
+	err := ReaderDb.Get(version, `SELECT version, ts, validators, changes FROM validator_entities_versions ORDER BY version DESC LIMIT 1`)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving latest validator entities version: %w", err)
+	}
+	return version, nil
+}
+
+// GetValidatorEntity returns the entity a validator is attributed to, nil if the validator does not exist
+func GetValidatorEntity(index uint64) (*types.ApiValidatorEntityResponse, error) {
+	entity := &types.ApiValidatorEntityResponse{}
+	err := ReaderDb.Get(entity, `
+		SELECT
+			v.validatorindex,
+			'0x' || ENCODE(v.pubkey, 'hex') AS pubkey,
+			COALESCE(p.pool, '') AS entity,
+			COALESCE(r.category, '') AS category,
+			COALESCE(p.source, '') AS source,
+			p.version,
+			ev.ts AS updated_ts
+		FROM validators v
+		LEFT JOIN validator_pool p ON p.publickey = v.pubkey
+		LEFT JOIN validator_entity_rules r ON r.id = p.rule_id
+		LEFT JOIN validator_entities_versions ev ON ev.version = p.version
+		WHERE v.validatorindex = $1`, index)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving entity of validator %v: %w", index, err)
+	}
+	return entity, nil
+}
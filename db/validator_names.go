@@ -0,0 +1,138 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/lib/pq"
+)
+
+// GetValidatorPubkeysByDepositAddress returns the pubkeys of all validators with a valid deposit sent from address
+func GetValidatorPubkeysByDepositAddress(address []byte) ([][]byte, error) {
+	var pubkeys [][]byte
+	err := ReaderDb.Select(&pubkeys, `SELECT DISTINCT publickey FROM eth1_deposits WHERE from_address = $1 AND valid_signature`, address)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validators deposited by %#x: %w", address, err)
+	}
+	return pubkeys, nil
+}
+
+// GetValidatorPubkeysByWithdrawalCredentials returns the pubkeys of all validators with the given withdrawal credentials
+func GetValidatorPubkeysByWithdrawalCredentials(credentials []byte) ([][]byte, error) {
+	var pubkeys [][]byte
+	err := ReaderDb.Select(&pubkeys, `SELECT pubkey FROM validators WHERE withdrawalcredentials = $1`, credentials)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validators with withdrawal credentials %#x: %w", credentials, err)
+	}
+	return pubkeys, nil
+}
+
+// SaveValidatorNameClaim queues the signed name claim of the validators for moderation, the name is only set once the
+// claim has been approved. It returns the amount of claimed validators.
+func SaveValidatorNameClaim(pubkeys [][]byte, claim *types.ValidatorNameClaim) (int64, error) {
+	res, err := WriterDb.Exec(`
+		INSERT INTO validator_name_claims (publickey, name, signer_address, proof_type, message, signature, status)
+		SELECT UNNEST($1::bytea[]), $2, $3, $4, $5, $6, $7`,
+		pq.ByteaArray(pubkeys), claim.Name, claim.SignerAddress, claim.ProofType, claim.Message, claim.Signature, types.ValidatorNameClaimPending)
+	if err != nil {
+		return 0, fmt.Errorf("error inserting validator name claims in SaveValidatorNameClaim: %w", err)
+	}
+	rowsAffected, _ := res.RowsAffected()
+	return rowsAffected, nil
+}
+
+// GetValidatorNameClaims returns the latest name claims with the given status, oldest claims first
+func GetValidatorNameClaims(status string, limit uint64) ([]*types.ValidatorNameClaim, error) {
+	claims := []*types.ValidatorNameClaim{}
+	err := ReaderDb.Select(&claims, `
+		SELECT
+			c.id, v.validatorindex, c.publickey, c.name, c.signer_address, c.proof_type, c.message, c.signature,
+			c.status, c.created_ts, c.reviewed_ts, c.reviewed_by
+		FROM validator_name_claims c
+		LEFT JOIN validators v ON v.pubkey = c.publickey
+		WHERE c.status = $1
+		ORDER BY c.created_ts, c.id
+		LIMIT $2`, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving %v validator name claims: %w", status, err)
+	}
+	return claims, nil
+}
+
+// ReviewValidatorNameClaim approves or rejects a pending name claim, approving sets the claimed name of the validator
+func ReviewValidatorNameClaim(id uint64, approve bool, reviewer uint64) error {
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db tx in ReviewValidatorNameClaim: %w", err)
+	}
+	defer tx.Rollback()
+
+	status := types.ValidatorNameClaimApproved
+	if !approve {
+		status = types.ValidatorNameClaimRejected
+	}
+
+	claim := &types.ValidatorNameClaim{}
+	err = tx.Get(claim, `
+		UPDATE validator_name_claims SET status = $2, reviewed_ts = NOW() AT TIME ZONE 'utc', reviewed_by = $3
+		WHERE id = $1 AND status = $4
+		RETURNING publickey, name`, id, status, reviewer, types.ValidatorNameClaimPending)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("no pending validator name claim with id %v", id)
+	}
+	if err != nil {
+		return fmt.Errorf("error updating validator name claim %v: %w", id, err)
+	}
+
+	if approve {
+		_, err = tx.Exec(`
+			INSERT INTO validator_names (publickey, name) VALUES ($1, $2)
+			ON CONFLICT (publickey) DO UPDATE SET name = excluded.name`, claim.PublicKey, claim.Name)
+		if err != nil {
+			return fmt.Errorf("error saving validator name of claim %v: %w", id, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetValidatorNameByIndex returns the name of a validator together with the proof of its latest claim, nil if the validator does not exist
+func GetValidatorNameByIndex(index uint64) (*types.ApiValidatorNameResponse, error) {
+	var row struct {
+		PublicKey     []byte         `db:"pubkey"`
+		Name          sql.NullString `db:"name"`
+		ProofType     sql.NullString `db:"proof_type"`
+		SignerAddress []byte         `db:"signer_address"`
+	}
+	err := ReaderDb.Get(&row, `
+		SELECT v.pubkey, n.name, c.proof_type, c.signer_address
+		FROM validators v
+		LEFT JOIN validator_names n ON n.publickey = v.pubkey
+		LEFT JOIN LATERAL (
+			SELECT proof_type, signer_address
+			FROM validator_name_claims
+			WHERE publickey = v.pubkey AND name = n.name AND status = $2
+			ORDER BY created_ts DESC
+			LIMIT 1
+		) c ON true
+		WHERE v.validatorindex = $1`, index, types.ValidatorNameClaimApproved)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving name of validator %v: %w", index, err)
+	}
+
+	res := &types.ApiValidatorNameResponse{
+		ValidatorIndex: index,
+		PublicKey:      fmt.Sprintf("%#x", row.PublicKey),
+		Name:           row.Name.String,
+		ProofType:      row.ProofType.String,
+	}
+	if len(row.SignerAddress) > 0 {
+		res.SignerAddress = fmt.Sprintf("%#x", row.SignerAddress)
+	}
+	return res, nil
+}
@@ -0,0 +1,130 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// SaveValidatorSetSnapshot copies the current state of the validators table into validator_set_snapshots if no snapshot
+// has been stored for the interval the epoch belongs to. It has to be called after the validators of the epoch have been
+// saved and runs in its own transaction as it copies the whole validator set. The returned bool is true if a snapshot was saved.
+func SaveValidatorSetSnapshot(epoch, interval uint64) (bool, error) {
+	if interval == 0 {
+		return false, fmt.Errorf("invalid validator set snapshot interval of 0")
+	}
+
+	start := time.Now()
+	defer func() {
+		metrics.TaskDuration.WithLabelValues("db_save_validator_set_snapshot").Observe(time.Since(start).Seconds())
+	}()
+
+	tx, err := WriterDb.Beginx()
+	if err != nil {
+		return false, fmt.Errorf("error starting db tx for validator set snapshot: %w", err)
+	}
+	defer tx.Rollback()
+
+	var lastSnapshotEpoch sql.NullInt64
+	err = tx.Get(&lastSnapshotEpoch, "SELECT MAX(epoch) FROM validator_set_snapshots")
+	if err != nil {
+		return false, fmt.Errorf("error getting last validator set snapshot epoch: %w", err)
+	}
+	if lastSnapshotEpoch.Valid && uint64(lastSnapshotEpoch.Int64)/interval >= epoch/interval {
+		return false, nil
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO validator_set_snapshots (
+			epoch,
+			validatorindex,
+			status,
+			balance,
+			effectivebalance,
+			withdrawalcredentials,
+			slashed,
+			activationeligibilityepoch,
+			activationepoch,
+			exitepoch,
+			withdrawableepoch
+		)
+		SELECT
+			$1,
+			validatorindex,
+			status,
+			balance,
+			effectivebalance,
+			withdrawalcredentials,
+			slashed,
+			activationeligibilityepoch,
+			activationepoch,
+			exitepoch,
+			withdrawableepoch
+		FROM validators
+		ON CONFLICT (epoch, validatorindex) DO NOTHING`, epoch)
+	if err != nil {
+		return false, fmt.Errorf("error saving validator set snapshot for epoch %v: %w", epoch, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, fmt.Errorf("error committing validator set snapshot for epoch %v: %w", epoch, err)
+	}
+
+	logger.Infof("saved validator set snapshot for epoch %v, took %v", epoch, time.Since(start))
+	return true, nil
+}
+
+// DeleteValidatorSetSnapshotsBefore deletes the validator set snapshots taken before the given epoch
+func DeleteValidatorSetSnapshotsBefore(epoch uint64) (int64, error) {
+	res, err := WriterDb.Exec("DELETE FROM validator_set_snapshots WHERE epoch < $1", epoch)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting validator set snapshots before epoch %v: %w", epoch, err)
+	}
+	return res.RowsAffected()
+}
+
+// GetValidatorSetSnapshotEpoch returns the epoch of the latest validator set snapshot taken at or before atEpoch,
+// ok is false if no such snapshot exists
+func GetValidatorSetSnapshotEpoch(atEpoch uint64) (snapshotEpoch uint64, ok bool, err error) {
+	var epoch sql.NullInt64
+	err = ReaderDb.Get(&epoch, "SELECT MAX(epoch) FROM validator_set_snapshots WHERE epoch <= $1", atEpoch)
+	if err != nil {
+		return 0, false, fmt.Errorf("error getting validator set snapshot epoch at epoch %v: %w", atEpoch, err)
+	}
+	if !epoch.Valid {
+		return 0, false, nil
+	}
+	return uint64(epoch.Int64), true, nil
+}
+
+// GetValidatorSetSnapshot returns the validators of the snapshot of the given epoch ordered by validator index
+func GetValidatorSetSnapshot(snapshotEpoch, limit, offset uint64) ([]*types.ApiValidatorSnapshotResponse, error) {
+	validators := []*types.ApiValidatorSnapshotResponse{}
+	err := ReaderDb.Select(&validators, `
+		SELECT
+			s.validatorindex,
+			'0x' || encode(v.pubkey, 'hex') AS pubkey,
+			s.status,
+			s.balance,
+			s.effectivebalance,
+			'0x' || encode(s.withdrawalcredentials, 'hex') AS withdrawalcredentials,
+			s.slashed,
+			s.activationeligibilityepoch,
+			s.activationepoch,
+			s.exitepoch,
+			s.withdrawableepoch
+		FROM validator_set_snapshots s
+		INNER JOIN validators v ON v.validatorindex = s.validatorindex
+		WHERE s.epoch = $1
+		ORDER BY s.validatorindex
+		LIMIT $2
+		OFFSET $3`, snapshotEpoch, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("error getting validator set snapshot of epoch %v: %w", snapshotEpoch, err)
+	}
+	return validators, nil
+}
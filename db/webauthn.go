@@ -0,0 +1,152 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// AddUserWebAuthnCredential stores a new security key or passkey of a user
+func AddUserWebAuthnCredential(userId uint64, credentialId, publicKey []byte, algorithm int64, signCount uint32, name string) error {
+	_, err := FrontendWriterDB.Exec(`
+		INSERT INTO users_webauthn_credentials (user_id, credential_id, public_key, algorithm, sign_count, name)
+		VALUES ($1, $2, $3, $4, $5, $6)`, userId, credentialId, publicKey, algorithm, signCount, truncateString(name, 100))
+	if err != nil {
+		return fmt.Errorf("error inserting webauthn credential of user %v: %w", userId, err)
+	}
+	return nil
+}
+
+// GetUserWebAuthnCredentials returns the security keys and passkeys of a user, oldest first
+func GetUserWebAuthnCredentials(userId uint64) ([]*types.UserWebAuthnCredential, error) {
+	credentials := []*types.UserWebAuthnCredential{}
+	err := FrontendReaderDB.Select(&credentials, `
+		SELECT id, user_id, credential_id, public_key, algorithm, sign_count, name, created_at, last_used_at
+		FROM users_webauthn_credentials
+		WHERE user_id = $1
+		ORDER BY created_at, id`, userId)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving webauthn credentials of user %v: %w", userId, err)
+	}
+	return credentials, nil
+}
+
+// HasUserWebAuthnCredentials returns true if the user has set up a security key or passkey, i.e. uses two-factor authentication
+func HasUserWebAuthnCredentials(userId uint64) (bool, error) {
+	var exists bool
+	err := FrontendWriterDB.Get(&exists, `SELECT EXISTS (SELECT 1 FROM users_webauthn_credentials WHERE user_id = $1)`, userId)
+	if err != nil {
+		return false, fmt.Errorf("error checking webauthn credentials of user %v: %w", userId, err)
+	}
+	return exists, nil
+}
+
+// GetWebAuthnCredential returns the credential with the given credential id, returns nil if it does not exist
+func GetWebAuthnCredential(credentialId []byte) (*types.UserWebAuthnCredential, error) {
+	credential := &types.UserWebAuthnCredential{}
+	err := FrontendWriterDB.Get(credential, `
+		SELECT id, user_id, credential_id, public_key, algorithm, sign_count, name, created_at, last_used_at
+		FROM users_webauthn_credentials
+		WHERE credential_id = $1`, credentialId)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving webauthn credential: %w", err)
+	}
+	return credential, nil
+}
+
+// UpdateWebAuthnCredentialUsage stores the signature counter of the last login with the credential
+func UpdateWebAuthnCredentialUsage(id uint64, signCount uint32) error {
+	_, err := FrontendWriterDB.Exec(`UPDATE users_webauthn_credentials SET sign_count = $2, last_used_at = NOW() WHERE id = $1`, id, signCount)
+	if err != nil {
+		return fmt.Errorf("error updating webauthn credential %v: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteUserWebAuthnCredential removes a credential of a user, the recovery codes are removed along with the last
+// credential as two-factor authentication is disabled then. Returns false if the credential does not exist.
+func DeleteUserWebAuthnCredential(userId, id uint64) (bool, error) {
+	tx, err := FrontendWriterDB.Beginx()
+	if err != nil {
+		return false, fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM users_webauthn_credentials WHERE user_id = $1 AND id = $2`, userId, id)
+	if err != nil {
+		return false, fmt.Errorf("error deleting webauthn credential %v of user %v: %w", id, userId, err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM users_recovery_codes
+		WHERE user_id = $1 AND NOT EXISTS (SELECT 1 FROM users_webauthn_credentials WHERE user_id = $1)`, userId)
+	if err != nil {
+		return false, fmt.Errorf("error deleting recovery codes of user %v: %w", userId, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, fmt.Errorf("error committing db transaction: %w", err)
+	}
+	return true, nil
+}
+
+// ReplaceUserRecoveryCodes replaces all recovery codes of a user, the codes are stored as sha256 hashes
+func ReplaceUserRecoveryCodes(userId uint64, codeHashes []string) error {
+	tx, err := FrontendWriterDB.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM users_recovery_codes WHERE user_id = $1`, userId)
+	if err != nil {
+		return fmt.Errorf("error deleting recovery codes of user %v: %w", userId, err)
+	}
+	for _, hash := range codeHashes {
+		_, err = tx.Exec(`INSERT INTO users_recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userId, hash)
+		if err != nil {
+			return fmt.Errorf("error inserting recovery code of user %v: %w", userId, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("error committing db transaction: %w", err)
+	}
+	return nil
+}
+
+// UseUserRecoveryCode marks an unused recovery code of a user as used, returns false if there is no such code
+func UseUserRecoveryCode(userId uint64, codeHash string) (bool, error) {
+	ids := []uint64{}
+	err := FrontendWriterDB.Select(&ids, `
+		UPDATE users_recovery_codes SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+		RETURNING id`, userId, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("error using recovery code of user %v: %w", userId, err)
+	}
+	return len(ids) > 0, nil
+}
+
+// CountUserRecoveryCodes returns the number of unused recovery codes of a user
+func CountUserRecoveryCodes(userId uint64) (int, error) {
+	var count int
+	err := FrontendReaderDB.Get(&count, `SELECT COUNT(*) FROM users_recovery_codes WHERE user_id = $1 AND used_at IS NULL`, userId)
+	if err != nil {
+		return 0, fmt.Errorf("error counting recovery codes of user %v: %w", userId, err)
+	}
+	return count, nil
+}
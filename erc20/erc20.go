@@ -11,7 +11,6 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/shopspring/decimal"
-	"github.com/sirupsen/logrus"
 )
 
 var ERC20Abi, _ = abi.JSON(strings.NewReader(Erc20ABI))
@@ -20,7 +19,7 @@ var TransferTopic []byte = []byte{0xdd, 0xf2, 0x52, 0xad, 0x1b, 0xe2, 0xc8, 0x9b
 
 var tokenMap = make(map[string]*ERC20TokenDetail)
 
-var logger = logrus.StandardLogger().WithField("module", "erc20")
+var logger = utils.NewLogger("erc20")
 
 func InitTokenList(path string) {
 	body, err := os.ReadFile(path)
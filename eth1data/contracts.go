@@ -0,0 +1,243 @@
+package eth1data
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/rpc"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	geth_types "github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/sync/errgroup"
+)
+
+// maximum number of view functions that are called when rendering the read contract tab of an address
+const contractReadsLimit = 50
+
+// DecodeCallData decodes the method and input parameters of a contract call using the abi of the called contract
+func DecodeCallData(contractAbi *abi.ABI, data []byte) (*types.Eth1DecodedCallData, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("call data is too short to contain a method id")
+	}
+
+	method, err := contractAbi.MethodById(data[:4])
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("error unpacking inputs of method %v: %w", method.Name, err)
+	}
+
+	decoded := &types.Eth1DecodedCallData{
+		Signature: method.Sig,
+		Params:    make([]*types.Eth1DecodedParam, 0, len(method.Inputs)),
+	}
+	for i, input := range method.Inputs {
+		decoded.Params = append(decoded.Params, decodeAbiParam(input.Name, input.Type, values[i]))
+	}
+
+	return decoded, nil
+}
+
+// DecodeCallDataWithSignature decodes the input parameters of a contract call using a text signature of the signature database, e.g. transfer(address,uint256).
+// As the signature database can contain colliding signatures an error is returned if the call data does not match the signature.
+func DecodeCallDataWithSignature(signature string, data []byte) (*types.Eth1DecodedCallData, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("call data is too short to contain a method id")
+	}
+
+	args, err := parseSignatureArguments(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := args.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("error unpacking inputs of signature %v: %w", signature, err)
+	}
+
+	decoded := &types.Eth1DecodedCallData{
+		Signature: signature,
+		Params:    make([]*types.Eth1DecodedParam, 0, len(args)),
+	}
+	for i, arg := range args {
+		decoded.Params = append(decoded.Params, decodeAbiParam(arg.Name, arg.Type, values[i]))
+	}
+
+	return decoded, nil
+}
+
+// DecodeLogWithSignature decodes the parameters of an event log using a text signature of the signature database, e.g. Transfer(address,address,uint256).
+// As signatures do not contain which parameters are indexed, the leading parameters are assumed to be the indexed ones.
+func DecodeLogWithSignature(signature string, log *geth_types.Log) (map[string]types.Eth1DecodedEventData, error) {
+	if len(log.Topics) == 0 {
+		return nil, fmt.Errorf("log does not contain an event id")
+	}
+
+	args, err := parseSignatureArguments(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	indexedCount := len(log.Topics) - 1
+	if indexedCount > len(args) {
+		return nil, fmt.Errorf("log contains more topics than signature %v has parameters", signature)
+	}
+
+	decoded := make(map[string]types.Eth1DecodedEventData, len(args))
+	for i, arg := range args[:indexedCount] {
+		topic := log.Topics[i+1]
+		param := types.Eth1DecodedEventData{
+			Type:  arg.Type.String(),
+			Raw:   topic.Hex(),
+			Value: topic.Hex(),
+		}
+		// indexed dynamic types are stored as hash of their value and can not be decoded
+		if !isDynamicAbiType(arg.Type) {
+			values, err := abi.Arguments{arg}.Unpack(topic.Bytes())
+			if err != nil {
+				return nil, fmt.Errorf("error unpacking topic %v of signature %v: %w", i+1, signature, err)
+			}
+			p := decodeAbiParam(arg.Name, arg.Type, values[0])
+			param.Value = p.Value
+			param.Address = p.Address
+		}
+		decoded[arg.Name] = param
+	}
+
+	values, err := args[indexedCount:].Unpack(log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("error unpacking data of signature %v: %w", signature, err)
+	}
+	for i, arg := range args[indexedCount:] {
+		p := decodeAbiParam(arg.Name, arg.Type, values[i])
+		decoded[arg.Name] = types.Eth1DecodedEventData{
+			Type:    p.Type,
+			Raw:     fmt.Sprintf("0x%x", values[i]),
+			Value:   p.Value,
+			Address: p.Address,
+		}
+	}
+
+	return decoded, nil
+}
+
+// parseSignatureArguments parses the parameter types of a text signature, unnamed parameters are named by their position (arg0, arg1, ...)
+func parseSignatureArguments(signature string) (abi.Arguments, error) {
+	start := strings.Index(signature, "(")
+	if start < 1 || !strings.HasSuffix(signature, ")") {
+		return nil, fmt.Errorf("invalid signature %v", signature)
+	}
+	inner := signature[start+1 : len(signature)-1]
+	if inner == "" {
+		return abi.Arguments{}, nil
+	}
+
+	args := abi.Arguments{}
+	for i, typeName := range strings.Split(inner, ",") {
+		if strings.ContainsAny(typeName, "() ") {
+			return nil, fmt.Errorf("unsupported parameter type %v in signature %v", typeName, signature)
+		}
+		t, err := abi.NewType(typeName, "", nil)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing parameter type %v in signature %v: %w", typeName, signature, err)
+		}
+		args = append(args, abi.Argument{Name: fmt.Sprintf("arg%d", i), Type: t})
+	}
+	return args, nil
+}
+
+func isDynamicAbiType(t abi.Type) bool {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return true
+	}
+	return false
+}
+
+// readableMethods returns the view functions of a contract that do not take any inputs sorted by function name
+func readableMethods(contractAbi *abi.ABI) []abi.Method {
+	methods := make([]abi.Method, 0, len(contractAbi.Methods))
+	for _, method := range contractAbi.Methods {
+		if method.IsConstant() && len(method.Inputs) == 0 {
+			methods = append(methods, method)
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].Name < methods[j].Name
+	})
+	if len(methods) > contractReadsLimit {
+		methods = methods[:contractReadsLimit]
+	}
+	return methods
+}
+
+// HasReadableMethods returns whether the contract has view functions that can be read without any inputs
+func HasReadableMethods(contractAbi *abi.ABI) bool {
+	return len(readableMethods(contractAbi)) > 0
+}
+
+// ReadContract calls all view functions of a contract that do not take any inputs at the given block and returns their
+// decoded outputs sorted by function name
+func ReadContract(ctx context.Context, address common.Address, contractAbi *abi.ABI, blockNumber *big.Int) []*types.ContractReadResult {
+	methods := readableMethods(contractAbi)
+
+	results := make([]*types.ContractReadResult, len(methods))
+	g := new(errgroup.Group)
+	g.SetLimit(10)
+	for i, method := range methods {
+		i, method := i, method
+		g.Go(func() error {
+			result := &types.ContractReadResult{Name: method.Name}
+			results[i] = result
+
+			input, err := contractAbi.Pack(method.Name)
+			if err != nil {
+				result.Error = err.Error()
+				return nil
+			}
+			output, err := rpc.CurrentErigonClient.GetNativeClient().CallContract(ctx, ethereum.CallMsg{To: &address, Data: input}, blockNumber)
+			if err != nil {
+				result.Error = err.Error()
+				return nil
+			}
+			values, err := method.Outputs.Unpack(output)
+			if err != nil {
+				result.Error = err.Error()
+				return nil
+			}
+			for j, out := range method.Outputs {
+				result.Outputs = append(result.Outputs, decodeAbiParam(out.Name, out.Type, values[j]))
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return results
+}
+
+func decodeAbiParam(name string, t abi.Type, val interface{}) *types.Eth1DecodedParam {
+	param := &types.Eth1DecodedParam{
+		Name:  name,
+		Type:  t.String(),
+		Value: fmt.Sprintf("%v", val),
+	}
+	switch t.T {
+	case abi.AddressTy:
+		if address, ok := val.(common.Address); ok {
+			param.Address = address
+		}
+	case abi.BytesTy, abi.FixedBytesTy:
+		param.Value = fmt.Sprintf("0x%x", val)
+	}
+	return param
+}
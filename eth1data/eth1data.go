@@ -22,10 +22,9 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	geth_types "github.com/ethereum/go-ethereum/core/types"
-	"github.com/sirupsen/logrus"
 )
 
-var logger = logrus.New().WithField("module", "eth1data")
+var logger = utils.NewLogger("eth1data")
 var ErrTxIsPending = errors.New("error retrieving data for tx: tx is still pending")
 
 func GetEth1Transaction(hash common.Hash, currency string) (*types.Eth1TxData, error) {
@@ -83,6 +82,27 @@ func GetEth1Transaction(hash common.Hash, currency string) (*types.Eth1TxData, e
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving code data for tx recipient %v: %w", tx.To(), err)
 	}
+	if txPageData.TargetIsContract && !txPageData.IsContractCreation && len(tx.Data()) >= 4 {
+		meta, err := db.BigtableClient.GetContractMetadata(txPageData.To.Bytes())
+		if err == nil && meta != nil && meta.ABI != nil {
+			txPageData.DecodedCallData, err = DecodeCallData(meta.ABI, tx.Data())
+			if err != nil {
+				logger.Warnf("error decoding call data for tx [0x%x]: %v", tx.Hash(), err)
+			}
+		}
+		if txPageData.DecodedCallData == nil {
+			sig, err := db.BigtableClient.GetSignature(fmt.Sprintf("0x%x", tx.Data()[:4]), types.MethodSignature)
+			if err == nil && sig != nil {
+				txPageData.DecodedCallData, err = DecodeCallDataWithSignature(*sig, tx.Data())
+				if err != nil {
+					logger.Warnf("error decoding call data for tx [0x%x] with signature %v: %v", tx.Hash(), *sig, err)
+				}
+			}
+		}
+	}
+	if txPageData.DecodedCallData != nil {
+		txPageData.Method = utils.RemoveRoundBracketsIncludingContent(txPageData.DecodedCallData.Signature)
+	}
 
 	header, err := getBlockHeaderByHash(ctx, receipt.BlockHash)
 	if err != nil {
@@ -154,6 +174,14 @@ func GetEth1Transaction(hash common.Hash, currency string) (*types.Eth1TxData, e
 	if err != nil {
 		return nil, fmt.Errorf("error loading internal transfers from tx: %w", err)
 	}
+	txPageData.UserOperations, err = db.BigtableClient.GetUserOperationsForTx(tx.Hash().Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error loading user operations from tx: %w", err)
+	}
+	txPageData.L2BridgeTx, err = db.BigtableClient.GetL2BridgeTx(tx.Hash().Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error loading l2 bridge interaction from tx: %w", err)
+	}
 	txPageData.FromName, err = db.BigtableClient.GetAddressName(msg.From.Bytes())
 	if err != nil {
 		return nil, fmt.Errorf("error retrieveing from name for tx: %w", err)
@@ -190,6 +218,12 @@ func GetEth1Transaction(hash common.Hash, currency string) (*types.Eth1TxData, e
 					Topics:  log.Topics,
 					Data:    log.Data,
 				}
+				if name != "" {
+					decodedData, err := DecodeLogWithSignature(name, log)
+					if err == nil {
+						eth1Event.DecodedData = decodedData
+					}
+				}
 
 				txPageData.Events = append(txPageData.Events, eth1Event)
 			} else {
@@ -11,11 +11,9 @@ import (
 	"time"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
-
-	"github.com/sirupsen/logrus"
 )
 
-var logger = logrus.New().WithField("module", "ethClients")
+var logger = utils.NewLogger("ethClients")
 
 type ethernodesAPIStruct struct {
 	Client string `json:"client"`
@@ -266,6 +264,30 @@ func GetEthClientData() EthClientServicesPageData {
 	return ethClients
 }
 
+// GetLatestRelease returns the latest release of the client with the given lowercase name, empty if it is unknown
+func GetLatestRelease(client string) string {
+	ethClientsMux.Lock()
+	defer ethClientsMux.Unlock()
+	var release string
+	switch client {
+	case "lighthouse":
+		release = ethClients.Lighthouse.ClientReleaseVersion
+	case "prysm":
+		release = ethClients.Prysm.ClientReleaseVersion
+	case "teku":
+		release = ethClients.Teku.ClientReleaseVersion
+	case "nimbus":
+		release = ethClients.Nimbus.ClientReleaseVersion
+	case "lodestar":
+		release = ethClients.Lodestar.ClientReleaseVersion
+	}
+	if release == "Github" {
+		// the release could not be retrieved from github yet
+		return ""
+	}
+	return release
+}
+
 // GetUpdatedClients returns a slice of latest updated clients or empty slice if no updates
 func GetUpdatedClients() []clientUpdateInfo {
 	bannerClientsMux.Lock()
@@ -0,0 +1,145 @@
+package exporter
+
+import (
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	eth_types "github.com/ethereum/go-ethereum/core/types"
+	gethRPC "github.com/ethereum/go-ethereum/rpc"
+)
+
+// censorshipPendingTxsMaxAge limits for how long we try to find the inclusion of a watched transaction, transactions
+// which are replaced or dropped from the mempool would otherwise be checked forever
+const censorshipPendingTxsMaxAge = time.Hour * 24
+
+// censorshipExporter watches the mempool for transactions sent from or to addresses of the configured watchlist
+// and tracks when and in which block they get included
+func censorshipExporter() {
+	watchlist := make(map[common.Address]bool, len(utils.Config.CensorshipExporter.Watchlist))
+	for _, address := range utils.Config.CensorshipExporter.Watchlist {
+		if !common.IsHexAddress(address) {
+			logger.Warnf("ignoring invalid address %v of the censorship watchlist", address)
+			continue
+		}
+		watchlist[common.HexToAddress(address)] = true
+	}
+	if len(watchlist) == 0 {
+		logger.Warnf("censorship exporter is enabled but the watchlist is empty, exiting")
+		return
+	}
+
+	var client *gethRPC.Client
+	var lastInclusionCheck time.Time
+	for {
+		var err error
+		if client == nil {
+			client, err = gethRPC.Dial(utils.Config.Eth1GethEndpoint)
+			if err != nil {
+				utils.LogError(err, "can't connect to geth node", 0)
+				client = nil
+				time.Sleep(time.Second * 30)
+				continue
+			}
+		}
+
+		err = exportWatchedMempoolTxs(client, watchlist)
+		if err != nil {
+			utils.LogError(err, "error exporting watched mempool transactions", 0)
+		}
+
+		if time.Since(lastInclusionCheck) >= time.Second*time.Duration(utils.Config.Chain.ClConfig.SecondsPerSlot) {
+			err = updateWatchedTxsInclusion(client)
+			if err != nil {
+				utils.LogError(err, "error updating inclusion of watched transactions", 0)
+			}
+			lastInclusionCheck = time.Now()
+		}
+
+		time.Sleep(time.Second * 2)
+	}
+}
+
+func exportWatchedMempoolTxs(client *gethRPC.Client, watchlist map[common.Address]bool) error {
+	var mempool types.RawMempoolResponse
+	err := client.Call(&mempool, "txpool_content")
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	watchedTxs := []*types.CensorshipWatchedTx{}
+	for _, pool := range []map[string]map[string]*types.RawMempoolTransaction{mempool.Pending, mempool.BaseFee} {
+		for _, txs := range pool {
+			for _, tx := range txs {
+				if tx.From == nil {
+					continue
+				}
+				if !watchlist[*tx.From] && (tx.To == nil || !watchlist[*tx.To]) {
+					continue
+				}
+				watchedTx := &types.CensorshipWatchedTx{
+					TxHash:      tx.Hash.Bytes(),
+					FromAddress: tx.From.Bytes(),
+					FirstSeen:   now,
+				}
+				if tx.To != nil {
+					watchedTx.ToAddress = tx.To.Bytes()
+				}
+				watchedTxs = append(watchedTxs, watchedTx)
+			}
+		}
+	}
+
+	return db.SaveCensorshipWatchedTxs(watchedTxs)
+}
+
+func updateWatchedTxsInclusion(client *gethRPC.Client) error {
+	hashes, err := db.GetPendingCensorshipWatchedTxHashes(time.Now().Add(-censorshipPendingTxsMaxAge))
+	if err != nil {
+		return err
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	receipts := make([]*eth_types.Receipt, len(hashes))
+	elems := make([]gethRPC.BatchElem, 0, len(hashes))
+	for i, hash := range hashes {
+		elems = append(elems, gethRPC.BatchElem{
+			Method: "eth_getTransactionReceipt",
+			Args:   []interface{}{common.BytesToHash(hash)},
+			Result: &receipts[i],
+		})
+	}
+
+	err = client.BatchCall(elems)
+	if err != nil {
+		return err
+	}
+
+	included := 0
+	for i, elem := range elems {
+		if elem.Error != nil {
+			logger.Warnf("error getting receipt of watched tx %#x: %v", hashes[i], elem.Error)
+			continue
+		}
+		if receipts[i] == nil || receipts[i].BlockNumber == nil {
+			continue
+		}
+		// the including block might not be exported yet, in that case we will retry in the next run
+		updated, err := db.UpdateCensorshipWatchedTxInclusion(hashes[i], receipts[i].BlockNumber.Uint64())
+		if err != nil {
+			return err
+		}
+		if updated {
+			included++
+		}
+	}
+	logger.Infof("checked inclusion of %v watched transactions, %v were included", len(hashes), included)
+
+	return nil
+}
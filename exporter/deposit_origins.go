@@ -0,0 +1,69 @@
+package exporter
+
+import (
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+const (
+	depositOriginBatchSize          = 100
+	depositOriginDefaultMaxHops     = 3
+	depositOriginDefaultRetraceTime = time.Hour * 24 * 7
+)
+
+// depositOriginExporter traces the funding source of deposit addresses in batches, it continues right away as long as
+// there are addresses left to trace and waits otherwise
+func depositOriginExporter() {
+	logger.Infoln("started deposit origin exporter")
+
+	maxHops := utils.Config.DepositOriginExporter.MaxHops
+	if maxHops <= 0 {
+		maxHops = depositOriginDefaultMaxHops
+	}
+	retraceInterval := utils.Config.DepositOriginExporter.RetraceInterval
+	if retraceInterval <= 0 {
+		retraceInterval = depositOriginDefaultRetraceTime
+	}
+
+	for {
+		start := time.Now()
+
+		traced, err := exportDepositOrigins(maxHops, time.Now().Add(-retraceInterval))
+		if err != nil {
+			utils.LogError(err, "error exporting deposit origins", 0)
+		} else {
+			logger.Infof("traced the origin of %v deposit addresses in %v", traced, time.Since(start))
+		}
+		metrics.TaskDuration.WithLabelValues("deposit_origin_exporter").Observe(time.Since(start).Seconds())
+
+		if err != nil || traced < depositOriginBatchSize {
+			time.Sleep(time.Minute * 10)
+		}
+	}
+}
+
+func exportDepositOrigins(maxHops int, retraceBefore time.Time) (int, error) {
+	addresses, err := db.GetDepositAddressesToTrace(retraceBefore, depositOriginBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	origins := make([]*types.DepositOrigin, 0, len(addresses))
+	for _, a := range addresses {
+		origin, err := db.BigtableClient.TraceDepositOrigin(a.Address, a.FirstDeposit, maxHops)
+		if err != nil {
+			return 0, err
+		}
+		origins = append(origins, origin)
+	}
+
+	err = db.SaveDepositOrigins(origins)
+	if err != nil {
+		return 0, err
+	}
+	return len(origins), nil
+}
@@ -137,6 +137,23 @@ func eth1DepositsExporter() {
 			}
 		}
 
+		// check all deposits once after starting so that deposits exported by older versions get flagged as well
+		if len(depositsToSave) > 0 || lastFetchedBlock == 0 {
+			warningsFromBlock := fromBlock
+			if lastFetchedBlock == 0 {
+				warningsFromBlock = eth1DepositContractFirstBlock
+			}
+			flagged, err := db.SaveEth1DepositWarnings(warningsFromBlock)
+			if err != nil {
+				logger.WithError(err).Errorf("error saving eth1-deposit-warnings")
+				time.Sleep(time.Second * 5)
+				continue
+			}
+			if flagged > 0 {
+				logger.Infof("flagged %v eth1-deposits with warnings", flagged)
+			}
+		}
+
 		// make sure we are progressing even if there are no deposits in the last batch
 		lastFetchedBlock = toBlock
 
@@ -149,9 +149,10 @@ func (ese *EthStoreExporter) prepareExportDayTx(tx *sqlx.Tx, day string, concurr
 		}
 	}
 
+	// the aggregated row (validator -1) additionally stores the size of the validator set of the day
 	stmt, err := tx.Prepare(`
-	INSERT INTO eth_store_stats (day, validator, effective_balances_sum_wei, start_balances_sum_wei, end_balances_sum_wei, deposits_sum_wei, tx_fees_sum_wei, consensus_rewards_sum_wei, total_rewards_wei, apr)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`)
+	INSERT INTO eth_store_stats (day, validator, effective_balances_sum_wei, start_balances_sum_wei, end_balances_sum_wei, deposits_sum_wei, tx_fees_sum_wei, consensus_rewards_sum_wei, total_rewards_wei, apr, validators)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`)
 	if err != nil {
 		return err
 	}
@@ -168,6 +169,7 @@ func (ese *EthStoreExporter) prepareExportDayTx(tx *sqlx.Tx, day string, concurr
 		ethStoreDay.ConsensusRewardsGwei.Mul(decimal.NewFromInt(1e9)),
 		ethStoreDay.TotalRewardsWei,
 		ethStoreDay.Apr,
+		len(validators),
 	)
 	if err != nil {
 		return err
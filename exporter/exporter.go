@@ -11,7 +11,7 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-var logger = logrus.New().WithField("module", "exporter")
+var logger = utils.NewLogger("exporter")
 
 var Client *rpc.Client
 
@@ -26,6 +26,9 @@ func Start(client rpc.Client) {
 	if utils.Config.SSVExporter.Enabled {
 		go ssvExporter()
 	}
+	if utils.Config.ObolExporter.Enabled {
+		go obolExporter()
+	}
 	if utils.Config.RocketpoolExporter.Enabled {
 		go rocketpoolExporter()
 	}
@@ -36,6 +39,31 @@ func Start(client rpc.Client) {
 
 	if utils.Config.MevBoostRelayExporter.Enabled {
 		go mevBoostRelaysExporter()
+		go relayValidatorRegistrationsExporter()
+	}
+
+	if utils.Config.CensorshipExporter.Enabled {
+		go censorshipExporter()
+	}
+
+	if utils.Config.ValidatorEntitiesExporter.Enabled {
+		go validatorEntitiesExporter()
+	}
+
+	if utils.Config.LidoExporter.Enabled {
+		go lidoExporter()
+	}
+
+	if utils.Config.DepositOriginExporter.Enabled {
+		go depositOriginExporter()
+	}
+
+	if utils.Config.Indexer.ValidatorSetSnapshots.Enabled {
+		go validatorSetSnapshotExporter()
+	}
+
+	if utils.Config.Indexer.GapExporter.Enabled {
+		go gapExporter(client)
 	}
 	// wait until the beacon-node is available
 	for {
@@ -49,6 +77,7 @@ func Start(client rpc.Client) {
 	}
 
 	firstRun := true
+	lastExportedSlot := uint64(0)
 
 	minWaitTimeBetweenRuns := time.Second * time.Duration(utils.Config.Chain.ClConfig.SecondsPerSlot)
 	for {
@@ -66,7 +95,19 @@ func Start(client rpc.Client) {
 			time.Sleep(minWaitTimeBetweenRuns - elapsed)
 		}
 
-		services.ReportStatus("slotExporter", "Running", nil)
+		var exportedSlot uint64
+		err = db.ReaderDb.Get(&exportedSlot, "SELECT COALESCE(MAX(slot), 0) FROM blocks WHERE status <> '0'")
+		if err != nil {
+			logrus.Errorf("error retrieving last exported slot: %v", err)
+			services.ReportStatus("slotExporter", "Running", nil)
+			continue
+		}
+		exportedSlots := uint64(0)
+		if exportedSlot > lastExportedSlot && lastExportedSlot > 0 {
+			exportedSlots = exportedSlot - lastExportedSlot
+		}
+		lastExportedSlot = exportedSlot
+		services.ReportExporterProgress("slotExporter", "Running", services.ExporterUnitSlot, exportedSlot, utils.TimeToSlot(uint64(time.Now().Unix())), exportedSlots)
 	}
 }
 
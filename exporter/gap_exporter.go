@@ -0,0 +1,150 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/rpc"
+	"github.com/gobitfly/eth2-beaconchain-explorer/tracing"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// gapExporter queues the slots, epochs and execution blocks missing in the exported data and re-exports the missing
+// slots and epochs. Missing execution blocks are re-exported by the eth1 indexer.
+func gapExporter(client rpc.Client) {
+	cfg := utils.Config.Indexer.GapExporter
+	if cfg.Interval == 0 {
+		cfg.Interval = time.Minute * 10
+	}
+	if cfg.Lookback == 0 {
+		cfg.Lookback = utils.SlotsPerDay()
+	}
+	if cfg.Eth1Lookback == 0 {
+		cfg.Eth1Lookback = int(utils.SlotsPerDay())
+	}
+
+	for {
+		_, span := tracing.StartSpan(context.Background(), "gapExporter.detect")
+		err := detectExportGaps(cfg.Lookback, cfg.Eth1Lookback)
+		tracing.EndSpan(span, err)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{"error": err}).Errorf("error detecting export gaps")
+		}
+		_, span = tracing.StartSpan(context.Background(), "gapExporter.reexport")
+		err = reexportGaps(client)
+		tracing.EndSpan(span, err)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{"error": err}).Errorf("error re-exporting gaps")
+		}
+		time.Sleep(cfg.Interval)
+	}
+}
+
+// detectExportGaps queues the gaps within the last lookback slots of the blocks and epochs tables and the last
+// eth1Lookback blocks of the bigtable blocks and data tables
+func detectExportGaps(lookback uint64, eth1Lookback int) error {
+	slots, err := db.GetMissingSlots(lookback)
+	if err != nil {
+		return err
+	}
+	err = db.QueueExportGaps(db.GapKindSlot, slots)
+	if err != nil {
+		return err
+	}
+
+	epochs, err := db.GetMissingEpochs(lookback / utils.Config.Chain.ClConfig.SlotsPerEpoch)
+	if err != nil {
+		return err
+	}
+	err = db.QueueExportGaps(db.GapKindEpoch, epochs)
+	if err != nil {
+		return err
+	}
+
+	blocks, err := db.BigtableClient.GetMissingBlocks(eth1Lookback, false)
+	if err != nil {
+		return fmt.Errorf("error retrieving missing blocks of the blocks table: %w", err)
+	}
+	err = db.QueueExportGaps(db.GapKindEth1Block, blocks)
+	if err != nil {
+		return err
+	}
+
+	data, err := db.BigtableClient.GetMissingBlocks(eth1Lookback, true)
+	if err != nil {
+		return fmt.Errorf("error retrieving missing blocks of the data table: %w", err)
+	}
+	err = db.QueueExportGaps(db.GapKindEth1Data, data)
+	if err != nil {
+		return err
+	}
+
+	if len(slots)+len(epochs)+len(blocks)+len(data) > 0 {
+		logger.Infof("found %v missing slots, %v missing epochs, %v missing blocks in the blocks table and %v missing blocks in the data table", len(slots), len(epochs), len(blocks), len(data))
+	}
+	return nil
+}
+
+// reexportGaps exports the queued slots and epochs, an epoch is exported with the first slot of the epoch
+func reexportGaps(client rpc.Client) error {
+	gaps, err := db.GetExportGaps()
+	if err != nil {
+		return err
+	}
+
+	counts := map[string]int{db.GapKindSlot: 0, db.GapKindEpoch: 0, db.GapKindEth1Block: 0, db.GapKindEth1Data: 0}
+	for _, gap := range gaps {
+		counts[gap.Kind]++
+	}
+
+	for _, gap := range gaps {
+		slot := gap.Number
+		switch gap.Kind {
+		case db.GapKindSlot:
+		case db.GapKindEpoch:
+			slot = gap.Number * utils.Config.Chain.ClConfig.SlotsPerEpoch
+		default:
+			continue
+		}
+
+		logger.Infof("re-exporting %v %v", gap.Kind, gap.Number)
+		err := reexportSlot(client, slot)
+		if err != nil {
+			utils.LogError(err, "error re-exporting gap", 0, map[string]interface{}{"kind": gap.Kind, "number": gap.Number})
+			err = db.FailExportGap(gap.Kind, gap.Number, err)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err = db.ResolveExportGap(gap.Kind, gap.Number)
+		if err != nil {
+			return err
+		}
+		counts[gap.Kind]--
+	}
+
+	for kind, count := range counts {
+		metrics.ExportGaps.WithLabelValues(kind).Set(float64(count))
+	}
+	return nil
+}
+
+func reexportSlot(client rpc.Client, slot uint64) error {
+	tx, err := db.WriterDb.Beginx()
+	if err != nil {
+		return fmt.Errorf("error starting tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = ExportSlot(client, slot, false, tx)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
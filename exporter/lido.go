@@ -0,0 +1,246 @@
+package exporter
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/contracts/lido"
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// lidoSigningKeysBatchSize is the amount of signing keys fetched from a staking module per call
+const lidoSigningKeysBatchSize = 500
+
+// lidoStakingModule abstracts the calls the exporter needs from the curated module (NodeOperatorsRegistry) and the community staking module
+type lidoStakingModule interface {
+	name() string
+	operatorsCount() (uint64, error)
+	operator(id uint64) (*types.LidoOperator, error)
+	// depositedKeys returns the pubkeys of the deposited keys of an operator starting at key index offset
+	depositedKeys(id, offset, limit uint64) ([][]byte, error)
+}
+
+// lidoExporter periodically exports the node operators of the lido staking modules and attributes their deposited keys
+func lidoExporter() {
+	logger.Infoln("started lido exporter")
+
+	client, err := ethclient.Dial(utils.Config.Eth1GethEndpoint)
+	if err != nil {
+		utils.LogFatal(err, "new lido exporter geth client error", 0)
+	}
+
+	chainID := utils.Config.Chain.ClConfig.DepositChainID
+	modules := []lidoStakingModule{}
+	if address, exists := lido.NodeOperatorsRegistryAddressesByChainID[chainID]; exists {
+		registry, err := lido.NewNodeOperatorsRegistryCaller(address, client)
+		if err != nil {
+			utils.LogFatal(err, "error initializing lido node operators registry", 0)
+		}
+		modules = append(modules, &lidoCuratedModule{registry})
+	}
+	if address, exists := lido.CSModuleAddressesByChainID[chainID]; exists {
+		csm, err := lido.NewCSModuleCaller(address, client)
+		if err != nil {
+			utils.LogFatal(err, "error initializing lido community staking module", 0)
+		}
+		modules = append(modules, &lidoCommunityStakingModule{csm})
+	}
+	if len(modules) == 0 {
+		logger.Warnf("lido is not deployed on chain %v, stopping lido exporter", chainID)
+		return
+	}
+
+	for {
+		start := time.Now()
+		for _, module := range modules {
+			err := exportLidoModule(module)
+			if err != nil {
+				utils.LogError(err, "error exporting lido staking module", 0, map[string]interface{}{"module": module.name()})
+			}
+		}
+		metrics.TaskDuration.WithLabelValues("lido_exporter").Observe(time.Since(start).Seconds())
+		logger.Infof("exported lido operators in %v", time.Since(start))
+
+		time.Sleep(time.Hour)
+	}
+}
+
+func exportLidoModule(module lidoStakingModule) error {
+	count, err := module.operatorsCount()
+	if err != nil {
+		return fmt.Errorf("error retrieving operators count: %w", err)
+	}
+
+	keysCount, err := db.GetLidoOperatorKeysCount(module.name())
+	if err != nil {
+		return err
+	}
+
+	operators := make([]*types.LidoOperator, 0, count)
+	for id := uint64(0); id < count; id++ {
+		operator, err := module.operator(id)
+		if err != nil {
+			return fmt.Errorf("error retrieving operator %v: %w", id, err)
+		}
+		operators = append(operators, operator)
+
+		// deposited keys can not be removed anymore, so only keys deposited since the last run need to be fetched
+		for offset := keysCount[id]; offset < operator.TotalDepositedValidators; offset += lidoSigningKeysBatchSize {
+			limit := operator.TotalDepositedValidators - offset
+			if limit > lidoSigningKeysBatchSize {
+				limit = lidoSigningKeysBatchSize
+			}
+			pubkeys, err := module.depositedKeys(id, offset, limit)
+			if err != nil {
+				return fmt.Errorf("error retrieving keys %v-%v of operator %v: %w", offset, offset+limit, id, err)
+			}
+			err = db.SaveLidoOperatorValidators(module.name(), id, offset, pubkeys)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return db.SaveLidoOperators(operators)
+}
+
+type lidoStakingModuleSummary struct {
+	TargetLimitMode            *big.Int
+	TargetValidatorsCount      *big.Int
+	StuckValidatorsCount       *big.Int
+	RefundedValidatorsCount    *big.Int
+	StuckPenaltyEndTimestamp   *big.Int
+	TotalExitedValidators      *big.Int
+	TotalDepositedValidators   *big.Int
+	DepositableValidatorsCount *big.Int
+}
+
+// apply sets the fields every staking module reports via getNodeOperatorSummary
+func (s *lidoStakingModuleSummary) apply(operator *types.LidoOperator) {
+	operator.TargetLimitMode = s.TargetLimitMode.Uint64()
+	operator.TargetValidators = s.TargetValidatorsCount.Uint64()
+	operator.StuckValidators = s.StuckValidatorsCount.Uint64()
+	operator.RefundedValidators = s.RefundedValidatorsCount.Uint64()
+	operator.TotalExitedValidators = s.TotalExitedValidators.Uint64()
+	operator.TotalDepositedValidators = s.TotalDepositedValidators.Uint64()
+	operator.DepositableValidators = s.DepositableValidatorsCount.Uint64()
+	if s.StuckPenaltyEndTimestamp.Sign() > 0 {
+		ts := time.Unix(s.StuckPenaltyEndTimestamp.Int64(), 0)
+		operator.StuckPenaltyEndTs = &ts
+	}
+}
+
+// splitLidoPubkeys splits the concatenated pubkeys returned by the staking modules
+func splitLidoPubkeys(data []byte) ([][]byte, error) {
+	if len(data)%48 != 0 {
+		return nil, fmt.Errorf("invalid length of concatenated pubkeys: %v", len(data))
+	}
+	pubkeys := make([][]byte, 0, len(data)/48)
+	for i := 0; i < len(data); i += 48 {
+		pubkeys = append(pubkeys, data[i:i+48])
+	}
+	return pubkeys, nil
+}
+
+type lidoCuratedModule struct {
+	registry *lido.NodeOperatorsRegistryCaller
+}
+
+func (m *lidoCuratedModule) name() string {
+	return lido.ModuleCurated
+}
+
+func (m *lidoCuratedModule) operatorsCount() (uint64, error) {
+	count, err := m.registry.GetNodeOperatorsCount(nil)
+	if err != nil {
+		return 0, err
+	}
+	return count.Uint64(), nil
+}
+
+func (m *lidoCuratedModule) operator(id uint64) (*types.LidoOperator, error) {
+	opts := &bind.CallOpts{}
+	info, err := m.registry.GetNodeOperator(opts, new(big.Int).SetUint64(id), true)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := m.registry.GetNodeOperatorSummary(opts, new(big.Int).SetUint64(id))
+	if err != nil {
+		return nil, err
+	}
+	penalized, err := m.registry.IsOperatorPenalized(opts, new(big.Int).SetUint64(id))
+	if err != nil {
+		return nil, err
+	}
+
+	operator := &types.LidoOperator{
+		Module:               lido.ModuleCurated,
+		OperatorId:           id,
+		Name:                 info.Name,
+		RewardAddress:        info.RewardAddress.Bytes(),
+		Active:               info.Active,
+		TotalAddedValidators: info.TotalAddedValidators,
+		Penalized:            penalized,
+		UpdatedTs:            time.Now(),
+	}
+	(*lidoStakingModuleSummary)(&summary).apply(operator)
+	return operator, nil
+}
+
+func (m *lidoCuratedModule) depositedKeys(id, offset, limit uint64) ([][]byte, error) {
+	keys, err := m.registry.GetSigningKeys(nil, new(big.Int).SetUint64(id), new(big.Int).SetUint64(offset), new(big.Int).SetUint64(limit))
+	if err != nil {
+		return nil, err
+	}
+	return splitLidoPubkeys(keys.Pubkeys)
+}
+
+type lidoCommunityStakingModule struct {
+	csm *lido.CSModuleCaller
+}
+
+func (m *lidoCommunityStakingModule) name() string {
+	return lido.ModuleCSM
+}
+
+func (m *lidoCommunityStakingModule) operatorsCount() (uint64, error) {
+	count, err := m.csm.GetNodeOperatorsCount(nil)
+	if err != nil {
+		return 0, err
+	}
+	return count.Uint64(), nil
+}
+
+func (m *lidoCommunityStakingModule) operator(id uint64) (*types.LidoOperator, error) {
+	summary, err := m.csm.GetNodeOperatorSummary(nil, new(big.Int).SetUint64(id))
+	if err != nil {
+		return nil, err
+	}
+
+	// community staking operators are permissionless and have no name, they are penalized via their bond instead of their rewards
+	operator := &types.LidoOperator{
+		Module:     lido.ModuleCSM,
+		OperatorId: id,
+		Name:       fmt.Sprintf("CSM Operator %v", id),
+		Active:     true,
+		UpdatedTs:  time.Now(),
+	}
+	(*lidoStakingModuleSummary)(&summary).apply(operator)
+	operator.Penalized = operator.StuckValidators > 0
+	return operator, nil
+}
+
+func (m *lidoCommunityStakingModule) depositedKeys(id, offset, limit uint64) ([][]byte, error) {
+	keys, err := m.csm.GetSigningKeys(nil, new(big.Int).SetUint64(id), new(big.Int).SetUint64(offset), new(big.Int).SetUint64(limit))
+	if err != nil {
+		return nil, err
+	}
+	return splitLidoPubkeys(keys)
+}
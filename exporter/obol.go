@@ -0,0 +1,106 @@
+package exporter
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+const obolLocksPageSize = 100
+
+// ObolLocksResponse is a page of the cluster locks of a network as returned by the obol api
+type ObolLocksResponse struct {
+	Locks      []*ObolClusterLock `json:"cluster_locks"`
+	TotalCount int                `json:"total_count"`
+}
+
+// ObolClusterLock contains the fields of a charon cluster-lock the exporter needs
+type ObolClusterLock struct {
+	LockHash          string `json:"lock_hash"`
+	ClusterDefinition struct {
+		Name      string `json:"name"`
+		Operators []struct {
+			Address string `json:"address"`
+		} `json:"operators"`
+	} `json:"cluster_definition"`
+	DistributedValidators []struct {
+		DistributedPublicKey string `json:"distributed_public_key"`
+	} `json:"distributed_validators"`
+}
+
+// obolExporter periodically exports the distributed validators of all obol clusters of the network
+func obolExporter() {
+	logger.Infoln("started obol exporter")
+	for {
+		start := time.Now()
+		err := exportObol()
+		if err != nil {
+			utils.LogError(err, "error exporting obol validators", 0)
+		}
+		metrics.TaskDuration.WithLabelValues("obol_exporter").Observe(time.Since(start).Seconds())
+
+		time.Sleep(time.Hour)
+	}
+}
+
+func exportObol() error {
+	client := &http.Client{Timeout: time.Minute}
+	address := strings.TrimSuffix(utils.Config.ObolExporter.Address, "/")
+	if address == "" {
+		address = "https://api.obol.tech"
+	}
+
+	validators := []*types.DvtValidator{}
+	for page := 0; ; page++ {
+		url := fmt.Sprintf("%s/v1/lock/network/%s?page=%d&limit=%d", address, utils.Config.Chain.Name, page, obolLocksPageSize)
+		resp, err := client.Get(url)
+		if err != nil {
+			return fmt.Errorf("error requesting obol cluster locks from %v: %w", url, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return fmt.Errorf("error requesting obol cluster locks from %v: unexpected status code %v", url, resp.StatusCode)
+		}
+		res := &ObolLocksResponse{}
+		err = json.NewDecoder(resp.Body).Decode(res)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("error decoding obol cluster locks from %v: %w", url, err)
+		}
+
+		for _, lock := range res.Locks {
+			operators := make([]string, 0, len(lock.ClusterDefinition.Operators))
+			for _, o := range lock.ClusterDefinition.Operators {
+				operators = append(operators, strings.ToLower(o.Address))
+			}
+			for _, dv := range lock.DistributedValidators {
+				pubkey, err := hex.DecodeString(strings.TrimPrefix(dv.DistributedPublicKey, "0x"))
+				if err != nil {
+					logger.Warnf("invalid distributed public key %v in obol cluster %v", dv.DistributedPublicKey, lock.LockHash)
+					continue
+				}
+				validators = append(validators, &types.DvtValidator{
+					PublicKey:   pubkey,
+					ClusterId:   lock.LockHash,
+					ClusterName: lock.ClusterDefinition.Name,
+					Operators:   operators,
+				})
+			}
+		}
+
+		if len(res.Locks) < obolLocksPageSize {
+			break
+		}
+	}
+
+	logger.Infof("exporting %v obol validators", len(validators))
+	return db.SaveDvtValidators(types.DvtProtocolObol, validators)
+}
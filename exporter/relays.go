@@ -2,20 +2,45 @@ package exporter
 
 import (
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/httpclient"
+	"github.com/gobitfly/eth2-beaconchain-explorer/tracing"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	// relayBidsSlotsPerRun limits the amount of bid requests sent to a single relay per export run
+	relayBidsSlotsPerRun = 100
+	// relayRegistrationsPerRun limits the amount of validators whose relay registrations are checked per export run
+	relayRegistrationsPerRun = 500
+	// RelayRegistrationsCheckInterval is the time after which the relay registrations of a validator are checked again
+	RelayRegistrationsCheckInterval = time.Hour
+)
+
+// validatorRegistration is the signed registration a validator sent to a relay via mev-boost
+type validatorRegistration struct {
+	Message struct {
+		FeeRecipient string `json:"fee_recipient"`
+		GasLimit     uint64 `json:"gas_limit,string"`
+		Timestamp    int64  `json:"timestamp,string"`
+		Pubkey       string `json:"pubkey"`
+	} `json:"message"`
+	Signature string `json:"signature"`
+}
+
 type BidTrace struct {
 	Slot                 uint64          `json:"slot,string"`
 	ParentHash           string          `json:"parent_hash"`
@@ -28,12 +53,15 @@ type BidTrace struct {
 	Value                types.WeiString `json:"value"`
 }
 
+// relayHttpClient fails fast for relays that keep failing, so a single slow relay does not hold up the export
+var relayHttpClient = httpclient.New(httpclient.TargetRelays, tracing.Transport(nil))
+
 func mevBoostRelaysExporter() {
 	var relays []types.Relay
 	for {
 		// we retrieve the relays from the db each loop to prevent having to restart the exporter for changes
 		relays = nil
-		err := db.ReaderDb.Select(&relays, `select tag_id, endpoint, public_link, is_censoring, is_ethical, export_failure_count, last_export_try_ts, last_export_success_ts from relays`)
+		err := db.ReaderDb.Select(&relays, `select tag_id, endpoint, public_link, is_censoring, is_ethical, export_failure_count, last_export_try_ts, last_export_success_ts, last_bids_export_slot from relays`)
 		wg := &sync.WaitGroup{}
 		mux := &sync.Mutex{}
 		if err == nil {
@@ -50,11 +78,33 @@ func mevBoostRelaysExporter() {
 			utils.LogError(err, "failed to retrieve relays from db", 0)
 		}
 		wg.Wait()
+
+		err = reconcileRelayBids()
+		if err != nil {
+			utils.LogError(err, "failed to reconcile relay bids", 0)
+		}
+
+		err = exportBlocksProduction()
+		if err != nil {
+			utils.LogError(err, "failed to export blocks production", 0)
+		}
 		time.Sleep(time.Minute)
 	}
 
 }
 
+// relayValidatorRegistrationsExporter checks the relay registrations of subscribed validators in its own loop, so that
+// slow relays do not hold up the payload export
+func relayValidatorRegistrationsExporter() {
+	for {
+		err := exportRelayValidatorRegistrations()
+		if err != nil {
+			utils.LogError(err, "failed to export relay validator registrations", 0)
+		}
+		time.Sleep(time.Minute)
+	}
+}
+
 func singleRelayExport(r types.Relay, wg *sync.WaitGroup, mux *sync.Mutex) {
 	defer wg.Done()
 
@@ -103,6 +153,14 @@ func singleRelayExport(r types.Relay, wg *sync.WaitGroup, mux *sync.Mutex) {
 	}
 
 	r.Logger.Infof("finished syncing payloads from relay")
+
+	// bid traces are only used for reconciliation, a failure here should not back off the payload export
+	err = exportRelayBids(r, mux)
+	if err != nil {
+		r.Logger.Warnf("failed to export bids for relay: %v", err)
+		return
+	}
+	r.Logger.Infof("finished syncing bids from relay")
 }
 
 func fetchDeliveredPayloads(r types.Relay, offset uint64) ([]BidTrace, error) {
@@ -113,7 +171,7 @@ func fetchDeliveredPayloads(r types.Relay, offset uint64) ([]BidTrace, error) {
 	}
 	r.Logger.Debugf("calling %v", url)
 
-	resp, err := http.Get(url)
+	resp, err := relayHttpClient.Get(url)
 
 	if err != nil {
 		r.Logger.Errorf("error retrieving delivered payloads: %v", err)
@@ -132,6 +190,316 @@ func fetchDeliveredPayloads(r types.Relay, offset uint64) ([]BidTrace, error) {
 	return payloads, nil
 }
 
+func fetchBuilderBids(r types.Relay, slot uint64) ([]BidTrace, error) {
+	var bids []BidTrace
+	url := fmt.Sprintf("%s/relay/v1/data/bidtraces/builder_blocks_received?slot=%v", r.Endpoint, slot)
+	r.Logger.Debugf("calling %v", url)
+
+	resp, err := relayHttpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %v when retrieving bids for slot %v", resp.StatusCode, slot)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&bids)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding bids for slot %v: %w", slot, err)
+	}
+
+	return bids, nil
+}
+
+// fetchValidatorRegistration retrieves the registration of a validator at a relay, returns nil if the validator is not
+// registered with the relay
+func fetchValidatorRegistration(r types.Relay, pubkey []byte) (*validatorRegistration, error) {
+	url := fmt.Sprintf("%s/relay/v1/data/validator_registration?pubkey=0x%x", r.Endpoint, pubkey)
+	r.Logger.Debugf("calling %v", url)
+
+	resp, err := relayHttpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	// relays also answer with bad request for malformed or rate limited requests, only an explicit
+	// "no registration found" message means that the validator is not registered
+	if resp.StatusCode == http.StatusBadRequest {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if err == nil && strings.Contains(strings.ToLower(string(body)), "no registration found") {
+			return nil, nil
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %v when retrieving registration of validator %#x", resp.StatusCode, pubkey)
+	}
+
+	registration := &validatorRegistration{}
+	err = json.NewDecoder(resp.Body).Decode(registration)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding registration of validator %#x: %w", pubkey, err)
+	}
+
+	return registration, nil
+}
+
+// CheckValidatorRelayRegistrations checks the registrations of the validators at all relays and stores the results.
+// Relays that fail to answer are skipped, their last known registrations are kept.
+func CheckValidatorRelayRegistrations(pubkeys [][]byte) error {
+	var relays []types.Relay
+	err := db.ReaderDb.Select(&relays, `SELECT DISTINCT ON (tag_id) tag_id, endpoint FROM relays ORDER BY tag_id, export_failure_count`)
+	if err != nil {
+		return fmt.Errorf("error retrieving relays: %w", err)
+	}
+
+	registrations := make([]*types.RelayValidatorRegistration, 0, len(relays)*len(pubkeys))
+	wg := &sync.WaitGroup{}
+	mux := &sync.Mutex{}
+	for _, relay := range relays {
+		relay.Logger = *logrus.New().WithFields(logrus.Fields{"module": "exporter", "relay": relay.ID})
+		wg.Add(1)
+		go func(relay types.Relay) {
+			defer wg.Done()
+			for _, pubkey := range pubkeys {
+				registration, err := fetchValidatorRegistration(relay, pubkey)
+				if err != nil {
+					relay.Logger.Warnf("failed to check validator registration: %v", err)
+					return
+				}
+
+				res := &types.RelayValidatorRegistration{
+					RelayID:    relay.ID,
+					Pubkey:     pubkey,
+					Registered: registration != nil,
+					CheckedTs:  time.Now(),
+				}
+				if registration != nil {
+					feeRecipient, err := hex.DecodeString(strings.TrimPrefix(registration.Message.FeeRecipient, "0x"))
+					if err != nil || len(feeRecipient) != 20 {
+						relay.Logger.Warnf("skipping registration of validator %#x with invalid fee recipient %v", pubkey, registration.Message.FeeRecipient)
+						continue
+					}
+					res.FeeRecipient = feeRecipient
+					res.GasLimit = sql.NullInt64{Int64: int64(registration.Message.GasLimit), Valid: true}
+					res.RegistrationTs = sql.NullTime{Time: time.Unix(registration.Message.Timestamp, 0), Valid: true}
+				}
+				mux.Lock()
+				registrations = append(registrations, res)
+				mux.Unlock()
+			}
+		}(relay)
+	}
+	wg.Wait()
+
+	return db.SaveRelayValidatorRegistrations(registrations)
+}
+
+// exportRelayValidatorRegistrations checks the relay registrations of the validators that are subscribed to the relay
+// registration notification and have not been checked within the check interval
+func exportRelayValidatorRegistrations() error {
+	pubkeys, _, err := db.GetSubsForEventFilter(types.ValidatorRelayRegistrationMissingEventName)
+	if err != nil {
+		return fmt.Errorf("error retrieving subscribed validators: %w", err)
+	}
+	if len(pubkeys) == 0 {
+		return nil
+	}
+
+	checked, err := db.GetRelayValidatorRegistrationsCheckedSince(pubkeys, time.Now().Add(-RelayRegistrationsCheckInterval))
+	if err != nil {
+		return err
+	}
+	checkedMap := make(map[string]bool, len(checked))
+	for _, pubkey := range checked {
+		checkedMap[string(pubkey)] = true
+	}
+
+	toCheck := make([][]byte, 0, relayRegistrationsPerRun)
+	for _, pubkey := range pubkeys {
+		if len(toCheck) >= relayRegistrationsPerRun {
+			break
+		}
+		if len(pubkey) == 48 && !checkedMap[string(pubkey)] {
+			checkedMap[string(pubkey)] = true
+			toCheck = append(toCheck, pubkey)
+		}
+	}
+	if len(toCheck) == 0 {
+		return nil
+	}
+
+	logger.Infof("checking relay registrations of %v validators", len(toCheck))
+	return CheckValidatorRelayRegistrations(toCheck)
+}
+
+// exportRelayBids retrieves the bids a relay received for recently proposed slots and stores the highest bid per slot
+func exportRelayBids(r types.Relay, mux *sync.Mutex) error {
+	var headSlot uint64
+	err := db.ReaderDb.Get(&headSlot, `SELECT COALESCE(MAX(slot), 0) FROM blocks`)
+	if err != nil {
+		return fmt.Errorf("error retrieving head slot: %w", err)
+	}
+
+	// relays only keep bids for a limited amount of time, so we never look further back than the lookback window
+	fromSlot := r.LastBidsExportSlot
+	// bids of the last day are requested from the relays and reconciled against our blocks
+	lookback := utils.SlotsPerDay()
+	if headSlot > lookback && fromSlot < headSlot-lookback {
+		fromSlot = headSlot - lookback
+	}
+
+	var slots []uint64
+	err = db.ReaderDb.Select(&slots, `
+		SELECT slot
+		FROM blocks
+		WHERE slot > $1 AND status = '1' AND exec_block_hash IS NOT NULL
+		ORDER BY slot ASC
+		LIMIT $2`, fromSlot, relayBidsSlotsPerRun)
+	if err != nil {
+		return fmt.Errorf("error retrieving slots to export bids for: %w", err)
+	}
+
+	for _, slot := range slots {
+		bids, err := fetchBuilderBids(r, slot)
+		if err != nil {
+			return err
+		}
+
+		var topBid *BidTrace
+		var topBlockHash, topBuilderPubkey, topProposerPubkey []byte
+		for i := range bids {
+			if topBid != nil && bids[i].Value.BigInt().Cmp(topBid.Value.BigInt()) <= 0 {
+				continue
+			}
+			// bids with malformed fields are skipped instead of aborting the export
+			blockHash, err := hex.DecodeString(strings.TrimPrefix(bids[i].BlockHash, "0x"))
+			if err != nil {
+				r.Logger.Warnf("skipping bid for slot %v with invalid block hash %v: %v", slot, bids[i].BlockHash, err)
+				continue
+			}
+			builderPubkey, err := hex.DecodeString(strings.TrimPrefix(bids[i].BuilderPubkey, "0x"))
+			if err != nil {
+				r.Logger.Warnf("skipping bid for slot %v with invalid builder pubkey %v: %v", slot, bids[i].BuilderPubkey, err)
+				continue
+			}
+			proposerPubkey, err := hex.DecodeString(strings.TrimPrefix(bids[i].ProposerPubkey, "0x"))
+			if err != nil {
+				r.Logger.Warnf("skipping bid for slot %v with invalid proposer pubkey %v: %v", slot, bids[i].ProposerPubkey, err)
+				continue
+			}
+			topBid = &bids[i]
+			topBlockHash, topBuilderPubkey, topProposerPubkey = blockHash, builderPubkey, proposerPubkey
+		}
+
+		if topBid != nil {
+			_, err = db.WriterDb.Exec(`
+				INSERT INTO relays_bids (tag_id, block_slot, block_hash, builder_pubkey, proposer_pubkey, value)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				ON CONFLICT (block_slot, tag_id) DO UPDATE SET
+					block_hash = excluded.block_hash,
+					builder_pubkey = excluded.builder_pubkey,
+					proposer_pubkey = excluded.proposer_pubkey,
+					value = excluded.value
+				WHERE relays_bids.value < excluded.value`,
+				r.ID, slot, topBlockHash, topBuilderPubkey, topProposerPubkey, topBid.Value)
+			if err != nil {
+				return fmt.Errorf("error inserting bid for slot %v: %w", slot, err)
+			}
+		}
+
+		mux.Lock()
+		_, err = db.WriterDb.Exec(`UPDATE relays SET last_bids_export_slot = $1 WHERE tag_id = $2 AND endpoint = $3`, slot, r.ID, r.Endpoint)
+		mux.Unlock()
+		if err != nil {
+			return fmt.Errorf("error updating last bids export slot: %w", err)
+		}
+
+		time.Sleep(time.Millisecond * 100)
+	}
+
+	return nil
+}
+
+// reconcileRelayBids compares the value a proposer received for a block with the highest bid any relay received for the slot
+// and records every block where the proposer was paid less than the winning bid
+func reconcileRelayBids() error {
+	var headSlot uint64
+	err := db.ReaderDb.Get(&headSlot, `SELECT COALESCE(MAX(slot), 0) FROM blocks`)
+	if err != nil {
+		return fmt.Errorf("error retrieving head slot: %w", err)
+	}
+
+	var fromSlot uint64
+	// bids of the last day are requested from the relays and reconciled against our blocks
+	lookback := utils.SlotsPerDay()
+	if headSlot > lookback {
+		fromSlot = headSlot - lookback
+	}
+
+	_, err = db.WriterDb.Exec(`
+		WITH winning_bids AS (
+			SELECT DISTINCT ON (block_slot) block_slot, tag_id, block_hash, value
+			FROM relays_bids
+			WHERE block_slot > $1
+			ORDER BY block_slot, value DESC
+		), delivered AS (
+			SELECT block_slot, block_root, MIN(tag_id) AS tag_id, MAX(value) AS value
+			FROM relays_blocks
+			WHERE block_slot > $1
+			GROUP BY block_slot, block_root
+		)
+		INSERT INTO relays_anomalies (block_slot, block_root, exec_block_hash, anomaly_type, tag_id, delivered_value, winning_bid_tag_id, winning_bid_block_hash, winning_bid_value)
+		SELECT
+			blocks.slot,
+			blocks.blockroot,
+			blocks.exec_block_hash,
+			CASE WHEN delivered.block_root IS NULL THEN $2 ELSE $3 END,
+			delivered.tag_id,
+			COALESCE(delivered.value, (execution_payloads.fee_recipient_reward * 1e18)::NUMERIC(78, 0)),
+			winning_bids.tag_id,
+			winning_bids.block_hash,
+			winning_bids.value
+		FROM blocks
+		INNER JOIN winning_bids ON winning_bids.block_slot = blocks.slot
+		LEFT JOIN delivered ON delivered.block_slot = blocks.slot AND delivered.block_root = blocks.blockroot
+		LEFT JOIN execution_payloads ON execution_payloads.block_hash = blocks.exec_block_hash
+		WHERE
+			blocks.slot > $1 AND
+			blocks.status = '1' AND
+			blocks.exec_block_hash IS NOT NULL AND
+			COALESCE(delivered.value, (execution_payloads.fee_recipient_reward * 1e18)::NUMERIC(78, 0)) < winning_bids.value
+		ON CONFLICT DO NOTHING`, fromSlot, types.RelayAnomalyLocalBlockBelowWinningBid, types.RelayAnomalyBelowWinningBid)
+	if err != nil {
+		return fmt.Errorf("error inserting relay anomalies: %w", err)
+	}
+
+	return nil
+}
+
+// exportBlocksProduction stores the production path of the blocks of the last day, relay payloads and bids can arrive
+// after the block was exported so the whole window is updated each run
+func exportBlocksProduction() error {
+	var headSlot uint64
+	err := db.ReaderDb.Get(&headSlot, `SELECT COALESCE(MAX(slot), 0) FROM blocks WHERE status <> '0'`)
+	if err != nil {
+		return fmt.Errorf("error retrieving head slot: %w", err)
+	}
+
+	var fromSlot uint64
+	lookback := utils.SlotsPerDay()
+	if headSlot > lookback {
+		fromSlot = headSlot - lookback
+	}
+
+	return db.SaveBlocksProduction(fromSlot)
+}
+
 func exportRelayBlocks(r types.Relay) error {
 	// retrieve the oldest tag usage so we know when to stop processing payloads from the head
 	var lastUsage types.RelayBlock
@@ -205,6 +573,28 @@ func retrieveAndInsertPayloadsFromRelay(r types.Relay, low_bound uint64, high_bo
 		}
 
 		for _, payload := range resp {
+			// payloads with malformed fields are skipped instead of aborting the export
+			blockHash, err := hex.DecodeString(strings.TrimPrefix(payload.BlockHash, "0x"))
+			if err != nil {
+				r.Logger.Warnf("skipping payload of slot %v with invalid block hash %v: %v", payload.Slot, payload.BlockHash, err)
+				continue
+			}
+			builderPubkey, err := hex.DecodeString(strings.TrimPrefix(payload.BuilderPubkey, "0x"))
+			if err != nil {
+				r.Logger.Warnf("skipping payload of slot %v with invalid builder pubkey %v: %v", payload.Slot, payload.BuilderPubkey, err)
+				continue
+			}
+			proposerPubkey, err := hex.DecodeString(strings.TrimPrefix(payload.ProposerPubkey, "0x"))
+			if err != nil {
+				r.Logger.Warnf("skipping payload of slot %v with invalid proposer pubkey %v: %v", payload.Slot, payload.ProposerPubkey, err)
+				continue
+			}
+			proposerFeeRecipient, err := hex.DecodeString(strings.TrimPrefix(payload.ProposerFeeRecipient, "0x"))
+			if err != nil {
+				r.Logger.Warnf("skipping payload of slot %v with invalid proposer fee recipient %v: %v", payload.Slot, payload.ProposerFeeRecipient, err)
+				continue
+			}
+
 			// first insert the tag into the blocks_tags table
 			_, err = tx.Exec(`
 				insert into blocks_tags
@@ -213,7 +603,7 @@ func retrieveAndInsertPayloadsFromRelay(r types.Relay, low_bound uint64, high_bo
 				where 
 					blocks.slot = $2 and
 					blocks.exec_block_hash = $3
-				ON CONFLICT DO NOTHING`, r.ID, payload.Slot, utils.MustParseHex(payload.BlockHash))
+				ON CONFLICT DO NOTHING`, r.ID, payload.Slot, blockHash)
 			if err != nil {
 				r.Logger.Error("failed to insert payload into blocks_tags table")
 				return err
@@ -237,10 +627,10 @@ func retrieveAndInsertPayloadsFromRelay(r types.Relay, low_bound uint64, high_bo
 					blocks.slot = $2 and
 					blocks.exec_block_hash = $3
 				ON CONFLICT (block_slot, block_root, tag_id) DO NOTHING`,
-				r.ID, payload.Slot, utils.MustParseHex(payload.BlockHash),
-				payload.Value, utils.MustParseHex(payload.BuilderPubkey),
-				utils.MustParseHex(payload.ProposerPubkey),
-				utils.MustParseHex(payload.ProposerFeeRecipient))
+				r.ID, payload.Slot, blockHash,
+				payload.Value, builderPubkey,
+				proposerPubkey,
+				proposerFeeRecipient)
 			if err != nil {
 				r.Logger.Error("failed to insert payload into relays_blocks table")
 				return err
@@ -789,6 +789,17 @@ func (rp *RocketpoolExporter) SaveMinipools() error {
 		}
 	}
 
+	// every status change updates the status time, so each (status, status_time) pair is one step of the minipool lifecycle
+	_, err = tx.Exec(`
+		insert into rocketpool_minipools_status_history (rocketpool_storage_address, address, status, status_time)
+		select rocketpool_storage_address, address, status, status_time
+		from rocketpool_minipools
+		where rocketpool_storage_address = $1 and status_time is not null
+		on conflict do nothing`, rp.API.RocketStorageContract.Address.Bytes())
+	if err != nil {
+		return fmt.Errorf("error inserting into rocketpool_minipools_status_history: %w", err)
+	}
+
 	return tx.Commit()
 }
 
@@ -892,6 +903,22 @@ func (rp *RocketpoolExporter) SaveNodes() error {
 		}
 	}
 
+	_, err = tx.Exec(`
+		insert into rocketpool_nodes_smoothing_pool_history (rocketpool_storage_address, address, opted_in, ts)
+		select n.rocketpool_storage_address, n.address, n.smoothing_pool_opted_in, now() at time zone 'utc'
+		from rocketpool_nodes n
+		where n.rocketpool_storage_address = $1 and n.smoothing_pool_opted_in is distinct from (
+			select h.opted_in
+			from rocketpool_nodes_smoothing_pool_history h
+			where h.rocketpool_storage_address = n.rocketpool_storage_address and h.address = n.address
+			order by h.ts desc
+			limit 1
+		)
+		on conflict do nothing`, rp.API.RocketStorageContract.Address.Bytes())
+	if err != nil {
+		return fmt.Errorf("error inserting into rocketpool_nodes_smoothing_pool_history: %w", err)
+	}
+
 	return tx.Commit()
 }
 
@@ -92,24 +92,25 @@ func RunSlotExporter(client rpc.Client, firstRun bool) error {
 
 	// check if any new slots have been added to the chain
 	if lastDbSlot != head.HeadSlot {
-		slotsExported := 0
-		for slot := lastDbSlot + 1; slot <= head.HeadSlot; slot++ { // export any new slots
-			err := ExportSlot(client, slot, utils.EpochOfSlot(slot) == head.HeadEpoch, tx)
-			if err != nil {
-				return fmt.Errorf("error exporting slot %v: %w", slot, err)
-			}
-			slotsExported++
+		// in case of large export runs, export at most 10 epochs per tx
+		lastSlot := head.HeadSlot
+		if maxSlots := utils.Config.Chain.ClConfig.SlotsPerEpoch * 10; lastSlot-lastDbSlot > maxSlots {
+			lastSlot = lastDbSlot + maxSlots
+		}
 
-			// in case of large export runs, export at most 10 epochs per tx
-			if slotsExported == int(utils.Config.Chain.ClConfig.SlotsPerEpoch)*10 {
-				err := tx.Commit()
+		err := exportSlots(client, lastDbSlot+1, lastSlot, head.HeadEpoch, tx)
+		if err != nil {
+			return err
+		}
 
-				if err != nil {
-					return fmt.Errorf("error committing tx: %w", err)
-				}
+		if lastSlot < head.HeadSlot {
+			err := tx.Commit()
 
-				return nil
+			if err != nil {
+				return fmt.Errorf("error committing tx: %w", err)
 			}
+
+			return nil
 		}
 	}
 
@@ -213,28 +214,69 @@ func RunSlotExporter(client rpc.Client, firstRun bool) error {
 }
 
 func ExportSlot(client rpc.Client, slot uint64, isHeadEpoch bool, tx *sqlx.Tx) error {
+	export := &slotExport{slot: slot, isHeadEpoch: isHeadEpoch}
 
-	isFirstSlotOfEpoch := slot%utils.Config.Chain.ClConfig.SlotsPerEpoch == 0
-	epoch := slot / utils.Config.Chain.ClConfig.SlotsPerEpoch
+	err := fetchSlot(client, export)
+	if err != nil {
+		return err
+	}
+	err = transformSlot(export)
+	if err != nil {
+		return err
+	}
+	err = persistSlotBigtable(export)
+	if err != nil {
+		return err
+	}
+	return persistSlotDb(client, export, tx)
+}
+
+// slotExport is the data of a slot passed through the stages of the export
+type slotExport struct {
+	slot        uint64
+	epoch       uint64
+	isHeadEpoch bool
+	start       time.Time
+
+	block *types.Block
 
-	if isFirstSlotOfEpoch {
-		logger.Infof("exporting slot %v (epoch transition into epoch %v)", slot, epoch)
+	// duties of the whole epoch, only set for the first slot of an epoch
+	attDutiesEpoch  map[types.Slot]map[types.ValidatorIndex][]types.Slot
+	syncDutiesEpoch map[types.Slot]map[types.ValidatorIndex]bool
+
+	attDuties  map[types.Slot]map[types.ValidatorIndex][]types.Slot
+	syncDuties map[types.Slot]map[types.ValidatorIndex]bool
+
+	// done is closed once the slot has been written to bigtable
+	done chan struct{}
+}
+
+// fetchSlot retrieves the data for the slot from the node, the first slot of an epoch will also contain all validator
+// duties for the whole epoch
+func fetchSlot(client rpc.Client, export *slotExport) error {
+	export.epoch = export.slot / utils.Config.Chain.ClConfig.SlotsPerEpoch
+	export.start = time.Now()
+
+	if export.slot%utils.Config.Chain.ClConfig.SlotsPerEpoch == 0 {
+		logger.Infof("exporting slot %v (epoch transition into epoch %v)", export.slot, export.epoch)
 	} else {
-		logger.Infof("exporting slot %v", slot)
+		logger.Infof("exporting slot %v", export.slot)
 	}
-	start := time.Now()
 
-	// retrieve the data for the slot from the node
-	// the first slot of an epoch will also contain all validator duties for the whole epoch
-	block, err := client.GetBlockBySlot(slot)
+	block, err := client.GetBlockBySlot(export.slot)
 	if err != nil {
-		return fmt.Errorf("error retrieving data for slot %v: %w", slot, err)
+		return fmt.Errorf("error retrieving data for slot %v: %w", export.slot, err)
 	}
+	export.block = block
+	return nil
+}
 
-	if block.EpochAssignments != nil { // export the epoch assignments as they are included in the first slot of an epoch
-		logger.Infof("exporting duties & balances for epoch %v", utils.EpochOfSlot(slot))
+// transformSlot prepares the duties of the slot for export to bigtable
+func transformSlot(export *slotExport) error {
+	block := export.block
+	epoch := export.epoch
 
-		// prepare the duties for export to bigtable
+	if block.EpochAssignments != nil { // export the epoch assignments as they are included in the first slot of an epoch
 		syncDutiesEpoch := make(map[types.Slot]map[types.ValidatorIndex]bool)
 		attDutiesEpoch := make(map[types.Slot]map[types.ValidatorIndex][]types.Slot)
 		for slot := epoch * utils.Config.Chain.ClConfig.SlotsPerEpoch; slot <= (epoch+1)*utils.Config.Chain.ClConfig.SlotsPerEpoch-1; slot++ {
@@ -260,19 +302,55 @@ func ExportSlot(client rpc.Client, slot uint64, isHeadEpoch bool, tx *sqlx.Tx) e
 
 			attDutiesEpoch[types.Slot(attestedSlot)][types.ValidatorIndex(validatorIndex)] = []types.Slot{}
 		}
+		export.syncDutiesEpoch = syncDutiesEpoch
+		export.attDutiesEpoch = attDutiesEpoch
+	}
 
-		g := errgroup.Group{}
+	// for the slot itself start by preparing the duties for export to bigtable
+	syncDuties := make(map[types.Slot]map[types.ValidatorIndex]bool)
+	syncDuties[types.Slot(block.Slot)] = make(map[types.ValidatorIndex]bool)
+
+	for validator, duty := range block.SyncDuties {
+		syncDuties[types.Slot(block.Slot)][types.ValidatorIndex(validator)] = duty
+	}
+
+	attDuties := make(map[types.Slot]map[types.ValidatorIndex][]types.Slot)
+	for validator, attestedSlots := range block.AttestationDuties {
+
+		for _, attestedSlot := range attestedSlots {
+			if attDuties[types.Slot(attestedSlot)] == nil {
+				attDuties[types.Slot(attestedSlot)] = make(map[types.ValidatorIndex][]types.Slot)
+			}
+			if attDuties[types.Slot(attestedSlot)][types.ValidatorIndex(validator)] == nil {
+				attDuties[types.Slot(attestedSlot)][types.ValidatorIndex(validator)] = make([]types.Slot, 0, 10)
+			}
+			attDuties[types.Slot(attestedSlot)][types.ValidatorIndex(validator)] = append(attDuties[types.Slot(attestedSlot)][types.ValidatorIndex(validator)], types.Slot(block.Slot))
+		}
+	}
+	export.syncDuties = syncDuties
+	export.attDuties = attDuties
+	return nil
+}
+
+// persistSlotBigtable saves the duties and balances of the slot to bigtable. The duties are stored with the slot of
+// the duty or inclusion as timestamp, so slots can be written in any order.
+func persistSlotBigtable(export *slotExport) error {
+	block := export.block
+
+	g := errgroup.Group{}
+	if export.attDutiesEpoch != nil {
+		logger.Infof("exporting duties & balances for epoch %v", export.epoch)
 
 		// save all duties to bigtable
 		g.Go(func() error {
-			err := db.BigtableClient.SaveAttestationDuties(attDutiesEpoch)
+			err := db.BigtableClient.SaveAttestationDuties(export.attDutiesEpoch)
 			if err != nil {
 				return fmt.Errorf("error exporting attestation assignments to bigtable for slot %v: %w", block.Slot, err)
 			}
 			return nil
 		})
 		g.Go(func() error {
-			err := db.BigtableClient.SaveSyncComitteeDuties(syncDutiesEpoch)
+			err := db.BigtableClient.SaveSyncComitteeDuties(export.syncDutiesEpoch)
 			if err != nil {
 				return fmt.Errorf("error exporting sync committee assignments to bigtable for slot %v: %w", block.Slot, err)
 			}
@@ -281,14 +359,41 @@ func ExportSlot(client rpc.Client, slot uint64, isHeadEpoch bool, tx *sqlx.Tx) e
 
 		// save the validator balances to bigtable
 		g.Go(func() error {
-			err := db.BigtableClient.SaveValidatorBalances(epoch, block.Validators)
+			err := db.BigtableClient.SaveValidatorBalances(export.epoch, block.Validators)
 			if err != nil {
 				return fmt.Errorf("error exporting validator balances to bigtable for slot %v: %w", block.Slot, err)
 			}
 			return nil
 		})
+	}
+	err := g.Wait()
+	if err != nil {
+		return err
+	}
+
+	// save sync & attestation duties to bigtable
+	err = db.BigtableClient.SaveAttestationDuties(export.attDuties)
+	if err != nil {
+		return fmt.Errorf("error exporting attestations to bigtable for slot %v: %w", block.Slot, err)
+	}
+	err = db.BigtableClient.SaveSyncComitteeDuties(export.syncDuties)
+	if err != nil {
+		return fmt.Errorf("error exporting sync committee duties to bigtable for slot %v: %w", block.Slot, err)
+	}
+	return nil
+}
+
+// persistSlotDb saves the epoch and block data of the slot to the db. Slots have to be persisted in order as they
+// share the transaction.
+func persistSlotDb(client rpc.Client, export *slotExport, tx *sqlx.Tx) error {
+	block := export.block
+	epoch := export.epoch
+
+	if block.EpochAssignments != nil {
+		g := errgroup.Group{}
+
 		// if we are exporting the head epoch, update the validator db table
-		if isHeadEpoch {
+		if export.isHeadEpoch {
 			g.Go(func() error {
 				err := db.SaveValidators(epoch, block.Validators, client, 10000, tx)
 				if err != nil {
@@ -316,7 +421,7 @@ func ExportSlot(client rpc.Client, slot uint64, isHeadEpoch bool, tx *sqlx.Tx) e
 				return nil
 			})
 		}
-		err = g.Wait()
+		err := g.Wait()
 		if err != nil {
 			return err
 		}
@@ -335,55 +440,20 @@ func ExportSlot(client rpc.Client, slot uint64, isHeadEpoch bool, tx *sqlx.Tx) e
 				return err
 			}
 		}
-
-		// time.Sleep(time.Minute)
-	}
-
-	// for the slot itself start by preparing the duties for export to bigtable
-	syncDuties := make(map[types.Slot]map[types.ValidatorIndex]bool)
-	syncDuties[types.Slot(block.Slot)] = make(map[types.ValidatorIndex]bool)
-
-	for validator, duty := range block.SyncDuties {
-		syncDuties[types.Slot(block.Slot)][types.ValidatorIndex(validator)] = duty
-	}
-
-	attDuties := make(map[types.Slot]map[types.ValidatorIndex][]types.Slot)
-	for validator, attestedSlots := range block.AttestationDuties {
-
-		for _, attestedSlot := range attestedSlots {
-			if attDuties[types.Slot(attestedSlot)] == nil {
-				attDuties[types.Slot(attestedSlot)] = make(map[types.ValidatorIndex][]types.Slot)
-			}
-			if attDuties[types.Slot(attestedSlot)][types.ValidatorIndex(validator)] == nil {
-				attDuties[types.Slot(attestedSlot)][types.ValidatorIndex(validator)] = make([]types.Slot, 0, 10)
-			}
-			attDuties[types.Slot(attestedSlot)][types.ValidatorIndex(validator)] = append(attDuties[types.Slot(attestedSlot)][types.ValidatorIndex(validator)], types.Slot(block.Slot))
-		}
-	}
-
-	// save sync & attestation duties to bigtable
-	err = db.BigtableClient.SaveAttestationDuties(attDuties)
-	if err != nil {
-		return fmt.Errorf("error exporting attestations to bigtable for slot %v: %w", block.Slot, err)
-	}
-	err = db.BigtableClient.SaveSyncComitteeDuties(syncDuties)
-	if err != nil {
-		return fmt.Errorf("error exporting sync committee duties to bigtable for slot %v: %w", block.Slot, err)
 	}
 
 	// save the block data to the db
-	err = db.SaveBlock(block, false, tx)
+	err := db.SaveBlock(block, false, tx)
 	if err != nil {
 		return fmt.Errorf("error saving slot to the db: %w", err)
 	}
-	// time.Sleep(time.Second)
 
 	logger.WithFields(
 		logrus.Fields{
 			"slot":      block.Slot,
 			"blockRoot": fmt.Sprintf("%x", block.BlockRoot),
 		},
-	).Infof("! export of slot completed, took %v", time.Since(start))
+	).Infof("! export of slot completed, took %v", time.Since(export.start))
 
 	return nil
 }
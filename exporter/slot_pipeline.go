@@ -0,0 +1,138 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/rpc"
+	"github.com/gobitfly/eth2-beaconchain-explorer/tracing"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+)
+
+// slotPipelineConcurrency returns the number of workers of the fetch, transform and bigtable persist stage
+func slotPipelineConcurrency() (fetch, transform, persist int) {
+	cfg := utils.Config.Indexer.SlotExporter
+	fetch, transform, persist = cfg.FetchConcurrency, cfg.TransformConcurrency, cfg.PersistConcurrency
+	if fetch <= 0 {
+		fetch = 4
+	}
+	if transform <= 0 {
+		transform = 2
+	}
+	if persist <= 0 {
+		persist = 4
+	}
+	return fetch, transform, persist
+}
+
+// exportSlots exports the slots from to to (inclusive). Slots are retrieved from the node, transformed and written to
+// bigtable by bounded worker pools, the db writes share the transaction and are done in slot order once a slot has been
+// written to bigtable. The channels between the stages are bounded so a slow stage blocks the ones before it.
+func exportSlots(client rpc.Client, from, to, headEpoch uint64, tx *sqlx.Tx) (err error) {
+	fetchConcurrency, transformConcurrency, persistConcurrency := slotPipelineConcurrency()
+
+	ctx, span := tracing.StartSpan(context.Background(), "slotExporter.exportSlots", attribute.Int64("from", int64(from)), attribute.Int64("to", int64(to)))
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	// ordered holds the slots in flight in slot order, its size bounds the number of slots in the pipeline
+	ordered := make(chan *slotExport, fetchConcurrency+transformConcurrency+persistConcurrency)
+	fetchQueue := make(chan *slotExport, fetchConcurrency)
+	transformQueue := make(chan *slotExport, transformConcurrency)
+	persistQueue := make(chan *slotExport, persistConcurrency)
+
+	g.Go(func() error {
+		defer close(ordered)
+		defer close(fetchQueue)
+		for slot := from; slot <= to; slot++ {
+			export := &slotExport{slot: slot, isHeadEpoch: utils.EpochOfSlot(slot) == headEpoch, done: make(chan struct{})}
+			select {
+			case ordered <- export:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			select {
+			case fetchQueue <- export:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+
+	runSlotStage(ctx, g, "fetch", fetchConcurrency, fetchQueue, transformQueue, func(export *slotExport) error {
+		return fetchSlot(client, export)
+	})
+	runSlotStage(ctx, g, "transform", transformConcurrency, transformQueue, persistQueue, transformSlot)
+	runSlotStage(ctx, g, "persistBigtable", persistConcurrency, persistQueue, nil, func(export *slotExport) error {
+		err := persistSlotBigtable(export)
+		if err != nil {
+			return err
+		}
+		close(export.done)
+		return nil
+	})
+
+	g.Go(func() error {
+		for export := range ordered {
+			select {
+			case <-export.done:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			_, span := tracing.StartSpan(ctx, "slotExporter.persistDb", attribute.Int64("slot", int64(export.slot)))
+			err := persistSlotDb(client, export, tx)
+			tracing.EndSpan(span, err)
+			if err != nil {
+				return fmt.Errorf("error exporting slot %v: %w", export.slot, err)
+			}
+		}
+		return nil
+	})
+
+	return g.Wait()
+}
+
+// runSlotStage starts workers processing the slots of in and passing them on to out, out is closed once all workers
+// are done. Errors cancel the context of the pipeline. Every processed slot is recorded as span named after the stage.
+func runSlotStage(ctx context.Context, g *errgroup.Group, name string, workers int, in <-chan *slotExport, out chan<- *slotExport, process func(*slotExport) error) {
+	stage, stageCtx := errgroup.WithContext(ctx)
+	for i := 0; i < workers; i++ {
+		stage.Go(func() error {
+			for export := range in {
+				if stageCtx.Err() != nil {
+					return stageCtx.Err()
+				}
+				_, span := tracing.StartSpan(stageCtx, "slotExporter."+name, attribute.Int64("slot", int64(export.slot)))
+				err := process(export)
+				tracing.EndSpan(span, err)
+				if err != nil {
+					return fmt.Errorf("error exporting slot %v: %w", export.slot, err)
+				}
+				if out == nil {
+					continue
+				}
+				select {
+				case out <- export:
+				case <-stageCtx.Done():
+					return stageCtx.Err()
+				}
+			}
+			return nil
+		})
+	}
+	g.Go(func() error {
+		err := stage.Wait()
+		if out != nil {
+			close(out)
+		}
+		return err
+	})
+}
@@ -4,10 +4,13 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 
 	"github.com/gorilla/websocket"
@@ -161,5 +164,28 @@ func saveSSV(res *SSVExporterResponse) error {
 		return err
 	}
 
-	return nil
+	// ssv clusters are identified by the set of their operators
+	validators := make([]*types.DvtValidator, 0, len(res.Data))
+	for _, d := range res.Data {
+		pubkey, err := hex.DecodeString(strings.Replace(d.Publickey, "0x", "", -1))
+		if err != nil {
+			return err
+		}
+		operatorIds := make([]int, 0, len(d.Operators))
+		for _, o := range d.Operators {
+			operatorIds = append(operatorIds, o.Nodeid)
+		}
+		sort.Ints(operatorIds)
+		operators := make([]string, 0, len(operatorIds))
+		for _, id := range operatorIds {
+			operators = append(operators, strconv.Itoa(id))
+		}
+		validators = append(validators, &types.DvtValidator{
+			PublicKey: pubkey,
+			ClusterId: strings.Join(operators, "-"),
+			Operators: operators,
+		})
+	}
+
+	return db.SaveDvtValidators(types.DvtProtocolSSV, validators)
 }
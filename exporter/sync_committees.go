@@ -22,7 +22,7 @@ func syncCommitteesExporter(rpcClient rpc.Client) {
 		if err != nil {
 			logrus.WithFields(logrus.Fields{"error": err, "duration": time.Since(t0)}).Errorf("error exporting sync_committees")
 		}
-		time.Sleep(time.Second * 12)
+		time.Sleep(utils.SlotDuration())
 	}
 }
 
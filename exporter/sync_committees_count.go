@@ -16,7 +16,7 @@ func syncCommitteesCountExporter() {
 		if err != nil {
 			logrus.WithFields(logrus.Fields{"error": err}).Errorf("error exporting sync_committees_count_per_validator")
 		}
-		time.Sleep(time.Second * 12)
+		time.Sleep(utils.SlotDuration())
 	}
 }
 
@@ -0,0 +1,29 @@
+package exporter
+
+import (
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// validatorEntitiesExporter periodically reapplies the attribution rules of validators to entities (pools, exchanges, solo stakers)
+func validatorEntitiesExporter() {
+	logger.Infoln("started validator entities exporter")
+	for {
+		start := time.Now()
+
+		version, err := db.UpdateValidatorEntities(utils.Config.ValidatorEntitiesExporter.SoloStakerMaxValidators)
+		if err != nil {
+			utils.LogError(err, "error updating validator entities", 0)
+		} else if version != nil {
+			logger.Infof("updated validator entities to version %v (%v validators, %v changes) in %v", version.Version, version.Validators, version.Changes, time.Since(start))
+		} else {
+			logger.Infof("validator entities are up to date, took %v", time.Since(start))
+		}
+		metrics.TaskDuration.WithLabelValues("validator_entities_exporter").Observe(time.Since(start).Seconds())
+
+		time.Sleep(time.Hour)
+	}
+}
@@ -0,0 +1,61 @@
+package exporter
+
+import (
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// validatorSetSnapshotExporter copies the validator set of the latest exported epoch once per snapshot interval and
+// deletes the snapshots that are older than the configured retention
+func validatorSetSnapshotExporter() {
+	interval := utils.Config.Indexer.ValidatorSetSnapshots.Interval
+	if interval == 0 {
+		interval = utils.EpochsPerDay()
+	}
+	retention := utils.Config.Indexer.ValidatorSetSnapshots.Retention
+	if retention == 0 {
+		retention = utils.Day * 90
+	}
+	epochDuration := time.Duration(utils.Config.Chain.ClConfig.SecondsPerSlot*utils.Config.Chain.ClConfig.SlotsPerEpoch) * time.Second
+	retentionEpochs := uint64(retention / epochDuration)
+
+	logger.Infoln("started validator set snapshot exporter")
+	for {
+		start := time.Now()
+
+		err := exportValidatorSetSnapshot(interval, retentionEpochs)
+		if err != nil {
+			utils.LogError(err, "error exporting validator set snapshot", 0)
+		}
+		metrics.TaskDuration.WithLabelValues("validator_set_snapshot_exporter").Observe(time.Since(start).Seconds())
+
+		time.Sleep(epochDuration)
+	}
+}
+
+func exportValidatorSetSnapshot(interval, retentionEpochs uint64) error {
+	epoch, err := db.GetLatestEpoch()
+	if err != nil {
+		return err
+	}
+
+	saved, err := db.SaveValidatorSetSnapshot(epoch, interval)
+	if err != nil {
+		return err
+	}
+	if !saved || epoch < retentionEpochs {
+		return nil
+	}
+
+	deleted, err := db.DeleteValidatorSetSnapshotsBefore(epoch - retentionEpochs)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		logger.Infof("deleted %v validator set snapshot rows older than epoch %v", deleted, epoch-retentionEpochs)
+	}
+	return nil
+}
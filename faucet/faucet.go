@@ -0,0 +1,174 @@
+package faucet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/rpc"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	geth_types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shopspring/decimal"
+)
+
+var logger = utils.NewLogger("faucet")
+
+// defaultAmountEth is the amount sent per request if none is configured
+const defaultAmountEth = 1.0
+
+// defaultCooldown is the time an address or ip has to wait between two requests if none is configured
+const defaultCooldown = time.Hour * 24
+
+// transferGasLimit is the gas limit of a plain value transfer
+const transferGasLimit = 21000
+
+// ErrCooldown is returned if the address or the ip of a request received funds within the cooldown
+var ErrCooldown = errors.New("the address or ip already received funds recently")
+
+// ErrInsufficientFunds is returned if the faucet can not afford the amount of a request
+var ErrInsufficientFunds = errors.New("the faucet is out of funds")
+
+var fundingKey *ecdsa.PrivateKey
+var fundingAddress common.Address
+
+// Init loads the funding key of the faucet, the faucet must neither run on mainnet nor without a captcha
+func Init() error {
+	if utils.Config.Chain.ClConfig.DepositChainID == 1 {
+		return fmt.Errorf("the faucet can not be enabled on mainnet")
+	}
+	if len(utils.Config.Frontend.RecaptchaSiteKey) == 0 || len(utils.Config.Frontend.RecaptchaSecretKey) == 0 {
+		return fmt.Errorf("the faucet requires the recaptcha keys to be configured")
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(utils.Config.Frontend.Faucet.PrivateKey, "0x"))
+	if err != nil {
+		return fmt.Errorf("error parsing faucet private key: %w", err)
+	}
+	fundingKey = key
+	fundingAddress = crypto.PubkeyToAddress(key.PublicKey)
+	logger.Infof("initialized faucet with funding address %v", fundingAddress.Hex())
+	return nil
+}
+
+// Address returns the funding address of the faucet
+func Address() common.Address {
+	return fundingAddress
+}
+
+// Amount returns the amount sent per request in wei
+func Amount() *big.Int {
+	amountEth := utils.Config.Frontend.Faucet.AmountEth
+	if amountEth <= 0 {
+		amountEth = defaultAmountEth
+	}
+	return decimal.NewFromFloat(amountEth).Mul(decimal.New(1, 18)).BigInt()
+}
+
+// Cooldown returns the time an address or ip has to wait between two requests
+func Cooldown() time.Duration {
+	if utils.Config.Frontend.Faucet.Cooldown > 0 {
+		return utils.Config.Frontend.Faucet.Cooldown
+	}
+	return defaultCooldown
+}
+
+// Balance returns the balance of the funding address in wei
+func Balance(ctx context.Context) (*big.Int, error) {
+	return rpc.CurrentErigonClient.GetNativeClient().BalanceAt(ctx, fundingAddress, nil)
+}
+
+// Fund sends Amount to the recipient unless the recipient or the ip received funds within the cooldown and returns
+// the hash of the funding transaction
+func Fund(ctx context.Context, recipient common.Address, ip string) (common.Hash, error) {
+	if fundingKey == nil {
+		return common.Hash{}, fmt.Errorf("faucet is not initialized")
+	}
+
+	tx, err := db.WriterDb.Beginx()
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("error starting db transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = db.LockFaucet(tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	recent, err := db.HasRecentFaucetRequest(tx, recipient.Bytes(), ip, time.Now().Add(-Cooldown()))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if recent {
+		return common.Hash{}, ErrCooldown
+	}
+
+	client := rpc.CurrentErigonClient.GetNativeClient()
+	nonce, err := client.PendingNonceAt(ctx, fundingAddress)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("error retrieving nonce of faucet: %w", err)
+	}
+	tipCap, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("error retrieving gas tip cap: %w", err)
+	}
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("error retrieving latest header: %w", err)
+	}
+	// allows the base fee to double before the transaction becomes unincludable
+	feeCap := new(big.Int).Add(tipCap, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+
+	amount := Amount()
+	balance, err := Balance(ctx)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("error retrieving balance of faucet: %w", err)
+	}
+	cost := new(big.Int).Add(amount, new(big.Int).Mul(feeCap, big.NewInt(transferGasLimit)))
+	if balance.Cmp(cost) < 0 {
+		return common.Hash{}, ErrInsufficientFunds
+	}
+
+	chainId := rpc.CurrentErigonClient.GetChainID()
+	signedTx, err := geth_types.SignNewTx(fundingKey, geth_types.LatestSignerForChainID(chainId), &geth_types.DynamicFeeTx{
+		ChainID:   chainId,
+		Nonce:     nonce,
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       transferGasLimit,
+		To:        &recipient,
+		Value:     amount,
+	})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("error signing faucet transaction: %w", err)
+	}
+
+	// the request is committed on its own before the transaction is sent so the cooldown is recorded even if the
+	// transaction holding the lock fails to commit, it is removed again if the transaction could not be sent
+	err = db.SaveFaucetRequest(recipient.Bytes(), ip, amount.String(), signedTx.Hash().Bytes())
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	err = client.SendTransaction(ctx, signedTx)
+	if err != nil {
+		if deleteErr := db.DeleteFaucetRequest(signedTx.Hash().Bytes()); deleteErr != nil {
+			logger.WithError(deleteErr).Errorf("error removing faucet request of unsent transaction %v", signedTx.Hash().Hex())
+		}
+		return common.Hash{}, fmt.Errorf("error sending faucet transaction: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		// the lock is released with the connection, the request has already been recorded
+		logger.WithError(err).Errorf("error releasing faucet lock after transaction %v", signedTx.Hash().Hex())
+	}
+	return signedTx.Hash(), nil
+}
@@ -0,0 +1,146 @@
+// Package featureflags evaluates the feature flags stored in the feature_flags table. Flags are cached in redis and
+// toggled at runtime via the admin api or the admin ui, so features can be shipped disabled and enabled without a
+// deploy, e.g. at the epoch of a fork.
+package featureflags
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+var logger = utils.NewLogger("featureflags")
+
+func cacheKey() string {
+	return fmt.Sprintf("%d:featureFlags", utils.Config.Chain.ClConfig.DepositChainID)
+}
+
+// localFlagsTTL is the time the flags are kept in-process if there is no tiered cache, so a toggle takes up to
+// localFlagsTTL to apply in other processes
+const localFlagsTTL = time.Second * 10
+
+var localFlags = struct {
+	sync.Mutex
+	flags   []*types.FeatureFlag
+	updated time.Time
+}{}
+
+// All returns all feature flags, from the cache if available
+func All() ([]*types.FeatureFlag, error) {
+	if cache.TieredCache != nil {
+		return cache.GetOrLoad(cache.KeyTypeFeatureFlags, cacheKey(), db.GetFeatureFlags)
+	}
+
+	// flags are read for every page view, so without the tiered cache they are cached in-process. If reading them
+	// fails the last known flags are used.
+	localFlags.Lock()
+	defer localFlags.Unlock()
+	if localFlags.flags == nil || time.Since(localFlags.updated) > localFlagsTTL {
+		flags, err := db.GetFeatureFlags()
+		if err != nil && localFlags.flags == nil {
+			return nil, err
+		}
+		if err != nil {
+			logger.WithError(err).Error("error retrieving feature flags, using the last known flags")
+		} else {
+			localFlags.flags = flags
+		}
+		localFlags.updated = time.Now()
+	}
+	return localFlags.flags, nil
+}
+
+// Save creates or updates the flag and refreshes the cached flags of all processes
+func Save(flag *types.FeatureFlag) error {
+	if flag.Name == "" {
+		return fmt.Errorf("feature flag name must not be empty")
+	}
+	if flag.RolloutPercentage < 0 || flag.RolloutPercentage > 100 {
+		return fmt.Errorf("rollout percentage of feature flag %v must be between 0 and 100", flag.Name)
+	}
+
+	err := db.SaveFeatureFlag(flag)
+	if err != nil {
+		return err
+	}
+	return refreshCache()
+}
+
+// Delete removes the flag and refreshes the cached flags of all processes
+func Delete(name string) error {
+	err := db.DeleteFeatureFlag(name)
+	if err != nil {
+		return err
+	}
+	return refreshCache()
+}
+
+func refreshCache() error {
+	if cache.TieredCache == nil {
+		localFlags.Lock()
+		localFlags.flags = nil
+		localFlags.Unlock()
+		return nil
+	}
+	flags, err := db.GetFeatureFlags()
+	if err != nil {
+		return err
+	}
+	return cache.Set(cache.KeyTypeFeatureFlags, cacheKey(), flags)
+}
+
+// Enabled returns whether the flag is enabled for the subject, usually the user or the ip of a request. Flags rolled
+// out to a percentage of the subjects are enabled for a stable set of subjects. Unknown flags are disabled.
+func Enabled(name, subject string) bool {
+	flags, err := All()
+	if err != nil {
+		logger.WithError(err).Error("error retrieving feature flags")
+		return false
+	}
+	for _, flag := range flags {
+		if flag.Name == name {
+			return enabledFor(flag, subject)
+		}
+	}
+	return false
+}
+
+// EnabledFlags returns the names of the flags enabled for the subject
+func EnabledFlags(subject string) map[string]bool {
+	enabled := map[string]bool{}
+	flags, err := All()
+	if err != nil {
+		logger.WithError(err).Error("error retrieving feature flags")
+		return enabled
+	}
+	for _, flag := range flags {
+		if enabledFor(flag, subject) {
+			enabled[flag.Name] = true
+		}
+	}
+	return enabled
+}
+
+func enabledFor(flag *types.FeatureFlag, subject string) bool {
+	if !flag.Enabled {
+		return false
+	}
+	if flag.RolloutPercentage >= 100 {
+		return true
+	}
+	return rolloutBucket(flag.Name, subject) < flag.RolloutPercentage
+}
+
+// rolloutBucket assigns the subject to one of 100 buckets, the flag name is part of the hash so different flags are
+// rolled out to different subjects
+func rolloutBucket(name, subject string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + subject))
+	return int(h.Sum32() % 100)
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// withdrawalAddressDashboardMaxValidators bounds the balances read per request for the addresses of staking pools
+	withdrawalAddressDashboardMaxValidators = 10000
+
+	withdrawalAddressDashboardRateLimitPerSecond = 1
+	withdrawalAddressDashboardRateLimitBurst     = 5
+)
+
+// withdrawalAddressDashboardLimiter rate limits the withdrawal address dashboards per ip
+var withdrawalAddressDashboardLimiter = newClientRateLimiter()
+
+// parseWithdrawalAddress returns the withdrawal address of the request, ens names are resolved to their address
+func parseWithdrawalAddress(r *http.Request) ([]byte, error) {
+	address := strings.ToLower(ReplaceEnsNameWithAddress(mux.Vars(r)["address"]))
+	if !strings.HasPrefix(address, "0x") {
+		address = "0x" + address
+	}
+	if !utils.IsValidEth1Address(address) {
+		return nil, fmt.Errorf("invalid withdrawal address provided")
+	}
+	return common.FromHex(address), nil
+}
+
+// WithdrawalAddressDashboard opens the dashboard page with all validators withdrawing to the address of the request.
+// Addresses with more validators than the validators based dashboard supports are only shown server side aggregated.
+func WithdrawalAddressDashboard(w http.ResponseWriter, r *http.Request) {
+	address, err := parseWithdrawalAddress(r)
+	if err != nil {
+		http.Error(w, "Error: Invalid withdrawal address", http.StatusBadRequest)
+		return
+	}
+
+	indices, err := db.GetValidatorIndicesByWithdrawalAddress(address)
+	if err != nil {
+		utils.LogError(err, "error retrieving validators of withdrawal address", 0, map[string]interface{}{"address": fmt.Sprintf("%#x", address)})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if len(indices) == 0 {
+		NotFound(w, r)
+		return
+	}
+
+	if len(indices) > getUserPremium(r).MaxValidators {
+		http.Redirect(w, r, fmt.Sprintf("/dashboard?address=%#x", address), http.StatusSeeOther)
+		return
+	}
+	validators := make([]string, 0, len(indices))
+	for _, index := range indices {
+		validators = append(validators, fmt.Sprintf("%d", index))
+	}
+	http.Redirect(w, r, fmt.Sprintf("/dashboard?validators=%s&address=%#x", strings.Join(validators, ","), address), http.StatusSeeOther)
+}
+
+// WithdrawalAddressDashboardData godoc
+// @Summary Get the aggregated balance, income and duties of all validators withdrawing to an address
+// @Tags Dashboard
+// @Description Assembles a read-only dashboard of all validators with the execution or compounding withdrawal credentials of the address.
+// @Description Addresses with more than 10000 validators are not supported.
+// @Produce json
+// @Param address path string true "The withdrawal address or an ens name"
+// @Param days query integer false "Number of days of the balance and income history, all days if omitted"
+// @Success 200 {object} types.ApiResponse{data=types.ApiWithdrawalAddressDashboardResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Router /api/v1/dashboard/address/{address} [get]
+func WithdrawalAddressDashboardData(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	if !withdrawalAddressDashboardLimiter.allowIP(w, r, withdrawalAddressDashboardRateLimitPerSecond, withdrawalAddressDashboardRateLimitBurst) {
+		return
+	}
+
+	address, err := parseWithdrawalAddress(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	days := parseUintWithDefault(r.URL.Query().Get("days"), 0)
+	errFields := map[string]interface{}{"route": r.URL.String(), "address": fmt.Sprintf("%#x", address)}
+
+	epoch := services.LatestEpoch()
+	cacheKey := fmt.Sprintf("%d:withdrawalAddressDashboard:%x:%d:%d", utils.Config.Chain.ClConfig.DepositChainID, address, epoch, days)
+	res := &types.ApiWithdrawalAddressDashboardResponse{}
+	if _, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, time.Minute, res); err == nil {
+		SendOKResponse(j, r.URL.String(), []interface{}{res})
+		return
+	}
+
+	summary, err := db.GetWithdrawalAddressDashboardSummary(address, epoch)
+	if err != nil {
+		utils.LogError(err, "error retrieving withdrawal address dashboard summary", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if summary.Validators == 0 {
+		sendErrorWithCodeResponse(w, r.URL.String(), "no validators found for withdrawal address", http.StatusNotFound)
+		return
+	}
+	if summary.Validators > withdrawalAddressDashboardMaxValidators {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("the withdrawal address has more than %d validators", withdrawalAddressDashboardMaxValidators))
+		return
+	}
+
+	indices, err := db.GetValidatorIndicesByWithdrawalAddress(address)
+	if err != nil {
+		utils.LogError(err, "error retrieving validators of withdrawal address", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	stats, err := db.GetWithdrawalAddressDashboardStats(address)
+	if err != nil {
+		utils.LogError(err, "error retrieving withdrawal address dashboard stats", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	err = addValidatorDashboardBalances(summary, indices, epoch)
+	if err != nil {
+		utils.LogError(err, "error retrieving balances of withdrawal address dashboard", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	aggregateValidatorDashboardSummary(summary, stats, days)
+
+	res = &types.ApiWithdrawalAddressDashboardResponse{
+		Address: fmt.Sprintf("%#x", address),
+		Summary: summary,
+	}
+	// the balances change once per epoch
+	err = cache.TieredCache.Set(cacheKey, res, time.Second*time.Duration(utils.Config.Chain.ClConfig.SecondsPerSlot*utils.Config.Chain.ClConfig.SlotsPerEpoch))
+	if err != nil {
+		utils.LogError(err, "error caching withdrawal address dashboard", 0, errFields)
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{res})
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/csrf"
+	"golang.org/x/time/rate"
+)
+
+const (
+	addressLabelsLimit    = 200
+	addressLabelMaxLength = 50
+	addressLabelFlash     = "address_label_flash"
+
+	// community submissions are limited per user so the moderation queue can not be flooded
+	addressLabelMaxPendingPerUser  = 20
+	addressLabelSubmitRateInterval = time.Minute
+	addressLabelSubmitRateBurst    = 5
+)
+
+var addressLabelSubmitLimiter = newClientRateLimiter()
+
+// Load the address labels of the given status, pending labels are the moderation queue of community submissions
+func AddressLabels(w http.ResponseWriter, r *http.Request) {
+	if isAdmin, _ := handleAdminPermissions(w, r); !isAdmin {
+		return
+	}
+
+	templateFiles := append(layoutTemplateFiles, "user/address_labels.html")
+	var userTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	status := r.URL.Query().Get("status")
+	if status != types.AddressLabelApproved && status != types.AddressLabelRejected {
+		status = types.AddressLabelPending
+	}
+
+	labels, err := db.GetAddressLabelsByStatus(status, addressLabelsLimit)
+	if err != nil {
+		utils.LogError(err, "error loading the address labels", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data := InitPageData(w, r, "user", "/user/address_labels", "Address Labels", templateFiles)
+	data.Data = types.AddressLabelsPageData{
+		Labels:     labels,
+		Status:     status,
+		Categories: types.AddressLabelCategories,
+		CsrfField:  csrf.TemplateField(r),
+	}
+
+	if handleTemplateError(w, r, "address_labels.go", "AddressLabels", "", userTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// Add a label to an address or approve or reject an existing label
+func AddressLabelsPost(w http.ResponseWriter, r *http.Request) {
+	isAdmin, user := handleAdminPermissions(w, r)
+	if !isAdmin {
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		utils.LogError(err, "error parsing form", 0)
+		http.Redirect(w, r, "/user/address_labels?error=parsingForm", http.StatusSeeOther)
+		return
+	}
+
+	action := r.FormValue("action")
+	if action == "add" {
+		label, ok := parseAddressLabelForm(r)
+		if !ok {
+			http.Redirect(w, r, "/user/address_labels?status=approved&error=invalidLabel", http.StatusSeeOther)
+			return
+		}
+		label.Source = types.AddressLabelSourceAdmin
+		label.SubmittedBy = &user.UserID
+
+		err = db.SaveAddressLabel(label)
+		if err != nil {
+			utils.LogError(err, "error saving address label", 0, map[string]interface{}{"address": fmt.Sprintf("%#x", label.Address)})
+			http.Redirect(w, r, "/user/address_labels?status=approved&error=notSaved", http.StatusSeeOther)
+			return
+		}
+		http.Redirect(w, r, "/user/address_labels?status=approved", http.StatusSeeOther)
+		return
+	}
+
+	id, err := strconv.ParseUint(r.FormValue("id"), 10, 64)
+	if err != nil {
+		utils.LogError(err, "error no label id provided", 0)
+		http.Redirect(w, r, "/user/address_labels?error=noLabelId", http.StatusSeeOther)
+		return
+	}
+
+	err = db.ReviewAddressLabel(id, action == "approve", user.UserID)
+	if err != nil {
+		utils.LogError(err, "error reviewing address label", 0, map[string]interface{}{"id": id})
+		http.Redirect(w, r, "/user/address_labels?error=notReviewed", http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/user/address_labels?status="+r.FormValue("status"), http.StatusSeeOther)
+}
+
+// Queue a label suggested by a logged in user for moderation
+func AddressLabelSubmit(w http.ResponseWriter, r *http.Request) {
+	if !utils.Config.Frontend.AddressLabels.CommunitySubmissions {
+		handleNotFoundHtml(w, r)
+		return
+	}
+	user := getUser(r)
+
+	err := r.ParseForm()
+	if err != nil {
+		utils.LogError(err, "error parsing form", 0)
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	label, ok := parseAddressLabelForm(r)
+	if !ok {
+		if len(label.Address) == 0 {
+			handleNotFoundHtml(w, r)
+			return
+		}
+		utils.SetFlash(w, r, addressLabelFlash, fmt.Sprintf("Error: the label must not be empty or longer than %v characters", addressLabelMaxLength))
+		http.Redirect(w, r, fmt.Sprintf("/address/0x%x", label.Address), http.StatusSeeOther)
+		return
+	}
+	label.Source = types.AddressLabelSourceCommunity
+	label.SubmittedBy = &user.UserID
+
+	if !addressLabelSubmitLimiter.allow(fmt.Sprintf("user:%d", user.UserID), rate.Every(addressLabelSubmitRateInterval), addressLabelSubmitRateBurst) {
+		utils.SetFlash(w, r, addressLabelFlash, "Error: you have submitted too many labels, please try again later")
+		http.Redirect(w, r, fmt.Sprintf("/address/0x%x", label.Address), http.StatusSeeOther)
+		return
+	}
+
+	pending, err := db.GetPendingAddressLabelCountByUser(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving pending address label count", 0, map[string]interface{}{"user": user.UserID})
+		utils.SetFlash(w, r, addressLabelFlash, "Error: could not submit the label, please try again later")
+		http.Redirect(w, r, fmt.Sprintf("/address/0x%x", label.Address), http.StatusSeeOther)
+		return
+	}
+	if pending >= addressLabelMaxPendingPerUser {
+		utils.SetFlash(w, r, addressLabelFlash, fmt.Sprintf("Error: you already have %v labels waiting for review, please wait until they have been reviewed", pending))
+		http.Redirect(w, r, fmt.Sprintf("/address/0x%x", label.Address), http.StatusSeeOther)
+		return
+	}
+
+	err = db.SaveAddressLabel(label)
+	if err != nil {
+		utils.LogError(err, "error saving submitted address label", 0, map[string]interface{}{"address": fmt.Sprintf("%#x", label.Address), "user": user.UserID})
+		utils.SetFlash(w, r, addressLabelFlash, "Error: could not submit the label, please try again later")
+		http.Redirect(w, r, fmt.Sprintf("/address/0x%x", label.Address), http.StatusSeeOther)
+		return
+	}
+
+	utils.SetFlash(w, r, addressLabelFlash, "Thank you, your label will be shown once it has been reviewed")
+	http.Redirect(w, r, fmt.Sprintf("/address/0x%x", label.Address), http.StatusSeeOther)
+}
+
+// parseAddressLabelForm reads the address, label, category and note of a label form, the address is only set if it is valid
+func parseAddressLabelForm(r *http.Request) (*types.AddressLabel, bool) {
+	label := &types.AddressLabel{
+		Label:    strings.TrimSpace(r.FormValue("label")),
+		Category: r.FormValue("category"),
+		Note:     strings.TrimSpace(r.FormValue("note")),
+	}
+
+	address := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(r.FormValue("address"))), "0x")
+	if !utils.IsEth1Address(address) {
+		return label, false
+	}
+	label.Address = common.FromHex(address)
+
+	if label.Label == "" || utf8.RuneCountInString(label.Label) > addressLabelMaxLength || !utils.SliceContains(types.AddressLabelCategories, label.Category) {
+		return label, false
+	}
+	return label, true
+}
+
+// resolveAddressLabels returns the approved labels of the addresses, errors are logged and result in no labels
+func resolveAddressLabels(addresses ...[]byte) map[string][]*types.AddressLabel {
+	labels, err := db.GetAddressLabelsForAddresses(addresses)
+	if err != nil {
+		logger.Errorf("error resolving address labels: %v", err)
+		return map[string][]*types.AddressLabel{}
+	}
+	return labels
+}
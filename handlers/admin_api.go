@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/featureflags"
+	"github.com/gobitfly/eth2-beaconchain-explorer/mail"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// adminReexportMaxRange is the maximum number of slots, epochs or blocks queued by a single re-export request
+const adminReexportMaxRange = 10000
+
+// AdminApiRouter returns the router of the admin api, it is served on its own listener and every request must carry
+// the configured api key as bearer token
+func AdminApiRouter() *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/exporter/heads", AdminExporterHeads).Methods("GET")
+	router.HandleFunc("/cache/flush", AdminCacheFlush).Methods("POST")
+	router.HandleFunc("/reexport", AdminReexport).Methods("POST")
+	router.HandleFunc("/feature_flags", AdminFeatureFlags).Methods("GET")
+	router.HandleFunc("/feature_flags", AdminFeatureFlagsPost).Methods("POST")
+	router.HandleFunc("/services/drain", AdminDrainStatus).Methods("GET")
+	router.HandleFunc("/services/drain", AdminDrain).Methods("POST")
+	router.HandleFunc("/services/resume", AdminResume).Methods("POST")
+	router.HandleFunc("/mail/preview", AdminMailPreview).Methods("GET")
+	router.HandleFunc("/mail/test", AdminMailTest).Methods("POST")
+	router.Use(utils.RequestIDMiddleware)
+	router.Use(adminApiKeyMiddleware)
+	return router
+}
+
+func adminApiKeyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if utils.Config.AdminApi.ApiKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(utils.Config.AdminApi.ApiKey)) != 1 {
+			sendErrorWithCodeResponse(w, r.URL.String(), "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AdminExporterHeads returns the positions of the slot, epoch, statistics and execution block exporters
+func AdminExporterHeads(w http.ResponseWriter, r *http.Request) {
+	heads, err := db.GetExporterHeads()
+	if err != nil {
+		utils.LogErrorContext(r.Context(), err, "error retrieving exporter heads", 0)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve exporter heads")
+		return
+	}
+
+	heads.Eth1BlocksTable, err = db.BigtableClient.GetLastBlockInBlocksTable()
+	if err != nil {
+		utils.LogErrorContext(r.Context(), err, "error retrieving last block of the blocks table", 0)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve exporter heads")
+		return
+	}
+	heads.Eth1DataTable, err = db.BigtableClient.GetLastBlockInDataTable()
+	if err != nil {
+		utils.LogErrorContext(r.Context(), err, "error retrieving last block of the data table", 0)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve exporter heads")
+		return
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{heads})
+}
+
+// AdminCacheFlush removes the keys from the remote cache and the in-process caches of all processes
+func AdminCacheFlush(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Keys []string `json:"keys"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil || len(req.Keys) == 0 {
+		SendBadRequestResponse(w, r.URL.String(), "request body must contain the keys to flush")
+		return
+	}
+
+	if cache.TieredCache == nil {
+		SendBadRequestResponse(w, r.URL.String(), "no tiered cache configured")
+		return
+	}
+
+	err = cache.TieredCache.Delete(req.Keys...)
+	if err != nil {
+		utils.LogErrorContext(r.Context(), err, "error flushing cache keys", 0, map[string]interface{}{"keys": req.Keys})
+		sendServerErrorResponse(w, r.URL.String(), "could not flush cache keys")
+		return
+	}
+	requestLogger(r).WithField("keys", req.Keys).Info("flushed cache keys")
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{req.Keys})
+}
+
+// AdminReexport queues a range of slots, epochs or execution blocks for re-export. Slots and epochs are re-exported by
+// the gap exporter of the exporter, execution blocks by the eth1 indexer.
+func AdminReexport(w http.ResponseWriter, r *http.Request) {
+	var req types.AdminReexportRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not parse request body")
+		return
+	}
+
+	switch req.Kind {
+	case db.GapKindSlot, db.GapKindEpoch, db.GapKindEth1Block, db.GapKindEth1Data:
+	default:
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("invalid kind %v", req.Kind))
+		return
+	}
+	if req.To < req.From || req.To-req.From >= adminReexportMaxRange {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("range must contain between 1 and %v numbers", adminReexportMaxRange))
+		return
+	}
+
+	numbers := make([]uint64, 0, req.To-req.From+1)
+	for n := req.From; n <= req.To; n++ {
+		numbers = append(numbers, n)
+	}
+	err = db.QueueExportGaps(req.Kind, numbers)
+	if err != nil {
+		utils.LogErrorContext(r.Context(), err, "error queueing re-export", 0, map[string]interface{}{"kind": req.Kind, "from": req.From, "to": req.To})
+		sendServerErrorResponse(w, r.URL.String(), "could not queue re-export")
+		return
+	}
+	requestLogger(r).WithFields(logrus.Fields{"kind": req.Kind, "from": req.From, "to": req.To}).Info("queued re-export")
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{req})
+}
+
+// AdminFeatureFlags returns all feature flags
+func AdminFeatureFlags(w http.ResponseWriter, r *http.Request) {
+	flags, err := featureflags.All()
+	if err != nil {
+		utils.LogErrorContext(r.Context(), err, "error retrieving feature flags", 0)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve feature flags")
+		return
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{flags})
+}
+
+// AdminFeatureFlagsPost creates or updates a feature flag, a missing rollout percentage enables the flag for all users
+func AdminFeatureFlagsPost(w http.ResponseWriter, r *http.Request) {
+	req := types.FeatureFlag{RolloutPercentage: 100}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not parse request body")
+		return
+	}
+
+	err = featureflags.Save(&req)
+	if err != nil {
+		utils.LogErrorContext(r.Context(), err, "error saving feature flag", 0, map[string]interface{}{"name": req.Name})
+		SendBadRequestResponse(w, r.URL.String(), "could not save feature flag")
+		return
+	}
+	requestLogger(r).WithFields(logrus.Fields{"name": req.Name, "enabled": req.Enabled, "rollout_percentage": req.RolloutPercentage}).Info("changed feature flag")
+
+	AdminFeatureFlags(w, r)
+}
+
+// AdminDrainStatus returns whether the background services of the process are drained
+func AdminDrainStatus(w http.ResponseWriter, r *http.Request) {
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{services.GetDrainStatus()})
+}
+
+// AdminDrain pauses the background services of the process once they finish their current iteration
+func AdminDrain(w http.ResponseWriter, r *http.Request) {
+	services.Drain()
+	AdminDrainStatus(w, r)
+}
+
+// AdminResume continues the background services paused by a drain
+func AdminResume(w http.ResponseWriter, r *http.Request) {
+	services.Resume()
+	AdminDrainStatus(w, r)
+}
+
+// AdminMailPreview renders a notification email with example notifications of the comma separated events in the
+// requested locale, the templates of the configured templates path are used so changes can be reviewed before sending
+func AdminMailPreview(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	events := []types.EventName{types.ValidatorBalanceDecreasedEventName}
+	if q.Get("events") != "" {
+		events = events[:0]
+		for _, event := range strings.Split(q.Get("events"), ",") {
+			events = append(events, types.EventName(strings.TrimSpace(event)))
+		}
+	}
+
+	msg, err := mail.PreviewNotificationMail(q.Get("locale"), events)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	content, err := mail.RenderMail(msg)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, err = w.Write(content)
+	if err != nil {
+		requestLogger(r).WithError(err).Error("error writing mail preview")
+	}
+}
+
+// AdminMailTest sends a notification email with example notifications of the events to the given address
+func AdminMailTest(w http.ResponseWriter, r *http.Request) {
+	var req types.AdminMailTestRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not parse request body")
+		return
+	}
+	if !utils.IsValidEmail(req.To) {
+		SendBadRequestResponse(w, r.URL.String(), "invalid email address")
+		return
+	}
+	if len(req.Events) == 0 {
+		req.Events = []types.EventName{types.ValidatorBalanceDecreasedEventName}
+	}
+
+	msg, err := mail.PreviewNotificationMail(req.Locale, req.Events)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	err = mail.SendHTMLMail(req.To, fmt.Sprintf("%s: test notification", utils.Config.Frontend.SiteDomain), msg, nil)
+	if err != nil {
+		utils.LogErrorContext(r.Context(), err, "error sending test mail", 0, map[string]interface{}{"to": req.To})
+		sendServerErrorResponse(w, r.URL.String(), "could not send test mail")
+		return
+	}
+	requestLogger(r).WithFields(logrus.Fields{"to": req.To, "locale": msg.Locale, "events": req.Events}).Info("sent test mail")
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{req})
+}
@@ -1,24 +1,30 @@
 package handlers
 
 import (
+	"bufio"
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"database/sql"
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"math/big"
 	"net/http"
 	"net/url"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
 
+	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
+	"github.com/gobitfly/eth2-beaconchain-explorer/contracts/lido"
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
 	"github.com/gobitfly/eth2-beaconchain-explorer/exporter"
 	"github.com/gobitfly/eth2-beaconchain-explorer/price"
@@ -69,10 +75,12 @@ import (
 // @tag.docs.description More info
 // @tag.name Misc
 // @tag.name User
-// @tag.description provided for Oauth applications (public OAuth support is a work in progress).
+// @tag.description provided for Oauth applications. Third-party apps must use PKCE (S256) and request a scope, scoped tokens are read-only.
 // @securitydefinitions.oauth2.accessCode OAuthAccessCode
 // @tokenurl https://beaconcha.in/user/token
 // @authorizationurl https://beaconcha.in/user/authorize
+// @scope.dashboard:read View your validator dashboards and watchlist
+// @scope.notifications:read View your notification subscriptions
 // @securitydefinitions.apikey ApiKeyAuth
 // @in header
 // @name Authorization
@@ -101,7 +109,7 @@ func ApiHealthz(w http.ResponseWriter, r *http.Request) {
 		Name   string
 		Status string
 	}{}
-	err := db.WriterDb.Select(&res, "SELECT name, status FROM service_status WHERE name = ANY($1) AND last_update > NOW() - INTERVAL '5 MINUTES' ORDER BY last_update DESC", pq.Array(modules))
+	err := db.WriterDb.SelectContext(r.Context(), &res, "SELECT name, status FROM service_status WHERE name = ANY($1) AND last_update > NOW() - INTERVAL '5 MINUTES' ORDER BY last_update DESC", pq.Array(modules))
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -143,7 +151,7 @@ func ApiHealthz(w http.ResponseWriter, r *http.Request) {
 		_, err = fmt.Fprint(w, response.String())
 
 		if err != nil {
-			logger.Debugf("error writing status: %v", err)
+			requestLogger(r).Debugf("error writing status: %v", err)
 		}
 	} else {
 		http.Error(w, response.String(), http.StatusInternalServerError)
@@ -183,19 +191,92 @@ func ApiHealthzLoadbalancer(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, "OK. Last epoch is from %v ago", time.Since(utils.EpochToTime(lastEpoch)))
 }
 
+// ApiPrice godoc
+// @Summary Get the price of the main currency of the network
+// @Tags Price
+// @Description Returns the latest price of one unit of the main currency of the network (e.g. ETH) in the requested currency.
+// @Description Besides fiat currencies BTC and GWEI are supported. Set the date to get the historical price of a past day, historical prices are only available for fiat currencies and BTC.
+// @Produce json
+// @Param currency path string true "The currency to get the price in, e.g. USD, EUR, BTC or GWEI"
+// @Param date query string false "The day to get the historical price for in the form YYYY-MM-DD"
+// @Success 200 {object} types.ApiResponse{data=types.ApiPriceResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/price/{currency} [get]
+func ApiPrice(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	vars := mux.Vars(r)
+	currency := strings.ToUpper(vars["currency"])
+	base := utils.Config.Frontend.MainCurrency
+
+	res := &types.ApiPriceResponse{
+		Base:     base,
+		Currency: currency,
+		Symbol:   price.GetCurrencySymbol(currency),
+	}
+
+	dateParam := r.URL.Query().Get("date")
+	if dateParam == "" {
+		if !price.IsAvailableCurrency(currency) && currency != "BTC" && currency != "GWEI" {
+			SendBadRequestResponse(w, r.URL.String(), "invalid currency provided")
+			return
+		}
+		res.Price = price.GetPrice(base, currency)
+		SendOKResponse(j, r.URL.String(), []interface{}{res})
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", dateParam)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid date provided, expected YYYY-MM-DD")
+		return
+	}
+	genesisTime := time.Unix(int64(utils.Config.Chain.GenesisTimestamp), 0).UTC()
+	genesisDay := time.Date(genesisTime.Year(), genesisTime.Month(), genesisTime.Day(), 0, 0, 0, 0, time.UTC)
+	if date.Before(genesisDay) || date.After(time.Now()) {
+		SendBadRequestResponse(w, r.URL.String(), "date must be between genesis and today")
+		return
+	}
+	if !utils.SliceContains(db.HistoricalPriceCurrencies, strings.ToLower(currency)) {
+		SendBadRequestResponse(w, r.URL.String(), "no historical prices available for the currency")
+		return
+	}
+
+	res.Price, err = db.GetHistoricalPrice(utils.Config.Chain.ClConfig.DepositChainID, currency, uint64(date.Sub(genesisDay)/utils.Day))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		requestLogger(r).WithError(err).Errorf("error retrieving historical price for %v on %v", currency, dateParam)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) || res.Price == 0 {
+		SendBadRequestResponse(w, r.URL.String(), "no price available for the date")
+		return
+	}
+	res.Date = &date
+
+	SendOKResponse(j, r.URL.String(), []interface{}{res})
+}
+
 // ApiEthStoreDay godoc
-// @Summary Get ETH.STORE® reference rate for a specified beaconchain-day or the latest day
+// @Summary Get ETH.STORE® reference rate for a specified beaconchain-day, a range of beaconchain-days or the latest day
 // @Tags ETH.STORE®
 // @Description ETH.STORE® represents the average financial return validators on the Ethereum network have achieved in a 24-hour period.
 // @Description For each 24-hour period the datapoint is denoted by the number of days that have passed since genesis for that period (= beaconchain-day)
+// @Description Every datapoint is broken down into its consensus and execution layer components and contains the size of the validator set as well as the annualized (compounded) rate.
 // @Description See https://github.com/gobitfly/eth.store for further information.
 // @Produce json
-// @Param day path string true "The beaconchain-day (periods of <(24 * 60 * 60) // SlotsPerEpoch // SecondsPerSlot> epochs) to get the the ETH.STORE® for. Must be a number or the string 'latest'."
+// @Produce text/csv
+// @Param day path string true "The beaconchain-day (periods of <(24 * 60 * 60) // SlotsPerEpoch // SecondsPerSlot> epochs) to get the the ETH.STORE® for. Must be a number, a range of at most 365 days in the form 'from-to' or the string 'latest'."
+// @Param format query string false "Set to 'csv' to export the data as csv"
 // @Success 200 {object} types.ApiResponse
 // @Failure 400 {object} types.ApiResponse
 // @Router /api/v1/ethstore/{day} [get]
 func ApiEthStoreDay(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
+	csvExport := r.URL.Query().Get("format") == "csv"
+	if !csvExport {
+		w.Header().Set("Content-Type", "application/json")
+	}
 
 	var err error
 	var rows *sql.Rows
@@ -210,6 +291,8 @@ func ApiEthStoreDay(w http.ResponseWriter, r *http.Request) {
 			consensus_rewards_sum_wei,
 			total_rewards_wei,
 			apr,
+			CAST (POWER(1 + apr / 365, 365) - 1 AS double precision) as apy,
+			validators,
 			CAST (ROUND((365 * consensus_rewards_sum_wei) / effective_balances_sum_wei, 16) AS double precision) as cl_apr,
 			CAST (ROUND((365 * tx_fees_sum_wei) / effective_balances_sum_wei, 16) AS double precision) as el_apr,
 			(select avg(apr) from eth_store_stats as e1 where e1.validator = -1 AND e1.day > e.day - 7 AND e1.day <= e.day) as avgapr7d,
@@ -226,19 +309,32 @@ func ApiEthStoreDay(w http.ResponseWriter, r *http.Request) {
 		WHERE validator = -1 `
 
 	vars := mux.Vars(r)
+	filename := "ethstore_" + vars["day"]
 	if vars["day"] == "latest" {
-		rows, err = db.ReaderDb.Query(query + ` ORDER BY day DESC LIMIT 1;`)
+		rows, err = db.ReaderDb.QueryContext(r.Context(), query+` ORDER BY day DESC LIMIT 1;`)
+	} else if from, to, found := strings.Cut(vars["day"], "-"); found {
+		fromDay, e1 := strconv.ParseInt(from, 10, 64)
+		toDay, e2 := strconv.ParseInt(to, 10, 64)
+		if e1 != nil || e2 != nil || fromDay > toDay {
+			SendBadRequestResponse(w, r.URL.String(), "invalid day range provided")
+			return
+		}
+		if toDay-fromDay >= 365 {
+			SendBadRequestResponse(w, r.URL.String(), "only up to 365 days can be requested at once")
+			return
+		}
+		rows, err = db.ReaderDb.QueryContext(r.Context(), query+` AND day >= $1 AND day <= $2 ORDER BY day;`, fromDay, toDay)
 	} else {
 		day, e := strconv.ParseInt(vars["day"], 10, 64)
 		if e != nil {
 			SendBadRequestResponse(w, r.URL.String(), "invalid day provided")
 			return
 		}
-		rows, err = db.ReaderDb.Query(query+` AND day = $1;`, day)
+		rows, err = db.ReaderDb.QueryContext(r.Context(), query+` AND day = $1;`, day)
 	}
 
 	if err != nil {
-		logger.Errorf("error retrieving eth.store data: %v", err)
+		requestLogger(r).Errorf("error retrieving eth.store data: %v", err)
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
@@ -255,6 +351,10 @@ func ApiEthStoreDay(w http.ResponseWriter, r *http.Request) {
 		return nil
 	}
 
+	if csvExport {
+		returnQueryResultsAsCsv(rows, w, r, filename, addDayTime)
+		return
+	}
 	returnQueryResults(rows, w, r, addDayTime)
 }
 
@@ -280,7 +380,7 @@ func ApiLatestState(w http.ResponseWriter, r *http.Request) {
 
 	err := json.NewEncoder(w).Encode(data)
 	if err != nil {
-		logger.Errorf("error sending latest index page data: %v", err)
+		requestLogger(r).Errorf("error sending latest index page data: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -326,25 +426,42 @@ func ApiEpoch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rows, err := db.ReaderDb.Query(`SELECT attestationscount, attesterslashingscount, averagevalidatorbalance, blockscount, depositscount, eligibleether, epoch, (epoch <= $2) AS finalized, globalparticipationrate, proposerslashingscount, rewards_exported, totalvalidatorbalance, validatorscount, voluntaryexitscount, votedether, COALESCE(withdrawalcount,0) as withdrawalcount, 
-		(SELECT COUNT(*) FROM blocks WHERE epoch = $1 AND status = '0') as scheduledblocks,
-		(SELECT COUNT(*) FROM blocks WHERE epoch = $1 AND status = '1') as proposedblocks,
-		(SELECT COUNT(*) FROM blocks WHERE epoch = $1 AND status = '2') as missedblocks,
-		(SELECT COUNT(*) FROM blocks WHERE epoch = $1 AND status = '3') as orphanedblocks
-		FROM epochs WHERE epoch = $1`, epoch, latestFinalizedEpoch)
+	// the data of an epoch changes until it is finalized
+	keyType := cache.KeyTypeEpoch
+	if epoch <= int64(latestFinalizedEpoch) {
+		keyType = cache.KeyTypeFinalizedEpoch
+	}
+	cacheKey := fmt.Sprintf("%d:api:epoch:%d", utils.Config.Chain.ClConfig.DepositChainID, epoch)
+	data, err := cache.GetOrLoad(keyType, cacheKey, func() ([]interface{}, error) {
+		rows, err := db.ReaderDb.QueryContext(r.Context(), `SELECT attestationscount, attesterslashingscount, averagevalidatorbalance, blockscount, depositscount, eligibleether, epoch, (epoch <= $2) AS finalized, globalparticipationrate, proposerslashingscount, rewards_exported, totalvalidatorbalance, validatorscount, voluntaryexitscount, votedether, COALESCE(withdrawalcount,0) as withdrawalcount, 
+			(SELECT COUNT(*) FROM blocks WHERE epoch = $1 AND status = '0') as scheduledblocks,
+			(SELECT COUNT(*) FROM blocks WHERE epoch = $1 AND status = '1') as proposedblocks,
+			(SELECT COUNT(*) FROM blocks WHERE epoch = $1 AND status = '2') as missedblocks,
+			(SELECT COUNT(*) FROM blocks WHERE epoch = $1 AND status = '3') as orphanedblocks
+			FROM epochs WHERE epoch = $1`, epoch, latestFinalizedEpoch)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving epoch data: %w", err)
+		}
+		defer rows.Close()
+
+		data, err := utils.SqlRowsToJSON(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing epoch data: %w", err)
+		}
+
+		addEpochTime := func(dataEntryMap map[string]interface{}) error {
+			dataEntryMap["ts"] = utils.EpochToTime(uint64(epoch))
+			return nil
+		}
+		return data, adjustQueryResults(data, addEpochTime)
+	})
 	if err != nil {
-		logger.WithError(err).Error("error retrieving epoch data")
+		requestLogger(r).WithError(err).Error("error retrieving epoch data")
 		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
-	defer rows.Close()
-
-	addEpochTime := func(dataEntryMap map[string]interface{}) error {
-		dataEntryMap["ts"] = utils.EpochToTime(uint64(epoch))
-		return nil
-	}
 
-	returnQueryResults(rows, w, r, addEpochTime)
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), data)
 }
 
 // ApiEpochSlots godoc
@@ -384,7 +501,7 @@ func ApiEpochSlots(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rows, err := db.ReaderDb.Query("SELECT attestationscount, attesterslashingscount, blockroot, depositscount, epoch, eth1data_blockhash, eth1data_depositcount, eth1data_depositroot, exec_base_fee_per_gas, exec_block_hash, exec_block_number, exec_extra_data, exec_fee_recipient, exec_gas_limit, exec_gas_used, exec_logs_bloom, exec_parent_hash, exec_random, exec_receipts_root, exec_state_root, exec_timestamp, COALESCE(exec_transactions_count,0) as exec_transactions_count, graffiti, graffiti_text, parentroot, proposer, proposerslashingscount, randaoreveal, signature, slot, stateroot, status, syncaggregate_bits, syncaggregate_participation, syncaggregate_signature, voluntaryexitscount, COALESCE(withdrawalcount,0) as withdrawalcount FROM blocks WHERE epoch = $1 ORDER BY slot", epoch)
+	rows, err := db.ReaderDb.QueryContext(r.Context(), "SELECT attestationscount, attesterslashingscount, blockroot, depositscount, epoch, eth1data_blockhash, eth1data_depositcount, eth1data_depositroot, exec_base_fee_per_gas, exec_block_hash, exec_block_number, exec_extra_data, exec_fee_recipient, exec_gas_limit, exec_gas_used, exec_logs_bloom, exec_parent_hash, exec_random, exec_receipts_root, exec_state_root, exec_timestamp, COALESCE(exec_transactions_count,0) as exec_transactions_count, graffiti, graffiti_text, parentroot, proposer, proposerslashingscount, randaoreveal, signature, slot, stateroot, status, syncaggregate_bits, syncaggregate_participation, syncaggregate_signature, voluntaryexitscount, COALESCE(withdrawalcount,0) as withdrawalcount FROM blocks WHERE epoch = $1 ORDER BY slot", epoch)
 	if err != nil {
 		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
 		return
@@ -437,7 +554,7 @@ func ApiSlots(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if len(blockRootHash) != 32 {
-		err := db.ReaderDb.Get(&blockRootHash, `SELECT blockroot FROM blocks WHERE slot = $1`, blockSlot)
+		err := db.ReaderDb.GetContext(r.Context(), &blockRootHash, `SELECT blockroot FROM blocks WHERE slot = $1`, blockSlot)
 
 		if err != nil || len(blockRootHash) != 32 {
 			SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
@@ -445,7 +562,7 @@ func ApiSlots(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	rows, err := db.ReaderDb.Query(`
+	rows, err := db.ReaderDb.QueryContext(r.Context(), `
 	SELECT
 		blocks.epoch,
 		blocks.slot,
@@ -493,7 +610,7 @@ func ApiSlots(w http.ResponseWriter, r *http.Request) {
 		blocks.blockroot = $1`, blockRootHash)
 
 	if err != nil {
-		logger.WithError(err).Error("could not retrieve db results")
+		requestLogger(r).WithError(err).Error("could not retrieve db results")
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
@@ -537,9 +654,9 @@ func ApiSlotAttestations(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rows, err := db.ReaderDb.Query("SELECT aggregationbits, beaconblockroot, block_index, block_root, block_slot, committeeindex, signature, slot, source_epoch, source_root, target_epoch, target_root, validators FROM blocks_attestations WHERE block_slot = $1 ORDER BY block_index", slot)
+	rows, err := db.ReaderDb.QueryContext(r.Context(), "SELECT aggregationbits, beaconblockroot, block_index, block_root, block_slot, committeeindex, signature, slot, source_epoch, source_root, target_epoch, target_root, validators FROM blocks_attestations WHERE block_slot = $1 ORDER BY block_index", slot)
 	if err != nil {
-		logger.WithError(err).Error("could not retrieve db results")
+		requestLogger(r).WithError(err).Error("could not retrieve db results")
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
@@ -568,7 +685,7 @@ func ApiSlotAttesterSlashings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rows, err := db.ReaderDb.Query("SELECT attestation1_beaconblockroot, attestation1_index, attestation1_indices, attestation1_signature, attestation1_slot, attestation1_source_epoch, attestation1_source_root, attestation1_target_epoch, attestation1_target_root, attestation2_beaconblockroot, attestation2_index, attestation2_indices, attestation2_signature, attestation2_slot, attestation2_source_epoch, attestation2_source_root, attestation2_target_epoch, attestation2_target_root, block_index, block_root, block_slot FROM blocks_attesterslashings WHERE block_slot = $1 ORDER BY block_index DESC", slot)
+	rows, err := db.ReaderDb.QueryContext(r.Context(), "SELECT attestation1_beaconblockroot, attestation1_index, attestation1_indices, attestation1_signature, attestation1_slot, attestation1_source_epoch, attestation1_source_root, attestation1_target_epoch, attestation1_target_root, attestation2_beaconblockroot, attestation2_index, attestation2_indices, attestation2_signature, attestation2_slot, attestation2_source_epoch, attestation2_source_root, attestation2_target_epoch, attestation2_target_root, block_index, block_root, block_slot FROM blocks_attesterslashings WHERE block_slot = $1 ORDER BY block_index DESC", slot)
 	if err != nil {
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
@@ -622,9 +739,9 @@ func ApiSlotDeposits(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rows, err := db.ReaderDb.Query("SELECT amount, block_index, block_root, block_slot, proof, publickey, signature, withdrawalcredentials FROM blocks_deposits WHERE block_slot = $1 ORDER BY block_index DESC limit $2 offset $3", slot, limit, offset)
+	rows, err := db.ReaderDb.QueryContext(r.Context(), "SELECT amount, block_index, block_root, block_slot, proof, publickey, signature, withdrawalcredentials FROM blocks_deposits WHERE block_slot = $1 ORDER BY block_index DESC limit $2 offset $3", slot, limit, offset)
 	if err != nil {
-		logger.WithError(err).Error("could not retrieve db results")
+		requestLogger(r).WithError(err).Error("could not retrieve db results")
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
@@ -654,9 +771,9 @@ func ApiSlotProposerSlashings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rows, err := db.ReaderDb.Query("SELECT block_index, block_root, block_slot, header1_bodyroot, header1_parentroot, header1_signature, header1_slot, header1_stateroot, header2_bodyroot, header2_parentroot, header2_signature, header2_slot, header2_stateroot, proposerindex FROM blocks_proposerslashings WHERE block_slot = $1 ORDER BY block_index DESC", slot)
+	rows, err := db.ReaderDb.QueryContext(r.Context(), "SELECT block_index, block_root, block_slot, header1_bodyroot, header1_parentroot, header1_signature, header1_slot, header1_stateroot, header2_bodyroot, header2_parentroot, header2_signature, header2_slot, header2_stateroot, proposerindex FROM blocks_proposerslashings WHERE block_slot = $1 ORDER BY block_index DESC", slot)
 	if err != nil {
-		logger.WithError(err).Error("could not retrieve db results")
+		requestLogger(r).WithError(err).Error("could not retrieve db results")
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
@@ -686,9 +803,9 @@ func ApiSlotVoluntaryExits(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rows, err := db.ReaderDb.Query("SELECT block_slot, block_index, block_root, epoch, validatorindex, signature FROM blocks_voluntaryexits WHERE block_slot = $1 ORDER BY block_index DESC", slot)
+	rows, err := db.ReaderDb.QueryContext(r.Context(), "SELECT block_slot, block_index, block_root, epoch, validatorindex, signature FROM blocks_voluntaryexits WHERE block_slot = $1 ORDER BY block_index DESC", slot)
 	if err != nil {
-		logger.WithError(err).Error("could not retrieve db results")
+		requestLogger(r).WithError(err).Error("could not retrieve db results")
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
@@ -716,9 +833,9 @@ func ApiSlotWithdrawals(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rows, err := db.ReaderDb.Query("SELECT block_slot, withdrawalindex, validatorindex, address, amount FROM blocks_withdrawals WHERE block_slot = $1 ORDER BY withdrawalindex", slot)
+	rows, err := db.ReaderDb.QueryContext(r.Context(), "SELECT block_slot, withdrawalindex, validatorindex, address, amount FROM blocks_withdrawals WHERE block_slot = $1 ORDER BY withdrawalindex", slot)
 	if err != nil {
-		logger.WithError(err).Error("error getting blocks_withdrawals")
+		requestLogger(r).WithError(err).Error("error getting blocks_withdrawals")
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
@@ -726,6 +843,141 @@ func ApiSlotWithdrawals(w http.ResponseWriter, r *http.Request) {
 	returnQueryResults(rows, w, r)
 }
 
+// ApiBlockProduction godoc
+// @Summary Get the production path of the block proposed in a specific slot
+// @Tags Slot
+// @Description Returns how the block of the slot was produced: the builder and relay that delivered it or whether it was built locally,
+// @Description the value reported by the relay, the value realized by the proposer and the highest bid any relay received for the slot. Values are given in wei.
+// @Produce json
+// @Param slot path string true "Block slot"
+// @Success 200 {object} types.ApiResponse{data=types.ApiBlockProductionResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Router /api/v1/block/{slot}/production [get]
+func ApiBlockProduction(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+
+	slot, err := strconv.ParseUint(vars["slot"], 10, 64)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid block slot provided")
+		return
+	}
+
+	production, err := db.GetBlockProduction(slot)
+	if err != nil {
+		requestLogger(r).WithError(err).Error("error getting block production")
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if production == nil {
+		sendErrorWithCodeResponse(w, r.URL.String(), "no production data for the slot", http.StatusNotFound)
+		return
+	}
+
+	data := &types.ApiBlockProductionResponse{
+		Slot:               production.Slot,
+		BlockRoot:          fmt.Sprintf("0x%x", production.BlockRoot),
+		ExecBlockHash:      fmt.Sprintf("0x%x", production.ExecBlockHash),
+		Proposer:           production.Proposer,
+		LocallyBuilt:       production.LocallyBuilt,
+		Relay:              production.Relay.String,
+		DeliveredValue:     production.DeliveredValue.BigInt().String(),
+		FeeRecipientReward: production.FeeRecipientReward.BigInt().String(),
+		RealizedValue:      production.RealizedValue().String(),
+		WinningBidRelay:    production.WinningBidRelay.String,
+		MissedValue:        production.MissedValue().String(),
+	}
+	if len(production.BuilderPubkey) > 0 {
+		data.BuilderPubkey = fmt.Sprintf("0x%x", production.BuilderPubkey)
+	}
+	if production.WinningBidRelay.Valid {
+		data.WinningBidBuilderPubkey = fmt.Sprintf("0x%x", production.WinningBidBuilderPubkey)
+		data.WinningBidBlockHash = fmt.Sprintf("0x%x", production.WinningBidBlockHash)
+		data.WinningBidValue = production.WinningBidValue.BigInt().String()
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{data})
+}
+
+// ApiSlotViz godoc
+// @Summary Get the slot visualization of the most recent epochs
+// @Tags Slot
+// @Description Returns the status matrix of the slot visualization shown on the homepage, the most recent epoch first.
+// @Description The status of a slot is one of proposed, missed, orphaned, scheduled or scheduled-missed.
+// @Description If the request carries a valid Authorization header the proposal and sync committee duties of the validators on the watchlist of the user are included.
+// @Produce json
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiSlotVizEpochResponse}
+// @Failure 500 {object} types.ApiResponse
+// @Router /api/v1/slotviz [get]
+func ApiSlotViz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	epochs := services.LatestSlotVizMetrics()
+
+	data := make([]*types.ApiSlotVizEpochResponse, 0, len(epochs))
+	for _, epoch := range epochs {
+		e := &types.ApiSlotVizEpochResponse{
+			Epoch:         epoch.Epoch,
+			Finalized:     epoch.Finalized,
+			Justified:     epoch.Justified,
+			Justifying:    epoch.Justifying,
+			Participation: epoch.Particicpation,
+			Slots:         make([]*types.ApiSlotVizSlotResponse, 0, len(epoch.Slots)),
+		}
+		for _, slot := range epoch.Slots {
+			if slot == nil {
+				continue
+			}
+			e.Slots = append(e.Slots, &types.ApiSlotVizSlotResponse{
+				Slot:   slot.Slot,
+				Status: slot.Status,
+				Active: slot.Active,
+			})
+		}
+		data = append(data, e)
+	}
+
+	claims := getAuthClaims(r)
+	if claims != nil && len(data) > 0 {
+		validators, err := db.GetTaggedValidators(db.WatchlistFilter{
+			UserId:         claims.UserID,
+			Tag:            types.ValidatorTagsWatchlist,
+			JoinValidators: true,
+			Network:        utils.GetNetwork(),
+		})
+		if err != nil {
+			requestLogger(r).WithError(err).Error("error getting watchlist of user")
+			sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+			return
+		}
+
+		indices := make([]uint64, 0, len(validators))
+		for _, v := range validators {
+			if v.Validator != nil {
+				indices = append(indices, v.Validator.Index)
+			}
+		}
+
+		proposals, syncMembers, err := db.GetSlotVizDuties(indices, data[len(data)-1].Epoch, data[0].Epoch)
+		if err != nil {
+			requestLogger(r).WithError(err).Error("error getting slot viz duties")
+			sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+			return
+		}
+		for _, epoch := range data {
+			epoch.SyncCommittee = syncMembers[epoch.Epoch]
+			for _, slot := range epoch.Slots {
+				slot.Proposers = proposals[slot.Slot]
+			}
+		}
+	} else {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", utils.Config.Chain.ClConfig.SecondsPerSlot))
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{data})
+}
+
 // ApiBlockVoluntaryExits godoc
 // ApiSyncCommittee godoc
 // @Summary Get the sync-committee for a sync-period
@@ -733,6 +985,7 @@ func ApiSlotWithdrawals(w http.ResponseWriter, r *http.Request) {
 // @Description Returns the sync-committee for a sync-period. Validators are sorted by sync-committee-index.
 // @Description Sync committees where introduced in the Altair hardfork. Peroids before the hardfork do not contain sync-committees.
 // @Description For mainnet sync-committes first started after epoch 74240 (period 290) and each sync-committee is active for 256 epochs.
+// @Description Members lists every distinct validator of the committee with its sync participation and net sync committee rewards in gwei.
 // @Produce json
 // @Param period path string true "Period ('latest' for latest period or 'next' for next period in the future)"
 // @Success 200 {object} types.ApiResponse{data=types.APISyncCommitteeResponse}
@@ -755,17 +1008,91 @@ func ApiSyncCommittee(w http.ResponseWriter, r *http.Request) {
 		period = utils.SyncPeriodOfEpoch(services.LatestEpoch()) + 1
 	}
 
+	// the performance of the members changes until the period has ended, finished periods are cached for longer
+	cacheDur := time.Second * time.Duration(utils.Config.Chain.ClConfig.SecondsPerSlot*utils.Config.Chain.ClConfig.SlotsPerEpoch)
+	if period < utils.SyncPeriodOfEpoch(services.LatestFinalizedEpoch()) {
+		cacheDur = time.Hour
+	}
+	cacheKey := fmt.Sprintf("%d:apiSyncCommittee:%d", utils.Config.Chain.ClConfig.DepositChainID, period)
+	cached := &types.APISyncCommitteeResponse{}
+	if _, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, cacheDur, cached); err == nil {
+		SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{cached})
+		return
+	}
+
+	committee, err := getSyncCommitteePageData(period)
+	if err != nil {
+		requestLogger(r).WithError(err).WithField("url", r.URL.String()).Errorf("error getting sync committee")
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if committee == nil {
+		SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{})
+		return
+	}
+
 	// Beware that we do not deduplicate here since a validator can be part multiple times of the same sync committee period
 	// and the order of the committeeindex is important, deduplicating it would mess up the order
-	rows, err := db.ReaderDb.Query(`SELECT period, GREATEST(period*$2, $3) AS start_epoch, ((period+1)*$2)-1 AS end_epoch, ARRAY_AGG(validatorindex ORDER BY committeeindex) AS validators FROM sync_committees WHERE period = $1 GROUP BY period`, period, utils.Config.Chain.ClConfig.EpochsPerSyncCommitteePeriod, utils.Config.Chain.ClConfig.AltairForkEpoch)
+	var validators pq.Int64Array
+	err = db.ReaderDb.GetContext(r.Context(), &validators, `SELECT ARRAY_AGG(validatorindex ORDER BY committeeindex) FROM sync_committees WHERE period = $1`, period)
 	if err != nil {
-		logger.WithError(err).WithField("url", r.URL.String()).Errorf("error querying db")
+		requestLogger(r).WithError(err).WithField("url", r.URL.String()).Errorf("error querying db")
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
-	defer rows.Close()
 
-	returnQueryResults(rows, w, r)
+	data := &types.APISyncCommitteeResponse{
+		EndEpoch:   committee.EndEpoch,
+		Period:     committee.Period,
+		StartEpoch: committee.StartEpoch,
+		Validators: make([]uint64, 0, len(validators)),
+		Members:    committee.Members,
+	}
+	for _, validator := range validators {
+		data.Validators = append(data.Validators, uint64(validator))
+	}
+
+	err = cache.TieredCache.Set(cacheKey, data, cacheDur)
+	if err != nil {
+		utils.LogError(err, fmt.Errorf("error setting tieredCache for sync committee with key %v", cacheKey), 0)
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{data})
+}
+
+// ApiValidatorSyncCommittees godoc
+// @Summary Get the sync committee history of a validator
+// @Tags SyncCommittee
+// @Description Returns the sync committee periods the validator was part of together with its sync participation and net sync committee rewards in gwei for each period, the most recent period first.
+// @Produce json
+// @Param indexOrPubkey path string true "Validator index or pubkey"
+// @Success 200 {object} types.ApiResponse{data=[]types.ValidatorSyncCommitteePeriod}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/sync_committees [get]
+func ApiValidatorSyncCommittees(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], 1)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	if len(queryIndices) == 0 {
+		SendBadRequestResponse(w, r.URL.String(), "no or invalid validator index provided")
+		return
+	}
+
+	history, err := getValidatorSyncCommitteeHistory(queryIndices[0])
+	if err != nil {
+		requestLogger(r).WithError(err).Errorf("error getting sync committee history for %v route", r.URL.String())
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve sync committee history")
+		return
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{history})
 }
 
 // ApiValidatorQueue godoc
@@ -779,7 +1106,7 @@ func ApiSyncCommittee(w http.ResponseWriter, r *http.Request) {
 func ApiValidatorQueue(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	rows, err := db.ReaderDb.Query("SELECT e.validatorscount, q.entering_validators_count as beaconchain_entering, q.exiting_validators_count as beaconchain_exiting FROM epochs e, queue q ORDER BY e.epoch DESC, q.ts DESC LIMIT 1")
+	rows, err := db.ReaderDb.QueryContext(r.Context(), "SELECT e.validatorscount, q.entering_validators_count as beaconchain_entering, q.exiting_validators_count as beaconchain_exiting FROM epochs e, queue q ORDER BY e.epoch DESC, q.ts DESC LIMIT 1")
 	if err != nil {
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
@@ -789,6 +1116,86 @@ func ApiValidatorQueue(w http.ResponseWriter, r *http.Request) {
 	returnQueryResults(rows, w, r)
 }
 
+// ApiValidatorSetSnapshot godoc
+// @Summary Get the validator set as it existed at a past epoch
+// @Tags Validator
+// @Description Returns the validators of the latest validator set snapshot taken at or before the requested epoch. Snapshots are stored periodically (by default once per day), the epoch of the used snapshot is returned as snapshot_epoch.
+// @Produce  json
+// @Param  at_epoch query int true "Epoch at which the validator set is requested"
+// @Param  offset query int false "Number of validators to skip, ordered by validator index"
+// @Param  limit query int false "Number of validators to return, defaults to 100 and is limited to 1000"
+// @Success 200 {object} types.ApiResponse{data=types.ApiValidatorSetSnapshotResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validators [get]
+func ApiValidatorSetSnapshot(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	q := r.URL.Query()
+
+	atEpoch, err := strconv.ParseUint(q.Get("at_epoch"), 10, 64)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid or missing at_epoch provided")
+		return
+	}
+	if atEpoch > services.LatestEpoch() {
+		SendBadRequestResponse(w, r.URL.String(), "at_epoch must not be in the future")
+		return
+	}
+
+	offset := parseUintWithDefault(q.Get("offset"), 0)
+	limit := parseUintWithDefault(q.Get("limit"), 100)
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	snapshotEpoch, ok, err := db.GetValidatorSetSnapshotEpoch(atEpoch)
+	if err != nil {
+		requestLogger(r).WithError(err).Error("can not GetValidatorSetSnapshotEpoch")
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if !ok {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("no validator set snapshot available at or before epoch %v", atEpoch))
+		return
+	}
+
+	validators, err := db.GetValidatorSetSnapshot(snapshotEpoch, limit, offset)
+	if err != nil {
+		requestLogger(r).WithError(err).Error("can not GetValidatorSetSnapshot")
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{types.ApiValidatorSetSnapshotResponse{
+		Epoch:         atEpoch,
+		SnapshotEpoch: snapshotEpoch,
+		Validators:    validators,
+	}})
+}
+
+// ApiValidatorsDvt godoc
+// @Summary Get the adoption of distributed validator technology (ssv and obol) with its daily history
+// @Tags Validator
+// @Produce  json
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiDvtAdoptionResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validators/dvt [get]
+func ApiValidatorsDvt(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+
+	adoption, err := db.GetDvtAdoption()
+	if err != nil {
+		requestLogger(r).WithError(err).Error("can not GetDvtAdoption")
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{adoption})
+}
+
 // ApiRocketpoolStats godoc
 // @Summary Get global rocketpool network statistics
 // @Tags Rocketpool
@@ -812,69 +1219,328 @@ func ApiRocketpoolStats(w http.ResponseWriter, r *http.Request) {
 	SendOKResponse(j, r.URL.String(), stats)
 }
 
-// ApiRocketpoolValidators godoc
-// @Summary Get rocketpool specific data for given validators
-// @Tags Rocketpool
-// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// ApiRelaysMarketShare godoc
+// @Summary Get the daily market share of relays and builders
+// @Tags Relays
+// @Description Returns the daily market share of relays and builders, the average winning bid per builder and the amount of blocks delivered by censoring relays
 // @Produce  json
-// @Success 200 {object} types.ApiResponse{data=types.ApiRocketpoolValidatorResponse}
+// @Param  days query int false "Number of days to return, defaults to 30 and is limited to 365"
+// @Success 200 {object} types.ApiResponse{data=types.RelaysMarketShareHistory}
 // @Failure 400 {object} types.ApiResponse
-// @Router /api/v1/rocketpool/validator/{indexOrPubkey} [get]
-func ApiRocketpoolValidators(w http.ResponseWriter, r *http.Request) {
-
+// @Router /api/v1/relays/marketshare [get]
+func ApiRelaysMarketShare(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	j := json.NewEncoder(w)
-	vars := mux.Vars(r)
-	maxValidators := getUserPremium(r).MaxValidators
 
-	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
-	if err != nil {
-		SendBadRequestResponse(w, r.URL.String(), err.Error())
-		return
+	days := parseUintWithDefault(r.URL.Query().Get("days"), 30)
+	if days > 365 {
+		days = 365
 	}
 
-	stats, err := getRocketpoolValidators(queryIndices)
+	var fromDay uint64
+	latestDay := utils.DayOfSlot(services.LatestSlot())
+	if latestDay > days {
+		fromDay = latestDay - days
+	}
 
+	history, err := db.GetRelaysMarketShareHistory(fromDay)
 	if err != nil {
-		SendBadRequestResponse(w, r.URL.String(), "could not parse db results")
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		requestLogger(r).WithError(err).Error("can not GetRelaysMarketShareHistory")
 		return
 	}
 
-	SendOKResponse(j, r.URL.String(), stats)
+	SendOKResponse(j, r.URL.String(), []any{history})
 }
 
-/*
-Combined validator get, performance, attestation efficency, sync committee statistics, epoch, historic epoch and rpl
-Not public documented
-*/
-func ApiDashboard(w http.ResponseWriter, r *http.Request) {
+// ApiStakingFlows godoc
+// @Summary Get the daily staking flows of the network and of validator entities
+// @Tags Network
+// @Description Returns the daily deposits, full withdrawals, partial withdrawals and the resulting net staking flow of the network and the deposits and full withdrawals per validator entity. Amounts are in Gwei.
+// @Produce  json
+// @Param  days query int false "Number of days to return, defaults to 30 and is limited to 365"
+// @Success 200 {object} types.ApiResponse{data=types.StakingFlowsHistory}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/staking-flows [get]
+func ApiStakingFlows(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	j := json.NewEncoder(w)
 
-	body, err := io.ReadAll(r.Body)
+	days := parseUintWithDefault(r.URL.Query().Get("days"), 30)
+	if days > 365 {
+		days = 365
+	}
+
+	var fromDay uint64
+	latestDay := utils.DayOfSlot(services.LatestSlot())
+	if latestDay > days {
+		fromDay = latestDay - days
+	}
+
+	history, err := db.GetStakingFlowsHistory(fromDay)
 	if err != nil {
-		utils.LogError(err, "reading body", 0)
-		SendBadRequestResponse(w, r.URL.String(), "could not read body")
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		requestLogger(r).WithError(err).Error("can not GetStakingFlowsHistory")
 		return
 	}
 
-	var getValidators bool = true
-	var parsedBody types.DashboardRequest
-	err = json.Unmarshal(body, &parsedBody)
+	SendOKResponse(j, r.URL.String(), []any{history})
+}
+
+// ApiL2Flows godoc
+// @Summary Get the daily layer 2 bridge flows
+// @Tags Execution
+// @Description Returns the daily count and amount of deposits into and withdrawals from the bridges of Arbitrum, Optimism, Base and zkSync together with the resulting net flow per network. Amounts are in Wei.
+// @Produce  json
+// @Param  days query int false "Number of days to return, defaults to 30 and is limited to 365"
+// @Success 200 {object} types.ApiResponse{data=[]types.L2BridgeFlowsDailyStats}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/l2-flows [get]
+func ApiL2Flows(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+
+	days := parseUintWithDefault(r.URL.Query().Get("days"), 30)
+	if days > 365 {
+		days = 365
+	}
+
+	var fromDay uint64
+	latestDay := utils.DayOfSlot(services.LatestSlot())
+	if latestDay > days {
+		fromDay = latestDay - days
+	}
+
+	history, err := db.GetL2BridgeFlowsHistory(fromDay)
 	if err != nil {
-		utils.LogError(err, "unmarshal json body error", 0)
-		getValidators = false
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		requestLogger(r).WithError(err).Error("can not GetL2BridgeFlowsHistory")
+		return
 	}
 
-	maxValidators := getUserPremium(r).MaxValidators
+	SendOKResponse(j, r.URL.String(), []any{history})
+}
 
-	epoch := services.LatestEpoch()
+// ApiBlockQualityLeaderboard godoc
+// @Summary Get the attestation packing leaderboard of entities
+// @Tags Network
+// @Description Returns how well the block proposers of each entity packed the available attestation votes, aggregated over the requested days. Entities with the lowest score come first.
+// @Produce  json
+// @Param  days query int false "Number of days to aggregate, defaults to 30 and is limited to 90"
+// @Param  min_blocks query int false "Minimum number of blocks an entity needs to have proposed, defaults to 10"
+// @Success 200 {object} types.ApiResponse{data=[]types.AttestationPackingEntityStats}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/blocks/quality/leaderboard [get]
+func ApiBlockQualityLeaderboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
 
-	g, _ := errgroup.WithContext(context.Background())
-	g.SetLimit(5) // limit concurrency
-	var validatorsData []interface{}
+	j := json.NewEncoder(w)
+	q := r.URL.Query()
+
+	days := parseUintWithDefault(q.Get("days"), blockQualityDays)
+	if days > 90 {
+		days = 90
+	}
+	minBlocks := parseUintWithDefault(q.Get("min_blocks"), blockQualityMinBlocks)
+
+	entities, err := db.GetAttestationPackingLeaderboard(blockQualityFromSlot(days), minBlocks)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		requestLogger(r).WithError(err).Error("can not GetAttestationPackingLeaderboard")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []any{entities})
+}
+
+// ApiRocketpoolValidators godoc
+// @Summary Get rocketpool specific data for given validators
+// @Tags Rocketpool
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Produce  json
+// @Success 200 {object} types.ApiResponse{data=types.ApiRocketpoolValidatorResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/rocketpool/validator/{indexOrPubkey} [get]
+func ApiRocketpoolValidators(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	stats, err := getRocketpoolValidators(queryIndices)
+
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not parse db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), stats)
+}
+
+// ApiRocketpoolNode godoc
+// @Summary Get a rocketpool node operator with the lifecycle of its minipools, its rpl collateral, smoothing pool membership and effective returns
+// @Tags Rocketpool
+// @Param  address path string true "Address of the rocketpool node"
+// @Produce  json
+// @Success 200 {object} types.ApiResponse{data=types.ApiRocketpoolNodeResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/rocketpool/node/{address} [get]
+func ApiRocketpoolNode(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+
+	address := strings.TrimPrefix(strings.ToLower(vars["address"]), "0x")
+	if !utils.IsEth1Address(address) {
+		SendBadRequestResponse(w, r.URL.String(), "invalid address provided")
+		return
+	}
+	addressBytes, err := hex.DecodeString(address)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid address provided")
+		return
+	}
+
+	node, err := db.GetRocketpoolNode(addressBytes)
+	if err != nil {
+		requestLogger(r).WithError(err).Error("can not GetRocketpoolNode")
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if node == nil {
+		SendBadRequestResponse(w, r.URL.String(), "no rocketpool node found for the provided address")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{node})
+}
+
+// ApiPoolsApr godoc
+// @Summary Get the average apr of the staking pools and solo stakers including execution rewards, before and after the fees of the pools
+// @Tags Pools
+// @Param  days query int false "Number of days to average over, defaults to 31, at most 365"
+// @Produce  json
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiPoolAprResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/pools/apr [get]
+func ApiPoolsApr(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+
+	days := parseUintWithDefault(r.URL.Query().Get("days"), 31)
+	if days == 0 || days > 365 {
+		SendBadRequestResponse(w, r.URL.String(), "days must be between 1 and 365")
+		return
+	}
+
+	pools, err := db.GetPoolAprComparison(days)
+	if err != nil {
+		requestLogger(r).WithError(err).Error("can not GetPoolAprComparison")
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{pools})
+}
+
+// ApiPoolsRisk godoc
+// @Summary Get the slashing and correlation risk metrics of the staking pools and other entities
+// @Tags Pools
+// @Description Returns the latest daily risk metrics of every entity: the slashed validators (all time and within the last year), the share of its validators ever slashed,
+// @Description the number of operators (lido node operators, rocketpool nodes, distributed validator clusters or deposit addresses) running its active validators, the operator concentration
+// @Description (square root of the herfindahl index of the operator shares), the correlation penalty (share of the balance lost if its largest operator gets slashed at once) and a risk score between 0 and 100.
+// @Produce  json
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiPoolRiskResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/pools/risk [get]
+func ApiPoolsRisk(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+
+	pools, err := db.GetPoolRisk()
+	if err != nil {
+		requestLogger(r).WithError(err).Error("can not GetPoolRisk")
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{pools})
+}
+
+// ApiLidoOperators godoc
+// @Summary Get the lido node operators with the performance of their validators in the last 31 days, best performing operators first
+// @Tags Lido
+// @Param  module query string false "Staking module of the operators, curated or csm, all modules if omitted"
+// @Produce  json
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiLidoOperatorResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/lido/operators [get]
+func ApiLidoOperators(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+
+	module := r.URL.Query().Get("module")
+	if module != "" && module != lido.ModuleCurated && module != lido.ModuleCSM {
+		SendBadRequestResponse(w, r.URL.String(), "invalid module provided, must be curated or csm")
+		return
+	}
+
+	operators, err := db.GetLidoOperators(module)
+	if err != nil {
+		requestLogger(r).WithError(err).Error("can not GetLidoOperators")
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{operators})
+}
+
+/*
+Combined validator get, performance, attestation efficency, sync committee statistics, epoch, historic epoch and rpl
+Not public documented
+*/
+func ApiDashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		utils.LogErrorContext(r.Context(), err, "reading body", 0)
+		SendBadRequestResponse(w, r.URL.String(), "could not read body")
+		return
+	}
+
+	var getValidators bool = true
+	var parsedBody types.DashboardRequest
+	err = json.Unmarshal(body, &parsedBody)
+	if err != nil {
+		utils.LogErrorContext(r.Context(), err, "unmarshal json body error", 0)
+		getValidators = false
+	}
+
+	maxValidators := getUserPremium(r).MaxValidators
+
+	epoch := services.LatestEpoch()
+
+	g, _ := errgroup.WithContext(context.Background())
+	g.SetLimit(5) // limit concurrency
+	var validatorsData []interface{}
 	var validatorEffectivenessData []*types.ValidatorEffectiveness
 	var rocketpoolData []interface{}
 	var rocketpoolStats []interface{}
@@ -900,7 +1566,7 @@ func ApiDashboard(w http.ResponseWriter, r *http.Request) {
 				validatorsData, err = getGeneralValidatorInfoForAppDashboard(queryIndices)
 				elapsed := time.Since(start)
 				if elapsed > 10*time.Second {
-					logger.Warnf("getGeneralValidatorInfoForAppDashboard(%v) took longer than 10 sec", queryIndices)
+					requestLogger(r).Warnf("getGeneralValidatorInfoForAppDashboard(%v) took longer than 10 sec", queryIndices)
 				}
 				return err
 			})
@@ -911,7 +1577,7 @@ func ApiDashboard(w http.ResponseWriter, r *http.Request) {
 				validatorEffectivenessData, err = getValidatorEffectiveness(epoch-1, queryIndices)
 				elapsed := time.Since(start)
 				if elapsed > 10*time.Second {
-					logger.Warnf("getValidatorEffectiveness(%v, %v) took longer than 10 sec", epoch-1, queryIndices)
+					requestLogger(r).Warnf("getValidatorEffectiveness(%v, %v) took longer than 10 sec", epoch-1, queryIndices)
 				}
 				return err
 			})
@@ -922,7 +1588,7 @@ func ApiDashboard(w http.ResponseWriter, r *http.Request) {
 				rocketpoolData, err = getRocketpoolValidators(queryIndices)
 				elapsed := time.Since(start)
 				if elapsed > 10*time.Second {
-					logger.Warnf("getRocketpoolValidators(%v) took longer than 10 sec", queryIndices)
+					requestLogger(r).Warnf("getRocketpoolValidators(%v) took longer than 10 sec", queryIndices)
 				}
 				return err
 			})
@@ -933,7 +1599,7 @@ func ApiDashboard(w http.ResponseWriter, r *http.Request) {
 				executionPerformance, err = getValidatorExecutionPerformance(queryIndices)
 				elapsed := time.Since(start)
 				if elapsed > 10*time.Second {
-					logger.Warnf("getValidatorExecutionPerformance(%v) took longer than 10 sec", queryIndices)
+					requestLogger(r).Warnf("getValidatorExecutionPerformance(%v) took longer than 10 sec", queryIndices)
 				}
 				return err
 			})
@@ -945,7 +1611,7 @@ func ApiDashboard(w http.ResponseWriter, r *http.Request) {
 				currentSyncCommittee, err = getSyncCommitteeInfoForValidators(queryIndices, period)
 				elapsed := time.Since(start)
 				if elapsed > 10*time.Second {
-					logger.Warnf("getSyncCommitteeInfoForValidators(%v, %v) took longer than 10 sec", queryIndices, period)
+					requestLogger(r).Warnf("getSyncCommitteeInfoForValidators(%v, %v) took longer than 10 sec", queryIndices, period)
 				}
 				return err
 			})
@@ -957,8 +1623,8 @@ func ApiDashboard(w http.ResponseWriter, r *http.Request) {
 				nextSyncCommittee, err = getSyncCommitteeInfoForValidators(queryIndices, period)
 				elapsed := time.Since(start)
 				if elapsed > 10*time.Second {
-					logger.Warnf("SyncPeriodOfEpoch(%v) + 1 took longer than 10 sec", epoch)
-					logger.Warnf("getSyncCommitteeInfoForValidators(%v, %v) took longer than 10 sec", queryIndices, period)
+					requestLogger(r).Warnf("SyncPeriodOfEpoch(%v) + 1 took longer than 10 sec", epoch)
+					requestLogger(r).Warnf("getSyncCommitteeInfoForValidators(%v, %v) took longer than 10 sec", queryIndices, period)
 				}
 				return err
 			})
@@ -969,7 +1635,7 @@ func ApiDashboard(w http.ResponseWriter, r *http.Request) {
 				syncCommitteeStats, err = getSyncCommitteeStatistics(queryIndices, epoch)
 				elapsed := time.Since(start)
 				if elapsed > 10*time.Second {
-					logger.Warnf("getSyncCommitteeStatistics(%v, %v) took longer than 10 sec", queryIndices, epoch)
+					requestLogger(r).Warnf("getSyncCommitteeStatistics(%v, %v) took longer than 10 sec", queryIndices, epoch)
 				}
 				return err
 			})
@@ -980,7 +1646,7 @@ func ApiDashboard(w http.ResponseWriter, r *http.Request) {
 				proposalLuckStats, err = getProposalLuckStats(queryIndices)
 				elapsed := time.Since(start)
 				if elapsed > 10*time.Second {
-					logger.Warnf("getProposalLuck(%v, %v) took longer than 10 sec", queryIndices, epoch)
+					requestLogger(r).Warnf("getProposalLuck(%v, %v) took longer than 10 sec", queryIndices, epoch)
 				}
 				return err
 			})
@@ -993,7 +1659,7 @@ func ApiDashboard(w http.ResponseWriter, r *http.Request) {
 		currentEpochData, err = getEpoch(int64(epoch) - 1)
 		elapsed := time.Since(start)
 		if elapsed > 10*time.Second {
-			logger.Warnf("getEpoch(%v) took longer than 10 sec", int64(epoch)-1)
+			requestLogger(r).Warnf("getEpoch(%v) took longer than 10 sec", int64(epoch)-1)
 		}
 		return err
 	})
@@ -1004,7 +1670,7 @@ func ApiDashboard(w http.ResponseWriter, r *http.Request) {
 		olderEpochData, err = getEpoch(int64(epoch) - 10)
 		elapsed := time.Since(start)
 		if elapsed > 10*time.Second {
-			logger.Warnf("getEpoch(%v) took longer than 10 sec", int64(epoch)-10)
+			requestLogger(r).Warnf("getEpoch(%v) took longer than 10 sec", int64(epoch)-10)
 		}
 		return err
 	})
@@ -1015,14 +1681,14 @@ func ApiDashboard(w http.ResponseWriter, r *http.Request) {
 		rocketpoolStats, err = getRocketpoolStats()
 		elapsed := time.Since(start)
 		if elapsed > 10*time.Second {
-			logger.Warnf("getRocketpoolStats() took longer than 10 sec")
+			requestLogger(r).Warnf("getRocketpoolStats() took longer than 10 sec")
 		}
 		return err
 	})
 
 	err = g.Wait()
 	if err != nil {
-		utils.LogError(err, "dashboard", 0)
+		utils.LogErrorContext(r.Context(), err, "dashboard", 0)
 		SendBadRequestResponse(w, r.URL.String(), err.Error())
 		return
 	}
@@ -1621,7 +2287,7 @@ func getApiValidator(w http.ResponseWriter, r *http.Request) {
 
 	data := make([]*ApiValidatorResponse, 0)
 
-	err = db.ReaderDb.Select(&data, `
+	err = db.ReaderDb.SelectContext(r.Context(), &data, `
 		WITH today AS (
 			SELECT
 				w.validatorindex,
@@ -1662,7 +2328,7 @@ func getApiValidator(w http.ResponseWriter, r *http.Request) {
 		ORDER BY v.validatorindex
 	`, pq.Array(queryIndices), cutoffSlot, lastExportedDay)
 	if err != nil {
-		logger.Warnf("error retrieving validator data from db: %v", err)
+		requestLogger(r).Warnf("error retrieving validator data from db: %v", err)
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
@@ -1708,7 +2374,7 @@ func getApiValidator(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		sendServerErrorResponse(w, r.URL.String(), "could not serialize data results")
-		logger.Errorf("error serializing json data for API %v route: %v", r.URL, err)
+		requestLogger(r).Errorf("error serializing json data for API %v route: %v", r.URL, err)
 	}
 }
 
@@ -1784,7 +2450,11 @@ func ApiValidatorDailyStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rows, err := db.ReaderDb.Query(`
+	var rows *sql.Rows
+	if db.ClickhouseStatisticsReadsEnabled() {
+		rows, err = db.GetValidatorDailyStatsClickhouse(index, startDay, endDay)
+	} else {
+		rows, err = db.ReaderDb.QueryContext(r.Context(), `
 		SELECT 
 		validatorindex,
 		day,
@@ -1809,8 +2479,12 @@ func ApiValidatorDailyStats(w http.ResponseWriter, r *http.Request) {
 		COALESCE(withdrawals_amount, 0) AS withdrawals_amount,
 		COALESCE(participated_sync, 0) AS participated_sync,
 		COALESCE(missed_sync, 0) AS missed_sync,
-		COALESCE(orphaned_sync, 0) AS orphaned_sync
+		COALESCE(orphaned_sync, 0) AS orphaned_sync,
+		attestation_percentile,
+		proposal_luck_percentile,
+		income_percentile
 	FROM validator_stats WHERE validatorindex = $1 and day <= $2 and day >= $3 ORDER BY day DESC`, index, endDay, startDay)
+	}
 	if err != nil {
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
@@ -1874,7 +2548,7 @@ func ApiValidatorByEth1Address(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rows, err := db.ReaderDb.Query("SELECT publickey, validatorindex, valid_signature FROM eth1_deposits LEFT JOIN validators ON eth1_deposits.publickey = validators.pubkey WHERE from_address = $1 GROUP BY publickey, validatorindex, valid_signature ORDER BY validatorindex OFFSET $2 LIMIT $3;", eth1Address, offset, limit)
+	rows, err := db.ReaderDb.QueryContext(r.Context(), "SELECT publickey, validatorindex, valid_signature FROM eth1_deposits LEFT JOIN validators ON eth1_deposits.publickey = validators.pubkey WHERE from_address = $1 GROUP BY publickey, validatorindex, valid_signature ORDER BY validatorindex OFFSET $2 LIMIT $3;", eth1Address, offset, limit)
 	if err != nil {
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
@@ -2059,7 +2733,7 @@ func ApiValidatorWithdrawals(w http.ResponseWriter, r *http.Request) {
 
 	data, err := db.GetValidatorsWithdrawals(queryIndices, endEpoch, epoch)
 	if err != nil {
-		logger.Errorf("error retrieving withdrawals for %v route: %v", r.URL.String(), err)
+		requestLogger(r).Errorf("error retrieving withdrawals for %v route: %v", r.URL.String(), err)
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
@@ -2089,15 +2763,17 @@ func ApiValidatorWithdrawals(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// ApiValidatorBlsChange godoc
-// @Summary Gets the BLS withdrawal address change for up to 100 validators
+// ApiValidatorWithdrawalEstimate godoc
+// @Summary Estimates the next withdrawal of up to 100 validators
 // @Tags Validator
+// @Description Returns the position of the validators in the withdrawal sweep and the estimated time and amount of their next withdrawal at their current balance.
+// @Description The sweep cursor is the index of the validator that was withdrawn last, the amount is given in gwei.
 // @Produce  json
 // @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
-// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorBlsChangeResponse}
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorWithdrawalEstimateResponse}
 // @Failure 400 {object} types.ApiResponse
-// @Router /api/v1/validator/{indexOrPubkey}/blsChange [get]
-func ApiValidatorBlsChange(w http.ResponseWriter, r *http.Request) {
+// @Router /api/v1/validator/{indexOrPubkey}/withdrawal-estimate [get]
+func ApiValidatorWithdrawalEstimate(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	vars := mux.Vars(r)
@@ -2111,68 +2787,102 @@ func ApiValidatorBlsChange(w http.ResponseWriter, r *http.Request) {
 
 	if len(queryIndices) == 0 {
 		SendBadRequestResponse(w, r.URL.String(), "no or invalid validator indicies provided")
+		return
 	}
 
-	data, err := db.GetValidatorsBLSChange(queryIndices)
-	if err != nil {
-		logger.Errorf("error retrieving validators bls change for %v route: %v", r.URL.String(), err)
-		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+	stats := services.GetLatestStats()
+	if stats == nil || stats.LatestValidatorWithdrawalIndex == nil {
+		sendServerErrorResponse(w, r.URL.String(), "withdrawal sweep data not available")
 		return
 	}
+	cursor := *stats.LatestValidatorWithdrawalIndex
+	epoch := services.LatestEpoch()
 
-	dataFormatted := make([]*types.ApiValidatorBlsChangeResponse, 0, len(data))
-
-	for _, d := range data {
-		dataFormatted = append(dataFormatted, &types.ApiValidatorBlsChangeResponse{
-			Epoch:                    d.Slot / utils.Config.Chain.ClConfig.SlotsPerEpoch,
-			Slot:                     d.Slot,
-			BlockRoot:                fmt.Sprintf("0x%x", d.BlockRoot),
-			Validatorindex:           d.Validatorindex,
-			BlsPubkey:                fmt.Sprintf("0x%x", d.BlsPubkey),
-			Address:                  fmt.Sprintf("0x%x", d.Address),
-			Signature:                fmt.Sprintf("0x%x", d.Signature),
-			WithdrawalCredentialsOld: fmt.Sprintf("0x%x", d.WithdrawalCredentialsOld),
-			WithdrawalCredentialsNew: fmt.Sprintf("0x"+utils.BeginningOfSetWithdrawalCredentials+"%x", d.Address),
-		})
+	var validators []*types.Validator
+	err = db.ReaderDb.Select(&validators, `
+		SELECT
+			validatorindex,
+			withdrawalcredentials,
+			withdrawableepoch
+		FROM validators
+		WHERE validatorindex = ANY($1)
+		ORDER BY validatorindex`, pq.Array(queryIndices))
+	if err != nil {
+		requestLogger(r).Errorf("error retrieving validators for %v route: %v", r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
 	}
 
-	response := &types.ApiResponse{}
-	response.Status = "OK"
-
-	response.Data = dataFormatted
+	balances, err := db.BigtableClient.GetValidatorBalanceHistory(queryIndices, epoch, epoch)
+	if err != nil {
+		requestLogger(r).Errorf("error retrieving validator balances for %v route: %v", r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve validator balances")
+		return
+	}
 
-	err = json.NewEncoder(w).Encode(response)
+	lastWithdrawalEpochs, err := db.GetLastWithdrawalEpoch(queryIndices)
 	if err != nil {
-		sendServerErrorResponse(w, r.URL.String(), "could not serialize data results")
+		requestLogger(r).Errorf("error retrieving last withdrawal epochs for %v route: %v", r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
+
+	data := make([]*types.ApiValidatorWithdrawalEstimateResponse, 0, len(validators))
+	for _, v := range validators {
+		res := &types.ApiValidatorWithdrawalEstimateResponse{
+			Validatorindex: v.Index,
+			SweepCursor:    cursor,
+		}
+		data = append(data, res)
+
+		if len(v.WithdrawalCredentials) == 0 || v.WithdrawalCredentials[0] != 0x01 {
+			continue
+		}
+		if balance := balances[v.Index]; len(balance) > 0 {
+			v.Balance = balance[0].Balance
+			v.EffectiveBalance = balance[0].EffectiveBalance
+		}
+
+		estimate, err := estimateNextWithdrawal(v, epoch, cursor, lastWithdrawalEpochs[v.Index])
+		if err != nil {
+			requestLogger(r).Errorf("error estimating next withdrawal for %v route: %v", r.URL.String(), err)
+			sendServerErrorResponse(w, r.URL.String(), "could not estimate next withdrawal")
+			return
+		}
+		if estimate == nil {
+			continue
+		}
+
+		res.Eligible = true
+		res.SweepPosition = estimate.SweepPosition
+		res.EstimatedEpoch = uint64(utils.TimeToEpoch(estimate.Time))
+		res.EstimatedSlot = utils.TimeToSlot(uint64(estimate.Time.Unix()))
+		res.EstimatedTimestamp = estimate.Time.Unix()
+		res.EstimatedDays = math.Max(time.Until(estimate.Time).Hours()/24, 0)
+		res.Amount = estimate.Amount
+		res.FullWithdrawal = estimate.IsFull
+		res.Address = fmt.Sprintf("0x%x", estimate.Address)
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{data})
 }
 
-// ApiValidator godoc
-// @Summary Get the balance history of up to 100 validators
+// ApiValidatorFeeRecipients godoc
+// @Summary Gets the fee recipients of the latest blocks proposed by up to 100 validators
 // @Tags Validator
+// @Description Returns the fee recipient paid by each of the latest 100 blocks proposed by the validators, newest first.
+// @Description For blocks built by a relay the fee recipient the proposer registered with the relay is returned.
 // @Produce  json
 // @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
-// @Param  latest_epoch query int false "The latest epoch to consider in the query"
-// @Param  offset query int false "Number of items to skip"
-// @Param  limit query int false "Maximum number of items to return, up to 100"
-// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorBalanceHistoryResponse}
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorFeeRecipientResponse}
 // @Failure 400 {object} types.ApiResponse
-// @Router /api/v1/validator/{indexOrPubkey}/balancehistory [get]
-func ApiValidatorBalanceHistory(w http.ResponseWriter, r *http.Request) {
-
+// @Router /api/v1/validator/{indexOrPubkey}/fee-recipients [get]
+func ApiValidatorFeeRecipients(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	j := json.NewEncoder(w)
 	vars := mux.Vars(r)
 	maxValidators := getUserPremium(r).MaxValidators
 
-	latestEpoch, limit, err := getBalanceHistoryQueryParameters(r.URL.Query())
-	if err != nil {
-		SendBadRequestResponse(w, r.URL.String(), err.Error())
-		return
-	}
-
 	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
 	if err != nil {
 		SendBadRequestResponse(w, r.URL.String(), err.Error())
@@ -2181,51 +2891,582 @@ func ApiValidatorBalanceHistory(w http.ResponseWriter, r *http.Request) {
 
 	if len(queryIndices) == 0 {
 		SendBadRequestResponse(w, r.URL.String(), "no or invalid validator indicies provided")
+		return
 	}
 
-	history, err := db.BigtableClient.GetValidatorBalanceHistory(queryIndices, latestEpoch-(limit-1), latestEpoch)
+	feeRecipients, err := db.GetValidatorsBlockFeeRecipients(queryIndices, 100)
 	if err != nil {
-		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		requestLogger(r).Errorf("error retrieving block fee recipients for %v route: %v", r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
 
-	responseData := make([]*types.ApiValidatorBalanceHistoryResponse, 0, len(history)*101)
-
-	epochsPerWeek := utils.EpochsPerDay() * 7
-	for validatorIndex, balances := range history {
-		for _, balance := range balances {
-			epochAtStartOfTheWeek := (balance.Epoch / epochsPerWeek) * epochsPerWeek
-			responseData = append(responseData, &types.ApiValidatorBalanceHistoryResponse{
-				Balance:          balance.Balance,
-				EffectiveBalance: balance.EffectiveBalance,
-				Epoch:            balance.Epoch,
-				Validatorindex:   validatorIndex,
-				Week:             balance.Epoch / epochsPerWeek,
-				WeekStart:        utils.EpochToTime(epochAtStartOfTheWeek),
-				WeekEnd:          utils.EpochToTime(epochAtStartOfTheWeek + epochsPerWeek),
-			})
-		}
+	data := make([]*types.ApiValidatorFeeRecipientResponse, 0, len(feeRecipients))
+	for _, feeRecipient := range feeRecipients {
+		data = append(data, &types.ApiValidatorFeeRecipientResponse{
+			Validatorindex:  feeRecipient.Proposer,
+			Slot:            feeRecipient.Slot,
+			ExecBlockNumber: feeRecipient.ExecBlockNumber,
+			FeeRecipient:    common.BytesToAddress(feeRecipient.FeeRecipient).Hex(),
+			Relayed:         feeRecipient.Relayed,
+		})
 	}
 
-	sort.Slice(responseData, func(i, j int) bool {
-		if responseData[i].Epoch != responseData[j].Epoch {
-			return responseData[i].Epoch > responseData[j].Epoch
-		}
-		return responseData[i].Validatorindex < responseData[j].Validatorindex
-	})
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{data})
+}
 
-	response := &types.ApiResponse{}
-	response.Status = "OK"
+// maxLiveRelayRegistrationChecks limits the validators whose relay registrations are checked during a single request
+const maxLiveRelayRegistrationChecks = 10
 
-	response.Data = responseData
+// ApiValidatorRelayRegistrations godoc
+// @Summary Gets the relay registrations of up to 100 validators
+// @Tags Validator
+// @Description Returns the relays the validators are registered with together with the registered fee recipient and gas limit.
+// @Description Registrations that have not been checked within the last hour are checked at the relays, at most 10 validators per request.
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorRelayRegistrationResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/relay-registrations [get]
+func ApiValidatorRelayRegistrations(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
 
-	err = j.Encode(response)
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
 
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
 	if err != nil {
-		sendServerErrorResponse(w, r.URL.String(), "could not serialize data results")
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
 		return
 	}
-}
+
+	if len(queryIndices) == 0 {
+		SendBadRequestResponse(w, r.URL.String(), "no or invalid validator indicies provided")
+		return
+	}
+
+	validators := []struct {
+		Index  uint64 `db:"validatorindex"`
+		Pubkey []byte `db:"pubkey"`
+	}{}
+	err = db.ReaderDb.Select(&validators, `SELECT validatorindex, pubkey FROM validators WHERE validatorindex = ANY($1) ORDER BY validatorindex`, pq.Array(queryIndices))
+	if err != nil {
+		requestLogger(r).Errorf("error retrieving validators for %v route: %v", r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	pubkeys := make([][]byte, 0, len(validators))
+	indices := make(map[string]uint64, len(validators))
+	for _, v := range validators {
+		pubkeys = append(pubkeys, v.Pubkey)
+		indices[string(v.Pubkey)] = v.Index
+	}
+
+	err = checkStaleRelayRegistrations(pubkeys, maxLiveRelayRegistrationChecks)
+	if err != nil {
+		requestLogger(r).Errorf("error checking relay registrations for %v route: %v", r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not check relay registrations")
+		return
+	}
+
+	registrations, err := db.GetRelayValidatorRegistrations(pubkeys)
+	if err != nil {
+		requestLogger(r).Errorf("error retrieving relay registrations for %v route: %v", r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	data := make([]*types.ApiValidatorRelayRegistrationResponse, 0, len(registrations))
+	for _, registration := range registrations {
+		res := &types.ApiValidatorRelayRegistrationResponse{
+			Validatorindex: indices[string(registration.Pubkey)],
+			Pubkey:         fmt.Sprintf("0x%x", registration.Pubkey),
+			Relay:          registration.RelayID,
+			Registered:     registration.Registered,
+			GasLimit:       registration.GasLimit.Int64,
+			CheckedTs:      registration.CheckedTs.Unix(),
+		}
+		if len(registration.FeeRecipient) > 0 {
+			res.FeeRecipient = common.BytesToAddress(registration.FeeRecipient).Hex()
+		}
+		if registration.RegistrationTs.Valid {
+			res.RegistrationTs = registration.RegistrationTs.Time.Unix()
+		}
+		if registration.UnregisteredTs.Valid {
+			res.UnregisteredTs = registration.UnregisteredTs.Time.Unix()
+		}
+		data = append(data, res)
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{data})
+}
+
+// ApiValidatorExitReadiness godoc
+// @Summary Checks whether up to 100 validators are ready to exit
+// @Tags Validator
+// @Description Returns everything to consider before submitting a voluntary exit: the credential type, scheduled block proposals, sync committee membership,
+// @Description the estimated exit and withdrawable epochs at the current exit queue and the consensus rewards expected until the exit. Amounts are given in gwei.
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorExitReadinessResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/exit-readiness [get]
+func ApiValidatorExitReadiness(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	if len(queryIndices) == 0 {
+		SendBadRequestResponse(w, r.URL.String(), "no or invalid validator indicies provided")
+		return
+	}
+
+	epoch := services.LatestEpoch()
+
+	var validators []*types.Validator
+	err = db.ReaderDb.Select(&validators, `
+		SELECT
+			validatorindex,
+			withdrawalcredentials,
+			slashed,
+			activationepoch,
+			exitepoch,
+			withdrawableepoch
+		FROM validators
+		WHERE validatorindex = ANY($1)
+		ORDER BY validatorindex`, pq.Array(queryIndices))
+	if err != nil {
+		requestLogger(r).Errorf("error retrieving validators for %v route: %v", r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	balances, err := db.BigtableClient.GetValidatorBalanceHistory(queryIndices, epoch, epoch)
+	if err != nil {
+		requestLogger(r).Errorf("error retrieving validator balances for %v route: %v", r.URL.String(), err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve validator balances")
+		return
+	}
+
+	data := make([]*types.ApiValidatorExitReadinessResponse, 0, len(validators))
+	for _, v := range validators {
+		if balance := balances[v.Index]; len(balance) > 0 {
+			v.Balance = balance[0].Balance
+			v.EffectiveBalance = balance[0].EffectiveBalance
+		}
+
+		readiness, err := getExitReadiness(v, epoch)
+		if err != nil {
+			requestLogger(r).Errorf("error checking exit readiness for %v route: %v", r.URL.String(), err)
+			sendServerErrorResponse(w, r.URL.String(), "could not check exit readiness")
+			return
+		}
+
+		res := &types.ApiValidatorExitReadinessResponse{
+			Validatorindex:        readiness.ValidatorIndex,
+			CredentialType:        readiness.CredentialType,
+			ExitInitiated:         readiness.ExitInitiated,
+			CanExit:               readiness.CanExit,
+			Blockers:              readiness.Blockers,
+			Warnings:              readiness.Warnings,
+			EligibleEpoch:         readiness.EligibleEpoch,
+			ScheduledProposals:    readiness.ScheduledProposals,
+			SyncCommitteePeriods:  readiness.SyncCommitteePeriods,
+			SyncCommitteeEnd:      readiness.SyncCommitteeEnd,
+			ExitQueueLength:       readiness.ExitQueueLength,
+			ExitEpoch:             readiness.ExitEpoch,
+			ExitTimestamp:         readiness.ExitTime.Unix(),
+			WithdrawableEpoch:     readiness.WithdrawableEpoch,
+			WithdrawableTimestamp: readiness.WithdrawableTime.Unix(),
+			Balance:               readiness.Balance,
+			RewardsPerDay:         readiness.RewardsPerDay,
+			RewardsUntilExit:      readiness.RewardsUntilExit,
+		}
+		if !readiness.FullWithdrawalTime.IsZero() {
+			res.FullWithdrawalTimestamp = readiness.FullWithdrawalTime.Unix()
+		}
+		data = append(data, res)
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{data})
+}
+
+// ApiValidatorCredentialChanges godoc
+// @Summary Gets the history of withdrawal credential changes for up to 100 validators
+// @Tags Validator
+// @Description Returns the BLS to execution changes of the validators with the inclusion time, the signature and the credentials before and after the change, newest first.
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorCredentialChangeResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/credential-changes [get]
+func ApiValidatorCredentialChanges(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	if len(queryIndices) == 0 {
+		SendBadRequestResponse(w, r.URL.String(), "no or invalid validator indicies provided")
+		return
+	}
+
+	data, err := db.GetValidatorsBLSChange(queryIndices)
+	if err != nil {
+		requestLogger(r).Errorf("error retrieving validators credential changes for %v route: %v", r.URL.String(), err)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	latestFinalizedEpoch := services.LatestFinalizedEpoch()
+	dataFormatted := make([]*types.ApiValidatorCredentialChangeResponse, 0, len(data))
+	for _, d := range data {
+		epoch := utils.EpochOfSlot(d.Slot)
+		dataFormatted = append(dataFormatted, &types.ApiValidatorCredentialChangeResponse{
+			Validatorindex:           d.Validatorindex,
+			Epoch:                    epoch,
+			Slot:                     d.Slot,
+			Timestamp:                utils.SlotToTime(d.Slot).Unix(),
+			BlockRoot:                fmt.Sprintf("0x%x", d.BlockRoot),
+			Finalized:                epoch <= latestFinalizedEpoch,
+			BlsPubkey:                fmt.Sprintf("0x%x", d.BlsPubkey),
+			Signature:                fmt.Sprintf("0x%x", d.Signature),
+			Address:                  fmt.Sprintf("0x%x", d.Address),
+			WithdrawalCredentialsOld: fmt.Sprintf("0x%x", d.WithdrawalCredentialsOld),
+			WithdrawalCredentialsNew: fmt.Sprintf("0x"+utils.BeginningOfSetWithdrawalCredentials+"%x", d.Address),
+		})
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{dataFormatted})
+}
+
+// ApiValidatorBlsChange godoc
+// @Summary Gets the BLS withdrawal address change for up to 100 validators
+// @Tags Validator
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorBlsChangeResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/blsChange [get]
+func ApiValidatorBlsChange(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	if len(queryIndices) == 0 {
+		SendBadRequestResponse(w, r.URL.String(), "no or invalid validator indicies provided")
+	}
+
+	data, err := db.GetValidatorsBLSChange(queryIndices)
+	if err != nil {
+		requestLogger(r).Errorf("error retrieving validators bls change for %v route: %v", r.URL.String(), err)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	dataFormatted := make([]*types.ApiValidatorBlsChangeResponse, 0, len(data))
+
+	for _, d := range data {
+		dataFormatted = append(dataFormatted, &types.ApiValidatorBlsChangeResponse{
+			Epoch:                    d.Slot / utils.Config.Chain.ClConfig.SlotsPerEpoch,
+			Slot:                     d.Slot,
+			BlockRoot:                fmt.Sprintf("0x%x", d.BlockRoot),
+			Validatorindex:           d.Validatorindex,
+			BlsPubkey:                fmt.Sprintf("0x%x", d.BlsPubkey),
+			Address:                  fmt.Sprintf("0x%x", d.Address),
+			Signature:                fmt.Sprintf("0x%x", d.Signature),
+			WithdrawalCredentialsOld: fmt.Sprintf("0x%x", d.WithdrawalCredentialsOld),
+			WithdrawalCredentialsNew: fmt.Sprintf("0x"+utils.BeginningOfSetWithdrawalCredentials+"%x", d.Address),
+		})
+	}
+
+	response := &types.ApiResponse{}
+	response.Status = "OK"
+
+	response.Data = dataFormatted
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sendServerErrorResponse(w, r.URL.String(), "could not serialize data results")
+		return
+	}
+}
+
+// ApiValidator godoc
+// @Summary Get the balance history of up to 100 validators
+// @Tags Validator
+// @Description Without from_epoch and to_epoch the balances of the latest epochs are returned one item per validator and epoch.
+// @Description If from_epoch or to_epoch is set the per-epoch balances of the range are streamed delta encoded instead, see types.ApiValidatorBalanceDeltasResponse. The range may contain at most 1000000 points over all validators, use step to downsample longer ranges. Independent of the step the range may span at most 5000000 epochs over all validators.
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Param  latest_epoch query int false "The latest epoch to consider in the query"
+// @Param  offset query int false "Number of items to skip"
+// @Param  limit query int false "Maximum number of items to return, up to 100"
+// @Param  from_epoch query int false "First epoch of the delta encoded range, defaults to 0"
+// @Param  to_epoch query int false "Last epoch of the delta encoded range, defaults to the latest epoch"
+// @Param  step query int false "Only return every step-th epoch of the delta encoded range, defaults to 1"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorBalanceHistoryResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/balancehistory [get]
+func ApiValidatorBalanceHistory(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if q := r.URL.Query(); q.Has("from_epoch") || q.Has("to_epoch") {
+		apiValidatorBalanceHistoryDeltas(w, r)
+		return
+	}
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	latestEpoch, limit, err := getBalanceHistoryQueryParameters(r.URL.Query())
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	if len(queryIndices) == 0 {
+		SendBadRequestResponse(w, r.URL.String(), "no or invalid validator indicies provided")
+	}
+
+	history, err := db.BigtableClient.GetValidatorBalanceHistory(queryIndices, latestEpoch-(limit-1), latestEpoch)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	responseData := make([]*types.ApiValidatorBalanceHistoryResponse, 0, len(history)*101)
+
+	epochsPerWeek := utils.EpochsPerDay() * 7
+	for validatorIndex, balances := range history {
+		for _, balance := range balances {
+			epochAtStartOfTheWeek := (balance.Epoch / epochsPerWeek) * epochsPerWeek
+			responseData = append(responseData, &types.ApiValidatorBalanceHistoryResponse{
+				Balance:          balance.Balance,
+				EffectiveBalance: balance.EffectiveBalance,
+				Epoch:            balance.Epoch,
+				Validatorindex:   validatorIndex,
+				Week:             balance.Epoch / epochsPerWeek,
+				WeekStart:        utils.EpochToTime(epochAtStartOfTheWeek),
+				WeekEnd:          utils.EpochToTime(epochAtStartOfTheWeek + epochsPerWeek),
+			})
+		}
+	}
+
+	sort.Slice(responseData, func(i, j int) bool {
+		if responseData[i].Epoch != responseData[j].Epoch {
+			return responseData[i].Epoch > responseData[j].Epoch
+		}
+		return responseData[i].Validatorindex < responseData[j].Validatorindex
+	})
+
+	response := &types.ApiResponse{}
+	response.Status = "OK"
+
+	response.Data = responseData
+
+	err = j.Encode(response)
+
+	if err != nil {
+		sendServerErrorResponse(w, r.URL.String(), "could not serialize data results")
+		return
+	}
+}
+
+const (
+	// maxBalanceHistoryDeltaPoints is the maximum amount of balances over all validators of a delta encoded response
+	maxBalanceHistoryDeltaPoints = 1000000
+	// maxBalanceHistoryDeltaReads is the maximum amount of balances over all validators read from bigtable for a delta
+	// encoded response, all epochs of the range are read regardless of the step
+	maxBalanceHistoryDeltaReads = 5000000
+	// balanceHistoryDeltaChunkEpochs is the amount of epochs read from bigtable at once while streaming
+	balanceHistoryDeltaChunkEpochs = 5000
+)
+
+// apiValidatorBalanceHistoryDeltas streams the balances of the validators between from_epoch and to_epoch. Every validator
+// starts with its balance at the first epoch of the range it has a balance at, followed by the difference of each next
+// step to the previous one. Effective balances rarely change and are returned as list of changes instead.
+func apiValidatorBalanceHistoryDeltas(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	queryIndices, err := parseApiValidatorParamToIndices(mux.Vars(r)["indexOrPubkey"], getUserPremium(r).MaxValidators)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	if len(queryIndices) == 0 {
+		SendBadRequestResponse(w, r.URL.String(), "no or invalid validator indicies provided")
+		return
+	}
+	queryIndices = utils.SortedUniqueUint64(queryIndices)
+
+	latestEpoch := services.LatestEpoch()
+	toEpoch := latestEpoch
+	if q.Has("to_epoch") {
+		toEpoch, err = strconv.ParseUint(q.Get("to_epoch"), 10, 64)
+		if err != nil || toEpoch > latestEpoch {
+			SendBadRequestResponse(w, r.URL.String(), "invalid to_epoch parameter")
+			return
+		}
+	}
+	fromEpoch := uint64(0)
+	if q.Has("from_epoch") {
+		fromEpoch, err = strconv.ParseUint(q.Get("from_epoch"), 10, 64)
+		if err != nil || fromEpoch > toEpoch {
+			SendBadRequestResponse(w, r.URL.String(), "invalid from_epoch parameter")
+			return
+		}
+	}
+	step := parseUintWithDefault(q.Get("step"), 1)
+	if step == 0 {
+		SendBadRequestResponse(w, r.URL.String(), "invalid step parameter")
+		return
+	}
+	points := ((toEpoch-fromEpoch)/step + 1) * uint64(len(queryIndices))
+	if points > maxBalanceHistoryDeltaPoints {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("the requested range contains %d balances but at most %d are allowed, increase the step or reduce the range", points, maxBalanceHistoryDeltaPoints))
+		return
+	}
+	reads := (toEpoch - fromEpoch + 1) * uint64(len(queryIndices))
+	if reads > maxBalanceHistoryDeltaReads {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("the requested range spans %d balances but at most %d are allowed, reduce the range or the amount of validators", reads, maxBalanceHistoryDeltaReads))
+		return
+	}
+
+	bw := bufio.NewWriter(w)
+	flusher, _ := w.(http.Flusher)
+	started := false
+	validators := 0
+	for _, index := range queryIndices {
+		encoder := &balanceDeltaEncoder{w: bw, index: index, fromEpoch: fromEpoch, step: step}
+		err = db.BigtableClient.StreamValidatorBalanceHistory([]uint64{index}, fromEpoch, toEpoch, balanceHistoryDeltaChunkEpochs, func(history map[uint64][]*types.ValidatorBalance) error {
+			for _, balance := range history[index] {
+				if (balance.Epoch-fromEpoch)%step != 0 {
+					continue
+				}
+				if !started {
+					// the response is started lazily so errors of the first chunk can still be returned as such
+					bw.WriteString(`{"status":"OK","data":[`)
+					started = true
+				}
+				if encoder.samples == 0 && validators > 0 {
+					bw.WriteString(",")
+				}
+				encoder.add(balance)
+			}
+			if started {
+				if err := bw.Flush(); err != nil {
+					return err
+				}
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			if !started {
+				SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+			}
+			// once the response is started it can only be aborted, the client receives incomplete json
+			requestLogger(r).WithError(err).Error("error streaming validator balance history")
+			return
+		}
+		if encoder.samples > 0 {
+			encoder.finish()
+			validators++
+		}
+	}
+
+	if !started {
+		bw.WriteString(`{"status":"OK","data":[`)
+	}
+	bw.WriteString("]}\n")
+	err = bw.Flush()
+	if err != nil {
+		requestLogger(r).WithError(err).Error("error writing validator balance history")
+	}
+}
+
+// balanceDeltaEncoder writes the delta encoded balances of a validator as types.ApiValidatorBalanceDeltasResponse
+type balanceDeltaEncoder struct {
+	w                *bufio.Writer
+	index            uint64
+	fromEpoch        uint64
+	step             uint64
+	samples          uint64
+	nextEpoch        uint64
+	balance          uint64
+	effectiveBalance uint64
+	effectiveChanges [][2]uint64
+}
+
+func (e *balanceDeltaEncoder) add(balance *types.ValidatorBalance) {
+	if e.samples == 0 {
+		fmt.Fprintf(e.w, `{"validatorindex":%d,"from_epoch":%d,"step":%d,"balance":%d,"effective_balance":%d,"balance_deltas":[`, e.index, balance.Epoch, e.step, balance.Balance, balance.EffectiveBalance)
+		e.samples = 1
+		e.nextEpoch = balance.Epoch + e.step
+		e.balance = balance.Balance
+		e.effectiveBalance = balance.EffectiveBalance
+		return
+	}
+
+	// epochs without a balance keep the previous balance so the epoch of every delta stays implicit
+	for ; e.nextEpoch < balance.Epoch; e.nextEpoch += e.step {
+		e.writeDelta(0)
+	}
+	e.writeDelta(int64(balance.Balance) - int64(e.balance))
+	e.nextEpoch = balance.Epoch + e.step
+	e.balance = balance.Balance
+	if balance.EffectiveBalance != e.effectiveBalance {
+		e.effectiveChanges = append(e.effectiveChanges, [2]uint64{e.samples - 1, balance.EffectiveBalance})
+		e.effectiveBalance = balance.EffectiveBalance
+	}
+}
+
+func (e *balanceDeltaEncoder) writeDelta(delta int64) {
+	if e.samples > 1 {
+		e.w.WriteString(",")
+	}
+	e.w.WriteString(strconv.FormatInt(delta, 10))
+	e.samples++
+}
+
+func (e *balanceDeltaEncoder) finish() {
+	changes, _ := json.Marshal(e.effectiveChanges)
+	if e.effectiveChanges == nil {
+		changes = []byte("[]")
+	}
+	fmt.Fprintf(e.w, `],"effective_balance_changes":%s}`, changes)
+}
 
 func getBalanceHistoryQueryParameters(q url.Values) (uint64, uint64, error) {
 	onChainLatestEpoch := services.LatestEpoch()
@@ -2281,7 +3522,7 @@ func ApiValidatorPerformance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rows, err := db.ReaderDb.Query(`
+	rows, err := db.ReaderDb.QueryContext(r.Context(), `
 	SELECT 
 		validators.validatorindex, 
 		COALESCE(validator_performance.cl_performance_1d, 0) AS performance1d, 
@@ -2325,13 +3566,13 @@ func ApiValidatorPerformance(w http.ResponseWriter, r *http.Request) {
 	for _, entry := range data {
 		eMap, ok := entry.(map[string]interface{})
 		if !ok {
-			logger.Errorf("error converting validator data to map[string]interface{}")
+			requestLogger(r).Errorf("error converting validator data to map[string]interface{}")
 			continue
 		}
 
 		validatorIndex, ok := eMap["validatorindex"].(int64)
 		if !ok {
-			logger.Errorf("error converting validatorindex to int64")
+			requestLogger(r).Errorf("error converting validatorindex to int64")
 			continue
 		}
 
@@ -2353,13 +3594,13 @@ func ApiValidatorPerformance(w http.ResponseWriter, r *http.Request) {
 	for _, entry := range data {
 		eMap, ok := entry.(map[string]interface{})
 		if !ok {
-			logger.Errorf("error converting validator data to map[string]interface{}")
+			requestLogger(r).Errorf("error converting validator data to map[string]interface{}")
 			continue
 		}
 
 		validatorIndex, ok := eMap["validatorindex"].(int64)
 		if !ok {
-			logger.Errorf("error converting validatorindex to int64")
+			requestLogger(r).Errorf("error converting validatorindex to int64")
 			continue
 		}
 
@@ -2396,10 +3637,98 @@ func ApiValidatorExecutionPerformance(w http.ResponseWriter, r *http.Request) {
 	result, err := getValidatorExecutionPerformance(queryIndices)
 	if err != nil {
 		SendBadRequestResponse(w, r.URL.String(), err.Error())
-		logger.WithError(err).Error("can not getValidatorExecutionPerformance")
+		requestLogger(r).WithError(err).Error("can not getValidatorExecutionPerformance")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []any{result})
+}
+
+// ApiValidatorMevIncome godoc
+// @Summary Get the mev income and relay usage of up to 100 validators. The income is aggregated from the relay payloads of all fully exported days.
+// @Tags Validator
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=[]types.ValidatorMevIncome}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/mev [get]
+func ApiValidatorMevIncome(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	incomes, err := db.GetValidatorsMevIncome(queryIndices)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		requestLogger(r).WithError(err).Error("can not GetValidatorsMevIncome")
+		return
+	}
+
+	result := make([]*types.ValidatorMevIncome, 0, len(queryIndices))
+	for _, index := range queryIndices {
+		income, ok := incomes[index]
+		if !ok {
+			income = &types.ValidatorMevIncome{
+				ValidatorIndex: index,
+				Relays:         []*types.ValidatorMevIncomeRelay{},
+			}
+		}
+		result = append(result, income)
+	}
+
+	SendOKResponse(j, r.URL.String(), []any{result})
+}
+
+// ApiValidatorLuck godoc
+// @Summary Get the proposal and sync committee luck of up to 100 validators
+// @Tags Validator
+// @Description Compares the actual proposal and sync committee assignments of the validators with the amount expected from their share of the effective balance of all active validators.
+// @Description A luck of 1 means exactly as many assignments as expected. The daily history is included to distinguish bad luck from bad performance.
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Param  days query int false "Amount of the last exported days to take into account, defaults to 365"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorLuckResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/luck [get]
+func ApiValidatorLuck(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	days := parseUintWithDefault(r.URL.Query().Get("days"), 365)
+	if days == 0 {
+		SendBadRequestResponse(w, r.URL.String(), "invalid days provided")
+		return
+	}
+
+	luck, err := db.GetValidatorsLuck(queryIndices, days)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		requestLogger(r).WithError(err).Error("can not GetValidatorsLuck")
 		return
 	}
 
+	result := make([]*types.ApiValidatorLuckResponse, 0, len(queryIndices))
+	for _, index := range queryIndices {
+		result = append(result, luck[index])
+	}
+
 	SendOKResponse(j, r.URL.String(), []any{result})
 }
 
@@ -2445,21 +3774,64 @@ func ApiValidatorAttestationEffectiveness(w http.ResponseWriter, r *http.Request
 	}
 }
 
-// ApiValidatorAttestationEfficiency godoc
-// @Summary Get the current performance of up to 100 validators
+// ApiValidatorAttestationEfficiency godoc
+// @Summary Get the current performance of up to 100 validators
+// @Tags Validator
+// @Description Only covers the attestation inclusion distance of the last 100 epochs, use /effectiveness-score for the daily effectiveness score and its components.
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/attestationefficiency [get]
+func ApiValidatorAttestationEfficiency(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+
+	maxValidators := getUserPremium(r).MaxValidators
+
+	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	data, err := getValidatorEffectiveness(services.LatestEpoch()-1, queryIndices)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	response := &types.ApiResponse{}
+	response.Status = "OK"
+
+	response.Data = data
+
+	err = j.Encode(response)
+
+	if err != nil {
+		sendServerErrorResponse(w, r.URL.String(), "could not serialize data results")
+		return
+	}
+}
+
+// ApiValidatorEffectivenessScore godoc
+// @Summary Get the daily effectiveness scores of up to 100 validators
 // @Tags Validator
+// @Description Returns the effectiveness score (0-100) of the validators for the most recently exported days together with its components.
+// @Description Every component is a ratio between 0 and 1, components the validator had no duties for during the day are null. See /api/v1/effectiveness/formula for how the score is calculated.
 // @Produce  json
 // @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
-// @Success 200 {object} types.ApiResponse
+// @Param  days query int false "Number of days, defaults to 1, maximum 31"
+// @Success 200 {object} types.ApiResponse{data=[]types.ValidatorEffectivenessScore}
 // @Failure 400 {object} types.ApiResponse
-// @Router /api/v1/validator/{indexOrPubkey}/attestationefficiency [get]
-func ApiValidatorAttestationEfficiency(w http.ResponseWriter, r *http.Request) {
-
+// @Router /api/v1/validator/{indexOrPubkey}/effectiveness-score [get]
+func ApiValidatorEffectivenessScore(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	j := json.NewEncoder(w)
 	vars := mux.Vars(r)
-
 	maxValidators := getUserPremium(r).MaxValidators
 
 	queryIndices, err := parseApiValidatorParamToIndices(vars["indexOrPubkey"], maxValidators)
@@ -2468,23 +3840,44 @@ func ApiValidatorAttestationEfficiency(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	data, err := getValidatorEffectiveness(services.LatestEpoch()-1, queryIndices)
-	if err != nil {
-		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+	if len(queryIndices) == 0 {
+		SendBadRequestResponse(w, r.URL.String(), "no or invalid validator indicies provided")
 		return
 	}
 
-	response := &types.ApiResponse{}
-	response.Status = "OK"
-
-	response.Data = data
-
-	err = j.Encode(response)
+	days := parseUintWithDefault(r.URL.Query().Get("days"), 1)
+	if days == 0 || days > 31 {
+		SendBadRequestResponse(w, r.URL.String(), "days must be between 1 and 31")
+		return
+	}
 
+	scores, err := db.GetValidatorEffectivenessScoreHistory(queryIndices, days)
 	if err != nil {
-		sendServerErrorResponse(w, r.URL.String(), "could not serialize data results")
+		requestLogger(r).WithError(err).Errorf("error retrieving effectiveness scores for %v route", r.URL.String())
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{scores})
+}
+
+// ApiEffectivenessScoreFormula godoc
+// @Summary Get the formula of the validator effectiveness score
+// @Tags Validator
+// @Description Returns the version, the component weights and a description of the formula the effectiveness scores are currently calculated with.
+// @Produce  json
+// @Success 200 {object} types.ApiResponse{data=types.ApiEffectivenessScoreFormulaResponse}
+// @Router /api/v1/effectiveness/formula [get]
+func ApiEffectivenessScoreFormula(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	data := &types.ApiEffectivenessScoreFormulaResponse{
+		Version:     utils.EffectivenessScoreVersion,
+		Weights:     utils.EffectivenessScoreWeights,
+		Description: utils.EffectivenessScoreFormula,
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{data})
 }
 
 // func getAttestationEfficiencyQuery(epoch int64, queryIndices []uint64) (*sql.Rows, error) {
@@ -2516,7 +3909,7 @@ func ApiValidatorLeaderboard(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 
-	rows, err := db.ReaderDb.Query(`
+	rows, err := db.ReaderDb.QueryContext(r.Context(), `
 			SELECT 
 				balance, 
 				COALESCE(validator_performance.cl_performance_1d, 0) AS performance1d, 
@@ -2557,12 +3950,12 @@ func ApiValidatorDeposits(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rows, err := db.ReaderDb.Query(
+	rows, err := db.ReaderDb.QueryContext(r.Context(),
 		`SELECT amount, block_number, block_ts, from_address, merkletree_index, publickey, removed, signature, tx_hash, tx_index, tx_input, valid_signature, withdrawal_credentials FROM eth1_deposits 
 		WHERE publickey = ANY($1)`, pubkeys,
 	)
 	if err != nil {
-		logger.WithError(err).Error("could not retrieve db results")
+		requestLogger(r).WithError(err).Error("could not retrieve db results")
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
@@ -2571,6 +3964,60 @@ func ApiValidatorDeposits(w http.ResponseWriter, r *http.Request) {
 	returnQueryResultsAsArray(rows, w, r)
 }
 
+// ApiValidatorDepositOrigin godoc
+// @Summary Get the funding origin of the deposit address for up to 100 validators
+// @Tags Validator
+// @Description Returns where the address of the first valid deposit of each validator got its funds from, found by following the largest incoming transfer back a few hops until a labeled exchange, custodian, bridge, mixer or smart contract is reached. The origin_kind is unknown if no such source was found and traced is false if the deposit address has not been traced yet.
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiValidatorDepositOriginResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/deposit-origin [get]
+func ApiValidatorDepositOrigin(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	maxValidators := getUserPremium(r).MaxValidators
+
+	pubkeys, err := parseApiValidatorParamToPubkeys(vars["indexOrPubkey"], maxValidators)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	origins, err := db.GetValidatorDepositOrigins(pubkeys)
+	if err != nil {
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	data := make([]interface{}, 0, len(origins))
+	for _, o := range origins {
+		res := &types.ApiValidatorDepositOriginResponse{
+			ValidatorIndex: o.ValidatorIndex,
+			PublicKey:      fmt.Sprintf("%#x", o.PublicKey),
+			DepositAddress: fmt.Sprintf("%#x", o.DepositAddress),
+			Path:           []string{},
+		}
+		if o.Origin != nil {
+			res.Traced = true
+			res.OriginKind = o.Origin.Kind
+			res.Hops = o.Origin.Hops
+			res.ExchangeFunded = o.Origin.Kind == types.DepositOriginExchange
+			if len(o.Origin.OriginAddress) > 0 {
+				res.OriginAddress = fmt.Sprintf("%#x", o.Origin.OriginAddress)
+			}
+			res.OriginLabel = o.Origin.OriginLabel
+			for _, a := range o.Origin.Path {
+				res.Path = append(res.Path, fmt.Sprintf("%#x", a))
+			}
+		}
+		data = append(data, res)
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), data)
+}
+
 // ApiValidatorAttestations godoc
 // @Summary Get all attestations during the last 100 epochs for up to 100 validators
 // @Tags Validator
@@ -2677,7 +4124,7 @@ func ApiValidatorProposals(w http.ResponseWriter, r *http.Request) {
 		epochQuery = 100
 	}
 
-	rows, err := db.ReaderDb.Query(`
+	rows, err := db.ReaderDb.QueryContext(r.Context(), `
 	SELECT 
 		b.epoch,
 		b.slot,
@@ -2720,7 +4167,7 @@ func ApiValidatorProposals(w http.ResponseWriter, r *http.Request) {
 	WHERE (proposer = ANY($1)) and epoch <= $2 AND epoch >= $3 
 	ORDER BY proposer, epoch desc, slot desc`, pq.Array(queryIndices), epochQuery, epochQuery-100)
 	if err != nil {
-		logger.Errorf("could not retrieve db results: %v", err)
+		requestLogger(r).Errorf("could not retrieve db results: %v", err)
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
@@ -2815,7 +4262,7 @@ func ApiGraffitiwall(w http.ResponseWriter, r *http.Request) {
 		summarize_query = "DISTINCT ON (x, y) "
 	}
 
-	rows, err := db.ReaderDb.Query(`
+	rows, err := db.ReaderDb.QueryContext(r.Context(), `
 	SELECT `+summarize_query+`
 		x,
 		y,
@@ -2827,7 +4274,7 @@ func ApiGraffitiwall(w http.ResponseWriter, r *http.Request) {
 	ORDER BY x, y, slot DESC`, startSlot, endSlot, startX, endX, startY, endY)
 	if err != nil {
 		if !errors.Is(err, sql.ErrNoRows) {
-			logger.WithError(err).Error("could not retrieve db results")
+			requestLogger(r).WithError(err).Error("could not retrieve db results")
 		}
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
@@ -2852,7 +4299,7 @@ func ApiChart(w http.ResponseWriter, r *http.Request) {
 	chartName := vars["chart"]
 
 	var image []byte
-	err := db.ReaderDb.Get(&image, "SELECT image FROM chart_images WHERE name = $1", chartName)
+	err := db.ReaderDb.GetContext(r.Context(), &image, "SELECT image FROM chart_images WHERE name = $1", chartName)
 	if err != nil {
 		SendBadRequestResponse(w, r.URL.String(), "no data available for the requested chart")
 		return
@@ -2867,6 +4314,128 @@ func ApiChart(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ApiNetworkIncidents godoc
+// @Summary Get the network incidents detected within a range of epochs
+// @Tags Network
+// @Description Incidents are periods in which the participation rate dropped, or the missed slot rate or the average attestation inclusion delay rose, significantly
+// @Description compared to their exponentially weighted moving average. Rates are in percent and the inclusion delay in slots.
+// @Produce  json
+// @Param  from_epoch query int false "First epoch of the range, defaults to one week before the end"
+// @Param  to_epoch query int false "Last epoch of the range, defaults to the latest epoch"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiNetworkIncidentResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/network/incidents [get]
+func ApiNetworkIncidents(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	q := r.URL.Query()
+
+	toEpoch := parseUintWithDefault(q.Get("to_epoch"), services.LatestEpoch())
+	fromEpoch := uint64(0)
+	if toEpoch > utils.EpochsPerDay()*7 {
+		fromEpoch = toEpoch - utils.EpochsPerDay()*7
+	}
+	fromEpoch = parseUintWithDefault(q.Get("from_epoch"), fromEpoch)
+	if fromEpoch > toEpoch {
+		SendBadRequestResponse(w, r.URL.String(), "from_epoch must not be after to_epoch")
+		return
+	}
+
+	incidents, err := db.GetNetworkIncidents(fromEpoch, toEpoch)
+	if err != nil {
+		requestLogger(r).WithError(err).Error("error retrieving network incidents")
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{incidents})
+}
+
+var chartSeriesRE = regexp.MustCompile(`^[A-Z0-9_]{1,50}$`)
+
+// ApiChartSeries godoc
+// @Summary Returns the points of a pre-aggregated chart series within a time range
+// @Tags Misc
+// @Description The chart series are maintained by the statistics service. Daily points are available for the whole history, hourly points
+// @Description (ACTIVE_VALIDATORS, STAKED_ETH, AVG_VALIDATOR_BALANCE_ETH, AVG_PARTICIPATION_RATE, PROPOSED_BLOCKS, MISSED_BLOCKS and ORPHANED_BLOCKS) for the last 90 days.
+// @Produce  json
+// @Param  series path string true "Name of the series, e.g. STAKED_ETH"
+// @Param  from query int false "Unix timestamp of the start of the range (inclusive), defaults to 30 days (daily) or 7 days (hourly) before the end"
+// @Param  to query int false "Unix timestamp of the end of the range (exclusive), defaults to now"
+// @Param  resolution query string false "Resolution of the points, day (default) or hour"
+// @Success 200 {object} types.ApiResponse{data=types.ApiChartSeriesResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/charts/{series} [get]
+func ApiChartSeries(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	vars := mux.Vars(r)
+	q := r.URL.Query()
+
+	series := strings.ToUpper(vars["series"])
+	if !chartSeriesRE.MatchString(series) {
+		SendBadRequestResponse(w, r.URL.String(), "invalid series provided")
+		return
+	}
+
+	resolution := q.Get("resolution")
+	if resolution == "" {
+		resolution = "day"
+	}
+	if resolution != "day" && resolution != "hour" {
+		SendBadRequestResponse(w, r.URL.String(), "invalid resolution provided, must be day or hour")
+		return
+	}
+
+	to := time.Now()
+	if q.Get("to") != "" {
+		ts, err := strconv.ParseInt(q.Get("to"), 10, 64)
+		if err != nil {
+			SendBadRequestResponse(w, r.URL.String(), "invalid to provided")
+			return
+		}
+		to = time.Unix(ts, 0)
+	}
+
+	from := to.Add(-30 * utils.Day)
+	if resolution == "hour" {
+		from = to.Add(-7 * utils.Day)
+	}
+	if q.Get("from") != "" {
+		ts, err := strconv.ParseInt(q.Get("from"), 10, 64)
+		if err != nil {
+			SendBadRequestResponse(w, r.URL.String(), "invalid from provided")
+			return
+		}
+		from = time.Unix(ts, 0)
+	}
+	if !from.Before(to) {
+		SendBadRequestResponse(w, r.URL.String(), "from must be before to")
+		return
+	}
+	if resolution == "hour" && to.Sub(from) > db.ChartSeriesHourlyRetention {
+		SendBadRequestResponse(w, r.URL.String(), "hourly ranges are limited to 90 days")
+		return
+	}
+
+	points, err := db.GetChartSeriesPoints(series, resolution, from, to)
+	if err != nil {
+		requestLogger(r).WithError(err).Error("error retrieving chart series points")
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{&types.ApiChartSeriesResponse{
+		Series:     series,
+		Resolution: resolution,
+		From:       from.Unix(),
+		To:         to.Unix(),
+		Points:     points,
+	}})
+}
+
 // APIGetToken godoc
 // @Summary Exchange your oauth code for an access token or refresh your access token
 // @Tags User
@@ -2874,6 +4443,7 @@ func ApiChart(w http.ResponseWriter, r *http.Request) {
 // @Param grant_type formData string true "grant_type use authorization_code for oauth code or refresh_token if you wish to refresh an token"
 // @Param code formData string false "Only required when using authorization_code grant type. Code received via oauth redirect_uri"
 // @Param redirect_uri formData string false "Only required when using authorization_code grant type. Must match the redirect_uri from your oauth flow."
+// @Param code_verifier formData string false "Only required when using authorization_code grant type with a code_challenge (PKCE). The verifier of the code_challenge."
 // @Param refresh_token formData string false "Only required when using refresh_token grant type. The refresh_token you received during authorization_code flow."
 // @Header 200 jwt Authorization "Authorization Only required when using refresh_token grant type. Use any access token that is linked with your refresh_token."
 // @Success 200 {object} utils.OAuthResponse
@@ -2907,7 +4477,7 @@ func getTokenByCode(w http.ResponseWriter, r *http.Request) {
 	deviceName := getDeviceNameFromUA(r.Header.Get("User-Agent"))
 
 	// Check if redirect URI is correct
-	_, err := db.GetAppDataFromRedirectUri(redirectURI)
+	appData, err := db.GetAppDataFromRedirectUri(redirectURI)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		utils.SendOAuthErrorResponse(j, r.URL.String(), utils.InvalidRequest, "redirect_uri do not match")
@@ -2920,12 +4490,25 @@ func getTokenByCode(w http.ResponseWriter, r *http.Request) {
 	// Check if code entry exists and isn't expired (codes expire after 5 minutes)
 	codeAuthData, err := db.GetUserAuthDataByAuthorizationCode(codeHashed)
 	if err != nil {
-		logger.Errorf("Error hashed code can not be found in table: %v | Error: %v", codeHashed, err)
+		requestLogger(r).Errorf("Error hashed code can not be found in table: %v | Error: %v", codeHashed, err)
 		w.WriteHeader(http.StatusUnauthorized)
 		utils.SendOAuthErrorResponse(j, r.URL.String(), utils.AccessDenied, "access_token or refresh_token invalid")
 		return
 	}
 
+	// the code must have been issued to the app of the redirect_uri
+	if codeAuthData.AppID != appData.ID {
+		w.WriteHeader(http.StatusBadRequest)
+		utils.SendOAuthErrorResponse(j, r.URL.String(), utils.InvalidGrant, "code was not issued for this redirect_uri")
+		return
+	}
+
+	if codeAuthData.CodeChallenge != "" && !utils.VerifyPKCE(r.FormValue("code_verifier"), codeAuthData.CodeChallenge) {
+		w.WriteHeader(http.StatusBadRequest)
+		utils.SendOAuthErrorResponse(j, r.URL.String(), utils.InvalidGrant, "code_verifier does not match the code_challenge")
+		return
+	}
+
 	// Create refresh token
 	refreshTokenBytes, err := utils.GenerateRandomBytesSecure(32)
 	if err != nil {
@@ -2938,7 +4521,7 @@ func getTokenByCode(w http.ResponseWriter, r *http.Request) {
 	refreshTokenHashed := utils.HashAndEncode(refreshToken) // save hashed in db
 
 	// save refreshtoken hashed in db
-	deviceID, errDb := db.InsertUserDevice(codeAuthData.UserID, refreshTokenHashed, deviceName, codeAuthData.AppID)
+	deviceID, errDb := db.InsertUserDevice(codeAuthData.UserID, refreshTokenHashed, deviceName, codeAuthData.AppID, codeAuthData.Scope)
 	if errDb != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		utils.SendOAuthErrorResponse(j, r.URL.String(), utils.ServerError, "can not store auth info")
@@ -2959,7 +4542,7 @@ func getTokenByCode(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create access token
-	token, expiresIn, err := utils.CreateAccessToken(codeAuthData.UserID, codeAuthData.AppID, deviceID, pkg.Package, theme)
+	token, expiresIn, err := utils.CreateAccessToken(codeAuthData.UserID, codeAuthData.AppID, deviceID, pkg.Package, theme, codeAuthData.Scope)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		utils.SendOAuthErrorResponse(j, r.URL.String(), utils.ServerError, "can not create access_token")
@@ -2983,19 +4566,19 @@ func getTokenByRefresh(w http.ResponseWriter, r *http.Request) {
 	// Do not use userIDClaim as userID until confirmed by refreshToken validation
 	unsafeClaims, err := utils.UnsafeGetClaims(accessToken)
 	if err != nil {
-		logger.Errorf("Error access_token claim: %v", err)
+		requestLogger(r).Errorf("Error access_token claim: %v", err)
 		w.WriteHeader(http.StatusBadRequest)
 		utils.SendOAuthErrorResponse(j, r.URL.String(), utils.InvalidRequest, "access_token validation failed")
 		return
 	}
 
 	// confirm all claims via db lookup and refreshtoken check
-	userID, err := db.GetByRefreshToken(unsafeClaims.UserID, unsafeClaims.AppID, unsafeClaims.DeviceID, refreshTokenHashed)
+	userID, scope, err := db.GetByRefreshToken(unsafeClaims.UserID, unsafeClaims.AppID, unsafeClaims.DeviceID, refreshTokenHashed)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			logger.Warnf("No refresh token found for user: %v | %v", unsafeClaims.UserID, refreshTokenHashed)
+			requestLogger(r).Warnf("No refresh token found for user: %v | %v", unsafeClaims.UserID, refreshTokenHashed)
 		} else {
-			logger.Errorf("Error refreshtoken check: %v | %v | %v", unsafeClaims.UserID, refreshTokenHashed, err)
+			requestLogger(r).Errorf("Error refreshtoken check: %v | %v | %v", unsafeClaims.UserID, refreshTokenHashed, err)
 		}
 		w.WriteHeader(http.StatusUnauthorized)
 		utils.SendOAuthErrorResponse(j, r.URL.String(), utils.UnauthorizedClient, "invalid token credentials")
@@ -3016,7 +4599,7 @@ func getTokenByRefresh(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create access token
-	token, expiresIn, err := utils.CreateAccessToken(userID, unsafeClaims.AppID, unsafeClaims.DeviceID, pkg.Package, theme)
+	token, expiresIn, err := utils.CreateAccessToken(userID, unsafeClaims.AppID, unsafeClaims.DeviceID, pkg.Package, theme, scope)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		utils.SendOAuthErrorResponse(j, r.URL.String(), utils.ServerError, "can not create access_token")
@@ -3027,6 +4610,48 @@ func getTokenByRefresh(w http.ResponseWriter, r *http.Request) {
 	utils.SendOAuthResponse(j, r.URL.String(), token, "", expiresIn)
 }
 
+// APIRevokeToken godoc
+// @Summary Revoke the authorization of your oauth app
+// @Tags User
+// @Description Revokes the grant of a refresh_token or access_token according to RFC 7009, the refresh_token and all access tokens
+// @Description of the grant become invalid. Responds with 200 for unknown tokens as well.
+// @Produce  json
+// @Param token formData string true "The refresh_token or access_token to revoke"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} utils.OAuthErrorResponse
+// @Router /api/v1/user/token/revoke [post]
+func APIRevokeToken(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	token := r.FormValue("token")
+	if token == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		utils.SendOAuthErrorResponse(j, r.URL.String(), utils.InvalidRequest, "missing token")
+		return
+	}
+
+	var userID uint64
+	var err error
+	if claims, claimsErr := utils.ValidateAccessTokenGetClaims(token); claimsErr == nil {
+		userID = claims.UserID
+		err = db.MobileDeviceDelete(claims.UserID, claims.DeviceID)
+	} else {
+		userID, err = db.DeleteUserDeviceByRefreshToken(utils.HashAndEncode(token))
+	}
+	if err != nil {
+		utils.LogErrorContext(r.Context(), err, "error revoking oauth token", 0)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		utils.SendOAuthErrorResponse(j, r.URL.String(), utils.TemporarilyUnavailable, "could not revoke token")
+		return
+	}
+	if userID != 0 {
+		auditUserAction(r, userID, types.UserAuditAppRevoked, nil)
+	}
+
+	SendOKResponse(j, r.URL.String(), nil)
+}
+
 // Device name is limited to 20 chars
 func getDeviceNameFromUA(userAgent string) string {
 	ua := user_agent.New(userAgent)
@@ -3051,8 +4676,11 @@ func getDeviceNameFromUA(userAgent string) string {
 // MobileNotificationUpdatePOST godoc
 // @Summary Register or update your mobile notification token
 // @Tags User
+// @Description Devices reporting the capability rich_payload receive the event, validator, epoch and a deep link as data of the push notifications,
+// @Description devices reporting actions additionally receive actionable notifications (e.g. mute_1h, see /api/v1/user/notifications/mute).
 // @Produce  json
 // @Param token body string true "Your device`s firebase notification token"
+// @Param capabilities body []string false "The push notification capabilities of the app: rich_payload, actions"
 // @Success 200 {object} types.ApiResponse
 // @Failure 400 {object} types.ApiResponse
 // @Failure 500 {object} types.ApiResponse
@@ -3063,10 +4691,11 @@ func MobileNotificationUpdatePOST(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	notifyToken := FormValueOrJSON(r, "token")
+	capabilities := parsePushCapabilities(FormValueOrJSON(r, "capabilities"))
 
 	claims := getAuthClaims(r)
 
-	err2 := db.MobileNotificatonTokenUpdate(claims.UserID, claims.DeviceID, notifyToken)
+	err2 := db.MobileNotificatonTokenUpdate(claims.UserID, claims.DeviceID, notifyToken, capabilities)
 	if err2 != nil {
 		SendBadRequestResponse(w, r.URL.String(), "Can not save notify token")
 		return
@@ -3075,6 +4704,61 @@ func MobileNotificationUpdatePOST(w http.ResponseWriter, r *http.Request) {
 	OKResponse(w, r)
 }
 
+// parsePushCapabilities returns the known capabilities of a comma or space separated list, json arrays are passed as
+// "[a b]" by FormValueOrJSON
+func parsePushCapabilities(value string) []string {
+	capabilities := []string{}
+	fields := strings.FieldsFunc(strings.Trim(value, "[]"), func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	for _, field := range fields {
+		if utils.SliceContains(types.PushCapabilities, field) && !utils.SliceContains(capabilities, field) {
+			capabilities = append(capabilities, field)
+		}
+	}
+	return capabilities
+}
+
+// MobileNotificationMutePOST godoc
+// @Summary Mute the push notifications of a subscription, e.g. from the mute action of a push notification
+// @Tags User
+// @Produce  json
+// @Param subscription_id body integer true "The subscription_id of the push notification data"
+// @Param minutes body integer false "Duration to mute the subscription for in minutes, at most 10080 (default 60)"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/notifications/mute [post]
+func MobileNotificationMutePOST(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	subscriptionID, err := strconv.ParseUint(FormValueOrJSON(r, "subscription_id"), 10, 64)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid subscription_id provided")
+		return
+	}
+	minutes := parseUintWithDefault(FormValueOrJSON(r, "minutes"), 60)
+	if minutes == 0 || minutes > 7*24*60 {
+		SendBadRequestResponse(w, r.URL.String(), "invalid minutes provided, must be between 1 and 10080")
+		return
+	}
+
+	claims := getAuthClaims(r)
+	found, err := db.MuteUserSubscription(claims.UserID, subscriptionID, time.Now().Add(time.Duration(minutes)*time.Minute))
+	if err != nil {
+		utils.LogErrorContext(r.Context(), err, "error muting subscription", 0, map[string]interface{}{"userId": claims.UserID, "subscriptionId": subscriptionID})
+		SendBadRequestResponse(w, r.URL.String(), "could not mute subscription")
+		return
+	}
+	if !found {
+		sendErrorWithCodeResponse(w, r.URL.String(), "subscription not found", http.StatusNotFound)
+		return
+	}
+
+	OKResponse(w, r)
+}
+
 func RegisterEthpoolSubscription(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -3085,7 +4769,7 @@ func RegisterEthpoolSubscription(w http.ResponseWriter, r *http.Request) {
 	localSignature := hmacSign(fmt.Sprintf("ETHPOOL %v %v", pkg, ethpoolUserID))
 	if signature != localSignature {
 		w.WriteHeader(http.StatusBadRequest)
-		logger.Errorf("signature mismatch %v | %v", signature, localSignature)
+		requestLogger(r).Errorf("signature mismatch %v | %v", signature, localSignature)
 		SendBadRequestResponse(w, r.URL.String(), "Unauthorized: signature not valid")
 		return
 	}
@@ -3094,7 +4778,7 @@ func RegisterEthpoolSubscription(w http.ResponseWriter, r *http.Request) {
 
 	subscriptionCount, err := db.GetAppSubscriptionCount(claims.UserID)
 	if err != nil {
-		utils.LogError(err, "could not get subscription count", 0)
+		utils.LogErrorContext(r.Context(), err, "could not get subscription count", 0)
 		sendServerErrorResponse(w, r.URL.String(), "Internal Server Error")
 		return
 	}
@@ -3117,7 +4801,7 @@ func RegisterEthpoolSubscription(w http.ResponseWriter, r *http.Request) {
 
 	err = db.InsertMobileSubscription(nil, claims.UserID, parsedBase, parsedBase.Transaction.Type, parsedBase.Transaction.Receipt, 0, "", "")
 	if err != nil {
-		logger.Errorf("could not save subscription data %v", err)
+		requestLogger(r).Errorf("could not save subscription data %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		SendBadRequestResponse(w, r.URL.String(), "Can not save subscription data")
 		return
@@ -3140,7 +4824,7 @@ func RegisterMobileSubscriptions(w http.ResponseWriter, r *http.Request) {
 	err := json.Unmarshal(gorillacontext.Get(r, utils.JsonBodyNakedKey).([]byte), &parsedBase)
 
 	if err != nil {
-		logger.Errorf("error parsing body | err: %v", err)
+		requestLogger(r).Errorf("error parsing body | err: %v", err)
 		SendBadRequestResponse(w, r.URL.String(), "could not parse body")
 		return
 	}
@@ -3160,7 +4844,7 @@ func RegisterMobileSubscriptions(w http.ResponseWriter, r *http.Request) {
 
 	subscriptionCount, err := db.GetAppSubscriptionCount(claims.UserID)
 	if err != nil {
-		utils.LogError(err, "could not get subscription count", 0)
+		utils.LogErrorContext(r.Context(), err, "could not get subscription count", 0)
 		sendServerErrorResponse(w, r.URL.String(), "Internal Server Error")
 		return
 	}
@@ -3183,20 +4867,20 @@ func RegisterMobileSubscriptions(w http.ResponseWriter, r *http.Request) {
 	// case is not needed on receipt insert
 	validationResult, err := exporter.VerifyReceipt(nil, nil, verifyPackage)
 	if err != nil {
-		utils.LogError(err, "could not verify receipt %v", 0, map[string]interface{}{"receipt": verifyPackage.Receipt})
+		utils.LogErrorContext(r.Context(), err, "could not verify receipt %v", 0, map[string]interface{}{"receipt": verifyPackage.Receipt})
 	}
 	parsedBase.Valid = validationResult.Valid
 	parsedBase.ProductID = verifyPackage.ProductID // apple verify can change the product id
 
 	err = db.InsertMobileSubscription(nil, claims.UserID, parsedBase, parsedBase.Transaction.Type, verifyPackage.Receipt, validationResult.ExpirationDate, validationResult.RejectReason, "")
 	if err != nil {
-		logger.Errorf("could not save subscription data %v", err)
+		requestLogger(r).Errorf("could not save subscription data %v", err)
 		SendBadRequestResponse(w, r.URL.String(), "Can not save subscription data")
 		return
 	}
 
 	if !parsedBase.Valid {
-		logger.Errorf("receipt is not valid %v", validationResult.RejectReason)
+		requestLogger(r).Errorf("receipt is not valid %v", validationResult.RejectReason)
 		SendBadRequestResponse(w, r.URL.String(), "receipt is not valid")
 		return
 	}
@@ -3207,8 +4891,10 @@ func RegisterMobileSubscriptions(w http.ResponseWriter, r *http.Request) {
 type PremiumUser struct {
 	Package                string
 	MaxValidators          int
+	MaxDashboardValidators int // limit of the server side aggregated saved dashboards
 	MaxStats               uint64
 	MaxNodes               uint64
+	MachineMetricsHistory  map[string]time.Duration // retention of the machine metrics per resolution (see db.MachineMetricsResolutions)
 	WidgetSupport          bool
 	NotificationThresholds bool
 	NoAds                  bool
@@ -3236,8 +4922,14 @@ func GetUserPremiumByPackage(pkg string) PremiumUser {
 	result := PremiumUser{
 		Package:                "standard",
 		MaxValidators:          100,
+		MaxDashboardValidators: 1000,
 		MaxStats:               180,
 		MaxNodes:               1,
+		MachineMetricsHistory: map[string]time.Duration{
+			"1m": time.Hour * 3,
+			"5m": utils.Day,
+			"1h": utils.Week,
+		},
 		WidgetSupport:          false,
 		NotificationThresholds: false,
 		NoAds:                  false,
@@ -3251,6 +4943,12 @@ func GetUserPremiumByPackage(pkg string) PremiumUser {
 
 	result.Package = pkg
 	result.MaxStats = 43200
+	result.MaxDashboardValidators = 10000
+	result.MachineMetricsHistory = map[string]time.Duration{
+		"1m": utils.Day * 30,
+		"5m": utils.Day * 90,
+		"1h": utils.Day * 365,
+	}
 	result.NotificationThresholds = true
 	result.NoAds = true
 
@@ -3263,7 +4961,10 @@ func GetUserPremiumByPackage(pkg string) PremiumUser {
 	}
 	if result.Package == "whale" {
 		result.MaxValidators = 300
+		result.MaxDashboardValidators = 100000
 		result.MaxNodes = 10
+		result.MachineMetricsHistory["5m"] = utils.Day * 180
+		result.MachineMetricsHistory["1h"] = utils.Day * 730
 	}
 
 	return result
@@ -3308,7 +5009,7 @@ func GetMobileWidgetStats(w http.ResponseWriter, r *http.Request, indexOrPubkey
 	var validatorRows *sql.Rows
 
 	g.Go(func() error {
-		validatorRows, err = db.ReaderDb.Query(
+		validatorRows, err = db.ReaderDb.QueryContext(r.Context(),
 			`SELECT 
 					validators.pubkey, 
 					slashed, 
@@ -3379,14 +5080,14 @@ func GetMobileWidgetStats(w http.ResponseWriter, r *http.Request, indexOrPubkey
 	for _, entry := range generalData {
 		eMap, ok := entry.(map[string]interface{})
 		if !ok {
-			logger.Errorf("error converting validator data to map[string]interface{}")
+			requestLogger(r).Errorf("error converting validator data to map[string]interface{}")
 			continue
 		}
 
 		validatorIndex, ok := eMap["validatorindex"].(int64)
 
 		if !ok {
-			logger.Errorf("error converting validatorindex to int64")
+			requestLogger(r).Errorf("error converting validatorindex to int64")
 			continue
 		}
 
@@ -3473,7 +5174,7 @@ func MobileDeviceSettingsPOST(w http.ResponseWriter, r *http.Request) {
 		customDeviceID := FormValueOrJSON(r, "id")
 		temp, err := strconv.ParseUint(customDeviceID, 10, 64)
 		if err != nil {
-			logger.Errorf("error parsing id %v | err: %v", customDeviceID, err)
+			requestLogger(r).Errorf("error parsing id %v | err: %v", customDeviceID, err)
 			SendBadRequestResponse(w, r.URL.String(), "could not parse id")
 			return
 		}
@@ -3491,7 +5192,7 @@ func MobileDeviceSettingsPOST(w http.ResponseWriter, r *http.Request) {
 
 	rows, err := db.MobileDeviceSettingsUpdate(userID, userDeviceID, notifyEnabled, active)
 	if err != nil {
-		logger.Errorf("could not retrieve db results err: %v", err)
+		requestLogger(r).Errorf("could not retrieve db results err: %v", err)
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
@@ -3579,21 +5280,21 @@ func ClientStats(w http.ResponseWriter, r *http.Request) {
 
 	system, err := db.BigtableClient.GetMachineMetricsSystem(claims.UserID, int(limit), int(offset))
 	if err != nil {
-		logger.Errorf("sytem stat error : %v", err)
+		requestLogger(r).Errorf("sytem stat error : %v", err)
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve system stats from db")
 		return
 	}
 
 	validator, err := db.BigtableClient.GetMachineMetricsValidator(claims.UserID, int(limit), int(offset))
 	if err != nil {
-		logger.Errorf("validator stat error : %v", err)
+		requestLogger(r).Errorf("validator stat error : %v", err)
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve validator stats from db")
 		return
 	}
 
 	node, err := db.BigtableClient.GetMachineMetricsNode(claims.UserID, int(limit), int(offset))
 	if err != nil {
-		logger.Errorf("node stat error : %v", err)
+		requestLogger(r).Errorf("node stat error : %v", err)
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve beaconnode stats from db")
 		return
 	}
@@ -3607,6 +5308,95 @@ func ClientStats(w http.ResponseWriter, r *http.Request) {
 	SendOKResponse(j, r.URL.String(), []interface{}{data})
 }
 
+// ClientStatsMachines godoc
+// @Summary Get the names of your machines that submitted stats within the last 5 hours
+// @Tags User
+// @Produce json
+// @Success 200 {object} types.ApiResponse{data=[]string}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/machines [get]
+func ClientStatsMachines(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	claims := getAuthClaims(r)
+
+	names, err := db.BigtableClient.GetMachineMetricsMachineNames(claims.UserID)
+	if err != nil {
+		requestLogger(r).Errorf("machine names error : %v", err)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve machines from db")
+		return
+	}
+	sort.Strings(names)
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{names})
+}
+
+// ClientStatsHistory godoc
+// @Summary Get the history of the submitted stats of one of your machines
+// @Tags User
+// @Description The stats are downsampled to 1m, 5m and 1h resolutions (the last sample of each interval), how far back each
+// @Description resolution reaches depends on your subscription.
+// @Produce json
+// @Param name path string true "Name of the machine"
+// @Param resolution query string false "Resolution of the stats: 1m, 5m or 1h" default(5m)
+// @Param from query int false "Unix timestamp of the start of the range, defaults to the oldest available stats"
+// @Param to query int false "Unix timestamp of the end of the range, defaults to now"
+// @Success 200 {object} types.ApiResponse{data=[]types.MachineMetricsHistory}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/machine/{name}/history [get]
+func ClientStatsHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	claims := getAuthClaims(r)
+	q := r.URL.Query()
+
+	machine := mux.Vars(r)["name"]
+	if machine == "" || strings.Contains(machine, ":") {
+		SendBadRequestResponse(w, r.URL.String(), "invalid machine name provided")
+		return
+	}
+
+	resolution := q.Get("resolution")
+	if resolution == "" {
+		resolution = "5m"
+	}
+	retention, ok := getUserPremium(r).MachineMetricsHistory[resolution]
+	if !ok {
+		SendBadRequestResponse(w, r.URL.String(), "invalid resolution provided, must be one of 1m, 5m or 1h")
+		return
+	}
+
+	now := time.Now()
+	to := now
+	if q.Get("to") != "" {
+		to = time.Unix(int64(parseUintWithDefault(q.Get("to"), uint64(now.Unix()))), 0)
+		if to.After(now) {
+			to = now
+		}
+	}
+	oldest := now.Add(-retention)
+	from := oldest
+	if q.Get("from") != "" {
+		from = time.Unix(int64(parseUintWithDefault(q.Get("from"), 0)), 0)
+		if from.Before(oldest) {
+			from = oldest
+		}
+	}
+	if !from.Before(to) {
+		SendBadRequestResponse(w, r.URL.String(), "invalid time range provided")
+		return
+	}
+
+	history, err := db.BigtableClient.GetMachineMetricsHistory(claims.UserID, machine, resolution, from, to)
+	if err != nil {
+		requestLogger(r).Errorf("machine stats history error : %v", err)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve machine stats from db")
+		return
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{history})
+}
+
 // ClientStatsPost godoc
 // @Summary Used in eth2 clients to submit stats to your beaconcha.in account. This data can be accessed by the app or the user stats api call.
 // @Tags User
@@ -3651,7 +5441,7 @@ func clientStatsPost(w http.ResponseWriter, r *http.Request, apiKey, machine str
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		logger.Warnf("error reading body | err: %v", err)
+		requestLogger(r).Warnf("error reading body | err: %v", err)
 		SendBadRequestResponse(w, r.URL.String(), "could not read body")
 		return
 	}
@@ -3662,7 +5452,7 @@ func clientStatsPost(w http.ResponseWriter, r *http.Request, apiKey, machine str
 		var jsonObject map[string]interface{}
 		err = json.Unmarshal(body, &jsonObject)
 		if err != nil {
-			logger.Warnf("Could not parse stats (meta stats) general | %v ", err)
+			requestLogger(r).Warnf("Could not parse stats (meta stats) general | %v ", err)
 			SendBadRequestResponse(w, r.URL.String(), "Invalid JSON format in request body")
 			return
 		}
@@ -3670,7 +5460,7 @@ func clientStatsPost(w http.ResponseWriter, r *http.Request, apiKey, machine str
 	}
 
 	if len(jsonObjects) >= 10 {
-		logger.Info("Max number of stat entries are 10", err)
+		requestLogger(r).Info("Max number of stat entries are 10", err)
 		SendBadRequestResponse(w, r.URL.String(), "Max number of stat entries are 10")
 		return
 	}
@@ -3707,7 +5497,7 @@ func insertStats(userData *types.UserWithPremium, machine string, body *map[stri
 	var parsedMeta *types.StatsMeta
 	err := mapstructure.Decode(body, &parsedMeta)
 	if err != nil {
-		logger.Warnf("Could not parse stats (meta stats) | %v ", err)
+		requestLogger(r).Warnf("Could not parse stats (meta stats) | %v ", err)
 		SendBadRequestResponse(w, r.URL.String(), "could not parse meta")
 		return err
 	}
@@ -3728,7 +5518,7 @@ func insertStats(userData *types.UserWithPremium, machine string, body *map[stri
 
 	count, err := db.BigtableClient.GetMachineMetricsMachineCount(userData.ID)
 	if err != nil {
-		logger.Errorf("Could not get max machine count| %v", err)
+		requestLogger(r).Errorf("Could not get max machine count| %v", err)
 		SendBadRequestResponse(w, r.URL.String(), "could not get machine count")
 		return err
 	}
@@ -3743,13 +5533,13 @@ func insertStats(userData *types.UserWithPremium, machine string, body *map[stri
 		var parsedResponse *types.MachineMetricSystem
 		err = DecodeMapStructure(body, &parsedResponse)
 		if err != nil {
-			logger.Warnf("Could not parse stats (system stats) | %v", err)
+			requestLogger(r).Warnf("Could not parse stats (system stats) | %v", err)
 			SendBadRequestResponse(w, r.URL.String(), "could not parse system")
 			return err
 		}
 		data, err = proto.Marshal(parsedResponse)
 		if err != nil {
-			logger.Errorf("Could not parse stats (system stats) | %v", err)
+			requestLogger(r).Errorf("Could not parse stats (system stats) | %v", err)
 			SendBadRequestResponse(w, r.URL.String(), "could marshal system")
 			return err
 		}
@@ -3757,13 +5547,13 @@ func insertStats(userData *types.UserWithPremium, machine string, body *map[stri
 		var parsedResponse *types.MachineMetricValidator
 		err = DecodeMapStructure(body, &parsedResponse)
 		if err != nil {
-			logger.Warnf("Could not parse stats (validator stats) | %v", err)
+			requestLogger(r).Warnf("Could not parse stats (validator stats) | %v", err)
 			SendBadRequestResponse(w, r.URL.String(), "could marshal validator")
 			return err
 		}
 		data, err = proto.Marshal(parsedResponse)
 		if err != nil {
-			logger.Errorf("Could not parse stats (validator stats) | %v", err)
+			requestLogger(r).Errorf("Could not parse stats (validator stats) | %v", err)
 			SendBadRequestResponse(w, r.URL.String(), "could marshal validator")
 			return err
 		}
@@ -3771,13 +5561,13 @@ func insertStats(userData *types.UserWithPremium, machine string, body *map[stri
 		var parsedResponse *types.MachineMetricNode
 		err = DecodeMapStructure(body, &parsedResponse)
 		if err != nil {
-			logger.Warnf("Could not parse stats (beaconnode stats) | %v", err)
+			requestLogger(r).Warnf("Could not parse stats (beaconnode stats) | %v", err)
 			SendBadRequestResponse(w, r.URL.String(), "could not parse beaconnode")
 			return err
 		}
 		data, err = proto.Marshal(parsedResponse)
 		if err != nil {
-			logger.Errorf("Could not parse stats (beaconnode stats) | %v", err)
+			requestLogger(r).Errorf("Could not parse stats (beaconnode stats) | %v", err)
 			SendBadRequestResponse(w, r.URL.String(), "could not parse beaconnode")
 			return err
 		}
@@ -3788,7 +5578,7 @@ func insertStats(userData *types.UserWithPremium, machine string, body *map[stri
 		if strings.HasPrefix(err.Error(), "rate limit") {
 			return err
 		}
-		logger.Errorf("Could not store stats | %v", err)
+		requestLogger(r).Errorf("Could not store stats | %v", err)
 		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("could not store stats: %v", err))
 		return err
 	}
@@ -3845,7 +5635,7 @@ func ApiWithdrawalCredentialsValidators(w http.ResponseWriter, r *http.Request)
 		Pubkey []byte `db:"pubkey"`
 	}{}
 
-	err = db.ReaderDb.Select(&result, `
+	err = db.ReaderDb.SelectContext(r.Context(), &result, `
 	SELECT
 		validatorindex,
 		pubkey
@@ -3857,7 +5647,7 @@ func ApiWithdrawalCredentialsValidators(w http.ResponseWriter, r *http.Request)
 	`, credentials, limit, offset)
 
 	if err != nil {
-		logger.Warnf("error retrieving validator data from db: %v", err)
+		requestLogger(r).Warnf("error retrieving validator data from db: %v", err)
 		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
 		return
 	}
@@ -3921,14 +5711,14 @@ func ApiProposalLuck(w http.ResponseWriter, r *http.Request) {
 	data, err := getProposalLuckStats(indices)
 	if err != nil {
 		sendServerErrorResponse(w, r.URL.String(), "error processing request, please try again later")
-		utils.LogError(err, "error retrieving data from db for proposal luck", 0, map[string]interface{}{"request": r.Method + " " + r.URL.String()})
+		utils.LogErrorContext(r.Context(), err, "error retrieving data from db for proposal luck", 0, map[string]interface{}{"request": r.Method + " " + r.URL.String()})
 	}
 
 	response.Data = data
 	err = json.NewEncoder(w).Encode(response)
 	if err != nil {
 		sendServerErrorResponse(w, r.URL.String(), "could not serialize data results")
-		utils.LogError(err, "error serializing json data for API", 0, map[string]interface{}{"request": r.Method + " " + r.URL.String()})
+		utils.LogErrorContext(r.Context(), err, "error serializing json data for API", 0, map[string]interface{}{"request": r.Method + " " + r.URL.String()})
 	}
 }
 
@@ -4008,7 +5798,7 @@ func APIDashboardDataBalance(w http.ResponseWriter, r *http.Request) {
 
 	queryValidatorIndices, queryValidatorPubkeys, err := parseValidatorsFromQueryString(q.Get("validators"), 100)
 	if err != nil || len(queryValidatorPubkeys) > 0 {
-		logger.WithError(err).WithField("route", r.URL.String()).Error("error parsing validators from query string")
+		requestLogger(r).WithError(err).WithField("route", r.URL.String()).Error("error parsing validators from query string")
 		http.Error(w, "Invalid query", http.StatusBadRequest)
 		return
 	}
@@ -4032,7 +5822,7 @@ func APIDashboardDataBalance(w http.ResponseWriter, r *http.Request) {
 
 	balances, err := db.BigtableClient.GetValidatorBalanceHistory(queryValidatorIndices, latestEpoch-queryOffsetEpoch, latestEpoch)
 	if err != nil {
-		logger.WithError(err).WithField("route", r.URL.String()).Errorf("error retrieving validator balance history")
+		requestLogger(r).WithError(err).WithField("route", r.URL.String()).Errorf("error retrieving validator balance history")
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -4071,7 +5861,7 @@ func APIDashboardDataBalance(w http.ResponseWriter, r *http.Request) {
 
 	err = json.NewEncoder(w).Encode(balanceHistoryChartData)
 	if err != nil {
-		logger.WithError(err).WithField("route", r.URL.String()).Error("error enconding json response")
+		requestLogger(r).WithError(err).WithField("route", r.URL.String()).Error("error enconding json response")
 		sendServerErrorResponse(w, r.URL.String(), "could not serialize data results")
 		return
 	}
@@ -4080,7 +5870,12 @@ func APIDashboardDataBalance(w http.ResponseWriter, r *http.Request) {
 func getAuthClaims(r *http.Request) *utils.CustomClaims {
 	middleWare := gorillacontext.Get(r, utils.MobileAuthorizedKey)
 	if middleWare == nil {
-		return utils.GetAuthorizationClaims(r)
+		// routes without the OAuthScopeMiddleware must not accept scoped tokens outside of their scope either
+		claims := utils.GetAuthorizationClaims(r)
+		if claims != nil && claims.Scope != "" && !oauthScopeAllows(r, claims.Scope) {
+			return nil
+		}
+		return claims
 	}
 
 	claims := gorillacontext.Get(r, utils.ClaimsContextKey)
@@ -4109,6 +5904,63 @@ func returnQueryResults(rows *sql.Rows, w http.ResponseWriter, r *http.Request,
 	SendOKResponse(j, r.URL.String(), data)
 }
 
+// Saves the result of a query as csv file in the response writer, one line per row with the column names as header.
+// Fields added by the adjustQueryEntriesFuncs are appended as additional columns.
+func returnQueryResultsAsCsv(rows *sql.Rows, w http.ResponseWriter, r *http.Request, filename string, adjustQueryEntriesFuncs ...func(map[string]interface{}) error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not parse db results")
+		return
+	}
+
+	data, err := utils.SqlRowsToJSON(rows)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not parse db results")
+		return
+	}
+
+	err = adjustQueryResults(data, adjustQueryEntriesFuncs...)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not adjust query results")
+		return
+	}
+
+	if len(data) > 0 {
+		extraColumns := []string{}
+		for key := range data[0].(map[string]interface{}) {
+			if !utils.SliceContains(columns, key) {
+				extraColumns = append(extraColumns, key)
+			}
+		}
+		sort.Strings(extraColumns)
+		columns = append(columns, extraColumns...)
+	}
+
+	records := make([][]string, 0, len(data)+1)
+	records = append(records, columns)
+	for _, entry := range data {
+		entryMap := entry.(map[string]interface{})
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			switch v := entryMap[column].(type) {
+			case nil:
+			case time.Time:
+				record[i] = v.UTC().Format(time.RFC3339)
+			default:
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		records = append(records, record)
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.csv", filename))
+	err = csv.NewWriter(w).WriteAll(records)
+	if err != nil {
+		requestLogger(r).Errorf("error writing csv response for route %v: %v", r.URL.String(), err)
+	}
+}
+
 // Saves the result of a query converted to JSON in the response writer as an array.
 // An arbitrary amount of functions adjustQueryEntriesFuncs can be added to adjust the JSON response.
 func returnQueryResultsAsArray(rows *sql.Rows, w http.ResponseWriter, r *http.Request, adjustQueryEntriesFuncs ...func(map[string]interface{}) error) {
@@ -4134,7 +5986,7 @@ func returnQueryResultsAsArray(rows *sql.Rows, w http.ResponseWriter, r *http.Re
 
 	if err != nil {
 		sendServerErrorResponse(w, r.URL.String(), "could not serialize data results")
-		logger.Errorf("error serializing json data for API %v route: %v", r.URL.String(), err)
+		requestLogger(r).Errorf("error serializing json data for API %v route: %v", r.URL.String(), err)
 	}
 }
 
@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
@@ -12,11 +13,13 @@ import (
 	"time"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/eth1data"
 	"github.com/gobitfly/eth2-beaconchain-explorer/price"
 	"github.com/gobitfly/eth2-beaconchain-explorer/services"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
 	"github.com/lib/pq"
@@ -249,6 +252,7 @@ func ApiETH1AccountProducedBlocks(w http.ResponseWriter, r *http.Request) {
 // @Summary Gets the current estimation for gas prices in GWei.
 // @Tags Execution
 // @Description The response is split into four estimated inclusion speeds rapid (15 seconds), fast (1 minute), standard (3 minutes) and slow (> 10 minutes).
+// @Description For EIP-1559 transactions the base fee of the next block, the base fees of the last 20 blocks (baseFeeTrend) and suggested priority fees per inclusion speed (the median of the 90th, 60th, 30th and 10th percentile of priority fees paid in the last 20 blocks) are provided. All values are in wei.
 // @Produce json
 // @Success 200 {object} types.ApiResponse
 // @Failure 400 {object} types.ApiResponse
@@ -409,6 +413,519 @@ func ApiEth1AddressERC20Tokens(w http.ResponseWriter, r *http.Request) {
 	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
 }
 
+// ApiEth1AddressERC20Transfers godoc
+// @Summary Returns the ERC20 token transfers of a given Ethereum address.
+// @Tags Execution
+// @Description Returns the ERC20 token transfers of a given Ethereum address, most recent first. Use the returned page_token to fetch the next page.
+// @Produce json
+// @Param address path string true "provide an Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters". It can also be a valid ENS name.
+// @Param token query string false "only return transfers of this token contract"
+// @Param page_token query string false "page token returned by the previous request"
+// @Success 200 {object} types.ApiResponse{data=types.ApiEth1AddressERC20TransfersResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/address/{address}/erc20 [get]
+func ApiEth1AddressERC20Transfers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	errFields := map[string]interface{}{
+		"route": r.URL.String()}
+
+	vars := mux.Vars(r)
+
+	address := ReplaceEnsNameWithAddress(vars["address"])
+	address = strings.Replace(address, "0x", "", -1)
+	address = strings.ToLower(address)
+
+	if !utils.IsEth1Address(address) {
+		SendBadRequestResponse(w, r.URL.String(), "error invalid address. An Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+		return
+	}
+
+	q := r.URL.Query()
+	token := strings.ToLower(strings.Replace(q.Get("token"), "0x", "", -1))
+	if len(token) > 0 && !utils.IsEth1Address(token) {
+		SendBadRequestResponse(w, r.URL.String(), "error invalid token address. An Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+		return
+	}
+	pageToken := q.Get("page_token")
+
+	errFields["address"] = address
+	errFields["pageToken"] = pageToken
+
+	transfers, tokens, lastKey, err := db.BigtableClient.GetAddressErc20Transfers(common.FromHex(address), common.FromHex(token), pageToken, db.DefaultInfScrollRows)
+	if err != nil {
+		utils.LogError(err, "error could not get erc20 transfers for address", 0, errFields)
+		sendServerErrorResponse(w, r.URL.String(), "error could not get erc20 transfers for address")
+		return
+	}
+
+	response := types.ApiEth1AddressERC20TransfersResponse{
+		Transfers: make([]types.ApiEth1AddressERC20TransferResponse, 0, len(transfers)),
+		PageToken: lastKey,
+	}
+	for _, t := range transfers {
+		value := decimal.NewFromBigInt(new(big.Int).SetBytes(t.Value), 0)
+		symbol := ""
+		if metadata := tokens[string(t.TokenAddress)]; metadata != nil {
+			value = value.Div(decimal.NewFromBigInt(big.NewInt(1), int32(new(big.Int).SetBytes(metadata.Decimals).Int64())))
+			symbol = metadata.Symbol
+		}
+
+		response.Transfers = append(response.Transfers, types.ApiEth1AddressERC20TransferResponse{
+			TxHash:      fmt.Sprintf("0x%x", t.ParentHash),
+			BlockNumber: t.BlockNumber,
+			Timestamp:   t.Time.AsTime().Unix(),
+			From:        fmt.Sprintf("0x%x", t.From),
+			To:          fmt.Sprintf("0x%x", t.To),
+			Token:       fmt.Sprintf("0x%x", t.TokenAddress),
+			Symbol:      symbol,
+			Value:       value.String(),
+		})
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
+
+// ApiEth1AddressNFTs godoc
+// @Summary Returns the ERC721 and ERC1155 tokens owned by a given Ethereum address.
+// @Tags Execution
+// @Description Returns the ERC721 and ERC1155 tokens owned by a given Ethereum address together with their metadata. The metadata is resolved in the background and omitted until it is available. At most 100 tokens are returned.
+// @Produce json
+// @Param address path string true "provide an Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters". It can also be a valid ENS name.
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiEth1AddressNFTResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/address/{address}/nfts [get]
+func ApiEth1AddressNFTs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	errFields := map[string]interface{}{
+		"route": r.URL.String()}
+
+	vars := mux.Vars(r)
+
+	address := ReplaceEnsNameWithAddress(vars["address"])
+	address = strings.Replace(address, "0x", "", -1)
+	address = strings.ToLower(address)
+
+	if !utils.IsEth1Address(address) {
+		SendBadRequestResponse(w, r.URL.String(), "error invalid address. An Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+		return
+	}
+
+	errFields["address"] = address
+
+	nfts, err := db.BigtableClient.GetNFTsForAddress(common.FromHex(address), db.NFTsPerAddressLimit)
+	if err != nil {
+		utils.LogError(err, "error could not get nfts for address", 0, errFields)
+		sendServerErrorResponse(w, r.URL.String(), "error could not get nfts for address")
+		return
+	}
+
+	response := make([]types.ApiEth1AddressNFTResponse, 0, len(nfts))
+	for _, nft := range nfts {
+		entry := types.ApiEth1AddressNFTResponse{
+			Standard: nft.Standard,
+			Token:    fmt.Sprintf("0x%x", nft.TokenAddress),
+			TokenId:  nft.TokenId.String(),
+			Balance:  nft.Balance.String(),
+		}
+		if nft.Metadata != nil {
+			entry.TokenURI = nft.Metadata.TokenURI
+			entry.Name = nft.Metadata.Name
+			entry.Description = nft.Metadata.Description
+			entry.Image = nft.Metadata.Image
+		}
+		response = append(response, entry)
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
+
+// ApiEth1TxInternal godoc
+// @Summary Returns the internal transactions of a given transaction.
+// @Tags Execution
+// @Description Returns the value transfers between accounts that happened within the execution of a transaction (e.g. eth sent by contracts), in trace order. Calls without value are omitted.
+// @Produce json
+// @Param hash path string true "transaction hash"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiEth1InternalTxResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/tx/{hash}/internal [get]
+func ApiEth1TxInternal(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	errFields := map[string]interface{}{
+		"route": r.URL.String()}
+
+	vars := mux.Vars(r)
+	hash := strings.ToLower(strings.Replace(vars["hash"], "0x", "", -1))
+
+	if !utils.IsValidEth1Tx(hash) {
+		SendBadRequestResponse(w, r.URL.String(), "error invalid transaction hash. A transaction hash consists of an optional 0x prefix followed by 64 hexadecimal characters.")
+		return
+	}
+
+	errFields["hash"] = hash
+
+	itxs, err := db.BigtableClient.GetInternalTransactionsForTx(common.FromHex(hash))
+	if err != nil {
+		utils.LogError(err, "error could not get internal transactions for tx", 0, errFields)
+		sendServerErrorResponse(w, r.URL.String(), "error could not get internal transactions for tx")
+		return
+	}
+
+	response := make([]types.ApiEth1InternalTxResponse, 0, len(itxs))
+	for _, itx := range itxs {
+		response = append(response, types.ApiEth1InternalTxResponse{
+			BlockNumber: itx.BlockNumber,
+			Timestamp:   itx.Time.AsTime().Unix(),
+			Type:        itx.Type,
+			From:        fmt.Sprintf("0x%x", itx.From),
+			To:          fmt.Sprintf("0x%x", itx.To),
+			Value:       utils.WeiBytesToEther(itx.Value).String(),
+		})
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
+
+// ApiEth1TxUserOps godoc
+// @Summary Returns the ERC-4337 user operations of a given transaction.
+// @Tags Execution
+// @Description Returns the account abstraction (ERC-4337) user operations that were bundled in a transaction and executed by an EntryPoint contract, in execution order. The actual gas cost is denominated in wei.
+// @Produce json
+// @Param hash path string true "transaction hash"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiEth1UserOpResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/tx/{hash}/userops [get]
+func ApiEth1TxUserOps(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	errFields := map[string]interface{}{
+		"route": r.URL.String()}
+
+	vars := mux.Vars(r)
+	hash := strings.ToLower(strings.Replace(vars["hash"], "0x", "", -1))
+
+	if !utils.IsValidEth1Tx(hash) {
+		SendBadRequestResponse(w, r.URL.String(), "error invalid transaction hash. A transaction hash consists of an optional 0x prefix followed by 64 hexadecimal characters.")
+		return
+	}
+
+	errFields["hash"] = hash
+
+	userOps, err := db.BigtableClient.GetUserOperationsForTx(common.FromHex(hash))
+	if err != nil {
+		utils.LogError(err, "error could not get user operations for tx", 0, errFields)
+		sendServerErrorResponse(w, r.URL.String(), "error could not get user operations for tx")
+		return
+	}
+
+	response := make([]types.ApiEth1UserOpResponse, 0, len(userOps))
+	for _, u := range userOps {
+		response = append(response, formatUserOpForApiResponse(u))
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
+
+// ApiEth1UserOp godoc
+// @Summary Returns an ERC-4337 user operation by its hash.
+// @Tags Execution
+// @Description Returns the account abstraction (ERC-4337) user operation with the given user operation hash. The actual gas cost is denominated in wei.
+// @Produce json
+// @Param hash path string true "user operation hash"
+// @Success 200 {object} types.ApiResponse{data=types.ApiEth1UserOpResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Router /api/v1/execution/userops/{hash} [get]
+func ApiEth1UserOp(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	errFields := map[string]interface{}{
+		"route": r.URL.String()}
+
+	vars := mux.Vars(r)
+	hash := strings.ToLower(strings.Replace(vars["hash"], "0x", "", -1))
+
+	if !utils.IsValidEth1Tx(hash) {
+		SendBadRequestResponse(w, r.URL.String(), "error invalid user operation hash. A user operation hash consists of an optional 0x prefix followed by 64 hexadecimal characters.")
+		return
+	}
+
+	errFields["hash"] = hash
+
+	userOp, err := db.BigtableClient.GetUserOperation(common.FromHex(hash))
+	if err != nil {
+		utils.LogError(err, "error could not get user operation", 0, errFields)
+		sendServerErrorResponse(w, r.URL.String(), "error could not get user operation")
+		return
+	}
+	if userOp == nil {
+		sendErrorWithCodeResponse(w, r.URL.String(), "user operation not found", http.StatusNotFound)
+		return
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{formatUserOpForApiResponse(userOp)})
+}
+
+// ApiEth1AddressUserOps godoc
+// @Summary Returns the ERC-4337 user operations of a given Ethereum address.
+// @Tags Execution
+// @Description Returns the account abstraction (ERC-4337) user operations a given Ethereum address took part in as sender, paymaster or bundler, most recent first. Use the returned page_token to fetch the next page.
+// @Produce json
+// @Param address path string true "provide an Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters". It can also be a valid ENS name.
+// @Param page_token query string false "page token returned by the previous request"
+// @Success 200 {object} types.ApiResponse{data=types.ApiEth1AddressUserOpsResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/address/{address}/userops [get]
+func ApiEth1AddressUserOps(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	errFields := map[string]interface{}{
+		"route": r.URL.String()}
+
+	vars := mux.Vars(r)
+
+	address := ReplaceEnsNameWithAddress(vars["address"])
+	address = strings.Replace(address, "0x", "", -1)
+	address = strings.ToLower(address)
+
+	if !utils.IsEth1Address(address) {
+		SendBadRequestResponse(w, r.URL.String(), "error invalid address. An Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+		return
+	}
+
+	pageToken := r.URL.Query().Get("page_token")
+
+	errFields["address"] = address
+	errFields["pageToken"] = pageToken
+
+	userOps, lastKey, err := db.BigtableClient.GetAddressUserOperations(common.FromHex(address), pageToken, db.DefaultInfScrollRows)
+	if err != nil {
+		utils.LogError(err, "error could not get user operations for address", 0, errFields)
+		sendServerErrorResponse(w, r.URL.String(), "error could not get user operations for address")
+		return
+	}
+
+	response := types.ApiEth1AddressUserOpsResponse{
+		UserOps:   make([]types.ApiEth1UserOpResponse, 0, len(userOps)),
+		PageToken: lastKey,
+	}
+	for _, u := range userOps {
+		response.UserOps = append(response.UserOps, formatUserOpForApiResponse(u))
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
+
+// ApiEth1AddressLabels godoc
+// @Summary Returns the labels of a given Ethereum address.
+// @Tags Execution
+// @Description Returns the approved labels (exchange, contract, bridge, mixer or entity) of a given Ethereum address. Labels are curated by admins or suggested by the community and approved by admins.
+// @Produce json
+// @Param address path string true "provide an Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters". It can also be a valid ENS name.
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiEth1AddressLabelResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/address/{address}/labels [get]
+func ApiEth1AddressLabels(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+
+	address := ReplaceEnsNameWithAddress(vars["address"])
+	address = strings.Replace(address, "0x", "", -1)
+	address = strings.ToLower(address)
+
+	if !utils.IsEth1Address(address) {
+		SendBadRequestResponse(w, r.URL.String(), "error invalid address. An Ethereum address consists of an optional 0x prefix followed by 40 hexadecimal characters.")
+		return
+	}
+
+	labels, err := db.GetAddressLabels(common.FromHex(address))
+	if err != nil {
+		utils.LogError(err, "error could not get labels for address", 0, map[string]interface{}{"route": r.URL.String(), "address": address})
+		sendServerErrorResponse(w, r.URL.String(), "error could not get labels for address")
+		return
+	}
+
+	response := make([]types.ApiEth1AddressLabelResponse, 0, len(labels))
+	for _, l := range labels {
+		response = append(response, types.ApiEth1AddressLabelResponse{
+			Label:    l.Label,
+			Category: l.Category,
+			Source:   l.Source,
+		})
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
+
+func formatUserOpForApiResponse(u *types.Eth1UserOperationIndexed) types.ApiEth1UserOpResponse {
+	beneficiary := ""
+	if len(u.Beneficiary) > 0 {
+		beneficiary = fmt.Sprintf("0x%x", u.Beneficiary)
+	}
+	return types.ApiEth1UserOpResponse{
+		UserOpHash:    fmt.Sprintf("0x%x", u.UserOpHash),
+		TxHash:        fmt.Sprintf("0x%x", u.TxHash),
+		BlockNumber:   u.BlockNumber,
+		Timestamp:     u.Time.Unix(),
+		EntryPoint:    fmt.Sprintf("0x%x", u.EntryPoint),
+		Sender:        fmt.Sprintf("0x%x", u.Sender),
+		Paymaster:     fmt.Sprintf("0x%x", u.Paymaster),
+		Bundler:       fmt.Sprintf("0x%x", u.Bundler),
+		Beneficiary:   beneficiary,
+		Nonce:         new(big.Int).SetBytes(u.Nonce).String(),
+		Success:       u.Success,
+		ActualGasCost: new(big.Int).SetBytes(u.ActualGasCost).String(),
+		ActualGasUsed: new(big.Int).SetBytes(u.ActualGasUsed).Uint64(),
+	}
+}
+
+// ApiEth1TxDecoded godoc
+// @Summary Returns the decoded input and event logs of a given transaction.
+// @Tags Execution
+// @Description Decodes the called method and its parameters as well as the emitted event logs of a transaction. The abi of verified contracts is used if available, otherwise the signature database is used. Parameters decoded via the signature database are named by their position (arg0, arg1, ...). Method and params are empty if the input could not be decoded.
+// @Produce json
+// @Param hash path string true "transaction hash"
+// @Success 200 {object} types.ApiResponse{data=types.ApiEth1TxDecodedResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/tx/{hash}/decoded [get]
+func ApiEth1TxDecoded(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	errFields := map[string]interface{}{
+		"route": r.URL.String()}
+
+	vars := mux.Vars(r)
+	hash := strings.ToLower(strings.Replace(vars["hash"], "0x", "", -1))
+
+	if !utils.IsValidEth1Tx(hash) {
+		SendBadRequestResponse(w, r.URL.String(), "error invalid transaction hash. A transaction hash consists of an optional 0x prefix followed by 64 hexadecimal characters.")
+		return
+	}
+
+	errFields["hash"] = hash
+
+	txData, err := eth1data.GetEth1Transaction(common.HexToHash(hash), GetCurrency(r))
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) || errors.Is(err, eth1data.ErrTxIsPending) {
+			SendBadRequestResponse(w, r.URL.String(), "error transaction not found or still pending")
+			return
+		}
+		utils.LogError(err, "error could not get transaction", 0, errFields)
+		sendServerErrorResponse(w, r.URL.String(), "error could not get transaction")
+		return
+	}
+
+	response := types.ApiEth1TxDecodedResponse{
+		Hash:   txData.Hash.Hex(),
+		Method: txData.Method,
+		Params: []types.ApiEth1DecodedParamResponse{},
+		Logs:   make([]types.ApiEth1DecodedLogResponse, 0, len(txData.Events)),
+	}
+	if txData.DecodedCallData != nil {
+		response.Signature = txData.DecodedCallData.Signature
+		for _, param := range txData.DecodedCallData.Params {
+			response.Params = append(response.Params, types.ApiEth1DecodedParamResponse{
+				Name:  param.Name,
+				Type:  param.Type,
+				Value: param.Value,
+			})
+		}
+	}
+
+	for _, event := range txData.Events {
+		logResponse := types.ApiEth1DecodedLogResponse{
+			Address: event.Address.Hex(),
+			Name:    event.Name,
+			Topics:  make([]string, 0, len(event.Topics)),
+			Data:    fmt.Sprintf("0x%x", event.Data),
+			Params:  make([]types.ApiEth1DecodedParamResponse, 0, len(event.DecodedData)),
+		}
+		for _, topic := range event.Topics {
+			logResponse.Topics = append(logResponse.Topics, topic.Hex())
+		}
+		names := maps.Keys(event.DecodedData)
+		sort.Strings(names)
+		for _, name := range names {
+			logResponse.Params = append(logResponse.Params, types.ApiEth1DecodedParamResponse{
+				Name:  name,
+				Type:  event.DecodedData[name].Type,
+				Value: event.DecodedData[name].Value,
+			})
+		}
+		response.Logs = append(response.Logs, logResponse)
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
+
+// ApiMempoolStats godoc
+// @Summary Returns the current and historical mempool stats.
+// @Tags Execution
+// @Description Returns the size, gas price percentiles (in GWei) and top spenders of the executable transactions of the mempool, as well as snapshots of the mempool stats taken once per minute. Top spenders are the senders with the highest maximum fees (gas limit * gas price) of their pending transactions, fees and values are in ether.
+// @Produce json
+// @Param hours query int false "Amount of hours of history to return (default 1, max 24)"
+// @Success 200 {object} types.ApiResponse{data=types.ApiMempoolStatsResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/execution/mempool/stats [get]
+func ApiMempoolStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	hours := uint64(1)
+	if q := r.URL.Query().Get("hours"); q != "" {
+		var err error
+		hours, err = strconv.ParseUint(q, 10, 64)
+		if err != nil || hours < 1 || hours > 24 {
+			SendBadRequestResponse(w, r.URL.String(), "invalid hours provided, must be between 1 and 24")
+			return
+		}
+	}
+
+	history, err := db.GetMempoolSnapshotHistory(time.Now().Add(-time.Hour*time.Duration(hours)), time.Minute)
+	if err != nil {
+		utils.LogError(err, "error could not get mempool snapshot history", 0, map[string]interface{}{"route": r.URL.String()})
+		sendServerErrorResponse(w, r.URL.String(), "error could not get mempool snapshot history")
+		return
+	}
+
+	current := services.LatestMempoolStats()
+	response := types.ApiMempoolStatsResponse{
+		Current: formatMempoolStatsForApiResponse(current),
+		History: make([]types.ApiMempoolSnapshotResponse, 0, len(history)),
+	}
+	response.Current.TopSpenders = make([]types.ApiMempoolSpenderResponse, 0, len(current.TopSpenders))
+	for _, spender := range current.TopSpenders {
+		response.Current.TopSpenders = append(response.Current.TopSpenders, types.ApiMempoolSpenderResponse{
+			Address:    fmt.Sprintf("0x%x", spender.Address),
+			TxCount:    spender.TxCount,
+			TotalFees:  utils.WeiToEther(spender.TotalFees.BigInt()).String(),
+			TotalValue: utils.WeiToEther(spender.TotalValue.BigInt()).String(),
+		})
+	}
+	for _, h := range history {
+		response.History = append(response.History, formatMempoolStatsForApiResponse(h))
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
+
+func formatMempoolStatsForApiResponse(stats *types.MempoolStats) types.ApiMempoolSnapshotResponse {
+	return types.ApiMempoolSnapshotResponse{
+		Timestamp:    stats.Ts.Unix(),
+		PendingCount: stats.PendingCount,
+		QueuedCount:  stats.QueuedCount,
+		BaseFeeCount: stats.BaseFeeCount,
+		PendingGas:   stats.PendingGas,
+		GasPriceP10:  stats.GasPriceP10,
+		GasPriceP25:  stats.GasPriceP25,
+		GasPriceP50:  stats.GasPriceP50,
+		GasPriceP75:  stats.GasPriceP75,
+		GasPriceP90:  stats.GasPriceP90,
+	}
+}
+
 func formatBlocksForApiResponse(blocks []*types.Eth1BlockIndexed, relaysData map[common.Hash]types.RelaysData, beaconDataMap map[uint64]types.ExecBlockProposer, sortFunc func(i, j types.ExecutionBlockApiResponse) bool) []types.ExecutionBlockApiResponse {
 	results := []types.ExecutionBlockApiResponse{}
 
@@ -488,8 +1005,8 @@ func getValidatorExecutionPerformance(queryIndices []uint64) ([]types.ExecutionP
 	last7dTimestamp := time.Now().Add(-7 * utils.Day)
 	last1dTimestamp := time.Now().Add(-1 * utils.Day)
 
-	monthRange := latestEpoch - 7200
-	if latestEpoch < 7200 {
+	monthRange := latestEpoch - utils.EpochsPerDay()*32
+	if latestEpoch < utils.EpochsPerDay()*32 {
 		monthRange = 0
 	}
 	validatorsPQArray := pq.Array(queryIndices)
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/lib/pq"
+)
+
+// ApiAppWidget godoc
+// @Summary Get a compact summary of the saved validators of the user for home screen widgets
+// @Tags User
+// @Description The summary is pre-aggregated once per epoch, widgets polling more often receive the cached summary of the current epoch.
+// @Produce json
+// @Success 200 {object} types.ApiResponse{data=types.ApiAppWidgetResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/app/widget [get]
+func ApiAppWidget(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	claims := getAuthClaims(r)
+	if claims == nil {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	epoch := services.LatestEpoch()
+	cacheDur := time.Second * time.Duration(utils.Config.Chain.ClConfig.SecondsPerSlot*utils.Config.Chain.ClConfig.SlotsPerEpoch)
+	cacheKey := fmt.Sprintf("%d:appWidget:%d:%d", utils.Config.Chain.ClConfig.DepositChainID, claims.UserID, epoch)
+	cached := &types.ApiAppWidgetResponse{}
+	if _, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, cacheDur, cached); err == nil {
+		SendOKResponse(j, r.URL.String(), []interface{}{cached})
+		return
+	}
+
+	widget, err := getAppWidget(claims.UserID, getUserPremium(r).MaxValidators, epoch)
+	if err != nil {
+		utils.LogError(err, "error retrieving app widget", 0, map[string]interface{}{"userId": claims.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	err = cache.TieredCache.Set(cacheKey, widget, cacheDur)
+	if err != nil {
+		utils.LogError(err, fmt.Errorf("error setting tieredCache for app widget with key %v", cacheKey), 0)
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{widget})
+}
+
+// getAppWidget aggregates the state, balance, income and next duty of the saved validators of the user
+func getAppWidget(userId uint64, maxValidators int, epoch uint64) (*types.ApiAppWidgetResponse, error) {
+	widget := &types.ApiAppWidgetResponse{Epoch: epoch}
+
+	tagged, err := db.GetTaggedValidators(db.WatchlistFilter{
+		UserId:         userId,
+		Tag:            types.ValidatorTagsWatchlist,
+		JoinValidators: true,
+		Network:        utils.GetNetwork(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving saved validators: %w", err)
+	}
+	validators := make([]uint64, 0, len(tagged))
+	for _, v := range tagged {
+		// validators which are not yet known to the beacon chain are joined as empty validators
+		if v.Validator == nil || len(v.Validator.PublicKey) == 0 {
+			continue
+		}
+		validators = append(validators, v.Validator.Index)
+		if len(validators) >= maxValidators {
+			break
+		}
+	}
+	widget.Validators = uint64(len(validators))
+	if len(validators) == 0 {
+		return widget, nil
+	}
+
+	income := struct {
+		Active       uint64  `db:"active"`
+		ClIncomeGwei int64   `db:"cl_income_1d"`
+		ElIncomeWei  float64 `db:"el_income_1d"`
+	}{}
+	err = db.ReaderDb.Get(&income, `
+		SELECT
+			COUNT(*) FILTER (WHERE v.activationepoch <= $2 AND v.exitepoch > $2) AS active,
+			COALESCE(SUM(vp.cl_performance_1d), 0) AS cl_income_1d,
+			COALESCE(SUM(vp.el_performance_1d), 0)::FLOAT AS el_income_1d
+		FROM validators v
+		LEFT JOIN validator_performance vp ON vp.validatorindex = v.validatorindex
+		WHERE v.validatorindex = ANY($1)`, pq.Array(validators), epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving income of saved validators: %w", err)
+	}
+	widget.Active = income.Active
+	widget.Income24h = float64(income.ClIncomeGwei)/1e9 + income.ElIncomeWei/1e18
+
+	balances, err := db.BigtableClient.GetValidatorBalanceHistory(validators, epoch, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving balances of saved validators: %w", err)
+	}
+	for _, history := range balances {
+		if len(history) > 0 {
+			widget.Balance += float64(history[0].Balance) / 1e9
+		}
+	}
+
+	offline, err := getOfflineValidatorIndices(validators, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving offline saved validators: %w", err)
+	}
+	widget.Offline = uint64(len(offline))
+
+	widget.NextDuty, err = getNextValidatorsDuty(validators, epoch)
+	if err != nil {
+		return nil, err
+	}
+	return widget, nil
+}
+
+// getNextValidatorsDuty returns the earliest scheduled proposal or current or upcoming sync committee of the validators,
+// nil if there is none
+func getNextValidatorsDuty(validators []uint64, epoch uint64) (*types.ApiAppWidgetDuty, error) {
+	currentSlot := utils.TimeToSlot(uint64(time.Now().Unix()))
+
+	var duty *types.ApiAppWidgetDuty
+	proposal := struct {
+		Proposer uint64 `db:"proposer"`
+		Slot     uint64 `db:"slot"`
+	}{}
+	err := db.ReaderDb.Get(&proposal, `
+		SELECT proposer, slot FROM blocks
+		WHERE proposer = ANY($1) AND status = '0' AND slot >= $2
+		ORDER BY slot LIMIT 1`, pq.Array(validators), currentSlot)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("error retrieving scheduled proposals: %w", err)
+	}
+	if err == nil {
+		duty = &types.ApiAppWidgetDuty{Type: "proposal", Validator: proposal.Proposer, Slot: proposal.Slot}
+	}
+
+	if epoch >= utils.Config.Chain.ClConfig.AltairForkEpoch {
+		sync := struct {
+			Period    uint64 `db:"period"`
+			Validator uint64 `db:"validatorindex"`
+		}{}
+		err = db.ReaderDb.Get(&sync, `
+			SELECT period, validatorindex FROM sync_committees
+			WHERE validatorindex = ANY($1) AND period >= $2
+			ORDER BY period, validatorindex LIMIT 1`, pq.Array(validators), utils.SyncPeriodOfEpoch(epoch))
+		if err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("error retrieving sync committee duties: %w", err)
+		}
+		if err == nil {
+			slot := utils.FirstEpochOfSyncPeriod(sync.Period) * utils.Config.Chain.ClConfig.SlotsPerEpoch
+			if slot < currentSlot {
+				slot = currentSlot
+			}
+			if duty == nil || slot < duty.Slot {
+				duty = &types.ApiAppWidgetDuty{Type: "sync", Validator: sync.Validator, Slot: slot}
+			}
+		}
+	}
+
+	if duty != nil {
+		duty.Ts = utils.SlotToTime(duty.Slot).Unix()
+	}
+	return duty, nil
+}
@@ -203,6 +203,61 @@ func Login(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+type loginUser struct {
+	ID        uint64 `db:"id"`
+	Email     string `db:"email"`
+	Password  string `db:"password"`
+	Confirmed bool   `db:"email_confirmed"`
+	ProductID string `db:"product_id"`
+	Active    bool   `db:"active"`
+	UserGroup string `db:"user_group"`
+	Locale    string `db:"locale"`
+}
+
+// getLoginUser returns the credentials and the most relevant active mobile subscription of the user with the email
+func getLoginUser(email string) (*loginUser, error) {
+	user := &loginUser{}
+	err := db.FrontendWriterDB.Get(user, `
+		WITH
+			latest_and_greatest_sub AS (
+				SELECT user_id, product_id, active, created_at FROM users_app_subscriptions 
+				left join users on users.id = user_id 
+				WHERE users.email = $1 AND active = true AND product_id IN ('orca.yearly', 'orca', 'dolphin.yearly', 'dolphin', 'guppy.yearly', 'guppy', 'whale', 'goldfish', 'plankton')
+				ORDER BY CASE product_id
+					WHEN 'orca.yearly'    THEN  1
+					WHEN 'orca'           THEN  2
+					WHEN 'dolphin.yearly' THEN  3
+					WHEN 'dolphin'        THEN  4
+					WHEN 'guppy.yearly'   THEN  5
+					WHEN 'guppy'          THEN  6
+					WHEN 'whale'          THEN  7
+					WHEN 'goldfish'       THEN  8
+					WHEN 'plankton'       THEN  9
+					ELSE                       10  -- For any other product_id values
+				END, users_app_subscriptions.created_at DESC LIMIT 1
+			)
+		SELECT users.id, email, password, email_confirmed, COALESCE(product_id, '') as product_id, COALESCE(active, false) as active, COALESCE(user_group, '') AS user_group, locale 
+		FROM users 
+		left join latest_and_greatest_sub on latest_and_greatest_sub.user_id = users.id  
+		WHERE email = $1`, email)
+	return user, err
+}
+
+// loginRedirectParam returns the query of the oauth authorization request that required the login, if any
+func loginRedirectParam(r *http.Request) string {
+	redirectParam := ""
+	redirectURI := r.FormValue("oauth_redirect_uri")
+	if redirectURI != "" {
+		redirectParam = "?redirect_uri=" + redirectURI
+
+		state := r.FormValue("state")
+		if state != "" {
+			redirectParam += "&state=" + state
+		}
+	}
+	return redirectParam
+}
+
 // LoginPost handles authenticating the user.
 func LoginPost(w http.ResponseWriter, r *http.Request) {
 	if err := utils.HandleRecaptcha(w, r, "/login"); err != nil {
@@ -236,50 +291,9 @@ func LoginPost(w http.ResponseWriter, r *http.Request) {
 	email = strings.ToLower(email)
 	pwd := r.FormValue("password")
 
-	user := struct {
-		ID        uint64 `db:"id"`
-		Email     string `db:"email"`
-		Password  string `db:"password"`
-		Confirmed bool   `db:"email_confirmed"`
-		ProductID string `db:"product_id"`
-		Active    bool   `db:"active"`
-		UserGroup string `db:"user_group"`
-	}{}
-
-	redirectParam := ""
-	redirectURI := r.FormValue("oauth_redirect_uri")
-	if redirectURI != "" {
-		redirectParam = "?redirect_uri=" + redirectURI
-
-		state := r.FormValue("state")
-		if state != "" {
-			redirectParam += "&state=" + state
-		}
-	}
+	redirectParam := loginRedirectParam(r)
 
-	err = db.FrontendWriterDB.Get(&user, `
-		WITH
-			latest_and_greatest_sub AS (
-				SELECT user_id, product_id, active, created_at FROM users_app_subscriptions 
-				left join users on users.id = user_id 
-				WHERE users.email = $1 AND active = true AND product_id IN ('orca.yearly', 'orca', 'dolphin.yearly', 'dolphin', 'guppy.yearly', 'guppy', 'whale', 'goldfish', 'plankton')
-				ORDER BY CASE product_id
-					WHEN 'orca.yearly'    THEN  1
-					WHEN 'orca'           THEN  2
-					WHEN 'dolphin.yearly' THEN  3
-					WHEN 'dolphin'        THEN  4
-					WHEN 'guppy.yearly'   THEN  5
-					WHEN 'guppy'          THEN  6
-					WHEN 'whale'          THEN  7
-					WHEN 'goldfish'       THEN  8
-					WHEN 'plankton'       THEN  9
-					ELSE                       10  -- For any other product_id values
-				END, users_app_subscriptions.created_at DESC LIMIT 1
-			)
-		SELECT users.id, email, password, email_confirmed, COALESCE(product_id, '') as product_id, COALESCE(active, false) as active, COALESCE(user_group, '') AS user_group 
-		FROM users 
-		left join latest_and_greatest_sub on latest_and_greatest_sub.user_id = users.id  
-		WHERE email = $1`, email)
+	user, err := getLoginUser(email)
 	if err != nil {
 		if err != sql.ErrNoRows {
 			logger.Errorf("error retrieving password for user %v: %v", email, err)
@@ -299,20 +313,53 @@ func LoginPost(w http.ResponseWriter, r *http.Request) {
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(pwd))
 	if err != nil {
+		auditUserAction(r, user.ID, types.UserAuditLoginFailed, nil)
 		session.AddFlash("Error: Invalid email or password!")
 		session.Save(r, w)
 		http.Redirect(w, r, "/login"+redirectParam, http.StatusSeeOther)
 		return
 	}
 
+	hasTwoFactor, err := db.HasUserWebAuthnCredentials(user.ID)
+	if err != nil {
+		utils.LogError(err, "error checking two-factor authentication of user", 0, map[string]interface{}{"userId": user.ID})
+		session.AddFlash(authInternalServerErrorFlashMsg)
+		session.Save(r, w)
+		http.Redirect(w, r, "/login"+redirectParam, http.StatusSeeOther)
+		return
+	}
+	if hasTwoFactor {
+		// the password is correct, the login is completed after the second factor has been verified in Login2FAPost
+		session.SetValue("2fa_user_id", user.ID)
+		session.SetValue("2fa_redirect", redirectParam)
+		session.SetValue("2fa_ts", time.Now().Unix())
+		session.DeleteValue("2fa_attempts")
+		session.Save(r, w)
+		http.Redirect(w, r, "/login/2fa", http.StatusSeeOther)
+		return
+	}
+
+	completeLogin(w, r, session, user, redirectParam)
+}
+
+// completeLogin authenticates the session after all factors of the login of the user have been verified
+func completeLogin(w http.ResponseWriter, r *http.Request, session *utils.CustomSession, user *loginUser, redirectParam string) {
 	if !user.Active {
 		user.ProductID = ""
 	}
 
+	session.DeleteValue("2fa_user_id")
+	session.DeleteValue("2fa_redirect")
+	session.DeleteValue("2fa_ts")
+	session.DeleteValue("2fa_attempts")
+
 	session.SetValue("authenticated", true)
 	session.SetValue("user_id", user.ID)
 	session.SetValue("subscription", user.ProductID)
 	session.SetValue("user_group", user.UserGroup)
+	session.SetValue("locale", user.Locale)
+	trackUserSession(r, session, user.ID)
+	auditUserAction(r, user.ID, types.UserAuditLogin, nil)
 
 	// save datatable state settings from anon session
 	dataTableStatePrefix := "table:state:" + utils.GetNetwork() + ":"
@@ -337,6 +384,14 @@ func LoginPost(w http.ResponseWriter, r *http.Request) {
 	}
 	// session.AddFlash("Successfully logged in")
 
+	if redirectParam != "" {
+		// continue the oauth authorization request that required the login including its scope and pkce parameters
+		if query, ok := session.GetValue("oauth_authorize_query").(string); ok && query != "" {
+			redirectParam = "?" + query
+		}
+		session.DeleteValue("oauth_authorize_query")
+	}
+
 	session.Save(r, w)
 
 	logger.WithFields(
@@ -366,13 +421,26 @@ func LoginPost(w http.ResponseWriter, r *http.Request) {
 // Logout handles ending the user session.
 func Logout(w http.ResponseWriter, r *http.Request) {
 
-	_, session, err := getUserSession(r)
+	user, session, err := getUserSession(r)
 	if err != nil {
 		logger.Errorf("error retrieving session: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
+	if user.Authenticated {
+		tokenHash := sessionTokenHash(session.SCS.Token(r.Context()))
+		err = db.EndUserSessionByTokenHash(tokenHash)
+		if err != nil {
+			utils.LogError(err, "error ending user session", 0, map[string]interface{}{"userId": user.UserID})
+		}
+		err = session.SCS.Store.Delete(sessionStoreRefKey(tokenHash))
+		if err != nil {
+			utils.LogError(err, "error deleting user session reference", 0, map[string]interface{}{"userId": user.UserID})
+		}
+		auditUserAction(r, user.UserID, types.UserAuditLogout, nil)
+	}
+
 	session.SetValue("subscription", "")
 	session.SetValue("authenticated", false)
 	session.DeleteValue("user_id")
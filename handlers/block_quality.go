@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// blockQualityDays is the amount of days the block quality leaderboard is aggregated over
+const blockQualityDays = 30
+
+// blockQualityMinBlocks is the minimum amount of blocks an entity needs to have proposed to be listed in the leaderboard
+const blockQualityMinBlocks = 10
+
+// BlockQuality renders the attestation packing leaderboard of entities and the worst packed recent blocks
+func BlockQuality(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	templateFiles := append(layoutTemplateFiles, "block_quality.html")
+	var blockQualityTemplate = templates.GetTemplate(templateFiles...)
+
+	data := InitPageData(w, r, "validators", "/validators/block-quality", "Block Quality", templateFiles)
+
+	fromSlot := blockQualityFromSlot(blockQualityDays)
+
+	entities, err := db.GetAttestationPackingLeaderboard(fromSlot, blockQualityMinBlocks)
+	if err != nil {
+		utils.LogError(err, "error retrieving attestation packing leaderboard", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	worstBlocks, err := db.GetWorstPackedBlocks(fromSlot, 25)
+	if err != nil {
+		utils.LogError(err, "error retrieving worst packed blocks", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data.Data = &types.BlockQualityPageData{
+		Days:        blockQualityDays,
+		Entities:    entities,
+		WorstBlocks: worstBlocks,
+	}
+
+	if handleTemplateError(w, r, "block_quality.go", "BlockQuality", "", blockQualityTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// blockQualityFromSlot returns the first slot of the given amount of days before the latest slot
+func blockQualityFromSlot(days uint64) uint64 {
+	lookback := days * utils.SlotsPerDay()
+	latestSlot := services.LatestSlot()
+	if latestSlot <= lookback {
+		return 0
+	}
+	return latestSlot - lookback
+}
@@ -1,37 +1,186 @@
 package handlers
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
 	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 )
 
+const (
+	// calculatorCompoundingMultiplier is the maximum effective balance of 0x02 validators as multiple of the activation
+	// balance, 2048 ETH on mainnet
+	calculatorCompoundingMultiplier      = 64
+	calculatorEffectiveBalanceHysteresis = 1.25
+	calculatorMaxDays                    = 3650
+)
+
 // StakingCalculator renders stakingCalculatorTemplate
 func StakingCalculator(w http.ResponseWriter, r *http.Request) {
 	templateFiles := append(layoutTemplateFiles, "calculator.html")
 	var stakingCalculatorTemplate = templates.GetTemplate(templateFiles...)
 
-	calculatorPageData := types.StakingCalculatorPageData{}
-
-	total, err := db.GetTotalEligibleEther()
+	network, err := getStakingCalculatorNetworkStats()
 	if err != nil {
-		logger.WithError(err).Error("error getting total staked ether")
+		logger.WithError(err).Error("error getting staking calculator network stats")
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	calculatorPageData.TotalStaked = total
-	calculatorPageData.EtherscanApiBaseUrl = utils.GetEtherscanAPIBaseUrl(true)
-
 	w.Header().Set("Content-Type", "text/html")
 
 	data := InitPageData(w, r, "stats", "/calculator", "Staking calculator", templateFiles)
-	data.Data = calculatorPageData
+	data.Data = types.StakingCalculatorPageData{
+		Network:               network,
+		ActivationBalance:     utils.ActivationBalance(),
+		CompoundingMaxBalance: utils.ActivationBalance() * calculatorCompoundingMultiplier,
+		Currency:              utils.Config.Frontend.ClCurrency,
+	}
 
 	if handleTemplateError(w, r, "calculator.go", "StakingCalculator", "", stakingCalculatorTemplate.ExecuteTemplate(w, "layout", data)) != nil {
 		return // an error has occurred and was processed
 	}
 }
+
+// ApiStakingCalculator godoc
+// @Summary Project the staking rewards of a stake based on the current network statistics
+// @Tags Calculator
+// @Description The projection uses the average consensus and execution apr of the network (ETH.STORE®) and the mev statistics of the last 31 days.
+// @Description The rewards start after the estimated waiting time in the activation queue. 0x01 validators use 32 ETH each and skim their rewards,
+// @Description 0x02 validators hold up to 2048 ETH each and compound their consensus rewards.
+// @Produce  json
+// @Param  amount query number true "The amount of ETH to stake (at least 32 ETH on mainnet)"
+// @Param  credentials query string false "The withdrawal credential type of the validators, 0x01 (default) or 0x02"
+// @Param  days query int false "The horizon of the projection in days (default 365, at most 3650)"
+// @Success 200 {object} types.ApiResponse{data=types.ApiStakingCalculatorResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/calculator/staking [get]
+func ApiStakingCalculator(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	q := r.URL.Query()
+
+	amount, err := strconv.ParseFloat(q.Get("amount"), 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) || amount < utils.ActivationBalance() {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("invalid amount provided, must be at least %v", utils.ActivationBalance()))
+		return
+	}
+
+	credentialType := q.Get("credentials")
+	if credentialType == "" {
+		credentialType = "0x01"
+	}
+	if credentialType != "0x01" && credentialType != "0x02" {
+		SendBadRequestResponse(w, r.URL.String(), "invalid credentials provided, must be 0x01 or 0x02")
+		return
+	}
+
+	days := parseUintWithDefault(q.Get("days"), 365)
+	if days == 0 || days > calculatorMaxDays {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("invalid days provided, must be between 1 and %v", calculatorMaxDays))
+		return
+	}
+
+	network, err := getStakingCalculatorNetworkStats()
+	if err != nil {
+		logger.WithError(err).Error("error getting staking calculator network stats")
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{projectStakingRewards(amount, credentialType, days, network)})
+}
+
+func getStakingCalculatorNetworkStats() (*types.StakingCalculatorNetworkStats, error) {
+	network, err := db.GetStakingCalculatorNetworkStats(31)
+	if err != nil {
+		return nil, err
+	}
+
+	network.EnteringValidators = services.LatestIndexPageData().EnteringValidators
+	if stats := services.GetLatestStats(); stats.ValidatorActivationChurnLimit != nil {
+		network.ActivationChurnLimit = *stats.ValidatorActivationChurnLimit
+	}
+	if network.ActivationChurnLimit > 0 {
+		network.ActivationWaitingDays = float64(network.EnteringValidators) / float64(network.ActivationChurnLimit) / float64(utils.EpochsPerDay())
+	}
+	return network, nil
+}
+
+// projectStakingRewards simulates the rewards of a stake day by day. The stake starts earning after the activation queue
+// has been passed, consensus and execution rewards as well as the proposals are proportional to the effective balance.
+func projectStakingRewards(amount float64, credentialType string, days uint64, network *types.StakingCalculatorNetworkStats) *types.ApiStakingCalculatorResponse {
+	res := &types.ApiStakingCalculatorResponse{
+		Amount:         amount,
+		CredentialType: credentialType,
+		Days:           days,
+		Network:        network,
+		Projection:     make([]*types.ApiStakingCalculatorProjectionDay, 0, days/7+2),
+	}
+
+	minEffectiveBalance := utils.ActivationBalance()
+	maxEffectiveBalance := minEffectiveBalance * calculatorCompoundingMultiplier
+
+	compounding := credentialType == "0x02"
+	var balance float64 // balance of a single validator
+	if compounding {
+		res.Validators = uint64(math.Ceil(amount / maxEffectiveBalance))
+		balance = amount / float64(res.Validators)
+	} else {
+		res.Validators = uint64(amount / minEffectiveBalance)
+		balance = minEffectiveBalance
+	}
+	res.StakedAmount = balance * float64(res.Validators)
+	effectiveBalance := math.Min(math.Floor(balance), maxEffectiveBalance)
+
+	proposalsPerEthAndDay := 0.0
+	if network.TotalStaked > 0 {
+		proposalsPerEthAndDay = float64(utils.EpochsPerDay()*utils.Config.Chain.ClConfig.SlotsPerEpoch) / float64(network.TotalStaked)
+	}
+
+	validators := float64(res.Validators)
+	waitingDays := uint64(math.Ceil(network.ActivationWaitingDays))
+	res.Projection = append(res.Projection, &types.ApiStakingCalculatorProjectionDay{Balance: res.StakedAmount})
+	for day := uint64(1); day <= days; day++ {
+		if day > waitingDays {
+			consensusRewards := effectiveBalance * network.ConsensusApr / 365
+			res.ConsensusRewards += consensusRewards * validators
+			res.ExecutionRewards += effectiveBalance * network.ExecutionApr / 365 * validators
+			res.ExpectedProposals += effectiveBalance * proposalsPerEthAndDay * validators
+
+			// the effective balance of compounding validators follows the balance once it exceeds the hysteresis
+			if compounding && effectiveBalance < maxEffectiveBalance {
+				balance += consensusRewards
+				if balance >= effectiveBalance+calculatorEffectiveBalanceHysteresis {
+					effectiveBalance = math.Min(math.Floor(balance), maxEffectiveBalance)
+				}
+			}
+		}
+
+		if day%7 == 0 || day == days {
+			res.Projection = append(res.Projection, &types.ApiStakingCalculatorProjectionDay{
+				Day:              day,
+				Balance:          res.StakedAmount + res.ConsensusRewards,
+				ConsensusRewards: res.ConsensusRewards,
+				ExecutionRewards: res.ExecutionRewards,
+			})
+		}
+	}
+
+	// mev is part of the execution rewards, it is estimated from the expected proposals and the mev of recent blocks
+	res.MevRewards = math.Min(res.ExpectedProposals*network.MevBlockShare*network.AvgMevPerBlock, res.ExecutionRewards)
+	res.TotalRewards = res.ConsensusRewards + res.ExecutionRewards
+	if res.StakedAmount > 0 {
+		res.ProjectedApr = res.TotalRewards / res.StakedAmount / float64(days) * 365
+	}
+
+	return res
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// censorshipHistoryDays is the amount of days shown in the inclusion delay chart
+const censorshipHistoryDays = 90
+
+// Censorship compares the inclusion of transactions of watchlisted addresses across relays and builders
+func Censorship(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	templateFiles := append(layoutTemplateFiles, "censorship.html")
+	var censorshipTemplate = templates.GetTemplate(templateFiles...)
+
+	data := InitPageData(w, r, "services", "/censorship", "Censorship", templateFiles)
+
+	day, err := db.GetLatestCensorshipStatsDay()
+	if err != nil {
+		utils.LogError(err, "error retrieving latest censorship stats day", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	stats, err := db.GetCensorshipDailyStats(day)
+	if err != nil {
+		utils.LogError(err, "error retrieving censorship stats", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	pageData := &types.CensorshipPageData{
+		Day:         day,
+		DayStart:    utils.DayToTime(int64(day)),
+		Relays:      []*types.CensorshipDailyStats{},
+		Builders:    []*types.CensorshipDailyStats{},
+		LastUpdated: time.Now(),
+	}
+	for _, s := range stats {
+		switch s.EntityType {
+		case types.CensorshipEntityNetwork:
+			pageData.Network = s
+		case types.CensorshipEntityRelay:
+			s.Name = s.Entity
+			pageData.Relays = append(pageData.Relays, s)
+		case types.CensorshipEntityBuilder:
+			if s.Entity == types.CensorshipEntityLocalBuilder {
+				s.Name = "Locally Built"
+			} else {
+				s.Name = utils.FormatBuilderName(s.Entity, s.BuilderExtraData)
+			}
+			pageData.Builders = append(pageData.Builders, s)
+		}
+	}
+
+	pageData.RecentTxs, err = db.GetRecentCensorshipWatchedTxs(50)
+	if err != nil {
+		utils.LogError(err, "error retrieving recent watched txs", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	fromDay := uint64(0)
+	if day > censorshipHistoryDays {
+		fromDay = day - censorshipHistoryDays
+	}
+	history, err := db.GetCensorshipNetworkHistory(fromDay)
+	if err != nil {
+		utils.LogError(err, "error retrieving censorship history", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	delayData := make([][]float64, 0, len(history))
+	skippedData := make([][]float64, 0, len(history))
+	for _, h := range history {
+		ts := float64(utils.DayToTime(int64(h.Day)).Unix() * 1000)
+		if h.AvgInclusionDelay.Valid {
+			delayData = append(delayData, []float64{ts, h.AvgInclusionDelay.Float64})
+		}
+		if h.AvgSkippedBlocks.Valid {
+			skippedData = append(skippedData, []float64{ts, h.AvgSkippedBlocks.Float64})
+		}
+	}
+	pageData.DelaySeries = []*types.GenericChartDataSeries{
+		{Name: "Avg. Inclusion Delay (s)", Data: delayData},
+		{Name: "Avg. Skipped Blocks", Data: skippedData},
+	}
+
+	data.Data = pageData
+
+	if handleTemplateError(w, r, "censorship.go", "Censorship", "", censorshipTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
@@ -67,6 +67,10 @@ func Chart(w http.ResponseWriter, r *http.Request) {
 	switch chartVar {
 	case "slotviz":
 		SlotViz(w, r)
+	case "staking-flows":
+		StakingFlows(w, r)
+	case "validator-set-projection":
+		ValidatorSetProjection(w, r)
 	default:
 		GenericChart(w, r)
 	}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/ratelimit"
+
+	"golang.org/x/time/rate"
+)
+
+type clientRateLimiterClient struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// clientRateLimiter rate limits expensive endpoints per client independently of the api rate limits, clients that
+// have not been seen for a few minutes are removed
+type clientRateLimiter struct {
+	clients map[string]*clientRateLimiterClient
+	mu      sync.Mutex
+	cleanup sync.Once
+}
+
+func newClientRateLimiter() *clientRateLimiter {
+	return &clientRateLimiter{clients: make(map[string]*clientRateLimiterClient)}
+}
+
+func (rl *clientRateLimiter) allow(key string, limit rate.Limit, burst int) bool {
+	rl.cleanup.Do(func() {
+		go func() {
+			for {
+				time.Sleep(time.Minute)
+				rl.mu.Lock()
+				for key, client := range rl.clients {
+					if time.Since(client.lastSeen) > 3*time.Minute {
+						delete(rl.clients, key)
+					}
+				}
+				rl.mu.Unlock()
+			}
+		}()
+	})
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	client, found := rl.clients[key]
+	if !found {
+		client = &clientRateLimiterClient{limiter: rate.NewLimiter(limit, burst)}
+		rl.clients[key] = client
+	}
+	client.lastSeen = time.Now()
+	return client.limiter.Allow()
+}
+
+// allowIP applies the rate limit to the ip of the request, it writes the error response and returns false if the
+// request must not be served
+func (rl *clientRateLimiter) allowIP(w http.ResponseWriter, r *http.Request, limit rate.Limit, burst int) bool {
+	if !rl.allow("ip:"+ratelimit.GetIP(r), limit, burst) {
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		return false
+	}
+	return true
+}
@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/exporter"
 	"github.com/gobitfly/eth2-beaconchain-explorer/price"
 	"github.com/gobitfly/eth2-beaconchain-explorer/services"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
@@ -498,6 +499,20 @@ func GetCurrency(r *http.Request) string {
 	return utils.Config.Frontend.MainCurrency
 }
 
+// GetLanguage returns the language the page is rendered in. A language chosen explicitly (language cookie) takes
+// precedence over the language of the account of the user, which takes precedence over the Accept-Language header.
+func GetLanguage(r *http.Request) string {
+	if cookie, err := r.Cookie("language"); err == nil {
+		if utils.IsSupportedLanguage(cookie.Value) {
+			return cookie.Value
+		}
+	}
+	if user := getUser(r); user.Authenticated && utils.IsSupportedLanguage(user.Locale) {
+		return user.Locale
+	}
+	return utils.MatchLanguage(r.Header.Get("Accept-Language"))
+}
+
 func GetCurrencySymbol(r *http.Request) string {
 	cookie, err := r.Cookie("currency")
 	if err != nil {
@@ -730,6 +745,186 @@ func GetWithdrawableCountFromCursor(epoch uint64, validatorindex uint64, cursor
 	}
 }
 
+// estimateNextWithdrawal estimates when the withdrawal sweep reaches the validator and how much it would withdraw at its
+// current balance, it returns nil if the validator is not eligible for a withdrawal. The caller has to make sure the
+// validator has (or will have) execution layer withdrawal credentials.
+func estimateNextWithdrawal(v *types.Validator, epoch, cursor, lastWithdrawalEpoch uint64) (*types.WithdrawalEstimate, error) {
+	isFullWithdrawal := v.Balance > 0 && v.WithdrawableEpoch <= epoch
+	isPartialWithdrawal := v.EffectiveBalance == utils.Config.Chain.ClConfig.MaxEffectiveBalance && v.Balance > utils.Config.Chain.ClConfig.MaxEffectiveBalance
+	if !isFullWithdrawal && !isPartialWithdrawal {
+		return nil, nil
+	}
+
+	distance, err := GetWithdrawableCountFromCursor(epoch, v.Index, cursor)
+	if err != nil {
+		return nil, fmt.Errorf("error getting withdrawable validator count from cursor: %w", err)
+	}
+
+	estimate := &types.WithdrawalEstimate{
+		ValidatorIndex: v.Index,
+		SweepCursor:    cursor,
+		SweepPosition:  distance,
+		Time:           utils.GetTimeToNextWithdrawal(distance),
+		IsFull:         isFullWithdrawal,
+	}
+	if isFullWithdrawal {
+		estimate.Amount = v.Balance
+	} else {
+		estimate.Amount = v.Balance - utils.Config.Chain.ClConfig.MaxEffectiveBalance
+	}
+	// the balance does not reflect a withdrawal of the current epoch yet
+	if lastWithdrawalEpoch == epoch {
+		estimate.Amount = 0
+	}
+
+	// an error only means the validator still has bls credentials with a pending change
+	estimate.Address, _ = utils.WithdrawalCredentialsToAddress(v.WithdrawalCredentials)
+
+	return estimate, nil
+}
+
+// checkStaleRelayRegistrations checks the relay registrations of up to limit validators that have not been checked within
+// the check interval of the relays exporter
+func checkStaleRelayRegistrations(pubkeys [][]byte, limit int) error {
+	checked, err := db.GetRelayValidatorRegistrationsCheckedSince(pubkeys, time.Now().Add(-exporter.RelayRegistrationsCheckInterval))
+	if err != nil {
+		return err
+	}
+	checkedMap := make(map[string]bool, len(checked))
+	for _, pubkey := range checked {
+		checkedMap[string(pubkey)] = true
+	}
+
+	stale := make([][]byte, 0, limit)
+	for _, pubkey := range pubkeys {
+		if len(stale) >= limit {
+			break
+		}
+		if !checkedMap[string(pubkey)] {
+			stale = append(stale, pubkey)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	return exporter.CheckValidatorRelayRegistrations(stale)
+}
+
+// getExitReadiness collects everything a validator operator should check before submitting a voluntary exit. The exit
+// epoch of a validator that has not yet initiated its exit is estimated the same way the beacon chain assigns it,
+// behind the last validator of the exit queue and limited by the churn limit.
+func getExitReadiness(v *types.Validator, epoch uint64) (*types.ExitReadiness, error) {
+	clConfig := utils.Config.Chain.ClConfig
+	readiness := &types.ExitReadiness{
+		ValidatorIndex:     v.Index,
+		CredentialType:     "bls",
+		ExitInitiated:      v.ExitEpoch < 100_000_000,
+		EligibleEpoch:      v.ActivationEpoch + clConfig.ShardCommitteePeriod,
+		Balance:            v.Balance,
+		Blockers:           []string{},
+		Warnings:           []string{},
+		ScheduledProposals: []uint64{},
+	}
+	if len(v.WithdrawalCredentials) > 0 && v.WithdrawalCredentials[0] == 0x01 {
+		readiness.CredentialType = "execution"
+	}
+
+	switch {
+	case v.Slashed:
+		readiness.Blockers = append(readiness.Blockers, "the validator has been slashed and is already exiting")
+	case readiness.ExitInitiated:
+		readiness.Blockers = append(readiness.Blockers, "the validator has already initiated its exit")
+	case v.ActivationEpoch > epoch:
+		readiness.Blockers = append(readiness.Blockers, "the validator is not active yet")
+	case readiness.EligibleEpoch > epoch:
+		readiness.Blockers = append(readiness.Blockers, fmt.Sprintf("the validator has to be active for %d epochs before it can exit, it is eligible in epoch %d", clConfig.ShardCommitteePeriod, readiness.EligibleEpoch))
+	}
+	readiness.CanExit = len(readiness.Blockers) == 0
+
+	queueLength, lastExitEpoch, err := db.GetExitQueue(epoch)
+	if err != nil {
+		return nil, err
+	}
+	readiness.ExitQueueLength = queueLength
+
+	if readiness.ExitInitiated {
+		readiness.ExitEpoch = v.ExitEpoch
+		readiness.WithdrawableEpoch = v.WithdrawableEpoch
+	} else {
+		exitEpoch := epoch + 1 + clConfig.MaxSeedLookahead
+		if lastExitEpoch > exitEpoch {
+			exitEpoch = lastExitEpoch
+		}
+		var churnLimit uint64
+		if stats := services.GetLatestStats(); stats != nil && stats.ValidatorChurnLimit != nil {
+			churnLimit = *stats.ValidatorChurnLimit
+		}
+		exitEpochCount, err := db.GetExitEpochCount(exitEpoch)
+		if err != nil {
+			return nil, err
+		}
+		if churnLimit > 0 && exitEpochCount >= churnLimit {
+			exitEpoch++
+		}
+		if readiness.EligibleEpoch > exitEpoch {
+			exitEpoch = readiness.EligibleEpoch
+		}
+		readiness.ExitEpoch = exitEpoch
+		readiness.WithdrawableEpoch = exitEpoch + clConfig.MinValidatorWithdrawabilityDelay
+	}
+	readiness.ExitTime = utils.EpochToTime(readiness.ExitEpoch)
+	readiness.WithdrawableTime = utils.EpochToTime(readiness.WithdrawableEpoch)
+
+	if readiness.CredentialType == "execution" {
+		var cursor, validatorCount uint64
+		if stats := services.GetLatestStats(); stats != nil {
+			if stats.LatestValidatorWithdrawalIndex != nil {
+				cursor = *stats.LatestValidatorWithdrawalIndex
+			}
+			if stats.TotalValidatorCount != nil {
+				validatorCount = *stats.TotalValidatorCount
+			}
+		}
+		readiness.FullWithdrawalTime = projectFullWithdrawal(epoch, v.Index, readiness.WithdrawableEpoch, cursor, validatorCount)
+	} else {
+		readiness.Warnings = append(readiness.Warnings, "the validator has bls withdrawal credentials, its balance can not be withdrawn until the credentials are changed to an execution address")
+	}
+
+	readiness.ScheduledProposals, err = db.GetValidatorScheduledProposals(v.Index, utils.TimeToSlot(uint64(time.Now().Unix())))
+	if err != nil {
+		return nil, err
+	}
+	if len(readiness.ScheduledProposals) > 0 {
+		readiness.Warnings = append(readiness.Warnings, fmt.Sprintf("the validator is scheduled to propose %d block(s), exiting before the proposals forfeits their rewards", len(readiness.ScheduledProposals)))
+	}
+
+	readiness.SyncCommitteePeriods, err = db.GetValidatorSyncCommitteePeriodsFrom(v.Index, utils.SyncPeriodOfEpoch(epoch))
+	if err != nil {
+		return nil, err
+	}
+	if len(readiness.SyncCommitteePeriods) > 0 {
+		lastPeriod := readiness.SyncCommitteePeriods[len(readiness.SyncCommitteePeriods)-1]
+		readiness.SyncCommitteeEnd = utils.FirstEpochOfSyncPeriod(lastPeriod + 1)
+		if readiness.SyncCommitteeEnd > readiness.ExitEpoch {
+			readiness.Warnings = append(readiness.Warnings, fmt.Sprintf("the validator is a member of a sync committee until epoch %d and will miss its sync committee rewards after exiting", readiness.SyncCommitteeEnd))
+		}
+	}
+
+	if readiness.ExitEpoch > epoch {
+		readiness.Warnings = append(readiness.Warnings, fmt.Sprintf("the validator has to keep performing its duties until it exits in epoch %d", readiness.ExitEpoch))
+
+		performance, err := db.GetValidatorClPerformance7d(v.Index)
+		if err != nil {
+			return nil, err
+		}
+		readiness.RewardsPerDay = performance / 7
+		days := float64(readiness.ExitEpoch-epoch) * float64(clConfig.SlotsPerEpoch*clConfig.SecondsPerSlot) / 86400
+		readiness.RewardsUntilExit = int64(float64(readiness.RewardsPerDay) * days)
+	}
+
+	return readiness, nil
+}
+
 func getExecutionChartData(indices []uint64, currency string, lowerBoundDay uint64) ([]*types.ChartDataPoint, error) {
 	var limit uint64 = 300
 	blockList, consMap, err := findExecBlockNumbersByProposerIndex(indices, 0, limit, false, true, lowerBoundDay)
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+const (
+	maxComparisonSubjects = 10
+	maxComparisonDays     = 90
+
+	compareRateLimitPerSecond = 1
+	compareRateLimitBurst     = 5
+	// comparisons only change when a new statistics day has been exported
+	compareCacheDuration = time.Hour
+)
+
+// compareLimiter rate limits the comparison api per ip
+var compareLimiter = newClientRateLimiter()
+
+// parseComparisonRequest returns the validators, entities and days to compare from the validators (comma separated
+// indices or pubkeys), entity (repeatable) and days query parameters
+func parseComparisonRequest(r *http.Request) (validators []uint64, entities []string, days uint64, err error) {
+	q := r.URL.Query()
+
+	if param := strings.TrimSpace(q.Get("validators")); param != "" {
+		validators, err = parseApiValidatorParamToIndices(strings.ReplaceAll(param, " ", ""), maxComparisonSubjects)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		validators = utils.SortedUniqueUint64(validators)
+	}
+	for _, entity := range q["entity"] {
+		if entity = strings.TrimSpace(entity); entity != "" {
+			entities = append(entities, entity)
+		}
+	}
+	entities = utils.UniqueStrings(entities)
+
+	if len(validators)+len(entities) > maxComparisonSubjects {
+		return nil, nil, 0, fmt.Errorf("only a maximum of %d validators and entities can be compared", maxComparisonSubjects)
+	}
+
+	days = parseUintWithDefault(q.Get("days"), 30)
+	if days == 0 || days > maxComparisonDays {
+		return nil, nil, 0, fmt.Errorf("days must be between 1 and %d", maxComparisonDays)
+	}
+
+	return validators, entities, days, nil
+}
+
+// getPerformanceComparison returns the comparison of the validators and entities over the last exported days
+func getPerformanceComparison(validators []uint64, entities []string, days uint64) ([]*types.ApiComparisonResponse, error) {
+	lastDay, err := db.GetLastExportedStatisticDay()
+	if err == db.ErrNoStats {
+		return []*types.ApiComparisonResponse{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fromDay := uint64(0)
+	if lastDay+1 > days {
+		fromDay = lastDay + 1 - days
+	}
+
+	sortedEntities := append([]string{}, entities...)
+	sort.Strings(sortedEntities)
+	cacheKey := fmt.Sprintf("%d:compare:%d:%d:%v:%q", utils.Config.Chain.ClConfig.DepositChainID, fromDay, lastDay, validators, sortedEntities)
+	cached := []*types.ApiComparisonResponse{}
+	if _, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, compareCacheDuration, &cached); err == nil {
+		return cached, nil
+	}
+
+	comparison, err := db.GetPerformanceComparison(validators, entities, fromDay, lastDay)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cache.TieredCache.Set(cacheKey, comparison, compareCacheDuration)
+	if err != nil {
+		utils.LogError(err, fmt.Errorf("error setting tieredCache for comparison with key %v", cacheKey), 0)
+	}
+	return comparison, nil
+}
+
+// Compare will return the page comparing the income, effectiveness, luck and uptime of validators and entities
+func Compare(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "compare.html")
+	var compareTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	available, err := db.GetComparisonEntities()
+	if err != nil {
+		logger.Errorf("error retrieving comparison entities for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	pageData := types.ComparePageData{
+		Validators: r.URL.Query().Get("validators"),
+		Entities:   r.URL.Query()["entity"],
+		Days:       30,
+		Available:  available,
+	}
+
+	validators, entities, days, err := parseComparisonRequest(r)
+	if err != nil {
+		pageData.Error = err.Error()
+	} else {
+		pageData.Days = days
+		if len(validators)+len(entities) > 0 {
+			pageData.Comparison, err = getPerformanceComparison(validators, entities, days)
+			if err != nil {
+				logger.Errorf("error retrieving performance comparison for %v route: %v", r.URL.String(), err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	data := InitPageData(w, r, "services", "/compare", "Compare Validators", templateFiles)
+	data.Data = pageData
+
+	if handleTemplateError(w, r, "compare.go", "Compare", "", compareTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// ApiCompare godoc
+// @Summary Compare the performance of up to 10 validators or entities
+// @Tags Validator
+// @Description Returns the income per validator, effectiveness, proposal luck and attestation uptime of the given validators and entities (staking pools) over the last exported days,
+// @Description together with their daily history. Income is the average income per validator and day in ETH so that entities of different sizes can be compared.
+// @Produce  json
+// @Param  validators query string false "Validator indices or pubkeys, comma separated"
+// @Param  entity query []string false "Entities as named on /pools, repeat the parameter for multiple entities" collectionFormat(multi)
+// @Param  days query int false "Amount of the last exported days to compare, between 1 and 90, defaults to 30"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiComparisonResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/compare [get]
+func ApiCompare(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+
+	if !compareLimiter.allowIP(w, r, compareRateLimitPerSecond, compareRateLimitBurst) {
+		return
+	}
+
+	validators, entities, days, err := parseComparisonRequest(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+	if len(validators)+len(entities) == 0 {
+		SendBadRequestResponse(w, r.URL.String(), "no validators or entities provided")
+		return
+	}
+
+	comparison, err := getPerformanceComparison(validators, entities, days)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		requestLogger(r).WithError(err).Error("can not GetPerformanceComparison")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{comparison})
+}
@@ -136,7 +136,7 @@ func updateValidatorsQueryString(w http.ResponseWriter, r *http.Request, validat
 			Index  uint64
 			Pubkey []byte
 		}{}
-		err := db.ReaderDb.Select(&validatorInfos, `SELECT validatorindex as index, pubkey FROM validators WHERE pubkey = ANY($1)`, validatorPubkeys)
+		err := db.ReaderDb.SelectContext(r.Context(), &validatorInfos, `SELECT validatorindex as index, pubkey FROM validators WHERE pubkey = ANY($1)`, validatorPubkeys)
 		if err != nil {
 			return false, err
 		}
@@ -305,7 +305,7 @@ func Dashboard(w http.ResponseWriter, r *http.Request) {
 	epoch := services.LatestEpoch()
 	dashboardData.CappellaHasHappened = epoch >= (utils.Config.Chain.ClConfig.CappellaForkEpoch)
 
-	data := InitPageData(w, r, "dashboard", "/dashboard", "Dashboard", templateFiles)
+	data := InitPageData(w, r, "dashboard", "/dashboard", utils.Tr(GetLanguage(r), "dashboard.title"), templateFiles)
 	data.Data = dashboardData
 
 	if handleTemplateError(w, r, "dashboard.go", "Dashboard", "", dashboardTemplate.ExecuteTemplate(w, "layout", data)) != nil {
@@ -377,6 +377,7 @@ func getNextWithdrawalRow(queryValidators []uint64, currency string) ([][]interf
 			if nextValidator == nil || v.Index > *stats.LatestValidatorWithdrawalIndex {
 				nextValidator = v
 				nextValidator.Balance = balance[0].Balance
+				nextValidator.EffectiveBalance = balance[0].EffectiveBalance
 				if nextValidator.Index > *stats.LatestValidatorWithdrawalIndex {
 					// the first validator after the cursor has to be the next validator
 					break
@@ -395,52 +396,53 @@ func getNextWithdrawalRow(queryValidators []uint64, currency string) ([][]interf
 	}
 	lastWithdrawnEpoch := lastWithdrawnEpochs[nextValidator.Index]
 
-	distance, err := GetWithdrawableCountFromCursor(epoch, nextValidator.Index, *stats.LatestValidatorWithdrawalIndex)
+	estimate, err := estimateNextWithdrawal(nextValidator, epoch, *stats.LatestValidatorWithdrawalIndex, lastWithdrawnEpoch)
 	if err != nil {
 		return nil, err
 	}
 
-	timeToWithdrawal := utils.GetTimeToNextWithdrawal(distance)
+	if estimate == nil {
+		// the validator is still shown, only the estimate is not available
+		var withdrawalCredentialsTemplate template.HTML = `<span class="text-muted">N/A</span>`
+		address, err := utils.WithdrawalCredentialsToAddress(nextValidator.WithdrawalCredentials)
+		if err != nil {
+			// warning only as "N/A" will be displayed
+			logger.Warn("invalid withdrawal credentials")
+		}
+		if address != nil {
+			withdrawalCredentialsTemplate = template.HTML(fmt.Sprintf(`<a href="/address/0x%x"><span class="text-muted">%s</span></a>`, address, utils.FormatAddress(address, nil, resolveEnsNames(address)[string(address)], false, false, true)))
+		}
+		return [][]interface{}{{
+			utils.FormatValidator(nextValidator.Index),
+			template.HTML(`<span class="text-muted">N/A</span>`),
+			template.HTML(`<span class="text-muted">N/A</span>`),
+			template.HTML(`<span class="text-muted">N/A</span>`),
+			withdrawalCredentialsTemplate,
+			template.HTML(`<span class="text-muted">N/A</span>`),
+		}}, nil
+	}
 
 	// it normally takes two epochs to finalize
 	latestFinalized := services.LatestFinalizedEpoch()
-	if timeToWithdrawal.Before(utils.EpochToTime(epoch + (epoch - latestFinalized))) {
+	if estimate.Time.Before(utils.EpochToTime(epoch + (epoch - latestFinalized))) {
 		return nil, nil
 	}
 
 	var withdrawalCredentialsTemplate template.HTML
-	address, err := utils.WithdrawalCredentialsToAddress(nextValidator.WithdrawalCredentials)
-	if err != nil {
-		// warning only as "N/A" will be displayed
-		logger.Warn("invalid withdrawal credentials")
-	}
-	if address != nil {
-		withdrawalCredentialsTemplate = template.HTML(fmt.Sprintf(`<a href="/address/0x%x"><span class="text-muted">%s</span></a>`, address, utils.FormatAddress(address, nil, "", false, false, true)))
+	if estimate.Address != nil {
+		withdrawalCredentialsTemplate = template.HTML(fmt.Sprintf(`<a href="/address/0x%x"><span class="text-muted">%s</span></a>`, estimate.Address, utils.FormatAddress(estimate.Address, nil, resolveEnsNames(estimate.Address)[string(estimate.Address)], false, false, true)))
 	} else {
 		withdrawalCredentialsTemplate = `<span class="text-muted">N/A</span>`
 	}
 
-	var withdrawalAmount uint64
-	if nextValidator.WithdrawableEpoch <= epoch {
-		// full withdrawal
-		withdrawalAmount = nextValidator.Balance
-	} else {
-		// partial withdrawal
-		withdrawalAmount = nextValidator.Balance - utils.Config.Chain.ClConfig.MaxEffectiveBalance
-	}
-
-	if lastWithdrawnEpoch == epoch || nextValidator.Balance < utils.Config.Chain.ClConfig.MaxEffectiveBalance {
-		withdrawalAmount = 0
-	}
-
 	nextData := make([][]interface{}, 0, 1)
 	nextData = append(nextData, []interface{}{
 		utils.FormatValidator(nextValidator.Index),
-		template.HTML(fmt.Sprintf(`<span class="text-muted">~ %s</span>`, utils.FormatEpoch(uint64(utils.TimeToEpoch(timeToWithdrawal))))),
-		template.HTML(fmt.Sprintf(`<span class="text-muted">~ %s</span>`, utils.FormatBlockSlot(utils.TimeToSlot(uint64(timeToWithdrawal.Unix()))))),
-		template.HTML(fmt.Sprintf(`<span class="">~ %s</span>`, utils.FormatTimestamp(timeToWithdrawal.Unix()))),
+		template.HTML(fmt.Sprintf(`<span class="text-muted">~ %s</span>`, utils.FormatEpoch(uint64(utils.TimeToEpoch(estimate.Time))))),
+		template.HTML(fmt.Sprintf(`<span class="text-muted">~ %s</span>`, utils.FormatBlockSlot(utils.TimeToSlot(uint64(estimate.Time.Unix()))))),
+		template.HTML(fmt.Sprintf(`<span class="">~ %s</span>`, utils.FormatTimestamp(estimate.Time.Unix()))),
 		withdrawalCredentialsTemplate,
-		template.HTML(fmt.Sprintf(`<span class="text-muted"><span data-toggle="tooltip" title="If the withdrawal were to be processed at this very moment, this amount would be withdrawn"><i class="far ml-1 fa-question-circle" style="margin-left: 0px !important;"></i></span> %s</span>`, utils.FormatClCurrency(withdrawalAmount, currency, 6, true, false, false, true))),
+		template.HTML(fmt.Sprintf(`<span class="text-muted"><span data-toggle="tooltip" title="If the withdrawal were to be processed at this very moment, this amount would be withdrawn"><i class="far ml-1 fa-question-circle" style="margin-left: 0px !important;"></i></span> %s</span>`, utils.FormatClCurrency(estimate.Amount, currency, 6, true, false, false, true))),
 	})
 
 	return nextData, nil
@@ -571,7 +573,7 @@ func DashboardDataProposals(w http.ResponseWriter, r *http.Request) {
 
 	errFieldMap := map[string]interface{}{"route": r.URL.String()}
 
-	err = db.ReaderDb.Select(&proposals, `
+	err = db.ReaderDb.SelectContext(r.Context(), &proposals, `
 		SELECT slot, status
 		FROM blocks
 		WHERE proposer = ANY($1)
@@ -675,13 +677,19 @@ func DashboardDataWithdrawals(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	addresses := make([][]byte, 0, len(withdrawals))
+	for _, w := range withdrawals {
+		addresses = append(addresses, w.Address)
+	}
+	names := resolveEnsNames(addresses...)
+
 	for _, w := range withdrawals {
 		tableData = append(tableData, []interface{}{
 			utils.FormatValidator(w.ValidatorIndex),
 			utils.FormatEpoch(utils.EpochOfSlot(w.Slot)),
 			utils.FormatBlockSlot(w.Slot),
 			utils.FormatTimestamp(utils.SlotToTime(w.Slot).Unix()),
-			utils.FormatAddress(w.Address, nil, "", false, false, true),
+			utils.FormatAddress(w.Address, nil, names[string(w.Address)], false, false, true),
 			utils.FormatClCurrency(w.Amount, reqCurrency, 6, true, false, false, true),
 		})
 	}
@@ -717,7 +725,7 @@ func DashboardDataValidators(w http.ResponseWriter, r *http.Request) {
 	validatorLimit := getUserPremium(r).MaxValidators
 
 	var validatorsByIndex []*types.ValidatorsData
-	err = db.ReaderDb.Select(&validatorsByIndex, `
+	err = db.ReaderDb.SelectContext(r.Context(), &validatorsByIndex, `
 		SELECT
 			validators.validatorindex,
 			validators.pubkey,
@@ -753,7 +761,7 @@ func DashboardDataValidators(w http.ResponseWriter, r *http.Request) {
 		Pubkey  []byte `db:"publickey"`
 		Address []byte `db:"from_address"`
 	}{}
-	err = db.ReaderDb.Select(&validatorsDeposits, `
+	err = db.ReaderDb.SelectContext(r.Context(), &validatorsDeposits, `
 		SELECT
 			publickey,
 			from_address
@@ -985,7 +993,7 @@ func DashboardDataEffectiveness(w http.ResponseWriter, r *http.Request) {
 	filter := pq.Array(filterArr)
 
 	var activeValidators []uint64
-	err = db.ReaderDb.Select(&activeValidators, `
+	err = db.ReaderDb.SelectContext(r.Context(), &activeValidators, `
 		SELECT validatorindex FROM validators where validatorindex = ANY($1) and activationepoch < $2 AND exitepoch > $2
 	`, filter, services.LatestEpoch())
 	if err != nil {
@@ -994,29 +1002,60 @@ func DashboardDataEffectiveness(w http.ResponseWriter, r *http.Request) {
 
 	if len(activeValidators) == 0 {
 		// valid 200 response with empty data
-		w.Write([]byte(`{}`))
+		w.Write([]byte(`[]`))
 		return
 	}
 
-	var avgIncDistance []float64
+	scores, err := db.GetLatestValidatorEffectivenessScores(activeValidators)
+	if err != nil {
+		utils.LogError(err, "error retrieving validator effectiveness scores", 0, errFieldMap)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
 
-	epoch := services.LatestEpoch()
-	if epoch > 0 {
-		epoch = epoch - 1
+	effectiveness := make([]float64, 0, len(scores))
+	for _, score := range scores {
+		effectiveness = append(effectiveness, score.Score)
 	}
+	if len(effectiveness) == 0 {
+		// valid 200 response with empty data
+		w.Write([]byte(`[]`))
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(effectiveness)
+	if err != nil {
+		utils.LogError(err, "error enconding json response", 0, errFieldMap)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
 
-	effectiveness, err := db.BigtableClient.GetValidatorEffectiveness(activeValidators, epoch)
+// DashboardDataPercentiles returns the percentile ranks of the selected validators averaged over the last 7 exported days
+func DashboardDataPercentiles(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	filterArr, _, redirect, err := handleValidatorsQuery(w, r, true)
+	if err != nil || redirect {
+		return
+	}
+
+	errFieldMap := map[string]interface{}{"route": r.URL.String()}
+
+	percentiles, err := db.GetValidatorsPercentiles(filterArr, 7)
 	if err != nil {
-		utils.LogError(err, "error retrieving validator effectiveness", 0, errFieldMap)
+		utils.LogError(err, "error retrieving validator percentiles", 0, errFieldMap)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	for _, e := range effectiveness {
-		avgIncDistance = append(avgIncDistance, e.AttestationEfficiency)
+	if percentiles == nil {
+		// valid 200 response with empty data
+		w.Write([]byte(`{}`))
+		return
 	}
 
-	err = json.NewEncoder(w).Encode(avgIncDistance)
+	err = json.NewEncoder(w).Encode(percentiles)
 	if err != nil {
 		utils.LogError(err, "error enconding json response", 0, errFieldMap)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -1024,6 +1063,86 @@ func DashboardDataEffectiveness(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// DashboardTaxReport exports the income of the dashboard validators in a year valued at the price of the day it was earned
+// as csv, pdf or json. Query parameters: currency (default usd), year (default current year), method (fifo, lifo or hifo,
+// default fifo) and format (csv, pdf or json, default csv).
+func DashboardTaxReport(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	filterArr, _, redirect, err := handleValidatorsQuery(w, r, true)
+	if err != nil || redirect {
+		return
+	}
+	if len(filterArr) == 0 {
+		http.Error(w, "Error: No validators provided.", http.StatusBadRequest)
+		return
+	}
+
+	errFieldMap := map[string]interface{}{"route": r.URL.String()}
+
+	currency := strings.ToLower(q.Get("currency"))
+	if currency == "" {
+		currency = "usd"
+	}
+	if !isValidCurrency(currency) {
+		http.Error(w, "Error: Invalid parameter currency.", http.StatusBadRequest)
+		return
+	}
+
+	year := time.Now().UTC().Year()
+	if q.Get("year") != "" {
+		year, err = strconv.Atoi(q.Get("year"))
+		if err != nil || year > time.Now().UTC().Year() || year < time.Unix(int64(utils.Config.Chain.GenesisTimestamp), 0).UTC().Year() {
+			http.Error(w, "Error: Invalid parameter year.", http.StatusBadRequest)
+			return
+		}
+	}
+
+	method := strings.ToLower(q.Get("method"))
+	if method == "" {
+		method = "fifo"
+	}
+	if !utils.SliceContains(services.TaxCostBasisMethods, method) {
+		http.Error(w, fmt.Sprintf("Error: Invalid parameter method, supported methods are %v.", strings.Join(services.TaxCostBasisMethods, ", ")), http.StatusBadRequest)
+		return
+	}
+
+	report, err := services.GetTaxReport(filterArr, currency, year, method)
+	if err != nil {
+		utils.LogError(err, "error retrieving tax report", 0, errFieldMap)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("staking_income_%d_%s_%s", year, currency, method)
+	switch q.Get("format") {
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(report)
+	case "pdf":
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.pdf", filename))
+		_, err = w.Write(services.GenerateTaxReportPdf(report))
+	case "", "csv":
+		var data []byte
+		data, err = services.GenerateTaxReportCsv(report)
+		if err != nil {
+			utils.LogError(err, "error generating tax report csv", 0, errFieldMap)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.csv", filename))
+		_, err = w.Write(data)
+	default:
+		http.Error(w, "Error: Invalid parameter format.", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		utils.LogError(err, "error writing tax report response", 0, errFieldMap)
+	}
+}
+
 func DashboardDataProposalsHistory(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -1079,7 +1198,7 @@ func DashboardDataProposalsHistory(w http.ResponseWriter, r *http.Request) {
 		args = []interface{}{filter, dayStart}
 	}
 
-	err = db.ReaderDb.Select(&proposals, fmt.Sprintf(`
+	err = db.ReaderDb.SelectContext(r.Context(), &proposals, fmt.Sprintf(`
 		SELECT validatorindex, day, proposed_blocks, missed_blocks, orphaned_blocks
 		FROM validator_stats
 		WHERE validatorindex = ANY($1) 
@@ -1095,7 +1214,7 @@ func DashboardDataProposalsHistory(w http.ResponseWriter, r *http.Request) {
 	if uint64(dayEnd) > lastDay {
 		_, lastExportedEpoch := utils.GetFirstAndLastEpochForDay(lastDay)
 
-		err = db.ReaderDb.Select(&todaysProposals, `
+		err = db.ReaderDb.SelectContext(r.Context(), &todaysProposals, `
 		SELECT
 			proposer as validatorindex,
 			SUM(CASE WHEN status = '1' THEN 1 ELSE 0 END) as proposed_blocks,
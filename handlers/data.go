@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/dataexport"
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// dataPageDays is the amount of days the data page lists the exported dumps of
+const dataPageDays = 90
+
+// Data will return the page listing the daily dumps of the exported datasets
+func Data(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "data.html")
+	var dataTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	data := InitPageData(w, r, "tools", "/data", "Data Exports", templateFiles)
+
+	exports, err := db.GetDataExports(dataPageDays)
+	if err != nil {
+		utils.LogError(err, "error retrieving data exports", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	pageData := &types.DataPageData{
+		Datasets: make([]types.DataPageDataset, 0, len(dataexport.Datasets)),
+		Days:     []*types.DataPageDay{},
+	}
+	for _, d := range dataexport.Datasets {
+		pageData.Datasets = append(pageData.Datasets, types.DataPageDataset{
+			Name:        d.Name,
+			Description: d.Description,
+			Columns:     d.Columns,
+			Format:      d.Format(),
+		})
+	}
+	// exports are ordered by day, newest first
+	for _, e := range exports {
+		if len(pageData.Days) == 0 || pageData.Days[len(pageData.Days)-1].Day != e.Day {
+			pageData.Days = append(pageData.Days, &types.DataPageDay{
+				Day:      e.Day,
+				DayStart: utils.DayToTime(int64(e.Day)),
+				Exports:  map[string]*types.DataExport{},
+			})
+		}
+		pageData.Days[len(pageData.Days)-1].Exports[e.Dataset] = e
+	}
+
+	data.Data = pageData
+	if handleTemplateError(w, r, "data.go", "Data", "", dataTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// DataDownload redirects to a signed download link of the dump of a dataset of a day
+func DataDownload(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	dataset := dataexport.GetDataset(vars["dataset"])
+	if dataset == nil {
+		NotFound(w, r)
+		return
+	}
+	day, err := strconv.ParseUint(vars["day"], 10, 64)
+	if err != nil {
+		NotFound(w, r)
+		return
+	}
+
+	export, err := db.GetDataExport(dataset.Name, day)
+	if err != nil {
+		utils.LogError(err, "error retrieving data export", 0, map[string]interface{}{"dataset": dataset.Name, "day": day})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if export == nil {
+		NotFound(w, r)
+		return
+	}
+
+	url, err := dataexport.SignedUrl(r.Context(), export.ObjectKey)
+	if err != nil {
+		utils.LogError(err, "error signing data export download link", 0, map[string]interface{}{"dataset": dataset.Name, "day": day})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, url, http.StatusFound)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// dataTableMaxLength is the maximum number of rows of a page of a server-side processed table
+const dataTableMaxLength = 100
+
+// DataTableQuery are the paging, ordering and search parameters of a server-side processed DataTables request
+type DataTableQuery struct {
+	Draw   uint64
+	Start  uint64
+	Length uint64
+	Search string
+	// OrderBy is the sql column of the ordered table column and OrderDir is either asc or desc
+	OrderBy  string
+	OrderDir string
+
+	values url.Values
+}
+
+// DataTableColumns describes how the columns of a table map to the query
+type DataTableColumns struct {
+	// OrderBy maps the index of a sortable table column to its sql column
+	OrderBy         map[string]string
+	DefaultOrderBy  string
+	DefaultOrderDir string
+	// MaxStart caps the offset to keep the queries fast, 0 means no cap
+	MaxStart uint64
+}
+
+// ParseDataTableQuery parses the parameters sent by a DataTables table with server-side processing enabled. The order
+// column is only accepted if it is part of the columns, so the returned OrderBy and OrderDir can be put into sql.
+func ParseDataTableQuery(r *http.Request, columns DataTableColumns) (*DataTableQuery, error) {
+	q := r.URL.Query()
+	query := &DataTableQuery{
+		Search: q.Get("search[value]"),
+		values: q,
+	}
+
+	var err error
+	query.Draw, err = strconv.ParseUint(q.Get("draw"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("missing or invalid parameter draw")
+	}
+	query.Start, err = strconv.ParseUint(q.Get("start"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("missing or invalid parameter start")
+	}
+	if columns.MaxStart > 0 && query.Start > columns.MaxStart {
+		query.Start = columns.MaxStart
+	}
+	// DataTables sends -1 to request all rows, which we never return in one page
+	length, err := strconv.ParseInt(q.Get("length"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("missing or invalid parameter length")
+	}
+	if length < 0 || length > dataTableMaxLength {
+		length = dataTableMaxLength
+	}
+	query.Length = uint64(length)
+
+	var exists bool
+	query.OrderBy, exists = columns.OrderBy[q.Get("order[0][column]")]
+	if !exists {
+		query.OrderBy = columns.DefaultOrderBy
+	}
+	query.OrderDir = q.Get("order[0][dir]")
+	if query.OrderDir != "asc" && query.OrderDir != "desc" {
+		query.OrderDir = columns.DefaultOrderDir
+		if query.OrderDir == "" {
+			query.OrderDir = "desc"
+		}
+	}
+
+	return query, nil
+}
+
+// ColumnSearch returns the search value of the table column with the index
+func (q *DataTableQuery) ColumnSearch(column int) string {
+	return q.values.Get(fmt.Sprintf("columns[%d][search][value]", column))
+}
+
+// handleDataTableQuery parses the DataTables parameters and responds with a bad request if they are invalid
+func handleDataTableQuery(w http.ResponseWriter, r *http.Request, columns DataTableColumns) (*DataTableQuery, bool) {
+	query, err := ParseDataTableQuery(r, columns)
+	if err != nil {
+		logger.Warnf("error parsing datatables parameters of %v route: %v", r.URL.String(), err)
+		http.Error(w, "Error: "+err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	return query, true
+}
+
+func writeDataTableResponse(w http.ResponseWriter, r *http.Request, data *types.DataTableResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(data)
+	if err != nil {
+		logger.Errorf("error encoding json response for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
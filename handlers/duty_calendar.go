@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// dutyCalendarDays is the number of days shown in the duty calendar
+const dutyCalendarDays = 365
+
+// dutyCalendarFromDayStats converts the last days of the daily stats of a set of validators into the days of the duty calendar
+func dutyCalendarFromDayStats(stats []*types.UserValidatorDashboardDayStats, days uint64) []*types.ApiDutyCalendarDay {
+	if uint64(len(stats)) > days {
+		stats = stats[uint64(len(stats))-days:]
+	}
+	epochsPerDay := utils.EpochsPerDay()
+
+	calendar := make([]*types.ApiDutyCalendarDay, 0, len(stats))
+	for _, s := range stats {
+		day := &types.ApiDutyCalendarDay{
+			Day:                s.Day,
+			DayStart:           utils.DayToTime(int64(s.Day)),
+			ActiveValidators:   s.ActiveDays,
+			MissedAttestations: s.MissedAttestations,
+			ProposedBlocks:     s.ProposedBlocks,
+			MissedBlocks:       s.MissedBlocks,
+		}
+		if expected := s.ActiveDays * epochsPerDay; expected > 0 {
+			day.AttestationEfficiency = 1 - float64(s.MissedAttestations)/float64(expected)
+		}
+		if proposals := s.ProposedBlocks + s.MissedBlocks; proposals > 0 {
+			efficiency := float64(s.ProposedBlocks) / float64(proposals)
+			day.ProposalEfficiency = &efficiency
+		}
+		calendar = append(calendar, day)
+	}
+	return calendar
+}
+
+// getValidatorsDutyCalendar returns the duty calendar of the last year of the given validators
+func getValidatorsDutyCalendar(validators []uint64) ([]*types.ApiDutyCalendarDay, error) {
+	lowerBoundDay := uint64(0)
+	lastDay, err := services.LatestExportedStatisticDay()
+	if err == nil && lastDay >= dutyCalendarDays {
+		lowerBoundDay = lastDay - dutyCalendarDays + 1
+	}
+	stats, err := db.GetValidatorsDayStats(validators, lowerBoundDay)
+	if err != nil {
+		return nil, err
+	}
+	return dutyCalendarFromDayStats(stats, dutyCalendarDays), nil
+}
+
+// ApiValidatorDutyCalendar godoc
+// @Summary Get the daily attestation and proposal performance of up to 100 validators over the last year
+// @Tags Validator
+// @Description The duties of all given validators are summed up per day, e.g. to render a calendar heatmap of a dashboard.
+// @Produce  json
+// @Param  indexOrPubkey path string true "Up to 100 validator indicesOrPubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiDutyCalendarDay}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{indexOrPubkey}/dutycalendar [get]
+func ApiValidatorDutyCalendar(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	queryIndices, err := parseApiValidatorParamToIndices(mux.Vars(r)["indexOrPubkey"], getUserPremium(r).MaxValidators)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	calendar, err := getValidatorsDutyCalendar(queryIndices)
+	if err != nil {
+		utils.LogError(err, "error retrieving duty calendar", 0, map[string]interface{}{"route": r.URL.String()})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{calendar})
+}
+
+// DashboardDataDutyCalendar returns the duty calendar of the validators of the dashboard or validator page
+func DashboardDataDutyCalendar(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	queryValidatorIndices, _, redirect, err := handleValidatorsQuery(w, r, true)
+	if err != nil || redirect {
+		return
+	}
+	if len(queryValidatorIndices) < 1 {
+		http.Error(w, "Invalid query", http.StatusBadRequest)
+		return
+	}
+
+	calendar, err := getValidatorsDutyCalendar(queryValidatorIndices)
+	if err != nil {
+		utils.LogError(err, "error retrieving duty calendar", 0, map[string]interface{}{"route": r.URL.String()})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(calendar)
+	if err != nil {
+		utils.LogError(err, "error enconding json response", 0, map[string]interface{}{"route": r.URL.String()})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// ValidatorDashboardDutyCalendarData godoc
+// @Summary Get the daily attestation and proposal performance of all validators of a validator dashboard over the last year
+// @Tags User
+// @Description Shared dashboards can be queried via /api/v1/dashboard/shared/{publicId}/dutycalendar without authentication.
+// @Produce json
+// @Param dashboardId path integer true "The id of the dashboard"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiDutyCalendarDay}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards/{dashboardId}/dutycalendar [get]
+func ValidatorDashboardDutyCalendarData(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	dashboard := getRequestedValidatorDashboard(w, r)
+	if dashboard == nil {
+		return
+	}
+	errFields := map[string]interface{}{"route": r.URL.String(), "dashboardId": dashboard.Id}
+
+	err := db.UpdateValidatorDashboardStats(dashboard.Id)
+	if err != nil {
+		utils.LogError(err, "error updating validator dashboard stats", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	stats, err := db.GetValidatorDashboardStats(dashboard.Id)
+	if err != nil {
+		utils.LogError(err, "error retrieving validator dashboard stats", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{dutyCalendarFromDayStats(stats, dutyCalendarDays)})
+}
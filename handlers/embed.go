@@ -0,0 +1,380 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/ratelimit"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/time/rate"
+)
+
+const (
+	embedRateLimitPerSecond       = 1
+	embedRateLimitBurst           = 10
+	embedSignedRateLimitPerSecond = 10
+	embedSignedRateLimitBurst     = 50
+	// embedMaxSignatureDays is the longest validity in days of a signed embed url handed out by ApiEmbedSign
+	embedMaxSignatureDays = 365
+	// embedMaxActiveKeys is the maximum number of signed embed urls of a user that are neither expired nor revoked
+	embedMaxActiveKeys = 100
+)
+
+var embedAccentRegex = regexp.MustCompile(`^[0-9a-fA-F]{6}$`)
+
+// embedLimiter rate limits the embed widgets per ip or, for signed requests, per embed key
+var embedLimiter = newClientRateLimiter()
+
+// embedSignature returns the signature of an embed url of the given key that is valid until expires
+func embedSignature(path, key string, expires int64) string {
+	h := hmac.New(sha256.New, []byte(utils.Config.Frontend.Embed.Secret))
+	h.Write([]byte(fmt.Sprintf("%v|%v|%v", path, key, expires)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// checkEmbedRequest verifies the signature of signed embed requests and applies the rate limit, it writes the error
+// response and returns false if the request must not be served
+func checkEmbedRequest(w http.ResponseWriter, r *http.Request) bool {
+	cfg := utils.Config.Frontend.Embed
+	q := r.URL.Query()
+
+	limiterKey := "ip:" + ratelimit.GetIP(r)
+	limit, burst := rate.Limit(embedRateLimitPerSecond), embedRateLimitBurst
+	if cfg.RateLimitPerSecond > 0 {
+		limit = rate.Limit(cfg.RateLimitPerSecond)
+	}
+	if cfg.RateLimitBurst > 0 {
+		burst = cfg.RateLimitBurst
+	}
+
+	if sig := q.Get("sig"); sig != "" {
+		key := q.Get("key")
+		expires, err := strconv.ParseInt(q.Get("expires"), 10, 64)
+		if cfg.Secret == "" || key == "" || err != nil || !hmac.Equal([]byte(sig), []byte(embedSignature(r.URL.Path, key, expires))) {
+			http.Error(w, "Invalid signature", http.StatusForbidden)
+			return false
+		}
+		if time.Now().Unix() > expires {
+			http.Error(w, "Signature expired", http.StatusForbidden)
+			return false
+		}
+		active, err := cache.GetOrLoad(cache.KeyTypeEmbed, embedCacheKey("key:"+key), func() (bool, error) {
+			return db.IsEmbedKeyActive(key)
+		})
+		if err != nil {
+			utils.LogError(err, "error checking embed key", 0)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return false
+		}
+		if !active {
+			http.Error(w, "Signature revoked", http.StatusForbidden)
+			return false
+		}
+
+		limiterKey = "key:" + key
+		limit, burst = rate.Limit(embedSignedRateLimitPerSecond), embedSignedRateLimitBurst
+		if cfg.SignedRateLimitPerSecond > 0 {
+			limit = rate.Limit(cfg.SignedRateLimitPerSecond)
+		}
+		if cfg.SignedRateLimitBurst > 0 {
+			burst = cfg.SignedRateLimitBurst
+		}
+	}
+
+	if !embedLimiter.allow(limiterKey, limit, burst) {
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		return false
+	}
+	return true
+}
+
+// renderEmbed writes the widget as json if format=json is requested and as html page otherwise, the theme is taken
+// from the theme (light or dark) and accent (hex color without #) query parameters
+func renderEmbed(w http.ResponseWriter, r *http.Request, data *types.EmbedPageData, jsonData interface{}) {
+	q := r.URL.Query()
+
+	// the widgets are only updated once per slot, let browsers and proxies cache them for the same duration
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", utils.Config.Chain.ClConfig.SecondsPerSlot))
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if q.Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{jsonData})
+		return
+	}
+
+	data.Dark = q.Get("theme") == "dark"
+	data.Accent = "3498db"
+	if accent := strings.TrimPrefix(q.Get("accent"), "#"); embedAccentRegex.MatchString(accent) {
+		data.Accent = strings.ToLower(accent)
+	}
+
+	embedTemplate := templates.GetTemplate("embed.html")
+	w.Header().Set("Content-Type", "text/html")
+	if handleTemplateError(w, r, "embed.go", "renderEmbed", data.Widget, embedTemplate.ExecuteTemplate(w, "embed", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+func embedCacheKey(widget string) string {
+	return fmt.Sprintf("%d:embed:%s", utils.Config.Chain.ClConfig.DepositChainID, widget)
+}
+
+// EmbedValidator serves the status card of a single validator as embeddable widget
+func EmbedValidator(w http.ResponseWriter, r *http.Request) {
+	if !checkEmbedRequest(w, r) {
+		return
+	}
+
+	index, err := strconv.ParseUint(mux.Vars(r)["index"], 10, 64)
+	if err != nil || index > db.MaxSqlInteger {
+		http.Error(w, "Invalid validator index", http.StatusBadRequest)
+		return
+	}
+
+	validator, err := cache.GetOrLoad(cache.KeyTypeEmbed, embedCacheKey(fmt.Sprintf("validator:%d", index)), func() (*types.EmbedValidatorData, error) {
+		validator := &types.EmbedValidatorData{}
+		err := db.ReaderDb.Get(validator, `
+			SELECT v.validatorindex, '0x' || encode(v.pubkey, 'hex') AS pubkey, COALESCE(n.name, '') AS name, v.status
+			FROM validators v
+			LEFT JOIN validator_names n ON n.publickey = v.pubkey
+			WHERE v.validatorindex = $1`, index)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving validator %v: %w", index, err)
+		}
+
+		validator.Epoch = services.LatestEpoch()
+		balances, err := db.BigtableClient.GetValidatorBalanceHistory([]uint64{index}, validator.Epoch, validator.Epoch)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving balance of validator %v: %w", index, err)
+		}
+		if history := balances[index]; len(history) > 0 {
+			validator.Balance = history[0].Balance
+			validator.EffectiveBalance = history[0].EffectiveBalance
+		}
+		return validator, nil
+	})
+	if err != nil {
+		utils.LogError(err, "error retrieving embed validator widget", 0, map[string]interface{}{"index": index})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if validator == nil {
+		http.Error(w, "Validator not found", http.StatusNotFound)
+		return
+	}
+
+	renderEmbed(w, r, &types.EmbedPageData{Widget: "validator", Validator: validator}, validator)
+}
+
+// EmbedNetworkParticipation serves the participation rate of the latest epoch as embeddable gauge
+func EmbedNetworkParticipation(w http.ResponseWriter, r *http.Request) {
+	if !checkEmbedRequest(w, r) {
+		return
+	}
+
+	network, err := cache.GetOrLoad(cache.KeyTypeEmbed, embedCacheKey("participation"), func() (*types.EmbedParticipationData, error) {
+		network := &types.EmbedParticipationData{}
+		// the participation of the current epoch is still incomplete, use the previous epoch
+		err := db.ReaderDb.Get(network, `
+			SELECT epoch, finalized, globalparticipationrate
+			FROM epochs
+			WHERE epoch < $1
+			ORDER BY epoch DESC
+			LIMIT 1`, services.LatestEpoch())
+		if err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("error retrieving network participation: %w", err)
+		}
+		return network, nil
+	})
+	if err != nil {
+		utils.LogError(err, "error retrieving embed participation widget", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	renderEmbed(w, r, &types.EmbedPageData{Widget: "participation", Network: network}, network)
+}
+
+// EmbedQueue serves the length of the activation and exit queue as embeddable widget
+func EmbedQueue(w http.ResponseWriter, r *http.Request) {
+	if !checkEmbedRequest(w, r) {
+		return
+	}
+
+	queue, err := cache.GetOrLoad(cache.KeyTypeEmbed, embedCacheKey("queue"), func() (*types.EmbedQueueData, error) {
+		queue := &types.EmbedQueueData{}
+		err := db.ReaderDb.Get(queue, `
+			SELECT
+				COALESCE((SELECT validatorscount FROM epochs ORDER BY epoch DESC LIMIT 1), 0) AS validatorscount,
+				COALESCE(q.entering_validators_count, 0) AS entering_validators_count,
+				COALESCE(q.exiting_validators_count, 0) AS exiting_validators_count
+			FROM (SELECT 1) AS one
+			LEFT JOIN (SELECT entering_validators_count, exiting_validators_count FROM queue ORDER BY ts DESC LIMIT 1) q ON true`)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("error retrieving validator queue: %w", err)
+		}
+		return queue, nil
+	})
+	if err != nil {
+		utils.LogError(err, "error retrieving embed queue widget", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	renderEmbed(w, r, &types.EmbedPageData{Widget: "queue", Queue: queue}, queue)
+}
+
+// ApiEmbedSign godoc
+// @Summary Sign an embed widget url
+// @Tags User
+// @Description Returns a signed url of an embeddable widget (/embed/...). Every signed url gets its own key, it is rate limited per key instead of per ip, stays valid for the requested number of days (default 30, at most 365) and can be revoked via /api/v1/user/embed/keys/{key}. A user can have at most 100 active signed urls.
+// @Produce json
+// @Param path query string true "Path of the widget, e.g. /embed/validator/1"
+// @Param days query integer false "Validity of the signature in days"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/embed/sign [get]
+func ApiEmbedSign(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	claims := getAuthClaims(r)
+	if claims == nil {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+	if utils.Config.Frontend.Embed.Secret == "" {
+		SendBadRequestResponse(w, r.URL.String(), "signed embed urls are not available")
+		return
+	}
+
+	q := r.URL.Query()
+	path := q.Get("path")
+	if !strings.HasPrefix(path, "/embed/") || strings.ContainsAny(path, "?#") {
+		SendBadRequestResponse(w, r.URL.String(), "invalid path provided, must be the path of an embed widget")
+		return
+	}
+	// the days are checked before they are converted to a duration so large values can not overflow
+	days := parseUintWithDefault(q.Get("days"), 30)
+	if days == 0 || days > embedMaxSignatureDays {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("invalid days provided, must be between 1 and %d", embedMaxSignatureDays))
+		return
+	}
+
+	activeKeys, err := db.CountActiveUserEmbedKeys(claims.UserID)
+	if err != nil {
+		utils.LogError(err, "error counting embed keys", 0, map[string]interface{}{"userId": claims.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not sign embed url")
+		return
+	}
+	if activeKeys >= embedMaxActiveKeys {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("at most %d signed embed urls can be active, revoke an unused one first", embedMaxActiveKeys))
+		return
+	}
+
+	// every signed url gets its own key so it is rate limited and can be revoked on its own
+	key := utils.RandomString(32)
+	expiresAt := time.Now().Add(time.Duration(days) * 24 * time.Hour)
+	err = db.AddUserEmbedKey(claims.UserID, key, path, expiresAt)
+	if err != nil {
+		utils.LogError(err, "error storing embed key", 0, map[string]interface{}{"userId": claims.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not sign embed url")
+		return
+	}
+
+	expires := expiresAt.Unix()
+	params := url.Values{}
+	params.Set("key", key)
+	params.Set("expires", strconv.FormatInt(expires, 10))
+	params.Set("sig", embedSignature(path, key, expires))
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{map[string]interface{}{
+		"url":     fmt.Sprintf("https://%s%s?%s", utils.Config.Frontend.SiteDomain, path, params.Encode()),
+		"key":     key,
+		"expires": expires,
+	}})
+}
+
+// ApiEmbedKeys godoc
+// @Summary Get the signed embed urls of the authenticated user
+// @Tags User
+// @Description Returns the keys of the signed embed urls of the user that have not expired yet, newest first.
+// @Produce json
+// @Success 200 {object} types.ApiResponse{data=[]types.UserEmbedKey}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/embed/keys [get]
+func ApiEmbedKeys(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	claims := getAuthClaims(r)
+	if claims == nil {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	keys, err := db.GetUserEmbedKeys(claims.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving embed keys", 0, map[string]interface{}{"userId": claims.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{keys})
+}
+
+// ApiEmbedKeyRevoke godoc
+// @Summary Revoke a signed embed url of the authenticated user
+// @Tags User
+// @Description Signed embed urls of a revoked key are rejected, it can take a few seconds until the revocation is effective.
+// @Produce json
+// @Param key path string true "Key of the signed embed url"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/embed/keys/{key} [delete]
+func ApiEmbedKeyRevoke(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	claims := getAuthClaims(r)
+	if claims == nil {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	found, err := db.RevokeUserEmbedKey(claims.UserID, mux.Vars(r)["key"])
+	if err != nil {
+		utils.LogError(err, "error revoking embed key", 0, map[string]interface{}{"userId": claims.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not revoke embed key")
+		return
+	}
+	if !found {
+		sendErrorWithCodeResponse(w, r.URL.String(), "embed key not found", http.StatusNotFound)
+		return
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), nil)
+}
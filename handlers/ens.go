@@ -107,3 +107,31 @@ func ReplaceEnsNameWithAddress(search string) string {
 	}
 	return search
 }
+
+// resolveEnsNames returns the primary ens names of the given addresses keyed by the raw address bytes, withdrawal credentials are resolved to their withdrawal address
+func resolveEnsNames(addresses ...[]byte) map[string]string {
+	names := make(map[string]string, len(addresses))
+	for _, address := range addresses {
+		if len(address) == 32 {
+			address, _ = utils.WithdrawalCredentialsToAddress(address)
+		}
+		if len(address) == 20 {
+			names[string(address)] = ""
+		}
+	}
+
+	err := db.GetEnsNamesForAddress(names)
+	if err != nil {
+		logger.Errorf("error resolving ens names: %v", err)
+	}
+	return names
+}
+
+// ensNameForWithdrawalCredentials returns the resolved ens name of the withdrawal address of 0x01 credentials
+func ensNameForWithdrawalCredentials(names map[string]string, credentials []byte) string {
+	address, err := utils.WithdrawalCredentialsToAddress(credentials)
+	if err != nil {
+		return ""
+	}
+	return names[string(address)]
+}
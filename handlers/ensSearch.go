@@ -3,13 +3,17 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
 	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 
 	"github.com/gorilla/mux"
+	"golang.org/x/sync/errgroup"
 )
 
-// Will return the EnsPage
+const ensProfileValidatorsLimit = 100
+
+// EnsSearch will return the ens profile page of a name (or of the primary name of an address) listing its addresses, validators and activity
 func EnsSearch(w http.ResponseWriter, r *http.Request) {
 	templateFiles := append(layoutTemplateFiles, "ensSearch.html")
 	var ensSearchTemplate = templates.GetTemplate(templateFiles...)
@@ -24,10 +28,37 @@ func EnsSearch(w http.ResponseWriter, r *http.Request) {
 	result, err := GetEnsDomain(search)
 
 	var pageData types.EnsSearchPageData
-	if err != nil {
+	if err != nil || len(result.Domain) == 0 {
 		pageData.Error = "No matching ENS registration found"
 	} else {
 		pageData.Result = result
+
+		profile, err := db.GetEnsProfile(result.Domain)
+		if err != nil {
+			logger.Errorf("error retrieving ens profile for %v route: %v", r.URL.String(), err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		pageData.Profile = profile
+
+		if profile != nil && len(profile.Address) > 0 {
+			g := new(errgroup.Group)
+			g.Go(func() error {
+				var err error
+				pageData.Validators, err = db.GetValidatorIndicesForEth1Address(profile.Address, ensProfileValidatorsLimit)
+				return err
+			})
+			g.Go(func() error {
+				var err error
+				pageData.Transactions, err = db.BigtableClient.GetAddressTransactionsTableData(profile.Address, "")
+				return err
+			})
+			if err := g.Wait(); err != nil {
+				logger.Errorf("error retrieving ens profile data for %v route: %v", r.URL.String(), err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+		}
 	}
 	pageData.Search = search
 
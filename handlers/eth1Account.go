@@ -1,21 +1,27 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"math/big"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
 	"github.com/gobitfly/eth2-beaconchain-explorer/eth1data"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
 	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/csrf"
 	"github.com/gorilla/mux"
 	"golang.org/x/sync/errgroup"
 )
@@ -57,18 +63,21 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	g := new(errgroup.Group)
-	g.SetLimit(11)
+	g.SetLimit(12)
 
 	isContract := false
 	txns := &types.DataTableResponse{}
 	blobs := &types.DataTableResponse{}
 	internal := &types.DataTableResponse{}
+	userOps := &types.DataTableResponse{}
 	erc20 := &types.DataTableResponse{}
 	erc721 := &types.DataTableResponse{}
 	erc1155 := &types.DataTableResponse{}
 	blocksMined := &types.DataTableResponse{}
 	unclesMined := &types.DataTableResponse{}
 	withdrawals := &types.DataTableResponse{}
+	nfts := []*types.Eth1AddressNFT{}
+	labels := []*types.AddressLabel{}
 	withdrawalSummary := template.HTML("0")
 
 	g.Go(func() error {
@@ -106,6 +115,14 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 		}
 		return nil
 	})
+	g.Go(func() error {
+		var err error
+		userOps, err = db.BigtableClient.GetAddressUserOpsTableData(addressBytes, "")
+		if err != nil {
+			return fmt.Errorf("GetAddressUserOpsTableData: %w", err)
+		}
+		return nil
+	})
 	g.Go(func() error {
 		var err error
 		erc20, err = db.BigtableClient.GetAddressErc20TableData(addressBytes, "")
@@ -130,6 +147,14 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 		}
 		return nil
 	})
+	g.Go(func() error {
+		var err error
+		nfts, err = db.BigtableClient.GetNFTsForAddress(addressBytes, db.NFTsPerAddressLimit)
+		if err != nil {
+			return fmt.Errorf("GetNFTsForAddress: %w", err)
+		}
+		return nil
+	})
 	g.Go(func() error {
 		var err error
 		blocksMined, err = db.BigtableClient.GetAddressBlocksMinedTableData(address, "")
@@ -154,6 +179,14 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 		}
 		return nil
 	})
+	g.Go(func() error {
+		var err error
+		labels, err = db.GetAddressLabels(addressBytes)
+		if err != nil {
+			return fmt.Errorf("GetAddressLabels: %w", err)
+		}
+		return nil
+	})
 	g.Go(func() error {
 		sumWithdrawals, err := db.GetAddressWithdrawalsTotal(addressBytes)
 		if err != nil {
@@ -170,6 +203,32 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var verifiedContract *types.VerifiedContract
+	hasContractReads := false
+	if isContract {
+		verifiedContract, err = db.GetVerifiedContract(addressBytes)
+		if err != nil {
+			utils.LogError(err, "error getting verified contract", 0, map[string]interface{}{"address": address})
+		}
+		if verifiedContract != nil {
+			contractAbi, err := abi.JSON(bytes.NewReader(verifiedContract.ABIJson))
+			if err != nil {
+				utils.LogError(err, "error parsing abi of verified contract", 0, map[string]interface{}{"address": address})
+			} else {
+				hasContractReads = eth1data.HasReadableMethods(&contractAbi)
+			}
+		}
+	}
+
+	flashMessage, err := utils.GetFlash(w, r, contractVerificationFlash)
+	if err != nil {
+		logger.Errorf("error retrieving flashes for address %v: %v", address, err)
+	}
+	labelFlashMessage, err := utils.GetFlash(w, r, addressLabelFlash)
+	if err != nil {
+		logger.Errorf("error retrieving label flashes for address %v: %v", address, err)
+	}
+
 	pngStr, pngStrInverse, err := utils.GenerateQRCodeForAddress(addressBytes)
 	if err != nil {
 		logger.WithError(err).Errorf("error generating qr code for address %v", address)
@@ -193,6 +252,14 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 			Data: internal,
 		})
 	}
+	if userOps != nil && len(userOps.Data) != 0 {
+		tabs = append(tabs, types.Eth1AddressPageTabs{
+			Id:   "userOps",
+			Href: "#userOps",
+			Text: "UserOps",
+			Data: userOps,
+		})
+	}
 	if erc20 != nil && len(erc20.Data) != 0 {
 		tabs = append(tabs, types.Eth1AddressPageTabs{
 			Id:   "erc20Txns",
@@ -233,6 +300,13 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 			Data: erc1155,
 		})
 	}
+	if len(nfts) != 0 {
+		tabs = append(tabs, types.Eth1AddressPageTabs{
+			Id:   "nfts",
+			Href: "#nfts",
+			Text: "NFTs",
+		})
+	}
 	if withdrawals != nil && len(withdrawals.Data) != 0 {
 		tabs = append(tabs, types.Eth1AddressPageTabs{
 			Id:   "withdrawals",
@@ -241,10 +315,46 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 			Data: withdrawals,
 		})
 	}
+	if isContract {
+		tabs = append(tabs, types.Eth1AddressPageTabs{
+			Id:   "contract",
+			Href: "#contract",
+			Text: "Contract",
+		})
+	}
+	if hasContractReads {
+		tabs = append(tabs, types.Eth1AddressPageTabs{
+			Id:   "readContract",
+			Href: "#readContract",
+			Text: "Read Contract",
+		})
+	}
+
+	subscribedEvents := make(map[types.EventName]bool)
+	user := getUser(r)
+	if user.Authenticated {
+		subs, err := db.GetSubscriptions(db.GetSubscriptionsFilter{
+			EventNames:   &types.EthAddressEvents,
+			UserIDs:      &[]uint64{user.UserID},
+			EventFilters: &[]string{address},
+		})
+		if err != nil {
+			logger.Errorf("error retrieving address subscriptions for %v route: %v", r.URL.String(), err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		for _, sub := range subs {
+			subscribedEvents[types.EventName(strings.TrimPrefix(sub.EventName, utils.GetNetwork()+":"))] = true
+		}
+	}
 
 	data.Data = types.Eth1AddressPageData{
 		Address:            address,
 		EnsName:            ensData.Domain,
+		Labels:             labels,
+		LabelCategories:    types.AddressLabelCategories,
+		LabelSubmissions:   utils.Config.Frontend.AddressLabels.CommunitySubmissions,
+		LabelFlashMessage:  labelFlashMessage,
 		IsContract:         isContract,
 		QRCode:             pngStr,
 		QRCodeInverse:      pngStrInverse,
@@ -253,10 +363,18 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 		TransactionsTable:  txns,
 		BlobTxnsTable:      blobs,
 		InternalTxnsTable:  internal,
+		UserOpsTable:       userOps,
 		Erc20Table:         erc20,
 		Erc721Table:        erc721,
 		Erc1155Table:       erc1155,
 		WithdrawalsTable:   withdrawals,
+		NFTs:               nfts,
+		VerifiedContract:   verifiedContract,
+		HasContractReads:   hasContractReads,
+		FlashMessage:       flashMessage,
+		CsrfField:          csrf.TemplateField(r),
+		NotificationEvents: types.EthAddressNotificationEvents,
+		SubscribedEvents:   subscribedEvents,
 		BlocksMinedTable:   blocksMined,
 		UnclesMinedTable:   unclesMined,
 		EtherValue:         utils.FormatPricedValue(utils.WeiBytesToEther(metadata.EthBalance.Balance), utils.Config.Frontend.ElCurrency, currency),
@@ -268,6 +386,71 @@ func Eth1Address(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+const contractVerificationFlash = "contract_verification_flash"
+
+// Eth1AddressVerifyContract fetches the verified source files and abi of a contract from sourcify and stores them
+func Eth1AddressVerifyContract(w http.ResponseWriter, r *http.Request) {
+	address, err := lowerAddressFromRequest(w, r)
+	if err != nil {
+		return
+	}
+	if !utils.IsEth1Address(address) {
+		handleNotFoundHtml(w, r)
+		return
+	}
+	addressBytes := common.FromHex(address)
+	redirectUrl := fmt.Sprintf("/address/0x%x#contract", addressBytes)
+	errFields := map[string]interface{}{"address": address}
+
+	existing, err := db.GetVerifiedContract(addressBytes)
+	if err != nil {
+		utils.LogError(err, "error getting verified contract", 0, errFields)
+		utils.SetFlash(w, r, contractVerificationFlash, "Error: could not verify the contract, please try again later")
+		http.Redirect(w, r, redirectUrl, http.StatusSeeOther)
+		return
+	}
+	if existing != nil && existing.MatchType == "full" {
+		http.Redirect(w, r, redirectUrl, http.StatusSeeOther)
+		return
+	}
+
+	contract, err := utils.FetchContractFromSourcify(addressBytes)
+	if err != nil {
+		utils.LogError(err, "error fetching contract from sourcify", 0, errFields)
+		utils.SetFlash(w, r, contractVerificationFlash, "Error: could not fetch the contract from sourcify, please try again later")
+		http.Redirect(w, r, redirectUrl, http.StatusSeeOther)
+		return
+	}
+	if contract == nil {
+		utils.SetFlash(w, r, contractVerificationFlash, "The contract source has not been verified on sourcify yet")
+		http.Redirect(w, r, redirectUrl, http.StatusSeeOther)
+		return
+	}
+
+	err = db.SaveVerifiedContract(contract)
+	if err != nil {
+		utils.LogError(err, "error saving verified contract", 0, errFields)
+		utils.SetFlash(w, r, contractVerificationFlash, "Error: could not verify the contract, please try again later")
+		http.Redirect(w, r, redirectUrl, http.StatusSeeOther)
+		return
+	}
+
+	contractAbi, err := abi.JSON(bytes.NewReader(contract.ABIJson))
+	if err == nil {
+		err = db.BigtableClient.UpdateContractMetadata(addressBytes, &types.ContractMetadata{
+			Name:    contract.Name,
+			ABI:     &contractAbi,
+			ABIJson: contract.ABIJson,
+		})
+	}
+	if err != nil {
+		utils.LogError(err, "error updating contract metadata of verified contract", 0, errFields)
+	}
+
+	utils.SetFlash(w, r, contractVerificationFlash, "The contract source has been verified via sourcify")
+	http.Redirect(w, r, redirectUrl, http.StatusSeeOther)
+}
+
 func Eth1AddressTransactions(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -379,6 +562,67 @@ func Eth1AddressWithdrawals(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Eth1AddressReadContract renders the results of the view functions of a verified contract that do not take any inputs,
+// the results are cached per contract and block
+func Eth1AddressReadContract(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "sprites.html", "execution/address.html")
+	var eth1AddressTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	address, err := lowerAddressFromRequest(w, r)
+	if err != nil {
+		return
+	}
+	addressBytes := common.FromHex(address)
+
+	errFields := map[string]interface{}{
+		"route": r.URL.String()}
+
+	verifiedContract, err := db.GetVerifiedContract(addressBytes)
+	if err != nil {
+		utils.LogError(err, "error getting verified contract", 0, errFields)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if verifiedContract == nil {
+		http.Error(w, "Contract not verified", http.StatusNotFound)
+		return
+	}
+
+	blockNumber := services.LatestEth1BlockNumber()
+	cacheKey := fmt.Sprintf("%d:contractReads:%x:%d", utils.Config.Chain.ClConfig.DepositChainID, addressBytes, blockNumber)
+	contractReads := []*types.ContractReadResult{}
+	if _, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, time.Minute, &contractReads); err != nil || blockNumber == 0 {
+		contractAbi, err := abi.JSON(bytes.NewReader(verifiedContract.ABIJson))
+		if err != nil {
+			utils.LogError(err, "error parsing abi of verified contract", 0, errFields)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		// the contract is read at the cached block so the results of a block are the same for all visitors
+		var atBlock *big.Int
+		if blockNumber != 0 {
+			atBlock = new(big.Int).SetUint64(blockNumber)
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), time.Second*10)
+		contractReads = eth1data.ReadContract(ctx, common.BytesToAddress(addressBytes), &contractAbi, atBlock)
+		cancel()
+
+		if blockNumber != 0 {
+			err = cache.TieredCache.Set(cacheKey, contractReads, time.Minute)
+			if err != nil {
+				utils.LogError(err, fmt.Errorf("error setting tieredCache for contract reads with key %v", cacheKey), 0)
+			}
+		}
+	}
+
+	if handleTemplateError(w, r, "eth1Account.go", "Eth1AddressReadContract", "", eth1AddressTemplate.ExecuteTemplate(w, "AddressReadContract", contractReads)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
 func Eth1AddressBlobTransactions(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -406,6 +650,33 @@ func Eth1AddressBlobTransactions(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func Eth1AddressUserOperations(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := r.URL.Query()
+	address, err := lowerAddressFromRequest(w, r)
+	if err != nil {
+		return
+	}
+	addressBytes := common.FromHex(address)
+
+	errFields := map[string]interface{}{
+		"route": r.URL.String()}
+
+	pageToken := q.Get("pageToken")
+	data, err := db.BigtableClient.GetAddressUserOpsTableData(addressBytes, pageToken)
+	if err != nil {
+		utils.LogError(err, "error getting eth1 user operations table data", 0, errFields)
+	}
+
+	err = json.NewEncoder(w).Encode(data)
+	if err != nil {
+		utils.LogError(err, "error enconding json response", 0, errFields)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
 func Eth1AddressInternalTransactions(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
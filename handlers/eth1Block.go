@@ -282,5 +282,14 @@ func GetExecutionBlockPageData(number uint64, limit int) (*types.Eth1BlockPageDa
 		eth1BlockPageData.MevBribe = relaysData.MevBribe.BigInt()
 		eth1BlockPageData.MevRecipientFormatted = utils.FormatAddressWithLimits(relaysData.MevRecipient, names[string(relaysData.MevRecipient)], false, "address", 42, 42, true)
 	}
+
+	eth1BlockPageData.RelayAnomalies, err = db.GetRelayAnomaliesForBlock(block.Hash)
+	if err != nil {
+		logger.Errorf("error retrieving relay anomalies for block %v: %v", block.Number, err)
+	}
+	eth1BlockPageData.Production, err = db.GetBlockProductionForBlock(block.Hash)
+	if err != nil {
+		logger.Errorf("error retrieving block production for block %v: %v", block.Number, err)
+	}
 	return &eth1BlockPageData, nil
 }
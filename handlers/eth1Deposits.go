@@ -1,9 +1,10 @@
 package handlers
 
 import (
-	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
 	"net/http"
-	"strconv"
 	"strings"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
@@ -47,41 +48,8 @@ func Eth1Deposits(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/validators/deposits", http.StatusMovedPermanently)
 }
 
-// Eth1DepositsData will return eth1-deposits as json
-func Eth1DepositsData(w http.ResponseWriter, r *http.Request) {
-	currency := GetCurrency(r)
-
-	w.Header().Set("Content-Type", "application/json")
-
-	q := r.URL.Query()
-
-	search := ReplaceEnsNameWithAddress(q.Get("search[value]"))
-	search = strings.Replace(search, "0x", "", -1)
-
-	draw, err := strconv.ParseUint(q.Get("draw"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables draw parameter from string to int: %v", err)
-		http.Error(w, "Error: Missing or invalid parameter draw", http.StatusBadRequest)
-		return
-	}
-	start, err := strconv.ParseUint(q.Get("start"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables start parameter from string to int: %v", err)
-		http.Error(w, "Error: Missing or invalid parameter start", http.StatusBadRequest)
-		return
-	}
-	length, err := strconv.ParseUint(q.Get("length"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables length parameter from string to int: %v", err)
-		http.Error(w, "Error: Missing or invalid parameter length", http.StatusBadRequest)
-		return
-	}
-	if length > 100 {
-		length = 100
-	}
-
-	orderColumn := q.Get("order[0][column]")
-	orderByMap := map[string]string{
+var eth1DepositsDataColumns = DataTableColumns{
+	OrderBy: map[string]string{
 		"0": "from_address",
 		"1": "publickey",
 		"2": "withdrawal_credential",
@@ -91,36 +59,54 @@ func Eth1DepositsData(w http.ResponseWriter, r *http.Request) {
 		"6": "block_number",
 		"7": "state",
 		"8": "valid_signature",
-	}
-	orderBy, exists := orderByMap[orderColumn]
-	if !exists {
-		orderBy = "block_ts"
-	}
+	},
+	DefaultOrderBy: "block_ts",
+}
 
-	orderDir := q.Get("order[0][dir]")
+// Eth1DepositsData will return eth1-deposits as json
+func Eth1DepositsData(w http.ResponseWriter, r *http.Request) {
+	currency := GetCurrency(r)
+
+	dataQuery, ok := handleDataTableQuery(w, r, eth1DepositsDataColumns)
+	if !ok {
+		return
+	}
+	search := ReplaceEnsNameWithAddress(dataQuery.Search)
+	search = strings.Replace(search, "0x", "", -1)
 
 	latestEpoch := services.LatestEpoch()
 	validatorOnlineThresholdSlot := GetValidatorOnlineThresholdSlot()
 
-	deposits, depositCount, err := db.GetEth1DepositsJoinEth2Deposits(search, length, start, orderBy, orderDir, latestEpoch, validatorOnlineThresholdSlot)
+	deposits, depositCount, err := db.GetEth1DepositsJoinEth2Deposits(search, dataQuery.Length, dataQuery.Start, dataQuery.OrderBy, dataQuery.OrderDir, latestEpoch, validatorOnlineThresholdSlot)
 	if err != nil {
 		logger.Errorf("GetEth1Deposits error retrieving eth1_deposit data: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
+	addresses := make([][]byte, 0, len(deposits)*2)
+	for _, d := range deposits {
+		addresses = append(addresses, d.FromAddress, d.WithdrawalCredentials)
+	}
+	names := resolveEnsNames(addresses...)
+	labels := resolveAddressLabels(addresses...)
+
 	tableData := make([][]interface{}, len(deposits))
 	for i, d := range deposits {
 		valid := "❌"
 		if d.ValidSignature {
 			valid = "✅"
 		}
+		txHash := utils.FormatEth1TxHash(d.TxHash)
+		if d.Warning != "" {
+			txHash = template.HTML(fmt.Sprintf(`<span class="text-danger mr-1"><i class="fas fa-exclamation-triangle" data-toggle="tooltip" title="%s"></i></span>`, html.EscapeString(utils.FormatEth1DepositWarning(d.Warning)))) + txHash
+		}
 		tableData[i] = []interface{}{
-			utils.FormatEth1Address(d.FromAddress),
+			utils.FormatEth1AddressWithName(d.FromAddress, names[string(d.FromAddress)]) + utils.FormatAddressLabels(labels[string(d.FromAddress)]),
 			utils.FormatPublicKey(d.PublicKey),
-			utils.FormatWithdawalCredentials(d.WithdrawalCredentials, true),
+			utils.FormatWithdrawalCredentialsWithName(d.WithdrawalCredentials, ensNameForWithdrawalCredentials(names, d.WithdrawalCredentials), true),
 			utils.FormatDepositAmount(d.Amount, currency),
-			utils.FormatEth1TxHash(d.TxHash),
+			txHash,
 			utils.FormatTimestamp(d.BlockTs.Unix()),
 			utils.FormatEth1Block(d.BlockNumber),
 			utils.FormatValidatorStatus(d.State),
@@ -128,19 +114,12 @@ func Eth1DepositsData(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	data := &types.DataTableResponse{
-		Draw:            draw,
+	writeDataTableResponse(w, r, &types.DataTableResponse{
+		Draw:            dataQuery.Draw,
 		RecordsTotal:    depositCount,
 		RecordsFiltered: depositCount,
 		Data:            tableData,
-	}
-
-	err = json.NewEncoder(w).Encode(data)
-	if err != nil {
-		logger.Errorf("error enconding json response for %v route: %v", r.URL.String(), err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
+	})
 }
 
 // Eth1Deposits will return information about deposits using a go template
@@ -161,39 +140,8 @@ func Eth1DepositsLeaderboard(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// Eth1DepositsData will return eth1-deposits as json
-func Eth1DepositsLeaderboardData(w http.ResponseWriter, r *http.Request) {
-	currency := GetCurrency(r)
-	w.Header().Set("Content-Type", "application/json")
-	q := r.URL.Query()
-
-	search := ReplaceEnsNameWithAddress(q.Get("search[value]"))
-	search = strings.Replace(search, "0x", "", -1)
-
-	draw, err := strconv.ParseUint(q.Get("draw"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables draw parameter from string to int: %v", err)
-		http.Error(w, "Error: Missing or invalid parameter draw", http.StatusBadRequest)
-		return
-	}
-	start, err := strconv.ParseUint(q.Get("start"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables start parameter from string to int: %v", err)
-		http.Error(w, "Error: Missing or invalid parameter start", http.StatusBadRequest)
-		return
-	}
-	length, err := strconv.ParseUint(q.Get("length"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables length parameter from string to int: %v", err)
-		http.Error(w, "Error: Missing or invalid parameter length", http.StatusBadRequest)
-		return
-	}
-	if length > 100 {
-		length = 100
-	}
-
-	orderColumn := q.Get("order[0][column]")
-	orderByMap := map[string]string{
+var eth1DepositsLeaderboardDataColumns = DataTableColumns{
+	OrderBy: map[string]string{
 		"0": "from_address",
 		"1": "amount",
 		"2": "validcount",
@@ -203,25 +151,39 @@ func Eth1DepositsLeaderboardData(w http.ResponseWriter, r *http.Request) {
 		"6": "slashedcount",
 		"7": "voluntary_exit_count",
 		"8": "totalcount",
-	}
-	orderBy, exists := orderByMap[orderColumn]
-	if !exists {
-		orderBy = "amount"
-	}
+	},
+	DefaultOrderBy: "amount",
+}
 
-	orderDir := q.Get("order[0][dir]")
+// Eth1DepositsData will return eth1-deposits as json
+func Eth1DepositsLeaderboardData(w http.ResponseWriter, r *http.Request) {
+	currency := GetCurrency(r)
+
+	dataQuery, ok := handleDataTableQuery(w, r, eth1DepositsLeaderboardDataColumns)
+	if !ok {
+		return
+	}
+	search := ReplaceEnsNameWithAddress(dataQuery.Search)
+	search = strings.Replace(search, "0x", "", -1)
 
-	deposits, depositCount, err := db.GetEth1DepositsLeaderboard(search, length, start, orderBy, orderDir)
+	deposits, depositCount, err := db.GetEth1DepositsLeaderboard(search, dataQuery.Length, dataQuery.Start, dataQuery.OrderBy, dataQuery.OrderDir)
 	if err != nil {
 		logger.Errorf("GetEth1Deposits error retrieving eth1_deposit leaderboard data: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
+	addresses := make([][]byte, 0, len(deposits))
+	for _, d := range deposits {
+		addresses = append(addresses, d.FromAddress)
+	}
+	names := resolveEnsNames(addresses...)
+	labels := resolveAddressLabels(addresses...)
+
 	tableData := make([][]interface{}, len(deposits))
 	for i, d := range deposits {
 		tableData[i] = []interface{}{
-			utils.FormatEth1Address(d.FromAddress),
+			utils.FormatEth1AddressWithName(d.FromAddress, names[string(d.FromAddress)]) + utils.FormatAddressLabels(labels[string(d.FromAddress)]),
 			utils.FormatBalance(d.Amount, currency),
 			d.ValidCount,
 			d.InvalidCount,
@@ -240,17 +202,10 @@ func Eth1DepositsLeaderboardData(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	data := &types.DataTableResponse{
-		Draw:            draw,
+	writeDataTableResponse(w, r, &types.DataTableResponse{
+		Draw:            dataQuery.Draw,
 		RecordsTotal:    depositCount,
 		RecordsFiltered: depositCount,
 		Data:            tableData,
-	}
-
-	err = json.NewEncoder(w).Encode(data)
-	if err != nil {
-		logger.Errorf("error enconding json response for %v route: %v", r.URL.String(), err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
+	})
 }
@@ -109,6 +109,27 @@ func Eth1TransactionTx(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 
+			addresses := [][]byte{txData.From.Bytes()}
+			if txData.To != nil {
+				addresses = append(addresses, txData.To.Bytes())
+			}
+			labels, err := db.GetAddressLabelsForAddresses(addresses)
+			if err != nil {
+				utils.LogError(err, "error retrieving address labels", 0, errFields)
+			} else {
+				txData.FromLabels = labels[string(txData.From.Bytes())]
+				if txData.To != nil {
+					txData.ToLabels = labels[string(txData.To.Bytes())]
+				}
+			}
+
+			if len(txData.DepositContractInteractions) > 0 {
+				txData.DepositWarnings, err = db.GetEth1DepositWarningsForTx(txData.Hash.Bytes())
+				if err != nil {
+					utils.LogError(err, "error retrieving eth1 deposit warnings", 0, errFields)
+				}
+			}
+
 			data = InitPageData(w, r, "blockchain", path, title, txTemplateFiles)
 			data.Data = txData
 		}
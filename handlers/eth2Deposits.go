@@ -1,9 +1,7 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
-	"strconv"
 	"strings"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
@@ -16,55 +14,29 @@ func Eth2Deposits(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/validators/deposits", http.StatusMovedPermanently)
 }
 
-// Eth2DepositsData will return information eth1-deposits in json
-func Eth2DepositsData(w http.ResponseWriter, r *http.Request) {
-	currency := GetCurrency(r)
-	w.Header().Set("Content-Type", "application/json")
-
-	q := r.URL.Query()
-
-	search := ReplaceEnsNameWithAddress(q.Get("search[value]"))
-	search = strings.Replace(search, "0x", "", -1)
-
-	draw, err := strconv.ParseUint(q.Get("draw"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables draw parameter from string to int: %v", err)
-		http.Error(w, "Error: Missing or invalid parameter draw", http.StatusBadRequest)
-		return
-	}
-	start, err := strconv.ParseUint(q.Get("start"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables start parameter from string to int: %v", err)
-		http.Error(w, "Error: Missing or invalid parameter start", http.StatusBadRequest)
-		return
-	}
-	length, err := strconv.ParseUint(q.Get("length"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables length parameter from string to int: %v", err)
-		http.Error(w, "Error: Missing or invalid parameter length", http.StatusBadRequest)
-		return
-	}
-	if length > 100 {
-		length = 100
-	}
-
-	orderColumn := q.Get("order[0][column]")
-	orderByMap := map[string]string{
+var eth2DepositsDataColumns = DataTableColumns{
+	OrderBy: map[string]string{
 		"0": "block_slot",
-		// "1": "validatorindex",
 		"1": "publickey",
 		"2": "amount",
 		"3": "withdrawalcredentials",
 		"4": "signature",
-	}
-	orderBy, exists := orderByMap[orderColumn]
-	if !exists {
-		orderBy = "block_ts"
-	}
+	},
+	DefaultOrderBy: "block_ts",
+}
 
-	orderDir := q.Get("order[0][dir]")
+// Eth2DepositsData will return information eth1-deposits in json
+func Eth2DepositsData(w http.ResponseWriter, r *http.Request) {
+	currency := GetCurrency(r)
 
-	deposits, depositCount, err := db.GetEth2Deposits(search, length, start, orderBy, orderDir)
+	dataQuery, ok := handleDataTableQuery(w, r, eth2DepositsDataColumns)
+	if !ok {
+		return
+	}
+	search := ReplaceEnsNameWithAddress(dataQuery.Search)
+	search = strings.Replace(search, "0x", "", -1)
+
+	deposits, depositCount, err := db.GetEth2Deposits(search, dataQuery.Length, dataQuery.Start, dataQuery.OrderBy, dataQuery.OrderDir)
 	if err != nil {
 		logger.Errorf("error retrieving eth2_deposit data or count: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -84,17 +56,10 @@ func Eth2DepositsData(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	data := &types.DataTableResponse{
-		Draw:            draw,
+	writeDataTableResponse(w, r, &types.DataTableResponse{
+		Draw:            dataQuery.Draw,
 		RecordsTotal:    depositCount,
 		RecordsFiltered: depositCount,
 		Data:            tableData,
-	}
-
-	err = json.NewEncoder(w).Encode(data)
-	if err != nil {
-		logger.Errorf("error enconding json response for %v route: %v", r.URL.String(), err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
+	})
 }
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// Exits returns the voluntary exits and the exit queue using a go template
+func Exits(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "exits.html")
+	var exitsTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	data := InitPageData(w, r, "validators", "/exits", "Voluntary Exits", templateFiles)
+
+	pageData := &types.ExitsPageData{}
+
+	epoch := services.LatestEpoch()
+	queueLength, lastExitEpoch, err := db.GetExitQueue(epoch)
+	if err != nil {
+		utils.LogError(err, "error getting exit queue", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	pageData.QueueLength = queueLength
+	pageData.LastExitEpoch = lastExitEpoch
+	if lastExitEpoch > epoch {
+		pageData.LastExitTime = utils.EpochToTime(lastExitEpoch)
+	}
+
+	totalExits, err := db.GetVoluntaryExitCount()
+	if err != nil {
+		utils.LogError(err, "error getting voluntary exit count", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	pageData.TotalExits = totalExits
+
+	stats := services.GetLatestStats()
+	if stats.ValidatorChurnLimit != nil {
+		pageData.ChurnLimit = *stats.ValidatorChurnLimit
+	}
+
+	data.Data = pageData
+
+	if handleTemplateError(w, r, "exits.go", "Exits", "", exitsTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// ExitsData returns the voluntary exits in json
+func ExitsData(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	currency := GetCurrency(r)
+
+	q := r.URL.Query()
+
+	draw, err := strconv.ParseUint(q.Get("draw"), 10, 64)
+	if err != nil {
+		logger.Warnf("error converting datatables draw parameter from string to int: %v", err)
+		http.Error(w, "Error: Missing or invalid parameter draw", http.StatusBadRequest)
+		return
+	}
+
+	start, err := strconv.ParseUint(q.Get("start"), 10, 64)
+	if err != nil {
+		logger.Warnf("error converting datatables start parameter from string to int: %v", err)
+		http.Error(w, "Error: Missing or invalid parameter start", http.StatusBadRequest)
+		return
+	}
+	length, err := strconv.ParseUint(q.Get("length"), 10, 64)
+	if err != nil {
+		logger.Warnf("error converting datatables length parameter from string to int: %v", err)
+		http.Error(w, "Error: Missing or invalid parameter length", http.StatusBadRequest)
+		return
+	}
+	if length > 100 {
+		length = 100
+	}
+
+	exits, err := db.GetVoluntaryExits(length, start)
+	if err != nil {
+		logger.Errorf("error retrieving voluntary exits from the database: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	records, err := db.GetVoluntaryExitCount()
+	if err != nil {
+		logger.Errorf("GetVoluntaryExitCount failed to retrieve record count: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	var validatorCount uint64
+	err = db.ReaderDb.Get(&validatorCount, "SELECT COUNT(*) FROM validators")
+	if err != nil {
+		logger.Errorf("error retrieving validator count from the database: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	var cursor uint64
+	stats := services.GetLatestStats()
+	if stats.LatestValidatorWithdrawalIndex != nil {
+		cursor = *stats.LatestValidatorWithdrawalIndex
+	}
+
+	validatorsForNameSearch := make([]uint64, 0, len(exits))
+	for _, exit := range exits {
+		validatorsForNameSearch = append(validatorsForNameSearch, exit.ValidatorIndex)
+	}
+	validatorNames, err := db.GetValidatorNames(validatorsForNameSearch)
+	if err != nil {
+		logger.Errorf("error retrieving validator names from the database: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	epoch := services.LatestEpoch()
+	tableData := make([][]interface{}, 0, len(exits))
+	for _, exit := range exits {
+		status := "Exited"
+		if exit.ExitEpoch > epoch {
+			status = "In Exit Queue"
+		} else if exit.WithdrawableEpoch <= epoch {
+			status = "Withdrawable"
+		}
+
+		projectedWithdrawal := "-"
+		if exit.Balance > 0 {
+			projectedWithdrawal = string(utils.FormatTimestamp(projectFullWithdrawal(epoch, exit.ValidatorIndex, exit.WithdrawableEpoch, cursor, validatorCount).Unix()))
+		} else if exit.WithdrawableEpoch <= epoch {
+			status = "Withdrawn"
+		}
+
+		tableData = append(tableData, []interface{}{
+			utils.FormatValidatorWithName(exit.ValidatorIndex, validatorNames[exit.ValidatorIndex]),
+			utils.FormatBlockSlot(exit.Slot),
+			utils.FormatTimestamp(utils.SlotToTime(exit.Slot).Unix()),
+			utils.FormatEpoch(exit.ExitEpoch),
+			utils.FormatEpoch(exit.WithdrawableEpoch),
+			projectedWithdrawal,
+			utils.FormatClCurrency(exit.Balance, currency, 6, true, false, false, true),
+			status,
+		})
+	}
+
+	data := &types.DataTableResponse{
+		Draw:            draw,
+		RecordsTotal:    records,
+		RecordsFiltered: records,
+		Data:            tableData,
+	}
+
+	err = json.NewEncoder(w).Encode(data)
+	if err != nil {
+		logger.Errorf("error enconding json response for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// projectFullWithdrawal estimates when the balance of an exited validator will be swept. If the validator is not yet
+// withdrawable the sweep cursor is advanced by the validators the sweep will pass until the withdrawable epoch is reached.
+func projectFullWithdrawal(epoch, validatorIndex, withdrawableEpoch, cursor, validatorCount uint64) time.Time {
+	if validatorCount == 0 {
+		return utils.EpochToTime(withdrawableEpoch)
+	}
+
+	start := time.Now()
+	if withdrawableEpoch > epoch {
+		slots := (withdrawableEpoch - epoch) * utils.Config.Chain.ClConfig.SlotsPerEpoch
+		cursor = (cursor + slots*utils.Config.Chain.ClConfig.MaxWithdrawalsPerPayload) % validatorCount
+		start = utils.EpochToTime(withdrawableEpoch)
+	}
+
+	distance := (validatorIndex + validatorCount - cursor%validatorCount) % validatorCount
+	return start.Add(time.Until(utils.GetTimeToNextWithdrawal(distance)))
+}
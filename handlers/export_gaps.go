@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// ExportGaps returns the slots, epochs and execution blocks missing in the exported data that are queued for re-export
+func ExportGaps(w http.ResponseWriter, r *http.Request) {
+	if isAdmin, _ := handleAdminPermissions(w, r); !isAdmin {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	gaps, err := db.GetExportGaps()
+	if err != nil {
+		utils.LogError(err, "error retrieving export gaps", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(gaps)
+	if err != nil {
+		logger.WithError(err).Error("error encoding export gaps")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/faucet"
+	"github.com/gobitfly/eth2-beaconchain-explorer/ratelimit"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Faucet will return the page of the testnet faucet
+func Faucet(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "faucet.html")
+	var faucetTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	data := InitPageData(w, r, "tools", "/faucet", "Faucet", templateFiles)
+	pageData := &types.FaucetPageData{
+		Address:      faucet.Address().Hex(),
+		Amount:       fmt.Sprintf("%v %v", utils.WeiToEther(faucet.Amount()), utils.Config.Frontend.ElCurrency),
+		Cooldown:     faucet.Cooldown(),
+		RecaptchaKey: utils.Config.Frontend.RecaptchaSiteKey,
+	}
+
+	balance, err := faucet.Balance(r.Context())
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving faucet balance")
+	} else {
+		pageData.Balance = fmt.Sprintf("%v %v", utils.WeiToEther(balance).Round(4), utils.Config.Frontend.ElCurrency)
+	}
+
+	pageData.RecentRequests, err = db.GetRecentFaucetRequests(10)
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving recent faucet requests")
+	}
+
+	pageData.FlashMessage, err = utils.GetFlash(w, r, "info_flash")
+	if err != nil {
+		logger.Errorf("error retrieving flashes for faucet %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data.Data = pageData
+	if handleTemplateError(w, r, "faucet.go", "Faucet", "", faucetTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// FaucetPost sends the funds of the faucet to the address of the submitted form
+func FaucetPost(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		utils.LogError(err, "error parsing form", 0)
+		utils.SetFlash(w, r, "info_flash", "Error: invalid form submitted")
+		http.Redirect(w, r, "/faucet", http.StatusSeeOther)
+		return
+	}
+
+	// the faucet is only initialized with recaptcha keys
+	valid, err := utils.ValidateReCAPTCHA(r.FormValue("g-recaptcha-response"))
+	if err != nil || !valid {
+		logger.Warnf("failed validating recaptcha %v route: %v", r.URL.String(), err)
+		utils.SetFlash(w, r, "info_flash", "Error: Failed to validate the captcha")
+		http.Redirect(w, r, "/faucet", http.StatusSeeOther)
+		return
+	}
+
+	address := strings.TrimSpace(r.FormValue("address"))
+	if !common.IsHexAddress(address) {
+		utils.SetFlash(w, r, "info_flash", "Error: invalid address")
+		http.Redirect(w, r, "/faucet", http.StatusSeeOther)
+		return
+	}
+
+	txHash, err := faucet.Fund(r.Context(), common.HexToAddress(address), ratelimit.GetIP(r))
+	if err != nil {
+		errMsg := "Error: Sorry something went wrong :("
+		switch {
+		case errors.Is(err, faucet.ErrCooldown):
+			errMsg = fmt.Sprintf("Error: %v, please try again later", err)
+		case errors.Is(err, faucet.ErrInsufficientFunds):
+			errMsg = fmt.Sprintf("Error: %v", err)
+		default:
+			logger.WithError(err).Errorf("error funding %v from the faucet", address)
+		}
+		utils.SetFlash(w, r, "info_flash", errMsg)
+		http.Redirect(w, r, "/faucet", http.StatusSeeOther)
+		return
+	}
+
+	utils.SetFlash(w, r, "info_flash", fmt.Sprintf(`Funds sent in transaction <a href="/tx/%v">%v</a>`, txHash.Hex(), txHash.Hex()))
+	http.Redirect(w, r, "/faucet", http.StatusSeeOther)
+}
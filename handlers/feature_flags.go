@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/featureflags"
+	"github.com/gobitfly/eth2-beaconchain-explorer/ratelimit"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/csrf"
+	"github.com/sirupsen/logrus"
+)
+
+// featureFlagSubject returns the subject the percentage rollout of the feature flags is based on, the user id for
+// logged in users and the ip otherwise. The flags enabled for the user of a request are part of the page data.
+func featureFlagSubject(r *http.Request, user *types.User) string {
+	if user != nil && user.Authenticated {
+		return fmt.Sprintf("user:%d", user.UserID)
+	}
+	return "ip:" + ratelimit.GetIP(r)
+}
+
+// FeatureFlags renders the feature flags admin page
+func FeatureFlags(w http.ResponseWriter, r *http.Request) {
+	if isAdmin, _ := handleAdminPermissions(w, r); !isAdmin {
+		return
+	}
+
+	templateFiles := append(layoutTemplateFiles, "user/feature_flags.html")
+	var userTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	flags, err := featureflags.All()
+	if err != nil {
+		utils.LogErrorContext(r.Context(), err, "error loading feature flags", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data := InitPageData(w, r, "user", "/user/feature_flags", "Feature Flags", templateFiles)
+	data.Data = types.FeatureFlagsPageData{Flags: flags, CsrfField: csrf.TemplateField(r)}
+
+	if handleTemplateError(w, r, "feature_flags.go", "FeatureFlags", "", userTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// FeatureFlagsPost creates, updates or deletes a feature flag
+func FeatureFlagsPost(w http.ResponseWriter, r *http.Request) {
+	isAdmin, user := handleAdminPermissions(w, r)
+	if !isAdmin {
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		utils.LogErrorContext(r.Context(), err, "error parsing form", 0)
+		http.Redirect(w, r, "/user/feature_flags?error=parsingForm", http.StatusSeeOther)
+		return
+	}
+
+	name := r.FormValue("name")
+	if r.FormValue("delete") != "" {
+		err = featureflags.Delete(name)
+		if err != nil {
+			utils.LogErrorContext(r.Context(), err, "error deleting feature flag", 0, map[string]interface{}{"name": name})
+			http.Redirect(w, r, "/user/feature_flags?error=deleteFailed", http.StatusSeeOther)
+			return
+		}
+		requestLogger(r).WithFields(logrus.Fields{"name": name, "user": user.UserID}).Info("deleted feature flag")
+		http.Redirect(w, r, "/user/feature_flags", http.StatusSeeOther)
+		return
+	}
+
+	rollout, err := strconv.Atoi(r.FormValue("rollout_percentage"))
+	if err != nil {
+		http.Redirect(w, r, "/user/feature_flags?error=invalidRolloutPercentage", http.StatusSeeOther)
+		return
+	}
+	flag := &types.FeatureFlag{
+		Name:              name,
+		Enabled:           r.FormValue("enabled") == "on",
+		RolloutPercentage: rollout,
+		Description:       r.FormValue("description"),
+	}
+	err = featureflags.Save(flag)
+	if err != nil {
+		utils.LogErrorContext(r.Context(), err, "error saving feature flag", 0, map[string]interface{}{"name": name})
+		http.Redirect(w, r, "/user/feature_flags?error=saveFailed", http.StatusSeeOther)
+		return
+	}
+	requestLogger(r).WithFields(logrus.Fields{"name": name, "enabled": flag.Enabled, "rollout_percentage": rollout, "user": user.UserID}).Info("changed feature flag")
+
+	http.Redirect(w, r, "/user/feature_flags", http.StatusSeeOther)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// ChainForks will return the page with the countdown to the upcoming forks and the readiness of the clients
+func ChainForks(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "forks.html")
+	var forksTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	data := InitPageData(w, r, "services", "/forks", "Network Upgrades", templateFiles)
+	data.Data = types.ChainForksPageData{
+		Forks:        services.LatestChainForks(),
+		CurrentEpoch: services.LatestEpoch(),
+	}
+
+	if handleTemplateError(w, r, "forks.go", "ChainForks", "", forksTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// ApiChainForks godoc
+// @Summary Get the forks of the chain with their activation status
+// @Tags Network
+// @Description Returns the forks specified by the chain config, oldest first. The status is active, scheduled or unscheduled (specified without activation epoch).
+// @Description For forks which are not active yet the readiness of the consensus clients is included: their latest and first fork-supporting releases and the share of the blocks
+// @Description of the last 7 days proposed with a fork-supporting client release, derived from the client versions in the block graffitis.
+// @Produce  json
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiChainForkResponse}
+// @Router /api/v1/chain/forks [get]
+func ApiChainForks(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+
+	SendOKResponse(j, r.URL.String(), []interface{}{services.LatestChainForks()})
+}
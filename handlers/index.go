@@ -100,7 +100,7 @@ func calculateChurn(page *types.IndexPageData) {
 	limit := services.GetLatestStats().ValidatorActivationChurnLimit
 	pending_validators := services.GetLatestStats().PendingValidatorCount
 	// calculate daily new validators
-	limit_per_day := *limit * uint64(225)
+	limit_per_day := *limit * utils.EpochsPerDay()
 	// calculate how long it will take for a new deposit to be processed
 	time := float64(*pending_validators) / float64((limit_per_day))
 	const hoursPerDay = 24
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+	"github.com/shopspring/decimal"
+)
+
+// l2FlowsHistoryDays is the amount of days shown in the layer 2 bridge flow charts
+const l2FlowsHistoryDays = 180
+
+// L2Flows renders the daily ether deposited into and withdrawn from the bridges of the tracked layer 2 networks
+func L2Flows(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	templateFiles := append(layoutTemplateFiles, "l2_flows.html")
+	var l2FlowsTemplate = templates.GetTemplate(templateFiles...)
+
+	data := InitPageData(w, r, "stats", "/l2-flows", "L2 Bridge Flows", templateFiles)
+
+	var fromDay uint64
+	latestDay := utils.DayOfSlot(services.LatestSlot())
+	if latestDay > l2FlowsHistoryDays {
+		fromDay = latestDay - l2FlowsHistoryDays
+	}
+
+	history, err := db.GetL2BridgeFlowsHistory(fromDay)
+	if err != nil {
+		utils.LogError(err, "error retrieving l2 bridge flows history", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	divisor := decimal.NewFromInt(utils.Config.Frontend.ElCurrencyDivisor)
+	depositData := map[string][][]float64{}
+	withdrawalData := map[string][][]float64{}
+	netData := map[string][][]float64{}
+
+	// the network table sums up the flows of the whole shown period
+	networks := map[string]*types.L2BridgeFlowsDailyStats{}
+	for _, network := range db.L2BridgeNetworks {
+		networks[network] = &types.L2BridgeFlowsDailyStats{Day: fromDay, Network: network}
+	}
+	for _, d := range history {
+		n, exists := networks[d.Network]
+		if !exists {
+			continue
+		}
+		ts := float64(utils.DayToTime(int64(d.Day)).Unix() * 1000)
+		depositData[d.Network] = append(depositData[d.Network], []float64{ts, d.DepositAmount.Div(divisor).InexactFloat64()})
+		withdrawalData[d.Network] = append(withdrawalData[d.Network], []float64{ts, -d.WithdrawalAmount.Div(divisor).InexactFloat64()})
+		netData[d.Network] = append(netData[d.Network], []float64{ts, d.NetFlow.Div(divisor).InexactFloat64()})
+
+		n.DepositCount += d.DepositCount
+		n.DepositAmount = n.DepositAmount.Add(d.DepositAmount)
+		n.WithdrawalCount += d.WithdrawalCount
+		n.WithdrawalAmount = n.WithdrawalAmount.Add(d.WithdrawalAmount)
+		n.NetFlow = n.NetFlow.Add(d.NetFlow)
+	}
+
+	pageData := &types.L2FlowsPageData{
+		Days:        latestDay - fromDay,
+		FlowSeries:  make([]*types.GenericChartDataSeries, 0, len(db.L2BridgeNetworks)*2),
+		NetSeries:   make([]*types.GenericChartDataSeries, 0, len(db.L2BridgeNetworks)),
+		Networks:    make([]*types.L2BridgeFlowsDailyStats, 0, len(networks)),
+		LastUpdated: time.Now(),
+	}
+	for _, network := range db.L2BridgeNetworks {
+		pageData.FlowSeries = append(pageData.FlowSeries,
+			&types.GenericChartDataSeries{Name: network + " Deposits", Data: depositData[network], Type: "column", Stack: "deposits"},
+			&types.GenericChartDataSeries{Name: network + " Withdrawals", Data: withdrawalData[network], Type: "column", Stack: "withdrawals"},
+		)
+		pageData.NetSeries = append(pageData.NetSeries, &types.GenericChartDataSeries{Name: network, Data: netData[network], Type: "column"})
+		pageData.Networks = append(pageData.Networks, networks[network])
+	}
+	sort.Slice(pageData.Networks, func(i, j int) bool {
+		return pageData.Networks[i].DepositAmount.GreaterThan(pageData.Networks[j].DepositAmount)
+	})
+
+	data.Data = pageData
+
+	if handleTemplateError(w, r, "l2_flows.go", "L2Flows", "", l2FlowsTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// SetLanguage stores the chosen language in the language cookie and redirects back to the page it was chosen on
+func SetLanguage(w http.ResponseWriter, r *http.Request) {
+	lang := mux.Vars(r)["lang"]
+	if !utils.IsSupportedLanguage(lang) {
+		http.Error(w, "Unsupported language", http.StatusBadRequest)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     "language",
+		Value:    lang,
+		Path:     "/",
+		Expires:  time.Now().Add(utils.Day * 365),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	// only redirect to pages of this site
+	redirect := "/"
+	if referer, err := url.Parse(r.Referer()); err == nil && referer.Host == r.Host {
+		redirect = referer.RequestURI()
+	}
+	http.Redirect(w, r, redirect, http.StatusSeeOther)
+}
@@ -1,5 +1,57 @@
 package handlers
 
-import "github.com/sirupsen/logrus"
+import (
+	"encoding/json"
+	"net/http"
 
-var logger = logrus.New().WithField("module", "handlers")
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+var logger = utils.NewLogger("handlers")
+
+// requestLogger returns the logger with the id of the request
+func requestLogger(r *http.Request) *logrus.Entry {
+	return utils.LoggerWithContext(r.Context(), logger)
+}
+
+// LogLevels returns the log level of every component
+func LogLevels(w http.ResponseWriter, r *http.Request) {
+	if isAdmin, _ := handleAdminPermissions(w, r); !isAdmin {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(utils.LogLevels())
+	if err != nil {
+		requestLogger(r).WithError(err).Error("error encoding log levels")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// LogLevelsPost changes the log level of a component until the next restart
+func LogLevelsPost(w http.ResponseWriter, r *http.Request) {
+	isAdmin, user := handleAdminPermissions(w, r)
+	if !isAdmin {
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, "Bad request", http.StatusBadRequest)
+		return
+	}
+
+	component := r.FormValue("component")
+	level := r.FormValue("level")
+	err = utils.SetLogLevel(component, level)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	requestLogger(r).WithFields(logrus.Fields{"component": component, "level": level, "user": user.UserID}).Info("changed log level")
+
+	LogLevels(w, r)
+}
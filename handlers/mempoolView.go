@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"encoding/json"
 	"math/big"
 	"net/http"
+	"time"
 
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
 	"github.com/gobitfly/eth2-beaconchain-explorer/services"
 	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
@@ -12,6 +15,9 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
+// mempoolHistoryDays is the amount of days shown in the mempool charts
+const mempoolHistoryDays = 7
+
 func MempoolView(w http.ResponseWriter, r *http.Request) {
 	mempool := services.LatestMempoolTransactions()
 	formatedData := formatToTable(mempool)
@@ -21,13 +27,59 @@ func MempoolView(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
 	data := InitPageData(w, r, "services", "/mempool", "Pending Mempool Transactions", templateFiles)
 
-	data.Data = formatedData
+	pageData := &types.MempoolPageData{
+		Table: formatedData,
+		Stats: services.LatestMempoolStats(),
+	}
+
+	history, err := db.GetMempoolSnapshotHistory(time.Now().Add(-time.Hour*24*mempoolHistoryDays), time.Minute*10)
+	if err != nil {
+		utils.LogError(err, "error retrieving mempool snapshot history", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	pendingData := make([][]float64, 0, len(history))
+	queuedData := make([][]float64, 0, len(history))
+	p25Data := make([][]float64, 0, len(history))
+	p50Data := make([][]float64, 0, len(history))
+	p75Data := make([][]float64, 0, len(history))
+	for _, h := range history {
+		ts := float64(h.Ts.Unix() * 1000)
+		pendingData = append(pendingData, []float64{ts, float64(h.PendingCount)})
+		queuedData = append(queuedData, []float64{ts, float64(h.NonExecutableCount())})
+		p25Data = append(p25Data, []float64{ts, h.GasPriceP25})
+		p50Data = append(p50Data, []float64{ts, h.GasPriceP50})
+		p75Data = append(p75Data, []float64{ts, h.GasPriceP75})
+	}
+	pageData.SizeSeries = []*types.GenericChartDataSeries{
+		{Name: "Pending", Data: pendingData},
+		{Name: "Queued", Data: queuedData},
+	}
+	pageData.GasPriceSeries = []*types.GenericChartDataSeries{
+		{Name: "25th Percentile", Data: p25Data},
+		{Name: "Median", Data: p50Data},
+		{Name: "75th Percentile", Data: p75Data},
+	}
+
+	data.Data = pageData
 
 	if handleTemplateError(w, r, "mempoolView.go", "MempoolView", "", mempoolViewTemplate.ExecuteTemplate(w, "layout", data)) != nil {
 		return // an error has occurred and was processed
 	}
 }
 
+// MempoolViewData returns the current mempool transactions formatted for the live table of the mempool page
+func MempoolViewData(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(formatToTable(services.LatestMempoolTransactions()))
+	if err != nil {
+		logger.Errorf("error enconding json response for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
 // This is a helper function. It replaces Nil or empty receiver Address with a string in case case of a new contract creation.
 // This function catches the Nil exception
 func _isContractCreation(tx *common.Address) string {
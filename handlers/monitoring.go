@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -61,3 +62,30 @@ func Monitoring(w http.ResponseWriter, r *http.Request) {
 	}
 
 }
+
+// ApiConsistencyReport godoc
+// @Summary Get the report of the last consistency audit
+// @Tags Monitoring
+// @Description Returns the mismatches between the stored data and the beacon and execution nodes found by the last run of the consistency audit
+// @Produce  json
+// @Success 200 {object} types.ApiResponse{data=types.ConsistencyReport}
+// @Failure 400 {object} types.ApiResponse "Failure"
+// @Failure 500 {object} types.ApiResponse "Server Error"
+// @Router /api/v1/monitoring/consistency [get]
+func ApiConsistencyReport(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	report, err := db.GetLatestConsistencyReport()
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			SendBadRequestResponse(w, r.URL.String(), "no consistency report available")
+			return
+		}
+		logger.Errorf("error retrieving consistency report: %v", err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{report})
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/tracing"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// networkLinks returns the entries of the network switcher, the network of the deployment is marked active
+func networkLinks() []types.NetworkLink {
+	links := make([]types.NetworkLink, 0, len(utils.Config.Frontend.Networks))
+	for _, network := range utils.Config.Frontend.Networks {
+		label := network.Label
+		if label == "" {
+			label = network.Name
+		}
+		links = append(links, types.NetworkLink{Label: label, Url: network.Url, Active: network.Name == utils.Config.Chain.Name})
+	}
+	return links
+}
+
+// RegisterNetworkApiRoutes serves the api of every configured network under /api/v1/{network}/. Requests for the
+// network of the deployment are served by the api router, requests for other networks are proxied to the api of
+// their deployment, so a single api host can serve all networks.
+func RegisterNetworkApiRoutes(router *mux.Router, apiV1Router *mux.Router) error {
+	for _, network := range utils.Config.Frontend.Networks {
+		prefix := "/api/v1/" + network.Name
+		if network.Name == utils.Config.Chain.Name {
+			router.PathPrefix(prefix + "/").Handler(http.StripPrefix(prefix, rewriteApiPath(apiV1Router)))
+			continue
+		}
+
+		apiUrl := network.ApiUrl
+		if apiUrl == "" {
+			apiUrl = network.Url
+		}
+		target, err := url.Parse(apiUrl)
+		if err != nil {
+			return err
+		}
+		proxy := httputil.NewSingleHostReverseProxy(target)
+		proxy.Transport = tracing.Transport(nil)
+		director := proxy.Director
+		proxy.Director = func(r *http.Request) {
+			director(r)
+			r.Host = target.Host
+		}
+		router.PathPrefix(prefix + "/").Handler(http.StripPrefix(prefix, rewriteApiPath(proxy)))
+	}
+	return nil
+}
+
+// rewriteApiPath restores the /api/v1 prefix removed together with the network name
+func rewriteApiPath(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.URL.Path = "/api/v1" + r.URL.Path
+		if r.URL.RawPath != "" {
+			r.URL.RawPath = "/api/v1" + r.URL.RawPath
+		}
+		r.RequestURI = r.URL.RequestURI()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -1,14 +1,151 @@
 package handlers
 
-/*
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
 
-TODO:
-Check code creation timestamp (expiration)
-Set consumed flag once exchanged for access token
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 
-Code -> AC & Refresh token:
-Check for consumed & expired during access token exchange
-Store device information in this table (makes it easier for users to manage & revoke access for their devices)
+	"github.com/gorilla/mux"
+)
 
+// oauthScopeRoutes lists the api routes that access tokens of third-party apps may use per scope, scoped tokens are read-only
+var oauthScopeRoutes = map[string][]string{
+	utils.OAuthScopeDashboardRead: {
+		"/api/v1/user/dashboards",
+		"/api/v1/user/dashboards/{dashboardId}",
+		"/api/v1/user/dashboards/{dashboardId}/groups",
+		"/api/v1/user/dashboards/{dashboardId}/summary",
+		"/api/v1/user/dashboards/{dashboardId}/dutycalendar",
+		"/api/v1/user/validator/saved",
+		"/api/v1/app/widget",
+	},
+	utils.OAuthScopeNotificationsRead: {
+		"/api/v1/user/notifications",
+		"/api/v1/user/notifications/subscriptions",
+		"/api/v1/user/notifications/subscriptions/{subscriptionId}",
+	},
+}
 
-*/
+// oauthAuthorizeError is an error of an authorization request that is sent to the redirect_uri of the app
+type oauthAuthorizeError struct {
+	Code        string
+	Description string
+}
+
+// parseOAuthAuthorizeRequest validates the scope and PKCE parameters of an authorization request and returns the
+// normalized scope. Third-party apps must request a scope and use PKCE, first-party apps may omit both.
+func parseOAuthAuthorizeRequest(appData *types.OAuthAppData, scope, codeChallenge, codeChallengeMethod string) (string, *oauthAuthorizeError) {
+	scope, err := utils.ParseOAuthScope(scope)
+	if err != nil {
+		return "", &oauthAuthorizeError{utils.InvalidScope, "unknown_scope"}
+	}
+	if scope == "" && !appData.FirstParty {
+		return "", &oauthAuthorizeError{utils.InvalidScope, "missing_scope"}
+	}
+
+	if codeChallenge == "" {
+		if !appData.FirstParty {
+			return "", &oauthAuthorizeError{utils.InvalidRequest, "missing_code_challenge"}
+		}
+		return scope, nil
+	}
+	if codeChallengeMethod != utils.PKCECodeChallengeMethod {
+		return "", &oauthAuthorizeError{utils.InvalidRequest, "unsupported_code_challenge_method"}
+	}
+	// the S256 challenge is the unpadded base64url encoded sha256 hash of the verifier
+	if len(codeChallenge) != 43 {
+		return "", &oauthAuthorizeError{utils.InvalidRequest, "invalid_code_challenge"}
+	}
+	return scope, nil
+}
+
+// oauthScopeAllows returns true if the request is a read request of a route of one of the scopes
+func oauthScopeAllows(r *http.Request, scope string) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return false
+	}
+	path, err := route.GetPathTemplate()
+	if err != nil {
+		return false
+	}
+	for _, s := range strings.Fields(scope) {
+		for _, p := range oauthScopeRoutes[s] {
+			if p == path {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// OAuthScopeMiddleware restricts the scoped access tokens of third-party apps to the routes of their scopes and rejects
+// tokens whose authorization has been revoked. Access tokens without a scope are not restricted.
+// Must be used after the AuthorizedAPIMiddleware.
+func OAuthScopeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims := getAuthClaims(r)
+		if claims == nil || claims.Scope == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		j := json.NewEncoder(w)
+		if !oauthScopeAllows(r, claims.Scope) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			utils.SendOAuthErrorResponse(j, r.URL.String(), utils.InvalidScope, "the scope of the access token does not allow this request")
+			return
+		}
+
+		// access tokens of revoked authorizations are rejected right away instead of when they expire
+		active, err := db.IsUserDeviceActive(claims.UserID, claims.DeviceID)
+		if err != nil {
+			utils.LogError(err, "error checking oauth authorization", 0, map[string]interface{}{"userId": claims.UserID, "deviceId": claims.DeviceID})
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			utils.SendOAuthErrorResponse(j, r.URL.String(), utils.TemporarilyUnavailable, "could not verify authorization")
+			return
+		}
+		if !active {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			utils.SendOAuthErrorResponse(j, r.URL.String(), utils.UnauthorizedClient, "authorization has been revoked")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UserOAuthAppRevokePost revokes the authorization of an app, its refresh token and access tokens become invalid
+func UserOAuthAppRevokePost(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+
+	deviceId, err := strconv.ParseUint(mux.Vars(r)["deviceId"], 10, 64)
+	if err != nil {
+		utils.SetFlash(w, r, authSessionName, "Error: Invalid app.")
+		http.Redirect(w, r, "/user/security", http.StatusSeeOther)
+		return
+	}
+
+	err = db.MobileDeviceDelete(user.UserID, deviceId)
+	if err != nil {
+		utils.LogError(err, "error revoking oauth app", 0, map[string]interface{}{"userId": user.UserID, "deviceId": deviceId})
+		utils.SetFlash(w, r, authSessionName, authInternalServerErrorFlashMsg)
+		http.Redirect(w, r, "/user/security", http.StatusSeeOther)
+		return
+	}
+	auditUserAction(r, user.UserID, types.UserAuditAppRevoked, types.UserAuditDetails{"device_id": deviceId})
+
+	utils.SetFlash(w, r, authSessionName, "The access of the app has been revoked.")
+	http.Redirect(w, r, "/user/security", http.StatusSeeOther)
+}
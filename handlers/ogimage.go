@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/ogimage"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// ogImageBalanceHistoryDays is the number of days of the balance sparkline of the validator preview image
+	ogImageBalanceHistoryDays = 30
+
+	ogImageRateLimitPerSecond = 2
+	ogImageRateLimitBurst     = 10
+)
+
+// ogImageLimiter rate limits the rendering of preview images per ip
+var ogImageLimiter = newClientRateLimiter()
+
+// OgImageValidator renders the preview image of a validator, the dark theme is used if theme=dark is requested
+func OgImageValidator(w http.ResponseWriter, r *http.Request) {
+	if !ogImageLimiter.allowIP(w, r, ogImageRateLimitPerSecond, ogImageRateLimitBurst) {
+		return
+	}
+
+	index, err := strconv.ParseUint(mux.Vars(r)["index"], 10, 64)
+	if err != nil || index > db.MaxSqlInteger {
+		http.Error(w, "Invalid validator index", http.StatusBadRequest)
+		return
+	}
+	dark := r.URL.Query().Get("theme") == "dark"
+	epoch := services.LatestEpoch()
+
+	// the image shows the daily balance history, so it is rendered at most once per day
+	day := utils.TimeToDay(uint64(time.Now().Unix()))
+	cacheKey := fmt.Sprintf("%d:ogImage:validator:%d:%d:%t", utils.Config.Chain.ClConfig.DepositChainID, index, day, dark)
+	img, err := cache.GetOrLoad(cache.KeyTypeOgImage, cacheKey, func() ([]byte, error) {
+		data, err := getOgImageValidatorData(index, epoch)
+		if err != nil || data == nil {
+			return nil, err
+		}
+		return ogimage.ValidatorImage(data, ogimage.GetTheme(dark))
+	})
+	if err != nil {
+		utils.LogError(err, "error rendering validator preview image", 0, map[string]interface{}{"index": index})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if img == nil {
+		http.Error(w, "Validator not found", http.StatusNotFound)
+		return
+	}
+
+	writeOgImage(w, r, img)
+}
+
+func getOgImageValidatorData(index, epoch uint64) (*ogimage.ValidatorImageData, error) {
+	data := &ogimage.ValidatorImageData{Index: index}
+	validator := struct {
+		Status   string `db:"status"`
+		Name     string `db:"name"`
+		Income7d int64  `db:"cl_performance_7d"`
+	}{}
+	err := db.ReaderDb.Get(&validator, `
+		SELECT v.status, COALESCE(n.name, '') AS name, COALESCE(vp.cl_performance_7d, 0) AS cl_performance_7d
+		FROM validators v
+		LEFT JOIN validator_names n ON n.publickey = v.pubkey
+		LEFT JOIN validator_performance vp ON vp.validatorindex = v.validatorindex
+		WHERE v.validatorindex = $1`, index)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validator %v: %w", index, err)
+	}
+	data.Status = validator.Status
+	data.Name = validator.Name
+	data.Income7d = validator.Income7d
+
+	err = db.ReaderDb.Select(&data.BalanceHistory, `
+		SELECT end_balance FROM (
+			SELECT day, COALESCE(end_balance, 0) AS end_balance
+			FROM validator_stats
+			WHERE validatorindex = $1
+			ORDER BY day DESC
+			LIMIT $2
+		) AS history
+		ORDER BY day`, index, ogImageBalanceHistoryDays)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving balance history of validator %v: %w", index, err)
+	}
+
+	balances, err := db.BigtableClient.GetValidatorBalanceHistory([]uint64{index}, epoch, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving balance of validator %v: %w", index, err)
+	}
+	if history := balances[index]; len(history) > 0 {
+		data.Balance = history[0].Balance
+		data.BalanceHistory = append(data.BalanceHistory, data.Balance)
+	}
+	return data, nil
+}
+
+// OgImageValidatorMilestone renders the preview image of a milestone a validator reached, the dark theme is used if
+// theme=dark is requested
+func OgImageValidatorMilestone(w http.ResponseWriter, r *http.Request) {
+	if !ogImageLimiter.allowIP(w, r, ogImageRateLimitPerSecond, ogImageRateLimitBurst) {
+		return
+	}
+
+	vars := mux.Vars(r)
+	index, err := strconv.ParseUint(vars["index"], 10, 64)
+	if err != nil || index > db.MaxSqlInteger {
+		http.Error(w, "Invalid validator index", http.StatusBadRequest)
+		return
+	}
+	desc, ok := types.ValidatorMilestones[vars["milestone"]]
+	if !ok {
+		http.Error(w, "Invalid milestone", http.StatusBadRequest)
+		return
+	}
+	dark := r.URL.Query().Get("theme") == "dark"
+
+	// milestones never change once they are reached, so the image does not depend on the epoch
+	cacheKey := fmt.Sprintf("%d:ogImage:milestone:%d:%s:%t", utils.Config.Chain.ClConfig.DepositChainID, index, vars["milestone"], dark)
+	img, err := cache.GetOrLoad(cache.KeyTypeOgImage, cacheKey, func() ([]byte, error) {
+		milestone, err := db.GetValidatorMilestone(index, vars["milestone"])
+		if err != nil || milestone == nil {
+			return nil, err
+		}
+		name := ""
+		err = db.ReaderDb.Get(&name, `
+			SELECT COALESCE(n.name, '')
+			FROM validators v
+			LEFT JOIN validator_names n ON n.publickey = v.pubkey
+			WHERE v.validatorindex = $1`, index)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving name of validator %v: %w", index, err)
+		}
+		return ogimage.MilestoneImage(&ogimage.MilestoneImageData{
+			Index:       index,
+			Name:        name,
+			Label:       desc.Label,
+			Description: desc.Description,
+			Date:        utils.DayToTime(int64(milestone.Day)).Format("January 2, 2006"),
+		}, ogimage.GetTheme(dark))
+	})
+	if err != nil {
+		utils.LogError(err, "error rendering validator milestone preview image", 0, map[string]interface{}{"index": index, "milestone": vars["milestone"]})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if img == nil {
+		http.Error(w, "Milestone not found", http.StatusNotFound)
+		return
+	}
+
+	writeOgImage(w, r, img)
+}
+
+// OgImageSlot renders the preview image of a slot, the dark theme is used if theme=dark is requested
+func OgImageSlot(w http.ResponseWriter, r *http.Request) {
+	if !ogImageLimiter.allowIP(w, r, ogImageRateLimitPerSecond, ogImageRateLimitBurst) {
+		return
+	}
+
+	slot, err := strconv.ParseUint(mux.Vars(r)["slot"], 10, 64)
+	if err != nil || slot > db.MaxSqlInteger {
+		http.Error(w, "Invalid slot", http.StatusBadRequest)
+		return
+	}
+	dark := r.URL.Query().Get("theme") == "dark"
+
+	// the slots of finalized epochs do not change anymore, so their image is rendered at most once per day
+	version := fmt.Sprintf("day-%d", utils.TimeToDay(uint64(time.Now().Unix())))
+	if utils.EpochOfSlot(slot) > services.LatestFinalizedEpoch() {
+		version = fmt.Sprintf("epoch-%d", services.LatestEpoch())
+	}
+	cacheKey := fmt.Sprintf("%d:ogImage:slot:%d:%s:%t", utils.Config.Chain.ClConfig.DepositChainID, slot, version, dark)
+	img, err := cache.GetOrLoad(cache.KeyTypeOgImage, cacheKey, func() ([]byte, error) {
+		data, err := getOgImageSlotData(slot)
+		if err != nil || data == nil {
+			return nil, err
+		}
+		return ogimage.BlockImage(data, ogimage.GetTheme(dark))
+	})
+	if err != nil {
+		utils.LogError(err, "error rendering slot preview image", 0, map[string]interface{}{"slot": slot})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if img == nil {
+		http.Error(w, "Slot not found", http.StatusNotFound)
+		return
+	}
+
+	writeOgImage(w, r, img)
+}
+
+func getOgImageSlotData(slot uint64) (*ogimage.BlockImageData, error) {
+	slotsPerEpoch := utils.Config.Chain.ClConfig.SlotsPerEpoch
+	data := &ogimage.BlockImageData{Slot: slot, Epoch: utils.EpochOfSlot(slot)}
+
+	blocks := []struct {
+		Slot         uint64 `db:"slot"`
+		Status       string `db:"status"`
+		Proposer     uint64 `db:"proposer"`
+		Transactions uint64 `db:"exec_transactions_count"`
+		Attestations uint64 `db:"attestationscount"`
+	}{}
+	// canonical blocks are sorted first so they win over orphaned blocks of the same slot
+	err := db.ReaderDb.Select(&blocks, `
+		SELECT slot, status, proposer, exec_transactions_count, attestationscount
+		FROM blocks
+		WHERE epoch = $1
+		ORDER BY slot, status = '1' DESC`, data.Epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving blocks of epoch %v: %w", data.Epoch, err)
+	}
+
+	found := false
+	data.EpochStatuses = make([]string, slotsPerEpoch)
+	for i := range data.EpochStatuses {
+		data.EpochStatuses[i] = "scheduled"
+	}
+	for i := len(blocks) - 1; i >= 0; i-- {
+		b := blocks[i]
+		status := "scheduled"
+		switch b.Status {
+		case "1":
+			status = "proposed"
+		case "2":
+			status = "missed"
+		case "3":
+			status = "orphaned"
+		}
+		data.EpochStatuses[b.Slot%slotsPerEpoch] = status
+		if b.Slot == slot {
+			found = true
+			data.Status = status
+			data.Proposer = b.Proposer
+			data.Transactions = b.Transactions
+			data.Attestations = b.Attestations
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+	return data, nil
+}
+
+func writeOgImage(w http.ResponseWriter, r *http.Request, img []byte) {
+	// clients revalidate the images once per epoch
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", utils.Config.Chain.ClConfig.SecondsPerSlot*utils.Config.Chain.ClConfig.SlotsPerEpoch))
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Length", strconv.Itoa(len(img)))
+	_, err := w.Write(img)
+	if err != nil {
+		logger.Errorf("error writing preview image for %v route: %v", r.URL.String(), err)
+	}
+}
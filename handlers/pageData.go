@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/featureflags"
 	"github.com/gobitfly/eth2-beaconchain-explorer/price"
 	"github.com/gobitfly/eth2-beaconchain-explorer/services"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
@@ -53,11 +54,13 @@ func InitPageData(w http.ResponseWriter, r *http.Request, active, path, title st
 		LatestFinalizedEpoch:  services.LatestFinalizedEpoch(),
 		CurrentSlot:           services.LatestSlot(),
 		FinalizationDelay:     services.FinalizationDelay(),
+		FeatureFlags:          featureflags.EnabledFlags(featureFlagSubject(r, user)),
+		Networks:              networkLinks(),
 		Rates:                 services.GetRates(GetCurrency(r)),
 		Mainnet:               utils.Config.Chain.ClConfig.ConfigName == "mainnet" || utils.Config.Chain.ClConfig.ConfigName == "gnosis",
 		DepositContract:       utils.Config.Chain.ClConfig.DepositContractAddress,
 		ChainConfig:           utils.Config.Chain.ClConfig,
-		Lang:                  "en-US",
+		Languages:             utils.Languages,
 		NoAds:                 user.Authenticated && user.Subscription != "",
 		Debug:                 utils.Config.Frontend.Debug,
 		GasNow:                services.LatestGasNowData(),
@@ -91,19 +94,7 @@ func InitPageData(w http.ResponseWriter, r *http.Request, active, path, title st
 		}
 	}
 
-	acceptedLangs := strings.Split(r.Header.Get("Accept-Language"), ",")
-	if len(acceptedLangs) > 0 {
-		if strings.Contains(acceptedLangs[0], "ru") || strings.Contains(acceptedLangs[0], "RU") {
-			data.Lang = "ru-RU"
-		}
-	}
-
-	for _, v := range r.Cookies() {
-		if v.Name == "language" {
-			data.Lang = v.Value
-			break
-		}
-	}
+	data.Lang = GetLanguage(r)
 
 	return data
 }
@@ -164,6 +155,8 @@ func getUserSession(r *http.Request) (*types.User, *utils.CustomSession, error)
 		u.UserGroup = ""
 		return u, session, nil
 	}
+	// sessions created before the locale was stored have no locale
+	u.Locale, _ = session.GetValue("locale").(string)
 	return u, session, nil
 }
 
@@ -181,9 +174,11 @@ func purgeAllSessionsForUser(ctx context.Context, userId uint64) error {
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
-
+	return db.EndUserSessions(userId)
 }
 
 func createMenuItems(active string, isMain bool) []types.MainMenuItem {
@@ -213,6 +208,11 @@ func createMenuItems(active string, isMain bool) []types.MainMenuItem {
 							Path:  "/slots",
 							Icon:  "fa-cube",
 						},
+						{
+							Label: "Sync Committees",
+							Path:  "/sync_committees",
+							Icon:  "fa-sync",
+						},
 					},
 				}, {
 					Links: []types.NavigationLink{
@@ -251,6 +251,11 @@ func createMenuItems(active string, isMain bool) []types.MainMenuItem {
 							Path:  "/validators/slashings",
 							Icon:  "fa-user-slash",
 						},
+						{
+							Label: "Exits",
+							Path:  "/exits",
+							Icon:  "fa-door-open",
+						},
 					},
 				}, {
 					Links: []types.NavigationLink{
@@ -259,6 +264,11 @@ func createMenuItems(active string, isMain bool) []types.MainMenuItem {
 							Path:  "/validators/leaderboard",
 							Icon:  "fa-medal",
 						},
+						{
+							Label: "Block Quality",
+							Path:  "/validators/block-quality",
+							Icon:  "fa-layer-group",
+						},
 						{
 							Label: "Deposit Leaderboard",
 							Path:  "/validators/deposit-leaderboard",
@@ -320,6 +330,11 @@ func createMenuItems(active string, isMain bool) []types.MainMenuItem {
 							Path:  "/pools",
 							Icon:  "fa-chart-pie",
 						},
+						{
+							Label: "Compare Validators",
+							Path:  "/compare",
+							Icon:  "fa-balance-scale",
+						},
 						{
 							Label: "Rocket Pool Stats",
 							Path:  "/pools/rocketpool",
@@ -440,6 +455,11 @@ func createMenuItems(active string, isMain bool) []types.MainMenuItem {
 							Path:  "/ethClients",
 							Icon:  "fa-desktop",
 						},
+						{
+							Label: "Network Upgrades",
+							Path:  "/forks",
+							Icon:  "fa-code-branch",
+						},
 						{
 							Label: "Slot Finder",
 							Path:  "/slots/finder",
@@ -480,6 +500,11 @@ func createMenuItemsGnosis(active string, isMain bool) []types.MainMenuItem {
 							Path:  "/slots",
 							Icon:  "fa-cube",
 						},
+						{
+							Label: "Sync Committees",
+							Path:  "/sync_committees",
+							Icon:  "fa-sync",
+						},
 					},
 				}, {
 					Links: []types.NavigationLink{
@@ -518,6 +543,11 @@ func createMenuItemsGnosis(active string, isMain bool) []types.MainMenuItem {
 							Path:  "/validators/slashings",
 							Icon:  "fa-user-slash",
 						},
+						{
+							Label: "Exits",
+							Path:  "/exits",
+							Icon:  "fa-door-open",
+						},
 					},
 				}, {
 					Links: []types.NavigationLink{
@@ -526,6 +556,11 @@ func createMenuItemsGnosis(active string, isMain bool) []types.MainMenuItem {
 							Path:  "/validators/leaderboard",
 							Icon:  "fa-medal",
 						},
+						{
+							Label: "Block Quality",
+							Path:  "/validators/block-quality",
+							Icon:  "fa-layer-group",
+						},
 						{
 							Label: "Deposit Leaderboard",
 							Path:  "/validators/deposit-leaderboard",
@@ -1,11 +1,10 @@
 package handlers
 
 import (
-	// "github.com/gobitfly/eth2-beaconchain-explorer/db"
-
 	"fmt"
 	"net/http"
 
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
 	"github.com/gobitfly/eth2-beaconchain-explorer/services"
 	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
@@ -70,3 +69,57 @@ func poolsPageData() (*types.PoolsData, error) {
 
 	return data, nil
 }
+
+// PoolsApr compares the fee-adjusted apr of the staking pools and solo stakers over the last days
+func PoolsApr(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "pools/apr.html")
+	var poolsAprTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	days := parseUintWithDefault(r.URL.Query().Get("days"), 31)
+	if days == 0 || days > 365 {
+		days = 31
+	}
+
+	pools, err := db.GetPoolAprComparison(days)
+	if err != nil {
+		logger.Errorf("error retrieving pool apr comparison for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data := InitPageData(w, r, "services", "/pools/apr", "Staking Pools APR Comparison", templateFiles)
+	data.Data = types.PoolsAprPageData{
+		Days:  days,
+		Pools: pools,
+	}
+
+	if handleTemplateError(w, r, "pools.go", "PoolsApr", "", poolsAprTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// PoolsRisk shows the slashing history and the operator concentration of the staking pools and other entities
+func PoolsRisk(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "pools/risk.html")
+	var poolsRiskTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	pools, err := db.GetPoolRisk()
+	if err != nil {
+		logger.Errorf("error retrieving pool risk for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data := InitPageData(w, r, "services", "/pools/risk", "Staking Pools Risk Metrics", templateFiles)
+	data.Data = types.PoolsRiskPageData{
+		Pools: pools,
+	}
+
+	if handleTemplateError(w, r, "pools.go", "PoolsRisk", "", poolsRiskTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/contracts/lido"
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// PoolsLido returns the performance leaderboard of the lido node operators using a go template
+func PoolsLido(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "pools_lido.html")
+	var poolsLidoTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	module := r.URL.Query().Get("module")
+	if module != lido.ModuleCurated && module != lido.ModuleCSM {
+		module = ""
+	}
+
+	operators, err := db.GetLidoOperators(module)
+	if err != nil {
+		logger.Errorf("error retrieving lido operators for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data := InitPageData(w, r, "pools/lido", "/pools/lido", "Lido Operators", templateFiles)
+	data.Data = types.LidoOperatorsPageData{
+		Module:    module,
+		Operators: operators,
+	}
+
+	if handleTemplateError(w, r, "pools_lido.go", "PoolsLido", "", poolsLidoTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
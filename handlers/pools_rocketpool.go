@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"html/template"
@@ -13,6 +14,8 @@ import (
 	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
 )
 
 // PoolsRocketpool returns the rocketpool using a go template
@@ -28,6 +31,43 @@ func PoolsRocketpool(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// PoolsRocketpoolNode returns the dashboard of a rocketpool node operator aggregating all of its minipools using a go template
+func PoolsRocketpoolNode(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "pools_rocketpool_node.html")
+	var poolsRocketpoolNodeTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	address := strings.TrimPrefix(strings.ToLower(mux.Vars(r)["address"]), "0x")
+	if !utils.IsEth1Address(address) {
+		NotFound(w, r)
+		return
+	}
+	addressBytes, err := hex.DecodeString(address)
+	if err != nil {
+		NotFound(w, r)
+		return
+	}
+
+	node, err := db.GetRocketpoolNode(addressBytes)
+	if err != nil {
+		logger.Errorf("error retrieving rocketpool node for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		NotFound(w, r)
+		return
+	}
+
+	data := InitPageData(w, r, "pools/rocketpool", "/pools/rocketpool/node/"+node.Address, fmt.Sprintf("Rocketpool Node %v", node.Address), templateFiles)
+	data.Data = node
+
+	if handleTemplateError(w, r, "pools_rocketpool.go", "PoolsRocketpoolNode", "", poolsRocketpoolNodeTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
 func PoolsRocketpoolDataMinipools(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	q := r.URL.Query()
@@ -317,7 +357,7 @@ func PoolsRocketpoolDataNodes(w http.ResponseWriter, r *http.Request) {
 
 	for _, row := range dbResult {
 		entry := []interface{}{}
-		entry = append(entry, utils.FormatEth1Address(row.Address))
+		entry = append(entry, utils.FormatEth1Address(row.Address)+template.HTML(fmt.Sprintf(` <a href="/pools/rocketpool/node/0x%x" title="Node dashboard"><i class="fas fa-chart-line"></i></a>`, row.Address)))
 		entry = append(entry, row.TimezoneLocation)
 		entry = append(entry, row.RPLStake)
 		entry = append(entry, row.MinRPLStake)
@@ -2,10 +2,13 @@ package handlers
 
 import (
 	"net/http"
+	"time"
 
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
 	"github.com/gobitfly/eth2-beaconchain-explorer/services"
 	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 )
 
 func Relays(w http.ResponseWriter, r *http.Request) {
@@ -27,3 +30,27 @@ func Relays(w http.ResponseWriter, r *http.Request) {
 		return // an error has occurred and was processed
 	}
 }
+
+func RelaysAnomalies(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	templateFiles := append(layoutTemplateFiles, "relays_anomalies.html")
+	var relaysAnomaliesTemplate = templates.GetTemplate(templateFiles...)
+
+	data := InitPageData(w, r, "services", "/relays/anomalies", "Relay Anomalies", templateFiles)
+
+	anomalies, err := db.GetRelayAnomalies(100)
+	if err != nil {
+		utils.LogError(err, "error retrieving relay anomalies", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data.Data = &types.RelayAnomaliesPageData{
+		Anomalies:   anomalies,
+		LastUpdated: time.Now(),
+	}
+
+	if handleTemplateError(w, r, "relays.go", "RelaysAnomalies", "", relaysAnomaliesTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"database/sql"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -11,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
 	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
@@ -20,6 +20,7 @@ import (
 )
 
 const searchValidatorsResultLimit = 300
+const searchEnsResultLimit = 10
 
 var transactionLikeRE = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
 var searchLikeRE = regexp.MustCompile(`^[0-9a-fA-F]{0,96}$`)
@@ -52,6 +53,8 @@ func Search(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/validator/"+search, http.StatusMovedPermanently)
 	} else if utils.IsValidEth1Address(search) {
 		http.Redirect(w, r, "/address/"+search, http.StatusMovedPermanently)
+	} else if resultUrl := searchResultUrl(search); resultUrl != "" {
+		http.Redirect(w, r, resultUrl, http.StatusMovedPermanently)
 	} else {
 		w.Header().Set("Content-Type", "text/html")
 		templateFiles := append(layoutTemplateFiles, "searchnotfound.html")
@@ -64,6 +67,57 @@ func Search(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// searchResultUrl returns the url of the most relevant exact match of the unified search, an empty string if there is none
+func searchResultUrl(search string) string {
+	results, err := services.Search(search, 1)
+	if err != nil {
+		logger.WithError(err).Errorf("error searching for %v", search)
+		return ""
+	}
+	if len(results) == 0 || results[0].Score < 1 {
+		return ""
+	}
+	return results[0].Url
+}
+
+// ApiSearch godoc
+// @Summary Search for validators, blocks, slots, epochs, transactions, addresses, ens names and graffiti
+// @Tags Search
+// @Description Returns the entities matching the query (exact, prefix or substring matches), ordered by relevance. Each result holds its type, a label and the url of its page.
+// @Produce  json
+// @Param  q query string true "The search query, e.g. a validator index, pubkey or name, a block or slot number, a tx hash, an address or ens name or a graffiti"
+// @Param  limit query int false "Maximum amount of results (default: 10, max: 100)"
+// @Success 200 {object} types.ApiResponse{data=[]types.SearchResult}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/search [get]
+func ApiSearch(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if len(q) == 0 {
+		SendBadRequestResponse(w, r.URL.String(), "missing search query q")
+		return
+	}
+
+	limit, err := strconv.ParseUint(r.URL.Query().Get("limit"), 10, 64)
+	if err != nil || limit == 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	results, err := services.Search(q, int(limit))
+	if err != nil {
+		logger.WithError(err).Errorf("error searching for %v", q)
+		SendBadRequestResponse(w, r.URL.String(), "could not perform search")
+		return
+	}
+
+	j := json.NewEncoder(w)
+	SendOKResponse(j, r.URL.String(), []interface{}{results})
+}
+
 // SearchAhead handles responses for the frontend search boxes
 func SearchAhead(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -362,17 +416,10 @@ func SearchAhead(w http.ResponseWriter, r *http.Request) {
 		}
 		result = &res
 	case "ens":
-		if !utils.IsValidEnsDomain(search) {
-			break
-		}
-		data, ensErr := GetEnsDomain(search)
-		if ensErr != nil {
-			if ensErr != sql.ErrNoRows {
-				err = ensErr
-			}
+		if len(search) < 3 {
 			break
 		}
-		result = &data
+		result, err = db.GetEnsNamesByPrefix(strings.ToLower(search), searchEnsResultLimit)
 	default:
 		http.Error(w, "Not found", 404)
 		return
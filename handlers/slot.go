@@ -142,6 +142,7 @@ func Slot(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	data := InitPageData(w, r, "blockchain", fmt.Sprintf("/slot/%v", slotPageData.Slot), fmt.Sprintf("Slot %v", slotOrHash), slotTemplateFiles)
+	data.Meta.Image = fmt.Sprintf("/img/og/slot/%v.png", slotPageData.Slot)
 	data.Data = slotPageData
 
 	if utils.IsApiRequest(r) {
@@ -367,6 +368,13 @@ func GetSlotPageData(blockSlot uint64) (*types.BlockPageData, error) {
 		return nil, fmt.Errorf("error retrieving block proposer slashings data: %v", err)
 	}
 
+	if slotPageData.Status == 1 {
+		slotPageData.AttestationPacking, err = db.GetBlockAttestationPacking(slotPageData.Slot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = db.ReaderDb.Select(&slotPageData.SyncCommittee, "SELECT validatorindex FROM sync_committees WHERE period = $1 ORDER BY committeeindex", utils.SyncPeriodOfEpoch(slotPageData.Epoch))
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving sync-committee of block %v: %v", slotPageData.Slot, err)
@@ -469,6 +477,12 @@ func SlotDepositData(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	credentials := make([][]byte, 0, len(deposits))
+	for _, deposit := range deposits {
+		credentials = append(credentials, deposit.WithdrawalCredentials)
+	}
+	names := resolveEnsNames(credentials...)
+
 	tableData := make([][]interface{}, 0, len(deposits))
 
 	for i, deposit := range deposits {
@@ -476,7 +490,7 @@ func SlotDepositData(w http.ResponseWriter, r *http.Request) {
 			i + 1 + int(start),
 			utils.FormatPublicKey(deposit.PublicKey),
 			utils.FormatBalance(deposit.Amount, currency),
-			utils.FormatWithdawalCredentials(deposit.WithdrawalCredentials, true),
+			utils.FormatWithdrawalCredentialsWithName(deposit.WithdrawalCredentials, ensNameForWithdrawalCredentials(names, deposit.WithdrawalCredentials), true),
 			fmt.Sprintf("0x%v", hex.EncodeToString(deposit.Signature)),
 			utils.FormatHash(deposit.Signature, true),
 		})
@@ -780,12 +794,18 @@ func SlotWithdrawalData(w http.ResponseWriter, r *http.Request) {
 		logger.Errorf("error retrieving withdrawals data for slot %v, err: %v", slot, err)
 	}
 
+	addresses := make([][]byte, 0, len(withdrawals))
+	for _, w := range withdrawals {
+		addresses = append(addresses, w.Address)
+	}
+	names := resolveEnsNames(addresses...)
+
 	tableData := make([][]interface{}, 0, len(withdrawals))
 	for _, w := range withdrawals {
 		tableData = append(tableData, []interface{}{
 			template.HTML(fmt.Sprintf("%v", w.Index)),
 			utils.FormatValidator(w.ValidatorIndex),
-			utils.FormatAddress(w.Address, nil, "", false, false, true),
+			utils.FormatAddress(w.Address, nil, names[string(w.Address)], false, false, true),
 			utils.FormatClCurrency(w.Amount, currency, 6, true, false, false, true),
 		})
 	}
@@ -819,13 +839,19 @@ func SlotBlsChangeData(w http.ResponseWriter, r *http.Request) {
 		logger.Errorf("error retrieving blsChange data for slot %v, err: %v", slot, err)
 	}
 
+	addresses := make([][]byte, 0, len(blsChange))
+	for _, c := range blsChange {
+		addresses = append(addresses, c.Address)
+	}
+	names := resolveEnsNames(addresses...)
+
 	tableData := make([][]interface{}, 0, len(blsChange))
 	for _, c := range blsChange {
 		tableData = append(tableData, []interface{}{
 			utils.FormatValidator(c.Validatorindex),
 			utils.FormatHashWithCopy(c.Signature),
 			utils.FormatHashWithCopy(c.BlsPubkey),
-			utils.FormatAddress(c.Address, nil, "", false, false, true),
+			utils.FormatAddress(c.Address, nil, names[string(c.Address)], false, false, true),
 		})
 	}
 
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"html/template"
 	"net/http"
@@ -35,52 +34,29 @@ func Slots(w http.ResponseWriter, r *http.Request) {
 
 // SlotsData will return information about slots
 func SlotsData(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-
-	q := r.URL.Query()
-
-	search := q.Get("search[value]")
+	// slots are ordered by their number and paged by slot range, so the ordering of the table is ignored
+	dataQuery, ok := handleDataTableQuery(w, r, DataTableColumns{})
+	if !ok {
+		return
+	}
 
-	if q.Get("q") != "" {
-		search = q.Get("q")
+	search := dataQuery.Search
+	if q := r.URL.Query().Get("q"); q != "" {
+		search = q
 	}
 
 	search = strings.Replace(search, "0x", "", -1)
 
-	searchForEmpty := (len(search) == 0 && q.Get("columns[11][search][value]") == "#showonlyemptygraffiti")
-
-	draw, err := strconv.ParseUint(q.Get("draw"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables draw parameter from string to int: %v", err)
-		http.Error(w, "Error: Missing or invalid parameter draw", http.StatusBadRequest)
-		return
-	}
-	start, err := strconv.ParseUint(q.Get("start"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables start parameter from string to int: %v", err)
-		http.Error(w, "Error: Missing or invalid parameter start", http.StatusBadRequest)
-		return
-	}
-	length, err := strconv.ParseUint(q.Get("length"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables length parameter from string to int: %v", err)
-		http.Error(w, "Error: Missing or invalid parameter length", http.StatusBadRequest)
-		return
-	}
+	searchForEmpty := (len(search) == 0 && dataQuery.ColumnSearch(11) == "#showonlyemptygraffiti")
 
-	tableData, err := GetSlotsTableData(draw, start, length, search, searchForEmpty)
+	tableData, err := GetSlotsTableData(dataQuery.Draw, dataQuery.Start, dataQuery.Length, search, searchForEmpty)
 	if err != nil {
 		logger.Errorf("error rendering blocks table data: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	err = json.NewEncoder(w).Encode(tableData)
-	if err != nil {
-		logger.Errorf("error enconding json response for %v route: %v", r.URL.String(), err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
+	writeDataTableResponse(w, r, tableData)
 }
 
 func GetSlotsTableData(draw, start, length uint64, search string, searchForEmpty bool) (*types.DataTableResponse, error) {
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// stakingFlowsHistoryDays is the amount of days shown in the staking flow charts
+const stakingFlowsHistoryDays = 180
+
+// StakingFlows renders the daily deposit inflow, full withdrawal outflow and the resulting net staking flow
+func StakingFlows(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	templateFiles := append(layoutTemplateFiles, "staking_flows.html")
+	var stakingFlowsTemplate = templates.GetTemplate(templateFiles...)
+
+	data := InitPageData(w, r, "stats", "/charts", "Staking Flows Chart", templateFiles)
+	data.Meta.Path = "/charts/staking-flows"
+
+	var fromDay uint64
+	latestDay := utils.DayOfSlot(services.LatestSlot())
+	if latestDay > stakingFlowsHistoryDays {
+		fromDay = latestDay - stakingFlowsHistoryDays
+	}
+
+	history, err := db.GetStakingFlowsHistory(fromDay)
+	if err != nil {
+		utils.LogError(err, "error retrieving staking flows history", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	divisor := float64(utils.Config.Frontend.ClCurrencyDivisor)
+	depositData := make([][]float64, 0, len(history.Days))
+	withdrawalData := make([][]float64, 0, len(history.Days))
+	partialData := make([][]float64, 0, len(history.Days))
+	netData := make([][]float64, 0, len(history.Days))
+	for _, d := range history.Days {
+		ts := float64(utils.DayToTime(int64(d.Day)).Unix() * 1000)
+		depositData = append(depositData, []float64{ts, float64(d.DepositAmount) / divisor})
+		withdrawalData = append(withdrawalData, []float64{ts, -float64(d.FullWithdrawalAmount) / divisor})
+		partialData = append(partialData, []float64{ts, -float64(d.PartialWithdrawalAmount) / divisor})
+		netData = append(netData, []float64{ts, float64(d.NetFlow) / divisor})
+	}
+
+	// the entity table sums up the flows of the whole shown period
+	entities := map[string]*types.StakingFlowsEntityDailyStats{}
+	for _, e := range history.Entities {
+		if _, exists := entities[e.Entity]; !exists {
+			entities[e.Entity] = &types.StakingFlowsEntityDailyStats{Day: fromDay, Entity: e.Entity}
+		}
+		entities[e.Entity].DepositCount += e.DepositCount
+		entities[e.Entity].DepositAmount += e.DepositAmount
+		entities[e.Entity].FullWithdrawalCount += e.FullWithdrawalCount
+		entities[e.Entity].FullWithdrawalAmount += e.FullWithdrawalAmount
+		entities[e.Entity].NetFlow += e.NetFlow
+	}
+
+	pageData := &types.StakingFlowsPageData{
+		Days: latestDay - fromDay,
+		FlowSeries: []*types.GenericChartDataSeries{
+			{Name: "Deposits", Data: depositData, Type: "column", Stack: "flows"},
+			{Name: "Full Withdrawals", Data: withdrawalData, Type: "column", Stack: "flows"},
+			{Name: "Partial Withdrawals", Data: partialData, Type: "column", Stack: "rewards"},
+		},
+		NetSeries: []*types.GenericChartDataSeries{
+			{Name: "Net Staking Flow", Data: netData, Type: "column"},
+		},
+		Entities:    make([]*types.StakingFlowsEntityDailyStats, 0, len(entities)),
+		LastUpdated: time.Now(),
+	}
+	for _, e := range entities {
+		pageData.Entities = append(pageData.Entities, e)
+	}
+	sort.Slice(pageData.Entities, func(i, j int) bool {
+		return pageData.Entities[i].DepositAmount > pageData.Entities[j].DepositAmount
+	})
+
+	data.Data = pageData
+
+	if handleTemplateError(w, r, "staking_flows.go", "StakingFlows", "", stakingFlowsTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
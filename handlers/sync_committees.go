@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// SyncCommittees returns the current and past sync committees and the sync committee history of a validator using a go template
+func SyncCommittees(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "sync_committees.html")
+	var syncCommitteesTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	data := InitPageData(w, r, "blockchain", "/sync_committees", "Sync Committees", templateFiles)
+
+	periods, err := db.GetSyncCommitteePeriods()
+	if err != nil {
+		utils.LogError(err, "error getting sync committee periods", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	pageData := &types.SyncCommitteesPageData{
+		Periods:       periods,
+		CurrentPeriod: utils.SyncPeriodOfEpoch(services.LatestEpoch()),
+	}
+
+	if validator := r.URL.Query().Get("validator"); validator != "" {
+		validatorIndex, err := strconv.ParseUint(validator, 10, 64)
+		if err != nil {
+			http.Error(w, "Error: Invalid parameter validator", http.StatusBadRequest)
+			return
+		}
+		history, err := getValidatorSyncCommitteeHistory(validatorIndex)
+		if err != nil {
+			utils.LogError(err, "error getting sync committee history of validator", 0, map[string]interface{}{"validator": validatorIndex})
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		pageData.ShowValidator = true
+		pageData.Validator = validatorIndex
+		pageData.ValidatorHistory = history
+		for _, period := range history {
+			pageData.ValidatorRewards += period.Rewards
+		}
+	}
+
+	data.Data = pageData
+
+	if handleTemplateError(w, r, "sync_committees.go", "SyncCommittees", "", syncCommitteesTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// SyncCommittee returns the members of a sync committee together with their participation and rewards using a go template
+func SyncCommittee(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "sync_committee.html")
+	var syncCommitteeTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	period, err := strconv.ParseUint(mux.Vars(r)["period"], 10, 64)
+	if err != nil {
+		NotFound(w, r)
+		return
+	}
+
+	pageData, err := getSyncCommitteePageData(period)
+	if err != nil {
+		utils.LogError(err, "error getting sync committee", 0, map[string]interface{}{"period": period})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if pageData == nil {
+		NotFound(w, r)
+		return
+	}
+
+	data := InitPageData(w, r, "blockchain", fmt.Sprintf("/sync_committee/%v", period), fmt.Sprintf("Sync Committee %v", period), templateFiles)
+	data.Data = pageData
+
+	if handleTemplateError(w, r, "sync_committees.go", "SyncCommittee", "", syncCommitteeTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// getSyncCommitteePageData returns the members of the sync committee of the given period, nil is returned if the
+// committee is not known yet
+func getSyncCommitteePageData(period uint64) (*types.SyncCommitteePageData, error) {
+	committee, err := db.GetSyncCommitteePeriod(period)
+	if err != nil || committee == nil {
+		return nil, err
+	}
+
+	members, err := db.GetSyncCommitteeMembers(period)
+	if err != nil {
+		return nil, err
+	}
+
+	pageData := &types.SyncCommitteePageData{
+		SyncCommitteePeriodInfo: *committee,
+		CurrentPeriod:           utils.SyncPeriodOfEpoch(services.LatestEpoch()),
+		Members:                 members,
+	}
+
+	startEpoch, endEpoch, ok := syncCommitteeDutyEpochs(committee)
+	if !ok {
+		return pageData, nil
+	}
+
+	validators := make([]uint64, 0, len(members))
+	for _, member := range members {
+		validators = append(validators, member.ValidatorIndex)
+	}
+	performance, err := db.GetSyncCommitteePerformance(validators, startEpoch, endEpoch)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, member := range members {
+		p := performance[member.ValidatorIndex]
+		if p == nil {
+			continue
+		}
+		member.SyncCommitteePerformance = *p
+		pageData.Performance.ParticipatedSync += p.ParticipatedSync
+		pageData.Performance.MissedSync += p.MissedSync
+		pageData.Performance.OrphanedSync += p.OrphanedSync
+		pageData.Performance.Rewards += p.Rewards
+	}
+
+	return pageData, nil
+}
+
+// getValidatorSyncCommitteeHistory returns the sync committee periods the validator was part of together with its
+// participation and rewards in each period, the most recent period first
+func getValidatorSyncCommitteeHistory(validatorIndex uint64) ([]*types.ValidatorSyncCommitteePeriod, error) {
+	periods, err := db.GetValidatorSyncCommitteePeriods(validatorIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*types.ValidatorSyncCommitteePeriod, 0, len(periods))
+	for _, period := range periods {
+		entry := &types.ValidatorSyncCommitteePeriod{SyncCommitteePeriodInfo: *period}
+		history = append(history, entry)
+
+		startEpoch, endEpoch, ok := syncCommitteeDutyEpochs(period)
+		if !ok {
+			continue
+		}
+		performance, err := db.GetSyncCommitteePerformance([]uint64{validatorIndex}, startEpoch, endEpoch)
+		if err != nil {
+			return nil, err
+		}
+		if p := performance[validatorIndex]; p != nil {
+			entry.SyncCommitteePerformance = *p
+		}
+	}
+
+	return history, nil
+}
+
+// syncCommitteeDutyEpochs returns the epochs of the period in which the committee already performed its duties,
+// ok is false if the period lies in the future
+func syncCommitteeDutyEpochs(period *types.SyncCommitteePeriodInfo) (startEpoch, endEpoch uint64, ok bool) {
+	latestEpoch := services.LatestEpoch()
+	if period.StartEpoch > latestEpoch {
+		return 0, 0, false
+	}
+	endEpoch = period.EndEpoch
+	if endEpoch > latestEpoch {
+		endEpoch = latestEpoch
+	}
+	return period.StartEpoch, endEpoch, true
+}
@@ -88,6 +88,11 @@ func UserSettings(w http.ResponseWriter, r *http.Request) {
 		logger.Errorf("Error retrieving stats sharing setting: %v %v", user.UserID, err)
 		statsSharing = false
 	}
+	locale, err := db.GetUserLocale(user.UserID)
+	if err != nil {
+		logger.Errorf("Error retrieving locale: %v %v", user.UserID, err)
+		locale = mail.DefaultLocale
+	}
 
 	rl, err := ratelimit.DBGetUserApiRateLimit(int64(user.UserID))
 	if err != nil {
@@ -125,6 +130,8 @@ func UserSettings(w http.ResponseWriter, r *http.Request) {
 	userSettingsData.Emerald = &utils.Config.Frontend.Stripe.Emerald
 	userSettingsData.Diamond = &utils.Config.Frontend.Stripe.Diamond
 	userSettingsData.ShareMonitoringData = statsSharing
+	userSettingsData.Locale = locale
+	userSettingsData.Locales = mail.Locales
 	userSettingsData.Flashes = utils.GetFlashes(w, r, authSessionName)
 	userSettingsData.CsrfField = csrf.TemplateField(r)
 
@@ -150,12 +157,33 @@ func GenerateAPIKey(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
 	user := getUser(r)
 
-	err := db.CreateAPIKey(user.UserID)
+	required, err := twoFactorRequired(user.UserID)
+	if err != nil {
+		logger.WithError(err).Error("Could not check two-factor requirement for user")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if required {
+		hasTwoFactor, err := db.HasUserWebAuthnCredentials(user.UserID)
+		if err != nil {
+			logger.WithError(err).Error("Could not check two-factor authentication for user")
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if !hasTwoFactor {
+			utils.SetFlash(w, r, authSessionName, "Error: Accounts with a paid API plan must set up two-factor authentication before creating an API key.")
+			http.Redirect(w, r, "/user/security", http.StatusSeeOther)
+			return
+		}
+	}
+
+	err = db.CreateAPIKey(user.UserID)
 	if err != nil {
 		logger.WithError(err).Error("Could not create API key for user")
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+	auditUserAction(r, user.UserID, types.UserAuditApiKeyCreated, nil)
 
 	http.Redirect(w, r, r.Referer(), http.StatusSeeOther)
 }
@@ -186,6 +214,10 @@ func UserAuthorizeConfirm(w http.ResponseWriter, r *http.Request) {
 
 	if !user.Authenticated {
 		if redirectURI != "" {
+			// the login only passes on redirect_uri and state, keep the whole request (scope, pkce) for after the login
+			session.SetValue("oauth_authorize_query", q.Encode())
+			session.Save(r, w)
+
 			var stateParam = ""
 			if state != "" {
 				stateParam = "&state=" + state
@@ -211,7 +243,22 @@ func UserAuthorizeConfirm(w http.ResponseWriter, r *http.Request) {
 		utils.SetFlash(w, r, authSessionName, "Error: App not found. Is your redirect_uri correct and registered?")
 		session.Save(r, w)
 	} else {
+		scope, authorizeErr := parseOAuthAuthorizeRequest(appData, q.Get("scope"), q.Get("code_challenge"), q.Get("code_challenge_method"))
+		if authorizeErr != nil {
+			var stateParam = ""
+			if state != "" {
+				stateParam = "&state=" + state
+			}
+			callback := appData.RedirectURI + "?error=" + authorizeErr.Code + "&error_description=" + authorizeErr.Description + stateParam
+			http.Redirect(w, r, callback, http.StatusSeeOther)
+			return
+		}
 		authorizeData.AppData = appData
+		authorizeData.Scope = scope
+		for _, s := range strings.Fields(scope) {
+			authorizeData.ScopeDescriptions = append(authorizeData.ScopeDescriptions, utils.OAuthScopes[s])
+		}
+		authorizeData.CodeChallenge = q.Get("code_challenge")
 	}
 
 	authorizeData.State = state
@@ -482,7 +529,14 @@ func UserNotificationsCenter(w http.ResponseWriter, r *http.Request) {
 			sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorGotSlashedEventName) ||
 			sub.EventName == utils.GetNetwork()+":"+string(types.SyncCommitteeSoon) ||
 			sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorMissedAttestationEventName) ||
-			sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorReceivedWithdrawalEventName) {
+			sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorReceivedWithdrawalEventName) ||
+			sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorWithdrawalCredentialsChangedEventName) ||
+			sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorVoluntaryExitEventName) ||
+			sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorWithdrawableEventName) ||
+			sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorFeeRecipientMismatchEventName) ||
+			sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorRelayRegistrationMissingEventName) ||
+			sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorMilestoneEventName) ||
+			sub.EventName == utils.GetNetwork()+":"+string(types.ValidatorDepositWarningEventName) {
 			typeCount.Validator++
 		} else if sub.EventName == string(types.MonitoringMachineOfflineEventName) ||
 			sub.EventName == string(types.MonitoringMachineDiskAlmostFullEventName) ||
@@ -496,7 +550,8 @@ func UserNotificationsCenter(w http.ResponseWriter, r *http.Request) {
 			sub.EventName == utils.GetNetwork()+":"+string(types.NetworkValidatorActivationQueueNotFullEventName) ||
 			sub.EventName == utils.GetNetwork()+":"+string(types.NetworkValidatorExitQueueFullEventName) ||
 			sub.EventName == utils.GetNetwork()+":"+string(types.NetworkValidatorExitQueueNotFullEventName) ||
-			sub.EventName == utils.GetNetwork()+":"+string(types.NetworkLivenessIncreasedEventName) {
+			sub.EventName == utils.GetNetwork()+":"+string(types.NetworkLivenessIncreasedEventName) ||
+			sub.EventName == utils.GetNetwork()+":"+string(types.NetworkIncidentEventName) {
 			typeCount.Network++
 		} else if sub.EventName == utils.GetNetwork()+":"+string(types.TaxReportEventName) {
 			typeCount.Income++
@@ -941,6 +996,14 @@ func UserAuthorizeConfirmPost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	codeChallenge := r.FormValue("code_challenge")
+	scope, authorizeErr := parseOAuthAuthorizeRequest(appData, r.FormValue("scope"), codeChallenge, r.FormValue("code_challenge_method"))
+	if authorizeErr != nil {
+		callback := appData.RedirectURI + "?error=" + authorizeErr.Code + "&error_description=" + authorizeErr.Description + stateAppend
+		http.Redirect(w, r, callback, http.StatusSeeOther)
+		return
+	}
+
 	if user.Authenticated {
 		codeBytes, err1 := utils.GenerateRandomBytesSecure(32)
 		if err1 != nil {
@@ -954,13 +1017,14 @@ func UserAuthorizeConfirmPost(w http.ResponseWriter, r *http.Request) {
 		codeHashed := utils.HashAndEncode(code) // save hashed code in db
 		clientID := session.GetValue("client_id").(string)
 
-		err2 := db.AddAuthorizeCode(user.UserID, codeHashed, clientID, appData.ID)
+		err2 := db.AddAuthorizeCode(user.UserID, codeHashed, clientID, appData.ID, scope, codeChallenge)
 		if err2 != nil {
 			logger.Errorf("error adding authorization code for user: %v %v", user.UserID, err2)
 			callback := appData.RedirectURI + "?error=server_error&error_description=err_db_storefail" + stateAppend
 			http.Redirect(w, r, callback, http.StatusSeeOther)
 			return
 		}
+		auditUserAction(r, user.UserID, types.UserAuditAppAuthorized, types.UserAuditDetails{"app": appData.AppName, "scope": scope})
 
 		callbackTemplate := appData.RedirectURI + "?code="
 
@@ -984,7 +1048,19 @@ func UserDeletePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if user.Authenticated {
-		err := db.DeleteUserById(user.UserID)
+		// an organization can not exist without its owner
+		organization, err := db.GetUserOrganization(user.UserID)
+		if err == nil && organization != nil && organization.Role == types.OrganizationRoleOwner {
+			err = db.DeleteOrganization(organization.ID)
+		}
+		if err != nil {
+			utils.LogError(err, "error deleting organization of user", 0, map[string]interface{}{"userID": user.UserID})
+			utils.SetFlash(w, r, authSessionName, "Error: Could not delete user.")
+			http.Redirect(w, r, "/user/settings", http.StatusSeeOther)
+			return
+		}
+
+		err = db.DeleteUserById(user.UserID)
 		if err != nil {
 			logger.Errorf("error deleting user by email for user: %v %v", user.UserID, err)
 			http.Redirect(w, r, "/user/settings", http.StatusSeeOther)
@@ -1027,6 +1103,38 @@ func UserUpdateFlagsPost(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/user/settings#app", http.StatusOK)
 }
 
+// UserUpdateLocalePost sets the language of the user, it is used for the emails and for the pages unless another
+// language has been chosen in the browser
+func UserUpdateLocalePost(w http.ResponseWriter, r *http.Request) {
+	user, session, err := getUserSession(r)
+	if err != nil {
+		logger.Errorf("error retrieving session: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	locale := FormValueOrJSON(r, "locale")
+	if mail.NormalizeLocale(locale) != locale {
+		utils.SetFlash(w, r, authSessionName, "Error: Unsupported language.")
+		http.Redirect(w, r, "/user/settings", http.StatusSeeOther)
+		return
+	}
+
+	err = db.SetUserLocale(user.UserID, locale)
+	if err != nil {
+		utils.LogError(err, "error setting user locale", 0, map[string]interface{}{"userID": user.UserID})
+		utils.SetFlash(w, r, authSessionName, authInternalServerErrorFlashMsg)
+		http.Redirect(w, r, "/user/settings", http.StatusSeeOther)
+		return
+	}
+
+	session.SetValue("locale", locale)
+	session.Save(r, w)
+
+	utils.SetFlash(w, r, authSessionName, "Your language has been updated.")
+	http.Redirect(w, r, "/user/settings", http.StatusSeeOther)
+}
+
 func UserUpdatePasswordPost(w http.ResponseWriter, r *http.Request) {
 	user, session, err := getUserSession(r)
 	if err != nil {
@@ -1114,6 +1222,8 @@ func UserUpdatePasswordPost(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/confirmation", http.StatusSeeOther)
 		return
 	}
+	trackUserSession(r, session, user.UserID)
+	auditUserAction(r, user.UserID, types.UserAuditPasswordChanged, nil)
 
 	session.AddFlash("Password Updated Successfully ✔️")
 	session.Save(r, w)
@@ -1339,6 +1449,8 @@ func UserConfirmUpdateEmail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditUserAction(r, uint64(user.ID), types.UserAuditEmailChanged, nil)
+
 	err = purgeAllSessionsForUser(r.Context(), uint64(user.ID))
 	if err != nil {
 		utils.LogError(err, "error purging sessions for user", 0, map[string]interface{}{"userID": user.ID})
@@ -1775,6 +1887,10 @@ func internUserNotificationsSubscribe(event, filter string, threshold float64, w
 
 	errFields["event_name"] = eventName
 
+	if types.IsEthAddressNotification(eventName) {
+		filter = strings.ToLower(filter)
+	}
+
 	valid, err := isValidSubscriptionFilter(user.UserID, eventName, filter)
 	if err != nil {
 		utils.LogError(err, "error validating filter", 0, errFields)
@@ -1783,7 +1899,7 @@ func internUserNotificationsSubscribe(event, filter string, threshold float64, w
 	}
 
 	if !valid {
-		ErrorOrJSONResponse(w, r, "Invalid filter, only pubkey, client or machine name is valid.", http.StatusBadRequest)
+		ErrorOrJSONResponse(w, r, "Invalid filter, only pubkey, address, client or machine name is valid.", http.StatusBadRequest)
 		return false
 	}
 
@@ -1797,16 +1913,7 @@ func internUserNotificationsSubscribe(event, filter string, threshold float64, w
 		Network:        utils.GetNetwork(),
 	}
 	if !userPremium.NotificationThresholds {
-		if eventName == types.MonitoringMachineDiskAlmostFullEventName {
-			threshold = 0.1
-		} else if eventName == types.MonitoringMachineCpuLoadEventName {
-			threshold = 0.6
-		} else if eventName == types.MonitoringMachineMemoryUsageEventName {
-			threshold = 0.8
-		} else if eventName == types.ValidatorIsOfflineEventName {
-			threshold = 3
-		}
-		// rocketpool thresholds are free
+		threshold = freeSubscriptionThreshold(eventName, threshold)
 	}
 
 	filterLen := len(filter)
@@ -1895,6 +2002,7 @@ func internUserNotificationsSubscribe(event, filter string, threshold float64, w
 
 	}
 
+	auditUserAction(r, user.UserID, types.UserAuditNotificationsChanged, types.UserAuditDetails{"subscribed": eventName, "filter": filter, "threshold": threshold})
 	return true
 }
 
@@ -1979,7 +2087,7 @@ func internUserNotificationsUnsubscribe(event, filter string, w http.ResponseWri
 	}
 
 	if !valid {
-		ErrorOrJSONResponse(w, r, "Invalid filter, only pubkey, client or machine name is valid.", http.StatusBadRequest)
+		ErrorOrJSONResponse(w, r, "Invalid filter, only pubkey, address, client or machine name is valid.", http.StatusBadRequest)
 		return false
 	}
 
@@ -2041,6 +2149,7 @@ func internUserNotificationsUnsubscribe(event, filter string, w http.ResponseWri
 
 	}
 
+	auditUserAction(r, user.UserID, types.UserAuditNotificationsChanged, types.UserAuditDetails{"unsubscribed": eventName, "filter": filter})
 	return true
 }
 
@@ -2060,6 +2169,10 @@ func UserNotificationsUnsubscribe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if types.IsEthAddressNotification(eventName) {
+		filter = strings.ToLower(filter)
+	}
+
 	valid, err := isValidSubscriptionFilter(user.UserID, eventName, filter)
 	if err != nil {
 		errMsg := fmt.Errorf("error validating filter")
@@ -2073,7 +2186,7 @@ func UserNotificationsUnsubscribe(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if !valid {
-		ErrorOrJSONResponse(w, r, "Invalid filter, only pubkey, client or machine name is valid.", http.StatusBadRequest)
+		ErrorOrJSONResponse(w, r, "Invalid filter, only pubkey, address, client or machine name is valid.", http.StatusBadRequest)
 		return
 	}
 
@@ -2126,6 +2239,22 @@ func UserNotificationsUnsubscribe(w http.ResponseWriter, r *http.Request) {
 	OKResponse(w, r)
 }
 
+// freeSubscriptionThreshold returns the threshold of a subscription of a user without custom notification thresholds
+func freeSubscriptionThreshold(eventName types.EventName, threshold float64) float64 {
+	switch eventName {
+	case types.MonitoringMachineDiskAlmostFullEventName:
+		return 0.1
+	case types.MonitoringMachineCpuLoadEventName:
+		return 0.6
+	case types.MonitoringMachineMemoryUsageEventName:
+		return 0.8
+	case types.ValidatorIsOfflineEventName:
+		return 3
+	}
+	// rocketpool thresholds are free
+	return threshold
+}
+
 func isValidSubscriptionFilter(userID uint64, eventName types.EventName, filter string) (bool, error) {
 	ethClients := []string{"geth", "nethermind", "besu", "erigon", "teku", "prysm", "nimbus", "lighthouse", "lodestar", "rocketpool", "mev-boost"}
 
@@ -2144,6 +2273,27 @@ func isValidSubscriptionFilter(userID uint64, eventName types.EventName, filter
 		isClient = true
 	}
 
+	// execution layer address events always require the watched address as filter
+	if types.IsEthAddressNotification(eventName) {
+		return utils.IsValidEth1Address(filter), nil
+	}
+
+	// cluster offline subscriptions cover the watchlist or, if the filter is set, a dashboard of the user
+	if eventName == types.ValidatorsClusterOfflineEventName {
+		if filter == "" {
+			return true, nil
+		}
+		dashboardId, err := strconv.ParseUint(filter, 10, 64)
+		if err != nil {
+			return false, nil
+		}
+		ownerId, _, err := db.GetValidatorDashboardOwner(dashboardId)
+		if err != nil {
+			return false, errors.Wrap(err, "can not get dashboard owner for validation")
+		}
+		return ownerId == userID, nil
+	}
+
 	isValidMachine := false
 	if types.IsMachineNotification(eventName) {
 		machines, err := db.BigtableClient.GetMachineMetricsMachineNames(userID)
@@ -2499,6 +2649,41 @@ func NotificationWebhookPage(w http.ResponseWriter, r *http.Request) {
 			EventName:  types.ValidatorReceivedWithdrawalEventName,
 			Active:     utils.ElementExists(wh.EventNames, string(types.ValidatorReceivedWithdrawalEventName)),
 		})
+		events = append(events, types.EventNameCheckbox{
+			EventLabel: "Withdrawal Credentials Changed",
+			EventName:  types.ValidatorWithdrawalCredentialsChangedEventName,
+			Active:     utils.ElementExists(wh.EventNames, string(types.ValidatorWithdrawalCredentialsChangedEventName)),
+		})
+		events = append(events, types.EventNameCheckbox{
+			EventLabel: "Voluntary Exit",
+			EventName:  types.ValidatorVoluntaryExitEventName,
+			Active:     utils.ElementExists(wh.EventNames, string(types.ValidatorVoluntaryExitEventName)),
+		})
+		events = append(events, types.EventNameCheckbox{
+			EventLabel: "Withdrawable",
+			EventName:  types.ValidatorWithdrawableEventName,
+			Active:     utils.ElementExists(wh.EventNames, string(types.ValidatorWithdrawableEventName)),
+		})
+		events = append(events, types.EventNameCheckbox{
+			EventLabel: "Unexpected Fee Recipient",
+			EventName:  types.ValidatorFeeRecipientMismatchEventName,
+			Active:     utils.ElementExists(wh.EventNames, string(types.ValidatorFeeRecipientMismatchEventName)),
+		})
+		events = append(events, types.EventNameCheckbox{
+			EventLabel: "Relay Registration Missing",
+			EventName:  types.ValidatorRelayRegistrationMissingEventName,
+			Active:     utils.ElementExists(wh.EventNames, string(types.ValidatorRelayRegistrationMissingEventName)),
+		})
+		events = append(events, types.EventNameCheckbox{
+			EventLabel: "Milestone Reached",
+			EventName:  types.ValidatorMilestoneEventName,
+			Active:     utils.ElementExists(wh.EventNames, string(types.ValidatorMilestoneEventName)),
+		})
+		events = append(events, types.EventNameCheckbox{
+			EventLabel: "Deposit Warning",
+			EventName:  types.ValidatorDepositWarningEventName,
+			Active:     utils.ElementExists(wh.EventNames, string(types.ValidatorDepositWarningEventName)),
+		})
 		events = append(events, types.EventNameCheckbox{
 			EventLabel: "Slashed",
 			EventName:  types.ValidatorGotSlashedEventName,
@@ -2599,6 +2784,34 @@ func NotificationWebhookPage(w http.ResponseWriter, r *http.Request) {
 		EventLabel: "Withdrawal",
 		EventName:  types.ValidatorReceivedWithdrawalEventName,
 	})
+	events = append(events, types.EventNameCheckbox{
+		EventLabel: "Withdrawal Credentials Changed",
+		EventName:  types.ValidatorWithdrawalCredentialsChangedEventName,
+	})
+	events = append(events, types.EventNameCheckbox{
+		EventLabel: "Voluntary Exit",
+		EventName:  types.ValidatorVoluntaryExitEventName,
+	})
+	events = append(events, types.EventNameCheckbox{
+		EventLabel: "Withdrawable",
+		EventName:  types.ValidatorWithdrawableEventName,
+	})
+	events = append(events, types.EventNameCheckbox{
+		EventLabel: "Unexpected Fee Recipient",
+		EventName:  types.ValidatorFeeRecipientMismatchEventName,
+	})
+	events = append(events, types.EventNameCheckbox{
+		EventLabel: "Relay Registration Missing",
+		EventName:  types.ValidatorRelayRegistrationMissingEventName,
+	})
+	events = append(events, types.EventNameCheckbox{
+		EventLabel: "Milestone Reached",
+		EventName:  types.ValidatorMilestoneEventName,
+	})
+	events = append(events, types.EventNameCheckbox{
+		EventLabel: "Deposit Warning",
+		EventName:  types.ValidatorDepositWarningEventName,
+	})
 	events = append(events, types.EventNameCheckbox{
 		EventLabel: "Got Slashed",
 		EventName:  types.ValidatorGotSlashedEventName,
@@ -2664,6 +2877,13 @@ func UsersAddWebhook(w http.ResponseWriter, r *http.Request) {
 	validatorProposalMissed := r.FormValue(string(types.ValidatorMissedProposalEventName)) == "on"
 	validatorProposalSubmitted := r.FormValue(string(types.ValidatorExecutedProposalEventName)) == "on"
 	validatorReceivedWithdrawal := r.FormValue(string(types.ValidatorReceivedWithdrawalEventName)) == "on"
+	validatorWithdrawalCredentialsChanged := r.FormValue(string(types.ValidatorWithdrawalCredentialsChangedEventName)) == "on"
+	validatorVoluntaryExit := r.FormValue(string(types.ValidatorVoluntaryExitEventName)) == "on"
+	validatorWithdrawable := r.FormValue(string(types.ValidatorWithdrawableEventName)) == "on"
+	validatorFeeRecipientMismatch := r.FormValue(string(types.ValidatorFeeRecipientMismatchEventName)) == "on"
+	validatorRelayRegistrationMissing := r.FormValue(string(types.ValidatorRelayRegistrationMissingEventName)) == "on"
+	validatorMilestone := r.FormValue(string(types.ValidatorMilestoneEventName)) == "on"
+	validatorDepositWarning := r.FormValue(string(types.ValidatorDepositWarningEventName)) == "on"
 	validatorGotSlashed := r.FormValue(string(types.ValidatorGotSlashedEventName)) == "on"
 	validatorSyncCommiteeSoon := r.FormValue(string(types.SyncCommitteeSoon)) == "on"
 	validatorAttestationMissed := r.FormValue(string(types.ValidatorMissedAttestationEventName)) == "on"
@@ -2684,6 +2904,13 @@ func UsersAddWebhook(w http.ResponseWriter, r *http.Request) {
 	events[string(types.ValidatorMissedProposalEventName)] = validatorProposalMissed
 	events[string(types.ValidatorExecutedProposalEventName)] = validatorProposalSubmitted
 	events[string(types.ValidatorReceivedWithdrawalEventName)] = validatorReceivedWithdrawal
+	events[string(types.ValidatorWithdrawalCredentialsChangedEventName)] = validatorWithdrawalCredentialsChanged
+	events[string(types.ValidatorVoluntaryExitEventName)] = validatorVoluntaryExit
+	events[string(types.ValidatorWithdrawableEventName)] = validatorWithdrawable
+	events[string(types.ValidatorFeeRecipientMismatchEventName)] = validatorFeeRecipientMismatch
+	events[string(types.ValidatorRelayRegistrationMissingEventName)] = validatorRelayRegistrationMissing
+	events[string(types.ValidatorMilestoneEventName)] = validatorMilestone
+	events[string(types.ValidatorDepositWarningEventName)] = validatorDepositWarning
 	events[string(types.ValidatorGotSlashedEventName)] = validatorGotSlashed
 	events[string(types.SyncCommitteeSoon)] = validatorSyncCommiteeSoon
 	events[string(types.ValidatorMissedAttestationEventName)] = validatorAttestationMissed
@@ -2769,6 +2996,8 @@ func UsersAddWebhook(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
 		return
 	}
+	// the url is not recorded as webhook urls usually contain secrets
+	auditUserAction(r, user.UserID, types.UserAuditWebhookAdded, types.UserAuditDetails{"destination": destination, "events": eventNames})
 	http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
 }
 
@@ -2799,6 +3028,13 @@ func UsersEditWebhook(w http.ResponseWriter, r *http.Request) {
 	validatorProposalMissed := r.FormValue(string(types.ValidatorMissedProposalEventName)) == "on"
 	validatorProposalSubmitted := r.FormValue(string(types.ValidatorExecutedProposalEventName)) == "on"
 	validatorReceivedWithdrawal := r.FormValue(string(types.ValidatorReceivedWithdrawalEventName)) == "on"
+	validatorWithdrawalCredentialsChanged := r.FormValue(string(types.ValidatorWithdrawalCredentialsChangedEventName)) == "on"
+	validatorVoluntaryExit := r.FormValue(string(types.ValidatorVoluntaryExitEventName)) == "on"
+	validatorWithdrawable := r.FormValue(string(types.ValidatorWithdrawableEventName)) == "on"
+	validatorFeeRecipientMismatch := r.FormValue(string(types.ValidatorFeeRecipientMismatchEventName)) == "on"
+	validatorRelayRegistrationMissing := r.FormValue(string(types.ValidatorRelayRegistrationMissingEventName)) == "on"
+	validatorMilestone := r.FormValue(string(types.ValidatorMilestoneEventName)) == "on"
+	validatorDepositWarning := r.FormValue(string(types.ValidatorDepositWarningEventName)) == "on"
 	validatorGotSlashed := r.FormValue(string(types.ValidatorGotSlashedEventName)) == "on"
 	validatorSyncCommiteeSoon := r.FormValue(string(types.SyncCommitteeSoon)) == "on"
 	validatorAttestationMissed := r.FormValue(string(types.ValidatorMissedAttestationEventName)) == "on"
@@ -2819,6 +3055,13 @@ func UsersEditWebhook(w http.ResponseWriter, r *http.Request) {
 	events[string(types.ValidatorMissedProposalEventName)] = validatorProposalMissed
 	events[string(types.ValidatorExecutedProposalEventName)] = validatorProposalSubmitted
 	events[string(types.ValidatorReceivedWithdrawalEventName)] = validatorReceivedWithdrawal
+	events[string(types.ValidatorWithdrawalCredentialsChangedEventName)] = validatorWithdrawalCredentialsChanged
+	events[string(types.ValidatorVoluntaryExitEventName)] = validatorVoluntaryExit
+	events[string(types.ValidatorWithdrawableEventName)] = validatorWithdrawable
+	events[string(types.ValidatorFeeRecipientMismatchEventName)] = validatorFeeRecipientMismatch
+	events[string(types.ValidatorRelayRegistrationMissingEventName)] = validatorRelayRegistrationMissing
+	events[string(types.ValidatorMilestoneEventName)] = validatorMilestone
+	events[string(types.ValidatorDepositWarningEventName)] = validatorDepositWarning
 	events[string(types.ValidatorGotSlashedEventName)] = validatorGotSlashed
 	events[string(types.SyncCommitteeSoon)] = validatorSyncCommiteeSoon
 	events[string(types.ValidatorMissedAttestationEventName)] = validatorAttestationMissed
@@ -2875,6 +3118,7 @@ func UsersEditWebhook(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
 		return
 	}
+	auditUserAction(r, user.UserID, types.UserAuditWebhookUpdated, types.UserAuditDetails{"webhook_id": webhookID, "destination": destination, "events": eventNames})
 	http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
 }
 
@@ -2909,6 +3153,7 @@ func UsersDeleteWebhook(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+	auditUserAction(r, user.UserID, types.UserAuditWebhookDeleted, types.UserAuditDetails{"webhook_id": webhookID})
 	http.Redirect(w, r, "/user/webhooks", http.StatusSeeOther)
 }
 
@@ -2961,6 +3206,11 @@ func UsersNotificationChannels(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+	auditUserAction(r, user.UserID, types.UserAuditNotificationChannelsChanged, types.UserAuditDetails{
+		string(types.EmailNotificationChannel):   channelEmail == "on",
+		string(types.PushNotificationChannel):    channelPush == "on",
+		string(types.WebhookNotificationChannel): channelWebhook == "on",
+	})
 
 	http.Redirect(w, r, "/user/notifications", http.StatusSeeOther)
 }
@@ -0,0 +1,366 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+)
+
+const maxUserValidatorDashboardCharts = 20
+const maxUserValidatorDashboardChartDays = 365
+
+func parseUserValidatorDashboardChartId(r *http.Request) (uint64, error) {
+	return strconv.ParseUint(mux.Vars(r)["chartId"], 10, 64)
+}
+
+// parseUserValidatorDashboardChartRequest reads and validates the chart of the request body against the groups of the
+// dashboard, returns an error text suitable for the response if the chart is invalid
+func parseUserValidatorDashboardChartRequest(r *http.Request, dashboard *types.UserValidatorDashboard) (*types.UserValidatorDashboardChart, string) {
+	chart := &types.UserValidatorDashboardChart{}
+	err := json.NewDecoder(r.Body).Decode(chart)
+	if err != nil {
+		return nil, "invalid request body"
+	}
+
+	chart.Name = strings.TrimSpace(chart.Name)
+	if chart.Name == "" || len(chart.Name) > maxUserValidatorDashboardNameLength {
+		return nil, fmt.Sprintf("the name of the chart must be between 1 and %d characters", maxUserValidatorDashboardNameLength)
+	}
+	if !db.IsValidatorDashboardChartMetric(chart.Metric) {
+		return nil, "invalid metric provided, must be income, cl_income, el_income, balance, effective_balance, effectiveness, missed_attestations or proposed_blocks"
+	}
+	if chart.Aggregation == "" {
+		chart.Aggregation = "sum"
+	}
+	if !db.IsValidatorDashboardChartAggregation(chart.Aggregation) {
+		return nil, "invalid aggregation provided, must be sum, avg, min or max"
+	}
+	if chart.Days == 0 {
+		chart.Days = 30
+	}
+	if chart.Days > maxUserValidatorDashboardChartDays {
+		return nil, fmt.Sprintf("a chart can show at most %d days", maxUserValidatorDashboardChartDays)
+	}
+
+	groups := map[uint64]bool{}
+	for _, group := range dashboard.Groups {
+		groups[group.Id] = true
+	}
+	chart.GroupIds = utils.SortedUniqueUint64(chart.GroupIds)
+	for _, id := range chart.GroupIds {
+		if !groups[id] {
+			return nil, fmt.Sprintf("the dashboard has no group %d", id)
+		}
+	}
+	return chart, ""
+}
+
+// getWritableValidatorDashboard returns the requested dashboard if the authenticated user may edit it. If nil is
+// returned the error response has already been sent.
+func getWritableValidatorDashboard(w http.ResponseWriter, r *http.Request) *types.UserValidatorDashboard {
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return nil
+	}
+
+	dashboardId, err := parseUserValidatorDashboardId(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid dashboard id provided")
+		return nil
+	}
+
+	ownerId := resolveValidatorDashboardOwner(w, r, user.UserID, dashboardId, true)
+	if ownerId == 0 {
+		return nil
+	}
+
+	dashboard, err := db.GetUserValidatorDashboard(ownerId, dashboardId)
+	if err != nil {
+		utils.LogError(err, "error retrieving validator dashboard", 0, map[string]interface{}{"userId": user.UserID, "dashboardId": dashboardId})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return nil
+	}
+	if dashboard == nil {
+		sendErrorWithCodeResponse(w, r.URL.String(), "dashboard not found", http.StatusNotFound)
+		return nil
+	}
+	return dashboard
+}
+
+// ValidatorDashboardCharts godoc
+// @Summary Get the custom charts of a validator dashboard
+// @Tags User
+// @Produce json
+// @Param dashboardId path integer true "The id of the dashboard"
+// @Success 200 {object} types.ApiResponse{data=[]types.UserValidatorDashboardChart}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards/{dashboardId}/charts [get]
+func ValidatorDashboardCharts(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	dashboard := getRequestedValidatorDashboard(w, r)
+	if dashboard == nil {
+		return
+	}
+
+	charts, err := db.GetValidatorDashboardCharts(dashboard.Id)
+	if err != nil {
+		utils.LogError(err, "error retrieving validator dashboard charts", 0, map[string]interface{}{"route": r.URL.String(), "dashboardId": dashboard.Id})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{charts})
+}
+
+// UserValidatorDashboardChartCreate godoc
+// @Summary Add a custom chart to a validator dashboard
+// @Tags User
+// @Description The metric (income, cl_income, el_income, balance, effective_balance, effectiveness, missed_attestations or proposed_blocks)
+// @Description is aggregated (sum, avg, min or max) per day over the validators of the selected groups, all groups if group_ids is empty.
+// @Description Amounts are in ETH. If per_group is set the chart shows one series per group.
+// @Accept json
+// @Produce json
+// @Param dashboardId path integer true "The id of the dashboard"
+// @Param chart body types.UserValidatorDashboardChart true "The chart, days defaults to 30 and at most 365"
+// @Success 200 {object} types.ApiResponse{data=types.UserValidatorDashboardChart}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards/{dashboardId}/charts [post]
+func UserValidatorDashboardChartCreate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	dashboard := getWritableValidatorDashboard(w, r)
+	if dashboard == nil {
+		return
+	}
+	errFields := map[string]interface{}{"route": r.URL.String(), "dashboardId": dashboard.Id}
+
+	chart, errText := parseUserValidatorDashboardChartRequest(r, dashboard)
+	if chart == nil {
+		SendBadRequestResponse(w, r.URL.String(), errText)
+		return
+	}
+
+	count, err := db.CountValidatorDashboardCharts(dashboard.Id)
+	if err != nil {
+		utils.LogError(err, "error counting validator dashboard charts", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if count >= maxUserValidatorDashboardCharts {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("a dashboard can have at most %d charts", maxUserValidatorDashboardCharts))
+		return
+	}
+
+	chartId, err := db.CreateValidatorDashboardChart(dashboard.Id, chart)
+	if err != nil {
+		utils.LogError(err, "error creating validator dashboard chart", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not save chart")
+		return
+	}
+
+	chart, err = db.GetValidatorDashboardChart(dashboard.Id, chartId)
+	if err != nil || chart == nil {
+		utils.LogError(err, "error retrieving created validator dashboard chart", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{chart})
+}
+
+// UserValidatorDashboardChartUpdate godoc
+// @Summary Replace a custom chart of a validator dashboard
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param dashboardId path integer true "The id of the dashboard"
+// @Param chartId path integer true "The id of the chart"
+// @Param chart body types.UserValidatorDashboardChart true "The chart"
+// @Success 200 {object} types.ApiResponse{data=types.UserValidatorDashboardChart}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards/{dashboardId}/charts/{chartId} [put]
+func UserValidatorDashboardChartUpdate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	dashboard := getWritableValidatorDashboard(w, r)
+	if dashboard == nil {
+		return
+	}
+	errFields := map[string]interface{}{"route": r.URL.String(), "dashboardId": dashboard.Id}
+
+	chartId, err := parseUserValidatorDashboardChartId(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid chart id provided")
+		return
+	}
+
+	chart, errText := parseUserValidatorDashboardChartRequest(r, dashboard)
+	if chart == nil {
+		SendBadRequestResponse(w, r.URL.String(), errText)
+		return
+	}
+
+	found, err := db.UpdateValidatorDashboardChart(dashboard.Id, chartId, chart)
+	if err != nil {
+		utils.LogError(err, "error updating validator dashboard chart", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not save chart")
+		return
+	}
+	if !found {
+		sendErrorWithCodeResponse(w, r.URL.String(), "chart not found", http.StatusNotFound)
+		return
+	}
+
+	chart, err = db.GetValidatorDashboardChart(dashboard.Id, chartId)
+	if err != nil || chart == nil {
+		utils.LogError(err, "error retrieving updated validator dashboard chart", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{chart})
+}
+
+// UserValidatorDashboardChartDelete godoc
+// @Summary Delete a custom chart of a validator dashboard
+// @Tags User
+// @Produce json
+// @Param dashboardId path integer true "The id of the dashboard"
+// @Param chartId path integer true "The id of the chart"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards/{dashboardId}/charts/{chartId} [delete]
+func UserValidatorDashboardChartDelete(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	dashboard := getWritableValidatorDashboard(w, r)
+	if dashboard == nil {
+		return
+	}
+
+	chartId, err := parseUserValidatorDashboardChartId(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid chart id provided")
+		return
+	}
+
+	found, err := db.DeleteValidatorDashboardChart(dashboard.Id, chartId)
+	if err != nil {
+		utils.LogError(err, "error deleting validator dashboard chart", 0, map[string]interface{}{"route": r.URL.String(), "dashboardId": dashboard.Id})
+		SendBadRequestResponse(w, r.URL.String(), "could not delete chart")
+		return
+	}
+	if !found {
+		sendErrorWithCodeResponse(w, r.URL.String(), "chart not found", http.StatusNotFound)
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), nil)
+}
+
+// ValidatorDashboardChartSeries godoc
+// @Summary Get the series of a custom chart of a validator dashboard
+// @Tags User
+// @Description Returns one series per group if the chart is split by group, otherwise a single series. The points are daily and
+// @Description cover the days of the chart up to the last exported day.
+// @Produce json
+// @Param dashboardId path integer true "The id of the dashboard"
+// @Param chartId path integer true "The id of the chart"
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiChartSeriesResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards/{dashboardId}/charts/{chartId}/series [get]
+func ValidatorDashboardChartSeries(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	dashboard := getRequestedValidatorDashboard(w, r)
+	if dashboard == nil {
+		return
+	}
+	errFields := map[string]interface{}{"route": r.URL.String(), "dashboardId": dashboard.Id}
+
+	chartId, err := parseUserValidatorDashboardChartId(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid chart id provided")
+		return
+	}
+
+	chart, err := db.GetValidatorDashboardChart(dashboard.Id, chartId)
+	if err != nil {
+		utils.LogError(err, "error retrieving validator dashboard chart", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if chart == nil {
+		sendErrorWithCodeResponse(w, r.URL.String(), "chart not found", http.StatusNotFound)
+		return
+	}
+
+	series := []*types.ApiChartSeriesResponse{}
+	toDay, err := db.GetLastExportedStatisticDay()
+	if errors.Is(err, db.ErrNoStats) {
+		SendOKResponse(j, r.URL.String(), []interface{}{series})
+		return
+	}
+	if err != nil {
+		utils.LogError(err, "error retrieving last exported statistic day", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	fromDay := uint64(0)
+	if toDay+1 > chart.Days {
+		fromDay = toDay + 1 - chart.Days
+	}
+
+	points, err := db.GetValidatorDashboardChartPoints(dashboard.Id, chart, fromDay, toDay)
+	if err != nil {
+		utils.LogError(err, "error retrieving validator dashboard chart points", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	from := utils.DayToTime(int64(fromDay)).Unix()
+	to := utils.DayToTime(int64(toDay) + 1).Unix()
+	if !chart.PerGroup {
+		chartPoints := points[-1]
+		if chartPoints == nil {
+			chartPoints = []*types.ChartSeriesPoint{}
+		}
+		series = append(series, &types.ApiChartSeriesResponse{Series: chart.Name, Resolution: "day", From: from, To: to, Points: chartPoints})
+	} else {
+		for _, group := range dashboard.Groups {
+			groupPoints, found := points[int64(group.Id)]
+			if !found {
+				continue
+			}
+			series = append(series, &types.ApiChartSeriesResponse{Series: group.Name, Resolution: "day", From: from, To: to, Points: groupPoints})
+		}
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{series})
+}
@@ -0,0 +1,936 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+const maxUserValidatorDashboards = 20
+const maxUserValidatorDashboardGroups = 20
+const maxUserValidatorDashboardNameLength = 50
+
+type userValidatorDashboardRequest struct {
+	Name   string                               `json:"name"`
+	Groups []*types.UserValidatorDashboardGroup `json:"groups"`
+	// Validators is a shorthand for a dashboard with only the default group
+	Validators []uint64 `json:"validators"`
+}
+
+// parseUserValidatorDashboardRequest reads and validates the dashboard of the request body, returns an error text
+// suitable for the response if the dashboard is invalid
+func parseUserValidatorDashboardRequest(r *http.Request) (*userValidatorDashboardRequest, string) {
+	req := &userValidatorDashboardRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		return nil, "invalid request body"
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > maxUserValidatorDashboardNameLength {
+		return nil, fmt.Sprintf("the name of the dashboard must be between 1 and %d characters", maxUserValidatorDashboardNameLength)
+	}
+	if len(req.Groups) == 0 {
+		req.Groups = []*types.UserValidatorDashboardGroup{{Name: "default", Validators: req.Validators}}
+	}
+	if len(req.Groups) > maxUserValidatorDashboardGroups {
+		return nil, fmt.Sprintf("a dashboard can have at most %d groups", maxUserValidatorDashboardGroups)
+	}
+
+	validatorCount := 0
+	for _, group := range req.Groups {
+		group.Name = strings.TrimSpace(group.Name)
+		if group.Name == "" || len(group.Name) > maxUserValidatorDashboardNameLength {
+			return nil, fmt.Sprintf("the name of a group must be between 1 and %d characters", maxUserValidatorDashboardNameLength)
+		}
+		group.Validators = utils.SortedUniqueUint64(group.Validators)
+		validatorCount += len(group.Validators)
+	}
+	if validatorCount > getUserPremium(r).MaxDashboardValidators {
+		return nil, fmt.Sprintf("a dashboard can have at most %d validators", getUserPremium(r).MaxDashboardValidators)
+	}
+	return req, ""
+}
+
+func parseUserValidatorDashboardId(r *http.Request) (uint64, error) {
+	return strconv.ParseUint(mux.Vars(r)["dashboardId"], 10, 64)
+}
+
+// resolveValidatorDashboardOwner returns the user owning the requested dashboard if the user may access it, either as
+// its owner or as member of the organization it is shared with. Write access requires the owner or admin role in the
+// organization. If 0 is returned the error response has already been sent.
+func resolveValidatorDashboardOwner(w http.ResponseWriter, r *http.Request, userId, dashboardId uint64, write bool) uint64 {
+	ownerId, organizationId, err := db.GetValidatorDashboardOwner(dashboardId)
+	if err != nil {
+		utils.LogError(err, "error retrieving validator dashboard owner", 0, map[string]interface{}{"userId": userId, "dashboardId": dashboardId})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return 0
+	}
+	if ownerId != 0 && ownerId == userId {
+		return ownerId
+	}
+	if ownerId != 0 && organizationId != 0 {
+		organization, err := db.GetUserOrganization(userId)
+		if err != nil {
+			utils.LogError(err, "error retrieving organization", 0, map[string]interface{}{"userId": userId})
+			SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+			return 0
+		}
+		if organization != nil && organization.ID == organizationId {
+			if write && !organization.Role.CanManage() {
+				sendErrorWithCodeResponse(w, r.URL.String(), "your role in the organization does not allow to edit the dashboard", http.StatusForbidden)
+				return 0
+			}
+			return ownerId
+		}
+	}
+	sendErrorWithCodeResponse(w, r.URL.String(), "dashboard not found", http.StatusNotFound)
+	return 0
+}
+
+// UserValidatorDashboards godoc
+// @Summary Get the validator dashboards of the authenticated user
+// @Tags User
+// @Description Includes the dashboards the other members of your organization shared with it.
+// @Produce json
+// @Success 200 {object} types.ApiResponse{data=[]types.UserValidatorDashboard}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards [get]
+func UserValidatorDashboards(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	dashboards, err := db.GetUserValidatorDashboards(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving validator dashboards", 0, map[string]interface{}{"userId": user.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	organization, err := db.GetUserOrganization(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving organization", 0, map[string]interface{}{"userId": user.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if organization != nil {
+		shared, err := db.GetOrganizationValidatorDashboards(organization.ID, user.UserID)
+		if err != nil {
+			utils.LogError(err, "error retrieving organization validator dashboards", 0, map[string]interface{}{"userId": user.UserID, "organizationId": organization.ID})
+			SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+			return
+		}
+		dashboards = append(dashboards, shared...)
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{dashboards})
+}
+
+// UserValidatorDashboard godoc
+// @Summary Get a validator dashboard of the authenticated user
+// @Tags User
+// @Produce json
+// @Param dashboardId path integer true "The id of the dashboard"
+// @Success 200 {object} types.ApiResponse{data=types.UserValidatorDashboard}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards/{dashboardId} [get]
+func UserValidatorDashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	dashboardId, err := parseUserValidatorDashboardId(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid dashboard id provided")
+		return
+	}
+	ownerId := resolveValidatorDashboardOwner(w, r, user.UserID, dashboardId, false)
+	if ownerId == 0 {
+		return
+	}
+
+	dashboard, err := db.GetUserValidatorDashboard(ownerId, dashboardId)
+	if err != nil {
+		utils.LogError(err, "error retrieving validator dashboard", 0, map[string]interface{}{"userId": user.UserID, "dashboardId": dashboardId})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if dashboard == nil {
+		sendErrorWithCodeResponse(w, r.URL.String(), "dashboard not found", http.StatusNotFound)
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{dashboard})
+}
+
+// UserValidatorDashboardCreate godoc
+// @Summary Create a validator dashboard for the authenticated user
+// @Tags User
+// @Description The validators of the dashboard are organized in named groups, a validator can only be part of one group.
+// @Description Instead of the groups a list of validators can be provided to create a dashboard with a single default group.
+// @Accept json
+// @Produce json
+// @Param dashboard body handlers.userValidatorDashboardRequest true "The name and groups of the dashboard"
+// @Success 200 {object} types.ApiResponse{data=types.UserValidatorDashboard}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards [post]
+func UserValidatorDashboardCreate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	req, errText := parseUserValidatorDashboardRequest(r)
+	if req == nil {
+		SendBadRequestResponse(w, r.URL.String(), errText)
+		return
+	}
+
+	count, err := db.CountUserValidatorDashboards(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error counting validator dashboards", 0, map[string]interface{}{"userId": user.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if count >= maxUserValidatorDashboards {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("you can have at most %d dashboards", maxUserValidatorDashboards))
+		return
+	}
+
+	dashboardId, err := db.CreateUserValidatorDashboard(user.UserID, req.Name, req.Groups)
+	if err != nil {
+		utils.LogError(err, "error creating validator dashboard", 0, map[string]interface{}{"userId": user.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not save dashboard")
+		return
+	}
+
+	dashboard, err := db.GetUserValidatorDashboard(user.UserID, dashboardId)
+	if err != nil || dashboard == nil {
+		utils.LogError(err, "error retrieving created validator dashboard", 0, map[string]interface{}{"userId": user.UserID, "dashboardId": dashboardId})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{dashboard})
+}
+
+// UserValidatorDashboardUpdate godoc
+// @Summary Rename a validator dashboard of the authenticated user and replace its groups
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param dashboardId path integer true "The id of the dashboard"
+// @Param dashboard body handlers.userValidatorDashboardRequest true "The name and groups of the dashboard"
+// @Success 200 {object} types.ApiResponse{data=types.UserValidatorDashboard}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards/{dashboardId} [put]
+func UserValidatorDashboardUpdate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	dashboardId, err := parseUserValidatorDashboardId(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid dashboard id provided")
+		return
+	}
+
+	ownerId := resolveValidatorDashboardOwner(w, r, user.UserID, dashboardId, true)
+	if ownerId == 0 {
+		return
+	}
+
+	req, errText := parseUserValidatorDashboardRequest(r)
+	if req == nil {
+		SendBadRequestResponse(w, r.URL.String(), errText)
+		return
+	}
+
+	found, err := db.UpdateUserValidatorDashboard(ownerId, dashboardId, req.Name, req.Groups)
+	if err != nil {
+		utils.LogError(err, "error updating validator dashboard", 0, map[string]interface{}{"userId": user.UserID, "dashboardId": dashboardId})
+		SendBadRequestResponse(w, r.URL.String(), "could not save dashboard")
+		return
+	}
+	if !found {
+		sendErrorWithCodeResponse(w, r.URL.String(), "dashboard not found", http.StatusNotFound)
+		return
+	}
+
+	dashboard, err := db.GetUserValidatorDashboard(ownerId, dashboardId)
+	if err != nil || dashboard == nil {
+		utils.LogError(err, "error retrieving updated validator dashboard", 0, map[string]interface{}{"userId": user.UserID, "dashboardId": dashboardId})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{dashboard})
+}
+
+// UserValidatorDashboardDelete godoc
+// @Summary Delete a validator dashboard of the authenticated user, its public links stop working
+// @Tags User
+// @Produce json
+// @Param dashboardId path integer true "The id of the dashboard"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards/{dashboardId} [delete]
+func UserValidatorDashboardDelete(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	dashboardId, err := parseUserValidatorDashboardId(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid dashboard id provided")
+		return
+	}
+
+	ownerId := resolveValidatorDashboardOwner(w, r, user.UserID, dashboardId, true)
+	if ownerId == 0 {
+		return
+	}
+
+	found, err := db.DeleteUserValidatorDashboard(ownerId, dashboardId)
+	if err != nil {
+		utils.LogError(err, "error deleting validator dashboard", 0, map[string]interface{}{"userId": user.UserID, "dashboardId": dashboardId})
+		SendBadRequestResponse(w, r.URL.String(), "could not delete dashboard")
+		return
+	}
+	if !found {
+		sendErrorWithCodeResponse(w, r.URL.String(), "dashboard not found", http.StatusNotFound)
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), nil)
+}
+
+// UserValidatorDashboardShare godoc
+// @Summary Create a read-only public link to a validator dashboard of the authenticated user
+// @Tags User
+// @Description Everyone knowing the returned public id can view the dashboard via /dashboard/shared/{publicId} or /api/v1/dashboard/shared/{publicId}.
+// @Accept json
+// @Produce json
+// @Param dashboardId path integer true "The id of the dashboard"
+// @Param share body types.UserValidatorDashboardShare false "The name shown to the viewers of the link, defaults to the name of the dashboard"
+// @Success 200 {object} types.ApiResponse{data=types.UserValidatorDashboardShare}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards/{dashboardId}/share [post]
+func UserValidatorDashboardShare(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	dashboardId, err := parseUserValidatorDashboardId(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid dashboard id provided")
+		return
+	}
+
+	ownerId := resolveValidatorDashboardOwner(w, r, user.UserID, dashboardId, true)
+	if ownerId == 0 {
+		return
+	}
+
+	dashboard, err := db.GetUserValidatorDashboard(ownerId, dashboardId)
+	if err != nil {
+		utils.LogError(err, "error retrieving validator dashboard", 0, map[string]interface{}{"userId": user.UserID, "dashboardId": dashboardId})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if dashboard == nil {
+		sendErrorWithCodeResponse(w, r.URL.String(), "dashboard not found", http.StatusNotFound)
+		return
+	}
+
+	share := &types.UserValidatorDashboardShare{}
+	if r.ContentLength != 0 {
+		err = json.NewDecoder(r.Body).Decode(share)
+		if err != nil {
+			SendBadRequestResponse(w, r.URL.String(), "invalid request body")
+			return
+		}
+	}
+	share.Name = strings.TrimSpace(share.Name)
+	if share.Name == "" {
+		share.Name = dashboard.Name
+	}
+	if len(share.Name) > maxUserValidatorDashboardNameLength {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("the name of the link must be at most %d characters", maxUserValidatorDashboardNameLength))
+		return
+	}
+
+	share.PublicId, err = db.ShareUserValidatorDashboard(ownerId, dashboardId, share.Name)
+	if err != nil {
+		utils.LogError(err, "error sharing validator dashboard", 0, map[string]interface{}{"userId": user.UserID, "dashboardId": dashboardId})
+		SendBadRequestResponse(w, r.URL.String(), "could not share dashboard")
+		return
+	}
+	if share.PublicId == "" {
+		sendErrorWithCodeResponse(w, r.URL.String(), "dashboard not found", http.StatusNotFound)
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{share})
+}
+
+// UserValidatorDashboardUnshare godoc
+// @Summary Revoke a public link to a validator dashboard of the authenticated user
+// @Tags User
+// @Produce json
+// @Param dashboardId path integer true "The id of the dashboard"
+// @Param publicId path string true "The public id of the link"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards/{dashboardId}/share/{publicId} [delete]
+func UserValidatorDashboardUnshare(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	dashboardId, err := parseUserValidatorDashboardId(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid dashboard id provided")
+		return
+	}
+
+	ownerId := resolveValidatorDashboardOwner(w, r, user.UserID, dashboardId, true)
+	if ownerId == 0 {
+		return
+	}
+
+	found, err := db.DeleteUserValidatorDashboardShare(ownerId, dashboardId, mux.Vars(r)["publicId"])
+	if err != nil {
+		utils.LogError(err, "error revoking validator dashboard share", 0, map[string]interface{}{"userId": user.UserID, "dashboardId": dashboardId})
+		SendBadRequestResponse(w, r.URL.String(), "could not revoke link")
+		return
+	}
+	if !found {
+		sendErrorWithCodeResponse(w, r.URL.String(), "link not found", http.StatusNotFound)
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), nil)
+}
+
+// UserValidatorDashboardOrganizationShare godoc
+// @Summary Share a validator dashboard of the authenticated user with the organization of the user
+// @Tags User
+// @Description All members of the organization can view the dashboard, owners and admins can also edit it.
+// @Produce json
+// @Param dashboardId path integer true "The id of the dashboard"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards/{dashboardId}/organization [put]
+func UserValidatorDashboardOrganizationShare(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	dashboardId, err := parseUserValidatorDashboardId(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid dashboard id provided")
+		return
+	}
+
+	organization, err := db.GetUserOrganization(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving organization", 0, map[string]interface{}{"userId": user.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if organization == nil {
+		SendBadRequestResponse(w, r.URL.String(), "you are not part of an organization")
+		return
+	}
+
+	found, err := db.SetUserValidatorDashboardOrganization(user.UserID, dashboardId, &organization.ID)
+	if err != nil {
+		utils.LogError(err, "error sharing validator dashboard with organization", 0, map[string]interface{}{"userId": user.UserID, "dashboardId": dashboardId})
+		SendBadRequestResponse(w, r.URL.String(), "could not share dashboard")
+		return
+	}
+	if !found {
+		sendErrorWithCodeResponse(w, r.URL.String(), "dashboard not found", http.StatusNotFound)
+		return
+	}
+	auditUserAction(r, user.UserID, types.UserAuditOrganizationShareChanged, types.UserAuditDetails{"organization_id": organization.ID, "dashboard_id": dashboardId, "shared": true})
+
+	SendOKResponse(j, r.URL.String(), nil)
+}
+
+// UserValidatorDashboardOrganizationUnshare godoc
+// @Summary Stop sharing a validator dashboard with the organization
+// @Tags User
+// @Description Can be used by the owner of the dashboard and the owners and admins of the organization.
+// @Produce json
+// @Param dashboardId path integer true "The id of the dashboard"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards/{dashboardId}/organization [delete]
+func UserValidatorDashboardOrganizationUnshare(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	dashboardId, err := parseUserValidatorDashboardId(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid dashboard id provided")
+		return
+	}
+	ownerId := resolveValidatorDashboardOwner(w, r, user.UserID, dashboardId, true)
+	if ownerId == 0 {
+		return
+	}
+
+	found, err := db.SetUserValidatorDashboardOrganization(ownerId, dashboardId, nil)
+	if err != nil {
+		utils.LogError(err, "error unsharing validator dashboard with organization", 0, map[string]interface{}{"userId": user.UserID, "dashboardId": dashboardId})
+		SendBadRequestResponse(w, r.URL.String(), "could not unshare dashboard")
+		return
+	}
+	if !found {
+		sendErrorWithCodeResponse(w, r.URL.String(), "dashboard not found", http.StatusNotFound)
+		return
+	}
+	auditUserAction(r, user.UserID, types.UserAuditOrganizationShareChanged, types.UserAuditDetails{"dashboard_id": dashboardId, "shared": false})
+
+	SendOKResponse(j, r.URL.String(), nil)
+}
+
+// ApiSharedValidatorDashboard godoc
+// @Summary Get a validator dashboard shared via a public link
+// @Tags Dashboard
+// @Produce json
+// @Param publicId path string true "The public id of the link"
+// @Success 200 {object} types.ApiResponse{data=types.UserValidatorDashboard}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Router /api/v1/dashboard/shared/{publicId} [get]
+func ApiSharedValidatorDashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	publicId := mux.Vars(r)["publicId"]
+	dashboard, err := db.GetSharedValidatorDashboard(publicId)
+	if err != nil {
+		utils.LogError(err, "error retrieving shared validator dashboard", 0, map[string]interface{}{"publicId": publicId})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if dashboard == nil {
+		sendErrorWithCodeResponse(w, r.URL.String(), "dashboard not found", http.StatusNotFound)
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{dashboard})
+}
+
+// SharedValidatorDashboard opens the dashboard page with the validators of a dashboard shared via a public link
+func SharedValidatorDashboard(w http.ResponseWriter, r *http.Request) {
+	publicId := mux.Vars(r)["publicId"]
+	dashboard, err := db.GetSharedValidatorDashboard(publicId)
+	if err != nil {
+		utils.LogError(err, "error retrieving shared validator dashboard", 0, map[string]interface{}{"publicId": publicId})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if dashboard == nil {
+		NotFound(w, r)
+		return
+	}
+
+	validators := []string{}
+	for _, group := range dashboard.Groups {
+		for _, v := range group.Validators {
+			validators = append(validators, fmt.Sprintf("%d", v))
+		}
+	}
+	// dashboards exceeding the validator limit of the validators based dashboard are only shown server side aggregated
+	if len(validators) > getUserPremium(r).MaxValidators {
+		http.Redirect(w, r, fmt.Sprintf("/dashboard?shared=%s", publicId), http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, fmt.Sprintf("/dashboard?validators=%s&shared=%s", strings.Join(validators, ","), publicId), http.StatusSeeOther)
+}
+
+// ValidatorDashboardGroupsData godoc
+// @Summary Get the aggregated performance of every group of a validator dashboard
+// @Tags User
+// @Description Sums up the duties and income of the validators of every group over the last days and flags groups which perform worse than the rest of the dashboard, e.g. to spot an underperforming machine.
+// @Description Shared dashboards can be queried via /api/v1/dashboard/shared/{publicId}/groups without authentication.
+// @Produce json
+// @Param dashboardId path integer true "The id of the dashboard"
+// @Param days query integer false "Number of days to aggregate, at most 31 (default 7)"
+// @Success 200 {object} types.ApiResponse{data=[]types.UserValidatorDashboardGroupStats}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards/{dashboardId}/groups [get]
+func ValidatorDashboardGroupsData(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	dashboard := getRequestedValidatorDashboard(w, r)
+	if dashboard == nil {
+		return
+	}
+
+	days := parseUintWithDefault(r.URL.Query().Get("days"), 7)
+	if days == 0 || days > 31 {
+		SendBadRequestResponse(w, r.URL.String(), "invalid days provided, must be between 1 and 31")
+		return
+	}
+
+	epoch := services.LatestEpoch()
+	groups, err := db.GetValidatorDashboardGroupStats(dashboard.Id, days, epoch)
+	if err != nil {
+		utils.LogError(err, "error retrieving validator dashboard group stats", 0, map[string]interface{}{"route": r.URL.String()})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	offline, err := getOfflineValidators(dashboard, epoch)
+	if err != nil {
+		utils.LogError(err, "error retrieving offline validators of validator dashboard", 0, map[string]interface{}{"route": r.URL.String()})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	aggregateValidatorDashboardGroupStats(groups, offline)
+
+	SendOKResponse(j, r.URL.String(), []interface{}{groups})
+}
+
+// getRequestedValidatorDashboard returns the dashboard of the request, either shared via the publicId or accessible by
+// the authenticated user. If the dashboard can not be returned the error response has already been sent and nil is returned.
+func getRequestedValidatorDashboard(w http.ResponseWriter, r *http.Request) *types.UserValidatorDashboard {
+	var dashboard *types.UserValidatorDashboard
+	var err error
+	if publicId, shared := mux.Vars(r)["publicId"]; shared {
+		dashboard, err = db.GetSharedValidatorDashboard(publicId)
+	} else {
+		user := getUser(r)
+		if !user.Authenticated {
+			sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+			return nil
+		}
+		var dashboardId uint64
+		dashboardId, err = parseUserValidatorDashboardId(r)
+		if err != nil {
+			SendBadRequestResponse(w, r.URL.String(), "invalid dashboard id provided")
+			return nil
+		}
+		ownerId := resolveValidatorDashboardOwner(w, r, user.UserID, dashboardId, false)
+		if ownerId == 0 {
+			return nil
+		}
+		dashboard, err = db.GetUserValidatorDashboard(ownerId, dashboardId)
+	}
+	if err != nil {
+		utils.LogError(err, "error retrieving validator dashboard", 0, map[string]interface{}{"route": r.URL.String()})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return nil
+	}
+	if dashboard == nil {
+		sendErrorWithCodeResponse(w, r.URL.String(), "dashboard not found", http.StatusNotFound)
+		return nil
+	}
+	return dashboard
+}
+
+// getOfflineValidators returns the active validators of every group of the dashboard which did not attest in the last two epochs
+func getOfflineValidators(dashboard *types.UserValidatorDashboard, epoch uint64) (map[uint64][]uint64, error) {
+	validators := []uint64{}
+	for _, group := range dashboard.Groups {
+		validators = append(validators, group.Validators...)
+	}
+	offlineValidators, err := getOfflineValidatorIndices(validators, epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	offline := map[uint64][]uint64{}
+	for _, group := range dashboard.Groups {
+		for _, v := range group.Validators {
+			if offlineValidators[v] {
+				offline[group.Id] = append(offline[group.Id], v)
+			}
+		}
+	}
+	return offline, nil
+}
+
+// getOfflineValidatorIndices returns the validators which were active but did not attest in the last two epochs
+func getOfflineValidatorIndices(validators []uint64, epoch uint64) (map[uint64]bool, error) {
+	offline := map[uint64]bool{}
+	if len(validators) == 0 || epoch < 2 {
+		return offline, nil
+	}
+
+	activeValidators := []uint64{}
+	err := db.ReaderDb.Select(&activeValidators, `
+		SELECT validatorindex FROM validators WHERE validatorindex = ANY($1) AND activationepoch < $2 AND exitepoch > $2`, pq.Array(validators), epoch-2)
+	if err != nil {
+		return nil, err
+	}
+	if len(activeValidators) == 0 {
+		return offline, nil
+	}
+
+	lastAttestationSlots, err := db.BigtableClient.GetLastAttestationSlots(activeValidators)
+	if err != nil {
+		return nil, err
+	}
+	threshold := (epoch - 2) * utils.Config.Chain.ClConfig.SlotsPerEpoch
+	for _, v := range activeValidators {
+		if lastAttestationSlots[v] < threshold {
+			offline[v] = true
+		}
+	}
+	return offline, nil
+}
+
+// aggregateValidatorDashboardGroupStats derives the efficiencies and income of the groups and raises alerts for groups
+// with offline, slashed or underperforming validators. A group underperforms if its attestation efficiency is more than
+// one percentage point below the efficiency of the whole dashboard.
+func aggregateValidatorDashboardGroupStats(groups []*types.UserValidatorDashboardGroupStats, offline map[uint64][]uint64) {
+	epochsPerDay := utils.EpochsPerDay()
+
+	var totalMissed, totalExpected uint64
+	for _, g := range groups {
+		totalMissed += g.MissedAttestations
+		totalExpected += g.ActiveDays * epochsPerDay
+	}
+	dashboardEfficiency := 0.0
+	if totalExpected > 0 {
+		dashboardEfficiency = 1 - float64(totalMissed)/float64(totalExpected)
+	}
+
+	for _, g := range groups {
+		g.ClIncome = float64(g.ClIncomeGwei) / 1e9
+		g.ElIncome = g.ElIncomeWei / 1e18
+		if expected := g.ActiveDays * epochsPerDay; expected > 0 {
+			g.AttestationEfficiency = 1 - float64(g.MissedAttestations)/float64(expected)
+		}
+		if proposals := g.ProposedBlocks + g.MissedBlocks; proposals > 0 {
+			efficiency := float64(g.ProposedBlocks) / float64(proposals)
+			g.ProposalEfficiency = &efficiency
+		}
+		if syncDuties := g.ParticipatedSync + g.MissedSync; syncDuties > 0 {
+			efficiency := float64(g.ParticipatedSync) / float64(syncDuties)
+			g.SyncEfficiency = &efficiency
+		}
+
+		g.OfflineValidators = offline[g.Id]
+		if g.OfflineValidators == nil {
+			g.OfflineValidators = []uint64{}
+		}
+		g.Alerts = []string{}
+		if len(g.OfflineValidators) > 0 {
+			g.Alerts = append(g.Alerts, fmt.Sprintf("%d of %d active validators offline", len(g.OfflineValidators), g.ActiveValidators))
+		}
+		if g.SlashedValidators > 0 {
+			g.Alerts = append(g.Alerts, fmt.Sprintf("%d validators slashed", g.SlashedValidators))
+		}
+		if g.MissedBlocks > 0 {
+			g.Alerts = append(g.Alerts, fmt.Sprintf("%d block proposals missed", g.MissedBlocks))
+		}
+		if len(groups) > 1 && g.ActiveDays > 0 && g.AttestationEfficiency < dashboardEfficiency-0.01 {
+			g.Alerts = append(g.Alerts, fmt.Sprintf("attestation efficiency %.2f%% below the dashboard average of %.2f%%", g.AttestationEfficiency*100, dashboardEfficiency*100))
+		}
+	}
+}
+
+// ValidatorDashboardSummaryData godoc
+// @Summary Get the server side aggregated balance, income and duties of all validators of a validator dashboard
+// @Tags User
+// @Description Unlike the validators based dashboard endpoints this endpoint is not limited in the number of validators, the
+// @Description stats are rolled up per day and dashboard so dashboards with many thousand validators are answered quickly.
+// @Description Shared dashboards can be queried via /api/v1/dashboard/shared/{publicId}/summary without authentication.
+// @Produce json
+// @Param dashboardId path integer true "The id of the dashboard"
+// @Param days query integer false "Number of days of the balance and income history, all days if omitted"
+// @Success 200 {object} types.ApiResponse{data=types.UserValidatorDashboardSummary}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/dashboards/{dashboardId}/summary [get]
+func ValidatorDashboardSummaryData(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+
+	dashboard := getRequestedValidatorDashboard(w, r)
+	if dashboard == nil {
+		return
+	}
+	days := parseUintWithDefault(r.URL.Query().Get("days"), 0)
+	errFields := map[string]interface{}{"route": r.URL.String(), "dashboardId": dashboard.Id}
+
+	epoch := services.LatestEpoch()
+	summary, err := db.GetValidatorDashboardSummary(dashboard.Id, epoch)
+	if err != nil {
+		utils.LogError(err, "error retrieving validator dashboard summary", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	err = db.UpdateValidatorDashboardStats(dashboard.Id)
+	if err != nil {
+		utils.LogError(err, "error updating validator dashboard stats", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	stats, err := db.GetValidatorDashboardStats(dashboard.Id)
+	if err != nil {
+		utils.LogError(err, "error retrieving validator dashboard stats", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	validators := []uint64{}
+	for _, group := range dashboard.Groups {
+		validators = append(validators, group.Validators...)
+	}
+	err = addValidatorDashboardBalances(summary, validators, epoch)
+	if err != nil {
+		utils.LogError(err, "error retrieving balances of validator dashboard", 0, errFields)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	aggregateValidatorDashboardSummary(summary, stats, days)
+
+	SendOKResponse(j, r.URL.String(), []interface{}{summary})
+}
+
+// addValidatorDashboardBalances adds the current balances of the validators to the summary
+func addValidatorDashboardBalances(summary *types.UserValidatorDashboardSummary, validators []uint64, epoch uint64) error {
+	if len(validators) == 0 {
+		return nil
+	}
+	// the bigtable client reads the balances in concurrent batches
+	balances, err := db.BigtableClient.GetValidatorBalanceHistory(validators, epoch, epoch)
+	if err != nil {
+		return err
+	}
+	for _, history := range balances {
+		if len(history) == 0 {
+			continue
+		}
+		summary.Balance += float64(history[0].Balance) / 1e9
+		summary.EffectiveBalance += float64(history[0].EffectiveBalance) / 1e9
+	}
+	return nil
+}
+
+// aggregateValidatorDashboardSummary derives the income, the efficiencies of the last 7 days and the history charts of
+// the last days (all days if days is 0) from the daily rollup of a dashboard
+func aggregateValidatorDashboardSummary(summary *types.UserValidatorDashboardSummary, stats []*types.UserValidatorDashboardDayStats, days uint64) {
+	epochsPerDay := utils.EpochsPerDay()
+
+	var missedAttestations, expectedAttestations, proposedBlocks, missedBlocks, participatedSync, missedSync uint64
+	summary.BalanceHistory = make([]*types.ChartDataPoint, 0, len(stats))
+	summary.IncomeHistory = make([]*types.ChartDataPoint, 0, len(stats))
+	for i, day := range stats {
+		income := float64(day.ClRewardsGwei)/1e9 + day.ElRewardsWei/1e18
+		age := len(stats) - i
+		summary.IncomeTotal += income
+		if age <= 31 {
+			summary.Income31d += income
+		}
+		if age <= 7 {
+			summary.Income7d += income
+			missedAttestations += day.MissedAttestations
+			expectedAttestations += day.ActiveDays * epochsPerDay
+			proposedBlocks += day.ProposedBlocks
+			missedBlocks += day.MissedBlocks
+			participatedSync += day.ParticipatedSync
+			missedSync += day.MissedSync
+		}
+		if age <= 1 {
+			summary.Income1d += income
+		}
+
+		if days > 0 && uint64(age) > days {
+			continue
+		}
+		ts := float64(utils.DayToTime(int64(day.Day)).Unix() * 1000)
+		summary.BalanceHistory = append(summary.BalanceHistory, &types.ChartDataPoint{X: ts, Y: float64(day.EndBalance) / 1e9})
+		summary.IncomeHistory = append(summary.IncomeHistory, &types.ChartDataPoint{X: ts, Y: income})
+	}
+
+	if expectedAttestations > 0 {
+		summary.AttestationEfficiency = 1 - float64(missedAttestations)/float64(expectedAttestations)
+	}
+	if proposals := proposedBlocks + missedBlocks; proposals > 0 {
+		efficiency := float64(proposedBlocks) / float64(proposals)
+		summary.ProposalEfficiency = &efficiency
+	}
+	if syncDuties := participatedSync + missedSync; syncDuties > 0 {
+		efficiency := float64(participatedSync) / float64(syncDuties)
+		summary.SyncEfficiency = &efficiency
+	}
+}
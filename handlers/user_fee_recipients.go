@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+)
+
+const maxExpectedFeeRecipientPubkeys = 100
+
+type userExpectedFeeRecipientRequest struct {
+	FeeRecipient string `json:"fee_recipient"`
+}
+
+// parseExpectedFeeRecipientPubkeys parses the comma separated validator pubkeys of the path, returns an error text
+// suitable for the response if a pubkey is invalid
+func parseExpectedFeeRecipientPubkeys(r *http.Request) ([][]byte, string) {
+	params := strings.Split(mux.Vars(r)["pubkey"], ",")
+	if len(params) > maxExpectedFeeRecipientPubkeys {
+		return nil, fmt.Sprintf("at most %d pubkeys can be provided", maxExpectedFeeRecipientPubkeys)
+	}
+	pubkeys := make([][]byte, 0, len(params))
+	for _, param := range params {
+		pubkey, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(param), "0x"))
+		if err != nil || len(pubkey) != 48 {
+			return nil, fmt.Sprintf("invalid pubkey %q", param)
+		}
+		pubkeys = append(pubkeys, pubkey)
+	}
+	return pubkeys, ""
+}
+
+// UserExpectedFeeRecipients godoc
+// @Summary Get the fee recipients the authenticated user expects for its validators
+// @Tags User
+// @Description Proposals of subscribed validators that pay a different fee recipient trigger the validator_fee_recipient_mismatch notification.
+// @Produce json
+// @Success 200 {object} types.ApiResponse{data=[]types.ApiUserExpectedFeeRecipientResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/validator/fee-recipients [get]
+func UserExpectedFeeRecipients(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	feeRecipients, err := db.GetUserExpectedFeeRecipients(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving expected fee recipients", 0, map[string]interface{}{"userId": user.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	data := make([]*types.ApiUserExpectedFeeRecipientResponse, 0, len(feeRecipients))
+	for _, feeRecipient := range feeRecipients {
+		data = append(data, &types.ApiUserExpectedFeeRecipientResponse{
+			Pubkey:       fmt.Sprintf("0x%x", feeRecipient.ValidatorPublickey),
+			FeeRecipient: common.BytesToAddress(feeRecipient.FeeRecipient).Hex(),
+			CreatedTs:    feeRecipient.CreatedTs.Unix(),
+		})
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
+// UserExpectedFeeRecipientSet godoc
+// @Summary Set the fee recipient the authenticated user expects for up to 100 validators
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param pubkey path string true "Up to 100 validator pubkeys, comma separated"
+// @Param body body handlers.userExpectedFeeRecipientRequest true "The expected fee recipient"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/validator/{pubkey}/fee-recipient [put]
+func UserExpectedFeeRecipientSet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	pubkeys, errText := parseExpectedFeeRecipientPubkeys(r)
+	if pubkeys == nil {
+		SendBadRequestResponse(w, r.URL.String(), errText)
+		return
+	}
+
+	req := &userExpectedFeeRecipientRequest{}
+	err := json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid request body")
+		return
+	}
+	if !utils.IsValidEth1Address(req.FeeRecipient) {
+		SendBadRequestResponse(w, r.URL.String(), "invalid fee recipient provided")
+		return
+	}
+
+	err = db.SetUserExpectedFeeRecipient(user.UserID, pubkeys, common.HexToAddress(req.FeeRecipient).Bytes())
+	if err != nil {
+		utils.LogError(err, "error setting expected fee recipient", 0, map[string]interface{}{"userId": user.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not set expected fee recipient")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), nil)
+}
+
+// UserExpectedFeeRecipientDelete godoc
+// @Summary Remove the fee recipient the authenticated user expects for up to 100 validators
+// @Tags User
+// @Produce json
+// @Param pubkey path string true "Up to 100 validator pubkeys, comma separated"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/validator/{pubkey}/fee-recipient [delete]
+func UserExpectedFeeRecipientDelete(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	pubkeys, errText := parseExpectedFeeRecipientPubkeys(r)
+	if pubkeys == nil {
+		SendBadRequestResponse(w, r.URL.String(), errText)
+		return
+	}
+
+	err := db.DeleteUserExpectedFeeRecipient(user.UserID, pubkeys)
+	if err != nil {
+		utils.LogError(err, "error deleting expected fee recipient", 0, map[string]interface{}{"userId": user.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not delete expected fee recipient")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), nil)
+}
@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+const maxUserNotificationSubscriptionsPerRequest = 1000
+const defaultUserNotificationSubscriptionsLimit = 100
+
+type userNotificationSubscriptionRequest struct {
+	EventName      string  `json:"event_name"`
+	EventFilter    string  `json:"event_filter"`
+	EventThreshold float64 `json:"event_threshold"`
+	// Channels are the channels the notifications are sent on, all channels of the user if empty
+	Channels []string `json:"channels"`
+}
+
+type userNotificationSubscriptionUpdateRequest struct {
+	EventThreshold float64  `json:"event_threshold"`
+	Channels       []string `json:"channels"`
+}
+
+type userNotificationSubscriptionsResponse struct {
+	Total         uint64                                `json:"total"`
+	Subscriptions []*types.UserNotificationSubscription `json:"subscriptions"`
+}
+
+// parseSubscriptionChannels validates the channels of a subscription request, returns an error text suitable for the
+// response if a channel is unknown
+func parseSubscriptionChannels(channels []string) (pq.StringArray, string) {
+	parsed := pq.StringArray{}
+	seen := map[types.NotificationChannel]bool{}
+	for _, channel := range channels {
+		ch, err := types.GetNotificationChannel(channel)
+		if err != nil {
+			return nil, fmt.Sprintf("unknown channel %q", channel)
+		}
+		if !seen[ch] {
+			seen[ch] = true
+			parsed = append(parsed, string(ch))
+		}
+	}
+	return parsed, ""
+}
+
+// parseUserNotificationSubscriptionRequest validates a subscription of the request body and normalizes its filter the
+// same way as the subscriptions of the website, returns an error text suitable for the response if it is invalid
+func parseUserNotificationSubscriptionRequest(r *http.Request, userID uint64, req *userNotificationSubscriptionRequest) (*types.UserNotificationSubscription, string, error) {
+	eventName, err := types.EventNameFromString(strings.TrimPrefix(req.EventName, utils.GetNetwork()+":"))
+	if err != nil {
+		return nil, fmt.Sprintf("unknown event name %q", req.EventName), nil
+	}
+
+	filter := strings.Replace(req.EventFilter, "0x", "", -1)
+	if types.IsEthAddressNotification(eventName) {
+		filter = strings.ToLower(filter)
+	}
+	valid, err := isValidSubscriptionFilter(userID, eventName, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	if !valid {
+		return nil, fmt.Sprintf("invalid filter %q for event %v, only pubkey, address, client or machine name is valid", req.EventFilter, eventName), nil
+	}
+
+	threshold := req.EventThreshold
+	if !getUserPremium(r).NotificationThresholds {
+		threshold = freeSubscriptionThreshold(eventName, threshold)
+	}
+
+	channels, errText := parseSubscriptionChannels(req.Channels)
+	if channels == nil {
+		return nil, errText, nil
+	}
+
+	return &types.UserNotificationSubscription{
+		EventName:      eventName,
+		EventFilter:    filter,
+		EventThreshold: threshold,
+		Channels:       channels,
+	}, "", nil
+}
+
+func parseUserNotificationSubscriptionId(r *http.Request) (uint64, error) {
+	return strconv.ParseUint(mux.Vars(r)["subscriptionId"], 10, 64)
+}
+
+// UserNotificationSubscriptions godoc
+// @Summary Get the notification subscriptions of the authenticated user on the current network
+// @Tags User
+// @Produce json
+// @Param limit query integer false "Number of subscriptions to return, at most 1000" default(100)
+// @Param offset query integer false "Number of subscriptions to skip"
+// @Success 200 {object} types.ApiResponse{data=handlers.userNotificationSubscriptionsResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/notifications/subscriptions [get]
+func UserNotificationSubscriptions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	q := r.URL.Query()
+	limit := uint64(defaultUserNotificationSubscriptionsLimit)
+	if q.Get("limit") != "" {
+		l, err := strconv.ParseUint(q.Get("limit"), 10, 64)
+		if err != nil || l == 0 || l > maxUserNotificationSubscriptionsPerRequest {
+			SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("limit must be between 1 and %d", maxUserNotificationSubscriptionsPerRequest))
+			return
+		}
+		limit = l
+	}
+	offset := uint64(0)
+	if q.Get("offset") != "" {
+		o, err := strconv.ParseUint(q.Get("offset"), 10, 64)
+		if err != nil {
+			SendBadRequestResponse(w, r.URL.String(), "invalid offset provided")
+			return
+		}
+		offset = o
+	}
+
+	subs, total, err := db.GetUserNotificationSubscriptions(user.UserID, limit, offset)
+	if err != nil {
+		utils.LogError(err, "error retrieving notification subscriptions", 0, map[string]interface{}{"userId": user.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{userNotificationSubscriptionsResponse{Total: total, Subscriptions: subs}})
+}
+
+// UserNotificationSubscription godoc
+// @Summary Get a notification subscription of the authenticated user
+// @Tags User
+// @Produce json
+// @Param subscriptionId path integer true "The id of the subscription"
+// @Success 200 {object} types.ApiResponse{data=types.UserNotificationSubscription}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/notifications/subscriptions/{subscriptionId} [get]
+func UserNotificationSubscription(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	subscriptionId, err := parseUserNotificationSubscriptionId(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid subscription id provided")
+		return
+	}
+
+	sub, err := db.GetUserNotificationSubscription(user.UserID, subscriptionId)
+	if err != nil {
+		utils.LogError(err, "error retrieving notification subscription", 0, map[string]interface{}{"userId": user.UserID, "subscriptionId": subscriptionId})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if sub == nil {
+		sendErrorWithCodeResponse(w, r.URL.String(), "subscription not found", http.StatusNotFound)
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{sub})
+}
+
+// UserNotificationSubscriptionsCreate godoc
+// @Summary Create notification subscriptions for the authenticated user
+// @Tags User
+// @Description Creates up to 1000 subscriptions at once. A subscription to an event and filter the user is already subscribed to
+// @Description replaces the threshold and channels of the existing subscription, so the request can be repeated to sync the subscriptions.
+// @Description Without channels the notifications are sent on all channels of the user.
+// @Accept json
+// @Produce json
+// @Param subscriptions body []handlers.userNotificationSubscriptionRequest true "The subscriptions"
+// @Success 200 {object} types.ApiResponse{data=[]types.UserNotificationSubscription}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/notifications/subscriptions [post]
+func UserNotificationSubscriptionsCreate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	reqs := []*userNotificationSubscriptionRequest{}
+	err := json.NewDecoder(r.Body).Decode(&reqs)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid request body")
+		return
+	}
+	if len(reqs) == 0 || len(reqs) > maxUserNotificationSubscriptionsPerRequest {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("between 1 and %d subscriptions can be created at once", maxUserNotificationSubscriptionsPerRequest))
+		return
+	}
+
+	subs := make([]*types.UserNotificationSubscription, 0, len(reqs))
+	for _, req := range reqs {
+		sub, errText, err := parseUserNotificationSubscriptionRequest(r, user.UserID, req)
+		if err != nil {
+			utils.LogError(err, "error validating notification subscription", 0, map[string]interface{}{"userId": user.UserID, "event": req.EventName, "filter": req.EventFilter})
+			SendBadRequestResponse(w, r.URL.String(), "could not validate subscription")
+			return
+		}
+		if sub == nil {
+			SendBadRequestResponse(w, r.URL.String(), errText)
+			return
+		}
+		subs = append(subs, sub)
+	}
+
+	saved, err := db.SaveUserNotificationSubscriptions(user.UserID, subs)
+	if err != nil {
+		utils.LogError(err, "error saving notification subscriptions", 0, map[string]interface{}{"userId": user.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not save subscriptions")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{saved})
+}
+
+// UserNotificationSubscriptionUpdate godoc
+// @Summary Replace the threshold and channels of a notification subscription of the authenticated user
+// @Tags User
+// @Description Without channels the notifications are sent on all channels of the user.
+// @Accept json
+// @Produce json
+// @Param subscriptionId path integer true "The id of the subscription"
+// @Param subscription body handlers.userNotificationSubscriptionUpdateRequest true "The threshold and channels of the subscription"
+// @Success 200 {object} types.ApiResponse{data=types.UserNotificationSubscription}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/notifications/subscriptions/{subscriptionId} [put]
+func UserNotificationSubscriptionUpdate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	subscriptionId, err := parseUserNotificationSubscriptionId(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid subscription id provided")
+		return
+	}
+
+	req := &userNotificationSubscriptionUpdateRequest{}
+	err = json.NewDecoder(r.Body).Decode(req)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid request body")
+		return
+	}
+	channels, errText := parseSubscriptionChannels(req.Channels)
+	if channels == nil {
+		SendBadRequestResponse(w, r.URL.String(), errText)
+		return
+	}
+
+	sub, err := db.GetUserNotificationSubscription(user.UserID, subscriptionId)
+	if err != nil {
+		utils.LogError(err, "error retrieving notification subscription", 0, map[string]interface{}{"userId": user.UserID, "subscriptionId": subscriptionId})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if sub == nil {
+		sendErrorWithCodeResponse(w, r.URL.String(), "subscription not found", http.StatusNotFound)
+		return
+	}
+
+	threshold := req.EventThreshold
+	if !getUserPremium(r).NotificationThresholds {
+		threshold = freeSubscriptionThreshold(sub.EventName, threshold)
+	}
+
+	found, err := db.UpdateUserNotificationSubscription(user.UserID, subscriptionId, threshold, channels)
+	if err != nil {
+		utils.LogError(err, "error updating notification subscription", 0, map[string]interface{}{"userId": user.UserID, "subscriptionId": subscriptionId})
+		SendBadRequestResponse(w, r.URL.String(), "could not save subscription")
+		return
+	}
+	if !found {
+		sendErrorWithCodeResponse(w, r.URL.String(), "subscription not found", http.StatusNotFound)
+		return
+	}
+
+	sub, err = db.GetUserNotificationSubscription(user.UserID, subscriptionId)
+	if err != nil || sub == nil {
+		utils.LogError(err, "error retrieving updated notification subscription", 0, map[string]interface{}{"userId": user.UserID, "subscriptionId": subscriptionId})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{sub})
+}
+
+// UserNotificationSubscriptionDelete godoc
+// @Summary Delete a notification subscription of the authenticated user
+// @Tags User
+// @Produce json
+// @Param subscriptionId path integer true "The id of the subscription"
+// @Success 200 {object} types.ApiResponse
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/notifications/subscriptions/{subscriptionId} [delete]
+func UserNotificationSubscriptionDelete(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	subscriptionId, err := parseUserNotificationSubscriptionId(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid subscription id provided")
+		return
+	}
+
+	found, err := db.DeleteUserNotificationSubscription(user.UserID, subscriptionId)
+	if err != nil {
+		utils.LogError(err, "error deleting notification subscription", 0, map[string]interface{}{"userId": user.UserID, "subscriptionId": subscriptionId})
+		SendBadRequestResponse(w, r.URL.String(), "could not delete subscription")
+		return
+	}
+	if !found {
+		sendErrorWithCodeResponse(w, r.URL.String(), "subscription not found", http.StatusNotFound)
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), nil)
+}
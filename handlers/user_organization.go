@@ -0,0 +1,490 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/mail"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/csrf"
+	"github.com/gorilla/mux"
+)
+
+// maxOrganizationSeats is the maximum number of members and pending invites of an organization
+const maxOrganizationSeats = 50
+const maxOrganizationNameLength = 100
+
+func redirectToOrganization(w http.ResponseWriter, r *http.Request, flash string) {
+	if flash != "" {
+		utils.SetFlash(w, r, authSessionName, flash)
+	}
+	http.Redirect(w, r, "/user/organization", http.StatusSeeOther)
+}
+
+// getUserOrganizationWithRole returns the organization of the user if the user has one of the given roles in it. If
+// nil is returned the user has already been redirected with an error flash.
+func getUserOrganizationWithRole(w http.ResponseWriter, r *http.Request, userId uint64, roles ...types.OrganizationRole) *types.Organization {
+	organization, err := db.GetUserOrganization(userId)
+	if err != nil {
+		utils.LogError(err, "error retrieving organization", 0, map[string]interface{}{"userId": userId})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return nil
+	}
+	if organization == nil {
+		redirectToOrganization(w, r, "Error: You are not part of an organization.")
+		return nil
+	}
+	for _, role := range roles {
+		if organization.Role == role {
+			return organization
+		}
+	}
+	redirectToOrganization(w, r, "Error: Your role in the organization does not allow this action.")
+	return nil
+}
+
+// parseOrganizationRole returns the role of the form, only the admin and viewer roles can be assigned
+func parseOrganizationRole(r *http.Request) (types.OrganizationRole, bool) {
+	role := types.OrganizationRole(r.FormValue("role"))
+	return role, role == types.OrganizationRoleAdmin || role == types.OrganizationRoleViewer
+}
+
+// UserOrganization renders the organization of the user with its members, invites and shared webhooks, or the pending
+// invites of the user if the user is not part of an organization yet
+func UserOrganization(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "user/organization.html")
+	var organizationTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	user := getUser(r)
+	pageData := &types.UserOrganizationPageData{
+		UserID:    user.UserID,
+		Flashes:   utils.GetFlashes(w, r, authSessionName),
+		CsrfField: csrf.TemplateField(r),
+	}
+
+	organization, err := db.GetUserOrganization(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving organization", 0, map[string]interface{}{"userId": user.UserID})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if organization == nil {
+		email, err := db.GetUserEmailById(user.UserID)
+		if err != nil {
+			utils.LogError(err, "error retrieving user email", 0, map[string]interface{}{"userId": user.UserID})
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		pageData.PendingInvites, err = db.GetOrganizationInvitesByEmail(email)
+		if err != nil {
+			utils.LogError(err, "error retrieving organization invites", 0, map[string]interface{}{"userId": user.UserID})
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	} else {
+		pageData.Organization = organization
+		pageData.Members, err = db.GetOrganizationMembers(organization.ID)
+		if err != nil {
+			utils.LogError(err, "error retrieving organization members", 0, map[string]interface{}{"organizationId": organization.ID})
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if organization.Role.CanManage() {
+			pageData.Invites, err = db.GetOrganizationInvites(organization.ID)
+			if err != nil {
+				utils.LogError(err, "error retrieving organization invites", 0, map[string]interface{}{"organizationId": organization.ID})
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+		}
+		pageData.Webhooks, err = db.GetOrganizationWebhooks(organization.ID, user.UserID)
+		if err != nil {
+			utils.LogError(err, "error retrieving organization webhooks", 0, map[string]interface{}{"organizationId": organization.ID})
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	data := InitPageData(w, r, "user", "/user/organization", "Organization", templateFiles)
+	data.Data = pageData
+	data.User = user
+
+	if handleTemplateError(w, r, "user_organization.go", "UserOrganization", "", organizationTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// UserOrganizationCreatePost creates an organization owned by the user, the api quota of the user is then shared with
+// the members
+func UserOrganizationCreatePost(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" || len(name) > maxOrganizationNameLength {
+		redirectToOrganization(w, r, fmt.Sprintf("Error: The name of the organization must be between 1 and %d characters.", maxOrganizationNameLength))
+		return
+	}
+
+	organization, err := db.GetUserOrganization(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving organization", 0, map[string]interface{}{"userId": user.UserID})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return
+	}
+	if organization != nil {
+		redirectToOrganization(w, r, "Error: You are already part of an organization.")
+		return
+	}
+
+	organizationId, err := db.CreateOrganization(user.UserID, name)
+	if err != nil {
+		utils.LogError(err, "error creating organization", 0, map[string]interface{}{"userId": user.UserID})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return
+	}
+	auditUserAction(r, user.UserID, types.UserAuditOrganizationCreated, types.UserAuditDetails{"organization_id": organizationId, "name": name})
+
+	redirectToOrganization(w, r, "The organization has been created.")
+}
+
+// UserOrganizationDeletePost deletes the organization of the user, only the owner can delete it
+func UserOrganizationDeletePost(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+	organization := getUserOrganizationWithRole(w, r, user.UserID, types.OrganizationRoleOwner)
+	if organization == nil {
+		return
+	}
+
+	members, err := db.GetOrganizationMembers(organization.ID)
+	if err != nil {
+		utils.LogError(err, "error retrieving organization members", 0, map[string]interface{}{"organizationId": organization.ID})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return
+	}
+
+	err = db.DeleteOrganization(organization.ID)
+	if err != nil {
+		utils.LogError(err, "error deleting organization", 0, map[string]interface{}{"organizationId": organization.ID})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return
+	}
+	for _, member := range members {
+		auditUserAction(r, member.UserID, types.UserAuditOrganizationDeleted, types.UserAuditDetails{"organization_id": organization.ID, "by_user_id": user.UserID})
+	}
+
+	redirectToOrganization(w, r, "The organization has been deleted.")
+}
+
+// UserOrganizationLeavePost removes the user from the organization, the owner has to delete the organization instead
+func UserOrganizationLeavePost(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+	organization := getUserOrganizationWithRole(w, r, user.UserID, types.OrganizationRoleAdmin, types.OrganizationRoleViewer)
+	if organization == nil {
+		return
+	}
+
+	_, err := db.RemoveOrganizationMember(organization.ID, user.UserID)
+	if err != nil {
+		utils.LogError(err, "error leaving organization", 0, map[string]interface{}{"userId": user.UserID, "organizationId": organization.ID})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return
+	}
+	auditUserAction(r, user.UserID, types.UserAuditOrganizationLeft, types.UserAuditDetails{"organization_id": organization.ID})
+
+	redirectToOrganization(w, r, "You have left the organization.")
+}
+
+// UserOrganizationInvitePost invites an email to the organization of the user, only the owner can invite admins
+func UserOrganizationInvitePost(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+	organization := getUserOrganizationWithRole(w, r, user.UserID, types.OrganizationRoleOwner, types.OrganizationRoleAdmin)
+	if organization == nil {
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(r.FormValue("email")))
+	if !utils.IsValidEmail(email) {
+		redirectToOrganization(w, r, "Error: Invalid email address.")
+		return
+	}
+	role, ok := parseOrganizationRole(r)
+	if !ok {
+		redirectToOrganization(w, r, "Error: Invalid role.")
+		return
+	}
+	if role == types.OrganizationRoleAdmin && organization.Role != types.OrganizationRoleOwner {
+		redirectToOrganization(w, r, "Error: Only the owner can invite admins.")
+		return
+	}
+
+	seats, err := db.CountOrganizationSeats(organization.ID)
+	if err != nil {
+		utils.LogError(err, "error counting organization members", 0, map[string]interface{}{"organizationId": organization.ID})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return
+	}
+	if seats >= maxOrganizationSeats {
+		redirectToOrganization(w, r, fmt.Sprintf("Error: An organization can have at most %d members and pending invites.", maxOrganizationSeats))
+		return
+	}
+
+	err = db.AddOrganizationInvite(organization.ID, email, role, user.UserID)
+	if err != nil {
+		utils.LogError(err, "error adding organization invite", 0, map[string]interface{}{"organizationId": organization.ID})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return
+	}
+	auditUserAction(r, user.UserID, types.UserAuditOrganizationInviteSent, types.UserAuditDetails{"organization_id": organization.ID, "email": email, "role": role})
+
+	err = sendOrganizationInviteEmail(email, organization.Name, role)
+	if err != nil {
+		utils.LogError(err, "error sending organization invite email", 0, map[string]interface{}{"organizationId": organization.ID})
+		redirectToOrganization(w, r, "Error: The invite has been created but the email could not be sent, the invited user can still accept it on the organization page.")
+		return
+	}
+
+	redirectToOrganization(w, r, fmt.Sprintf("An invite has been sent to %s.", email))
+}
+
+func sendOrganizationInviteEmail(email, organizationName string, role types.OrganizationRole) error {
+	subject := fmt.Sprintf("%s: You have been invited to %s", utils.Config.Frontend.SiteDomain, organizationName)
+	msg := fmt.Sprintf(`You have been invited to join the organization %[2]s on %[1]s as %[3]s.
+
+Please log in or sign up with this email address and accept the invite within %[4]d days on:
+
+https://%[1]s/user/organization
+
+Best regards,
+
+%[1]s
+`, utils.Config.Frontend.SiteDomain, organizationName, role, int(db.OrganizationInviteValidity.Hours()/24))
+	return mail.SendTextMail(email, subject, msg, []types.EmailAttachment{})
+}
+
+// UserOrganizationInviteRevokePost revokes a pending invite of the organization of the user
+func UserOrganizationInviteRevokePost(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+	organization := getUserOrganizationWithRole(w, r, user.UserID, types.OrganizationRoleOwner, types.OrganizationRoleAdmin)
+	if organization == nil {
+		return
+	}
+
+	inviteId, err := strconv.ParseUint(mux.Vars(r)["inviteId"], 10, 64)
+	if err != nil {
+		redirectToOrganization(w, r, "Error: Invalid invite.")
+		return
+	}
+
+	found, err := db.DeleteOrganizationInvite(organization.ID, inviteId)
+	if err != nil {
+		utils.LogError(err, "error revoking organization invite", 0, map[string]interface{}{"organizationId": organization.ID, "inviteId": inviteId})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return
+	}
+	if !found {
+		redirectToOrganization(w, r, "Error: The invite does not exist.")
+		return
+	}
+	auditUserAction(r, user.UserID, types.UserAuditOrganizationInviteRevoked, types.UserAuditDetails{"organization_id": organization.ID, "invite_id": inviteId})
+
+	redirectToOrganization(w, r, "The invite has been revoked.")
+}
+
+// UserOrganizationInviteAcceptPost adds the user to the organization of an invite to the email of the user
+func UserOrganizationInviteAcceptPost(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+
+	inviteId, err := strconv.ParseUint(mux.Vars(r)["inviteId"], 10, 64)
+	if err != nil {
+		redirectToOrganization(w, r, "Error: Invalid invite.")
+		return
+	}
+
+	organization, err := db.GetUserOrganization(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving organization", 0, map[string]interface{}{"userId": user.UserID})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return
+	}
+	if organization != nil {
+		redirectToOrganization(w, r, "Error: You are already part of an organization, please leave it first.")
+		return
+	}
+
+	email, err := db.GetUserEmailById(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving user email", 0, map[string]interface{}{"userId": user.UserID})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return
+	}
+	invite, err := db.AcceptOrganizationInvite(user.UserID, email, inviteId)
+	if err != nil {
+		utils.LogError(err, "error accepting organization invite", 0, map[string]interface{}{"userId": user.UserID, "inviteId": inviteId})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return
+	}
+	if invite == nil {
+		redirectToOrganization(w, r, "Error: The invite does not exist or has expired.")
+		return
+	}
+	auditUserAction(r, user.UserID, types.UserAuditOrganizationJoined, types.UserAuditDetails{"organization_id": invite.OrganizationID, "role": invite.Role})
+
+	redirectToOrganization(w, r, fmt.Sprintf("You have joined %s.", invite.OrganizationName))
+}
+
+// UserOrganizationInviteDeclinePost deletes an invite to the email of the user
+func UserOrganizationInviteDeclinePost(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+
+	inviteId, err := strconv.ParseUint(mux.Vars(r)["inviteId"], 10, 64)
+	if err != nil {
+		redirectToOrganization(w, r, "Error: Invalid invite.")
+		return
+	}
+
+	email, err := db.GetUserEmailById(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving user email", 0, map[string]interface{}{"userId": user.UserID})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return
+	}
+	_, err = db.DeclineOrganizationInvite(email, inviteId)
+	if err != nil {
+		utils.LogError(err, "error declining organization invite", 0, map[string]interface{}{"userId": user.UserID, "inviteId": inviteId})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return
+	}
+
+	redirectToOrganization(w, r, "The invite has been declined.")
+}
+
+// getManagedOrganizationMember returns the member of the request if the user may manage it: the owner manages all
+// other members, admins only manage viewers. If nil is returned the user has already been redirected with an error flash.
+func getManagedOrganizationMember(w http.ResponseWriter, r *http.Request, userId uint64, organization *types.Organization) *types.OrganizationMember {
+	memberId, err := strconv.ParseUint(mux.Vars(r)["memberId"], 10, 64)
+	if err != nil || memberId == userId {
+		redirectToOrganization(w, r, "Error: Invalid member.")
+		return nil
+	}
+	member, err := db.GetOrganizationMember(organization.ID, memberId)
+	if err != nil {
+		utils.LogError(err, "error retrieving organization member", 0, map[string]interface{}{"organizationId": organization.ID, "memberId": memberId})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return nil
+	}
+	if member == nil {
+		redirectToOrganization(w, r, "Error: The member does not exist.")
+		return nil
+	}
+	if member.Role == types.OrganizationRoleOwner || (organization.Role != types.OrganizationRoleOwner && member.Role != types.OrganizationRoleViewer) {
+		redirectToOrganization(w, r, "Error: Your role in the organization does not allow this action.")
+		return nil
+	}
+	return member
+}
+
+// UserOrganizationMemberRolePost changes the role of a member of the organization, only the owner can change roles
+func UserOrganizationMemberRolePost(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+	organization := getUserOrganizationWithRole(w, r, user.UserID, types.OrganizationRoleOwner)
+	if organization == nil {
+		return
+	}
+	member := getManagedOrganizationMember(w, r, user.UserID, organization)
+	if member == nil {
+		return
+	}
+	role, ok := parseOrganizationRole(r)
+	if !ok {
+		redirectToOrganization(w, r, "Error: Invalid role.")
+		return
+	}
+
+	_, err := db.UpdateOrganizationMemberRole(organization.ID, member.UserID, role)
+	if err != nil {
+		utils.LogError(err, "error updating organization member role", 0, map[string]interface{}{"organizationId": organization.ID, "memberId": member.UserID})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return
+	}
+	details := types.UserAuditDetails{"organization_id": organization.ID, "member_id": member.UserID, "email": member.Email, "role": role, "by_user_id": user.UserID}
+	auditUserAction(r, user.UserID, types.UserAuditOrganizationRoleChanged, details)
+	auditUserAction(r, member.UserID, types.UserAuditOrganizationRoleChanged, details)
+
+	redirectToOrganization(w, r, fmt.Sprintf("%s is now %s.", member.Email, role))
+}
+
+// UserOrganizationMemberRemovePost removes a member from the organization
+func UserOrganizationMemberRemovePost(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+	organization := getUserOrganizationWithRole(w, r, user.UserID, types.OrganizationRoleOwner, types.OrganizationRoleAdmin)
+	if organization == nil {
+		return
+	}
+	member := getManagedOrganizationMember(w, r, user.UserID, organization)
+	if member == nil {
+		return
+	}
+
+	_, err := db.RemoveOrganizationMember(organization.ID, member.UserID)
+	if err != nil {
+		utils.LogError(err, "error removing organization member", 0, map[string]interface{}{"organizationId": organization.ID, "memberId": member.UserID})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return
+	}
+	details := types.UserAuditDetails{"organization_id": organization.ID, "member_id": member.UserID, "email": member.Email, "by_user_id": user.UserID}
+	auditUserAction(r, user.UserID, types.UserAuditOrganizationMemberRemoved, details)
+	auditUserAction(r, member.UserID, types.UserAuditOrganizationMemberRemoved, details)
+
+	redirectToOrganization(w, r, fmt.Sprintf("%s has been removed from the organization.", member.Email))
+}
+
+// UserOrganizationWebhookSharePost shares a webhook of the user with the organization or stops sharing it, owners and
+// admins can share webhooks while every member can unshare their own webhooks
+func UserOrganizationWebhookSharePost(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+	organization := getUserOrganizationWithRole(w, r, user.UserID, types.OrganizationRoleOwner, types.OrganizationRoleAdmin, types.OrganizationRoleViewer)
+	if organization == nil {
+		return
+	}
+
+	webhookId, err := strconv.ParseUint(mux.Vars(r)["webhookId"], 10, 64)
+	if err != nil {
+		redirectToOrganization(w, r, "Error: Invalid webhook.")
+		return
+	}
+	shared := r.FormValue("shared") == "true"
+	if shared && !organization.Role.CanManage() {
+		redirectToOrganization(w, r, "Error: Your role in the organization does not allow this action.")
+		return
+	}
+
+	var organizationId *uint64
+	if shared {
+		organizationId = &organization.ID
+	}
+	found, err := db.SetUserWebhookOrganization(user.UserID, webhookId, organizationId)
+	if err != nil {
+		utils.LogError(err, "error sharing webhook with organization", 0, map[string]interface{}{"userId": user.UserID, "webhookId": webhookId})
+		redirectToOrganization(w, r, authInternalServerErrorFlashMsg)
+		return
+	}
+	if !found {
+		redirectToOrganization(w, r, "Error: The webhook does not exist.")
+		return
+	}
+	auditUserAction(r, user.UserID, types.UserAuditOrganizationShareChanged, types.UserAuditDetails{"organization_id": organization.ID, "webhook_id": webhookId, "shared": shared})
+
+	if shared {
+		redirectToOrganization(w, r, "The webhook now receives the notifications of all members.")
+		return
+	}
+	redirectToOrganization(w, r, "The webhook is no longer shared with the organization.")
+}
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/ratelimit"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/csrf"
+	"github.com/gorilla/mux"
+)
+
+const (
+	userSecurityAuditLogLimit = 50
+
+	// sessionStoreRefTokenKey is the value of a session reference in the session store that holds the session token
+	sessionStoreRefTokenKey = "session_token"
+)
+
+// auditUserAction records an action of the user in the audit log, failing to do so does not fail the request
+func auditUserAction(r *http.Request, userId uint64, action types.UserAuditAction, details types.UserAuditDetails) {
+	err := db.AddUserAuditLogEntry(userId, action, ratelimit.GetIP(r), r.UserAgent(), details)
+	if err != nil {
+		utils.LogError(err, "error adding audit log entry", 0, map[string]interface{}{"userId": userId, "action": action})
+	}
+}
+
+// sessionTokenHash returns the hash of a session token, the tokens themselves are never stored in the database
+func sessionTokenHash(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
+}
+
+// sessionStoreRefKey is the key of the session store entry that references the token of a session by its hash, so the
+// session can be removed from the store when it is revoked. The key is keyed with the session secret, so it can not be
+// derived from the hashes in the database, and it contains a colon which session tokens never do.
+func sessionStoreRefKey(tokenHash []byte) string {
+	mac := hmac.New(sha256.New, []byte(utils.Config.Frontend.SessionSecret))
+	mac.Write(tokenHash)
+	return fmt.Sprintf("ref:%x", mac.Sum(nil))
+}
+
+// trackUserSession records the web session of the request after a successful login so it can be listed and ended remotely
+func trackUserSession(r *http.Request, session *utils.CustomSession, userId uint64) {
+	token := session.SCS.Token(r.Context())
+	tokenHash := sessionTokenHash(token)
+	// the reference is encoded like a session, so iterating over all sessions of the store still works
+	expiry := time.Now().Add(session.SCS.Lifetime)
+	ref, err := session.SCS.Codec.Encode(expiry, map[string]interface{}{sessionStoreRefTokenKey: token})
+	if err == nil {
+		err = session.SCS.Store.Commit(sessionStoreRefKey(tokenHash), ref, expiry)
+	}
+	if err != nil {
+		utils.LogError(err, "error adding user session reference", 0, map[string]interface{}{"userId": userId})
+		return
+	}
+	err = db.AddUserSession(userId, tokenHash, ratelimit.GetIP(r), r.UserAgent())
+	if err != nil {
+		utils.LogError(err, "error adding user session", 0, map[string]interface{}{"userId": userId})
+	}
+}
+
+// endUserSessionInStore removes the session with the given token hash from the session store
+func endUserSessionInStore(session *utils.CustomSession, tokenHash []byte) error {
+	refKey := sessionStoreRefKey(tokenHash)
+	ref, found, err := session.SCS.Store.Find(refKey)
+	if err != nil {
+		return err
+	}
+	if !found {
+		// the session has already expired
+		return nil
+	}
+	_, values, err := session.SCS.Codec.Decode(ref)
+	if err != nil {
+		return err
+	}
+	if token, ok := values[sessionStoreRefTokenKey].(string); ok {
+		err = session.SCS.Store.Delete(token)
+	}
+	if err != nil {
+		return err
+	}
+	return session.SCS.Store.Delete(refKey)
+}
+
+// UserSecurity renders the security keys, active sessions, paired devices and the recent audit log of the user
+func UserSecurity(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "user/security.html")
+	var securityTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	user, session, err := getUserSession(r)
+	if err != nil {
+		logger.Errorf("error retrieving session: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	sessions, err := db.GetUserSessions(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving user sessions", 0, map[string]interface{}{"userId": user.UserID})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	currentTokenHash := sessionTokenHash(session.SCS.Token(r.Context()))
+	for _, s := range sessions {
+		s.Current = bytes.Equal(s.TokenHash, currentTokenHash)
+	}
+
+	pairedDevices, err := db.GetUserDevicesByUserID(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving paired devices", 0, map[string]interface{}{"userId": user.UserID})
+		pairedDevices = nil
+	}
+
+	auditLog, err := db.GetUserAuditLog(user.UserID, userSecurityAuditLogLimit, 0)
+	if err != nil {
+		utils.LogError(err, "error retrieving audit log", 0, map[string]interface{}{"userId": user.UserID})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	credentials, err := db.GetUserWebAuthnCredentials(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving webauthn credentials", 0, map[string]interface{}{"userId": user.UserID})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	recoveryCodesLeft, err := db.CountUserRecoveryCodes(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error counting recovery codes", 0, map[string]interface{}{"userId": user.UserID})
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	required, err := twoFactorRequired(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error checking two-factor requirement", 0, map[string]interface{}{"userId": user.UserID})
+		required = false
+	}
+
+	data := InitPageData(w, r, "user", "/user/security", "Security", templateFiles)
+	data.Data = &types.UserSecurityPageData{
+		Sessions:          sessions,
+		PairedDevices:     pairedDevices,
+		AuditLog:          auditLog,
+		Credentials:       credentials,
+		RecoveryCodesLeft: recoveryCodesLeft,
+		TwoFactorRequired: required,
+		Flashes:           utils.GetFlashes(w, r, authSessionName),
+		CsrfField:         csrf.TemplateField(r),
+	}
+	data.User = user
+
+	if handleTemplateError(w, r, "user_security.go", "UserSecurity", "", securityTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// UserSessionRevokePost ends another web session of the user, the device of the session is logged out on its next request
+func UserSessionRevokePost(w http.ResponseWriter, r *http.Request) {
+	user, session, err := getUserSession(r)
+	if err != nil {
+		logger.Errorf("error retrieving session: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	sessionId, err := strconv.ParseUint(mux.Vars(r)["sessionId"], 10, 64)
+	if err != nil {
+		utils.SetFlash(w, r, authSessionName, "Error: Invalid session.")
+		http.Redirect(w, r, "/user/security", http.StatusSeeOther)
+		return
+	}
+
+	tokenHash, err := db.EndUserSession(user.UserID, sessionId)
+	if err != nil {
+		utils.LogError(err, "error ending user session", 0, map[string]interface{}{"userId": user.UserID, "sessionId": sessionId})
+		utils.SetFlash(w, r, authSessionName, authInternalServerErrorFlashMsg)
+		http.Redirect(w, r, "/user/security", http.StatusSeeOther)
+		return
+	}
+	if tokenHash == nil {
+		utils.SetFlash(w, r, authSessionName, "Error: The session has already ended.")
+		http.Redirect(w, r, "/user/security", http.StatusSeeOther)
+		return
+	}
+
+	err = endUserSessionInStore(session, tokenHash)
+	if err != nil {
+		utils.LogError(err, "error deleting session from session store", 0, map[string]interface{}{"userId": user.UserID, "sessionId": sessionId})
+		utils.SetFlash(w, r, authSessionName, authInternalServerErrorFlashMsg)
+		http.Redirect(w, r, "/user/security", http.StatusSeeOther)
+		return
+	}
+	auditUserAction(r, user.UserID, types.UserAuditSessionRevoked, types.UserAuditDetails{"session_id": sessionId})
+
+	if bytes.Equal(tokenHash, sessionTokenHash(session.SCS.Token(r.Context()))) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	utils.SetFlash(w, r, authSessionName, "The session has been logged out.")
+	http.Redirect(w, r, "/user/security", http.StatusSeeOther)
+}
+
+// ApiUserAuditLog godoc
+// @Summary Get the audit log of your account
+// @Tags User
+// @Description Returns the logins, session and api key changes, notification setting and webhook changes of your account, newest first.
+// @Produce json
+// @Param limit query int false "Limit the number of results, maximum: 100" default(100)
+// @Param offset query int false "Offset the number of results" default(0)
+// @Success 200 {object} types.ApiResponse{data=[]types.UserAuditLogEntry}
+// @Failure 400 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/audit [get]
+func ApiUserAuditLog(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	claims := getAuthClaims(r)
+	q := r.URL.Query()
+
+	limit := parseUintWithDefault(q.Get("limit"), 100)
+	if limit > 100 {
+		limit = 100
+	}
+	offset := parseUintWithDefault(q.Get("offset"), 0)
+
+	entries, err := db.GetUserAuditLog(claims.UserID, limit, offset)
+	if err != nil {
+		utils.LogError(err, "error retrieving audit log", 0, map[string]interface{}{"userId": claims.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{entries})
+}
@@ -0,0 +1,624 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/csrf"
+	"github.com/gorilla/mux"
+)
+
+// webAuthnTimeout is the time the user has to complete a WebAuthn ceremony or the second factor of a login
+const webAuthnTimeout = time.Minute * 5
+const recoveryCodesCount = 10
+
+// maxTwoFactorAttempts is the number of failed second factors after which the login has to be started over
+const maxTwoFactorAttempts = 5
+
+// webAuthnCredentialResponse is the credential created or the assertion returned by navigator.credentials, binary fields
+// are base64url encoded by static/js/webauthn.js
+type webAuthnCredentialResponse struct {
+	ID                 string `json:"id"`
+	ClientDataJSON     string `json:"client_data_json"`
+	AuthenticatorData  string `json:"authenticator_data"`
+	PublicKey          string `json:"public_key"`
+	PublicKeyAlgorithm int64  `json:"public_key_algorithm"`
+	Signature          string `json:"signature"`
+	Name               string `json:"name"`
+}
+
+// newSessionWebAuthnChallenge creates the challenge of a WebAuthn ceremony and stores it in the session
+func newSessionWebAuthnChallenge(session *utils.CustomSession, key string) (string, error) {
+	challenge, err := utils.NewWebAuthnChallenge()
+	if err != nil {
+		return "", err
+	}
+	session.SetValue(key, challenge)
+	session.SetValue(key+"_ts", time.Now().Unix())
+	return challenge, nil
+}
+
+// consumeSessionWebAuthnChallenge removes the challenge from the session and returns it, returns an empty string if the
+// challenge does not exist or has expired. Every challenge can only be used once.
+func consumeSessionWebAuthnChallenge(session *utils.CustomSession, key string) string {
+	challenge, _ := session.GetValue(key).(string)
+	ts, _ := session.GetValue(key + "_ts").(int64)
+	session.DeleteValue(key)
+	session.DeleteValue(key + "_ts")
+	if time.Since(time.Unix(ts, 0)) > webAuthnTimeout {
+		return ""
+	}
+	return challenge
+}
+
+// twoFactorRequired returns true if the user must use two-factor authentication, which is the case for accounts with
+// a paid api subscription if enabled in the config
+func twoFactorRequired(userId uint64) (bool, error) {
+	if !utils.Config.Frontend.TwoFactorRequiredForPaidApi {
+		return false, nil
+	}
+	subscription, err := db.StripeGetUserSubscription(userId, utils.GROUP_API)
+	if err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+	return subscription.Active != nil && *subscription.Active, nil
+}
+
+// newRecoveryCodes generates and stores new recovery codes of the user and returns them, the codes are only shown once
+func newRecoveryCodes(userId uint64) ([]string, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]string, 0, recoveryCodesCount)
+	for i := 0; i < recoveryCodesCount; i++ {
+		b, err := utils.GenerateRandomBytesSecure(5)
+		if err != nil {
+			return nil, err
+		}
+		code := hex.EncodeToString(b)
+		code = code[:5] + "-" + code[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, utils.HashAndEncode(code))
+	}
+	err := db.ReplaceUserRecoveryCodes(userId, hashes)
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// verifyWebAuthnAssertion verifies a login assertion against the challenge of the session and returns its credential.
+// If userId is not 0 the credential must belong to that user.
+func verifyWebAuthnAssertion(session *utils.CustomSession, assertion string, userId uint64, requireUserVerification bool) (*types.UserWebAuthnCredential, error) {
+	challenge := consumeSessionWebAuthnChallenge(session, "webauthn_login_challenge")
+	if challenge == "" {
+		return nil, errors.New("challenge expired")
+	}
+
+	res := webAuthnCredentialResponse{}
+	err := json.Unmarshal([]byte(assertion), &res)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing assertion: %w", err)
+	}
+	credentialId, err := utils.DecodeWebAuthnBase64(res.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding credential id: %w", err)
+	}
+	clientDataJSON, err := utils.DecodeWebAuthnBase64(res.ClientDataJSON)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding client data: %w", err)
+	}
+	authData, err := utils.DecodeWebAuthnBase64(res.AuthenticatorData)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding authenticator data: %w", err)
+	}
+	signature, err := utils.DecodeWebAuthnBase64(res.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding signature: %w", err)
+	}
+
+	credential, err := db.GetWebAuthnCredential(credentialId)
+	if err != nil {
+		return nil, err
+	}
+	if credential == nil || (userId != 0 && credential.UserID != userId) {
+		return nil, errors.New("unknown credential")
+	}
+
+	err = utils.VerifyWebAuthnClientData(clientDataJSON, "webauthn.get", challenge)
+	if err != nil {
+		return nil, err
+	}
+	data, err := utils.ParseWebAuthnAuthenticatorData(authData)
+	if err != nil {
+		return nil, err
+	}
+	if requireUserVerification && !data.UserVerified {
+		return nil, errors.New("user not verified")
+	}
+	err = utils.VerifyWebAuthnSignature(credential.PublicKey, credential.Algorithm, authData, clientDataJSON, signature)
+	if err != nil {
+		return nil, err
+	}
+	// authenticators without a counter always report 0, a counter that does not increase indicates a cloned authenticator
+	if (data.SignCount != 0 || credential.SignCount != 0) && data.SignCount <= credential.SignCount {
+		return nil, fmt.Errorf("sign count %v not greater than %v", data.SignCount, credential.SignCount)
+	}
+
+	err = db.UpdateWebAuthnCredentialUsage(credential.ID, data.SignCount)
+	if err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
+
+// UserWebAuthnRegisterOptions returns the options for navigator.credentials.create to register a new security key or passkey
+func UserWebAuthnRegisterOptions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	user, session, err := getUserSession(r)
+	if err != nil {
+		logger.Errorf("error retrieving session: %v", err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve session")
+		return
+	}
+
+	email, err := db.GetUserEmailById(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving user email", 0, map[string]interface{}{"userId": user.UserID})
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	credentials, err := db.GetUserWebAuthnCredentials(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving webauthn credentials", 0, map[string]interface{}{"userId": user.UserID})
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	challenge, err := newSessionWebAuthnChallenge(session, "webauthn_register_challenge")
+	if err != nil {
+		utils.LogError(err, "error creating webauthn challenge", 0)
+		sendServerErrorResponse(w, r.URL.String(), "could not create challenge")
+		return
+	}
+	session.Save(r, w)
+
+	userHandle := make([]byte, 8)
+	binary.BigEndian.PutUint64(userHandle, user.UserID)
+
+	pubKeyCredParams := []map[string]interface{}{}
+	for _, alg := range utils.WebAuthnAlgorithms {
+		pubKeyCredParams = append(pubKeyCredParams, map[string]interface{}{"type": "public-key", "alg": alg})
+	}
+	excludeCredentials := []map[string]interface{}{}
+	for _, c := range credentials {
+		excludeCredentials = append(excludeCredentials, map[string]interface{}{"type": "public-key", "id": base64.RawURLEncoding.EncodeToString(c.CredentialID)})
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{map[string]interface{}{
+		"challenge": challenge,
+		"rp":        map[string]interface{}{"id": utils.WebAuthnRPID(), "name": utils.Config.Frontend.SiteName},
+		"user": map[string]interface{}{
+			"id":          base64.RawURLEncoding.EncodeToString(userHandle),
+			"name":        email,
+			"displayName": email,
+		},
+		"pubKeyCredParams":   pubKeyCredParams,
+		"excludeCredentials": excludeCredentials,
+		"authenticatorSelection": map[string]interface{}{
+			"residentKey":      "preferred",
+			"userVerification": "preferred",
+		},
+		"attestation": "none",
+		"timeout":     webAuthnTimeout.Milliseconds(),
+	}})
+}
+
+// UserWebAuthnRegisterPost stores the security key or passkey created with the options of UserWebAuthnRegisterOptions.
+// Two-factor authentication is enabled with the first credential, the recovery codes are generated then and returned once.
+func UserWebAuthnRegisterPost(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	user, session, err := getUserSession(r)
+	if err != nil {
+		logger.Errorf("error retrieving session: %v", err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve session")
+		return
+	}
+
+	challenge := consumeSessionWebAuthnChallenge(session, "webauthn_register_challenge")
+	session.Save(r, w)
+	if challenge == "" {
+		SendBadRequestResponse(w, r.URL.String(), "challenge expired, please try again")
+		return
+	}
+
+	res := webAuthnCredentialResponse{}
+	err = json.NewDecoder(http.MaxBytesReader(w, r.Body, 64*1024)).Decode(&res)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid credential")
+		return
+	}
+	credentialId, err := utils.DecodeWebAuthnBase64(res.ID)
+	if err != nil || len(credentialId) == 0 || len(credentialId) > 1023 {
+		SendBadRequestResponse(w, r.URL.String(), "invalid credential id")
+		return
+	}
+	clientDataJSON, err := utils.DecodeWebAuthnBase64(res.ClientDataJSON)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid client data")
+		return
+	}
+	authData, err := utils.DecodeWebAuthnBase64(res.AuthenticatorData)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid authenticator data")
+		return
+	}
+	publicKey, err := utils.DecodeWebAuthnBase64(res.PublicKey)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid public key")
+		return
+	}
+
+	err = utils.VerifyWebAuthnClientData(clientDataJSON, "webauthn.create", challenge)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("invalid client data: %v", err))
+		return
+	}
+	data, err := utils.ParseWebAuthnAuthenticatorData(authData)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("invalid authenticator data: %v", err))
+		return
+	}
+	err = utils.ParseWebAuthnPublicKey(publicKey, res.PublicKeyAlgorithm)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), err.Error())
+		return
+	}
+
+	existing, err := db.GetWebAuthnCredential(credentialId)
+	if err != nil {
+		utils.LogError(err, "error retrieving webauthn credential", 0, map[string]interface{}{"userId": user.UserID})
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if existing != nil {
+		SendBadRequestResponse(w, r.URL.String(), "the security key is already registered")
+		return
+	}
+	hadTwoFactor, err := db.HasUserWebAuthnCredentials(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error checking webauthn credentials", 0, map[string]interface{}{"userId": user.UserID})
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	name := strings.TrimSpace(res.Name)
+	if name == "" {
+		name = "Security key"
+	}
+	err = db.AddUserWebAuthnCredential(user.UserID, credentialId, publicKey, res.PublicKeyAlgorithm, data.SignCount, name)
+	if err != nil {
+		utils.LogError(err, "error adding webauthn credential", 0, map[string]interface{}{"userId": user.UserID})
+		sendServerErrorResponse(w, r.URL.String(), "could not save security key")
+		return
+	}
+	auditUserAction(r, user.UserID, types.UserAuditSecurityKeyAdded, types.UserAuditDetails{"name": name})
+
+	response := struct {
+		RecoveryCodes []string `json:"recovery_codes,omitempty"`
+	}{}
+	if !hadTwoFactor {
+		response.RecoveryCodes, err = newRecoveryCodes(user.UserID)
+		if err != nil {
+			utils.LogError(err, "error creating recovery codes", 0, map[string]interface{}{"userId": user.UserID})
+			sendServerErrorResponse(w, r.URL.String(), "could not create recovery codes")
+			return
+		}
+		auditUserAction(r, user.UserID, types.UserAuditRecoveryCodesGenerated, nil)
+	}
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{response})
+}
+
+// UserWebAuthnCredentialDeletePost removes a security key or passkey, removing the last one disables two-factor authentication
+func UserWebAuthnCredentialDeletePost(w http.ResponseWriter, r *http.Request) {
+	user := getUser(r)
+
+	credentialId, err := strconv.ParseUint(mux.Vars(r)["credentialId"], 10, 64)
+	if err != nil {
+		utils.SetFlash(w, r, authSessionName, "Error: Invalid security key.")
+		http.Redirect(w, r, "/user/security", http.StatusSeeOther)
+		return
+	}
+
+	credentials, err := db.GetUserWebAuthnCredentials(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error retrieving webauthn credentials", 0, map[string]interface{}{"userId": user.UserID})
+		utils.SetFlash(w, r, authSessionName, authInternalServerErrorFlashMsg)
+		http.Redirect(w, r, "/user/security", http.StatusSeeOther)
+		return
+	}
+	if len(credentials) == 1 && credentials[0].ID == credentialId {
+		required, err := twoFactorRequired(user.UserID)
+		if err != nil {
+			utils.LogError(err, "error checking two-factor requirement", 0, map[string]interface{}{"userId": user.UserID})
+			utils.SetFlash(w, r, authSessionName, authInternalServerErrorFlashMsg)
+			http.Redirect(w, r, "/user/security", http.StatusSeeOther)
+			return
+		}
+		if required {
+			utils.SetFlash(w, r, authSessionName, "Error: Two-factor authentication is required for accounts with a paid API plan, please add another security key before removing this one.")
+			http.Redirect(w, r, "/user/security", http.StatusSeeOther)
+			return
+		}
+	}
+
+	found, err := db.DeleteUserWebAuthnCredential(user.UserID, credentialId)
+	if err != nil {
+		utils.LogError(err, "error deleting webauthn credential", 0, map[string]interface{}{"userId": user.UserID, "credentialId": credentialId})
+		utils.SetFlash(w, r, authSessionName, authInternalServerErrorFlashMsg)
+		http.Redirect(w, r, "/user/security", http.StatusSeeOther)
+		return
+	}
+	if !found {
+		utils.SetFlash(w, r, authSessionName, "Error: Security key not found.")
+		http.Redirect(w, r, "/user/security", http.StatusSeeOther)
+		return
+	}
+	auditUserAction(r, user.UserID, types.UserAuditSecurityKeyRemoved, types.UserAuditDetails{"credential_id": credentialId})
+
+	utils.SetFlash(w, r, authSessionName, "The security key has been removed.")
+	http.Redirect(w, r, "/user/security", http.StatusSeeOther)
+}
+
+// UserRecoveryCodesPost replaces the recovery codes of the user and returns the new ones
+func UserRecoveryCodesPost(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	user := getUser(r)
+
+	hasTwoFactor, err := db.HasUserWebAuthnCredentials(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error checking webauthn credentials", 0, map[string]interface{}{"userId": user.UserID})
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if !hasTwoFactor {
+		SendBadRequestResponse(w, r.URL.String(), "two-factor authentication is not enabled")
+		return
+	}
+
+	codes, err := newRecoveryCodes(user.UserID)
+	if err != nil {
+		utils.LogError(err, "error creating recovery codes", 0, map[string]interface{}{"userId": user.UserID})
+		sendServerErrorResponse(w, r.URL.String(), "could not create recovery codes")
+		return
+	}
+	auditUserAction(r, user.UserID, types.UserAuditRecoveryCodesGenerated, nil)
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}{codes}})
+}
+
+// getPendingTwoFactorLogin returns the user whose password has been verified by LoginPost and who still has to provide
+// the second factor, returns 0 if there is no such login or it has expired
+func getPendingTwoFactorLogin(session *utils.CustomSession) (uint64, string) {
+	userId, _ := session.GetValue("2fa_user_id").(uint64)
+	redirectParam, _ := session.GetValue("2fa_redirect").(string)
+	ts, _ := session.GetValue("2fa_ts").(int64)
+	if userId == 0 || time.Since(time.Unix(ts, 0)) > webAuthnTimeout {
+		return 0, ""
+	}
+	return userId, redirectParam
+}
+
+// LoginWebAuthnOptions returns the options for navigator.credentials.get, for the second factor of a pending login or
+// for a passwordless login with a passkey
+func LoginWebAuthnOptions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	session, err := utils.SessionStore.Get(r, authSessionName)
+	if err != nil {
+		logger.Errorf("error retrieving session: %v", err)
+		sendServerErrorResponse(w, r.URL.String(), "could not retrieve session")
+		return
+	}
+
+	allowCredentials := []map[string]interface{}{}
+	userVerification := "required"
+	if userId, _ := getPendingTwoFactorLogin(session); userId != 0 {
+		credentials, err := db.GetUserWebAuthnCredentials(userId)
+		if err != nil {
+			utils.LogError(err, "error retrieving webauthn credentials", 0, map[string]interface{}{"userId": userId})
+			sendServerErrorResponse(w, r.URL.String(), "could not retrieve db results")
+			return
+		}
+		for _, c := range credentials {
+			allowCredentials = append(allowCredentials, map[string]interface{}{"type": "public-key", "id": base64.RawURLEncoding.EncodeToString(c.CredentialID)})
+		}
+		// the password has already been verified, the presence of the security key is sufficient
+		userVerification = "discouraged"
+	}
+
+	challenge, err := newSessionWebAuthnChallenge(session, "webauthn_login_challenge")
+	if err != nil {
+		utils.LogError(err, "error creating webauthn challenge", 0)
+		sendServerErrorResponse(w, r.URL.String(), "could not create challenge")
+		return
+	}
+	session.Save(r, w)
+
+	SendOKResponse(json.NewEncoder(w), r.URL.String(), []interface{}{map[string]interface{}{
+		"challenge":        challenge,
+		"rpId":             utils.WebAuthnRPID(),
+		"allowCredentials": allowCredentials,
+		"userVerification": userVerification,
+		"timeout":          webAuthnTimeout.Milliseconds(),
+	}})
+}
+
+// Login2FA renders the second step of the login of users with two-factor authentication
+func Login2FA(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "login2fa.html")
+	var login2faTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	session, err := utils.SessionStore.Get(r, authSessionName)
+	if err != nil {
+		logger.Errorf("error retrieving session: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if userId, _ := getPendingTwoFactorLogin(session); userId == 0 {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	data := InitPageData(w, r, "login", "/login/2fa", "Two-factor authentication", templateFiles)
+	data.Data = types.AuthData{
+		Flashes:   utils.GetFlashes(w, r, authSessionName),
+		CsrfField: csrf.TemplateField(r),
+	}
+	data.Meta.NoTrack = true
+
+	if handleTemplateError(w, r, "user_webauthn.go", "Login2FA", "", login2faTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// Login2FAPost completes the login of users with two-factor authentication with a security key assertion or a recovery code
+func Login2FAPost(w http.ResponseWriter, r *http.Request) {
+	session, err := utils.SessionStore.Get(r, authSessionName)
+	if err != nil {
+		logger.Errorf("error retrieving session: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	userId, redirectParam := getPendingTwoFactorLogin(session)
+	if userId == 0 {
+		session.AddFlash("Error: Your login has expired, please try again.")
+		session.Save(r, w)
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if code := strings.ToLower(strings.TrimSpace(r.FormValue("recovery_code"))); code != "" {
+		used, err := db.UseUserRecoveryCode(userId, utils.HashAndEncode(code))
+		if err != nil {
+			utils.LogError(err, "error using recovery code", 0, map[string]interface{}{"userId": userId})
+			session.AddFlash(authInternalServerErrorFlashMsg)
+			session.Save(r, w)
+			http.Redirect(w, r, "/login/2fa", http.StatusSeeOther)
+			return
+		}
+		if !used {
+			auditUserAction(r, userId, types.UserAuditLoginFailed, types.UserAuditDetails{"factor": "recovery_code"})
+			failTwoFactorLogin(w, r, session, "Error: Invalid recovery code!")
+			return
+		}
+		auditUserAction(r, userId, types.UserAuditRecoveryCodeUsed, nil)
+	} else {
+		_, err := verifyWebAuthnAssertion(session, r.FormValue("assertion"), userId, false)
+		if err != nil {
+			logger.Warnf("error verifying webauthn assertion of user %v: %v", userId, err)
+			auditUserAction(r, userId, types.UserAuditLoginFailed, types.UserAuditDetails{"factor": "security_key"})
+			failTwoFactorLogin(w, r, session, "Error: The security key could not be verified, please try again.")
+			return
+		}
+	}
+
+	completeWebAuthnLogin(w, r, session, userId, redirectParam)
+}
+
+// failTwoFactorLogin lets the user retry the second factor until maxTwoFactorAttempts is reached, then the login has to
+// be started over with the password
+func failTwoFactorLogin(w http.ResponseWriter, r *http.Request, session *utils.CustomSession, flash string) {
+	attempts, _ := session.GetValue("2fa_attempts").(int)
+	attempts++
+	if attempts >= maxTwoFactorAttempts {
+		session.DeleteValue("2fa_user_id")
+		session.DeleteValue("2fa_redirect")
+		session.DeleteValue("2fa_ts")
+		session.DeleteValue("2fa_attempts")
+		session.AddFlash("Error: Too many failed attempts, please sign in again.")
+		session.Save(r, w)
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	session.SetValue("2fa_attempts", attempts)
+	session.AddFlash(flash)
+	session.Save(r, w)
+	http.Redirect(w, r, "/login/2fa", http.StatusSeeOther)
+}
+
+// LoginPasskeyPost handles the passwordless login with a passkey
+func LoginPasskeyPost(w http.ResponseWriter, r *http.Request) {
+	session, err := utils.SessionStore.Get(r, authSessionName)
+	if err != nil {
+		logger.Errorf("error retrieving session: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	redirectParam := loginRedirectParam(r)
+
+	credential, err := verifyWebAuthnAssertion(session, r.FormValue("assertion"), 0, true)
+	if err != nil {
+		logger.Warnf("error verifying passkey assertion: %v", err)
+		session.AddFlash("Error: The passkey could not be verified, please try again or sign in with your password.")
+		session.Save(r, w)
+		http.Redirect(w, r, "/login"+redirectParam, http.StatusSeeOther)
+		return
+	}
+
+	completeWebAuthnLogin(w, r, session, credential.UserID, redirectParam)
+}
+
+// completeWebAuthnLogin completes the login of the user after a verified passkey or second factor
+func completeWebAuthnLogin(w http.ResponseWriter, r *http.Request, session *utils.CustomSession, userId uint64, redirectParam string) {
+	err := session.SCS.RenewToken(r.Context())
+	if err != nil {
+		logger.Errorf("error renewing session token: %v", err)
+		session.AddFlash(authInternalServerErrorFlashMsg)
+		session.Save(r, w)
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	email, err := db.GetUserEmailById(userId)
+	if err != nil {
+		utils.LogError(err, "error retrieving user email", 0, map[string]interface{}{"userId": userId})
+		session.AddFlash(authInternalServerErrorFlashMsg)
+		session.Save(r, w)
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	user, err := getLoginUser(email)
+	if err != nil {
+		utils.LogError(err, "error retrieving login user", 0, map[string]interface{}{"userId": userId})
+		session.AddFlash(authInternalServerErrorFlashMsg)
+		session.Save(r, w)
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if !user.Confirmed {
+		session.AddFlash("Error: Email has not been confirmed, please click the link in the email we sent you or <a href='/resend'>resend link</a>!")
+		session.Save(r, w)
+		http.Redirect(w, r, "/login"+redirectParam, http.StatusSeeOther)
+		return
+	}
+
+	completeLogin(w, r, session, user, redirectParam)
+}
@@ -9,14 +9,18 @@ import (
 	"html/template"
 	"math"
 	"net/http"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/exporter"
 	"github.com/gobitfly/eth2-beaconchain-explorer/services"
 	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/tracing"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 
@@ -151,7 +155,7 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 
 			// the validator might only have a public key but no index yet
 			var name string
-			err := db.ReaderDb.Get(&name, `SELECT name FROM validator_names WHERE publickey = $1`, pubKey)
+			err := db.ReaderDb.GetContext(r.Context(), &name, `SELECT name FROM validator_names WHERE publickey = $1`, pubKey)
 			if err != nil && err != sql.ErrNoRows {
 				utils.LogError(err, "error getting validator-name from db for pubKey", 0, errFields)
 				validatorNotFound(data, w, r, vars, "")
@@ -162,7 +166,7 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 			}
 
 			var pool string
-			err = db.ReaderDb.Get(&pool, `SELECT pool FROM validator_pool WHERE publickey = $1`, pubKey)
+			err = db.ReaderDb.GetContext(r.Context(), &pool, `SELECT pool FROM validator_pool WHERE publickey = $1`, pubKey)
 			if err != nil && err != sql.ErrNoRows {
 				utils.LogError(err, "error getting validator-pool from db for pubkey", 0, errFields)
 				validatorNotFound(data, w, r, vars, "")
@@ -276,11 +280,12 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 
 	errFields["index"] = index
 
-	SetPageDataTitle(data, fmt.Sprintf("Validator %v", index))
+	SetPageDataTitle(data, utils.Tr(data.Lang, "validator.title", index))
 	data.Meta.Path = fmt.Sprintf("/validator/%v", index)
+	data.Meta.Image = fmt.Sprintf("/img/og/validator/%v.png", index)
 
 	// we use MAX(validatorindex)+1 instead of COUNT(*) for querying the rank_count for performance-reasons
-	err = db.ReaderDb.Get(&validatorPageData, `
+	err = db.ReaderDb.GetContext(r.Context(), &validatorPageData, `
 		SELECT
 			validators.pubkey,
 			validators.validatorindex,
@@ -378,6 +383,9 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 	}
 	g := errgroup.Group{}
 	g.Go(func() error {
+		_, span := tracing.StartSpan(r.Context(), "validator.incomeHistoryChart")
+		defer span.End()
+
 		start := time.Now()
 		defer func() {
 			timings.Charts = time.Since(start)
@@ -397,6 +405,9 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 	})
 
 	g.Go(func() error {
+		_, span := tracing.StartSpan(r.Context(), "validator.executionIncomeHistory")
+		defer span.End()
+
 		start := time.Now()
 		defer func() {
 			timings.Charts = time.Since(start)
@@ -412,6 +423,9 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 	})
 
 	g.Go(func() error {
+		_, span := tracing.StartSpan(r.Context(), "validator.earnings")
+		defer span.End()
+
 		// those functions need to be executed sequentially as both require the CurrentBalance value
 		start := time.Now()
 		defer func() {
@@ -435,9 +449,28 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 			validatorPageData.EffectiveBalance = vbalance.EffectiveBalance
 		}
 
+		// the exit readiness depends on the current balance, it is only relevant as long as the validator has not exited
+		if validatorPageData.ActivationEpoch <= validatorPageData.Epoch && validatorPageData.ExitEpoch > validatorPageData.Epoch {
+			readiness, err := getExitReadiness(&types.Validator{
+				Index:                 validatorPageData.Index,
+				Balance:               validatorPageData.CurrentBalance,
+				EffectiveBalance:      validatorPageData.EffectiveBalance,
+				Slashed:               validatorPageData.Slashed,
+				ActivationEpoch:       validatorPageData.ActivationEpoch,
+				ExitEpoch:             validatorPageData.ExitEpoch,
+				WithdrawableEpoch:     validatorPageData.WithdrawableEpoch,
+				WithdrawalCredentials: validatorPageData.WithdrawCredentials,
+			}, validatorPageData.Epoch)
+			if err != nil {
+				return fmt.Errorf("error getting exit readiness for validator for %v route: %w", r.URL.String(), err)
+			}
+			validatorPageData.ExitReadiness = readiness
+		}
+
 		if bytes.Equal(validatorPageData.WithdrawCredentials[:1], []byte{0x01}) {
 			// validators can have 0x01 credentials even before the cappella fork
 			validatorPageData.IsWithdrawableAddress = true
+			validatorPageData.WithdrawalAddressName = ensNameForWithdrawalCredentials(resolveEnsNames(validatorPageData.WithdrawCredentials), validatorPageData.WithdrawCredentials)
 		}
 
 		if validatorPageData.CappellaHasHappened {
@@ -461,55 +494,49 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 			}
 			validatorPageData.BLSChange = blsChange
 
+			credentialChanges, err := db.GetValidatorsBLSChange(validatorSlice)
+			if err != nil {
+				return fmt.Errorf("error getting validator credential changes from db: %w", err)
+			}
+			validatorPageData.CredentialChanges = credentialChanges
+
 			if bytes.Equal(validatorPageData.WithdrawCredentials[:1], []byte{0x00}) && blsChange != nil {
 				// blsChanges are only possible afters cappeala
 				validatorPageData.IsWithdrawableAddress = true
 			}
 
 			// only calculate the expected next withdrawal if the validator is eligible
-			isFullWithdrawal := validatorPageData.CurrentBalance > 0 && validatorPageData.WithdrawableEpoch <= validatorPageData.Epoch
-			isPartialWithdrawal := validatorPageData.EffectiveBalance == utils.Config.Chain.ClConfig.MaxEffectiveBalance && validatorPageData.CurrentBalance > utils.Config.Chain.ClConfig.MaxEffectiveBalance
-			if stats != nil && stats.LatestValidatorWithdrawalIndex != nil && stats.TotalValidatorCount != nil && validatorPageData.IsWithdrawableAddress && (isFullWithdrawal || isPartialWithdrawal) {
-				distance, err := GetWithdrawableCountFromCursor(validatorPageData.Epoch, validatorPageData.Index, *stats.LatestValidatorWithdrawalIndex)
+			if stats != nil && stats.LatestValidatorWithdrawalIndex != nil && stats.TotalValidatorCount != nil && validatorPageData.IsWithdrawableAddress {
+				estimate, err := estimateNextWithdrawal(&types.Validator{
+					Index:                 validatorPageData.Index,
+					Balance:               validatorPageData.CurrentBalance,
+					EffectiveBalance:      validatorPageData.EffectiveBalance,
+					WithdrawableEpoch:     validatorPageData.WithdrawableEpoch,
+					WithdrawalCredentials: validatorPageData.WithdrawCredentials,
+				}, validatorPageData.Epoch, *stats.LatestValidatorWithdrawalIndex, lastWithdrawalsEpoch)
 				if err != nil {
-					return fmt.Errorf("error getting withdrawable validator count from cursor: %w", err)
+					return err
 				}
 
-				timeToWithdrawal := utils.GetTimeToNextWithdrawal(distance)
-
 				// it normally takes two epochs to finalize
-				if timeToWithdrawal.After(utils.EpochToTime(latestEpoch + (latestEpoch - lastFinalizedEpoch))) {
-					address, err := utils.WithdrawalCredentialsToAddress(validatorPageData.WithdrawCredentials)
-					if err != nil {
-						// warning only as "N/A" will be displayed
-						logger.Warn("invalid withdrawal credentials")
-					}
+				if estimate != nil && estimate.Time.After(utils.EpochToTime(latestEpoch+(latestEpoch-lastFinalizedEpoch))) {
+					validatorPageData.NextWithdrawal = estimate
 
 					// create the table data
 					tableData := make([][]interface{}, 0, 1)
 					var withdrawalCredentialsTemplate template.HTML
-					if address != nil {
-						withdrawalCredentialsTemplate = template.HTML(fmt.Sprintf(`<a href="/address/0x%x"><span class="text-muted">%s</span></a>`, address, utils.FormatAddress(address, nil, "", false, false, true)))
+					if estimate.Address != nil {
+						withdrawalCredentialsTemplate = template.HTML(fmt.Sprintf(`<a href="/address/0x%x"><span class="text-muted">%s</span></a>`, estimate.Address, utils.FormatAddress(estimate.Address, nil, resolveEnsNames(estimate.Address)[string(estimate.Address)], false, false, true)))
 					} else {
 						withdrawalCredentialsTemplate = `<span class="text-muted">N/A</span>`
 					}
 
-					var withdrawalAmount uint64
-					if isFullWithdrawal {
-						withdrawalAmount = validatorPageData.CurrentBalance
-					} else {
-						withdrawalAmount = validatorPageData.CurrentBalance - utils.Config.Chain.ClConfig.MaxEffectiveBalance
-					}
-
-					if latestEpoch == lastWithdrawalsEpoch {
-						withdrawalAmount = 0
-					}
 					tableData = append(tableData, []interface{}{
-						template.HTML(fmt.Sprintf(`<span class="text-muted">~ %s</span>`, utils.FormatEpoch(uint64(utils.TimeToEpoch(timeToWithdrawal))))),
-						template.HTML(fmt.Sprintf(`<span class="text-muted">~ %s</span>`, utils.FormatBlockSlot(utils.TimeToSlot(uint64(timeToWithdrawal.Unix()))))),
-						template.HTML(fmt.Sprintf(`<span class="">~ %s</span>`, utils.FormatTimestamp(timeToWithdrawal.Unix()))),
+						template.HTML(fmt.Sprintf(`<span class="text-muted">~ %s</span>`, utils.FormatEpoch(uint64(utils.TimeToEpoch(estimate.Time))))),
+						template.HTML(fmt.Sprintf(`<span class="text-muted">~ %s</span>`, utils.FormatBlockSlot(utils.TimeToSlot(uint64(estimate.Time.Unix()))))),
+						template.HTML(fmt.Sprintf(`<span class="">~ %s</span>`, utils.FormatTimestamp(estimate.Time.Unix()))),
 						withdrawalCredentialsTemplate,
-						template.HTML(fmt.Sprintf(`<span class="text-muted"><span data-toggle="tooltip" title="If the withdrawal were to be processed at this very moment, this amount would be withdrawn"><i class="far ml-1 fa-question-circle" style="margin-left: 0px !important;"></i></span> %s</span>`, utils.FormatClCurrency(withdrawalAmount, currency, 6, true, false, false, true))),
+						template.HTML(fmt.Sprintf(`<span class="text-muted"><span data-toggle="tooltip" title="If the withdrawal were to be processed at this very moment, this amount would be withdrawn"><i class="far ml-1 fa-question-circle" style="margin-left: 0px !important;"></i></span> %s</span>`, utils.FormatClCurrency(estimate.Amount, currency, 6, true, false, false, true))),
 					})
 
 					validatorPageData.NextWithdrawalRow = tableData
@@ -520,6 +547,9 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 	})
 
 	g.Go(func() error {
+		_, span := tracing.StartSpan(r.Context(), "validator.watchlist")
+		defer span.End()
+
 		filter := db.WatchlistFilter{
 			UserId:         data.User.UserID,
 			Validators:     &pq.ByteaArray{validatorPageData.PublicKey},
@@ -538,6 +568,9 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 	})
 
 	g.Go(func() error {
+		_, span := tracing.StartSpan(r.Context(), "validator.deposits")
+		defer span.End()
+
 		start := time.Now()
 		defer func() {
 			timings.Deposits = time.Since(start)
@@ -556,12 +589,28 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
+		origins, err := db.GetValidatorDepositOrigins([][]byte{validatorPageData.PublicKey})
+		if err != nil {
+			return fmt.Errorf("error getting validator deposit origin from db: %w", err)
+		}
+		if len(origins) > 0 {
+			validatorPageData.DepositOrigin = origins[0].Origin
+		}
+
+		validatorPageData.DepositWarnings, err = db.GetEth1DepositWarningsForPublicKeys([][]byte{validatorPageData.PublicKey})
+		if err != nil {
+			return fmt.Errorf("error getting validator deposit warnings from db: %w", err)
+		}
+
 		validatorPageData.ShowMultipleWithdrawalCredentialsWarning = hasMultipleWithdrawalCredentials(validatorPageData.Deposits)
 
 		return nil
 	})
 
 	g.Go(func() error {
+		_, span := tracing.StartSpan(r.Context(), "validator.queue")
+		defer span.End()
+
 		// we only need to get the queue information if we don't have an activation epoch but we have an eligibility epoch
 		if validatorPageData.ActivationEpoch > 100_000_000 && validatorPageData.ActivationEligibilityEpoch < 100_000_000 {
 			queueAhead, err := db.GetQueueAheadOfValidator(validatorPageData.Index)
@@ -582,6 +631,9 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 	})
 
 	g.Go(func() error {
+		_, span := tracing.StartSpan(r.Context(), "validator.attestations")
+		defer span.End()
+
 		// Every validator is scheduled to issue an attestation once per epoch
 		// Hence we can calculate the number of attestations using the current epoch and the activation epoch
 		// Special care needs to be take for exited and pending validators
@@ -618,7 +670,7 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 				MissedAttestations uint64 `db:"missed_attestations"`
 			}{}
 			if lastStatsDay > 0 {
-				err := db.ReaderDb.Get(&attestationStats, "SELECT missed_attestations_total AS missed_attestations FROM validator_stats WHERE validatorindex = $1 AND day = $2", index, lastStatsDay)
+				err := db.ReaderDb.GetContext(r.Context(), &attestationStats, "SELECT missed_attestations_total AS missed_attestations FROM validator_stats WHERE validatorindex = $1 AND day = $2", index, lastStatsDay)
 				if err == sql.ErrNoRows {
 					logger.Warningf("no entry in validator_stats for validator index %v while lastStatsDay = %v", index, lastStatsDay)
 				} else if err != nil {
@@ -649,6 +701,9 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 	})
 
 	g.Go(func() error {
+		_, span := tracing.StartSpan(r.Context(), "validator.slashing")
+		defer span.End()
+
 		var err error
 		if validatorPageData.Slashed {
 			var slashingInfo struct {
@@ -656,7 +711,7 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 				Slasher uint64
 				Reason  string
 			}
-			err = db.ReaderDb.Get(&slashingInfo,
+			err = db.ReaderDb.GetContext(r.Context(), &slashingInfo,
 				`SELECT block_slot AS slot, proposer AS slasher, 'Attestation Violation' AS reason
 					FROM blocks_attesterslashings a1 LEFT JOIN blocks b1 ON b1.slot = a1.block_slot
 					WHERE b1.status = '1' AND $1 = ANY(a1.attestation1_indices) AND $1 = ANY(a1.attestation2_indices)
@@ -674,7 +729,7 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 			validatorPageData.SlashedFor = slashingInfo.Reason
 		}
 
-		err = db.ReaderDb.Get(&validatorPageData.SlashingsCount, `SELECT COALESCE(SUM(attesterslashingscount) + SUM(proposerslashingscount), 0) FROM blocks WHERE blocks.proposer = $1 AND blocks.status = '1'`, index)
+		err = db.ReaderDb.GetContext(r.Context(), &validatorPageData.SlashingsCount, `SELECT COALESCE(SUM(attesterslashingscount) + SUM(proposerslashingscount), 0) FROM blocks WHERE blocks.proposer = $1 AND blocks.status = '1'`, index)
 		if err != nil {
 			return fmt.Errorf("error getting slashings-count: %w", err)
 		}
@@ -682,21 +737,21 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 	})
 
 	g.Go(func() error {
-		eff, err := db.BigtableClient.GetValidatorEffectiveness([]uint64{index}, validatorPageData.Epoch-1)
+		_, span := tracing.StartSpan(r.Context(), "validator.effectiveness")
+		defer span.End()
+
+		scores, err := db.GetLatestValidatorEffectivenessScores([]uint64{index})
 		if err != nil {
-			return fmt.Errorf("error getting validator effectiveness: %w", err)
-		}
-		if len(eff) > 1 {
-			return fmt.Errorf("error getting validator effectiveness: invalid length %v", len(eff))
-		} else if len(eff) == 0 {
-			validatorPageData.AttestationInclusionEffectiveness = 0
-		} else {
-			validatorPageData.AttestationInclusionEffectiveness = eff[0].AttestationEfficiency
+			return fmt.Errorf("error getting validator effectiveness score: %w", err)
 		}
+		validatorPageData.EffectivenessScore = scores[index]
 		return nil
 	})
 
 	g.Go(func() error {
+		_, span := tracing.StartSpan(r.Context(), "validator.syncParticipation")
+		defer span.End()
+
 		validatorPageData.SlotsPerSyncCommittee = utils.SlotsPerSyncCommittee()
 
 		// sync participation
@@ -708,7 +763,7 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 		}
 		allSyncPeriods := actualSyncPeriods
 
-		err := db.ReaderDb.Select(&allSyncPeriods, `
+		err := db.ReaderDb.SelectContext(r.Context(), &allSyncPeriods, `
 		SELECT period, GREATEST(period*$1, $2) AS firstepoch, ((period+1)*$1)-1 AS lastepoch
 		FROM sync_committees 
 		WHERE validatorindex = $3
@@ -733,7 +788,7 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 			// get sync stats from validator_stats
 			syncStats := types.SyncCommitteesStats{}
 			if lastStatsDay > 0 {
-				err = db.ReaderDb.Get(&syncStats, `
+				err = db.ReaderDb.GetContext(r.Context(), &syncStats, `
 					SELECT
 						COALESCE(participated_sync_total, 0) AS participated_sync,
 						COALESCE(missed_sync_total, 0) AS missed_sync,
@@ -794,9 +849,12 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 	})
 
 	g.Go(func() error {
+		_, span := tracing.StartSpan(r.Context(), "validator.rocketpool")
+		defer span.End()
+
 		// add rocketpool-data if available
 		validatorPageData.Rocketpool = &types.RocketpoolValidatorPageData{}
-		err := db.ReaderDb.Get(validatorPageData.Rocketpool, `
+		err := db.ReaderDb.GetContext(r.Context(), validatorPageData.Rocketpool, `
 		SELECT
 			rplm.node_address      					AS node_address,
 			rplm.address           					AS minipool_address,
@@ -840,6 +898,110 @@ func Validator(w http.ResponseWriter, r *http.Request) {
 		return nil
 	})
 
+	g.Go(func() error {
+		_, span := tracing.StartSpan(r.Context(), "validator.dvt")
+		defer span.End()
+
+		dvt, err := db.GetValidatorDvtCluster(validatorPageData.PublicKey)
+		if err != nil {
+			return fmt.Errorf("error getting dvt cluster for validator for %v route: %w", r.URL.String(), err)
+		}
+		validatorPageData.Dvt = dvt
+		return nil
+	})
+
+	g.Go(func() error {
+		_, span := tracing.StartSpan(r.Context(), "validator.relayRegistrations")
+		defer span.End()
+
+		registrations, err := db.GetRelayValidatorRegistrations([][]byte{validatorPageData.PublicKey})
+		if err != nil {
+			return fmt.Errorf("error getting relay registrations for validator for %v route: %w", r.URL.String(), err)
+		}
+		validatorPageData.RelayRegistrations = registrations
+
+		// stale registrations are checked in the background so slow relays do not hold up the page, the page shows the
+		// last known registrations until the check finished
+		stale := len(registrations) == 0
+		for _, registration := range registrations {
+			if time.Since(registration.CheckedTs) > exporter.RelayRegistrationsCheckInterval {
+				stale = true
+			}
+		}
+		if stale && validatorPageData.ExitEpoch > validatorPageData.Epoch {
+			go func(pubkey []byte) {
+				err := exporter.CheckValidatorRelayRegistrations([][]byte{pubkey})
+				if err != nil {
+					utils.LogError(err, "error checking relay registrations of validator", 0, map[string]interface{}{"validator": index})
+				}
+			}(validatorPageData.PublicKey)
+		}
+		return nil
+	})
+
+	// the statistics based data only changes with the daily statistics export, so the cache keys contain the last exported day
+	overviewCacheKey := func(name string) string {
+		return fmt.Sprintf("%d:frontend:validatorOverview:%s:%d:%d", utils.Config.Chain.ClConfig.DepositChainID, name, index, lastStatsDay)
+	}
+
+	g.Go(func() error {
+		_, span := tracing.StartSpan(r.Context(), "validator.percentiles")
+		defer span.End()
+
+		percentiles, err := cache.GetOrLoad(cache.KeyTypeValidatorOverview, overviewCacheKey("percentiles"), func() (*types.ValidatorPercentiles, error) {
+			return db.GetValidatorsPercentiles([]uint64{index}, 7)
+		})
+		if err != nil {
+			return fmt.Errorf("error getting percentiles for validator for %v route: %w", r.URL.String(), err)
+		}
+		validatorPageData.Percentiles = percentiles
+		return nil
+	})
+
+	g.Go(func() error {
+		_, span := tracing.StartSpan(r.Context(), "validator.luck")
+		defer span.End()
+
+		luck, err := cache.GetOrLoad(cache.KeyTypeValidatorOverview, overviewCacheKey("luck"), func() (map[uint64]*types.ApiValidatorLuckResponse, error) {
+			return db.GetValidatorsLuck([]uint64{index}, 365)
+		})
+		if err != nil {
+			return fmt.Errorf("error getting luck for validator for %v route: %w", r.URL.String(), err)
+		}
+		if luck[index] != nil && len(luck[index].History) > 0 {
+			validatorPageData.Luck = luck[index]
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		_, span := tracing.StartSpan(r.Context(), "validator.milestones")
+		defer span.End()
+
+		milestones, err := cache.GetOrLoad(cache.KeyTypeValidatorOverview, overviewCacheKey("milestones"), func() ([]*types.ValidatorMilestone, error) {
+			return db.GetValidatorMilestones(index)
+		})
+		if err != nil {
+			return fmt.Errorf("error getting milestones for validator for %v route: %w", r.URL.String(), err)
+		}
+		validatorPageData.Milestones = milestones
+		return nil
+	})
+
+	g.Go(func() error {
+		_, span := tracing.StartSpan(r.Context(), "validator.mevIncome")
+		defer span.End()
+
+		mevIncome, err := cache.GetOrLoad(cache.KeyTypeValidatorOverview, overviewCacheKey("mevIncome"), func() (map[uint64]*types.ValidatorMevIncome, error) {
+			return db.GetValidatorsMevIncome([]uint64{index})
+		})
+		if err != nil {
+			return fmt.Errorf("error getting mev income for validator for %v route: %w", r.URL.String(), err)
+		}
+		validatorPageData.MevIncome = mevIncome[index]
+		return nil
+	})
+
 	err = g.Wait()
 	if err != nil {
 		utils.LogError(err, "error getting validator data", 0)
@@ -927,7 +1089,7 @@ func ValidatorDeposits(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// ValidatorAttestationInclusionEffectiveness returns a validator's effectiveness in json
+// ValidatorAttestationInclusionEffectiveness returns the effectiveness score of a validator of the latest exported day in json
 func ValidatorAttestationInclusionEffectiveness(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -938,48 +1100,35 @@ func ValidatorAttestationInclusionEffectiveness(w http.ResponseWriter, r *http.R
 		http.Error(w, "Error: Invalid parameter validator index.", http.StatusBadRequest)
 		return
 	}
-	epoch := services.LatestEpoch()
-	if epoch > 0 {
-		epoch = epoch - 1
-	}
 
 	errFields := map[string]interface{}{
 		"route": r.URL.String(),
-		"index": index,
-		"epoch": epoch}
+		"index": index}
 
-	eff, err := db.BigtableClient.GetValidatorEffectiveness([]uint64{index}, epoch)
+	scores, err := db.GetLatestValidatorEffectivenessScores([]uint64{index})
 	if err != nil {
-		utils.LogError(err, "error getting validator effectiveness from bigtable", 0, errFields)
+		utils.LogError(err, "error getting validator effectiveness score", 0, errFields)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
 	type resp struct {
-		Effectiveness float64 `json:"effectiveness"`
+		Effectiveness float64                            `json:"effectiveness"`
+		Components    *types.ValidatorEffectivenessScore `json:"components"`
 	}
 
-	errFields["effectiveness length"] = len(eff)
-	if len(eff) > 1 {
-		utils.LogError(err, "error getting validator effectiveness because of invalid length", 0, errFields)
+	res := resp{}
+	if score := scores[index]; score != nil {
+		res.Effectiveness = score.Score
+		res.Components = score
+	}
+
+	err = json.NewEncoder(w).Encode(res)
+	if err != nil {
+		utils.LogError(err, "error encoding json response", 0, errFields)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
-	} else if len(eff) == 0 {
-		err = json.NewEncoder(w).Encode(resp{Effectiveness: 0})
-		if err != nil {
-			utils.LogError(err, "error encoding json response", 0, errFields)
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-			return
-		}
-	} else {
-		err = json.NewEncoder(w).Encode(resp{Effectiveness: eff[0].AttestationEfficiency})
-		if err != nil {
-			utils.LogError(err, "error encoding json response", 0, errFields)
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-			return
-		}
 	}
-
 }
 
 // ValidatorProposedBlocks returns a validator's proposed blocks in json
@@ -1027,7 +1176,7 @@ func ValidatorProposedBlocks(w http.ResponseWriter, r *http.Request) {
 
 	var totalCount uint64
 
-	err = db.ReaderDb.Get(&totalCount, "SELECT COUNT(*) FROM blocks WHERE proposer = $1", index)
+	err = db.ReaderDb.GetContext(r.Context(), &totalCount, "SELECT COUNT(*) FROM blocks WHERE proposer = $1", index)
 	if err != nil {
 		utils.LogError(err, "error getting proposed blocks count from db", 0, errFields)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -1053,7 +1202,7 @@ func ValidatorProposedBlocks(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var blocks []*types.IndexPageDataBlocks
-	err = db.ReaderDb.Select(&blocks, `
+	err = db.ReaderDb.SelectContext(r.Context(), &blocks, `
 		SELECT 
 			epoch, 
 			slot, 
@@ -1152,7 +1301,7 @@ func ValidatorAttestations(w http.ResponseWriter, r *http.Request) {
 		ExitEpoch       uint64
 	}{}
 
-	err = db.ReaderDb.Get(&ae, "SELECT activationepoch, exitepoch FROM validators WHERE validatorindex = $1", index)
+	err = db.ReaderDb.GetContext(r.Context(), &ae, "SELECT activationepoch, exitepoch FROM validators WHERE validatorindex = $1", index)
 	if err != nil {
 		utils.LogError(err, "error getting attestations count from db", 0, errFields)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -1295,6 +1444,12 @@ func ValidatorWithdrawals(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	addresses := make([][]byte, 0, len(withdrawals))
+	for _, w := range withdrawals {
+		addresses = append(addresses, w.Address)
+	}
+	names := resolveEnsNames(addresses...)
+
 	tableData := make([][]interface{}, 0, len(withdrawals))
 
 	for _, w := range withdrawals {
@@ -1302,7 +1457,7 @@ func ValidatorWithdrawals(w http.ResponseWriter, r *http.Request) {
 			utils.FormatEpoch(utils.EpochOfSlot(w.Slot)),
 			utils.FormatBlockSlot(w.Slot),
 			utils.FormatTimestamp(utils.SlotToTime(w.Slot).Unix()),
-			utils.FormatAddress(w.Address, nil, "", false, false, true),
+			utils.FormatAddress(w.Address, nil, names[string(w.Address)], false, false, true),
 			utils.FormatClCurrency(w.Amount, reqCurrency, 6, true, false, false, true),
 		})
 	}
@@ -1349,7 +1504,7 @@ func ValidatorSlashings(w http.ResponseWriter, r *http.Request) {
 		"draw":  draw}
 
 	var totalCount uint64
-	err = db.ReaderDb.Get(&totalCount, `
+	err = db.ReaderDb.GetContext(r.Context(), &totalCount, `
 		SELECT
 			(
 				SELECT COUNT(*) FROM blocks_attesterslashings a
@@ -1366,7 +1521,7 @@ func ValidatorSlashings(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var attesterSlashings []*types.ValidatorAttestationSlashing
-	err = db.ReaderDb.Select(&attesterSlashings, `
+	err = db.ReaderDb.SelectContext(r.Context(), &attesterSlashings, `
 		SELECT 
 			blocks.slot, 
 			blocks.epoch, 
@@ -1384,7 +1539,7 @@ func ValidatorSlashings(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var proposerSlashings []*types.ValidatorProposerSlashing
-	err = db.ReaderDb.Select(&proposerSlashings, `
+	err = db.ReaderDb.SelectContext(r.Context(), &proposerSlashings, `
 		SELECT blocks.slot, blocks.epoch, blocks.proposer, blocks_proposerslashings.proposerindex 
 		FROM blocks_proposerslashings 
 		INNER JOIN blocks ON blocks.proposer = $1 AND blocks_proposerslashings.block_slot = blocks.slot`, index)
@@ -1466,20 +1621,44 @@ func sanitizeSignature(sig string) ([]byte, error) {
 // If successful it turns string into []byte value and returns it
 //
 // If it fails, it will try to decode `msg`value from Hexadecimal to string and retry search again
-func sanitizeMessage(msg string) ([]byte, error) {
-	subString := "beaconcha.in"
+const (
+	// validatorNameClaimMaxAge is how long a signed name claim can be submitted after it has been signed
+	validatorNameClaimMaxAge = time.Hour
+	// validatorNameClaimMaxClockSkew tolerates clocks of the signers that are ahead of ours
+	validatorNameClaimMaxClockSkew = 5 * time.Minute
+)
 
-	if strings.Contains(msg, subString) {
-		return []byte(msg), nil
-	} else {
-		decoded := strings.Replace(msg, "0x", "", -1)
-		dec, _ := hex.DecodeString(decoded)
-		decodedString := (string(dec))
-		if strings.Contains(decodedString, subString) {
-			return []byte(decodedString), nil
-		}
-		return nil, fmt.Errorf("%v was not found", subString)
+var validatorNameClaimTimestampRE = regexp.MustCompile(`(?m)^Timestamp: (\d+)$`)
+
+// validatorNameClaimMessage returns the message that has to be signed to claim the name of a validator, it is bound to
+// the name, the validator and the time of signing so published signatures can not be replayed for other names
+func validatorNameClaimMessage(name string, pubkey []byte, timestamp int64) string {
+	return fmt.Sprintf("beaconcha.in validator name claim\nName: %s\nValidator: %#x\nTimestamp: %d", name, pubkey, timestamp)
+}
+
+// verifyValidatorNameClaimMessage checks that the signed message, which wallets may hex encode, is the claim message of
+// the name and the validator and that it has been signed recently
+func verifyValidatorNameClaimMessage(msg, name string, pubkey []byte) ([]byte, error) {
+	if dec, err := hex.DecodeString(strings.TrimPrefix(msg, "0x")); err == nil {
+		msg = string(dec)
+	}
+
+	match := validatorNameClaimTimestampRE.FindStringSubmatch(msg)
+	if match == nil {
+		return nil, fmt.Errorf("the message contains no timestamp")
 	}
+	timestamp, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp %v: %w", match[1], err)
+	}
+	if msg != validatorNameClaimMessage(name, pubkey, timestamp) {
+		return nil, fmt.Errorf("the message is not the claim message of the name and the validator")
+	}
+	signedAt := time.Unix(timestamp, 0)
+	if time.Since(signedAt) > validatorNameClaimMaxAge || time.Until(signedAt) > validatorNameClaimMaxClockSkew {
+		return nil, fmt.Errorf("the message has not been signed recently")
+	}
+	return []byte(msg), nil
 }
 
 func SaveValidatorName(w http.ResponseWriter, r *http.Request) {
@@ -1514,10 +1693,10 @@ func SaveValidatorName(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	msg, err := sanitizeMessage(signatureWrapper.Msg)
+	msg, err := verifyValidatorNameClaimMessage(signatureWrapper.Msg, name, pubkeyDecoded)
 	if err != nil {
 		logger.Warnf("Message is invalid %v: %v", signatureWrapper.Msg, err)
-		utils.SetFlash(w, r, validatorEditFlash, "Error: the provided message is invalid")
+		utils.SetFlash(w, r, validatorEditFlash, "Error: the provided message is invalid, please sign the message shown in the form within an hour")
 		http.Redirect(w, r, "/validator/"+pubkey, http.StatusMovedPermanently)
 		return
 	}
@@ -1554,6 +1733,7 @@ func SaveValidatorName(w http.ResponseWriter, r *http.Request) {
 		utils.LogError(err, "error getting validator-deposits from db for signature verification", 0, errFields)
 		utils.SetFlash(w, r, validatorEditFlash, "Error: the provided signature is invalid")
 		http.Redirect(w, r, "/validator/"+pubkey, http.StatusMovedPermanently)
+		return
 	}
 	for _, deposit := range deposits.Eth1Deposits {
 		if deposit.ValidSignature {
@@ -1562,43 +1742,132 @@ func SaveValidatorName(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	var withdrawalCredentials []byte
+	err = db.ReaderDb.GetContext(r.Context(), &withdrawalCredentials, `SELECT withdrawalcredentials FROM validators WHERE pubkey = $1`, pubkeyDecoded)
+	if err != nil && err != sql.ErrNoRows {
+		utils.LogError(err, "error getting validator withdrawal credentials from db for signature verification", 0, errFields)
+		utils.SetFlash(w, r, validatorEditFlash, "Error: the provided signature is invalid")
+		http.Redirect(w, r, "/validator/"+pubkey, http.StatusMovedPermanently)
+		return
+	}
+
+	// the name can be claimed by the address of the first valid deposit or by the execution layer withdrawal address
+	claim := &types.ValidatorNameClaim{
+		Name:          name,
+		SignerAddress: recoveredAddress.Bytes(),
+		Message:       string(msg),
+		Signature:     sig,
+	}
+	var pubkeys [][]byte
 	if strings.EqualFold(depositedAddress, recoveredAddress.Hex()) {
+		claim.ProofType = types.ValidatorNameProofDeposit
 		if applyNameToAll == "on" {
-			res, err := db.WriterDb.Exec(`
-				INSERT INTO validator_names (publickey, name)
-				SELECT publickey, $1 as name
-				FROM (SELECT DISTINCT publickey FROM eth1_deposits WHERE from_address = $2 AND valid_signature) a
-				ON CONFLICT (publickey) DO UPDATE SET name = excluded.name`, name, recoveredAddress.Bytes())
-			if err != nil {
-				utils.LogError(err, "error saving validator name", 0, errFields)
-				utils.SetFlash(w, r, validatorEditFlash, "Error: Db error while updating validator names")
-				http.Redirect(w, r, "/validator/"+pubkey, http.StatusMovedPermanently)
-				return
-			}
-
-			rowsAffected, _ := res.RowsAffected()
-			utils.SetFlash(w, r, validatorEditFlash, fmt.Sprintf("Your custom name has been saved for %v validator(s).", rowsAffected))
-			http.Redirect(w, r, "/validator/"+pubkey, http.StatusMovedPermanently)
-		} else {
-			_, err := db.WriterDb.Exec(`
-				INSERT INTO validator_names (publickey, name) 
-				VALUES($2, $1) 
-				ON CONFLICT (publickey) DO UPDATE SET name = excluded.name`, name, pubkeyDecoded)
-			if err != nil {
-				utils.LogError(err, "error saving validator name", 0, errFields)
-				utils.SetFlash(w, r, validatorEditFlash, "Error: Db error while updating validator name")
-				http.Redirect(w, r, "/validator/"+pubkey, http.StatusMovedPermanently)
-				return
-			}
-
-			utils.SetFlash(w, r, validatorEditFlash, "Your custom name has been saved.")
-			http.Redirect(w, r, "/validator/"+pubkey, http.StatusMovedPermanently)
+			pubkeys, err = db.GetValidatorPubkeysByDepositAddress(recoveredAddress.Bytes())
+		}
+	} else if len(withdrawalCredentials) == 32 && (withdrawalCredentials[0] == 0x01 || withdrawalCredentials[0] == 0x02) && bytes.Equal(withdrawalCredentials[1:12], make([]byte, 11)) && bytes.Equal(withdrawalCredentials[12:], recoveredAddress.Bytes()) {
+		// execution layer (0x01) and compounding (0x02) withdrawal credentials both end with the withdrawal address
+		claim.ProofType = types.ValidatorNameProofWithdrawal
+		if applyNameToAll == "on" {
+			pubkeys, err = db.GetValidatorPubkeysByWithdrawalCredentials(withdrawalCredentials)
 		}
-
 	} else {
 		utils.SetFlash(w, r, validatorEditFlash, "Error: the provided signature is invalid")
 		http.Redirect(w, r, "/validator/"+pubkey, http.StatusMovedPermanently)
+		return
+	}
+	if err != nil {
+		utils.LogError(err, "error getting validators of the signing address", 0, errFields)
+		utils.SetFlash(w, r, validatorEditFlash, "Error: Db error while updating validator names")
+		http.Redirect(w, r, "/validator/"+pubkey, http.StatusMovedPermanently)
+		return
+	}
+	if len(pubkeys) == 0 {
+		pubkeys = [][]byte{pubkeyDecoded}
+	}
+
+	rowsAffected, err := db.SaveValidatorNameClaim(pubkeys, claim)
+	if err != nil {
+		utils.LogError(err, "error saving validator name", 0, errFields)
+		utils.SetFlash(w, r, validatorEditFlash, "Error: Db error while updating validator names")
+		http.Redirect(w, r, "/validator/"+pubkey, http.StatusMovedPermanently)
+		return
+	}
+
+	// the name is only shown once a moderator approved the claim
+	if applyNameToAll == "on" {
+		utils.SetFlash(w, r, validatorEditFlash, utils.Tr(GetLanguage(r), "validator.name_saved", rowsAffected))
+	} else {
+		utils.SetFlash(w, r, validatorEditFlash, "Your custom name has been submitted and will be shown once it has been reviewed.")
+	}
+	http.Redirect(w, r, "/validator/"+pubkey, http.StatusMovedPermanently)
+}
+
+// ApiValidatorName godoc
+// @Summary Get the public name of a validator
+// @Tags Validator
+// @Description Returns the name of a validator and, if the name was claimed with a signed message, the kind of address (deposit or withdrawal) and the address that signed the claim.
+// @Produce  json
+// @Param  index path string true "Validator index"
+// @Success 200 {object} types.ApiResponse{data=types.ApiValidatorNameResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validator/{index}/name [get]
+func ApiValidatorName(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	index, err := strconv.ParseUint(vars["index"], 10, 64)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid validator index provided")
+		return
 	}
+
+	data, err := db.GetValidatorNameByIndex(index)
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving name of validator %v", index)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if data == nil {
+		SendBadRequestResponse(w, r.URL.String(), "validator not found")
+		return
+	}
+
+	j := json.NewEncoder(w)
+	SendOKResponse(j, r.URL.String(), []interface{}{data})
+}
+
+// ApiValidatorEntity godoc
+// @Summary Get the entity a validator is attributed to
+// @Tags Validator
+// @Description Returns the entity (e.g. a staking pool, an exchange or solo staker) a validator is attributed to, together with the source of the attribution (deposit_address, withdrawal_credentials, fee_recipient, tag, stake_pools_stats or solo) and the version of the attribution that last changed it. The entity is empty for unattributed validators.
+// @Produce  json
+// @Param  index path string true "Validator index"
+// @Success 200 {object} types.ApiResponse{data=types.ApiValidatorEntityResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validators/{index}/entity [get]
+func ApiValidatorEntity(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	vars := mux.Vars(r)
+	index, err := strconv.ParseUint(vars["index"], 10, 64)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), "invalid validator index provided")
+		return
+	}
+
+	data, err := db.GetValidatorEntity(index)
+	if err != nil {
+		logger.WithError(err).Errorf("error retrieving entity of validator %v", index)
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	if data == nil {
+		SendBadRequestResponse(w, r.URL.String(), "validator not found")
+		return
+	}
+
+	j := json.NewEncoder(w)
+	SendOKResponse(j, r.URL.String(), []interface{}{data})
 }
 
 // ValidatorHistory returns a validators history in json
@@ -1642,7 +1911,7 @@ func ValidatorHistory(w http.ResponseWriter, r *http.Request) {
 		ActivationEpoch uint64 `db:"activationepoch"`
 		ExitEpoch       uint64 `db:"exitepoch"`
 	}{}
-	err = db.ReaderDb.Get(&activationAndExitEpoch, "SELECT activationepoch, exitepoch FROM validators WHERE validatorindex = $1", index)
+	err = db.ReaderDb.GetContext(r.Context(), &activationAndExitEpoch, "SELECT activationepoch, exitepoch FROM validators WHERE validatorindex = $1", index)
 	if err != nil {
 		utils.LogError(err, "error getting activationAndExitEpoch for validator-history from db", 0, errFields)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -1676,7 +1945,7 @@ func ValidatorHistory(w http.ResponseWriter, r *http.Request) {
 
 		var lastActionDay uint64
 		// let's get the last day where validator had duties which can be after the exit epoch of the validator
-		err = db.ReaderDb.Get(&lastActionDay, `
+		err = db.ReaderDb.GetContext(r.Context(), &lastActionDay, `
 			SELECT COALESCE(MAX(day), 0) 
 			FROM validator_stats 
 			WHERE 
@@ -1917,7 +2186,7 @@ func ValidatorStatsTable(w http.ResponseWriter, r *http.Request) {
 
 	// Check if validator index is available
 	var doesIndexExist bool
-	err = db.ReaderDb.Get(&doesIndexExist, "SELECT EXISTS (SELECT validatorindex FROM validators WHERE validatorindex = $1)", index)
+	err = db.ReaderDb.GetContext(r.Context(), &doesIndexExist, "SELECT EXISTS (SELECT validatorindex FROM validators WHERE validatorindex = $1)", index)
 	if err != nil || !doesIndexExist {
 		if err != nil {
 			utils.LogError(err, "error checking for index in validators table", 0, errFields)
@@ -1926,7 +2195,7 @@ func ValidatorStatsTable(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	SetPageDataTitle(data, fmt.Sprintf("Validator %v Daily Statistics", index))
+	SetPageDataTitle(data, utils.Tr(data.Lang, "validator.title_stats", index))
 	data.Meta.Path = fmt.Sprintf("/validator/%v/stats", index)
 
 	validatorStatsTablePageData := &types.ValidatorStatsTablePageData{
@@ -1934,7 +2203,7 @@ func ValidatorStatsTable(w http.ResponseWriter, r *http.Request) {
 		Rows:           make([]*types.ValidatorStatsTableRow, 0),
 	}
 
-	err = db.ReaderDb.Select(&validatorStatsTablePageData.Rows, `
+	err = db.ReaderDb.SelectContext(r.Context(), &validatorStatsTablePageData.Rows, `
 	SELECT 
 		validatorindex,
 		day,
@@ -2019,7 +2288,7 @@ func ValidatorSync(w http.ResponseWriter, r *http.Request) {
 
 	// retrieve all sync periods for this validator
 	var syncPeriods []uint64 = []uint64{}
-	err = db.ReaderDb.Select(&syncPeriods, `
+	err = db.ReaderDb.SelectContext(r.Context(), &syncPeriods, `
 		SELECT distinct period
 		FROM sync_committees 
 		WHERE validatorindex = $1
@@ -2133,7 +2402,7 @@ func ValidatorSync(w http.ResponseWriter, r *http.Request) {
 	slotsRange := slots[endIndex : startIndex+1]
 
 	missedSlots := []uint64{}
-	err = db.ReaderDb.Select(&missedSlots, `SELECT slot FROM blocks WHERE slot = ANY($1) AND status = '2'`, slotsRange)
+	err = db.ReaderDb.SelectContext(r.Context(), &missedSlots, `SELECT slot FROM blocks WHERE slot = ANY($1) AND status = '2'`, slotsRange)
 	if err != nil {
 		utils.LogError(err, "error getting missed slots data from db", 0, errFields)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
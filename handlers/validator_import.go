@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/lib/pq"
+)
+
+const maxValidatorKeysImportSize = 10 << 20 // 10 MB
+const maxValidatorKeysImport = 10000
+
+type validatorKeyFile struct {
+	name string
+	data []byte
+}
+
+// readValidatorKeyFiles returns the uploaded files of a multipart request (field files) or the request body as single file
+func readValidatorKeyFiles(r *http.Request) ([]*validatorKeyFile, error) {
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) == 0 {
+			return nil, nil
+		}
+		return []*validatorKeyFile{{name: "request body", data: data}}, nil
+	}
+
+	err := r.ParseMultipartForm(maxValidatorKeysImportSize)
+	if err != nil {
+		return nil, err
+	}
+	files := []*validatorKeyFile{}
+	for _, header := range r.MultipartForm.File["files"] {
+		f, err := header.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &validatorKeyFile{name: header.Filename, data: data})
+	}
+	return files, nil
+}
+
+// ValidatorKeysImport godoc
+// @Summary Add validators to the watchlist or a validator dashboard by uploading deposit data or keystore files
+// @Tags User
+// @Description Accepts the deposit_data-*.json files of the staking-deposit-cli and EIP-2335 keystores, either as multipart upload
+// @Description (field files, multiple files allowed) or a single file as request body. Only the pubkeys are read, the files are not stored.
+// @Description Without a dashboard the validators are added to the watchlist and subscribed to the given events.
+// @Accept mpfd
+// @Accept json
+// @Produce json
+// @Param files formData file false "The deposit data or keystore files"
+// @Param dashboard query integer false "The id of the validator dashboard to add the validators to"
+// @Param group query integer false "The id of the group of the dashboard to add the validators to" default(0)
+// @Param events query string false "Comma separated list of events to subscribe the validators of the watchlist to, e.g. validator_balance_decreased,validator_got_slashed"
+// @Success 200 {object} types.ApiResponse{data=types.ApiValidatorKeysImportResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Failure 404 {object} types.ApiResponse
+// @Security ApiKeyAuth
+// @Router /api/v1/user/validators/import [post]
+func ValidatorKeysImport(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	j := json.NewEncoder(w)
+	user := getUser(r)
+	if !user.Authenticated {
+		sendErrorWithCodeResponse(w, r.URL.String(), "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxValidatorKeysImportSize)
+	files, err := readValidatorKeyFiles(r)
+	if err != nil {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("could not read files, the files must not exceed %d MB in total", maxValidatorKeysImportSize>>20))
+		return
+	}
+	if len(files) == 0 {
+		SendBadRequestResponse(w, r.URL.String(), "no deposit data or keystore files provided")
+		return
+	}
+
+	pubkeys := [][]byte{}
+	seen := make(map[string]bool)
+	for _, file := range files {
+		keys, err := utils.ParseValidatorKeyFile(file.data)
+		if err != nil {
+			SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("%v: %v", file.name, err))
+			return
+		}
+		for _, key := range keys {
+			if seen[string(key)] {
+				continue
+			}
+			seen[string(key)] = true
+			pubkeys = append(pubkeys, key)
+		}
+	}
+	if len(pubkeys) > maxValidatorKeysImport {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("at most %d validators can be imported at once", maxValidatorKeysImport))
+		return
+	}
+
+	known := []struct {
+		Index  uint64 `db:"validatorindex"`
+		Pubkey []byte `db:"pubkey"`
+	}{}
+	err = db.ReaderDb.Select(&known, `SELECT validatorindex, pubkey FROM validators WHERE pubkey = ANY($1)`, pq.ByteaArray(pubkeys))
+	if err != nil {
+		utils.LogError(err, "error retrieving validators of imported pubkeys", 0, map[string]interface{}{"userId": user.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+	indices := make(map[string]uint64, len(known))
+	for _, v := range known {
+		indices[string(v.Pubkey)] = v.Index
+	}
+
+	res := &types.ApiValidatorKeysImportResponse{
+		Pubkeys:    len(pubkeys),
+		Validators: make([]uint64, 0, len(known)),
+		Unknown:    []string{},
+	}
+	for _, key := range pubkeys {
+		if index, ok := indices[string(key)]; ok {
+			res.Validators = append(res.Validators, index)
+		} else {
+			res.Unknown = append(res.Unknown, fmt.Sprintf("%#x", key))
+		}
+	}
+	res.Validators = utils.SortedUniqueUint64(res.Validators)
+
+	if r.FormValue("dashboard") != "" {
+		dashboardId, err := strconv.ParseUint(r.FormValue("dashboard"), 10, 64)
+		if err != nil {
+			SendBadRequestResponse(w, r.URL.String(), "invalid dashboard id provided")
+			return
+		}
+		groupId := parseUintWithDefault(r.FormValue("group"), 0)
+		ownerId := resolveValidatorDashboardOwner(w, r, user.UserID, dashboardId, true)
+		if ownerId == 0 {
+			return
+		}
+
+		dashboard, err := db.GetUserValidatorDashboard(ownerId, dashboardId)
+		if err != nil {
+			utils.LogError(err, "error retrieving validator dashboard", 0, map[string]interface{}{"userId": user.UserID, "dashboardId": dashboardId})
+			SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+			return
+		}
+		if dashboard == nil {
+			sendErrorWithCodeResponse(w, r.URL.String(), "dashboard not found", http.StatusNotFound)
+			return
+		}
+
+		existing := make(map[uint64]bool)
+		for _, group := range dashboard.Groups {
+			for _, v := range group.Validators {
+				existing[v] = true
+			}
+		}
+		for _, v := range res.Validators {
+			if !existing[v] {
+				res.Added++
+			}
+		}
+		if len(existing)+res.Added > getUserPremium(r).MaxDashboardValidators {
+			SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("a dashboard can have at most %d validators", getUserPremium(r).MaxDashboardValidators))
+			return
+		}
+
+		found, err := db.AddValidatorsToUserValidatorDashboard(ownerId, dashboardId, groupId, res.Validators)
+		if err != nil {
+			utils.LogError(err, "error adding imported validators to validator dashboard", 0, map[string]interface{}{"userId": user.UserID, "dashboardId": dashboardId})
+			SendBadRequestResponse(w, r.URL.String(), "could not save dashboard")
+			return
+		}
+		if !found {
+			sendErrorWithCodeResponse(w, r.URL.String(), "dashboard group not found", http.StatusNotFound)
+			return
+		}
+		res.DashboardId = &dashboardId
+
+		SendOKResponse(j, r.URL.String(), []interface{}{res})
+		return
+	}
+
+	events := []types.EventName{}
+	for _, event := range strings.Split(r.FormValue("events"), ",") {
+		event = strings.TrimSpace(event)
+		if event == "" {
+			continue
+		}
+		valid := false
+		for _, ev := range types.AddWatchlistEvents {
+			if string(ev.Event) == event {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("invalid event %v provided", event))
+			return
+		}
+		events = append(events, types.EventName(event))
+	}
+
+	pubkeyStrings := make([]string, 0, len(pubkeys))
+	entries := make([]db.WatchlistEntry, 0, len(pubkeys))
+	for _, key := range pubkeys {
+		keyString := hex.EncodeToString(key)
+		pubkeyStrings = append(pubkeyStrings, keyString)
+		entries = append(entries, db.WatchlistEntry{UserId: user.UserID, Validator_publickey: keyString})
+	}
+	err = db.AddToWatchlist(entries, utils.GetNetwork())
+	if err != nil {
+		utils.LogError(err, "error adding imported validators to watchlist", 0, map[string]interface{}{"userId": user.UserID})
+		SendBadRequestResponse(w, r.URL.String(), "could not add validators to the watchlist")
+		return
+	}
+	for _, event := range events {
+		err = db.AddSubscriptionBatch(user.UserID, utils.GetNetwork(), event, pubkeyStrings, 0)
+		if err != nil {
+			utils.LogError(err, "error subscribing imported validators", 0, map[string]interface{}{"userId": user.UserID, "event": event})
+			SendBadRequestResponse(w, r.URL.String(), "could not subscribe validators")
+			return
+		}
+	}
+	res.Added = len(pubkeys)
+
+	SendOKResponse(j, r.URL.String(), []interface{}{res})
+}
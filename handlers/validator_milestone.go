@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// ValidatorMilestone will return the shareable page of a milestone a validator reached
+func ValidatorMilestone(w http.ResponseWriter, r *http.Request) {
+	templateFiles := append(layoutTemplateFiles, "validator_milestone.html")
+	var milestoneTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+	vars := mux.Vars(r)
+
+	index, err := strconv.ParseUint(vars["index"], 10, 64)
+	if err != nil || index > math.MaxInt32 {
+		NotFound(w, r)
+		return
+	}
+	desc, ok := types.ValidatorMilestones[vars["milestone"]]
+	if !ok {
+		NotFound(w, r)
+		return
+	}
+
+	milestone, err := db.GetValidatorMilestone(index, vars["milestone"])
+	if err != nil {
+		logger.Errorf("error retrieving validator milestone for %v route: %v", r.URL.String(), err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if milestone == nil {
+		NotFound(w, r)
+		return
+	}
+
+	path := fmt.Sprintf("/validator/%d/milestone/%s", index, milestone.Milestone)
+	title := fmt.Sprintf("Validator %d: %s", index, desc.Label)
+	data := InitPageData(w, r, "validators", path, title, templateFiles)
+	data.Meta.Description = fmt.Sprintf("Validator %d %s on %s", index, desc.Description, utils.DayToTime(int64(milestone.Day)).Format("January 2, 2006"))
+	data.Meta.Image = fmt.Sprintf("/img/og/validator/%d/milestone/%s.png", index, milestone.Milestone)
+	data.Data = types.ValidatorMilestonePageData{
+		ValidatorIndex: index,
+		Milestone:      milestone,
+		Desc:           desc,
+		DayStart:       utils.DayToTime(int64(milestone.Day)),
+		ShareUrl:       fmt.Sprintf("https://%s%s", utils.Config.Frontend.SiteDomain, path),
+	}
+
+	if handleTemplateError(w, r, "validator_milestone.go", "ValidatorMilestone", "", milestoneTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/csrf"
+)
+
+const validatorNameClaimsLimit = 200
+
+// Load the moderation queue of signed validator name claims
+func ValidatorNameClaims(w http.ResponseWriter, r *http.Request) {
+	if isAdmin, _ := handleAdminPermissions(w, r); !isAdmin {
+		return
+	}
+
+	templateFiles := append(layoutTemplateFiles, "user/validator_name_claims.html")
+	var userTemplate = templates.GetTemplate(templateFiles...)
+
+	w.Header().Set("Content-Type", "text/html")
+
+	status := r.URL.Query().Get("status")
+	if status != types.ValidatorNameClaimApproved && status != types.ValidatorNameClaimRejected {
+		status = types.ValidatorNameClaimPending
+	}
+
+	claims, err := db.GetValidatorNameClaims(status, validatorNameClaimsLimit)
+	if err != nil {
+		utils.LogError(err, "error loading the validator name claims", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data := InitPageData(w, r, "user", "/user/validator_name_claims", "Validator Name Claims", templateFiles)
+	data.Data = types.ValidatorNameClaimsPageData{
+		Claims:    claims,
+		Status:    status,
+		CsrfField: csrf.TemplateField(r),
+	}
+
+	if handleTemplateError(w, r, "validator_name_claims.go", "ValidatorNameClaims", "", userTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// Approve or reject a validator name claim, approving sets the claimed name
+func ValidatorNameClaimsPost(w http.ResponseWriter, r *http.Request) {
+	isAdmin, user := handleAdminPermissions(w, r)
+	if !isAdmin {
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		utils.LogError(err, "error parsing form", 0)
+		http.Redirect(w, r, "/user/validator_name_claims?error=parsingForm", http.StatusSeeOther)
+		return
+	}
+
+	id, err := strconv.ParseUint(r.FormValue("id"), 10, 64)
+	if err != nil {
+		utils.LogError(err, "error no claim id provided", 0)
+		http.Redirect(w, r, "/user/validator_name_claims?error=noClaimId", http.StatusSeeOther)
+		return
+	}
+
+	err = db.ReviewValidatorNameClaim(id, r.FormValue("action") == "approve", user.UserID)
+	if err != nil {
+		utils.LogError(err, "error reviewing validator name claim", 0, map[string]interface{}{"id": id})
+		http.Redirect(w, r, "/user/validator_name_claims?error=notReviewed", http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/user/validator_name_claims", http.StatusSeeOther)
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/services"
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+const (
+	validatorSetProjectionDefaultDays = 365
+	validatorSetProjectionMaxDays     = 730
+	// validatorSetProjectionMaxFlow limits the validators entering or exiting per day of a scenario
+	validatorSetProjectionMaxFlow = 1000000
+	// validatorSetProjectionFlowDays is the amount of days the default scenario averages the staking flows over
+	validatorSetProjectionFlowDays = 31
+)
+
+// ValidatorSetProjection renders the validator set projection chart, the scenario is simulated by the api
+func ValidatorSetProjection(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	templateFiles := append(layoutTemplateFiles, "validator_set_projection.html")
+	var validatorSetProjectionTemplate = templates.GetTemplate(templateFiles...)
+
+	data := InitPageData(w, r, "stats", "/charts", "Validator Set Projection", templateFiles)
+	data.Meta.Path = "/charts/validator-set-projection"
+
+	inflow, outflow, err := getDefaultValidatorSetFlows()
+	if err != nil {
+		utils.LogError(err, "error retrieving default validator set flows", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data.Data = &types.ValidatorSetProjectionPageData{
+		DailyInflow:  math.Round(inflow),
+		DailyOutflow: math.Round(outflow),
+		Days:         validatorSetProjectionDefaultDays,
+		MaxDays:      validatorSetProjectionMaxDays,
+	}
+
+	if handleTemplateError(w, r, "validator_set_projection.go", "ValidatorSetProjection", "", validatorSetProjectionTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+// ApiValidatorSetProjection godoc
+// @Summary Project the validator set, the churn limits, the queues and the apr under a growth scenario
+// @Tags Network
+// @Description Simulates the validator set day by day starting with the current validators and queues. Every day the given amount of validators requests to enter and to exit,
+// @Description both queues are processed at the churn limits of the projected validator set. The consensus apr scales with the inverse square root of the staked amount,
+// @Description the execution rewards are assumed to stay constant. Inflow and outflow default to the average deposits and full withdrawals per day of the last 31 days.
+// @Produce  json
+// @Param  inflow query number false "Validators requesting to enter per day"
+// @Param  outflow query number false "Validators requesting to exit per day"
+// @Param  days query int false "The horizon of the projection in days (default 365, at most 730)"
+// @Success 200 {object} types.ApiResponse{data=types.ApiValidatorSetProjectionResponse}
+// @Failure 400 {object} types.ApiResponse
+// @Router /api/v1/validators/projection [get]
+func ApiValidatorSetProjection(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	j := json.NewEncoder(w)
+	q := r.URL.Query()
+
+	days := parseUintWithDefault(q.Get("days"), validatorSetProjectionDefaultDays)
+	if days == 0 || days > validatorSetProjectionMaxDays {
+		SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("invalid days provided, must be between 1 and %v", validatorSetProjectionMaxDays))
+		return
+	}
+
+	inflow, outflow, err := getDefaultValidatorSetFlows()
+	if err != nil {
+		requestLogger(r).WithError(err).Error("error retrieving default validator set flows")
+		SendBadRequestResponse(w, r.URL.String(), "could not retrieve db results")
+		return
+	}
+
+	if q.Get("inflow") != "" {
+		inflow, err = parseValidatorSetFlow(q.Get("inflow"))
+		if err != nil {
+			SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("invalid inflow provided, %v", err))
+			return
+		}
+	}
+	if q.Get("outflow") != "" {
+		outflow, err = parseValidatorSetFlow(q.Get("outflow"))
+		if err != nil {
+			SendBadRequestResponse(w, r.URL.String(), fmt.Sprintf("invalid outflow provided, %v", err))
+			return
+		}
+	}
+
+	projection, err := services.ProjectValidatorSet(inflow, outflow, days)
+	if err != nil {
+		requestLogger(r).WithError(err).Error("error projecting validator set")
+		sendServerErrorResponse(w, r.URL.String(), "could not project the validator set")
+		return
+	}
+
+	SendOKResponse(j, r.URL.String(), []interface{}{projection})
+}
+
+// getDefaultValidatorSetFlows returns the average validators entering and exiting per day of the recent staking flows
+func getDefaultValidatorSetFlows() (inflow float64, outflow float64, err error) {
+	var fromDay uint64
+	latestDay := utils.DayOfSlot(services.LatestSlot())
+	if latestDay > validatorSetProjectionFlowDays {
+		fromDay = latestDay - validatorSetProjectionFlowDays
+	}
+	return db.GetAverageStakingFlowCounts(fromDay)
+}
+
+func parseValidatorSetFlow(value string) (float64, error) {
+	flow, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(flow) || math.IsInf(flow, 0) || flow < 0 || flow > validatorSetProjectionMaxFlow {
+		return 0, fmt.Errorf("must be between 0 and %v", validatorSetProjectionMaxFlow)
+	}
+	return flow, nil
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"fmt"
 	"html"
 	"net/http"
@@ -27,7 +26,7 @@ func Validators(w http.ResponseWriter, r *http.Request) {
 	validatorsPageData := types.ValidatorsPageData{}
 
 	var currentStateCounts []*types.ValidatorStateCountRow
-	err := db.ReaderDb.Select(&currentStateCounts, "SELECT status, validator_count FROM validators_status_counts")
+	err := db.ReaderDb.SelectContext(r.Context(), &currentStateCounts, "SELECT status, validator_count FROM validators_status_counts")
 	if err != nil {
 		utils.LogError(err, "error retrieving validators state counts", 0, nil)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -77,158 +76,143 @@ func Validators(w http.ResponseWriter, r *http.Request) {
 }
 
 type ValidatorsDataQueryParams struct {
-	Search            string
+	*DataTableQuery
 	SearchIndex       *uint64
 	SearchPubkeyExact *string
 	SearchPubkeyLike  *string
-	OrderBy           string
-	OrderDir          string
-	Draw              uint64
-	Start             uint64
-	Length            int64
 	StateFilter       string
 }
 
 var searchPubkeyExactRE = regexp.MustCompile(`^(0x)?[0-9a-fA-F]{96}`) // only search for pubkeys if string consists of 96 hex-chars
 var searchPubkeyLikeRE = regexp.MustCompile(`^(0x)?[0-9a-fA-F]{2,96}`)
 
+// validatorsDataColumns are the columns of the validators table, the offset is limited to 10000 as larger offsets
+// make the query too slow
+var validatorsDataColumns = DataTableColumns{
+	OrderBy: map[string]string{
+		"0": "pubkey",
+		"1": "validatorindex",
+		"3": "state",
+		"4": "activationepoch",
+		"5": "exitepoch",
+		"6": "withdrawableepoch",
+		"8": "slashed",
+	},
+	DefaultOrderBy: "validatorindex",
+	MaxStart:       10000,
+}
+
 func parseValidatorsDataQueryParams(r *http.Request) (*ValidatorsDataQueryParams, error) {
-	q := r.URL.Query()
+	query, err := ParseDataTableQuery(r, validatorsDataColumns)
+	if err != nil {
+		return nil, err
+	}
 
-	search := strings.Replace(q.Get("search[value]"), "0x", "", -1)
+	search := strings.Replace(query.Search, "0x", "", -1)
 	if len(search) > 128 {
 		search = search[:128]
 	}
+	query.Search = search
+
 	var searchIndex *uint64
-	if search == "" {
-		index := uint64(0)
+	if index, err := strconv.ParseUint(search, 10, 64); err == nil {
 		searchIndex = &index
-	} else {
-		index, err := strconv.ParseUint(search, 10, 64)
-		if err == nil {
-			searchIndex = &index
-		}
 	}
 
 	var searchPubkeyExact *string
 	var searchPubkeyLike *string
 	if searchPubkeyExactRE.MatchString(search) {
-		pubkey := strings.ToLower(strings.Replace(search, "0x", "", -1))
+		pubkey := strings.ToLower(search)
 		searchPubkeyExact = &pubkey
 	} else if searchPubkeyLikeRE.MatchString(search) {
-		pubkey := strings.ToLower(strings.Replace(search, "0x", "", -1))
+		pubkey := strings.ToLower(search)
 		searchPubkeyLike = &pubkey
 	}
 
-	filterByState := q.Get("filterByState")
 	var qryStateFilter string
-	switch filterByState {
+	switch r.URL.Query().Get("filterByState") {
 	case "online":
-		qryStateFilter = "WHERE validators.status LIKE '%online'"
+		qryStateFilter = "validators.status LIKE '%online'"
 	case "offline":
-		qryStateFilter = "WHERE validators.status LIKE '%offline'"
+		qryStateFilter = "validators.status LIKE '%offline'"
 	case "pending":
-		qryStateFilter = "WHERE validators.status = 'pending'"
+		qryStateFilter = "validators.status = 'pending'"
 	case "active":
-		qryStateFilter = "WHERE validators.status LIKE 'active%'"
+		qryStateFilter = "validators.status LIKE 'active%'"
 	case "active_online":
-		qryStateFilter = "WHERE validators.status = 'active_online'"
+		qryStateFilter = "validators.status = 'active_online'"
 	case "active_offline":
-		qryStateFilter = "WHERE validators.status = 'active_offline'"
+		qryStateFilter = "validators.status = 'active_offline'"
 	case "slashing":
-		qryStateFilter = "WHERE validators.status LIKE 'slashing%'"
+		qryStateFilter = "validators.status LIKE 'slashing%'"
 	case "slashing_online":
-		qryStateFilter = "WHERE validators.status = 'slashing_online'"
+		qryStateFilter = "validators.status = 'slashing_online'"
 	case "slashing_offline":
-		qryStateFilter = "WHERE validators.status = 'slashing_offline'"
+		qryStateFilter = "validators.status = 'slashing_offline'"
 	case "slashed":
-		qryStateFilter = "WHERE validators.status = 'slashed'"
+		qryStateFilter = "validators.status = 'slashed'"
 	case "exiting":
-		qryStateFilter = "WHERE validators.status LIKE 'exiting%'"
+		qryStateFilter = "validators.status LIKE 'exiting%'"
 	case "exiting_online":
-		qryStateFilter = "WHERE validators.status = 'exiting_online'"
+		qryStateFilter = "validators.status = 'exiting_online'"
 	case "exiting_offline":
-		qryStateFilter = "WHERE validators.status = 'exiting_offline'"
+		qryStateFilter = "validators.status = 'exiting_offline'"
 	case "exited":
-		qryStateFilter = "WHERE (validators.status = 'exited' OR validators.status = 'slashed')"
+		qryStateFilter = "(validators.status = 'exited' OR validators.status = 'slashed')"
 	case "voluntary":
-		qryStateFilter = "WHERE validators.status = 'exited'"
+		qryStateFilter = "validators.status = 'exited'"
 	case "deposited":
-		qryStateFilter = "WHERE validators.status = 'deposited'"
+		qryStateFilter = "validators.status = 'deposited'"
 	default:
 		qryStateFilter = ""
 	}
 
-	orderColumn := q.Get("order[0][column]")
-	orderByMap := map[string]string{
-		"0": "pubkey",
-		"1": "validatorindex",
-		"3": "state",
-		"4": "activationepoch",
-		"5": "exitepoch",
-		"6": "withdrawableepoch",
-		"8": "slashed",
-	}
-	orderBy, exists := orderByMap[orderColumn]
-	if !exists {
-		orderBy = "validatorindex"
-	}
-
-	orderDir := q.Get("order[0][dir]")
-	if orderDir != "desc" && orderDir != "asc" {
-		orderDir = "desc"
-	}
-
-	draw, err := strconv.ParseUint(q.Get("draw"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables draw parameter from string to int: %v", err)
-		return nil, err
-	}
-
-	start, err := strconv.ParseUint(q.Get("start"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables start parameter from string to int: %v", err)
-		return nil, err
-	}
-	if start > 10000 {
-		// limit offset to 10000, otherwise the query will be too slow
-		start = 10000
-	}
-
-	length, err := strconv.ParseInt(q.Get("length"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables length parameter from string to int: %v", err)
-		return nil, err
-	}
-	if length < 0 {
-		length = 100
-	}
-	if length > 100 {
-		length = 100
-	}
-
 	res := &ValidatorsDataQueryParams{
-		Search:            search,
+		DataTableQuery:    query,
 		SearchIndex:       searchIndex,
 		SearchPubkeyExact: searchPubkeyExact,
 		SearchPubkeyLike:  searchPubkeyLike,
-		OrderBy:           orderBy,
-		OrderDir:          orderDir,
-		Draw:              draw,
-		Start:             start,
-		Length:            length,
 		StateFilter:       qryStateFilter,
 	}
 
 	return res, nil
 }
 
+// where returns the where clause of the state filter and the search and its arguments, the arguments are numbered
+// starting after the given number of arguments
+func (p *ValidatorsDataQueryParams) where(argOffset int) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+	if p.StateFilter != "" {
+		conditions = append(conditions, p.StateFilter)
+	}
+	if p.Search != "" {
+		searchConditions := []string{}
+		if p.SearchIndex != nil {
+			args = append(args, *p.SearchIndex)
+			searchConditions = append(searchConditions, fmt.Sprintf("validators.validatorindex = $%d", argOffset+len(args)))
+		}
+		if p.SearchPubkeyExact != nil {
+			args = append(args, *p.SearchPubkeyExact)
+			searchConditions = append(searchConditions, fmt.Sprintf("validators.pubkeyhex = $%d", argOffset+len(args)))
+		} else if p.SearchPubkeyLike != nil {
+			args = append(args, *p.SearchPubkeyLike+"%")
+			searchConditions = append(searchConditions, fmt.Sprintf("validators.pubkeyhex LIKE $%d", argOffset+len(args)))
+		}
+		args = append(args, "%"+p.Search+"%")
+		searchConditions = append(searchConditions, fmt.Sprintf("validator_names.name ILIKE $%d", argOffset+len(args)))
+		conditions = append(conditions, "("+strings.Join(searchConditions, " OR ")+")")
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
 // ValidatorsData returns all validators and basic information about them based on a StateFilter
 func ValidatorsData(w http.ResponseWriter, r *http.Request) {
 	currency := GetCurrency(r)
 
-	w.Header().Set("Content-Type", "application/json")
-
 	dataQuery, err := parseValidatorsDataQueryParams(r)
 	if err != nil {
 		logger.Warnf("error parsing query-data: %v", err)
@@ -241,6 +225,8 @@ func ValidatorsData(w http.ResponseWriter, r *http.Request) {
 		"dataQuery": dataQuery,
 	}
 
+	where, whereArgs := dataQuery.where(2)
+
 	var validators []*types.ValidatorsData
 	qry := fmt.Sprintf(`
 		SELECT  
@@ -256,9 +242,9 @@ func ValidatorsData(w http.ResponseWriter, r *http.Request) {
 		LEFT JOIN validator_names ON validators.pubkey = validator_names.publickey  
 		%s
 		ORDER BY %s %s  
-		LIMIT $1 OFFSET $2`, dataQuery.StateFilter, dataQuery.OrderBy, dataQuery.OrderDir)
+		LIMIT $1 OFFSET $2`, where, dataQuery.OrderBy, dataQuery.OrderDir)
 
-	err = db.ReaderDb.Select(&validators, qry, dataQuery.Length, dataQuery.Start)
+	err = db.ReaderDb.SelectContext(r.Context(), &validators, qry, append([]interface{}{dataQuery.Length, dataQuery.Start}, whereArgs...)...)
 	if err != nil {
 		utils.LogError(err, "error retrieving validators data", 0, errFields)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -350,16 +336,32 @@ func ValidatorsData(w http.ResponseWriter, r *http.Request) {
 
 	countTotal := uint64(0)
 	qry = "SELECT MAX(validatorindex) + 1 as total FROM validators"
-	err = db.ReaderDb.Get(&countTotal, qry)
+	err = db.ReaderDb.GetContext(r.Context(), &countTotal, qry)
 	if err != nil {
 		utils.LogError(err, "error retrieving validators total count", 0, errFields)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 	countFiltered := uint64(0)
-	if dataQuery.StateFilter != "" {
-		qry = fmt.Sprintf(`SELECT SUM(validator_count) FROM validators_status_counts AS validators %s`, dataQuery.StateFilter)
-		err = db.ReaderDb.Get(&countFiltered, qry)
+	if dataQuery.Search != "" {
+		// the count is capped like the offset to keep the query fast
+		where, whereArgs := dataQuery.where(0)
+		qry = fmt.Sprintf(`
+			SELECT COUNT(*) FROM (
+				SELECT 1 FROM validators
+				LEFT JOIN validator_names ON validators.pubkey = validator_names.publickey
+				%s
+				LIMIT 10000
+			) AS filtered`, where)
+		err = db.ReaderDb.GetContext(r.Context(), &countFiltered, qry, whereArgs...)
+		if err != nil {
+			utils.LogError(err, "error retrieving validators filtered count", 0, errFields)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	} else if dataQuery.StateFilter != "" {
+		qry = fmt.Sprintf(`SELECT SUM(validator_count) FROM validators_status_counts AS validators WHERE %s`, dataQuery.StateFilter)
+		err = db.ReaderDb.GetContext(r.Context(), &countFiltered, qry)
 		if err != nil {
 			utils.LogError(err, "error retrieving validators total count", 0, errFields)
 			http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -376,17 +378,10 @@ func ValidatorsData(w http.ResponseWriter, r *http.Request) {
 		countFiltered = 10000
 	}
 
-	data := &types.DataTableResponse{
+	writeDataTableResponse(w, r, &types.DataTableResponse{
 		Draw:            dataQuery.Draw,
 		RecordsTotal:    countTotal,
 		RecordsFiltered: countFiltered,
 		Data:            tableData,
-	}
-
-	err = json.NewEncoder(w).Encode(data)
-	if err != nil {
-		utils.LogError(err, "error encoding json response", 0, errFields)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
+	})
 }
@@ -4,8 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
-	"strings"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
 	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
@@ -13,6 +13,44 @@ import (
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 )
 
+// validatorLeaderboardWindows are the time windows in days the validator leaderboard is rolled up for
+var validatorLeaderboardWindows = []uint64{1, 7, 31}
+
+// parseValidatorLeaderboardFilter parses the window, mode, order, entity and minimum active days of the leaderboard
+// from the query, the leaderboard defaults to the top earners of the last 7 days
+func parseValidatorLeaderboardFilter(q url.Values) (*types.ValidatorLeaderboardFilter, error) {
+	filter := &types.ValidatorLeaderboardFilter{
+		WindowDays:    parseUintWithDefault(q.Get("window"), 7),
+		Mode:          q.Get("mode"),
+		Bottom:        q.Get("order") == "bottom",
+		Entity:        q.Get("entity"),
+		MinActiveDays: parseUintWithDefault(q.Get("min_active_days"), 1),
+	}
+
+	validWindow := false
+	for _, window := range validatorLeaderboardWindows {
+		if window == filter.WindowDays {
+			validWindow = true
+		}
+	}
+	if !validWindow {
+		return nil, fmt.Errorf("invalid window %v, must be one of %v", filter.WindowDays, validatorLeaderboardWindows)
+	}
+	if filter.Mode == "" {
+		filter.Mode = "income"
+	}
+	if filter.Mode != "income" && filter.Mode != "efficiency" {
+		return nil, fmt.Errorf("invalid mode %v, must be income or efficiency", filter.Mode)
+	}
+	if filter.MinActiveDays > filter.WindowDays {
+		filter.MinActiveDays = filter.WindowDays
+	}
+	if len(filter.Entity) > 128 {
+		filter.Entity = filter.Entity[:128]
+	}
+	return filter, nil
+}
+
 // ValidatorsLeaderboard returns the validator-leaderboard using a go template
 func ValidatorsLeaderboard(w http.ResponseWriter, r *http.Request) {
 	templateFiles := append(layoutTemplateFiles, "validators_leaderboard.html")
@@ -22,12 +60,31 @@ func ValidatorsLeaderboard(w http.ResponseWriter, r *http.Request) {
 
 	data := InitPageData(w, r, "validators", "/validators/leaderboard", "Validator Staking Leaderboard", templateFiles)
 
+	filter, err := parseValidatorLeaderboardFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, "Error: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entities, err := db.GetValidatorLeaderboardEntities()
+	if err != nil {
+		utils.LogError(err, "error getting validator leaderboard entities", 0)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	data.Data = &types.ValidatorsLeaderboardPageData{
+		Filter:   filter,
+		Windows:  validatorLeaderboardWindows,
+		Entities: entities,
+	}
+
 	if handleTemplateError(w, r, "validators_leaderboard.go", "ValidatorsLeaderboard", "", validatorsLeaderboardTemplate.ExecuteTemplate(w, "layout", data)) != nil {
 		return // an error has occurred and was processed
 	}
 }
 
-// ValidatorsLeaderboardData returns the leaderboard of validators according to their income in json
+// ValidatorsLeaderboardData returns the leaderboard of validators according to their income or efficiency in json
 func ValidatorsLeaderboardData(w http.ResponseWriter, r *http.Request) {
 	currency := GetCurrency(r)
 
@@ -35,26 +92,6 @@ func ValidatorsLeaderboardData(w http.ResponseWriter, r *http.Request) {
 
 	q := r.URL.Query()
 
-	search := strings.Replace(q.Get("search[value]"), "0x", "", -1)
-	if len(search) > 128 {
-		search = search[:128]
-	}
-	// var searchIndex *uint64
-	// index, err := strconv.ParseUint(search, 10, 64)
-	// if err == nil {
-	// searchIndex = &index
-	// }
-
-	// var searchPubkeyExact *string
-	// var searchPubkeyLike *string
-	// if searchPubkeyExactRE.MatchString(search) {
-	// 	pubkey := strings.ToLower(strings.Replace(search, "0x", "", -1))
-	// 	searchPubkeyExact = &pubkey
-	// } else if searchPubkeyLikeRE.MatchString(search) {
-	// 	pubkey := strings.ToLower(strings.Replace(search, "0x", "", -1))
-	// 	searchPubkeyLike = &pubkey
-	// }
-
 	draw, err := strconv.ParseUint(q.Get("draw"), 10, 64)
 	if err != nil {
 		logger.Warnf("error converting datatables draw parameter from string to int: %v", err)
@@ -77,79 +114,38 @@ func ValidatorsLeaderboardData(w http.ResponseWriter, r *http.Request) {
 		length = 100
 	}
 
-	orderColumn := q.Get("order[0][column]")
-	orderByMap := map[string]string{
-		"4": "cl_performance_1d",
-		"5": "cl_performance_7d",
-		"6": "cl_performance_31d",
-		"7": "cl_performance_365d",
-	}
-	orderBy, exists := orderByMap[orderColumn]
-	if !exists {
-		orderBy = "cl_performance_7d"
-	}
-
-	orderDir := q.Get("order[0][dir]")
-	if orderDir != "desc" && orderDir != "asc" {
-		orderDir = "desc"
+	filter, err := parseValidatorLeaderboardFilter(q)
+	if err != nil {
+		http.Error(w, "Error: "+err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	var totalCount uint64
-	var performanceData []*types.ValidatorPerformance
-
-	// if search == "" {
-	err = db.ReaderDb.Select(&performanceData, `
-			SELECT 
-				a.rank,
-				a.balance, 
-				a.performance1d, 
-				a.performance7d, 
-				a.performance31d, 
-				a.performance365d, 
-				a.rank7d, 
-				a.validatorindex,
-				validators.pubkey,
-				COALESCE(validator_names.name, '') AS name,
-				cnt.total_count
-			FROM (
-					SELECT
-						ROW_NUMBER() OVER (ORDER BY `+orderBy+` DESC) AS rank,						
-						validator_performance.balance, 
-						COALESCE(validator_performance.cl_performance_1d, 0) AS performance1d, 
-						COALESCE(validator_performance.cl_performance_7d, 0) AS performance7d, 
-						COALESCE(validator_performance.cl_performance_31d, 0) AS performance31d, 
-						COALESCE(validator_performance.cl_performance_365d, 0) AS performance365d, 
-						COALESCE(validator_performance.cl_performance_total, 0) AS performanceTotal, 
-						validator_performance.rank7d, 
-						validator_performance.validatorindex
-					FROM validator_performance
-					ORDER BY `+orderBy+` `+orderDir+`
-					LIMIT $1 OFFSET $2
-			) AS a
-			LEFT JOIN validators ON validators.validatorindex = a.validatorindex
-			LEFT JOIN validator_names ON validators.pubkey = validator_names.publickey
-			LEFT JOIN (SELECT COUNT(*) FROM validator_performance) cnt(total_count) ON true`, length, start)
-
+	entries, err := db.GetValidatorLeaderboard(filter, length, start)
 	if err != nil {
-		logger.Errorf("error retrieving performanceData data (search=%v): %v", search != "", err)
+		logger.Errorf("error retrieving validator leaderboard: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
-	if len(performanceData) > 0 {
-		totalCount = performanceData[0].TotalCount
+
+	var totalCount uint64
+	if len(entries) > 0 {
+		totalCount = entries[0].TotalCount
 	}
 
-	tableData := make([][]interface{}, len(performanceData))
-	for i, b := range performanceData {
+	tableData := make([][]interface{}, len(entries))
+	for i, e := range entries {
+		efficiency := "-"
+		if e.Efficiency != nil {
+			efficiency = fmt.Sprintf("%.2f%%", *e.Efficiency)
+		}
 		tableData[i] = []interface{}{
-			b.Rank,
-			utils.FormatValidatorWithName(b.Index, b.Name),
-			utils.FormatPublicKey(b.PublicKey),
-			fmt.Sprintf("%v", b.Balance),
-			utils.FormatClCurrency(b.Performance1d, currency, 5, true, true, true, false),
-			utils.FormatClCurrency(b.Performance7d, currency, 5, true, true, true, false),
-			utils.FormatClCurrency(b.Performance31d, currency, 5, true, true, true, false),
-			utils.FormatClCurrency(b.Performance365d, currency, 5, true, true, true, false),
+			start + uint64(i) + 1,
+			utils.FormatValidatorWithName(e.ValidatorIndex, e.Name),
+			utils.FormatPublicKey(e.PublicKey),
+			e.Entity,
+			e.ActiveDays,
+			utils.FormatClCurrency(e.IncomeGwei, currency, 5, true, true, true, false),
+			efficiency,
 		}
 	}
 
@@ -50,79 +50,42 @@ func Withdrawals(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+var withdrawalsDataColumns = DataTableColumns{
+	OrderBy: map[string]string{
+		"0": "epoch",
+		"1": "slot",
+		"2": "index",
+		"3": "validator",
+		"4": "address",
+		"5": "amount",
+	},
+	DefaultOrderBy: "index",
+	MaxStart:       db.WithdrawalsQueryLimit,
+}
+
 // WithdrawalsData will return eth1-deposits as json
 func WithdrawalsData(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
 	currency := GetCurrency(r)
-	q := r.URL.Query()
-
-	search := ReplaceEnsNameWithAddress(q.Get("search[value]"))
-
-	draw, err := strconv.ParseUint(q.Get("draw"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables draw parameter from string to int: %v", err)
-		http.Error(w, "Error: Missing or invalid parameter draw", http.StatusBadRequest)
-		return
-	}
 
-	start, err := strconv.ParseUint(q.Get("start"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables start parameter from string to int: %v", err)
-		http.Error(w, "Error: Missing or invalid parameter start", http.StatusBadRequest)
-		return
-	}
-	if start > db.WithdrawalsQueryLimit {
-		start = db.WithdrawalsQueryLimit
-	}
-	length, err := strconv.ParseUint(q.Get("length"), 10, 64)
-	if err != nil {
-		logger.Warnf("error converting datatables length parameter from string to int: %v", err)
-		http.Error(w, "Error: Missing or invalid parameter length", http.StatusBadRequest)
+	dataQuery, ok := handleDataTableQuery(w, r, withdrawalsDataColumns)
+	if !ok {
 		return
 	}
-	if length > 100 {
-		length = 100
-	}
-
-	orderBy := q.Get("order[0][column]")
-	orderDir := q.Get("order[0][dir]")
 
-	data, err := WithdrawalsTableData(draw, search, length, start, orderBy, orderDir, currency)
+	data, err := WithdrawalsTableData(dataQuery, ReplaceEnsNameWithAddress(dataQuery.Search), currency)
 	if err != nil {
 		logger.Errorf("error getting withdrawal table data: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	err = json.NewEncoder(w).Encode(data)
-	if err != nil {
-		logger.Errorf("error enconding json response for %v route: %v", r.URL.String(), err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
-	}
+	writeDataTableResponse(w, r, data)
 }
 
-func WithdrawalsTableData(draw uint64, search string, length, start uint64, orderBy, orderDir string, currency string) (*types.DataTableResponse, error) {
+func WithdrawalsTableData(dataQuery *DataTableQuery, search string, currency string) (*types.DataTableResponse, error) {
 	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Minute*10))
 	defer cancel()
 
-	orderByMap := map[string]string{
-		"0": "epoch",
-		"1": "slot",
-		"2": "index",
-		"3": "validator",
-		"4": "address",
-		"5": "amount",
-	}
-	orderColumn, exists := orderByMap[orderBy]
-	if !exists {
-		orderBy = "index"
-	}
-
-	if orderDir != "asc" {
-		orderDir = "desc"
-	}
-
 	g, gCtx := errgroup.WithContext(ctx)
 	withdrawalCount := uint64(0)
 	g.Go(func() error {
@@ -165,7 +128,7 @@ func WithdrawalsTableData(draw uint64, search string, length, start uint64, orde
 		default:
 		}
 		var err error
-		withdrawals, err = db.GetWithdrawals(search, length, start, orderColumn, orderDir)
+		withdrawals, err = db.GetWithdrawals(search, dataQuery.Length, dataQuery.Start, dataQuery.OrderBy, dataQuery.OrderDir)
 		if err != nil {
 			return fmt.Errorf("error getting withdrawals: %w", err)
 		}
@@ -216,12 +179,12 @@ func WithdrawalsTableData(draw uint64, search string, length, start uint64, orde
 	}
 
 	data := &types.DataTableResponse{
-		Draw:            draw,
+		Draw:            dataQuery.Draw,
 		RecordsTotal:    withdrawalCount,
 		RecordsFiltered: filteredCount,
 		Data:            tableData,
-		PageLength:      length,
-		DisplayStart:    start,
+		PageLength:      dataQuery.Length,
+		DisplayStart:    dataQuery.Start,
 	}
 	return data, nil
 }
@@ -0,0 +1,99 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+type breakerStatus int
+
+const (
+	breakerClosed breakerStatus = iota
+	breakerHalfOpen
+	breakerOpen
+)
+
+// breaker is the circuit breaker of a host of a target
+type breaker struct {
+	target string
+	host   string
+
+	mu       sync.Mutex
+	status   breakerStatus
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+var breakersMu sync.Mutex
+var breakers = map[string]*breaker{}
+
+func getBreaker(target, host string) *breaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	key := target + "|" + host
+	b, ok := breakers[key]
+	if !ok {
+		b = &breaker{target: target, host: host}
+		breakers[key] = b
+		breakerState.WithLabelValues(target, host).Set(float64(breakerClosed))
+	}
+	return b
+}
+
+// allow returns whether a request may be sent, after the cooldown of an open breaker a single probe request is allowed
+func (b *breaker) allow(policy types.HttpClientPolicy) bool {
+	if policy.BreakerThreshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.status {
+	case breakerOpen:
+		if time.Since(b.openedAt) < policy.BreakerCooldown {
+			return false
+		}
+		b.setStatus(breakerHalfOpen)
+		b.probing = true
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.probing = false
+	if b.status != breakerClosed {
+		b.setStatus(breakerClosed)
+	}
+}
+
+func (b *breaker) failure(policy types.HttpClientPolicy) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if policy.BreakerThreshold <= 0 {
+		return
+	}
+	if b.status == breakerHalfOpen || b.failures >= policy.BreakerThreshold {
+		b.openedAt = time.Now()
+		b.setStatus(breakerOpen)
+	}
+}
+
+func (b *breaker) setStatus(status breakerStatus) {
+	b.status = status
+	breakerState.WithLabelValues(b.target, b.host).Set(float64(status))
+}
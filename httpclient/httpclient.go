@@ -0,0 +1,224 @@
+// Package httpclient provides the http clients of the calls to external services like the nodes, relays, price apis
+// and push providers. Every target has a timeout per attempt, retries idempotent requests with a jittered backoff and
+// opens a circuit breaker per host after consecutive failures, so a slow or failing host fails fast instead of
+// stalling its caller.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Targets are the external services the policies are configured for
+const (
+	TargetBeaconNode    = "beaconNode"
+	TargetExecutionNode = "executionNode"
+	TargetRelays        = "relays"
+	TargetPrices        = "prices"
+	TargetPush          = "push"
+)
+
+// ErrCircuitOpen is returned for requests to a host whose circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_client_requests_total",
+		Help: "Total number of requests to external services by target and status code, failed requests have the status error and rejected requests circuit_open",
+	}, []string{"target", "status_code"})
+	retriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_client_retries_total",
+		Help: "Total number of retried requests to external services by target",
+	}, []string{"target"})
+	breakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_client_circuit_breaker_state",
+		Help: "State of the circuit breakers of external services by target and host: 0 closed, 1 half-open, 2 open",
+	}, []string{"target", "host"})
+)
+
+var defaultPolicies = map[string]types.HttpClientPolicy{
+	TargetBeaconNode:    {Timeout: time.Minute * 2, Retries: 2, RetryBackoff: time.Millisecond * 500, BreakerThreshold: 10, BreakerCooldown: time.Second * 30},
+	TargetExecutionNode: {Timeout: time.Minute * 2, Retries: 2, RetryBackoff: time.Millisecond * 500, BreakerThreshold: 10, BreakerCooldown: time.Second * 30},
+	TargetRelays:        {Timeout: time.Second * 30, Retries: 2, RetryBackoff: time.Second, BreakerThreshold: 5, BreakerCooldown: time.Minute * 5},
+	TargetPrices:        {Timeout: time.Second * 10, Retries: 2, RetryBackoff: time.Second, BreakerThreshold: 5, BreakerCooldown: time.Minute},
+	TargetPush:          {Timeout: time.Second * 30, Retries: 1, RetryBackoff: time.Second, BreakerThreshold: 5, BreakerCooldown: time.Minute},
+}
+
+// defaultPolicy is the policy of targets without a default policy
+var defaultPolicy = types.HttpClientPolicy{Timeout: time.Second * 30, RetryBackoff: time.Second, BreakerThreshold: 5, BreakerCooldown: time.Minute}
+
+var policiesMu sync.RWMutex
+var policies = defaultPolicies
+
+// Configure sets the policies of the targets, unset fields of a policy keep the defaults of the target
+func Configure(configured map[string]types.HttpClientPolicy) {
+	merged := make(map[string]types.HttpClientPolicy, len(defaultPolicies)+len(configured))
+	for target, policy := range defaultPolicies {
+		merged[target] = policy
+	}
+	for target, policy := range configured {
+		p, ok := merged[target]
+		if !ok {
+			p = defaultPolicy
+		}
+		if policy.Timeout != 0 {
+			p.Timeout = policy.Timeout
+		}
+		if policy.Retries != 0 {
+			p.Retries = policy.Retries
+		}
+		if policy.RetryBackoff != 0 {
+			p.RetryBackoff = policy.RetryBackoff
+		}
+		if policy.BreakerThreshold != 0 {
+			p.BreakerThreshold = policy.BreakerThreshold
+		}
+		if policy.BreakerCooldown != 0 {
+			p.BreakerCooldown = policy.BreakerCooldown
+		}
+		merged[target] = p
+	}
+
+	policiesMu.Lock()
+	policies = merged
+	policiesMu.Unlock()
+}
+
+func getPolicy(target string) types.HttpClientPolicy {
+	policiesMu.RLock()
+	defer policiesMu.RUnlock()
+	if policy, ok := policies[target]; ok {
+		return policy
+	}
+	return defaultPolicy
+}
+
+// New returns a client applying the policy of the target to its requests, the policy is looked up on every request so
+// clients can be created before the config is read. The base transport defaults to http.DefaultTransport. The
+// timeout covers the whole response including its body, so the client is not suited for streamed responses.
+func New(target string, base http.RoundTripper) *http.Client {
+	return &http.Client{Transport: NewTransport(target, base)}
+}
+
+// NewTransport returns the transport of the clients returned by New
+func NewTransport(target string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{target: target, base: base}
+}
+
+type transport struct {
+	target string
+	base   http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	policy := getPolicy(t.target)
+	breaker := getBreaker(t.target, req.URL.Host)
+	if !breaker.allow(policy) {
+		requestsTotal.WithLabelValues(t.target, "circuit_open").Inc()
+		return nil, fmt.Errorf("%w for %v host %v", ErrCircuitOpen, t.target, req.URL.Host)
+	}
+
+	retries := 0
+	if isIdempotent(req) {
+		retries = policy.Retries
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.attempt(req, policy.Timeout)
+		if err != nil {
+			requestsTotal.WithLabelValues(t.target, "error").Inc()
+		} else {
+			requestsTotal.WithLabelValues(t.target, strconv.Itoa(resp.StatusCode)).Inc()
+		}
+		if !isFailure(resp, err) {
+			breaker.success()
+			return resp, nil
+		}
+		if attempt >= retries || req.Context().Err() != nil {
+			breaker.failure(policy)
+			return resp, err
+		}
+
+		if resp != nil {
+			// the connection can only be reused if the body is read to the end
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+			resp.Body.Close()
+		}
+		retriesTotal.WithLabelValues(t.target).Inc()
+		select {
+		case <-time.After(backoff(policy.RetryBackoff, attempt)):
+		case <-req.Context().Done():
+			breaker.failure(policy)
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// attempt sends the request with the timeout, the timeout is cancelled when the body of the response is closed
+func (t *transport) attempt(req *http.Request, timeout time.Duration) (*http.Response, error) {
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+	if timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// isIdempotent returns whether the request can be sent again, requests with a body must be able to recreate it
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	}
+	return false
+}
+
+func isFailure(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// backoff returns the exponential backoff of the attempt with a jitter of +-50%
+func backoff(base time.Duration, attempt int) time.Duration {
+	d := base << attempt
+	return d/2 + time.Duration(rand.Int63n(int64(d)+1))
+}
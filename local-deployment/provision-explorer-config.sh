@@ -125,5 +125,5 @@ go run ./cmd/misc/main.go -config local-deployment/config.yml -command initBigta
 echo "bigtable schema initialization completed"
 
 echo "provisioning postgres db schema"
-go run ./cmd/misc/main.go -config local-deployment/config.yml -command applyDbSchema
+go run ./cmd/misc/main.go -config local-deployment/config.yml -command migrate up
 echo "postgres db schema initialization completed"
\ No newline at end of file
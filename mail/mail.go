@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
-	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 
@@ -19,27 +18,33 @@ import (
 type MailTemplate struct {
 	Mail   types.Email
 	Domain string
+	Locale string
 }
 
 // SendMail sends an email to the given address with the given message.
 // It will use smtp if configured otherwise it will use gunmail if configured.
 func SendHTMLMail(to, subject string, msg types.Email, attachment []types.EmailAttachment) error {
-	var renderer = templates.GetTemplate("mail/layout.html")
-
 	var err error
 	var body bytes.Buffer
+	var content []byte
 
 	if utils.Config.Frontend.Mail.SMTP.User != "" {
+		content, err = RenderMail(msg)
+		if err != nil {
+			return err
+		}
 		headers := "MIME-version: 1.0;\nContent-Type: text/html;"
 		body.Write([]byte(fmt.Sprintf("To: %s\r\nSubject: %s\r\n%s\r\n", to, subject, headers)))
-		renderer.Execute(&body, MailTemplate{Mail: msg, Domain: utils.Config.Frontend.SiteDomain})
+		body.Write(content)
 
 		fmt.Println("Email Attachments will not work with SMTP server")
 		err = SendMailSMTP(to, body.Bytes())
 	} else if utils.Config.Frontend.Mail.Mailgun.PrivateKey != "" {
-		_ = renderer.ExecuteTemplate(&body, "layout", MailTemplate{Mail: msg, Domain: utils.Config.Frontend.SiteDomain})
-		content := body.String()
-		err = SendMailMailgun(to, subject, content, createTextMessage(msg), attachment)
+		content, err = RenderMail(msg)
+		if err != nil {
+			return err
+		}
+		err = SendMailMailgun(to, subject, string(content), createTextMessage(msg), attachment)
 	} else {
 		utils.LogError(nil, "error sending reset-email: invalid config for mail-service", 0)
 		err = nil
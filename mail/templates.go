@@ -0,0 +1,203 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/templates"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// DefaultLocale is the locale of users without a supported locale, its templates are stored in templates/mail
+const DefaultLocale = "en-US"
+
+// Locales are the locales emails can be sent in, which are the languages of the frontend. The templates of a locale
+// other than the DefaultLocale are stored in templates/mail/<locale> and fall back to the ones of the DefaultLocale if
+// missing. Texts shared by all locales are translated with trLang and the mail_* keys of the locale files.
+var Locales = utils.Languages
+
+// The templates are looked up in the directory configured with frontend.mail.templatesPath first, so they can be
+// changed without a new release. Templates compiled from MJML can be dropped in as long as they define the same
+// blocks as the embedded ones: "layout" for layout.html, "notifications" for notifications.html and "event" for the
+// templates in events/, of which events/<event name>.html takes precedence over events/default.html.
+var mailTemplateCache = make(map[string]*template.Template)
+var mailTemplateCacheMux = &sync.RWMutex{}
+
+// NormalizeLocale returns the locale if emails can be sent in it and the DefaultLocale otherwise
+func NormalizeLocale(locale string) string {
+	for _, l := range Locales {
+		if l == locale {
+			return l
+		}
+	}
+	return DefaultLocale
+}
+
+// getMailTemplate returns the first of the templates found in the templates path or the embedded templates, the
+// template of the locale is preferred over the one of the DefaultLocale
+func getMailTemplate(locale string, names ...string) (*template.Template, error) {
+	locale = NormalizeLocale(locale)
+	candidates := []string{}
+	if locale != DefaultLocale {
+		for _, name := range names {
+			candidates = append(candidates, path.Join("mail", locale, name))
+		}
+	}
+	for _, name := range names {
+		candidates = append(candidates, path.Join("mail", name))
+	}
+
+	for _, candidate := range candidates {
+		// overrides are parsed on every use so changes take effect immediately
+		if dir := utils.Config.Frontend.Mail.TemplatesPath; dir != "" {
+			overrides := os.DirFS(dir)
+			if _, err := fs.Stat(overrides, candidate); err == nil {
+				return parseMailTemplate(overrides, candidate)
+			}
+		}
+		if _, err := fs.Stat(templates.Files, candidate); err != nil {
+			continue
+		}
+		if utils.Config.Frontend.Debug {
+			return parseMailTemplate(os.DirFS("templates"), candidate)
+		}
+
+		mailTemplateCacheMux.RLock()
+		tmpl := mailTemplateCache[candidate]
+		mailTemplateCacheMux.RUnlock()
+		if tmpl != nil {
+			return tmpl, nil
+		}
+		tmpl, err := parseMailTemplate(templates.Files, candidate)
+		if err != nil {
+			return nil, err
+		}
+		mailTemplateCacheMux.Lock()
+		mailTemplateCache[candidate] = tmpl
+		mailTemplateCacheMux.Unlock()
+		return tmpl, nil
+	}
+	return nil, fmt.Errorf("no mail template found for %v in locale %v", names, locale)
+}
+
+func parseMailTemplate(fsys fs.FS, name string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(utils.GetTemplateFuncs()).ParseFS(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing mail template %v: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func executeMailTemplate(locale, block string, data interface{}, names ...string) (template.HTML, error) {
+	tmpl, err := getMailTemplate(locale, names...)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = tmpl.ExecuteTemplate(&buf, block, data)
+	if err != nil {
+		return "", fmt.Errorf("error executing mail template %v: %w", tmpl.Name(), err)
+	}
+	return template.HTML(buf.String()), nil
+}
+
+// NotificationSection are the notifications of one event of a notification email
+type NotificationSection struct {
+	Event         types.EventName
+	Label         string
+	Notifications []template.HTML
+	// Info is a summary of all notifications of the event
+	Info template.HTML
+}
+
+type notificationSectionTemplate struct {
+	NotificationSection
+	Locale string
+	Domain string
+}
+
+type notificationsTemplate struct {
+	Locale   string
+	Domain   string
+	Network  string
+	Sections []template.HTML
+}
+
+// RenderNotificationMail renders the body of a notification email with the sections in the given locale
+func RenderNotificationMail(locale string, sections []NotificationSection) (template.HTML, error) {
+	locale = NormalizeLocale(locale)
+	data := notificationsTemplate{
+		Locale:   locale,
+		Domain:   utils.Config.Frontend.SiteDomain,
+		Sections: make([]template.HTML, 0, len(sections)),
+	}
+	if utils.Config.Chain.Name != "mainnet" {
+		data.Network = utils.Config.Chain.Name
+	}
+
+	for _, section := range sections {
+		content, err := executeMailTemplate(locale, "event", notificationSectionTemplate{
+			NotificationSection: section,
+			Locale:              locale,
+			Domain:              data.Domain,
+		}, path.Join("events", string(section.Event)+".html"), "events/default.html")
+		if err != nil {
+			return "", err
+		}
+		data.Sections = append(data.Sections, content)
+	}
+
+	return executeMailTemplate(locale, "notifications", data, "notifications.html")
+}
+
+// RenderMail renders the email with the layout of its locale
+func RenderMail(msg types.Email) ([]byte, error) {
+	if msg.Locale == "" {
+		msg.Locale = DefaultLocale
+	}
+	content, err := executeMailTemplate(msg.Locale, "layout", MailTemplate{
+		Mail:   msg,
+		Domain: utils.Config.Frontend.SiteDomain,
+		Locale: NormalizeLocale(msg.Locale),
+	}, "layout.html")
+	if err != nil {
+		return nil, err
+	}
+	return []byte(content), nil
+}
+
+// PreviewNotificationMail returns a notification email with example notifications of the events, it is used to
+// preview and test-send the templates
+func PreviewNotificationMail(locale string, events []types.EventName) (types.Email, error) {
+	msg := types.Email{Locale: NormalizeLocale(locale)}
+	sections := make([]NotificationSection, 0, len(events))
+	for _, event := range events {
+		label, ok := types.EventLabel[event]
+		if !ok {
+			return msg, fmt.Errorf("unknown event %v", event)
+		}
+		sections = append(sections, NotificationSection{
+			Event: event,
+			Label: label,
+			Notifications: []template.HTML{
+				template.HTML(fmt.Sprintf(`Example notification of validator <a href="https://%s/validator/1">1</a>`, utils.Config.Frontend.SiteDomain)),
+				template.HTML(fmt.Sprintf(`Example notification of validator <a href="https://%s/validator/2">2</a>`, utils.Config.Frontend.SiteDomain)),
+			},
+		})
+	}
+
+	body, err := RenderNotificationMail(msg.Locale, sections)
+	if err != nil {
+		return msg, err
+	}
+	msg.Body = body
+	msg.UnSubURL = template.HTML(fmt.Sprintf(`<a style="color: white" href="https://%s/notifications/unsubscribe">%s</a>`, utils.Config.Frontend.SiteDomain, utils.TrLang(msg.Locale, "mail_unsubscribe_link")))
+	msg.SubscriptionManageURL = template.HTML(fmt.Sprintf(`<a style="color: white" href="https://%s/user/notifications">%s</a>`, utils.Config.Frontend.SiteDomain, utils.TrLang(msg.Locale, "mail_manage_link")))
+	return msg, nil
+}
@@ -50,6 +50,76 @@ var (
 		Name: "db_long_running_queries",
 		Help: "Counter of long-running-queries with database and query in labels",
 	}, []string{"database", "query"})
+	DBReplicaLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "db_replica_lag_seconds",
+		Help: "Replication lag of the reader database replicas in seconds, -1 if the replica is unreachable",
+	}, []string{"replica"})
+	RetentionPrunableRows = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "retention_prunable_rows",
+		Help: "Rows older than the retention period by table, updated on every retention run including dry runs",
+	}, []string{"table"})
+	RetentionPrunableBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "retention_prunable_bytes",
+		Help: "Estimated size of the rows older than the retention period by table",
+	}, []string{"table"})
+	RetentionPrunedRows = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "retention_pruned_rows",
+		Help: "Counter of rows pruned by the retention service by table",
+	}, []string{"table"})
+	RetentionReclaimedBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "retention_reclaimed_bytes",
+		Help: "Estimated size of the rows pruned by the retention service by table, the space is reusable after the next vacuum",
+	}, []string{"table"})
+	BigtableQueuedMutations = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "bigtable_queued_mutations",
+		Help: "Number of bigtable mutations that failed to apply and are queued for replay",
+	})
+	ConsistencyMismatches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "consistency_mismatches",
+		Help: "Counter of mismatches between the stored data and the nodes found by the consistency audit by check and source",
+	}, []string{"check", "source"})
+	ExportGaps = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "export_gaps",
+		Help: "Number of slots, epochs and execution blocks missing in the exported data that are queued for re-export by kind",
+	}, []string{"kind"})
+	HttpRequestsDBDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_requests_db_duration",
+		Help:    "Cumulative duration of the db queries of HTTP requests in seconds by path",
+		Buckets: []float64{.005, .01, .05, .1, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"path"})
+	HttpRequestsDBQueries = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_requests_db_queries",
+		Help:    "Number of db queries of HTTP requests by path",
+		Buckets: []float64{0, 1, 2, 5, 10, 25, 50, 100, 250},
+	}, []string{"path"})
+	HttpRequestsOverDBBudget = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_over_db_budget",
+		Help: "Counter of HTTP requests exceeding the db query budget by path",
+	}, []string{"path"})
+	ExporterHead = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "exporter_head",
+		Help: "Position (slot, epoch, block or day) an exporter has exported up to by exporter",
+	}, []string{"exporter"})
+	ExporterLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "exporter_lag",
+		Help: "Distance of the position of an exporter to the chain head in the unit of the exporter by exporter",
+	}, []string{"exporter"})
+	ExporterRowsPerSecond = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "exporter_rows_per_second",
+		Help: "Throughput of an exporter in exported rows per second by exporter",
+	}, []string{"exporter"})
+	ExporterCatchUpSeconds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "exporter_catch_up_seconds",
+		Help: "Estimated time in seconds until an exporter has caught up with the chain head by exporter, 0 if it is in sync and -1 if it is not catching up",
+	}, []string{"exporter"})
+	CacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_hits",
+		Help: "Counter of cache hits by key type and tier (local, remote)",
+	}, []string{"key_type", "tier"})
+	CacheMisses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_misses",
+		Help: "Counter of cache misses by key type",
+	}, []string{"key_type"})
 	Errors = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "errors",
 		Help: "Counter of errors with name in labels",
@@ -72,7 +142,7 @@ var (
 	}, []string{"name"})
 )
 
-var logger = logrus.New().WithField("module", "metrics")
+var logger = utils.NewLogger("metrics")
 
 func init() {
 	Version.WithLabelValues(version.Version).Set(1)
@@ -9,11 +9,10 @@ import (
 
 	firebase "firebase.google.com/go/v4"
 	"firebase.google.com/go/v4/messaging"
-	"github.com/sirupsen/logrus"
 	"google.golang.org/api/option"
 )
 
-var logger = logrus.New().WithField("module", "notify").WithField("service", "firebase")
+var logger = utils.NewLogger("notify").WithField("channel", "firebase")
 
 func isRelevantError(response *messaging.SendResponse) bool {
 	if !response.Success && response.Error != nil {
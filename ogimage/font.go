@@ -0,0 +1,90 @@
+package ogimage
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// the go font is embedded in the binary so the images look the same on all hosts
+var boldFont *opentype.Font
+
+func init() {
+	var err error
+	boldFont, err = opentype.Parse(gobold.TTF)
+	if err != nil {
+		panic(fmt.Sprintf("error parsing preview image font: %v", err))
+	}
+}
+
+// face returns the font face of the given size in pixels, faces are not safe for concurrent use so every canvas keeps
+// its own faces
+func (c *canvas) face(size int) font.Face {
+	if f, ok := c.faces[size]; ok {
+		return f
+	}
+	f, err := opentype.NewFace(boldFont, &opentype.FaceOptions{
+		Size:    float64(size),
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		// the size is always valid and the font has already been parsed
+		panic(fmt.Sprintf("error creating preview image font face: %v", err))
+	}
+	c.faces[size] = f
+	return f
+}
+
+// textWidth returns the width in pixels of the text drawn with the given size
+func (c *canvas) textWidth(text string, size int) int {
+	return font.MeasureString(c.face(size), text).Ceil()
+}
+
+// textHeight returns the height in pixels of a line of text drawn with the given size
+func (c *canvas) textHeight(size int) int {
+	m := c.face(size).Metrics()
+	return (m.Ascent + m.Descent).Ceil()
+}
+
+// text draws the text with its top left corner at x, y and returns the x coordinate after the text
+func (c *canvas) text(x, y, size int, text string, col color.Color) int {
+	face := c.face(size)
+	d := &font.Drawer{
+		Dst:  c.img,
+		Src:  image.NewUniform(col),
+		Face: face,
+		Dot:  fixed.P(x, y+face.Metrics().Ascent.Ceil()),
+	}
+	d.DrawString(text)
+	return d.Dot.X.Ceil()
+}
+
+// truncate shortens the text so it fits into width pixels when drawn with the given size
+func (c *canvas) truncate(text string, size, width int) string {
+	if c.textWidth(text, size) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && c.textWidth(string(runes)+"…", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// fitSize returns the largest size up to max the text fits into width pixels with, but at least min
+func (c *canvas) fitSize(text string, min, max, width int) int {
+	size := max
+	for size > min && c.textWidth(text, size) > width {
+		size -= 2
+	}
+	if size < min {
+		size = min
+	}
+	return size
+}
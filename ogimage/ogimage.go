@@ -0,0 +1,299 @@
+// Package ogimage renders the preview images (OpenGraph / twitter cards) of validators and blocks that are shown when
+// links are shared on social media or embedded in notification emails.
+package ogimage
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strings"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"golang.org/x/image/font"
+)
+
+// Width and Height are the dimensions of the preview images recommended by OpenGraph
+const (
+	Width  = 1200
+	Height = 630
+	margin = 60
+)
+
+// Theme are the colors a preview image is drawn with
+type Theme struct {
+	Background color.RGBA
+	Foreground color.RGBA
+	Muted      color.RGBA
+	Accent     color.RGBA
+	Success    color.RGBA
+	Warning    color.RGBA
+	Danger     color.RGBA
+}
+
+var LightTheme = Theme{
+	Background: color.RGBA{0xff, 0xff, 0xff, 0xff},
+	Foreground: color.RGBA{0x21, 0x25, 0x29, 0xff},
+	Muted:      color.RGBA{0x6c, 0x75, 0x7d, 0xff},
+	Accent:     color.RGBA{0x34, 0x98, 0xdb, 0xff},
+	Success:    color.RGBA{0x28, 0xa7, 0x45, 0xff},
+	Warning:    color.RGBA{0xf0, 0xad, 0x4e, 0xff},
+	Danger:     color.RGBA{0xdc, 0x35, 0x45, 0xff},
+}
+
+var DarkTheme = Theme{
+	Background: color.RGBA{0x18, 0x1a, 0x1b, 0xff},
+	Foreground: color.RGBA{0xe8, 0xe6, 0xe3, 0xff},
+	Muted:      color.RGBA{0xa8, 0xa0, 0x95, 0xff},
+	Accent:     color.RGBA{0x3d, 0xa5, 0xe8, 0xff},
+	Success:    color.RGBA{0x4c, 0xc2, 0x66, 0xff},
+	Warning:    color.RGBA{0xf5, 0xc0, 0x6f, 0xff},
+	Danger:     color.RGBA{0xe8, 0x5c, 0x69, 0xff},
+}
+
+// GetTheme returns the dark theme if dark is set and the light theme otherwise
+func GetTheme(dark bool) Theme {
+	if dark {
+		return DarkTheme
+	}
+	return LightTheme
+}
+
+// canvas is a preview image that is drawn on
+type canvas struct {
+	img   *image.RGBA
+	theme Theme
+	faces map[int]font.Face
+}
+
+func newCanvas(theme Theme) *canvas {
+	c := &canvas{
+		img:   image.NewRGBA(image.Rect(0, 0, Width, Height)),
+		theme: theme,
+		faces: map[int]font.Face{},
+	}
+	draw.Draw(c.img, c.img.Bounds(), &image.Uniform{theme.Background}, image.Point{}, draw.Src)
+	// brand bar at the top
+	c.rect(image.Rect(0, 0, Width, 12), theme.Accent)
+	return c
+}
+
+func (c *canvas) rect(r image.Rectangle, col color.Color) {
+	draw.Draw(c.img, r, &image.Uniform{col}, image.Point{}, draw.Over)
+}
+
+// line draws a line of the given thickness from x0, y0 to x1, y1
+func (c *canvas) line(x0, y0, x1, y1 float64, thickness int, col color.Color) {
+	steps := int(math.Max(math.Abs(x1-x0), math.Abs(y1-y0)))
+	if steps == 0 {
+		steps = 1
+	}
+	half := thickness / 2
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x := int(math.Round(x0 + (x1-x0)*t))
+		y := int(math.Round(y0 + (y1-y0)*t))
+		c.rect(image.Rect(x-half, y-half, x-half+thickness, y-half+thickness), col)
+	}
+}
+
+// sparkline draws the values as line chart into r, the area below the line is filled with a translucent color
+func (c *canvas) sparkline(r image.Rectangle, values []float64, col color.RGBA) {
+	if len(values) < 2 {
+		return
+	}
+	min, max := values[0], values[0]
+	for _, v := range values {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+	}
+	if max == min {
+		max = min + 1
+	}
+
+	point := func(i int) (float64, float64) {
+		x := float64(r.Min.X) + float64(r.Dx())*float64(i)/float64(len(values)-1)
+		y := float64(r.Max.Y) - float64(r.Dy())*(values[i]-min)/(max-min)
+		return x, y
+	}
+
+	fill := color.RGBA{col.R / 4, col.G / 4, col.B / 4, 0x40}
+	for i := 1; i < len(values); i++ {
+		x0, y0 := point(i - 1)
+		x1, y1 := point(i)
+		for x := int(x0); x < int(x1); x++ {
+			y := y0 + (y1-y0)*(float64(x)-x0)/(x1-x0)
+			c.rect(image.Rect(x, int(y), x+1, r.Max.Y), fill)
+		}
+	}
+	for i := 1; i < len(values); i++ {
+		x0, y0 := point(i - 1)
+		x1, y1 := point(i)
+		c.line(x0, y0, x1, y1, 4, col)
+	}
+}
+
+func (c *canvas) png() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	err := png.Encode(buf, c.img)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding preview image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// header draws the site name, the title and the subtitle and returns the y coordinate below them
+func (c *canvas) header(title, subtitle string) int {
+	y := 44
+	c.text(margin, y, 32, utils.Config.Frontend.SiteName, c.theme.Muted)
+	y += 64
+	size := c.fitSize(title, 64, 96, Width-2*margin)
+	c.text(margin, y, size, c.truncate(title, size, Width-2*margin), c.theme.Foreground)
+	y += c.textHeight(size) + 16
+	if subtitle != "" {
+		c.text(margin, y, 40, c.truncate(subtitle, 40, Width-2*margin), c.theme.Muted)
+		y += c.textHeight(40) + 16
+	}
+	return y
+}
+
+// field draws a label and its value in a column starting at x and returns the x coordinate of the next column
+func (c *canvas) field(x, y int, label, value string, col color.Color) int {
+	c.text(x, y, 26, strings.ToUpper(label), c.theme.Muted)
+	end := c.text(x, y+c.textHeight(26)+8, 36, value, col)
+	return int(math.Max(float64(end), float64(x+c.textWidth(strings.ToUpper(label), 26)))) + 60
+}
+
+// ValidatorImageData is the data shown on the preview image of a validator
+type ValidatorImageData struct {
+	Index  uint64
+	Name   string
+	Status string
+	// Balance and Income7d are in gwei, BalanceHistory are the daily end balances of the last days in gwei
+	Balance        uint64
+	Income7d       int64
+	BalanceHistory []uint64
+}
+
+// ValidatorImage renders the preview image of a validator as png
+func ValidatorImage(data *ValidatorImageData, theme Theme) ([]byte, error) {
+	c := newCanvas(theme)
+	y := c.header(fmt.Sprintf("Validator %d", data.Index), data.Name)
+
+	statusColor := theme.Muted
+	switch {
+	case strings.Contains(data.Status, "slashed") || strings.Contains(data.Status, "offline"):
+		statusColor = theme.Danger
+	case strings.HasPrefix(data.Status, "active"):
+		statusColor = theme.Success
+	case data.Status == "pending" || data.Status == "deposited":
+		statusColor = theme.Warning
+	}
+	incomeColor := theme.Success
+	if data.Income7d < 0 {
+		incomeColor = theme.Danger
+	}
+
+	x := c.field(margin, y, "Status", strings.ToUpper(strings.ReplaceAll(data.Status, "_", " ")), statusColor)
+	x = c.field(x, y, "Balance", formatGwei(int64(data.Balance), false), theme.Foreground)
+	c.field(x, y, "Income 7d", formatGwei(data.Income7d, true), incomeColor)
+
+	history := make([]float64, len(data.BalanceHistory))
+	for i, b := range data.BalanceHistory {
+		history[i] = float64(b)
+	}
+	c.sparkline(image.Rect(margin, Height-200, Width-margin, Height-margin), history, theme.Accent)
+
+	return c.png()
+}
+
+// MilestoneImageData is the data shown on the preview image of a validator milestone
+type MilestoneImageData struct {
+	Index       uint64
+	Name        string
+	Label       string
+	Description string
+	Date        string
+}
+
+// MilestoneImage renders the preview image of a milestone a validator reached as png
+func MilestoneImage(data *MilestoneImageData, theme Theme) ([]byte, error) {
+	c := newCanvas(theme)
+	y := c.header(fmt.Sprintf("Validator %d", data.Index), data.Name)
+
+	size := c.fitSize(data.Label, 56, 88, Width-2*margin)
+	c.text(margin, y, size, c.truncate(data.Label, size, Width-2*margin), theme.Success)
+	y += c.textHeight(size) + 12
+	c.text(margin, y, 36, c.truncate(data.Description, 36, Width-2*margin), theme.Foreground)
+
+	c.rect(image.Rect(margin, Height-margin-8, Width-margin, Height-margin), theme.Accent)
+	c.text(margin, Height-margin-8-24-c.textHeight(26), 26, data.Date, theme.Muted)
+
+	return c.png()
+}
+
+// BlockImageData is the data shown on the preview image of a block
+type BlockImageData struct {
+	Slot         uint64
+	Epoch        uint64
+	Status       string
+	Proposer     uint64
+	Transactions uint64
+	Attestations uint64
+	// EpochStatuses are the statuses of the slots of the epoch of the block, the block is highlighted
+	EpochStatuses []string
+}
+
+// BlockImage renders the preview image of a block as png
+func BlockImage(data *BlockImageData, theme Theme) ([]byte, error) {
+	c := newCanvas(theme)
+	y := c.header(fmt.Sprintf("Slot %d", data.Slot), fmt.Sprintf("Epoch %d", data.Epoch))
+
+	x := c.field(margin, y, "Status", strings.ToUpper(data.Status), slotStatusColor(theme, data.Status))
+	x = c.field(x, y, "Proposer", fmt.Sprintf("%d", data.Proposer), theme.Foreground)
+	x = c.field(x, y, "Transactions", fmt.Sprintf("%d", data.Transactions), theme.Foreground)
+	c.field(x, y, "Attestations", fmt.Sprintf("%d", data.Attestations), theme.Foreground)
+
+	// a row with the status of every slot of the epoch, similar to the slot visualization
+	if n := len(data.EpochStatuses); n > 0 {
+		slotsPerEpoch := utils.Config.Chain.ClConfig.SlotsPerEpoch
+		gap := 6
+		size := (Width - 2*margin - (n-1)*gap) / n
+		top := Height - margin - size
+		for i, status := range data.EpochStatuses {
+			x := margin + i*(size+gap)
+			if slotsPerEpoch > 0 && uint64(i) == data.Slot%slotsPerEpoch {
+				c.rect(image.Rect(x-4, top-4, x+size+4, top+size+4), theme.Accent)
+			}
+			c.rect(image.Rect(x, top, x+size, top+size), slotStatusColor(theme, status))
+		}
+	}
+
+	return c.png()
+}
+
+func slotStatusColor(theme Theme, status string) color.RGBA {
+	switch status {
+	case "proposed":
+		return theme.Success
+	case "missed":
+		return theme.Danger
+	case "orphaned":
+		return theme.Warning
+	default:
+		return theme.Muted
+	}
+}
+
+// formatGwei formats the gwei amount in the consensus layer currency with 5 decimals
+func formatGwei(gwei int64, showPlusSign bool) string {
+	sign := ""
+	if showPlusSign && gwei > 0 {
+		sign = "+"
+	}
+	return fmt.Sprintf("%s%s %s", sign, utils.ClToCurrency(gwei, utils.Config.Frontend.ClCurrency).StringFixed(5), utils.Config.Frontend.ClCurrency)
+}
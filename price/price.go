@@ -15,7 +15,6 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/shopspring/decimal"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/sync/errgroup"
 )
 
 var logger = logrus.New().WithField("module", "price")
@@ -27,17 +26,34 @@ var runOnceWg sync.WaitGroup
 var prices = map[string]float64{}
 var pricesMu = &sync.Mutex{}
 var didInit = uint64(0)
-var feeds = map[string]*chainlink_feed.Feed{}
+var providers = []provider{}
 var calcPairs = map[string]bool{}
 var clCurrency = "ETH"
 var elCurrency = "ETH"
+var cacheKey = ""
+var priceCache Cache
+
+// Cache is the shared cache the latest prices are published to, processes whose providers are all unavailable fall back
+// to the prices published by other processes
+type Cache interface {
+	Set(key string, value interface{}, expiration time.Duration) error
+	GetWithLocalTimeout(key string, localExpiration time.Duration, returnValue interface{}) (interface{}, error)
+}
+
+// SetCache sets the shared cache of the prices, it must be called before Init
+func SetCache(c Cache) {
+	priceCache = c
+}
 
 var currencies = map[string]struct {
 	Symbol string
 	Label  string
 }{
 	"AUD":  {"A$", "Australian Dollar"},
+	"BRL":  {"R$", "Brazilian Real"},
+	"BTC":  {"₿", "Bitcoin"},
 	"CAD":  {"C$", "Canadian Dollar"},
+	"CHF":  {"CHF", "Swiss Franc"},
 	"CNY":  {"¥", "Chinese Yuan"},
 	"DAI":  {"DAI", "DAI stablecoin"},
 	"xDAI": {"xDAI", "xDAI stablecoin"},
@@ -45,9 +61,14 @@ var currencies = map[string]struct {
 	"EUR":  {"€", "Euro"},
 	"GBP":  {"£", "Pound Sterling"},
 	"GNO":  {"GNO", "Gnosis"},
+	"GWEI": {"Gwei", "Gwei"},
+	"HKD":  {"HK$", "Hong Kong Dollar"},
+	"INR":  {"₹", "Indian Rupee"},
 	"mGNO": {"mGNO", "mGnosis"},
 	"JPY":  {"¥", "Japanese Yen"},
+	"KRW":  {"₩", "South Korean Won"},
 	"RUB":  {"₽", "Russian Ruble"},
+	"SGD":  {"S$", "Singapore Dollar"},
 	"USD":  {"$", "United States Dollar"},
 }
 
@@ -97,6 +118,7 @@ func Init(chainId uint64, eth1Endpoint, clCurrencyParam, elCurrencyParam string)
 	}
 
 	feedAddrs := map[string]string{}
+	coingeckoCoins := map[string]string{}
 	switch chainId {
 	case 1:
 		// see: https://docs.chain.link/data-feeds/price-feeds/addresses/
@@ -108,7 +130,8 @@ func Init(chainId uint64, eth1Endpoint, clCurrencyParam, elCurrencyParam string)
 		feedAddrs["GBP/USD"] = "0x5c0ab2d9b5a7ed9f470386e82bb36a3613cdd4b5"
 		feedAddrs["AUD/USD"] = "0x77f9710e7d0a19669a13c055f62cd80d313df022"
 
-		availableCurrencies = []string{"ETH", "USD", "EUR", "GBP", "CNY", "CAD", "AUD", "JPY"}
+		availableCurrencies = []string{"ETH", "USD", "EUR", "GBP", "CNY", "CAD", "AUD", "JPY", "CHF", "INR", "BRL", "KRW", "HKD", "SGD"}
+		coingeckoCoins = map[string]string{"ethereum": "ETH", "bitcoin": "BTC"}
 	case 5:
 		// see: https://docs.chain.link/data-feeds/price-feeds/addresses/
 		feedAddrs["ETH/USD"] = "0x694AA1769357215DE4FAC081bf1f309aDC325306"
@@ -138,10 +161,12 @@ func Init(chainId uint64, eth1Endpoint, clCurrencyParam, elCurrencyParam string)
 		calcPairs["GNO"] = true
 
 		availableCurrencies = []string{"GNO", "mGNO", "DAI", "ETH", "USD", "EUR", "JPY"}
+		coingeckoCoins = map[string]string{"ethereum": "ETH", "gnosis": "GNO", "dai": "DAI", "bitcoin": "BTC"}
 	default:
 		logger.Fatalf("unsupported chainId %v", chainId)
 	}
 
+	feeds := map[string]*chainlink_feed.Feed{}
 	for pair, addrHex := range feedAddrs {
 		feed, err := chainlink_feed.NewFeed(common.HexToAddress(addrHex), eClient)
 		if err != nil {
@@ -151,6 +176,24 @@ func Init(chainId uint64, eth1Endpoint, clCurrencyParam, elCurrencyParam string)
 		feeds[pair] = feed
 	}
 
+	// providers are queried in order, pairs of a provider are only used if no earlier provider returned them. The
+	// chainlink feeds are the primary source, coingecko supplies the remaining currencies and takes over if chainlink fails.
+	providers = []provider{&chainlinkProvider{feeds: feeds}}
+	if len(coingeckoCoins) > 0 {
+		coins := map[string]bool{"mGNO": true, "USD": true}
+		for _, c := range coingeckoCoins {
+			coins[c] = true
+		}
+		fiatCurrencies := []string{}
+		for _, c := range availableCurrencies {
+			if !coins[c] {
+				fiatCurrencies = append(fiatCurrencies, c)
+			}
+		}
+		providers = append(providers, newCoingeckoProvider(coingeckoCoins, "ethereum", fiatCurrencies))
+	}
+	cacheKey = fmt.Sprintf("%d:prices", chainId)
+
 	go func() {
 		for {
 			updatePrices()
@@ -160,31 +203,52 @@ func Init(chainId uint64, eth1Endpoint, clCurrencyParam, elCurrencyParam string)
 }
 
 func updatePrices() {
-	g := &errgroup.Group{}
-	for pair, feed := range feeds {
-		pair := pair
-		feed := feed
-		g.Go(func() error {
-			price, err := getPriceFromFeed(feed)
-			if err != nil {
-				return fmt.Errorf("error getting price from feed for %v: %w", pair, err)
-			}
-			pricesMu.Lock()
-			defer pricesMu.Unlock()
-			prices[pair] = price
-			if pair == "GNO/USD" {
-				prices["mGNO/USD"] = price / 32
+	fetched := map[string]float64{}
+	for _, p := range providers {
+		res, err := p.fetch()
+		if err != nil {
+			logger.WithError(err).Warnf("error fetching prices from provider %v", p.name())
+			continue
+		}
+		for pair, price := range res {
+			if _, exists := fetched[pair]; !exists {
+				fetched[pair] = price
 			}
-			return nil
-		})
+		}
 	}
-	err := g.Wait()
-	if err != nil {
-		logger.WithError(err).Errorf("error upating prices")
-		return
+
+	if len(fetched) == 0 {
+		if priceCache == nil {
+			logger.Errorf("error upating prices: all providers failed")
+			return
+		}
+		_, err := priceCache.GetWithLocalTimeout(cacheKey, time.Minute, &fetched)
+		if err != nil {
+			logger.WithError(err).Errorf("error upating prices: all providers failed and no cached prices are available")
+			return
+		}
+		logger.Warnf("all price providers failed, using cached prices")
+	} else if priceCache != nil {
+		err := priceCache.Set(cacheKey, fetched, time.Hour)
+		if err != nil {
+			logger.WithError(err).Errorf("error caching prices")
+		}
+	}
+
+	pricesMu.Lock()
+	for pair, price := range fetched {
+		prices[pair] = price
 	}
+	if p, exists := prices["GNO/USD"]; exists {
+		prices["mGNO/USD"] = p / 32
+	}
+	if p, exists := prices["ETH/USD"]; exists {
+		prices["GWEI/USD"] = p / 1e9
+	}
+	pricesMu.Unlock()
+
 	for p := range calcPairs {
-		if err = calcPricePairs(p); err != nil {
+		if err := calcPricePairs(p); err != nil {
 			logger.WithError(err).Errorf("error calculating price pairs for %v", p)
 			return
 		}
@@ -0,0 +1,116 @@
+package price
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/contracts/chainlink_feed"
+	"github.com/gobitfly/eth2-beaconchain-explorer/httpclient"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// provider fetches the current prices of the supported currencies, all pairs are quoted in USD (e.g. ETH/USD)
+type provider interface {
+	name() string
+	fetch() (map[string]float64, error)
+}
+
+// chainlinkProvider reads the prices from the chainlink price feed contracts of the network
+type chainlinkProvider struct {
+	feeds map[string]*chainlink_feed.Feed
+}
+
+func (p *chainlinkProvider) name() string {
+	return "chainlink"
+}
+
+func (p *chainlinkProvider) fetch() (map[string]float64, error) {
+	res := make(map[string]float64, len(p.feeds))
+	resMu := &sync.Mutex{}
+	g := &errgroup.Group{}
+	for pair, feed := range p.feeds {
+		pair := pair
+		feed := feed
+		g.Go(func() error {
+			price, err := getPriceFromFeed(feed)
+			if err != nil {
+				return fmt.Errorf("error getting price from feed for %v: %w", pair, err)
+			}
+			resMu.Lock()
+			defer resMu.Unlock()
+			res[pair] = price
+			return nil
+		})
+	}
+	err := g.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// coingeckoProvider reads the usd prices of the given coins and the fiat currencies from the coingecko api, the fiat
+// pairs are derived from the prices of the base coin
+type coingeckoProvider struct {
+	client     *http.Client
+	coins      map[string]string // coingecko coin id => currency
+	baseCoin   string
+	currencies []string
+}
+
+func (p *coingeckoProvider) name() string {
+	return "coingecko"
+}
+
+func (p *coingeckoProvider) fetch() (map[string]float64, error) {
+	ids := make([]string, 0, len(p.coins))
+	for id := range p.coins {
+		ids = append(ids, id)
+	}
+	url := fmt.Sprintf("https://api.coingecko.com/api/v3/simple/price?ids=%s&vs_currencies=usd,%s", strings.Join(ids, ","), strings.ToLower(strings.Join(p.currencies, ",")))
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting prices from coingecko: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error requesting prices from coingecko: unexpected status code %v", resp.StatusCode)
+	}
+
+	data := map[string]map[string]float64{}
+	err = json.NewDecoder(resp.Body).Decode(&data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding prices from coingecko: %w", err)
+	}
+
+	res := map[string]float64{}
+	for id, currency := range p.coins {
+		if usd := data[id]["usd"]; usd > 0 {
+			res[currency+"/USD"] = usd
+		}
+	}
+	base := data[p.baseCoin]
+	baseUsd := base["usd"]
+	if baseUsd == 0 {
+		return nil, fmt.Errorf("error requesting prices from coingecko: no usd price for %v", p.baseCoin)
+	}
+	for _, currency := range p.currencies {
+		if v := base[strings.ToLower(currency)]; v > 0 {
+			res[currency+"/USD"] = baseUsd / v
+		}
+	}
+	return res, nil
+}
+
+func newCoingeckoProvider(coins map[string]string, baseCoin string, currencies []string) *coingeckoProvider {
+	return &coingeckoProvider{
+		client:     httpclient.New(httpclient.TargetPrices, nil),
+		coins:      coins,
+		baseCoin:   baseCoin,
+		currencies: currencies,
+	}
+}
@@ -86,7 +86,7 @@ var weightsMu = &sync.RWMutex{}
 var weights = map[string]int64{}  // guarded by weightsMu
 var buckets = map[string]string{} // guarded by weightsMu
 
-var logger = logrus.StandardLogger().WithField("module", "ratelimit")
+var logger = utils.NewLogger("ratelimit")
 
 type DbEntry struct {
 	Date     time.Time
@@ -606,7 +606,13 @@ func updateRateLimits() error {
 		ChangedAt  time.Time `db:"changed_at"`
 	}{}
 
-	err = tx.Select(&dbApiKeys, `SELECT user_id, api_key, valid_until, changed_at FROM api_keys WHERE changed_at > $1 OR valid_until < NOW()`, lastTKeys)
+	// the api keys of organization members use the quota of the owner of the organization
+	err = tx.Select(&dbApiKeys, `
+		SELECT COALESCE(o.user_id, k.user_id) AS user_id, k.api_key, k.valid_until, k.changed_at
+		FROM api_keys k
+		LEFT JOIN organization_members m ON m.user_id = k.user_id
+		LEFT JOIN organization_members o ON o.organization_id = m.organization_id AND o.role = 'owner'
+		WHERE k.changed_at > $1 OR k.valid_until < NOW()`, lastTKeys)
 	if err != nil {
 		return fmt.Errorf("error getting api_keys: %w", err)
 	}
@@ -920,7 +926,7 @@ func max(vals ...int64) int64 {
 
 // getKey returns the key used for RateLimiting. It first checks the query params, then the header and finally the ip address.
 func getKey(r *http.Request) (key, ip string) {
-	ip = getIP(r)
+	ip = GetIP(r)
 	key = r.URL.Query().Get("apikey")
 	if key != "" {
 		return key, ip
@@ -961,8 +967,8 @@ func getRoute(r *http.Request) string {
 	return pathTpl
 }
 
-// getIP returns the ip address from the http request
-func getIP(r *http.Request) string {
+// GetIP returns the ip address from the http request
+func GetIP(r *http.Request) string {
 	ips := r.Header.Get("CF-Connecting-IP")
 	if ips == "" {
 		ips = r.Header.Get("X-Forwarded-For")
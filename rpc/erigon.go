@@ -5,12 +5,15 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/contracts/oneinchoracle"
 	"github.com/gobitfly/eth2-beaconchain-explorer/erc20"
+	"github.com/gobitfly/eth2-beaconchain-explorer/httpclient"
 	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/tracing"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 
@@ -44,17 +47,18 @@ func NewErigonClient(endpoint string) (*ErigonClient, error) {
 		endpoint: endpoint,
 	}
 
-	rpcClient, err := geth_rpc.Dial(client.endpoint)
+	httpClient := geth_rpc.WithHTTPClient(&http.Client{Transport: httpclient.NewTransport(httpclient.TargetExecutionNode, tracing.Transport(nil))})
+	rpcClient, err := geth_rpc.DialOptions(context.Background(), client.endpoint, httpClient)
 	if err != nil {
 		return nil, fmt.Errorf("error dialing rpc node: %w", err)
 	}
 	client.rpcClient = rpcClient
 
-	ethClient, err := ethclient.Dial(client.endpoint)
+	ethRpcClient, err := geth_rpc.DialOptions(context.Background(), client.endpoint, httpClient)
 	if err != nil {
 		return nil, fmt.Errorf("error dialing rpc node: %w", err)
 	}
-	client.ethClient = ethClient
+	client.ethClient = ethclient.NewClient(ethRpcClient)
 
 	client.multiChecker, err = NewBalance(common.HexToAddress("0xb1F8e55c7f64D203C1400B9D8555d050F94aDF39"), client.ethClient)
 	if err != nil {
@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/contracts/oneinchoracle"
+	"github.com/gobitfly/eth2-beaconchain-explorer/erc1155"
 	"github.com/gobitfly/eth2-beaconchain-explorer/erc20"
+	"github.com/gobitfly/eth2-beaconchain-explorer/erc721"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 
 	"github.com/ethereum/go-ethereum"
@@ -378,6 +380,47 @@ func (client *GethClient) GetERC20TokenBalance(address string, token string) ([]
 	return balance, nil
 }
 
+// GetERC1155TokenBalance returns the amount of the given erc1155 token id held by an address
+func (client *GethClient) GetERC1155TokenBalance(address []byte, token []byte, tokenId *big.Int) (*big.Int, error) {
+	contract, err := erc1155.NewErc1155(common.BytesToAddress(token), client.ethClient)
+	if err != nil {
+		return nil, fmt.Errorf("error getting token-contract: erc1155.NewErc1155: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	return contract.BalanceOf(&bind.CallOpts{Context: ctx}, common.BytesToAddress(address), tokenId)
+}
+
+// GetNFTTokenURI returns the metadata uri of an erc721 or erc1155 token, the {id} placeholder of erc1155 uris is replaced with the token id
+func (client *GethClient) GetNFTTokenURI(standard string, token []byte, tokenId *big.Int) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	opts := &bind.CallOpts{Context: ctx}
+
+	switch standard {
+	case types.NFTStandardERC721:
+		contract, err := erc721.NewErc721(common.BytesToAddress(token), client.ethClient)
+		if err != nil {
+			return "", fmt.Errorf("error getting token-contract: erc721.NewErc721: %w", err)
+		}
+		return contract.TokenURI(opts, tokenId)
+	case types.NFTStandardERC1155:
+		contract, err := erc1155.NewErc1155(common.BytesToAddress(token), client.ethClient)
+		if err != nil {
+			return "", fmt.Errorf("error getting token-contract: erc1155.NewErc1155: %w", err)
+		}
+		uri, err := contract.Uri(opts, tokenId)
+		if err != nil {
+			return "", err
+		}
+		return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", tokenId)), nil
+	default:
+		return "", fmt.Errorf("unknown nft standard %v", standard)
+	}
+}
+
 func (client *GethClient) GetERC20TokenMetadata(token []byte) (*types.ERC20Metadata, error) {
 	logger.Infof("retrieving metadata for token %x", token)
 
@@ -4,8 +4,7 @@ import (
 	"math/big"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
-
-	"github.com/sirupsen/logrus"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 )
 
 // Client provides an interface for RPC clients
@@ -30,4 +29,4 @@ type Eth1Client interface {
 	Close()
 }
 
-var logger = logrus.New().WithField("module", "rpc")
+var logger = utils.NewLogger("rpc")
@@ -15,6 +15,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gobitfly/eth2-beaconchain-explorer/httpclient"
+	"github.com/gobitfly/eth2-beaconchain-explorer/tracing"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 
@@ -1291,11 +1293,12 @@ func (lc *LighthouseClient) GetBlobSidecars(stateID string) (*StandardBlobSideca
 
 var errNotFound = errors.New("not found 404")
 
+var beaconNodeHttpClient = httpclient.New(httpclient.TargetBeaconNode, tracing.Transport(nil))
+
 func (lc *LighthouseClient) get(url string) ([]byte, error) {
 	// t0 := time.Now()
 	// defer func() { fmt.Println(url, time.Since(t0)) }()
-	client := &http.Client{Timeout: time.Minute * 2}
-	resp, err := client.Get(url)
+	resp, err := beaconNodeHttpClient.Get(url)
 	if err != nil {
 		return nil, err
 	}
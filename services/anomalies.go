@@ -0,0 +1,226 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+const (
+	// networkAnomalyWindowEpochs is the amount of epochs the baselines of the network metrics are computed from
+	networkAnomalyWindowEpochs = 450
+	// networkAnomalyWarmupEpochs is the amount of epochs at the start of the window which only build the baselines
+	networkAnomalyWarmupEpochs = 32
+	// networkAnomalyEwmaAlpha is the weight of the latest epoch in the exponentially weighted baselines
+	networkAnomalyEwmaAlpha = 0.05
+	// networkAnomalyZScoreThreshold is the amount of standard deviations a metric has to deviate from its baseline
+	networkAnomalyZScoreThreshold = 4.0
+	// networkAnomalyMaxFrozenEpochs is the amount of anomalous epochs after which the baselines adapt again, so that
+	// a lasting change of a metric does not remain an incident forever
+	networkAnomalyMaxFrozenEpochs = 225
+)
+
+// networkAnomalyMetric is a network metric watched by the anomaly detector
+type networkAnomalyMetric struct {
+	Name  string
+	Label string
+	Unit  string
+	Value func(*types.NetworkEpochMetrics) float64
+	// Direction is 1 if values above the baseline are anomalous and -1 if values below the baseline are
+	Direction float64
+	// MinDeviation is the minimum deviation from the baseline of an anomaly, it prevents incidents caused by tiny
+	// deviations of very stable metrics
+	MinDeviation float64
+}
+
+var networkAnomalyMetrics = []*networkAnomalyMetric{
+	{
+		Name:         "participation_rate",
+		Label:        "participation rate",
+		Unit:         "%",
+		Value:        func(m *types.NetworkEpochMetrics) float64 { return m.ParticipationRate },
+		Direction:    -1,
+		MinDeviation: 2,
+	},
+	{
+		Name:         "missed_slot_rate",
+		Label:        "missed slot rate",
+		Unit:         "%",
+		Value:        func(m *types.NetworkEpochMetrics) float64 { return m.MissedSlotRate },
+		Direction:    1,
+		MinDeviation: 10,
+	},
+	{
+		Name:         "attestation_delay",
+		Label:        "attestation inclusion delay",
+		Unit:         " slots",
+		Value:        func(m *types.NetworkEpochMetrics) float64 { return m.AttestationDelay },
+		Direction:    1,
+		MinDeviation: 0.5,
+	},
+}
+
+func getNetworkAnomalyMetric(name string) *networkAnomalyMetric {
+	for _, metric := range networkAnomalyMetrics {
+		if metric.Name == name {
+			return metric
+		}
+	}
+	return nil
+}
+
+// describeNetworkIncident returns a human readable summary of the incident
+func describeNetworkIncident(incident *types.ApiNetworkIncidentResponse) string {
+	metric := getNetworkAnomalyMetric(incident.Metric)
+	if metric == nil {
+		return fmt.Sprintf("%v anomaly in epochs %v to %v", incident.Metric, incident.StartEpoch, incident.EndEpoch)
+	}
+	verb := "rose"
+	if metric.Direction < 0 {
+		verb = "dropped"
+	}
+	return fmt.Sprintf("The %v %v to %.2f%v (expected %.2f%v) in epochs %v to %v", metric.Label, verb, incident.Value, metric.Unit, incident.Baseline, metric.Unit, incident.StartEpoch, incident.EndEpoch)
+}
+
+// networkIncidentPlotBands returns the network incidents as plot bands to annotate charts with
+func networkIncidentPlotBands() ([]*types.ChartPlotBand, error) {
+	incidents, err := db.GetNetworkIncidents(0, LatestEpoch())
+	if err != nil {
+		return nil, err
+	}
+
+	plotBands := make([]*types.ChartPlotBand, 0, len(incidents))
+	for _, incident := range incidents {
+		label := incident.Metric
+		if metric := getNetworkAnomalyMetric(incident.Metric); metric != nil {
+			label = metric.Label
+		}
+		plotBands = append(plotBands, &types.ChartPlotBand{
+			From:  utils.EpochToTime(incident.StartEpoch).UnixMilli(),
+			To:    utils.EpochToTime(incident.EndEpoch + 1).UnixMilli(),
+			Color: "rgba(220, 53, 69, 0.2)",
+			Label: &types.ChartPlotBandLabel{Text: label},
+		})
+	}
+	return plotBands, nil
+}
+
+// detectNetworkAnomalies tracks an exponentially weighted moving average and variance of the metric over the epochs
+// and returns the incidents in which the z-score of the metric exceeded networkAnomalyZScoreThreshold. The baseline is
+// not updated during anomalies, so that an incident does not quickly become the new normal.
+func detectNetworkAnomalies(metric *networkAnomalyMetric, epochs []*types.NetworkEpochMetrics) []*types.ApiNetworkIncidentResponse {
+	incidents := []*types.ApiNetworkIncidentResponse{}
+	var incident *types.ApiNetworkIncidentResponse
+	var mean, variance float64
+	frozenEpochs := 0
+
+	for i, epoch := range epochs {
+		value := metric.Value(epoch)
+		if i == 0 {
+			mean = value
+			continue
+		}
+
+		deviation := (value - mean) * metric.Direction
+		// the deviation of an anomaly must at least be MinDeviation, so the standard deviation is floored accordingly
+		stdDev := math.Max(math.Sqrt(variance), metric.MinDeviation/networkAnomalyZScoreThreshold)
+		zScore := deviation / stdDev
+
+		if i >= networkAnomalyWarmupEpochs && deviation >= metric.MinDeviation && zScore >= networkAnomalyZScoreThreshold {
+			if incident == nil || epoch.Epoch > incident.EndEpoch+db.NetworkIncidentMergeEpochs {
+				incident = &types.ApiNetworkIncidentResponse{
+					Metric:     metric.Name,
+					StartEpoch: epoch.Epoch,
+				}
+				incidents = append(incidents, incident)
+			}
+			incident.EndEpoch = epoch.Epoch
+			if zScore > incident.ZScore {
+				incident.Value = value
+				incident.Baseline = mean
+				incident.ZScore = zScore
+			}
+			frozenEpochs++
+			if frozenEpochs <= networkAnomalyMaxFrozenEpochs {
+				continue
+			}
+		} else {
+			frozenEpochs = 0
+		}
+
+		diff := value - mean
+		increment := networkAnomalyEwmaAlpha * diff
+		mean += increment
+		variance = (1 - networkAnomalyEwmaAlpha) * (variance + diff*increment)
+	}
+
+	return incidents
+}
+
+// networkAnomalyEpochs holds the metrics of the epochs of the anomaly window loaded so far, it is only accessed by the
+// network anomaly updater
+var networkAnomalyEpochs []*types.NetworkEpochMetrics
+
+// detectNetworkIncidents runs the anomaly detection over the recent epochs and stores the detected incidents
+func detectNetworkIncidents(epoch uint64) error {
+	// the participation rate of the latest epochs is not final yet
+	if epoch < 2 {
+		return nil
+	}
+	toEpoch := epoch - 2
+	fromEpoch := uint64(0)
+	if toEpoch > networkAnomalyWindowEpochs {
+		fromEpoch = toEpoch - networkAnomalyWindowEpochs
+	}
+
+	// only the epochs that were not loaded by a previous run are read from the db
+	loadFrom := fromEpoch
+	if n := len(networkAnomalyEpochs); n > 0 && networkAnomalyEpochs[n-1].Epoch >= fromEpoch {
+		loadFrom = networkAnomalyEpochs[n-1].Epoch + 1
+	}
+	if loadFrom > toEpoch && len(networkAnomalyEpochs) > 0 {
+		return nil
+	}
+
+	newEpochs, err := db.GetNetworkEpochMetrics(loadFrom, toEpoch)
+	if err != nil {
+		return err
+	}
+	epochs := append(networkAnomalyEpochs, newEpochs...)
+	first := sort.Search(len(epochs), func(i int) bool { return epochs[i].Epoch >= fromEpoch })
+	networkAnomalyEpochs = epochs[first:]
+
+	incidents := []*types.ApiNetworkIncidentResponse{}
+	for _, metric := range networkAnomalyMetrics {
+		incidents = append(incidents, detectNetworkAnomalies(metric, networkAnomalyEpochs)...)
+	}
+
+	return db.SaveNetworkIncidents(incidents)
+}
+
+func networkAnomalyUpdater(wg *sync.WaitGroup) {
+	firstRun := true
+
+	for {
+		err := detectNetworkIncidents(LatestEpoch())
+		if err != nil {
+			logger.Errorf("error detecting network incidents: %v", err)
+			time.Sleep(time.Second * 10)
+			continue
+		}
+
+		if firstRun {
+			logger.Info("initialized network anomaly updater")
+			wg.Done()
+			firstRun = false
+		}
+		ReportStatus("networkAnomalyUpdater", "Running", nil)
+		time.Sleep(time.Second * time.Duration(utils.Config.Chain.ClConfig.SecondsPerSlot*utils.Config.Chain.ClConfig.SlotsPerEpoch))
+	}
+}
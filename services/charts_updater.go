@@ -6,6 +6,7 @@ import (
 	"html/template"
 	"math"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -46,6 +47,7 @@ var ChartHandlers = map[string]chartHandler{
 	"graffiti_wordcloud":             {14, graffitiCloudChartData},
 	"pools_distribution":             {15, poolsDistributionChartData},
 	"historic_pool_performance":      {16, historicPoolPerformanceData},
+	"pool_luck":                      {18, poolLuckChartData},
 
 	// execution charts start with 20+
 
@@ -59,6 +61,7 @@ var ChartHandlers = map[string]chartHandler{
 	"avg_gas_limit_chart_data":  {28, AvgGasLimitChartData},
 	"avg_block_util_chart_data": {29, AvgBlockUtilChartData},
 	"tx_count_chart_data":       {31, TxCountChartData},
+	"dvt_adoption":              {33, dvtAdoptionChartData},
 	// "avg_block_size_chart_data":          {32, AvgBlockSizeChartData},
 }
 
@@ -265,23 +268,56 @@ func activeValidatorsChartData() (*types.GenericChartData, error) {
 		return nil, fmt.Errorf("chart-data not available pre-genesis")
 	}
 
+	data := []struct {
+		Time  time.Time `db:"time"`
+		Value float64   `db:"value"`
+	}{}
+
+	err := db.ReaderDb.Select(&data, "SELECT time, value FROM chart_series WHERE indicator = 'ACTIVE_VALIDATORS' ORDER BY time")
+	if err != nil {
+		return nil, err
+	}
+
+	// the daily series only covers the days since the statistics have been exported, the days before and the days that
+	// have not been exported yet are aggregated from the epochs
+	epochsQuery := "SELECT epoch, validatorscount FROM epochs ORDER BY epoch"
+	epochsArgs := []interface{}{}
+	if len(data) > 0 {
+		epochsQuery = "SELECT epoch, validatorscount FROM epochs WHERE epoch < $1 OR epoch >= $2 ORDER BY epoch"
+		epochsArgs = append(epochsArgs, utils.TimeToEpoch(data[0].Time), utils.TimeToEpoch(data[len(data)-1].Time.Add(utils.Day)))
+	}
+
 	rows := []struct {
 		Epoch           uint64
 		ValidatorsCount uint64
 	}{}
-
-	err := db.ReaderDb.Select(&rows, "SELECT epoch, validatorscount FROM epochs ORDER BY epoch")
+	err = db.ReaderDb.Select(&rows, epochsQuery, epochsArgs...)
 	if err != nil {
 		return nil, err
 	}
 
-	dailyActiveValidators := [][]float64{}
-
+	epochDays := [][]float64{}
 	for _, row := range rows {
 		day := float64(utils.EpochToTime(row.Epoch).Truncate(utils.Day).Unix() * 1000)
 
-		if len(dailyActiveValidators) == 0 || dailyActiveValidators[len(dailyActiveValidators)-1][0] != day {
-			dailyActiveValidators = append(dailyActiveValidators, []float64{day, float64(row.ValidatorsCount)})
+		if len(epochDays) == 0 || epochDays[len(epochDays)-1][0] != day {
+			epochDays = append(epochDays, []float64{day, float64(row.ValidatorsCount)})
+		}
+	}
+
+	dailyActiveValidators := make([][]float64, 0, len(data)+len(epochDays))
+	for _, d := range epochDays {
+		if len(data) > 0 && d[0] >= float64(data[0].Time.UnixMilli()) {
+			break
+		}
+		dailyActiveValidators = append(dailyActiveValidators, d)
+	}
+	for _, d := range data {
+		dailyActiveValidators = append(dailyActiveValidators, []float64{float64(d.Time.UnixMilli()), d.Value})
+	}
+	for _, d := range epochDays {
+		if len(data) > 0 && d[0] > float64(data[len(data)-1].Time.UnixMilli()) {
+			dailyActiveValidators = append(dailyActiveValidators, d)
 		}
 	}
 
@@ -395,7 +431,14 @@ func networkLivenessChartData() (*types.GenericChartData, error) {
 		// FinalizedEpoch uint64
 	}{}
 
-	err := db.ReaderDb.Select(&rows, "SELECT EXTRACT(epoch FROM date_trunc('day', ts))::bigint as day, max(headepoch-finalizedepoch) as diff FROM network_liveness group by day ORDER BY day;")
+	err := db.ReaderDb.Select(&rows, `
+		SELECT day, MAX(diff) AS diff FROM (
+			SELECT EXTRACT(epoch FROM date_trunc('day', ts))::bigint AS day, headepoch-finalizedepoch AS diff FROM network_liveness
+			UNION ALL
+			SELECT day, max_finality_delay AS diff FROM network_liveness_daily
+		) a
+		GROUP BY day
+		ORDER BY day;`)
 	if err != nil {
 		return nil, err
 	}
@@ -449,6 +492,11 @@ func participationRateChartData() (*types.GenericChartData, error) {
 		series = append(series, []float64{float64(d.Time.UnixMilli()), d.Value})
 	}
 
+	plotBands, err := networkIncidentPlotBands()
+	if err != nil {
+		return nil, err
+	}
+
 	chartData := &types.GenericChartData{
 		Title:        "Participation Rate",
 		Subtitle:     "Participation Rate measures how many of the validators expected to attest to blocks are actually doing so.",
@@ -462,6 +510,7 @@ func participationRateChartData() (*types.GenericChartData, error) {
 				Data: series,
 			},
 		},
+		XAxisPlotBands: plotBands,
 	}
 
 	return chartData, nil
@@ -872,6 +921,76 @@ func withdrawalsChartData() (*types.GenericChartData, error) {
 	return chartData, nil
 }
 
+func dvtAdoptionChartData() (*types.GenericChartData, error) {
+	adoption, err := db.GetDvtAdoption()
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]*types.GenericChartDataSeries, 0, len(adoption))
+	for _, protocol := range adoption {
+		seriesData := make([][]float64, 0, len(protocol.History))
+		for _, day := range protocol.History {
+			seriesData = append(seriesData, []float64{
+				float64(day.Day.UnixMilli()),
+				float64(day.ActiveValidators),
+			})
+		}
+		series = append(series, &types.GenericChartDataSeries{
+			Name: strings.ToUpper(protocol.Protocol),
+			Data: seriesData,
+		})
+	}
+
+	chartData := &types.GenericChartData{
+		Title:        "DVT Adoption",
+		Subtitle:     "Daily amount of active validators run by distributed validator clusters (SSV and Obol).",
+		XAxisTitle:   "",
+		YAxisTitle:   "Active Validators",
+		StackingMode: "normal",
+		Type:         "area",
+		Series:       series,
+	}
+
+	return chartData, nil
+}
+
+func poolLuckChartData() (*types.GenericChartData, error) {
+	history, err := db.GetPoolLuckHistory(10, 30)
+	if err != nil {
+		return nil, err
+	}
+
+	// the history is ordered by pool, so every pool is a continuous block of days
+	series := []*types.GenericChartDataSeries{}
+	seriesData := [][]float64{}
+	for i, day := range history {
+		seriesData = append(seriesData, []float64{
+			float64(utils.DayToTime(int64(day.Day)).UnixMilli()),
+			day.ProposalLuck * 100,
+		})
+		if i == len(history)-1 || history[i+1].Pool != day.Pool {
+			series = append(series, &types.GenericChartDataSeries{
+				Name: day.Pool,
+				Data: seriesData,
+			})
+			seriesData = [][]float64{}
+		}
+	}
+
+	chartData := &types.GenericChartData{
+		Title:        "Pool Proposal Luck",
+		Subtitle:     "Proposals of the largest staking pools compared to the amount expected from their share of the effective balance over the previous 30 days. A luck of 100% means exactly as many proposals as expected.",
+		XAxisTitle:   "",
+		YAxisTitle:   "Proposal Luck [%]",
+		StackingMode: "false",
+		Type:         "line",
+		Series:       series,
+	}
+
+	return chartData, nil
+}
+
 func poolsDistributionChartData() (*types.GenericChartData, error) {
 
 	type seriesDataItem struct {
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/rpc"
+	"github.com/gobitfly/eth2-beaconchain-explorer/tracing"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	eth_rewards "github.com/gobitfly/eth-rewards"
+	"github.com/gobitfly/eth-rewards/beacon"
+	"google.golang.org/protobuf/proto"
+)
+
+// consistencyAuditDelay is the number of finalized epochs skipped by the audit so the sampled epochs have been
+// exported by all exporters, including the rewards exporter
+const consistencyAuditDelay = 10
+
+// StartConsistencyAuditService compares the stored data of randomly sampled epochs and slots with the data of the
+// beacon and execution nodes once per configured interval and reports the mismatches
+func StartConsistencyAuditService(client rpc.Client) {
+	cfg := utils.Config.ConsistencyAudit
+	if cfg.Interval == 0 {
+		cfg.Interval = time.Hour
+	}
+	if cfg.SampleEpochs == 0 {
+		cfg.SampleEpochs = 2
+	}
+	if cfg.SampleSlots == 0 {
+		cfg.SampleSlots = 10
+	}
+	if cfg.SampleValidators == 0 {
+		cfg.SampleValidators = 100
+	}
+
+	rewardsClient := beacon.NewClient("http://"+utils.Config.Indexer.Node.Host+":"+utils.Config.Indexer.Node.Port, time.Minute*5)
+
+	for {
+		start := time.Now()
+		_, span := tracing.StartSpan(context.Background(), "consistencyAudit")
+		report, err := runConsistencyAudit(client, rewardsClient, cfg.SampleEpochs, cfg.SampleSlots, cfg.SampleValidators)
+		tracing.EndSpan(span, err)
+		if err != nil {
+			utils.LogError(err, "error running consistency audit", 0)
+			time.Sleep(cfg.Interval)
+			continue
+		}
+
+		err = db.SaveConsistencyReport(report)
+		if err != nil {
+			utils.LogError(err, "error saving consistency report", 0)
+		}
+
+		status := "OK"
+		if len(report.Mismatches) > 0 {
+			status = fmt.Sprintf("%v mismatches between the stored data and the nodes", len(report.Mismatches))
+			utils.LogError(nil, status, 0, map[string]interface{}{"epochs": report.Epochs, "slots": report.Slots})
+		}
+		metadata, err := json.Marshal(report.Mismatches)
+		if err != nil {
+			utils.LogError(err, "error marshalling consistency mismatches", 0)
+		}
+		rawMetadata := json.RawMessage(metadata)
+		ReportStatus("consistency_audit", status, &rawMetadata)
+
+		logger.Infof("consistency audit of %v epochs and %v slots completed with %v mismatches, took %v", len(report.Epochs), len(report.Slots), len(report.Mismatches), time.Since(start))
+		time.Sleep(cfg.Interval)
+	}
+}
+
+func runConsistencyAudit(client rpc.Client, rewardsClient *beacon.Client, sampleEpochs, sampleSlots, sampleValidators int) (*types.ConsistencyReport, error) {
+	latestFinalizedEpoch, err := db.GetLatestFinalizedEpoch()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving latest finalized epoch: %w", err)
+	}
+	if latestFinalizedEpoch <= consistencyAuditDelay {
+		return nil, fmt.Errorf("not enough finalized epochs to audit")
+	}
+	maxEpoch := latestFinalizedEpoch - consistencyAuditDelay
+
+	report := &types.ConsistencyReport{Ts: time.Now(), Mismatches: []types.ConsistencyMismatch{}}
+	for i := 0; i < sampleEpochs; i++ {
+		epoch := uint64(rand.Int63n(int64(maxEpoch))) + 1
+		report.Epochs = append(report.Epochs, epoch)
+
+		validators, err := auditEpochBalances(client, report, epoch, sampleValidators)
+		if err != nil {
+			return nil, err
+		}
+		if utils.Config.Eth1ErigonEndpoint != "" {
+			err = auditEpochRewards(rewardsClient, report, epoch, validators)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	maxSlot := (maxEpoch+1)*utils.Config.Chain.ClConfig.SlotsPerEpoch - 1
+	for i := 0; i < sampleSlots; i++ {
+		slot := uint64(rand.Int63n(int64(maxSlot))) + 1
+		report.Slots = append(report.Slots, slot)
+
+		err := auditSlot(client, report, slot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, m := range report.Mismatches {
+		metrics.ConsistencyMismatches.WithLabelValues(m.Check, m.Source).Inc()
+	}
+	return report, nil
+}
+
+// auditEpochBalances compares the balances of a random sample of validators stored in bigtable with the balances of
+// the beacon node and returns the sampled validators
+func auditEpochBalances(client rpc.Client, report *types.ConsistencyReport, epoch uint64, sampleValidators int) ([]uint64, error) {
+	balances, err := client.GetBalancesForEpoch(int64(epoch))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving balances of epoch %v from the node: %w", epoch, err)
+	}
+
+	// map iteration order is random, so the first validators form a random sample
+	validators := make([]uint64, 0, sampleValidators)
+	for validator := range balances {
+		if len(validators) == sampleValidators {
+			break
+		}
+		validators = append(validators, validator)
+	}
+
+	stored, err := db.BigtableClient.GetValidatorBalanceHistory(validators, epoch, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving balances of epoch %v from bigtable: %w", epoch, err)
+	}
+	for _, validator := range validators {
+		storedBalance := "missing"
+		for _, b := range stored[validator] {
+			if b.Epoch == epoch {
+				storedBalance = fmt.Sprint(b.Balance)
+			}
+		}
+		if expected := fmt.Sprint(balances[validator]); storedBalance != expected {
+			validator := validator
+			report.Mismatches = append(report.Mismatches, types.ConsistencyMismatch{Check: "balance", Source: "bigtable", Epoch: epoch, Validator: &validator, Stored: storedBalance, Expected: expected})
+		}
+	}
+	return validators, nil
+}
+
+// auditEpochRewards compares the income details of the validators stored in bigtable with the rewards calculated from
+// the nodes. Validators without stored income details are skipped as the rewards exporter may still be catching up.
+func auditEpochRewards(rewardsClient *beacon.Client, report *types.ConsistencyReport, epoch uint64, validators []uint64) error {
+	stored, err := db.BigtableClient.GetValidatorIncomeDetailsHistory(validators, epoch, epoch)
+	if err != nil {
+		return fmt.Errorf("error retrieving income details of epoch %v from bigtable: %w", epoch, err)
+	}
+	if len(stored) == 0 {
+		return nil
+	}
+
+	rewards, err := eth_rewards.GetRewardsForEpoch(epoch, rewardsClient, utils.Config.Eth1ErigonEndpoint)
+	if err != nil {
+		return fmt.Errorf("error retrieving rewards of epoch %v from the nodes: %w", epoch, err)
+	}
+	for _, validator := range validators {
+		storedIncome := stored[validator][epoch]
+		if storedIncome == nil || proto.Equal(storedIncome, rewards[validator]) {
+			continue
+		}
+		validator := validator
+		report.Mismatches = append(report.Mismatches, types.ConsistencyMismatch{Check: "rewards", Source: "bigtable", Epoch: epoch, Validator: &validator, Stored: storedIncome.String(), Expected: rewards[validator].String()})
+	}
+	return nil
+}
+
+// auditSlot compares the block of the slot stored in postgres with the block of the beacon node, and the transaction
+// count stored in postgres and bigtable with the execution node
+func auditSlot(client rpc.Client, report *types.ConsistencyReport, slot uint64) error {
+	epoch := utils.EpochOfSlot(slot)
+	mismatch := func(check, source, stored, expected string) {
+		slot := slot
+		report.Mismatches = append(report.Mismatches, types.ConsistencyMismatch{Check: check, Source: source, Epoch: epoch, Slot: &slot, Stored: stored, Expected: expected})
+	}
+
+	block, err := client.GetBlockBySlot(slot)
+	if err != nil {
+		return fmt.Errorf("error retrieving block of slot %v from the node: %w", slot, err)
+	}
+
+	var stored struct {
+		BlockRoot         []byte `db:"blockroot"`
+		AttestationsCount uint64 `db:"attestationscount"`
+		DepositsCount     uint64 `db:"depositscount"`
+		ExecTxCount       uint64 `db:"exec_transactions_count"`
+	}
+	err = db.WriterDb.Get(&stored, `
+		SELECT blockroot, attestationscount, depositscount, COALESCE(exec_transactions_count, 0) AS exec_transactions_count
+		FROM blocks
+		WHERE slot = $1 AND status = '1'`, slot)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("error retrieving block of slot %v from the db: %w", slot, err)
+	}
+
+	if block.Status != 1 {
+		if stored.BlockRoot != nil {
+			mismatch("block", "postgres", fmt.Sprintf("proposed block %#x", stored.BlockRoot), "no proposed block")
+		}
+		return nil
+	}
+	if !bytes.Equal(stored.BlockRoot, block.BlockRoot) {
+		mismatch("block", "postgres", fmt.Sprintf("%#x", stored.BlockRoot), fmt.Sprintf("%#x", block.BlockRoot))
+		return nil
+	}
+	if stored.AttestationsCount != uint64(len(block.Attestations)) {
+		mismatch("attestations_count", "postgres", fmt.Sprint(stored.AttestationsCount), fmt.Sprint(len(block.Attestations)))
+	}
+	if stored.DepositsCount != uint64(len(block.Deposits)) {
+		mismatch("deposits_count", "postgres", fmt.Sprint(stored.DepositsCount), fmt.Sprint(len(block.Deposits)))
+	}
+
+	if block.ExecutionPayload == nil || rpc.CurrentErigonClient == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	txCount, err := rpc.CurrentErigonClient.GetNativeClient().TransactionCount(ctx, common.BytesToHash(block.ExecutionPayload.BlockHash))
+	if err != nil {
+		return fmt.Errorf("error retrieving transaction count of block %v from the execution node: %w", block.ExecutionPayload.BlockNumber, err)
+	}
+	if stored.ExecTxCount != uint64(txCount) {
+		mismatch("tx_count", "postgres", fmt.Sprint(stored.ExecTxCount), fmt.Sprint(txCount))
+	}
+
+	eth1Block, err := db.BigtableClient.GetBlockFromBlocksTable(block.ExecutionPayload.BlockNumber)
+	if err != nil {
+		mismatch("tx_count", "bigtable", "missing", fmt.Sprint(txCount))
+		return nil
+	}
+	if uint(len(eth1Block.Transactions)) != txCount {
+		mismatch("tx_count", "bigtable", fmt.Sprint(len(eth1Block.Transactions)), fmt.Sprint(txCount))
+	}
+	return nil
+}
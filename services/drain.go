@@ -0,0 +1,62 @@
+package services
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// drain pauses the background services of the process. Services report their status once per iteration, so
+// ReportStatus is the point at which a draining service pauses, after the work of its current iteration is done.
+var drain = struct {
+	sync.Mutex
+	cond     *sync.Cond
+	draining bool
+	paused   map[string]bool
+}{paused: map[string]bool{}}
+
+func init() {
+	drain.cond = sync.NewCond(&drain.Mutex)
+}
+
+// Drain pauses the background services of the process once they finish their current iteration
+func Drain() {
+	drain.Lock()
+	defer drain.Unlock()
+	drain.draining = true
+	logger.Warn("draining background services")
+}
+
+// Resume continues the background services paused by Drain
+func Resume() {
+	drain.Lock()
+	defer drain.Unlock()
+	drain.draining = false
+	drain.cond.Broadcast()
+	logger.Warn("resuming background services")
+}
+
+// GetDrainStatus returns whether the services are drained and the services that paused so far
+func GetDrainStatus() types.DrainStatus {
+	drain.Lock()
+	defer drain.Unlock()
+
+	status := types.DrainStatus{Draining: drain.draining, Paused: make([]string, 0, len(drain.paused))}
+	for name := range drain.paused {
+		status.Paused = append(status.Paused, name)
+	}
+	sort.Strings(status.Paused)
+	return status
+}
+
+// waitWhileDrained blocks the calling service while the services are drained
+func waitWhileDrained(name string) {
+	drain.Lock()
+	defer drain.Unlock()
+	for drain.draining {
+		drain.paused[name] = true
+		drain.cond.Wait()
+	}
+	delete(drain.paused, name)
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// Units of the progress of the exporters
+const (
+	ExporterUnitSlot  = "slot"
+	ExporterUnitEpoch = "epoch"
+	ExporterUnitBlock = "block"
+	ExporterUnitDay   = "day"
+)
+
+// exporterProgressWindow is the minimum duration the rates of the progress are measured over
+const exporterProgressWindow = time.Minute
+
+// exporterInSyncLag is the lag in units up to which an exporter is considered in sync with the chain head
+const exporterInSyncLag = 2
+
+// exporterProgressWindowState is the start of the window the rates of an exporter are measured over
+type exporterProgressWindowState struct {
+	start    time.Time
+	head     uint64
+	rows     uint64
+	progress types.ExporterProgress
+}
+
+var exporterProgressMu sync.Mutex
+var exporterProgressWindows = map[string]*exporterProgressWindowState{}
+
+// ReportExporterProgress reports the status of an exporter together with its position, its lag to the chain head and
+// its throughput. Rows are the rows exported since the previous call. The progress is exported as metrics and stored
+// as metadata of the service status, so the exporters monitoring service can estimate when the exporter catches up.
+func ReportExporterProgress(name, status, unit string, head, chainHead, rows uint64) {
+	progress := updateExporterProgress(name, unit, head, chainHead, rows)
+
+	if utils.Config.Metrics.Enabled {
+		metrics.ExporterHead.WithLabelValues(name).Set(float64(progress.Head))
+		metrics.ExporterLag.WithLabelValues(name).Set(float64(progress.Lag))
+		metrics.ExporterRowsPerSecond.WithLabelValues(name).Set(progress.RowsPerSecond)
+	}
+
+	metadata, err := json.Marshal(progress)
+	if err != nil {
+		utils.LogError(err, "error marshalling exporter progress", 0, map[string]interface{}{"name": name})
+		ReportStatus(name, status, nil)
+		return
+	}
+	rawMetadata := json.RawMessage(metadata)
+	ReportStatus(name, status, &rawMetadata)
+}
+
+func updateExporterProgress(name, unit string, head, chainHead, rows uint64) types.ExporterProgress {
+	exporterProgressMu.Lock()
+	defer exporterProgressMu.Unlock()
+
+	now := time.Now()
+	w, ok := exporterProgressWindows[name]
+	if !ok {
+		w = &exporterProgressWindowState{start: now, head: head}
+		exporterProgressWindows[name] = w
+	}
+	w.rows += rows
+
+	// the rates are kept until the window is long enough to measure them again
+	if elapsed := now.Sub(w.start); elapsed >= exporterProgressWindow {
+		w.progress.RowsPerSecond = float64(w.rows) / elapsed.Seconds()
+		w.progress.HeadRate = 0
+		if head > w.head {
+			w.progress.HeadRate = float64(head-w.head) / elapsed.Seconds()
+		}
+		w.start, w.head, w.rows = now, head, 0
+	}
+
+	w.progress.Unit = unit
+	w.progress.Head = head
+	w.progress.ChainHead = chainHead
+	w.progress.Lag = 0
+	if chainHead > head {
+		w.progress.Lag = chainHead - head
+	}
+	w.progress.Ts = now
+	return w.progress
+}
+
+// exporterUnitDuration returns the duration the chain head advances one unit in
+func exporterUnitDuration(unit string) time.Duration {
+	slot := time.Second * time.Duration(utils.Config.Chain.ClConfig.SecondsPerSlot)
+	switch unit {
+	case ExporterUnitEpoch:
+		return slot * time.Duration(utils.Config.Chain.ClConfig.SlotsPerEpoch)
+	case ExporterUnitDay:
+		return utils.Day
+	default:
+		// execution blocks are produced once per slot
+		return slot
+	}
+}
+
+// exporterCatchUp returns the estimated duration until the exporter has caught up with the chain head, 0 if it is in
+// sync and false if it does not advance faster than the chain head
+func exporterCatchUp(progress types.ExporterProgress) (time.Duration, bool) {
+	if progress.Lag <= exporterInSyncLag {
+		return 0, true
+	}
+	chainRate := 1 / exporterUnitDuration(progress.Unit).Seconds()
+	netRate := progress.HeadRate - chainRate
+	if netRate <= 0 {
+		return 0, false
+	}
+	return time.Duration(float64(progress.Lag) / netRate * float64(time.Second)), true
+}
+
+// startExportersMonitoringService estimates when the exporters catch up with the chain head from their reported
+// progress and reports an error for exporters that are behind and not catching up
+func startExportersMonitoringService() {
+	name := "monitoring_exporters"
+	exporters := []string{"slotExporter", "eth1indexer", "rewardsExporter", "statistics"}
+
+	firstRun := true
+	for {
+		if !firstRun {
+			time.Sleep(time.Minute)
+		}
+		firstRun = false
+
+		stuck := []string{}
+		for _, exporter := range exporters {
+			var metadata []byte
+			err := db.Reader(db.ReadPrimary).Get(&metadata, `
+				SELECT metadata FROM service_status
+				WHERE name = $1 AND metadata IS NOT NULL AND last_update > NOW() - INTERVAL '1 HOUR'
+				ORDER BY last_update DESC LIMIT 1`, exporter)
+			if err == sql.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				logger.Errorf("error retrieving progress of exporter %v: %v", exporter, err)
+				continue
+			}
+
+			progress := types.ExporterProgress{}
+			err = json.Unmarshal(metadata, &progress)
+			if err != nil || progress.Unit == "" {
+				continue
+			}
+
+			eta, catchingUp := exporterCatchUp(progress)
+			if utils.Config.Metrics.Enabled {
+				if catchingUp {
+					metrics.ExporterCatchUpSeconds.WithLabelValues(exporter).Set(eta.Seconds())
+				} else {
+					metrics.ExporterCatchUpSeconds.WithLabelValues(exporter).Set(-1)
+				}
+			}
+			switch {
+			case !catchingUp:
+				logger.Warnf("exporter %v is %v %vs behind the chain head and not catching up", exporter, progress.Lag, progress.Unit)
+				stuck = append(stuck, fmt.Sprintf("%v (%v %vs behind)", exporter, progress.Lag, progress.Unit))
+			case eta > 0:
+				logger.Infof("exporter %v is %v %vs behind the chain head, catching up in %v", exporter, progress.Lag, progress.Unit, eta.Round(time.Second))
+			}
+		}
+
+		if len(stuck) > 0 {
+			ReportStatus(name, fmt.Sprintf("error: exporters not catching up with the chain head: %v", stuck), nil)
+			continue
+		}
+		ReportStatus(name, "OK", nil)
+	}
+}
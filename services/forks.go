@@ -0,0 +1,200 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/cache"
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	ethclients "github.com/gobitfly/eth2-beaconchain-explorer/ethClients"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/hashicorp/go-version"
+)
+
+// forkReadinessDays is the amount of days of blocks the fork readiness of the clients is derived from
+const forkReadinessDays = 7
+
+// forkReadinessClients are the consensus clients whose fork readiness is derived from the versions in block graffitis
+var forkReadinessClients = []string{"lighthouse", "prysm", "teku", "nimbus", "lodestar", "grandine"}
+
+// graffitiClientVersionPattern matches client versions in graffitis like the default ones of the clients, e.g.
+// Lighthouse/v4.6.0-441fc16. It is used case-insensitive both in go and in postgres.
+var graffitiClientVersionPattern = `(` + strings.Join(forkReadinessClients, "|") + `)[/ -]?v?([0-9]+\.[0-9]+\.[0-9]+)`
+var graffitiClientVersionRE = regexp.MustCompile(`(?i)` + graffitiClientVersionPattern)
+
+// getChainForks returns the forks specified by the chain config, oldest first
+func getChainForks() []*types.ApiChainForkResponse {
+	cfg := utils.Config.Chain.ClConfig
+	specs := []struct {
+		Name    string
+		Version string
+		Epoch   uint64
+	}{
+		{"phase0", cfg.GenesisForkVersion, 0},
+		{"altair", cfg.AltairForkVersion, cfg.AltairForkEpoch},
+		{"bellatrix", cfg.BellatrixForkVersion, cfg.BellatrixForkEpoch},
+		{"capella", cfg.CappellaForkVersion, cfg.CappellaForkEpoch},
+		{"deneb", cfg.DenebForkVersion, cfg.DenebForkEpoch},
+		{"eip6110", cfg.Eip6110ForkVersion, cfg.Eip6110ForkEpoch},
+		{"eip7002", cfg.Eip7002ForkVersion, cfg.Eip7002ForkEpoch},
+		{"whisk", cfg.WhiskForkVersion, cfg.WhiskForkEpoch},
+	}
+
+	latestEpoch := LatestEpoch()
+	forks := []*types.ApiChainForkResponse{}
+	for _, spec := range specs {
+		if spec.Version == "" {
+			continue
+		}
+		fork := &types.ApiChainForkResponse{
+			Name:    spec.Name,
+			Version: spec.Version,
+			Status:  "unscheduled",
+		}
+		// forks without activation epoch use the far future epoch of the spec
+		if spec.Epoch != math.MaxUint64 {
+			epoch := spec.Epoch
+			activationTime := utils.EpochToTime(epoch)
+			fork.Epoch = &epoch
+			fork.ActivationTime = &activationTime
+			fork.Status = "scheduled"
+			if epoch <= latestEpoch {
+				fork.Status = "active"
+			}
+		}
+		forks = append(forks, fork)
+	}
+	return forks
+}
+
+// addForkReadiness adds the readiness of the clients for the fork, based on the first releases supporting the fork
+// configured in ForkReleases and the client versions of the graffitis of the recent blocks
+func addForkReadiness(fork *types.ApiChainForkResponse, graffitiCounts map[string]uint64) {
+	minReleases := utils.Config.Chain.ForkReleases[fork.Name]
+
+	clients := make(map[string]*types.ApiChainForkClientResponse, len(forkReadinessClients))
+	for _, client := range forkReadinessClients {
+		clients[client] = &types.ApiChainForkClientResponse{
+			Client:        client,
+			MinRelease:    minReleases[client],
+			LatestRelease: ethclients.GetLatestRelease(client),
+		}
+		fork.Clients = append(fork.Clients, clients[client])
+	}
+
+	readyBlocks := uint64(0)
+	for graffiti, count := range graffitiCounts {
+		match := graffitiClientVersionRE.FindStringSubmatch(graffiti)
+		if match == nil {
+			continue
+		}
+		client := clients[strings.ToLower(match[1])]
+		client.Blocks += count
+		fork.VersionedBlocks += count
+
+		if client.MinRelease == "" {
+			continue
+		}
+		minRelease, err := version.NewVersion(client.MinRelease)
+		if err != nil {
+			logger.Warnf("invalid release %v of %v configured for fork %v: %v", client.MinRelease, client.Client, fork.Name, err)
+			continue
+		}
+		blockVersion, err := version.NewVersion(match[2])
+		if err != nil {
+			continue
+		}
+		if blockVersion.GreaterThanOrEqual(minRelease) {
+			client.ReadyBlocks += count
+			readyBlocks += count
+		}
+	}
+
+	if fork.VersionedBlocks > 0 && len(minReleases) > 0 {
+		share := float64(readyBlocks) / float64(fork.VersionedBlocks) * 100
+		fork.ReadyBlocksShare = &share
+	}
+	sort.SliceStable(fork.Clients, func(i, j int) bool {
+		return fork.Clients[i].Blocks > fork.Clients[j].Blocks
+	})
+}
+
+func getChainForksData() ([]*types.ApiChainForkResponse, error) {
+	forks := getChainForks()
+
+	var graffitiCounts map[string]uint64
+	for _, fork := range forks {
+		if fork.Status == "active" {
+			continue
+		}
+		if graffitiCounts == nil {
+			fromSlot := uint64(0)
+			readinessSlots := forkReadinessDays * utils.EpochsPerDay() * utils.Config.Chain.ClConfig.SlotsPerEpoch
+			if LatestSlot() > readinessSlots {
+				fromSlot = LatestSlot() - readinessSlots
+			}
+			var err error
+			graffitiCounts, err = db.GetBlockGraffitiCounts(fromSlot, graffitiClientVersionPattern)
+			if err != nil {
+				return nil, err
+			}
+		}
+		addForkReadiness(fork, graffitiCounts)
+	}
+	return forks, nil
+}
+
+func chainForksUpdater(wg *sync.WaitGroup) {
+	firstRun := true
+
+	for {
+		data, err := getChainForksData()
+		if err != nil {
+			logger.Errorf("error retrieving chain forks data: %v", err)
+			time.Sleep(time.Second * 10)
+			continue
+		}
+
+		cacheKey := fmt.Sprintf("%d:frontend:chainForks", utils.Config.Chain.ClConfig.DepositChainID)
+		err = cache.TieredCache.Set(cacheKey, data, utils.Day)
+		if err != nil {
+			logger.Errorf("error caching chainForks: %v", err)
+		}
+		if firstRun {
+			logger.Info("initialized chain forks updater")
+			wg.Done()
+			firstRun = false
+		}
+		ReportStatus("chainForksUpdater", "Running", nil)
+		time.Sleep(time.Minute * 10)
+	}
+}
+
+// LatestChainForks returns the forks of the chain with the readiness of the clients for the upcoming forks
+func LatestChainForks() []*types.ApiChainForkResponse {
+	wanted := &[]*types.ApiChainForkResponse{}
+	cacheKey := fmt.Sprintf("%d:frontend:chainForks", utils.Config.Chain.ClConfig.DepositChainID)
+
+	if wanted, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, time.Second*5, wanted); err == nil {
+		forks := *wanted.(*[]*types.ApiChainForkResponse)
+		// the cached status may be outdated if a fork activated since the last update
+		latestEpoch := LatestEpoch()
+		for _, fork := range forks {
+			if fork.Status == "scheduled" && *fork.Epoch <= latestEpoch {
+				fork.Status = "active"
+			}
+		}
+		return forks
+	} else {
+		logger.Errorf("error retrieving chainForks from cache: %v", err)
+	}
+
+	return getChainForks()
+}
@@ -40,8 +40,8 @@ func WriteHistoricPricesForDay(ts time.Time) error {
 	}
 
 	_, err = db.WriterDb.Exec(`
-		INSERT INTO price (ts, eur, usd, rub, cny, cad, jpy, gbp, aud)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO price (ts, eur, usd, rub, cny, cad, jpy, gbp, aud, chf, inr, brl, krw, hkd, sgd, btc)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10::NUMERIC, 0), NULLIF($11::NUMERIC, 0), NULLIF($12::NUMERIC, 0), NULLIF($13::NUMERIC, 0), NULLIF($14::NUMERIC, 0), NULLIF($15::NUMERIC, 0), NULLIF($16::NUMERIC, 0))
 		ON CONFLICT (ts) DO UPDATE SET
 			eur = excluded.eur,
 			usd = excluded.usd,
@@ -50,7 +50,14 @@ func WriteHistoricPricesForDay(ts time.Time) error {
 			cad = excluded.cad,
 			jpy = excluded.jpy,
 			gbp = excluded.gbp,
-			aud = excluded.aud`,
+			aud = excluded.aud,
+			chf = excluded.chf,
+			inr = excluded.inr,
+			brl = excluded.brl,
+			krw = excluded.krw,
+			hkd = excluded.hkd,
+			sgd = excluded.sgd,
+			btc = excluded.btc`,
 		ts,
 		historicPrice.MarketData.CurrentPrice.Eur,
 		historicPrice.MarketData.CurrentPrice.Usd,
@@ -60,6 +67,13 @@ func WriteHistoricPricesForDay(ts time.Time) error {
 		historicPrice.MarketData.CurrentPrice.Jpy,
 		historicPrice.MarketData.CurrentPrice.Gbp,
 		historicPrice.MarketData.CurrentPrice.Aud,
+		historicPrice.MarketData.CurrentPrice.Chf,
+		historicPrice.MarketData.CurrentPrice.Inr,
+		historicPrice.MarketData.CurrentPrice.Brl,
+		historicPrice.MarketData.CurrentPrice.Krw,
+		historicPrice.MarketData.CurrentPrice.Hkd,
+		historicPrice.MarketData.CurrentPrice.Sgd,
+		historicPrice.MarketData.CurrentPrice.Btc,
 	)
 
 	if err != nil {
@@ -75,7 +89,7 @@ func updateHistoricPrices() error {
 	}()
 	var dates []time.Time
 
-	err := db.WriterDb.Select(&dates, "SELECT ts FROM price")
+	err := db.Reader(db.ReadPrimary).Select(&dates, "SELECT ts FROM price")
 
 	if err != nil {
 		return err
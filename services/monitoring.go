@@ -24,6 +24,7 @@ func startMonitoringService(wg *sync.WaitGroup) {
 	go startApiMonitoringService()
 	go startAppMonitoringService()
 	go startServicesMonitoringService()
+	go startExportersMonitoringService()
 }
 
 // The cl data monitoring service will check that the data in the validators, blocks & epochs tables is up to date
@@ -60,7 +61,7 @@ func startClDataMonitoringService() {
 
 		// retrieve the max slot from the blocks table and check tat it is not older than 15 minutes
 		var maxSlot uint64
-		err = db.WriterDb.Get(&maxSlot, "SELECT MAX(slot) FROM blocks;")
+		err = db.Reader(db.ReadPrimary).Get(&maxSlot, "SELECT MAX(slot) FROM blocks;")
 		if err != nil {
 			logger.Errorf("error retrieving max slot from blocks table: %v", err)
 			continue
@@ -75,7 +76,7 @@ func startClDataMonitoringService() {
 
 		// retrieve the max epoch from the epochs table and check tat it is not older than 15 minutes
 		var maxEpoch uint64
-		err = db.WriterDb.Get(&maxEpoch, "SELECT MAX(epoch) FROM epochs;")
+		err = db.Reader(db.ReadPrimary).Get(&maxEpoch, "SELECT MAX(epoch) FROM epochs;")
 		if err != nil {
 			logger.Errorf("error retrieving max slot from blocks table: %v", err)
 			continue
@@ -294,7 +295,7 @@ func startServicesMonitoringService() {
 		hasError := false
 		for serviceName, maxTimeout := range servicesToCheck {
 			var status string
-			err := db.WriterDb.Get(&status, `select status from service_status where last_update > $1 and name = $2 ORDER BY last_update DESC LIMIT 1;`, now.Add(maxTimeout*-1), serviceName)
+			err := db.Reader(db.ReadPrimary).Get(&status, `select status from service_status where last_update > $1 and name = $2 ORDER BY last_update DESC LIMIT 1;`, now.Add(maxTimeout*-1), serviceName)
 
 			if err != nil {
 
@@ -14,14 +14,15 @@ import (
 	"html/template"
 	"io"
 	"math/big"
-	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/db"
 	ethclients "github.com/gobitfly/eth2-beaconchain-explorer/ethClients"
+	"github.com/gobitfly/eth2-beaconchain-explorer/httpclient"
 	"github.com/gobitfly/eth2-beaconchain-explorer/mail"
 	"github.com/gobitfly/eth2-beaconchain-explorer/metrics"
 	"github.com/gobitfly/eth2-beaconchain-explorer/notify"
@@ -34,6 +35,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"github.com/rocket-pool/rocketpool-go/utils/eth"
+	"github.com/shopspring/decimal"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -43,6 +45,9 @@ import (
 // notifications are collected in ascending epoch order
 // the epochs_notified sql table is used to keep track of already notified epochs
 // before collecting notifications several db consistency checks are done
+// eth1AddressEventsRetention is how long events of watched addresses are kept after their epoch has been notified
+const eth1AddressEventsRetention = time.Hour * 24
+
 func notificationCollector() {
 	for {
 		latestFinalizedEpoch := LatestFinalizedEpoch()
@@ -276,6 +281,62 @@ func collectNotifications(epoch uint64) (map[uint64]map[types.EventName][]types.
 	}
 	logger.Infof("collecting withdrawal notifications took: %v", time.Since(start))
 
+	err = collectWithdrawalCredentialsChangedNotifications(notificationsByUserID, epoch)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_withdrawal_credentials_changed").Inc()
+		return nil, fmt.Errorf("error collecting withdrawal credentials changed notifications: %v", err)
+	}
+	logger.Infof("collecting withdrawal credentials changed notifications took: %v", time.Since(start))
+
+	err = collectValidatorExitNotifications(notificationsByUserID, epoch, types.ValidatorVoluntaryExitEventName)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_validator_voluntary_exit").Inc()
+		return nil, fmt.Errorf("error collecting voluntary exit notifications: %v", err)
+	}
+	logger.Infof("collecting voluntary exit notifications took: %v", time.Since(start))
+
+	err = collectValidatorExitNotifications(notificationsByUserID, epoch, types.ValidatorWithdrawableEventName)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_validator_withdrawable").Inc()
+		return nil, fmt.Errorf("error collecting validator withdrawable notifications: %v", err)
+	}
+	logger.Infof("collecting validator withdrawable notifications took: %v", time.Since(start))
+
+	err = collectFeeRecipientMismatchNotifications(notificationsByUserID, epoch)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_fee_recipient_mismatch").Inc()
+		return nil, fmt.Errorf("error collecting fee recipient mismatch notifications: %v", err)
+	}
+	logger.Infof("collecting fee recipient mismatch notifications took: %v", time.Since(start))
+
+	err = collectRelayRegistrationMissingNotifications(notificationsByUserID, epoch)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_relay_registration_missing").Inc()
+		return nil, fmt.Errorf("error collecting relay registration missing notifications: %v", err)
+	}
+	logger.Infof("collecting relay registration missing notifications took: %v", time.Since(start))
+
+	err = collectValidatorMilestoneNotifications(notificationsByUserID, epoch)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_validator_milestone").Inc()
+		return nil, fmt.Errorf("error collecting validator milestone notifications: %v", err)
+	}
+	logger.Infof("collecting validator milestone notifications took: %v", time.Since(start))
+
+	err = collectValidatorDepositWarningNotifications(notificationsByUserID, epoch)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_validator_deposit_warning").Inc()
+		return nil, fmt.Errorf("error collecting validator deposit warning notifications: %v", err)
+	}
+	logger.Infof("collecting validator deposit warning notifications took: %v", time.Since(start))
+
+	err = collectEthAddressNotifications(notificationsByUserID, epoch)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_eth_address").Inc()
+		return nil, fmt.Errorf("error collecting eth address notifications: %v", err)
+	}
+	logger.Infof("collecting eth address notifications took: %v", time.Since(start))
+
 	err = collectNetworkNotifications(notificationsByUserID, types.NetworkLivenessIncreasedEventName)
 	if err != nil {
 		metrics.Errors.WithLabelValues("notifications_collect_network").Inc()
@@ -283,6 +344,13 @@ func collectNotifications(epoch uint64) (map[uint64]map[types.EventName][]types.
 	}
 	logger.Infof("collecting network notifications took: %v", time.Since(start))
 
+	err = collectNetworkIncidentNotifications(notificationsByUserID, epoch)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_network_incident").Inc()
+		return nil, fmt.Errorf("error collecting network incident notifications: %v", err)
+	}
+	logger.Infof("collecting network incident notifications took: %v", time.Since(start))
+
 	// Rocketpool
 	{
 		var ts int64
@@ -408,17 +476,32 @@ func queueNotifications(notificationsByUserID map[uint64]map[types.EventName][]t
 		}
 	}
 
-	err := queueEmailNotifications(notificationsByUserID, useDB)
+	subIDs := []uint64{}
+	for _, notifications := range notificationsByUserID {
+		for _, events := range notifications {
+			for _, ev := range events {
+				subIDs = append(subIDs, ev.GetSubscriptionID())
+			}
+		}
+	}
+	channelsBySubID, err := db.GetSubscriptionChannels(subIDs)
+	if err != nil {
+		// the notifications are sent on all channels rather than not at all
+		logger.WithError(err).Error("error retrieving subscription channels")
+		channelsBySubID = map[uint64][]types.NotificationChannel{}
+	}
+
+	err = queueEmailNotifications(filterNotificationsByChannel(notificationsByUserID, channelsBySubID, types.EmailNotificationChannel), useDB)
 	if err != nil {
 		logger.WithError(err).Error("error queuing email notifications")
 	}
 
-	err = queuePushNotification(notificationsByUserID, useDB)
+	err = queuePushNotification(filterNotificationsByChannel(notificationsByUserID, channelsBySubID, types.PushNotificationChannel), useDB)
 	if err != nil {
 		logger.WithError(err).Error("error queuing push notifications")
 	}
 
-	err = queueWebhookNotifications(notificationsByUserID, useDB)
+	err = queueWebhookNotifications(filterNotificationsByChannel(notificationsByUserID, channelsBySubID, types.WebhookNotificationChannel, types.WebhookDiscordNotificationChannel), useDB)
 	if err != nil {
 		logger.WithError(err).Error("error queuing webhook notifications")
 	}
@@ -475,6 +558,41 @@ func queueNotifications(notificationsByUserID map[uint64]map[types.EventName][]t
 	}
 }
 
+// filterNotificationsByChannel returns the notifications of subscriptions that are sent on one of the channels,
+// subscriptions without channels are sent on all channels
+func filterNotificationsByChannel(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, channelsBySubID map[uint64][]types.NotificationChannel, channels ...types.NotificationChannel) map[uint64]map[types.EventName][]types.Notification {
+	if len(channelsBySubID) == 0 {
+		return notificationsByUserID
+	}
+
+	filtered := make(map[uint64]map[types.EventName][]types.Notification, len(notificationsByUserID))
+	for userID, notifications := range notificationsByUserID {
+		for eventName, events := range notifications {
+			for _, ev := range events {
+				if subChannels, ok := channelsBySubID[ev.GetSubscriptionID()]; ok && !hasNotificationChannel(subChannels, channels) {
+					continue
+				}
+				if filtered[userID] == nil {
+					filtered[userID] = map[types.EventName][]types.Notification{}
+				}
+				filtered[userID][eventName] = append(filtered[userID][eventName], ev)
+			}
+		}
+	}
+	return filtered
+}
+
+func hasNotificationChannel(subChannels, channels []types.NotificationChannel) bool {
+	for _, sc := range subChannels {
+		for _, ch := range channels {
+			if sc == ch {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func dispatchNotifications(useDB *sqlx.DB) error {
 
 	err := sendEmailNotifications(useDB)
@@ -503,7 +621,12 @@ func dispatchNotifications(useDB *sqlx.DB) error {
 // garbageCollectNotificationQueue deletes entries from the notification queue that have been processed
 func garbageCollectNotificationQueue(useDB *sqlx.DB) error {
 
-	rows, err := useDB.Exec(`DELETE FROM notification_queue WHERE (sent < now() - INTERVAL '30 minutes') OR (created < now() - INTERVAL '1 hour')`)
+	// entries with a dedup key are kept longer, so notifications collected again after a restart are not queued again
+	rows, err := useDB.Exec(`
+		DELETE FROM notification_queue
+		WHERE (sent < now() - INTERVAL '30 minutes' AND dedup_key IS NULL)
+			OR (sent IS NULL AND created < now() - INTERVAL '1 hour')
+			OR created < now() - INTERVAL '6 hours'`)
 	if err != nil {
 		return fmt.Errorf("error deleting from notification_queue %w", err)
 	}
@@ -515,6 +638,50 @@ func garbageCollectNotificationQueue(useDB *sqlx.DB) error {
 	return nil
 }
 
+// notificationDedupKey returns the key of the queue entry of the notifications of a user on a channel, the same
+// notifications always result in the same key
+func notificationDedupKey(channel types.NotificationChannel, userID uint64, userNotifications map[types.EventName][]types.Notification) string {
+	ids := []string{}
+	for event, ns := range userNotifications {
+		for _, n := range ns {
+			ids = append(ids, fmt.Sprintf("%s:%d:%d:%s", event, n.GetSubscriptionID(), n.GetEpoch(), n.GetEventFilter()))
+		}
+	}
+	sort.Strings(ids)
+	hash := sha256.Sum256([]byte(strings.Join(ids, "|")))
+	return fmt.Sprintf("%s:%d:%s", channel, userID, hex.EncodeToString(hash[:]))
+}
+
+// claimNotificationQueueItem records that the entry is handed to the provider and returns false if it was claimed
+// before. Entries are claimed only once, so an entry is never sent twice.
+func claimNotificationQueueItem(useDB *sqlx.DB, id uint64) (bool, error) {
+	res, err := useDB.Exec(`UPDATE notification_queue SET claimed = now() WHERE id = $1 AND sent IS NULL AND claimed IS NULL`, id)
+	if err != nil {
+		return false, fmt.Errorf("error claiming notification with id: %v, err: %w", id, err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error claiming notification with id: %v, err: %w", id, err)
+	}
+	return rowsAffected == 1, nil
+}
+
+// resolveInterruptedNotifications marks the entries of the channel that were claimed but never marked as sent as sent.
+// The sender was interrupted after handing them to the provider, they might have been delivered and are not sent again.
+// The sender holds an advisory lock, so no other sender can be sending them.
+func resolveInterruptedNotifications(useDB *sqlx.DB, channel types.NotificationChannel) error {
+	res, err := useDB.Exec(`UPDATE notification_queue SET sent = now() WHERE channel = $1 AND sent IS NULL AND claimed IS NOT NULL`, channel)
+	if err != nil {
+		return fmt.Errorf("error resolving interrupted %v notifications, err: %w", channel, err)
+	}
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected > 0 {
+		metrics.Errors.WithLabelValues(fmt.Sprintf("notifications_interrupted_%s", channel)).Add(float64(rowsAffected))
+		logger.Warnf("marked %v interrupted %v notifications as sent without sending them again", rowsAffected, channel)
+	}
+	return nil
+}
+
 func getNetwork() string {
 	domainParts := strings.Split(utils.Config.Frontend.SiteDomain, ".")
 	if len(domainParts) >= 3 {
@@ -534,6 +701,11 @@ func queuePushNotification(notificationsByUserID map[uint64]map[types.EventName]
 		metrics.Errors.WithLabelValues("notifications_send_push_notifications").Inc()
 		return fmt.Errorf("error when sending push-notifications: could not get tokens: %w", err)
 	}
+	mutedSubscriptions, err := db.GetMutedSubscriptionIDs(userIDs)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_send_push_notifications").Inc()
+		return fmt.Errorf("error when sending push-notifications: could not get muted subscriptions: %w", err)
+	}
 
 	for userID, userNotifications := range notificationsByUserID {
 		userTokens, exists := tokensByUserID[userID]
@@ -541,10 +713,13 @@ func queuePushNotification(notificationsByUserID map[uint64]map[types.EventName]
 			continue
 		}
 
-		go func(userTokens []string, userNotifications map[types.EventName][]types.Notification) {
+		go func(userID uint64, userTokens []*types.PushDevice, userNotifications map[types.EventName][]types.Notification) {
 			var batch []*messaging.Message
 			for event, ns := range userNotifications {
 				for _, n := range ns {
+					if mutedSubscriptions[n.GetSubscriptionID()] {
+						continue
+					}
 					added := false
 					for _, userToken := range userTokens {
 						notification := new(messaging.Notification)
@@ -557,13 +732,20 @@ func queuePushNotification(notificationsByUserID map[uint64]map[types.EventName]
 
 						message := new(messaging.Message)
 						message.Notification = notification
-						message.Token = userToken
+						message.Token = userToken.Token
 
 						message.APNS = new(messaging.APNSConfig)
 						message.APNS.Payload = new(messaging.APNSPayload)
 						message.APNS.Payload.Aps = new(messaging.Aps)
 						message.APNS.Payload.Aps.Sound = "default"
 
+						if userToken.HasCapability(types.PushCapabilityRichPayload) {
+							message.Data = getPushNotificationData(n, userToken.HasCapability(types.PushCapabilityActions))
+						}
+						if userToken.HasCapability(types.PushCapabilityActions) {
+							message.APNS.Payload.Aps.Category = types.PushNotificationActionsCategory
+						}
+
 						batch = append(batch, message)
 					}
 					if added {
@@ -576,20 +758,47 @@ func queuePushNotification(notificationsByUserID map[uint64]map[types.EventName]
 				Messages: batch,
 			}
 
-			_, err = useDB.Exec(`INSERT INTO notification_queue (created, channel, content) VALUES ($1, 'push', $2)`, time.Now(), transitPushContent)
+			dedupKey := notificationDedupKey(types.PushNotificationChannel, userID, userNotifications)
+			_, err := useDB.Exec(`INSERT INTO notification_queue (created, channel, content, dedup_key) VALUES ($1, 'push', $2, $3) ON CONFLICT (dedup_key) DO NOTHING`, time.Now(), transitPushContent, dedupKey)
 			if err != nil {
 				logger.WithError(err).Errorf("error writing transit push notification to db")
 				return
 			}
-		}(userTokens, userNotifications)
+		}(userID, userTokens, userNotifications)
 	}
 	return nil
 }
 
+// getPushNotificationData returns the structured data of rich push notifications (see types.PushNotificationSchemaVersion),
+// all values are strings as required by firebase. The url deep links into the app, the actions are only added for devices
+// supporting them.
+func getPushNotificationData(n types.Notification, withActions bool) map[string]string {
+	data := map[string]string{
+		"schema":          types.PushNotificationSchemaVersion,
+		"event":           string(n.GetEventName()),
+		"epoch":           fmt.Sprintf("%d", n.GetEpoch()),
+		"subscription_id": fmt.Sprintf("%d", n.GetSubscriptionID()),
+		"url":             fmt.Sprintf("https://%s/user/notifications", utils.Config.Frontend.SiteDomain),
+	}
+	if vn, ok := n.(types.ValidatorNotification); ok {
+		data["validator_index"] = fmt.Sprintf("%d", vn.GetValidatorIndex())
+		data["url"] = fmt.Sprintf("https://%s/validator/%d", utils.Config.Frontend.SiteDomain, vn.GetValidatorIndex())
+	}
+	if withActions && n.GetSubscriptionID() != 0 {
+		data["actions"] = types.PushNotificationActionMute1h
+	}
+	return data
+}
+
 func sendPushNotifications(useDB *sqlx.DB) error {
 	var notificationQueueItem []types.TransitPush
 
-	err := useDB.Select(&notificationQueueItem, `SELECT
+	err := resolveInterruptedNotifications(useDB, types.PushNotificationChannel)
+	if err != nil {
+		return err
+	}
+
+	err = useDB.Select(&notificationQueueItem, `SELECT
 		id,
 		created,
 		sent,
@@ -604,6 +813,14 @@ func sendPushNotifications(useDB *sqlx.DB) error {
 
 	batchSize := 500
 	for _, n := range notificationQueueItem {
+		claimed, err := claimNotificationQueueItem(useDB, n.Id)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
 		for b := 0; b < len(n.Content.Messages); b += batchSize {
 			start := b
 			end := b + batchSize
@@ -616,13 +833,13 @@ func sendPushNotifications(useDB *sqlx.DB) error {
 				metrics.Errors.WithLabelValues("notifications_send_push_batch").Inc()
 				logger.WithError(err).Error("error sending firebase batch job")
 			} else {
-				metrics.NotificationsSent.WithLabelValues("push", "200").Add(float64(len(n.Content.Messages)))
+				metrics.NotificationsSent.WithLabelValues("push", "200").Add(float64(end - start))
 			}
+		}
 
-			_, err = useDB.Exec(`UPDATE notification_queue SET sent = now() WHERE id = $1`, n.Id)
-			if err != nil {
-				return fmt.Errorf("error updating sent status for push notification with id: %v, err: %w", n.Id, err)
-			}
+		_, err = useDB.Exec(`UPDATE notification_queue SET sent = now() WHERE id = $1`, n.Id)
+		if err != nil {
+			return fmt.Errorf("error updating sent status for push notification with id: %v, err: %w", n.Id, err)
 		}
 	}
 	return nil
@@ -638,6 +855,11 @@ func queueEmailNotifications(notificationsByUserID map[uint64]map[types.EventNam
 		metrics.Errors.WithLabelValues("notifications_get_user_mail_by_id").Inc()
 		return fmt.Errorf("error when sending email-notifications: could not get emails: %w", err)
 	}
+	localesByUserID, err := db.GetUserLocalesByIds(userIDs)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_get_user_locale_by_id").Inc()
+		return fmt.Errorf("error when sending email-notifications: could not get locales: %w", err)
+	}
 
 	for userID, userNotifications := range notificationsByUserID {
 		userEmail, exists := emailsByUserID[userID]
@@ -647,27 +869,21 @@ func queueEmailNotifications(notificationsByUserID map[uint64]map[types.EventNam
 			// metrics.Errors.WithLabelValues("notifications_mail_not_found").Inc()
 			continue
 		}
-		go func(userEmail string, userNotifications map[types.EventName][]types.Notification) {
+		go func(userID uint64, userEmail, locale string, userNotifications map[types.EventName][]types.Notification) {
 			attachments := []types.EmailAttachment{}
 
-			var msg types.Email
-
-			if utils.Config.Chain.Name != "mainnet" {
-				msg.Body += template.HTML(fmt.Sprintf("<b>Notice: This email contains notifications for the %s network!</b><br>", utils.Config.Chain.Name))
-			}
+			msg := types.Email{Locale: mail.NormalizeLocale(locale)}
+			sections := []mail.NotificationSection{}
 
 			subject := ""
 			notificationTitlesMap := make(map[string]bool)
 			notificationTitles := []string{}
 			for event, ns := range userNotifications {
-				if len(msg.Body) > 0 {
-					msg.Body += "<br>"
-				}
 				event_title := event
 				if event == types.TaxReportEventName {
 					event_title = "income_history"
 				}
-				msg.Body += template.HTML(fmt.Sprintf("%s<br>====<br><br>", types.EventLabel[event_title]))
+				section := mail.NotificationSection{Event: event, Label: types.EventLabel[event_title]}
 				unsubURL := "https://" + utils.Config.Frontend.SiteDomain + "/notifications/unsubscribe"
 				for i, n := range ns {
 					// Find all unique notification titles for the subject
@@ -727,11 +943,11 @@ func queueEmailNotifications(notificationsByUserID map[uint64]map[types.EventNam
 					} else {
 						unsubURL += "&hash=" + html.EscapeString(unsubHash)
 					}
-					msg.UnSubURL = template.HTML(fmt.Sprintf(`<a style="color: white" onMouseOver="this.style.color='#F5B498'" onMouseOut="this.style.color='#FFFFFF'" href="%v">Unsubscribe</a>`, unsubURL))
+					msg.UnSubURL = template.HTML(fmt.Sprintf(`<a style="color: white" onMouseOver="this.style.color='#F5B498'" onMouseOut="this.style.color='#FFFFFF'" href="%v">%s</a>`, unsubURL, utils.TrLang(msg.Locale, "mail_unsubscribe_link")))
 
 					if event != types.SyncCommitteeSoon {
 						// SyncCommitteeSoon notifications are summed up in getEventInfo for all validators
-						msg.Body += template.HTML(fmt.Sprintf("%s<br>", n.GetInfo(true)))
+						section.Notifications = append(section.Notifications, template.HTML(n.GetInfo(true)))
 					}
 
 					if att := n.GetEmailAttachment(); att != nil {
@@ -741,11 +957,16 @@ func queueEmailNotifications(notificationsByUserID map[uint64]map[types.EventNam
 					metrics.NotificationsQueued.WithLabelValues("email", string(event)).Inc()
 				}
 
-				eventInfo := getEventInfo(event, ns)
-				if eventInfo != "" {
-					msg.Body += template.HTML(fmt.Sprintf("%s<br>", eventInfo))
-				}
+				section.Info = template.HTML(getEventInfo(event, ns))
+				sections = append(sections, section)
+			}
+
+			body, err := mail.RenderNotificationMail(msg.Locale, sections)
+			if err != nil {
+				logger.WithError(err).Errorf("error rendering email notifications")
+				return
 			}
+			msg.Body = body
 
 			if len(notificationTitles) > 2 {
 				subject = fmt.Sprintf("%s: %s,... and %d other notifications", utils.Config.Frontend.SiteDomain, notificationTitles[0], len(notificationTitles)-1)
@@ -756,7 +977,7 @@ func queueEmailNotifications(notificationsByUserID map[uint64]map[types.EventNam
 			}
 
 			// msg.Body += template.HTML(fmt.Sprintf("<br>Best regards<br>\n%s", utils.Config.Frontend.SiteDomain))
-			msg.SubscriptionManageURL = template.HTML(fmt.Sprintf(`<a href="%v" style="color: white" onMouseOver="this.style.color='#F5B498'" onMouseOut="this.style.color='#FFFFFF'">Manage</a>`, "https://"+utils.Config.Frontend.SiteDomain+"/user/notifications"))
+			msg.SubscriptionManageURL = template.HTML(fmt.Sprintf(`<a href="%v" style="color: white" onMouseOver="this.style.color='#F5B498'" onMouseOut="this.style.color='#FFFFFF'">%s</a>`, "https://"+utils.Config.Frontend.SiteDomain+"/user/notifications", utils.TrLang(msg.Locale, "mail_manage_link")))
 
 			transitEmailContent := types.TransitEmailContent{
 				Address:     userEmail,
@@ -765,11 +986,12 @@ func queueEmailNotifications(notificationsByUserID map[uint64]map[types.EventNam
 				Attachments: attachments,
 			}
 
-			_, err = useDB.Exec(`INSERT INTO notification_queue (created, channel, content) VALUES ($1, 'email', $2)`, time.Now(), transitEmailContent)
+			dedupKey := notificationDedupKey(types.EmailNotificationChannel, userID, userNotifications)
+			_, err = useDB.Exec(`INSERT INTO notification_queue (created, channel, content, dedup_key) VALUES ($1, 'email', $2, $3) ON CONFLICT (dedup_key) DO NOTHING`, time.Now(), transitEmailContent, dedupKey)
 			if err != nil {
 				logger.WithError(err).Errorf("error writing transit email to db")
 			}
-		}(userEmail, userNotifications)
+		}(userID, userEmail, localesByUserID[userID], userNotifications)
 	}
 	return nil
 }
@@ -777,7 +999,12 @@ func queueEmailNotifications(notificationsByUserID map[uint64]map[types.EventNam
 func sendEmailNotifications(useDb *sqlx.DB) error {
 	var notificationQueueItem []types.TransitEmail
 
-	err := useDb.Select(&notificationQueueItem, `SELECT
+	err := resolveInterruptedNotifications(useDb, types.EmailNotificationChannel)
+	if err != nil {
+		return err
+	}
+
+	err = useDb.Select(&notificationQueueItem, `SELECT
 		id,
 		created,
 		sent,
@@ -791,6 +1018,14 @@ func sendEmailNotifications(useDb *sqlx.DB) error {
 	logger.Infof("processing %v email notifications", len(notificationQueueItem))
 
 	for _, n := range notificationQueueItem {
+		claimed, err := claimNotificationQueueItem(useDb, n.Id)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
 		err = mail.SendMailRateLimited(n.Content.Address, n.Content.Subject, n.Content.Email, n.Content.Attachments)
 		if err != nil {
 			if !strings.Contains(err.Error(), "rate limit has been exceeded") {
@@ -808,6 +1043,8 @@ func sendEmailNotifications(useDb *sqlx.DB) error {
 	return nil
 }
 
+// queueWebhookNotifications queues the notifications of every user for the webhooks of the user and the webhooks shared
+// with the organization of the user
 func queueWebhookNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, useDB *sqlx.DB) error {
 	for userID, userNotifications := range notificationsByUserID {
 		var webhooks []types.UserWebhook
@@ -822,7 +1059,8 @@ func queueWebhookNotifications(notificationsByUserID map[uint64]map[types.EventN
 			FROM 
 				users_webhooks
 			WHERE 
-				user_id = $1 AND user_id NOT IN (SELECT user_id from users_notification_channels WHERE active = false and channel = $2)
+				(user_id = $1 OR organization_id = (SELECT organization_id FROM organization_members WHERE user_id = $1))
+				AND user_id NOT IN (SELECT user_id from users_notification_channels WHERE active = false and channel = $2)
 		`, userID, types.WebhookNotificationChannel)
 		// continue if the user does not have a webhook
 		if err == sql.ErrNoRows {
@@ -962,7 +1200,7 @@ func sendWebhookNotifications(useDB *sqlx.DB) error {
 	if err != nil {
 		return fmt.Errorf("error querying notification queue, err: %w", err)
 	}
-	client := &http.Client{Timeout: time.Second * 30}
+	client := httpclient.New(httpclient.TargetPush, nil)
 
 	logger.Infof("processing %v webhook notifications", len(notificationQueueItem))
 
@@ -1053,7 +1291,7 @@ func sendDiscordNotifications(useDB *sqlx.DB) error {
 	if err != nil {
 		return fmt.Errorf("error querying notification queue, err: %w", err)
 	}
-	client := &http.Client{Timeout: time.Second * 30}
+	client := httpclient.New(httpclient.TargetPush, nil)
 
 	logger.Infof("processing %v discord webhook notifications", len(notificationQueueItem))
 	webhookMap := make(map[uint64]types.UserWebhook)
@@ -1293,6 +1531,10 @@ func (n *validatorProposalNotification) GetEmailAttachment() *types.EmailAttachm
 	return nil
 }
 
+func (n *validatorProposalNotification) GetValidatorIndex() uint64 {
+	return n.ValidatorIndex
+}
+
 func (n *validatorProposalNotification) GetSubscriptionID() uint64 {
 	return n.SubscriptionID
 }
@@ -1467,10 +1709,6 @@ func collectAttestationAndOfflineValidatorNotifications(notificationsByUserID ma
 	}
 
 	// detect online & offline validators
-	type indexPubkeyPair struct {
-		Index  uint64
-		Pubkey []byte
-	}
 	var offlineValidators []*indexPubkeyPair
 	var onlineValidators []*indexPubkeyPair
 
@@ -1648,9 +1886,291 @@ func collectAttestationAndOfflineValidatorNotifications(notificationsByUserID ma
 		}
 	}
 
+	err = collectValidatorsClusterOfflineNotifications(notificationsByUserID, offlineValidators, epoch)
+	if err != nil {
+		metrics.Errors.WithLabelValues("notifications_collect_validators_cluster_offline").Inc()
+		logger.WithError(err).Error("error collecting validators cluster offline notifications")
+	}
+
+	return nil
+}
+
+type indexPubkeyPair struct {
+	Index  uint64
+	Pubkey []byte
+}
+
+// defaultValidatorsClusterOfflineThreshold is the number of validators that have to go offline at once for a cluster
+// offline notification if the subscription has no threshold
+const defaultValidatorsClusterOfflineThreshold = 10
+
+// collectValidatorsClusterOfflineNotifications aggregates the validators of a user that went offline in the same epoch
+// window into a single notification once at least the threshold of the subscription went offline. The subscription
+// covers the watchlist of the user or, if the filter is a dashboard id, the validators of the dashboard. The offline
+// and missed attestation notifications of the aggregated validators are dropped, so the user is not flooded with them.
+func collectValidatorsClusterOfflineNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, offlineValidators []*indexPubkeyPair, epoch uint64) error {
+	if len(offlineValidators) == 0 {
+		return nil
+	}
+
+	var subs []struct {
+		ID              uint64         `db:"id"`
+		UserID          uint64         `db:"user_id"`
+		EventFilter     string         `db:"event_filter"`
+		EventThreshold  float64        `db:"event_threshold"`
+		UnsubscribeHash sql.NullString `db:"unsubscribe_hash"`
+	}
+	err := db.FrontendWriterDB.Select(&subs, `
+		SELECT id, user_id, event_filter, COALESCE(event_threshold, 0) AS event_threshold, ENCODE(unsubscribe_hash, 'hex') AS unsubscribe_hash
+		FROM users_subscriptions
+		WHERE event_name = $1 AND created_epoch <= $2`, utils.GetNetwork()+":"+string(types.ValidatorsClusterOfflineEventName), epoch)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for %v: %w", types.ValidatorsClusterOfflineEventName, err)
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	userIDs := make([]uint64, 0, len(subs))
+	for _, sub := range subs {
+		userIDs = append(userIDs, sub.UserID)
+	}
+	indexByPubkey := make(map[string]uint64, len(offlineValidators))
+	pubkeys := make(pq.ByteaArray, 0, len(offlineValidators))
+	indices := make(pq.Int64Array, 0, len(offlineValidators))
+	for _, v := range offlineValidators {
+		indexByPubkey[string(v.Pubkey)] = v.Index
+		pubkeys = append(pubkeys, v.Pubkey)
+		indices = append(indices, int64(v.Index))
+	}
+
+	// the offline validators on the watchlists and dashboards of the subscribed users
+	var watched []struct {
+		UserID uint64 `db:"user_id"`
+		Pubkey []byte `db:"validator_publickey"`
+	}
+	err = db.FrontendWriterDB.Select(&watched, `
+		SELECT user_id, validator_publickey
+		FROM users_validators_tags
+		WHERE user_id = ANY($1) AND tag = $2 AND validator_publickey = ANY($3)`,
+		pq.Array(userIDs), utils.GetNetwork()+":"+string(types.ValidatorTagsWatchlist), pubkeys)
+	if err != nil {
+		return fmt.Errorf("error getting watched offline validators: %w", err)
+	}
+	var onDashboards []struct {
+		DashboardID    uint64 `db:"dashboard_id"`
+		UserID         uint64 `db:"user_id"`
+		ValidatorIndex uint64 `db:"validator_index"`
+	}
+	err = db.ReaderDb.Select(&onDashboards, `
+		SELECT d.id AS dashboard_id, d.user_id, v.validator_index
+		FROM users_val_dashboards d
+		INNER JOIN users_val_dashboards_validators v ON v.dashboard_id = d.id
+		WHERE d.user_id = ANY($1) AND d.network = $2 AND v.validator_index = ANY($3)`, pq.Array(userIDs), utils.Config.Chain.ClConfig.DepositChainID, indices)
+	if err != nil {
+		return fmt.Errorf("error getting offline validators of dashboards: %w", err)
+	}
+
+	offlineByWatchlist := map[uint64][]uint64{}
+	for _, w := range watched {
+		offlineByWatchlist[w.UserID] = append(offlineByWatchlist[w.UserID], indexByPubkey[string(w.Pubkey)])
+	}
+	offlineByDashboard := map[string][]uint64{}
+	for _, d := range onDashboards {
+		key := fmt.Sprintf("%d:%d", d.UserID, d.DashboardID)
+		offlineByDashboard[key] = append(offlineByDashboard[key], d.ValidatorIndex)
+	}
+
+	for _, sub := range subs {
+		offline := offlineByWatchlist[sub.UserID]
+		if sub.EventFilter != "" {
+			offline = offlineByDashboard[fmt.Sprintf("%d:%s", sub.UserID, sub.EventFilter)]
+		}
+		threshold := int(sub.EventThreshold)
+		if threshold <= 0 {
+			threshold = defaultValidatorsClusterOfflineThreshold
+		}
+		if len(offline) < threshold {
+			continue
+		}
+		offline = utils.SortedUniqueUint64(offline)
+
+		machines, err := getOfflineUserMachines(sub.UserID)
+		if err != nil {
+			// the notification is sent without the correlated machines
+			logger.WithError(err).Errorf("error getting offline machines of user %v", sub.UserID)
+		}
+
+		logger.Infof("new event: %v validators of user %v detected as offline in epoch %v", len(offline), sub.UserID, epoch)
+		n := &validatorsClusterOfflineNotification{
+			SubscriptionID:   sub.ID,
+			UserID:           sub.UserID,
+			EventEpoch:       epoch,
+			EventFilter:      sub.EventFilter,
+			ValidatorIndices: offline,
+			OfflineMachines:  machines,
+			UnsubscribeHash:  sub.UnsubscribeHash,
+		}
+		dropClusteredValidatorNotifications(notificationsByUserID[sub.UserID], offline)
+		if _, exists := notificationsByUserID[sub.UserID]; !exists {
+			notificationsByUserID[sub.UserID] = map[types.EventName][]types.Notification{}
+		}
+		notificationsByUserID[sub.UserID][n.GetEventName()] = append(notificationsByUserID[sub.UserID][n.GetEventName()], n)
+		metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+	}
+	return nil
+}
+
+// dropClusteredValidatorNotifications removes the offline and missed attestation notifications of the validators
+// from the notifications of a user
+func dropClusteredValidatorNotifications(userNotifications map[types.EventName][]types.Notification, validators []uint64) {
+	clustered := make(map[uint64]bool, len(validators))
+	for _, v := range validators {
+		clustered[v] = true
+	}
+	for _, eventName := range []types.EventName{types.ValidatorIsOfflineEventName, types.ValidatorMissedAttestationEventName} {
+		ns, ok := userNotifications[eventName]
+		if !ok {
+			continue
+		}
+		kept := make([]types.Notification, 0, len(ns))
+		for _, n := range ns {
+			if offlineN, ok := n.(*validatorIsOfflineNotification); ok && !offlineN.IsOffline {
+				kept = append(kept, n)
+				continue
+			}
+			if vn, ok := n.(types.ValidatorNotification); ok && clustered[vn.GetValidatorIndex()] {
+				continue
+			}
+			kept = append(kept, n)
+		}
+		userNotifications[eventName] = kept
+	}
+}
+
+// getOfflineUserMachines returns the machines of the user that stopped reporting metrics, they are the likely cause of
+// validators going offline at once
+func getOfflineUserMachines(userID uint64) ([]string, error) {
+	names, err := db.BigtableClient.GetMachineMetricsMachineNames(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	rowKeys := gcp_bigtable.RowList{}
+	for _, name := range names {
+		rowKeys = append(rowKeys, db.BigtableClient.GetMachineRowKey(userID, "system", name))
+	}
+	machineData, err := db.BigtableClient.GetMachineMetricsForNotifications(rowKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	offline := []string{}
+	for name, data := range machineData[userID] {
+		if isMachineOffline(data) {
+			offline = append(offline, name)
+		}
+	}
+	sort.Strings(offline)
+	return offline, nil
+}
+
+type validatorsClusterOfflineNotification struct {
+	SubscriptionID   uint64
+	UserID           uint64
+	EventEpoch       uint64
+	EventFilter      string
+	ValidatorIndices []uint64
+	OfflineMachines  []string
+	UnsubscribeHash  sql.NullString
+}
+
+func (n *validatorsClusterOfflineNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *validatorsClusterOfflineNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *validatorsClusterOfflineNotification) GetEventName() types.EventName {
+	return types.ValidatorsClusterOfflineEventName
+}
+
+func (n *validatorsClusterOfflineNotification) GetEpoch() uint64 {
+	return n.EventEpoch
+}
+
+// validatorList returns the first validators of the cluster, the format function formats a single validator
+func (n *validatorsClusterOfflineNotification) validatorList(format func(index uint64) string) string {
+	const maxListed = 10
+	listed := []string{}
+	for i, index := range n.ValidatorIndices {
+		if i == maxListed {
+			listed = append(listed, fmt.Sprintf("and %d more", len(n.ValidatorIndices)-maxListed))
+			break
+		}
+		listed = append(listed, format(index))
+	}
+	return strings.Join(listed, ", ")
+}
+
+func (n *validatorsClusterOfflineNotification) scope() string {
+	if n.EventFilter != "" {
+		return fmt.Sprintf("of dashboard %v ", n.EventFilter)
+	}
+	return ""
+}
+
+func (n *validatorsClusterOfflineNotification) machinesPart() string {
+	if len(n.OfflineMachines) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" The following of your machines stopped reporting metrics: %s.", strings.Join(n.OfflineMachines, ", "))
+}
+
+func (n *validatorsClusterOfflineNotification) GetInfo(includeUrl bool) string {
+	validators := n.validatorList(func(index uint64) string {
+		if includeUrl {
+			return fmt.Sprintf(`<a href="https://%[2]v/validator/%[1]v">%[1]v</a>`, index, utils.Config.Frontend.SiteDomain)
+		}
+		return fmt.Sprint(index)
+	})
+	machines := n.machinesPart()
+	if includeUrl {
+		machines = html.EscapeString(machines)
+	}
+	return fmt.Sprintf(`%v of your validators %swent offline at once in epoch %v (%s).%s`, len(n.ValidatorIndices), n.scope(), n.EventEpoch, validators, machines)
+}
+
+func (n *validatorsClusterOfflineNotification) GetTitle() string {
+	return "Validators Cluster Offline"
+}
+
+func (n *validatorsClusterOfflineNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *validatorsClusterOfflineNotification) GetEmailAttachment() *types.EmailAttachment {
 	return nil
 }
 
+func (n *validatorsClusterOfflineNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *validatorsClusterOfflineNotification) GetInfoMarkdown() string {
+	validators := n.validatorList(func(index uint64) string {
+		return fmt.Sprintf(`[%[1]v](https://%[2]v/validator/%[1]v)`, index, utils.Config.Frontend.SiteDomain)
+	})
+	return fmt.Sprintf(`%v of your validators %swent offline at once in epoch [%[3]v](https://%[6]v/epoch/%[3]v) (%[4]s).%[5]s`, len(n.ValidatorIndices), n.scope(), n.EventEpoch, validators, n.machinesPart(), utils.Config.Frontend.SiteDomain)
+}
+
 type validatorIsOfflineNotification struct {
 	SubscriptionID  uint64
 	ValidatorIndex  uint64
@@ -1667,6 +2187,10 @@ func (n *validatorIsOfflineNotification) GetLatestState() string {
 	return n.InternalState
 }
 
+func (n *validatorIsOfflineNotification) GetValidatorIndex() uint64 {
+	return n.ValidatorIndex
+}
+
 func (n *validatorIsOfflineNotification) GetSubscriptionID() uint64 {
 	return n.SubscriptionID
 }
@@ -1741,6 +2265,10 @@ func (n *validatorAttestationNotification) GetLatestState() string {
 	return ""
 }
 
+func (n *validatorAttestationNotification) GetValidatorIndex() uint64 {
+	return n.ValidatorIndex
+}
+
 func (n *validatorAttestationNotification) GetSubscriptionID() uint64 {
 	return n.SubscriptionID
 }
@@ -1835,6 +2363,10 @@ func (n *validatorGotSlashedNotification) GetEmailAttachment() *types.EmailAttac
 	return nil
 }
 
+func (n *validatorGotSlashedNotification) GetValidatorIndex() uint64 {
+	return n.ValidatorIndex
+}
+
 func (n *validatorGotSlashedNotification) GetSubscriptionID() uint64 {
 	return n.SubscriptionID
 }
@@ -1956,6 +2488,10 @@ func (n *validatorWithdrawalNotification) GetEmailAttachment() *types.EmailAttac
 	return nil
 }
 
+func (n *validatorWithdrawalNotification) GetValidatorIndex() uint64 {
+	return n.ValidatorIndex
+}
+
 func (n *validatorWithdrawalNotification) GetSubscriptionID() uint64 {
 	return n.SubscriptionID
 }
@@ -2044,31 +2580,1008 @@ func collectWithdrawalNotifications(notificationsByUserID map[uint64]map[types.E
 	return nil
 }
 
-type ethClientNotification struct {
+type withdrawalCredentialsChangedNotification struct {
 	SubscriptionID  uint64
-	UserID          uint64
+	ValidatorIndex  uint64
 	Epoch           uint64
-	EthClient       string
+	Slot            uint64
+	Address         []byte
 	EventFilter     string
 	UnsubscribeHash sql.NullString
 }
 
-func (n *ethClientNotification) GetLatestState() string {
+func (n *withdrawalCredentialsChangedNotification) GetLatestState() string {
 	return ""
 }
 
-func (n *ethClientNotification) GetUnsubscribeHash() string {
+func (n *withdrawalCredentialsChangedNotification) GetUnsubscribeHash() string {
 	if n.UnsubscribeHash.Valid {
 		return n.UnsubscribeHash.String
 	}
 	return ""
 }
 
-func (n *ethClientNotification) GetEmailAttachment() *types.EmailAttachment {
+func (n *withdrawalCredentialsChangedNotification) GetEmailAttachment() *types.EmailAttachment {
 	return nil
 }
 
-func (n *ethClientNotification) GetSubscriptionID() uint64 {
+func (n *withdrawalCredentialsChangedNotification) GetValidatorIndex() uint64 {
+	return n.ValidatorIndex
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetEventName() types.EventName {
+	return types.ValidatorWithdrawalCredentialsChangedEventName
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetInfo(includeUrl bool) string {
+	generalPart := fmt.Sprintf(`The withdrawal credentials of validator %v have been changed to the address %v in slot %v. If you did not request this change, your withdrawal key might be compromised.`, n.ValidatorIndex, utils.FormatHashRaw(n.Address), n.Slot)
+	if includeUrl {
+		return generalPart + getUrlPart(n.ValidatorIndex)
+	}
+	return generalPart
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetTitle() string {
+	return "Withdrawal Credentials Changed"
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *withdrawalCredentialsChangedNotification) GetInfoMarkdown() string {
+	generalPart := fmt.Sprintf(`The withdrawal credentials of validator [%[1]v](https://%[5]v/validator/%[1]v) have been changed to the address [%[3]v](https://%[5]v/address/0x%[4]x) in slot [%[2]v](https://%[5]v/slot/%[2]v). If you did not request this change, your withdrawal key might be compromised.`, n.ValidatorIndex, n.Slot, utils.FormatHashRaw(n.Address), n.Address, utils.Config.Frontend.SiteDomain)
+	return generalPart
+}
+
+// collectWithdrawalCredentialsChangedNotifications collects the notifications of watched validators whose withdrawal
+// credentials were changed by a BLS to execution change included in the epoch
+func collectWithdrawalCredentialsChangedNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, epoch uint64) error {
+	_, subMap, err := db.GetSubsForEventFilter(types.ValidatorWithdrawalCredentialsChangedEventName)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for withdrawal credential changes %w", err)
+	}
+
+	// bls changes are limited to 16 per block
+	events, err := db.GetEpochBLSChanges(epoch)
+	if err != nil {
+		return fmt.Errorf("error getting bls changes from database, err: %w", err)
+	}
+
+	for _, event := range events {
+		subscribers, ok := subMap[hex.EncodeToString(event.Pubkey)]
+		if !ok {
+			continue
+		}
+		for _, sub := range subscribers {
+			if sub.UserID == nil || sub.ID == nil {
+				return fmt.Errorf("error expected userId and subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+			}
+			if sub.LastEpoch != nil {
+				lastSentEpoch := *sub.LastEpoch
+				if lastSentEpoch >= epoch || epoch < sub.CreatedEpoch {
+					continue
+				}
+			}
+			n := &withdrawalCredentialsChangedNotification{
+				SubscriptionID:  *sub.ID,
+				ValidatorIndex:  event.ValidatorIndex,
+				Epoch:           epoch,
+				Slot:            event.Slot,
+				Address:         event.Address,
+				EventFilter:     hex.EncodeToString(event.Pubkey),
+				UnsubscribeHash: sub.UnsubscribeHash,
+			}
+			if _, exists := notificationsByUserID[*sub.UserID]; !exists {
+				notificationsByUserID[*sub.UserID] = map[types.EventName][]types.Notification{}
+			}
+			if _, exists := notificationsByUserID[*sub.UserID][n.GetEventName()]; !exists {
+				notificationsByUserID[*sub.UserID][n.GetEventName()] = []types.Notification{}
+			}
+			notificationsByUserID[*sub.UserID][n.GetEventName()] = append(notificationsByUserID[*sub.UserID][n.GetEventName()], n)
+			metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+		}
+	}
+
+	return nil
+}
+
+type validatorExitNotification struct {
+	SubscriptionID    uint64
+	ValidatorIndex    uint64
+	Epoch             uint64
+	Slot              uint64
+	ExitEpoch         uint64
+	WithdrawableEpoch uint64
+	EventName         types.EventName
+	EventFilter       string
+	UnsubscribeHash   sql.NullString
+}
+
+func (n *validatorExitNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *validatorExitNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *validatorExitNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *validatorExitNotification) GetValidatorIndex() uint64 {
+	return n.ValidatorIndex
+}
+
+func (n *validatorExitNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *validatorExitNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *validatorExitNotification) GetEventName() types.EventName {
+	return n.EventName
+}
+
+func (n *validatorExitNotification) GetInfo(includeUrl bool) string {
+	var generalPart string
+	switch n.EventName {
+	case types.ValidatorVoluntaryExitEventName:
+		generalPart = fmt.Sprintf(`The voluntary exit of validator %v has been included in slot %v. The validator will exit in epoch %v and become withdrawable in epoch %v.`, n.ValidatorIndex, n.Slot, n.ExitEpoch, n.WithdrawableEpoch)
+	case types.ValidatorWithdrawableEventName:
+		generalPart = fmt.Sprintf(`Validator %v has become withdrawable in epoch %v. Its balance will be withdrawn once the withdrawal sweep reaches it.`, n.ValidatorIndex, n.WithdrawableEpoch)
+	}
+	if includeUrl {
+		return generalPart + getUrlPart(n.ValidatorIndex)
+	}
+	return generalPart
+}
+
+func (n *validatorExitNotification) GetTitle() string {
+	switch n.EventName {
+	case types.ValidatorVoluntaryExitEventName:
+		return "Voluntary Exit Included"
+	case types.ValidatorWithdrawableEventName:
+		return "Validator Withdrawable"
+	}
+	return "-"
+}
+
+func (n *validatorExitNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *validatorExitNotification) GetInfoMarkdown() string {
+	switch n.EventName {
+	case types.ValidatorVoluntaryExitEventName:
+		return fmt.Sprintf(`The voluntary exit of validator [%[1]v](https://%[5]v/validator/%[1]v) has been included in slot [%[2]v](https://%[5]v/slot/%[2]v). The validator will exit in epoch [%[3]v](https://%[5]v/epoch/%[3]v) and become withdrawable in epoch [%[4]v](https://%[5]v/epoch/%[4]v).`, n.ValidatorIndex, n.Slot, n.ExitEpoch, n.WithdrawableEpoch, utils.Config.Frontend.SiteDomain)
+	case types.ValidatorWithdrawableEventName:
+		return fmt.Sprintf(`Validator [%[1]v](https://%[3]v/validator/%[1]v) has become withdrawable in epoch [%[2]v](https://%[3]v/epoch/%[2]v). Its balance will be withdrawn once the withdrawal sweep reaches it.`, n.ValidatorIndex, n.WithdrawableEpoch, utils.Config.Frontend.SiteDomain)
+	}
+	return ""
+}
+
+// collectValidatorExitNotifications collects the notifications of watched validators whose voluntary exit was included
+// in the epoch or which became withdrawable in the epoch, depending on the event name
+func collectValidatorExitNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, epoch uint64, eventName types.EventName) error {
+	_, subMap, err := db.GetSubsForEventFilter(eventName)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for %v %w", eventName, err)
+	}
+
+	var events []*types.ValidatorExitNotification
+	switch eventName {
+	case types.ValidatorVoluntaryExitEventName:
+		events, err = db.GetEpochVoluntaryExits(epoch)
+	case types.ValidatorWithdrawableEventName:
+		events, err = db.GetValidatorsWithdrawableAtEpoch(epoch)
+	default:
+		return fmt.Errorf("unsupported validator exit event name: %v", eventName)
+	}
+	if err != nil {
+		return fmt.Errorf("error getting %v events from database, err: %w", eventName, err)
+	}
+
+	for _, event := range events {
+		subscribers, ok := subMap[hex.EncodeToString(event.Pubkey)]
+		if !ok {
+			continue
+		}
+		for _, sub := range subscribers {
+			if sub.UserID == nil || sub.ID == nil {
+				return fmt.Errorf("error expected userId and subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+			}
+			if sub.LastEpoch != nil {
+				lastSentEpoch := *sub.LastEpoch
+				if lastSentEpoch >= epoch || epoch < sub.CreatedEpoch {
+					continue
+				}
+			}
+			n := &validatorExitNotification{
+				SubscriptionID:    *sub.ID,
+				ValidatorIndex:    event.ValidatorIndex,
+				Epoch:             epoch,
+				Slot:              event.Slot,
+				ExitEpoch:         event.ExitEpoch,
+				WithdrawableEpoch: event.WithdrawableEpoch,
+				EventName:         eventName,
+				EventFilter:       hex.EncodeToString(event.Pubkey),
+				UnsubscribeHash:   sub.UnsubscribeHash,
+			}
+			if _, exists := notificationsByUserID[*sub.UserID]; !exists {
+				notificationsByUserID[*sub.UserID] = map[types.EventName][]types.Notification{}
+			}
+			if _, exists := notificationsByUserID[*sub.UserID][n.GetEventName()]; !exists {
+				notificationsByUserID[*sub.UserID][n.GetEventName()] = []types.Notification{}
+			}
+			notificationsByUserID[*sub.UserID][n.GetEventName()] = append(notificationsByUserID[*sub.UserID][n.GetEventName()], n)
+			metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+		}
+	}
+
+	return nil
+}
+
+type validatorFeeRecipientNotification struct {
+	SubscriptionID       uint64
+	ValidatorIndex       uint64
+	Epoch                uint64
+	Slot                 uint64
+	FeeRecipient         []byte
+	ExpectedFeeRecipient []byte
+	EventFilter          string
+	UnsubscribeHash      sql.NullString
+}
+
+func (n *validatorFeeRecipientNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *validatorFeeRecipientNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *validatorFeeRecipientNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *validatorFeeRecipientNotification) GetValidatorIndex() uint64 {
+	return n.ValidatorIndex
+}
+
+func (n *validatorFeeRecipientNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *validatorFeeRecipientNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *validatorFeeRecipientNotification) GetEventName() types.EventName {
+	return types.ValidatorFeeRecipientMismatchEventName
+}
+
+func (n *validatorFeeRecipientNotification) GetInfo(includeUrl bool) string {
+	generalPart := fmt.Sprintf(`Validator %v proposed the block at slot %v with fee recipient %v instead of the expected fee recipient %v. Please check the fee recipient configuration of your validator client.`, n.ValidatorIndex, n.Slot, common.BytesToAddress(n.FeeRecipient).Hex(), common.BytesToAddress(n.ExpectedFeeRecipient).Hex())
+	if includeUrl {
+		return generalPart + getUrlPart(n.ValidatorIndex)
+	}
+	return generalPart
+}
+
+func (n *validatorFeeRecipientNotification) GetTitle() string {
+	return "Unexpected Fee Recipient"
+}
+
+func (n *validatorFeeRecipientNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *validatorFeeRecipientNotification) GetInfoMarkdown() string {
+	return fmt.Sprintf(`Validator [%[1]v](https://%[5]v/validator/%[1]v) proposed the block at slot [%[2]v](https://%[5]v/slot/%[2]v) with fee recipient [%[3]v](https://%[5]v/address/%[3]v) instead of the expected fee recipient [%[4]v](https://%[5]v/address/%[4]v). Please check the fee recipient configuration of your validator client.`, n.ValidatorIndex, n.Slot, common.BytesToAddress(n.FeeRecipient).Hex(), common.BytesToAddress(n.ExpectedFeeRecipient).Hex(), utils.Config.Frontend.SiteDomain)
+}
+
+// collectFeeRecipientMismatchNotifications collects the notifications of watched validators that proposed a block in the
+// epoch which pays a different fee recipient than the one their user expects. Subscriptions without an expected fee
+// recipient for the validator are skipped.
+func collectFeeRecipientMismatchNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, epoch uint64) error {
+	_, subMap, err := db.GetSubsForEventFilter(types.ValidatorFeeRecipientMismatchEventName)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for %v %w", types.ValidatorFeeRecipientMismatchEventName, err)
+	}
+	if len(subMap) == 0 {
+		return nil
+	}
+
+	blocks, err := db.GetBlockFeeRecipients(epoch)
+	if err != nil {
+		return err
+	}
+
+	pubkeys := make(map[uint64][]byte, len(blocks))
+	pubkeyList := make([][]byte, 0, len(blocks))
+	for _, block := range blocks {
+		pubkey, err := GetPubkeyForIndex(block.Proposer)
+		if err != nil {
+			utils.LogError(err, "error retrieving pubkey for validator", 0, map[string]interface{}{"validator": block.Proposer})
+			continue
+		}
+		if _, ok := subMap[hex.EncodeToString(pubkey)]; !ok {
+			continue
+		}
+		pubkeys[block.Proposer] = pubkey
+		pubkeyList = append(pubkeyList, pubkey)
+	}
+	if len(pubkeyList) == 0 {
+		return nil
+	}
+
+	expectedFeeRecipients, err := db.GetExpectedFeeRecipientsForValidators(pubkeyList)
+	if err != nil {
+		return err
+	}
+
+	for _, block := range blocks {
+		pubkey, ok := pubkeys[block.Proposer]
+		if !ok {
+			continue
+		}
+		pubkeyHex := hex.EncodeToString(pubkey)
+		for _, sub := range subMap[pubkeyHex] {
+			if sub.UserID == nil || sub.ID == nil {
+				return fmt.Errorf("error expected userId and subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+			}
+			if sub.LastEpoch != nil {
+				lastSentEpoch := *sub.LastEpoch
+				if lastSentEpoch >= epoch || epoch < sub.CreatedEpoch {
+					continue
+				}
+			}
+			expected, ok := expectedFeeRecipients[*sub.UserID][pubkeyHex]
+			if !ok || bytes.Equal(expected, block.FeeRecipient) {
+				continue
+			}
+			logger.Infof("creating %v notification for validator %v in epoch %v", types.ValidatorFeeRecipientMismatchEventName, block.Proposer, epoch)
+			n := &validatorFeeRecipientNotification{
+				SubscriptionID:       *sub.ID,
+				ValidatorIndex:       block.Proposer,
+				Epoch:                epoch,
+				Slot:                 block.Slot,
+				FeeRecipient:         block.FeeRecipient,
+				ExpectedFeeRecipient: expected,
+				EventFilter:          pubkeyHex,
+				UnsubscribeHash:      sub.UnsubscribeHash,
+			}
+			if _, exists := notificationsByUserID[*sub.UserID]; !exists {
+				notificationsByUserID[*sub.UserID] = map[types.EventName][]types.Notification{}
+			}
+			if _, exists := notificationsByUserID[*sub.UserID][n.GetEventName()]; !exists {
+				notificationsByUserID[*sub.UserID][n.GetEventName()] = []types.Notification{}
+			}
+			notificationsByUserID[*sub.UserID][n.GetEventName()] = append(notificationsByUserID[*sub.UserID][n.GetEventName()], n)
+			metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+		}
+	}
+
+	return nil
+}
+
+type relayRegistrationNotification struct {
+	SubscriptionID  uint64
+	ValidatorIndex  uint64
+	Epoch           uint64
+	Relays          []string
+	EventFilter     string
+	UnsubscribeHash sql.NullString
+}
+
+func (n *relayRegistrationNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *relayRegistrationNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *relayRegistrationNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *relayRegistrationNotification) GetValidatorIndex() uint64 {
+	return n.ValidatorIndex
+}
+
+func (n *relayRegistrationNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *relayRegistrationNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *relayRegistrationNotification) GetEventName() types.EventName {
+	return types.ValidatorRelayRegistrationMissingEventName
+}
+
+func (n *relayRegistrationNotification) GetInfo(includeUrl bool) string {
+	generalPart := fmt.Sprintf(`Validator %v is no longer registered with the relay(s) %v. Please check the mev-boost configuration of your validator.`, n.ValidatorIndex, strings.Join(n.Relays, ", "))
+	if includeUrl {
+		return generalPart + getUrlPart(n.ValidatorIndex)
+	}
+	return generalPart
+}
+
+func (n *relayRegistrationNotification) GetTitle() string {
+	return "Relay Registration Missing"
+}
+
+func (n *relayRegistrationNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *relayRegistrationNotification) GetInfoMarkdown() string {
+	return fmt.Sprintf(`Validator [%[1]v](https://%[3]v/validator/%[1]v) is no longer registered with the relay(s) %[2]v. Please check the mev-boost configuration of your validator.`, n.ValidatorIndex, strings.Join(n.Relays, ", "), utils.Config.Frontend.SiteDomain)
+}
+
+// collectRelayRegistrationMissingNotifications collects the notifications of watched validators whose registration at
+// a relay disappeared during the epoch. The registrations are checked by the relays exporter.
+func collectRelayRegistrationMissingNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, epoch uint64) error {
+	_, subMap, err := db.GetSubsForEventFilter(types.ValidatorRelayRegistrationMissingEventName)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for %v %w", types.ValidatorRelayRegistrationMissingEventName, err)
+	}
+	if len(subMap) == 0 {
+		return nil
+	}
+
+	unregistrations, err := db.GetRelayValidatorUnregistrations(utils.EpochToTime(epoch), utils.EpochToTime(epoch+1))
+	if err != nil {
+		return err
+	}
+
+	relaysByPubkey := make(map[string][]string)
+	pubkeys := make([][]byte, 0)
+	for _, unregistration := range unregistrations {
+		pubkeyHex := hex.EncodeToString(unregistration.Pubkey)
+		if _, ok := relaysByPubkey[pubkeyHex]; !ok {
+			pubkeys = append(pubkeys, unregistration.Pubkey)
+		}
+		relay := unregistration.RelayID
+		if unregistration.RelayName.Valid && unregistration.RelayName.String != "" {
+			relay = unregistration.RelayName.String
+		}
+		relaysByPubkey[pubkeyHex] = append(relaysByPubkey[pubkeyHex], relay)
+	}
+
+	for _, pubkey := range pubkeys {
+		pubkeyHex := hex.EncodeToString(pubkey)
+		subscribers, ok := subMap[pubkeyHex]
+		if !ok {
+			continue
+		}
+		validatorIndex, err := GetIndexForPubkey(pubkey)
+		if err != nil {
+			utils.LogError(err, "error retrieving index for validator", 0, map[string]interface{}{"pubkey": pubkeyHex})
+			continue
+		}
+		for _, sub := range subscribers {
+			if sub.UserID == nil || sub.ID == nil {
+				return fmt.Errorf("error expected userId and subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+			}
+			if sub.LastEpoch != nil {
+				lastSentEpoch := *sub.LastEpoch
+				if lastSentEpoch >= epoch || epoch < sub.CreatedEpoch {
+					continue
+				}
+			}
+			n := &relayRegistrationNotification{
+				SubscriptionID:  *sub.ID,
+				ValidatorIndex:  validatorIndex,
+				Epoch:           epoch,
+				Relays:          relaysByPubkey[pubkeyHex],
+				EventFilter:     pubkeyHex,
+				UnsubscribeHash: sub.UnsubscribeHash,
+			}
+			if _, exists := notificationsByUserID[*sub.UserID]; !exists {
+				notificationsByUserID[*sub.UserID] = map[types.EventName][]types.Notification{}
+			}
+			if _, exists := notificationsByUserID[*sub.UserID][n.GetEventName()]; !exists {
+				notificationsByUserID[*sub.UserID][n.GetEventName()] = []types.Notification{}
+			}
+			notificationsByUserID[*sub.UserID][n.GetEventName()] = append(notificationsByUserID[*sub.UserID][n.GetEventName()], n)
+			metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+		}
+	}
+
+	return nil
+}
+
+type validatorMilestoneNotification struct {
+	SubscriptionID  uint64
+	ValidatorIndex  uint64
+	Epoch           uint64
+	Milestone       string
+	Day             uint64
+	EventFilter     string
+	UnsubscribeHash sql.NullString
+}
+
+func (n *validatorMilestoneNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *validatorMilestoneNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *validatorMilestoneNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *validatorMilestoneNotification) GetValidatorIndex() uint64 {
+	return n.ValidatorIndex
+}
+
+func (n *validatorMilestoneNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *validatorMilestoneNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *validatorMilestoneNotification) GetEventName() types.EventName {
+	return types.ValidatorMilestoneEventName
+}
+
+func (n *validatorMilestoneNotification) GetInfo(includeUrl bool) string {
+	generalPart := fmt.Sprintf(`Congratulations, validator %v %v on %v.`, n.ValidatorIndex, types.ValidatorMilestones[n.Milestone].Description, utils.DayToTime(int64(n.Day)).Format("2006-01-02"))
+	if includeUrl {
+		return generalPart + fmt.Sprintf(" Share it: https://%s/validator/%v/milestone/%v", utils.Config.Frontend.SiteDomain, n.ValidatorIndex, n.Milestone)
+	}
+	return generalPart
+}
+
+func (n *validatorMilestoneNotification) GetTitle() string {
+	return "Milestone Reached: " + types.ValidatorMilestones[n.Milestone].Label
+}
+
+func (n *validatorMilestoneNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *validatorMilestoneNotification) GetInfoMarkdown() string {
+	return fmt.Sprintf(`Congratulations, validator [%[1]v](https://%[5]v/validator/%[1]v) %[2]v on %[3]v. [Share it](https://%[5]v/validator/%[1]v/milestone/%[4]v)`, n.ValidatorIndex, types.ValidatorMilestones[n.Milestone].Description, utils.DayToTime(int64(n.Day)).Format("2006-01-02"), n.Milestone, utils.Config.Frontend.SiteDomain)
+}
+
+// collectValidatorMilestoneNotifications collects the notifications of watched validators whose milestones were detected
+// by the statistics exporter since the last run and marks them as notified. Milestones that were detected long after
+// they were reached, e.g. while backfilling, do not trigger a notification.
+func collectValidatorMilestoneNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, epoch uint64) error {
+	milestones, err := db.GetUnnotifiedValidatorMilestones()
+	if err != nil {
+		return err
+	}
+	if len(milestones) == 0 {
+		return nil
+	}
+
+	_, subMap, err := db.GetSubsForEventFilter(types.ValidatorMilestoneEventName)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for %v %w", types.ValidatorMilestoneEventName, err)
+	}
+
+	for _, m := range milestones {
+		if m.DetectedTs.After(utils.DayToTime(int64(m.Day + 2))) {
+			continue
+		}
+		pubkey, err := GetPubkeyForIndex(m.ValidatorIndex)
+		if err != nil {
+			utils.LogError(err, "error retrieving pubkey for validator", 0, map[string]interface{}{"index": m.ValidatorIndex})
+			continue
+		}
+		pubkeyHex := hex.EncodeToString(pubkey)
+		subscribers, ok := subMap[pubkeyHex]
+		if !ok {
+			continue
+		}
+		for _, sub := range subscribers {
+			if sub.UserID == nil || sub.ID == nil {
+				return fmt.Errorf("error expected userId and subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+			}
+			if sub.LastEpoch != nil {
+				lastSentEpoch := *sub.LastEpoch
+				if lastSentEpoch >= epoch || epoch < sub.CreatedEpoch {
+					continue
+				}
+			}
+			n := &validatorMilestoneNotification{
+				SubscriptionID:  *sub.ID,
+				ValidatorIndex:  m.ValidatorIndex,
+				Epoch:           epoch,
+				Milestone:       m.Milestone,
+				Day:             m.Day,
+				EventFilter:     pubkeyHex,
+				UnsubscribeHash: sub.UnsubscribeHash,
+			}
+			if _, exists := notificationsByUserID[*sub.UserID]; !exists {
+				notificationsByUserID[*sub.UserID] = map[types.EventName][]types.Notification{}
+			}
+			if _, exists := notificationsByUserID[*sub.UserID][n.GetEventName()]; !exists {
+				notificationsByUserID[*sub.UserID][n.GetEventName()] = []types.Notification{}
+			}
+			notificationsByUserID[*sub.UserID][n.GetEventName()] = append(notificationsByUserID[*sub.UserID][n.GetEventName()], n)
+			metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+		}
+	}
+
+	return db.SetValidatorMilestonesNotified(milestones)
+}
+
+type validatorDepositWarningNotification struct {
+	SubscriptionID  uint64
+	Epoch           uint64
+	PublicKey       []byte
+	TxHash          []byte
+	Amount          uint64
+	Warning         string
+	EventFilter     string
+	UnsubscribeHash sql.NullString
+}
+
+func (n *validatorDepositWarningNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *validatorDepositWarningNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *validatorDepositWarningNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *validatorDepositWarningNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *validatorDepositWarningNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *validatorDepositWarningNotification) GetEventName() types.EventName {
+	return types.ValidatorDepositWarningEventName
+}
+
+func (n *validatorDepositWarningNotification) GetInfo(includeUrl bool) string {
+	generalPart := fmt.Sprintf(`The deposit of %v to validator %#x in transaction %#x needs your attention. %v`, utils.FormatClCurrencyString(n.Amount, utils.Config.Frontend.MainCurrency, 6, true, false, false), n.PublicKey, n.TxHash, utils.FormatEth1DepositWarning(n.Warning))
+	if includeUrl {
+		return generalPart + fmt.Sprintf(" https://%s/tx/%#x", utils.Config.Frontend.SiteDomain, n.TxHash)
+	}
+	return generalPart
+}
+
+func (n *validatorDepositWarningNotification) GetTitle() string {
+	return "Deposit Warning"
+}
+
+func (n *validatorDepositWarningNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *validatorDepositWarningNotification) GetInfoMarkdown() string {
+	return fmt.Sprintf(`The deposit of %[1]v to validator [%#[2]x](https://%[5]v/validator/%[2]x) in transaction [%#[3]x](https://%[5]v/tx/%#[3]x) needs your attention. %[4]v`, utils.FormatClCurrencyString(n.Amount, utils.Config.Frontend.MainCurrency, 6, true, false, false), n.PublicKey, n.TxHash, utils.FormatEth1DepositWarning(n.Warning), utils.Config.Frontend.SiteDomain)
+}
+
+// collectValidatorDepositWarningNotifications collects the notifications of watched validators for deposits that were
+// flagged by the eth1 deposits exporter during the epoch. Deposits that were flagged long after they were made, e.g.
+// while checking old deposits after an update, do not trigger a notification.
+func collectValidatorDepositWarningNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, epoch uint64) error {
+	_, subMap, err := db.GetSubsForEventFilter(types.ValidatorDepositWarningEventName)
+	if err != nil {
+		return fmt.Errorf("error getting subscriptions for %v %w", types.ValidatorDepositWarningEventName, err)
+	}
+	if len(subMap) == 0 {
+		return nil
+	}
+
+	warnings, err := db.GetEth1DepositWarningsDetectedBetween(utils.EpochToTime(epoch), utils.EpochToTime(epoch+1))
+	if err != nil {
+		return err
+	}
+
+	for _, w := range warnings {
+		if w.DetectedTs.Sub(w.BlockTs) > utils.Day {
+			continue
+		}
+		pubkeyHex := hex.EncodeToString(w.PublicKey)
+		subscribers, ok := subMap[pubkeyHex]
+		if !ok {
+			continue
+		}
+		for _, sub := range subscribers {
+			if sub.UserID == nil || sub.ID == nil {
+				return fmt.Errorf("error expected userId and subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+			}
+			if sub.LastEpoch != nil {
+				lastSentEpoch := *sub.LastEpoch
+				if lastSentEpoch >= epoch || epoch < sub.CreatedEpoch {
+					continue
+				}
+			}
+			n := &validatorDepositWarningNotification{
+				SubscriptionID:  *sub.ID,
+				Epoch:           epoch,
+				PublicKey:       w.PublicKey,
+				TxHash:          w.TxHash,
+				Amount:          w.Amount,
+				Warning:         w.Warning,
+				EventFilter:     pubkeyHex,
+				UnsubscribeHash: sub.UnsubscribeHash,
+			}
+			if _, exists := notificationsByUserID[*sub.UserID]; !exists {
+				notificationsByUserID[*sub.UserID] = map[types.EventName][]types.Notification{}
+			}
+			if _, exists := notificationsByUserID[*sub.UserID][n.GetEventName()]; !exists {
+				notificationsByUserID[*sub.UserID][n.GetEventName()] = []types.Notification{}
+			}
+			notificationsByUserID[*sub.UserID][n.GetEventName()] = append(notificationsByUserID[*sub.UserID][n.GetEventName()], n)
+			metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+		}
+	}
+
+	return nil
+}
+
+type ethAddressNotification struct {
+	SubscriptionID  uint64
+	Epoch           uint64
+	EventName       types.EventName
+	Address         []byte
+	Counterparty    []byte
+	TxHash          []byte
+	Amount          string
+	EventFilter     string
+	UnsubscribeHash sql.NullString
+}
+
+func (n *ethAddressNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *ethAddressNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *ethAddressNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *ethAddressNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *ethAddressNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *ethAddressNotification) GetEventName() types.EventName {
+	return n.EventName
+}
+
+func (n *ethAddressNotification) GetInfo(includeUrl bool) string {
+	var generalPart string
+	switch n.EventName {
+	case types.EthAddressIncomingTransactionEventName:
+		generalPart = fmt.Sprintf(`Address 0x%x received a transaction of %v from 0x%x.`, n.Address, n.Amount, n.Counterparty)
+	case types.EthAddressOutgoingTransactionEventName:
+		generalPart = fmt.Sprintf(`Address 0x%x sent a transaction of %v to 0x%x.`, n.Address, n.Amount, n.Counterparty)
+	case types.EthAddressTokenTransferEventName:
+		generalPart = fmt.Sprintf(`Address 0x%x transferred %v with 0x%x.`, n.Address, n.Amount, n.Counterparty)
+	case types.EthAddressContractInteractionEventName:
+		generalPart = fmt.Sprintf(`Address 0x%x interacted with contract 0x%x.`, n.Address, n.Counterparty)
+	}
+	if includeUrl {
+		return generalPart + fmt.Sprintf(` For more information visit: <a href='https://%[1]s/tx/0x%[2]x'>https://%[1]s/tx/0x%[2]x</a>.`, utils.Config.Frontend.SiteDomain, n.TxHash)
+	}
+	return generalPart
+}
+
+func (n *ethAddressNotification) GetTitle() string {
+	switch n.EventName {
+	case types.EthAddressIncomingTransactionEventName:
+		return "Incoming Transaction"
+	case types.EthAddressOutgoingTransactionEventName:
+		return "Outgoing Transaction"
+	case types.EthAddressTokenTransferEventName:
+		return "Token Transfer"
+	case types.EthAddressContractInteractionEventName:
+		return "Contract Interaction"
+	}
+	return "-"
+}
+
+func (n *ethAddressNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *ethAddressNotification) GetInfoMarkdown() string {
+	generalPart := n.GetInfo(false)
+	return generalPart + fmt.Sprintf(` [View transaction](https://%v/tx/0x%x)`, utils.Config.Frontend.SiteDomain, n.TxHash)
+}
+
+// collectEthAddressNotifications collects the notifications of watched execution layer addresses from the events the eth1 indexer recorded up to the epoch.
+// It also publishes the set of watched addresses to the eth1 indexer, token transfers are only notified if the transferred amount reaches the subscription threshold.
+func collectEthAddressNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, epoch uint64) error {
+	subMaps := make(map[types.EventName]map[string][]types.Subscription, len(types.EthAddressEvents))
+	watched := make(map[string][]byte)
+	for _, eventName := range types.EthAddressEvents {
+		_, subMap, err := db.GetSubsForEventFilter(eventName)
+		if err != nil {
+			return fmt.Errorf("error getting subscriptions for %v: %w", eventName, err)
+		}
+		subMaps[eventName] = subMap
+
+		for filter := range subMap {
+			address, err := hex.DecodeString(filter)
+			if err != nil || len(address) != 20 {
+				continue
+			}
+			watched[filter] = address
+		}
+	}
+
+	addresses := make([][]byte, 0, len(watched))
+	for _, address := range watched {
+		addresses = append(addresses, address)
+	}
+	err := db.SetEth1AddressWatchlist(addresses)
+	if err != nil {
+		return err
+	}
+
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	// events the eth1 indexer recorded after their epoch was notified are caught up on by continuing after the last
+	// notified block, the events of the current epoch are the starting point if no block has been notified yet
+	lastNotifiedBlock, err := db.GetLastNotifiedEth1AddressEventsBlock()
+	if err != nil {
+		return err
+	}
+	from := utils.EpochToTime(epoch).Add(-eth1AddressEventsRetention)
+	if lastNotifiedBlock == 0 {
+		from = utils.EpochToTime(epoch)
+	}
+	events, err := db.GetEth1AddressEvents(lastNotifiedBlock, from, utils.EpochToTime(epoch+1))
+	if err != nil {
+		return err
+	}
+
+	tokenMetadata := make(map[string]*types.ERC20Metadata)
+	for _, event := range events {
+		subscribers, ok := subMaps[event.EventName][hex.EncodeToString(event.Address)]
+		if !ok {
+			continue
+		}
+
+		amount := fmt.Sprintf("%v %v", utils.WeiToEther(event.Value.BigInt()).String(), utils.Config.Frontend.ElCurrency)
+		var tokenAmount decimal.Decimal
+		if event.EventName == types.EthAddressTokenTransferEventName {
+			metadata, ok := tokenMetadata[string(event.Token)]
+			if !ok {
+				metadata, err = db.BigtableClient.GetERC20MetadataForAddress(event.Token)
+				if err != nil {
+					return fmt.Errorf("error getting erc20 metadata for token %x: %w", event.Token, err)
+				}
+				tokenMetadata[string(event.Token)] = metadata
+			}
+			tokenAmount = utils.FormatErc20Decimals(event.Value.BigInt().Bytes(), metadata)
+			amount = fmt.Sprintf("%v %v", tokenAmount.String(), metadata.Symbol)
+		}
+
+		for _, sub := range subscribers {
+			if sub.UserID == nil || sub.ID == nil {
+				return fmt.Errorf("error expected userId and subId to be defined but got user: %v, sub: %v", sub.UserID, sub.ID)
+			}
+			if sub.LastEpoch != nil {
+				lastSentEpoch := *sub.LastEpoch
+				if lastSentEpoch >= epoch || epoch < sub.CreatedEpoch {
+					continue
+				}
+			}
+			if event.EventName == types.EthAddressTokenTransferEventName && tokenAmount.LessThan(decimal.NewFromFloat(sub.EventThreshold)) {
+				continue
+			}
+
+			n := &ethAddressNotification{
+				SubscriptionID:  *sub.ID,
+				Epoch:           epoch,
+				EventName:       event.EventName,
+				Address:         event.Address,
+				Counterparty:    event.Counterparty,
+				TxHash:          event.TxHash,
+				Amount:          amount,
+				EventFilter:     hex.EncodeToString(event.Address),
+				UnsubscribeHash: sub.UnsubscribeHash,
+			}
+			if _, exists := notificationsByUserID[*sub.UserID]; !exists {
+				notificationsByUserID[*sub.UserID] = map[types.EventName][]types.Notification{}
+			}
+			if _, exists := notificationsByUserID[*sub.UserID][n.GetEventName()]; !exists {
+				notificationsByUserID[*sub.UserID][n.GetEventName()] = []types.Notification{}
+			}
+			notificationsByUserID[*sub.UserID][n.GetEventName()] = append(notificationsByUserID[*sub.UserID][n.GetEventName()], n)
+			metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+		}
+	}
+
+	if len(events) > 0 {
+		err = db.SetLastNotifiedEth1AddressEventsBlock(events[len(events)-1].BlockNumber)
+		if err != nil {
+			return err
+		}
+	}
+
+	// events are only needed until their epoch has been notified
+	return db.DeleteEth1AddressEventsBefore(utils.EpochToTime(epoch).Add(-eth1AddressEventsRetention))
+}
+
+type ethClientNotification struct {
+	SubscriptionID  uint64
+	UserID          uint64
+	Epoch           uint64
+	EthClient       string
+	EventFilter     string
+	UnsubscribeHash sql.NullString
+}
+
+func (n *ethClientNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *ethClientNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *ethClientNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *ethClientNotification) GetSubscriptionID() uint64 {
 	return n.SubscriptionID
 }
 
@@ -2212,19 +3725,22 @@ type MachineEvents struct {
 }
 
 func collectMonitoringMachineOffline(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, epoch uint64) error {
-	nowTs := time.Now().Unix()
 	return collectMonitoringMachine(notificationsByUserID, types.MonitoringMachineOfflineEventName, 120,
 		// notify condition
 		func(_ *MachineEvents, machineData *types.MachineMetricSystemUser) bool {
-			if machineData.CurrentDataInsertTs < nowTs-10*60 && machineData.CurrentDataInsertTs > nowTs-90*60 {
-				return true
-			}
-			return false
+			return isMachineOffline(machineData)
 		},
 		epoch,
 	)
 }
 
+// isMachineOffline returns whether the machine stopped reporting metrics recently, machines that stopped reporting
+// long ago are considered decommissioned
+func isMachineOffline(machineData *types.MachineMetricSystemUser) bool {
+	nowTs := time.Now().Unix()
+	return machineData.CurrentDataInsertTs < nowTs-10*60 && machineData.CurrentDataInsertTs > nowTs-90*60
+}
+
 func isMachineDataRecent(machineData *types.MachineMetricSystemUser) bool {
 	nowTs := time.Now().Unix()
 	return machineData.CurrentDataInsertTs >= nowTs-60*60
@@ -2733,6 +4249,119 @@ func collectNetworkNotifications(notificationsByUserID map[uint64]map[types.Even
 	return nil
 }
 
+type networkIncidentNotification struct {
+	SubscriptionID  uint64
+	UserID          uint64
+	Epoch           uint64
+	EventFilter     string
+	Incidents       []*types.ApiNetworkIncidentResponse
+	UnsubscribeHash sql.NullString
+}
+
+func (n *networkIncidentNotification) GetLatestState() string {
+	return ""
+}
+
+func (n *networkIncidentNotification) GetUnsubscribeHash() string {
+	if n.UnsubscribeHash.Valid {
+		return n.UnsubscribeHash.String
+	}
+	return ""
+}
+
+func (n *networkIncidentNotification) GetEmailAttachment() *types.EmailAttachment {
+	return nil
+}
+
+func (n *networkIncidentNotification) GetSubscriptionID() uint64 {
+	return n.SubscriptionID
+}
+
+func (n *networkIncidentNotification) GetEpoch() uint64 {
+	return n.Epoch
+}
+
+func (n *networkIncidentNotification) GetEventName() types.EventName {
+	return types.NetworkIncidentEventName
+}
+
+func (n *networkIncidentNotification) describeIncidents() string {
+	descriptions := make([]string, 0, len(n.Incidents))
+	for _, incident := range n.Incidents {
+		descriptions = append(descriptions, describeNetworkIncident(incident))
+	}
+	return strings.Join(descriptions, ". ")
+}
+
+func (n *networkIncidentNotification) GetInfo(includeUrl bool) string {
+	generalPart := fmt.Sprintf(`An anomaly of the network was detected. %v.`, n.describeIncidents())
+	if includeUrl {
+		return generalPart + fmt.Sprintf(` Learn more at https://%v/charts/participation_rate`, utils.Config.Frontend.SiteDomain)
+	}
+	return generalPart
+}
+
+func (n *networkIncidentNotification) GetTitle() string {
+	return "Network Incident"
+}
+
+func (n *networkIncidentNotification) GetEventFilter() string {
+	return n.EventFilter
+}
+
+func (n *networkIncidentNotification) GetInfoMarkdown() string {
+	return fmt.Sprintf(`An anomaly of the network was detected ([view chart](https://%v/charts/participation_rate)). %v.`, utils.Config.Frontend.SiteDomain, n.describeIncidents())
+}
+
+// collectNetworkIncidentNotifications notifies the subscribers of network incidents about the incidents detected by
+// the network anomaly detector within the last hour
+func collectNetworkIncidentNotifications(notificationsByUserID map[uint64]map[types.EventName][]types.Notification, epoch uint64) error {
+	incidents, err := db.GetNetworkIncidentsDetectedSince(time.Now().Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if len(incidents) == 0 {
+		return nil
+	}
+
+	var dbResult []struct {
+		SubscriptionID  uint64         `db:"id"`
+		UserID          uint64         `db:"user_id"`
+		EventFilter     string         `db:"event_filter"`
+		UnsubscribeHash sql.NullString `db:"unsubscribe_hash"`
+	}
+	err = db.FrontendWriterDB.Select(&dbResult, `
+		SELECT us.id, us.user_id, us.event_filter, ENCODE(us.unsubscribe_hash, 'hex') AS unsubscribe_hash
+		FROM users_subscriptions AS us
+		WHERE us.event_name=$1 AND (us.last_sent_ts <= NOW() - INTERVAL '1 hour' OR us.last_sent_ts IS NULL);
+		`,
+		utils.GetNetwork()+":"+string(types.NetworkIncidentEventName))
+	if err != nil {
+		return err
+	}
+
+	for _, r := range dbResult {
+		n := &networkIncidentNotification{
+			SubscriptionID:  r.SubscriptionID,
+			UserID:          r.UserID,
+			Epoch:           epoch,
+			EventFilter:     r.EventFilter,
+			Incidents:       incidents,
+			UnsubscribeHash: r.UnsubscribeHash,
+		}
+		if _, exists := notificationsByUserID[r.UserID]; !exists {
+			notificationsByUserID[r.UserID] = map[types.EventName][]types.Notification{}
+		}
+		if _, exists := notificationsByUserID[r.UserID][n.GetEventName()]; !exists {
+			notificationsByUserID[r.UserID][n.GetEventName()] = []types.Notification{}
+		}
+		notificationsByUserID[r.UserID][n.GetEventName()] = append(notificationsByUserID[r.UserID][n.GetEventName()], n)
+		metrics.NotificationsCollected.WithLabelValues(string(n.GetEventName())).Inc()
+	}
+
+	return nil
+}
+
 type rocketpoolNotification struct {
 	SubscriptionID  uint64
 	UserID          uint64
@@ -3017,7 +4646,7 @@ func collectRocketpoolRPLCollateralNotifications(notificationsByUserID map[uint6
 
 		if sub.LastEpoch != nil {
 			lastSentEpoch := *sub.LastEpoch
-			if lastSentEpoch >= epoch-225 || epoch < sub.CreatedEpoch {
+			if lastSentEpoch >= epoch-utils.EpochsPerDay() || epoch < sub.CreatedEpoch {
 				continue
 			}
 		}
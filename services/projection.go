@@ -0,0 +1,92 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// ProjectValidatorSet simulates the validator set day by day under a scenario of validators requesting to enter and to
+// exit per day. Both queues are processed at the churn limits of the projected validator set. The consensus apr scales
+// with the inverse square root of the staked amount (the issuance depends on the square root of the total stake), the
+// execution rewards are assumed to stay constant and are shared by all validators. The starting point are the current
+// validator set, the current queues and the average aprs of the last 31 days.
+func ProjectValidatorSet(dailyInflow, dailyOutflow float64, days uint64) (*types.ApiValidatorSetProjectionResponse, error) {
+	stats := GetLatestStats()
+	if stats.ActiveValidatorCount == nil || *stats.ActiveValidatorCount == 0 {
+		return nil, fmt.Errorf("active validator count is not available yet")
+	}
+
+	network, err := db.GetStakingCalculatorNetworkStats(31)
+	if err != nil {
+		return nil, err
+	}
+
+	index := LatestIndexPageData()
+	epoch := LatestEpoch()
+	epochsPerDay := float64(utils.EpochsPerDay())
+
+	initialValidators := float64(*stats.ActiveValidatorCount)
+	avgEffectiveBalance := float64(network.TotalStaked) / initialValidators
+
+	res := &types.ApiValidatorSetProjectionResponse{
+		DailyInflow:         dailyInflow,
+		DailyOutflow:        dailyOutflow,
+		Days:                days,
+		AvgEffectiveBalance: avgEffectiveBalance,
+		Projection:          make([]*types.ApiValidatorSetProjectionDay, 0, days+1),
+	}
+
+	validators := initialValidators
+	entryQueue := float64(index.EnteringValidators)
+	exitQueue := float64(index.ExitingValidators)
+	start := time.Now()
+	for day := uint64(0); day <= days; day++ {
+		dayEpoch := epoch + day*utils.EpochsPerDay()
+		exitChurn, err := getValidatorChurnLimit(uint64(validators))
+		if err != nil {
+			return nil, err
+		}
+		activationChurn, err := getValidatorActivationChurnLimit(uint64(validators), dayEpoch)
+		if err != nil {
+			return nil, err
+		}
+
+		// the first day is the current state, the queues of the following days are processed at the churn of the day
+		if day > 0 {
+			entryQueue += dailyInflow
+			exitQueue += dailyOutflow
+			activated := math.Min(entryQueue, float64(activationChurn)*epochsPerDay)
+			exited := math.Min(exitQueue, float64(exitChurn)*epochsPerDay)
+			entryQueue -= activated
+			exitQueue -= exited
+			validators = math.Max(validators+activated-exited, 1)
+		}
+
+		consensusApr := network.ConsensusApr * math.Sqrt(initialValidators/validators)
+		executionApr := network.ExecutionApr * initialValidators / validators
+		stakedAmount := validators * avgEffectiveBalance
+		res.Projection = append(res.Projection, &types.ApiValidatorSetProjectionDay{
+			Day:                  day,
+			Ts:                   start.Add(utils.Day * time.Duration(day)).Unix(),
+			ActiveValidators:     uint64(validators),
+			StakedAmount:         stakedAmount,
+			EntryQueue:           uint64(entryQueue),
+			ExitQueue:            uint64(exitQueue),
+			ActivationChurnLimit: activationChurn,
+			ExitChurnLimit:       exitChurn,
+			EntryWaitDays:        entryQueue / float64(activationChurn) / epochsPerDay,
+			ExitWaitDays:         exitQueue / float64(exitChurn) / epochsPerDay,
+			ConsensusApr:         consensusApr,
+			ExecutionApr:         executionApr,
+			Apr:                  consensusApr + executionApr,
+			AnnualIssuance:       consensusApr * stakedAmount,
+		})
+	}
+
+	return res, nil
+}
@@ -33,7 +33,7 @@ func GetPubkeyForIndex(index uint64) ([]byte, error) {
 
 	pubkey, err := pubkeyCacheDb.Get(key, nil)
 	if err == leveldb.ErrNotFound {
-		err = db.WriterDb.Get(&pubkey, "SELECT pubkey FROM validators WHERE validatorindex = $1", index)
+		err = db.ReaderDb.Get(&pubkey, "SELECT pubkey FROM validators WHERE validatorindex = $1", index)
 
 		if err != nil {
 			return nil, err
@@ -61,7 +61,7 @@ func GetIndexForPubkey(pubkey []byte) (uint64, error) {
 
 	indexString, err := pubkeyCacheDb.Get(key, nil)
 	if err == leveldb.ErrNotFound {
-		err = db.WriterDb.Get(&index, "SELECT validatorindex FROM validators WHERE pubkey = $1", pubkey)
+		err = db.ReaderDb.Get(&index, "SELECT validatorindex FROM validators WHERE pubkey = $1", pubkey)
 
 		if err != nil {
 			return 0, err
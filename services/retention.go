@@ -0,0 +1,35 @@
+package services
+
+import (
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+)
+
+// StartRetentionService prunes the epoch granular tables once per configured interval
+func StartRetentionService() {
+	interval := utils.Config.Retention.Interval
+	if interval == 0 {
+		interval = time.Hour
+	}
+	batchSize := utils.Config.Retention.BatchSize
+	if batchSize == 0 {
+		batchSize = 10000
+	}
+	if utils.Config.Retention.DryRun {
+		logger.Infof("retention service running in dry run mode, no data will be pruned")
+	}
+
+	for {
+		start := time.Now()
+		err := db.RunRetention(utils.Config.Retention.DryRun, batchSize)
+		if err != nil {
+			utils.LogError(err, "error running retention", 0)
+		} else {
+			logger.Infof("retention run completed, took %v", time.Since(start))
+			ReportStatus("retention", "Running", nil)
+		}
+		time.Sleep(interval)
+	}
+}
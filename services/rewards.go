@@ -34,7 +34,7 @@ func GetValidatorHist(validatorArr []uint64, currency string, start uint64, end
 	if start == end { // no date range was provided, use the current day as ending boundary
 		end = uint64(time.Now().Unix())
 	}
-	err = db.WriterDb.Select(&pricesDb,
+	err = db.ReaderDb.Select(&pricesDb,
 		`select ts, eur, usd, gbp, cad, jpy, cny, rub, aud from price where ts >= TO_TIMESTAMP($1) and ts <= TO_TIMESTAMP($2) order by ts desc`, start-oneDay, end+oneDay)
 	if err != nil {
 		logger.Errorf("error getting prices: %v", err)
@@ -303,7 +303,7 @@ func GetPdfReport(validatorArr []uint64, currency string, start uint64, end uint
 func getValidatorDetails(validators []uint64) [][]string {
 	validatorFilter := pq.Array(validators)
 	var data []types.ValidatorPageData
-	err := db.WriterDb.Select(&data,
+	err := db.ReaderDb.Select(&data,
 		`SELECT validatorindex, balanceactivation
 		 FROM validators 
 		 WHERE validatorindex = ANY($1)
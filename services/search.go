@@ -0,0 +1,452 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// searchResultsPerTypeLimit is the maximum amount of results a single entity type contributes to a search
+const searchResultsPerTypeLimit = 10
+
+// searchTextMinLength is the minimum length of a query before validator names, ens names and graffiti are searched
+const searchTextMinLength = 3
+
+var searchHexRE = regexp.MustCompile(`^[0-9a-f]{1,96}$`)
+var searchPubkeyPrefixRE = regexp.MustCompile(`^[0-9a-f]{5,96}$`)
+
+// searchResultTypeOrder is used to order results of the same score, types listed first are preferred
+var searchResultTypeOrder = map[types.SearchResultType]int{
+	types.SearchResultTypeValidator:   0,
+	types.SearchResultTypeBlock:       1,
+	types.SearchResultTypeSlot:        2,
+	types.SearchResultTypeEpoch:       3,
+	types.SearchResultTypeTransaction: 4,
+	types.SearchResultTypeAddress:     5,
+	types.SearchResultTypeEns:         6,
+	types.SearchResultTypeGraffiti:    7,
+}
+
+// Search looks up the query across validators (index, pubkey and name), blocks, slots, epochs, transactions,
+// addresses, ens names and graffiti and returns at most limit results ordered by relevance
+func Search(query string, limit int) ([]*types.SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if len(query) == 0 || limit <= 0 {
+		return []*types.SearchResult{}, nil
+	}
+
+	lowerQuery := strings.ToLower(query)
+	hexQuery := strings.TrimPrefix(lowerQuery, "0x")
+	number, numberErr := strconv.ParseUint(query, 10, 64)
+	isNumber := numberErr == nil
+	isHex := searchHexRE.MatchString(hexQuery)
+
+	results := make([]*types.SearchResult, 0)
+	resultsMux := &sync.Mutex{}
+	addResults := func(res []*types.SearchResult) {
+		resultsMux.Lock()
+		results = append(results, res...)
+		resultsMux.Unlock()
+	}
+
+	g := new(errgroup.Group)
+	if isNumber {
+		g.Go(func() error {
+			res, err := searchValidatorByIndex(number)
+			addResults(res)
+			return err
+		})
+		g.Go(func() error {
+			res, err := searchBlockByNumber(number)
+			addResults(res)
+			return err
+		})
+		g.Go(func() error {
+			res, err := searchSlotByNumber(number)
+			addResults(res)
+			return err
+		})
+		g.Go(func() error {
+			res, err := searchEpochByNumber(number)
+			addResults(res)
+			return err
+		})
+	}
+	if isHex && len(hexQuery) == 64 {
+		g.Go(func() error {
+			res, err := searchTransactionByHash(hexQuery)
+			addResults(res)
+			return err
+		})
+		g.Go(func() error {
+			res, err := searchSlotByRoot(hexQuery)
+			addResults(res)
+			return err
+		})
+	}
+	if searchPubkeyPrefixRE.MatchString(hexQuery) {
+		g.Go(func() error {
+			res, err := searchValidatorsByPubkeyPrefix(hexQuery)
+			addResults(res)
+			return err
+		})
+	}
+	if isHex && len(hexQuery) <= 40 && (!isNumber || strings.HasPrefix(lowerQuery, "0x")) {
+		g.Go(func() error {
+			res, err := searchAddressesByPrefix(hexQuery)
+			addResults(res)
+			return err
+		})
+	}
+	if len(query) >= searchTextMinLength {
+		g.Go(func() error {
+			res, err := searchValidatorsByName(lowerQuery)
+			addResults(res)
+			return err
+		})
+		g.Go(func() error {
+			res, err := searchEnsNames(lowerQuery)
+			addResults(res)
+			return err
+		})
+		g.Go(func() error {
+			res, err := searchGraffiti(lowerQuery)
+			addResults(res)
+			return err
+		})
+	}
+
+	err := g.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if results[i].Type != results[j].Type {
+			return searchResultTypeOrder[results[i].Type] < searchResultTypeOrder[results[j].Type]
+		}
+		return results[i].Label < results[j].Label
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// searchMatchScore ranks how well value matches the (lower case) query: exact matches score 1,
+// prefix matches score between 0.5 and 0.9 and substring matches between 0.1 and 0.4, shorter values scoring higher
+func searchMatchScore(query, value string) float64 {
+	value = strings.ToLower(value)
+	if len(value) == 0 {
+		return 0
+	}
+	coverage := float64(len(query)) / float64(len(value))
+	if coverage > 1 {
+		coverage = 1
+	}
+	switch {
+	case value == query:
+		return 1
+	case strings.HasPrefix(value, query):
+		return 0.5 + 0.4*coverage
+	case strings.Contains(value, query):
+		return 0.1 + 0.3*coverage
+	}
+	return 0
+}
+
+func searchValidatorByIndex(index uint64) ([]*types.SearchResult, error) {
+	var pubkey []byte
+	err := db.ReaderDb.Get(&pubkey, `SELECT pubkey FROM validators WHERE validatorindex = $1`, index)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error searching validator by index %v: %w", index, err)
+	}
+	return []*types.SearchResult{{
+		Type:  types.SearchResultTypeValidator,
+		Value: fmt.Sprintf("%d", index),
+		Label: fmt.Sprintf("Validator %d (%#x)", index, pubkey),
+		Url:   fmt.Sprintf("/validator/%d", index),
+		Score: 1,
+	}}, nil
+}
+
+func searchValidatorsByPubkeyPrefix(prefix string) ([]*types.SearchResult, error) {
+	validators := []struct {
+		Index  uint64 `db:"validatorindex"`
+		Pubkey string `db:"pubkeyhex"`
+	}{}
+	err := db.ReaderDb.Select(&validators, `
+		SELECT validatorindex, pubkeyhex
+		FROM validators
+		WHERE pubkeyhex LIKE ($1 || '%')
+		ORDER BY validatorindex
+		LIMIT $2`, prefix, searchResultsPerTypeLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error searching validators by pubkey prefix %v: %w", prefix, err)
+	}
+
+	results := make([]*types.SearchResult, 0, len(validators))
+	for _, v := range validators {
+		results = append(results, &types.SearchResult{
+			Type:  types.SearchResultTypeValidator,
+			Value: fmt.Sprintf("%d", v.Index),
+			Label: fmt.Sprintf("Validator %d (0x%v)", v.Index, v.Pubkey),
+			Url:   fmt.Sprintf("/validator/%d", v.Index),
+			Score: searchMatchScore(prefix, v.Pubkey),
+		})
+	}
+	return results, nil
+}
+
+func searchValidatorsByName(query string) ([]*types.SearchResult, error) {
+	validators := []struct {
+		Index uint64 `db:"validatorindex"`
+		Name  string `db:"name"`
+	}{}
+	// the substring match is backed by the trigram index on the lower case validator names
+	err := db.ReaderDb.Select(&validators, `
+		SELECT validators.validatorindex, validator_names.name
+		FROM validator_names
+		INNER JOIN validators ON validators.pubkey = validator_names.publickey
+		WHERE LOWER(validator_names.name) LIKE ('%' || $1 || '%')
+		ORDER BY LOWER(validator_names.name) LIKE ($1 || '%') DESC, LENGTH(validator_names.name), validators.validatorindex
+		LIMIT $2`, query, searchResultsPerTypeLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error searching validators by name %v: %w", query, err)
+	}
+
+	results := make([]*types.SearchResult, 0, len(validators))
+	for _, v := range validators {
+		results = append(results, &types.SearchResult{
+			Type:  types.SearchResultTypeValidator,
+			Value: fmt.Sprintf("%d", v.Index),
+			Label: fmt.Sprintf("Validator %d (%v)", v.Index, v.Name),
+			Url:   fmt.Sprintf("/validator/%d", v.Index),
+			Score: searchMatchScore(query, v.Name),
+		})
+	}
+	return results, nil
+}
+
+func searchBlockByNumber(number uint64) ([]*types.SearchResult, error) {
+	block, err := db.BigtableClient.GetBlockFromBlocksTable(number)
+	if err == db.ErrBlockNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error searching block %v: %w", number, err)
+	}
+	return []*types.SearchResult{{
+		Type:  types.SearchResultTypeBlock,
+		Value: fmt.Sprintf("%d", block.Number),
+		Label: fmt.Sprintf("Block %d (%#x)", block.Number, block.Hash),
+		Url:   fmt.Sprintf("/block/%d", block.Number),
+		Score: 1,
+	}}, nil
+}
+
+func searchSlotByNumber(slot uint64) ([]*types.SearchResult, error) {
+	slots := []struct {
+		Slot      uint64 `db:"slot"`
+		Blockroot []byte `db:"blockroot"`
+	}{}
+	err := db.ReaderDb.Select(&slots, `SELECT slot, blockroot FROM blocks WHERE slot = $1 ORDER BY status = '1' DESC LIMIT 1`, slot)
+	if err != nil {
+		return nil, fmt.Errorf("error searching slot %v: %w", slot, err)
+	}
+	results := make([]*types.SearchResult, 0, len(slots))
+	for _, s := range slots {
+		results = append(results, &types.SearchResult{
+			Type:  types.SearchResultTypeSlot,
+			Value: fmt.Sprintf("%d", s.Slot),
+			Label: fmt.Sprintf("Slot %d (%#x)", s.Slot, s.Blockroot),
+			Url:   fmt.Sprintf("/slot/%d", s.Slot),
+			Score: 1,
+		})
+	}
+	return results, nil
+}
+
+func searchSlotByRoot(root string) ([]*types.SearchResult, error) {
+	rootBytes, err := hex.DecodeString(root)
+	if err != nil {
+		return nil, nil
+	}
+	slots := []struct {
+		Slot      uint64 `db:"slot"`
+		Blockroot []byte `db:"blockroot"`
+	}{}
+	err = db.ReaderDb.Select(&slots, `
+		SELECT slot, blockroot
+		FROM blocks
+		WHERE blockroot = $1 OR stateroot = $1
+		ORDER BY slot
+		LIMIT $2`, rootBytes, searchResultsPerTypeLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error searching slot by root %v: %w", root, err)
+	}
+	results := make([]*types.SearchResult, 0, len(slots))
+	for _, s := range slots {
+		results = append(results, &types.SearchResult{
+			Type:  types.SearchResultTypeSlot,
+			Value: fmt.Sprintf("%x", s.Blockroot),
+			Label: fmt.Sprintf("Slot %d (%#x)", s.Slot, s.Blockroot),
+			Url:   fmt.Sprintf("/slot/%x", s.Blockroot),
+			Score: 1,
+		})
+	}
+	return results, nil
+}
+
+func searchEpochByNumber(epoch uint64) ([]*types.SearchResult, error) {
+	var epochs []uint64
+	err := db.ReaderDb.Select(&epochs, `SELECT epoch FROM epochs WHERE epoch = $1`, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("error searching epoch %v: %w", epoch, err)
+	}
+	results := make([]*types.SearchResult, 0, len(epochs))
+	for _, e := range epochs {
+		results = append(results, &types.SearchResult{
+			Type:  types.SearchResultTypeEpoch,
+			Value: fmt.Sprintf("%d", e),
+			Label: fmt.Sprintf("Epoch %d", e),
+			Url:   fmt.Sprintf("/epoch/%d", e),
+			Score: 1,
+		})
+	}
+	return results, nil
+}
+
+func searchTransactionByHash(hash string) ([]*types.SearchResult, error) {
+	hashBytes, err := hex.DecodeString(hash)
+	if err != nil {
+		return nil, nil
+	}
+	tx, err := db.BigtableClient.GetIndexedEth1Transaction(hashBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error searching transaction %v: %w", hash, err)
+	}
+	if tx == nil {
+		return nil, nil
+	}
+	return []*types.SearchResult{{
+		Type:  types.SearchResultTypeTransaction,
+		Value: fmt.Sprintf("%#x", tx.Hash),
+		Label: fmt.Sprintf("Transaction %#x", tx.Hash),
+		Url:   fmt.Sprintf("/tx/%#x", tx.Hash),
+		Score: 1,
+	}}, nil
+}
+
+func searchAddressesByPrefix(prefix string) ([]*types.SearchResult, error) {
+	results := make([]*types.SearchResult, 0)
+	if utils.IsValidEth1Address(prefix) {
+		// full addresses are always returned, even when the indexer has no metadata for them
+		results = append(results, &types.SearchResult{
+			Type:  types.SearchResultTypeAddress,
+			Value: "0x" + prefix,
+			Label: "0x" + prefix,
+			Url:   "/address/0x" + prefix,
+			Score: 1,
+		})
+		return results, nil
+	}
+
+	if len(prefix)%2 != 0 { // pad with 0 if uneven
+		prefix = prefix + "0"
+	}
+	prefixBytes, err := hex.DecodeString(prefix)
+	if err != nil {
+		return nil, nil
+	}
+	addresses, err := db.BigtableClient.SearchForAddress(prefixBytes, searchResultsPerTypeLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error searching addresses by prefix %v: %w", prefix, err)
+	}
+	for _, a := range addresses {
+		address := strings.ToLower(a.Address)
+		if !strings.HasPrefix(address, "0x") {
+			address = "0x" + address
+		}
+		label := address
+		if len(a.Name) > 0 {
+			label = fmt.Sprintf("%v (%v)", a.Name, address)
+		}
+		results = append(results, &types.SearchResult{
+			Type:  types.SearchResultTypeAddress,
+			Value: address,
+			Label: label,
+			Url:   "/address/" + address,
+			Score: searchMatchScore(prefix, strings.TrimPrefix(address, "0x")),
+		})
+	}
+	return results, nil
+}
+
+func searchEnsNames(query string) ([]*types.SearchResult, error) {
+	names, err := db.GetEnsNamesByPrefix(query, searchResultsPerTypeLimit)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*types.SearchResult, 0, len(names))
+	for _, n := range names {
+		results = append(results, &types.SearchResult{
+			Type:  types.SearchResultTypeEns,
+			Value: n.Domain,
+			Label: fmt.Sprintf("%v (%v)", n.Domain, n.Address),
+			Url:   "/ens/" + n.Domain,
+			Score: searchMatchScore(query, n.Domain),
+		})
+	}
+	return results, nil
+}
+
+func searchGraffiti(query string) ([]*types.SearchResult, error) {
+	graffiti := []struct {
+		Graffiti string `db:"graffiti"`
+		Count    uint64 `db:"count"`
+	}{}
+	err := db.ReaderDb.Select(&graffiti, `
+		SELECT graffiti, SUM(count) AS count
+		FROM graffiti_stats
+		WHERE graffiti_text ILIKE ('%' || $1 || '%')
+		GROUP BY graffiti
+		ORDER BY count DESC
+		LIMIT $2`, query, searchResultsPerTypeLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error searching graffiti %v: %w", query, err)
+	}
+	results := make([]*types.SearchResult, 0, len(graffiti))
+	for _, g := range graffiti {
+		// the formatted graffiti is html-escaped, the url needs the raw text
+		text := utils.FormatGraffitiString(g.Graffiti)
+		results = append(results, &types.SearchResult{
+			Type:  types.SearchResultTypeGraffiti,
+			Value: text,
+			Label: fmt.Sprintf("%v (%d blocks)", text, g.Count),
+			Url:   "/slots?q=" + url.QueryEscape(html.UnescapeString(text)),
+			Score: searchMatchScore(query, text),
+		})
+	}
+	return results, nil
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"database/sql"
 	"encoding/hex"
 	"encoding/json"
@@ -28,10 +29,26 @@ import (
 	"github.com/sirupsen/logrus"
 
 	geth_types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
 	geth_rpc "github.com/ethereum/go-ethereum/rpc"
 )
 
-var logger = logrus.New().WithField("module", "services")
+var logger = utils.NewLogger("services")
+
+const (
+	// relaysMarketShareDays is the amount of days shown in the market share charts of the relays page
+	relaysMarketShareDays = 90
+	// relaysMarketShareTopBuilders is the amount of builders that get their own series in the builder market share chart
+	relaysMarketShareTopBuilders = 10
+	// mempoolSnapshotInterval is the interval in which mempool stats are persisted
+	mempoolSnapshotInterval = time.Minute
+	// mempoolSnapshotRetention is the amount of time mempool snapshots are kept
+	mempoolSnapshotRetention = time.Hour * 24 * 30
+	// mempoolTopSpendersLimit is the amount of senders with the highest pending fees kept per snapshot
+	mempoolTopSpendersLimit = 10
+	// gasNowFeeHistoryBlocks is the amount of recent blocks used for the base fee trend and priority fee estimation
+	gasNowFeeHistoryBlocks = 20
+)
 
 // Init will initialize the services
 func Init() {
@@ -63,6 +80,12 @@ func Init() {
 	ready.Add(1)
 	go relaysUpdater(ready)
 
+	ready.Add(1)
+	go networkAnomalyUpdater(ready)
+
+	ready.Add(1)
+	go chainForksUpdater(ready)
+
 	ready.Add(1)
 	go chartsPageDataUpdater(ready)
 
@@ -312,9 +335,105 @@ func getRelaysPageData() (*types.RelaysResp, error) {
 		return nil, err
 	}
 
+	feeRecipientNames := make(map[string]string)
+	for _, block := range append(relaysData.RecentBlocks, relaysData.TopBlocks...) {
+		feeRecipientNames[string(block.ProposerFeeRecipient)] = ""
+	}
+	err = db.GetEnsNamesForAddress(feeRecipientNames)
+	if err != nil {
+		logger.Errorf("failed to resolve fee recipient ens names for relays page %v", err)
+		return nil, err
+	}
+	for _, block := range append(relaysData.RecentBlocks, relaysData.TopBlocks...) {
+		block.ProposerFeeRecipientName = feeRecipientNames[string(block.ProposerFeeRecipient)]
+	}
+
+	var fromDay uint64
+	latestDay := utils.DayOfSlot(latest)
+	if latestDay > relaysMarketShareDays {
+		fromDay = latestDay - relaysMarketShareDays
+	}
+	history, err := db.GetRelaysMarketShareHistory(fromDay)
+	if err != nil {
+		logger.Errorf("failed to get market share history for relays page %v", err)
+		return nil, err
+	}
+	relaysData.RelayMarketShareSeries, relaysData.BuilderMarketShareSeries, relaysData.CensoringShareSeries = getRelaysMarketShareSeries(history)
+
 	return &relaysData, nil
 }
 
+// getRelaysMarketShareSeries converts the daily relay statistics into chart series, builders outside of the top builders are grouped together
+func getRelaysMarketShareSeries(history *types.RelaysMarketShareHistory) (relaySeries, builderSeries, censoringSeries []*types.GenericChartDataSeries) {
+	relaySeries = []*types.GenericChartDataSeries{}
+	relaySeriesMap := map[string]*types.GenericChartDataSeries{}
+	for _, stat := range history.Relays {
+		series, ok := relaySeriesMap[stat.Relay]
+		if !ok {
+			series = &types.GenericChartDataSeries{Name: stat.Relay, Data: [][]float64{}}
+			relaySeriesMap[stat.Relay] = series
+			relaySeries = append(relaySeries, series)
+		}
+		series.Data = append(series.Data.([][]float64), []float64{float64(utils.DayToTime(int64(stat.Day)).UnixMilli()), stat.MarketShare * 100})
+	}
+
+	builderBlocks := map[string]uint64{}
+	builderNames := map[string]string{}
+	for _, stat := range history.Builders {
+		builderBlocks[stat.BuilderPubkey] += stat.BlockCount
+		builderNames[stat.BuilderPubkey] = utils.FormatBuilderName(stat.BuilderPubkey, stat.ExtraData)
+	}
+	topBuilders := make([]string, 0, len(builderBlocks))
+	for builder := range builderBlocks {
+		topBuilders = append(topBuilders, builder)
+	}
+	sort.Slice(topBuilders, func(i, j int) bool {
+		return builderBlocks[topBuilders[i]] > builderBlocks[topBuilders[j]]
+	})
+	if len(topBuilders) > relaysMarketShareTopBuilders {
+		topBuilders = topBuilders[:relaysMarketShareTopBuilders]
+	}
+	isTopBuilder := map[string]bool{}
+	for _, builder := range topBuilders {
+		isTopBuilder[builder] = true
+	}
+
+	othersShare := map[uint64]float64{}
+	builderSeries = []*types.GenericChartDataSeries{}
+	builderSeriesMap := map[string]*types.GenericChartDataSeries{}
+	for _, builder := range topBuilders {
+		series := &types.GenericChartDataSeries{Name: builderNames[builder], Data: [][]float64{}}
+		builderSeriesMap[builder] = series
+		builderSeries = append(builderSeries, series)
+	}
+	for _, stat := range history.Builders {
+		if !isTopBuilder[stat.BuilderPubkey] {
+			othersShare[stat.Day] += stat.MarketShare
+			continue
+		}
+		series := builderSeriesMap[stat.BuilderPubkey]
+		series.Data = append(series.Data.([][]float64), []float64{float64(utils.DayToTime(int64(stat.Day)).UnixMilli()), stat.MarketShare * 100})
+	}
+
+	censoring := [][]float64{}
+	others := [][]float64{}
+	for _, day := range history.Days {
+		ts := float64(utils.DayToTime(int64(day.Day)).UnixMilli())
+		if share, ok := othersShare[day.Day]; ok {
+			others = append(others, []float64{ts, share * 100})
+		}
+		if day.BlockCount > 0 {
+			censoring = append(censoring, []float64{ts, float64(day.CensoringBlockCount) / float64(day.BlockCount) * 100})
+		}
+	}
+	if len(others) > 0 {
+		builderSeries = append(builderSeries, &types.GenericChartDataSeries{Name: "Others", Data: others})
+	}
+	censoringSeries = []*types.GenericChartDataSeries{{Name: "Blocks via censoring relays", Data: censoring}}
+
+	return relaySeries, builderSeries, censoringSeries
+}
+
 func relaysUpdater(wg *sync.WaitGroup) {
 	firstRun := true
 
@@ -346,7 +465,7 @@ func epochUpdater(wg *sync.WaitGroup) {
 	for {
 		// latest epoch acording to the node
 		var epochNode uint64
-		err := db.WriterDb.Get(&epochNode, "SELECT headepoch FROM network_liveness order by headepoch desc LIMIT 1")
+		err := db.Reader(db.ReadPrimary).Get(&epochNode, "SELECT headepoch FROM network_liveness order by headepoch desc LIMIT 1")
 		if err != nil {
 			logger.Errorf("error retrieving latest node epoch from the database: %v", err)
 		} else {
@@ -359,7 +478,7 @@ func epochUpdater(wg *sync.WaitGroup) {
 
 		// latest finalized epoch acording to the node
 		var latestNodeFinalized uint64
-		err = db.WriterDb.Get(&latestNodeFinalized, "SELECT finalizedepoch FROM network_liveness order by headepoch desc LIMIT 1")
+		err = db.Reader(db.ReadPrimary).Get(&latestNodeFinalized, "SELECT finalizedepoch FROM network_liveness order by headepoch desc LIMIT 1")
 		if err != nil {
 			logger.Errorf("error retrieving latest node finalized epoch from the database: %v", err)
 		} else {
@@ -372,7 +491,7 @@ func epochUpdater(wg *sync.WaitGroup) {
 
 		// latest exported epoch
 		var epoch uint64
-		err = db.WriterDb.Get(&epoch, "SELECT COALESCE(MAX(epoch), 0) FROM epochs")
+		err = db.Reader(db.ReadPrimary).Get(&epoch, "SELECT COALESCE(MAX(epoch), 0) FROM epochs")
 		if err != nil {
 			logger.Errorf("error retrieving latest exported epoch from the database: %v", err)
 		} else {
@@ -410,7 +529,7 @@ func slotUpdater(wg *sync.WaitGroup) {
 
 	for {
 		var slot uint64
-		err := db.WriterDb.Get(&slot, "SELECT COALESCE(MAX(slot), 0) FROM blocks where slot < $1", utils.TimeToSlot(uint64(time.Now().Add(time.Second*10).Unix())))
+		err := db.Reader(db.ReadPrimary).Get(&slot, "SELECT COALESCE(MAX(slot), 0) FROM blocks where slot < $1", utils.TimeToSlot(uint64(time.Now().Add(time.Second*10).Unix())))
 
 		if err != nil {
 			logger.Errorf("error retrieving latest slot from the database: %v", err)
@@ -439,6 +558,16 @@ func poolsUpdater(wg *sync.WaitGroup) {
 	firstRun := true
 
 	for {
+		err := db.SavePoolAprSnapshots()
+		if err != nil {
+			logger.Errorf("error saving pool apr snapshots: %v", err)
+		}
+
+		err = db.SavePoolRiskSnapshot(utils.TimeToDay(uint64(time.Now().Unix())), LatestEpoch(), utils.EpochsPerDay()*365)
+		if err != nil {
+			logger.Errorf("error saving pool risk snapshot: %v", err)
+		}
+
 		data, err := getPoolsPageData()
 		if err != nil {
 			logger.Errorf("error retrieving pools page data: %v", err)
@@ -483,8 +612,27 @@ func getPoolsPageData() (*types.PoolsResp, error) {
 		pool.EthstoreComparison7d = pool.AvgPerformance7d*100/ethstoreData.AvgPerformance7d - 100
 		pool.EthstoreComparison31d = pool.AvgPerformance31d*100/ethstoreData.AvgPerformance31d - 100
 	}
+
+	risks, err := db.GetPoolRisk()
+	if err != nil {
+		return nil, err
+	}
+	riskByPool := make(map[string]*types.ApiPoolRiskResponse, len(risks))
+	for _, risk := range risks {
+		riskByPool[risk.Pool] = risk
+	}
+	for _, pool := range poolData.PoolInfos {
+		if risk, ok := riskByPool[pool.Name]; ok {
+			pool.RiskScore = &risk.RiskScore
+		}
+	}
 	poolData.PoolInfos = append([]*types.PoolInfo{ethstoreData}, poolData.PoolInfos...)
 
+	poolData.EntitiesVersion, err = db.GetLatestValidatorEntitiesVersion()
+	if err != nil {
+		return nil, err
+	}
+
 	return &poolData, nil
 }
 
@@ -493,7 +641,7 @@ func latestProposedSlotUpdater(wg *sync.WaitGroup) {
 
 	for {
 		var slot uint64
-		err := db.WriterDb.Get(&slot, "SELECT COALESCE(MAX(slot), 0) FROM blocks WHERE status = '1'")
+		err := db.Reader(db.ReadPrimary).Get(&slot, "SELECT COALESCE(MAX(slot), 0) FROM blocks WHERE status = '1'")
 
 		if err != nil {
 			logger.Errorf("error retrieving latest proposed slot from the database: %v", err)
@@ -530,7 +678,7 @@ func indexPageDataUpdater(wg *sync.WaitGroup) {
 		logger.WithFields(logrus.Fields{"genesis": data.Genesis, "currentEpoch": data.CurrentEpoch, "networkName": data.NetworkName, "networkStartTs": data.NetworkStartTs}).Infof("index page data update completed in %v", time.Since(start))
 
 		cacheKey := fmt.Sprintf("%d:frontend:indexPageData", utils.Config.Chain.ClConfig.DepositChainID)
-		err = cache.TieredCache.Set(cacheKey, data, utils.Day)
+		err = cache.Set(cache.KeyTypeIndexPage, cacheKey, data)
 		if err != nil {
 			logger.Errorf("error caching indexPageData: %v", err)
 		}
@@ -690,8 +838,8 @@ func getIndexPageData() (*types.IndexPageData, error) {
 				FROM eth1_deposits
 				WHERE valid_signature = true
 				GROUP BY publickey
-				HAVING SUM(amount) >= 32e9
-			) a`)
+				HAVING SUM(amount) >= $1
+			) a`, utils.Config.Chain.ClConfig.MaxEffectiveBalance)
 		if err != nil {
 			return nil, fmt.Errorf("error retrieving eth1 deposits: %v", err)
 		}
@@ -703,15 +851,15 @@ func getIndexPageData() (*types.IndexPageData, error) {
 			}
 
 			if deposit.Total > 0 {
-				deposit.Total = (deposit.Total + 1) * 32
+				deposit.Total = uint64(float64(deposit.Total+1) * utils.ActivationBalance())
 				deposit.BlockTs = time.Now()
 			}
 		}
 
-		data.DepositThreshold = float64(utils.Config.Chain.ClConfig.MinGenesisActiveValidatorCount) * 32
+		data.DepositThreshold = float64(utils.Config.Chain.ClConfig.MinGenesisActiveValidatorCount) * utils.ActivationBalance()
 		data.DepositedTotal = float64(deposit.Total)
 
-		data.ValidatorsRemaining = (data.DepositThreshold - data.DepositedTotal) / 32
+		data.ValidatorsRemaining = (data.DepositThreshold - data.DepositedTotal) / utils.ActivationBalance()
 		// genesisDelay := time.Duration(int64(utils.Config.Chain.ClConfig.GenesisDelay) * 1000 * 1000 * 1000) // convert seconds to nanoseconds
 
 		minGenesisTime := time.Unix(int64(utils.Config.Chain.ClConfig.MinGenesisTime), 0)
@@ -789,7 +937,7 @@ func getIndexPageData() (*types.IndexPageData, error) {
 	}
 
 	var scheduledCount uint8
-	err = db.WriterDb.Get(&scheduledCount, `
+	err = db.ReaderDb.Get(&scheduledCount, `
 		select count(*) from blocks where status = '0' and epoch = $1;
 	`, epoch)
 	if err != nil {
@@ -918,7 +1066,7 @@ func getIndexPageData() (*types.IndexPageData, error) {
 		epochLowerBound = epoch - 1600
 	}
 	var epochHistory []*types.IndexPageEpochHistory
-	err = db.WriterDb.Select(&epochHistory, "SELECT epoch, eligibleether, validatorscount, (epoch <= $3) AS finalized, averagevalidatorbalance FROM epochs WHERE epoch < $1 and epoch > $2 ORDER BY epoch", epoch, epochLowerBound, latestFinalizedEpoch)
+	err = db.ReaderDb.Select(&epochHistory, "SELECT epoch, eligibleether, validatorscount, (epoch <= $3) AS finalized, averagevalidatorbalance FROM epochs WHERE epoch < $1 and epoch > $2 ORDER BY epoch", epoch, epochLowerBound, latestFinalizedEpoch)
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving staked ether history: %v", err)
 	}
@@ -954,7 +1102,7 @@ func getIndexPageData() (*types.IndexPageData, error) {
 func LatestEpoch() uint64 {
 	cacheKey := fmt.Sprintf("%d:frontend:latestEpoch", utils.Config.Chain.ClConfig.DepositChainID)
 
-	if wanted, err := cache.TieredCache.GetUint64WithLocalTimeout(cacheKey, time.Second*5); err == nil {
+	if wanted, err := cache.TieredCache.GetUint64Hot(cacheKey); err == nil {
 		return wanted
 	} else {
 		logger.Errorf("error retrieving latestEpoch from cache: %v", err)
@@ -966,7 +1114,7 @@ func LatestEpoch() uint64 {
 func LatestNodeEpoch() uint64 {
 	cacheKey := fmt.Sprintf("%d:frontend:latestNodeEpoch", utils.Config.Chain.ClConfig.DepositChainID)
 
-	if wanted, err := cache.TieredCache.GetUint64WithLocalTimeout(cacheKey, time.Second*5); err == nil {
+	if wanted, err := cache.TieredCache.GetUint64Hot(cacheKey); err == nil {
 		return wanted
 	} else {
 		logger.Errorf("error retrieving latestNodeEpoch from cache: %v", err)
@@ -978,7 +1126,7 @@ func LatestNodeEpoch() uint64 {
 func LatestNodeFinalizedEpoch() uint64 {
 	cacheKey := fmt.Sprintf("%d:frontend:latestNodeFinalizedEpoch", utils.Config.Chain.ClConfig.DepositChainID)
 
-	if wanted, err := cache.TieredCache.GetUint64WithLocalTimeout(cacheKey, time.Second*5); err == nil {
+	if wanted, err := cache.TieredCache.GetUint64Hot(cacheKey); err == nil {
 		return wanted
 	} else {
 		logger.Errorf("error retrieving latestNodeFinalizedEpoch from cache: %v", err)
@@ -991,7 +1139,7 @@ func LatestNodeFinalizedEpoch() uint64 {
 func LatestFinalizedEpoch() uint64 {
 	cacheKey := fmt.Sprintf("%d:frontend:latestFinalized", utils.Config.Chain.ClConfig.DepositChainID)
 
-	if wanted, err := cache.TieredCache.GetUint64WithLocalTimeout(cacheKey, time.Second*5); err == nil {
+	if wanted, err := cache.TieredCache.GetUint64Hot(cacheKey); err == nil {
 		return wanted
 	} else {
 		logger.Errorf("error retrieving latestFinalized for key: %v from cache: %v", cacheKey, err)
@@ -1003,7 +1151,7 @@ func LatestFinalizedEpoch() uint64 {
 func LatestSlot() uint64 {
 	cacheKey := fmt.Sprintf("%d:frontend:slot", utils.Config.Chain.ClConfig.DepositChainID)
 
-	if wanted, err := cache.TieredCache.GetUint64WithLocalTimeout(cacheKey, time.Second*5); err == nil {
+	if wanted, err := cache.TieredCache.GetUint64Hot(cacheKey); err == nil {
 		return wanted
 	} else {
 		logger.Errorf("error retrieving latest slot from cache: %v", err)
@@ -1020,7 +1168,7 @@ func FinalizationDelay() uint64 {
 func LatestProposedSlot() uint64 {
 	cacheKey := fmt.Sprintf("%d:frontend:latestProposedSlot", utils.Config.Chain.ClConfig.DepositChainID)
 
-	if wanted, err := cache.TieredCache.GetUint64WithLocalTimeout(cacheKey, time.Second*5); err == nil {
+	if wanted, err := cache.TieredCache.GetUint64Hot(cacheKey); err == nil {
 		return wanted
 	} else {
 		logger.Errorf("error retrieving latestProposedSlot from cache: %v", err)
@@ -1039,6 +1187,17 @@ func LatestMempoolTransactions() *types.RawMempoolResponse {
 	return &types.RawMempoolResponse{}
 }
 
+func LatestMempoolStats() *types.MempoolStats {
+	wanted := &types.MempoolStats{}
+	cacheKey := fmt.Sprintf("%d:frontend:mempoolStats", utils.Config.Chain.ClConfig.DepositChainID)
+	if wanted, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, time.Minute, wanted); err == nil {
+		return wanted.(*types.MempoolStats)
+	} else {
+		logger.Errorf("error retrieving mempool stats from cache: %v", err)
+	}
+	return &types.MempoolStats{}
+}
+
 func LatestBurnData() *types.BurnPageData {
 	wanted := &types.BurnPageData{}
 	cacheKey := fmt.Sprintf("%d:frontend:burn", utils.Config.Chain.ClConfig.DepositChainID)
@@ -1067,11 +1226,10 @@ func EthStoreDisclaimer() string {
 
 // LatestIndexPageData returns the latest index page data
 func LatestIndexPageData() *types.IndexPageData {
-	wanted := &types.IndexPageData{}
 	cacheKey := fmt.Sprintf("%d:frontend:indexPageData", utils.Config.Chain.ClConfig.DepositChainID)
 
-	if wanted, err := cache.TieredCache.GetWithLocalTimeout(cacheKey, time.Second*5, wanted); err == nil {
-		return wanted.(*types.IndexPageData)
+	if wanted, err := cache.Get[*types.IndexPageData](cache.KeyTypeIndexPage, cacheKey); err == nil {
+		return wanted
 	} else {
 		logger.Errorf("error retrieving indexPageData from cache: %v", err)
 	}
@@ -1251,7 +1409,7 @@ func GlobalNotificationMessage() template.HTML {
 	if time.Since(globalNotificationMessageTs) > time.Minute*10 {
 		globalNotificationMessageTs = time.Now()
 
-		err := db.WriterDb.Get(&globalNotificationMessage, "SELECT content FROM global_notifications WHERE target = $1 AND enabled", utils.Config.Chain.Name)
+		err := db.ReaderDb.Get(&globalNotificationMessage, "SELECT content FROM global_notifications WHERE target = $1 AND enabled", utils.Config.Chain.Name)
 
 		if err != nil && err != sql.ErrNoRows {
 			logger.Errorf("error updating global notification message: %v", err)
@@ -1389,6 +1547,11 @@ func getGasNowData() (*types.GasNowPageData, error) {
 		gpoData.Data.Slow = header.BaseFee
 	}
 
+	err = setGasNowFeeEstimates(client, gpoData)
+	if err != nil {
+		logger.Warnf("error estimating base and priority fees for gas now data: %v", err)
+	}
+
 	err = db.BigtableClient.SaveGasNowHistory(gpoData.Data.Slow, gpoData.Data.Standard, gpoData.Data.Fast, gpoData.Data.Rapid)
 	if err != nil {
 		logrus.WithError(err).Error("error updating gas now history")
@@ -1404,6 +1567,51 @@ func getGasNowData() (*types.GasNowPageData, error) {
 	return gpoData, nil
 }
 
+// setGasNowFeeEstimates adds the base fee of the next block, the base fee trend and the priority fee percentiles
+// of the most recent blocks to the gas now data. The percentiles 90, 60, 30 and 10 are used for the rapid, fast,
+// standard and slow inclusion speeds, each being the median of the respective percentile over the recent blocks.
+func setGasNowFeeEstimates(client *geth_rpc.Client, gpoData *types.GasNowPageData) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	feeHistory, err := ethclient.NewClient(client).FeeHistory(ctx, gasNowFeeHistoryBlocks, nil, []float64{90, 60, 30, 10})
+	if err != nil {
+		return fmt.Errorf("error retrieving fee history: %w", err)
+	}
+	if len(feeHistory.BaseFee) == 0 {
+		return fmt.Errorf("fee history does not contain any base fees")
+	}
+
+	// the fee history contains the base fee of the next block as last entry
+	gpoData.Data.BaseFee = feeHistory.BaseFee[len(feeHistory.BaseFee)-1]
+	gpoData.Data.BaseFeeTrend = feeHistory.BaseFee[:len(feeHistory.BaseFee)-1]
+
+	median := func(percentileIndex int) *big.Int {
+		fees := make([]*big.Int, 0, len(feeHistory.Reward))
+		for _, reward := range feeHistory.Reward {
+			// blocks without transactions may not report any rewards
+			if len(reward) > percentileIndex {
+				fees = append(fees, reward[percentileIndex])
+			}
+		}
+		if len(fees) == 0 {
+			return new(big.Int)
+		}
+		sort.Slice(fees, func(i, j int) bool {
+			return fees[i].Cmp(fees[j]) < 0
+		})
+		return fees[len(fees)/2]
+	}
+	gpoData.Data.PriorityFees = &types.GasNowPriorityFees{
+		Rapid:    median(0),
+		Fast:     median(1),
+		Standard: median(2),
+		Slow:     median(3),
+	}
+
+	return nil
+}
+
 type TxPoolContent struct {
 	Pending map[string]map[int]*TxPoolContentTransaction
 }
@@ -1463,6 +1671,7 @@ func (tx *rpcTransaction) UnmarshalJSON(msg []byte) error {
 func mempoolUpdater(wg *sync.WaitGroup) {
 	firstRun := true
 	errorCount := 0
+	lastSnapshot := time.Time{}
 
 	var client *geth_rpc.Client
 
@@ -1532,6 +1741,25 @@ func mempoolUpdater(wg *sync.WaitGroup) {
 		if err != nil {
 			logger.Errorf("error caching mempool data: %v", err)
 		}
+
+		stats := getMempoolStats(&mempoolTx, time.Now())
+		cacheKey = fmt.Sprintf("%d:frontend:mempoolStats", utils.Config.Chain.ClConfig.DepositChainID)
+		err = cache.TieredCache.Set(cacheKey, stats, utils.Day)
+		if err != nil {
+			logger.Errorf("error caching mempool stats: %v", err)
+		}
+		if time.Since(lastSnapshot) >= mempoolSnapshotInterval {
+			err = db.SaveMempoolSnapshot(stats)
+			if err != nil {
+				logger.Errorf("error saving mempool snapshot: %v", err)
+			} else {
+				lastSnapshot = stats.Ts
+				err = db.DeleteMempoolSnapshotsBefore(stats.Ts.Add(-mempoolSnapshotRetention))
+				if err != nil {
+					logger.Errorf("error deleting old mempool snapshots: %v", err)
+				}
+			}
+		}
 		if firstRun {
 			logger.Info("initialized mempool updater")
 			wg.Done()
@@ -1542,6 +1770,78 @@ func mempoolUpdater(wg *sync.WaitGroup) {
 	}
 }
 
+// getMempoolStats calculates the size, gas price percentiles and top spenders of the executable (pending) transactions of the mempool
+func getMempoolStats(mempool *types.RawMempoolResponse, ts time.Time) *types.MempoolStats {
+	stats := &types.MempoolStats{Ts: ts}
+
+	gasPrices := make([]*big.Int, 0)
+	spenders := make(map[common.Address]*types.MempoolSpender)
+	for _, txs := range mempool.Pending {
+		for _, tx := range txs {
+			stats.PendingCount++
+			if tx.Gas == nil || tx.GasPrice == nil {
+				continue
+			}
+			gas := tx.Gas.ToInt()
+			stats.PendingGas += gas.Uint64()
+			gasPrices = append(gasPrices, tx.GasPrice.ToInt())
+
+			if tx.From == nil {
+				continue
+			}
+			spender, exists := spenders[*tx.From]
+			if !exists {
+				spender = &types.MempoolSpender{Address: tx.From.Bytes()}
+				spenders[*tx.From] = spender
+			}
+			spender.TxCount++
+			spender.TotalFees = spender.TotalFees.Add(decimal.NewFromBigInt(new(big.Int).Mul(gas, tx.GasPrice.ToInt()), 0))
+			if tx.Value != nil {
+				spender.TotalValue = spender.TotalValue.Add(decimal.NewFromBigInt(tx.Value.ToInt(), 0))
+			}
+		}
+	}
+	for _, txs := range mempool.Queued {
+		stats.QueuedCount += uint64(len(txs))
+	}
+	for _, txs := range mempool.BaseFee {
+		stats.BaseFeeCount += uint64(len(txs))
+	}
+
+	sort.Slice(gasPrices, func(i, j int) bool {
+		return gasPrices[i].Cmp(gasPrices[j]) < 0
+	})
+	// nearest-rank percentile of the gas prices in GWei
+	percentile := func(p float64) float64 {
+		if len(gasPrices) == 0 {
+			return 0
+		}
+		idx := int(math.Ceil(p*float64(len(gasPrices)))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		return decimal.NewFromBigInt(gasPrices[idx], -9).InexactFloat64()
+	}
+	stats.GasPriceP10 = percentile(0.1)
+	stats.GasPriceP25 = percentile(0.25)
+	stats.GasPriceP50 = percentile(0.5)
+	stats.GasPriceP75 = percentile(0.75)
+	stats.GasPriceP90 = percentile(0.9)
+
+	stats.TopSpenders = make([]*types.MempoolSpender, 0, len(spenders))
+	for _, spender := range spenders {
+		stats.TopSpenders = append(stats.TopSpenders, spender)
+	}
+	sort.Slice(stats.TopSpenders, func(i, j int) bool {
+		return stats.TopSpenders[i].TotalFees.GreaterThan(stats.TopSpenders[j].TotalFees)
+	})
+	if len(stats.TopSpenders) > mempoolTopSpendersLimit {
+		stats.TopSpenders = stats.TopSpenders[:mempoolTopSpendersLimit]
+	}
+
+	return stats
+}
+
 func burnUpdater(wg *sync.WaitGroup) {
 	firstRun := true
 	for ; ; time.Sleep(time.Minute * 15) { // only update once every 15 minutes
@@ -145,7 +145,7 @@ func calculateStats() (*types.Stats, error) {
 func eth1TopDepositers() (*[]types.StatsTopDepositors, error) {
 	topDepositors := []types.StatsTopDepositors{}
 
-	err := db.WriterDb.Select(&topDepositors, `
+	err := db.ReaderDb.Select(&topDepositors, `
 	SELECT 
 		ENCODE(from_address::bytea, 'hex') as from_address, 
 		count(from_address) as count 
@@ -165,7 +165,7 @@ func eth1TopDepositers() (*[]types.StatsTopDepositors, error) {
 func eth1InvalidDeposits() (*uint64, error) {
 	count := uint64(0)
 
-	err := db.WriterDb.Get(&count, `
+	err := db.ReaderDb.Get(&count, `
 	SELECT 
 		count(*) as count
 	FROM eth1_deposits
@@ -182,7 +182,7 @@ func eth1InvalidDeposits() (*uint64, error) {
 func eth1UniqueValidatorsCount() (*uint64, error) {
 	count := uint64(0)
 
-	err := db.WriterDb.Get(&count, `
+	err := db.ReaderDb.Get(&count, `
 	SELECT 
 		count(*) as count
 	FROM 
@@ -196,9 +196,9 @@ func eth1UniqueValidatorsCount() (*uint64, error) {
 			valid_signature = true 
 		GROUP BY 
 			publickey 
-		HAVING sum(amount) >= 32e9
+		HAVING sum(amount) >= $1
 	) as q;
-	`)
+	`, utils.Config.Chain.ClConfig.MaxEffectiveBalance)
 	if err != nil {
 		return nil, err
 	}
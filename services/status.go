@@ -14,7 +14,10 @@ var lastStatusUpdate = make(map[string]time.Time)
 
 // Report the status of a particular service, will add current Pid and executable name
 // Throttle calls to 1/min for each service name so that we don't report too often
+// While the services are drained the calling service is paused, see Drain
 func ReportStatus(name, status string, metadata *json.RawMessage) {
+	waitWhileDrained(name)
+
 	if !utils.Config.ReportServiceStatus {
 		return
 	}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/db"
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// TaxCostBasisMethods are the supported methods to match withdrawals against the income lots they are paid out of
+var TaxCostBasisMethods = []string{"fifo", "lifo", "hifo"}
+
+type taxLot struct {
+	amount float64
+	price  float64
+}
+
+// GetTaxReport values the daily income of the validators in the given year at the price of the day it was earned. Every
+// day of positive consensus income forms a lot, withdrawals consume these lots (oldest first for fifo, newest first for
+// lifo and most expensive first for hifo) and are reported with the cost basis of the consumed lots. Withdrawals exceeding
+// the open lots are principal and carry no cost basis. The lots are built from genesis so withdrawals of income earned in
+// earlier years are matched correctly.
+func GetTaxReport(validators []uint64, currency string, year int, method string) (*types.ApiTaxReportResponse, error) {
+	yearStart := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	yearEnd := yearStart.AddDate(1, 0, 0)
+	genesis := time.Unix(int64(utils.Config.Chain.GenesisTimestamp), 0)
+	if !yearEnd.After(genesis) {
+		return nil, fmt.Errorf("year %v is before genesis", year)
+	}
+
+	// a day belongs to the year it starts in
+	lowerBoundDay := uint64(0)
+	if yearStart.After(genesis) {
+		lowerBoundDay = utils.TimeToDay(uint64(yearStart.Unix()))
+		if utils.DayToTime(int64(lowerBoundDay)).Before(yearStart) {
+			lowerBoundDay++
+		}
+	}
+	upperBoundDay := utils.TimeToDay(uint64(yearEnd.Unix()))
+	if !utils.DayToTime(int64(upperBoundDay)).Before(yearEnd) {
+		upperBoundDay--
+	}
+
+	days, err := db.GetValidatorsTaxIncome(validators, 0, upperBoundDay, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &types.ApiTaxReportResponse{
+		Year:            year,
+		Currency:        strings.ToUpper(currency),
+		CostBasisMethod: method,
+		Validators:      validators,
+		Days:            make([]*types.ApiTaxReportDay, 0, len(days)),
+	}
+
+	lots := []*taxLot{}
+	for _, day := range days {
+		day.Date = utils.DayToTime(int64(day.Day))
+		day.ClIncome = float64(day.ClIncomeGwei) / 1e9
+		day.ElIncome = day.ElIncomeWei / 1e18
+		day.IncomeValue = (day.ClIncome + day.ElIncome) * day.Price
+		day.Withdrawn = float64(day.WithdrawalsGwei) / 1e9
+
+		if day.Withdrawn > 0 {
+			day.WithdrawnCostBasis, lots = consumeTaxLots(lots, day.Withdrawn, method)
+		}
+		if day.ClIncome > 0 {
+			lots = append(lots, &taxLot{amount: day.ClIncome, price: day.Price})
+		}
+
+		if day.Day < lowerBoundDay {
+			continue
+		}
+		report.ClIncome += day.ClIncome
+		report.ElIncome += day.ElIncome
+		report.IncomeValue += day.IncomeValue
+		report.Withdrawn += day.Withdrawn
+		report.WithdrawnCostBasis += day.WithdrawnCostBasis
+		report.Days = append(report.Days, day)
+	}
+
+	for _, lot := range lots {
+		report.RemainingCostBasis += lot.amount * lot.price
+	}
+
+	return report, nil
+}
+
+// consumeTaxLots removes the amount from the lots in the order of the cost basis method and returns the cost basis of
+// the consumed amount together with the remaining lots
+func consumeTaxLots(lots []*taxLot, amount float64, method string) (float64, []*taxLot) {
+	switch method {
+	case "lifo":
+		for i, j := 0, len(lots)-1; i < j; i, j = i+1, j-1 {
+			lots[i], lots[j] = lots[j], lots[i]
+		}
+	case "hifo":
+		sort.SliceStable(lots, func(i, j int) bool {
+			return lots[i].price > lots[j].price
+		})
+	}
+
+	costBasis := 0.0
+	remaining := make([]*taxLot, 0, len(lots))
+	for _, lot := range lots {
+		if amount <= 0 {
+			remaining = append(remaining, lot)
+			continue
+		}
+		consumed := lot.amount
+		if consumed > amount {
+			consumed = amount
+		}
+		costBasis += consumed * lot.price
+		amount -= consumed
+		lot.amount -= consumed
+		if lot.amount > 0 {
+			remaining = append(remaining, lot)
+		}
+	}
+
+	// keep the lots in the order they were acquired in
+	if method == "lifo" {
+		for i, j := 0, len(remaining)-1; i < j; i, j = i+1, j-1 {
+			remaining[i], remaining[j] = remaining[j], remaining[i]
+		}
+	}
+	return costBasis, remaining
+}
+
+// GenerateTaxReportCsv returns the daily rows of the tax report as csv
+func GenerateTaxReportCsv(report *types.ApiTaxReportResponse) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	writer := csv.NewWriter(buf)
+
+	err := writer.Write([]string{
+		"date",
+		"consensus_income_eth",
+		"execution_income_eth",
+		fmt.Sprintf("eth_price_%s", strings.ToLower(report.Currency)),
+		fmt.Sprintf("income_value_%s", strings.ToLower(report.Currency)),
+		"withdrawn_eth",
+		fmt.Sprintf("withdrawn_cost_basis_%s_%s", strings.ToLower(report.Currency), report.CostBasisMethod),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, day := range report.Days {
+		err := writer.Write([]string{
+			day.Date.UTC().Format("2006-01-02"),
+			fmt.Sprintf("%.9f", day.ClIncome),
+			fmt.Sprintf("%.9f", day.ElIncome),
+			fmt.Sprintf("%.2f", day.Price),
+			fmt.Sprintf("%.2f", day.IncomeValue),
+			fmt.Sprintf("%.9f", day.Withdrawn),
+			fmt.Sprintf("%.2f", day.WithdrawnCostBasis),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GenerateTaxReportPdf renders the tax report as pdf, a summary of the year followed by the daily rows
+func GenerateTaxReportPdf(report *types.ApiTaxReportResponse) []byte {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTopMargin(15)
+	pdf.SetHeaderFuncMode(func() {
+		pdf.SetY(5)
+		pdf.SetFont("Arial", "B", 12)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Beaconcha.in Staking Income %d", report.Year), "", 0, "C", false, 0, "")
+	}, true)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Arial", "I", 8)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d/{nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	pdf.AddPage()
+	pdf.SetFont("Times", "", 9)
+	pdf.SetTextColor(24, 24, 24)
+
+	const (
+		lineHt  = 5.0
+		labelWd = 60.0
+	)
+	summary := [][]string{
+		{"Validators", fmt.Sprintf("%d", len(report.Validators))},
+		{"Consensus Income", fmt.Sprintf("%s ETH", addCommas(report.ClIncome, "%.5f"))},
+		{"Execution Income", fmt.Sprintf("%s ETH", addCommas(report.ElIncome, "%.5f"))},
+		{"Income Value", fmt.Sprintf("%s %s", report.Currency, addCommas(report.IncomeValue, "%.2f"))},
+		{"Withdrawn", fmt.Sprintf("%s ETH", addCommas(report.Withdrawn, "%.5f"))},
+		{fmt.Sprintf("Withdrawn Cost Basis (%s)", strings.ToUpper(report.CostBasisMethod)), fmt.Sprintf("%s %s", report.Currency, addCommas(report.WithdrawnCostBasis, "%.2f"))},
+		{"Remaining Cost Basis", fmt.Sprintf("%s %s", report.Currency, addCommas(report.RemainingCostBasis, "%.2f"))},
+	}
+	for _, row := range summary {
+		pdf.CellFormat(labelWd, lineHt, row[0], "", 0, "LM", false, 0, "")
+		pdf.CellFormat(0, lineHt, row[1], "", 1, "LM", false, 0, "")
+	}
+	pdf.Ln(5)
+
+	header := []string{"Date", "Consensus (ETH)", "Execution (ETH)", fmt.Sprintf("Price (%s)", report.Currency), fmt.Sprintf("Value (%s)", report.Currency), "Withdrawn (ETH)", fmt.Sprintf("Cost Basis (%s)", report.Currency)}
+	colWd := 190.0 / float64(len(header))
+	drawHeader := func() {
+		pdf.SetTextColor(224, 224, 224)
+		pdf.SetFillColor(64, 64, 64)
+		for _, h := range header {
+			pdf.CellFormat(colWd, lineHt, h, "1", 0, "CM", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetTextColor(24, 24, 24)
+	}
+	drawHeader()
+
+	_, pageHt := pdf.GetPageSize()
+	for i, day := range report.Days {
+		if pdf.GetY()+lineHt > pageHt-20 {
+			pdf.AddPage()
+			drawHeader()
+		}
+		pdf.SetFillColor(255, 255, 255)
+		if i%2 != 0 {
+			pdf.SetFillColor(191, 191, 191)
+		}
+		row := []string{
+			day.Date.UTC().Format("2006-01-02"),
+			addCommas(day.ClIncome, "%.5f"),
+			addCommas(day.ElIncome, "%.5f"),
+			addCommas(day.Price, "%.2f"),
+			addCommas(day.IncomeValue, "%.2f"),
+			addCommas(day.Withdrawn, "%.5f"),
+			addCommas(day.WithdrawnCostBasis, "%.2f"),
+		}
+		for _, cell := range row {
+			pdf.CellFormat(colWd, lineHt, cell, "1", 0, "RM", true, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	buf := new(bytes.Buffer)
+	err := pdf.Output(buf)
+	if err != nil {
+		logger.Errorf("error generating tax report pdf: %v", err)
+		return []byte{}
+	}
+	return buf.Bytes()
+}
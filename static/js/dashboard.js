@@ -387,6 +387,20 @@ $(document).ready(function () {
     window.location.href = "/rewards"
   })
 
+  for (var year = new Date().getUTCFullYear(); year >= new Date($("#tax-export-year").data("genesis") * 1000).getUTCFullYear(); year--) {
+    $("#tax-export-year").append(`<option value="${year}">${year}</option>`)
+  }
+  $("#tax-export-download").on("click", () => {
+    var params = new URLSearchParams({
+      validators: state.validators.join(","),
+      year: $("#tax-export-year").val(),
+      currency: $("#tax-export-currency").val(),
+      method: $("#tax-export-method").val(),
+      format: $("#tax-export-format").val(),
+    })
+    window.location.href = `/dashboard/tax?${params.toString()}`
+  })
+
   $(".proposal-switch").on("click", () => {
     if ($(".switch-chart").hasClass("proposal-switch-selected")) {
       if (firstSwitch) {
@@ -904,6 +918,249 @@ $(document).ready(function () {
     $(".multiselect-border").removeClass("focused")
   })
 
+  function loadSavedDashboards() {
+    if (!document.getElementById("saved-dashboards-menu")) return
+    fetch("/dashboard/saved")
+      .then((res) => res.json())
+      .then((res) => {
+        if (res.status !== "OK") return
+        renderSavedDashboards(res.data || [])
+      })
+  }
+
+  function renderSavedDashboards(dashboards) {
+    var list = $("#saved-dashboards-list").empty()
+    if (!dashboards.length) {
+      list.append('<span class="dropdown-item-text text-muted small">No saved dashboards yet</span>')
+      return
+    }
+    dashboards.forEach((dashboard) => {
+      var validators = dashboard.groups.flatMap((g) => g.validators)
+      var item = $('<div class="dropdown-item d-flex justify-content-between align-items-center"></div>')
+      // dashboards exceeding the validator limit are only shown server side aggregated
+      var href = validators.length > VALLIMIT ? `/dashboard?dashboard=${dashboard.id}` : `/dashboard?validators=${validators.join(",")}&dashboard=${dashboard.id}`
+      var link = $("<a></a>").attr("href", href).text(`${dashboard.name} (${validators.length})`)
+      var share = $('<a href="#" class="ml-3" title="Share a read-only link"><i class="fas fa-share-alt"></i></a>')
+      share.on("click", (e) => {
+        e.preventDefault()
+        e.stopPropagation()
+        fetch(`/dashboard/saved/${dashboard.id}/share`, { method: "POST" })
+          .then((res) => res.json())
+          .then((res) => {
+            if (res.status !== "OK") {
+              alert(res.status)
+              return
+            }
+            window.prompt("Everyone with this link can view the dashboard:", `${window.location.origin}/dashboard/shared/${res.data.public_id}`)
+          })
+      })
+      var remove = $('<a href="#" class="ml-2 text-danger" title="Delete dashboard"><i class="fas fa-trash"></i></a>')
+      remove.on("click", (e) => {
+        e.preventDefault()
+        e.stopPropagation()
+        if (!window.confirm(`Delete the dashboard ${dashboard.name}? Its shared links will stop working.`)) return
+        fetch(`/dashboard/saved/${dashboard.id}`, { method: "DELETE" }).then(loadSavedDashboards)
+      })
+      item.append(link, $("<span></span>").append(share, remove))
+      list.append(item)
+    })
+  }
+
+  function formatEfficiency(value) {
+    if (value === null || value === undefined) return "-"
+    return (value * 100).toFixed(2) + "%"
+  }
+
+  function savedDashboardUrl() {
+    var params = new URLSearchParams(window.location.search)
+    if (params.get("dashboard")) {
+      return `/dashboard/saved/${encodeURIComponent(params.get("dashboard"))}`
+    } else if (params.get("shared")) {
+      return `/dashboard/shared/${encodeURIComponent(params.get("shared"))}`
+    }
+    return null
+  }
+
+  function loadDashboardSummary() {
+    var url = savedDashboardUrl()
+    var address = new URLSearchParams(window.location.search).get("address")
+    if (!url && address) {
+      url = `/dashboard/address/${encodeURIComponent(address)}`
+    }
+    if (!url) return
+    fetch(url + "/summary?days=31")
+      .then((res) => res.json())
+      .then((res) => {
+        if (res.status !== "OK" || !res.data) return
+        var s = res.data.summary || res.data
+        $("#dashboard-summary-validators").text(`${s.active_validators} active / ${s.pending_validators} pending / ${s.exited_validators} exited`)
+        $("#dashboard-summary-balance").text(`${s.balance.toFixed(4)} ETH`)
+        $("#dashboard-summary-income").text(`${s.income_1d.toFixed(4)} / ${s.income_7d.toFixed(4)} / ${s.income_31d.toFixed(4)} ETH`)
+        $("#dashboard-summary-efficiency").text(`${formatEfficiency(s.attestation_efficiency)} / ${formatEfficiency(s.proposal_efficiency)} / ${formatEfficiency(s.sync_efficiency)}`)
+        Highcharts.stockChart("dashboard-summary-chart", {
+          title: { text: "Daily Income and Balance" },
+          legend: { enabled: true },
+          rangeSelector: { enabled: false },
+          navigator: { enabled: false },
+          scrollbar: { enabled: false },
+          yAxis: [
+            { title: { text: "Income [ETH]" }, opposite: false },
+            { title: { text: "Balance [ETH]" }, opposite: true },
+          ],
+          series: [
+            { name: "Income", type: "column", data: s.income_history.map((d) => [d.x, d.y]), yAxis: 0 },
+            { name: "Balance", type: "line", data: s.balance_history.map((d) => [d.x, d.y]), yAxis: 1 },
+          ],
+        })
+        $("#dashboard-summary").removeClass("d-none")
+      })
+  }
+  loadDashboardSummary()
+
+  function loadDashboardGroups() {
+    var url = savedDashboardUrl()
+    if (!url) return
+    fetch(url + "/groups")
+      .then((res) => res.json())
+      .then((res) => {
+        if (res.status !== "OK" || !res.data) return
+        var groups = Array.isArray(res.data) ? res.data : [res.data]
+        var table = $("#dashboard-groups-table").empty()
+        groups.forEach((g) => {
+          var row = $("<tr></tr>")
+          row.append($("<td></td>").text(g.name))
+          row.append($('<td class="text-right"></td>').text(`${g.active_validators} / ${g.validators}`))
+          row.append($('<td class="text-right"></td>').text(g.offline_validators.length).toggleClass("text-danger", g.offline_validators.length > 0))
+          row.append($('<td class="text-right"></td>').text(formatEfficiency(g.attestation_efficiency)))
+          row.append($('<td class="text-right"></td>').text(formatEfficiency(g.proposal_efficiency)))
+          row.append($('<td class="text-right"></td>').text(formatEfficiency(g.sync_efficiency)))
+          row.append($('<td class="text-right"></td>').text(`${(g.cl_income + g.el_income).toFixed(4)} ETH`))
+          var alerts = $("<td></td>")
+          g.alerts.forEach((a) => alerts.append($('<span class="badge badge-warning mr-1"></span>').text(a)))
+          row.append(alerts)
+          table.append(row)
+        })
+        var select = $("#dashboard-charts-groups").empty()
+        groups.forEach((g) => select.append($("<option></option>").val(g.id).text(g.name)))
+        $("#dashboard-groups").removeClass("d-none")
+      })
+  }
+  loadDashboardGroups()
+
+  function loadDashboardDutyCalendar() {
+    var url = savedDashboardUrl()
+    if (!url) return
+    fetch(url + "/dutycalendar")
+      .then((res) => res.json())
+      .then((res) => {
+        if (res.status !== "OK") return
+        renderDutyCalendar("#duty-calendar", res.data)
+        $("#duty-calendar-card").removeClass("d-none")
+      })
+  }
+  loadDashboardDutyCalendar()
+
+  function renderDashboardChart(url, chart) {
+    var id = `dashboard-chart-${chart.id}`
+    var item = $('<div class="my-2"></div>')
+    item.append($(`<div id="${id}" style="height:300px;"></div>`))
+    if (url.startsWith("/dashboard/saved/")) {
+      var remove = $('<button type="button" class="btn btn-sm btn-outline-danger">Remove</button>')
+      remove.on("click", () => {
+        if (!window.confirm(`Remove the chart ${chart.name}?`)) return
+        fetch(`${url}/charts/${chart.id}`, { method: "DELETE" }).then(() => loadDashboardCharts())
+      })
+      item.append(remove)
+    }
+    $("#dashboard-charts-list").append(item)
+    fetch(`${url}/charts/${chart.id}/series`)
+      .then((res) => res.json())
+      .then((res) => {
+        if (res.status !== "OK" || !res.data) return
+        var series = Array.isArray(res.data) ? res.data : [res.data]
+        Highcharts.stockChart(id, {
+          title: { text: chart.name },
+          legend: { enabled: series.length > 1 },
+          rangeSelector: { enabled: false },
+          navigator: { enabled: false },
+          scrollbar: { enabled: false },
+          yAxis: { title: { text: `${chart.aggregation} of ${chart.metric.replace(/_/g, " ")}` }, opposite: false },
+          series: series.map((s) => ({ name: s.series, type: "line", data: s.points.map((p) => [new Date(p.time).getTime(), p.value]) })),
+        })
+      })
+  }
+
+  function loadDashboardCharts() {
+    var url = savedDashboardUrl()
+    if (!url) return
+    fetch(url + "/charts")
+      .then((res) => res.json())
+      .then((res) => {
+        if (res.status !== "OK") return
+        var charts = Array.isArray(res.data) ? res.data : res.data ? [res.data] : []
+        $("#dashboard-charts-list").empty()
+        charts.forEach((chart) => renderDashboardChart(url, chart))
+        $("#dashboard-charts-form").toggleClass("d-none", !url.startsWith("/dashboard/saved/"))
+        $("#dashboard-charts").removeClass("d-none")
+      })
+  }
+  loadDashboardCharts()
+
+  $("#dashboard-charts-form").on("submit", (e) => {
+    e.preventDefault()
+    var url = savedDashboardUrl()
+    fetch(url + "/charts", {
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify({
+        name: $("#dashboard-charts-name").val(),
+        metric: $("#dashboard-charts-metric").val(),
+        aggregation: $("#dashboard-charts-aggregation").val(),
+        group_ids: ($("#dashboard-charts-groups").val() || []).map((id) => parseInt(id)),
+        per_group: $("#dashboard-charts-per-group").is(":checked"),
+        days: parseInt($("#dashboard-charts-days").val()),
+      }),
+    })
+      .then((res) => res.json())
+      .then((res) => {
+        if (res.status !== "OK") {
+          alert(res.status)
+          return
+        }
+        $("#dashboard-charts-name").val("")
+        loadDashboardCharts()
+      })
+  })
+
+  $("#saved-dashboards-save").on("click", (e) => {
+    e.preventDefault()
+    var validators = state.validators.filter((v) => !isValidatorPubkey(v)).map((v) => parseInt(v))
+    if (!validators.length) {
+      alert("Add validators to the dashboard before saving it")
+      return
+    }
+    var name = window.prompt("Name of the dashboard")
+    if (!name) return
+    fetch("/dashboard/saved", {
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify({ name: name, validators: validators }),
+    })
+      .then((res) => res.json())
+      .then((res) => {
+        if (res.status !== "OK") {
+          alert(res.status)
+          return
+        }
+        loadSavedDashboards()
+      })
+  })
+  loadSavedDashboards()
+
   $("#clear-search").on("click", function (event) {
     if (state) {
       state = setInitialState()
@@ -991,6 +1248,14 @@ $(document).ready(function () {
         })
       })
 
+      fetch(`/dashboard/data/percentiles${getValidatorQueryString()}`, {
+        method: "GET",
+      }).then((res) => {
+        res.json().then((data) => {
+          renderValidatorPercentiles(data)
+        })
+      })
+
       showProposedHistoryTable()
     } else {
       $("#dash-validator-history-info").addClass("d-none")
@@ -998,6 +1263,7 @@ $(document).ready(function () {
       $("#dash-validator-history-index-div").addClass("d-none")
 
       $("#validator-eff-total").html(summaryDefaultValue)
+      renderValidatorPercentiles({})
       renderProposedHistoryTable([])
     }
 
@@ -1012,6 +1278,28 @@ $(document).ready(function () {
     }, 1100)
   }
 
+  function renderValidatorPercentiles(data) {
+    let badges = ""
+    if (Object.keys(data).length !== 0) {
+      for (let [label, percentile] of [
+        ["Attestations", data.attestation_percentile],
+        ["Proposal Luck", data.proposal_luck_percentile],
+        ["Income", data.income_percentile],
+      ]) {
+        let top = Math.max(Math.ceil(100 - percentile), 1)
+        if (top <= 50) {
+          badges += `<span style="background-color: rgba(46, 204, 113, .2); font-size: 14px;" class="badge-pill mr-1 font-weight-normal"><span style="color: var(--green);">Top ${top}% ${label}</span></span>`
+        }
+      }
+    }
+    $("#validator-percentiles").html(badges)
+    if (badges === "") {
+      $("#validator-percentiles-row").addClass("d-none")
+    } else {
+      $("#validator-percentiles-row").removeClass("d-none")
+    }
+  }
+
   function renderDashboardInfo() {
     var el = document.getElementById("dashboard-info")
     var depositedText = ""
@@ -1177,6 +1465,10 @@ $(document).ready(function () {
 
     if (state.validators.length) {
       var qryStr = "?validators=" + state.validators.join(",")
+      var params = new URLSearchParams(window.location.search)
+      ;["dashboard", "shared", "address"].forEach((p) => {
+        if (params.get(p)) qryStr += `&${p}=${encodeURIComponent(params.get(p))}`
+      })
       if (window.location.search != qryStr) {
         var newUrl = window.location.pathname + qryStr + window.location.hash
         window.history.replaceState(null, "Dashboard", newUrl)
@@ -1236,6 +1528,7 @@ $(document).ready(function () {
 
       if (firstValidatorWithIndex() !== undefined) {
         document.querySelector("#rewards-button").style.visibility = "visible"
+        document.querySelector("#tax-export-button").style.visibility = "visible"
         document.querySelector("#bookmark-button").style.visibility = "visible"
 
         $.ajax({
@@ -1257,6 +1550,7 @@ $(document).ready(function () {
         })
       } else {
         document.querySelector("#rewards-button").style.visibility = "hidden"
+        document.querySelector("#tax-export-button").style.visibility = "hidden"
         document.querySelector("#bookmark-button").style.visibility = "hidden"
 
         document.querySelector("#earnings-day").innerHTML = summaryDefaultValue
@@ -1268,6 +1562,7 @@ $(document).ready(function () {
     } else {
       document.querySelector("#copy-button").style.visibility = "hidden"
       document.querySelector("#rewards-button").style.visibility = "hidden"
+      document.querySelector("#tax-export-button").style.visibility = "hidden"
       document.querySelector("#bookmark-button").style.visibility = "hidden"
       document.querySelector("#clear-search").style.visibility = "hidden"
     }
@@ -1305,6 +1600,9 @@ $(document).ready(function () {
   function hideCharts() {
     hideIncomeChart()
     hideProposedChart()
+    if (!savedDashboardUrl()) {
+      $("#duty-calendar-card").addClass("d-none")
+    }
   }
 
   function hideIncomeChart() {
@@ -1337,6 +1635,15 @@ $(document).ready(function () {
         $("#load-income-btn").removeClass("d-none")
       },
     })
+    if (!savedDashboardUrl()) {
+      $.ajax({
+        url: "/dashboard/data/dutycalendar" + qryStr,
+        success: function (result) {
+          renderDutyCalendar("#duty-calendar", result)
+          $("#duty-calendar-card").removeClass("d-none")
+        },
+      })
+    }
     $.ajax({
       url: "/dashboard/data/proposals" + qryStr,
       success: function (result) {
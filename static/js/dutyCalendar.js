@@ -0,0 +1,69 @@
+// renders a calendar heatmap of the daily duty performance (see /api/v1/validator/{indexOrPubkey}/dutycalendar) into the
+// element, one column per week and one cell per day, colored by the attestation efficiency of the day
+function renderDutyCalendar(element, days) {
+  var container = $(element).empty()
+  if (!days || !days.length) {
+    container.append('<span class="text-muted small">No duty statistics available yet</span>')
+    return
+  }
+
+  function cellColor(day) {
+    if (!day || !day.active_validators) return "var(--duty-calendar-empty, #ebedf0)"
+    var eff = day.attestation_efficiency
+    if (eff >= 0.99) return "#216e39"
+    if (eff >= 0.97) return "#30a14e"
+    if (eff >= 0.95) return "#9be9a8"
+    if (eff >= 0.9) return "#f1c232"
+    if (eff >= 0.75) return "#e69138"
+    return "#cc0000"
+  }
+
+  function cellTitle(day, date) {
+    var title = date.toISOString().substring(0, 10)
+    if (!day || !day.active_validators) return title + ": no active validators"
+    title += `: attestations ${(day.attestation_efficiency * 100).toFixed(2)}% (${day.missed_attestations} missed)`
+    if (day.proposal_efficiency !== null) {
+      title += `, proposals ${day.proposed_blocks}/${day.proposed_blocks + day.missed_blocks}`
+    }
+    return title
+  }
+
+  var byDate = {}
+  days.forEach((d) => {
+    byDate[new Date(d.day_start).toISOString().substring(0, 10)] = d
+  })
+
+  // the calendar starts on the sunday before the first day and ends with the last day
+  var first = new Date(days[0].day_start)
+  var last = new Date(days[days.length - 1].day_start)
+  var start = new Date(Date.UTC(first.getUTCFullYear(), first.getUTCMonth(), first.getUTCDate() - first.getUTCDay()))
+
+  var calendar = $('<div class="d-flex" style="gap:3px;overflow-x:auto;"></div>')
+  var week = null
+  for (var date = start; date <= last; date = new Date(date.getTime() + 86400000)) {
+    if (date.getUTCDay() === 0) {
+      week = $('<div class="d-flex flex-column" style="gap:3px;"></div>')
+      calendar.append(week)
+    }
+    var day = byDate[date.toISOString().substring(0, 10)]
+    var cell = $('<div style="width:11px;height:11px;border-radius:2px;"></div>')
+    cell.css("background-color", date < first ? "transparent" : cellColor(day))
+    if (day && day.missed_blocks > 0) {
+      cell.css("box-shadow", "inset 0 0 0 2px #cc0000")
+    }
+    if (date >= first) {
+      cell.attr("title", cellTitle(day, date))
+    }
+    week.append(cell)
+  }
+  container.append(calendar)
+
+  var legend = $('<div class="d-flex align-items-center small text-muted mt-2" style="gap:3px;"></div>')
+  legend.append('<span class="mr-1">Less</span>')
+  ;[0.5, 0.8, 0.92, 0.96, 0.98, 1].forEach((eff) => {
+    legend.append($('<div style="width:11px;height:11px;border-radius:2px;"></div>').css("background-color", cellColor({ active_validators: 1, attestation_efficiency: eff })))
+  })
+  legend.append('<span class="ml-1">More</span>')
+  legend.append('<div class="ml-3" style="width:11px;height:11px;border-radius:2px;box-shadow:inset 0 0 0 2px #cc0000;"></div><span class="ml-1">Missed proposal</span>')
+  container.append(legend)
+}
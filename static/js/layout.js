@@ -282,7 +282,7 @@ $(document).ready(function () {
       wildcard: "%QUERY",
       maxPendingRequests: requestNum,
       transform: function (data) {
-        return data?.address && data?.domain ? { data: { ...data } } : null
+        return data?.filter((d) => d?.address && d?.domain) ?? []
       },
     },
   })
@@ -456,7 +456,7 @@ $(document).ready(function () {
       templates: {
         header: '<h3 class="h5">Ens</h3>',
         suggestion: function (data) {
-          return `<div class="text-monospace text-truncate"><a href="/ens/${data.domain}">${data.domain} Registration Overview</a></div>`
+          return `<div class="text-monospace text-truncate"><a href="/ens/${data.domain}">${data.domain}</a> <span class="text-muted">${data.address.substring(0, 10)}…</span></div>`
         },
       },
     },
@@ -603,6 +603,8 @@ $(document).ready(function () {
       window.location = "/validator/" + sug.pubkey
     } else if (sug.epoch !== undefined) {
       window.location = "/epoch/" + sug.epoch
+    } else if (sug.domain !== undefined) {
+      window.location = "/ens/" + sug.domain
     } else if (sug.address !== undefined) {
       window.location = "/address/" + sug.address
     } else if (sug.eth1_address !== undefined) {
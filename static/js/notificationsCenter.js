@@ -876,6 +876,42 @@ $(function () {
   }
 
   create_typeahead(".validator-typeahead")
+
+  $("#import-validator-keys-btn").on("click", function () {
+    $("#import-validator-keys-input").val("").trigger("click")
+  })
+  $("#import-validator-keys-input").on("change", function () {
+    if (!this.files.length) return
+    let body = new FormData()
+    for (let file of this.files) {
+      body.append("files", file)
+    }
+    let btn = $("#import-validator-keys-btn")
+    let btnContent = btn.html()
+    btn.attr("disabled", true).html('<div class="spinner-border spinner-border-sm" role="status"><span class="sr-only">Importing...</span></div>')
+    fetch("/user/validators/import", {
+      method: "POST",
+      headers: { "X-CSRF-Token": csrfToken },
+      credentials: "include",
+      body: body,
+    })
+      .then((res) => res.json())
+      .then((res) => {
+        if (res.status !== "OK") {
+          alert(`Error importing validators: ${res.status.replace(/^ERROR: /, "")}`)
+          return
+        }
+        let data = res.data
+        let msg = `Added ${data.added} validator${data.added === 1 ? "" : "s"} to your watchlist.`
+        if (data.unknown_pubkeys.length) {
+          msg += ` ${data.unknown_pubkeys.length} of them ${data.unknown_pubkeys.length === 1 ? "is" : "are"} not deposited yet.`
+        }
+        alert(msg)
+        window.location.reload()
+      })
+      .catch(() => alert("Error importing validators"))
+      .finally(() => btn.attr("disabled", false).html(btnContent))
+  })
   // create_typeahead('.monitoring-typeahead')
 
   loadValidatorsData(DATA)
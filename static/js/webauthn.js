@@ -0,0 +1,74 @@
+// helpers for the WebAuthn ceremonies of handlers/user_webauthn.go, binary fields are exchanged base64url encoded
+
+function webAuthnDecode(s) {
+  s = s.replace(/-/g, "+").replace(/_/g, "/")
+  while (s.length % 4) s += "="
+  return Uint8Array.from(atob(s), (c) => c.charCodeAt(0))
+}
+
+function webAuthnEncode(buf) {
+  var bytes = new Uint8Array(buf)
+  var s = ""
+  for (var i = 0; i < bytes.length; i++) s += String.fromCharCode(bytes[i])
+  return btoa(s).replace(/\+/g, "-").replace(/\//g, "_").replace(/=+$/, "")
+}
+
+function webAuthnSupported() {
+  return !!(window.PublicKeyCredential && navigator.credentials)
+}
+
+function webAuthnFetch(url, options) {
+  return fetch(url, options)
+    .then((res) => res.json())
+    .then((res) => {
+      if (res.status !== "OK") throw new Error(res.status.replace(/^ERROR: /, ""))
+      return res.data
+    })
+}
+
+// registers a new security key or passkey of the logged in user, resolves with the recovery codes if they have been created
+function webAuthnRegister(name, csrfToken) {
+  return webAuthnFetch("/user/webauthn/register")
+    .then((options) => {
+      options.challenge = webAuthnDecode(options.challenge)
+      options.user.id = webAuthnDecode(options.user.id)
+      options.excludeCredentials.forEach((c) => (c.id = webAuthnDecode(c.id)))
+      return navigator.credentials.create({ publicKey: options })
+    })
+    .then((credential) => {
+      var response = credential.response
+      if (!response.getPublicKey) throw new Error("your browser does not support registering security keys")
+      return webAuthnFetch("/user/webauthn/register", {
+        method: "POST",
+        headers: { "Content-Type": "application/json", "X-CSRF-Token": csrfToken },
+        body: JSON.stringify({
+          id: webAuthnEncode(credential.rawId),
+          client_data_json: webAuthnEncode(response.clientDataJSON),
+          authenticator_data: webAuthnEncode(response.getAuthenticatorData()),
+          public_key: webAuthnEncode(response.getPublicKey()),
+          public_key_algorithm: response.getPublicKeyAlgorithm(),
+          name: name,
+        }),
+      })
+    })
+}
+
+// requests an assertion of a security key or passkey and submits it with the form in its assertion field
+function webAuthnLogin(form) {
+  return webAuthnFetch("/login/webauthn")
+    .then((options) => {
+      options.challenge = webAuthnDecode(options.challenge)
+      options.allowCredentials.forEach((c) => (c.id = webAuthnDecode(c.id)))
+      return navigator.credentials.get({ publicKey: options })
+    })
+    .then((credential) => {
+      var response = credential.response
+      form.querySelector("input[name=assertion]").value = JSON.stringify({
+        id: webAuthnEncode(credential.rawId),
+        client_data_json: webAuthnEncode(response.clientDataJSON),
+        authenticator_data: webAuthnEncode(response.authenticatorData),
+        signature: webAuthnEncode(response.signature),
+      })
+      form.submit()
+    })
+}
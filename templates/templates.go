@@ -10,11 +10,9 @@ import (
 	"sync"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
-
-	"github.com/sirupsen/logrus"
 )
 
-var logger = logrus.New().WithField("module", "templates")
+var logger = utils.NewLogger("templates")
 
 var (
 	//go:embed *
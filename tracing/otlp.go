@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpClient uploads spans to the /v1/traces endpoint of an OTLP/HTTP collector. The otlptracehttp client is not used
+// as its collector package depends on a grpc-gateway version that conflicts with the fork required by prysm.
+type otlpClient struct {
+	url        string
+	httpClient *http.Client
+}
+
+func newOtlpClient(endpoint string, insecure bool) *otlpClient {
+	scheme := "https"
+	if insecure {
+		scheme = "http"
+	}
+	return &otlpClient{
+		url:        fmt.Sprintf("%s://%s/v1/traces", scheme, endpoint),
+		httpClient: &http.Client{Timeout: time.Second * 10},
+	}
+}
+
+func (c *otlpClient) Start(ctx context.Context) error {
+	return nil
+}
+
+func (c *otlpClient) Stop(ctx context.Context) error {
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// UploadTraces sends the spans as ExportTraceServiceRequest, which has the same wire format as TracesData
+func (c *otlpClient) UploadTraces(ctx context.Context, spans []*tracepb.ResourceSpans) error {
+	body, err := proto.Marshal(&tracepb.TracesData{ResourceSpans: spans})
+	if err != nil {
+		return fmt.Errorf("error marshalling spans: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error uploading spans: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error uploading spans: collector responded with status %v", resp.Status)
+	}
+	return nil
+}
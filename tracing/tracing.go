@@ -0,0 +1,102 @@
+// Package tracing sets up the OpenTelemetry tracer provider exporting spans via OTLP and provides the instrumentation
+// of the http servers and clients, the db layer and the background services
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/gobitfly/eth2-beaconchain-explorer"
+
+var logger = utils.NewLogger("tracing")
+
+// Init sets the global tracer provider exporting the spans to the configured OTLP endpoint. Without tracing enabled
+// the global no-op provider is kept, so spans are not recorded. The returned function flushes the pending spans.
+func Init() (func(context.Context) error, error) {
+	cfg := utils.Config.Tracing
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptrace.New(context.Background(), newOtlpClient(cfg.Endpoint, cfg.Insecure))
+	if err != nil {
+		return nil, fmt.Errorf("error creating otlp trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(filepath.Base(os.Args[0])),
+		attribute.String("chain", utils.Config.Chain.Name),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("error creating trace resource: %w", err)
+	}
+
+	sampleRatio := cfg.SampleRatio
+	if sampleRatio == 0 {
+		sampleRatio = 1
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	logger.Infof("exporting traces to %v with a sample ratio of %v", cfg.Endpoint, sampleRatio)
+	return provider.Shutdown, nil
+}
+
+// StartSpan starts a span as child of the span of the context
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records the error on the span, if any, and ends it
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// HttpMiddleware starts a span for every request named after the route template, continuing the trace of the caller
+// if the request carries a trace context
+func HttpMiddleware(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http", otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+		if route := mux.CurrentRoute(r); route != nil {
+			if tpl, err := route.GetPathTemplate(); err == nil {
+				return r.Method + " " + tpl
+			}
+		}
+		return r.Method
+	}))
+}
+
+// Transport returns the transport of http clients calling other services, it starts a span for every request and
+// propagates the trace context to the called service
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
@@ -77,6 +77,17 @@ type StatsDataStruct struct {
 	System    interface{} `json:"system"`
 }
 
+// MachineMetricsHistory contains the metrics of a machine downsampled to the resolution, ordered by time ascending
+type MachineMetricsHistory struct {
+	Machine    string                    `json:"machine"`
+	Resolution string                    `json:"resolution"`
+	From       int64                     `json:"from"`
+	To         int64                     `json:"to"`
+	Validator  []*MachineMetricValidator `json:"validator"`
+	Node       []*MachineMetricNode      `json:"node"`
+	System     []*MachineMetricSystem    `json:"system"`
+}
+
 type WidgetResponse struct {
 	Eff             any   `json:"efficiency"`
 	Validator       any   `json:"validator"`
@@ -221,6 +232,117 @@ type ApiEth1AddressERC20TokenResponse struct {
 	Symbol  string `json:"symbol"`
 }
 
+type ApiEth1AddressERC20TransfersResponse struct {
+	Transfers []ApiEth1AddressERC20TransferResponse `json:"transfers"`
+	PageToken string                                `json:"page_token"`
+}
+
+type ApiEth1AddressERC20TransferResponse struct {
+	TxHash      string `json:"tx_hash"`
+	BlockNumber uint64 `json:"block_number"`
+	Timestamp   int64  `json:"timestamp"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Token       string `json:"token"`
+	Symbol      string `json:"symbol"`
+	Value       string `json:"value"`
+}
+
+type ApiEth1AddressNFTResponse struct {
+	Standard    string `json:"standard"`
+	Token       string `json:"token"`
+	TokenId     string `json:"token_id"`
+	Balance     string `json:"balance"`
+	TokenURI    string `json:"token_uri"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Image       string `json:"image"`
+}
+
+type ApiEth1InternalTxResponse struct {
+	BlockNumber uint64 `json:"block_number"`
+	Timestamp   int64  `json:"timestamp"`
+	Type        string `json:"type"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Value       string `json:"value"`
+}
+
+type ApiEth1UserOpResponse struct {
+	UserOpHash    string `json:"user_op_hash"`
+	TxHash        string `json:"tx_hash"`
+	BlockNumber   uint64 `json:"block_number"`
+	Timestamp     int64  `json:"timestamp"`
+	EntryPoint    string `json:"entry_point"`
+	Sender        string `json:"sender"`
+	Paymaster     string `json:"paymaster"`
+	Bundler       string `json:"bundler"`
+	Beneficiary   string `json:"beneficiary"`
+	Nonce         string `json:"nonce"`
+	Success       bool   `json:"success"`
+	ActualGasCost string `json:"actual_gas_cost"`
+	ActualGasUsed uint64 `json:"actual_gas_used"`
+}
+
+type ApiEth1AddressUserOpsResponse struct {
+	UserOps   []ApiEth1UserOpResponse `json:"userops"`
+	PageToken string                  `json:"page_token"`
+}
+
+type ApiEth1AddressLabelResponse struct {
+	Label    string `json:"label"`
+	Category string `json:"category"`
+	Source   string `json:"source"`
+}
+
+type ApiEth1TxDecodedResponse struct {
+	Hash      string                        `json:"hash"`
+	Method    string                        `json:"method"`
+	Signature string                        `json:"signature"`
+	Params    []ApiEth1DecodedParamResponse `json:"params"`
+	Logs      []ApiEth1DecodedLogResponse   `json:"logs"`
+}
+
+type ApiEth1DecodedLogResponse struct {
+	Address string                        `json:"address"`
+	Name    string                        `json:"name"`
+	Topics  []string                      `json:"topics"`
+	Data    string                        `json:"data"`
+	Params  []ApiEth1DecodedParamResponse `json:"params"`
+}
+
+type ApiEth1DecodedParamResponse struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type ApiMempoolStatsResponse struct {
+	Current ApiMempoolSnapshotResponse   `json:"current"`
+	History []ApiMempoolSnapshotResponse `json:"history"`
+}
+
+type ApiMempoolSnapshotResponse struct {
+	Timestamp    int64                       `json:"timestamp"`
+	PendingCount uint64                      `json:"pending_count"`
+	QueuedCount  uint64                      `json:"queued_count"`
+	BaseFeeCount uint64                      `json:"basefee_count"`
+	PendingGas   uint64                      `json:"pending_gas"`
+	GasPriceP10  float64                     `json:"gas_price_p10_gwei"`
+	GasPriceP25  float64                     `json:"gas_price_p25_gwei"`
+	GasPriceP50  float64                     `json:"gas_price_p50_gwei"`
+	GasPriceP75  float64                     `json:"gas_price_p75_gwei"`
+	GasPriceP90  float64                     `json:"gas_price_p90_gwei"`
+	TopSpenders  []ApiMempoolSpenderResponse `json:"top_spenders,omitempty"`
+}
+
+type ApiMempoolSpenderResponse struct {
+	Address    string `json:"address"`
+	TxCount    uint64 `json:"tx_count"`
+	TotalFees  string `json:"total_fees"`
+	TotalValue string `json:"total_value"`
+}
+
 type ApiEth1AddressResponse struct {
 	Address string                             `json:"address"`
 	Ether   string                             `json:"ether"`
@@ -444,10 +566,11 @@ type APIVoluntaryExitResponse struct {
 }
 
 type APISyncCommitteeResponse struct {
-	EndEpoch   uint64   `json:"end_epoch"`
-	Period     uint64   `json:"period"`
-	StartEpoch uint64   `json:"start_epoch"`
-	Validators []uint64 `json:"validators"`
+	EndEpoch   uint64                 `json:"end_epoch"`
+	Period     uint64                 `json:"period"`
+	StartEpoch uint64                 `json:"start_epoch"`
+	Validators []uint64               `json:"validators"`
+	Members    []*SyncCommitteeMember `json:"members"`
 }
 
 type APIRocketpoolStatsResponse struct {
@@ -488,6 +611,474 @@ type ApiRocketpoolValidatorResponse struct {
 	UnclaimedSmoothingPool float64 `json:"unclaimed_smoothing_pool"`
 }
 
+// ApiRocketpoolNodeResponse aggregates a rocketpool node operator and all of its minipools, eth and rpl amounts are in ether
+type ApiRocketpoolNodeResponse struct {
+	Address                string  `json:"address"`
+	TimezoneLocation       string  `json:"timezone_location"`
+	RplStake               float64 `json:"rpl_stake"`
+	EffectiveRplStake      float64 `json:"effective_rpl_stake"`
+	MinRplStake            float64 `json:"min_rpl_stake"`
+	MaxRplStake            float64 `json:"max_rpl_stake"`
+	RplPrice               float64 `json:"rpl_price"`
+	BondedEth              float64 `json:"bonded_eth"`
+	BorrowedEth            float64 `json:"borrowed_eth"`
+	CollateralRatio        float64 `json:"collateral_ratio"`
+	RplCumulativeRewards   float64 `json:"rpl_cumulative_rewards"`
+	UnclaimedRplRewards    float64 `json:"unclaimed_rpl_rewards"`
+	SmoothingPoolOptedIn   bool    `json:"smoothing_pool_opted_in"`
+	ClaimedSmoothingPool   float64 `json:"claimed_smoothing_pool"`
+	UnclaimedSmoothingPool float64 `json:"unclaimed_smoothing_pool"`
+	DepositCredit          float64 `json:"deposit_credit"`
+	// NodeRewards31d is the node operators share (bond plus commission) of the consensus and execution rewards of its minipools in the last 31 days
+	NodeRewards31d       float64                             `json:"node_rewards_31d"`
+	NodeApr31d           float64                             `json:"node_apr_31d"`
+	MinipoolsByStatus    map[string]uint64                   `json:"minipools_by_status"`
+	Minipools            []*ApiRocketpoolNodeMinipool        `json:"minipools"`
+	SmoothingPoolHistory []*ApiRocketpoolSmoothingPoolChange `json:"smoothing_pool_history"`
+}
+
+type ApiRocketpoolNodeMinipool struct {
+	Address            string                               `json:"address"`
+	Pubkey             string                               `json:"pubkey"`
+	ValidatorIndex     *uint64                              `json:"validator_index"`
+	Status             string                               `json:"status"`
+	StatusTime         time.Time                            `json:"status_time"`
+	DepositType        string                               `json:"deposit_type"`
+	NodeFee            float64                              `json:"node_fee"`
+	NodeDepositBalance float64                              `json:"node_deposit_balance"`
+	UserDepositBalance float64                              `json:"user_deposit_balance"`
+	PenaltyCount       uint64                               `json:"penalty_count"`
+	IsVacant           bool                                 `json:"is_vacant"`
+	NodeRewards31d     float64                              `json:"node_rewards_31d"`
+	StatusHistory      []*ApiRocketpoolMinipoolStatusChange `json:"status_history"`
+}
+
+type ApiRocketpoolMinipoolStatusChange struct {
+	Status     string    `json:"status" db:"status"`
+	StatusTime time.Time `json:"status_time" db:"status_time"`
+}
+
+type ApiRocketpoolSmoothingPoolChange struct {
+	OptedIn bool      `json:"opted_in" db:"opted_in"`
+	Ts      time.Time `json:"ts" db:"ts"`
+}
+
+// ApiLidoOperatorResponse is a lido node operator together with the performance of its validators, rewards are in ether
+type ApiLidoOperatorResponse struct {
+	Module                   string     `json:"module" db:"module"`
+	OperatorId               uint64     `json:"operator_id" db:"operator_id"`
+	Name                     string     `json:"name" db:"name"`
+	RewardAddress            string     `json:"reward_address" db:"reward_address"`
+	Active                   bool       `json:"active" db:"active"`
+	TotalDepositedValidators uint64     `json:"total_deposited_validators" db:"total_deposited_validators"`
+	TotalExitedValidators    uint64     `json:"total_exited_validators" db:"total_exited_validators"`
+	StuckValidators          uint64     `json:"stuck_validators" db:"stuck_validators"`
+	RefundedValidators       uint64     `json:"refunded_validators" db:"refunded_validators"`
+	StuckPenaltyEndTs        *time.Time `json:"stuck_penalty_end_ts" db:"stuck_penalty_end_ts"`
+	Penalized                bool       `json:"penalized" db:"penalized"`
+	Validators               uint64     `json:"validators" db:"validators"`
+	ActiveValidators         uint64     `json:"active_validators" db:"active_validators"`
+	ClRewards31d             float64    `json:"cl_rewards_31d" db:"cl_rewards_31d"`
+	ElRewards31d             float64    `json:"el_rewards_31d" db:"el_rewards_31d"`
+	RewardsPerValidator31d   float64    `json:"rewards_per_validator_31d" db:"rewards_per_validator_31d"`
+	Apr31d                   float64    `json:"apr_31d" db:"apr_31d"`
+	UpdatedTs                time.Time  `json:"updated_ts" db:"updated_ts"`
+}
+
+// ApiDvtAdoptionResponse is the adoption of a distributed validator technology protocol, share is the percentage of all active validators
+type ApiDvtAdoptionResponse struct {
+	Protocol         string                   `json:"protocol" db:"protocol"`
+	Validators       uint64                   `json:"validators" db:"validators"`
+	ActiveValidators uint64                   `json:"active_validators" db:"active_validators"`
+	Clusters         uint64                   `json:"clusters" db:"clusters"`
+	Share            float64                  `json:"share" db:"share"`
+	History          []*ApiDvtAdoptionHistory `json:"history"`
+}
+
+type ApiDvtAdoptionHistory struct {
+	Day              time.Time `json:"day" db:"day"`
+	Validators       uint64    `json:"validators" db:"validators"`
+	ActiveValidators uint64    `json:"active_validators" db:"active_validators"`
+	Clusters         uint64    `json:"clusters" db:"clusters"`
+}
+
+// ApiPoolAprResponse is the average apr of a pool over a range of days in percent, the net apr is the gross apr
+// (consensus and execution rewards) reduced by the fee of the pool
+type ApiPoolAprResponse struct {
+	Pool         string  `json:"pool" db:"pool"`
+	Validators   uint64  `json:"validators" db:"validators"`
+	ConsensusApr float64 `json:"consensus_apr" db:"consensus_apr"`
+	ExecutionApr float64 `json:"execution_apr" db:"execution_apr"`
+	GrossApr     float64 `json:"gross_apr" db:"gross_apr"`
+	Fee          float64 `json:"fee" db:"fee"`
+	NetApr       float64 `json:"net_apr" db:"net_apr"`
+	Days         uint64  `json:"days" db:"days"`
+}
+
+// ApiPoolRiskResponse contains the slashing and correlation risk metrics of an entity, rates and shares are given in percent.
+// The risk score ranges from 0 (no slashings, many independent operators) to 100.
+type ApiPoolRiskResponse struct {
+	Day                   uint64  `json:"day" db:"day"`
+	Pool                  string  `json:"pool" db:"pool"`
+	Validators            uint64  `json:"validators" db:"validators"`
+	ValidatorsTotal       uint64  `json:"validators_total" db:"validators_total"`
+	SlashedValidators     uint64  `json:"slashed_validators" db:"slashed_validators"`
+	SlashedValidators1y   uint64  `json:"slashed_validators_1y" db:"slashed_validators_1y"`
+	SlashingRate          float64 `json:"slashing_rate" db:"slashing_rate"`
+	Operators             uint64  `json:"operators" db:"operators"`
+	MaxOperatorValidators uint64  `json:"max_operator_validators" db:"max_operator_validators"`
+	OperatorConcentration float64 `json:"operator_concentration" db:"operator_concentration"`
+	CorrelationPenalty    float64 `json:"correlation_penalty" db:"correlation_penalty"`
+	RiskScore             float64 `json:"risk_score" db:"risk_score"`
+}
+
+// ApiValidatorLuckResponse compares the actual proposal and sync committee assignments of a validator with the amount
+// expected from its share of the effective balance of all active validators, a luck of 1 means exactly as many
+// assignments as expected
+type ApiValidatorLuckResponse struct {
+	ValidatorIndex    uint64                     `json:"validatorindex"`
+	Proposals         uint64                     `json:"proposals"`
+	ExpectedProposals float64                    `json:"expected_proposals"`
+	ProposalLuck      float64                    `json:"proposal_luck"`
+	SyncSlots         uint64                     `json:"sync_slots"`
+	ExpectedSyncSlots float64                    `json:"expected_sync_slots"`
+	SyncLuck          float64                    `json:"sync_luck"`
+	History           []*ApiValidatorLuckHistory `json:"history"`
+}
+
+type ApiValidatorLuckHistory struct {
+	Day               uint64    `json:"day" db:"day"`
+	DayStart          time.Time `json:"day_start" db:"-"`
+	Proposals         uint64    `json:"proposals" db:"proposals"`
+	ExpectedProposals float64   `json:"expected_proposals" db:"expected_proposals"`
+	SyncSlots         uint64    `json:"sync_slots" db:"sync_slots"`
+	ExpectedSyncSlots float64   `json:"expected_sync_slots" db:"expected_sync_slots"`
+}
+
+// ApiComparisonResponse is the performance of a validator or an entity over the compared days. Income is the average
+// income per validator and day in ETH, effectiveness the average daily effectiveness score, uptime the share of
+// attestations that were not missed.
+type ApiComparisonResponse struct {
+	Subject           string                  `json:"subject"`
+	Type              string                  `json:"type"`
+	Name              string                  `json:"name"`
+	Validators        uint64                  `json:"validators"`
+	Income            float64                 `json:"income"`
+	Effectiveness     float64                 `json:"effectiveness"`
+	Proposals         uint64                  `json:"proposals"`
+	ExpectedProposals float64                 `json:"expected_proposals"`
+	ProposalLuck      float64                 `json:"proposal_luck"`
+	Uptime            float64                 `json:"uptime"`
+	History           []*ApiComparisonHistory `json:"history"`
+}
+
+// ApiComparisonHistory is the performance of a compared validator or entity on a single day, the proposal luck is
+// accumulated since the first compared day as a single day rarely contains a proposal
+type ApiComparisonHistory struct {
+	Day           uint64    `json:"day"`
+	DayStart      time.Time `json:"day_start"`
+	Validators    uint64    `json:"validators"`
+	Income        float64   `json:"income"`
+	Effectiveness *float64  `json:"effectiveness"`
+	ProposalLuck  float64   `json:"proposal_luck"`
+	Uptime        float64   `json:"uptime"`
+}
+
+// ApiDutyCalendarDay is the duty performance of a set of validators on a day. The efficiencies are ratios between 0 and 1,
+// the proposal efficiency is nil on days without proposals.
+type ApiDutyCalendarDay struct {
+	Day                   uint64    `json:"day"`
+	DayStart              time.Time `json:"day_start"`
+	ActiveValidators      uint64    `json:"active_validators"`
+	MissedAttestations    uint64    `json:"missed_attestations"`
+	ProposedBlocks        uint64    `json:"proposed_blocks"`
+	MissedBlocks          uint64    `json:"missed_blocks"`
+	AttestationEfficiency float64   `json:"attestation_efficiency"`
+	ProposalEfficiency    *float64  `json:"proposal_efficiency"`
+}
+
+// ApiAppWidgetResponse is the compact summary of the saved validators of a user for home screen widgets, amounts are in ETH
+type ApiAppWidgetResponse struct {
+	Validators uint64            `json:"validators"`
+	Active     uint64            `json:"active"`
+	Offline    uint64            `json:"offline"`
+	Balance    float64           `json:"balance"`
+	Income24h  float64           `json:"income_24h"`
+	NextDuty   *ApiAppWidgetDuty `json:"next_duty,omitempty"`
+	Epoch      uint64            `json:"epoch"`
+}
+
+// ApiAppWidgetDuty is the next proposal or sync committee duty of a validator, for sync committees the slot is the start of
+// the period or the current slot if the period is ongoing
+type ApiAppWidgetDuty struct {
+	Type      string `json:"type"`
+	Validator uint64 `json:"validator"`
+	Slot      uint64 `json:"slot"`
+	Ts        int64  `json:"ts"`
+}
+
+// ApiTaxReportResponse is the income of a set of validators over a tax year valued at the price of the day it was earned.
+// Withdrawals of consensus rewards are matched against the income lots using the cost basis method of the report.
+type ApiTaxReportResponse struct {
+	Year               int                `json:"year"`
+	Currency           string             `json:"currency"`
+	CostBasisMethod    string             `json:"cost_basis_method"`
+	Validators         []uint64           `json:"validators"`
+	ClIncome           float64            `json:"cl_income"`
+	ElIncome           float64            `json:"el_income"`
+	IncomeValue        float64            `json:"income_value"`
+	Withdrawn          float64            `json:"withdrawn"`
+	WithdrawnCostBasis float64            `json:"withdrawn_cost_basis"`
+	RemainingCostBasis float64            `json:"remaining_cost_basis"`
+	Days               []*ApiTaxReportDay `json:"days"`
+}
+
+type ApiTaxReportDay struct {
+	Day                uint64    `json:"day" db:"day"`
+	Date               time.Time `json:"date" db:"-"`
+	ClIncomeGwei       int64     `json:"-" db:"cl_rewards_gwei"`
+	ElIncomeWei        float64   `json:"-" db:"el_rewards_wei"`
+	WithdrawalsGwei    int64     `json:"-" db:"withdrawals_amount"`
+	ClIncome           float64   `json:"cl_income" db:"-"`
+	ElIncome           float64   `json:"el_income" db:"-"`
+	Price              float64   `json:"price" db:"price"`
+	IncomeValue        float64   `json:"income_value" db:"-"`
+	Withdrawn          float64   `json:"withdrawn" db:"-"`
+	WithdrawnCostBasis float64   `json:"withdrawn_cost_basis" db:"-"`
+}
+
+// ApiPriceResponse is the price of one unit of the main currency of the network in the requested currency, either the
+// latest price or the price of a past day
+type ApiPriceResponse struct {
+	Base     string     `json:"base"`
+	Currency string     `json:"currency"`
+	Symbol   string     `json:"symbol"`
+	Price    float64    `json:"price"`
+	Date     *time.Time `json:"date,omitempty"`
+}
+
+// UserValidatorDashboard is a named dashboard of a user, its validators are organized in named groups. Shares are the
+// read-only public links of the dashboard.
+type UserValidatorDashboard struct {
+	Id        uint64    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	// OrganizationId is the organization the dashboard is shared with, if any
+	OrganizationId *uint64                        `json:"organization_id,omitempty" db:"organization_id"`
+	Groups         []*UserValidatorDashboardGroup `json:"groups"`
+	Shares         []*UserValidatorDashboardShare `json:"shares,omitempty"`
+}
+
+type UserValidatorDashboardGroup struct {
+	Id         uint64   `json:"id" db:"id"`
+	Name       string   `json:"name" db:"name"`
+	Validators []uint64 `json:"validators" db:"-"`
+}
+
+// ApiValidatorKeysImportResponse is the result of importing validators from deposit data or keystore files. Pubkeys
+// without a validator index (not yet deposited or processed) are added to the watchlist but not to dashboards.
+type ApiValidatorKeysImportResponse struct {
+	Pubkeys     int      `json:"pubkeys"`
+	Validators  []uint64 `json:"validators"`
+	Unknown     []string `json:"unknown_pubkeys"`
+	Added       int      `json:"added"`
+	DashboardId *uint64  `json:"dashboard_id,omitempty"`
+}
+
+// UserValidatorDashboardGroupStats aggregates the performance of the validators of a dashboard group over the last days.
+// The efficiencies are ratios between 0 and 1, the proposal and sync efficiency are nil if the group had no such duties.
+type UserValidatorDashboardGroupStats struct {
+	Id                    uint64   `json:"id" db:"id"`
+	Name                  string   `json:"name" db:"name"`
+	Validators            uint64   `json:"validators" db:"validators"`
+	ActiveValidators      uint64   `json:"active_validators" db:"active_validators"`
+	SlashedValidators     uint64   `json:"slashed_validators" db:"slashed_validators"`
+	OfflineValidators     []uint64 `json:"offline_validators" db:"-"`
+	ActiveDays            uint64   `json:"-" db:"active_days"`
+	MissedAttestations    uint64   `json:"missed_attestations" db:"missed_attestations"`
+	ProposedBlocks        uint64   `json:"proposed_blocks" db:"proposed_blocks"`
+	MissedBlocks          uint64   `json:"missed_blocks" db:"missed_blocks"`
+	ParticipatedSync      uint64   `json:"participated_sync" db:"participated_sync"`
+	MissedSync            uint64   `json:"missed_sync" db:"missed_sync"`
+	AttestationEfficiency float64  `json:"attestation_efficiency" db:"-"`
+	ProposalEfficiency    *float64 `json:"proposal_efficiency" db:"-"`
+	SyncEfficiency        *float64 `json:"sync_efficiency" db:"-"`
+	ClIncomeGwei          int64    `json:"-" db:"cl_rewards_gwei"`
+	ElIncomeWei           float64  `json:"-" db:"el_rewards_wei"`
+	ClIncome              float64  `json:"cl_income" db:"-"`
+	ElIncome              float64  `json:"el_income" db:"-"`
+	Alerts                []string `json:"alerts" db:"-"`
+}
+
+// UserValidatorDashboardDayStats is the daily rollup of the validator stats of all validators of a dashboard
+type UserValidatorDashboardDayStats struct {
+	Day                 uint64  `db:"day"`
+	Validators          uint64  `db:"validators"`
+	StartBalance        int64   `db:"start_balance"`
+	EndBalance          int64   `db:"end_balance"`
+	EndEffectiveBalance int64   `db:"end_effective_balance"`
+	DepositsAmount      int64   `db:"deposits_amount"`
+	WithdrawalsAmount   int64   `db:"withdrawals_amount"`
+	ClRewardsGwei       int64   `db:"cl_rewards_gwei"`
+	ElRewardsWei        float64 `db:"el_rewards_wei"`
+	ActiveDays          uint64  `db:"active_days"`
+	MissedAttestations  uint64  `db:"missed_attestations"`
+	ProposedBlocks      uint64  `db:"proposed_blocks"`
+	MissedBlocks        uint64  `db:"missed_blocks"`
+	ParticipatedSync    uint64  `db:"participated_sync"`
+	MissedSync          uint64  `db:"missed_sync"`
+}
+
+// UserValidatorDashboardSummary is the server side aggregation of all validators of a dashboard, all amounts are in ETH.
+// The efficiencies cover the last 7 days, the history charts hold one data point per day (x is the unix timestamp in ms).
+type UserValidatorDashboardSummary struct {
+	Validators            uint64            `json:"validators" db:"validators"`
+	ActiveValidators      uint64            `json:"active_validators" db:"active_validators"`
+	PendingValidators     uint64            `json:"pending_validators" db:"pending_validators"`
+	ExitedValidators      uint64            `json:"exited_validators" db:"exited_validators"`
+	SlashedValidators     uint64            `json:"slashed_validators" db:"slashed_validators"`
+	Balance               float64           `json:"balance" db:"-"`
+	EffectiveBalance      float64           `json:"effective_balance" db:"-"`
+	Income1d              float64           `json:"income_1d" db:"-"`
+	Income7d              float64           `json:"income_7d" db:"-"`
+	Income31d             float64           `json:"income_31d" db:"-"`
+	IncomeTotal           float64           `json:"income_total" db:"-"`
+	AttestationEfficiency float64           `json:"attestation_efficiency" db:"-"`
+	ProposalEfficiency    *float64          `json:"proposal_efficiency" db:"-"`
+	SyncEfficiency        *float64          `json:"sync_efficiency" db:"-"`
+	BalanceHistory        []*ChartDataPoint `json:"balance_history" db:"-"`
+	IncomeHistory         []*ChartDataPoint `json:"income_history" db:"-"`
+}
+
+type ApiWithdrawalAddressDashboardResponse struct {
+	Address string                         `json:"address"`
+	Summary *UserValidatorDashboardSummary `json:"summary"`
+}
+
+// UserValidatorDashboardChart is a custom chart of a validator dashboard. The metric is aggregated per day over the
+// validators of the selected groups (all groups if none are selected), either as one series or as one series per group.
+type UserValidatorDashboardChart struct {
+	Id          uint64    `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	Metric      string    `json:"metric" db:"metric"`
+	Aggregation string    `json:"aggregation" db:"aggregation"`
+	GroupIds    []uint64  `json:"group_ids" db:"-"`
+	PerGroup    bool      `json:"per_group" db:"per_group"`
+	Days        uint64    `json:"days" db:"days"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+type UserValidatorDashboardShare struct {
+	PublicId string `json:"public_id" db:"public_id"`
+	Name     string `json:"name" db:"name"`
+}
+
+// ApiStakingCalculatorResponse is the projection of the rewards of a stake over a horizon of days, all amounts are in ETH.
+// The rewards of 0x01 validators are skimmed, the consensus rewards of 0x02 validators compound up to the maximum effective balance.
+type ApiStakingCalculatorResponse struct {
+	Amount            float64                              `json:"amount"`
+	CredentialType    string                               `json:"credential_type"`
+	Days              uint64                               `json:"days"`
+	Validators        uint64                               `json:"validators"`
+	StakedAmount      float64                              `json:"staked_amount"`
+	ExpectedProposals float64                              `json:"expected_proposals"`
+	ConsensusRewards  float64                              `json:"consensus_rewards"`
+	ExecutionRewards  float64                              `json:"execution_rewards"`
+	MevRewards        float64                              `json:"mev_rewards"`
+	TotalRewards      float64                              `json:"total_rewards"`
+	ProjectedApr      float64                              `json:"projected_apr"`
+	Network           *StakingCalculatorNetworkStats       `json:"network"`
+	Projection        []*ApiStakingCalculatorProjectionDay `json:"projection"`
+}
+
+// ApiStakingCalculatorProjectionDay contains the cumulative rewards until a day of the projection
+type ApiStakingCalculatorProjectionDay struct {
+	Day              uint64  `json:"day"`
+	Balance          float64 `json:"balance"`
+	ConsensusRewards float64 `json:"consensus_rewards"`
+	ExecutionRewards float64 `json:"execution_rewards"`
+}
+
+// ApiValidatorSetProjectionResponse is the projection of the validator set under a scenario of daily entering and exiting
+// validators, aprs are fractions and amounts are in ETH
+type ApiValidatorSetProjectionResponse struct {
+	DailyInflow         float64                         `json:"daily_inflow"`
+	DailyOutflow        float64                         `json:"daily_outflow"`
+	Days                uint64                          `json:"days"`
+	AvgEffectiveBalance float64                         `json:"avg_effective_balance"`
+	Projection          []*ApiValidatorSetProjectionDay `json:"projection"`
+}
+
+// ApiValidatorSetProjectionDay is the projected state of the validator set at the end of a day, the queues are the
+// validators still waiting and the wait times the days a validator joining the queue on that day has to wait
+type ApiValidatorSetProjectionDay struct {
+	Day                  uint64  `json:"day"`
+	Ts                   int64   `json:"ts"`
+	ActiveValidators     uint64  `json:"active_validators"`
+	StakedAmount         float64 `json:"staked_amount"`
+	EntryQueue           uint64  `json:"entry_queue"`
+	ExitQueue            uint64  `json:"exit_queue"`
+	ActivationChurnLimit uint64  `json:"activation_churn_limit"`
+	ExitChurnLimit       uint64  `json:"exit_churn_limit"`
+	EntryWaitDays        float64 `json:"entry_wait_days"`
+	ExitWaitDays         float64 `json:"exit_wait_days"`
+	ConsensusApr         float64 `json:"consensus_apr"`
+	ExecutionApr         float64 `json:"execution_apr"`
+	Apr                  float64 `json:"apr"`
+	AnnualIssuance       float64 `json:"annual_issuance"`
+}
+
+// ApiChainForkResponse is a fork of the chain spec. Status is active, scheduled or unscheduled (the fork is specified
+// but has no activation epoch yet). The readiness of the clients is only included for forks which are not active yet.
+type ApiChainForkResponse struct {
+	Name           string     `json:"name"`
+	Version        string     `json:"version"`
+	Epoch          *uint64    `json:"epoch"`
+	ActivationTime *time.Time `json:"activation_time"`
+	Status         string     `json:"status"`
+	// VersionedBlocks are the blocks of the last 7 days whose graffiti contains a client version
+	VersionedBlocks uint64 `json:"versioned_blocks,omitempty"`
+	// ReadyBlocksShare is the share of the versioned blocks proposed with a client release supporting the fork in percent
+	ReadyBlocksShare *float64                      `json:"ready_blocks_share,omitempty"`
+	Clients          []*ApiChainForkClientResponse `json:"clients,omitempty"`
+}
+
+type ApiChainForkClientResponse struct {
+	Client string `json:"client"`
+	// MinRelease is the first release of the client supporting the fork, empty if unknown
+	MinRelease    string `json:"min_release"`
+	LatestRelease string `json:"latest_release"`
+	Blocks        uint64 `json:"blocks"`
+	ReadyBlocks   uint64 `json:"ready_blocks"`
+}
+
+// ApiNetworkIncidentResponse is a period of epochs in which a network metric deviated significantly from its baseline.
+// Value is the most anomalous value of the metric during the incident and Baseline the expected value at that time.
+type ApiNetworkIncidentResponse struct {
+	Id         uint64    `json:"id" db:"id"`
+	Metric     string    `json:"metric" db:"metric"`
+	StartEpoch uint64    `json:"start_epoch" db:"start_epoch"`
+	EndEpoch   uint64    `json:"end_epoch" db:"end_epoch"`
+	Value      float64   `json:"value" db:"value"`
+	Baseline   float64   `json:"baseline" db:"baseline"`
+	ZScore     float64   `json:"z_score" db:"z_score"`
+	DetectedTs time.Time `json:"detected_ts" db:"detected_ts"`
+}
+
+// ApiChartSeriesResponse contains the points of a chart series within a time range
+type ApiChartSeriesResponse struct {
+	Series     string              `json:"series"`
+	Resolution string              `json:"resolution"`
+	From       int64               `json:"from"`
+	To         int64               `json:"to"`
+	Points     []*ChartSeriesPoint `json:"points"`
+}
+
+type ChartSeriesPoint struct {
+	Time  time.Time `json:"time" db:"time"`
+	Value float64   `json:"value" db:"value"`
+}
+
 type ApiValidatorQueueResponse struct {
 	BeaconchainEntering uint64 `json:"beaconchain_entering"`
 	BeaconchainExiting  uint64 `json:"beaconchain_exiting"`
@@ -545,6 +1136,37 @@ type ApiValidatorEth1Response struct {
 	ValidatorIndex uint64 `json:"validator_index"`
 }
 
+type ApiValidatorNameResponse struct {
+	ValidatorIndex uint64 `json:"validatorindex"`
+	PublicKey      string `json:"pubkey"`
+	Name           string `json:"name"`
+	ProofType      string `json:"proof_type,omitempty"`
+	SignerAddress  string `json:"signer_address,omitempty"`
+}
+
+type ApiValidatorDepositOriginResponse struct {
+	ValidatorIndex *uint64  `json:"validatorindex"`
+	PublicKey      string   `json:"pubkey"`
+	DepositAddress string   `json:"deposit_address"`
+	OriginKind     string   `json:"origin_kind"`
+	OriginAddress  string   `json:"origin_address,omitempty"`
+	OriginLabel    string   `json:"origin_label,omitempty"`
+	Hops           uint64   `json:"hops"`
+	Path           []string `json:"path"`
+	ExchangeFunded bool     `json:"exchange_funded"`
+	Traced         bool     `json:"traced"`
+}
+
+type ApiValidatorEntityResponse struct {
+	ValidatorIndex uint64     `json:"validatorindex" db:"validatorindex"`
+	PublicKey      string     `json:"pubkey" db:"pubkey"`
+	Entity         string     `json:"entity" db:"entity"`
+	Category       string     `json:"category" db:"category"`
+	Source         string     `json:"source" db:"source"`
+	Version        *uint64    `json:"version" db:"version"`
+	UpdatedTs      *time.Time `json:"updated_ts" db:"updated_ts"`
+}
+
 type ApiValidatorIncomeHistoryResponse struct {
 	Income         *ApiValidatorIncomeHistory `json:"income"`
 	Epoch          uint64                     `json:"epoch"`
@@ -582,6 +1204,20 @@ type ApiValidatorBalanceHistoryResponse struct {
 	WeekEnd          time.Time `json:"week_end"`
 }
 
+// ApiValidatorBalanceDeltasResponse are the delta encoded per-epoch balances of a validator in gwei. Balance is the
+// balance at FromEpoch, the n-th delta is the difference of the balance at FromEpoch+n*Step to the previous step.
+// EffectiveBalanceChanges are pairs of the index of the step the effective balance changed at and the new effective
+// balance, starting from EffectiveBalance.
+type ApiValidatorBalanceDeltasResponse struct {
+	ValidatorIndex          uint64      `json:"validatorindex"`
+	FromEpoch               uint64      `json:"from_epoch"`
+	Step                    uint64      `json:"step"`
+	Balance                 uint64      `json:"balance"`
+	EffectiveBalance        uint64      `json:"effective_balance"`
+	BalanceDeltas           []int64     `json:"balance_deltas"`
+	EffectiveBalanceChanges [][2]uint64 `json:"effective_balance_changes"`
+}
+
 type ApiValidatorWithdrawalResponse struct {
 	Epoch          uint64 `json:"epoch,omitempty"`
 	Slot           uint64 `json:"slot,omitempty"`
@@ -604,6 +1240,154 @@ type ApiValidatorBlsChangeResponse struct {
 	WithdrawalCredentialsNew string `db:"withdrawalcredentials_0x01" json:"withdrawalcredentials_0x01,omitempty"`
 }
 
+type ApiValidatorCredentialChangeResponse struct {
+	Validatorindex           uint64 `json:"validatorindex"`
+	Epoch                    uint64 `json:"epoch"`
+	Slot                     uint64 `json:"slot"`
+	Timestamp                int64  `json:"timestamp"`
+	BlockRoot                string `json:"blockroot"`
+	Finalized                bool   `json:"finalized"`
+	BlsPubkey                string `json:"bls_pubkey"`
+	Signature                string `json:"bls_signature"`
+	Address                  string `json:"address"`
+	WithdrawalCredentialsOld string `json:"withdrawalcredentials_old"`
+	WithdrawalCredentialsNew string `json:"withdrawalcredentials_new"`
+}
+
+// ApiValidatorSetSnapshotResponse is the validator set as it was stored in the latest snapshot at or before the requested epoch
+type ApiValidatorSetSnapshotResponse struct {
+	Epoch         uint64                          `json:"epoch"`
+	SnapshotEpoch uint64                          `json:"snapshot_epoch"`
+	Validators    []*ApiValidatorSnapshotResponse `json:"validators"`
+}
+
+type ApiValidatorSnapshotResponse struct {
+	Validatorindex             uint64 `json:"validatorindex" db:"validatorindex"`
+	Pubkey                     string `json:"pubkey" db:"pubkey"`
+	Status                     string `json:"status" db:"status"`
+	Balance                    uint64 `json:"balance" db:"balance"`
+	Effectivebalance           uint64 `json:"effectivebalance" db:"effectivebalance"`
+	Withdrawalcredentials      string `json:"withdrawalcredentials" db:"withdrawalcredentials"`
+	Slashed                    bool   `json:"slashed" db:"slashed"`
+	Activationeligibilityepoch int64  `json:"activationeligibilityepoch" db:"activationeligibilityepoch"`
+	Activationepoch            int64  `json:"activationepoch" db:"activationepoch"`
+	Exitepoch                  int64  `json:"exitepoch" db:"exitepoch"`
+	Withdrawableepoch          int64  `json:"withdrawableepoch" db:"withdrawableepoch"`
+}
+
+// ApiSlotVizEpochResponse is an epoch of the slot visualization, the most recent epoch comes first
+type ApiBlockProductionResponse struct {
+	Slot          uint64 `json:"slot"`
+	BlockRoot     string `json:"blockroot"`
+	ExecBlockHash string `json:"exec_block_hash"`
+	Proposer      uint64 `json:"proposer"`
+	LocallyBuilt  bool   `json:"locally_built"`
+	Relay         string `json:"relay,omitempty"`
+	BuilderPubkey string `json:"builder_pubkey,omitempty"`
+	// DeliveredValue is the value the relay reported for the delivered payload in wei
+	DeliveredValue string `json:"delivered_value"`
+	// FeeRecipientReward is the reward of the fee recipient of the execution payload in wei
+	FeeRecipientReward string `json:"fee_recipient_reward"`
+	// RealizedValue is the value the proposer received in wei
+	RealizedValue           string `json:"realized_value"`
+	WinningBidRelay         string `json:"winning_bid_relay,omitempty"`
+	WinningBidBuilderPubkey string `json:"winning_bid_builder_pubkey,omitempty"`
+	WinningBidBlockHash     string `json:"winning_bid_block_hash,omitempty"`
+	WinningBidValue         string `json:"winning_bid_value,omitempty"`
+	MissedValue             string `json:"missed_value"`
+}
+
+type ApiSlotVizEpochResponse struct {
+	Epoch         uint64                    `json:"epoch"`
+	Finalized     bool                      `json:"finalized"`
+	Justified     bool                      `json:"justified"`
+	Justifying    bool                      `json:"justifying"`
+	Participation float64                   `json:"participation"`
+	Slots         []*ApiSlotVizSlotResponse `json:"slots"`
+	// SyncCommittee lists the validators of the authenticated user that are part of the sync committee during the epoch
+	SyncCommittee []uint64 `json:"sync_committee,omitempty"`
+}
+
+type ApiSlotVizSlotResponse struct {
+	Slot   uint64 `json:"slot"`
+	Status string `json:"status"`
+	Active bool   `json:"active"`
+	// Proposers lists the validators of the authenticated user that are scheduled to propose the slot
+	Proposers []uint64 `json:"proposers,omitempty"`
+}
+
+type ApiEffectivenessScoreFormulaResponse struct {
+	Version     uint64                    `json:"version"`
+	Weights     EffectivenessScoreWeights `json:"weights"`
+	Description string                    `json:"description"`
+}
+
+type ApiValidatorWithdrawalEstimateResponse struct {
+	Validatorindex uint64 `json:"validatorindex"`
+	// Eligible is false if the validator has no execution withdrawal credentials or no excess balance to withdraw
+	Eligible           bool    `json:"eligible"`
+	SweepCursor        uint64  `json:"sweep_cursor"`
+	SweepPosition      uint64  `json:"sweep_position"`
+	EstimatedEpoch     uint64  `json:"estimated_epoch,omitempty"`
+	EstimatedSlot      uint64  `json:"estimated_slot,omitempty"`
+	EstimatedTimestamp int64   `json:"estimated_timestamp,omitempty"`
+	EstimatedDays      float64 `json:"estimated_days,omitempty"`
+	Amount             uint64  `json:"amount"`
+	FullWithdrawal     bool    `json:"full_withdrawal"`
+	Address            string  `json:"address,omitempty"`
+}
+
+type ApiValidatorFeeRecipientResponse struct {
+	Validatorindex  uint64 `json:"validatorindex"`
+	Slot            uint64 `json:"slot"`
+	ExecBlockNumber uint64 `json:"exec_block_number"`
+	FeeRecipient    string `json:"fee_recipient"`
+	// Relayed is set if the block was built by a relay, the fee recipient is the one registered with the relay
+	Relayed bool `json:"relayed"`
+}
+
+type ApiValidatorRelayRegistrationResponse struct {
+	Validatorindex uint64 `json:"validatorindex"`
+	Pubkey         string `json:"pubkey"`
+	Relay          string `json:"relay"`
+	Registered     bool   `json:"registered"`
+	// FeeRecipient, GasLimit and RegistrationTs are those of the last registration if the validator is no longer registered
+	FeeRecipient   string `json:"fee_recipient,omitempty"`
+	GasLimit       int64  `json:"gas_limit,omitempty"`
+	RegistrationTs int64  `json:"registration_ts,omitempty"`
+	CheckedTs      int64  `json:"checked_ts"`
+	UnregisteredTs int64  `json:"unregistered_ts,omitempty"`
+}
+
+type ApiUserExpectedFeeRecipientResponse struct {
+	Pubkey       string `json:"pubkey"`
+	FeeRecipient string `json:"fee_recipient"`
+	CreatedTs    int64  `json:"created_ts"`
+}
+
+type ApiValidatorExitReadinessResponse struct {
+	Validatorindex uint64 `json:"validatorindex"`
+	// CredentialType is bls or execution, the balance of validators with bls credentials can not be withdrawn
+	CredentialType          string   `json:"credential_type"`
+	ExitInitiated           bool     `json:"exit_initiated"`
+	CanExit                 bool     `json:"can_exit"`
+	Blockers                []string `json:"blockers"`
+	Warnings                []string `json:"warnings"`
+	EligibleEpoch           uint64   `json:"eligible_epoch"`
+	ScheduledProposals      []uint64 `json:"scheduled_proposals"`
+	SyncCommitteePeriods    []uint64 `json:"sync_committee_periods"`
+	SyncCommitteeEnd        uint64   `json:"sync_committee_end_epoch,omitempty"`
+	ExitQueueLength         uint64   `json:"exit_queue_length"`
+	ExitEpoch               uint64   `json:"exit_epoch"`
+	ExitTimestamp           int64    `json:"exit_timestamp"`
+	WithdrawableEpoch       uint64   `json:"withdrawable_epoch"`
+	WithdrawableTimestamp   int64    `json:"withdrawable_timestamp"`
+	FullWithdrawalTimestamp int64    `json:"full_withdrawal_timestamp,omitempty"`
+	Balance                 uint64   `json:"balance"`
+	RewardsPerDay           int64    `json:"rewards_per_day"`
+	RewardsUntilExit        int64    `json:"rewards_until_exit"`
+}
+
 type ApiValidatorPerformanceResponse struct {
 	Balance         uint64 `json:"balance"`
 	Performance1d   uint64 `json:"performance1d"`
@@ -700,3 +1484,67 @@ type ApiProposalLuckResponse struct {
 	NextProposalEstimateTs  *int64   `json:"next_proposal_estimate_ts"` // The estimated timestamp of the next proposal
 	TimeFrameName           *string  `json:"time_frame_name"`           // The timeframe for which the luck is calculated
 }
+
+// ConsistencyReport is the result of a run of the consistency audit, comparing the stored data of sampled epochs and
+// slots with the data of the beacon and execution nodes
+type ConsistencyReport struct {
+	Ts         time.Time             `json:"ts"`
+	Epochs     []uint64              `json:"epochs"`
+	Slots      []uint64              `json:"slots"`
+	Mismatches []ConsistencyMismatch `json:"mismatches"`
+}
+
+type ConsistencyMismatch struct {
+	Check     string  `json:"check"`  // balance, rewards, block, attestations_count, deposits_count or tx_count
+	Source    string  `json:"source"` // the store holding the mismatching value, postgres or bigtable
+	Epoch     uint64  `json:"epoch"`
+	Slot      *uint64 `json:"slot,omitempty"`
+	Validator *uint64 `json:"validator,omitempty"`
+	Stored    string  `json:"stored"`
+	Expected  string  `json:"expected"`
+}
+
+// ExporterHeads are the positions of the exporters, compared to the head of the node
+type ExporterHeads struct {
+	NodeHeadEpoch        uint64 `db:"node_head_epoch" json:"node_head_epoch"`
+	NodeFinalizedEpoch   uint64 `db:"node_finalized_epoch" json:"node_finalized_epoch"`
+	LatestEpoch          uint64 `db:"latest_epoch" json:"latest_epoch"`
+	LatestFinalizedEpoch uint64 `db:"latest_finalized_epoch" json:"latest_finalized_epoch"`
+	LatestSlot           uint64 `db:"latest_slot" json:"latest_slot"`
+	LatestStatisticsDay  *int64 `db:"latest_statistics_day" json:"latest_statistics_day"`
+	Eth1BlocksTable      int    `json:"eth1_blocks_table"`
+	Eth1DataTable        int    `json:"eth1_data_table"`
+}
+
+// AdminReexportRequest queues the range from to to (inclusive) of the kind for re-export
+type AdminReexportRequest struct {
+	Kind string `json:"kind"` // slot, epoch, eth1_block or eth1_data
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+// AdminMailTestRequest sends a notification email with example notifications of the events to an address
+type AdminMailTestRequest struct {
+	To     string      `json:"to"`
+	Locale string      `json:"locale"`
+	Events []EventName `json:"events"`
+}
+
+// DrainStatus lists the background services paused by a drain
+// ExporterProgress is the progress of an exporter, it is reported as metadata of the service status of the exporter.
+// Head and ChainHead are in Unit (slot, epoch, block or day), HeadRate is the rate Head advanced with in units per
+// second and RowsPerSecond the throughput over the last reporting window.
+type ExporterProgress struct {
+	Unit          string    `json:"unit"`
+	Head          uint64    `json:"head"`
+	ChainHead     uint64    `json:"chain_head"`
+	Lag           uint64    `json:"lag"`
+	HeadRate      float64   `json:"head_rate"`
+	RowsPerSecond float64   `json:"rows_per_second"`
+	Ts            time.Time `json:"ts"`
+}
+
+type DrainStatus struct {
+	Draining bool     `json:"draining"`
+	Paused   []string `json:"paused"`
+}
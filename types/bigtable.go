@@ -1,10 +1,12 @@
 package types
 
 import (
+	"fmt"
 	"math/big"
 	"time"
 
 	gcp_bigtable "cloud.google.com/go/bigtable"
+	btpb "google.golang.org/genproto/googleapis/bigtable/v2"
 )
 
 type ValidatorBalanceStatistic struct {
@@ -57,19 +59,99 @@ type GasNowHistory struct {
 	Rapid    *big.Int
 }
 
+// Mutation is a bigtable mutation that also records its operations. The bigtable client does not expose the operations
+// of its mutations, they are needed to persist mutations that could not be applied and replay them later.
+// Conditional mutations are not supported.
+type Mutation struct {
+	*gcp_bigtable.Mutation
+	Ops []*btpb.Mutation
+}
+
+func NewMutation() *Mutation {
+	return &Mutation{Mutation: gcp_bigtable.NewMutation()}
+}
+
+// NewMutationFromOps builds the mutation of previously recorded operations, it fails on unknown operations
+func NewMutationFromOps(ops []*btpb.Mutation) (*Mutation, error) {
+	mut := NewMutation()
+	for _, op := range ops {
+		switch m := op.Mutation.(type) {
+		case *btpb.Mutation_SetCell_:
+			mut.Set(m.SetCell.FamilyName, string(m.SetCell.ColumnQualifier), gcp_bigtable.Timestamp(m.SetCell.TimestampMicros), m.SetCell.Value)
+		case *btpb.Mutation_DeleteFromColumn_:
+			if r := m.DeleteFromColumn.TimeRange; r != nil {
+				mut.DeleteTimestampRange(m.DeleteFromColumn.FamilyName, string(m.DeleteFromColumn.ColumnQualifier), gcp_bigtable.Timestamp(r.StartTimestampMicros), gcp_bigtable.Timestamp(r.EndTimestampMicros))
+			} else {
+				mut.DeleteCellsInColumn(m.DeleteFromColumn.FamilyName, string(m.DeleteFromColumn.ColumnQualifier))
+			}
+		case *btpb.Mutation_DeleteFromFamily_:
+			mut.DeleteCellsInFamily(m.DeleteFromFamily.FamilyName)
+		case *btpb.Mutation_DeleteFromRow_:
+			mut.DeleteRow()
+		default:
+			return nil, fmt.Errorf("unsupported bigtable mutation operation %T", op.Mutation)
+		}
+	}
+	return mut, nil
+}
+
+// The operations are recorded the same way the bigtable client builds them
+
+func (m *Mutation) Set(family, column string, ts gcp_bigtable.Timestamp, value []byte) {
+	m.Mutation.Set(family, column, ts, value)
+	m.Ops = append(m.Ops, &btpb.Mutation{Mutation: &btpb.Mutation_SetCell_{SetCell: &btpb.Mutation_SetCell{
+		FamilyName:      family,
+		ColumnQualifier: []byte(column),
+		TimestampMicros: int64(ts.TruncateToMilliseconds()),
+		Value:           value,
+	}}})
+}
+
+func (m *Mutation) DeleteCellsInColumn(family, column string) {
+	m.Mutation.DeleteCellsInColumn(family, column)
+	m.Ops = append(m.Ops, &btpb.Mutation{Mutation: &btpb.Mutation_DeleteFromColumn_{DeleteFromColumn: &btpb.Mutation_DeleteFromColumn{
+		FamilyName:      family,
+		ColumnQualifier: []byte(column),
+	}}})
+}
+
+func (m *Mutation) DeleteTimestampRange(family, column string, start, end gcp_bigtable.Timestamp) {
+	m.Mutation.DeleteTimestampRange(family, column, start, end)
+	m.Ops = append(m.Ops, &btpb.Mutation{Mutation: &btpb.Mutation_DeleteFromColumn_{DeleteFromColumn: &btpb.Mutation_DeleteFromColumn{
+		FamilyName:      family,
+		ColumnQualifier: []byte(column),
+		TimeRange: &btpb.TimestampRange{
+			StartTimestampMicros: int64(start.TruncateToMilliseconds()),
+			EndTimestampMicros:   int64(end.TruncateToMilliseconds()),
+		},
+	}}})
+}
+
+func (m *Mutation) DeleteCellsInFamily(family string) {
+	m.Mutation.DeleteCellsInFamily(family)
+	m.Ops = append(m.Ops, &btpb.Mutation{Mutation: &btpb.Mutation_DeleteFromFamily_{DeleteFromFamily: &btpb.Mutation_DeleteFromFamily{
+		FamilyName: family,
+	}}})
+}
+
+func (m *Mutation) DeleteRow() {
+	m.Mutation.DeleteRow()
+	m.Ops = append(m.Ops, &btpb.Mutation{Mutation: &btpb.Mutation_DeleteFromRow_{DeleteFromRow: &btpb.Mutation_DeleteFromRow{}}})
+}
+
 type BulkMutations struct {
 	Keys []string
-	Muts []*gcp_bigtable.Mutation
+	Muts []*Mutation
 }
 
 func NewBulkMutations(length int) *BulkMutations {
 	return &BulkMutations{
 		Keys: make([]string, 0, length),
-		Muts: make([]*gcp_bigtable.Mutation, 0, length),
+		Muts: make([]*Mutation, 0, length),
 	}
 }
 
-func (bulkMutations *BulkMutations) Add(key string, mut *gcp_bigtable.Mutation) {
+func (bulkMutations *BulkMutations) Add(key string, mut *Mutation) {
 	bulkMutations.Keys = append(bulkMutations.Keys, key)
 	bulkMutations.Muts = append(bulkMutations.Muts, mut)
 }
@@ -89,5 +171,5 @@ func (bulkMutations *BulkMutations) Swap(i, j int) {
 
 type BulkMutation struct {
 	Key string
-	Mut *gcp_bigtable.Mutation
+	Mut *Mutation
 }
@@ -18,6 +18,10 @@ type Config struct {
 		MaxOpenConns int    `yaml:"maxOpenConns" envconfig:"READER_DB_MAX_OPEN_CONNS"`
 		MaxIdleConns int    `yaml:"maxIdleConns" envconfig:"READER_DB_MAX_IDLE_CONNS"`
 		SSL          bool   `yaml:"ssl" envconfig:"READER_DB_SSL"`
+		// Replicas are additional host:port addresses of replicas of the reader database sharing its credentials
+		Replicas []string `yaml:"replicas" envconfig:"READER_DB_REPLICAS"`
+		// MaxReplicationLag is the lag after which a replica no longer serves reads, 0 disables the lag check
+		MaxReplicationLag time.Duration `yaml:"maxReplicationLag" envconfig:"READER_DB_MAX_REPLICATION_LAG"`
 	} `yaml:"readerDatabase"`
 	WriterDatabase struct {
 		Username     string `yaml:"user" envconfig:"WRITER_DB_USERNAME"`
@@ -29,6 +33,11 @@ type Config struct {
 		MaxIdleConns int    `yaml:"maxIdleConns" envconfig:"WRITER_DB_MAX_IDLE_CONNS"`
 		SSL          bool   `yaml:"ssl" envconfig:"WRITER_DB_SSL"`
 	} `yaml:"writerDatabase"`
+	// DbAutoMigrate applies pending db schema migrations on startup of the explorer
+	DbAutoMigrate bool `yaml:"dbAutoMigrate" envconfig:"DB_AUTO_MIGRATE"`
+	// Devnet runs exporter and frontend against a local devnet (e.g. kurtosis), loading the chain spec from the node and
+	// defaulting the storage and frontend settings so only the node, database and redis endpoints need to be set
+	Devnet   bool `yaml:"devnet" envconfig:"DEVNET"`
 	Bigtable struct {
 		Project             string `yaml:"project" envconfig:"BIGTABLE_PROJECT"`
 		Instance            string `yaml:"instance" envconfig:"BIGTABLE_INSTANCE"`
@@ -36,6 +45,7 @@ type Config struct {
 		EmulatorPort        int    `yaml:"emulatorPort" envconfig:"BIGTABLE_EMULATOR_PORT"`
 		EmulatorHost        string `yaml:"emulatorHost" envconfig:"BIGTABLE_EMULATOR_HOST"`
 		V2SchemaCutOffEpoch uint64 `yaml:"v2SchemaCutOffEpoch" envconfig:"BIGTABLE_V2_SCHEMA_CUTT_OFF_EPOCH"`
+		Backend             string `yaml:"backend" envconfig:"BIGTABLE_BACKEND"`
 	} `yaml:"bigtable"`
 	BlobIndexer struct {
 		S3 struct {
@@ -45,6 +55,23 @@ type Config struct {
 			AccessKeySecret string `yaml:"accessKeySecret" envconfig:"BLOB_INDEXER_S3_ACCESS_KEY_SECRET"`
 		} `yaml:"s3"`
 	} `yaml:"blobIndexer"`
+	// DataExport configures the daily dumps of attestations, blocks and validator statistics written by the statistics
+	// exporter to an s3 compatible object store (gcs via its s3 interoperability api) and listed on /data. Downloads are
+	// redirected to presigned urls valid for LinkExpiry.
+	DataExport struct {
+		Enabled bool `yaml:"enabled" envconfig:"DATA_EXPORT_ENABLED"`
+		S3      struct {
+			Endpoint        string `yaml:"endpoint" envconfig:"DATA_EXPORT_S3_ENDPOINT"`
+			Region          string `yaml:"region" envconfig:"DATA_EXPORT_S3_REGION"`
+			Bucket          string `yaml:"bucket" envconfig:"DATA_EXPORT_S3_BUCKET"`
+			AccessKeyId     string `yaml:"accessKeyId" envconfig:"DATA_EXPORT_S3_ACCESS_KEY_ID"`
+			AccessKeySecret string `yaml:"accessKeySecret" envconfig:"DATA_EXPORT_S3_ACCESS_KEY_SECRET"`
+		} `yaml:"s3"`
+		Prefix     string        `yaml:"prefix" envconfig:"DATA_EXPORT_PREFIX"`
+		LinkExpiry time.Duration `yaml:"linkExpiry" envconfig:"DATA_EXPORT_LINK_EXPIRY"`
+		// Formats maps dataset names to the format of their dumps, csv (gzipped) or parquet. Datasets default to csv.
+		Formats map[string]string `yaml:"formats" envconfig:"DATA_EXPORT_FORMATS"`
+	} `yaml:"dataExport"`
 	Chain struct {
 		Name                       string `yaml:"name" envconfig:"CHAIN_NAME"`
 		Id                         uint64 `yaml:"id" envconfig:"CHAIN_ID"`
@@ -56,11 +83,14 @@ type Config struct {
 		ElConfigPath               string `yaml:"elConfigPath" envconfig:"CHAIN_EL_CONFIG_PATH"`
 		ClConfig                   ClChainConfig
 		ElConfig                   *params.ChainConfig
+		// ForkReleases are the first consensus client releases supporting a fork, keyed by the lowercase fork and client name (e.g. deneb: {lighthouse: v4.6.0})
+		ForkReleases map[string]map[string]string `yaml:"forkReleases"`
 	} `yaml:"chain"`
 	Eth1ErigonEndpoint        string `yaml:"eth1ErigonEndpoint" envconfig:"ETH1_ERIGON_ENDPOINT"`
 	Eth1GethEndpoint          string `yaml:"eth1GethEndpoint" envconfig:"ETH1_GETH_ENDPOINT"`
 	EtherscanAPIKey           string `yaml:"etherscanApiKey" envconfig:"ETHERSCAN_API_KEY"`
 	EtherscanAPIBaseURL       string `yaml:"etherscanApiBaseUrl" envconfig:"ETHERSCAN_API_BASEURL"`
+	SourcifyAPIBaseURL        string `yaml:"sourcifyApiBaseUrl" envconfig:"SOURCIFY_API_BASEURL"`
 	RedisCacheEndpoint        string `yaml:"redisCacheEndpoint" envconfig:"REDIS_CACHE_ENDPOINT"`
 	RedisSessionStoreEndpoint string `yaml:"redisSessionStoreEndpoint" envconfig:"REDIS_SESSION_STORE_ENDPOINT"`
 	TieredCacheProvider       string `yaml:"tieredCacheProvider" envconfig:"CACHE_PROVIDER"`
@@ -75,6 +105,19 @@ type Config struct {
 			MaxOpenConns int    `yaml:"maxOpenConns" envconfig:"CLICKHOUSE_READER_DB_MAX_OPEN_CONNS"`
 			MaxIdleConns int    `yaml:"maxIdleConns" envconfig:"CLICKHOUSE_READER_DB_MAX_IDLE_CONNS"`
 		} `yaml:"readerDatabase"`
+		WriterDatabase struct {
+			Username     string `yaml:"user" envconfig:"CLICKHOUSE_WRITER_DB_USERNAME"`
+			Password     string `yaml:"password" envconfig:"CLICKHOUSE_WRITER_DB_PASSWORD"`
+			Name         string `yaml:"name" envconfig:"CLICKHOUSE_WRITER_DB_NAME"`
+			Host         string `yaml:"host" envconfig:"CLICKHOUSE_WRITER_DB_HOST"`
+			Port         string `yaml:"port" envconfig:"CLICKHOUSE_WRITER_DB_PORT"`
+			MaxOpenConns int    `yaml:"maxOpenConns" envconfig:"CLICKHOUSE_WRITER_DB_MAX_OPEN_CONNS"`
+			MaxIdleConns int    `yaml:"maxIdleConns" envconfig:"CLICKHOUSE_WRITER_DB_MAX_IDLE_CONNS"`
+		} `yaml:"writerDatabase"`
+		// StatisticsSinkEnabled makes the statistics service write the daily validator statistics to clickhouse in addition to postgres
+		StatisticsSinkEnabled bool `yaml:"statisticsSinkEnabled" envconfig:"CLICKHOUSE_STATISTICS_SINK_ENABLED"`
+		// StatisticsReadsEnabled routes the validator statistics chart and history queries to clickhouse, requires clickHouseEnabled
+		StatisticsReadsEnabled bool `yaml:"statisticsReadsEnabled" envconfig:"CLICKHOUSE_STATISTICS_READS_ENABLED"`
 	} `yaml:"clickhouse"`
 	ClickHouseEnabled bool          `yaml:"clickHouseEnabled" envconfig:"CLICKHOUSE_ENABLED"`
 	ClickhouseDelay   time.Duration `yaml:"clickhouseDelay" envconfig:"CLICKHOUSE_DELAY"`
@@ -93,6 +136,26 @@ type Config struct {
 		EnsTransformer struct {
 			ValidRegistrarContracts []string `yaml:"validRegistrarContracts" envconfig:"ENS_VALID_REGISTRAR_CONTRACTS"`
 		} `yaml:"ensTransformer"`
+		// SlotExporter configures the number of workers of each stage of the slot export pipeline
+		SlotExporter struct {
+			FetchConcurrency     int `yaml:"fetchConcurrency" envconfig:"INDEXER_SLOT_EXPORTER_FETCH_CONCURRENCY"`
+			TransformConcurrency int `yaml:"transformConcurrency" envconfig:"INDEXER_SLOT_EXPORTER_TRANSFORM_CONCURRENCY"`
+			PersistConcurrency   int `yaml:"persistConcurrency" envconfig:"INDEXER_SLOT_EXPORTER_PERSIST_CONCURRENCY"`
+		} `yaml:"slotExporter"`
+		// GapExporter scans the exported data for missing slots, epochs and execution blocks and queues them for re-export
+		GapExporter struct {
+			Enabled      bool          `yaml:"enabled" envconfig:"INDEXER_GAP_EXPORTER_ENABLED"`
+			Interval     time.Duration `yaml:"interval" envconfig:"INDEXER_GAP_EXPORTER_INTERVAL"`
+			Lookback     uint64        `yaml:"lookback" envconfig:"INDEXER_GAP_EXPORTER_LOOKBACK"`
+			Eth1Lookback int           `yaml:"eth1Lookback" envconfig:"INDEXER_GAP_EXPORTER_ETH1_LOOKBACK"`
+		} `yaml:"gapExporter"`
+		// ValidatorSetSnapshots stores a copy of the validator set every Interval epochs (defaults to one day) so it can be
+		// queried historically, snapshots older than Retention (defaults to 90 days) are deleted
+		ValidatorSetSnapshots struct {
+			Enabled   bool          `yaml:"enabled" envconfig:"INDEXER_VALIDATOR_SET_SNAPSHOTS_ENABLED"`
+			Interval  uint64        `yaml:"interval" envconfig:"INDEXER_VALIDATOR_SET_SNAPSHOTS_INTERVAL"`
+			Retention time.Duration `yaml:"retention" envconfig:"INDEXER_VALIDATOR_SET_SNAPSHOTS_RETENTION"`
+		} `yaml:"validatorSetSnapshots"`
 	} `yaml:"indexer"`
 	Frontend struct {
 		Debug                          bool   `yaml:"debug" envconfig:"FRONTEND_DEBUG"`
@@ -183,6 +246,7 @@ type Config struct {
 		JwtSigningSecret                     string        `yaml:"jwtSigningSecret" envconfig:"FRONTEND_JWT_SECRET"`
 		JwtIssuer                            string        `yaml:"jwtIssuer" envconfig:"FRONTEND_JWT_ISSUER"`
 		JwtValidityInMinutes                 int           `yaml:"jwtValidityInMinutes" envconfig:"FRONTEND_JWT_VALIDITY_INMINUTES"`
+		TwoFactorRequiredForPaidApi          bool          `yaml:"twoFactorRequiredForPaidApi" envconfig:"FRONTEND_TWO_FACTOR_REQUIRED_FOR_PAID_API"`
 		MaxMailsPerEmailPerDay               int           `yaml:"maxMailsPerEmailPerDay" envconfig:"FRONTEND_MAX_MAIL_PER_EMAIL_PER_DAY"`
 		Mail                                 struct {
 			SMTP struct {
@@ -200,6 +264,8 @@ type Config struct {
 				SupportEmail string `yaml:"supportEmail" envconfig:"FRONTEND_MAIL_CONTACT_SUPPORT_EMAIL"`
 				InquiryEmail string `yaml:"inquiryEmail" envconfig:"FRONTEND_MAIL_CONTACT_INQUIRY_EMAIL"`
 			} `yaml:"contact"`
+			// TemplatesPath is a directory with email templates that take precedence over the embedded ones
+			TemplatesPath string `yaml:"templatesPath" envconfig:"FRONTEND_MAIL_TEMPLATES_PATH"`
 		} `yaml:"mail"`
 		GATag         string `yaml:"gatag" envconfig:"GATAG"`
 		VerifyAppSubs bool   `yaml:"verifyAppSubscriptions" envconfig:"FRONTEND_VERIFY_APP_SUBSCRIPTIONS"`
@@ -231,6 +297,66 @@ type Config struct {
 		ElCurrencyDivisor  int64         `yaml:"elCurrencyDivisor" envconfig:"FRONTEND_EL_CURRENCY_DIVISOR"`
 		ElCurrencyDecimals int64         `yaml:"elCurrencyDecimals" envconfig:"FRONTEND_EL_CURRENCY_DECIMALS"`
 		MainCurrency       string        `yaml:"mainCurrency" envconfig:"FRONTEND_MAIN_CURRENCY"`
+
+		// Embed configures the widgets served under /embed/, requests with a valid signature of Secret are rate limited
+		// per embed key with the Signed limits, all other requests per ip
+		Embed struct {
+			Secret                   string  `yaml:"secret" envconfig:"FRONTEND_EMBED_SECRET"`
+			RateLimitPerSecond       float64 `yaml:"rateLimitPerSecond" envconfig:"FRONTEND_EMBED_RATE_LIMIT_PER_SECOND"`
+			RateLimitBurst           int     `yaml:"rateLimitBurst" envconfig:"FRONTEND_EMBED_RATE_LIMIT_BURST"`
+			SignedRateLimitPerSecond float64 `yaml:"signedRateLimitPerSecond" envconfig:"FRONTEND_EMBED_SIGNED_RATE_LIMIT_PER_SECOND"`
+			SignedRateLimitBurst     int     `yaml:"signedRateLimitBurst" envconfig:"FRONTEND_EMBED_SIGNED_RATE_LIMIT_BURST"`
+		} `yaml:"embed"`
+
+		// Faucet configures the testnet faucet served under /faucet, an address or ip can request AmountEth from the
+		// funding account of PrivateKey once per Cooldown. Requests are protected by the recaptcha if it is configured.
+		Faucet struct {
+			Enabled    bool          `yaml:"enabled" envconfig:"FRONTEND_FAUCET_ENABLED"`
+			PrivateKey string        `yaml:"privateKey" envconfig:"FRONTEND_FAUCET_PRIVATE_KEY"`
+			AmountEth  float64       `yaml:"amountEth" envconfig:"FRONTEND_FAUCET_AMOUNT_ETH"`
+			Cooldown   time.Duration `yaml:"cooldown" envconfig:"FRONTEND_FAUCET_COOLDOWN"`
+		} `yaml:"faucet"`
+
+		// AddressLabels configures the labels (exchange, bridge, ...) shown next to execution layer addresses. Admins
+		// curate the labels under /user/address_labels, if CommunitySubmissions is set logged in users can suggest
+		// labels on the address page which are shown once an admin approved them.
+		AddressLabels struct {
+			CommunitySubmissions bool `yaml:"communitySubmissions" envconfig:"FRONTEND_ADDRESS_LABELS_COMMUNITY_SUBMISSIONS"`
+		} `yaml:"addressLabels"`
+
+		// Networks are the networks shown in the network switcher, the api of a network is served under
+		// /api/v1/{name}/, requests for other networks are proxied to their deployment
+		Networks []Network `yaml:"networks"`
+
+		// Cors are the CORS policies of the api (/api/v1) and user (/user) route groups, the policy with the longest
+		// PathPrefix matching a request is applied. If none are configured any origin may access the route groups.
+		Cors []CorsPolicy `yaml:"cors"`
+
+		// Compression configures the gzip and brotli compression of responses, responses smaller than MinSize bytes
+		// or with a content type not in ContentTypes are sent uncompressed
+		Compression struct {
+			Disabled     bool     `yaml:"disabled" envconfig:"FRONTEND_COMPRESSION_DISABLED"`
+			MinSize      int      `yaml:"minSize" envconfig:"FRONTEND_COMPRESSION_MIN_SIZE"`
+			GzipLevel    int      `yaml:"gzipLevel" envconfig:"FRONTEND_COMPRESSION_GZIP_LEVEL"`
+			BrotliLevel  int      `yaml:"brotliLevel" envconfig:"FRONTEND_COMPRESSION_BROTLI_LEVEL"`
+			ContentTypes []string `yaml:"contentTypes" envconfig:"FRONTEND_COMPRESSION_CONTENT_TYPES"`
+		} `yaml:"compression"`
+
+		// QueryBudget limits the db queries of a request, requests exceeding MaxQueries or MaxDuration and queries
+		// slower than SlowQuery are logged. Only queries run with the context of the request are accounted.
+		QueryBudget struct {
+			MaxQueries  int           `yaml:"maxQueries" envconfig:"FRONTEND_QUERY_BUDGET_MAX_QUERIES"`
+			MaxDuration time.Duration `yaml:"maxDuration" envconfig:"FRONTEND_QUERY_BUDGET_MAX_DURATION"`
+			SlowQuery   time.Duration `yaml:"slowQuery" envconfig:"FRONTEND_QUERY_BUDGET_SLOW_QUERY"`
+		} `yaml:"queryBudget"`
+
+		// CacheControl are the Cache-Control headers of responses that do not set their own, by the class of the
+		// response: api for json, html for pages and static for all other files
+		CacheControl struct {
+			Api    string `yaml:"api" envconfig:"FRONTEND_CACHE_CONTROL_API"`
+			Html   string `yaml:"html" envconfig:"FRONTEND_CACHE_CONTROL_HTML"`
+			Static string `yaml:"static" envconfig:"FRONTEND_CACHE_CONTROL_STATIC"`
+		} `yaml:"cacheControl"`
 	} `yaml:"frontend"`
 	Metrics struct {
 		Enabled bool   `yaml:"enabled" envconfig:"METRICS_ENABLED"`
@@ -252,6 +378,50 @@ type Config struct {
 		Enabled        bool          `yaml:"enabled" envconfig:"RATELIMIT_UPDATER_ENABLED"`
 		UpdateInterval time.Duration `yaml:"updateInterval" envconfig:"RATELIMIT_UPDATER_UPDATE_INTERVAL"`
 	} `yaml:"ratelimitUpdater"`
+	// Retention prunes epoch granular data older than the configured days, 0 days keeps the data of a table forever
+	Retention struct {
+		Enabled   bool          `yaml:"enabled" envconfig:"RETENTION_ENABLED"`
+		DryRun    bool          `yaml:"dryRun" envconfig:"RETENTION_DRY_RUN"`
+		Interval  time.Duration `yaml:"interval" envconfig:"RETENTION_INTERVAL"`
+		BatchSize uint64        `yaml:"batchSize" envconfig:"RETENTION_BATCH_SIZE"`
+		Days      struct {
+			BlocksAttestations      uint64 `yaml:"blocksAttestations" envconfig:"RETENTION_DAYS_BLOCKS_ATTESTATIONS"`
+			ProposalAssignments     uint64 `yaml:"proposalAssignments" envconfig:"RETENTION_DAYS_PROPOSAL_ASSIGNMENTS"`
+			ValidatorBalancesRecent uint64 `yaml:"validatorBalancesRecent" envconfig:"RETENTION_DAYS_VALIDATOR_BALANCES_RECENT"`
+			NetworkLiveness         uint64 `yaml:"networkLiveness" envconfig:"RETENTION_DAYS_NETWORK_LIVENESS"`
+			MachineMetrics          uint64 `yaml:"machineMetrics" envconfig:"RETENTION_DAYS_MACHINE_METRICS"`
+		} `yaml:"days"`
+	} `yaml:"retention"`
+	// Logging configures the format and the levels of the loggers, Levels overrides the level of single components
+	Logging struct {
+		Format string            `yaml:"format" envconfig:"LOGGING_FORMAT"`
+		Level  string            `yaml:"level" envconfig:"LOGGING_LEVEL"`
+		Levels map[string]string `yaml:"levels" envconfig:"LOGGING_LEVELS"`
+	} `yaml:"logging"`
+	// Tracing exports OpenTelemetry spans of the http handlers, db queries, node requests and background services via OTLP
+	Tracing struct {
+		Enabled     bool    `yaml:"enabled" envconfig:"TRACING_ENABLED"`
+		Endpoint    string  `yaml:"endpoint" envconfig:"TRACING_ENDPOINT"`
+		Insecure    bool    `yaml:"insecure" envconfig:"TRACING_INSECURE"`
+		SampleRatio float64 `yaml:"sampleRatio" envconfig:"TRACING_SAMPLE_RATIO"`
+	} `yaml:"tracing"`
+	// HttpClients are the timeout, retry and circuit breaker policies of the calls to external services by target
+	// (beaconNode, executionNode, relays, prices, push), unset fields keep the defaults of the target
+	HttpClients map[string]HttpClientPolicy `yaml:"httpClients"`
+	// ConsistencyAudit compares the stored data of randomly sampled epochs and slots with the data of the nodes
+	ConsistencyAudit struct {
+		Enabled          bool          `yaml:"enabled" envconfig:"CONSISTENCY_AUDIT_ENABLED"`
+		Interval         time.Duration `yaml:"interval" envconfig:"CONSISTENCY_AUDIT_INTERVAL"`
+		SampleEpochs     int           `yaml:"sampleEpochs" envconfig:"CONSISTENCY_AUDIT_SAMPLE_EPOCHS"`
+		SampleSlots      int           `yaml:"sampleSlots" envconfig:"CONSISTENCY_AUDIT_SAMPLE_SLOTS"`
+		SampleValidators int           `yaml:"sampleValidators" envconfig:"CONSISTENCY_AUDIT_SAMPLE_VALIDATORS"`
+	} `yaml:"consistencyAudit"`
+	// AdminApi serves the operational endpoints on a separate listener, requests must carry the api key as bearer token
+	AdminApi struct {
+		Enabled bool   `yaml:"enabled" envconfig:"ADMIN_API_ENABLED"`
+		Address string `yaml:"address" envconfig:"ADMIN_API_ADDRESS"`
+		ApiKey  string `yaml:"apiKey" envconfig:"ADMIN_API_KEY"`
+	} `yaml:"adminApi"`
 	SSVExporter struct {
 		Enabled bool   `yaml:"enabled" envconfig:"SSV_EXPORTER_ENABLED"`
 		Address string `yaml:"address" envconfig:"SSV_EXPORTER_ADDRESS"`
@@ -262,6 +432,28 @@ type Config struct {
 	MevBoostRelayExporter struct {
 		Enabled bool `yaml:"enabled" envconfig:"MEVBOOSTRELAY_EXPORTER_ENABLED"`
 	} `yaml:"mevBoostRelayExporter"`
+	CensorshipExporter struct {
+		Enabled   bool     `yaml:"enabled" envconfig:"CENSORSHIP_EXPORTER_ENABLED"`
+		Watchlist []string `yaml:"watchlist" envconfig:"CENSORSHIP_EXPORTER_WATCHLIST"`
+	} `yaml:"censorshipExporter"`
+	ValidatorEntitiesExporter struct {
+		Enabled                 bool   `yaml:"enabled" envconfig:"VALIDATOR_ENTITIES_EXPORTER_ENABLED"`
+		SoloStakerMaxValidators uint64 `yaml:"soloStakerMaxValidators" envconfig:"VALIDATOR_ENTITIES_EXPORTER_SOLO_STAKER_MAX_VALIDATORS"`
+	} `yaml:"validatorEntitiesExporter"`
+	ObolExporter struct {
+		Enabled bool   `yaml:"enabled" envconfig:"OBOL_EXPORTER_ENABLED"`
+		Address string `yaml:"address" envconfig:"OBOL_EXPORTER_ADDRESS"`
+	} `yaml:"obolExporter"`
+	LidoExporter struct {
+		Enabled bool `yaml:"enabled" envconfig:"LIDO_EXPORTER_ENABLED"`
+	} `yaml:"lidoExporter"`
+	// DepositOriginExporter traces the deposit addresses of validators back up to MaxHops transfers to find the exchange,
+	// custodian or contract that funded them, addresses without a known origin are traced again after RetraceInterval
+	DepositOriginExporter struct {
+		Enabled         bool          `yaml:"enabled" envconfig:"DEPOSIT_ORIGIN_EXPORTER_ENABLED"`
+		MaxHops         int           `yaml:"maxHops" envconfig:"DEPOSIT_ORIGIN_EXPORTER_MAX_HOPS"`
+		RetraceInterval time.Duration `yaml:"retraceInterval" envconfig:"DEPOSIT_ORIGIN_EXPORTER_RETRACE_INTERVAL"`
+	} `yaml:"depositOriginExporter"`
 	Pprof struct {
 		Enabled bool   `yaml:"enabled" envconfig:"PPROF_ENABLED"`
 		Port    string `yaml:"port" envconfig:"PPROF_PORT"`
@@ -278,14 +470,16 @@ type Config struct {
 }
 
 type DatabaseConfig struct {
-	Username     string
-	Password     string
-	Name         string
-	Host         string
-	Port         string
-	MaxOpenConns int
-	MaxIdleConns int
-	SSL          bool
+	Username          string
+	Password          string
+	Name              string
+	Host              string
+	Port              string
+	MaxOpenConns      int
+	MaxIdleConns      int
+	SSL               bool
+	Replicas          []string
+	MaxReplicationLag time.Duration
 }
 
 type ServiceMonitoringConfiguration struct {
@@ -395,3 +589,45 @@ type ConfigJsonResponse struct {
 		DomainApplicationMask                   string `json:"DOMAIN_APPLICATION_MASK"`
 	} `json:"data"`
 }
+
+// LogLevel is the current log level of a component
+type LogLevel struct {
+	Component string `json:"component"`
+	Level     string `json:"level"`
+}
+
+// HttpClientPolicy is the policy of the calls to an external service. Idempotent requests failing with an error or a
+// 5xx or 429 status are retried up to Retries times with a jittered exponential backoff starting at RetryBackoff. The
+// circuit breaker of a host opens after BreakerThreshold consecutive failures and fails all requests to the host for
+// BreakerCooldown, after which a single request is let through to probe the host.
+type HttpClientPolicy struct {
+	Timeout          time.Duration `yaml:"timeout"`
+	Retries          int           `yaml:"retries"`
+	RetryBackoff     time.Duration `yaml:"retryBackoff"`
+	BreakerThreshold int           `yaml:"breakerThreshold"`
+	BreakerCooldown  time.Duration `yaml:"breakerCooldown"`
+}
+
+// CorsPolicy is the CORS policy and the additional response headers of the routes below PathPrefix
+type CorsPolicy struct {
+	PathPrefix string `yaml:"pathPrefix"`
+	// AllowedOrigins may contain * to allow any origin and wildcard subdomains like https://*.example.com, the * origin
+	// can not be combined with AllowCredentials
+	AllowedOrigins   []string      `yaml:"allowedOrigins"`
+	AllowedMethods   []string      `yaml:"allowedMethods"`
+	AllowedHeaders   []string      `yaml:"allowedHeaders"`
+	ExposedHeaders   []string      `yaml:"exposedHeaders"`
+	AllowCredentials bool          `yaml:"allowCredentials"`
+	MaxAge           time.Duration `yaml:"maxAge"`
+	// Headers are added to every response, e.g. the Cache-Control or version headers expected by an api gateway
+	Headers map[string]string `yaml:"headers"`
+}
+
+// Network is a network served by a separate explorer deployment
+type Network struct {
+	// Name is the chain name of the network, e.g. mainnet, holesky or gnosis
+	Name   string `yaml:"name"`
+	Label  string `yaml:"label"`
+	Url    string `yaml:"url"`
+	ApiUrl string `yaml:"apiUrl"` // defaults to Url
+}
@@ -7,3 +7,36 @@ type GetBlockTimings struct {
 	Receipts time.Duration
 	Traces   time.Duration
 }
+
+// Eth1UserOperationIndexed is an ERC-4337 user operation executed by an EntryPoint contract, it is stored json encoded
+// in the data table
+type Eth1UserOperationIndexed struct {
+	UserOpHash    []byte    `json:"user_op_hash"`
+	TxHash        []byte    `json:"tx_hash"`
+	BlockNumber   uint64    `json:"block_number"`
+	Time          time.Time `json:"time"`
+	LogIndex      uint64    `json:"log_index"`
+	EntryPoint    []byte    `json:"entry_point"`
+	Sender        []byte    `json:"sender"`
+	Paymaster     []byte    `json:"paymaster"`
+	Bundler       []byte    `json:"bundler"`
+	Beneficiary   []byte    `json:"beneficiary"`
+	Nonce         []byte    `json:"nonce"`
+	Success       bool      `json:"success"`
+	ActualGasCost []byte    `json:"actual_gas_cost"`
+	ActualGasUsed []byte    `json:"actual_gas_used"`
+}
+
+// Eth1L2BridgeTxIndexed is a transaction that interacted with the bridge contracts of a layer 2 network, the value is
+// the amount of ether that was moved into (deposit) or out of (withdrawal) the bridge contracts. It is stored json
+// encoded in the data table.
+type Eth1L2BridgeTxIndexed struct {
+	TxHash      []byte    `json:"tx_hash"`
+	BlockNumber uint64    `json:"block_number"`
+	Time        time.Time `json:"time"`
+	Network     string    `json:"network"`
+	Direction   string    `json:"direction"`
+	From        []byte    `json:"from"`
+	Contract    []byte    `json:"contract"`
+	Value       []byte    `json:"value"`
+}
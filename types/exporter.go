@@ -92,6 +92,15 @@ type Validator struct {
 	LastAttestationSlot sql.NullInt64 `db:"lastattestationslot"`
 }
 
+// BlockFeeRecipient is the fee recipient paid by a proposed block, Relayed is set if the block was built by a relay
+type BlockFeeRecipient struct {
+	Proposer        uint64 `db:"proposer"`
+	Slot            uint64 `db:"slot"`
+	ExecBlockNumber uint64 `db:"exec_block_number"`
+	FeeRecipient    []byte `db:"fee_recipient"`
+	Relayed         bool   `db:"relayed"`
+}
+
 // ValidatorQueue is a struct to hold validator queue data
 type ValidatorQueue struct {
 	Activating uint64
@@ -213,6 +222,24 @@ type WithdrawalsNotification struct {
 	Pubkey         []byte `json:"pubkey"`
 }
 
+// BLSChangeNotification is a BLS to execution change included in a finalized block, changing the withdrawal
+// credentials of the validator to the address
+type BLSChangeNotification struct {
+	Slot           uint64 `db:"slot"`
+	ValidatorIndex uint64 `db:"validatorindex"`
+	Address        []byte `db:"address"`
+	Pubkey         []byte `db:"pubkey"`
+}
+
+// ValidatorExitNotification is a struct to hold the exit info of a validator for exit and withdrawable notifications
+type ValidatorExitNotification struct {
+	Slot              uint64 `db:"slot"`
+	ValidatorIndex    uint64 `db:"validatorindex"`
+	ExitEpoch         uint64 `db:"exitepoch"`
+	WithdrawableEpoch uint64 `db:"withdrawableepoch"`
+	Pubkey            []byte `db:"pubkey"`
+}
+
 // Eth1Data is a struct to hold the ETH1 data
 type Eth1Data struct {
 	DepositRoot  []byte
@@ -522,9 +549,24 @@ type Relay struct {
 	ExportFailureCount  uint64         `db:"export_failure_count"`
 	LastExportTryTs     time.Time      `db:"last_export_try_ts"`
 	LastExportSuccessTs time.Time      `db:"last_export_success_ts"`
+	LastBidsExportSlot  uint64         `db:"last_bids_export_slot"`
 	Logger              logrus.Entry
 }
 
+// RelayValidatorRegistration is the last known registration of a validator at a relay. The fee recipient, gas limit and
+// registration time are kept from the last registration if the validator is no longer registered.
+type RelayValidatorRegistration struct {
+	RelayID        string         `db:"tag_id"`
+	RelayName      sql.NullString `db:"relay_name"`
+	Pubkey         []byte         `db:"pubkey"`
+	Registered     bool           `db:"registered"`
+	FeeRecipient   []byte         `db:"fee_recipient"`
+	GasLimit       sql.NullInt64  `db:"gas_limit"`
+	RegistrationTs sql.NullTime   `db:"registration_ts"`
+	CheckedTs      time.Time      `db:"checked_ts"`
+	UnregisteredTs sql.NullTime   `db:"unregistered_ts"`
+}
+
 type RelayBlock struct {
 	ID                   string `db:"tag_id" json:"tag_id"`
 	BlockSlot            uint64 `db:"block_slot" json:"block_slot"`
@@ -675,4 +717,55 @@ type ValidatorStatsTableDbRow struct {
 	MEVPerformance7d   decimal.Decimal `db:"-"`
 	MEVPerformance31d  decimal.Decimal `db:"-"`
 	MEVPerformance365d decimal.Decimal `db:"-"`
+
+	// expected amount of proposals and sync committee slots of the validators that were active during the whole day, nil for all other validators
+	ExpectedProposals *float64 `db:"expected_proposals"`
+	ExpectedSyncSlots *float64 `db:"expected_sync_slots"`
+
+	// percentile ranks (0-100, higher is better) within the validators that were active during the whole day, nil for all other validators
+	AttestationPercentile  *float64 `db:"attestation_percentile"`
+	ProposalLuckPercentile *float64 `db:"proposal_luck_percentile"`
+	IncomePercentile       *float64 `db:"income_percentile"`
+}
+
+// LidoOperator is a node operator of a lido staking module as exported from the module contract
+type LidoOperator struct {
+	Module                   string     `db:"module"`
+	OperatorId               uint64     `db:"operator_id"`
+	Name                     string     `db:"name"`
+	RewardAddress            []byte     `db:"reward_address"`
+	Active                   bool       `db:"active"`
+	TotalAddedValidators     uint64     `db:"total_added_validators"`
+	TotalDepositedValidators uint64     `db:"total_deposited_validators"`
+	TotalExitedValidators    uint64     `db:"total_exited_validators"`
+	DepositableValidators    uint64     `db:"depositable_validators"`
+	StuckValidators          uint64     `db:"stuck_validators"`
+	RefundedValidators       uint64     `db:"refunded_validators"`
+	TargetLimitMode          uint64     `db:"target_limit_mode"`
+	TargetValidators         uint64     `db:"target_validators"`
+	StuckPenaltyEndTs        *time.Time `db:"stuck_penalty_end_ts"`
+	Penalized                bool       `db:"penalized"`
+	UpdatedTs                time.Time  `db:"updated_ts"`
+}
+
+const (
+	DvtProtocolSSV  = "ssv"
+	DvtProtocolObol = "obol"
+)
+
+// DvtValidator is a validator run by a distributed validator cluster as exported from the registry of the dvt protocol
+type DvtValidator struct {
+	PublicKey   []byte
+	ClusterId   string
+	ClusterName string
+	Operators   []string
+}
+
+// ExportGap is a slot, epoch or execution block missing in the exported data that is queued for re-export
+type ExportGap struct {
+	Kind       string    `db:"kind" json:"kind"`
+	Number     uint64    `db:"number" json:"number"`
+	DetectedTs time.Time `db:"detected_ts" json:"detected_ts"`
+	Attempts   uint64    `db:"attempts" json:"attempts"`
+	LastError  string    `db:"last_error" json:"last_error"`
 }
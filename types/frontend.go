@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
+	"fmt"
 	"html/template"
 	"math/big"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/lib/pq"
 	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -28,14 +30,23 @@ const (
 	ValidatorGotSlashedEventName                     EventName = "validator_got_slashed"
 	ValidatorDidSlashEventName                       EventName = "validator_did_slash"
 	ValidatorIsOfflineEventName                      EventName = "validator_is_offline"
+	ValidatorsClusterOfflineEventName                EventName = "validators_cluster_offline"
 	ValidatorReceivedWithdrawalEventName             EventName = "validator_withdrawal"
 	ValidatorReceivedDepositEventName                EventName = "validator_received_deposit"
+	ValidatorWithdrawalCredentialsChangedEventName   EventName = "validator_withdrawal_credentials_changed"
+	ValidatorVoluntaryExitEventName                  EventName = "validator_voluntary_exit"
+	ValidatorWithdrawableEventName                   EventName = "validator_withdrawable"
+	ValidatorFeeRecipientMismatchEventName           EventName = "validator_fee_recipient_mismatch"
+	ValidatorRelayRegistrationMissingEventName       EventName = "validator_relay_registration_missing"
+	ValidatorMilestoneEventName                      EventName = "validator_milestone"
+	ValidatorDepositWarningEventName                 EventName = "validator_deposit_warning"
 	NetworkSlashingEventName                         EventName = "network_slashing"
 	NetworkValidatorActivationQueueFullEventName     EventName = "network_validator_activation_queue_full"
 	NetworkValidatorActivationQueueNotFullEventName  EventName = "network_validator_activation_queue_not_full"
 	NetworkValidatorExitQueueFullEventName           EventName = "network_validator_exit_queue_full"
 	NetworkValidatorExitQueueNotFullEventName        EventName = "network_validator_exit_queue_not_full"
 	NetworkLivenessIncreasedEventName                EventName = "network_liveness_increased"
+	NetworkIncidentEventName                         EventName = "network_incident"
 	EthClientUpdateEventName                         EventName = "eth_client_update"
 	MonitoringMachineOfflineEventName                EventName = "monitoring_machine_offline"
 	MonitoringMachineDiskAlmostFullEventName         EventName = "monitoring_hdd_almostfull"
@@ -49,6 +60,10 @@ const (
 	RocketpoolCollateralMinReached                   EventName = "rocketpool_colleteral_min"
 	RocketpoolCollateralMaxReached                   EventName = "rocketpool_colleteral_max"
 	SyncCommitteeSoon                                EventName = "validator_synccommittee_soon"
+	EthAddressIncomingTransactionEventName           EventName = "eth_address_incoming_tx"
+	EthAddressOutgoingTransactionEventName           EventName = "eth_address_outgoing_tx"
+	EthAddressTokenTransferEventName                 EventName = "eth_address_token_transfer"
+	EthAddressContractInteractionEventName           EventName = "eth_address_contract_interaction"
 )
 
 var MachineEvents = []EventName{
@@ -61,6 +76,14 @@ var MachineEvents = []EventName{
 	MonitoringMachineSwitchedToETH1FallbackEventName,
 }
 
+// EthAddressEvents are subscribed to per execution layer address, the event filter is the hex encoded address
+var EthAddressEvents = []EventName{
+	EthAddressIncomingTransactionEventName,
+	EthAddressOutgoingTransactionEventName,
+	EthAddressTokenTransferEventName,
+	EthAddressContractInteractionEventName,
+}
+
 var UserIndexEvents = []EventName{
 	EthClientUpdateEventName,
 	MonitoringMachineCpuLoadEventName,
@@ -81,14 +104,23 @@ var EventLabel map[EventName]string = map[EventName]string{
 	ValidatorGotSlashedEventName:                     "Your validator(s) got slashed",
 	ValidatorDidSlashEventName:                       "Your validator(s) slashed another validator",
 	ValidatorIsOfflineEventName:                      "Your validator(s) state changed",
+	ValidatorsClusterOfflineEventName:                "Many of your validators went offline at once",
 	ValidatorReceivedDepositEventName:                "Your validator(s) received a deposit",
 	ValidatorReceivedWithdrawalEventName:             "A withdrawal was initiated for your validators",
+	ValidatorWithdrawalCredentialsChangedEventName:   "The withdrawal credentials of your validator(s) changed",
+	ValidatorVoluntaryExitEventName:                  "The voluntary exit of your validator(s) was included",
+	ValidatorWithdrawableEventName:                   "Your validator(s) became withdrawable",
+	ValidatorFeeRecipientMismatchEventName:           "Your validator(s) proposed a block with an unexpected fee recipient",
+	ValidatorRelayRegistrationMissingEventName:       "Your validator(s) are no longer registered with a relay",
+	ValidatorMilestoneEventName:                      "Your validator(s) reached a milestone",
+	ValidatorDepositWarningEventName:                 "A deposit to your validator(s) needs your attention",
 	NetworkSlashingEventName:                         "A slashing event has been registered by the network",
 	NetworkValidatorActivationQueueFullEventName:     "The activation queue is full",
 	NetworkValidatorActivationQueueNotFullEventName:  "The activation queue is empty",
 	NetworkValidatorExitQueueFullEventName:           "The validator exit queue is full",
 	NetworkValidatorExitQueueNotFullEventName:        "The validator exit queue is empty",
 	NetworkLivenessIncreasedEventName:                "The network is experiencing liveness issues",
+	NetworkIncidentEventName:                         "An anomaly in the participation, missed slots or attestation delays of the network was detected",
 	EthClientUpdateEventName:                         "An Ethereum client has a new update available",
 	MonitoringMachineOfflineEventName:                "Your machine(s) might be offline",
 	MonitoringMachineDiskAlmostFullEventName:         "Your machine(s) disk space is running low",
@@ -102,6 +134,10 @@ var EventLabel map[EventName]string = map[EventName]string{
 	RocketpoolCollateralMinReached:                   "You reached the Rocket Pool min RPL collateral",
 	RocketpoolCollateralMaxReached:                   "You reached the Rocket Pool max RPL collateral",
 	SyncCommitteeSoon:                                "Your validator(s) will soon be part of the sync committee",
+	EthAddressIncomingTransactionEventName:           "Your watched address(es) received a transaction",
+	EthAddressOutgoingTransactionEventName:           "Your watched address(es) sent a transaction",
+	EthAddressTokenTransferEventName:                 "Your watched address(es) sent or received a token transfer",
+	EthAddressContractInteractionEventName:           "Your watched address(es) interacted with a contract",
 }
 
 func IsUserIndexed(event EventName) bool {
@@ -113,6 +149,15 @@ func IsUserIndexed(event EventName) bool {
 	return false
 }
 
+func IsEthAddressNotification(event EventName) bool {
+	for _, ev := range EthAddressEvents {
+		if ev == event {
+			return true
+		}
+	}
+	return false
+}
+
 func IsMachineNotification(event EventName) bool {
 	for _, ev := range MachineEvents {
 		if ev == event {
@@ -130,14 +175,23 @@ var EventNames = []EventName{
 	ValidatorGotSlashedEventName,
 	ValidatorDidSlashEventName,
 	ValidatorIsOfflineEventName,
+	ValidatorsClusterOfflineEventName,
 	ValidatorReceivedDepositEventName,
 	ValidatorReceivedWithdrawalEventName,
+	ValidatorWithdrawalCredentialsChangedEventName,
+	ValidatorVoluntaryExitEventName,
+	ValidatorWithdrawableEventName,
+	ValidatorFeeRecipientMismatchEventName,
+	ValidatorRelayRegistrationMissingEventName,
+	ValidatorMilestoneEventName,
+	ValidatorDepositWarningEventName,
 	NetworkSlashingEventName,
 	NetworkValidatorActivationQueueFullEventName,
 	NetworkValidatorActivationQueueNotFullEventName,
 	NetworkValidatorExitQueueFullEventName,
 	NetworkValidatorExitQueueNotFullEventName,
 	NetworkLivenessIncreasedEventName,
+	NetworkIncidentEventName,
 	EthClientUpdateEventName,
 	MonitoringMachineOfflineEventName,
 	MonitoringMachineDiskAlmostFullEventName,
@@ -151,6 +205,10 @@ var EventNames = []EventName{
 	RocketpoolCollateralMinReached,
 	RocketpoolCollateralMaxReached,
 	SyncCommitteeSoon,
+	EthAddressIncomingTransactionEventName,
+	EthAddressOutgoingTransactionEventName,
+	EthAddressTokenTransferEventName,
+	EthAddressContractInteractionEventName,
 }
 
 type EventNameDesc struct {
@@ -202,6 +260,61 @@ var AddWatchlistEvents = []EventNameDesc{
 		Event: ValidatorReceivedWithdrawalEventName,
 		Info:  template.HTML(`<i data-toggle="tooltip" data-html="true" title="<div class='text-left'>Will trigger a notifcation when:<br><ul><li>A partial withdrawal is processed</li><li>Your validator exits and its full balance is withdrawn</li></ul> <div>Requires that your validator has 0x01 credentials</div></div>" class="fas fa-question-circle"></i>`),
 	},
+	{
+		Desc:  "Withdrawal credentials changed",
+		Event: ValidatorWithdrawalCredentialsChangedEventName,
+		Info:  template.HTML(`<i data-toggle="tooltip" data-html="true" title="<div class='text-left'>Will trigger a notifcation when a BLS to execution change of your validator is included in a finalized block. If you did not sign it, your withdrawal key might be compromised.</div>" class="fas fa-question-circle"></i>`),
+	},
+	{
+		Desc:  "Voluntary exit included",
+		Event: ValidatorVoluntaryExitEventName,
+	},
+	{
+		Desc:  "Validator withdrawable",
+		Event: ValidatorWithdrawableEventName,
+		Info:  template.HTML(`<i data-toggle="tooltip" data-html="true" title="<div class='text-left'>Will trigger a notifcation when your exited validator reaches its withdrawable epoch and its balance can be swept</div>" class="fas fa-question-circle"></i>`),
+	},
+	{
+		Desc:  "Unexpected fee recipient",
+		Event: ValidatorFeeRecipientMismatchEventName,
+		Info:  template.HTML(`<i data-toggle="tooltip" data-html="true" title="<div class='text-left'>Will trigger a notifcation when your validator proposes a block that pays a different fee recipient than the one you expect. Requires that you set the expected fee recipient of the validator.</div>" class="fas fa-question-circle"></i>`),
+	},
+	{
+		Desc:  "Relay registration missing",
+		Event: ValidatorRelayRegistrationMissingEventName,
+		Info:  template.HTML(`<i data-toggle="tooltip" data-html="true" title="<div class='text-left'>Will trigger a notifcation when a relay your validator was registered with no longer returns its registration. The registrations are checked about once per hour.</div>" class="fas fa-question-circle"></i>`),
+	},
+	{
+		Desc:  "Milestone reached",
+		Event: ValidatorMilestoneEventName,
+		Info:  template.HTML(`<i data-toggle="tooltip" data-html="true" title="<div class='text-left'>Will trigger a notifcation when your validator proposes its first block, earns its first ETH, has been active for one year or attests for 30 days without a miss. Milestones are detected once per day.</div>" class="fas fa-question-circle"></i>`),
+	},
+	{
+		Desc:  "Deposit warning",
+		Event: ValidatorDepositWarningEventName,
+		Info:  template.HTML(`<i data-toggle="tooltip" data-html="true" title="<div class='text-left'>Will trigger a notifcation when a deposit with an invalid signature is made for your validator or when a deposit is made to your validator after it was already activated.</div>" class="fas fa-question-circle"></i>`),
+	},
+}
+
+// this is the source of truth for the execution layer address events that are supported by the address page
+var EthAddressNotificationEvents = []EventNameDesc{
+	{
+		Desc:  "Incoming transactions",
+		Event: EthAddressIncomingTransactionEventName,
+	},
+	{
+		Desc:  "Outgoing transactions",
+		Event: EthAddressOutgoingTransactionEventName,
+	},
+	{
+		Desc:  "Token transfers",
+		Event: EthAddressTokenTransferEventName,
+		Info:  template.HTML(`<i data-toggle="tooltip" title="Only transfers reaching the threshold of the subscription will trigger a notification" class="fas fa-question-circle"></i>`),
+	},
+	{
+		Desc:  "Contract interactions",
+		Event: EthAddressContractInteractionEventName,
+	},
 }
 
 // this is the source of truth for the network events that are supported by the user/notification page
@@ -210,6 +323,10 @@ var NetworkNotificationEvents = []EventNameDesc{
 		Desc:  "Network Notifications",
 		Event: NetworkLivenessIncreasedEventName,
 	},
+	{
+		Desc:  "Network Incidents",
+		Event: NetworkIncidentEventName,
+	},
 	// {
 	// 	Desc:  "Slashing Notifications",
 	// 	Event: NetworkSlashingEventName,
@@ -248,6 +365,46 @@ type Notification interface {
 	GetInfoMarkdown() string
 }
 
+// ValidatorNotification is implemented by the notifications concerning a single validator
+type ValidatorNotification interface {
+	GetValidatorIndex() uint64
+}
+
+// Capabilities of the app on a device, reported on registration of the notification token. Devices without capabilities
+// only receive the title and body of push notifications.
+const (
+	// PushCapabilityRichPayload devices receive the event, validator, epoch and deep link as data of the push notification
+	PushCapabilityRichPayload = "rich_payload"
+	// PushCapabilityActions devices show the actions of the push notification (e.g. PushNotificationActionMute1h) as buttons
+	PushCapabilityActions = "actions"
+)
+
+var PushCapabilities = []string{PushCapabilityRichPayload, PushCapabilityActions}
+
+// PushNotificationSchemaVersion is the version of the data of rich push notifications, increased on breaking changes
+const PushNotificationSchemaVersion = "1"
+
+// PushNotificationActionMute1h mutes the push notifications of the subscription for an hour, see /api/v1/user/notifications/mute
+const PushNotificationActionMute1h = "mute_1h"
+
+// PushNotificationActionsCategory is the apns category the app registers the notification actions for
+const PushNotificationActionsCategory = "NOTIFICATION_ACTIONS"
+
+type PushDevice struct {
+	Token        string         `db:"notification_token"`
+	Capabilities pq.StringArray `db:"capabilities"`
+}
+
+// HasCapability returns true if the app on the device reported the capability
+func (d *PushDevice) HasCapability(capability string) bool {
+	for _, c := range d.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
 // func UnMarschal
 
 type Subscription struct {
@@ -265,6 +422,26 @@ type Subscription struct {
 	State           sql.NullString `db:"internal_state" swaggertype:"string"`
 }
 
+// ExpectedFeeRecipient is the fee recipient a user expects the blocks of a validator to pay to
+type ExpectedFeeRecipient struct {
+	ValidatorPublickey []byte    `db:"validator_publickey"`
+	FeeRecipient       []byte    `db:"fee_recipient"`
+	CreatedTs          time.Time `db:"created_ts"`
+}
+
+// UserNotificationSubscription is a notification subscription of a user as managed via the api. Without channels the
+// notifications are sent on all channels of the user.
+type UserNotificationSubscription struct {
+	ID             uint64         `db:"id" json:"id"`
+	EventName      EventName      `db:"event_name" json:"event_name"`
+	EventFilter    string         `db:"event_filter" json:"event_filter"`
+	EventThreshold float64        `db:"event_threshold" json:"event_threshold"`
+	Channels       pq.StringArray `db:"channels" json:"channels" swaggertype:"array,string"`
+	CreatedTs      time.Time      `db:"created_ts" json:"created_ts"`
+	LastSentTs     *time.Time     `db:"last_sent_ts" json:"last_sent_ts"`
+	LastSentEpoch  *uint64        `db:"last_sent_epoch" json:"last_sent_epoch"`
+}
+
 type TaggedValidators struct {
 	UserID             uint64 `db:"user_id"`
 	Tag                string `db:"tag"`
@@ -284,11 +461,14 @@ type OAuthAppData struct {
 	AppName     string `db:"app_name"`
 	RedirectURI string `db:"redirect_uri"`
 	Active      bool   `db:"active"`
+	FirstParty  bool   `db:"first_party"`
 }
 
 type OAuthCodeData struct {
-	AppID  uint64 `db:"app_id"`
-	UserID uint64 `db:"user_id"`
+	AppID         uint64 `db:"app_id"`
+	UserID        uint64 `db:"user_id"`
+	Scope         string `db:"scope"`
+	CodeChallenge string `db:"code_challenge"`
 }
 
 type MobileSettingsData struct {
@@ -453,6 +633,8 @@ type Email struct {
 	Body                  template.HTML `json:"body"`
 	SubscriptionManageURL template.HTML `json:"subscriptionManageUrl"`
 	UnSubURL              template.HTML `json:"unSubURL"`
+	// Locale selects the layout the email is rendered with, the default locale is used if it is empty
+	Locale string `json:"locale,omitempty"`
 }
 
 type UserWebhook struct {
@@ -525,25 +707,311 @@ func (a ErrorResponse) Value() (driver.Value, error) {
 }
 
 type EnsSearchPageData = struct {
-	Error  string
-	Search string
-	Result *EnsDomainResponse
+	Error        string
+	Search       string
+	Result       *EnsDomainResponse
+	Profile      *EnsProfile
+	Validators   []uint64
+	Transactions *DataTableResponse
+}
+
+// EnsProfile holds the registration of an ens name and the other names resolving to the same address
+type EnsProfile struct {
+	Name          string    `db:"ens_name"`
+	Address       []byte    `db:"address"`
+	IsPrimaryName bool      `db:"is_primary_name"`
+	ValidTo       time.Time `db:"valid_to"`
+	OtherNames    []string
+}
+
+const (
+	// ValidatorNameProofDeposit is used for name claims signed by the address of the first valid deposit of a validator
+	ValidatorNameProofDeposit = "deposit"
+	// ValidatorNameProofWithdrawal is used for name claims signed by the execution layer withdrawal address of a validator
+	ValidatorNameProofWithdrawal = "withdrawal"
+
+	ValidatorNameClaimPending  = "pending"
+	ValidatorNameClaimApproved = "approved"
+	ValidatorNameClaimRejected = "rejected"
+)
+
+// ValidatorNameClaim is a signed request to publicly name a validator, names are shown right away and can be rejected by an admin afterwards
+type ValidatorNameClaim struct {
+	Id             uint64     `db:"id"`
+	ValidatorIndex *uint64    `db:"validatorindex"`
+	PublicKey      []byte     `db:"publickey"`
+	Name           string     `db:"name"`
+	SignerAddress  []byte     `db:"signer_address"`
+	ProofType      string     `db:"proof_type"`
+	Message        string     `db:"message"`
+	Signature      []byte     `db:"signature"`
+	Status         string     `db:"status"`
+	CreatedTs      time.Time  `db:"created_ts"`
+	ReviewedTs     *time.Time `db:"reviewed_ts"`
+	ReviewedBy     *uint64    `db:"reviewed_by"`
+}
+
+const (
+	AddressLabelCategoryExchange = "exchange"
+	AddressLabelCategoryContract = "contract"
+	AddressLabelCategoryBridge   = "bridge"
+	AddressLabelCategoryMixer    = "mixer"
+	AddressLabelCategoryEntity   = "entity"
+
+	// AddressLabelSourceAdmin is used for labels created by an admin, they are approved right away
+	AddressLabelSourceAdmin = "admin"
+	// AddressLabelSourceCommunity is used for labels suggested by users, they are shown once an admin approved them
+	AddressLabelSourceCommunity = "community"
+
+	AddressLabelPending  = "pending"
+	AddressLabelApproved = "approved"
+	AddressLabelRejected = "rejected"
+)
+
+// AddressLabelCategories are the categories an address label can have, in the order they are offered
+var AddressLabelCategories = []string{AddressLabelCategoryExchange, AddressLabelCategoryContract, AddressLabelCategoryBridge, AddressLabelCategoryMixer, AddressLabelCategoryEntity}
+
+// AddressLabel tags an execution layer address with a name and a category, only approved labels are shown
+type AddressLabel struct {
+	Id          uint64     `db:"id"`
+	Address     []byte     `db:"address"`
+	Label       string     `db:"label"`
+	Category    string     `db:"category"`
+	Source      string     `db:"source"`
+	Note        string     `db:"note"`
+	Status      string     `db:"status"`
+	SubmittedBy *uint64    `db:"submitted_by"`
+	CreatedTs   time.Time  `db:"created_ts"`
+	ReviewedTs  *time.Time `db:"reviewed_ts"`
+	ReviewedBy  *uint64    `db:"reviewed_by"`
+}
+
+const (
+	DepositOriginExchange  = "exchange"
+	DepositOriginCustodian = "custodian"
+	DepositOriginContract  = "contract"
+	DepositOriginBridge    = "bridge"
+	DepositOriginMixer     = "mixer"
+	DepositOriginUnknown   = "unknown"
+)
+
+// DepositOrigin is the funding source of a deposit address, found by following the largest incoming transfer of each
+// address back until a labeled address or a smart contract is reached. Path holds the addresses of the traced hops,
+// starting with the address that funded the deposit address.
+type DepositOrigin struct {
+	DepositAddress []byte        `db:"from_address"`
+	Kind           string        `db:"origin_kind"`
+	OriginAddress  []byte        `db:"origin_address"`
+	OriginLabel    string        `db:"origin_label"`
+	Hops           uint64        `db:"hops"`
+	Path           pq.ByteaArray `db:"path"`
+	TracedTs       time.Time     `db:"traced_ts"`
+}
+
+// ValidatorDepositOrigin is the deposit origin of the address of the first valid deposit of a validator, Origin is nil
+// if the address has not been traced yet
+type ValidatorDepositOrigin struct {
+	PublicKey      []byte
+	ValidatorIndex *uint64
+	DepositAddress []byte
+	Origin         *DepositOrigin
+}
+
+const (
+	Eth1DepositWarningInvalidSignature = "invalid_signature"
+	Eth1DepositWarningActivePubkey     = "active_pubkey"
+)
+
+// Eth1DepositWarnings describe the mistakes flagged by the eth1 deposits exporter
+var Eth1DepositWarnings = map[string]string{
+	Eth1DepositWarningInvalidSignature: "The deposit has an invalid signature and was made before any valid deposit of this public key. The beacon chain ignores it and the deposited amount is lost.",
+	Eth1DepositWarningActivePubkey:     "The deposit was made to a validator that was already activated. It does not create a new validator, the amount is added to the balance of the existing one and is paid out to its withdrawal credentials.",
+}
+
+// Eth1DepositWarning flags a deposit of the deposit contract that most likely was a mistake
+type Eth1DepositWarning struct {
+	TxHash          []byte    `db:"tx_hash"`
+	MerkletreeIndex []byte    `db:"merkletree_index"`
+	PublicKey       []byte    `db:"publickey"`
+	ValidatorIndex  *uint64   `db:"validatorindex"`
+	Amount          uint64    `db:"amount"`
+	BlockTs         time.Time `db:"block_ts"`
+	Warning         string    `db:"warning"`
+	DetectedTs      time.Time `db:"detected_ts"`
+}
+
+// ValidatorEntitiesVersion describes a run of the validator attribution, a new version is only created if the mapping changed
+type ValidatorEntitiesVersion struct {
+	Version    uint64    `db:"version"`
+	Ts         time.Time `db:"ts"`
+	Validators uint64    `db:"validators"`
+	Changes    uint64    `db:"changes"`
+}
+
+type PoolsAprPageData struct {
+	Days  uint64
+	Pools []*ApiPoolAprResponse
+}
+
+type PoolsRiskPageData struct {
+	Pools []*ApiPoolRiskResponse
+}
+
+// ValidatorMilestone is a milestone a validator reached on a day, detected by the statistics exporter
+type ValidatorMilestone struct {
+	ValidatorIndex uint64    `db:"validatorindex" json:"validatorindex"`
+	Milestone      string    `db:"milestone" json:"milestone"`
+	Day            uint64    `db:"day" json:"day"`
+	DetectedTs     time.Time `db:"detected_ts" json:"-"`
+}
+
+type ValidatorMilestoneDesc struct {
+	Label string
+	// Description completes a sentence starting with the validator, e.g. "Validator 1 proposed its first block"
+	Description string
+	Icon        string
+}
+
+// ValidatorMilestones are the milestones detected by the statistics exporter
+var ValidatorMilestones = map[string]ValidatorMilestoneDesc{
+	"first_proposal": {Label: "First Proposal", Description: "proposed its first block", Icon: "fa-cube"},
+	"first_eth":      {Label: "1 ETH Earned", Description: "earned its first ETH of rewards", Icon: "fa-coins"},
+	"one_year":       {Label: "One Year Active", Description: "has been validating for one year", Icon: "fa-birthday-cake"},
+	"perfect_month":  {Label: "Perfect Month", Description: "attested for 30 days without a single miss", Icon: "fa-bullseye"},
+}
+
+type ValidatorMilestonePageData struct {
+	ValidatorIndex uint64
+	Milestone      *ValidatorMilestone
+	Desc           ValidatorMilestoneDesc
+	DayStart       time.Time
+	ShareUrl       string
+}
+
+type ComparePageData struct {
+	Validators string
+	Entities   []string
+	Days       uint64
+	Available  []string
+	Comparison []*ApiComparisonResponse
+	Error      string
+}
+
+// DataExport is the dump of a dataset of a day in the object store of the data export
+type DataExport struct {
+	Dataset   string    `db:"dataset"`
+	Day       uint64    `db:"day"`
+	ObjectKey string    `db:"object_key"`
+	Rows      uint64    `db:"rows"`
+	SizeBytes uint64    `db:"size_bytes"`
+	CreatedTs time.Time `db:"created_ts"`
+}
+
+// Size returns the size of the dump in a human readable unit
+func (e *DataExport) Size() string {
+	units := []string{"B", "KB", "MB", "GB"}
+	size := float64(e.SizeBytes)
+	i := 0
+	for ; size >= 1024 && i < len(units)-1; i++ {
+		size /= 1024
+	}
+	return fmt.Sprintf("%.1f %s", size, units[i])
+}
+
+type DataPageDataset struct {
+	Name        string
+	Description string
+	Columns     []string
+	Format      string
+}
+
+type DataPageDay struct {
+	Day      uint64
+	DayStart time.Time
+	Exports  map[string]*DataExport
+}
+
+type DataPageData struct {
+	Datasets []DataPageDataset
+	Days     []*DataPageDay
+}
+
+type FaucetRequest struct {
+	Address []byte `db:"address"`
+	// Amount is in ether
+	Amount    float64   `db:"amount"`
+	TxHash    []byte    `db:"tx_hash"`
+	CreatedTs time.Time `db:"created_ts"`
+}
+
+type FaucetPageData struct {
+	Address        string
+	Balance        string
+	Amount         string
+	Cooldown       time.Duration
+	FlashMessage   string
+	RecaptchaKey   string
+	RecentRequests []*FaucetRequest
+}
+
+type ChainForksPageData struct {
+	Forks        []*ApiChainForkResponse
+	CurrentEpoch uint64
+}
+
+type LidoOperatorsPageData struct {
+	Module    string
+	Operators []*ApiLidoOperatorResponse
 }
 
 type GasNowPageData struct {
 	Code int `json:"code"`
 	Data struct {
-		Rapid     *big.Int `json:"rapid"`
-		Fast      *big.Int `json:"fast"`
-		Standard  *big.Int `json:"standard"`
-		Slow      *big.Int `json:"slow"`
-		Timestamp int64    `json:"timestamp"`
-		Price     float64  `json:"price,omitempty"`
-		PriceUSD  float64  `json:"priceUSD"`
-		Currency  string   `json:"currency,omitempty"`
+		Rapid        *big.Int            `json:"rapid"`
+		Fast         *big.Int            `json:"fast"`
+		Standard     *big.Int            `json:"standard"`
+		Slow         *big.Int            `json:"slow"`
+		BaseFee      *big.Int            `json:"baseFee,omitempty"`
+		BaseFeeTrend []*big.Int          `json:"baseFeeTrend,omitempty"`
+		PriorityFees *GasNowPriorityFees `json:"priorityFees,omitempty"`
+		Timestamp    int64               `json:"timestamp"`
+		Price        float64             `json:"price,omitempty"`
+		PriceUSD     float64             `json:"priceUSD"`
+		Currency     string              `json:"currency,omitempty"`
 	} `json:"data"`
 }
 
+// GasNowPriorityFees holds the suggested priority fees (in wei) per inclusion speed, derived from the priority fees paid in the most recent blocks
+type GasNowPriorityFees struct {
+	Rapid    *big.Int `json:"rapid"`
+	Fast     *big.Int `json:"fast"`
+	Standard *big.Int `json:"standard"`
+	Slow     *big.Int `json:"slow"`
+}
+
+// SearchResultType is the kind of entity a search result points to
+type SearchResultType string
+
+const (
+	SearchResultTypeValidator   SearchResultType = "validator"
+	SearchResultTypeBlock       SearchResultType = "block"
+	SearchResultTypeSlot        SearchResultType = "slot"
+	SearchResultTypeEpoch       SearchResultType = "epoch"
+	SearchResultTypeTransaction SearchResultType = "transaction"
+	SearchResultTypeAddress     SearchResultType = "address"
+	SearchResultTypeEns         SearchResultType = "ens"
+	SearchResultTypeGraffiti    SearchResultType = "graffiti"
+)
+
+// SearchResult is a single typed result of the unified search, results with a higher score are more relevant
+type SearchResult struct {
+	Type  SearchResultType `json:"type"`
+	Value string           `json:"value"`
+	Label string           `json:"label"`
+	Url   string           `json:"url"`
+	Score float64          `json:"score"`
+}
+
 type Eth1AddressSearchItem struct {
 	Address string `json:"address"`
 	Name    string `json:"name"`
@@ -582,6 +1050,54 @@ type MempoolTxPageData struct {
 	IsContractCreation bool
 }
 
+// MempoolStats holds the size, gas price percentiles (in GWei) and top spenders of the mempool at a point in time
+type MempoolStats struct {
+	Ts           time.Time `db:"ts"`
+	PendingCount uint64    `db:"pending_count"`
+	QueuedCount  uint64    `db:"queued_count"`
+	BaseFeeCount uint64    `db:"basefee_count"`
+	PendingGas   uint64    `db:"pending_gas"`
+	GasPriceP10  float64   `db:"gas_price_p10"`
+	GasPriceP25  float64   `db:"gas_price_p25"`
+	GasPriceP50  float64   `db:"gas_price_p50"`
+	GasPriceP75  float64   `db:"gas_price_p75"`
+	GasPriceP90  float64   `db:"gas_price_p90"`
+	TopSpenders  []*MempoolSpender
+}
+
+// NonExecutableCount returns the amount of queued transactions, including those whose fee cap is below the current base fee
+func (s *MempoolStats) NonExecutableCount() uint64 {
+	return s.QueuedCount + s.BaseFeeCount
+}
+
+// MempoolSpender aggregates the pending transactions of a sender, the total fees are the maximum fees (gas limit * gas price) the sender is willing to pay
+type MempoolSpender struct {
+	Address    []byte          `db:"address"`
+	TxCount    uint64          `db:"tx_count"`
+	TotalFees  decimal.Decimal `db:"total_fees"`
+	TotalValue decimal.Decimal `db:"total_value"`
+}
+
+// Eth1AddressEvent is an execution layer event of a watched address recorded by the eth1 indexer, the log index is -1 for transaction events
+type Eth1AddressEvent struct {
+	BlockNumber  uint64          `db:"block_number"`
+	Ts           time.Time       `db:"ts"`
+	TxHash       []byte          `db:"tx_hash"`
+	LogIndex     int64           `db:"log_index"`
+	Address      []byte          `db:"address"`
+	EventName    EventName       `db:"event_name"`
+	Counterparty []byte          `db:"counterparty"`
+	Token        []byte          `db:"token"`
+	Value        decimal.Decimal `db:"value"`
+}
+
+type MempoolPageData struct {
+	Table          *DataTableResponse
+	Stats          *MempoolStats
+	SizeSeries     []*GenericChartDataSeries
+	GasPriceSeries []*GenericChartDataSeries
+}
+
 type SyncCommitteesStats struct {
 	ParticipatedSlots uint64 `db:"participated_sync" json:"participatedSlots"`
 	MissedSlots       uint64 `db:"missed_sync" json:"missedSlots"`
@@ -620,3 +1136,11 @@ type ValidatorStateCountRow struct {
 	Name  string `db:"status"`
 	Count uint64 `db:"validator_count"`
 }
+
+// NetworkEpochMetrics are the network metrics of an epoch watched by the network anomaly detector
+type NetworkEpochMetrics struct {
+	Epoch             uint64  `db:"epoch"`
+	ParticipationRate float64 `db:"participation_rate"`
+	MissedSlotRate    float64 `db:"missed_slot_rate"`
+	AttestationDelay  float64 `db:"attestation_delay"`
+}
@@ -44,6 +44,7 @@ type PageData struct {
 	// IsUserClientUpdated   func(uint64) bool
 	ChainConfig         ClChainConfig
 	Lang                string
+	Languages           []string
 	NoAds               bool
 	Debug               bool
 	DebugTemplates      []string
@@ -54,6 +55,16 @@ type PageData struct {
 	MainMenuItems       []MainMenuItem
 	TermsOfServiceUrl   string
 	PrivacyPolicyUrl    string
+	// FeatureFlags are the feature flags enabled for the user of the request
+	FeatureFlags map[string]bool
+	Networks     []NetworkLink
+}
+
+// NetworkLink is an entry of the network switcher
+type NetworkLink struct {
+	Label  string
+	Url    string
+	Active bool
 }
 
 type MainMenuItem struct {
@@ -122,6 +133,9 @@ type Meta struct {
 	GATag       string
 	NoTrack     bool
 	Templates   string
+
+	// Image is the path of the preview image shown when the page is shared, the logo is shown if it is empty
+	Image string
 }
 
 // LatestState is a struct to hold data for the banner
@@ -200,6 +214,48 @@ type IndexPageData struct {
 	NewDepositProcessAfter    string
 }
 
+// EmbedPageData is the data of an embeddable widget served under /embed/
+type EmbedPageData struct {
+	Widget string
+	// Dark selects the dark theme, Accent is the hex color (without #) of highlighted values
+	Dark      bool
+	Accent    string
+	Validator *EmbedValidatorData
+	Network   *EmbedParticipationData
+	Queue     *EmbedQueueData
+}
+
+type EmbedValidatorData struct {
+	Index            uint64 `json:"validatorindex" db:"validatorindex"`
+	PublicKey        string `json:"pubkey" db:"pubkey"`
+	Name             string `json:"name" db:"name"`
+	Status           string `json:"status" db:"status"`
+	Balance          uint64 `json:"balance"`
+	EffectiveBalance uint64 `json:"effectivebalance"`
+	Epoch            uint64 `json:"epoch"`
+}
+
+// UserEmbedKey is the key of a signed embed url of a user, signed urls of revoked keys are rejected
+type UserEmbedKey struct {
+	Key       string     `json:"key" db:"key"`
+	Path      string     `json:"path" db:"path"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+type EmbedParticipationData struct {
+	Epoch         uint64  `json:"epoch" db:"epoch"`
+	Finalized     bool    `json:"finalized" db:"finalized"`
+	Participation float64 `json:"participation" db:"globalparticipationrate"`
+}
+
+type EmbedQueueData struct {
+	ActiveValidators   uint64 `json:"active_validators" db:"validatorscount"`
+	EnteringValidators uint64 `json:"entering_validators" db:"entering_validators_count"`
+	ExitingValidators  uint64 `json:"exiting_validators" db:"exiting_validators_count"`
+}
+
 type SlotVizPageData struct {
 	Epochs   []*SlotVizEpochs
 	Selector string
@@ -331,6 +387,7 @@ type ValidatorPageData struct {
 	PublicKey                                []byte `db:"pubkey"`
 	WithdrawableEpoch                        uint64 `db:"withdrawableepoch"`
 	WithdrawCredentials                      []byte `db:"withdrawalcredentials"`
+	WithdrawalAddressName                    string
 	CurrentBalance                           uint64 `db:"balance"`
 	BalanceActivation                        uint64 `db:"balanceactivation"`
 	EffectiveBalance                         uint64 `db:"effectivebalance"`
@@ -388,11 +445,13 @@ type ValidatorPageData struct {
 	ExecutionIncomeHistoryData               []*ChartDataPoint
 	Deposits                                 *ValidatorDeposits
 	Eth1DepositAddress                       []byte
+	DepositOrigin                            *DepositOrigin
+	DepositWarnings                          []*Eth1DepositWarning
 	FlashMessage                             string
 	Watchlist                                []*TaggedValidators
 	SubscriptionFlash                        []interface{}
 	User                                     *User
-	AttestationInclusionEffectiveness        float64
+	EffectivenessScore                       *ValidatorEffectivenessScore
 	CsrfField                                template.HTML
 	NetworkStats                             *IndexPageData
 	ChurnRate                                uint64
@@ -404,13 +463,22 @@ type ValidatorPageData struct {
 	LongestAttestationStreak                 uint64
 	IsRocketpool                             bool
 	Rocketpool                               *RocketpoolValidatorPageData
+	MevIncome                                *ValidatorMevIncome
+	Dvt                                      *ValidatorDvtCluster
+	Percentiles                              *ValidatorPercentiles
+	Luck                                     *ApiValidatorLuckResponse
+	Milestones                               []*ValidatorMilestone
 	ShowMultipleWithdrawalCredentialsWarning bool
 	CappellaHasHappened                      bool
 	BLSChange                                *BLSChange
+	CredentialChanges                        []*ValidatorsBLSChange
 	IsWithdrawableAddress                    bool
 	EstimatedNextWithdrawal                  template.HTML
 	AddValidatorWatchlistModal               *AddValidatorWatchlistModal
 	NextWithdrawalRow                        [][]interface{}
+	NextWithdrawal                           *WithdrawalEstimate
+	ExitReadiness                            *ExitReadiness
+	RelayRegistrations                       []*RelayValidatorRegistration
 	ValidatorProposalData
 }
 
@@ -540,6 +608,32 @@ type ValidatorPerformance struct {
 	TotalCount      uint64 `db:"total_count"`
 }
 
+// ValidatorLeaderboardFilter selects the window, ranking mode and validators of the validator leaderboard
+type ValidatorLeaderboardFilter struct {
+	WindowDays    uint64
+	Mode          string
+	Bottom        bool
+	Entity        string
+	MinActiveDays uint64
+}
+
+type ValidatorLeaderboardEntry struct {
+	ValidatorIndex uint64   `db:"validatorindex" json:"validatorindex"`
+	PublicKey      []byte   `db:"pubkey" json:"-"`
+	Name           string   `db:"name" json:"name"`
+	Entity         string   `db:"entity" json:"entity"`
+	ActiveDays     uint64   `db:"active_days" json:"active_days"`
+	IncomeGwei     int64    `db:"income_gwei" json:"income_gwei"`
+	Efficiency     *float64 `db:"efficiency" json:"efficiency"`
+	TotalCount     uint64   `db:"total_count" json:"-"`
+}
+
+type ValidatorsLeaderboardPageData struct {
+	Filter   *ValidatorLeaderboardFilter
+	Windows  []uint64
+	Entities []string
+}
+
 // ValidatorAttestation is a struct for the validators attestations data
 type ValidatorAttestation struct {
 	Index          uint64
@@ -674,6 +768,8 @@ type BlockPageData struct {
 	Tags       TagMetadataSlice `db:"tags"`
 	IsValidMev bool             `db:"is_valid_mev"`
 	ValidatorProposalInfo
+
+	AttestationPacking *BlockAttestationPacking
 }
 
 func (u *BlockPageData) MarshalJSON() ([]byte, error) {
@@ -944,6 +1040,19 @@ type GenericChartData struct {
 	Series                          []*GenericChartDataSeries `json:"series"`
 	Drilldown                       interface{}               `json:"drilldown"`
 	Footer                          string                    `json:"footer"`
+	XAxisPlotBands                  []*ChartPlotBand          `json:"x_axis_plot_bands,omitempty"`
+}
+
+// ChartPlotBand highlights a range of the x axis of a chart, e.g. a network incident
+type ChartPlotBand struct {
+	From  int64               `json:"from"`
+	To    int64               `json:"to"`
+	Color string              `json:"color"`
+	Label *ChartPlotBandLabel `json:"label,omitempty"`
+}
+
+type ChartPlotBandLabel struct {
+	Text string `json:"text"`
 }
 
 type SeriesDataItem struct {
@@ -1098,11 +1207,43 @@ type ValidatorSlashing struct {
 	Type                   string        `db:"type" json:"type"`
 }
 
+// ExitsPageExit is a struct to hold a voluntary exit shown on the exits page
+type ExitsPageExit struct {
+	Slot              uint64 `db:"slot"`
+	ValidatorIndex    uint64 `db:"validatorindex"`
+	ExitEpoch         uint64 `db:"exitepoch"`
+	WithdrawableEpoch uint64 `db:"withdrawableepoch"`
+	Balance           uint64 `db:"balance"`
+}
+
+// ExitsPageData is a struct to hold the exit queue summary of the exits page
+type ExitsPageData struct {
+	QueueLength   uint64
+	LastExitEpoch uint64
+	LastExitTime  time.Time
+	ChurnLimit    uint64
+	TotalExits    uint64
+}
+
 type StakingCalculatorPageData struct {
-	BestValidatorBalanceHistory *[]ValidatorBalanceHistory
-	WatchlistBalanceHistory     [][]interface{}
-	TotalStaked                 uint64
-	EtherscanApiBaseUrl         string
+	Network *StakingCalculatorNetworkStats
+	// ActivationBalance and CompoundingMaxBalance are the balances of 0x01 and 0x02 validators in Currency
+	ActivationBalance     float64
+	CompoundingMaxBalance float64
+	Currency              string
+}
+
+// StakingCalculatorNetworkStats are the current network statistics the staking calculator bases its projections on,
+// aprs are fractions and amounts are in ETH
+type StakingCalculatorNetworkStats struct {
+	ConsensusApr          float64 `db:"consensus_apr" json:"consensus_apr"`
+	ExecutionApr          float64 `db:"execution_apr" json:"execution_apr"`
+	MevBlockShare         float64 `db:"mev_block_share" json:"mev_block_share"`
+	AvgMevPerBlock        float64 `db:"avg_mev_per_block" json:"avg_mev_per_block"`
+	TotalStaked           uint64  `db:"-" json:"total_staked"`
+	EnteringValidators    uint64  `db:"-" json:"entering_validators"`
+	ActivationChurnLimit  uint64  `db:"-" json:"activation_churn_limit"`
+	ActivationWaitingDays float64 `db:"-" json:"activation_waiting_days"`
 }
 
 type DepositsPageData struct {
@@ -1130,6 +1271,7 @@ type EthOneDepositsData struct {
 	MerkletreeIndex       []byte    `db:"merkletree_index"`
 	State                 string    `db:"state"`
 	ValidSignature        bool      `db:"valid_signature"`
+	Warning               string    `db:"warning"`
 }
 
 type EthOneDepositLeaderboardData struct {
@@ -1173,6 +1315,7 @@ type User struct {
 	Authenticated bool   `json:"authenticated"`
 	Subscription  string `json:"subscription"`
 	UserGroup     string `json:"user_group"`
+	Locale        string `json:"locale"`
 }
 
 type UserSubscription struct {
@@ -1228,6 +1371,9 @@ type UserSettingsPageData struct {
 	Diamond             *string
 	ShareMonitoringData bool
 	ApiStatistics       *ApiStatistics
+	// Locale is the language of the emails of the user, it is one of Locales
+	Locale  string
+	Locales []string
 }
 
 type PairedDevice struct {
@@ -1236,11 +1382,173 @@ type PairedDevice struct {
 	NotifyEnabled bool      `json:"notify_enabled"`
 	Active        bool      `json:"active"`
 	AppName       string    `json:"app_name"`
+	Scope         string    `json:"scope,omitempty"`
 	CreatedAt     time.Time `json:"created_ts"`
 }
 
+// UserAuditAction is the kind of a security relevant change of a user account recorded in the audit log
+type UserAuditAction string
+
+const (
+	UserAuditLogin                       UserAuditAction = "login"
+	UserAuditLoginFailed                 UserAuditAction = "login_failed"
+	UserAuditLogout                      UserAuditAction = "logout"
+	UserAuditSessionRevoked              UserAuditAction = "session_revoked"
+	UserAuditPasswordChanged             UserAuditAction = "password_changed"
+	UserAuditEmailChanged                UserAuditAction = "email_changed"
+	UserAuditApiKeyCreated               UserAuditAction = "api_key_created"
+	UserAuditNotificationsChanged        UserAuditAction = "notifications_changed"
+	UserAuditNotificationChannelsChanged UserAuditAction = "notification_channels_changed"
+	UserAuditWebhookAdded                UserAuditAction = "webhook_added"
+	UserAuditWebhookUpdated              UserAuditAction = "webhook_updated"
+	UserAuditWebhookDeleted              UserAuditAction = "webhook_deleted"
+	UserAuditAppAuthorized               UserAuditAction = "app_authorized"
+	UserAuditAppRevoked                  UserAuditAction = "app_revoked"
+	UserAuditSecurityKeyAdded            UserAuditAction = "security_key_added"
+	UserAuditSecurityKeyRemoved          UserAuditAction = "security_key_removed"
+	UserAuditRecoveryCodesGenerated      UserAuditAction = "recovery_codes_generated"
+	UserAuditRecoveryCodeUsed            UserAuditAction = "recovery_code_used"
+	UserAuditOrganizationCreated         UserAuditAction = "organization_created"
+	UserAuditOrganizationDeleted         UserAuditAction = "organization_deleted"
+	UserAuditOrganizationInviteSent      UserAuditAction = "organization_invite_sent"
+	UserAuditOrganizationInviteRevoked   UserAuditAction = "organization_invite_revoked"
+	UserAuditOrganizationJoined          UserAuditAction = "organization_joined"
+	UserAuditOrganizationLeft            UserAuditAction = "organization_left"
+	UserAuditOrganizationRoleChanged     UserAuditAction = "organization_role_changed"
+	UserAuditOrganizationMemberRemoved   UserAuditAction = "organization_member_removed"
+	UserAuditOrganizationShareChanged    UserAuditAction = "organization_share_changed"
+)
+
+// UserAuditDetails describes the change of an audit log entry, e.g. the affected webhook or event
+type UserAuditDetails map[string]interface{}
+
+func (e *UserAuditDetails) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, &e)
+}
+
+func (a UserAuditDetails) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	return json.Marshal(a)
+}
+
+type UserAuditLogEntry struct {
+	Id        uint64           `json:"id" db:"id"`
+	Action    UserAuditAction  `json:"action" db:"action"`
+	Ip        string           `json:"ip" db:"ip"`
+	UserAgent string           `json:"user_agent" db:"user_agent"`
+	Details   UserAuditDetails `json:"details,omitempty" db:"details"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+}
+
+// UserSession is a web session of a user, sessions of the mobile app are managed as paired devices
+type UserSession struct {
+	Id        uint64    `json:"id" db:"id"`
+	Ip        string    `json:"ip" db:"ip"`
+	UserAgent string    `json:"user_agent" db:"user_agent"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	Current   bool      `json:"current" db:"-"`
+	TokenHash []byte    `json:"-" db:"token_hash"`
+}
+
+// UserWebAuthnCredential is a security key or passkey of a user, used as second factor or for passwordless login
+type UserWebAuthnCredential struct {
+	ID           uint64     `json:"id" db:"id"`
+	UserID       uint64     `json:"-" db:"user_id"`
+	CredentialID []byte     `json:"-" db:"credential_id"`
+	PublicKey    []byte     `json:"-" db:"public_key"`
+	Algorithm    int64      `json:"algorithm" db:"algorithm"`
+	SignCount    uint32     `json:"-" db:"sign_count"`
+	Name         string     `json:"name" db:"name"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt   *time.Time `json:"last_used_at" db:"last_used_at"`
+}
+
+// OrganizationRole is the role of a member of an organization
+type OrganizationRole string
+
+const (
+	OrganizationRoleOwner  OrganizationRole = "owner"
+	OrganizationRoleAdmin  OrganizationRole = "admin"
+	OrganizationRoleViewer OrganizationRole = "viewer"
+)
+
+// CanManage returns true if the role may invite and remove members, edit the dashboards and share webhooks of the organization
+func (r OrganizationRole) CanManage() bool {
+	return r == OrganizationRoleOwner || r == OrganizationRoleAdmin
+}
+
+// Organization is a team of users sharing dashboards, webhooks and the api quota of its owner
+type Organization struct {
+	ID        uint64           `json:"id" db:"id"`
+	Name      string           `json:"name" db:"name"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+	Role      OrganizationRole `json:"role" db:"role"` // the role of the requesting user
+}
+
+type OrganizationMember struct {
+	UserID   uint64           `json:"-" db:"user_id"`
+	Email    string           `json:"email" db:"email"`
+	Role     OrganizationRole `json:"role" db:"role"`
+	JoinedAt time.Time        `json:"joined_at" db:"joined_at"`
+}
+
+type OrganizationInvite struct {
+	ID               uint64           `db:"id"`
+	OrganizationID   uint64           `db:"organization_id"`
+	OrganizationName string           `db:"organization_name"`
+	Email            string           `db:"email"`
+	Role             OrganizationRole `db:"role"`
+	CreatedAt        time.Time        `db:"created_at"`
+}
+
+// OrganizationWebhook is a webhook of a member that can be shared with the organization, the url is not exposed as it
+// usually contains a secret
+type OrganizationWebhook struct {
+	ID          uint64         `db:"id"`
+	UserID      uint64         `db:"user_id"`
+	Destination string         `db:"destination"`
+	EventNames  pq.StringArray `db:"event_names"`
+	Shared      bool           `db:"shared"`
+}
+
+type UserOrganizationPageData struct {
+	Organization *Organization
+	Members      []*OrganizationMember
+	Invites      []*OrganizationInvite
+	// PendingInvites are the invites to the email of the user, only shown if the user is not part of an organization yet
+	PendingInvites []*OrganizationInvite
+	Webhooks       []*OrganizationWebhook
+	UserID         uint64
+	Flashes        []interface{}
+	CsrfField      template.HTML
+}
+
+type UserSecurityPageData struct {
+	Sessions          []*UserSession
+	PairedDevices     []PairedDevice
+	AuditLog          []*UserAuditLogEntry
+	Credentials       []*UserWebAuthnCredential
+	RecoveryCodesLeft int
+	TwoFactorRequired bool
+	Flashes           []interface{}
+	CsrfField         template.HTML
+}
+
 type UserAuthorizeConfirmPageData struct {
-	AppData *OAuthAppData
+	AppData           *OAuthAppData
+	Scope             string
+	ScopeDescriptions []string
+	CodeChallenge     string
 	AuthData
 }
 
@@ -1476,11 +1784,38 @@ type AdConfigurationPageData struct {
 	TemplateNames  []string
 }
 
+type ValidatorNameClaimsPageData struct {
+	Claims    []*ValidatorNameClaim
+	Status    string
+	CsrfField template.HTML
+}
+
+type AddressLabelsPageData struct {
+	Labels     []*AddressLabel
+	Status     string
+	Categories []string
+	CsrfField  template.HTML
+}
+
 type ExplorerConfigurationPageData struct {
 	Configurations ExplorerConfigurationMap
 	CsrfField      template.HTML
 }
 
+type FeatureFlagsPageData struct {
+	Flags     []*FeatureFlag
+	CsrfField template.HTML
+}
+
+// FeatureFlag enables a feature for RolloutPercentage percent of the users while Enabled is set
+type FeatureFlag struct {
+	Name              string    `db:"name" json:"name"`
+	Enabled           bool      `db:"enabled" json:"enabled"`
+	RolloutPercentage int       `db:"rollout_percentage" json:"rollout_percentage"`
+	Description       string    `db:"description" json:"description"`
+	UpdatedTs         time.Time `db:"updated_ts" json:"updated_ts"`
+}
+
 type UserWebhookRowError struct {
 	SummaryRequest  template.HTML
 	SummaryResponse template.HTML
@@ -1510,6 +1845,7 @@ type PoolsResp struct {
 	PoolsDistribution       ChartsPageDataChart
 	HistoricPoolPerformance ChartsPageDataChart
 	PoolInfos               []*PoolInfo
+	EntitiesVersion         *ValidatorEntitiesVersion
 }
 
 type PoolsData struct {
@@ -1526,6 +1862,7 @@ type PoolInfo struct {
 	EthstoreComparison1d  float64
 	EthstoreComparison7d  float64
 	EthstoreComparison31d float64
+	RiskScore             *float64
 }
 
 type AddValidatorWatchlistModal struct {
@@ -1589,6 +1926,10 @@ type DataTableSaveStateColumns struct {
 type Eth1AddressPageData struct {
 	Address            string `json:"address"`
 	EnsName            string `json:"ensName"`
+	Labels             []*AddressLabel
+	LabelCategories    []string
+	LabelSubmissions   bool
+	LabelFlashMessage  string
 	IsContract         bool
 	QRCode             string `json:"qr_code_base64"`
 	QRCodeInverse      string
@@ -1599,14 +1940,48 @@ type Eth1AddressPageData struct {
 	TransactionsTable  *DataTableResponse
 	BlobTxnsTable      *DataTableResponse
 	InternalTxnsTable  *DataTableResponse
+	UserOpsTable       *DataTableResponse
 	Erc20Table         *DataTableResponse
 	Erc721Table        *DataTableResponse
 	Erc1155Table       *DataTableResponse
 	WithdrawalsTable   *DataTableResponse
+	NFTs               []*Eth1AddressNFT
+	VerifiedContract   *VerifiedContract
+	HasContractReads   bool
+	FlashMessage       string
+	CsrfField          template.HTML
+	NotificationEvents []EventNameDesc
+	SubscribedEvents   map[EventName]bool
 	EtherValue         template.HTML
 	Tabs               []Eth1AddressPageTabs
 }
 
+const VerifiedContractSourceSourcify = "sourcify"
+
+// VerifiedContract holds the abi and source files of a contract whose source has been verified against its deployed bytecode
+type VerifiedContract struct {
+	Address         []byte    `db:"address"`
+	Name            string    `db:"name"`
+	CompilerVersion string    `db:"compiler_version"`
+	MatchType       string    `db:"match_type"`
+	ABIJson         []byte    `db:"abi"`
+	Source          string    `db:"source"`
+	VerifiedTs      time.Time `db:"verified_ts"`
+	Files           []*VerifiedContractFile
+}
+
+type VerifiedContractFile struct {
+	Path    string `db:"path"`
+	Content string `db:"content"`
+}
+
+// ContractReadResult holds the outputs of a call to a view function of a contract that does not take any inputs
+type ContractReadResult struct {
+	Name    string
+	Outputs []*Eth1DecodedParam
+	Error   string
+}
+
 type ContractInteractionType uint8
 
 const (
@@ -1658,6 +2033,32 @@ type ERC20Metadata struct {
 	Price        []byte
 }
 
+const (
+	NFTStandardERC721  = "ERC721"
+	NFTStandardERC1155 = "ERC1155"
+)
+
+// Eth1AddressNFT is a single erc721 or erc1155 token owned by an address
+type Eth1AddressNFT struct {
+	Standard     string
+	TokenAddress []byte
+	TokenId      *big.Int
+	Balance      *big.Int
+	Metadata     *NFTMetadata
+}
+
+// NFTMetadata is the metadata json a token uri points to, see https://eips.ethereum.org/EIPS/eip-721#specification
+type NFTMetadata struct {
+	TokenURI    string `json:"token_uri,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Image       string `json:"image"`
+}
+
+func (metadata NFTMetadata) MarshalBinary() ([]byte, error) {
+	return json.Marshal(metadata)
+}
+
 func (metadata ERC20Metadata) MarshalBinary() ([]byte, error) {
 	return json.Marshal(metadata)
 }
@@ -1730,6 +2131,8 @@ type Eth1TxData struct {
 	InternalTxns []ITransaction
 	FromName     string
 	ToName       string
+	FromLabels   []*AddressLabel
+	ToLabels     []*AddressLabel
 	Gas          struct {
 		BlockBaseFee   []byte
 		MaxFee         []byte
@@ -1758,13 +2161,29 @@ type Eth1TxData struct {
 	TargetIsContract            bool
 	IsContractCreation          bool
 	CallData                    string
+	DecodedCallData             *Eth1DecodedCallData
 	Method                      string
 	Events                      []*Eth1EventData
 	Transfers                   []*Transfer
 	DepositContractInteractions []DepositContractInteraction
+	DepositWarnings             []*Eth1DepositWarning
 	CurrentEtherPrice           template.HTML
 	HistoricalEtherPrice        template.HTML
 	BlobHashes                  [][]byte
+	UserOperations              []*Eth1UserOperationIndexed
+	L2BridgeTx                  *Eth1L2BridgeTxIndexed
+}
+
+type Eth1DecodedCallData struct {
+	Signature string
+	Params    []*Eth1DecodedParam
+}
+
+type Eth1DecodedParam struct {
+	Name    string
+	Type    string
+	Value   string
+	Address common.Address
 }
 
 type Eth1EventData struct {
@@ -1885,6 +2304,8 @@ type Eth1BlockPageData struct {
 	Txs                   []Eth1BlockPageTransaction
 	Uncles                []Eth1BlockPageData
 	State                 string
+	RelayAnomalies        []*RelayAnomaly
+	Production            *BlockProduction
 }
 
 type Eth1BlockPageTransaction struct {
@@ -1917,11 +2338,14 @@ type SlotVizEpochs struct {
 }
 
 type RelaysResp struct {
-	RelaysInfoContainers [3]RelayInfoContainer
-	RecentBlocks         []*RelaysRespBlock
-	TopBlocks            []*RelaysRespBlock
-	LastUpdated          time.Time
-	TopBuilders          []*struct {
+	RelaysInfoContainers     [3]RelayInfoContainer
+	RecentBlocks             []*RelaysRespBlock
+	TopBlocks                []*RelaysRespBlock
+	RelayMarketShareSeries   []*GenericChartDataSeries
+	BuilderMarketShareSeries []*GenericChartDataSeries
+	CensoringShareSeries     []*GenericChartDataSeries
+	LastUpdated              time.Time
+	TopBuilders              []*struct {
 		Tags       TagMetadataSlice `db:"tags"`
 		Builder    []byte           `db:"builder_pubkey"`
 		BlockCount uint64           `db:"c"`
@@ -1931,13 +2355,14 @@ type RelaysResp struct {
 }
 
 type RelaysRespBlock struct {
-	Tags                 TagMetadataSlice `db:"tags"`
-	Value                WeiString        `db:"value"`
-	Slot                 uint64           `db:"slot"`
-	Builder              []byte           `db:"builder_pubkey"`
-	ProposerFeeRecipient []byte           `db:"proposer_fee_recipient"`
-	Proposer             uint64           `db:"proposer"`
-	BlockExtraData       string           `db:"block_extra_data"`
+	Tags                     TagMetadataSlice `db:"tags"`
+	Value                    WeiString        `db:"value"`
+	Slot                     uint64           `db:"slot"`
+	Builder                  []byte           `db:"builder_pubkey"`
+	ProposerFeeRecipient     []byte           `db:"proposer_fee_recipient"`
+	ProposerFeeRecipientName string
+	Proposer                 uint64 `db:"proposer"`
+	BlockExtraData           string `db:"block_extra_data"`
 }
 
 type RelayInfoContainer struct {
@@ -1962,6 +2387,355 @@ type RelayInfo struct {
 	MaxValueSlot   uint64         `db:"max_value_slot"`
 }
 
+const (
+	RelayAnomalyBelowWinningBid           = "below_winning_bid"
+	RelayAnomalyLocalBlockBelowWinningBid = "local_block_below_winning_bid"
+)
+
+type RelayAnomaly struct {
+	Slot                uint64         `db:"block_slot" json:"slot"`
+	BlockRoot           []byte         `db:"block_root" json:"-"`
+	ExecBlockHash       []byte         `db:"exec_block_hash" json:"-"`
+	Type                string         `db:"anomaly_type" json:"type"`
+	Relay               sql.NullString `db:"tag_id" json:"-"`
+	DeliveredValue      WeiString      `db:"delivered_value" json:"delivered_value"`
+	WinningBidRelay     string         `db:"winning_bid_tag_id" json:"winning_bid_relay"`
+	WinningBidBlockHash []byte         `db:"winning_bid_block_hash" json:"-"`
+	WinningBidValue     WeiString      `db:"winning_bid_value" json:"winning_bid_value"`
+	Proposer            uint64         `db:"proposer" json:"proposer"`
+}
+
+// Difference returns the amount in wei the proposer missed compared to the winning bid
+func (a *RelayAnomaly) Difference() *big.Int {
+	return new(big.Int).Sub(a.WinningBidValue.BigInt(), a.DeliveredValue.BigInt())
+}
+
+// BlockProduction is the production path of a proposed block from the builder bid over the relay to the proposal. Blocks
+// that were not delivered by any relay are locally built, their realized value is the reward of the fee recipient.
+type BlockProduction struct {
+	Slot                    uint64         `db:"block_slot"`
+	BlockRoot               []byte         `db:"block_root"`
+	ExecBlockHash           []byte         `db:"exec_block_hash"`
+	Proposer                uint64         `db:"proposer"`
+	LocallyBuilt            bool           `db:"locally_built"`
+	Relay                   sql.NullString `db:"relay_tag_id"`
+	BuilderPubkey           []byte         `db:"builder_pubkey"`
+	DeliveredValue          WeiString      `db:"delivered_value"`
+	FeeRecipientReward      WeiString      `db:"fee_recipient_reward"`
+	WinningBidRelay         sql.NullString `db:"winning_bid_tag_id"`
+	WinningBidBuilderPubkey []byte         `db:"winning_bid_builder_pubkey"`
+	WinningBidBlockHash     []byte         `db:"winning_bid_block_hash"`
+	WinningBidValue         WeiString      `db:"winning_bid_value"`
+	UpdatedTs               time.Time      `db:"updated_ts"`
+}
+
+// RealizedValue returns the value in wei the proposer received for the block
+func (p *BlockProduction) RealizedValue() *big.Int {
+	if p.LocallyBuilt {
+		return p.FeeRecipientReward.BigInt()
+	}
+	return p.DeliveredValue.BigInt()
+}
+
+// MissedValue returns the value in wei the proposer missed compared to the winning bid, zero if it got at least the bid
+func (p *BlockProduction) MissedValue() *big.Int {
+	if !p.WinningBidRelay.Valid {
+		return new(big.Int)
+	}
+	missed := new(big.Int).Sub(p.WinningBidValue.BigInt(), p.RealizedValue())
+	if missed.Sign() < 0 {
+		return new(big.Int)
+	}
+	return missed
+}
+
+type RelaysMarketShareHistory struct {
+	Days     []*RelaysDailyOverview `json:"days"`
+	Relays   []*RelayDailyStats     `json:"relays"`
+	Builders []*BuilderDailyStats   `json:"builders"`
+}
+
+// StakingFlowsHistory holds the daily deposits and withdrawals of the network and of the validator entities, amounts are in Gwei
+type StakingFlowsHistory struct {
+	Days     []*StakingFlowsDailyStats       `json:"days"`
+	Entities []*StakingFlowsEntityDailyStats `json:"entities"`
+}
+
+type StakingFlowsDailyStats struct {
+	Day                     uint64 `db:"day" json:"day"`
+	DepositCount            uint64 `db:"deposit_count" json:"deposit_count"`
+	DepositAmount           uint64 `db:"deposit_amount" json:"deposit_amount"`
+	FullWithdrawalCount     uint64 `db:"full_withdrawal_count" json:"full_withdrawal_count"`
+	FullWithdrawalAmount    uint64 `db:"full_withdrawal_amount" json:"full_withdrawal_amount"`
+	PartialWithdrawalAmount uint64 `db:"partial_withdrawal_amount" json:"partial_withdrawal_amount"`
+	NetFlow                 int64  `json:"net_flow"`
+}
+
+type StakingFlowsEntityDailyStats struct {
+	Day                  uint64 `db:"day" json:"day"`
+	Entity               string `db:"entity" json:"entity"`
+	DepositCount         uint64 `db:"deposit_count" json:"deposit_count"`
+	DepositAmount        uint64 `db:"deposit_amount" json:"deposit_amount"`
+	FullWithdrawalCount  uint64 `db:"full_withdrawal_count" json:"full_withdrawal_count"`
+	FullWithdrawalAmount uint64 `db:"full_withdrawal_amount" json:"full_withdrawal_amount"`
+	NetFlow              int64  `json:"net_flow"`
+}
+
+// L2BridgeFlowsDailyStats holds the daily deposits into and withdrawals out of the bridge contracts of a layer 2
+// network, amounts are in wei
+type L2BridgeFlowsDailyStats struct {
+	Day              uint64          `db:"day" json:"day"`
+	Network          string          `db:"network" json:"network"`
+	DepositCount     uint64          `db:"deposit_count" json:"deposit_count"`
+	DepositAmount    decimal.Decimal `db:"deposit_amount" json:"deposit_amount"`
+	WithdrawalCount  uint64          `db:"withdrawal_count" json:"withdrawal_count"`
+	WithdrawalAmount decimal.Decimal `db:"withdrawal_amount" json:"withdrawal_amount"`
+	NetFlow          decimal.Decimal `json:"net_flow"`
+}
+
+type RelaysDailyOverview struct {
+	Day                 uint64 `db:"day" json:"day"`
+	BlockCount          uint64 `db:"block_count" json:"block_count"`
+	CensoringBlockCount uint64 `db:"censoring_block_count" json:"censoring_block_count"`
+}
+
+type RelayDailyStats struct {
+	Day         uint64    `db:"day" json:"day"`
+	Relay       string    `db:"tag_id" json:"relay"`
+	BlockCount  uint64    `db:"block_count" json:"block_count"`
+	TotalValue  WeiString `db:"total_value" json:"total_value"`
+	MarketShare float64   `db:"market_share" json:"market_share"`
+}
+
+type BuilderDailyStats struct {
+	Day           uint64    `db:"day" json:"day"`
+	BuilderPubkey string    `db:"builder_pubkey" json:"builder_pubkey"`
+	ExtraData     string    `db:"extra_data" json:"extra_data"`
+	BlockCount    uint64    `db:"block_count" json:"block_count"`
+	TotalValue    WeiString `db:"total_value" json:"total_value"`
+	AvgBidValue   WeiString `db:"avg_bid_value" json:"avg_bid_value"`
+	MarketShare   float64   `db:"market_share" json:"market_share"`
+}
+
+type ValidatorMevIncome struct {
+	ValidatorIndex uint64                     `db:"validatorindex" json:"validatorindex"`
+	BuilderBlocks  uint64                     `db:"builder_blocks" json:"builder_blocks"`
+	LocalBlocks    uint64                     `db:"local_blocks" json:"local_blocks"`
+	TotalValue     WeiString                  `db:"mev_value" json:"total_mev_value"`
+	Relays         []*ValidatorMevIncomeRelay `json:"relays"`
+}
+
+type ValidatorMevIncomeRelay struct {
+	Relay      string    `db:"tag_id" json:"relay"`
+	BlockCount uint64    `db:"block_count" json:"block_count"`
+	TotalValue WeiString `db:"value" json:"total_value"`
+}
+
+// ValidatorDvtCluster is the distributed validator cluster (ssv or obol) running a validator, operators are
+// the operator ids for ssv and the operator addresses for obol
+type ValidatorDvtCluster struct {
+	Protocol    string         `db:"protocol" json:"protocol"`
+	ClusterId   string         `db:"cluster_id" json:"cluster_id"`
+	ClusterName string         `db:"cluster_name" json:"cluster_name"`
+	Operators   pq.StringArray `db:"operators" json:"operators"`
+	UpdatedTs   time.Time      `db:"updated_ts" json:"updated_ts"`
+}
+
+// ValidatorPercentiles are the average daily percentile ranks (0-100, higher is better) of validators over the last exported days
+type ValidatorPercentiles struct {
+	Days                   uint64  `db:"days" json:"days"`
+	AttestationPercentile  float64 `db:"attestation_percentile" json:"attestation_percentile"`
+	ProposalLuckPercentile float64 `db:"proposal_luck_percentile" json:"proposal_luck_percentile"`
+	IncomePercentile       float64 `db:"income_percentile" json:"income_percentile"`
+}
+
+// PoolLuckHistory is the proposal and sync committee luck of a pool on a day, a luck of 1 means exactly as many assignments as expected
+type PoolLuckHistory struct {
+	Day          uint64  `db:"day"`
+	Pool         string  `db:"pool"`
+	ProposalLuck float64 `db:"proposal_luck"`
+	SyncLuck     float64 `db:"sync_luck"`
+}
+
+type RelayAnomaliesPageData struct {
+	Anomalies   []*RelayAnomaly
+	LastUpdated time.Time
+}
+
+const (
+	CensorshipEntityNetwork = "network"
+	CensorshipEntityRelay   = "relay"
+	CensorshipEntityBuilder = "builder"
+	// CensorshipEntityLocalBuilder is used as builder entity for blocks that were not delivered by any known relay
+	CensorshipEntityLocalBuilder = "local"
+)
+
+type CensorshipWatchedTx struct {
+	TxHash              []byte    `db:"tx_hash" json:"-"`
+	FromAddress         []byte    `db:"from_address" json:"-"`
+	ToAddress           []byte    `db:"to_address" json:"-"`
+	FirstSeen           time.Time `db:"first_seen_ts" json:"first_seen"`
+	IncludedBlockNumber uint64    `db:"included_block_number" json:"-"`
+	IncludedSlot        uint64    `db:"included_slot" json:"-"`
+	InclusionDelay      uint64    `db:"inclusion_delay" json:"-"`
+	SkippedBlocks       uint64    `db:"skipped_blocks" json:"-"`
+	Proposer            uint64    `db:"proposer" json:"-"`
+	Relay               string    `db:"relay_tag_id" json:"-"`
+	BuilderPubkey       []byte    `db:"builder_pubkey" json:"-"`
+}
+
+type CensorshipDailyStats struct {
+	Day                 uint64          `db:"day" json:"day"`
+	EntityType          string          `db:"entity_type" json:"entity_type"`
+	Entity              string          `db:"entity" json:"entity"`
+	BlockCount          uint64          `db:"block_count" json:"block_count"`
+	WatchedTxBlockCount uint64          `db:"watched_tx_block_count" json:"watched_tx_block_count"`
+	WatchedTxCount      uint64          `db:"watched_tx_count" json:"watched_tx_count"`
+	AvgInclusionDelay   sql.NullFloat64 `db:"avg_inclusion_delay" json:"-"`
+	AvgSkippedBlocks    sql.NullFloat64 `db:"avg_skipped_blocks" json:"-"`
+	BuilderExtraData    string          `db:"builder_extra_data" json:"-"`
+	Name                string          `db:"-" json:"-"`
+}
+
+// InclusionShare returns the share of blocks of the entity that included at least one watched transaction
+func (s *CensorshipDailyStats) InclusionShare() float64 {
+	if s.BlockCount == 0 {
+		return 0
+	}
+	return float64(s.WatchedTxBlockCount) / float64(s.BlockCount)
+}
+
+type CensorshipPageData struct {
+	Day         uint64
+	DayStart    time.Time
+	Network     *CensorshipDailyStats
+	Relays      []*CensorshipDailyStats
+	Builders    []*CensorshipDailyStats
+	RecentTxs   []*CensorshipWatchedTx
+	DelaySeries []*GenericChartDataSeries
+	LastUpdated time.Time
+}
+
+// BlockAttestationPacking holds how well the proposer of a block packed the available attestation votes
+type BlockAttestationPacking struct {
+	Slot          uint64  `db:"slot" json:"slot"`
+	Proposer      uint64  `db:"proposer" json:"proposer"`
+	IncludedVotes uint64  `db:"included_votes" json:"included_votes"`
+	MissedVotes   uint64  `db:"missed_votes" json:"missed_votes"`
+	LateVotes     uint64  `db:"late_votes" json:"late_votes"`
+	Score         float64 `db:"score" json:"score"`
+}
+
+type AttestationPackingEntityStats struct {
+	Entity        string  `db:"entity" json:"entity"`
+	BlockCount    uint64  `db:"block_count" json:"block_count"`
+	IncludedVotes uint64  `db:"included_votes" json:"included_votes"`
+	MissedVotes   uint64  `db:"missed_votes" json:"missed_votes"`
+	LateVotes     uint64  `db:"late_votes" json:"late_votes"`
+	AvgScore      float64 `db:"avg_score" json:"avg_score"`
+	MinScore      float64 `db:"min_score" json:"min_score"`
+}
+
+type BlockQualityPageData struct {
+	Days        uint64
+	Entities    []*AttestationPackingEntityStats
+	WorstBlocks []*BlockAttestationPacking
+}
+
+type SyncCommitteePeriodInfo struct {
+	Period      uint64 `db:"period" json:"period"`
+	StartEpoch  uint64 `db:"start_epoch" json:"start_epoch"`
+	EndEpoch    uint64 `db:"end_epoch" json:"end_epoch"`
+	MemberCount uint64 `db:"member_count" json:"-"`
+}
+
+// SyncCommitteePerformance holds the sync duties of a validator within a sync committee period, rewards are the
+// net sync committee rewards in gwei
+type SyncCommitteePerformance struct {
+	ParticipatedSync uint64 `json:"participated_sync"`
+	MissedSync       uint64 `json:"missed_sync"`
+	OrphanedSync     uint64 `json:"orphaned_sync"`
+	Rewards          int64  `json:"rewards"`
+}
+
+func (p SyncCommitteePerformance) ParticipationRate() float64 {
+	total := p.ParticipatedSync + p.MissedSync + p.OrphanedSync
+	if total == 0 {
+		return 0
+	}
+	return float64(p.ParticipatedSync) / float64(total)
+}
+
+type SyncCommitteeMember struct {
+	ValidatorIndex   uint64        `db:"validatorindex" json:"validatorindex"`
+	CommitteeIndices pq.Int64Array `db:"committee_indices" json:"committee_indices" swaggertype:"array,integer"`
+	SyncCommitteePerformance
+}
+
+type ValidatorSyncCommitteePeriod struct {
+	SyncCommitteePeriodInfo
+	SyncCommitteePerformance
+}
+
+type SyncCommitteesPageData struct {
+	Periods          []*SyncCommitteePeriodInfo
+	CurrentPeriod    uint64
+	ShowValidator    bool
+	Validator        uint64
+	ValidatorHistory []*ValidatorSyncCommitteePeriod
+	ValidatorRewards int64
+}
+
+type SyncCommitteePageData struct {
+	SyncCommitteePeriodInfo
+	CurrentPeriod uint64
+	Members       []*SyncCommitteeMember
+	Performance   SyncCommitteePerformance
+}
+
+// ValidatorEffectivenessScore is the effectiveness score (0-100) of a validator for a day together with its components.
+// Every component is a ratio between 0 and 1, components the validator had no duties for are nil.
+type ValidatorEffectivenessScore struct {
+	ValidatorIndex         uint64   `db:"validatorindex" json:"validatorindex"`
+	Day                    uint64   `db:"day" json:"day"`
+	Version                uint64   `db:"version" json:"version"`
+	Score                  float64  `db:"score" json:"score"`
+	AttestationCorrectness float64  `db:"attestation_correctness" json:"attestation_correctness"`
+	InclusionDelay         *float64 `db:"inclusion_delay" json:"inclusion_delay"`
+	Proposal               *float64 `db:"proposal" json:"proposal"`
+	Sync                   *float64 `db:"sync" json:"sync"`
+}
+
+type EffectivenessScoreWeights struct {
+	AttestationCorrectness float64 `json:"attestation_correctness"`
+	InclusionDelay         float64 `json:"inclusion_delay"`
+	Proposal               float64 `json:"proposal"`
+	Sync                   float64 `json:"sync"`
+}
+
+type L2FlowsPageData struct {
+	Days        uint64
+	FlowSeries  []*GenericChartDataSeries
+	NetSeries   []*GenericChartDataSeries
+	Networks    []*L2BridgeFlowsDailyStats
+	LastUpdated time.Time
+}
+
+type StakingFlowsPageData struct {
+	Days        uint64
+	FlowSeries  []*GenericChartDataSeries
+	NetSeries   []*GenericChartDataSeries
+	Entities    []*StakingFlowsEntityDailyStats
+	LastUpdated time.Time
+}
+
+type ValidatorSetProjectionPageData struct {
+	DailyInflow  float64
+	DailyOutflow float64
+	Days         uint64
+	MaxDays      uint64
+}
+
 type BurnPageDataBlock struct {
 	Number        int64     `json:"number"`
 	Hash          string    `json:"hash"`
@@ -2100,6 +2874,43 @@ type WithdrawalStats struct {
 	ValidatorsWithBLSCredentials uint64
 }
 
+// WithdrawalEstimate is the estimated next withdrawal of a validator, based on the position of the validator relative to
+// the withdrawal sweep cursor (the index of the validator that was withdrawn last) and its current balance
+type WithdrawalEstimate struct {
+	ValidatorIndex uint64
+	SweepCursor    uint64
+	SweepPosition  uint64 // number of validators the sweep has to pass before reaching the validator
+	Time           time.Time
+	Amount         uint64
+	IsFull         bool
+	Address        []byte
+}
+
+// ExitReadiness summarizes what has to be considered before a validator submits a voluntary exit. The exit epoch is
+// estimated from the current exit queue and churn limit, for validators that already initiated their exit the actual
+// exit and withdrawable epochs are reported.
+type ExitReadiness struct {
+	ValidatorIndex       uint64
+	CredentialType       string // "bls" or "execution"
+	ExitInitiated        bool
+	CanExit              bool
+	Blockers             []string // reasons the validator can not submit a voluntary exit now
+	Warnings             []string
+	EligibleEpoch        uint64   // first epoch the validator may exit, SHARD_COMMITTEE_PERIOD epochs after its activation
+	ScheduledProposals   []uint64 // slots of the upcoming proposals
+	SyncCommitteePeriods []uint64 // current and upcoming sync committee periods
+	SyncCommitteeEnd     uint64   // first epoch after the last sync committee period of the validator
+	ExitQueueLength      uint64
+	ExitEpoch            uint64
+	ExitTime             time.Time
+	WithdrawableEpoch    uint64
+	WithdrawableTime     time.Time
+	FullWithdrawalTime   time.Time // zero if the validator has bls credentials
+	Balance              uint64
+	RewardsPerDay        int64 // average consensus rewards of the last 7 days in gwei
+	RewardsUntilExit     int64 // consensus rewards expected until the exit epoch in gwei
+}
+
 type ChangeWithdrawalCredentialsPageData struct {
 	FlashMessage string
 	CsrfField    template.HTML
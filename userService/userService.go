@@ -1,10 +1,10 @@
 package userService
 
 import (
-	"github.com/sirupsen/logrus"
+	"github.com/gobitfly/eth2-beaconchain-explorer/utils"
 )
 
-var logger = logrus.New().WithField("module", "userService")
+var logger = utils.NewLogger("userService")
 
 func Init() {
 	logger.Info("starting user service")
@@ -0,0 +1,262 @@
+package utils
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+)
+
+const defaultGzipLevel = gzip.DefaultCompression
+
+// defaultCompressionContentTypes are the compressed content types if none are configured, images other than svg and
+// fonts are compressed already
+var defaultCompressionContentTypes = []string{
+	"text/html",
+	"text/css",
+	"text/plain",
+	"text/javascript",
+	"application/javascript",
+	"application/json",
+	"application/xml",
+	"text/xml",
+	"image/svg+xml",
+}
+
+var gzipWriterPool sync.Pool
+var brotliWriterPool sync.Pool
+
+type compressionEncoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+func getCompressionEncoder(encoding string, w io.Writer) compressionEncoder {
+	switch encoding {
+	case "br":
+		if e, ok := brotliWriterPool.Get().(*brotli.Writer); ok {
+			e.Reset(w)
+			return e
+		}
+		return brotli.NewWriterLevel(w, Config.Frontend.Compression.BrotliLevel)
+	default:
+		if e, ok := gzipWriterPool.Get().(*gzip.Writer); ok {
+			e.Reset(w)
+			return e
+		}
+		e, err := gzip.NewWriterLevel(w, Config.Frontend.Compression.GzipLevel)
+		if err != nil {
+			// the level was invalid
+			e = gzip.NewWriter(w)
+		}
+		return e
+	}
+}
+
+func putCompressionEncoder(e compressionEncoder) {
+	switch e := e.(type) {
+	case *brotli.Writer:
+		brotliWriterPool.Put(e)
+	case *gzip.Writer:
+		gzipWriterPool.Put(e)
+	}
+}
+
+// acceptedEncoding returns the encoding a response to the request should be compressed with, brotli is preferred
+// over gzip. An empty string is returned if the client accepts neither.
+func acceptedEncoding(r *http.Request) string {
+	gzipAccepted := false
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		switch strings.ToLower(strings.TrimSpace(coding)) {
+		case "br":
+			return "br"
+		case "gzip":
+			gzipAccepted = true
+		}
+	}
+	if gzipAccepted {
+		return "gzip"
+	}
+	return ""
+}
+
+func isCompressibleContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range Config.Frontend.Compression.ContentTypes {
+		if t == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheControlForContentType returns the configured Cache-Control header of the class of the response
+func cacheControlForContentType(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/json":
+		return Config.Frontend.CacheControl.Api
+	case "text/html":
+		return Config.Frontend.CacheControl.Html
+	default:
+		return Config.Frontend.CacheControl.Static
+	}
+}
+
+// compressionResponseWriter buffers the start of the body until it is known whether the response is large enough to
+// be compressed, the headers are written once that is decided
+type compressionResponseWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+	status   int
+	buf      []byte
+	decided  bool
+	encoder  compressionEncoder
+}
+
+func (cw *compressionResponseWriter) WriteHeader(status int) {
+	if cw.decided || cw.status != 0 {
+		return
+	}
+	cw.status = status
+	// responses without a body or with a body of a known small size are never compressed
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		cw.decide(false)
+		return
+	}
+	if length, err := strconv.Atoi(cw.Header().Get("Content-Length")); err == nil && length < cw.minSize {
+		cw.decide(false)
+	}
+}
+
+func (cw *compressionResponseWriter) Write(p []byte) (int, error) {
+	if !cw.decided {
+		if cw.status == 0 {
+			cw.WriteHeader(http.StatusOK)
+		}
+		if !cw.decided {
+			if contentType := cw.Header().Get("Content-Type"); contentType != "" && !isCompressibleContentType(contentType) {
+				cw.decide(false)
+			}
+		}
+	}
+	if !cw.decided {
+		cw.buf = append(cw.buf, p...)
+		if len(cw.buf) < cw.minSize {
+			return len(p), nil
+		}
+		if err := cw.decide(true); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if cw.encoder != nil {
+		return cw.encoder.Write(p)
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// decide writes the headers and the buffered body, compressed if compress is set and the content type is compressible
+func (cw *compressionResponseWriter) decide(compress bool) error {
+	cw.decided = true
+	header := cw.Header()
+	if header.Get("Content-Type") == "" && len(cw.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(cw.buf))
+	}
+	contentType := header.Get("Content-Type")
+	if header.Get("Cache-Control") == "" {
+		if cacheControl := cacheControlForContentType(contentType); cacheControl != "" {
+			header.Set("Cache-Control", cacheControl)
+		}
+	}
+
+	compressible := cw.encoding != "" && header.Get("Content-Encoding") == "" && isCompressibleContentType(contentType)
+	if compressible {
+		header.Add("Vary", "Accept-Encoding")
+	}
+	if compress && compressible {
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", cw.encoding)
+		cw.encoder = getCompressionEncoder(cw.encoding, cw.ResponseWriter)
+	}
+
+	if cw.status != 0 {
+		cw.ResponseWriter.WriteHeader(cw.status)
+	}
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.encoder != nil {
+		_, err = cw.encoder.Write(buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+func (cw *compressionResponseWriter) Flush() {
+	if !cw.decided {
+		cw.decide(true)
+	}
+	if cw.encoder != nil {
+		cw.encoder.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (cw *compressionResponseWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// close sends the rest of the response, bodies smaller than the minimum size are sent uncompressed
+func (cw *compressionResponseWriter) close() {
+	if !cw.decided {
+		cw.decide(false)
+	}
+	if cw.encoder != nil {
+		cw.encoder.Close()
+		putCompressionEncoder(cw.encoder)
+		cw.encoder = nil
+	}
+}
+
+// CompressionMiddleware compresses responses with brotli or gzip, depending on what the client accepts, and sets the
+// configured Cache-Control headers of responses that do not set their own
+func CompressionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := ""
+		// range requests address the uncompressed body
+		if !Config.Frontend.Compression.Disabled && r.Method != http.MethodHead && r.Header.Get("Range") == "" {
+			encoding = acceptedEncoding(r)
+		}
+
+		cw := &compressionResponseWriter{
+			ResponseWriter: w,
+			encoding:       encoding,
+			minSize:        Config.Frontend.Compression.MinSize,
+		}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
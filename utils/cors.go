@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+)
+
+// defaultCorsPolicy allows any origin, it is used if no policies are configured
+var defaultCorsPolicy = types.CorsPolicy{
+	PathPrefix:     "/",
+	AllowedOrigins: []string{"*"},
+	AllowedMethods: []string{"*"},
+	AllowedHeaders: []string{"*", "Authorization"},
+}
+
+// corsPolicy returns the configured policy with the longest PathPrefix matching the path
+func corsPolicy(path string) *types.CorsPolicy {
+	policies := Config.Frontend.Cors
+	if len(policies) == 0 {
+		return &defaultCorsPolicy
+	}
+	var match *types.CorsPolicy
+	for i := range policies {
+		if strings.HasPrefix(path, policies[i].PathPrefix) && (match == nil || len(policies[i].PathPrefix) > len(match.PathPrefix)) {
+			match = &policies[i]
+		}
+	}
+	return match
+}
+
+// corsAllowedOrigin returns the value of the Access-Control-Allow-Origin header for the origin of a request, or an
+// empty string if the origin is not allowed
+func corsAllowedOrigin(policy *types.CorsPolicy, origin string) string {
+	for _, allowed := range policy.AllowedOrigins {
+		if allowed == "*" {
+			// the wildcard is never combined with credentials, ReadConfig rejects such policies
+			return "*"
+		}
+		if origin == "" {
+			continue
+		}
+		if strings.EqualFold(allowed, origin) {
+			return origin
+		}
+		if prefix, suffix, found := strings.Cut(allowed, "*"); found &&
+			len(origin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(strings.ToLower(origin), strings.ToLower(prefix)) &&
+			strings.HasSuffix(strings.ToLower(origin), strings.ToLower(suffix)) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// CORSMiddleware applies the CORS policy and the headers configured for the path of the request and answers
+// preflight requests. Requests of paths without a policy are passed through unchanged.
+func CORSMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		policy := corsPolicy(r.URL.Path)
+		if policy == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		for name, value := range policy.Headers {
+			header.Set(name, value)
+		}
+
+		allowedOrigin := corsAllowedOrigin(policy, r.Header.Get("Origin"))
+		if allowedOrigin != "*" {
+			header.Add("Vary", "Origin")
+		}
+		if allowedOrigin != "" {
+			header.Set("Access-Control-Allow-Origin", allowedOrigin)
+			if len(policy.AllowedMethods) > 0 {
+				header.Set("Access-Control-Allow-Methods", strings.Join(policy.AllowedMethods, ", "))
+			}
+			if len(policy.AllowedHeaders) > 0 {
+				header.Set("Access-Control-Allow-Headers", strings.Join(policy.AllowedHeaders, ", "))
+			}
+			if len(policy.ExposedHeaders) > 0 {
+				header.Set("Access-Control-Expose-Headers", strings.Join(policy.ExposedHeaders, ", "))
+			}
+			if policy.AllowCredentials && allowedOrigin != "*" {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+			if policy.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", fmt.Sprintf("%d", int64(policy.MaxAge.Seconds())))
+			}
+		}
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
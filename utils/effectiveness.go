@@ -0,0 +1,45 @@
+package utils
+
+import "github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+// EffectivenessScoreVersion is the version of the effectiveness score formula. It has to be increased whenever a
+// component or weight changes, scores of different versions are stored side by side and must never be mixed.
+const EffectivenessScoreVersion = 1
+
+// EffectivenessScoreWeights are the weights of the components of the current effectiveness score version
+var EffectivenessScoreWeights = types.EffectivenessScoreWeights{
+	AttestationCorrectness: 0.5,
+	InclusionDelay:         0.3,
+	Proposal:               0.1,
+	Sync:                   0.1,
+}
+
+// EffectivenessScoreFormula documents the current effectiveness score version, it is served by the api and shown on the validator page
+const EffectivenessScoreFormula = "The effectiveness score is the weighted average of the components a validator had duties for during a day, scaled to 0-100. " +
+	"Attestation correctness is the share of correct head and target votes of all attestation duties, missed attestations count as incorrect. " +
+	"Inclusion delay is the average of the optimal over the actual inclusion distance of the included attestations, the optimal distance being the distance to the first block after the attestation slot. " +
+	"Proposal is the share of the assigned proposals that ended up as canonical blocks. " +
+	"Sync is the share of the sync committee duties that were included in canonical blocks."
+
+// EffectivenessScore returns the effectiveness score (0-100) of the given components using the weights of the current version.
+// Components without duties (nil) are left out and the weights of the remaining components are normalized.
+func EffectivenessScore(score *types.ValidatorEffectivenessScore) float64 {
+	w := EffectivenessScoreWeights
+
+	sum := w.AttestationCorrectness * score.AttestationCorrectness
+	weights := w.AttestationCorrectness
+	if score.InclusionDelay != nil {
+		sum += w.InclusionDelay * *score.InclusionDelay
+		weights += w.InclusionDelay
+	}
+	if score.Proposal != nil {
+		sum += w.Proposal * *score.Proposal
+		weights += w.Proposal
+	}
+	if score.Sync != nil {
+		sum += w.Sync * *score.Sync
+		weights += w.Sync
+	}
+
+	return sum / weights * 100
+}
@@ -1,8 +1,12 @@
 package utils
 
 import (
+	"bytes"
 	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/attestantio/go-eth2-client/spec/capella"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
@@ -141,3 +145,66 @@ func VersionedBlobHash(commitment []byte) common.Hash {
 	vhash[0] = 0x01
 	return vhash
 }
+
+// validatorKeyFileEntry contains the fields of the entries of a deposit_data-*.json file (as created by the
+// staking-deposit-cli) and of an EIP-2335 keystore that are needed to import the validator
+type validatorKeyFileEntry struct {
+	Pubkey                string          `json:"pubkey"`
+	WithdrawalCredentials string          `json:"withdrawal_credentials"`
+	ForkVersion           string          `json:"fork_version"`
+	Crypto                json.RawMessage `json:"crypto"`
+	Version               int             `json:"version"`
+}
+
+// ParseValidatorKeyFile returns the validator pubkeys of a deposit_data-*.json file or an EIP-2335 keystore. Only the
+// public parts are read, the pubkeys must be valid BLS public keys and deposit data must belong to the configured network.
+func ParseValidatorKeyFile(data []byte) ([][]byte, error) {
+	data = bytes.TrimSpace(data)
+	entries := []*validatorKeyFileEntry{}
+	isKeystore := len(data) > 0 && data[0] == '{'
+	if isKeystore {
+		keystore := &validatorKeyFileEntry{}
+		err := json.Unmarshal(data, keystore)
+		if err != nil {
+			return nil, fmt.Errorf("invalid keystore: %w", err)
+		}
+		if keystore.Version != 4 || len(keystore.Crypto) == 0 {
+			return nil, fmt.Errorf("invalid keystore: only EIP-2335 keystores (version 4) are supported")
+		}
+		if keystore.Pubkey == "" {
+			return nil, fmt.Errorf("invalid keystore: the keystore does not contain the pubkey")
+		}
+		entries = append(entries, keystore)
+	} else {
+		err := json.Unmarshal(data, &entries)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deposit data: %w", err)
+		}
+		if len(entries) == 0 {
+			return nil, fmt.Errorf("invalid deposit data: the file does not contain any deposits")
+		}
+	}
+
+	genesisForkVersion := strings.TrimPrefix(strings.ToLower(Config.Chain.ClConfig.GenesisForkVersion), "0x")
+	pubkeys := make([][]byte, 0, len(entries))
+	for i, entry := range entries {
+		if !isKeystore {
+			if !IsValidWithdrawalCredentials(entry.WithdrawalCredentials) {
+				return nil, fmt.Errorf("invalid deposit data: deposit %v has invalid withdrawal credentials", i)
+			}
+			if strings.TrimPrefix(strings.ToLower(entry.ForkVersion), "0x") != genesisForkVersion {
+				return nil, fmt.Errorf("invalid deposit data: deposit %v is for fork version %v, expected %v", i, entry.ForkVersion, Config.Chain.ClConfig.GenesisForkVersion)
+			}
+		}
+		pubkey, err := hex.DecodeString(strings.TrimPrefix(entry.Pubkey, "0x"))
+		if err != nil || len(pubkey) != 48 {
+			return nil, fmt.Errorf("invalid pubkey %v", entry.Pubkey)
+		}
+		_, err = e2types.BLSPublicKeyFromBytes(pubkey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pubkey %v: %w", entry.Pubkey, err)
+		}
+		pubkeys = append(pubkeys, pubkey)
+	}
+	return pubkeys, nil
+}
@@ -8,6 +8,7 @@ import (
 	"html/template"
 	"math/big"
 	"strings"
+	"unicode"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 
@@ -128,6 +129,37 @@ func FormatBuilder(pubkey []byte) template.HTML {
 	return FormatAddress(pubkey, nil, name, false, false, false)
 }
 
+// FormatBuilderName returns the printable block extra data of a builder or the shortened builder pubkey if the extra data is not readable
+func FormatBuilderName(pubkeyHex, extraDataHex string) string {
+	extraData, err := hexutil.Decode(extraDataHex)
+	if err == nil {
+		name := strings.TrimSpace(string(bytes.ToValidUTF8(extraData, nil)))
+		printable := len(name) > 0
+		for _, r := range name {
+			if !unicode.IsPrint(r) {
+				printable = false
+				break
+			}
+		}
+		if printable {
+			return name
+		}
+	}
+	if len(pubkeyHex) > 10 {
+		return pubkeyHex[:10] + "…"
+	}
+	return pubkeyHex
+}
+
+// ResolveIpfsURI rewrites ipfs:// uris to the public ipfs.io gateway, all other uris are returned unchanged
+func ResolveIpfsURI(uri string) string {
+	if !strings.HasPrefix(uri, "ipfs://") {
+		return uri
+	}
+	path := strings.TrimPrefix(strings.TrimPrefix(uri, "ipfs://"), "ipfs/")
+	return "https://ipfs.io/ipfs/" + path
+}
+
 func FormatBytes(b []byte, addCopyToClipboard bool, link string) template.HTML {
 	bStr := fmt.Sprintf("%#x", b)
 	ret := ""
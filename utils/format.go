@@ -747,6 +747,50 @@ func FormatWithdawalCredentials(hash []byte, addCopyButton bool) template.HTML {
 	return text
 }
 
+// FormatWithdrawalCredentialsWithName formats 0x01 withdrawal credentials with the ens name of the withdrawal address if there is one
+func FormatWithdrawalCredentialsWithName(hash []byte, name string, addCopyButton bool) template.HTML {
+	if len(hash) != 32 || hash[0] != 0x01 || len(name) == 0 {
+		return FormatWithdawalCredentials(hash, addCopyButton)
+	}
+
+	text := template.HTML(fmt.Sprintf("<a href=\"/address/0x%x\" data-toggle=\"tooltip\" title=\"%#x\"><span class=\"badge badge-pill badge-ens\">%s</span></a>", hash[12:], hash, template.HTMLEscapeString(name)))
+	if addCopyButton {
+		text += template.HTML(fmt.Sprintf("<i class=\"fa fa-copy text-muted p-1\" role=\"button\" data-toggle=\"tooltip\" title=\"Copy to clipboard\" data-clipboard-text=\"%#x\"></i>", hash))
+	}
+
+	return text
+}
+
+// FormatEth1AddressWithName will return the eth1-address formated as html, showing the ens name of the address if there is one
+func FormatEth1AddressWithName(addr []byte, name string) template.HTML {
+	if len(name) == 0 {
+		return FormatEth1Address(addr)
+	}
+	eth1Addr := FixAddressCasing(fmt.Sprintf("%x", addr))
+	copyBtn := CopyButton(eth1Addr)
+	return template.HTML(fmt.Sprintf("<a href=\"/address/%s\" data-toggle=\"tooltip\" title=\"%s\"><span class=\"badge badge-pill badge-ens\">%s</span></a>%s", eth1Addr, eth1Addr, template.HTMLEscapeString(name), copyBtn))
+}
+
+// FormatAddressLabels returns a badge per address label, colored by the category of the label
+func FormatAddressLabels(labels []*types.AddressLabel) template.HTML {
+	var sb strings.Builder
+	for _, l := range labels {
+		badge := "badge-dark"
+		switch l.Category {
+		case types.AddressLabelCategoryExchange:
+			badge = "badge-info"
+		case types.AddressLabelCategoryBridge:
+			badge = "badge-primary"
+		case types.AddressLabelCategoryMixer:
+			badge = "badge-danger"
+		case types.AddressLabelCategoryContract:
+			badge = "badge-secondary"
+		}
+		sb.WriteString(fmt.Sprintf(`<span class="badge %s text-white align-middle ml-1" data-toggle="tooltip" title="%s">%s</span>`, badge, template.HTMLEscapeString(l.Category), template.HTMLEscapeString(l.Label)))
+	}
+	return template.HTML(sb.String())
+}
+
 func FormatAddressToWithdrawalCredentials(address []byte, addCopyButton bool) template.HTML {
 	credentials, err := hex.DecodeString(BeginningOfSetWithdrawalCredentials)
 	if err != nil {
@@ -1062,12 +1106,59 @@ func FormatValidatorTag(tag string) template.HTML {
 		result = `<span style="background-color: rgba(240, 149, 45, .2); font-size: 18px;" class="badge-pill mr-1 font-weight-normal" data-toggle="tooltip" title="Rocket Pool Validator"><a style="color: var(--yellow);" href="/pools/rocketpool">Rocket Pool</a></span>`
 	case "ssv":
 		result = `<span style="background-color: rgba(238, 113, 18, .2); font-size: 18px;" class="badge-pill mr-1 font-weight-normal" data-toggle="tooltip" title="Secret Shared Validator"><a style="color: var(--orange);" href="https://github.com/bloxapp/ssv/">SSV</a></span>`
+	case "obol":
+		result = `<span style="background-color: rgba(46, 204, 113, .2); font-size: 18px;" class="badge-pill mr-1 font-weight-normal" data-toggle="tooltip" title="Obol Distributed Validator"><a style="color: var(--green);" href="https://obol.org/">Obol</a></span>`
 	default:
 		result = formatSpecialTag(tag)
 	}
 	return template.HTML(result)
 }
 
+// FormatPercentileBadge will return a "top X%" badge for a percentile rank (0-100, higher is better), nothing if the rank is not in the top half
+func FormatPercentileBadge(label string, percentile float64) template.HTML {
+	top := math.Max(math.Ceil(100-percentile), 1)
+	if top > 50 {
+		return ""
+	}
+	return template.HTML(fmt.Sprintf(`<span style="background-color: rgba(46, 204, 113, .2); font-size: 14px;" class="badge-pill mr-1 font-weight-normal" data-toggle="tooltip" title="%[1]s ranks in the top %.0[2]f%% of all active validators over the last days"><span style="color: var(--green);">Top %.0[2]f%% %[1]s</span></span>`, label, top))
+}
+
+// FormatMilestoneBadge will return a badge linking to the shareable page of a milestone the validator reached
+func FormatMilestoneBadge(m *types.ValidatorMilestone) template.HTML {
+	desc, ok := types.ValidatorMilestones[m.Milestone]
+	if !ok {
+		return ""
+	}
+	return template.HTML(fmt.Sprintf(`<a href="/validator/%[1]d/milestone/%[2]s" style="background-color: rgba(52, 152, 219, .2); font-size: 14px;" class="badge-pill mr-1 font-weight-normal" data-toggle="tooltip" title="Validator %[1]d %[3]s on %[4]s"><span style="color: var(--primary);"><i class="fas %[5]s mr-1"></i>%[6]s</span></a>`, m.ValidatorIndex, m.Milestone, html.EscapeString(desc.Description), DayToTime(int64(m.Day)).Format("2006-01-02"), desc.Icon, html.EscapeString(desc.Label)))
+}
+
+// FormatEth1DepositWarning returns the explanation of a warning flagged for a deposit
+func FormatEth1DepositWarning(warning string) string {
+	if desc, ok := types.Eth1DepositWarnings[warning]; ok {
+		return desc
+	}
+	return "The deposit was flagged: " + warning
+}
+
+// FormatDepositOriginBadge returns a badge naming the funding source of the deposit address of a validator, nothing if
+// the origin is unknown
+func FormatDepositOriginBadge(o *types.DepositOrigin) template.HTML {
+	if o == nil || o.Kind == types.DepositOriginUnknown || len(o.OriginAddress) == 0 {
+		return ""
+	}
+	name := o.OriginLabel
+	if name == "" {
+		name = fmt.Sprintf("%s 0x%x…", o.Kind, o.OriginAddress[:3])
+	}
+	via := "deposited directly"
+	if o.Hops == 1 {
+		via = "1 transfer before the deposit"
+	} else if o.Hops > 1 {
+		via = fmt.Sprintf("%d transfers before the deposit", o.Hops)
+	}
+	return template.HTML(fmt.Sprintf(`<a href="/address/0x%[1]x" style="background-color: rgba(127, 119, 255, .2); font-size: 14px;" class="badge-pill mr-1 font-weight-normal" data-toggle="tooltip" title="The deposit address was funded by a %[2]s address (%[3]s)"><span style="color: var(--primary);"><i class="fas fa-route mr-1"></i>Funded via %[4]s</span></a>`, o.OriginAddress, html.EscapeString(o.Kind), via, html.EscapeString(name)))
+}
+
 func FormatValidatorTags(tags []string) template.HTML {
 	str := ""
 	for _, tag := range tags {
@@ -1162,18 +1253,29 @@ func FormatValidatorName(name string) template.HTML {
 	return template.HTML(fmt.Sprintf("<b><abbr title=\"This name has been set by the owner of this validator. Pool tags have been set by the beaconcha.in team.\">%s</abbr></b>", str))
 }
 
-func FormatAttestationInclusionEffectiveness(eff float64) template.HTML {
-	tooltipText := "The attestation inclusion effectiveness should be 80% or higher to minimize reward penalties."
-	if eff == 0 {
-		return template.HTML(`<span class="text-danger" data-toggle="tooltip" title="Validator did not attest during the last 100 epochs"> N/A <i class="fas fa-frown"></i>`)
-	} else if eff >= 100 {
-		return template.HTML(fmt.Sprintf(`<span class="text-success" data-toggle="tooltip" title="%s"> %.0f%% - Perfect <i class="fas fa-grin-stars"></i>`, tooltipText, eff))
-	} else if eff > 80 {
-		return template.HTML(fmt.Sprintf(`<span class="text-success" data-toggle="tooltip" title="%s"> %.0f%% - Good <i class="fas fa-smile"></i></span>`, tooltipText, eff))
-	} else if eff > 60 {
-		return template.HTML(fmt.Sprintf(`<span class="text-warning" data-toggle="tooltip" title="%s"> %.0f%% - Fair <i class="fas fa-meh"></i></span>`, tooltipText, eff))
+// FormatEffectivenessScore returns the effectiveness score of a validator with a breakdown of its components as tooltip
+func FormatEffectivenessScore(score *types.ValidatorEffectivenessScore) template.HTML {
+	if score == nil {
+		return template.HTML(`<span class="text-muted" data-toggle="tooltip" title="No effectiveness score has been calculated for this validator yet"> N/A</span>`)
+	}
+
+	component := func(value *float64) string {
+		if value == nil {
+			return "no duties"
+		}
+		return fmt.Sprintf("%.1f%%", *value*100)
+	}
+	tooltipText := fmt.Sprintf("Effectiveness score v%d of day %d. Attestation correctness: %.1f%%, inclusion delay: %s, proposals: %s, sync committee: %s. %s",
+		score.Version, score.Day, score.AttestationCorrectness*100, component(score.InclusionDelay), component(score.Proposal), component(score.Sync), EffectivenessScoreFormula)
+
+	if score.Score >= 100 {
+		return template.HTML(fmt.Sprintf(`<span class="text-success" data-toggle="tooltip" title="%s"> %.0f%% - Perfect <i class="fas fa-grin-stars"></i>`, tooltipText, score.Score))
+	} else if score.Score > 80 {
+		return template.HTML(fmt.Sprintf(`<span class="text-success" data-toggle="tooltip" title="%s"> %.0f%% - Good <i class="fas fa-smile"></i></span>`, tooltipText, score.Score))
+	} else if score.Score > 60 {
+		return template.HTML(fmt.Sprintf(`<span class="text-warning" data-toggle="tooltip" title="%s"> %.0f%% - Fair <i class="fas fa-meh"></i></span>`, tooltipText, score.Score))
 	} else {
-		return template.HTML(fmt.Sprintf(`<span class="text-danger" data-toggle="tooltip" title="%s"> %.0f%% - Bad <i class="fas fa-frown"></i></span>`, tooltipText, eff))
+		return template.HTML(fmt.Sprintf(`<span class="text-danger" data-toggle="tooltip" title="%s"> %.0f%% - Bad <i class="fas fa-frown"></i></span>`, tooltipText, score.Score))
 	}
 }
 
@@ -1202,10 +1304,9 @@ func DerefString(str *string) string {
 	return ""
 }
 
-// TrLang returns translated text based on language tag and text id
-func TrLang(lang string, key string) template.HTML {
-	I18n := getLocaliser()
-	return template.HTML(I18n.Tr(lang, key))
+// TrLang returns translated text based on language tag and text id, see Tr for the args
+func TrLang(lang string, key string, args ...interface{}) template.HTML {
+	return template.HTML(Tr(lang, key, args...))
 }
 
 func KFormatterEthPrice(price uint64) template.HTML {
@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/gobitfly/eth2-beaconchain-explorer/types"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type requestIDKey struct{}
+
+var (
+	loggersMu sync.Mutex
+	// loggers holds the logger of every component so the format and level can be changed for all of them at runtime
+	loggers = map[string]*logrus.Logger{}
+	// errorLogger is used by LogError, LogWarn and LogFatal
+	errorLogger = newComponentLogger("errors")
+	// serviceName is the name of the executable, added to every log entry to tell the services apart in aggregated logs
+	serviceName = filepath.Base(os.Args[0])
+)
+
+// NewLogger returns the logger of the component. Entries contain the component as module and the name of the service,
+// the level of the component can be changed at runtime with SetLogLevel.
+func NewLogger(component string) *logrus.Entry {
+	return newComponentLogger(component).WithFields(logrus.Fields{"module": component, "service": serviceName})
+}
+
+func newComponentLogger(component string) *logrus.Logger {
+	loggersMu.Lock()
+	defer loggersMu.Unlock()
+
+	if l, ok := loggers[component]; ok {
+		return l
+	}
+	l := logrus.New()
+	loggers[component] = l
+	return l
+}
+
+// ConfigureLogging applies the configured format and levels to the loggers of all components
+func ConfigureLogging(cfg *types.Config) error {
+	loggersMu.Lock()
+	defer loggersMu.Unlock()
+
+	var formatter logrus.Formatter = &logrus.TextFormatter{}
+	switch cfg.Logging.Format {
+	case "", "text":
+	case "json":
+		formatter = &logrus.JSONFormatter{}
+	default:
+		return fmt.Errorf("unknown log format %v", cfg.Logging.Format)
+	}
+
+	level := logrus.InfoLevel
+	if cfg.Logging.Level != "" {
+		var err error
+		level, err = logrus.ParseLevel(cfg.Logging.Level)
+		if err != nil {
+			return fmt.Errorf("error parsing log level: %w", err)
+		}
+	}
+
+	for _, l := range append([]*logrus.Logger{logrus.StandardLogger()}, loggerList()...) {
+		l.SetFormatter(formatter)
+		l.SetLevel(level)
+	}
+	for component, componentLevel := range cfg.Logging.Levels {
+		// components of packages the service does not import are not registered
+		l, ok := loggers[component]
+		if !ok {
+			continue
+		}
+		parsed, err := logrus.ParseLevel(componentLevel)
+		if err != nil {
+			return fmt.Errorf("error parsing log level of component %v: %w", component, err)
+		}
+		l.SetLevel(parsed)
+	}
+	return nil
+}
+
+func loggerList() []*logrus.Logger {
+	list := make([]*logrus.Logger, 0, len(loggers))
+	for _, l := range loggers {
+		list = append(list, l)
+	}
+	return list
+}
+
+// SetLogLevel changes the level of the logger of the component
+func SetLogLevel(component, level string) error {
+	parsed, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	loggersMu.Lock()
+	defer loggersMu.Unlock()
+	l, ok := loggers[component]
+	if !ok {
+		return fmt.Errorf("unknown log component %v", component)
+	}
+	l.SetLevel(parsed)
+	return nil
+}
+
+// LogLevels returns the current level of the logger of every component
+func LogLevels() []types.LogLevel {
+	loggersMu.Lock()
+	defer loggersMu.Unlock()
+
+	levels := make([]types.LogLevel, 0, len(loggers))
+	for component, l := range loggers {
+		levels = append(levels, types.LogLevel{Component: component, Level: l.GetLevel().String()})
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i].Component < levels[j].Component
+	})
+	return levels
+}
+
+// ContextWithRequestID returns a copy of the context carrying the id of the request it belongs to
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the id of the request the context belongs to, or an empty string
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// LoggerWithContext adds the request id and the trace id of the context to the entries of the logger
+func LoggerWithContext(ctx context.Context, logger *logrus.Entry) *logrus.Entry {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		logger = logger.WithField("request_id", requestID)
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		logger = logger.WithField("trace_id", spanContext.TraceID().String())
+	}
+	return logger
+}
+
+// RequestIDMiddleware assigns an id to every request, the id of the X-Request-ID header is used if it is set. The id
+// is returned in the X-Request-ID response header and carried by the request context so the handler and db layer can
+// add it to their log entries.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get("X-Request-ID")
+		if requestID == "" || len(requestID) > 128 {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set("X-Request-ID", requestID)
+		next.ServeHTTP(w, r.WithContext(ContextWithRequestID(r.Context(), requestID)))
+	})
+}
@@ -2,12 +2,16 @@ package utils
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -26,6 +30,19 @@ const AccessDenied = "access_denied"
 const ServerError = "server_error"
 const TemporarilyUnavailable = "temporarily_unavailable"
 
+// OAuth scopes that can be granted to third-party apps, tokens without a scope (first-party apps) have full access
+const OAuthScopeDashboardRead = "dashboard:read"
+const OAuthScopeNotificationsRead = "notifications:read"
+
+// OAuthScopes maps the supported scopes to the description shown on the authorization page
+var OAuthScopes = map[string]string{
+	OAuthScopeDashboardRead:     "View your validator dashboards and watchlist",
+	OAuthScopeNotificationsRead: "View your notification subscriptions",
+}
+
+// PKCECodeChallengeMethod is the only supported code challenge method, see RFC 7636
+const PKCECodeChallengeMethod = "S256"
+
 // UserIDKey Key for context access to get the validated userID
 const ClaimsContextKey = "ClaimsKey"
 
@@ -34,7 +51,7 @@ const MobileAuthorizedKey = "MobileAuthKey"
 const JsonBodyKey = "JsonBodyKey"
 const JsonBodyNakedKey = "JsonBodyNakedKey"
 
-var logger = logrus.New().WithField("module", "oauth")
+var logger = NewLogger("oauth")
 var signingMethod = jwt.SigningMethodHS256
 
 // CustomClaims Structure of JWT body, contains standard JWT claims and userID as a custom claim
@@ -44,6 +61,7 @@ type CustomClaims struct {
 	DeviceID uint64 `json:"deviceID"`
 	Package  string `json:"package"`
 	Theme    string `json:"theme"`
+	Scope    string `json:"scope,omitempty"`
 	jwt.StandardClaims
 }
 
@@ -61,8 +79,8 @@ type OAuthErrorResponse struct {
 	Description string `json:"error_description"`
 }
 
-// CreateAccessToken Creates a new access token for a given user
-func CreateAccessToken(userID, appID, deviceID uint64, pkg, theme string) (string, int, error) {
+// CreateAccessToken Creates a new access token for a given user, an empty scope grants full access
+func CreateAccessToken(userID, appID, deviceID uint64, pkg, theme, scope string) (string, int, error) {
 	expiresIn := Config.Frontend.JwtValidityInMinutes * 60
 
 	standardlaims := jwt.StandardClaims{
@@ -76,6 +94,7 @@ func CreateAccessToken(userID, appID, deviceID uint64, pkg, theme string) (strin
 		deviceID,
 		pkg,
 		theme,
+		scope,
 		standardlaims,
 	})
 
@@ -161,6 +180,44 @@ func accessTokenGetClaims(tokenStringFull string, validate bool) (*CustomClaims,
 	return nil, errors.New("token validity or claims cannot be verified")
 }
 
+// ParseOAuthScope validates a space separated list of scopes and returns it normalized (sorted, without duplicates)
+func ParseOAuthScope(scope string) (string, error) {
+	scopes := []string{}
+	seen := make(map[string]bool)
+	for _, s := range strings.Fields(scope) {
+		if _, ok := OAuthScopes[s]; !ok {
+			return "", fmt.Errorf("unknown scope %v", s)
+		}
+		if !seen[s] {
+			seen[s] = true
+			scopes = append(scopes, s)
+		}
+	}
+	sort.Strings(scopes)
+	return strings.Join(scopes, " "), nil
+}
+
+// HasOAuthScope returns true if the space separated list of scopes contains the given scope
+func HasOAuthScope(scope, want string) bool {
+	for _, s := range strings.Fields(scope) {
+		if s == want {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyPKCE checks the code_verifier of a token request against the S256 code_challenge of the authorization request
+func VerifyPKCE(verifier, challenge string) bool {
+	// RFC 7636 4.1: the verifier has a length of 43 to 128 characters
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	hash := sha256.Sum256([]byte(verifier))
+	computed := base64.RawURLEncoding.EncodeToString(hash[:])
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(challenge)) == 1
+}
+
 func createExpiration(validForSeconds int64) int64 {
 	return time.Now().Unix() + validForSeconds
 }
@@ -28,11 +28,13 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 	"unicode/utf8"
 
 	"github.com/gobitfly/eth2-beaconchain-explorer/config"
+	"github.com/gobitfly/eth2-beaconchain-explorer/httpclient"
 	"github.com/gobitfly/eth2-beaconchain-explorer/price"
 	"github.com/gobitfly/eth2-beaconchain-explorer/types"
 
@@ -63,109 +65,161 @@ var Config *types.Config
 
 var ErrRateLimit = errors.New("## RATE LIMIT ##")
 
+// Languages are the languages of the message catalogs in locales/, the first one is the default language and used for
+// messages missing in the catalog of another language
+var Languages = []string{"en-US", "ru-RU"}
+
 var localiser *i18n.I18n
+var localiserOnce sync.Once
 
 // making sure language files are loaded only once
 func getLocaliser() *i18n.I18n {
-	if localiser == nil {
-		localiser, err := i18n.New(i18n.Glob("locales/*/*"), "en-US", "ru-RU")
+	localiserOnce.Do(func() {
+		var err error
+		localiser, err = i18n.New(i18n.Glob("locales/*/*"), Languages...)
 		if err != nil {
 			log.Println(err)
 		}
-		return localiser
-	}
+	})
 	return localiser
 }
 
+// Tr returns the message of the key in the language, the args are formatted into the message. If the message has
+// plural forms (zero, one, two, other, =x, <x, >x) the first arg is the count that selects the form. The key is
+// returned if the message is missing in all catalogs.
+func Tr(lang, key string, args ...interface{}) string {
+	I18n := getLocaliser()
+	if I18n == nil {
+		return key
+	}
+	msg := I18n.Tr(lang, key, args...)
+	if msg == "" {
+		return key
+	}
+	return msg
+}
+
+// IsSupportedLanguage returns whether there is a message catalog for the language
+func IsSupportedLanguage(lang string) bool {
+	for _, l := range Languages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchLanguage returns the supported language that matches the Accept-Language header best, or the default language
+func MatchLanguage(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Languages[0]
+	}
+	supported := make([]language.Tag, len(Languages))
+	for i, l := range Languages {
+		supported[i] = language.MustParse(l)
+	}
+	_, index, confidence := language.NewMatcher(supported).Match(tags...)
+	if confidence == language.No {
+		return Languages[0]
+	}
+	return Languages[index]
+}
+
 var HashLikeRegex = regexp.MustCompile(`^[0-9a-fA-F]{0,96}$`)
 
 // GetTemplateFuncs will get the template functions
 func GetTemplateFuncs() template.FuncMap {
 	return template.FuncMap{
-		"includeHTML":                             IncludeHTML,
-		"includeSvg":                              IncludeSvg,
-		"formatHTML":                              FormatMessageToHtml,
-		"formatBalance":                           FormatBalance,
-		"formatNotificationChannel":               FormatNotificationChannel,
-		"formatBalanceSql":                        FormatBalanceSql,
-		"formatCurrentBalance":                    FormatCurrentBalance,
-		"formatElCurrency":                        FormatElCurrency,
-		"formatClCurrency":                        FormatClCurrency,
-		"formatEffectiveBalance":                  FormatEffectiveBalance,
-		"formatBlockStatus":                       FormatBlockStatus,
-		"formatBlockSlot":                         FormatBlockSlot,
-		"formatSlotToTimestamp":                   FormatSlotToTimestamp,
-		"formatDepositAmount":                     FormatDepositAmount,
-		"formatEpoch":                             FormatEpoch,
-		"fixAddressCasing":                        FixAddressCasing,
-		"formatAddressLong":                       FormatAddressLong,
-		"formatHashLong":                          FormatHashLong,
-		"formatEth1Block":                         FormatEth1Block,
-		"formatEth1BlockHash":                     FormatEth1BlockHash,
-		"formatEth1Address":                       FormatEth1Address,
-		"formatEth1AddressStringLowerCase":        FormatEth1AddressStringLowerCase,
-		"formatEth1TxHash":                        FormatEth1TxHash,
-		"formatGraffiti":                          FormatGraffiti,
-		"formatHash":                              FormatHash,
-		"formatWithdawalCredentials":              FormatWithdawalCredentials,
-		"formatAddressToWithdrawalCredentials":    FormatAddressToWithdrawalCredentials,
-		"formatBitlist":                           FormatBitlist,
-		"formatBitvectorValidators":               formatBitvectorValidators,
-		"formatParticipation":                     FormatParticipation,
-		"formatIncome":                            FormatIncome,
-		"formatIncomeSql":                         FormatIncomeSql,
-		"formatSqlInt64":                          FormatSqlInt64,
-		"formatValidator":                         FormatValidator,
-		"formatValidatorWithName":                 FormatValidatorWithName,
-		"formatValidatorInt64":                    FormatValidatorInt64,
-		"formatValidatorStatus":                   FormatValidatorStatus,
-		"formatPercentage":                        FormatPercentage,
-		"formatPercentageWithPrecision":           FormatPercentageWithPrecision,
-		"formatPercentageWithGPrecision":          FormatPercentageWithGPrecision,
-		"formatPercentageColoredEmoji":            FormatPercentageColoredEmoji,
-		"formatPublicKey":                         FormatPublicKey,
-		"formatSlashedValidator":                  FormatSlashedValidator,
-		"formatSlashedValidatorInt64":             FormatSlashedValidatorInt64,
-		"formatTimestamp":                         FormatTimestamp,
-		"formatTsWithoutTooltip":                  FormatTsWithoutTooltip,
-		"formatValidatorName":                     FormatValidatorName,
-		"formatAttestationInclusionEffectiveness": FormatAttestationInclusionEffectiveness,
-		"formatValidatorTags":                     FormatValidatorTags,
-		"formatValidatorTag":                      FormatValidatorTag,
-		"formatRPL":                               FormatRPL,
-		"formatETH":                               FormatETH,
-		"formatFloat":                             FormatFloat,
-		"formatAmount":                            FormatAmount,
-		"formatBytes":                             FormatBytes,
-		"formatBlobVersionedHash":                 FormatBlobVersionedHash,
-		"formatBigAmount":                         FormatBigAmount,
-		"formatBytesAmount":                       FormatBytesAmount,
-		"formatYesNo":                             FormatYesNo,
-		"formatAmountFormatted":                   FormatAmountFormatted,
-		"formatAddressAsLink":                     FormatAddressAsLink,
-		"formatBuilder":                           FormatBuilder,
-		"formatDifficulty":                        FormatDifficulty,
-		"getCurrencyLabel":                        price.GetCurrencyLabel,
-		"config":                                  func() *types.Config { return Config },
-		"epochOfSlot":                             EpochOfSlot,
-		"dayToTime":                               DayToTime,
-		"contains":                                strings.Contains,
-		"roundDecimals":                           RoundDecimals,
-		"bigIntCmp":                               func(i *big.Int, j int) int { return i.Cmp(big.NewInt(int64(j))) },
-		"mod":                                     func(i, j int) bool { return i%j == 0 },
-		"sub":                                     func(i, j int) int { return i - j },
-		"subUI64":                                 func(i, j uint64) uint64 { return i - j },
-		"add":                                     func(i, j int) int { return i + j },
-		"addI64":                                  func(i, j int64) int64 { return i + j },
-		"addUI64":                                 func(i, j uint64) uint64 { return i + j },
-		"addFloat64":                              func(i, j float64) float64 { return i + j },
-		"addBigInt":                               func(i, j *big.Int) *big.Int { return new(big.Int).Add(i, j) },
-		"mul":                                     func(i, j float64) float64 { return i * j },
-		"div":                                     func(i, j float64) float64 { return i / j },
-		"divInt":                                  func(i, j int) float64 { return float64(i) / float64(j) },
-		"nef":                                     func(i, j float64) bool { return i != j },
-		"gtf":                                     func(i, j float64) bool { return i > j },
-		"ltf":                                     func(i, j float64) bool { return i < j },
+		"includeHTML":                          IncludeHTML,
+		"includeSvg":                           IncludeSvg,
+		"formatHTML":                           FormatMessageToHtml,
+		"formatBalance":                        FormatBalance,
+		"formatNotificationChannel":            FormatNotificationChannel,
+		"formatBalanceSql":                     FormatBalanceSql,
+		"formatCurrentBalance":                 FormatCurrentBalance,
+		"formatElCurrency":                     FormatElCurrency,
+		"formatClCurrency":                     FormatClCurrency,
+		"formatEffectiveBalance":               FormatEffectiveBalance,
+		"formatBlockStatus":                    FormatBlockStatus,
+		"formatBlockSlot":                      FormatBlockSlot,
+		"formatSlotToTimestamp":                FormatSlotToTimestamp,
+		"formatDepositAmount":                  FormatDepositAmount,
+		"formatEpoch":                          FormatEpoch,
+		"fixAddressCasing":                     FixAddressCasing,
+		"formatAddressLong":                    FormatAddressLong,
+		"formatHashLong":                       FormatHashLong,
+		"formatEth1Block":                      FormatEth1Block,
+		"formatEth1BlockHash":                  FormatEth1BlockHash,
+		"formatEth1Address":                    FormatEth1Address,
+		"formatEth1AddressStringLowerCase":     FormatEth1AddressStringLowerCase,
+		"formatEth1TxHash":                     FormatEth1TxHash,
+		"formatGraffiti":                       FormatGraffiti,
+		"formatHash":                           FormatHash,
+		"formatWithdawalCredentials":           FormatWithdawalCredentials,
+		"formatWithdrawalCredentialsWithName":  FormatWithdrawalCredentialsWithName,
+		"formatAddressToWithdrawalCredentials": FormatAddressToWithdrawalCredentials,
+		"formatBitlist":                        FormatBitlist,
+		"formatBitvectorValidators":            formatBitvectorValidators,
+		"formatParticipation":                  FormatParticipation,
+		"formatIncome":                         FormatIncome,
+		"formatIncomeSql":                      FormatIncomeSql,
+		"formatSqlInt64":                       FormatSqlInt64,
+		"formatValidator":                      FormatValidator,
+		"formatValidatorWithName":              FormatValidatorWithName,
+		"formatValidatorInt64":                 FormatValidatorInt64,
+		"formatValidatorStatus":                FormatValidatorStatus,
+		"formatPercentage":                     FormatPercentage,
+		"formatPercentileBadge":                FormatPercentileBadge,
+		"formatMilestoneBadge":                 FormatMilestoneBadge,
+		"formatDepositOriginBadge":             FormatDepositOriginBadge,
+		"formatEth1DepositWarning":             FormatEth1DepositWarning,
+		"formatPercentageWithPrecision":        FormatPercentageWithPrecision,
+		"formatPercentageWithGPrecision":       FormatPercentageWithGPrecision,
+		"formatPercentageColoredEmoji":         FormatPercentageColoredEmoji,
+		"formatPublicKey":                      FormatPublicKey,
+		"formatSlashedValidator":               FormatSlashedValidator,
+		"formatSlashedValidatorInt64":          FormatSlashedValidatorInt64,
+		"formatTimestamp":                      FormatTimestamp,
+		"formatTsWithoutTooltip":               FormatTsWithoutTooltip,
+		"formatValidatorName":                  FormatValidatorName,
+		"formatEffectivenessScore":             FormatEffectivenessScore,
+		"formatValidatorTags":                  FormatValidatorTags,
+		"formatValidatorTag":                   FormatValidatorTag,
+		"formatRPL":                            FormatRPL,
+		"formatETH":                            FormatETH,
+		"formatFloat":                          FormatFloat,
+		"formatAmount":                         FormatAmount,
+		"formatBytes":                          FormatBytes,
+		"formatBlobVersionedHash":              FormatBlobVersionedHash,
+		"formatBigAmount":                      FormatBigAmount,
+		"formatBytesAmount":                    FormatBytesAmount,
+		"formatYesNo":                          FormatYesNo,
+		"formatAmountFormatted":                FormatAmountFormatted,
+		"formatAddressAsLink":                  FormatAddressAsLink,
+		"formatBuilder":                        FormatBuilder,
+		"formatDifficulty":                     FormatDifficulty,
+		"getCurrencyLabel":                     price.GetCurrencyLabel,
+		"config":                               func() *types.Config { return Config },
+		"epochOfSlot":                          EpochOfSlot,
+		"dayToTime":                            DayToTime,
+		"contains":                             strings.Contains,
+		"roundDecimals":                        RoundDecimals,
+		"bigIntCmp":                            func(i *big.Int, j int) int { return i.Cmp(big.NewInt(int64(j))) },
+		"mod":                                  func(i, j int) bool { return i%j == 0 },
+		"sub":                                  func(i, j int) int { return i - j },
+		"subUI64":                              func(i, j uint64) uint64 { return i - j },
+		"add":                                  func(i, j int) int { return i + j },
+		"addI64":                               func(i, j int64) int64 { return i + j },
+		"addUI64":                              func(i, j uint64) uint64 { return i + j },
+		"addFloat64":                           func(i, j float64) float64 { return i + j },
+		"addBigInt":                            func(i, j *big.Int) *big.Int { return new(big.Int).Add(i, j) },
+		"mul":                                  func(i, j float64) float64 { return i * j },
+		"div":                                  func(i, j float64) float64 { return i / j },
+		"divInt":                               func(i, j int) float64 { return float64(i) / float64(j) },
+		"nef":                                  func(i, j float64) bool { return i != j },
+		"gtf":                                  func(i, j float64) bool { return i > j },
+		"ltf":                                  func(i, j float64) bool { return i < j },
 		"round": func(i float64, n int) float64 {
 			return math.Round(i*math.Pow10(n)) / math.Pow10(n)
 		},
@@ -217,6 +271,7 @@ func GetTemplateFuncs() template.FuncMap {
 		// ETH1 related formatting
 		"formatEth1TxStatus":    FormatEth1TxStatus,
 		"formatEth1AddressFull": FormatEth1AddressFull,
+		"formatAddressLabels":   FormatAddressLabels,
 		"byteToString": func(num []byte) string {
 			return string(num)
 		},
@@ -433,6 +488,10 @@ func ReadConfig(cfg *types.Config, path string) error {
 		return err
 	}
 
+	if cfg.Devnet {
+		setDevnetConfigDefaults(cfg)
+	}
+
 	if cfg.Frontend.SiteBrand == "" {
 		cfg.Frontend.SiteBrand = "beaconcha.in"
 	}
@@ -633,7 +692,11 @@ func ReadConfig(cfg *types.Config, path string) error {
 		case "holesky":
 			err = yaml.Unmarshal([]byte(config.HoleskyChainYml), &minimalCfg)
 		default:
-			return fmt.Errorf("tried to set known chain-config, but unknown chain-name: %v (path: %v)", cfg.Chain.Name, cfg.Chain.ElConfigPath)
+			if !cfg.Devnet {
+				return fmt.Errorf("tried to set known chain-config, but unknown chain-name: %v (path: %v)", cfg.Chain.Name, cfg.Chain.ElConfigPath)
+			}
+			// devnets activate all el forks at genesis
+			cfg.Chain.Id = cfg.Chain.ClConfig.DepositChainID
 		}
 		if err != nil {
 			return err
@@ -738,6 +801,32 @@ func ReadConfig(cfg *types.Config, path string) error {
 		}
 	}
 
+	if cfg.Frontend.Compression.MinSize == 0 {
+		cfg.Frontend.Compression.MinSize = 1024
+	}
+	if cfg.Frontend.Compression.GzipLevel == 0 {
+		cfg.Frontend.Compression.GzipLevel = defaultGzipLevel
+	}
+	if cfg.Frontend.Compression.BrotliLevel == 0 {
+		// higher levels are too slow for dynamic responses
+		cfg.Frontend.Compression.BrotliLevel = 4
+	}
+	if len(cfg.Frontend.Compression.ContentTypes) == 0 {
+		cfg.Frontend.Compression.ContentTypes = defaultCompressionContentTypes
+	}
+
+	httpclient.Configure(cfg.HttpClients)
+
+	if cfg.Frontend.QueryBudget.MaxQueries == 0 {
+		cfg.Frontend.QueryBudget.MaxQueries = 50
+	}
+	if cfg.Frontend.QueryBudget.MaxDuration == 0 {
+		cfg.Frontend.QueryBudget.MaxDuration = time.Second * 2
+	}
+	if cfg.Frontend.QueryBudget.SlowQuery == 0 {
+		cfg.Frontend.QueryBudget.SlowQuery = time.Second
+	}
+
 	if cfg.Frontend.SiteTitle == "" {
 		cfg.Frontend.SiteTitle = "Open Source Ethereum Explorer"
 	}
@@ -773,6 +862,17 @@ func ReadConfig(cfg *types.Config, path string) error {
 		cfg.RedisSessionStoreEndpoint = cfg.RedisCacheEndpoint
 	}
 
+	err = ConfigureLogging(cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range cfg.Frontend.Cors {
+		if policy.AllowCredentials && ElementExists(policy.AllowedOrigins, "*") {
+			return fmt.Errorf("cors policy for path prefix %v allows credentials for any origin, list the allowed origins explicitly", policy.PathPrefix)
+		}
+	}
+
 	logrus.WithFields(logrus.Fields{
 		"genesisTimestamp":       cfg.Chain.GenesisTimestamp,
 		"genesisValidatorsRoot":  cfg.Chain.GenesisValidatorsRoot,
@@ -802,6 +902,64 @@ func mustParseUint(str string) uint64 {
 	return nbr
 }
 
+// setDevnetConfigDefaults fills the settings not provided for a devnet deployment: the chain spec is loaded from the
+// node, bigtable data is stored in postgres, the schema is migrated on startup and exporter and frontend run in the
+// same process
+func setDevnetConfigDefaults(cfg *types.Config) {
+	if cfg.Chain.ClConfigPath == "" {
+		cfg.Chain.ClConfigPath = "node"
+	}
+	if cfg.Indexer.Node.Type == "" {
+		cfg.Indexer.Node.Type = "lighthouse"
+	}
+	cfg.Indexer.Enabled = true
+	cfg.Frontend.Enabled = true
+	cfg.DbAutoMigrate = true
+
+	if cfg.Bigtable.Backend == "" && !cfg.Bigtable.Emulator {
+		cfg.Bigtable.Backend = "postgres"
+	}
+	if cfg.Bigtable.Project == "" {
+		cfg.Bigtable.Project = "explorer"
+	}
+	if cfg.Bigtable.Instance == "" {
+		cfg.Bigtable.Instance = "explorer"
+	}
+
+	// all databases default to the writer database
+	w := cfg.WriterDatabase
+	if cfg.ReaderDatabase.Host == "" {
+		cfg.ReaderDatabase.Username, cfg.ReaderDatabase.Password, cfg.ReaderDatabase.Name = w.Username, w.Password, w.Name
+		cfg.ReaderDatabase.Host, cfg.ReaderDatabase.Port, cfg.ReaderDatabase.SSL = w.Host, w.Port, w.SSL
+	}
+	if cfg.Frontend.WriterDatabase.Host == "" {
+		cfg.Frontend.WriterDatabase.Username, cfg.Frontend.WriterDatabase.Password, cfg.Frontend.WriterDatabase.Name = w.Username, w.Password, w.Name
+		cfg.Frontend.WriterDatabase.Host, cfg.Frontend.WriterDatabase.Port, cfg.Frontend.WriterDatabase.SSL = w.Host, w.Port, w.SSL
+	}
+	if cfg.Frontend.ReaderDatabase.Host == "" {
+		cfg.Frontend.ReaderDatabase.Username, cfg.Frontend.ReaderDatabase.Password, cfg.Frontend.ReaderDatabase.Name = w.Username, w.Password, w.Name
+		cfg.Frontend.ReaderDatabase.Host, cfg.Frontend.ReaderDatabase.Port, cfg.Frontend.ReaderDatabase.SSL = w.Host, w.Port, w.SSL
+	}
+
+	if cfg.TieredCacheProvider == "" {
+		cfg.TieredCacheProvider = "redis"
+	}
+	if cfg.Eth1GethEndpoint == "" {
+		cfg.Eth1GethEndpoint = cfg.Eth1ErigonEndpoint
+	}
+
+	// devnets are served over plain http on localhost and are not reachable from the outside
+	cfg.Frontend.CsrfInsecure = true
+	if cfg.Frontend.SessionSecret == "" {
+		cfg.Frontend.SessionSecret = RandomString(32)
+	}
+	if cfg.Frontend.CsrfAuthKey == "" {
+		cfg.Frontend.CsrfAuthKey = hex.EncodeToString([]byte(RandomString(32)))
+	}
+
+	logger.Infof("running in devnet mode, loading the chain config from the node at %s:%s", cfg.Indexer.Node.Host, cfg.Indexer.Node.Port)
+}
+
 func readConfigFile(cfg *types.Config, path string) error {
 	if path == "" {
 		return yaml.Unmarshal([]byte(config.DefaultConfigYml), cfg)
@@ -838,19 +996,6 @@ func MustParseHex(hexString string) []byte {
 	return data
 }
 
-func CORSMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Headers", "*, Authorization")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "*")
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-		next.ServeHTTP(w, r)
-	})
-}
-
 func IsApiRequest(r *http.Request) bool {
 	query, ok := r.URL.Query()["format"]
 	return ok && len(query) > 0 && query[0] == "json"
@@ -971,9 +1116,9 @@ func SqlRowsToJSON(rows *sql.Rows) ([]interface{}, error) {
 				scanArgs[i] = new(sql.NullString)
 			case "BOOL":
 				scanArgs[i] = new(sql.NullBool)
-			case "INT4", "INT8":
+			case "INT4", "INT8", "Int32", "Int64", "UInt32", "UInt64", "Nullable(Int64)": // clickhouse types use their own names
 				scanArgs[i] = new(sql.NullInt64)
-			case "FLOAT8":
+			case "FLOAT8", "Float64", "Nullable(Float64)":
 				scanArgs[i] = new(sql.NullFloat64)
 			case "TIMESTAMP":
 				scanArgs[i] = new(sql.NullTime)
@@ -1206,6 +1351,92 @@ func TryFetchContractMetadata(address []byte) (*types.ContractMetadata, error) {
 // 	}
 // }
 
+func GetSourcifyAPIBaseUrl() string {
+	if len(Config.SourcifyAPIBaseURL) > 0 {
+		return strings.TrimSuffix(Config.SourcifyAPIBaseURL, "/")
+	}
+	return "https://sourcify.dev/server"
+}
+
+// FetchContractFromSourcify retrieves the abi and source files of a contract verified on sourcify, nil is returned if the contract is not verified
+func FetchContractFromSourcify(address []byte) (*types.VerifiedContract, error) {
+	httpClient := http.Client{Timeout: time.Second * 10}
+	resp, err := httpClient.Get(fmt.Sprintf("%s/files/any/%d/0x%x", GetSourcifyAPIBaseUrl(), Config.Chain.ClConfig.DepositChainID, address))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("StatusCode: '%d', Status: '%s'", resp.StatusCode, resp.Status)
+	}
+
+	data := &struct {
+		Status string `json:"status"`
+		Files  []struct {
+			Name    string `json:"name"`
+			Path    string `json:"path"`
+			Content string `json:"content"`
+		} `json:"files"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	contract := &types.VerifiedContract{
+		Address:    address,
+		MatchType:  data.Status,
+		Source:     types.VerifiedContractSourceSourcify,
+		VerifiedTs: time.Now(),
+		Files:      make([]*types.VerifiedContractFile, 0, len(data.Files)),
+	}
+	for _, file := range data.Files {
+		if file.Name != "metadata.json" {
+			path := file.Name
+			if i := strings.Index(file.Path, "/sources/"); i >= 0 {
+				path = file.Path[i+len("/sources/"):]
+			}
+			contract.Files = append(contract.Files, &types.VerifiedContractFile{Path: path, Content: file.Content})
+			continue
+		}
+
+		metadata := &struct {
+			Compiler struct {
+				Version string `json:"version"`
+			} `json:"compiler"`
+			Output struct {
+				Abi json.RawMessage `json:"abi"`
+			} `json:"output"`
+			Settings struct {
+				CompilationTarget map[string]string `json:"compilationTarget"`
+			} `json:"settings"`
+		}{}
+		err = json.Unmarshal([]byte(file.Content), metadata)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding sourcify metadata: %w", err)
+		}
+		contract.CompilerVersion = metadata.Compiler.Version
+		contract.ABIJson = metadata.Output.Abi
+		for _, name := range metadata.Settings.CompilationTarget {
+			contract.Name = name
+		}
+	}
+
+	if len(contract.ABIJson) == 0 {
+		return nil, fmt.Errorf("sourcify response for 0x%x does not contain a contract abi", address)
+	}
+	_, err = abi.JSON(bytes.NewReader(contract.ABIJson))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing sourcify abi: %w", err)
+	}
+
+	return contract, nil
+}
+
 func GetEtherscanAPIBaseUrl(provideDefault bool) string {
 	const mainnetBaseUrl = "api.etherscan.io"
 	const goerliBaseUrl = "api-goerli.etherscan.io"
@@ -1427,6 +1658,22 @@ func EpochsPerDay() uint64 {
 	return (uint64(Day.Seconds()) / Config.Chain.ClConfig.SlotsPerEpoch) / Config.Chain.ClConfig.SecondsPerSlot
 }
 
+// SlotsPerDay returns the number of slots of a day, 7200 on mainnet
+func SlotsPerDay() uint64 {
+	return EpochsPerDay() * Config.Chain.ClConfig.SlotsPerEpoch
+}
+
+// SlotDuration returns the duration of a slot, 12 seconds on mainnet and 5 seconds on gnosis
+func SlotDuration() time.Duration {
+	return time.Second * time.Duration(Config.Chain.ClConfig.SecondsPerSlot)
+}
+
+// ActivationBalance returns the balance a validator needs to be activated in the cl currency, 32 ETH on mainnet and
+// 32 mGNO (1 GNO) on gnosis
+func ActivationBalance() float64 {
+	return float64(Config.Chain.ClConfig.MaxEffectiveBalance) / float64(Config.Frontend.ClCurrencyDivisor)
+}
+
 func GetFirstAndLastEpochForDay(day uint64) (firstEpoch uint64, lastEpoch uint64) {
 	firstEpoch = day * EpochsPerDay()
 	lastEpoch = firstEpoch + EpochsPerDay() - 1
@@ -1485,8 +1732,13 @@ func LogWarn(err error, errorMsg interface{}, callerSkip int, additionalInfos ..
 	logErrorInfo(err, callerSkip, additionalInfos...).Warn(errorMsg)
 }
 
+// LogErrorContext logs an error like LogError and adds the id of the request the context belongs to
+func LogErrorContext(ctx context.Context, err error, errorMsg interface{}, callerSkip int, additionalInfos ...map[string]interface{}) {
+	LoggerWithContext(ctx, logErrorInfo(err, callerSkip, additionalInfos...)).Error(errorMsg)
+}
+
 func logErrorInfo(err error, callerSkip int, additionalInfos ...map[string]interface{}) *logrus.Entry {
-	logFields := logrus.NewEntry(logrus.New())
+	logFields := errorLogger.WithField("service", serviceName)
 
 	pc, fullFilePath, line, ok := runtime.Caller(callerSkip + 2)
 	if ok {
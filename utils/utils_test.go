@@ -26,3 +26,38 @@ func TestIsValidUrl(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatBuilderName(t *testing.T) {
+	tests := []struct {
+		pubkey    string
+		extraData string
+		name      string
+	}{
+		{"0xa1dead01e65f0a0eee7b5170223f20c8f0cbf122eac3324d61afbdb33a8885ff8cab2ef514ac2c7698ae0d6289ef27fc", "0x6265617665726275696c642e6f7267", "beaverbuild.org"},
+		{"0xa1dead01e65f0a0eee7b5170223f20c8f0cbf122eac3324d61afbdb33a8885ff8cab2ef514ac2c7698ae0d6289ef27fc", "0x0000", "0xa1dead01…"},
+		{"0xa1dead01e65f0a0eee7b5170223f20c8f0cbf122eac3324d61afbdb33a8885ff8cab2ef514ac2c7698ae0d6289ef27fc", "", "0xa1dead01…"},
+	}
+	for _, tt := range tests {
+		name := FormatBuilderName(tt.pubkey, tt.extraData)
+		if name != tt.name {
+			t.Errorf("wrong builder name for extra data %v, got %v, expected %v", tt.extraData, name, tt.name)
+		}
+	}
+}
+
+func TestResolveIpfsURI(t *testing.T) {
+	tests := []struct {
+		uri      string
+		resolved string
+	}{
+		{"ipfs://QmYDvPAXtiJg7s8JdRBSLWdgSphQdac8j1YuQNNxcGE1hg/1.json", "https://ipfs.io/ipfs/QmYDvPAXtiJg7s8JdRBSLWdgSphQdac8j1YuQNNxcGE1hg/1.json"},
+		{"ipfs://ipfs/QmYDvPAXtiJg7s8JdRBSLWdgSphQdac8j1YuQNNxcGE1hg", "https://ipfs.io/ipfs/QmYDvPAXtiJg7s8JdRBSLWdgSphQdac8j1YuQNNxcGE1hg"},
+		{"https://example.com/token/1", "https://example.com/token/1"},
+	}
+	for _, tt := range tests {
+		resolved := ResolveIpfsURI(tt.uri)
+		if resolved != tt.resolved {
+			t.Errorf("wrong resolved uri for %v, got %v, expected %v", tt.uri, resolved, tt.resolved)
+		}
+	}
+}
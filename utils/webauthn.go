@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+)
+
+// COSE algorithm identifiers of the supported WebAuthn credentials
+const (
+	WebAuthnAlgES256 int64 = -7
+	WebAuthnAlgEdDSA int64 = -8
+	WebAuthnAlgRS256 int64 = -257
+)
+
+// WebAuthnAlgorithms are the supported credential algorithms in order of preference
+var WebAuthnAlgorithms = []int64{WebAuthnAlgES256, WebAuthnAlgEdDSA, WebAuthnAlgRS256}
+
+const (
+	webAuthnFlagUserPresent  = 0x01
+	webAuthnFlagUserVerified = 0x04
+)
+
+// WebAuthnAuthenticatorData is the parsed part of the authenticator data that is relevant for verification
+type WebAuthnAuthenticatorData struct {
+	UserPresent  bool
+	UserVerified bool
+	SignCount    uint32
+}
+
+type webAuthnClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// WebAuthnRPID returns the relying party id of the WebAuthn credentials, the domain of the site without port
+func WebAuthnRPID() string {
+	host, _, err := net.SplitHostPort(Config.Frontend.SiteDomain)
+	if err != nil {
+		return Config.Frontend.SiteDomain
+	}
+	return host
+}
+
+// NewWebAuthnChallenge returns a random base64url encoded challenge for a WebAuthn ceremony
+func NewWebAuthnChallenge() (string, error) {
+	challenge, err := GenerateRandomBytesSecure(32)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(challenge), nil
+}
+
+// DecodeWebAuthnBase64 decodes the base64url encoded binary fields sent by the browser, padding is optional
+func DecodeWebAuthnBase64(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(trimBase64Padding(s))
+}
+
+func trimBase64Padding(s string) string {
+	for len(s) > 0 && s[len(s)-1] == '=' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// VerifyWebAuthnClientData checks the type, challenge and origin of the client data of a WebAuthn ceremony,
+// ceremonyType is webauthn.create for registrations and webauthn.get for logins
+func VerifyWebAuthnClientData(clientDataJSON []byte, ceremonyType, challenge string) error {
+	clientData := webAuthnClientData{}
+	err := json.Unmarshal(clientDataJSON, &clientData)
+	if err != nil {
+		return fmt.Errorf("error parsing client data: %w", err)
+	}
+	if clientData.Type != ceremonyType {
+		return fmt.Errorf("invalid client data type %v", clientData.Type)
+	}
+	if challenge == "" || subtle.ConstantTimeCompare([]byte(trimBase64Padding(clientData.Challenge)), []byte(challenge)) != 1 {
+		return errors.New("invalid challenge")
+	}
+	origin, err := url.Parse(clientData.Origin)
+	if err != nil {
+		return fmt.Errorf("error parsing origin: %w", err)
+	}
+	if origin.Hostname() != WebAuthnRPID() {
+		return fmt.Errorf("invalid origin %v", clientData.Origin)
+	}
+	// browsers only allow WebAuthn on secure origins, localhost is the only exception
+	if origin.Scheme != "https" && origin.Hostname() != "localhost" {
+		return fmt.Errorf("invalid origin %v", clientData.Origin)
+	}
+	return nil
+}
+
+// ParseWebAuthnAuthenticatorData checks the relying party id hash and the user presence of the authenticator data
+func ParseWebAuthnAuthenticatorData(authData []byte) (*WebAuthnAuthenticatorData, error) {
+	// rpIdHash (32 bytes), flags (1 byte), signCount (4 bytes), optional attested credential data and extensions
+	if len(authData) < 37 {
+		return nil, errors.New("authenticator data too short")
+	}
+	rpIdHash := sha256.Sum256([]byte(WebAuthnRPID()))
+	if !bytes.Equal(authData[:32], rpIdHash[:]) {
+		return nil, errors.New("invalid relying party id hash")
+	}
+	data := &WebAuthnAuthenticatorData{
+		UserPresent:  authData[32]&webAuthnFlagUserPresent != 0,
+		UserVerified: authData[32]&webAuthnFlagUserVerified != 0,
+		SignCount:    binary.BigEndian.Uint32(authData[33:37]),
+	}
+	if !data.UserPresent {
+		return nil, errors.New("user not present")
+	}
+	return data, nil
+}
+
+// ParseWebAuthnPublicKey checks that the DER encoded SubjectPublicKeyInfo of a new credential is a valid key of the algorithm
+func ParseWebAuthnPublicKey(publicKey []byte, alg int64) error {
+	key, err := x509.ParsePKIXPublicKey(publicKey)
+	if err != nil {
+		return fmt.Errorf("error parsing public key: %w", err)
+	}
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if alg == WebAuthnAlgES256 && k.Curve == elliptic.P256() {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if alg == WebAuthnAlgEdDSA {
+			return nil
+		}
+	case *rsa.PublicKey:
+		if alg == WebAuthnAlgRS256 && k.N.BitLen() >= 2048 {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported public key for algorithm %v", alg)
+}
+
+// VerifyWebAuthnSignature verifies the assertion signature over the authenticator data and the hash of the client data
+func VerifyWebAuthnSignature(publicKey []byte, alg int64, authData, clientDataJSON, signature []byte) error {
+	key, err := x509.ParsePKIXPublicKey(publicKey)
+	if err != nil {
+		return fmt.Errorf("error parsing public key: %w", err)
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte{}, authData...), clientDataHash[:]...)
+
+	valid := false
+	switch alg {
+	case WebAuthnAlgES256:
+		k, ok := key.(*ecdsa.PublicKey)
+		if ok {
+			hash := sha256.Sum256(signed)
+			valid = ecdsa.VerifyASN1(k, hash[:], signature)
+		}
+	case WebAuthnAlgEdDSA:
+		k, ok := key.(ed25519.PublicKey)
+		if ok {
+			valid = ed25519.Verify(k, signed, signature)
+		}
+	case WebAuthnAlgRS256:
+		k, ok := key.(*rsa.PublicKey)
+		if ok {
+			hash := sha256.Sum256(signed)
+			valid = rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], signature) == nil
+		}
+	default:
+		return fmt.Errorf("unsupported algorithm %v", alg)
+	}
+	if !valid {
+		return errors.New("invalid signature")
+	}
+	return nil
+}